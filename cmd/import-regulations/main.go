@@ -0,0 +1,105 @@
+// Command import-regulations loads an OSHA standards export into the
+// regulations table.
+//
+// Usage:
+//
+//	DATABASE_URL=postgres://... go run ./cmd/import-regulations -file standards.csv
+//
+// The file is a CSV with a header row, or a JSON array of objects, using the
+// regulations column names (standard_number, title, category, subcategory,
+// full_text, summary, severity_typical, parent_standard, effective_date,
+// last_updated). Standards are upserted by standard number; standards missing
+// from the file are deactivated. Malformed rows are skipped and listed in the
+// summary. Re-running the same file changes nothing.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/DukeRupert/lukaut/internal"
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/DukeRupert/lukaut/internal/service"
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+func run() error {
+	ctx := context.Background()
+
+	file := flag.String("file", "", "path to the standards export (.csv or .json)")
+	format := flag.String("format", "", "file format: csv or json (default: from the file extension)")
+	flag.Parse()
+
+	if *file == "" {
+		flag.Usage()
+		return errors.New("-file is required")
+	}
+	importFormat := domain.RegulationImportFormat(strings.ToLower(*format))
+	if importFormat == "" {
+		importFormat = domain.RegulationImportFormat(strings.TrimPrefix(strings.ToLower(filepath.Ext(*file)), "."))
+	}
+	if !importFormat.IsValid() {
+		return fmt.Errorf("unsupported format %q: use -format csv or -format json", importFormat)
+	}
+
+	databaseURL := os.Getenv("DATABASE_URL")
+	if databaseURL == "" {
+		return errors.New("DATABASE_URL is required")
+	}
+
+	logger := internal.NewLogger(os.Stderr, os.Getenv("ENV"), os.Getenv("LOG_LEVEL"))
+
+	// Initialize database connection
+	db, err := sql.Open("pgx", databaseURL)
+	if err != nil {
+		return fmt.Errorf("database connection failed: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	if err := db.PingContext(ctx); err != nil {
+		return fmt.Errorf("database ping failed: %w", err)
+	}
+
+	// The import relies on the deactivated_at column and regulation_imports table
+	if err := internal.RunMigrations(db); err != nil {
+		return fmt.Errorf("migration failed: %w", err)
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		return fmt.Errorf("open import file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	importService := service.NewRegulationImportService(db, repository.New(db), logger)
+	summary, err := importService.Import(ctx, filepath.Base(*file), importFormat, f)
+	if err != nil {
+		return fmt.Errorf("import failed: %w", err)
+	}
+
+	fmt.Printf("Imported %s (import %s)\n", summary.Source, summary.ID)
+	fmt.Printf("  added:       %d\n", summary.Added)
+	fmt.Printf("  updated:     %d\n", summary.Updated)
+	fmt.Printf("  unchanged:   %d\n", summary.Unchanged)
+	fmt.Printf("  deactivated: %d\n", summary.Deactivated)
+	fmt.Printf("  skipped:     %d\n", len(summary.Errors))
+	for _, msg := range summary.Errors {
+		fmt.Printf("    %s\n", msg)
+	}
+
+	return nil
+}
+
+func main() {
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -2,12 +2,14 @@ package main
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"slices"
+	"sync"
 	"syscall"
 	"time"
 
@@ -15,20 +17,29 @@ import (
 	"github.com/DukeRupert/lukaut/internal/ai"
 	"github.com/DukeRupert/lukaut/internal/ai/anthropic"
 	"github.com/DukeRupert/lukaut/internal/ai/mock"
+	"github.com/DukeRupert/lukaut/internal/ai/openai"
 	"github.com/DukeRupert/lukaut/internal/billing"
+	"github.com/DukeRupert/lukaut/internal/dbtrace"
+	"github.com/DukeRupert/lukaut/internal/domain"
 	"github.com/DukeRupert/lukaut/internal/email"
+	"github.com/DukeRupert/lukaut/internal/geocode"
 	"github.com/DukeRupert/lukaut/internal/handler"
 	"github.com/DukeRupert/lukaut/internal/invite"
+	"github.com/DukeRupert/lukaut/internal/jobevents"
 	"github.com/DukeRupert/lukaut/internal/jobs"
 	"github.com/DukeRupert/lukaut/internal/metrics"
 	"github.com/DukeRupert/lukaut/internal/middleware"
+	"github.com/DukeRupert/lukaut/internal/report"
 	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/DukeRupert/lukaut/internal/scan"
 	"github.com/DukeRupert/lukaut/internal/service"
 	"github.com/DukeRupert/lukaut/internal/storage"
 	publicpages "github.com/DukeRupert/lukaut/internal/templ/pages/public"
+	"github.com/DukeRupert/lukaut/internal/weather"
+	"github.com/DukeRupert/lukaut/internal/webhook"
 	"github.com/DukeRupert/lukaut/internal/worker"
 	"github.com/google/uuid"
-	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/jackc/pgx/v5"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
@@ -45,14 +56,15 @@ func run() error {
 	logger := internal.NewLogger(os.Stdout, cfg.Env, cfg.LogLevel)
 
 	// Initialize database connection
-	db, err := sql.Open("pgx", cfg.DatabaseUrl)
+	db, err := internal.OpenDB(ctx, cfg.DatabaseUrl, cfg.HTTPPoolConfig(), slowQueryTracer(cfg, logger, "http"))
 	if err != nil {
 		return fmt.Errorf("database connection failed: %w", err)
 	}
+	// Closed when run returns, after the server and worker have stopped
 	defer func() { _ = db.Close() }()
-
-	if err := db.PingContext(ctx); err != nil {
-		return fmt.Errorf("database ping failed: %w", err)
+	metrics.RegisterDBStats(db, "http")
+	if cfg.DBLogSlowQueries {
+		logger.Info("slow query logging enabled", "threshold", cfg.DBSlowQueryThreshold)
 	}
 
 	// Run migrations
@@ -63,6 +75,7 @@ func run() error {
 
 	// Initialize repository
 	repo := repository.New(db)
+	uow := repository.NewUnitOfWork(repo)
 
 	// Initialize storage service
 	var storageService storage.Storage
@@ -90,59 +103,174 @@ func run() error {
 	logger.Info("Storage service initialized", "provider", cfg.StorageProvider)
 
 	// Initialize job enqueuer for services
-	jobEnqueuer := newServiceJobEnqueuer(worker.NewJobEnqueuer(repo))
+	enqueueLimits := worker.EnqueueLimits{
+		MaxInFlightPerUser: cfg.WorkerMaxUserJobs,
+		MaxQueueDepth:      cfg.WorkerMaxQueue,
+	}
+	if err := enqueueLimits.Validate(); err != nil {
+		return fmt.Errorf("invalid job enqueue limits: %w", err)
+	}
+	workerEnqueuer := worker.NewJobEnqueuer(repo, enqueueLimits)
+	jobEnqueuer := newServiceJobEnqueuer(workerEnqueuer)
 
 	// Initialize quota service for rate limiting
 	quotaService := service.NewQuotaService(repo, logger)
 
-	// Initialize services
-	userService := service.NewUserServiceWithConfig(repo, logger, service.UserServiceConfig{
-		SessionDuration: cfg.SessionDuration,
+	// Outbound webhooks notify users' integrations of finished analyses and reports
+	webhookService := service.NewWebhookService(repo, workerEnqueuer, logger)
+
+	// Address geocoder. Without a provider, addresses are stored as entered,
+	// autocomplete offers no suggestions, and no geocoding jobs are enqueued.
+	geocoder, err := geocode.New(geocode.Config{
+		Provider:  cfg.GeocoderProvider,
+		APIKey:    cfg.GeocoderAPIKey,
+		URL:       cfg.GeocoderURL,
+		UserAgent: "Lukaut (+" + cfg.BaseURL + ")",
 	})
-	logger.Info("session configuration", "duration", cfg.SessionDuration)
-	inspectionService := service.NewInspectionService(repo, jobEnqueuer, quotaService, logger)
-	violationService := service.NewViolationService(repo, logger)
+	if err != nil {
+		return fmt.Errorf("geocoder initialization failed: %w", err)
+	}
+	var geocodeEnqueuer service.GeocodeEnqueuer
+	if cfg.GeocoderProvider != geocode.ProviderNone {
+		geocodeEnqueuer = jobEnqueuer
+		logger.Info("geocoding enabled", "provider", cfg.GeocoderProvider)
+	}
+
+	// Weather provider. Without an API key there are no suggestions and no
+	// weather auto-fill jobs are enqueued.
+	weatherProvider := weather.New(weather.Config{APIKey: cfg.WeatherAPIKey, URL: cfg.WeatherURL})
+	var weatherEnqueuer service.WeatherEnqueuer
+	if cfg.WeatherAPIKey != "" {
+		weatherEnqueuer = jobEnqueuer
+		logger.Info("weather suggestions enabled")
+	}
+
+	// Initialize services
+	userServiceConfig := service.UserServiceConfig{
+		SessionDuration:              cfg.SessionDuration,
+		SessionIdleTimeout:           cfg.SessionIdleTimeout,
+		EmailVerificationGracePeriod: cfg.EmailVerificationGracePeriod,
+	}
+	if cfg.PasswordBreachCheck {
+		userServiceConfig.BreachedPasswordChecker = service.NewPwnedPasswordsChecker("", service.DefaultBreachCheckTimeout)
+		logger.Info("breached password check enabled")
+	}
+	userService := service.NewUserServiceWithConfig(repo, uow, logger, userServiceConfig)
+	logger.Info("session configuration", "duration", cfg.SessionDuration, "idle_timeout", cfg.SessionIdleTimeout)
+	auditService := service.NewAuditService(repo, uow, logger)
+	inspectionService := service.NewInspectionService(repo, jobEnqueuer, quotaService, auditService, geocodeEnqueuer, weatherEnqueuer, logger)
+	violationService := service.NewViolationService(repo, uow, auditService, logger)
+	weatherService := service.NewWeatherService(repo, weatherProvider, logger)
 	clientService := service.NewClientService(repo, logger)
-	reportService := service.NewReportService(repo, storageService, jobEnqueuer, quotaService, logger)
+	preferenceService := service.NewPreferenceService(repo, logger)
+	reportService := service.NewReportService(repo, storageService, jobEnqueuer, quotaService, auditService, preferenceService, report.DefaultBranding(), logger)
+	brandingService := service.NewBrandingService(repo, storageService, logger)
 	regulationService := service.NewRegulationService(repo, logger)
 
 	// Initialize thumbnail processor
-	thumbnailProcessor := service.NewImagingProcessor()
+	thumbnailProcessor := service.NewImagingProcessor(service.ThumbnailConfig{
+		Sizes: cfg.ThumbnailSizes,
+		WebP:  cfg.ThumbnailWebP,
+	})
+
+	// HEIC/HEIF converter. Without heif-convert, HEIC uploads are refused.
+	// Converted JPEGs are held to the same size limit as direct uploads.
+	uploadLimits := cfg.UploadLimits()
+	var imageConverter service.ImageConverter
+	if cfg.HEIFConvertCommand != "" && service.IsHEIFConverterAvailable(cfg.HEIFConvertCommand) {
+		imageConverter = service.NewHEIFConverter(cfg.HEIFConvertCommand, uploadLimits.MaxSize)
+		logger.Info("HEIC conversion enabled", "command", cfg.HEIFConvertCommand)
+	} else {
+		logger.Warn("HEIC conversion disabled: heif-convert not found", "command", cfg.HEIFConvertCommand)
+	}
+
+	// Malware scanner. Without a provider, uploads are stored unscanned.
+	scanner, err := scan.New(scan.Config{
+		Provider: cfg.ScanProvider,
+		Address:  cfg.ClamAVAddress,
+		Timeout:  cfg.ScanTimeout,
+	})
+	if err != nil {
+		return fmt.Errorf("scanner initialization failed: %w", err)
+	}
+	if cfg.ScanProvider != scan.ProviderNone {
+		logger.Info("upload scanning enabled", "provider", cfg.ScanProvider, "address", cfg.ClamAVAddress)
+	}
 
 	// Initialize image service
-	imageService := service.NewImageService(repo, storageService, thumbnailProcessor, logger)
+	// Upload limits; HEIC is only accepted when it can be converted
+	if imageConverter == nil {
+		uploadLimits.ContentTypes = slices.DeleteFunc(slices.Clone(uploadLimits.ContentTypes), domain.IsConvertibleImageContentType)
+	}
+	imageService := service.NewImageService(repo, storageService, thumbnailProcessor, imageConverter, scanner, jobEnqueuer, logger)
 
-	// Initialize email service
-	emailService, err := email.NewSMTPEmailService(
-		email.SMTPConfig{
+	// Initialize email service. SMTP_BREAKER_THRESHOLD=0 disables the
+	// breaker, which SMTPConfig spells as a negative threshold.
+	smtpBreakerThreshold := cfg.SMTPBreakerThreshold
+	if smtpBreakerThreshold == 0 {
+		smtpBreakerThreshold = -1
+	}
+	emailService, err := email.New(email.Config{
+		Provider:     cfg.EmailProvider,
+		BaseURL:      cfg.BaseURL,
+		TemplatesDir: "web/templates/email",
+		SMTP: email.SMTPConfig{
 			Host:     cfg.SMTPHost,
 			Port:     cfg.SMTPPort,
 			Username: cfg.SMTPUsername,
 			Password: cfg.SMTPPassword,
-			From:     cfg.SMTPFrom,
-			FromName: cfg.SMTPFromName,
+			From:     cfg.EmailFrom,
+			FromName: cfg.EmailFromName,
+			Timeout:  cfg.EmailSendTimeout,
+
+			DialTimeout:      cfg.SMTPDialTimeout,
+			MaxAttempts:      cfg.SMTPMaxAttempts,
+			RetryBaseDelay:   cfg.SMTPRetryBaseDelay,
+			BreakerThreshold: smtpBreakerThreshold,
+			BreakerCooldown:  cfg.SMTPBreakerCooldown,
 		},
-		cfg.BaseURL,
-		"web/templates/email",
-		logger,
-	)
+		Postmark: email.PostmarkConfig{
+			ServerToken:   cfg.PostmarkToken,
+			MessageStream: cfg.PostmarkStream,
+			From:          cfg.EmailFrom,
+			FromName:      cfg.EmailFromName,
+			Timeout:       cfg.EmailSendTimeout,
+		},
+		File: email.FileConfig{
+			Dir:      cfg.EmailFileDir,
+			From:     cfg.EmailFrom,
+			FromName: cfg.EmailFromName,
+		},
+	}, logger)
 	if err != nil {
 		return fmt.Errorf("email service initialization failed: %w", err)
 	}
-	logger.Info("Email service initialized", "host", cfg.SMTPHost, "port", cfg.SMTPPort)
+	logger.Info("Email service initialized", "provider", cfg.EmailProvider)
+
+	// Emails are delivered by the worker so failed sends are retried.
+	// Without a worker nothing would process the jobs, so send inline.
+	var emailQueue email.Queue = email.NewSyncQueue(emailService)
+	if cfg.WorkerEnabled {
+		emailQueue = newWorkerEmailQueue(workerEnqueuer)
+	}
+
+	organizationService := service.NewOrganizationService(db, repo, emailQueue, logger)
 
 	// Initialize AI provider
+	aiProviderConfig := ai.ProviderConfig{
+		MaxRetries:     cfg.AIMaxRetries,
+		RetryBaseDelay: cfg.AIRetryBaseDelay,
+		RequestTimeout: cfg.AIRequestTimeout,
+		Temperature:    cfg.AITemperature,
+	}
 	var aiProvider ai.AIProvider
-	if cfg.AIProvider == "anthropic" {
+	switch cfg.AIProvider {
+	case "anthropic":
 		aiProvider, err = anthropic.New(
 			anthropic.Config{
-				APIKey: cfg.AnthropicAPIKey,
-				Model:  cfg.AnthropicModel,
-				ProviderConfig: ai.ProviderConfig{
-					MaxRetries:     cfg.AIMaxRetries,
-					RetryBaseDelay: cfg.AIRetryBaseDelay,
-					RequestTimeout: cfg.AIRequestTimeout,
-				},
+				APIKey:         cfg.AnthropicAPIKey,
+				Model:          cfg.AIModel,
+				ProviderConfig: aiProviderConfig,
 			},
 			repo,
 			logger,
@@ -150,34 +278,107 @@ func run() error {
 		if err != nil {
 			return fmt.Errorf("anthropic provider initialization failed: %w", err)
 		}
-	} else {
+	case "openai":
+		aiProvider, err = openai.New(
+			openai.Config{
+				APIKey:         cfg.OpenAIAPIKey,
+				Model:          cfg.AIModel,
+				BaseURL:        cfg.OpenAIBaseURL,
+				ProviderConfig: aiProviderConfig,
+			},
+			repo,
+			logger,
+		)
+		if err != nil {
+			return fmt.Errorf("openai provider initialization failed: %w", err)
+		}
+	default:
 		aiProvider = mock.New(logger)
 	}
-	logger.Info("AI provider initialized", "provider", cfg.AIProvider)
+	logger.Info("AI provider initialized", "provider", cfg.AIProvider, "model", cfg.AIModel, "concurrency", cfg.AIConcurrency)
+
+	// Job state changes from the in-process worker, streamed to browsers
+	// watching an analysis
+	jobEvents := jobevents.NewBroker()
 
 	// Initialize background worker
 	var jobWorker *worker.Worker
 	if cfg.WorkerEnabled {
+		// The worker gets its own pool so long-running jobs can't take
+		// every connection from HTTP requests, or the reverse
+		workerDB, err := internal.OpenDB(ctx, cfg.DatabaseUrl, cfg.WorkerPoolConfig(), slowQueryTracer(cfg, logger, "worker"))
+		if err != nil {
+			return fmt.Errorf("worker database connection failed: %w", err)
+		}
+		defer func() { _ = workerDB.Close() }()
+		metrics.RegisterDBStats(workerDB, "worker")
+		workerRepo := repository.New(workerDB)
+		workerUOW := repository.NewUnitOfWork(workerRepo)
+
+		// Services the job handlers call query through the worker's pool
+		// too. The worker never edits addresses, so its inspection service
+		// enqueues no geocoding or weather jobs.
+		workerEnqueuer := worker.NewJobEnqueuer(workerRepo, enqueueLimits)
+		workerJobEnqueuer := newServiceJobEnqueuer(workerEnqueuer)
+		workerAuditService := service.NewAuditService(workerRepo, workerUOW, logger)
+		workerWebhookService := service.NewWebhookService(workerRepo, workerEnqueuer, logger)
+		workerInspectionService := service.NewInspectionService(workerRepo, workerJobEnqueuer, quotaService, workerAuditService, nil, nil, logger)
+		workerViolationService := service.NewViolationService(workerRepo, workerUOW, workerAuditService, logger)
+		workerReportService := service.NewReportService(workerRepo, storageService, workerJobEnqueuer, quotaService, workerAuditService, preferenceService, report.DefaultBranding(), logger)
+
 		workerConfig := worker.Config{
-			Concurrency:       cfg.WorkerConcurrency,
-			PollInterval:      cfg.WorkerPollInterval,
-			JobTimeout:        cfg.WorkerJobTimeout,
-			ShutdownTimeout:   30 * time.Second,
-			StaleJobThreshold: 10 * time.Minute,
+			Concurrency:               cfg.WorkerConcurrency,
+			PollInterval:              cfg.WorkerPollInterval,
+			JobTimeout:                cfg.WorkerJobTimeout,
+			ShutdownTimeout:           shutdownTimeout,
+			StaleJobThreshold:         10 * time.Minute,
+			ReservedHighPrioritySlots: cfg.WorkerReservedHigh,
 		}
 
-		jobWorker, err = worker.New(db, repo, workerConfig, logger)
+		jobWorker, err = worker.New(workerDB, workerRepo, workerConfig, logger)
 		if err != nil {
 			return fmt.Errorf("worker initialization failed: %w", err)
 		}
-
-		// Register job handlers (reportService already initialized above)
-		jobWorker.Register(jobs.NewAnalyzeInspectionHandler(repo, aiProvider, storageService, inspectionService, violationService, logger))
-		jobWorker.Register(jobs.NewGenerateReportHandler(repo, storageService, emailService, reportService, logger, cfg.BaseURL))
+		jobWorker.WithNotifier(jobEvents)
+
+		// Register job handlers
+		jobWorker.Register(jobs.NewAnalyzeInspectionHandler(workerRepo, workerUOW, aiProvider, storageService, workerInspectionService, workerViolationService, workerWebhookService, cfg.AIConcurrency, logger).
+			WithNotifier(jobEvents))
+		jobWorker.Register(jobs.NewGenerateReportHandler(workerRepo, storageService, emailQueue, workerReportService, workerAuditService, workerWebhookService, logger, cfg.BaseURL))
+		jobWorker.Register(jobs.NewDeliverWebhookHandler(workerRepo, webhook.NewClient(webhook.ClientConfig{}), logger))
+		jobWorker.Register(jobs.NewSendEmailHandler(emailService, logger))
+		jobWorker.Register(jobs.NewGeocodeInspectionHandler(workerRepo, geocoder, logger))
+		jobWorker.Register(jobs.NewFillInspectionWeatherHandler(weatherService, logger))
+		jobWorker.Register(jobs.NewVerificationRemindersHandler(workerRepo, userService, emailQueue, jobs.VerificationRemindersConfig{
+			Delay:        cfg.VerificationReminderDelay,
+			MaxReminders: cfg.VerificationReminderMax,
+		}, logger))
+		jobWorker.Register(jobs.NewPurgeInspectionsHandler(workerRepo, storageService, workerAuditService, jobs.PurgeInspectionsConfig{
+			Months:         cfg.RetentionMonths,
+			DryRun:         cfg.RetentionDryRun,
+			ThumbnailSizes: thumbnailProcessor.Config().Sizes,
+		}, logger))
 
 		// Start the worker
 		jobWorker.Start(ctx)
 		logger.Info("Background worker started", "concurrency", workerConfig.Concurrency)
+
+		// Verification reminders recur by rescheduling themselves after each run
+		if cfg.VerificationReminderMax > 0 {
+			if _, err := worker.ScheduleVerificationReminders(ctx, repo); err != nil {
+				logger.Error("failed to schedule verification reminders", "error", err)
+			}
+		}
+
+		// The retention purge recurs the same way; a zero period keeps everything
+		if cfg.RetentionMonths > 0 {
+			if _, err := worker.SchedulePurgeInspections(ctx, repo); err != nil {
+				logger.Error("failed to schedule inspection purge", "error", err)
+			}
+			if cfg.RetentionDryRun {
+				logger.Info("data retention purge in dry-run mode", "retention_months", cfg.RetentionMonths)
+			}
+		}
 	}
 
 	// Initialize invite code validator for MVP testing
@@ -206,17 +407,39 @@ func run() error {
 		"password_reset_limit", "3 per hour",
 	)
 
-	authHandler := handler.NewAuthHandler(userService, emailService, inviteValidator, logger, isSecure).
+	authHandler := handler.NewAuthHandler(userService, emailQueue, inviteValidator, logger, isSecure).
+		WithSessionDuration(cfg.SessionDuration).
 		WithRateLimiter(authRateLimiter)
-	dashboardHandler := handler.NewDashboardHandler(repo, logger)
-	inspectionHandler := handler.NewInspectionHandler(inspectionService, imageService, violationService, clientService, reportService, logger)
-	imageHandler := handler.NewImageHandler(imageService, inspectionService, logger)
+	dashboardHandler := handler.NewDashboardHandler(repo, inspectionService, logger)
+	inspectionHandler := handler.NewInspectionHandler(inspectionService, imageService, violationService, clientService, reportService, quotaService, logger).
+		WithOrganizations(organizationService).
+		WithPreferences(preferenceService).
+		WithRegulations(regulationService).
+		WithUploadLimits(uploadLimits)
+	if jobWorker != nil {
+		// Without an in-process worker nothing publishes, so pages poll
+		inspectionHandler.WithJobEvents(jobEvents)
+	}
+	imageHandler := handler.NewImageHandler(imageService, inspectionService, logger).WithUploadLimits(uploadLimits)
+	if cfg.StorageProvider == storage.ProviderLocal {
+		imageHandler.WithDirectServing()
+	}
 	violationHandler := handler.NewViolationHandler(violationService, inspectionService, imageService, logger)
-	regulationHandler := handler.NewRegulationHandler(regulationService, violationService, logger)
-	settingsHandler := handler.NewSettingsHandler(userService, logger)
+	regulationHandler := handler.NewRegulationHandler(regulationService, violationService, logger).
+		WithPreferences(preferenceService)
+	settingsHandler := handler.NewSettingsHandler(userService, quotaService, webhookService, brandingService, organizationService, logger, isSecure).
+		WithPreferences(preferenceService).
+		WithReportTemplates(service.NewReportTemplateService(repo, logger))
 	clientHandler := handler.NewClientHandler(clientService, logger)
 	reportHandler := handler.NewReportHandler(reportService, storageService, logger)
-	adminHandler := handler.NewAdminHandler(repo, logger)
+	archiveHandler := handler.NewArchiveHandler(inspectionService, imageService, violationService, reportService, storageService, logger)
+	shareHandler := handler.NewShareHandler(inspectionService, reportService, cfg.BaseURL, logger)
+	commentHandler := handler.NewCommentHandler(inspectionService, logger)
+	jobHandler := handler.NewJobHandler(service.NewJobService(repo, logger), logger)
+	geocodeHandler := handler.NewGeocodeHandler(geocoder, logger)
+	weatherHandler := handler.NewWeatherHandler(weatherService, logger)
+	adminHandler := handler.NewAdminHandler(repo, userService, auditService, logger).
+		WithRegulationImports(service.NewRegulationImportService(db, repo, logger))
 	// Initialize billing service (conditionally — nil when Stripe not configured)
 	var billingService billing.Service
 	if cfg.StripeSecretKey != "" {
@@ -241,8 +464,9 @@ func run() error {
 		ProfessionalMonthlyPriceID: cfg.StripeProfessionalMonthlyPriceID,
 		ProfessionalYearlyPriceID:  cfg.StripeProfessionalYearlyPriceID,
 	}
+	webhookEventService := service.NewWebhookEventService(repo, logger)
 	billingHandler := handler.NewBillingHandler(billingService, userService, cfg.BaseURL, billingPrices, logger)
-	webhookHandler := handler.NewWebhookHandler(billingService, userService, logger)
+	webhookHandler := handler.NewWebhookHandler(billingService, userService, webhookEventService, logger)
 
 	// ==========================================================================
 	// Create router and register routes
@@ -310,31 +534,49 @@ func run() error {
 		}
 	})
 
+	// CSP violation reports (public - browsers post them without credentials)
+	mux.Handle("POST "+middleware.CSPReportPath, middleware.CSPReportHandler(logger))
+
 	// Auth routes (public - no auth required)
 	// Using templ-based routes with CSRF protection
 	authHandler.RegisterTemplRoutes(mux)
 
 	// Create middleware stacks for protected routes
+	// Unverified users can browse; analysis and report generation require a
+	// verified email once the grace period after sign-up has passed.
 	requireUser := middleware.Stack(authMw.WithUser, authMw.RequireUser)
-	requireVerified := middleware.Stack(authMw.WithUser, authMw.RequireUser, authMw.RequireEmailVerified)
-	requireSubscription := middleware.Stack(authMw.WithUser, authMw.RequireUser, authMw.RequireEmailVerified, authMw.RequireActiveSubscription)
+	requireVerified := middleware.Stack(authMw.WithUser, authMw.RequireUser, authMw.RequireEmailVerifiedAfterGrace)
+	requireSubscription := middleware.Stack(authMw.WithUser, authMw.RequireUser, authMw.RequireEmailVerifiedAfterGrace, authMw.RequireActiveSubscription)
 	requireAdmin := middleware.Stack(authMw.WithUser, authMw.RequireUser, authMw.RequireAdmin)
 
 	// Email verification reminder (requires auth, but NOT email verification)
 	authHandler.RegisterVerifyEmailReminderRoutes(mux, requireUser)
 
-	// Dashboard (requires authentication + verified email) - using templ
-	mux.Handle("GET /dashboard", requireVerified(http.HandlerFunc(dashboardHandler.ShowTempl)))
-
-	// Feature routes (requires authentication + verified email)
-	inspectionHandler.RegisterTemplRoutes(mux, requireVerified)
-	imageHandler.RegisterRoutes(mux, requireVerified)
-	violationHandler.RegisterRoutes(mux, requireVerified)
-	regulationHandler.RegisterTemplRoutes(mux, requireVerified)
-	clientHandler.RegisterTemplRoutes(mux, requireVerified)
-	reportHandler.RegisterRoutes(mux, requireVerified)
-
-	// Subscription-gated routes (requires active subscription)
+	// Log out of all devices (requires auth to know whose sessions to revoke)
+	authHandler.RegisterLogoutAllRoutes(mux, requireUser)
+
+	// Dashboard (requires authentication) - using templ
+	mux.Handle("GET /dashboard", requireUser(http.HandlerFunc(dashboardHandler.ShowTempl)))
+	mux.Handle("GET /dashboard/map-data", requireUser(http.HandlerFunc(dashboardHandler.MapData)))
+
+	// Feature routes (requires authentication)
+	inspectionHandler.RegisterTemplRoutes(mux, requireUser)
+	imageHandler.RegisterRoutes(mux, requireUser)
+	violationHandler.RegisterRoutes(mux, requireUser)
+	regulationHandler.RegisterTemplRoutes(mux, requireUser)
+	clientHandler.RegisterTemplRoutes(mux, requireUser)
+	reportHandler.RegisterRoutes(mux, requireUser)
+	archiveHandler.RegisterRoutes(mux, requireUser)
+	shareHandler.RegisterRoutes(mux, requireUser)
+	commentHandler.RegisterRoutes(mux, requireUser)
+	jobHandler.RegisterRoutes(mux, requireUser)
+	geocodeHandler.RegisterRoutes(mux, requireUser)
+	weatherHandler.RegisterRoutes(mux, requireUser)
+
+	// Verification-gated routes (verified email, or still within the grace period)
+	mux.Handle("POST /images/{id}/reanalyze", requireVerified(http.HandlerFunc(imageHandler.Reanalyze)))
+
+	// Subscription-gated routes (verification gate + active subscription)
 	mux.Handle("POST /inspections/{id}/analyze", requireSubscription(http.HandlerFunc(inspectionHandler.TriggerAnalysis)))
 	mux.Handle("POST /inspections/{id}/reports", requireSubscription(http.HandlerFunc(inspectionHandler.GenerateReport)))
 
@@ -353,17 +595,48 @@ func run() error {
 	// ==========================================================================
 
 	// Apply middleware chain (outermost first)
-	// 1. Request logging (logs all requests with timing)
-	// 2. Security headers (sets HTTP security headers)
-	// 3. Metrics (Prometheus metrics collection)
-	requestLoggingMw := middleware.NewRequestLoggingMiddleware(logger)
+	// 1. In-flight tracking (lets shutdown drain active requests)
+	// 2. Request ID (correlates log lines and jobs for a request)
+	// 3. Request logging (access log with route, status, size, timing, and user)
+	// 4. Timeout (bounds each request's context; uploads and downloads get longer)
+	// 5. Compression (gzips text responses for clients that accept it)
+	// 6. Security headers (sets HTTP security headers)
+	// 7. CORS (answers preflight and adds CORS headers on /api/ routes)
+	// 8. Metrics (Prometheus metrics collection)
+	inFlight := middleware.NewInFlightTracker()
+	requestLoggingMw := middleware.NewRequestLoggingMiddleware(logger).
+		WithRoutes(mux).
+		WithSlowThreshold(cfg.LogSlowRequestThreshold).
+		WithSampleRate(cfg.LogRequestSampleRate)
+	timeoutMw := middleware.NewTimeout(middleware.TimeoutConfig{
+		Request: cfg.HTTPRequestTimeout,
+		Long:    cfg.HTTPLongRequestTimeout,
+		LongRunning: []string{
+			"POST /inspections/{id}/images",
+			"POST /settings/business",
+			"POST /account/business", // Logo upload alias of /settings/business
+			"GET /inspections/{id}/archive.zip",
+			"GET /inspections/{id}/progress", // Analysis progress stream
+		},
+	})
 	securityMw := middleware.NewSecurityHeadersMiddleware(isSecure)
-	handler := requestLoggingMw.Handler(securityMw.Handler(metrics.Middleware(mux)))
-	logger.Info("middleware enabled", "request_logging", true, "security_headers", true, "hsts", isSecure)
+	corsMw := middleware.NewCORS(middleware.CORSConfig{
+		AllowedOrigins: cfg.CORSAllowedOrigins,
+		AllowedMethods: cfg.CORSAllowedMethods,
+		AllowedHeaders: cfg.CORSAllowedHeaders,
+	})
+	if corsMw.Enabled() {
+		logger.Info("CORS enabled for API routes", "origins", cfg.CORSAllowedOrigins)
+	}
+	handler := inFlight.Handler(middleware.RequestID(requestLoggingMw.Handler(timeoutMw.Handler(middleware.Compress(securityMw.Handler(corsMw.Handler(metrics.Middleware(mux))))))))
+	logger.Info("middleware enabled", "request_logging", true, "request_timeout", cfg.HTTPRequestTimeout, "compression", true, "security_headers", true, "hsts", isSecure)
 
 	server := &http.Server{
-		Addr:    fmt.Sprintf(":%d", cfg.Port),
-		Handler: handler,
+		Addr:              fmt.Sprintf(":%d", cfg.Port),
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.HTTPReadHeaderTimeout,
+		WriteTimeout:      cfg.HTTPWriteTimeout,
+		IdleTimeout:       cfg.HTTPIdleTimeout,
 	}
 
 	// Channel to listen for interrupt signals
@@ -382,24 +655,65 @@ func run() error {
 	<-sigChan
 	logger.Info("Shutdown signal received, initiating graceful shutdown...")
 
-	// Stop background worker first (if running)
-	if jobWorker != nil {
-		logger.Info("Stopping background worker...")
-		jobWorker.Stop()
-	}
-
 	// Create shutdown context with timeout
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
 	defer cancel()
 
-	if err := server.Shutdown(shutdownCtx); err != nil {
-		logger.Error("Server shutdown error", "error", err)
+	// End analysis progress streams so they don't hold up the drain;
+	// browsers reconnect to another instance or poll
+	jobEvents.Close()
+
+	// Stop the background worker while HTTP requests drain
+	var workerStopped sync.WaitGroup
+	if jobWorker != nil {
+		workerStopped.Add(1)
+		go func() {
+			defer workerStopped.Done()
+			logger.Info("Stopping background worker...")
+			jobWorker.Stop()
+		}()
 	}
 
+	drainHTTP(shutdownCtx, server, inFlight, logger)
+	workerStopped.Wait()
+
 	logger.Info("Graceful shutdown complete")
 	return nil
 }
 
+// slowQueryTracer returns the tracer that logs the pool's slow queries, or
+// nil if slow query logging is disabled.
+func slowQueryTracer(cfg *internal.Config, logger *slog.Logger, pool string) pgx.QueryTracer {
+	if !cfg.DBLogSlowQueries {
+		return nil
+	}
+	return dbtrace.NewSlowQueryLogger(logger.With("db_pool", pool), cfg.DBSlowQueryThreshold)
+}
+
+// shutdownTimeout bounds how long shutdown waits for in-flight HTTP requests.
+const shutdownTimeout = 30 * time.Second
+
+// drainHTTP stops the server accepting new requests, then waits for requests
+// already in flight to finish until ctx expires, logging how many drained.
+func drainHTTP(ctx context.Context, server *http.Server, inFlight *middleware.InFlightTracker, logger *slog.Logger) {
+	pending := inFlight.InFlight()
+	logger.Info("Draining in-flight HTTP requests...", "in_flight", pending)
+
+	if err := server.Shutdown(ctx); err != nil {
+		logger.Error("Server shutdown error", "error", err)
+	}
+
+	err := inFlight.Wait(ctx)
+	remaining := inFlight.InFlight()
+	drained := max(pending-remaining, 0)
+	if err != nil {
+		logger.Warn("Shutdown timeout reached before all HTTP requests finished",
+			"drained", drained, "abandoned", remaining)
+		return
+	}
+	logger.Info("In-flight HTTP requests drained", "drained", drained)
+}
+
 func main() {
 	if err := run(); err != nil {
 		log.Fatal(err)
@@ -427,7 +741,43 @@ func (a *serviceJobEnqueuer) EnqueueAnalyzeInspection(ctx context.Context, inspe
 	return a.enqueuer.EnqueueAnalyzeInspection(ctx, inspectionID, userID)
 }
 
+// EnqueueAnalyzeImages implements service.JobEnqueuer.
+func (a *serviceJobEnqueuer) EnqueueAnalyzeImages(ctx context.Context, inspectionID, userID uuid.UUID, imageIDs []uuid.UUID, force bool) (repository.Job, error) {
+	return a.enqueuer.EnqueueAnalyzeImages(ctx, inspectionID, userID, imageIDs, force)
+}
+
 // EnqueueGenerateReport implements service.JobEnqueuer.
-func (a *serviceJobEnqueuer) EnqueueGenerateReport(ctx context.Context, inspectionID, userID uuid.UUID, format, recipientEmail string) (repository.Job, error) {
-	return a.enqueuer.EnqueueGenerateReport(ctx, inspectionID, userID, format, recipientEmail)
+func (a *serviceJobEnqueuer) EnqueueGenerateReport(ctx context.Context, inspectionID, ownerID uuid.UUID, format string, recipientEmails []string) (repository.Job, error) {
+	return a.enqueuer.EnqueueGenerateReport(ctx, inspectionID, ownerID, format, recipientEmails)
+}
+
+// EnqueueGeocodeInspection implements service.GeocodeEnqueuer.
+func (a *serviceJobEnqueuer) EnqueueGeocodeInspection(ctx context.Context, inspectionID, userID uuid.UUID, address string) (repository.Job, error) {
+	return a.enqueuer.EnqueueGeocodeInspection(ctx, inspectionID, userID, address)
+}
+
+// EnqueueFillInspectionWeather implements service.WeatherEnqueuer.
+func (a *serviceJobEnqueuer) EnqueueFillInspectionWeather(ctx context.Context, inspectionID, userID uuid.UUID) (repository.Job, error) {
+	return a.enqueuer.EnqueueFillInspectionWeather(ctx, inspectionID, userID)
+}
+
+// =============================================================================
+// Email Queue Adapter
+// =============================================================================
+
+// workerEmailQueue adapts worker.JobEnqueuer to email.Queue so handlers can
+// queue emails without depending on the worker package.
+type workerEmailQueue struct {
+	enqueuer worker.JobEnqueuer
+}
+
+// newWorkerEmailQueue creates a new adapter.
+func newWorkerEmailQueue(enqueuer worker.JobEnqueuer) *workerEmailQueue {
+	return &workerEmailQueue{enqueuer: enqueuer}
+}
+
+// Enqueue implements email.Queue.
+func (q *workerEmailQueue) Enqueue(ctx context.Context, msg email.Message) error {
+	_, err := q.enqueuer.EnqueueSendEmail(ctx, msg.Template, msg.To, msg.Data)
+	return err
 }
@@ -3,6 +3,7 @@ module github.com/DukeRupert/lukaut
 go 1.24.0
 
 require (
+	github.com/HugoSmits86/nativewebp v1.3.0
 	github.com/Oudwins/tailwind-merge-go v0.2.1
 	github.com/a-h/templ v0.3.977
 	github.com/aws/aws-sdk-go-v2 v1.41.0
@@ -48,7 +49,7 @@ require (
 	github.com/stripe/stripe-go/v79 v79.12.0 // indirect
 	go.uber.org/multierr v1.11.0 // indirect
 	go.yaml.in/yaml/v2 v2.4.2 // indirect
-	golang.org/x/image v0.18.0 // indirect
+	golang.org/x/image v0.24.0 // indirect
 	golang.org/x/sync v0.19.0 // indirect
 	golang.org/x/sys v0.35.0 // indirect
 	google.golang.org/protobuf v1.36.8 // indirect
@@ -77,6 +77,7 @@ type RegulationMatch struct {
 
 // UsageInfo tracks API usage for billing and monitoring
 type UsageInfo struct {
+	Provider     string        // AI provider used (e.g., "anthropic")
 	Model        string        // AI model used
 	InputTokens  int           // Tokens in the request
 	OutputTokens int           // Tokens in the response
@@ -128,6 +129,7 @@ type ProviderConfig struct {
 	MaxRetries     int           // Maximum retry attempts for transient errors
 	RetryBaseDelay time.Duration // Base delay for exponential backoff
 	RequestTimeout time.Duration // Timeout for individual requests
+	Temperature    float64       // Sampling temperature (0-1); lower is more consistent between runs
 }
 
 // Error codes for AI provider operations
@@ -151,6 +153,31 @@ var (
 	ErrAIUnauthorized = errors.New("ai provider authentication failed")
 )
 
+// ValidateImageParams checks that params carry a supported image no larger
+// than maxSize bytes
+func ValidateImageParams(params AnalyzeImageParams, maxSize int) error {
+	if len(params.ImageData) == 0 {
+		return ErrAIInvalidImage
+	}
+	if len(params.ImageData) > maxSize {
+		return fmt.Errorf("%w: image size %d exceeds maximum %d", ErrAIInvalidImage, len(params.ImageData), maxSize)
+	}
+	if params.ContentType == "" {
+		return fmt.Errorf("%w: content type is required", ErrAIInvalidImage)
+	}
+	// Validate content type
+	validTypes := map[string]bool{
+		"image/jpeg": true,
+		"image/png":  true,
+		"image/gif":  true,
+		"image/webp": true,
+	}
+	if !validTypes[params.ContentType] {
+		return fmt.Errorf("%w: unsupported content type %s", ErrAIInvalidImage, params.ContentType)
+	}
+	return nil
+}
+
 // IsRetryable returns true if the error is a transient error that can be retried
 func IsRetryable(err error) bool {
 	return errors.Is(err, ErrAIRateLimit) ||
@@ -14,10 +14,12 @@ import (
 	"github.com/DukeRupert/lukaut/internal/ai"
 	"github.com/DukeRupert/lukaut/internal/metrics"
 	"github.com/DukeRupert/lukaut/internal/repository"
-	"github.com/google/uuid"
 )
 
 const (
+	// ProviderName identifies this provider in usage records
+	ProviderName = "anthropic"
+
 	// APIBaseURL is the base URL for the Anthropic API
 	APIBaseURL = "https://api.anthropic.com/v1/messages"
 
@@ -85,7 +87,7 @@ func (p *Provider) AnalyzeImage(ctx context.Context, params ai.AnalyzeImageParam
 	startTime := time.Now()
 
 	// Validate input
-	if err := p.validateImageParams(params); err != nil {
+	if err := ai.ValidateImageParams(params, MaxImageSize); err != nil {
 		metrics.AIAPICalls.WithLabelValues("error").Inc()
 		return nil, ai.WrapError("analyze image", err)
 	}
@@ -114,6 +116,7 @@ func (p *Provider) AnalyzeImage(ctx context.Context, params ai.AnalyzeImageParam
 	// Calculate cost
 	duration := time.Since(startTime)
 	result.Usage = ai.UsageInfo{
+		Provider:     ProviderName,
 		Model:        p.config.Model,
 		InputTokens:  resp.Usage.InputTokens,
 		OutputTokens: resp.Usage.OutputTokens,
@@ -122,77 +125,18 @@ func (p *Provider) AnalyzeImage(ctx context.Context, params ai.AnalyzeImageParam
 	}
 
 	// Track usage in database
-	if err := p.trackUsage(ctx, params.UserID, params.InspectionID, result.Usage, "analyze_image"); err != nil {
+	if err := ai.TrackUsage(ctx, p.queries, params.UserID, params.InspectionID, result.Usage, "analyze_image"); err != nil {
 		// Log but don't fail the request
 		p.logger.Error("Failed to track AI usage", "error", err)
 	}
 
-	// Track aggregate cost metrics (no user label - low cardinality)
-	metrics.AITokensTotal.WithLabelValues("input").Add(float64(result.Usage.InputTokens))
-	metrics.AITokensTotal.WithLabelValues("output").Add(float64(result.Usage.OutputTokens))
-	metrics.AICostCentsTotal.Add(float64(result.Usage.CostCents))
-
-	metrics.AIAPICalls.WithLabelValues("success").Inc()
+	ai.RecordUsageMetrics(result.Usage)
 	return result, nil
 }
 
 // MatchRegulations finds relevant OSHA regulations using PostgreSQL full-text search
 func (p *Provider) MatchRegulations(ctx context.Context, params ai.MatchParams) ([]ai.RegulationMatch, error) {
-	// Default max results
-	maxResults := params.MaxResults
-	if maxResults <= 0 {
-		maxResults = 10
-	}
-
-	// Search regulations using full-text search
-	results, err := p.queries.SearchRegulations(ctx, repository.SearchRegulationsParams{
-		WebsearchToTsquery: params.ViolationDescription,
-		Limit:              int32(maxResults),
-	})
-	if err != nil {
-		return nil, fmt.Errorf("search regulations: %w", err)
-	}
-
-	// Convert to domain types
-	matches := make([]ai.RegulationMatch, 0, len(results))
-	for i, result := range results {
-		match := ai.RegulationMatch{
-			RegulationID:   result.ID,
-			StandardNumber: result.StandardNumber,
-			Title:          result.Title,
-			Category:       result.Category,
-			RelevanceScore: float64(result.Rank),
-			Explanation:    "",     // No explanation in MVP - could add AI-generated explanation later
-			IsPrimary:      i == 0, // First result is primary
-		}
-		matches = append(matches, match)
-	}
-
-	return matches, nil
-}
-
-// validateImageParams validates the image analysis parameters
-func (p *Provider) validateImageParams(params ai.AnalyzeImageParams) error {
-	if len(params.ImageData) == 0 {
-		return ai.ErrAIInvalidImage
-	}
-	if len(params.ImageData) > MaxImageSize {
-		return fmt.Errorf("%w: image size %d exceeds maximum %d", ai.ErrAIInvalidImage, len(params.ImageData), MaxImageSize)
-	}
-	if params.ContentType == "" {
-		return fmt.Errorf("%w: content type is required", ai.ErrAIInvalidImage)
-	}
-	// Validate content type
-	validTypes := map[string]bool{
-		"image/jpeg": true,
-		"image/png":  true,
-		"image/gif":  true,
-		"image/webp": true,
-	}
-	if !validTypes[params.ContentType] {
-		return fmt.Errorf("%w: unsupported content type %s", ai.ErrAIInvalidImage, params.ContentType)
-	}
-	return nil
+	return ai.SearchRegulations(ctx, p.queries, params)
 }
 
 // buildAnalyzeImageRequest builds the HTTP request for image analysis
@@ -202,8 +146,9 @@ func (p *Provider) buildAnalyzeImageRequest(ctx context.Context, params ai.Analy
 
 	// Build the request body
 	reqBody := apiRequest{
-		Model:     p.config.Model,
-		MaxTokens: 4096,
+		Model:       p.config.Model,
+		MaxTokens:   4096,
+		Temperature: p.config.ProviderConfig.Temperature,
 		Messages: []apiMessage{
 			{
 				Role: "user",
@@ -218,7 +163,7 @@ func (p *Provider) buildAnalyzeImageRequest(ctx context.Context, params ai.Analy
 					},
 					{
 						Type: "text",
-						Text: buildImageAnalysisPrompt(params.Context),
+						Text: ai.ImageAnalysisPrompt(params.Context),
 					},
 				},
 			},
@@ -368,51 +313,7 @@ func (p *Provider) parseAnalysisResponse(resp *apiResponse) (*ai.AnalysisResult,
 		return nil, fmt.Errorf("no text content in response")
 	}
 
-	// Parse JSON from text content
-	var output analysisOutput
-	if err := json.Unmarshal([]byte(textContent), &output); err != nil {
-		return nil, fmt.Errorf("parse analysis output: %w", err)
-	}
-
-	// Convert to domain types
-	result := &ai.AnalysisResult{
-		Violations:          make([]ai.PotentialViolation, 0, len(output.Violations)),
-		GeneralObservations: output.GeneralObservations,
-		ImageQualityNotes:   output.ImageQualityNotes,
-	}
-
-	for _, v := range output.Violations {
-		violation := ai.PotentialViolation{
-			Description:          v.Description,
-			Location:             v.Location,
-			Confidence:           ai.Confidence(v.Confidence),
-			Category:             v.Category,
-			Severity:             ai.Severity(v.Severity),
-			SuggestedRegulations: v.SuggestedRegulations,
-		}
-
-		// Add bounding box if present
-		if v.BoundingBox != nil {
-			violation.BoundingBox = &ai.BoundingBox{
-				X:      v.BoundingBox.X,
-				Y:      v.BoundingBox.Y,
-				Width:  v.BoundingBox.Width,
-				Height: v.BoundingBox.Height,
-			}
-		}
-
-		// Validate and set defaults
-		if !violation.Confidence.Valid() {
-			violation.Confidence = ai.ConfidenceMedium
-		}
-		if !violation.Severity.Valid() {
-			violation.Severity = ai.SeverityOther
-		}
-
-		result.Violations = append(result.Violations, violation)
-	}
-
-	return result, nil
+	return ai.ParseAnalysisOutput(textContent)
 }
 
 // calculateCost calculates the cost in cents for the given token usage
@@ -422,29 +323,13 @@ func (p *Provider) calculateCost(inputTokens, outputTokens int) int {
 	return inputCost + outputCost
 }
 
-// trackUsage records AI usage in the database
-func (p *Provider) trackUsage(ctx context.Context, userID, inspectionID uuid.UUID, usage ai.UsageInfo, requestType string) error {
-	_, err := p.queries.CreateAIUsage(ctx, repository.CreateAIUsageParams{
-		UserID: userID,
-		InspectionID: uuid.NullUUID{
-			UUID:  inspectionID,
-			Valid: inspectionID != uuid.Nil,
-		},
-		Model:        usage.Model,
-		InputTokens:  int32(usage.InputTokens),
-		OutputTokens: int32(usage.OutputTokens),
-		CostCents:    int32(usage.CostCents),
-		RequestType:  requestType,
-	})
-	return err
-}
-
 // API request/response types
 
 type apiRequest struct {
-	Model     string       `json:"model"`
-	MaxTokens int          `json:"max_tokens"`
-	Messages  []apiMessage `json:"messages"`
+	Model       string       `json:"model"`
+	MaxTokens   int          `json:"max_tokens"`
+	Temperature float64      `json:"temperature"`
+	Messages    []apiMessage `json:"messages"`
 }
 
 type apiMessage struct {
@@ -492,27 +377,3 @@ type apiError struct {
 	Type    string `json:"type"`
 	Message string `json:"message"`
 }
-
-// analysisOutput represents the JSON structure returned by Claude
-type analysisOutput struct {
-	Violations          []outputViolation `json:"violations"`
-	GeneralObservations string            `json:"general_observations"`
-	ImageQualityNotes   string            `json:"image_quality_notes"`
-}
-
-type outputViolation struct {
-	Description          string             `json:"description"`
-	Location             string             `json:"location"`
-	BoundingBox          *outputBoundingBox `json:"bounding_box,omitempty"`
-	Confidence           string             `json:"confidence"`
-	Category             string             `json:"category"`
-	Severity             string             `json:"severity"`
-	SuggestedRegulations []string           `json:"suggested_regulations"`
-}
-
-type outputBoundingBox struct {
-	X      float64 `json:"x"`
-	Y      float64 `json:"y"`
-	Width  float64 `json:"width"`
-	Height float64 `json:"height"`
-}
@@ -3,15 +3,23 @@ package mock
 import (
 	"context"
 	"log/slog"
+	"sync"
 	"time"
 
 	"github.com/DukeRupert/lukaut/internal/ai"
 	"github.com/google/uuid"
 )
 
-// Provider is a mock AI provider for testing and development
+// ProviderName identifies this provider in usage records
+const ProviderName = "mock"
+
+// Provider is a mock AI provider for testing and local development without
+// API keys. It is deterministic: every image gets the same canned violations
+// and every regulation match the same ID, so tests can assert on exact results.
+// It is safe for concurrent use by the analysis pool.
 type Provider struct {
 	logger *slog.Logger
+	mu     sync.Mutex
 
 	// Configurable responses for testing
 	AnalyzeImageResponse     *ai.AnalysisResult
@@ -33,6 +41,8 @@ func New(logger *slog.Logger) *Provider {
 
 // AnalyzeImage returns a canned response with sample violations
 func (p *Provider) AnalyzeImage(ctx context.Context, params ai.AnalyzeImageParams) (*ai.AnalysisResult, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 	p.AnalyzeImageCalls++
 
 	// If a custom response or error is set, use it
@@ -92,6 +102,7 @@ func (p *Provider) AnalyzeImage(ctx context.Context, params ai.AnalyzeImageParam
 		GeneralObservations: "Active construction site with multiple workers. Scaffolding is prominently featured. Overall site appears moderately organized but has several safety concerns.",
 		ImageQualityNotes:   "Image quality is good with clear visibility. Adequate lighting and resolution for safety analysis.",
 		Usage: ai.UsageInfo{
+			Provider:     ProviderName,
 			Model:        "mock-ai-v1",
 			InputTokens:  1250,
 			OutputTokens: 850,
@@ -103,6 +114,8 @@ func (p *Provider) AnalyzeImage(ctx context.Context, params ai.AnalyzeImageParam
 
 // MatchRegulations returns canned regulation matches
 func (p *Provider) MatchRegulations(ctx context.Context, params ai.MatchParams) ([]ai.RegulationMatch, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 	p.MatchRegulationsCalls++
 
 	// If a custom response or error is set, use it
@@ -116,7 +129,7 @@ func (p *Provider) MatchRegulations(ctx context.Context, params ai.MatchParams)
 	// Default canned response based on common categories
 	matches := []ai.RegulationMatch{
 		{
-			RegulationID:   uuid.New(), // Mock UUID
+			RegulationID:   regulationID("1926.501(b)(1)"),
 			StandardNumber: "1926.501(b)(1)",
 			Title:          "Fall protection - Unprotected sides and edges",
 			Category:       "Fall Protection",
@@ -125,7 +138,7 @@ func (p *Provider) MatchRegulations(ctx context.Context, params ai.MatchParams)
 			IsPrimary:      true,
 		},
 		{
-			RegulationID:   uuid.New(),
+			RegulationID:   regulationID("1926.451(g)(1)"),
 			StandardNumber: "1926.451(g)(1)",
 			Title:          "Scaffolding - Guardrail systems",
 			Category:       "Scaffolding",
@@ -143,8 +156,15 @@ func (p *Provider) MatchRegulations(ctx context.Context, params ai.MatchParams)
 	return matches, nil
 }
 
+// regulationID derives a stable mock ID from a standard number
+func regulationID(standardNumber string) uuid.UUID {
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte("lukaut-mock-regulation:"+standardNumber))
+}
+
 // Reset clears call counters and custom responses for testing
 func (p *Provider) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 	p.AnalyzeImageCalls = 0
 	p.MatchRegulationsCalls = 0
 	p.AnalyzeImageResponse = nil
@@ -0,0 +1,50 @@
+package mock
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/DukeRupert/lukaut/internal/ai"
+)
+
+func TestProvider_IsDeterministic(t *testing.T) {
+	ctx := context.Background()
+	p := New(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	first, err := p.AnalyzeImage(ctx, ai.AnalyzeImageParams{ImageData: []byte("a")})
+	if err != nil {
+		t.Fatalf("AnalyzeImage failed: %v", err)
+	}
+	second, _ := p.AnalyzeImage(ctx, ai.AnalyzeImageParams{ImageData: []byte("b")})
+	if !reflect.DeepEqual(first, second) {
+		t.Error("expected the same analysis for every image")
+	}
+
+	matchesA, _ := p.MatchRegulations(ctx, ai.MatchParams{ViolationDescription: "guardrail"})
+	matchesB, _ := New(nil).MatchRegulations(ctx, ai.MatchParams{ViolationDescription: "guardrail"})
+	if !reflect.DeepEqual(matchesA, matchesB) {
+		t.Error("expected stable regulation matches, including IDs")
+	}
+}
+
+func TestProvider_ConcurrentCalls(t *testing.T) {
+	p := New(nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = p.AnalyzeImage(context.Background(), ai.AnalyzeImageParams{})
+		}()
+	}
+	wg.Wait()
+
+	if p.AnalyzeImageCalls != 10 {
+		t.Errorf("expected 10 calls to be counted, got %d", p.AnalyzeImageCalls)
+	}
+}
@@ -0,0 +1,339 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/ai"
+	"github.com/DukeRupert/lukaut/internal/metrics"
+	"github.com/DukeRupert/lukaut/internal/repository"
+)
+
+const (
+	// ProviderName identifies this provider in usage records
+	ProviderName = "openai"
+
+	// DefaultBaseURL is the base URL for the OpenAI API. Any endpoint that
+	// implements the chat completions API with image input can be used instead.
+	DefaultBaseURL = "https://api.openai.com/v1"
+
+	// DefaultModel is the default vision model to use
+	DefaultModel = "gpt-4o"
+
+	// MaxImageSize is the maximum image size in bytes (20MB)
+	MaxImageSize = 20 * 1024 * 1024
+
+	// Pricing in cents per 1M tokens for gpt-4o
+	PricingInputCents  = 250  // $2.50 per 1M input tokens
+	PricingOutputCents = 1000 // $10 per 1M output tokens
+)
+
+// Config contains configuration for the OpenAI provider
+type Config struct {
+	APIKey         string
+	Model          string
+	BaseURL        string // Optional endpoint override, e.g. an OpenAI-compatible gateway
+	ProviderConfig ai.ProviderConfig
+}
+
+// Provider implements the AIProvider interface using the OpenAI chat
+// completions API
+type Provider struct {
+	config  Config
+	client  *http.Client
+	queries *repository.Queries
+	logger  *slog.Logger
+}
+
+// New creates a new OpenAI provider
+func New(config Config, queries *repository.Queries, logger *slog.Logger) (*Provider, error) {
+	if config.APIKey == "" {
+		return nil, fmt.Errorf("openai API key is required")
+	}
+
+	// Set defaults
+	if config.Model == "" {
+		config.Model = DefaultModel
+	}
+	if config.BaseURL == "" {
+		config.BaseURL = DefaultBaseURL
+	}
+	config.BaseURL = strings.TrimSuffix(config.BaseURL, "/")
+	if config.ProviderConfig.MaxRetries == 0 {
+		config.ProviderConfig.MaxRetries = 3
+	}
+	if config.ProviderConfig.RetryBaseDelay == 0 {
+		config.ProviderConfig.RetryBaseDelay = 1 * time.Second
+	}
+	if config.ProviderConfig.RequestTimeout == 0 {
+		config.ProviderConfig.RequestTimeout = 60 * time.Second
+	}
+
+	return &Provider{
+		config: config,
+		client: &http.Client{
+			Timeout: config.ProviderConfig.RequestTimeout,
+		},
+		queries: queries,
+		logger:  logger,
+	}, nil
+}
+
+// AnalyzeImage analyzes a construction site image for OSHA violations
+func (p *Provider) AnalyzeImage(ctx context.Context, params ai.AnalyzeImageParams) (*ai.AnalysisResult, error) {
+	startTime := time.Now()
+
+	// Validate input
+	if err := ai.ValidateImageParams(params, MaxImageSize); err != nil {
+		metrics.AIAPICalls.WithLabelValues("error").Inc()
+		return nil, ai.WrapError("analyze image", err)
+	}
+
+	body, err := p.buildAnalyzeImageBody(params)
+	if err != nil {
+		metrics.AIAPICalls.WithLabelValues("error").Inc()
+		return nil, ai.WrapError("build request", err)
+	}
+
+	// Execute with retry logic
+	resp, err := p.executeWithRetry(ctx, body)
+	if err != nil {
+		metrics.AIAPICalls.WithLabelValues("error").Inc()
+		return nil, ai.WrapError("execute request", err)
+	}
+
+	// Parse the response
+	if len(resp.Choices) == 0 || resp.Choices[0].Message.Content == "" {
+		metrics.AIAPICalls.WithLabelValues("error").Inc()
+		return nil, ai.WrapError("parse response", fmt.Errorf("no content in response"))
+	}
+	result, err := ai.ParseAnalysisOutput(resp.Choices[0].Message.Content)
+	if err != nil {
+		metrics.AIAPICalls.WithLabelValues("error").Inc()
+		return nil, ai.WrapError("parse response", err)
+	}
+
+	// Calculate cost
+	result.Usage = ai.UsageInfo{
+		Provider:     ProviderName,
+		Model:        p.config.Model,
+		InputTokens:  resp.Usage.PromptTokens,
+		OutputTokens: resp.Usage.CompletionTokens,
+		CostCents:    p.calculateCost(resp.Usage.PromptTokens, resp.Usage.CompletionTokens),
+		Duration:     time.Since(startTime),
+	}
+
+	// Track usage in database
+	if p.queries != nil {
+		if err := ai.TrackUsage(ctx, p.queries, params.UserID, params.InspectionID, result.Usage, "analyze_image"); err != nil {
+			// Log but don't fail the request
+			p.logger.Error("Failed to track AI usage", "error", err)
+		}
+	}
+
+	ai.RecordUsageMetrics(result.Usage)
+	return result, nil
+}
+
+// MatchRegulations finds relevant OSHA regulations using PostgreSQL full-text search
+func (p *Provider) MatchRegulations(ctx context.Context, params ai.MatchParams) ([]ai.RegulationMatch, error) {
+	return ai.SearchRegulations(ctx, p.queries, params)
+}
+
+// buildAnalyzeImageBody builds the JSON request body for image analysis
+func (p *Provider) buildAnalyzeImageBody(params ai.AnalyzeImageParams) ([]byte, error) {
+	dataURL := "data:" + params.ContentType + ";base64," + base64.StdEncoding.EncodeToString(params.ImageData)
+
+	reqBody := apiRequest{
+		Model:          p.config.Model,
+		MaxTokens:      4096,
+		Temperature:    p.config.ProviderConfig.Temperature,
+		ResponseFormat: apiResponseFormat{Type: "json_object"},
+		Messages: []apiMessage{
+			{
+				Role: "user",
+				Content: []apiContent{
+					{Type: "text", Text: ai.ImageAnalysisPrompt(params.Context)},
+					{Type: "image_url", ImageURL: &apiImageURL{URL: dataURL}},
+				},
+			},
+		},
+	}
+
+	bodyBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+	return bodyBytes, nil
+}
+
+// executeWithRetry sends the request body with exponential backoff retry
+func (p *Provider) executeWithRetry(ctx context.Context, body []byte) (*apiResponse, error) {
+	var lastErr error
+
+	for attempt := 1; attempt <= p.config.ProviderConfig.MaxRetries; attempt++ {
+		resp, err := p.executeRequest(ctx, body)
+		if err == nil {
+			return resp, nil
+		}
+
+		lastErr = err
+
+		// Only retry on retryable errors
+		if !ai.IsRetryable(err) {
+			return nil, err
+		}
+
+		// Don't retry if we've exhausted attempts
+		if attempt >= p.config.ProviderConfig.MaxRetries {
+			break
+		}
+
+		// Calculate backoff delay (exponential: base * 2^(attempt-1))
+		delay := p.config.ProviderConfig.RetryBaseDelay * time.Duration(1<<(attempt-1))
+		p.logger.Info("Retrying AI request", "attempt", attempt, "delay", delay, "error", err)
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	return nil, lastErr
+}
+
+// executeRequest sends a single chat completions request
+func (p *Provider) executeRequest(ctx context.Context, body []byte) (*apiResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.config.BaseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+p.config.APIKey)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		// Network errors are typically retryable
+		return nil, ai.ErrAIUnavailable
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, p.mapHTTPError(resp.StatusCode, bodyBytes)
+	}
+
+	var apiResp apiResponse
+	if err := json.Unmarshal(bodyBytes, &apiResp); err != nil {
+		return nil, fmt.Errorf("unmarshal response: %w", err)
+	}
+
+	return &apiResp, nil
+}
+
+// mapHTTPError maps HTTP status codes to domain errors
+func (p *Provider) mapHTTPError(statusCode int, body []byte) error {
+	var errResp apiErrorResponse
+	_ = json.Unmarshal(body, &errResp)
+
+	switch statusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ai.ErrAIUnauthorized
+	case http.StatusTooManyRequests:
+		return ai.ErrAIRateLimit
+	case http.StatusRequestTimeout:
+		return ai.ErrAITimeout
+	case http.StatusBadRequest:
+		if errResp.Error.Code == "content_policy_violation" {
+			return ai.ErrAIContentPolicy
+		}
+		if errResp.Error.Code == "invalid_image" || errResp.Error.Code == "invalid_image_format" {
+			return ai.ErrAIInvalidImage
+		}
+		return fmt.Errorf("bad request: %s", errResp.Error.Message)
+	case http.StatusInternalServerError, http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusGatewayTimeout:
+		return ai.ErrAIUnavailable
+	default:
+		return fmt.Errorf("API error (status %d): %s", statusCode, errResp.Error.Message)
+	}
+}
+
+// calculateCost calculates the cost in cents for the given token usage
+func (p *Provider) calculateCost(inputTokens, outputTokens int) int {
+	inputCost := (inputTokens * PricingInputCents) / 1_000_000
+	outputCost := (outputTokens * PricingOutputCents) / 1_000_000
+	return inputCost + outputCost
+}
+
+// API request/response types
+
+type apiRequest struct {
+	Model          string            `json:"model"`
+	MaxTokens      int               `json:"max_tokens"`
+	Temperature    float64           `json:"temperature"`
+	ResponseFormat apiResponseFormat `json:"response_format"`
+	Messages       []apiMessage      `json:"messages"`
+}
+
+type apiResponseFormat struct {
+	Type string `json:"type"`
+}
+
+type apiMessage struct {
+	Role    string       `json:"role"`
+	Content []apiContent `json:"content"`
+}
+
+type apiContent struct {
+	Type     string       `json:"type"`
+	Text     string       `json:"text,omitempty"`
+	ImageURL *apiImageURL `json:"image_url,omitempty"`
+}
+
+type apiImageURL struct {
+	URL string `json:"url"`
+}
+
+type apiResponse struct {
+	ID      string      `json:"id"`
+	Model   string      `json:"model"`
+	Choices []apiChoice `json:"choices"`
+	Usage   apiUsage    `json:"usage"`
+}
+
+type apiChoice struct {
+	Message apiChoiceMessage `json:"message"`
+}
+
+type apiChoiceMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type apiUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+type apiErrorResponse struct {
+	Error apiError `json:"error"`
+}
+
+type apiError struct {
+	Type    string `json:"type"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
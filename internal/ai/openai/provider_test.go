@@ -0,0 +1,130 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DukeRupert/lukaut/internal/ai"
+)
+
+func newTestProvider(t *testing.T, srv *httptest.Server) *Provider {
+	t.Helper()
+	p, err := New(Config{
+		APIKey:         "test-key",
+		Model:          "gpt-4o-mini",
+		BaseURL:        srv.URL + "/v1/",
+		ProviderConfig: ai.ProviderConfig{MaxRetries: 2, RetryBaseDelay: 1, Temperature: 0.2},
+	}, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return p
+}
+
+func TestAnalyzeImage(t *testing.T) {
+	var got apiRequest
+	var gotPath, gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotAuth = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_, _ = w.Write([]byte(`{
+			"choices": [{"message": {"role": "assistant", "content": "{\"violations\":[{\"description\":\"No hard hat\",\"location\":\"Left\",\"confidence\":\"high\",\"category\":\"Personal Protective Equipment\",\"severity\":\"serious\",\"suggested_regulations\":[\"1926.100(a)\"]}]}"}}],
+			"usage": {"prompt_tokens": 1000000, "completion_tokens": 100000}
+		}`))
+	}))
+	defer srv.Close()
+
+	result, err := newTestProvider(t, srv).AnalyzeImage(context.Background(), ai.AnalyzeImageParams{
+		ImageData:   []byte("jpeg bytes"),
+		ContentType: "image/jpeg",
+		Context:     "East stair tower",
+	})
+	if err != nil {
+		t.Fatalf("AnalyzeImage failed: %v", err)
+	}
+
+	if gotPath != "/v1/chat/completions" || gotAuth != "Bearer test-key" {
+		t.Errorf("unexpected request: path=%q auth=%q", gotPath, gotAuth)
+	}
+	if got.Model != "gpt-4o-mini" || got.Temperature != 0.2 || got.ResponseFormat.Type != "json_object" {
+		t.Errorf("unexpected request settings: %+v", got)
+	}
+	content := got.Messages[0].Content
+	if len(content) != 2 || !strings.Contains(content[0].Text, "East stair tower") {
+		t.Errorf("expected prompt with inspector context, got %+v", content)
+	}
+	if content[1].ImageURL == nil || !strings.HasPrefix(content[1].ImageURL.URL, "data:image/jpeg;base64,") {
+		t.Errorf("expected image data URL, got %+v", content[1].ImageURL)
+	}
+
+	if len(result.Violations) != 1 || result.Violations[0].Severity != ai.SeveritySerious {
+		t.Fatalf("unexpected violations: %+v", result.Violations)
+	}
+	if result.Usage.Model != "gpt-4o-mini" || result.Usage.CostCents != 350 {
+		t.Errorf("unexpected usage: %+v", result.Usage)
+	}
+}
+
+func TestAnalyzeImage_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   error
+	}{
+		{"unauthorized", http.StatusUnauthorized, `{"error":{"message":"bad key"}}`, ai.ErrAIUnauthorized},
+		{"rate limited", http.StatusTooManyRequests, `{}`, ai.ErrAIRateLimit},
+		{"content policy", http.StatusBadRequest, `{"error":{"code":"content_policy_violation"}}`, ai.ErrAIContentPolicy},
+		{"unavailable", http.StatusServiceUnavailable, `{}`, ai.ErrAIUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			_, err := newTestProvider(t, srv).AnalyzeImage(context.Background(), ai.AnalyzeImageParams{
+				ImageData:   []byte("png bytes"),
+				ContentType: "image/png",
+			})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+			wantCalls := 1
+			if ai.IsRetryable(tt.want) {
+				wantCalls = 2
+			}
+			if calls != wantCalls {
+				t.Errorf("expected %d calls, got %d", wantCalls, calls)
+			}
+		})
+	}
+}
+
+func TestAnalyzeImage_RejectsUnsupportedImage(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("expected no request for an unsupported image")
+	}))
+	defer srv.Close()
+
+	_, err := newTestProvider(t, srv).AnalyzeImage(context.Background(), ai.AnalyzeImageParams{
+		ImageData:   []byte("tiff bytes"),
+		ContentType: "image/tiff",
+	})
+	if !errors.Is(err, ai.ErrAIInvalidImage) {
+		t.Errorf("expected ErrAIInvalidImage, got %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ParseAnalysisOutput parses the JSON object requested by ImageAnalysisPrompt
+// into an AnalysisResult. Unknown confidence and severity values fall back to
+// ConfidenceMedium and SeverityOther. Usage is left for the provider to fill in.
+func ParseAnalysisOutput(text string) (*AnalysisResult, error) {
+	// Models sometimes wrap the object in a Markdown code fence despite the prompt
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, "```") {
+		text = strings.TrimPrefix(text, "```json")
+		text = strings.TrimPrefix(text, "```")
+		text = strings.TrimSuffix(strings.TrimSpace(text), "```")
+	}
+
+	var output analysisOutput
+	if err := json.Unmarshal([]byte(text), &output); err != nil {
+		return nil, fmt.Errorf("parse analysis output: %w", err)
+	}
+
+	result := &AnalysisResult{
+		Violations:          make([]PotentialViolation, 0, len(output.Violations)),
+		GeneralObservations: output.GeneralObservations,
+		ImageQualityNotes:   output.ImageQualityNotes,
+	}
+
+	for _, v := range output.Violations {
+		violation := PotentialViolation{
+			Description:          v.Description,
+			Location:             v.Location,
+			Confidence:           Confidence(v.Confidence),
+			Category:             v.Category,
+			Severity:             Severity(v.Severity),
+			SuggestedRegulations: v.SuggestedRegulations,
+		}
+
+		// Add bounding box if present
+		if v.BoundingBox != nil {
+			violation.BoundingBox = &BoundingBox{
+				X:      v.BoundingBox.X,
+				Y:      v.BoundingBox.Y,
+				Width:  v.BoundingBox.Width,
+				Height: v.BoundingBox.Height,
+			}
+		}
+
+		// Validate and set defaults
+		if !violation.Confidence.Valid() {
+			violation.Confidence = ConfidenceMedium
+		}
+		if !violation.Severity.Valid() {
+			violation.Severity = SeverityOther
+		}
+
+		result.Violations = append(result.Violations, violation)
+	}
+
+	return result, nil
+}
+
+// analysisOutput represents the JSON structure requested by ImageAnalysisPrompt
+type analysisOutput struct {
+	Violations          []outputViolation `json:"violations"`
+	GeneralObservations string            `json:"general_observations"`
+	ImageQualityNotes   string            `json:"image_quality_notes"`
+}
+
+type outputViolation struct {
+	Description          string             `json:"description"`
+	Location             string             `json:"location"`
+	BoundingBox          *outputBoundingBox `json:"bounding_box,omitempty"`
+	Confidence           string             `json:"confidence"`
+	Category             string             `json:"category"`
+	Severity             string             `json:"severity"`
+	SuggestedRegulations []string           `json:"suggested_regulations"`
+}
+
+type outputBoundingBox struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
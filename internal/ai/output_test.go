@@ -0,0 +1,72 @@
+package ai
+
+import (
+	"testing"
+)
+
+func TestParseAnalysisOutput(t *testing.T) {
+	const body = `{
+		"violations": [
+			{
+				"description": "Open edge without guardrail",
+				"location": "Second floor slab",
+				"bounding_box": {"x": 0.1, "y": 0.2, "width": 0.3, "height": 0.4},
+				"confidence": "high",
+				"category": "Fall Protection",
+				"severity": "critical",
+				"suggested_regulations": ["1926.501(b)(1)"]
+			},
+			{
+				"description": "Loose debris",
+				"location": "Walkway",
+				"confidence": "certain",
+				"severity": "minor"
+			}
+		],
+		"general_observations": "Active framing",
+		"image_quality_notes": "Clear"
+	}`
+
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"plain JSON", body},
+		{"fenced JSON", "```json\n" + body + "\n```"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseAnalysisOutput(tt.text)
+			if err != nil {
+				t.Fatalf("ParseAnalysisOutput failed: %v", err)
+			}
+			if len(result.Violations) != 2 || result.GeneralObservations != "Active framing" {
+				t.Fatalf("unexpected result: %+v", result)
+			}
+			first := result.Violations[0]
+			if first.Severity != SeverityCritical || first.Confidence != ConfidenceHigh {
+				t.Errorf("unexpected severity/confidence: %q/%q", first.Severity, first.Confidence)
+			}
+			if first.BoundingBox == nil || first.BoundingBox.Height != 0.4 {
+				t.Errorf("expected bounding box, got %+v", first.BoundingBox)
+			}
+			if len(first.SuggestedRegulations) != 1 || first.SuggestedRegulations[0] != "1926.501(b)(1)" {
+				t.Errorf("unexpected regulation hints: %v", first.SuggestedRegulations)
+			}
+			second := result.Violations[1]
+			if second.Confidence != ConfidenceMedium || second.Severity != SeverityOther {
+				t.Errorf("expected unknown values to fall back to medium/other, got %q/%q", second.Confidence, second.Severity)
+			}
+			if second.BoundingBox != nil {
+				t.Errorf("expected no bounding box, got %+v", second.BoundingBox)
+			}
+		})
+	}
+}
+
+func TestParseAnalysisOutput_NotJSON(t *testing.T) {
+	if _, err := ParseAnalysisOutput("I could not analyze this image."); err == nil {
+		t.Error("expected an error for a non-JSON reply")
+	}
+}
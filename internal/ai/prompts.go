@@ -1,9 +1,11 @@
-package anthropic
+package ai
 
 import "fmt"
 
-// buildImageAnalysisPrompt creates a detailed prompt for analyzing construction site images
-func buildImageAnalysisPrompt(context string) string {
+// ImageAnalysisPrompt creates a detailed prompt for analyzing construction site
+// images. Every provider sends the same prompt and parses the reply with
+// ParseAnalysisOutput.
+func ImageAnalysisPrompt(context string) string {
 	prompt := `You are an expert OSHA construction safety inspector analyzing a construction site photograph. Your task is to identify potential safety violations and hazards.
 
 Analyze the image for violations in these OSHA categories:
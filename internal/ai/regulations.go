@@ -0,0 +1,44 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/DukeRupert/lukaut/internal/repository"
+)
+
+// SearchRegulations finds relevant OSHA regulations using PostgreSQL full-text
+// search. Providers use it to implement MatchRegulations without a model call.
+func SearchRegulations(ctx context.Context, queries *repository.Queries, params MatchParams) ([]RegulationMatch, error) {
+	// Default max results
+	maxResults := params.MaxResults
+	if maxResults <= 0 {
+		maxResults = 10
+	}
+
+	// Search regulations using full-text search
+	results, err := queries.SearchRegulations(ctx, repository.SearchRegulationsParams{
+		WebsearchToTsquery: params.ViolationDescription,
+		Limit:              int32(maxResults),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("search regulations: %w", err)
+	}
+
+	// Convert to domain types
+	matches := make([]RegulationMatch, 0, len(results))
+	for i, result := range results {
+		match := RegulationMatch{
+			RegulationID:   result.ID,
+			StandardNumber: result.StandardNumber,
+			Title:          result.Title,
+			Category:       result.Category,
+			RelevanceScore: float64(result.Rank),
+			Explanation:    "",     // No explanation in MVP - could add AI-generated explanation later
+			IsPrimary:      i == 0, // First result is primary
+		}
+		matches = append(matches, match)
+	}
+
+	return matches, nil
+}
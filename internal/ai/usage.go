@@ -0,0 +1,35 @@
+package ai
+
+import (
+	"context"
+
+	"github.com/DukeRupert/lukaut/internal/metrics"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/google/uuid"
+)
+
+// TrackUsage records AI usage in the database for billing and quota reporting
+func TrackUsage(ctx context.Context, queries *repository.Queries, userID, inspectionID uuid.UUID, usage UsageInfo, requestType string) error {
+	_, err := queries.CreateAIUsage(ctx, repository.CreateAIUsageParams{
+		UserID: userID,
+		InspectionID: uuid.NullUUID{
+			UUID:  inspectionID,
+			Valid: inspectionID != uuid.Nil,
+		},
+		Model:        usage.Model,
+		InputTokens:  int32(usage.InputTokens),
+		OutputTokens: int32(usage.OutputTokens),
+		CostCents:    int32(usage.CostCents),
+		RequestType:  requestType,
+	})
+	return err
+}
+
+// RecordUsageMetrics adds a successful call's tokens and cost to the aggregate
+// metrics (no user label - low cardinality)
+func RecordUsageMetrics(usage UsageInfo) {
+	metrics.AITokensTotal.WithLabelValues("input").Add(float64(usage.InputTokens))
+	metrics.AITokensTotal.WithLabelValues("output").Add(float64(usage.OutputTokens))
+	metrics.AICostCentsTotal.Add(float64(usage.CostCents))
+	metrics.AIAPICalls.WithLabelValues("success").Inc()
+}
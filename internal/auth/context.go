@@ -17,6 +17,12 @@ type contextKey string
 const (
 	// userContextKey is the key used to store the authenticated user in context.
 	userContextKey contextKey = "user"
+
+	// sessionTokenContextKey is the key used to store the raw session token in context.
+	sessionTokenContextKey contextKey = "session_token"
+
+	// sessionClientContextKey is the key used to store the request's client details in context.
+	sessionClientContextKey contextKey = "session_client"
 )
 
 // GetUser retrieves the authenticated user from the context.
@@ -51,3 +57,35 @@ func GetUserFromRequest(r *http.Request) *domain.User {
 func SetUser(ctx context.Context, user *domain.User) context.Context {
 	return context.WithValue(ctx, userContextKey, user)
 }
+
+// GetSessionToken retrieves the raw token of the session making the request.
+//
+// Returns "" if the request is not authenticated.
+func GetSessionToken(ctx context.Context) string {
+	token, _ := ctx.Value(sessionTokenContextKey).(string)
+	return token
+}
+
+// SetSessionToken stores the raw session token in the context.
+//
+// This is called by authentication middleware alongside SetUser so services
+// can tell the current session apart from the user's others.
+func SetSessionToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, sessionTokenContextKey, token)
+}
+
+// GetSessionClient retrieves the client details of the request.
+//
+// Returns the zero value if none were stored.
+func GetSessionClient(ctx context.Context) domain.SessionClient {
+	client, _ := ctx.Value(sessionClientContextKey).(domain.SessionClient)
+	return client
+}
+
+// SetSessionClient stores the request's client details in the context.
+//
+// The user service records them on the session at login and when the session
+// is refreshed.
+func SetSessionClient(ctx context.Context, client domain.SessionClient) context.Context {
+	return context.WithValue(ctx, sessionClientContextKey, client)
+}
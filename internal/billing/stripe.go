@@ -18,8 +18,10 @@ type Service interface {
 	CreateCustomer(email, name string) (string, error)
 
 	// CreateCheckoutSession creates a Stripe Checkout session for subscribing.
+	// The clientReferenceID (our user ID) and the price's tier are attached to
+	// the session so the checkout.session.completed webhook can apply them.
 	// Returns the checkout URL to redirect the user to.
-	CreateCheckoutSession(customerID, priceID, successURL, cancelURL string) (string, error)
+	CreateCheckoutSession(customerID, clientReferenceID, priceID, successURL, cancelURL string) (string, error)
 
 	// CreatePortalSession creates a Stripe Customer Portal session.
 	// Returns the portal URL to redirect the user to.
@@ -49,6 +51,44 @@ type PriceConfig struct {
 	ProfessionalYearlyPriceID  string
 }
 
+// Billing intervals accepted by PriceID.
+const (
+	IntervalMonthly = "monthly"
+	IntervalYearly  = "yearly"
+)
+
+// MetadataTier is the checkout session metadata key holding the purchased tier.
+const MetadataTier = "tier"
+
+// PriceID returns the configured price ID for a tier ("starter" or
+// "professional") and interval, or "" if that plan is not configured.
+func (p PriceConfig) PriceID(tier, interval string) string {
+	switch {
+	case tier == "starter" && interval == IntervalMonthly:
+		return p.StarterMonthlyPriceID
+	case tier == "starter" && interval == IntervalYearly:
+		return p.StarterYearlyPriceID
+	case tier == "professional" && interval == IntervalMonthly:
+		return p.ProfessionalMonthlyPriceID
+	case tier == "professional" && interval == IntervalYearly:
+		return p.ProfessionalYearlyPriceID
+	}
+	return ""
+}
+
+// IsKnown reports whether priceID is one of the configured price IDs.
+func (p PriceConfig) IsKnown(priceID string) bool {
+	if priceID == "" {
+		return false
+	}
+	switch priceID {
+	case p.StarterMonthlyPriceID, p.StarterYearlyPriceID,
+		p.ProfessionalMonthlyPriceID, p.ProfessionalYearlyPriceID:
+		return true
+	}
+	return false
+}
+
 // stripeService is the concrete implementation of Service.
 type stripeService struct {
 	webhookSecret string
@@ -97,10 +137,11 @@ func (s *stripeService) CreateCustomer(email, name string) (string, error) {
 	return c.ID, nil
 }
 
-func (s *stripeService) CreateCheckoutSession(customerID, priceID, successURL, cancelURL string) (string, error) {
+func (s *stripeService) CreateCheckoutSession(customerID, clientReferenceID, priceID, successURL, cancelURL string) (string, error) {
 	params := &stripe.CheckoutSessionParams{
-		Customer: stripe.String(customerID),
-		Mode:     stripe.String(string(stripe.CheckoutSessionModeSubscription)),
+		Customer:          stripe.String(customerID),
+		ClientReferenceID: stripe.String(clientReferenceID),
+		Mode:              stripe.String(string(stripe.CheckoutSessionModeSubscription)),
 		LineItems: []*stripe.CheckoutSessionLineItemParams{
 			{
 				Price:    stripe.String(priceID),
@@ -110,6 +151,9 @@ func (s *stripeService) CreateCheckoutSession(customerID, priceID, successURL, c
 		SuccessURL: stripe.String(successURL),
 		CancelURL:  stripe.String(cancelURL),
 	}
+	if tier := s.TierForPriceID(priceID); tier != "" {
+		params.AddMetadata(MetadataTier, tier)
+	}
 	sess, err := checkoutsession.New(params)
 	if err != nil {
 		return "", fmt.Errorf("stripe create checkout session: %w", err)
@@ -2,11 +2,13 @@ package internal
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/DukeRupert/lukaut/internal/domain"
 	"github.com/joho/godotenv"
 )
 
@@ -16,6 +18,41 @@ type Config struct {
 	LogLevel    string
 	DatabaseUrl string
 
+	// Access log. Fast successful requests are logged at debug level.
+	LogSlowRequestThreshold time.Duration // Requests at least this slow are logged at info level
+	LogRequestSampleRate    float64       // Fraction (0-1) of fast successful requests logged
+
+	// HTTP server timeouts. Long-running routes (uploads and archive
+	// downloads) get HTTPLongRequestTimeout in place of the request and
+	// write timeouts.
+	HTTPReadHeaderTimeout  time.Duration // Maximum time to read request headers
+	HTTPWriteTimeout       time.Duration // Maximum time from reading headers to finishing the response
+	HTTPIdleTimeout        time.Duration // Keep-alive connections are closed after this long idle
+	HTTPRequestTimeout     time.Duration // Context deadline for a request's handler
+	HTTPLongRequestTimeout time.Duration // Context and connection deadline for long-running routes
+
+	// Database connection pools. The HTTP server and the background worker
+	// each get their own pool so a busy worker can't starve web requests.
+	DBMaxOpenConns       int           // Open connections allowed in the HTTP server's pool
+	DBMaxIdleConns       int           // Idle connections kept in the HTTP server's pool
+	DBConnMaxLifetime    time.Duration // Connections are closed after this long; 0 keeps them forever
+	DBConnMaxIdleTime    time.Duration // Idle connections are closed after this long; 0 keeps them forever
+	DBWorkerMaxOpenConns int           // Open connections allowed in the worker's pool
+
+	// Slow query log. Queries taking at least the threshold are logged
+	// with their duration and a sanitized statement.
+	DBLogSlowQueries     bool          // Whether to log slow queries
+	DBSlowQueryThreshold time.Duration // Queries at least this slow are logged
+
+	// Email Configuration
+	EmailProvider    string        // "smtp", "postmark", "log", or "file"
+	EmailSendTimeout time.Duration // Maximum time for one send
+	EmailFrom        string        // Sender address (all providers)
+	EmailFromName    string        // Sender display name (all providers)
+	PostmarkToken    string        // Postmark server API token
+	PostmarkStream   string        // Postmark message stream ID
+	EmailFileDir     string        // Directory for .eml files when EmailProvider is "file"
+
 	// SMTP Configuration
 	SMTPHost     string
 	SMTPPort     int
@@ -24,6 +61,13 @@ type Config struct {
 	SMTPFrom     string
 	SMTPFromName string
 
+	// SMTP retries and circuit breaking for transient failures
+	SMTPDialTimeout      time.Duration // Maximum time to connect
+	SMTPMaxAttempts      int           // Attempts per send, including the first
+	SMTPRetryBaseDelay   time.Duration // Base delay for exponential backoff
+	SMTPBreakerThreshold int           // Consecutive failed attempts that stop sends; 0 disables
+	SMTPBreakerCooldown  time.Duration // How long sends stay stopped
+
 	// Application base URL (for email links)
 	BaseURL string
 
@@ -41,19 +85,51 @@ type Config struct {
 	R2BucketName      string
 	R2PublicURL       string // Optional custom domain URL
 
+	// Thumbnail Configuration
+	ThumbnailSizes []int // Bounding boxes (px) of generated thumbnail variants
+	ThumbnailWebP  bool  // Also generate WebP variants alongside JPEG
+
+	// HEIC Conversion
+	HEIFConvertCommand string // heif-convert binary used to convert HEIC uploads to JPEG; empty disables
+
+	// Photo Upload Limits
+	ImageMaxUploadMB  int      // Largest accepted photo, in megabytes (default and maximum: 20)
+	ImageAllowedTypes []string // Accepted photo MIME types (default: JPEG, PNG, HEIC, HEIF)
+
+	// Malware Scanning
+	ScanProvider  string        // "none" (default) or "clamav"
+	ClamAVAddress string        // clamd TCP address, e.g. "clamav:3310"; required for clamav
+	ScanTimeout   time.Duration // Bounds a single scan (default: 30s)
+
 	// Worker Configuration
 	WorkerEnabled      bool
 	WorkerConcurrency  int
 	WorkerPollInterval time.Duration
 	WorkerJobTimeout   time.Duration
+	WorkerMaxUserJobs  int // Pending or running analysis (or report) jobs allowed per user; 0 disables
+	WorkerMaxQueue     int // Pending jobs allowed before user-initiated jobs are refused; 0 disables
+	WorkerReservedHigh int // Workers that only take high-priority (analysis, email) jobs
 
 	// AI Provider Configuration
-	AIProvider       string // "anthropic" or "mock"
+	AIProvider       string  // "anthropic", "openai", or "mock"
+	AIModel          string  // Model name for the provider; empty uses the provider's default
+	AITemperature    float64 // Sampling temperature (0-1)
 	AnthropicAPIKey  string
-	AnthropicModel   string
+	OpenAIAPIKey     string
+	OpenAIBaseURL    string // Optional endpoint override for OpenAI-compatible APIs
 	AIMaxRetries     int
 	AIRetryBaseDelay time.Duration
 	AIRequestTimeout time.Duration
+	AIConcurrency    int // Maximum images analyzed in parallel per inspection job
+
+	// Address geocoding (runs in the background worker)
+	GeocoderProvider string // "none" (default), "nominatim", or "google"
+	GeocoderAPIKey   string // Required for google
+	GeocoderURL      string // Optional endpoint override, e.g. a self-hosted Nominatim
+
+	// Weather suggestions (Visual Crossing)
+	WeatherAPIKey string // Empty disables weather suggestions and auto-fill
+	WeatherURL    string // Optional endpoint override
 
 	// Invite code system (MVP testing)
 	InviteCodesEnabled bool     // Enable/disable invite code requirement
@@ -63,7 +139,25 @@ type Config struct {
 	AdminEmails []string // List of email addresses with admin access
 
 	// Session configuration
-	SessionDuration time.Duration // How long user sessions remain valid (default: 24h)
+	SessionDuration    time.Duration // How long user sessions remain valid (default: 24h)
+	SessionIdleTimeout time.Duration // Expire sessions after this long without activity (default: 0, disabled)
+
+	// Email verification policy
+	EmailVerificationGracePeriod time.Duration // How long unverified users can analyze and generate reports (default: 72h)
+	VerificationReminderDelay    time.Duration // Wait after sign-up, and between reminders, before emailing unverified users (default: 24h)
+	VerificationReminderMax      int           // Reminders sent to each unverified user; 0 disables reminders (default: 2)
+
+	// Data retention
+	RetentionMonths int  // Completed inspections untouched this many months are purged; 0 disables the purge (default: 0)
+	RetentionDryRun bool // Log what the purge would delete without deleting anything (default: false)
+
+	// Password policy
+	PasswordBreachCheck bool // Reject new passwords found by the HaveIBeenPwned range API (default: false)
+
+	// CORS for the JSON API (/api/ routes); no origins means same-origin only
+	CORSAllowedOrigins []string // Origins allowed to call the API, or "*" (default: none)
+	CORSAllowedMethods []string // Methods allowed in preflight (default: GET, POST, PUT, PATCH, DELETE)
+	CORSAllowedHeaders []string // Request headers allowed in preflight (default: Content-Type, Authorization)
 
 	// Stripe Billing Configuration
 	// These are required when billing is enabled in production.
@@ -87,6 +181,34 @@ func NewConfig() (*Config, error) {
 		Port:     getEnvInt("PORT", 8080),
 		LogLevel: getEnv("LOG_LEVEL", "debug"),
 
+		LogSlowRequestThreshold: getEnvDuration("LOG_SLOW_REQUEST_THRESHOLD", time.Second),
+		LogRequestSampleRate:    getEnvFloat("LOG_REQUEST_SAMPLE_RATE", 1),
+
+		// The write timeout leaves a handler that hits its request timeout
+		// time to write its error response
+		HTTPReadHeaderTimeout:  getEnvDuration("HTTP_READ_HEADER_TIMEOUT", 10*time.Second),
+		HTTPWriteTimeout:       getEnvDuration("HTTP_WRITE_TIMEOUT", 60*time.Second),
+		HTTPIdleTimeout:        getEnvDuration("HTTP_IDLE_TIMEOUT", 120*time.Second),
+		HTTPRequestTimeout:     getEnvDuration("HTTP_REQUEST_TIMEOUT", 30*time.Second),
+		HTTPLongRequestTimeout: getEnvDuration("HTTP_LONG_REQUEST_TIMEOUT", 10*time.Minute),
+
+		// Database pool defaults stay well under Postgres's default
+		// max_connections of 100, leaving room for other clients
+		DBMaxOpenConns:    getEnvInt("DB_MAX_OPEN_CONNS", 20),
+		DBMaxIdleConns:    getEnvInt("DB_MAX_IDLE_CONNS", 10),
+		DBConnMaxLifetime: getEnvDuration("DB_CONN_MAX_LIFETIME", 30*time.Minute),
+		DBConnMaxIdleTime: getEnvDuration("DB_CONN_MAX_IDLE_TIME", 5*time.Minute),
+
+		DBLogSlowQueries:     getEnvBool("DB_LOG_SLOW_QUERIES", false),
+		DBSlowQueryThreshold: getEnvDuration("DB_SLOW_QUERY_THRESHOLD", 200*time.Millisecond),
+
+		// Email defaults to SMTP (Mailhog in development)
+		EmailProvider:    getEnv("EMAIL_PROVIDER", "smtp"),
+		EmailSendTimeout: getEnvDuration("EMAIL_SEND_TIMEOUT", 10*time.Second),
+		PostmarkToken:    getEnv("POSTMARK_SERVER_TOKEN", ""),
+		PostmarkStream:   getEnv("POSTMARK_MESSAGE_STREAM", "outbound"),
+		EmailFileDir:     getEnv("EMAIL_FILE_DIR", "tmp/emails"),
+
 		// SMTP defaults for Mailhog (development)
 		SMTPHost:     getEnv("SMTP_HOST", "localhost"),
 		SMTPPort:     getEnvInt("SMTP_PORT", 1025),
@@ -95,6 +217,12 @@ func NewConfig() (*Config, error) {
 		SMTPFrom:     getEnv("SMTP_FROM", "noreply@lukaut.com"),
 		SMTPFromName: getEnv("SMTP_FROM_NAME", "Lukaut"),
 
+		SMTPDialTimeout:      getEnvDuration("SMTP_DIAL_TIMEOUT", 5*time.Second),
+		SMTPMaxAttempts:      getEnvInt("SMTP_MAX_ATTEMPTS", 3),
+		SMTPRetryBaseDelay:   getEnvDuration("SMTP_RETRY_BASE_DELAY", 500*time.Millisecond),
+		SMTPBreakerThreshold: getEnvInt("SMTP_BREAKER_THRESHOLD", 5),
+		SMTPBreakerCooldown:  getEnvDuration("SMTP_BREAKER_COOLDOWN", 30*time.Second),
+
 		// Base URL defaults to localhost for development
 		BaseURL: getEnv("BASE_URL", "http://localhost:8080"),
 
@@ -110,19 +238,50 @@ func NewConfig() (*Config, error) {
 		R2BucketName:      getEnv("R2_BUCKET_NAME", ""),
 		R2PublicURL:       getEnv("R2_PUBLIC_URL", ""),
 
+		// Thumbnail defaults (200px gallery + 600px preview, JPEG only)
+		ThumbnailWebP: getEnvBool("THUMBNAIL_WEBP", false),
+
+		// HEIC uploads are converted when heif-convert is installed
+		HEIFConvertCommand: getEnv("HEIF_CONVERT_COMMAND", "heif-convert"),
+
+		// Upload limits default to everything the image pipeline handles
+		ImageMaxUploadMB:  getEnvInt("IMAGE_MAX_UPLOAD_MB", domain.MaxImageSize/(1024*1024)),
+		ImageAllowedTypes: splitEnvList(getEnv("IMAGE_ALLOWED_TYPES", strings.Join(domain.DefaultUploadLimits().ContentTypes, ","))),
+
+		// Uploads are not scanned unless a provider is configured
+		ScanProvider:  getEnv("SCAN_PROVIDER", "none"),
+		ClamAVAddress: getEnv("CLAMAV_ADDRESS", ""),
+		ScanTimeout:   getEnvDuration("SCAN_TIMEOUT", 30*time.Second),
+
 		// Worker defaults
 		WorkerEnabled:      getEnvBool("WORKER_ENABLED", true),
 		WorkerConcurrency:  getEnvInt("WORKER_CONCURRENCY", 2),
 		WorkerPollInterval: getEnvDuration("WORKER_POLL_INTERVAL", 5*time.Second),
 		WorkerJobTimeout:   getEnvDuration("WORKER_JOB_TIMEOUT", 5*time.Minute),
+		WorkerMaxUserJobs:  getEnvInt("WORKER_MAX_JOBS_PER_USER", 3),
+		WorkerMaxQueue:     getEnvInt("WORKER_MAX_QUEUE_DEPTH", 500),
+		WorkerReservedHigh: getEnvInt("WORKER_RESERVED_HIGH_PRIORITY_SLOTS", 0),
 
 		// AI provider defaults
 		AIProvider:       getEnv("AI_PROVIDER", "mock"),
+		AIModel:          getEnv("AI_MODEL", ""),
+		AITemperature:    getEnvFloat("AI_TEMPERATURE", 0.2),
 		AnthropicAPIKey:  getEnv("ANTHROPIC_API_KEY", ""),
-		AnthropicModel:   getEnv("ANTHROPIC_MODEL", "claude-3-5-sonnet-20241022"),
+		OpenAIAPIKey:     getEnv("OPENAI_API_KEY", ""),
+		OpenAIBaseURL:    getEnv("OPENAI_BASE_URL", ""),
 		AIMaxRetries:     getEnvInt("AI_MAX_RETRIES", 3),
 		AIRetryBaseDelay: getEnvDuration("AI_RETRY_BASE_DELAY", 1*time.Second),
 		AIRequestTimeout: getEnvDuration("AI_REQUEST_TIMEOUT", 60*time.Second),
+		AIConcurrency:    getEnvInt("AI_CONCURRENCY", 3),
+
+		// Geocoding is off unless a provider is configured
+		GeocoderProvider: getEnv("GEOCODER_PROVIDER", "none"),
+		GeocoderAPIKey:   getEnv("GEOCODER_API_KEY", ""),
+		GeocoderURL:      getEnv("GEOCODER_URL", ""),
+
+		// Weather lookups are off without an API key
+		WeatherAPIKey: getEnv("WEATHER_API_KEY", ""),
+		WeatherURL:    getEnv("WEATHER_URL", ""),
 
 		// Invite code defaults (enabled by default for MVP testing)
 		InviteCodesEnabled: getEnvBool("INVITE_CODES_ENABLED", true),
@@ -130,6 +289,23 @@ func NewConfig() (*Config, error) {
 		// Session duration (default 24 hours, can be configured)
 		SessionDuration: getEnvDuration("SESSION_DURATION", 24*time.Hour),
 
+		// Idle timeout is off unless configured
+		SessionIdleTimeout: getEnvDuration("SESSION_IDLE_TIMEOUT", 0),
+
+		// Unverified users can analyze and generate reports for 3 days after sign-up
+		EmailVerificationGracePeriod: getEnvDuration("EMAIL_VERIFICATION_GRACE_PERIOD", 72*time.Hour),
+
+		// Unverified users get up to two reminders, a day apart
+		VerificationReminderDelay: getEnvDuration("VERIFICATION_REMINDER_DELAY", 24*time.Hour),
+		VerificationReminderMax:   getEnvInt("VERIFICATION_REMINDER_MAX", 2),
+
+		// Inspections are kept forever unless a retention period is configured
+		RetentionMonths: getEnvInt("RETENTION_MONTHS", 0),
+		RetentionDryRun: getEnvBool("RETENTION_DRY_RUN", false),
+
+		// Breached password check calls a third-party API, so it is opt-in
+		PasswordBreachCheck: getEnvBool("PASSWORD_BREACH_CHECK", false),
+
 		// Stripe billing (optional — stubs work without these)
 		StripeSecretKey:     getEnv("STRIPE_SECRET_KEY", ""),
 		StripeWebhookSecret: getEnv("STRIPE_WEBHOOK_SECRET", ""),
@@ -141,6 +317,9 @@ func NewConfig() (*Config, error) {
 		StripeProfessionalYearlyPriceID:  getEnv("STRIPE_PROFESSIONAL_YEARLY_PRICE_ID", ""),
 	}
 
+	// The worker needs a connection per concurrent job, plus one to poll
+	cfg.DBWorkerMaxOpenConns = getEnvInt("DB_WORKER_MAX_OPEN_CONNS", cfg.WorkerConcurrency+2)
+
 	// Parse invite codes from comma-separated environment variable
 	inviteCodesStr := getEnv("VALID_INVITE_CODES", "")
 	if inviteCodesStr != "" {
@@ -153,6 +332,49 @@ func NewConfig() (*Config, error) {
 		}
 	}
 
+	// Parse thumbnail sizes from comma-separated environment variable
+	thumbnailSizesStr := getEnv("THUMBNAIL_SIZES", "200,600")
+	for _, sizeStr := range strings.Split(thumbnailSizesStr, ",") {
+		size, err := strconv.Atoi(strings.TrimSpace(sizeStr))
+		if err != nil || size <= 0 {
+			return nil, fmt.Errorf("THUMBNAIL_SIZES must be a comma-separated list of positive integers, got: %s", thumbnailSizesStr)
+		}
+		cfg.ThumbnailSizes = append(cfg.ThumbnailSizes, size)
+	}
+
+	// Validate photo upload limits. AI providers accept images up to
+	// domain.MaxImageSize, so the limit can only be lowered.
+	if maxMB := domain.MaxImageSize / (1024 * 1024); cfg.ImageMaxUploadMB < 1 || cfg.ImageMaxUploadMB > maxMB {
+		return nil, fmt.Errorf("IMAGE_MAX_UPLOAD_MB must be between 1 and %d, got: %d", maxMB, cfg.ImageMaxUploadMB)
+	}
+	if len(cfg.ImageAllowedTypes) == 0 {
+		return nil, fmt.Errorf("IMAGE_ALLOWED_TYPES must list at least one type")
+	}
+	for _, contentType := range cfg.ImageAllowedTypes {
+		if !domain.IsValidImageContentType(contentType) && !domain.IsConvertibleImageContentType(contentType) {
+			return nil, fmt.Errorf("IMAGE_ALLOWED_TYPES must contain only image/jpeg, image/png, image/heic, or image/heif, got: %s", contentType)
+		}
+	}
+
+	// Validate malware scanning configuration
+	switch cfg.ScanProvider {
+	case "none":
+	case "clamav":
+		if cfg.ClamAVAddress == "" {
+			return nil, fmt.Errorf("CLAMAV_ADDRESS is required when SCAN_PROVIDER is 'clamav'")
+		}
+	default:
+		return nil, fmt.Errorf("SCAN_PROVIDER must be 'none' or 'clamav', got: %s", cfg.ScanProvider)
+	}
+	if cfg.ScanTimeout <= 0 {
+		return nil, fmt.Errorf("SCAN_TIMEOUT must be positive, got: %s", cfg.ScanTimeout)
+	}
+
+	// Sender address is shared by all providers; SMTP_FROM is kept for
+	// existing deployments
+	cfg.EmailFrom = getEnv("EMAIL_FROM", cfg.SMTPFrom)
+	cfg.EmailFromName = getEnv("EMAIL_FROM_NAME", cfg.SMTPFromName)
+
 	// Parse admin emails from comma-separated environment variable
 	adminEmailsStr := getEnv("ADMIN_EMAILS", "")
 	if adminEmailsStr != "" {
@@ -165,12 +387,67 @@ func NewConfig() (*Config, error) {
 		}
 	}
 
+	// Parse CORS settings from comma-separated environment variables
+	cfg.CORSAllowedOrigins = splitEnvList(getEnv("CORS_ALLOWED_ORIGINS", ""))
+	cfg.CORSAllowedMethods = splitEnvList(strings.ToUpper(getEnv("CORS_ALLOWED_METHODS", "")))
+	cfg.CORSAllowedHeaders = splitEnvList(getEnv("CORS_ALLOWED_HEADERS", ""))
+	for _, origin := range cfg.CORSAllowedOrigins {
+		if !validCORSOrigin(origin) {
+			return nil, fmt.Errorf("CORS_ALLOWED_ORIGINS entries must be \"*\" or an origin like https://app.example.com, got: %s", origin)
+		}
+	}
+
 	// Required
 	cfg.DatabaseUrl = os.Getenv("DATABASE_URL")
 	if cfg.DatabaseUrl == "" {
 		return nil, fmt.Errorf("DATABASE_URL is required")
 	}
 
+	// Validate database pool configuration
+	if cfg.DBMaxOpenConns < 1 {
+		return nil, fmt.Errorf("DB_MAX_OPEN_CONNS must be at least 1, got: %d", cfg.DBMaxOpenConns)
+	}
+	if cfg.DBMaxIdleConns < 0 || cfg.DBMaxIdleConns > cfg.DBMaxOpenConns {
+		return nil, fmt.Errorf("DB_MAX_IDLE_CONNS must be between 0 and DB_MAX_OPEN_CONNS (%d), got: %d", cfg.DBMaxOpenConns, cfg.DBMaxIdleConns)
+	}
+	if cfg.DBConnMaxLifetime < 0 {
+		return nil, fmt.Errorf("DB_CONN_MAX_LIFETIME must not be negative, got: %s", cfg.DBConnMaxLifetime)
+	}
+	if cfg.DBConnMaxIdleTime < 0 {
+		return nil, fmt.Errorf("DB_CONN_MAX_IDLE_TIME must not be negative, got: %s", cfg.DBConnMaxIdleTime)
+	}
+	if cfg.DBWorkerMaxOpenConns < 1 {
+		return nil, fmt.Errorf("DB_WORKER_MAX_OPEN_CONNS must be at least 1, got: %d", cfg.DBWorkerMaxOpenConns)
+	}
+	if cfg.DBLogSlowQueries && cfg.DBSlowQueryThreshold <= 0 {
+		return nil, fmt.Errorf("DB_SLOW_QUERY_THRESHOLD must be positive, got: %s", cfg.DBSlowQueryThreshold)
+	}
+
+	// Validate access log settings
+	if cfg.LogSlowRequestThreshold <= 0 {
+		return nil, fmt.Errorf("LOG_SLOW_REQUEST_THRESHOLD must be positive, got: %s", cfg.LogSlowRequestThreshold)
+	}
+	if cfg.LogRequestSampleRate < 0 || cfg.LogRequestSampleRate > 1 {
+		return nil, fmt.Errorf("LOG_REQUEST_SAMPLE_RATE must be between 0 and 1, got: %g", cfg.LogRequestSampleRate)
+	}
+
+	// Validate HTTP server timeouts
+	if cfg.HTTPReadHeaderTimeout <= 0 {
+		return nil, fmt.Errorf("HTTP_READ_HEADER_TIMEOUT must be positive, got: %s", cfg.HTTPReadHeaderTimeout)
+	}
+	if cfg.HTTPIdleTimeout <= 0 {
+		return nil, fmt.Errorf("HTTP_IDLE_TIMEOUT must be positive, got: %s", cfg.HTTPIdleTimeout)
+	}
+	if cfg.HTTPRequestTimeout <= 0 {
+		return nil, fmt.Errorf("HTTP_REQUEST_TIMEOUT must be positive, got: %s", cfg.HTTPRequestTimeout)
+	}
+	if cfg.HTTPWriteTimeout <= cfg.HTTPRequestTimeout {
+		return nil, fmt.Errorf("HTTP_WRITE_TIMEOUT (%s) must be longer than HTTP_REQUEST_TIMEOUT (%s)", cfg.HTTPWriteTimeout, cfg.HTTPRequestTimeout)
+	}
+	if cfg.HTTPLongRequestTimeout < cfg.HTTPWriteTimeout {
+		return nil, fmt.Errorf("HTTP_LONG_REQUEST_TIMEOUT (%s) must not be shorter than HTTP_WRITE_TIMEOUT (%s)", cfg.HTTPLongRequestTimeout, cfg.HTTPWriteTimeout)
+	}
+
 	// Validate storage configuration
 	if cfg.StorageProvider == "r2" {
 		if cfg.R2AccountID == "" {
@@ -189,18 +466,135 @@ func NewConfig() (*Config, error) {
 		return nil, fmt.Errorf("STORAGE_PROVIDER must be either 'local' or 'r2', got: %s", cfg.StorageProvider)
 	}
 
-	// Validate AI provider configuration
-	if cfg.AIProvider == "anthropic" {
+	// Validate email provider configuration
+	switch cfg.EmailProvider {
+	case "smtp":
+		if cfg.SMTPHost == "" {
+			return nil, fmt.Errorf("SMTP_HOST is required when EMAIL_PROVIDER is 'smtp'")
+		}
+	case "postmark":
+		if cfg.PostmarkToken == "" {
+			return nil, fmt.Errorf("POSTMARK_SERVER_TOKEN is required when EMAIL_PROVIDER is 'postmark'")
+		}
+	case "log", "file":
+		if cfg.Env == "production" {
+			return nil, fmt.Errorf("EMAIL_PROVIDER '%s' cannot be used in production", cfg.EmailProvider)
+		}
+	default:
+		return nil, fmt.Errorf("EMAIL_PROVIDER must be 'smtp', 'postmark', 'log', or 'file', got: %s", cfg.EmailProvider)
+	}
+	if cfg.EmailSendTimeout <= 0 {
+		return nil, fmt.Errorf("EMAIL_SEND_TIMEOUT must be positive, got: %s", cfg.EmailSendTimeout)
+	}
+	if cfg.SMTPDialTimeout <= 0 {
+		return nil, fmt.Errorf("SMTP_DIAL_TIMEOUT must be positive, got: %s", cfg.SMTPDialTimeout)
+	}
+	if cfg.SMTPMaxAttempts < 1 {
+		return nil, fmt.Errorf("SMTP_MAX_ATTEMPTS must be at least 1, got: %d", cfg.SMTPMaxAttempts)
+	}
+	if cfg.SMTPRetryBaseDelay <= 0 {
+		return nil, fmt.Errorf("SMTP_RETRY_BASE_DELAY must be positive, got: %s", cfg.SMTPRetryBaseDelay)
+	}
+	if cfg.SMTPBreakerThreshold < 0 {
+		return nil, fmt.Errorf("SMTP_BREAKER_THRESHOLD must not be negative, got: %d", cfg.SMTPBreakerThreshold)
+	}
+	if cfg.SMTPBreakerCooldown <= 0 {
+		return nil, fmt.Errorf("SMTP_BREAKER_COOLDOWN must be positive, got: %s", cfg.SMTPBreakerCooldown)
+	}
+	if cfg.SessionIdleTimeout < 0 {
+		return nil, fmt.Errorf("SESSION_IDLE_TIMEOUT must not be negative, got: %s", cfg.SessionIdleTimeout)
+	}
+	if cfg.EmailVerificationGracePeriod < 0 {
+		return nil, fmt.Errorf("EMAIL_VERIFICATION_GRACE_PERIOD must not be negative, got: %s", cfg.EmailVerificationGracePeriod)
+	}
+	if cfg.VerificationReminderDelay <= 0 {
+		return nil, fmt.Errorf("VERIFICATION_REMINDER_DELAY must be positive, got: %s", cfg.VerificationReminderDelay)
+	}
+	if cfg.VerificationReminderMax < 0 {
+		return nil, fmt.Errorf("VERIFICATION_REMINDER_MAX must not be negative, got: %d", cfg.VerificationReminderMax)
+	}
+	if cfg.RetentionMonths < 0 {
+		return nil, fmt.Errorf("RETENTION_MONTHS must not be negative, got: %d", cfg.RetentionMonths)
+	}
+
+	// Validate AI provider configuration. ANTHROPIC_MODEL is still read
+	// for existing deployments.
+	switch cfg.AIProvider {
+	case "anthropic":
 		if cfg.AnthropicAPIKey == "" {
 			return nil, fmt.Errorf("ANTHROPIC_API_KEY is required when AI_PROVIDER is 'anthropic'")
 		}
-	} else if cfg.AIProvider != "mock" {
-		return nil, fmt.Errorf("AI_PROVIDER must be either 'anthropic' or 'mock', got: %s", cfg.AIProvider)
+		if cfg.AIModel == "" {
+			cfg.AIModel = getEnv("ANTHROPIC_MODEL", "")
+		}
+	case "openai":
+		if cfg.OpenAIAPIKey == "" {
+			return nil, fmt.Errorf("OPENAI_API_KEY is required when AI_PROVIDER is 'openai'")
+		}
+		if u, err := url.Parse(cfg.OpenAIBaseURL); cfg.OpenAIBaseURL != "" && (err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "") {
+			return nil, fmt.Errorf("OPENAI_BASE_URL must be an http(s) URL, got: %s", cfg.OpenAIBaseURL)
+		}
+	case "mock":
+	default:
+		return nil, fmt.Errorf("AI_PROVIDER must be 'anthropic', 'openai', or 'mock', got: %s", cfg.AIProvider)
+	}
+	if cfg.AITemperature < 0 || cfg.AITemperature > 1 {
+		return nil, fmt.Errorf("AI_TEMPERATURE must be between 0 and 1, got: %g", cfg.AITemperature)
+	}
+	if cfg.AIConcurrency < 1 || cfg.AIConcurrency > 20 {
+		return nil, fmt.Errorf("AI_CONCURRENCY must be between 1 and 20, got: %d", cfg.AIConcurrency)
+	}
+	if cfg.AIMaxRetries < 1 {
+		return nil, fmt.Errorf("AI_MAX_RETRIES must be at least 1, got: %d", cfg.AIMaxRetries)
+	}
+
+	// Validate geocoding configuration
+	switch cfg.GeocoderProvider {
+	case "none", "nominatim":
+	case "google":
+		if cfg.GeocoderAPIKey == "" {
+			return nil, fmt.Errorf("GEOCODER_API_KEY is required when GEOCODER_PROVIDER is 'google'")
+		}
+	default:
+		return nil, fmt.Errorf("GEOCODER_PROVIDER must be 'none', 'nominatim', or 'google', got: %s", cfg.GeocoderProvider)
 	}
 
 	return cfg, nil
 }
 
+// UploadLimits returns the photo uploads the deployment accepts.
+func (c *Config) UploadLimits() domain.UploadLimits {
+	return domain.UploadLimits{
+		MaxSize:      int64(c.ImageMaxUploadMB) * 1024 * 1024,
+		ContentTypes: c.ImageAllowedTypes,
+	}
+}
+
+// splitEnvList splits a comma-separated value, dropping blank entries.
+func splitEnvList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// validCORSOrigin reports whether origin is "*" or a scheme and host with no
+// path, as browsers send in the Origin header.
+func validCORSOrigin(origin string) bool {
+	if origin == "*" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" &&
+		(u.Path == "" || u.Path == "/") && u.RawQuery == "" && u.User == nil
+}
+
 func getEnv(key, fallback string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -217,6 +611,15 @@ func getEnvInt(key string, fallback int) int {
 	return fallback
 }
 
+func getEnvFloat(key string, fallback float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	}
+	return fallback
+}
+
 func getEnvBool(key string, fallback bool) bool {
 	if value := os.Getenv(key); value != "" {
 		if b, err := strconv.ParseBool(value); err == nil {
@@ -0,0 +1,64 @@
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// PoolConfig sizes a database connection pool.
+type PoolConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+}
+
+// HTTPPoolConfig returns the pool settings for the HTTP server.
+func (c *Config) HTTPPoolConfig() PoolConfig {
+	return PoolConfig{
+		MaxOpenConns:    c.DBMaxOpenConns,
+		MaxIdleConns:    c.DBMaxIdleConns,
+		ConnMaxLifetime: c.DBConnMaxLifetime,
+		ConnMaxIdleTime: c.DBConnMaxIdleTime,
+	}
+}
+
+// WorkerPoolConfig returns the pool settings for the background worker.
+// The worker polls steadily, so it keeps all its connections idle between
+// jobs rather than reconnecting.
+func (c *Config) WorkerPoolConfig() PoolConfig {
+	return PoolConfig{
+		MaxOpenConns:    c.DBWorkerMaxOpenConns,
+		MaxIdleConns:    c.DBWorkerMaxOpenConns,
+		ConnMaxLifetime: c.DBConnMaxLifetime,
+		ConnMaxIdleTime: c.DBConnMaxIdleTime,
+	}
+}
+
+// OpenDB opens a connection pool to the database at url using the pgx
+// driver, sized by pool, and checks that it can connect. tracer, if not
+// nil, is called around every query, e.g. a dbtrace.SlowQueryLogger.
+func OpenDB(ctx context.Context, url string, pool PoolConfig, tracer pgx.QueryTracer) (*sql.DB, error) {
+	connConfig, err := pgx.ParseConfig(url)
+	if err != nil {
+		return nil, err
+	}
+	if tracer != nil {
+		connConfig.Tracer = tracer
+	}
+	db := stdlib.OpenDB(*connConfig)
+	db.SetMaxOpenConns(pool.MaxOpenConns)
+	db.SetMaxIdleConns(pool.MaxIdleConns)
+	db.SetConnMaxLifetime(pool.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(pool.ConnMaxIdleTime)
+
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return db, nil
+}
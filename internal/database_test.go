@@ -0,0 +1,97 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// =============================================================================
+// Pool Configuration Tests
+// =============================================================================
+
+func TestNewConfig_PoolDefaults(t *testing.T) {
+	t.Setenv("DATABASE_URL", "postgres://localhost/lukaut")
+	t.Setenv("WORKER_CONCURRENCY", "4")
+
+	cfg, err := NewConfig()
+	if err != nil {
+		t.Fatalf("NewConfig failed: %v", err)
+	}
+
+	if got, want := cfg.HTTPPoolConfig(), (PoolConfig{
+		MaxOpenConns:    20,
+		MaxIdleConns:    10,
+		ConnMaxLifetime: 30 * time.Minute,
+		ConnMaxIdleTime: 5 * time.Minute,
+	}); got != want {
+		t.Errorf("expected HTTP pool %+v, got %+v", want, got)
+	}
+	// A connection per concurrent job plus one to poll, all kept idle
+	if got, want := cfg.WorkerPoolConfig(), (PoolConfig{
+		MaxOpenConns:    6,
+		MaxIdleConns:    6,
+		ConnMaxLifetime: 30 * time.Minute,
+		ConnMaxIdleTime: 5 * time.Minute,
+	}); got != want {
+		t.Errorf("expected worker pool %+v, got %+v", want, got)
+	}
+}
+
+func TestNewConfig_PoolFromEnvironment(t *testing.T) {
+	t.Setenv("DATABASE_URL", "postgres://localhost/lukaut")
+	t.Setenv("DB_MAX_OPEN_CONNS", "40")
+	t.Setenv("DB_MAX_IDLE_CONNS", "15")
+	t.Setenv("DB_CONN_MAX_LIFETIME", "1h")
+	t.Setenv("DB_CONN_MAX_IDLE_TIME", "0")
+	t.Setenv("DB_WORKER_MAX_OPEN_CONNS", "8")
+
+	cfg, err := NewConfig()
+	if err != nil {
+		t.Fatalf("NewConfig failed: %v", err)
+	}
+
+	if got, want := cfg.HTTPPoolConfig(), (PoolConfig{
+		MaxOpenConns:    40,
+		MaxIdleConns:    15,
+		ConnMaxLifetime: time.Hour,
+	}); got != want {
+		t.Errorf("expected HTTP pool %+v, got %+v", want, got)
+	}
+	if got, want := cfg.WorkerPoolConfig(), (PoolConfig{
+		MaxOpenConns:    8,
+		MaxIdleConns:    8,
+		ConnMaxLifetime: time.Hour,
+	}); got != want {
+		t.Errorf("expected worker pool %+v, got %+v", want, got)
+	}
+}
+
+func TestNewConfig_InvalidPool(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{"no open connections", map[string]string{"DB_MAX_OPEN_CONNS": "0"}, "DB_MAX_OPEN_CONNS"},
+		{"more idle than open", map[string]string{"DB_MAX_OPEN_CONNS": "5", "DB_MAX_IDLE_CONNS": "6"}, "DB_MAX_IDLE_CONNS"},
+		{"negative idle", map[string]string{"DB_MAX_IDLE_CONNS": "-1"}, "DB_MAX_IDLE_CONNS"},
+		{"negative lifetime", map[string]string{"DB_CONN_MAX_LIFETIME": "-1m"}, "DB_CONN_MAX_LIFETIME"},
+		{"negative idle time", map[string]string{"DB_CONN_MAX_IDLE_TIME": "-1m"}, "DB_CONN_MAX_IDLE_TIME"},
+		{"no worker connections", map[string]string{"DB_WORKER_MAX_OPEN_CONNS": "0"}, "DB_WORKER_MAX_OPEN_CONNS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("DATABASE_URL", "postgres://localhost/lukaut")
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			_, err := NewConfig()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected an error naming %s, got %v", tt.want, err)
+			}
+		})
+	}
+}
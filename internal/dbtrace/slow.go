@@ -0,0 +1,109 @@
+// Package dbtrace times database queries made through pgx and logs the slow
+// ones, to help diagnose performance problems.
+package dbtrace
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// maxStatementLength caps the logged statement so one huge query cannot
+// flood the logs.
+const maxStatementLength = 1000
+
+// SlowQueryLogger is a pgx.QueryTracer that logs queries taking at least a
+// threshold, with their duration and a sanitized statement. It is attached
+// to the connection config, so it times every query on the pool, including
+// those inside transactions.
+//
+// Records are logged with the query's context, so queries made while
+// serving a request carry its request_id.
+type SlowQueryLogger struct {
+	logger    *slog.Logger
+	threshold time.Duration
+}
+
+// NewSlowQueryLogger creates a SlowQueryLogger that logs queries taking at
+// least threshold.
+func NewSlowQueryLogger(logger *slog.Logger, threshold time.Duration) *SlowQueryLogger {
+	return &SlowQueryLogger{logger: logger, threshold: threshold}
+}
+
+var _ pgx.QueryTracer = (*SlowQueryLogger)(nil)
+
+// queryStartKey is the context key for the query's queryStart.
+type queryStartKey struct{}
+
+// queryStart records when a query began and its SQL.
+type queryStart struct {
+	sql string
+	at  time.Time
+}
+
+// TraceQueryStart records the query's start time.
+func (t *SlowQueryLogger) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryStartKey{}, queryStart{sql: data.SQL, at: time.Now()})
+}
+
+// TraceQueryEnd logs the query if it took at least the threshold. Its
+// arguments are never logged.
+func (t *SlowQueryLogger) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	start, ok := ctx.Value(queryStartKey{}).(queryStart)
+	if !ok {
+		return
+	}
+	duration := time.Since(start.at)
+	if duration < t.threshold {
+		return
+	}
+
+	attrs := []any{
+		"duration", duration,
+		"query", QueryName(start.sql),
+		"statement", SanitizeStatement(start.sql),
+	}
+	if data.Err != nil {
+		attrs = append(attrs, "error", data.Err)
+	}
+	t.logger.WarnContext(ctx, "slow database query", attrs...)
+}
+
+// sqlcNamePattern matches the "-- name: GetUserByID :one" comment sqlc
+// puts at the start of each query.
+var sqlcNamePattern = regexp.MustCompile(`^\s*-- name: (\w+)`)
+
+// QueryName returns the sqlc name of a query, or "" for queries written
+// by hand.
+func QueryName(sql string) string {
+	if m := sqlcNamePattern.FindStringSubmatch(sql); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// literalOrCommentPattern matches string literals and line comments,
+// leftmost first, so "--" inside a literal or "'" inside a comment is not
+// mistaken for the other.
+var literalOrCommentPattern = regexp.MustCompile(`'(?:[^']|'')*'|--[^\n]*`)
+
+// SanitizeStatement prepares a statement for logging: comments are dropped,
+// string literals are replaced with '?' in case they hold user data,
+// whitespace is collapsed, and the result is truncated.
+func SanitizeStatement(sql string) string {
+	sql = literalOrCommentPattern.ReplaceAllStringFunc(sql, func(m string) string {
+		if strings.HasPrefix(m, "--") {
+			return " "
+		}
+		return "'?'"
+	})
+	sql = strings.Join(strings.Fields(sql), " ")
+	if len(sql) > maxStatementLength {
+		sql = sql[:maxStatementLength] + "..."
+	}
+	return sql
+}
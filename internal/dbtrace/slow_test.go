@@ -0,0 +1,87 @@
+package dbtrace
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/requestid"
+	"github.com/jackc/pgx/v5"
+)
+
+const stubQuery = `-- name: GetUserByEmail :one
+SELECT id, email FROM users
+WHERE email = $1 AND status <> 'deleted'
+`
+
+// runStubQuery traces a query that takes d, as pgx does around a real one.
+func runStubQuery(ctx context.Context, tracer pgx.QueryTracer, d time.Duration, err error) {
+	ctx = tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: stubQuery, Args: []any{"pat@example.com"}})
+	time.Sleep(d)
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: err})
+}
+
+func newTestLogger(buf *bytes.Buffer) *slog.Logger {
+	return slog.New(requestid.NewLogHandler(slog.NewTextHandler(buf, nil)))
+}
+
+func TestSlowQueryLogger_LogsSlowQuery(t *testing.T) {
+	var buf bytes.Buffer
+	tracer := NewSlowQueryLogger(newTestLogger(&buf), 10*time.Millisecond)
+	ctx := requestid.NewContext(context.Background(), "req-123")
+
+	runStubQuery(ctx, tracer, 20*time.Millisecond, errors.New("canceling statement due to statement timeout"))
+
+	out := buf.String()
+	for _, want := range []string{
+		"level=WARN",
+		`msg="slow database query"`,
+		"query=GetUserByEmail",
+		`statement="SELECT id, email FROM users WHERE email = $1 AND status <> '?'"`,
+		"request_id=req-123",
+		"statement timeout",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected log to contain %s, got %s", want, out)
+		}
+	}
+	if strings.Contains(out, "pat@example.com") {
+		t.Error("expected query arguments not to be logged")
+	}
+	if !strings.Contains(out, "duration=") {
+		t.Error("expected the duration to be logged")
+	}
+}
+
+func TestSlowQueryLogger_IgnoresFastQuery(t *testing.T) {
+	var buf bytes.Buffer
+	tracer := NewSlowQueryLogger(newTestLogger(&buf), time.Hour)
+
+	runStubQuery(context.Background(), tracer, 0, nil)
+
+	if buf.Len() != 0 {
+		t.Errorf("expected no log for a fast query, got %s", buf.String())
+	}
+}
+
+func TestSanitizeStatement(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want string
+	}{
+		{"SELECT 1", "SELECT 1"},
+		{"-- name: X :exec\nUPDATE jobs\n  SET status = 'failed'\nWHERE id = $1", "UPDATE jobs SET status = '?' WHERE id = $1"},
+		{"SELECT 'it''s' -- trailing\n, 2", "SELECT '?' , 2"},
+		{"SELECT 'a--b', 1 -- don't\n", "SELECT '?', 1"},
+		{strings.Repeat("x", maxStatementLength+10), strings.Repeat("x", maxStatementLength) + "..."},
+	}
+	for _, tt := range tests {
+		if got := SanitizeStatement(tt.sql); got != tt.want {
+			t.Errorf("SanitizeStatement(%q) = %q, want %q", tt.sql, got, tt.want)
+		}
+	}
+}
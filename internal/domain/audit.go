@@ -0,0 +1,183 @@
+// Package domain contains core business types and interfaces.
+//
+// This file defines the AuditEvent domain type used to record who changed
+// inspections, violations, and reports, and when.
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// Audit Entity Type
+// =============================================================================
+
+// AuditEntityType identifies the kind of record an audit event describes.
+type AuditEntityType string
+
+const (
+	// AuditEntityInspection indicates the event concerns an inspection.
+	AuditEntityInspection AuditEntityType = "inspection"
+
+	// AuditEntityViolation indicates the event concerns a violation.
+	AuditEntityViolation AuditEntityType = "violation"
+
+	// AuditEntityReport indicates the event concerns a report.
+	AuditEntityReport AuditEntityType = "report"
+
+	// AuditEntityUser indicates the event concerns a user account.
+	AuditEntityUser AuditEntityType = "user"
+
+	// AuditEntityJob indicates the event concerns a background job.
+	AuditEntityJob AuditEntityType = "job"
+)
+
+// String returns the string representation of the entity type.
+func (t AuditEntityType) String() string {
+	return string(t)
+}
+
+// =============================================================================
+// Audit Action
+// =============================================================================
+
+// AuditAction identifies what happened to the audited entity.
+type AuditAction string
+
+const (
+	// AuditActionStatusChanged indicates the entity's status was changed.
+	AuditActionStatusChanged AuditAction = "status_changed"
+
+	// AuditActionDeleted indicates the entity was deleted.
+	AuditActionDeleted AuditAction = "deleted"
+
+	// AuditActionReportRequested indicates report generation was requested.
+	AuditActionReportRequested AuditAction = "report_requested"
+
+	// AuditActionReportGenerated indicates a report was generated and stored.
+	AuditActionReportGenerated AuditAction = "report_generated"
+
+	// AuditActionEmailVerified indicates an admin manually verified a user's email.
+	AuditActionEmailVerified AuditAction = "email_verified"
+
+	// AuditActionQuotaOverrideChanged indicates an admin set or cleared a user's quota override.
+	AuditActionQuotaOverrideChanged AuditAction = "quota_override_changed"
+
+	// AuditActionAccountDisabled indicates an admin disabled a user account.
+	AuditActionAccountDisabled AuditAction = "account_disabled"
+
+	// AuditActionAccountEnabled indicates an admin re-enabled a user account.
+	AuditActionAccountEnabled AuditAction = "account_enabled"
+
+	// AuditActionSessionsRevoked indicates an admin signed a user out of all sessions.
+	AuditActionSessionsRevoked AuditAction = "sessions_revoked"
+
+	// AuditActionTierChanged indicates an admin changed a user's subscription tier.
+	AuditActionTierChanged AuditAction = "tier_changed"
+
+	// AuditActionJobRetried indicates an admin re-enqueued a failed job.
+	AuditActionJobRetried AuditAction = "job_retried"
+
+	// AuditActionLegalHoldChanged indicates an admin placed or released a legal hold.
+	AuditActionLegalHoldChanged AuditAction = "legal_hold_changed"
+
+	// AuditActionPurged indicates the data retention purge deleted the entity.
+	AuditActionPurged AuditAction = "purged"
+)
+
+// String returns the string representation of the action.
+func (a AuditAction) String() string {
+	return string(a)
+}
+
+// =============================================================================
+// Audit Event Domain Type
+// =============================================================================
+
+// AuditEvent records a single state change for liability purposes.
+//
+// OldValues and NewValues hold the changed fields (e.g. "status") before and
+// after the change. Either may be empty, e.g. NewValues for a deletion.
+type AuditEvent struct {
+	ID           uuid.UUID         // Unique identifier
+	ActorUserID  uuid.UUID         // User who made the change
+	ActorName    string            // Actor's display name (joined, may be empty)
+	InspectionID *uuid.UUID        // Inspection the entity belongs to, for history lookups
+	EntityType   AuditEntityType   // Kind of record changed
+	EntityID     uuid.UUID         // ID of the record changed
+	Action       AuditAction       // What happened
+	OldValues    map[string]string // Field values before the change
+	NewValues    map[string]string // Field values after the change
+	CreatedAt    time.Time         // When the change was recorded
+}
+
+// Summary returns a short human-readable description of the event,
+// e.g. "Violation confirmed" or "Inspection status changed from review to completed".
+func (e *AuditEvent) Summary() string {
+	switch e.Action {
+	case AuditActionStatusChanged:
+		newStatus := e.NewValues["status"]
+		if e.EntityType == AuditEntityViolation {
+			return fmt.Sprintf("Violation %s", newStatus)
+		}
+		if forced := e.NewValues["forced"]; forced != "" {
+			return fmt.Sprintf("Inspection %s despite %s", newStatus, forced)
+		}
+		if oldStatus := e.OldValues["status"]; oldStatus != "" {
+			return fmt.Sprintf("Inspection status changed from %s to %s", oldStatus, newStatus)
+		}
+		return fmt.Sprintf("Inspection status changed to %s", newStatus)
+	case AuditActionDeleted:
+		if e.EntityType == AuditEntityViolation {
+			if description := e.OldValues["description"]; description != "" {
+				return fmt.Sprintf("Violation deleted: %s", description)
+			}
+			return "Violation deleted"
+		}
+		return "Inspection deleted"
+	case AuditActionReportRequested:
+		if format := e.NewValues["format"]; format != "" {
+			return fmt.Sprintf("Report requested (%s)", format)
+		}
+		return "Report requested"
+	case AuditActionReportGenerated:
+		if format := e.NewValues["format"]; format != "" {
+			return fmt.Sprintf("Report generated (%s)", format)
+		}
+		return "Report generated"
+	case AuditActionEmailVerified:
+		return "Email manually verified"
+	case AuditActionQuotaOverrideChanged:
+		if len(e.NewValues) == 0 {
+			return "Quota override cleared"
+		}
+		return "Quota override updated"
+	case AuditActionAccountDisabled:
+		return "Account disabled"
+	case AuditActionAccountEnabled:
+		return "Account enabled"
+	case AuditActionSessionsRevoked:
+		return "Signed out of all sessions"
+	case AuditActionTierChanged:
+		if oldTier := e.OldValues["subscription_tier"]; oldTier != "" {
+			return fmt.Sprintf("Subscription tier changed from %s to %s", oldTier, e.NewValues["subscription_tier"])
+		}
+		return fmt.Sprintf("Subscription tier changed to %s", e.NewValues["subscription_tier"])
+	case AuditActionJobRetried:
+		if jobType := e.OldValues["job_type"]; jobType != "" {
+			return fmt.Sprintf("Job retried (%s)", jobType)
+		}
+		return "Job retried"
+	case AuditActionLegalHoldChanged:
+		if e.NewValues["legal_hold"] == "true" {
+			return "Legal hold placed"
+		}
+		return "Legal hold released"
+	case AuditActionPurged:
+		return "Inspection purged by data retention policy"
+	}
+	return fmt.Sprintf("%s %s", e.EntityType, e.Action)
+}
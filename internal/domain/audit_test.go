@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditEvent_Summary(t *testing.T) {
+	tests := []struct {
+		name  string
+		event AuditEvent
+		want  string
+	}{
+		{
+			name: "violation status change",
+			event: AuditEvent{
+				EntityType: AuditEntityViolation,
+				Action:     AuditActionStatusChanged,
+				OldValues:  map[string]string{"status": "pending"},
+				NewValues:  map[string]string{"status": "confirmed"},
+			},
+			want: "Violation confirmed",
+		},
+		{
+			name: "inspection status change",
+			event: AuditEvent{
+				EntityType: AuditEntityInspection,
+				Action:     AuditActionStatusChanged,
+				OldValues:  map[string]string{"status": "review"},
+				NewValues:  map[string]string{"status": "completed"},
+			},
+			want: "Inspection status changed from review to completed",
+		},
+		{
+			name: "inspection forced to completed",
+			event: AuditEvent{
+				EntityType: AuditEntityInspection,
+				Action:     AuditActionStatusChanged,
+				OldValues:  map[string]string{"status": "review"},
+				NewValues:  map[string]string{"status": "completed", "forced": "2 violations pending review"},
+			},
+			want: "Inspection completed despite 2 violations pending review",
+		},
+		{
+			name: "inspection status change without previous status",
+			event: AuditEvent{
+				EntityType: AuditEntityInspection,
+				Action:     AuditActionStatusChanged,
+				NewValues:  map[string]string{"status": "analyzing"},
+			},
+			want: "Inspection status changed to analyzing",
+		},
+		{
+			name: "violation deleted with description",
+			event: AuditEvent{
+				EntityType: AuditEntityViolation,
+				Action:     AuditActionDeleted,
+				OldValues:  map[string]string{"description": "Missing guardrail"},
+			},
+			want: "Violation deleted: Missing guardrail",
+		},
+		{
+			name:  "violation deleted without description",
+			event: AuditEvent{EntityType: AuditEntityViolation, Action: AuditActionDeleted},
+			want:  "Violation deleted",
+		},
+		{
+			name:  "inspection deleted",
+			event: AuditEvent{EntityType: AuditEntityInspection, Action: AuditActionDeleted},
+			want:  "Inspection deleted",
+		},
+		{
+			name: "report requested",
+			event: AuditEvent{
+				EntityType: AuditEntityInspection,
+				Action:     AuditActionReportRequested,
+				NewValues:  map[string]string{"format": "pdf"},
+			},
+			want: "Report requested (pdf)",
+		},
+		{
+			name: "report generated",
+			event: AuditEvent{
+				EntityType: AuditEntityReport,
+				Action:     AuditActionReportGenerated,
+				NewValues:  map[string]string{"format": "docx"},
+			},
+			want: "Report generated (docx)",
+		},
+		{
+			name: "quota override cleared",
+			event: AuditEvent{
+				EntityType: AuditEntityUser,
+				Action:     AuditActionQuotaOverrideChanged,
+				OldValues:  map[string]string{"analysis_per_month": "10"},
+			},
+			want: "Quota override cleared",
+		},
+		{
+			name: "tier changed",
+			event: AuditEvent{
+				EntityType: AuditEntityUser,
+				Action:     AuditActionTierChanged,
+				OldValues:  map[string]string{"subscription_tier": "free"},
+				NewValues:  map[string]string{"subscription_tier": "professional"},
+			},
+			want: "Subscription tier changed from free to professional",
+		},
+		{
+			name: "job retried",
+			event: AuditEvent{
+				EntityType: AuditEntityJob,
+				Action:     AuditActionJobRetried,
+				OldValues:  map[string]string{"job_type": "generate_report"},
+			},
+			want: "Job retried (generate_report)",
+		},
+		{
+			name: "legal hold placed",
+			event: AuditEvent{
+				EntityType: AuditEntityInspection,
+				Action:     AuditActionLegalHoldChanged,
+				NewValues:  map[string]string{"legal_hold": "true"},
+			},
+			want: "Legal hold placed",
+		},
+		{
+			name: "inspection purged",
+			event: AuditEvent{
+				EntityType: AuditEntityInspection,
+				Action:     AuditActionPurged,
+			},
+			want: "Inspection purged by data retention policy",
+		},
+		{
+			name:  "unknown action falls back to entity and action",
+			event: AuditEvent{EntityType: AuditEntityReport, Action: AuditAction("archived")},
+			want:  "report archived",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.event.Summary())
+		})
+	}
+}
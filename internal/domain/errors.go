@@ -3,6 +3,7 @@ package domain
 import (
 	"errors"
 	"fmt"
+	"time"
 )
 
 // Application error codes
@@ -15,6 +16,7 @@ const (
 	EGONE         = "gone"         // Resource no longer available
 	ETOOLARGE     = "too_large"    // Request entity too large
 	ERATELIMIT    = "rate_limit"   // Rate limit exceeded
+	EQUOTA        = "quota"        // Monthly plan quota exhausted
 	EINTERNAL     = "internal"     // Internal server error
 	ENOTIMPL      = "not_impl"     // Not implemented
 	EPAYMENT      = "payment"      // Payment required
@@ -165,11 +167,13 @@ func RateLimit(op string) *Error {
 }
 
 // QuotaExceeded creates a quota exceeded error with usage details.
-func QuotaExceeded(op string, quotaType QuotaType, used, limit int64) *Error {
+// resetsAt is when the quota period rolls over and is included in the message.
+func QuotaExceeded(op string, quotaType QuotaType, used, limit int64, resetsAt time.Time) *Error {
 	return &Error{
-		Code:    ERATELIMIT,
-		Op:      op,
-		Message: fmt.Sprintf("Monthly %s quota exceeded (%d/%d). Upgrade your plan for more.", quotaType, used, limit),
+		Code: EQUOTA,
+		Op:   op,
+		Message: fmt.Sprintf("You've used all %d of your monthly %s quota (%d/%d). Upgrade your plan or wait until %s.",
+			limit, quotaType, used, limit, resetsAt.Format("January 2, 2006")),
 	}
 }
 
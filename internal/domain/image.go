@@ -5,6 +5,15 @@
 package domain
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -51,26 +60,126 @@ func (s ImageAnalysisStatus) IsValid() bool {
 // =============================================================================
 
 // SupportedImageTypes maps MIME types to their human-readable names.
-// For MVP, only JPEG and PNG are supported (HEIC requires CGO).
+// These are stored as uploaded; see ConvertibleImageTypes for the formats
+// converted to JPEG first.
 var SupportedImageTypes = map[string]string{
 	"image/jpeg": "JPEG",
 	"image/png":  "PNG",
 }
 
+// ConvertibleImageTypes maps the MIME types accepted for upload but converted
+// to JPEG before storage to their human-readable names. iPhones shoot HEIC
+// by default.
+var ConvertibleImageTypes = map[string]string{
+	"image/heic": "HEIC",
+	"image/heif": "HEIF",
+}
+
 const (
 	// MaxImageSize is the maximum allowed size for uploaded images (20MB).
 	MaxImageSize = 20 * 1024 * 1024 // 20MB in bytes
 
-	// ThumbnailMaxWidth is the maximum width for generated thumbnails.
-	ThumbnailMaxWidth = 200
+	// MaxImagesPerInspection is the maximum number of photos an inspection
+	// can hold.
+	MaxImagesPerInspection = 200
+
+	// MaxConvertedImagePixels caps the pixel count of a photo converted to
+	// JPEG, so a small HEIC file can't decode into an image that exhausts
+	// memory. 64 megapixels leaves room for 48MP phone cameras.
+	MaxConvertedImagePixels = 64_000_000
+
+	// DefaultThumbnailSize is the bounding box (in pixels, both width and
+	// height) of the thumbnail shown in galleries. Its JPEG variant is the
+	// one recorded as the image's ThumbnailKey.
+	DefaultThumbnailSize = 200
 
-	// ThumbnailMaxHeight is the maximum height for generated thumbnails.
-	ThumbnailMaxHeight = 200
+	// LargeThumbnailSize is the bounding box of the larger preview variant.
+	LargeThumbnailSize = 600
 
 	// ThumbnailJPEGQuality is the JPEG quality for thumbnail generation (0-100).
 	ThumbnailJPEGQuality = 85
 )
 
+// DefaultThumbnailSizes is the thumbnail size set used when none is configured.
+var DefaultThumbnailSizes = []int{DefaultThumbnailSize, LargeThumbnailSize}
+
+// =============================================================================
+// Thumbnail Variants
+// =============================================================================
+
+// ThumbnailFormat is the encoding of a generated thumbnail variant.
+type ThumbnailFormat string
+
+const (
+	// ThumbnailFormatJPEG is always generated so every client can display it.
+	ThumbnailFormatJPEG ThumbnailFormat = "jpeg"
+
+	// ThumbnailFormatWebP is generated in addition to JPEG when enabled.
+	ThumbnailFormatWebP ThumbnailFormat = "webp"
+)
+
+// ContentType returns the MIME type for the format.
+func (f ThumbnailFormat) ContentType() string {
+	if f == ThumbnailFormatWebP {
+		return "image/webp"
+	}
+	return "image/jpeg"
+}
+
+// Extension returns the file extension (without dot) for the format.
+func (f ThumbnailFormat) Extension() string {
+	if f == ThumbnailFormatWebP {
+		return "webp"
+	}
+	return "jpg"
+}
+
+// ThumbnailVariantKey returns the storage key for one thumbnail variant.
+// Format: {base}_{size}.{ext}, e.g. "inspections/{id}/thumbnails/{imageID}_200.jpg".
+func ThumbnailVariantKey(base string, size int, format ThumbnailFormat) string {
+	return fmt.Sprintf("%s_%d.%s", base, size, format.Extension())
+}
+
+// ThumbnailKeyBase returns the base of a size-suffixed thumbnail key, i.e.
+// the inverse of ThumbnailVariantKey. It returns false for keys without a
+// size suffix, such as thumbnails stored before variants were introduced.
+func ThumbnailKeyBase(key string) (string, bool) {
+	dot := strings.LastIndex(key, ".")
+	if dot < 0 {
+		return "", false
+	}
+	underscore := strings.LastIndex(key[:dot], "_")
+	if underscore < 0 {
+		return "", false
+	}
+	size, err := strconv.Atoi(key[underscore+1 : dot])
+	if err != nil || size <= 0 {
+		return "", false
+	}
+	return key[:underscore], true
+}
+
+// ThumbnailKeys returns every thumbnail key that may exist for an image with
+// the given stored thumbnail key: the stored key plus its JPEG and WebP
+// variants at each size.
+func ThumbnailKeys(storedKey string, sizes []int) []string {
+	keys := []string{storedKey}
+
+	base, ok := ThumbnailKeyBase(storedKey)
+	if !ok {
+		return keys
+	}
+
+	for _, size := range sizes {
+		for _, format := range []ThumbnailFormat{ThumbnailFormatJPEG, ThumbnailFormatWebP} {
+			if key := ThumbnailVariantKey(base, size, format); key != storedKey {
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
+
 // =============================================================================
 // Image Domain Type
 // =============================================================================
@@ -90,6 +199,9 @@ type Image struct {
 	Width            int32               // Image width in pixels
 	Height           int32               // Image height in pixels
 	AnalysisStatus   ImageAnalysisStatus // Current AI analysis status
+	AnalysisError    string              // Why analysis last failed; empty once it succeeds
+	DisplayOrder     int32               // Position in the inspection's gallery, from 1
+	ContentHash      string              // Hex SHA-256 of the stored original; empty for older uploads
 	CreatedAt        time.Time           // When image was uploaded
 	UpdatedAt        time.Time           // When image was last modified
 
@@ -126,6 +238,25 @@ func (i *Image) SizeMB() float64 {
 	return float64(i.SizeBytes) / (1024 * 1024)
 }
 
+// ETag returns a strong entity tag for the stored object at key, which is
+// the original or one of its thumbnails. Stored objects never change once
+// written, so the content hash and key together identify the bytes. It
+// returns "" when the image has no recorded content hash.
+func (i *Image) ETag(key string) string {
+	if i.ContentHash == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(i.ContentHash + ":" + key))
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// ImageObject identifies a stored image file, the original or a thumbnail
+// variant, for serving its bytes directly.
+type ImageObject struct {
+	Key  string // Storage key
+	ETag string // Strong entity tag; empty when the image has no content hash
+}
+
 // =============================================================================
 // Image Service Parameters
 // =============================================================================
@@ -153,6 +284,13 @@ func IsValidImageContentType(contentType string) bool {
 	return ok
 }
 
+// IsConvertibleImageContentType checks if the content type is accepted for
+// upload once converted to JPEG.
+func IsConvertibleImageContentType(contentType string) bool {
+	_, ok := ConvertibleImageTypes[contentType]
+	return ok
+}
+
 // ValidateImageSize checks if the file size is within limits.
 func ValidateImageSize(size int64) error {
 	if size > MaxImageSize {
@@ -163,3 +301,206 @@ func ValidateImageSize(size int64) error {
 	}
 	return nil
 }
+
+// =============================================================================
+// Upload Limits
+// =============================================================================
+
+// UploadLimits are the photo uploads a deployment accepts. They can narrow,
+// but not widen, what MaxImageSize and the supported and convertible types
+// allow.
+type UploadLimits struct {
+	MaxSize      int64    // Largest accepted file, in bytes
+	ContentTypes []string // Accepted MIME types, in display order
+}
+
+// DefaultUploadLimits accepts every supported and convertible type up to
+// MaxImageSize.
+func DefaultUploadLimits() UploadLimits {
+	return UploadLimits{
+		MaxSize:      MaxImageSize,
+		ContentTypes: []string{"image/jpeg", "image/png", "image/heic", "image/heif"},
+	}
+}
+
+// Allows reports whether contentType is accepted.
+func (l UploadLimits) Allows(contentType string) bool {
+	return slices.Contains(l.ContentTypes, contentType)
+}
+
+// Check returns an error if a file of the given size and content type is
+// not accepted.
+func (l UploadLimits) Check(size int64, contentType string) error {
+	const op = "image.check_upload_limits"
+	if size > l.MaxSize {
+		return Errorf(ETOOLARGE, op, "File is %.1f MB, over the %s limit.", float64(size)/(1024*1024), l.MaxSizeLabel())
+	}
+	if size == 0 {
+		return Invalid(op, "Image file is empty")
+	}
+	if !l.Allows(contentType) {
+		return Invalid(op, fmt.Sprintf("Unsupported image type: %s. Only %s are supported.", contentType, l.TypeNames()))
+	}
+	return nil
+}
+
+// MaxSizeLabel returns the size limit for display, e.g. "20 MB".
+func (l UploadLimits) MaxSizeLabel() string {
+	return strconv.FormatFloat(float64(l.MaxSize)/(1024*1024), 'f', -1, 64) + " MB"
+}
+
+// TypeNames returns the accepted types' names for display, e.g.
+// "JPEG, PNG, and HEIC". HEIC and HEIF are named once, as HEIC.
+func (l UploadLimits) TypeNames() string {
+	var names []string
+	for _, contentType := range l.ContentTypes {
+		name, ok := SupportedImageTypes[contentType]
+		if !ok && IsConvertibleImageContentType(contentType) {
+			name = "HEIC"
+		}
+		if name != "" && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	switch len(names) {
+	case 0:
+		return ""
+	case 1:
+		return names[0]
+	case 2:
+		return names[0] + " and " + names[1]
+	}
+	return strings.Join(names[:len(names)-1], ", ") + ", and " + names[len(names)-1]
+}
+
+// Accept returns the accepted types as a file input's accept attribute.
+// File extensions are added for HEIC, which some browsers report without a
+// MIME type.
+func (l UploadLimits) Accept() string {
+	accept := slices.Clone(l.ContentTypes)
+	if slices.ContainsFunc(accept, IsConvertibleImageContentType) {
+		accept = append(accept, ".heic", ".heif")
+	}
+	return strings.Join(accept, ",")
+}
+
+// =============================================================================
+// Image Annotations
+// =============================================================================
+
+// AnnotationSource records who drew an image annotation.
+type AnnotationSource string
+
+const (
+	AnnotationSourceAI     AnnotationSource = "ai"     // Region returned by AI analysis
+	AnnotationSourceManual AnnotationSource = "manual" // Region drawn by the inspector
+)
+
+// IsValid returns true if the source is a recognized value.
+func (s AnnotationSource) IsValid() bool {
+	return s == AnnotationSourceAI || s == AnnotationSourceManual
+}
+
+// MaxAnnotationLabelLength is the longest label an annotation can have.
+const MaxAnnotationLabelLength = 255
+
+// AnnotationBox is a rectangle on an image, normalized to the image size:
+// 0,0 is the top left corner and 1,1 the bottom right.
+type AnnotationBox struct {
+	X      float64 // Left edge
+	Y      float64 // Top edge
+	Width  float64
+	Height float64
+}
+
+// Normalize checks that the box starts inside the image and has a size, and
+// trims any part that extends past the right or bottom edge. AI regions and
+// boxes dragged off the photo often overshoot slightly.
+func (b AnnotationBox) Normalize() (AnnotationBox, error) {
+	for _, v := range []float64{b.X, b.Y, b.Width, b.Height} {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return b, errors.New("coordinates must be numbers")
+		}
+	}
+	if b.X < 0 || b.X >= 1 || b.Y < 0 || b.Y >= 1 {
+		return b, errors.New("box must start inside the image (x and y from 0 to 1)")
+	}
+	if b.Width <= 0 || b.Height <= 0 {
+		return b, errors.New("box width and height must be greater than 0")
+	}
+	b.Width = math.Min(b.Width, 1-b.X)
+	b.Height = math.Min(b.Height, 1-b.Y)
+	return b, nil
+}
+
+// Zoom returns the area shown in a close-up of the box: the box grown by
+// margin times its size on every side, kept inside the image.
+func (b AnnotationBox) Zoom(margin float64) AnnotationBox {
+	left := math.Max(0, b.X-b.Width*margin)
+	top := math.Max(0, b.Y-b.Height*margin)
+	right := math.Min(1, b.X+b.Width*(1+margin))
+	bottom := math.Min(1, b.Y+b.Height*(1+margin))
+	return AnnotationBox{X: left, Y: top, Width: right - left, Height: bottom - top}
+}
+
+// Rect returns the pixels the box covers in an image with the given bounds,
+// at least one pixel wide and high.
+func (b AnnotationBox) Rect(bounds image.Rectangle) image.Rectangle {
+	x0, x1 := pixelSpan(b.X, b.Width, bounds.Dx())
+	y0, y1 := pixelSpan(b.Y, b.Height, bounds.Dy())
+	return image.Rect(x0, y0, x1, y1).Add(bounds.Min)
+}
+
+// pixelSpan converts a fractional start and length along an edge of size
+// pixels to pixel offsets covering at least one pixel.
+func pixelSpan(start, length float64, size int) (int, int) {
+	from := int(math.Round(start * float64(size)))
+	to := int(math.Round((start + length) * float64(size)))
+	to = min(max(to, from+1), size)
+	from = max(min(from, to-1), 0)
+	return from, to
+}
+
+// String formats the box as "x,y,width,height", the form of a thumbnail
+// crop parameter.
+func (b AnnotationBox) String() string {
+	return fmt.Sprintf("%.4f,%.4f,%.4f,%.4f", b.X, b.Y, b.Width, b.Height)
+}
+
+// ParseAnnotationBox reads a box formatted by String and normalizes it.
+func ParseAnnotationBox(s string) (AnnotationBox, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 4 {
+		return AnnotationBox{}, errors.New("box must be x,y,width,height")
+	}
+	var values [4]float64
+	for i, part := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return AnnotationBox{}, errors.New("coordinates must be numbers")
+		}
+		values[i] = v
+	}
+	return AnnotationBox{X: values[0], Y: values[1], Width: values[2], Height: values[3]}.Normalize()
+}
+
+// ImageAnnotation is a region of a photo that shows a violation, drawn over
+// the image during review.
+type ImageAnnotation struct {
+	ID          uuid.UUID
+	ViolationID uuid.UUID
+	ImageID     uuid.UUID
+	Box         AnnotationBox
+	Label       string // Short description shown with the box (may be empty)
+	Source      AnnotationSource
+	CreatedAt   time.Time
+}
+
+// CreateImageAnnotationParams contains parameters for an inspector drawing
+// a box on a violation's photo.
+type CreateImageAnnotationParams struct {
+	ViolationID uuid.UUID
+	UserID      uuid.UUID
+	Box         AnnotationBox
+	Label       string
+}
@@ -0,0 +1,186 @@
+package domain
+
+import (
+	"image"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThumbnailVariantKey(t *testing.T) {
+	base := "inspections/3f6c/thumbnails/9a1e-44b2"
+
+	assert.Equal(t, base+"_200.jpg", ThumbnailVariantKey(base, 200, ThumbnailFormatJPEG))
+	assert.Equal(t, base+"_600.webp", ThumbnailVariantKey(base, 600, ThumbnailFormatWebP))
+}
+
+func TestThumbnailKeyBase(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      string
+		wantBase string
+		wantOK   bool
+	}{
+		{
+			name:     "jpeg variant",
+			key:      "inspections/3f6c/thumbnails/9a1e-44b2_200.jpg",
+			wantBase: "inspections/3f6c/thumbnails/9a1e-44b2",
+			wantOK:   true,
+		},
+		{
+			name:     "webp variant",
+			key:      "inspections/3f6c/thumbnails/9a1e-44b2_600.webp",
+			wantBase: "inspections/3f6c/thumbnails/9a1e-44b2",
+			wantOK:   true,
+		},
+		{
+			name: "legacy key without size",
+			key:  "inspections/3f6c/thumbnails/9a1e-44b2.jpg",
+		},
+		{
+			name: "non-numeric suffix",
+			key:  "inspections/3f6c/thumbnails/site_photo.jpg",
+		},
+		{
+			name: "empty key",
+			key:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base, ok := ThumbnailKeyBase(tt.key)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantBase, base)
+		})
+	}
+}
+
+func TestThumbnailKeys(t *testing.T) {
+	base := "inspections/3f6c/thumbnails/9a1e-44b2"
+
+	assert.Equal(t, []string{
+		base + "_200.jpg",
+		base + "_200.webp",
+		base + "_600.jpg",
+		base + "_600.webp",
+	}, ThumbnailKeys(base+"_200.jpg", []int{200, 600}))
+
+	legacy := "inspections/3f6c/thumbnails/9a1e-44b2.jpg"
+	assert.Equal(t, []string{legacy}, ThumbnailKeys(legacy, []int{200, 600}))
+}
+
+func TestThumbnailFormat_ContentType(t *testing.T) {
+	assert.Equal(t, "image/jpeg", ThumbnailFormatJPEG.ContentType())
+	assert.Equal(t, "image/webp", ThumbnailFormatWebP.ContentType())
+}
+
+func TestImage_ETag(t *testing.T) {
+	img := &Image{ContentHash: "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"}
+
+	original := img.ETag("inspections/a/images/b.jpg")
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, original)
+	assert.Equal(t, original, img.ETag("inspections/a/images/b.jpg"))
+	assert.NotEqual(t, original, img.ETag("inspections/a/thumbnails/b_320.jpg"))
+	assert.Empty(t, (&Image{}).ETag("inspections/a/images/b.jpg"))
+}
+
+func TestUploadLimits_Check(t *testing.T) {
+	limits := UploadLimits{MaxSize: 10 * 1024 * 1024, ContentTypes: []string{"image/jpeg", "image/png"}}
+
+	assert.NoError(t, limits.Check(1024, "image/png"))
+	assert.Equal(t, ETOOLARGE, ErrorCode(limits.Check(12*1024*1024, "image/jpeg")))
+	assert.Equal(t, "File is 12.0 MB, over the 10 MB limit.", ErrorMessage(limits.Check(12*1024*1024, "image/jpeg")))
+	assert.Equal(t, EINVALID, ErrorCode(limits.Check(0, "image/jpeg")))
+	assert.Equal(t, "Unsupported image type: image/heic. Only JPEG and PNG are supported.", ErrorMessage(limits.Check(1024, "image/heic")))
+}
+
+func TestUploadLimits_TypeNamesAndAccept(t *testing.T) {
+	limits := DefaultUploadLimits()
+
+	assert.Equal(t, "JPEG, PNG, and HEIC", limits.TypeNames())
+	assert.Equal(t, "image/jpeg,image/png,image/heic,image/heif,.heic,.heif", limits.Accept())
+	assert.Equal(t, "20 MB", limits.MaxSizeLabel())
+
+	jpegOnly := UploadLimits{MaxSize: MaxImageSize, ContentTypes: []string{"image/jpeg"}}
+	assert.Equal(t, "JPEG", jpegOnly.TypeNames())
+	assert.Equal(t, "image/jpeg", jpegOnly.Accept())
+}
+
+func TestAnnotationBox_Normalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		box     AnnotationBox
+		want    AnnotationBox
+		wantErr bool
+	}{
+		{
+			name: "inside the image",
+			box:  AnnotationBox{X: 0.1, Y: 0.2, Width: 0.3, Height: 0.4},
+			want: AnnotationBox{X: 0.1, Y: 0.2, Width: 0.3, Height: 0.4},
+		},
+		{
+			name: "overshooting the edges is trimmed",
+			box:  AnnotationBox{X: 0.75, Y: 0.5, Width: 0.5, Height: 0.75},
+			want: AnnotationBox{X: 0.75, Y: 0.5, Width: 0.25, Height: 0.5},
+		},
+		{name: "negative x", box: AnnotationBox{X: -0.1, Y: 0, Width: 0.5, Height: 0.5}, wantErr: true},
+		{name: "starts past the right edge", box: AnnotationBox{X: 1, Y: 0, Width: 0.5, Height: 0.5}, wantErr: true},
+		{name: "zero width", box: AnnotationBox{X: 0.1, Y: 0.1, Width: 0, Height: 0.5}, wantErr: true},
+		{name: "NaN height", box: AnnotationBox{X: 0.1, Y: 0.1, Width: 0.5, Height: math.NaN()}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.box.Normalize()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAnnotationBox_Zoom(t *testing.T) {
+	box := AnnotationBox{X: 0.4, Y: 0.4, Width: 0.2, Height: 0.2}
+	got := box.Zoom(0.5)
+	assert.InDelta(t, 0.3, got.X, 1e-9)
+	assert.InDelta(t, 0.3, got.Y, 1e-9)
+	assert.InDelta(t, 0.4, got.Width, 1e-9)
+	assert.InDelta(t, 0.4, got.Height, 1e-9)
+
+	// Kept inside the image near its corner
+	corner := AnnotationBox{X: 0, Y: 0.9, Width: 0.2, Height: 0.1}.Zoom(0.5)
+	assert.Equal(t, 0.0, corner.X)
+	assert.InDelta(t, 0.3, corner.Width, 1e-9)
+	assert.InDelta(t, 1.0, corner.Y+corner.Height, 1e-9)
+}
+
+func TestAnnotationBox_Rect(t *testing.T) {
+	bounds := image.Rect(0, 0, 1000, 500)
+	assert.Equal(t, image.Rect(100, 100, 400, 300), AnnotationBox{X: 0.1, Y: 0.2, Width: 0.3, Height: 0.4}.Rect(bounds))
+	assert.Equal(t, image.Rect(0, 0, 1000, 500), AnnotationBox{X: 0, Y: 0, Width: 1, Height: 1}.Rect(bounds))
+	// A sliver still covers a pixel
+	assert.Equal(t, image.Rect(999, 499, 1000, 500), AnnotationBox{X: 0.9999, Y: 0.9999, Width: 0.0001, Height: 0.0001}.Rect(bounds))
+}
+
+func TestParseAnnotationBox(t *testing.T) {
+	box := AnnotationBox{X: 0.125, Y: 0.25, Width: 0.5, Height: 0.375}
+	got, err := ParseAnnotationBox(box.String())
+	assert.NoError(t, err)
+	assert.Equal(t, box, got)
+
+	for _, s := range []string{"", "0.1,0.2,0.3", "0.1,x,0.3,0.4", "-0.1,0.2,0.3,0.4", "0.1,0.2,0,0.4"} {
+		_, err := ParseAnnotationBox(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestAnnotationSource_IsValid(t *testing.T) {
+	assert.True(t, AnnotationSourceAI.IsValid())
+	assert.True(t, AnnotationSourceManual.IsValid())
+	assert.False(t, AnnotationSource("import").IsValid())
+}
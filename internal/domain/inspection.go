@@ -6,6 +6,7 @@ package domain
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -79,6 +80,33 @@ func (s InspectionStatus) CanTransitionTo(target InspectionStatus) bool {
 	return false
 }
 
+// inspectionStatuses lists every inspection status in workflow order.
+var inspectionStatuses = []InspectionStatus{
+	InspectionStatusDraft,
+	InspectionStatusAnalyzing,
+	InspectionStatusReview,
+	InspectionStatusCompleted,
+}
+
+// AllowedTransitions returns the statuses the inspection can move to from s,
+// in workflow order, so the UI only offers changes CanTransitionTo accepts.
+// The current status is not included.
+func (s InspectionStatus) AllowedTransitions() []InspectionStatus {
+	allowed := []InspectionStatus{}
+	for _, target := range inspectionStatuses {
+		if target != s && s.CanTransitionTo(target) {
+			allowed = append(allowed, target)
+		}
+	}
+	return allowed
+}
+
+// AllowsReportGeneration returns true if reports may be generated or
+// previewed for an inspection in this status (review or completed).
+func (s InspectionStatus) AllowsReportGeneration() bool {
+	return s == InspectionStatusReview || s == InspectionStatusCompleted
+}
+
 // TransitionTo validates and applies a status transition on the inspection.
 // Returns an error if the transition is not allowed by the state machine.
 func (i *Inspection) TransitionTo(newStatus InspectionStatus) error {
@@ -103,6 +131,7 @@ type AnalysisStatus struct {
 	PendingImages  int64
 	TotalImages    int64
 	AnalyzedImages int64
+	FailedImages   int64 // Images whose analysis failed; counted as analyzed, not pending
 	ViolationCount int64
 	Message        string
 	PollingEnabled bool
@@ -170,6 +199,10 @@ type Inspection struct {
 	InspectorNotes    string           // Optional: General notes from inspector
 	CreatedAt         time.Time        // When inspection was created
 	UpdatedAt         time.Time        // When inspection was last modified
+	Version           int32            // Incremented on every edit; see UpdateInspectionParams.Version
+	AssignedTo        *uuid.UUID       // Optional: Organization member the inspection is assigned to
+	IsTemplate        bool             // New inspections can be created from this one
+	TemplateName      string           // Name the template is listed under (if IsTemplate)
 
 	// Address fields (required)
 	AddressLine1 string // Street address
@@ -178,9 +211,15 @@ type Inspection struct {
 	State        string // State
 	PostalCode   string // Postal/ZIP code
 
+	// Geocoded coordinates (nil if the address was not recognized)
+	Latitude  *float64
+	Longitude *float64
+
 	// Computed fields (not stored in database, populated by queries/services)
 	ViolationCount int    // Number of violations found in this inspection
 	ClientName     string // Name of associated client (if any)
+	OwnerName      string // Name of the inspector who owns it (organization lists only)
+	AssigneeName   string // Name of the member it is assigned to (if any)
 }
 
 // HasClient returns true if the inspection is associated with a client.
@@ -198,6 +237,11 @@ func (i *Inspection) FullAddress() string {
 	return addr
 }
 
+// HasLocation returns true if the address has been geocoded.
+func (i *Inspection) HasLocation() bool {
+	return i.Latitude != nil && i.Longitude != nil
+}
+
 // IsEditable returns true if the inspection can be edited.
 // Inspections in analyzing status should not be edited as it may conflict
 // with ongoing AI analysis.
@@ -229,6 +273,7 @@ type CreateInspectionParams struct {
 	InspectionDate    time.Time  // Required: Date of inspection
 	WeatherConditions string     // Optional
 	Temperature       string     // Optional
+	Units             UnitSystem // Scale of a temperature typed without one; empty is imperial
 	InspectorNotes    string     // Optional
 	AddressLine1      string     // Required: Street address
 	AddressLine2      string     // Optional: Apt, suite, etc.
@@ -246,19 +291,87 @@ type UpdateInspectionParams struct {
 	InspectionDate    time.Time  // Required: Date of inspection
 	WeatherConditions string     // Optional
 	Temperature       string     // Optional
+	Units             UnitSystem // Scale of a temperature typed without one; empty is imperial
 	InspectorNotes    string     // Optional
 	AddressLine1      string     // Required: Street address
 	AddressLine2      string     // Optional: Apt, suite, etc.
 	City              string     // Required: City
 	State             string     // Required: State
 	PostalCode        string     // Required: Postal/ZIP code
+	Version           int32      // Version the edit was based on; a mismatch is a conflict
+}
+
+// AssignInspectionParams contains parameters for assigning an inspection.
+type AssignInspectionParams struct {
+	ID         uuid.UUID  // Inspection to assign
+	UserID     uuid.UUID  // User making the assignment (for authorization)
+	AssigneeID *uuid.UUID // Member to assign it to; nil unassigns it
 }
 
 // ListInspectionsParams contains parameters for listing inspections.
 type ListInspectionsParams struct {
-	UserID uuid.UUID // Filter by user
-	Limit  int32     // Max results to return
-	Offset int32     // Number of results to skip
+	UserID       uuid.UUID      // Filter by user
+	Organization bool           // Include inspections of the user's organization members
+	Sort         InspectionSort // Column to order by; empty uses DefaultInspectionSort
+	Descending   bool           // Order from largest to smallest
+	Limit        int32          // Max results to return
+	Offset       int32          // Number of results to skip
+}
+
+// =============================================================================
+// List Sorting
+// =============================================================================
+
+// InspectionSort is a column the inspection list can be ordered by.
+type InspectionSort string
+
+const (
+	InspectionSortInspectionDate InspectionSort = "inspection_date"
+	InspectionSortCreatedAt      InspectionSort = "created_at"
+	InspectionSortTitle          InspectionSort = "title"
+	InspectionSortClientName     InspectionSort = "client_name"
+	InspectionSortViolationCount InspectionSort = "violation_count"
+	InspectionSortStatus         InspectionSort = "status"
+)
+
+// DefaultInspectionSort lists the newest inspections first.
+const DefaultInspectionSort = InspectionSortCreatedAt
+
+// IsValid reports whether s is a column the list can be ordered by.
+func (s InspectionSort) IsValid() bool {
+	switch s {
+	case InspectionSortInspectionDate, InspectionSortCreatedAt, InspectionSortTitle,
+		InspectionSortClientName, InspectionSortViolationCount, InspectionSortStatus:
+		return true
+	}
+	return false
+}
+
+// DefaultDescending reports whether s is first sorted in descending order:
+// dates and counts largest first, text alphabetically.
+func (s InspectionSort) DefaultDescending() bool {
+	switch s {
+	case InspectionSortInspectionDate, InspectionSortCreatedAt, InspectionSortViolationCount:
+		return true
+	}
+	return false
+}
+
+// ParseInspectionSort validates the sort and dir query parameters of the
+// inspection list. An unknown column falls back to DefaultInspectionSort,
+// and a direction other than "asc" or "desc" to the column's default.
+func ParseInspectionSort(sort, dir string) (InspectionSort, bool) {
+	column := InspectionSort(sort)
+	if !column.IsValid() {
+		column = DefaultInspectionSort
+	}
+	switch dir {
+	case "asc":
+		return column, false
+	case "desc":
+		return column, true
+	}
+	return column, column.DefaultDescending()
 }
 
 // UpdateInspectionStatusParams contains parameters for updating inspection status.
@@ -266,6 +379,66 @@ type UpdateInspectionStatusParams struct {
 	ID     uuid.UUID        // Inspection to update
 	UserID uuid.UUID        // Owner (for authorization)
 	Status InspectionStatus // New status
+
+	// Force completes the inspection despite its CompletionBlockers. It only
+	// takes effect when Confirmation is CompletionForceConfirmation.
+	Force        bool
+	Confirmation string // Typed by the inspector to confirm Force
+}
+
+// CompletionForceConfirmation is the text an inspector types to complete an
+// inspection that is not ready.
+const CompletionForceConfirmation = "COMPLETE"
+
+// ConfirmsForce reports whether the params force completion with the
+// confirmation typed correctly.
+func (p UpdateInspectionStatusParams) ConfirmsForce() bool {
+	return p.Force && strings.TrimSpace(p.Confirmation) == CompletionForceConfirmation
+}
+
+// CompletionBlockers is what keeps an inspection from being completed: its
+// report would leave out violations still pending review, or there is no
+// report yet.
+type CompletionBlockers struct {
+	PendingViolations int  // Violations not yet confirmed or rejected
+	MissingReport     bool // No report has been generated
+}
+
+// Any reports whether anything blocks completion.
+func (b CompletionBlockers) Any() bool {
+	return b.PendingViolations > 0 || b.MissingReport
+}
+
+// MissingReportReason describes the blocker of an inspection without a report.
+const MissingReportReason = "no report has been generated"
+
+// PendingReason describes the violations pending review, e.g.
+// "3 violations pending review", or "" if there are none.
+func (b CompletionBlockers) PendingReason() string {
+	switch {
+	case b.PendingViolations == 1:
+		return "1 violation pending review"
+	case b.PendingViolations > 1:
+		return fmt.Sprintf("%d violations pending review", b.PendingViolations)
+	}
+	return ""
+}
+
+// Reasons describes each blocker.
+func (b CompletionBlockers) Reasons() []string {
+	var reasons []string
+	if pending := b.PendingReason(); pending != "" {
+		reasons = append(reasons, pending)
+	}
+	if b.MissingReport {
+		reasons = append(reasons, MissingReportReason)
+	}
+	return reasons
+}
+
+// Error describes the blockers as the reason completion was refused.
+func (b CompletionBlockers) Error() string {
+	return "cannot complete inspection: " + strings.Join(b.Reasons(), "; ")
 }
 
 // =============================================================================
@@ -309,3 +482,118 @@ func (r *ListInspectionsResult) TotalPages() int {
 	}
 	return int(pages)
 }
+
+// =============================================================================
+// Inspection Locations
+// =============================================================================
+
+// InspectionLocation is a geocoded inspection plotted on the dashboard map.
+type InspectionLocation struct {
+	ID             uuid.UUID
+	Title          string
+	Status         InspectionStatus
+	InspectionDate time.Time
+	Address        string // Street and city, for the marker popup
+	Latitude       float64
+	Longitude      float64
+}
+
+// =============================================================================
+// Weather Suggestions
+// =============================================================================
+
+// WeatherSuggestion is the weather looked up for an inspection's site and
+// date, formatted for the inspection's weather fields.
+type WeatherSuggestion struct {
+	Conditions  string // e.g. "Partially cloudy"
+	Temperature string // e.g. "72°F"
+}
+
+// =============================================================================
+// Inspection Drafts
+// =============================================================================
+
+// InspectionDraft holds the autosaved values of a user's unfinished
+// new-inspection form. Values are kept as typed and are not validated, so
+// ClientID and InspectionDate may not parse.
+type InspectionDraft struct {
+	UserID            uuid.UUID
+	Title             string
+	ClientID          string
+	AddressLine1      string
+	AddressLine2      string
+	City              string
+	State             string
+	PostalCode        string
+	InspectionDate    string // As typed; normally YYYY-MM-DD
+	WeatherConditions string
+	Temperature       string
+	InspectorNotes    string
+	UpdatedAt         time.Time
+}
+
+// SaveInspectionDraftParams contains the fields of an autosave. A nil field
+// was not sent and keeps its saved value; an empty string clears it.
+type SaveInspectionDraftParams struct {
+	UserID            uuid.UUID
+	Title             *string
+	ClientID          *string
+	AddressLine1      *string
+	AddressLine2      *string
+	City              *string
+	State             *string
+	PostalCode        *string
+	InspectionDate    *string
+	WeatherConditions *string
+	Temperature       *string
+	InspectorNotes    *string
+}
+
+// =============================================================================
+// Inspection Templates
+// =============================================================================
+
+// MaxTemplateNameLength is the longest template name accepted.
+const MaxTemplateNameLength = 100
+
+// InspectionTemplate is an inspection kept as a template, as listed for
+// creating new inspections from it.
+type InspectionTemplate struct {
+	ID           uuid.UUID // The template inspection
+	Name         string    // Name the template is listed under
+	Title        string    // Title given to new inspections
+	ClientName   string    // Name of the client new inspections are for (if any)
+	AddressLine1 string
+	AddressLine2 string
+	City         string
+	State        string
+	PostalCode   string
+	UpdatedAt    time.Time
+}
+
+// SaveInspectionTemplateParams contains parameters for keeping an
+// inspection as a template.
+type SaveInspectionTemplateParams struct {
+	ID     uuid.UUID // Inspection to keep as a template
+	UserID uuid.UUID // Owner (for authorization)
+	Name   string    // Required: name the template is listed under
+}
+
+// TemplateCreateParams returns the parameters for a new draft inspection
+// created from this template on date. The title, client, address, and
+// inspector notes are copied; the weather, photos, violations, assignee,
+// and coordinates belong to a single visit and are not.
+func (i *Inspection) TemplateCreateParams(userID uuid.UUID, date time.Time) CreateInspectionParams {
+	return CreateInspectionParams{
+		UserID:         userID,
+		ClientID:       i.ClientID,
+		Title:          i.Title,
+		InspectionDate: date,
+		InspectorNotes: i.InspectorNotes,
+		AddressLine1:   i.AddressLine1,
+		AddressLine2:   i.AddressLine2,
+		City:           i.City,
+		State:          i.State,
+		PostalCode:     i.PostalCode,
+	}
+}
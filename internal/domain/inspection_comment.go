@@ -0,0 +1,31 @@
+// Package domain contains core business types and interfaces.
+//
+// This file defines inspection comments: a discussion thread on an
+// inspection between its owner and their organization's members.
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxCommentLength is the longest comment body accepted, in characters.
+const MaxCommentLength = 5000
+
+// InspectionComment is one message in an inspection's discussion.
+type InspectionComment struct {
+	ID           uuid.UUID
+	InspectionID uuid.UUID
+	AuthorID     uuid.UUID
+	AuthorName   string
+	Body         string
+	CreatedAt    time.Time
+}
+
+// AddCommentParams contains the parameters for commenting on an inspection.
+type AddCommentParams struct {
+	InspectionID uuid.UUID
+	UserID       uuid.UUID // Author; the owner or a member of their organization
+	Body         string
+}
@@ -0,0 +1,107 @@
+// Package domain contains core business types and interfaces.
+//
+// This file defines the InspectionEvent domain type shown in the live
+// activity feed on an inspection while it is being analyzed.
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// Inspection Event Kind
+// =============================================================================
+
+// InspectionEventKind identifies what happened on an inspection.
+type InspectionEventKind string
+
+const (
+	// InspectionEventAnalysisStarted indicates an analysis run began.
+	InspectionEventAnalysisStarted InspectionEventKind = "analysis_started"
+
+	// InspectionEventImageAnalyzed indicates one image was analyzed.
+	InspectionEventImageAnalyzed InspectionEventKind = "image_analyzed"
+
+	// InspectionEventImageFailed indicates one image could not be analyzed.
+	InspectionEventImageFailed InspectionEventKind = "image_failed"
+
+	// InspectionEventAnalysisCompleted indicates an analysis run finished.
+	InspectionEventAnalysisCompleted InspectionEventKind = "analysis_completed"
+
+	// InspectionEventStatusChanged indicates the inspection's status changed.
+	InspectionEventStatusChanged InspectionEventKind = "status_changed"
+
+	// InspectionEventReportGenerated indicates a report was generated.
+	InspectionEventReportGenerated InspectionEventKind = "report_generated"
+)
+
+// String returns the string representation of the event kind.
+func (k InspectionEventKind) String() string {
+	return string(k)
+}
+
+// InspectionEventPageSize is the most events returned by one request for the
+// activity feed.
+const InspectionEventPageSize = 50
+
+// =============================================================================
+// Inspection Event
+// =============================================================================
+
+// InspectionEvent is one entry in an inspection's activity feed.
+// IDs increase in the order events were recorded, so the last ID a client has
+// seen is the cursor for fetching newer events.
+type InspectionEvent struct {
+	ID           int64
+	InspectionID uuid.UUID
+	Kind         InspectionEventKind
+	Data         map[string]string
+	CreatedAt    time.Time
+}
+
+// Summary returns a short human-readable description of the event,
+// e.g. "Analyzed photo IMG_0042.jpg: 2 potential violations".
+func (e *InspectionEvent) Summary() string {
+	switch e.Kind {
+	case InspectionEventAnalysisStarted:
+		if images := e.Data["images"]; images != "" {
+			return fmt.Sprintf("Analysis started for %s photo(s)", images)
+		}
+		return "Analysis started"
+	case InspectionEventImageAnalyzed:
+		return fmt.Sprintf("Analyzed %s: %s potential violation(s)", e.photo(), valueOr(e.Data["violations"], "0"))
+	case InspectionEventImageFailed:
+		return fmt.Sprintf("Could not analyze %s", e.photo())
+	case InspectionEventAnalysisCompleted:
+		return fmt.Sprintf("Analysis finished: %s photo(s) analyzed, %s failed, %s potential violation(s)",
+			valueOr(e.Data["analyzed"], "0"), valueOr(e.Data["failed"], "0"), valueOr(e.Data["violations"], "0"))
+	case InspectionEventStatusChanged:
+		return fmt.Sprintf("Status changed to %s", e.Data["status"])
+	case InspectionEventReportGenerated:
+		if format := e.Data["format"]; format != "" {
+			return fmt.Sprintf("Report generated (%s)", format)
+		}
+		return "Report generated"
+	default:
+		return string(e.Kind)
+	}
+}
+
+// photo names the event's photo for display.
+func (e *InspectionEvent) photo() string {
+	if filename := e.Data["filename"]; filename != "" {
+		return "photo " + filename
+	}
+	return "a photo"
+}
+
+// valueOr returns value, or fallback when value is empty.
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
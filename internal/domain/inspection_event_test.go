@@ -0,0 +1,55 @@
+package domain
+
+import "testing"
+
+func TestInspectionEvent_Summary(t *testing.T) {
+	tests := []struct {
+		name  string
+		event InspectionEvent
+		want  string
+	}{
+		{
+			name:  "analysis started",
+			event: InspectionEvent{Kind: InspectionEventAnalysisStarted, Data: map[string]string{"images": "3"}},
+			want:  "Analysis started for 3 photo(s)",
+		},
+		{
+			name:  "image analyzed",
+			event: InspectionEvent{Kind: InspectionEventImageAnalyzed, Data: map[string]string{"filename": "roof.jpg", "violations": "2"}},
+			want:  "Analyzed photo roof.jpg: 2 potential violation(s)",
+		},
+		{
+			name:  "image analyzed without filename",
+			event: InspectionEvent{Kind: InspectionEventImageAnalyzed},
+			want:  "Analyzed a photo: 0 potential violation(s)",
+		},
+		{
+			name:  "image failed",
+			event: InspectionEvent{Kind: InspectionEventImageFailed, Data: map[string]string{"filename": "roof.jpg"}},
+			want:  "Could not analyze photo roof.jpg",
+		},
+		{
+			name:  "analysis completed",
+			event: InspectionEvent{Kind: InspectionEventAnalysisCompleted, Data: map[string]string{"analyzed": "2", "failed": "1", "violations": "4"}},
+			want:  "Analysis finished: 2 photo(s) analyzed, 1 failed, 4 potential violation(s)",
+		},
+		{
+			name:  "status changed",
+			event: InspectionEvent{Kind: InspectionEventStatusChanged, Data: map[string]string{"status": "review"}},
+			want:  "Status changed to review",
+		},
+		{
+			name:  "report generated",
+			event: InspectionEvent{Kind: InspectionEventReportGenerated, Data: map[string]string{"format": "pdf"}},
+			want:  "Report generated (pdf)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.event.Summary(); got != tt.want {
+				t.Errorf("Summary() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -161,3 +161,71 @@ func TestInspection_TransitionTo(t *testing.T) {
 		})
 	}
 }
+
+func TestInspectionStatus_AllowsReportGeneration(t *testing.T) {
+	tests := []struct {
+		status InspectionStatus
+		want   bool
+	}{
+		{InspectionStatusDraft, false},
+		{InspectionStatusAnalyzing, false},
+		{InspectionStatusReview, true},
+		{InspectionStatusCompleted, true},
+		{InspectionStatus("unknown"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.status.AllowsReportGeneration())
+		})
+	}
+}
+
+func TestInspectionStatus_AllowedTransitions(t *testing.T) {
+	tests := []struct {
+		status InspectionStatus
+		want   []InspectionStatus
+	}{
+		{InspectionStatusDraft, []InspectionStatus{InspectionStatusAnalyzing}},
+		{InspectionStatusAnalyzing, []InspectionStatus{InspectionStatusDraft, InspectionStatusReview}},
+		{InspectionStatusReview, []InspectionStatus{InspectionStatusDraft, InspectionStatusCompleted}},
+		{InspectionStatusCompleted, []InspectionStatus{InspectionStatusDraft, InspectionStatusReview}},
+		{InspectionStatus("unknown"), []InspectionStatus{InspectionStatusDraft}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			got := tt.status.AllowedTransitions()
+			assert.Equal(t, tt.want, got)
+			for _, target := range got {
+				assert.True(t, tt.status.CanTransitionTo(target), "%s -> %s", tt.status, target)
+			}
+		})
+	}
+}
+
+func TestParseInspectionSort(t *testing.T) {
+	tests := []struct {
+		name     string
+		sort     string
+		dir      string
+		wantSort InspectionSort
+		wantDesc bool
+	}{
+		{name: "default", wantSort: InspectionSortCreatedAt, wantDesc: true},
+		{name: "explicit direction", sort: "title", dir: "desc", wantSort: InspectionSortTitle, wantDesc: true},
+		{name: "text defaults to ascending", sort: "client_name", wantSort: InspectionSortClientName, wantDesc: false},
+		{name: "counts default to descending", sort: "violation_count", wantSort: InspectionSortViolationCount, wantDesc: true},
+		{name: "unknown direction uses the column default", sort: "status", dir: "up", wantSort: InspectionSortStatus, wantDesc: false},
+		{name: "unknown column", sort: "password_hash", dir: "asc", wantSort: InspectionSortCreatedAt, wantDesc: false},
+		{name: "injection attempt", sort: "title; DROP TABLE inspections", wantSort: InspectionSortCreatedAt, wantDesc: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotSort, gotDesc := ParseInspectionSort(tt.sort, tt.dir)
+			assert.Equal(t, tt.wantSort, gotSort)
+			assert.Equal(t, tt.wantDesc, gotDesc)
+		})
+	}
+}
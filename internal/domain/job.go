@@ -0,0 +1,48 @@
+// Package domain contains core business types and interfaces.
+//
+// This file defines the Job domain type: a background job as its owner sees
+// it, for following analysis and report generation from the browser.
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JobStatus is the state of a background job.
+type JobStatus string
+
+const (
+	// JobStatusPending indicates the job is waiting to run, including
+	// between retries.
+	JobStatusPending JobStatus = "pending"
+
+	// JobStatusRunning indicates a worker is running the job.
+	JobStatusRunning JobStatus = "running"
+
+	// JobStatusCompleted indicates the job succeeded.
+	JobStatusCompleted JobStatus = "completed"
+
+	// JobStatusFailed indicates the job failed and will not be retried.
+	JobStatusFailed JobStatus = "failed"
+)
+
+// Done returns true if the job will not run again.
+func (s JobStatus) Done() bool {
+	return s == JobStatusCompleted || s == JobStatusFailed
+}
+
+// Job is a background job enqueued on behalf of a user.
+type Job struct {
+	ID          uuid.UUID
+	Type        string
+	Status      JobStatus
+	Attempts    int
+	MaxAttempts int
+	Result      json.RawMessage // Structured outcome written by the job handler, if any
+	Error       string          // Error from the last failed attempt
+	CreatedAt   time.Time
+	FinishedAt  *time.Time // nil until the job completes or fails for good
+}
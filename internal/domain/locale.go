@@ -0,0 +1,153 @@
+// Package domain contains core business types and interfaces.
+//
+// This file defines the locales dates are written in on reports and
+// inspection pages.
+package domain
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateLocale is a language and region whose conventions dates are written
+// in, as a BCP 47 tag.
+type DateLocale string
+
+const (
+	DateLocaleEnUS DateLocale = "en-US"
+	DateLocaleEnGB DateLocale = "en-GB"
+	DateLocaleDeDE DateLocale = "de-DE"
+	DateLocaleFrFR DateLocale = "fr-FR"
+	DateLocaleEsES DateLocale = "es-ES"
+)
+
+// DefaultDateLocale is used until the user picks another.
+const DefaultDateLocale = DateLocaleEnUS
+
+// DateLocales lists the supported locales in the order the settings page
+// shows them.
+var DateLocales = []DateLocale{
+	DateLocaleEnUS,
+	DateLocaleEnGB,
+	DateLocaleDeDE,
+	DateLocaleFrFR,
+	DateLocaleEsES,
+}
+
+// dateLocaleFormat describes how a locale writes dates. The date patterns
+// use {d} for the day, {month} for the month name, and {y} for the year;
+// dateTime joins {date} and {time}, where time is a Go time layout.
+type dateLocaleFormat struct {
+	label       string
+	months      [12]string
+	shortMonths [12]string
+	date        string
+	shortDate   string
+	dateTime    string
+	time        string
+}
+
+var dateLocaleFormats = map[DateLocale]dateLocaleFormat{
+	DateLocaleEnUS: {
+		label:       "English (United States)",
+		months:      [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		shortMonths: [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		date:        "{month} {d}, {y}",
+		shortDate:   "{month} {d}, {y}",
+		dateTime:    "{date} at {time}",
+		time:        "3:04 PM",
+	},
+	DateLocaleEnGB: {
+		label:       "English (United Kingdom)",
+		months:      [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		shortMonths: [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		date:        "{d} {month} {y}",
+		shortDate:   "{d} {month} {y}",
+		dateTime:    "{date} at {time}",
+		time:        "15:04",
+	},
+	DateLocaleDeDE: {
+		label:       "Deutsch (Deutschland)",
+		months:      [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		shortMonths: [12]string{"Jan.", "Feb.", "März", "Apr.", "Mai", "Juni", "Juli", "Aug.", "Sept.", "Okt.", "Nov.", "Dez."},
+		date:        "{d}. {month} {y}",
+		shortDate:   "{d}. {month} {y}",
+		dateTime:    "{date} um {time}",
+		time:        "15:04",
+	},
+	DateLocaleFrFR: {
+		label:       "Français (France)",
+		months:      [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		shortMonths: [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+		date:        "{d} {month} {y}",
+		shortDate:   "{d} {month} {y}",
+		dateTime:    "{date} à {time}",
+		time:        "15:04",
+	},
+	DateLocaleEsES: {
+		label:       "Español (España)",
+		months:      [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		shortMonths: [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+		date:        "{d} de {month} de {y}",
+		shortDate:   "{d} {month} {y}",
+		dateTime:    "{date}, {time}",
+		time:        "15:04",
+	},
+}
+
+// IsValid returns true if the locale is supported.
+func (l DateLocale) IsValid() bool {
+	_, ok := dateLocaleFormats[l]
+	return ok
+}
+
+// Label returns the locale's name in its own language.
+func (l DateLocale) Label() string {
+	if f, ok := dateLocaleFormats[l]; ok {
+		return f.label
+	}
+	return string(l)
+}
+
+// FormatDate writes t with the month spelled out, e.g. "March 5, 2025" or
+// "5. März 2025". Unsupported locales, including the zero value, use
+// DefaultDateLocale.
+func (l DateLocale) FormatDate(t time.Time) string {
+	f := l.format()
+	return formatLocaleDate(f.date, f.months, t)
+}
+
+// FormatShortDate writes t with the month abbreviated, e.g. "Mar 5, 2025"
+// or "5 mars 2025".
+func (l DateLocale) FormatShortDate(t time.Time) string {
+	f := l.format()
+	return formatLocaleDate(f.shortDate, f.shortMonths, t)
+}
+
+// FormatDateTime writes t as FormatDate does, followed by the time of day
+// on the locale's clock, e.g. "March 5, 2025 at 2:30 PM".
+func (l DateLocale) FormatDateTime(t time.Time) string {
+	f := l.format()
+	return strings.NewReplacer(
+		"{date}", formatLocaleDate(f.date, f.months, t),
+		"{time}", t.Format(f.time),
+	).Replace(f.dateTime)
+}
+
+// format returns the locale's format, or the default locale's.
+func (l DateLocale) format() dateLocaleFormat {
+	if f, ok := dateLocaleFormats[l]; ok {
+		return f
+	}
+	return dateLocaleFormats[DefaultDateLocale]
+}
+
+// formatLocaleDate fills in a date pattern of dateLocaleFormat.
+func formatLocaleDate(pattern string, months [12]string, t time.Time) string {
+	return strings.NewReplacer(
+		"{d}", strconv.Itoa(t.Day()),
+		"{month}", months[t.Month()-1],
+		"{y}", strconv.Itoa(t.Year()),
+	).Replace(pattern)
+}
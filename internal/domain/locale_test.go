@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDateLocale_Format(t *testing.T) {
+	date := time.Date(2025, time.March, 5, 14, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		locale       DateLocale
+		wantDate     string
+		wantShort    string
+		wantDateTime string
+	}{
+		{locale: DateLocaleEnUS, wantDate: "March 5, 2025", wantShort: "Mar 5, 2025", wantDateTime: "March 5, 2025 at 2:30 PM"},
+		{locale: DateLocaleDeDE, wantDate: "5. März 2025", wantShort: "5. März 2025", wantDateTime: "5. März 2025 um 14:30"},
+		{locale: DateLocaleEnGB, wantDate: "5 March 2025", wantShort: "5 Mar 2025", wantDateTime: "5 March 2025 at 14:30"},
+		{locale: DateLocaleFrFR, wantDate: "5 mars 2025", wantShort: "5 mars 2025", wantDateTime: "5 mars 2025 à 14:30"},
+		{locale: DateLocaleEsES, wantDate: "5 de marzo de 2025", wantShort: "5 mar 2025", wantDateTime: "5 de marzo de 2025, 14:30"},
+		{locale: "", wantDate: "March 5, 2025", wantShort: "Mar 5, 2025", wantDateTime: "March 5, 2025 at 2:30 PM"},
+		{locale: "xx-XX", wantDate: "March 5, 2025", wantShort: "Mar 5, 2025", wantDateTime: "March 5, 2025 at 2:30 PM"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.locale), func(t *testing.T) {
+			assert.Equal(t, tt.wantDate, tt.locale.FormatDate(date))
+			assert.Equal(t, tt.wantShort, tt.locale.FormatShortDate(date))
+			assert.Equal(t, tt.wantDateTime, tt.locale.FormatDateTime(date))
+		})
+	}
+}
+
+func TestDisplayFormat_Normalize(t *testing.T) {
+	got := DisplayFormat{Units: UnitsMetric, DateLocale: "xx-XX"}.Normalize()
+	assert.Equal(t, DisplayFormat{Units: UnitsMetric, DateLocale: DefaultDateLocale}, got)
+
+	assert.Equal(t, DefaultDisplayFormat(), DisplayFormat{}.Normalize())
+	assert.NoError(t, DefaultDisplayFormat().Validate())
+	assert.Equal(t, EINVALID, ErrorCode(DisplayFormat{Units: "kelvin", DateLocale: DateLocaleEnUS}.Validate()))
+}
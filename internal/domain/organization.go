@@ -0,0 +1,80 @@
+// Package domain contains core business types and interfaces.
+//
+// This file defines organizations: groups of inspectors at one company who
+// share their inspections with each other.
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OrganizationInviteTTL is how long an emailed invitation can be accepted.
+const OrganizationInviteTTL = 7 * 24 * time.Hour
+
+// OrganizationRole is a member's role in an organization.
+type OrganizationRole string
+
+const (
+	OrganizationRoleOwner  OrganizationRole = "owner"  // Can invite and remove members
+	OrganizationRoleMember OrganizationRole = "member" // Can work on the organization's inspections
+)
+
+// IsValid returns true if the role is a known organization role.
+func (r OrganizationRole) IsValid() bool {
+	return r == OrganizationRoleOwner || r == OrganizationRoleMember
+}
+
+// Membership describes the organization a user works in.
+//
+// A user who has not joined or created an organization works in an implicit
+// personal organization: Personal is true, OrganizationID is uuid.Nil and the
+// user is its owner. Nothing is stored for personal organizations.
+type Membership struct {
+	OrganizationID   uuid.UUID
+	OrganizationName string
+	Role             OrganizationRole
+	Personal         bool
+}
+
+// IsOwner returns true if the user can manage the organization's members.
+func (m *Membership) IsOwner() bool {
+	return m.Role == OrganizationRoleOwner
+}
+
+// OrganizationMember is a user in an organization.
+type OrganizationMember struct {
+	UserID   uuid.UUID
+	Name     string
+	Email    string
+	Role     OrganizationRole
+	JoinedAt time.Time
+}
+
+// OrganizationInvite is an invitation, emailed to Email, to join an organization.
+type OrganizationInvite struct {
+	ID               uuid.UUID
+	OrganizationID   uuid.UUID
+	OrganizationName string
+	Email            string
+	ExpiresAt        time.Time
+	AcceptedAt       *time.Time
+	CreatedAt        time.Time
+}
+
+// IsExpired returns true if the invitation has expired as of now.
+func (i *OrganizationInvite) IsExpired(now time.Time) bool {
+	return !now.Before(i.ExpiresAt)
+}
+
+// IsUsable returns true if the invitation can still be accepted as of now.
+func (i *OrganizationInvite) IsUsable(now time.Time) bool {
+	return i.AcceptedAt == nil && !i.IsExpired(now)
+}
+
+// InviteMemberParams contains parameters for inviting a user to an organization.
+type InviteMemberParams struct {
+	InviterID uuid.UUID // Owner sending the invitation
+	Email     string    // Address to send the invitation to
+}
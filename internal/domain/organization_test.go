@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrganizationInvite_IsUsable(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	acceptedAt := now.Add(-time.Hour)
+
+	tests := []struct {
+		name   string
+		invite OrganizationInvite
+		want   bool
+	}{
+		{
+			name:   "pending invitation",
+			invite: OrganizationInvite{ExpiresAt: now.Add(time.Hour)},
+			want:   true,
+		},
+		{
+			name:   "expired invitation",
+			invite: OrganizationInvite{ExpiresAt: now.Add(-time.Minute)},
+			want:   false,
+		},
+		{
+			name:   "expires exactly now",
+			invite: OrganizationInvite{ExpiresAt: now},
+			want:   false,
+		},
+		{
+			name:   "accepted invitation",
+			invite: OrganizationInvite{ExpiresAt: now.Add(time.Hour), AcceptedAt: &acceptedAt},
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.invite.IsUsable(now))
+		})
+	}
+}
+
+func TestOrganizationRole_IsValid(t *testing.T) {
+	assert.True(t, OrganizationRoleOwner.IsValid())
+	assert.True(t, OrganizationRoleMember.IsValid())
+	assert.False(t, OrganizationRole("admin").IsValid())
+}
@@ -0,0 +1,216 @@
+// Package domain contains core business types and interfaces.
+//
+// This file defines user preferences, such as how many rows list pages show,
+// the keys that drive the review queue, and how units and dates are shown.
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// =============================================================================
+// Page Size
+// =============================================================================
+
+// PreferencePerPage is the preference key for the list page size.
+const PreferencePerPage = "per_page"
+
+// DefaultPerPage is the list page size used until the user picks another.
+const DefaultPerPage = 20
+
+// PerPageOptions are the list page sizes a user can choose, smallest first.
+var PerPageOptions = []int{10, 20, 50, 100}
+
+// IsValidPerPage reports whether n is one of PerPageOptions.
+func IsValidPerPage(n int) bool {
+	for _, option := range PerPageOptions {
+		if n == option {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizePerPage returns n if it is one of PerPageOptions, otherwise
+// DefaultPerPage.
+func NormalizePerPage(n int) int {
+	if IsValidPerPage(n) {
+		return n
+	}
+	return DefaultPerPage
+}
+
+// =============================================================================
+// Review Queue Shortcuts
+// =============================================================================
+
+// PreferenceReviewShortcuts is the preference key for custom review queue
+// shortcuts.
+const PreferenceReviewShortcuts = "review_shortcuts"
+
+// ReviewAction is a review queue action that can be bound to a key.
+type ReviewAction string
+
+const (
+	ReviewActionConfirm ReviewAction = "confirm"
+	ReviewActionReject  ReviewAction = "reject"
+	ReviewActionEdit    ReviewAction = "edit"
+	ReviewActionUndo    ReviewAction = "undo"
+	ReviewActionNext    ReviewAction = "next"
+	ReviewActionPrev    ReviewAction = "prev"
+)
+
+// ReviewActions lists the review queue actions in the order the help
+// overlay shows them.
+var ReviewActions = []ReviewAction{
+	ReviewActionConfirm,
+	ReviewActionReject,
+	ReviewActionEdit,
+	ReviewActionUndo,
+	ReviewActionNext,
+	ReviewActionPrev,
+}
+
+// IsValid returns true if the action is a recognized value.
+func (a ReviewAction) IsValid() bool {
+	switch a {
+	case ReviewActionConfirm, ReviewActionReject, ReviewActionEdit,
+		ReviewActionUndo, ReviewActionNext, ReviewActionPrev:
+		return true
+	}
+	return false
+}
+
+// Label returns a human-readable description of the action.
+func (a ReviewAction) Label() string {
+	switch a {
+	case ReviewActionConfirm:
+		return "Accept violation"
+	case ReviewActionReject:
+		return "Reject violation"
+	case ReviewActionEdit:
+		return "Edit violation"
+	case ReviewActionUndo:
+		return "Undo last decision"
+	case ReviewActionNext:
+		return "Next violation"
+	case ReviewActionPrev:
+		return "Previous violation"
+	default:
+		return string(a)
+	}
+}
+
+// ReservedShortcutKeys are bound on every page of the app (help, new
+// client, new inspection, and the "g" go-to prefix), so review actions
+// can't use them.
+var ReservedShortcutKeys = []string{"?", "c", "i", "g"}
+
+// ReviewShortcuts maps every review queue action to its key. Keys are single
+// lowercase letters or digits. The arrow keys always move between
+// violations and Escape always leaves the queue, whatever the mapping.
+type ReviewShortcuts map[ReviewAction]string
+
+// DefaultReviewShortcuts returns the keys the review queue uses until the
+// user remaps them.
+func DefaultReviewShortcuts() ReviewShortcuts {
+	return ReviewShortcuts{
+		ReviewActionConfirm: "a",
+		ReviewActionReject:  "r",
+		ReviewActionEdit:    "e",
+		ReviewActionUndo:    "u",
+		ReviewActionNext:    "j",
+		ReviewActionPrev:    "k",
+	}
+}
+
+// MergeReviewShortcuts applies custom keys over the defaults. Keys are
+// trimmed and lowercased; actions missing from custom keep their default.
+// Returns EINVALID for an unknown action, a key that isn't a single letter
+// or digit, a reserved key, or two actions sharing a key.
+func MergeReviewShortcuts(custom map[ReviewAction]string) (ReviewShortcuts, error) {
+	const op = "preference.merge_review_shortcuts"
+
+	shortcuts := DefaultReviewShortcuts()
+	for action, key := range custom {
+		if !action.IsValid() {
+			return nil, Invalid(op, fmt.Sprintf("%q is not a review action", action))
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		if !isShortcutKey(key) {
+			return nil, Invalid(op, fmt.Sprintf("%s must be a single letter or digit", action.Label()))
+		}
+		for _, reserved := range ReservedShortcutKeys {
+			if key == reserved {
+				return nil, Invalid(op, fmt.Sprintf("%q is already used on every page and can't be assigned to %s", key, strings.ToLower(action.Label())))
+			}
+		}
+		shortcuts[action] = key
+	}
+
+	taken := make(map[string]ReviewAction, len(shortcuts))
+	for _, action := range ReviewActions {
+		key := shortcuts[action]
+		if other, ok := taken[key]; ok {
+			return nil, Invalid(op, fmt.Sprintf("%q is assigned to both %s and %s", key, strings.ToLower(other.Label()), strings.ToLower(action.Label())))
+		}
+		taken[key] = action
+	}
+
+	return shortcuts, nil
+}
+
+// isShortcutKey reports whether key is a single lowercase ASCII letter or
+// digit.
+func isShortcutKey(key string) bool {
+	if len(key) != 1 {
+		return false
+	}
+	c := key[0]
+	return ('a' <= c && c <= 'z') || ('0' <= c && c <= '9')
+}
+
+// =============================================================================
+// Units and Dates
+// =============================================================================
+
+// PreferenceDisplayFormat is the preference key for the unit system and
+// date locale.
+const PreferenceDisplayFormat = "display_format"
+
+// DisplayFormat is how measurements and dates are shown to a user, on
+// inspection pages and in the reports they generate.
+type DisplayFormat struct {
+	Units      UnitSystem `json:"units"`
+	DateLocale DateLocale `json:"date_locale"`
+}
+
+// DefaultDisplayFormat returns the format used until the user picks another.
+func DefaultDisplayFormat() DisplayFormat {
+	return DisplayFormat{Units: DefaultUnitSystem, DateLocale: DefaultDateLocale}
+}
+
+// Validate returns EINVALID if the unit system or locale is not supported.
+func (f DisplayFormat) Validate() error {
+	const op = "preference.validate_display_format"
+
+	if !f.Units.IsValid() {
+		return Invalid(op, fmt.Sprintf("%q is not a unit system", f.Units))
+	}
+	if !f.DateLocale.IsValid() {
+		return Invalid(op, fmt.Sprintf("%q is not a supported date format", f.DateLocale))
+	}
+	return nil
+}
+
+// Normalize replaces an unsupported unit system or locale with the default.
+func (f DisplayFormat) Normalize() DisplayFormat {
+	if !f.Units.IsValid() {
+		f.Units = DefaultUnitSystem
+	}
+	if !f.DateLocale.IsValid() {
+		f.DateLocale = DefaultDateLocale
+	}
+	return f
+}
@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizePerPage(t *testing.T) {
+	tests := []struct {
+		name string
+		n    int
+		want int
+	}{
+		{name: "offered size", n: 50, want: 50},
+		{name: "smallest size", n: 10, want: 10},
+		{name: "unset", n: 0, want: DefaultPerPage},
+		{name: "not offered", n: 25, want: DefaultPerPage},
+		{name: "negative", n: -10, want: DefaultPerPage},
+		{name: "too large", n: 1000, want: DefaultPerPage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NormalizePerPage(tt.n))
+		})
+	}
+}
+
+func TestDefaultReviewShortcuts(t *testing.T) {
+	shortcuts := DefaultReviewShortcuts()
+
+	assert.Len(t, shortcuts, len(ReviewActions))
+	assert.Equal(t, "a", shortcuts[ReviewActionConfirm])
+	assert.Equal(t, "r", shortcuts[ReviewActionReject])
+	assert.Equal(t, "j", shortcuts[ReviewActionNext])
+	assert.Equal(t, "k", shortcuts[ReviewActionPrev])
+	assert.Equal(t, "u", shortcuts[ReviewActionUndo])
+
+	// The defaults must pass their own validation
+	merged, err := MergeReviewShortcuts(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, shortcuts, merged)
+}
+
+func TestMergeReviewShortcuts(t *testing.T) {
+	tests := []struct {
+		name    string
+		custom  map[ReviewAction]string
+		want    map[ReviewAction]string // Expected keys for the remapped actions
+		wantErr string
+	}{
+		{
+			name:   "remap keeps other defaults",
+			custom: map[ReviewAction]string{ReviewActionConfirm: "Y"},
+			want:   map[ReviewAction]string{ReviewActionConfirm: "y", ReviewActionReject: "r"},
+		},
+		{
+			name:   "swap keys between actions",
+			custom: map[ReviewAction]string{ReviewActionConfirm: "r", ReviewActionReject: "a"},
+			want:   map[ReviewAction]string{ReviewActionConfirm: "r", ReviewActionReject: "a"},
+		},
+		{
+			name:    "conflicts with a default",
+			custom:  map[ReviewAction]string{ReviewActionConfirm: "r"},
+			wantErr: `"r" is assigned to both accept violation and reject violation`,
+		},
+		{
+			name:    "conflicts with another custom key",
+			custom:  map[ReviewAction]string{ReviewActionNext: "n", ReviewActionPrev: "N"},
+			wantErr: `"n" is assigned to both next violation and previous violation`,
+		},
+		{
+			name:    "reserved key",
+			custom:  map[ReviewAction]string{ReviewActionEdit: "g"},
+			wantErr: "already used on every page",
+		},
+		{
+			name:    "more than one character",
+			custom:  map[ReviewAction]string{ReviewActionUndo: "ctrl+z"},
+			wantErr: "single letter or digit",
+		},
+		{
+			name:    "unknown action",
+			custom:  map[ReviewAction]string{"approve": "p"},
+			wantErr: "not a review action",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MergeReviewShortcuts(tt.custom)
+			if tt.wantErr != "" {
+				assert.Equal(t, EINVALID, ErrorCode(err))
+				assert.Contains(t, ErrorMessage(err), tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, got, len(ReviewActions))
+			for action, key := range tt.want {
+				assert.Equal(t, key, got[action], string(action))
+			}
+		})
+	}
+}
@@ -3,6 +3,12 @@
 // This file defines quota types for rate limiting job enqueueing based on subscription tier.
 package domain
 
+import "time"
+
+// QuotaWarningThreshold is the fraction of a monthly quota remaining at or
+// below which users are warned before they hit the limit.
+const QuotaWarningThreshold = 0.2
+
 // QuotaType identifies the type of quota being checked.
 type QuotaType string
 
@@ -36,6 +42,35 @@ var TierQuotas = map[SubscriptionTier]TierQuota{
 	},
 }
 
+// QuotaOverride replaces a user's tier limits with admin-set values.
+// A nil limit keeps the tier's limit for that quota type.
+type QuotaOverride struct {
+	AnalysisPerMonth *int
+	ReportsPerMonth  *int
+}
+
+// IsEmpty returns true if the override changes neither limit.
+func (o *QuotaOverride) IsEmpty() bool {
+	return o == nil || (o.AnalysisPerMonth == nil && o.ReportsPerMonth == nil)
+}
+
+// WithOverride returns the quota with the override's limits applied.
+// An overridden limit is always finite, even on an unlimited tier.
+func (q TierQuota) WithOverride(o *QuotaOverride) TierQuota {
+	if o == nil {
+		return q
+	}
+	if o.AnalysisPerMonth != nil {
+		q.AnalysisPerMonth = *o.AnalysisPerMonth
+		q.UnlimitedAnalysis = false
+	}
+	if o.ReportsPerMonth != nil {
+		q.ReportsPerMonth = *o.ReportsPerMonth
+		q.UnlimitedReports = false
+	}
+	return q
+}
+
 // QuotaUsage represents current usage against quota limits.
 type QuotaUsage struct {
 	AnalysisUsed  int64
@@ -43,6 +78,73 @@ type QuotaUsage struct {
 	ReportsUsed   int64
 	ReportsLimit  int64
 	IsUnlimited   bool
+	ResetsAt      time.Time // Start of the next quota period
+}
+
+// AnalysisRemaining returns the number of analyses left this period.
+func (u *QuotaUsage) AnalysisRemaining() int64 {
+	return quotaRemaining(u.AnalysisUsed, u.AnalysisLimit)
+}
+
+// ReportsRemaining returns the number of reports left this period.
+func (u *QuotaUsage) ReportsRemaining() int64 {
+	return quotaRemaining(u.ReportsUsed, u.ReportsLimit)
+}
+
+// AnalysisPercentUsed returns analysis usage as a percentage (0-100).
+func (u *QuotaUsage) AnalysisPercentUsed() int {
+	return quotaPercentUsed(u.AnalysisUsed, u.AnalysisLimit)
+}
+
+// ReportsPercentUsed returns report usage as a percentage (0-100).
+func (u *QuotaUsage) ReportsPercentUsed() int {
+	return quotaPercentUsed(u.ReportsUsed, u.ReportsLimit)
+}
+
+// AnalysisNearLimit returns true if remaining analyses have dropped to the
+// warning threshold. The threshold is at least one analysis, so small quotas
+// still warn before the last one is used.
+func (u *QuotaUsage) AnalysisNearLimit() bool {
+	return !u.IsUnlimited && quotaNearLimit(u.AnalysisUsed, u.AnalysisLimit)
+}
+
+// ReportsNearLimit returns true if remaining reports have dropped to the
+// warning threshold.
+func (u *QuotaUsage) ReportsNearLimit() bool {
+	return !u.IsUnlimited && quotaNearLimit(u.ReportsUsed, u.ReportsLimit)
+}
+
+// AnalysisExhausted returns true if no analyses remain this period.
+func (u *QuotaUsage) AnalysisExhausted() bool {
+	return !u.IsUnlimited && u.AnalysisLimit > 0 && u.AnalysisRemaining() == 0
+}
+
+func quotaRemaining(used, limit int64) int64 {
+	if used >= limit {
+		return 0
+	}
+	return limit - used
+}
+
+func quotaNearLimit(used, limit int64) bool {
+	if limit <= 0 {
+		return false
+	}
+	threshold := int64(float64(limit) * QuotaWarningThreshold)
+	if threshold < 1 {
+		threshold = 1
+	}
+	return quotaRemaining(used, limit) <= threshold
+}
+
+func quotaPercentUsed(used, limit int64) int {
+	if limit <= 0 {
+		return 0
+	}
+	if used >= limit {
+		return 100
+	}
+	return int(used * 100 / limit)
 }
 
 // GetTierQuota returns the quota for a tier, defaulting to free tier for unknown tiers.
@@ -0,0 +1,150 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuotaUsage_Analysis(t *testing.T) {
+	tests := []struct {
+		name          string
+		usage         QuotaUsage
+		wantRemaining int64
+		wantPercent   int
+		wantNearLimit bool
+		wantExhausted bool
+	}{
+		{
+			name:          "fresh free tier",
+			usage:         QuotaUsage{AnalysisUsed: 0, AnalysisLimit: 3},
+			wantRemaining: 3,
+			wantPercent:   0,
+		},
+		{
+			name:          "last analysis of small quota warns",
+			usage:         QuotaUsage{AnalysisUsed: 2, AnalysisLimit: 3},
+			wantRemaining: 1,
+			wantPercent:   66,
+			wantNearLimit: true,
+		},
+		{
+			name:          "above threshold on larger quota",
+			usage:         QuotaUsage{AnalysisUsed: 7, AnalysisLimit: 10},
+			wantRemaining: 3,
+			wantPercent:   70,
+		},
+		{
+			name:          "at threshold on larger quota",
+			usage:         QuotaUsage{AnalysisUsed: 8, AnalysisLimit: 10},
+			wantRemaining: 2,
+			wantPercent:   80,
+			wantNearLimit: true,
+		},
+		{
+			name:          "exhausted",
+			usage:         QuotaUsage{AnalysisUsed: 3, AnalysisLimit: 3},
+			wantRemaining: 0,
+			wantPercent:   100,
+			wantNearLimit: true,
+			wantExhausted: true,
+		},
+		{
+			name:          "over limit clamps",
+			usage:         QuotaUsage{AnalysisUsed: 5, AnalysisLimit: 3},
+			wantRemaining: 0,
+			wantPercent:   100,
+			wantNearLimit: true,
+			wantExhausted: true,
+		},
+		{
+			name:  "unlimited never warns",
+			usage: QuotaUsage{IsUnlimited: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantRemaining, tt.usage.AnalysisRemaining())
+			assert.Equal(t, tt.wantPercent, tt.usage.AnalysisPercentUsed())
+			assert.Equal(t, tt.wantNearLimit, tt.usage.AnalysisNearLimit())
+			assert.Equal(t, tt.wantExhausted, tt.usage.AnalysisExhausted())
+		})
+	}
+}
+
+func TestQuotaUsage_Reports(t *testing.T) {
+	usage := QuotaUsage{ReportsUsed: 1, ReportsLimit: 2}
+
+	assert.Equal(t, int64(1), usage.ReportsRemaining())
+	assert.Equal(t, 50, usage.ReportsPercentUsed())
+	assert.True(t, usage.ReportsNearLimit())
+}
+
+func TestTierQuota_WithOverride(t *testing.T) {
+	ten := 10
+	zero := 0
+
+	t.Run("nil override keeps tier limits", func(t *testing.T) {
+		quota := GetTierQuota(SubscriptionTierFree).WithOverride(nil)
+		assert.Equal(t, TierQuotas[SubscriptionTierFree], quota)
+	})
+
+	t.Run("raises one limit on free tier", func(t *testing.T) {
+		quota := GetTierQuota(SubscriptionTierFree).WithOverride(&QuotaOverride{AnalysisPerMonth: &ten})
+		assert.Equal(t, 10, quota.AnalysisPerMonth)
+		assert.Equal(t, 2, quota.ReportsPerMonth)
+	})
+
+	t.Run("caps an unlimited tier", func(t *testing.T) {
+		quota := GetTierQuota(SubscriptionTierStarter).WithOverride(&QuotaOverride{ReportsPerMonth: &zero})
+		assert.True(t, quota.UnlimitedAnalysis)
+		assert.False(t, quota.UnlimitedReports)
+		assert.Equal(t, 0, quota.ReportsPerMonth)
+	})
+}
+
+func TestQuotaExceeded_IncludesResetDate(t *testing.T) {
+	resetsAt := time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)
+
+	err := QuotaExceeded("quota.check_analysis", QuotaTypeAnalysis, 3, 3, resetsAt)
+
+	assert.Equal(t, EQUOTA, ErrorCode(err))
+	assert.Contains(t, ErrorMessage(err), "Upgrade your plan or wait until February 1, 2026")
+}
+
+func TestUser_QuotaTier(t *testing.T) {
+	tests := []struct {
+		name string
+		user User
+		want SubscriptionTier
+	}{
+		{
+			name: "active subscription uses its tier",
+			user: User{SubscriptionStatus: SubscriptionStatusActive, SubscriptionTier: SubscriptionTierProfessional},
+			want: SubscriptionTierProfessional,
+		},
+		{
+			name: "trialing subscription uses its tier",
+			user: User{SubscriptionStatus: SubscriptionStatusTrialing, SubscriptionTier: SubscriptionTierStarter},
+			want: SubscriptionTierStarter,
+		},
+		{
+			name: "canceled subscription falls back to free",
+			user: User{SubscriptionStatus: SubscriptionStatusCanceled, SubscriptionTier: SubscriptionTierStarter},
+			want: SubscriptionTierFree,
+		},
+		{
+			name: "active without tier falls back to free",
+			user: User{SubscriptionStatus: SubscriptionStatusActive},
+			want: SubscriptionTierFree,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.user.QuotaTier())
+		})
+	}
+}
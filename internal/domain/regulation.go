@@ -41,6 +41,7 @@ type RegulationSummary struct {
 	Summary         string
 	SeverityTypical string
 	Rank            float32 // Search relevance rank (only populated for search results)
+	UseCount        int64   // Times linked to the user's violations (only populated for most-used lists)
 }
 
 // =============================================================================
@@ -53,6 +54,40 @@ type RegulationSearchResult struct {
 	Total       int64
 }
 
+// RegulationFilter narrows a regulation search or browse to a category and,
+// within it, a subcategory, and to a typical severity. Empty fields are not
+// applied.
+type RegulationFilter struct {
+	Category    string
+	Subcategory string            // Only applied together with Category
+	Severity    ViolationSeverity // Matched against SeverityTypical
+}
+
+// RegulationSeverities lists the typical severities regulations are filed
+// under, in the order the severity filter offers them.
+var RegulationSeverities = []ViolationSeverity{
+	ViolationSeverityCritical,
+	ViolationSeveritySerious,
+	ViolationSeverityOther,
+}
+
+// RecentRegulationViewsLimit caps how many recently viewed regulations are
+// kept per user.
+const RecentRegulationViewsLimit = 8
+
+// RegulationFacet is a category or subcategory with the number of regulations
+// in it.
+type RegulationFacet struct {
+	Name  string
+	Count int64
+}
+
+// RegulationFacets contains the facet counts for a search or browse.
+type RegulationFacets struct {
+	Categories    []RegulationFacet
+	Subcategories []RegulationFacet // Subcategories of the selected category; empty without one
+}
+
 // =============================================================================
 // Service Parameters
 // =============================================================================
@@ -73,3 +108,55 @@ type UnlinkRegulationParams struct {
 	RegulationID uuid.UUID
 	UserID       uuid.UUID
 }
+
+// =============================================================================
+// Regulation Import
+// =============================================================================
+
+// RegulationImportFormat identifies the file format of an OSHA standards export.
+type RegulationImportFormat string
+
+const (
+	// RegulationImportFormatCSV is a CSV file with a header row naming the columns.
+	RegulationImportFormatCSV RegulationImportFormat = "csv"
+
+	// RegulationImportFormatJSON is a JSON array of objects keyed by column name.
+	RegulationImportFormatJSON RegulationImportFormat = "json"
+)
+
+// IsValid returns true if the format is supported.
+func (f RegulationImportFormat) IsValid() bool {
+	switch f {
+	case RegulationImportFormatCSV, RegulationImportFormatJSON:
+		return true
+	default:
+		return false
+	}
+}
+
+// RegulationImportRecord is a single standard read from an import file.
+type RegulationImportRecord struct {
+	StandardNumber  string
+	Title           string
+	Category        string
+	Subcategory     string
+	FullText        string
+	Summary         string
+	SeverityTypical string
+	ParentStandard  string
+	EffectiveDate   *time.Time
+	LastUpdated     *time.Time
+}
+
+// RegulationImportSummary describes what a regulation import changed.
+// Malformed rows are skipped and described in Errors.
+type RegulationImportSummary struct {
+	ID          uuid.UUID
+	Source      string   // File or description the regulations were read from
+	Added       int      // New standards
+	Updated     int      // Existing standards whose content changed or that were reactivated
+	Unchanged   int      // Existing standards that already matched
+	Deactivated int      // Standards missing from the import
+	Errors      []string // One entry per skipped row
+	CreatedAt   time.Time
+}
@@ -5,7 +5,11 @@
 package domain
 
 import (
+	"fmt"
+	"net/mail"
+	"strings"
 	"time"
+	"unicode"
 
 	"github.com/google/uuid"
 )
@@ -62,13 +66,22 @@ func (f ReportFormat) FileExtension() string {
 
 // Report represents a generated inspection report stored in the database.
 type Report struct {
-	ID             uuid.UUID // Unique identifier
-	InspectionID   uuid.UUID // Inspection this report was generated from
-	UserID         uuid.UUID // User who generated the report
-	PDFStorageKey  string    // Storage key for PDF file (empty if not generated)
-	DOCXStorageKey string    // Storage key for DOCX file (empty if not generated)
-	ViolationCount int       // Number of violations included in report
-	GeneratedAt    time.Time // When report was generated
+	ID              uuid.UUID    // Unique identifier
+	InspectionID    uuid.UUID    // Inspection this report was generated from
+	UserID          uuid.UUID    // User who generated the report
+	PDFStorageKey   string       // Storage key for PDF file (empty if not generated)
+	DOCXStorageKey  string       // Storage key for DOCX file (empty if not generated)
+	ViolationCount  int          // Number of violations included in report
+	GeneratedAt     time.Time    // When report was generated
+	FailedAt        *time.Time   // When generation failed permanently (nil for generated reports)
+	FailedFormat    ReportFormat // Format requested by the failed generation job
+	RecipientEmails []string     // Addresses the report was emailed to, besides the inspector
+}
+
+// IsFailed returns true if this row records a failed generation job rather
+// than a downloadable report.
+func (r *Report) IsFailed() bool {
+	return r.FailedAt != nil
 }
 
 // HasPDF returns true if this report has a PDF version.
@@ -81,6 +94,201 @@ func (r *Report) HasDOCX() bool {
 	return r.DOCXStorageKey != ""
 }
 
+// =============================================================================
+// Report Recipients
+// =============================================================================
+
+// MaxReportRecipients caps the addresses one report can be emailed to.
+const MaxReportRecipients = 10
+
+// ParseReportRecipients splits a list of email addresses separated by commas,
+// semicolons, or whitespace. Addresses are lowercased and duplicates dropped,
+// keeping the first occurrence. An empty input yields no recipients.
+// Returns EINVALID for a malformed address or more than MaxReportRecipients.
+func ParseReportRecipients(input string) ([]string, error) {
+	const op = "report.parse_recipients"
+
+	fields := strings.FieldsFunc(input, func(r rune) bool {
+		return r == ',' || r == ';' || unicode.IsSpace(r)
+	})
+
+	recipients := []string{}
+	seen := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		addr := strings.ToLower(field)
+		if parsed, err := mail.ParseAddress(addr); err != nil || parsed.Address != addr {
+			return nil, Invalid(op, fmt.Sprintf("%q is not a valid email address", field))
+		}
+		if seen[addr] {
+			continue
+		}
+		seen[addr] = true
+		recipients = append(recipients, addr)
+	}
+
+	if len(recipients) > MaxReportRecipients {
+		return nil, Invalid(op, fmt.Sprintf("a report can be emailed to at most %d recipients", MaxReportRecipients))
+	}
+
+	return recipients, nil
+}
+
+// =============================================================================
+// Report Preview
+// =============================================================================
+
+const (
+	// MaxInlinePreviewViolations is the most confirmed violations a report
+	// preview renders while the user waits. Larger reports take too long to
+	// render within a request and must be generated in the background.
+	MaxInlinePreviewViolations = 25
+
+	// ReportPreviewCacheTTL is how long a rendered preview is reused while the
+	// inspection's report data stays the same.
+	ReportPreviewCacheTTL = 10 * time.Minute
+)
+
+// ReportPreview is what the report preview shows: a freshly rendered PDF, or
+// the latest generated report for inspections too large to render inline.
+// Neither is set when a large inspection has no report yet.
+type ReportPreview struct {
+	PDF            []byte  // Rendered PDF of the current report data
+	Report         *Report // Latest generated report with a PDF
+	ViolationCount int     // Confirmed violations in the report
+}
+
+// IsTooLarge returns true if the report is too large to render inline and
+// there is no generated report to show instead.
+func (p *ReportPreview) IsTooLarge() bool {
+	return p.PDF == nil && p.Report == nil
+}
+
+// =============================================================================
+// Violations Summary
+// =============================================================================
+
+// MaxSummaryRangeDays caps the span of a violations summary, counting both
+// ends of the range.
+const MaxSummaryRangeDays = 366
+
+// SummaryDateLayout is the layout of the from and to dates of a summary.
+const SummaryDateLayout = "2006-01-02"
+
+// SummarySeverities lists the severities of a violations summary in report
+// order. The empty severity collects violations that were never rated.
+var SummarySeverities = []ViolationSeverity{
+	ViolationSeverityCritical,
+	ViolationSeveritySerious,
+	ViolationSeverityOther,
+	ViolationSeverityRecommendation,
+	"",
+}
+
+// SummaryStatuses lists the statuses of a violations summary in report order.
+var SummaryStatuses = []ViolationStatus{
+	ViolationStatusConfirmed,
+	ViolationStatusPending,
+	ViolationStatusRejected,
+}
+
+// ParseSummaryRange parses the inclusive from and to dates of a violations
+// summary, both in SummaryDateLayout.
+// Returns EINVALID if a date is missing or malformed, or the range fails
+// ValidateSummaryRange.
+func ParseSummaryRange(from, to string) (time.Time, time.Time, error) {
+	const op = "report.parse_summary_range"
+
+	if from == "" || to == "" {
+		return time.Time{}, time.Time{}, Invalid(op, "Both a from and a to date are required")
+	}
+	fromDate, err := time.Parse(SummaryDateLayout, from)
+	if err != nil {
+		return time.Time{}, time.Time{}, Invalid(op, fmt.Sprintf("%q is not a valid from date (use YYYY-MM-DD)", from))
+	}
+	toDate, err := time.Parse(SummaryDateLayout, to)
+	if err != nil {
+		return time.Time{}, time.Time{}, Invalid(op, fmt.Sprintf("%q is not a valid to date (use YYYY-MM-DD)", to))
+	}
+	if err := ValidateSummaryRange(fromDate, toDate); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return fromDate, toDate, nil
+}
+
+// ValidateSummaryRange checks the inclusive date range of a violations
+// summary. Returns EINVALID if from is after to or the range spans more than
+// MaxSummaryRangeDays.
+func ValidateSummaryRange(from, to time.Time) error {
+	const op = "report.validate_summary_range"
+
+	if from.After(to) {
+		return Invalid(op, "The from date must not be after the to date")
+	}
+	if days := int(to.Sub(from).Hours()/24) + 1; days > MaxSummaryRangeDays {
+		return Invalid(op, fmt.Sprintf("A summary can cover at most %d days", MaxSummaryRangeDays))
+	}
+	return nil
+}
+
+// ViolationSummaryCount is the number of violations with one severity and
+// status.
+type ViolationSummaryCount struct {
+	Severity ViolationSeverity
+	Status   ViolationStatus
+	Count    int64
+}
+
+// ViolationSummary aggregates violations by severity and status across the
+// inspections dated within a range.
+type ViolationSummary struct {
+	From        time.Time // First inspection date included
+	To          time.Time // Last inspection date included
+	Counts      []ViolationSummaryCount
+	GeneratedAt time.Time
+}
+
+// Count returns the number of violations with the severity and status.
+func (s *ViolationSummary) Count(severity ViolationSeverity, status ViolationStatus) int64 {
+	var total int64
+	for _, c := range s.Counts {
+		if c.Severity == severity && c.Status == status {
+			total += c.Count
+		}
+	}
+	return total
+}
+
+// SeverityTotal returns the number of violations with the severity.
+func (s *ViolationSummary) SeverityTotal(severity ViolationSeverity) int64 {
+	var total int64
+	for _, c := range s.Counts {
+		if c.Severity == severity {
+			total += c.Count
+		}
+	}
+	return total
+}
+
+// StatusTotal returns the number of violations with the status.
+func (s *ViolationSummary) StatusTotal(status ViolationStatus) int64 {
+	var total int64
+	for _, c := range s.Counts {
+		if c.Status == status {
+			total += c.Count
+		}
+	}
+	return total
+}
+
+// Total returns the number of violations in the summary.
+func (s *ViolationSummary) Total() int64 {
+	var total int64
+	for _, c := range s.Counts {
+		total += c.Count
+	}
+	return total
+}
+
 // =============================================================================
 // Report Data Aggregates (for generation)
 // =============================================================================
@@ -96,13 +304,14 @@ type ReportData struct {
 	InspectorPhone   string // Contact phone
 	InspectorAddress string // Business address (formatted)
 	InspectorLogoURL string // URL to company logo (for embedding)
+	BrandColor       string // Hex color for headers; empty uses the default
 
 	// Inspection details
 	InspectionID      uuid.UUID // Inspection ID
 	InspectionTitle   string    // Inspection title
 	InspectionDate    time.Time // Date inspection was conducted
 	WeatherConditions string    // Weather during inspection
-	Temperature       string    // Temperature during inspection
+	Temperature       string    // Temperature during inspection, in the inspector's units
 	InspectorNotes    string    // General notes from inspector
 
 	// Site information
@@ -120,8 +329,22 @@ type ReportData struct {
 	// Violations with regulations
 	Violations []ReportViolation
 
+	// Boilerplate sections, with placeholders filled in
+	Boilerplate ReportTemplate
+
 	// Metadata
-	GeneratedAt time.Time // When report is being generated
+	GeneratedAt time.Time  // When report is being generated
+	DateLocale  DateLocale // Locale dates are written in; empty uses DefaultDateLocale
+}
+
+// PlaceholderValues returns the values of the ReportPlaceholders for this
+// report, keyed by placeholder name.
+func (d *ReportData) PlaceholderValues() map[string]string {
+	return map[string]string{
+		PlaceholderClientName:     d.ClientName,
+		PlaceholderInspectionDate: d.DateLocale.FormatDate(d.InspectionDate),
+		PlaceholderInspectorName:  d.InspectorName,
+	}
 }
 
 // TotalViolations returns the total number of violations.
@@ -183,9 +406,22 @@ type ReportViolation struct {
 	Severity       ViolationSeverity // Severity level
 	InspectorNotes string            // Additional inspector notes
 	ThumbnailURL   string            // URL to thumbnail image (presigned)
+	EvidenceURLs   []string          // URLs to thumbnails of additional photos, in attached order
+	Region         *AnnotationBox    // Optional: area of the primary image to show close up instead of the full thumbnail
+	OriginalURL    string            // URL to the full-size primary image the close-up is cut from (presigned)
 	Regulations    []ReportRegulation
 }
 
+// PhotoURLs returns the thumbnail URLs of all the violation's photos, the
+// primary image first.
+func (v *ReportViolation) PhotoURLs() []string {
+	urls := make([]string, 0, len(v.EvidenceURLs)+1)
+	if v.ThumbnailURL != "" {
+		urls = append(urls, v.ThumbnailURL)
+	}
+	return append(urls, v.EvidenceURLs...)
+}
+
 // PrimaryRegulation returns the primary regulation for this violation, if any.
 func (v *ReportViolation) PrimaryRegulation() *ReportRegulation {
 	for i := range v.Regulations {
@@ -0,0 +1,181 @@
+// Package domain contains core business types and interfaces.
+//
+// This file defines the replaceable boilerplate sections of generated
+// reports and the placeholders they may contain.
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxReportTemplateSectionLength is the longest boilerplate section, in
+// characters.
+const MaxReportTemplateSectionLength = 4000
+
+// Placeholders that boilerplate sections may contain, written {{Name}}.
+const (
+	PlaceholderClientName     = "ClientName"
+	PlaceholderInspectionDate = "InspectionDate"
+	PlaceholderInspectorName  = "InspectorName"
+)
+
+// ReportPlaceholders lists the supported placeholders in display order.
+var ReportPlaceholders = []string{
+	PlaceholderClientName,
+	PlaceholderInspectionDate,
+	PlaceholderInspectorName,
+}
+
+// ReportTemplate holds the boilerplate sections of a report. Sections are
+// plain text: blank lines separate paragraphs and placeholders are filled in
+// when a report is generated. An empty section is left out of the report.
+type ReportTemplate struct {
+	Intro      string // Opening paragraph(s) before the findings
+	Disclaimer string // Limitations of the inspection, after the findings
+	Signature  string // Sign-off block at the end of the report
+}
+
+// DefaultReportTemplate returns the sections used by inspectors who have not
+// customized their reports.
+func DefaultReportTemplate() ReportTemplate {
+	return ReportTemplate{
+		Intro: "This report presents the findings of the safety inspection conducted on {{InspectionDate}}. " +
+			"Each finding describes the observed condition, its severity, and the regulations that apply.",
+		Disclaimer: "This report reflects the conditions observed at the time of the inspection. " +
+			"It does not certify that the site is free of hazards that were not visible or accessible during the inspection.",
+		Signature: "Inspected by {{InspectorName}}\n{{InspectionDate}}",
+	}
+}
+
+// Validate checks every section with ValidateReportTemplateText.
+// Returns EINVALID naming the first section that fails.
+func (t ReportTemplate) Validate() error {
+	const op = "report_template.validate"
+
+	for _, section := range []struct{ label, text string }{
+		{"Introduction", t.Intro},
+		{"Disclaimer", t.Disclaimer},
+		{"Signature", t.Signature},
+	} {
+		if err := ValidateReportTemplateText(section.text); err != nil {
+			return Invalid(op, fmt.Sprintf("%s: %s", section.label, ErrorMessage(err)))
+		}
+	}
+	return nil
+}
+
+// Render returns the sections with their placeholders replaced by values,
+// keyed by placeholder name. Unknown placeholders are left as written.
+func (t ReportTemplate) Render(values map[string]string) ReportTemplate {
+	return ReportTemplate{
+		Intro:      RenderReportTemplateText(t.Intro, values),
+		Disclaimer: RenderReportTemplateText(t.Disclaimer, values),
+		Signature:  RenderReportTemplateText(t.Signature, values),
+	}
+}
+
+// ValidateReportTemplateText checks one boilerplate section.
+// Returns EINVALID if the text is longer than
+// MaxReportTemplateSectionLength, has an unclosed "{{", or names a
+// placeholder not in ReportPlaceholders.
+func ValidateReportTemplateText(text string) error {
+	const op = "report_template.validate_text"
+
+	if utf8.RuneCountInString(text) > MaxReportTemplateSectionLength {
+		return Invalid(op, fmt.Sprintf("must be %d characters or fewer", MaxReportTemplateSectionLength))
+	}
+
+	rest := text
+	for {
+		start := strings.Index(rest, "{{")
+		if start < 0 {
+			return nil
+		}
+		end := strings.Index(rest[start:], "}}")
+		if end < 0 {
+			return Invalid(op, `"{{" is not closed with "}}"`)
+		}
+		name := strings.TrimSpace(rest[start+2 : start+end])
+		if !isReportPlaceholder(name) {
+			return Invalid(op, fmt.Sprintf("{{%s}} is not a placeholder; use one of %s", name, placeholderList()))
+		}
+		rest = rest[start+end+2:]
+	}
+}
+
+// RenderReportTemplateText replaces each {{Name}} in text with values[Name].
+// Whitespace inside the braces is ignored. Unknown placeholders and an
+// unclosed "{{" are left as written, so text saved before a placeholder was
+// retired still renders.
+func RenderReportTemplateText(text string, values map[string]string) string {
+	var b strings.Builder
+	rest := text
+	for {
+		start := strings.Index(rest, "{{")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(rest[start:], "}}")
+		if end < 0 {
+			break
+		}
+		b.WriteString(rest[:start])
+		name := strings.TrimSpace(rest[start+2 : start+end])
+		if isReportPlaceholder(name) {
+			b.WriteString(values[name])
+		} else {
+			b.WriteString(rest[start : start+end+2])
+		}
+		rest = rest[start+end+2:]
+	}
+	b.WriteString(rest)
+	return b.String()
+}
+
+// ReportTemplateParagraphs splits rendered section text into paragraphs at
+// blank lines, and each paragraph into its lines. Generators lay the text
+// out from these pieces rather than from markup, so the text itself is
+// always escaped for the output format.
+func ReportTemplateParagraphs(text string) [][]string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	var paragraphs [][]string
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			if len(lines) > 0 {
+				paragraphs = append(paragraphs, lines)
+				lines = nil
+			}
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) > 0 {
+		paragraphs = append(paragraphs, lines)
+	}
+	return paragraphs
+}
+
+// isReportPlaceholder reports whether name is a supported placeholder.
+func isReportPlaceholder(name string) bool {
+	for _, p := range ReportPlaceholders {
+		if name == p {
+			return true
+		}
+	}
+	return false
+}
+
+// placeholderList returns the supported placeholders for error messages,
+// e.g. "{{ClientName}}, {{InspectionDate}}, {{InspectorName}}".
+func placeholderList() string {
+	names := make([]string, len(ReportPlaceholders))
+	for i, p := range ReportPlaceholders {
+		names[i] = "{{" + p + "}}"
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateReportTemplateText(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		wantErr string
+	}{
+		{name: "plain text", text: "No placeholders here."},
+		{name: "empty", text: ""},
+		{name: "known placeholders", text: "For {{ClientName}} on {{ InspectionDate }} by {{InspectorName}}"},
+		{name: "unknown placeholder", text: "At {{SiteName}}", wantErr: "{{SiteName}} is not a placeholder"},
+		{name: "placeholders are case sensitive", text: "{{clientname}}", wantErr: "{{clientname}} is not a placeholder"},
+		{name: "unclosed placeholder", text: "For {{ClientName", wantErr: "is not closed"},
+		{name: "too long", text: strings.Repeat("a", MaxReportTemplateSectionLength+1), wantErr: "characters or fewer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateReportTemplateText(tt.text)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Equal(t, EINVALID, ErrorCode(err))
+			assert.Contains(t, ErrorMessage(err), tt.wantErr)
+		})
+	}
+}
+
+func TestReportTemplate_ValidateNamesSection(t *testing.T) {
+	tmpl := DefaultReportTemplate()
+	assert.NoError(t, tmpl.Validate())
+
+	tmpl.Disclaimer = "{{Nope}}"
+	err := tmpl.Validate()
+	assert.Equal(t, EINVALID, ErrorCode(err))
+	assert.Contains(t, ErrorMessage(err), "Disclaimer: {{Nope}} is not a placeholder")
+}
+
+func TestRenderReportTemplateText(t *testing.T) {
+	values := map[string]string{
+		PlaceholderClientName:    "Acme <Builders>",
+		PlaceholderInspectorName: "Pat",
+	}
+
+	assert.Equal(t, "For Acme <Builders>, by Pat.", RenderReportTemplateText("For {{ClientName}}, by {{ InspectorName }}.", values))
+	assert.Equal(t, "Keep {{Unknown}} and {{open", RenderReportTemplateText("Keep {{Unknown}} and {{open", values))
+	assert.Equal(t, "On .", RenderReportTemplateText("On {{InspectionDate}}.", values))
+}
+
+func TestReportTemplateParagraphs(t *testing.T) {
+	got := ReportTemplateParagraphs("First line\r\nsecond line\n\n\n  Next paragraph  \n")
+	assert.Equal(t, [][]string{{"First line", "second line"}, {"Next paragraph"}}, got)
+	assert.Empty(t, ReportTemplateParagraphs(" \n "))
+}
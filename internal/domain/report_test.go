@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseReportRecipients(t *testing.T) {
+	tooMany := make([]string, MaxReportRecipients+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("client%d@example.com", i)
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "empty",
+			input: "  ",
+			want:  []string{},
+		},
+		{
+			name:  "single address",
+			input: "owner@example.com",
+			want:  []string{"owner@example.com"},
+		},
+		{
+			name:  "mixed separators",
+			input: "owner@example.com, gc@example.com;\nsafety@example.com  ",
+			want:  []string{"owner@example.com", "gc@example.com", "safety@example.com"},
+		},
+		{
+			name:  "duplicates differing in case",
+			input: "Owner@Example.com, gc@example.com, owner@example.com",
+			want:  []string{"owner@example.com", "gc@example.com"},
+		},
+		{
+			name:    "invalid address",
+			input:   "owner@example.com, not-an-email",
+			wantErr: true,
+		},
+		{
+			name:    "display name form",
+			input:   "<owner@example.com>",
+			wantErr: true,
+		},
+		{
+			name:    "over the cap",
+			input:   strings.Join(tooMany, ","),
+			wantErr: true,
+		},
+		{
+			name:  "duplicates don't count toward the cap",
+			input: strings.Join(append(tooMany[:MaxReportRecipients:MaxReportRecipients], tooMany[0]), ","),
+			want:  tooMany[:MaxReportRecipients],
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseReportRecipients(tt.input)
+			if tt.wantErr {
+				assert.Equal(t, EINVALID, ErrorCode(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseSummaryRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		wantErr  bool
+	}{
+		{name: "single day", from: "2025-03-01", to: "2025-03-01"},
+		{name: "leap year at the cap", from: "2024-01-01", to: "2024-12-31"},
+		{name: "one day over the cap", from: "2024-01-01", to: "2025-01-01", wantErr: true},
+		{name: "missing to", from: "2025-03-01", wantErr: true},
+		{name: "malformed from", from: "03/01/2025", to: "2025-03-31", wantErr: true},
+		{name: "from after to", from: "2025-03-02", to: "2025-03-01", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, err := ParseSummaryRange(tt.from, tt.to)
+			if tt.wantErr {
+				assert.Equal(t, EINVALID, ErrorCode(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.from, from.Format(SummaryDateLayout))
+			assert.Equal(t, tt.to, to.Format(SummaryDateLayout))
+		})
+	}
+}
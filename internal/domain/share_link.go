@@ -0,0 +1,85 @@
+// Package domain contains core business types and interfaces.
+//
+// This file defines share links: expiring, read-only public URLs to a
+// completed inspection's report for clients who do not have an account.
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// Share Link Configuration Constants
+// =============================================================================
+
+const (
+	// DefaultShareLinkTTL is how long a share link stays valid when the
+	// inspector does not choose an expiry.
+	DefaultShareLinkTTL = 7 * 24 * time.Hour
+
+	// MaxShareLinkTTL caps how long a share link can stay valid. Links are
+	// bearer credentials, so they should not live indefinitely.
+	MaxShareLinkTTL = 30 * 24 * time.Hour
+)
+
+// =============================================================================
+// Share Link
+// =============================================================================
+
+// ShareLink grants anyone holding its token read-only access to an
+// inspection's report, without signing in.
+//
+// Security model:
+// - Raw token (64 hex chars) appears only in the URL given to the inspector
+// - Only SHA-256 hash of token is stored in database
+// - Link stops working once expired, revoked, or out of views
+type ShareLink struct {
+	ID           uuid.UUID
+	InspectionID uuid.UUID
+	UserID       uuid.UUID // Inspector who created the link
+	ExpiresAt    time.Time
+	MaxViews     *int // nil = unlimited
+	ViewCount    int
+	RevokedAt    *time.Time // nil = active
+	CreatedAt    time.Time
+}
+
+// IsExpired returns true if the link has expired as of now.
+func (l *ShareLink) IsExpired(now time.Time) bool {
+	return !now.Before(l.ExpiresAt)
+}
+
+// IsRevoked returns true if the inspector revoked the link.
+func (l *ShareLink) IsRevoked() bool {
+	return l.RevokedAt != nil
+}
+
+// ViewsExhausted returns true if the link has a view limit and it has been reached.
+func (l *ShareLink) ViewsExhausted() bool {
+	return l.MaxViews != nil && l.ViewCount >= *l.MaxViews
+}
+
+// IsUsable returns true if the link can still be opened as of now.
+func (l *ShareLink) IsUsable(now time.Time) bool {
+	return !l.IsRevoked() && !l.IsExpired(now) && !l.ViewsExhausted()
+}
+
+// =============================================================================
+// Service Parameters
+// =============================================================================
+
+// CreateShareLinkParams contains parameters for creating a share link.
+type CreateShareLinkParams struct {
+	InspectionID uuid.UUID
+	UserID       uuid.UUID     // Owner of the inspection (from auth context)
+	TTL          time.Duration // Zero uses DefaultShareLinkTTL
+	MaxViews     *int          // Optional view limit; nil = unlimited
+}
+
+// ShareLinkResult contains a newly created share link and its raw token.
+type ShareLinkResult struct {
+	Link  *ShareLink
+	Token string // Raw token for the public URL (NOT the hash)
+}
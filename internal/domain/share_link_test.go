@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShareLink_IsUsable(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	revokedAt := now.Add(-time.Hour)
+	limit := 3
+
+	tests := []struct {
+		name string
+		link ShareLink
+		want bool
+	}{
+		{
+			name: "active link",
+			link: ShareLink{ExpiresAt: now.Add(time.Hour)},
+			want: true,
+		},
+		{
+			name: "expired link",
+			link: ShareLink{ExpiresAt: now.Add(-time.Minute)},
+			want: false,
+		},
+		{
+			name: "expires exactly now",
+			link: ShareLink{ExpiresAt: now},
+			want: false,
+		},
+		{
+			name: "revoked link",
+			link: ShareLink{ExpiresAt: now.Add(time.Hour), RevokedAt: &revokedAt},
+			want: false,
+		},
+		{
+			name: "views remaining",
+			link: ShareLink{ExpiresAt: now.Add(time.Hour), MaxViews: &limit, ViewCount: 2},
+			want: true,
+		},
+		{
+			name: "views exhausted",
+			link: ShareLink{ExpiresAt: now.Add(time.Hour), MaxViews: &limit, ViewCount: 3},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.link.IsUsable(now))
+		})
+	}
+}
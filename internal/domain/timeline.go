@@ -0,0 +1,160 @@
+// Package domain contains core business types and interfaces.
+//
+// This file defines the inspection activity timeline, a chronological view
+// derived from the inspection itself, its uploaded images, and the audit log.
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// =============================================================================
+// Timeline Entry Kind
+// =============================================================================
+
+// TimelineEntryKind identifies what kind of activity a timeline entry shows.
+type TimelineEntryKind string
+
+const (
+	// TimelineCreated marks when the inspection was created.
+	TimelineCreated TimelineEntryKind = "created"
+
+	// TimelineImagesUploaded marks a batch of photo uploads.
+	TimelineImagesUploaded TimelineEntryKind = "images_uploaded"
+
+	// TimelineAnalysisStarted marks the inspection entering analysis.
+	TimelineAnalysisStarted TimelineEntryKind = "analysis_started"
+
+	// TimelineAnalysisCompleted marks analysis finishing and review beginning.
+	TimelineAnalysisCompleted TimelineEntryKind = "analysis_completed"
+
+	// TimelineStatusChanged marks any other inspection status change.
+	TimelineStatusChanged TimelineEntryKind = "status_changed"
+
+	// TimelineViolationReviewed marks a violation being confirmed, rejected, or reset.
+	TimelineViolationReviewed TimelineEntryKind = "violation_reviewed"
+
+	// TimelineViolationDeleted marks a violation being deleted.
+	TimelineViolationDeleted TimelineEntryKind = "violation_deleted"
+
+	// TimelineReportRequested marks a report being queued for generation.
+	TimelineReportRequested TimelineEntryKind = "report_requested"
+
+	// TimelineReportGenerated marks a report finishing generation.
+	TimelineReportGenerated TimelineEntryKind = "report_generated"
+)
+
+// String returns the string representation of the kind.
+func (k TimelineEntryKind) String() string {
+	return string(k)
+}
+
+// =============================================================================
+// Timeline Entry Domain Type
+// =============================================================================
+
+// TimelineUploadGap is the longest pause between photo uploads that still
+// counts as one upload batch on the timeline.
+const TimelineUploadGap = 10 * time.Minute
+
+// TimelineEntry is a single item in an inspection's activity timeline.
+type TimelineEntry struct {
+	Kind       TimelineEntryKind // What happened
+	Summary    string            // Human-readable description
+	ActorName  string            // Who did it (empty when unknown)
+	OccurredAt time.Time         // When it happened
+}
+
+// BuildInspectionTimeline assembles the activity timeline for an inspection,
+// oldest first.
+//
+// imageUploads holds the upload time of each image; uploads less than
+// TimelineUploadGap apart are collapsed into one entry. events are the
+// inspection's audit events in any order. Entries with the same timestamp
+// keep their source order (creation, uploads, then audit events).
+func BuildInspectionTimeline(inspection *Inspection, imageUploads []time.Time, events []AuditEvent) []TimelineEntry {
+	entries := make([]TimelineEntry, 0, 1+len(imageUploads)+len(events))
+
+	entries = append(entries, TimelineEntry{
+		Kind:       TimelineCreated,
+		Summary:    "Inspection created",
+		OccurredAt: inspection.CreatedAt,
+	})
+
+	entries = append(entries, uploadBatches(imageUploads)...)
+
+	for i := range events {
+		entries = append(entries, timelineEntryForEvent(&events[i]))
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].OccurredAt.Before(entries[j].OccurredAt)
+	})
+
+	return entries
+}
+
+// uploadBatches groups upload times into one timeline entry per batch.
+func uploadBatches(uploads []time.Time) []TimelineEntry {
+	if len(uploads) == 0 {
+		return nil
+	}
+
+	sorted := make([]time.Time, len(uploads))
+	copy(sorted, uploads)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+
+	var batches []TimelineEntry
+	start, count := sorted[0], 1
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].Sub(sorted[i-1]) <= TimelineUploadGap {
+			count++
+			continue
+		}
+		batches = append(batches, uploadEntry(start, count))
+		start, count = sorted[i], 1
+	}
+	return append(batches, uploadEntry(start, count))
+}
+
+func uploadEntry(at time.Time, count int) TimelineEntry {
+	summary := "1 photo uploaded"
+	if count != 1 {
+		summary = fmt.Sprintf("%d photos uploaded", count)
+	}
+	return TimelineEntry{Kind: TimelineImagesUploaded, Summary: summary, OccurredAt: at}
+}
+
+// timelineEntryForEvent converts an audit event to a timeline entry.
+// Inspection status changes into and out of analyzing are shown as
+// analysis milestones rather than raw status changes.
+func timelineEntryForEvent(e *AuditEvent) TimelineEntry {
+	entry := TimelineEntry{
+		Kind:       TimelineStatusChanged,
+		Summary:    e.Summary(),
+		ActorName:  e.ActorName,
+		OccurredAt: e.CreatedAt,
+	}
+
+	switch {
+	case e.EntityType == AuditEntityInspection && e.Action == AuditActionStatusChanged:
+		switch {
+		case e.NewValues["status"] == string(InspectionStatusAnalyzing):
+			entry.Kind, entry.Summary = TimelineAnalysisStarted, "Analysis started"
+		case e.OldValues["status"] == string(InspectionStatusAnalyzing) && e.NewValues["status"] == string(InspectionStatusReview):
+			entry.Kind, entry.Summary = TimelineAnalysisCompleted, "Analysis completed"
+		}
+	case e.EntityType == AuditEntityViolation && e.Action == AuditActionStatusChanged:
+		entry.Kind = TimelineViolationReviewed
+	case e.EntityType == AuditEntityViolation && e.Action == AuditActionDeleted:
+		entry.Kind = TimelineViolationDeleted
+	case e.Action == AuditActionReportRequested:
+		entry.Kind = TimelineReportRequested
+	case e.Action == AuditActionReportGenerated:
+		entry.Kind = TimelineReportGenerated
+	}
+
+	return entry
+}
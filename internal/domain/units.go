@@ -0,0 +1,159 @@
+// Package domain contains core business types and interfaces.
+//
+// This file defines unit systems and the temperature readings recorded on
+// inspections, which are stored as text like "72°F".
+package domain
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// =============================================================================
+// Unit System
+// =============================================================================
+
+// UnitSystem is the system of measurement a user reads and enters values in.
+type UnitSystem string
+
+const (
+	UnitsImperial UnitSystem = "imperial"
+	UnitsMetric   UnitSystem = "metric"
+)
+
+// DefaultUnitSystem is used until the user picks another.
+const DefaultUnitSystem = UnitsImperial
+
+// UnitSystems lists the unit systems in the order the settings page shows
+// them.
+var UnitSystems = []UnitSystem{UnitsImperial, UnitsMetric}
+
+// IsValid returns true if the unit system is a recognized value.
+func (u UnitSystem) IsValid() bool {
+	switch u {
+	case UnitsImperial, UnitsMetric:
+		return true
+	}
+	return false
+}
+
+// Label returns a human-readable name for the unit system.
+func (u UnitSystem) Label() string {
+	switch u {
+	case UnitsImperial:
+		return "Imperial (°F)"
+	case UnitsMetric:
+		return "Metric (°C)"
+	default:
+		return string(u)
+	}
+}
+
+// TemperatureUnit returns the unit temperatures are shown in. Unrecognized
+// unit systems use the default's.
+func (u UnitSystem) TemperatureUnit() TemperatureUnit {
+	if u == UnitsMetric {
+		return Celsius
+	}
+	return Fahrenheit
+}
+
+// =============================================================================
+// Temperature
+// =============================================================================
+
+// TemperatureUnit is the scale of a temperature reading.
+type TemperatureUnit string
+
+const (
+	Fahrenheit TemperatureUnit = "F"
+	Celsius    TemperatureUnit = "C"
+)
+
+// Temperature is a parsed temperature reading.
+type Temperature struct {
+	Value float64
+	Unit  TemperatureUnit
+}
+
+// In returns the reading converted to unit.
+func (t Temperature) In(unit TemperatureUnit) Temperature {
+	switch {
+	case t.Unit == unit:
+		return t
+	case unit == Celsius:
+		return Temperature{Value: (t.Value - 32) * 5 / 9, Unit: Celsius}
+	default:
+		return Temperature{Value: t.Value*9/5 + 32, Unit: Fahrenheit}
+	}
+}
+
+// String returns the reading in the stored form, e.g. "72°F" or "21.5°C",
+// rounded to a tenth of a degree.
+func (t Temperature) String() string {
+	value := math.Round(t.Value*10) / 10
+	if value == 0 {
+		value = 0 // Avoid "-0°C"
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64) + "°" + string(t.Unit)
+}
+
+// temperaturePattern matches readings like "72", "72F", "72 °F", "-5 c",
+// "21,5 °C", and "68 degrees fahrenheit".
+var temperaturePattern = regexp.MustCompile(`(?i)^([+-]?\d+(?:[.,]\d+)?)\s*(?:°|º|deg(?:rees?)?\.?)?\s*(f|c|fahrenheit|celsius)?$`)
+
+// ParseTemperature parses a temperature as users type it. A reading without
+// a unit is taken to be in defaultUnit. Returns false for anything else,
+// such as "mild" or "70-75F".
+func ParseTemperature(s string, defaultUnit TemperatureUnit) (Temperature, bool) {
+	m := temperaturePattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return Temperature{}, false
+	}
+
+	value, err := strconv.ParseFloat(strings.Replace(m[1], ",", ".", 1), 64)
+	if err != nil {
+		return Temperature{}, false
+	}
+
+	unit := defaultUnit
+	switch strings.ToLower(m[2]) {
+	case "f", "fahrenheit":
+		unit = Fahrenheit
+	case "c", "celsius":
+		unit = Celsius
+	}
+	return Temperature{Value: value, Unit: unit}, true
+}
+
+// NormalizeTemperature rewrites a temperature typed in by a user of units
+// in the stored form, keeping the scale it was measured in. Text that isn't
+// a single reading is kept as typed, trimmed.
+func NormalizeTemperature(s string, units UnitSystem) string {
+	t, ok := ParseTemperature(s, units.TemperatureUnit())
+	if !ok {
+		return strings.TrimSpace(s)
+	}
+	return t.String()
+}
+
+// FormatTemperature returns a stored temperature in the units of the
+// reader. Readings stored without a unit are taken to be Fahrenheit, which
+// is what the weather lookup records. Converted readings are rounded to
+// whole degrees; text that isn't a single reading is returned unchanged.
+func FormatTemperature(stored string, units UnitSystem) string {
+	t, ok := ParseTemperature(stored, Fahrenheit)
+	if !ok {
+		return stored
+	}
+
+	unit := units.TemperatureUnit()
+	if t.Unit == unit {
+		return t.String()
+	}
+	converted := t.In(unit)
+	converted.Value = math.Round(converted.Value)
+	return converted.String()
+}
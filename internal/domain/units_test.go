@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTemperature(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		want   Temperature
+		wantOK bool
+	}{
+		{name: "stored form", input: "72°F", want: Temperature{Value: 72, Unit: Fahrenheit}, wantOK: true},
+		{name: "bare number uses the default unit", input: "22", want: Temperature{Value: 22, Unit: Celsius}, wantOK: true},
+		{name: "lowercase unit with space", input: "-5 c", want: Temperature{Value: -5, Unit: Celsius}, wantOK: true},
+		{name: "decimal comma", input: "21,5 °C", want: Temperature{Value: 21.5, Unit: Celsius}, wantOK: true},
+		{name: "spelled out", input: "68 degrees Fahrenheit", want: Temperature{Value: 68, Unit: Fahrenheit}, wantOK: true},
+		{name: "surrounding space", input: "  50F ", want: Temperature{Value: 50, Unit: Fahrenheit}, wantOK: true},
+		{name: "range", input: "70-75F"},
+		{name: "words", input: "mild"},
+		{name: "empty", input: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseTemperature(tt.input, Celsius)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNormalizeTemperature(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		units UnitSystem
+		want  string
+	}{
+		{name: "bare number for imperial user", input: "72", units: UnitsImperial, want: "72°F"},
+		{name: "bare number for metric user", input: "22", units: UnitsMetric, want: "22°C"},
+		{name: "keeps the unit typed", input: "22 c", units: UnitsImperial, want: "22°C"},
+		{name: "rounds to a tenth", input: "21.46C", units: UnitsMetric, want: "21.5°C"},
+		{name: "unset units are imperial", input: "60", want: "60°F"},
+		{name: "free text is kept", input: " low 70s ", units: UnitsImperial, want: "low 70s"},
+		{name: "empty", input: "", units: UnitsMetric, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NormalizeTemperature(tt.input, tt.units))
+		})
+	}
+}
+
+func TestFormatTemperature(t *testing.T) {
+	tests := []struct {
+		name   string
+		stored string
+		units  UnitSystem
+		want   string
+	}{
+		{name: "fahrenheit to metric", stored: "72°F", units: UnitsMetric, want: "22°C"},
+		{name: "celsius to imperial", stored: "22°C", units: UnitsImperial, want: "72°F"},
+		{name: "freezing", stored: "32°F", units: UnitsMetric, want: "0°C"},
+		{name: "just below freezing rounds without a sign", stored: "31.5°F", units: UnitsMetric, want: "0°C"},
+		{name: "same units unchanged", stored: "21.5°C", units: UnitsMetric, want: "21.5°C"},
+		{name: "legacy bare number is fahrenheit", stored: "50", units: UnitsMetric, want: "10°C"},
+		{name: "legacy spacing is tidied", stored: "72 F", units: UnitsImperial, want: "72°F"},
+		{name: "free text is unchanged", stored: "low 70s", units: UnitsMetric, want: "low 70s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, FormatTemperature(tt.stored, tt.units))
+		})
+	}
+}
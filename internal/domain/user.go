@@ -33,6 +33,23 @@ const (
 	SubscriptionTierProfessional SubscriptionTier = "professional"
 )
 
+// SubscriptionTiers lists the tiers in pricing order.
+var SubscriptionTiers = []SubscriptionTier{
+	SubscriptionTierFree,
+	SubscriptionTierStarter,
+	SubscriptionTierProfessional,
+}
+
+// IsValid returns true if the tier is a known subscription tier.
+func (t SubscriptionTier) IsValid() bool {
+	switch t {
+	case SubscriptionTierFree, SubscriptionTierStarter, SubscriptionTierProfessional:
+		return true
+	default:
+		return false
+	}
+}
+
 // User represents a registered user of the Lukaut platform.
 //
 // This is the domain representation of a user, designed for use in business logic.
@@ -53,6 +70,7 @@ type User struct {
 	SubscriptionID     string
 	EmailVerified      bool
 	EmailVerifiedAt    *time.Time
+	DisabledAt         *time.Time // Set when an admin disables the account
 	CreatedAt          time.Time
 	UpdatedAt          time.Time
 
@@ -67,6 +85,13 @@ type User struct {
 	BusinessPostalCode    string
 	BusinessLicenseNumber string
 	BusinessLogoURL       string
+	BusinessLogoKey       string // Storage key of the uploaded logo; takes precedence over BusinessLogoURL
+	BusinessBrandColor    string // Hex color for report headers; empty uses the default
+}
+
+// IsDisabled returns true if the account has been disabled.
+func (u *User) IsDisabled() bool {
+	return u.DisabledAt != nil
 }
 
 // IsActive returns true if the user has an active subscription or is trialing.
@@ -80,6 +105,15 @@ func (u *User) IsProfessional() bool {
 	return u.SubscriptionTier == SubscriptionTierProfessional
 }
 
+// QuotaTier returns the tier used for quota limits: the subscription tier
+// while the subscription is active or trialing, otherwise the free tier.
+func (u *User) QuotaTier() SubscriptionTier {
+	if u.IsActive() && u.SubscriptionTier != "" {
+		return u.SubscriptionTier
+	}
+	return SubscriptionTierFree
+}
+
 // CanGenerateReports returns true if the user can generate reports.
 // This checks both subscription status and tier limits.
 func (u *User) CanGenerateReports() bool {
@@ -104,16 +138,41 @@ func (u *User) HasBusinessProfile() bool {
 	return u.BusinessName != "" || u.HasBusinessAddress()
 }
 
+// HasBusinessLogo returns true if the user uploaded a logo for their reports.
+func (u *User) HasBusinessLogo() bool {
+	return u.BusinessLogoKey != ""
+}
+
+// MaxBusinessLogoSize is the maximum size of an uploaded business logo (2MB).
+const MaxBusinessLogoSize = 2 * 1024 * 1024
+
+// IsValidBrandColor reports whether color is a hex color in #RRGGBB form.
+func IsValidBrandColor(color string) bool {
+	if len(color) != 7 || color[0] != '#' {
+		return false
+	}
+	for _, c := range color[1:] {
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+			return false
+		}
+	}
+	return true
+}
+
 // Session represents an authenticated session.
 //
 // Sessions are stored in the database with a hashed token.
 // The raw token is only given to the client once (at login).
 type Session struct {
-	ID        uuid.UUID
-	UserID    uuid.UUID
-	TokenHash string // SHA-256 hash of the session token
-	ExpiresAt time.Time
-	CreatedAt time.Time
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	TokenHash  string // SHA-256 hash of the session token
+	ExpiresAt  time.Time
+	CreatedAt  time.Time
+	LastSeenAt time.Time // Most recent authenticated request
+	UserAgent  string    // User-Agent of the most recent request, if known
+	IPAddress  string    // Client IP of the most recent request, if known
+	Current    bool      // True for the session making the request
 }
 
 // IsExpired returns true if the session has expired.
@@ -121,6 +180,13 @@ func (s *Session) IsExpired() bool {
 	return time.Now().After(s.ExpiresAt)
 }
 
+// SessionClient identifies the client behind a request, recorded on its
+// session at login and on each refresh.
+type SessionClient struct {
+	UserAgent string
+	IPAddress string
+}
+
 // RegisterParams contains the validated parameters for user registration.
 type RegisterParams struct {
 	Email       string
@@ -163,6 +229,46 @@ type BusinessProfileUpdateParams struct {
 	PostalCode    string
 	LicenseNumber string
 	LogoURL       string
+	BrandColor    string // #RRGGBB, or empty for the default
+}
+
+// BusinessProfileField is a required business profile form field.
+type BusinessProfileField struct {
+	Name  string // Form field name
+	Label string // Label shown to the user
+}
+
+// MissingFields returns the required fields left empty. A profile with every
+// field empty is disabled and needs nothing; once any field is filled in, the
+// business name is required, and an address must be complete enough to print
+// on a report (street, city, state and ZIP code).
+func (p BusinessProfileUpdateParams) MissingFields() []BusinessProfileField {
+	hasAddress := p.AddressLine1 != "" || p.AddressLine2 != "" || p.City != "" || p.State != "" || p.PostalCode != ""
+	enabled := hasAddress || p.BusinessName != "" || p.BusinessEmail != "" || p.BusinessPhone != "" || p.LicenseNumber != ""
+	if !enabled {
+		return nil
+	}
+
+	var missing []BusinessProfileField
+	if p.BusinessName == "" {
+		missing = append(missing, BusinessProfileField{Name: "business_name", Label: "Business name"})
+	}
+	if hasAddress {
+		for _, f := range []struct {
+			value string
+			field BusinessProfileField
+		}{
+			{p.AddressLine1, BusinessProfileField{Name: "address_line1", Label: "Street address"}},
+			{p.City, BusinessProfileField{Name: "city", Label: "City"}},
+			{p.State, BusinessProfileField{Name: "state", Label: "State"}},
+			{p.PostalCode, BusinessProfileField{Name: "postal_code", Label: "ZIP code"}},
+		} {
+			if f.value == "" {
+				missing = append(missing, f.field)
+			}
+		}
+	}
+	return missing
 }
 
 // =============================================================================
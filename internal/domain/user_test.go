@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBusinessProfileUpdateParams_MissingFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		params BusinessProfileUpdateParams
+		want   []string
+	}{
+		{
+			name:   "empty profile stays disabled",
+			params: BusinessProfileUpdateParams{},
+			want:   nil,
+		},
+		{
+			name:   "name only",
+			params: BusinessProfileUpdateParams{BusinessName: "Acme Safety"},
+			want:   nil,
+		},
+		{
+			name:   "license without a name",
+			params: BusinessProfileUpdateParams{LicenseNumber: "CSP-12345"},
+			want:   []string{"business_name"},
+		},
+		{
+			name: "partial address",
+			params: BusinessProfileUpdateParams{
+				BusinessName: "Acme Safety",
+				City:         "Tampa",
+			},
+			want: []string{"address_line1", "state", "postal_code"},
+		},
+		{
+			name: "complete profile",
+			params: BusinessProfileUpdateParams{
+				BusinessName: "Acme Safety",
+				AddressLine1: "100 Main St",
+				City:         "Tampa",
+				State:        "FL",
+				PostalCode:   "33602",
+			},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, f := range tt.params.MissingFields() {
+				got = append(got, f.Name)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestIsValidBrandColor(t *testing.T) {
+	assert.True(t, IsValidBrandColor("#1E3A5F"))
+	assert.True(t, IsValidBrandColor("#ff6b35"))
+	assert.False(t, IsValidBrandColor(""))
+	assert.False(t, IsValidBrandColor("1E3A5F"))
+	assert.False(t, IsValidBrandColor("#1E3A5"))
+	assert.False(t, IsValidBrandColor("#1E3A5G"))
+}
@@ -5,7 +5,12 @@
 package domain
 
 import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
 	"time"
+	"unicode"
 
 	"github.com/google/uuid"
 )
@@ -130,9 +135,10 @@ type Violation struct {
 	Description    string              // Inspector-editable description
 	AIDescription  string              // Original AI-generated description
 	Confidence     ViolationConfidence // AI confidence level
-	BoundingBox    string              // Optional: JSON coordinates for image annotation
+	BoundingBox    *AnnotationBox      // Optional: region of the image showing the violation, from AI analysis
 	Status         ViolationStatus     // Review status (pending, confirmed, rejected)
 	Severity       ViolationSeverity   // Severity level
+	AISeverity     ViolationSeverity   // Original AI-assigned severity (empty for manual violations)
 	InspectorNotes string              // Optional: Additional notes from inspector
 	SortOrder      int                 // Display order in reports
 	CreatedAt      time.Time           // When violation was created
@@ -143,6 +149,30 @@ type Violation struct {
 	OriginalFilename string // Image filename (if linked to image)
 }
 
+// RegionZoomMargin is how much of the photo around a violation's bounding
+// box its close-up shows, as a fraction of the box size on each side.
+const RegionZoomMargin = 0.25
+
+// ThumbnailURL returns the path serving the image's default thumbnail.
+func ThumbnailURL(imageID uuid.UUID) string {
+	return "/images/" + imageID.String() + "/thumbnail"
+}
+
+// ThumbnailCropURL returns the path serving a thumbnail of the image cropped
+// to box.
+func ThumbnailCropURL(imageID uuid.UUID, box AnnotationBox) string {
+	return ThumbnailURL(imageID) + "?crop=" + url.QueryEscape(box.String())
+}
+
+// RegionThumbnailURL returns the path of a close-up of the violation's
+// bounding box, or thumbnailURL when the violation has no image or box.
+func (v *Violation) RegionThumbnailURL(thumbnailURL string) string {
+	if v.ImageID == nil || v.BoundingBox == nil {
+		return thumbnailURL
+	}
+	return ThumbnailCropURL(*v.ImageID, v.BoundingBox.Zoom(RegionZoomMargin))
+}
+
 // IsAIDetected returns true if this violation was detected by AI.
 func (v *Violation) IsAIDetected() bool {
 	return v.AIDescription != ""
@@ -153,11 +183,82 @@ func (v *Violation) IsManual() bool {
 	return v.AIDescription == ""
 }
 
+// SeverityOverridden returns true if the inspector changed the severity
+// the AI originally assigned.
+func (v *Violation) SeverityOverridden() bool {
+	return v.AISeverity != "" && v.AISeverity != v.Severity
+}
+
 // HasImage returns true if this violation is linked to an image.
 func (v *Violation) HasImage() bool {
 	return v.ImageID != nil
 }
 
+// =============================================================================
+// Violation Evidence Photos
+// =============================================================================
+
+// MaxViolationImages is the most photos a violation can have, counting its
+// primary image.
+const MaxViolationImages = 5
+
+// ViolationImage is an evidence photo attached to a violation in addition to
+// its primary image, such as a wide shot to go with a close-up.
+type ViolationImage struct {
+	ImageID          uuid.UUID // The attached image
+	Position         int       // Order among the violation's additional photos
+	ThumbnailKey     string    // Image thumbnail key
+	OriginalFilename string    // Image filename
+}
+
+// maxSuggestionTerms caps the keywords used to suggest regulations.
+const maxSuggestionTerms = 12
+
+// suggestionStopWords are common words that say nothing about which
+// regulation applies.
+var suggestionStopWords = map[string]bool{
+	"about": true, "above": true, "after": true, "all": true, "along": true,
+	"also": true, "and": true, "any": true, "are": true, "area": true,
+	"been": true, "being": true, "but": true, "can": true, "could": true,
+	"does": true, "for": true, "from": true, "has": true, "have": true,
+	"into": true, "its": true, "may": true, "near": true, "not": true,
+	"observed": true, "off": true, "one": true, "other": true, "over": true,
+	"should": true, "site": true, "some": true, "than": true, "that": true,
+	"the": true, "their": true, "there": true, "these": true, "this": true,
+	"those": true, "through": true, "under": true, "visible": true, "was": true,
+	"were": true, "which": true, "while": true, "with": true, "without": true,
+	"worker": true, "workers": true,
+}
+
+// RegulationSuggestionQuery returns a full-text query for regulations that
+// may apply to the violation, built from keywords of the AI description (or
+// the inspector's description for manual violations). Keywords are joined
+// with "or" so regulations matching more of them rank higher. Returns an
+// empty string if the description has no usable keywords.
+func (v *Violation) RegulationSuggestionQuery() string {
+	text := v.AIDescription
+	if text == "" {
+		text = v.Description
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var terms []string
+	for _, word := range words {
+		if len(word) < 3 || suggestionStopWords[word] || slices.Contains(terms, word) {
+			continue
+		}
+		terms = append(terms, word)
+		if len(terms) == maxSuggestionTerms {
+			break
+		}
+	}
+
+	return strings.Join(terms, " or ")
+}
+
 // =============================================================================
 // Violation Regulation Link
 // =============================================================================
@@ -206,6 +307,111 @@ func CalculateViolationCounts(violations []Violation) ViolationCounts {
 	return counts
 }
 
+// =============================================================================
+// Violation Filtering
+// =============================================================================
+
+// ViolationSort identifies an ordering for a filtered list of violations.
+type ViolationSort string
+
+const (
+	// ViolationSortDefault keeps the stored sort order.
+	ViolationSortDefault ViolationSort = ""
+
+	// ViolationSortSeverityDesc orders the most severe violations first.
+	ViolationSortSeverityDesc ViolationSort = "severity_desc"
+
+	// ViolationSortConfidenceAsc orders the least confident detections first.
+	ViolationSortConfidenceAsc ViolationSort = "confidence_asc"
+)
+
+// IsValid returns true if the sort is a recognized value.
+func (s ViolationSort) IsValid() bool {
+	switch s {
+	case ViolationSortDefault, ViolationSortSeverityDesc, ViolationSortConfidenceAsc:
+		return true
+	}
+	return false
+}
+
+// Rank returns the ordering weight of a severity, higher being more severe.
+// Unknown or empty severities rank lowest.
+func (s ViolationSeverity) Rank() int {
+	switch s {
+	case ViolationSeverityCritical:
+		return 4
+	case ViolationSeveritySerious:
+		return 3
+	case ViolationSeverityOther:
+		return 2
+	case ViolationSeverityRecommendation:
+		return 1
+	}
+	return 0
+}
+
+// Rank returns the ordering weight of a confidence, higher being more confident.
+// Manual violations have no confidence and rank lowest.
+func (c ViolationConfidence) Rank() int {
+	switch c {
+	case ViolationConfidenceHigh:
+		return 3
+	case ViolationConfidenceMedium:
+		return 2
+	case ViolationConfidenceLow:
+		return 1
+	}
+	return 0
+}
+
+// ViolationFilter narrows and orders a list of violations.
+// An empty filter matches every violation and keeps the stored order.
+type ViolationFilter struct {
+	Severities  []ViolationSeverity   // Match any of these severities (empty = all)
+	Confidences []ViolationConfidence // Match any of these confidences (empty = all)
+	Sort        ViolationSort         // Ordering applied after filtering
+}
+
+// IsEmpty returns true if the filter neither narrows nor reorders violations.
+func (f ViolationFilter) IsEmpty() bool {
+	return len(f.Severities) == 0 && len(f.Confidences) == 0 && f.Sort == ViolationSortDefault
+}
+
+// Matches returns true if the violation satisfies the filter's criteria.
+func (f ViolationFilter) Matches(v Violation) bool {
+	if len(f.Severities) > 0 && !slices.Contains(f.Severities, v.Severity) {
+		return false
+	}
+	if len(f.Confidences) > 0 && !slices.Contains(f.Confidences, v.Confidence) {
+		return false
+	}
+	return true
+}
+
+// Apply returns the violations matching the filter in the requested order.
+// The input slice is not modified. Ties keep their original relative order.
+func (f ViolationFilter) Apply(violations []Violation) []Violation {
+	result := make([]Violation, 0, len(violations))
+	for _, v := range violations {
+		if f.Matches(v) {
+			result = append(result, v)
+		}
+	}
+
+	switch f.Sort {
+	case ViolationSortSeverityDesc:
+		slices.SortStableFunc(result, func(a, b Violation) int {
+			return b.Severity.Rank() - a.Severity.Rank()
+		})
+	case ViolationSortConfidenceAsc:
+		slices.SortStableFunc(result, func(a, b Violation) int {
+			return a.Confidence.Rank() - b.Confidence.Rank()
+		})
+	}
+
+	return result
+}
+
 // =============================================================================
 // Violation Service Parameters
 // =============================================================================
@@ -218,6 +424,7 @@ type CreateViolationParams struct {
 	Description    string            // Required: Violation description
 	Severity       ViolationSeverity // Required: Severity level
 	InspectorNotes string            // Optional: Additional notes
+	RegulationIDs  []uuid.UUID       // Optional: Regulations to link; the first is primary
 }
 
 // UpdateViolationParams contains validated parameters for updating a violation.
@@ -235,3 +442,111 @@ type UpdateViolationStatusParams struct {
 	UserID uuid.UUID       // User updating (for authorization)
 	Status ViolationStatus // New status
 }
+
+// BulkUpdateViolationStatusParams contains parameters for updating the status
+// of several violations of one inspection at once.
+type BulkUpdateViolationStatusParams struct {
+	InspectionID uuid.UUID       // Inspection the violations belong to
+	IDs          []uuid.UUID     // Violations to update
+	UserID       uuid.UUID       // User updating (for authorization)
+	Status       ViolationStatus // New status
+}
+
+// =============================================================================
+// Violation Search
+// =============================================================================
+
+// MaxViolationSearchLength is the longest search text accepted.
+const MaxViolationSearchLength = 200
+
+// SearchViolationsParams narrows a search across every violation of a user's
+// inspections. Zero values match everything.
+type SearchViolationsParams struct {
+	UserID         uuid.UUID         // User whose inspections are searched
+	Query          string            // Matched against description, AI description and inspector notes
+	Severity       ViolationSeverity // Optional: only this severity
+	Status         ViolationStatus   // Optional: only this review status
+	From           *time.Time        // Optional: earliest inspection date, inclusive
+	To             *time.Time        // Optional: latest inspection date, inclusive
+	StandardNumber string            // Optional: regulation standard number prefix, e.g. "1926.50"
+	Limit          int32             // Results per page
+	Offset         int32             // Results skipped
+}
+
+// IsFiltered returns true if any search text or filter is set.
+func (p SearchViolationsParams) IsFiltered() bool {
+	return p.Query != "" || p.Severity != "" || p.Status != "" ||
+		p.From != nil || p.To != nil || p.StandardNumber != ""
+}
+
+// Validate returns EINVALID if a filter is not a recognized value or the
+// date range ends before it starts.
+func (p SearchViolationsParams) Validate() error {
+	const op = "violation.validate_search"
+
+	if len(p.Query) > MaxViolationSearchLength {
+		return Invalid(op, fmt.Sprintf("search must be %d characters or fewer", MaxViolationSearchLength))
+	}
+	if p.Severity != "" && !p.Severity.IsValid() {
+		return Invalid(op, fmt.Sprintf("%q is not a severity", p.Severity))
+	}
+	if p.Status != "" && !p.Status.IsValid() {
+		return Invalid(op, fmt.Sprintf("%q is not a review status", p.Status))
+	}
+	if p.From != nil && p.To != nil && p.To.Before(*p.From) {
+		return Invalid(op, "the end date must not be before the start date")
+	}
+	return nil
+}
+
+// ViolationSearchResult is a violation found by a search, with the
+// inspection it belongs to.
+type ViolationSearchResult struct {
+	Violation       Violation
+	InspectionTitle string
+	InspectionDate  time.Time
+	StandardNumber  string // Primary regulation's standard number; empty when none is linked
+}
+
+// ReviewURL returns the path of the review queue opened at the violation.
+func (r *ViolationSearchResult) ReviewURL() string {
+	return "/inspections/" + r.Violation.InspectionID.String() + "/review/queue?violation=" + r.Violation.ID.String()
+}
+
+// SearchViolationsResult contains a page of violation search results.
+type SearchViolationsResult struct {
+	Results []ViolationSearchResult // The violations on this page
+	Total   int64                   // Total number of matching violations (for pagination)
+	Limit   int32                   // Number of results requested
+	Offset  int32                   // Number of results skipped
+}
+
+// HasMore returns true if there are more results available.
+func (r *SearchViolationsResult) HasMore() bool {
+	return int64(r.Offset+r.Limit) < r.Total
+}
+
+// HasPrevious returns true if there are previous results available.
+func (r *SearchViolationsResult) HasPrevious() bool {
+	return r.Offset > 0
+}
+
+// CurrentPage returns the current page number (1-indexed).
+func (r *SearchViolationsResult) CurrentPage() int {
+	if r.Limit == 0 {
+		return 1
+	}
+	return int(r.Offset/r.Limit) + 1
+}
+
+// TotalPages returns the total number of pages.
+func (r *SearchViolationsResult) TotalPages() int {
+	if r.Limit == 0 {
+		return 1
+	}
+	pages := r.Total / int64(r.Limit)
+	if r.Total%int64(r.Limit) > 0 {
+		pages++
+	}
+	return int(pages)
+}
@@ -70,3 +70,127 @@ func TestCalculateViolationCounts(t *testing.T) {
 		})
 	}
 }
+
+func TestViolationFilter_Apply(t *testing.T) {
+	critical := Violation{ID: uuid.New(), Severity: ViolationSeverityCritical, Confidence: ViolationConfidenceMedium}
+	serious := Violation{ID: uuid.New(), Severity: ViolationSeveritySerious, Confidence: ViolationConfidenceHigh}
+	other := Violation{ID: uuid.New(), Severity: ViolationSeverityOther, Confidence: ViolationConfidenceLow}
+	manual := Violation{ID: uuid.New(), Severity: ViolationSeverityCritical}
+	all := []Violation{other, serious, critical, manual}
+
+	tests := []struct {
+		name   string
+		filter ViolationFilter
+		want   []Violation
+	}{
+		{
+			name:   "empty filter keeps everything in order",
+			filter: ViolationFilter{},
+			want:   all,
+		},
+		{
+			name:   "single severity",
+			filter: ViolationFilter{Severities: []ViolationSeverity{ViolationSeverityCritical}},
+			want:   []Violation{critical, manual},
+		},
+		{
+			name:   "multiple confidences",
+			filter: ViolationFilter{Confidences: []ViolationConfidence{ViolationConfidenceHigh, ViolationConfidenceLow}},
+			want:   []Violation{other, serious},
+		},
+		{
+			name: "severity and confidence combine",
+			filter: ViolationFilter{
+				Severities:  []ViolationSeverity{ViolationSeverityCritical},
+				Confidences: []ViolationConfidence{ViolationConfidenceMedium},
+			},
+			want: []Violation{critical},
+		},
+		{
+			name:   "severity descending is stable",
+			filter: ViolationFilter{Sort: ViolationSortSeverityDesc},
+			want:   []Violation{critical, manual, serious, other},
+		},
+		{
+			name:   "confidence ascending puts manual first",
+			filter: ViolationFilter{Sort: ViolationSortConfidenceAsc},
+			want:   []Violation{manual, other, critical, serious},
+		},
+		{
+			name:   "no matches",
+			filter: ViolationFilter{Severities: []ViolationSeverity{ViolationSeverityRecommendation}},
+			want:   []Violation{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.filter.Apply(all)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestViolationFilter_ApplyDoesNotModifyInput(t *testing.T) {
+	low := Violation{ID: uuid.New(), Severity: ViolationSeverityOther}
+	high := Violation{ID: uuid.New(), Severity: ViolationSeverityCritical}
+	input := []Violation{low, high}
+
+	_ = ViolationFilter{Sort: ViolationSortSeverityDesc}.Apply(input)
+
+	assert.Equal(t, []Violation{low, high}, input)
+}
+
+func TestViolation_RegulationSuggestionQuery(t *testing.T) {
+	tests := []struct {
+		name      string
+		violation Violation
+		want      string
+	}{
+		{
+			name: "keywords from AI description",
+			violation: Violation{
+				Description:   "Edited by inspector",
+				AIDescription: "Worker on the roof edge without guardrail; no fall protection observed.",
+			},
+			want: "roof or edge or guardrail or fall or protection",
+		},
+		{
+			name:      "manual violation uses description",
+			violation: Violation{Description: "Ladder extends less than 3 feet above the landing"},
+			want:      "ladder or extends or less or feet or landing",
+		},
+		{
+			name:      "repeated words appear once",
+			violation: Violation{AIDescription: "Scaffold plank missing; scaffold unstable"},
+			want:      "scaffold or plank or missing or unstable",
+		},
+		{
+			name:      "no usable keywords",
+			violation: Violation{AIDescription: "It is on the"},
+			want:      "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.violation.RegulationSuggestionQuery())
+		})
+	}
+}
+
+func TestViolation_RegionThumbnailURL(t *testing.T) {
+	imageID := uuid.MustParse("5f0b6a1e-8a43-4d57-9d0e-2f4b1c9a7e10")
+	box := &AnnotationBox{X: 0.4, Y: 0.4, Width: 0.2, Height: 0.2}
+
+	v := Violation{ImageID: &imageID, BoundingBox: box}
+	assert.Equal(t,
+		"/images/5f0b6a1e-8a43-4d57-9d0e-2f4b1c9a7e10/thumbnail?crop=0.3500%2C0.3500%2C0.3000%2C0.3000",
+		v.RegionThumbnailURL("https://cdn.example.com/thumb.jpg"))
+
+	noBox := Violation{ImageID: &imageID}
+	assert.Equal(t, "https://cdn.example.com/thumb.jpg", noBox.RegionThumbnailURL("https://cdn.example.com/thumb.jpg"))
+
+	noImage := Violation{BoundingBox: box}
+	assert.Equal(t, "", noImage.RegionThumbnailURL(""))
+}
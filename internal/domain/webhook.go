@@ -0,0 +1,45 @@
+// Package domain contains core business types and interfaces.
+//
+// This file defines outbound webhooks: URLs a user registers to be notified
+// when an inspection's analysis completes or a report is ready.
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxWebhookEndpoints caps the endpoints a user can register.
+const MaxWebhookEndpoints = 5
+
+// WebhookDeliveryStatus is the state of a single webhook delivery.
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"   // Not yet sent, or being retried
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered" // Endpoint answered 2xx
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"    // Out of retries
+)
+
+// WebhookEndpoint is a URL that receives the user's webhook events.
+type WebhookEndpoint struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	URL       string
+	Secret    string // Signing secret shared with the receiver
+	CreatedAt time.Time
+}
+
+// WebhookDelivery is one event sent, or to be sent, to an endpoint.
+type WebhookDelivery struct {
+	ID             uuid.UUID
+	EndpointURL    string
+	Event          string
+	Status         WebhookDeliveryStatus
+	Attempts       int
+	ResponseStatus *int   // Status of the last response; nil if none was received
+	ErrorMessage   string // Why the last attempt failed
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time
+}
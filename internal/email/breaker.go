@@ -0,0 +1,75 @@
+package email
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// Circuit Breaker
+// =============================================================================
+
+// ErrCircuitOpen is returned without contacting the server while the circuit
+// breaker is open. It is retryable: the worker tries again after backing off,
+// by which time the breaker lets a trial send through.
+var ErrCircuitOpen = errors.New("email server unavailable: circuit breaker open")
+
+// circuitBreaker stops sending to a server that keeps failing.
+//
+// After threshold consecutive transient failures the breaker opens and
+// rejects sends for cooldown. Once the cooldown has passed it lets sends
+// through again; one more failure reopens it immediately, and a success
+// closes it. A threshold of zero or less disables the breaker.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time // overridden in tests
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// allow reports whether a send may be attempted.
+func (b *circuitBreaker) allow() bool {
+	if b.threshold <= 0 {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return !b.now().Before(b.openUntil)
+}
+
+// success records that the server accepted or definitively rejected a
+// message, which shows it is up, and closes the breaker.
+func (b *circuitBreaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.openUntil = time.Time{}
+}
+
+// failure records a transient failure and reports whether it opened the
+// breaker.
+func (b *circuitBreaker) failure() bool {
+	if b.threshold <= 0 {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	if b.failures < b.threshold {
+		return false
+	}
+	b.openUntil = b.now().Add(b.cooldown)
+	return true
+}
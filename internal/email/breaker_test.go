@@ -0,0 +1,56 @@
+package email
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	b := newCircuitBreaker(3, time.Minute)
+	b.now = func() time.Time { return now }
+
+	for i := 1; i < 3; i++ {
+		if b.failure() {
+			t.Fatalf("expected failure %d not to open the breaker", i)
+		}
+		if !b.allow() {
+			t.Fatalf("expected sends to be allowed after %d failures", i)
+		}
+	}
+	if !b.failure() {
+		t.Fatal("expected the third failure to open the breaker")
+	}
+	if b.allow() {
+		t.Fatal("expected sends to be rejected while open")
+	}
+
+	now = now.Add(time.Minute)
+	if !b.allow() {
+		t.Fatal("expected a trial send after the cooldown")
+	}
+	if !b.failure() {
+		t.Fatal("expected a failed trial to reopen the breaker")
+	}
+	if b.allow() {
+		t.Fatal("expected sends to be rejected after the failed trial")
+	}
+
+	now = now.Add(time.Minute)
+	b.success()
+	if b.failure() {
+		t.Fatal("expected a success to reset the failure count")
+	}
+}
+
+func TestCircuitBreaker_Disabled(t *testing.T) {
+	b := newCircuitBreaker(-1, time.Minute)
+	for i := 0; i < 10; i++ {
+		if b.failure() {
+			t.Fatal("expected a disabled breaker never to open")
+		}
+	}
+	if !b.allow() {
+		t.Fatal("expected a disabled breaker to allow sends")
+	}
+}
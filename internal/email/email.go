@@ -2,11 +2,21 @@
 //
 // This package defines an EmailService interface with implementations for:
 // - SMTP (for development with Mailhog and production with services like Postmark SMTP)
-// - Future: Postmark API implementation for advanced features
+// - Postmark HTTP API (for hosts that block outbound SMTP ports)
+// - Log only (for local work without a mail server)
+// - Files (for local work without a mail server; writes .eml files)
+//
+// All implementations render the same templates from web/templates/email.
+// Use New to construct the implementation selected by Config.Provider.
 package email
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
+	"net/mail"
+	"strings"
+	"time"
 )
 
 // =============================================================================
@@ -17,9 +27,12 @@ import (
 //
 // Implementations:
 // - SMTPEmailService: Uses SMTP protocol (Mailhog for dev, Postmark SMTP for prod)
-// - Future: PostmarkAPIService for API-based sending with delivery tracking
+// - PostmarkEmailService: Uses the Postmark HTTP API
+// - LogEmailService: Writes rendered emails to the logger instead of sending
+// - FileEmailService: Writes rendered emails to .eml files instead of sending
 //
 // All methods are context-aware for timeout and cancellation support.
+// Delivery errors can be classified with IsRetryable.
 type EmailService interface {
 	// SendVerificationEmail sends an email verification link to a new user.
 	// Parameters:
@@ -28,6 +41,14 @@ type EmailService interface {
 	// - token: Raw verification token to include in the link
 	SendVerificationEmail(ctx context.Context, to, name, token string) error
 
+	// SendVerificationReminderEmail reminds a user who has not verified their
+	// email address, with a fresh verification link.
+	// Parameters:
+	// - to: Recipient email address
+	// - name: Recipient's name for personalization
+	// - token: Raw verification token to include in the link
+	SendVerificationReminderEmail(ctx context.Context, to, name, token string) error
+
 	// SendPasswordResetEmail sends a password reset link to a user.
 	// Parameters:
 	// - to: Recipient email address
@@ -49,7 +70,36 @@ type EmailService interface {
 	// - inspectorCompany: Company name of the inspector
 	// - siteName: Name of the inspection site
 	// - reportURL: URL where the report can be downloaded
-	SendReportToClientEmail(ctx context.Context, to, inspectorName, inspectorCompany, siteName, reportURL string) error
+	// - bcc: Blind copy address, typically the inspector (empty for none)
+	// - replyTo: Address client replies go to, typically the inspector's business email (empty for none)
+	// The email is sent from the configured address but shows the inspector's
+	// company, or name, as the sender.
+	SendReportToClientEmail(ctx context.Context, to, inspectorName, inspectorCompany, siteName, reportURL, bcc, replyTo string) error
+
+	// SendReportFailedEmail tells a user that their report could not be generated.
+	// Parameters:
+	// - to: Recipient email address
+	// - name: Recipient's name for personalization
+	// - inspectionURL: URL of the inspection page, where generation can be retried
+	SendReportFailedEmail(ctx context.Context, to, name, inspectionURL string) error
+
+	// SendReportDelayedEmail apologizes to a client whose report could not be sent.
+	// It deliberately gives no details about the failure.
+	// Parameters:
+	// - to: Recipient email address (client)
+	// - inspectorName: Name of the inspector who conducted the inspection
+	// - inspectorCompany: Company name of the inspector
+	// - replyTo: Address client replies go to (empty for none)
+	// Like SendReportToClientEmail, it shows the inspector as the sender.
+	SendReportDelayedEmail(ctx context.Context, to, inspectorName, inspectorCompany, replyTo string) error
+
+	// SendOrganizationInviteEmail invites someone to join an organization.
+	// Parameters:
+	// - to: Recipient email address
+	// - inviterName: Name of the organization owner who sent the invitation
+	// - organizationName: Name of the organization
+	// - token: Raw invitation token to include in the link
+	SendOrganizationInviteEmail(ctx context.Context, to, inviterName, organizationName, token string) error
 }
 
 // =============================================================================
@@ -57,8 +107,14 @@ type EmailService interface {
 // =============================================================================
 
 // Email represents a single email message.
+//
+// FromName and ReplyTo only change the message headers. Every provider still
+// sends from its configured address, so SPF and DKIM keep passing.
 type Email struct {
 	To       string // Recipient email address
+	Bcc      string // Blind copy address (optional)
+	FromName string // Sender display name (optional; defaults to the provider's FromName)
+	ReplyTo  string // Reply-To address (optional)
 	Subject  string // Email subject line
 	HTMLBody string // HTML content of the email
 	TextBody string // Plain text fallback content
@@ -70,14 +126,118 @@ type Email struct {
 
 // SMTPConfig holds SMTP server configuration.
 type SMTPConfig struct {
-	Host     string // SMTP server hostname (e.g., "localhost" for Mailhog)
-	Port     int    // SMTP server port (e.g., 1025 for Mailhog)
-	Username string // SMTP authentication username (empty for Mailhog)
-	Password string // SMTP authentication password (empty for Mailhog)
+	Host     string        // SMTP server hostname (e.g., "localhost" for Mailhog)
+	Port     int           // SMTP server port (e.g., 1025 for Mailhog)
+	Username string        // SMTP authentication username (empty for Mailhog)
+	Password string        // SMTP authentication password (empty for Mailhog)
+	From     string        // Default sender email address
+	FromName string        // Default sender display name
+	Timeout  time.Duration // Maximum time for one send attempt (default DefaultSendTimeout)
+
+	// Retries and circuit breaking for transient failures (connection
+	// errors and 4xx replies). Zero values use the defaults below.
+	DialTimeout      time.Duration // Maximum time to connect (default DefaultSMTPDialTimeout)
+	MaxAttempts      int           // Attempts per send, including the first (default DefaultSMTPMaxAttempts)
+	RetryBaseDelay   time.Duration // Base delay for exponential backoff between attempts (default DefaultSMTPRetryBaseDelay)
+	BreakerThreshold int           // Consecutive failed attempts that open the breaker (default DefaultSMTPBreakerThreshold; negative disables)
+	BreakerCooldown  time.Duration // How long the open breaker rejects sends (default DefaultSMTPBreakerCooldown)
+}
+
+// PostmarkConfig holds Postmark HTTP API configuration.
+type PostmarkConfig struct {
+	ServerToken   string        // Postmark server API token
+	MessageStream string        // Message stream ID (default "outbound")
+	APIURL        string        // API base URL (default DefaultPostmarkAPIURL; overridden in tests)
+	From          string        // Default sender email address
+	FromName      string        // Default sender display name
+	Timeout       time.Duration // Maximum time for one send (default DefaultSendTimeout)
+}
+
+// FileConfig holds configuration for writing emails to files.
+type FileConfig struct {
+	Dir      string // Directory the .eml files are written to (default DefaultFileEmailDir)
 	From     string // Default sender email address
 	FromName string // Default sender display name
 }
 
+// Provider names accepted by Config.Provider.
+const (
+	ProviderSMTP     = "smtp"
+	ProviderPostmark = "postmark"
+	ProviderLog      = "log"
+	ProviderFile     = "file"
+)
+
+// Config selects and configures an email provider for New.
+type Config struct {
+	Provider     string         // ProviderSMTP, ProviderPostmark, ProviderLog, or ProviderFile
+	BaseURL      string         // Application base URL for links in emails
+	TemplatesDir string         // Path to email templates (e.g., "web/templates/email")
+	SMTP         SMTPConfig     // Used when Provider is ProviderSMTP
+	Postmark     PostmarkConfig // Used when Provider is ProviderPostmark
+	File         FileConfig     // Used when Provider is ProviderFile
+}
+
+// New creates the EmailService for cfg.Provider.
+func New(cfg Config, logger *slog.Logger) (EmailService, error) {
+	switch cfg.Provider {
+	case ProviderSMTP, "":
+		return NewSMTPEmailService(cfg.SMTP, cfg.BaseURL, cfg.TemplatesDir, logger)
+	case ProviderPostmark:
+		return NewPostmarkEmailService(cfg.Postmark, cfg.BaseURL, cfg.TemplatesDir, logger)
+	case ProviderLog:
+		return NewLogEmailService(cfg.BaseURL, cfg.TemplatesDir, logger)
+	case ProviderFile:
+		return NewFileEmailService(cfg.File, cfg.BaseURL, cfg.TemplatesDir, logger)
+	default:
+		return nil, fmt.Errorf("unknown email provider: %q", cfg.Provider)
+	}
+}
+
+// fromAddress formats the From header for a message sent from address. The
+// message's FromName, when set, replaces the provider's default display name.
+// Names are quoted or encoded as needed, so they cannot inject headers.
+func fromAddress(email Email, defaultName, address string) string {
+	name := defaultName
+	if email.FromName != "" {
+		name = email.FromName
+	}
+	if isPlainPhrase(name) {
+		return fmt.Sprintf("%s <%s>", name, address)
+	}
+	return (&mail.Address{Name: name, Address: address}).String()
+}
+
+// isPlainPhrase reports whether name can appear in a header unquoted: it is
+// made only of RFC 5322 atext characters and single spaces.
+func isPlainPhrase(name string) bool {
+	if name == "" || strings.TrimSpace(name) != name || strings.Contains(name, "  ") {
+		return false
+	}
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == ' ':
+		case strings.ContainsRune("!#$%&'*+-/=?^_`{|}~", r):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// replyToAddress returns the message's Reply-To header value, or "" if it has
+// none or the address is malformed.
+func replyToAddress(email Email) string {
+	if email.ReplyTo == "" {
+		return ""
+	}
+	addr, err := mail.ParseAddress(email.ReplyTo)
+	if err != nil {
+		return ""
+	}
+	return addr.String()
+}
+
 // BaseURL is used for constructing links in emails.
 // This should be set to the application's public URL.
 type BaseURL string
@@ -92,4 +252,37 @@ const (
 
 	// DefaultFromName is the default sender display name.
 	DefaultFromName = "Lukaut"
+
+	// DefaultSendTimeout bounds a single send when the provider config
+	// does not set a timeout.
+	DefaultSendTimeout = 10 * time.Second
+
+	// DefaultSMTPDialTimeout bounds connecting to the SMTP server.
+	DefaultSMTPDialTimeout = 5 * time.Second
+
+	// DefaultSMTPMaxAttempts is how many times a send is attempted before
+	// the failure is returned to the caller.
+	DefaultSMTPMaxAttempts = 3
+
+	// DefaultSMTPRetryBaseDelay is the delay before the first retry; each
+	// later retry waits twice as long.
+	DefaultSMTPRetryBaseDelay = 500 * time.Millisecond
+
+	// DefaultSMTPBreakerThreshold is how many consecutive failed attempts
+	// open the circuit breaker.
+	DefaultSMTPBreakerThreshold = 5
+
+	// DefaultSMTPBreakerCooldown is how long the open circuit breaker
+	// rejects sends before trying the server again.
+	DefaultSMTPBreakerCooldown = 30 * time.Second
+
+	// DefaultFileEmailDir is where FileEmailService writes emails when the
+	// config does not name a directory.
+	DefaultFileEmailDir = "tmp/emails"
+
+	// DefaultPostmarkAPIURL is the Postmark API base URL.
+	DefaultPostmarkAPIURL = "https://api.postmarkapp.com"
+
+	// DefaultPostmarkMessageStream is Postmark's default transactional stream.
+	DefaultPostmarkMessageStream = "outbound"
 )
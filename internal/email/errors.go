@@ -0,0 +1,55 @@
+package email
+
+import "errors"
+
+// =============================================================================
+// Error Classification
+// =============================================================================
+
+// SendError wraps a delivery failure with whether retrying could succeed.
+//
+// Providers classify their failures so callers (e.g. the background worker)
+// can retry outages and rate limits but give up on rejected recipients,
+// bad credentials, or malformed messages.
+type SendError struct {
+	Err       error
+	Retryable bool
+}
+
+func (e *SendError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *SendError) Unwrap() error {
+	return e.Err
+}
+
+// Retryable marks err as a transient failure that may succeed if retried.
+func Retryable(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &SendError{Err: err, Retryable: true}
+}
+
+// Permanent marks err as a failure that will not succeed if retried.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &SendError{Err: err, Retryable: false}
+}
+
+// IsRetryable reports whether err may succeed if the send is retried.
+// Unclassified errors are treated as retryable, since most of them are
+// network failures.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	var sendErr *SendError
+	if errors.As(err, &sendErr) {
+		return sendErr.Retryable
+	}
+	return true
+}
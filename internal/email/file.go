@@ -0,0 +1,135 @@
+package email
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// =============================================================================
+// File Email Service Implementation
+// =============================================================================
+
+// FileEmailService writes each email as an .eml file instead of sending it.
+// Use it for local work without a mail server: the files open in any mail
+// client, and links back to the app (such as verification and password
+// reset URLs) are also logged so they can be clicked from the terminal.
+type FileEmailService struct {
+	renderingService
+	config  FileConfig
+	baseURL string
+	seq     atomic.Uint64
+	logger  *slog.Logger
+}
+
+// NewFileEmailService creates an email service that writes messages to
+// config.Dir, creating the directory if needed.
+//
+// Parameters:
+// - config: Output directory and sender
+// - baseURL: Application base URL for constructing links
+// - templatesDir: Path to email templates directory (e.g., "web/templates/email")
+// - logger: Logger that receives the file paths and links
+func NewFileEmailService(config FileConfig, baseURL, templatesDir string, logger *slog.Logger) (*FileEmailService, error) {
+	// Set defaults
+	if config.Dir == "" {
+		config.Dir = DefaultFileEmailDir
+	}
+	if config.From == "" {
+		config.From = DefaultFromEmail
+	}
+	if config.FromName == "" {
+		config.FromName = DefaultFromName
+	}
+
+	if err := os.MkdirAll(config.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create email directory: %w", err)
+	}
+
+	renderer, err := newRenderer(baseURL, templatesDir, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &FileEmailService{
+		config:  config,
+		baseURL: renderer.baseURL,
+		logger:  logger,
+	}
+	s.renderingService = renderingService{renderer: renderer, sender: s}
+	return s, nil
+}
+
+// send writes the email to a new .eml file in the output directory.
+func (s *FileEmailService) send(ctx context.Context, email Email) error {
+	msg, err := formatMessage(email, s.config.FromName, s.config.From)
+	if err != nil {
+		return Permanent(fmt.Errorf("failed to build email: %w", err))
+	}
+
+	path := filepath.Join(s.config.Dir, s.fileName(email.To))
+	if err := os.WriteFile(path, msg, 0o644); err != nil {
+		return fmt.Errorf("failed to write email: %w", err)
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+
+	s.logger.Info("email written to file (not sent)",
+		"to", email.To,
+		"bcc", email.Bcc,
+		"subject", email.Subject,
+		"path", path,
+	)
+	for _, link := range appLinks(email.TextBody, s.baseURL) {
+		s.logger.Info("email link", "to", email.To, "url", link)
+	}
+	return nil
+}
+
+// fileName returns a unique name for a message to recipient. Names sort in
+// the order the messages were written.
+func (s *FileEmailService) fileName(recipient string) string {
+	return fmt.Sprintf("%s-%04d-%s.eml",
+		time.Now().UTC().Format("20060102T150405.000"),
+		s.seq.Add(1)%10000,
+		sanitizeFileName(recipient),
+	)
+}
+
+// sanitizeFileName keeps the characters of s that are safe in a file name.
+func sanitizeFileName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_', r == '@':
+			return r
+		default:
+			return '_'
+		}
+	}, s)
+}
+
+// appLinks returns the URLs in body that point at the application.
+func appLinks(body, baseURL string) []string {
+	if baseURL == "" {
+		return nil
+	}
+	var links []string
+	for _, field := range strings.Fields(body) {
+		if strings.HasPrefix(field, baseURL) {
+			links = append(links, strings.TrimRight(field, ".,;:)>\""))
+		}
+	}
+	return links
+}
+
+// =============================================================================
+// Compile-time interface check
+// =============================================================================
+
+var _ EmailService = (*FileEmailService)(nil)
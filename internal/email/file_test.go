@@ -0,0 +1,116 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestFileService(t *testing.T, logs *bytes.Buffer) (*FileEmailService, string) {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "emails")
+	logger := newTestLogger()
+	if logs != nil {
+		logger = slog.New(slog.NewTextHandler(logs, nil))
+	}
+	svc, err := NewFileEmailService(FileConfig{
+		Dir:      dir,
+		From:     "noreply@example.com",
+		FromName: "Lukaut",
+	}, "https://app.example.com", testTemplatesDir, logger)
+	if err != nil {
+		t.Fatalf("failed to create file service: %v", err)
+	}
+	return svc, dir
+}
+
+// readEmails returns the contents of the .eml files in dir, in name order.
+func readEmails(t *testing.T, dir string) []string {
+	t.Helper()
+	paths, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if err != nil {
+		t.Fatalf("failed to list emails: %v", err)
+	}
+	var emails []string
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", path, err)
+		}
+		emails = append(emails, string(data))
+	}
+	return emails
+}
+
+// =============================================================================
+// File Tests
+// =============================================================================
+
+func TestFile_WritesMessage(t *testing.T) {
+	svc, dir := newTestFileService(t, nil)
+
+	if err := svc.SendPasswordResetEmail(context.Background(), "pat@example.com", "Pat", "tok_123"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	emails := readEmails(t, dir)
+	if len(emails) != 1 {
+		t.Fatalf("expected 1 email file, got %d", len(emails))
+	}
+	h := headers(t, emails[0])
+	if to := h.Get("To"); to != "pat@example.com" {
+		t.Errorf("expected recipient pat@example.com, got %q", to)
+	}
+	if subject := h.Get("Subject"); subject != "Reset your Lukaut password" {
+		t.Errorf("unexpected subject %q", subject)
+	}
+	if from := h.Get("From"); from != "Lukaut <noreply@example.com>" {
+		t.Errorf("unexpected sender %q", from)
+	}
+	if !strings.Contains(h.Get("Content-Type"), "multipart/alternative") {
+		t.Errorf("expected a multipart message, got %q", h.Get("Content-Type"))
+	}
+}
+
+func TestFile_WritesEachMessageToItsOwnFile(t *testing.T) {
+	svc, dir := newTestFileService(t, nil)
+
+	for _, to := range []string{"a@example.com", "b@example.com", "a@example.com"} {
+		if err := svc.SendVerificationEmail(context.Background(), to, "Pat", "tok_123"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	emails := readEmails(t, dir)
+	if len(emails) != 3 {
+		t.Fatalf("expected 3 email files, got %d", len(emails))
+	}
+	for i, want := range []string{"a@example.com", "b@example.com", "a@example.com"} {
+		if to := headers(t, emails[i]).Get("To"); to != want {
+			t.Errorf("expected file %d to be for %s, got %q", i, want, to)
+		}
+	}
+}
+
+func TestFile_LogsAppLinks(t *testing.T) {
+	var logs bytes.Buffer
+	svc, _ := newTestFileService(t, &logs)
+
+	if err := svc.SendVerificationEmail(context.Background(), "pat@example.com", "Pat", "tok_123"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(logs.String(), `url="https://app.example.com/verify-email?token=tok_123"`) {
+		t.Errorf("expected the verification link to be logged, got %s", logs.String())
+	}
+}
+
+func TestSanitizeFileName(t *testing.T) {
+	if got := sanitizeFileName("../pat+test@example.com"); got != ".._pat_test@example.com" {
+		t.Errorf("expected path separators and punctuation to be replaced, got %q", got)
+	}
+}
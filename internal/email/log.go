@@ -0,0 +1,55 @@
+package email
+
+import (
+	"context"
+	"log/slog"
+)
+
+// =============================================================================
+// Log-Only Email Service Implementation
+// =============================================================================
+
+// LogEmailService writes rendered emails to the logger instead of sending
+// them. Use it for local work without Mailhog; links such as verification
+// and password reset URLs appear in the text body.
+type LogEmailService struct {
+	renderingService
+	logger *slog.Logger
+}
+
+// NewLogEmailService creates an email service that only logs messages.
+//
+// Parameters:
+// - baseURL: Application base URL for constructing links
+// - templatesDir: Path to email templates directory (e.g., "web/templates/email")
+// - logger: Logger that receives the rendered emails
+func NewLogEmailService(baseURL, templatesDir string, logger *slog.Logger) (*LogEmailService, error) {
+	renderer, err := newRenderer(baseURL, templatesDir, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &LogEmailService{logger: logger}
+	s.renderingService = renderingService{renderer: renderer, sender: s}
+	return s, nil
+}
+
+// send logs the email. It never fails.
+func (s *LogEmailService) send(ctx context.Context, email Email) error {
+	s.logger.Info("email (log only, not sent)",
+		"to", email.To,
+		"bcc", email.Bcc,
+		"from_name", email.FromName,
+		"reply_to", email.ReplyTo,
+		"subject", email.Subject,
+		"text_body", email.TextBody,
+		"html_bytes", len(email.HTMLBody),
+	)
+	return nil
+}
+
+// =============================================================================
+// Compile-time interface check
+// =============================================================================
+
+var _ EmailService = (*LogEmailService)(nil)
@@ -0,0 +1,78 @@
+package email
+
+import (
+	"bytes"
+	"fmt"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+)
+
+// =============================================================================
+// MIME Assembly
+// =============================================================================
+
+// formatMessage constructs the raw email message with headers and a
+// multipart/alternative body holding the text and HTML versions. The From
+// header shows address with the message's FromName, or defaultName. Bcc is
+// an envelope recipient only and never appears in the headers.
+func formatMessage(email Email, defaultName, address string) ([]byte, error) {
+	var body bytes.Buffer
+	contentType, err := writeAlternative(&body, email.TextBody, email.HTMLBody)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("From: %s\r\n", fromAddress(email, defaultName, address)))
+	if replyTo := replyToAddress(email); replyTo != "" {
+		buf.WriteString(fmt.Sprintf("Reply-To: %s\r\n", replyTo))
+	}
+	buf.WriteString(fmt.Sprintf("To: %s\r\n", email.To))
+	buf.WriteString(fmt.Sprintf("Subject: %s\r\n", email.Subject))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString(fmt.Sprintf("Content-Type: %s\r\n", contentType))
+	buf.WriteString("\r\n")
+	buf.Write(body.Bytes())
+
+	return buf.Bytes(), nil
+}
+
+// writeAlternative writes a multipart/alternative body with the plain text
+// part first and the HTML part last, as clients show the last part they
+// support. It returns the Content-Type header for the body.
+//
+// Parts are quoted-printable encoded, so the message is 7-bit clean with
+// short CRLF-terminated lines. Relays then have no reason to re-encode or
+// re-wrap it, which would break a DKIM body signature.
+func writeAlternative(buf *bytes.Buffer, textBody, htmlBody string) (string, error) {
+	mw := multipart.NewWriter(buf)
+
+	for _, part := range []struct {
+		contentType string
+		body        string
+	}{
+		{"text/plain; charset=utf-8", textBody},
+		{"text/html; charset=utf-8", htmlBody},
+	} {
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Type", part.contentType)
+		header.Set("Content-Transfer-Encoding", "quoted-printable")
+		pw, err := mw.CreatePart(header)
+		if err != nil {
+			return "", fmt.Errorf("create %s part: %w", part.contentType, err)
+		}
+		qp := quotedprintable.NewWriter(pw)
+		if _, err := qp.Write([]byte(part.body)); err != nil {
+			return "", fmt.Errorf("write %s part: %w", part.contentType, err)
+		}
+		if err := qp.Close(); err != nil {
+			return "", fmt.Errorf("write %s part: %w", part.contentType, err)
+		}
+	}
+
+	if err := mw.Close(); err != nil {
+		return "", fmt.Errorf("close multipart body: %w", err)
+	}
+	return fmt.Sprintf("multipart/alternative; boundary=%q", mw.Boundary()), nil
+}
@@ -0,0 +1,143 @@
+package email
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// htmlTag matches anything that looks like an HTML tag or entity.
+var htmlTag = regexp.MustCompile(`<[a-zA-Z/!][^>]*>|&[a-z]+;`)
+
+// alternativeParts parses a raw message and returns its decoded parts by
+// content type.
+func alternativeParts(t *testing.T, raw []byte) map[string]string {
+	t.Helper()
+	msg, err := mail.ReadMessage(bufio.NewReader(bytes.NewReader(raw)))
+	if err != nil {
+		t.Fatalf("failed to parse message: %v", err)
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatalf("failed to parse content type: %v", err)
+	}
+	if mediaType != "multipart/alternative" {
+		t.Fatalf("expected multipart/alternative, got %q", mediaType)
+	}
+
+	parts := make(map[string]string)
+	var order []string
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("failed to read part: %v", err)
+		}
+		// NextPart decodes quoted-printable bodies
+		body, err := io.ReadAll(part)
+		if err != nil {
+			t.Fatalf("failed to read part body: %v", err)
+		}
+		contentType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		parts[contentType] = string(body)
+		order = append(order, contentType)
+	}
+	if strings.Join(order, ",") != "text/plain,text/html" {
+		t.Errorf("expected text then HTML parts, got %v", order)
+	}
+	return parts
+}
+
+func TestBuildMessage_EveryEmailHasTextAndHTMLParts(t *testing.T) {
+	r, err := newRenderer("https://app.example.com", testTemplatesDir, newTestLogger())
+	if err != nil {
+		t.Fatalf("failed to load templates: %v", err)
+	}
+	svc := &SMTPEmailService{config: SMTPConfig{From: "noreply@example.com", FromName: "Lukaut"}}
+
+	render := map[string]func() (Email, error){
+		"verification": func() (Email, error) { return r.verification("pat@example.com", "Pat", "tok_verify") },
+		"verification reminder": func() (Email, error) {
+			return r.verificationReminder("pat@example.com", "Pat", "tok_verify")
+		},
+		"password reset": func() (Email, error) { return r.passwordReset("pat@example.com", "Pat", "tok_reset") },
+		"report ready": func() (Email, error) {
+			return r.reportReady("pat@example.com", "Pat", "https://app.example.com/reports/1/download")
+		},
+		"report to client": func() (Email, error) {
+			return r.reportToClient("client@example.com", "Pat", "Acme Safety", "Main St", "https://app.example.com/r/1", "", "")
+		},
+		"report failed": func() (Email, error) {
+			return r.reportFailed("pat@example.com", "Pat", "https://app.example.com/inspections/1")
+		},
+		"report delayed": func() (Email, error) { return r.reportDelayed("client@example.com", "Pat", "Acme Safety", "") },
+		"organization invite": func() (Email, error) {
+			return r.organizationInvite("max@example.com", "Pat", "Acme Safety", "tok_invite")
+		},
+	}
+
+	for name, build := range render {
+		t.Run(name, func(t *testing.T) {
+			email, err := build()
+			if err != nil {
+				t.Fatalf("failed to render: %v", err)
+			}
+			raw, err := svc.buildMessage(email)
+			if err != nil {
+				t.Fatalf("failed to build message: %v", err)
+			}
+
+			// The body must survive relays untouched: 7-bit, CRLF, short lines
+			_, body, _ := strings.Cut(string(raw), "\r\n\r\n")
+			for i, line := range strings.Split(body, "\r\n") {
+				if len(line) > 76 {
+					t.Errorf("body line %d is %d characters, want at most 76", i+1, len(line))
+				}
+				for _, b := range []byte(line) {
+					if b > 127 || b == '\n' {
+						t.Fatalf("body line %d is not 7-bit CRLF text: %q", i+1, line)
+					}
+				}
+			}
+
+			parts := alternativeParts(t, raw)
+			text, html := parts["text/plain"], parts["text/html"]
+			if strings.TrimSpace(text) == "" || strings.TrimSpace(html) == "" {
+				t.Fatal("expected non-empty text and HTML parts")
+			}
+			if tag := htmlTag.FindString(text); tag != "" {
+				t.Errorf("expected no markup in the text part, found %q", tag)
+			}
+			if !strings.Contains(html, "<html") {
+				t.Error("expected the HTML part to be the HTML template")
+			}
+		})
+	}
+}
+
+func TestReportToClient_TextPartOmitsMissingCompany(t *testing.T) {
+	r, err := newRenderer("https://app.example.com", testTemplatesDir, newTestLogger())
+	if err != nil {
+		t.Fatalf("failed to load templates: %v", err)
+	}
+
+	email, err := r.reportToClient("client@example.com", "Pat Inspector", "", "Main St", "https://app.example.com/r/1", "", "")
+	if err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+	if strings.Contains(email.TextBody, "Company:") {
+		t.Error("expected no company line without a company")
+	}
+	if !strings.Contains(email.TextBody, "Inspector: Pat Inspector\n\nYou can download the report here:\n\nhttps://app.example.com/r/1\n") {
+		t.Errorf("unexpected text body:\n%s", email.TextBody)
+	}
+}
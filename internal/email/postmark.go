@@ -0,0 +1,182 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// =============================================================================
+// Postmark Email Service Implementation
+// =============================================================================
+
+// PostmarkEmailService sends emails via the Postmark HTTP API.
+//
+// Use this when outbound SMTP ports are blocked. It renders the same
+// templates as SMTPEmailService and POSTs each message to /email.
+//
+// Error classification:
+// - 429 and 5xx responses, timeouts, and connection errors are retryable
+// - Other 4xx responses (rejected recipient, bad token, invalid message) are permanent
+type PostmarkEmailService struct {
+	renderingService
+	config PostmarkConfig
+	client *http.Client
+	logger *slog.Logger
+}
+
+// NewPostmarkEmailService creates a new Postmark API email service.
+//
+// Parameters:
+// - config: Postmark API configuration (ServerToken is required)
+// - baseURL: Application base URL for constructing links
+// - templatesDir: Path to email templates directory (e.g., "web/templates/email")
+// - logger: Structured logger for error reporting
+func NewPostmarkEmailService(
+	config PostmarkConfig,
+	baseURL string,
+	templatesDir string,
+	logger *slog.Logger,
+) (*PostmarkEmailService, error) {
+	if config.ServerToken == "" {
+		return nil, fmt.Errorf("postmark server token is required")
+	}
+
+	// Set defaults
+	if config.From == "" {
+		config.From = DefaultFromEmail
+	}
+	if config.FromName == "" {
+		config.FromName = DefaultFromName
+	}
+	if config.MessageStream == "" {
+		config.MessageStream = DefaultPostmarkMessageStream
+	}
+	if config.APIURL == "" {
+		config.APIURL = DefaultPostmarkAPIURL
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultSendTimeout
+	}
+
+	renderer, err := newRenderer(baseURL, templatesDir, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &PostmarkEmailService{
+		config: config,
+		client: &http.Client{},
+		logger: logger,
+	}
+	s.renderingService = renderingService{renderer: renderer, sender: s}
+	return s, nil
+}
+
+// =============================================================================
+// API Types
+// =============================================================================
+
+// postmarkMessage is the request body for POST /email.
+type postmarkMessage struct {
+	From          string `json:"From"`
+	To            string `json:"To"`
+	Bcc           string `json:"Bcc,omitempty"`
+	ReplyTo       string `json:"ReplyTo,omitempty"`
+	Subject       string `json:"Subject"`
+	HTMLBody      string `json:"HtmlBody,omitempty"`
+	TextBody      string `json:"TextBody,omitempty"`
+	MessageStream string `json:"MessageStream"`
+}
+
+// postmarkResponse is the response body for POST /email.
+type postmarkResponse struct {
+	ErrorCode int    `json:"ErrorCode"`
+	Message   string `json:"Message"`
+	MessageID string `json:"MessageID"`
+}
+
+// =============================================================================
+// Internal Methods
+// =============================================================================
+
+// send sends an email via the Postmark API.
+func (s *PostmarkEmailService) send(ctx context.Context, email Email) error {
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	messageID, err := s.post(ctx, email)
+	if err != nil {
+		s.logger.Error("failed to send email",
+			"to", email.To,
+			"subject", email.Subject,
+			"error", err,
+		)
+		return err
+	}
+
+	s.logger.Info("email sent",
+		"to", email.To,
+		"subject", email.Subject,
+		"message_id", messageID,
+	)
+
+	return nil
+}
+
+// post submits the message and returns Postmark's message ID.
+func (s *PostmarkEmailService) post(ctx context.Context, email Email) (string, error) {
+	body, err := json.Marshal(postmarkMessage{
+		From:          fromAddress(email, s.config.FromName, s.config.From),
+		To:            email.To,
+		Bcc:           email.Bcc,
+		ReplyTo:       replyToAddress(email),
+		Subject:       email.Subject,
+		HTMLBody:      email.HTMLBody,
+		TextBody:      email.TextBody,
+		MessageStream: s.config.MessageStream,
+	})
+	if err != nil {
+		return "", Permanent(fmt.Errorf("failed to encode postmark message: %w", err))
+	}
+
+	url := strings.TrimSuffix(s.config.APIURL, "/") + "/email"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return "", Permanent(fmt.Errorf("failed to build postmark request: %w", err))
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Postmark-Server-Token", s.config.ServerToken)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", Retryable(fmt.Errorf("postmark request failed: %w", err))
+	}
+	defer resp.Body.Close()
+
+	var result postmarkResponse
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	_ = json.Unmarshal(respBody, &result)
+
+	if resp.StatusCode == http.StatusOK && result.ErrorCode == 0 {
+		return result.MessageID, nil
+	}
+
+	err = fmt.Errorf("postmark returned status %d (error code %d): %s", resp.StatusCode, result.ErrorCode, result.Message)
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return "", Retryable(err)
+	}
+	return "", Permanent(err)
+}
+
+// =============================================================================
+// Compile-time interface check
+// =============================================================================
+
+var _ EmailService = (*PostmarkEmailService)(nil)
@@ -0,0 +1,154 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testTemplatesDir = "../../web/templates/email"
+
+func newTestLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func newTestPostmarkService(t *testing.T, apiURL string, timeout time.Duration) *PostmarkEmailService {
+	t.Helper()
+	svc, err := NewPostmarkEmailService(PostmarkConfig{
+		ServerToken: "server-token",
+		APIURL:      apiURL,
+		From:        "noreply@example.com",
+		FromName:    "Lukaut",
+		Timeout:     timeout,
+	}, "https://app.example.com", testTemplatesDir, newTestLogger())
+	if err != nil {
+		t.Fatalf("failed to create postmark service: %v", err)
+	}
+	return svc
+}
+
+// =============================================================================
+// Postmark Tests
+// =============================================================================
+
+func TestPostmark_SendsRenderedEmail(t *testing.T) {
+	var got postmarkMessage
+	var token string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/email" {
+			t.Errorf("expected POST /email, got %s %s", r.Method, r.URL.Path)
+		}
+		token = r.Header.Get("X-Postmark-Server-Token")
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_, _ = w.Write([]byte(`{"ErrorCode":0,"Message":"OK","MessageID":"b7bc2f4a"}`))
+	}))
+	defer server.Close()
+
+	svc := newTestPostmarkService(t, server.URL, time.Second)
+	if err := svc.SendPasswordResetEmail(context.Background(), "pat@example.com", "Pat", "tok_123"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if token != "server-token" {
+		t.Errorf("expected server token header, got %q", token)
+	}
+	if got.To != "pat@example.com" || got.From != "Lukaut <noreply@example.com>" || got.MessageStream != "outbound" {
+		t.Errorf("unexpected message envelope: %+v", got)
+	}
+	if !strings.Contains(got.TextBody, "https://app.example.com/reset-password?token=tok_123") {
+		t.Errorf("expected reset link in text body, got %q", got.TextBody)
+	}
+	if got.HTMLBody == "" {
+		t.Error("expected rendered HTML body")
+	}
+}
+
+func TestPostmark_ClassifiesErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		retryable bool
+	}{
+		{"inactive recipient", http.StatusUnprocessableEntity, `{"ErrorCode":406,"Message":"Inactive recipient"}`, false},
+		{"bad token", http.StatusUnauthorized, `{"ErrorCode":10,"Message":"Bad or missing API token"}`, false},
+		{"rate limited", http.StatusTooManyRequests, `{"ErrorCode":0,"Message":"Rate limit exceeded"}`, true},
+		{"server error", http.StatusServiceUnavailable, ``, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			svc := newTestPostmarkService(t, server.URL, time.Second)
+			err := svc.SendVerificationEmail(context.Background(), "pat@example.com", "Pat", "tok_123")
+
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if got := IsRetryable(err); got != tt.retryable {
+				t.Errorf("expected retryable=%v, got %v (%v)", tt.retryable, got, err)
+			}
+		})
+	}
+}
+
+func TestPostmark_TimeoutIsRetryable(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	svc := newTestPostmarkService(t, server.URL, 50*time.Millisecond)
+
+	start := time.Now()
+	err := svc.SendReportReadyEmail(context.Background(), "pat@example.com", "Pat", "https://app.example.com/r/1")
+
+	if err == nil || !IsRetryable(err) {
+		t.Errorf("expected retryable timeout error, got %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("expected send to give up at the timeout")
+	}
+}
+
+func TestNewPostmarkEmailService_RequiresToken(t *testing.T) {
+	_, err := NewPostmarkEmailService(PostmarkConfig{}, "https://app.example.com", testTemplatesDir, newTestLogger())
+	if err == nil {
+		t.Error("expected error when server token is missing")
+	}
+}
+
+// =============================================================================
+// Log Service Tests
+// =============================================================================
+
+func TestLogEmailService_WritesRenderedEmail(t *testing.T) {
+	var buf bytes.Buffer
+	svc, err := NewLogEmailService("http://localhost:8080", testTemplatesDir, slog.New(slog.NewTextHandler(&buf, nil)))
+	if err != nil {
+		t.Fatalf("failed to create log service: %v", err)
+	}
+
+	if err := svc.SendVerificationEmail(context.Background(), "pat@example.com", "Pat", "tok_123"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "verify-email?token=tok_123") {
+		t.Errorf("expected verification link in log output, got %q", buf.String())
+	}
+}
@@ -0,0 +1,105 @@
+package email
+
+import (
+	"context"
+	"fmt"
+)
+
+// =============================================================================
+// Queued Messages
+// =============================================================================
+
+// Template names identify which EmailService method renders a queued Message.
+const (
+	TemplateVerification         = "verification"
+	TemplateVerificationReminder = "verification_reminder"
+	TemplatePasswordReset        = "password_reset"
+	TemplateReportReady          = "report_ready"
+	TemplateReportToClient       = "report_to_client"
+	TemplateReportFailed         = "report_failed"
+	TemplateReportDelayed        = "report_delayed"
+	TemplateOrganizationInvite   = "organization_invite"
+)
+
+// Data keys used by the templates above.
+const (
+	DataName             = "name"
+	DataToken            = "token"
+	DataReportURL        = "report_url"
+	DataInspectorName    = "inspector_name"
+	DataInspectorCompany = "inspector_company"
+	DataSiteName         = "site_name"
+	DataInspectionURL    = "inspection_url"
+	DataBcc              = "bcc"
+	DataReplyTo          = "reply_to"
+	DataInviterName      = "inviter_name"
+	DataOrganizationName = "organization_name"
+)
+
+// Message is an email to be rendered and sent later, typically by the
+// background worker. Data holds the template's parameters and is stored in
+// the job payload, so it should only contain values needed to render it.
+type Message struct {
+	Template string            `json:"template"`
+	To       string            `json:"to"`
+	Data     map[string]string `json:"data,omitempty"`
+}
+
+// Queue accepts messages for delivery.
+//
+// Implementations:
+// - The worker-backed queue in cmd/server: retries transient failures with backoff
+// - SyncQueue: sends immediately, for tests and when the worker is disabled
+type Queue interface {
+	Enqueue(ctx context.Context, msg Message) error
+}
+
+// UnknownTemplateError is returned by Send when a message names a template
+// that does not exist. Retrying such a message can never succeed.
+type UnknownTemplateError struct {
+	Template string
+}
+
+func (e *UnknownTemplateError) Error() string {
+	return fmt.Sprintf("unknown email template: %q", e.Template)
+}
+
+// Send renders msg with its template and sends it using svc.
+func Send(ctx context.Context, svc EmailService, msg Message) error {
+	d := msg.Data
+	switch msg.Template {
+	case TemplateVerification:
+		return svc.SendVerificationEmail(ctx, msg.To, d[DataName], d[DataToken])
+	case TemplateVerificationReminder:
+		return svc.SendVerificationReminderEmail(ctx, msg.To, d[DataName], d[DataToken])
+	case TemplatePasswordReset:
+		return svc.SendPasswordResetEmail(ctx, msg.To, d[DataName], d[DataToken])
+	case TemplateReportReady:
+		return svc.SendReportReadyEmail(ctx, msg.To, d[DataName], d[DataReportURL])
+	case TemplateReportToClient:
+		return svc.SendReportToClientEmail(ctx, msg.To, d[DataInspectorName], d[DataInspectorCompany], d[DataSiteName], d[DataReportURL], d[DataBcc], d[DataReplyTo])
+	case TemplateReportFailed:
+		return svc.SendReportFailedEmail(ctx, msg.To, d[DataName], d[DataInspectionURL])
+	case TemplateReportDelayed:
+		return svc.SendReportDelayedEmail(ctx, msg.To, d[DataInspectorName], d[DataInspectorCompany], d[DataReplyTo])
+	case TemplateOrganizationInvite:
+		return svc.SendOrganizationInviteEmail(ctx, msg.To, d[DataInviterName], d[DataOrganizationName], d[DataToken])
+	default:
+		return &UnknownTemplateError{Template: msg.Template}
+	}
+}
+
+// SyncQueue sends each message immediately instead of queueing it.
+type SyncQueue struct {
+	svc EmailService
+}
+
+// NewSyncQueue creates a Queue that sends messages synchronously with svc.
+func NewSyncQueue(svc EmailService) *SyncQueue {
+	return &SyncQueue{svc: svc}
+}
+
+// Enqueue sends msg and returns any delivery error.
+func (q *SyncQueue) Enqueue(ctx context.Context, msg Message) error {
+	return Send(ctx, q.svc, msg)
+}
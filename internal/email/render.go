@@ -0,0 +1,368 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+// =============================================================================
+// Template Rendering
+// =============================================================================
+
+// renderer builds Email messages from the templates in web/templates/email.
+// Every message has an HTML template (name.html) and a plain text template
+// (name.txt), so it can be sent as multipart/alternative. It is shared by
+// every provider so all of them send identical content.
+type renderer struct {
+	baseURL       string
+	templates     *template.Template
+	textTemplates *texttemplate.Template
+	logger        *slog.Logger
+}
+
+// newRenderer loads the email templates from templatesDir.
+func newRenderer(baseURL, templatesDir string, logger *slog.Logger) (*renderer, error) {
+	templates, err := template.New("email").Funcs(emailTemplateFuncs()).ParseGlob(filepath.Join(templatesDir, "*.html"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse email templates: %w", err)
+	}
+	textTemplates, err := texttemplate.New("email").ParseGlob(filepath.Join(templatesDir, "*.txt"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse plain text email templates: %w", err)
+	}
+
+	return &renderer{
+		baseURL:       strings.TrimSuffix(baseURL, "/"),
+		templates:     templates,
+		textTemplates: textTemplates,
+		logger:        logger,
+	}, nil
+}
+
+// verification renders the email verification message.
+func (r *renderer) verification(to, name, token string) (Email, error) {
+	verifyURL := fmt.Sprintf("%s/verify-email?token=%s", r.baseURL, token)
+
+	data := map[string]interface{}{
+		"Name":      name,
+		"VerifyURL": verifyURL,
+		"Year":      time.Now().Year(),
+	}
+
+	htmlBody, textBody, err := r.renderTemplates("verification", data)
+	if err != nil {
+		return Email{}, fmt.Errorf("failed to render verification email template: %w", err)
+	}
+
+	return Email{
+		To:       to,
+		Subject:  "Verify your Lukaut account",
+		HTMLBody: htmlBody,
+		TextBody: textBody,
+	}, nil
+}
+
+// verificationReminder renders the reminder sent to users who have not yet
+// verified their email address.
+func (r *renderer) verificationReminder(to, name, token string) (Email, error) {
+	verifyURL := fmt.Sprintf("%s/verify-email?token=%s", r.baseURL, token)
+
+	data := map[string]interface{}{
+		"Name":      name,
+		"VerifyURL": verifyURL,
+		"Year":      time.Now().Year(),
+	}
+
+	htmlBody, textBody, err := r.renderTemplates("verification_reminder", data)
+	if err != nil {
+		return Email{}, fmt.Errorf("failed to render verification reminder email template: %w", err)
+	}
+
+	return Email{
+		To:       to,
+		Subject:  "Reminder: verify your Lukaut account",
+		HTMLBody: htmlBody,
+		TextBody: textBody,
+	}, nil
+}
+
+// passwordReset renders the password reset message.
+func (r *renderer) passwordReset(to, name, token string) (Email, error) {
+	resetURL := fmt.Sprintf("%s/reset-password?token=%s", r.baseURL, token)
+
+	data := map[string]interface{}{
+		"Name":     name,
+		"ResetURL": resetURL,
+		"Year":     time.Now().Year(),
+	}
+
+	htmlBody, textBody, err := r.renderTemplates("password_reset", data)
+	if err != nil {
+		return Email{}, fmt.Errorf("failed to render password reset email template: %w", err)
+	}
+
+	return Email{
+		To:       to,
+		Subject:  "Reset your Lukaut password",
+		HTMLBody: htmlBody,
+		TextBody: textBody,
+	}, nil
+}
+
+// reportReady renders the report ready notification.
+func (r *renderer) reportReady(to, name, reportURL string) (Email, error) {
+	data := map[string]interface{}{
+		"Name":      name,
+		"ReportURL": reportURL,
+		"Year":      time.Now().Year(),
+	}
+
+	htmlBody, textBody, err := r.renderTemplates("report_ready", data)
+	if err != nil {
+		return Email{}, fmt.Errorf("failed to render report ready email template: %w", err)
+	}
+
+	return Email{
+		To:       to,
+		Subject:  "Your inspection report is ready",
+		HTMLBody: htmlBody,
+		TextBody: textBody,
+	}, nil
+}
+
+// reportToClient renders the message sending a report to a client.
+func (r *renderer) reportToClient(to, inspectorName, inspectorCompany, siteName, reportURL, bcc, replyTo string) (Email, error) {
+	// Use inspector company if available, otherwise fall back to inspector name
+	fromEntity := inspectorCompany
+	if fromEntity == "" {
+		fromEntity = inspectorName
+	}
+
+	data := map[string]interface{}{
+		"InspectorName":    inspectorName,
+		"InspectorCompany": inspectorCompany,
+		"FromEntity":       fromEntity,
+		"SiteName":         siteName,
+		"ReportURL":        reportURL,
+		"Year":             time.Now().Year(),
+	}
+
+	htmlBody, textBody, err := r.renderTemplates("report_to_client", data)
+	if err != nil {
+		return Email{}, fmt.Errorf("failed to render report to client email template: %w", err)
+	}
+
+	subject := fmt.Sprintf("Safety Inspection Report for %s", siteName)
+	if siteName == "" {
+		subject = "Your Safety Inspection Report is Ready"
+	}
+
+	return Email{
+		To:       to,
+		Bcc:      bcc,
+		FromName: fromEntity,
+		ReplyTo:  replyTo,
+		Subject:  subject,
+		HTMLBody: htmlBody,
+		TextBody: textBody,
+	}, nil
+}
+
+// reportFailed renders the notification that report generation failed.
+func (r *renderer) reportFailed(to, name, inspectionURL string) (Email, error) {
+	data := map[string]interface{}{
+		"Name":          name,
+		"InspectionURL": inspectionURL,
+		"Year":          time.Now().Year(),
+	}
+
+	htmlBody, textBody, err := r.renderTemplates("report_failed", data)
+	if err != nil {
+		return Email{}, fmt.Errorf("failed to render report failed email template: %w", err)
+	}
+
+	return Email{
+		To:       to,
+		Subject:  "We couldn't generate your inspection report",
+		HTMLBody: htmlBody,
+		TextBody: textBody,
+	}, nil
+}
+
+// reportDelayed renders the apology sent to a client when their report
+// could not be generated. It does not mention the cause.
+func (r *renderer) reportDelayed(to, inspectorName, inspectorCompany, replyTo string) (Email, error) {
+	// Use inspector company if available, otherwise fall back to inspector name
+	fromEntity := inspectorCompany
+	if fromEntity == "" {
+		fromEntity = inspectorName
+	}
+
+	data := map[string]interface{}{
+		"FromEntity": fromEntity,
+		"Year":       time.Now().Year(),
+	}
+
+	htmlBody, textBody, err := r.renderTemplates("report_delayed", data)
+	if err != nil {
+		return Email{}, fmt.Errorf("failed to render report delayed email template: %w", err)
+	}
+
+	return Email{
+		To:       to,
+		FromName: fromEntity,
+		ReplyTo:  replyTo,
+		Subject:  "Your safety inspection report is delayed",
+		HTMLBody: htmlBody,
+		TextBody: textBody,
+	}, nil
+}
+
+// organizationInvite renders an invitation to join an organization.
+func (r *renderer) organizationInvite(to, inviterName, organizationName, token string) (Email, error) {
+	inviteURL := fmt.Sprintf("%s/organization/join?token=%s", r.baseURL, token)
+
+	data := map[string]interface{}{
+		"InviterName":      inviterName,
+		"OrganizationName": organizationName,
+		"InviteURL":        inviteURL,
+		"Year":             time.Now().Year(),
+	}
+
+	htmlBody, textBody, err := r.renderTemplates("organization_invite", data)
+	if err != nil {
+		return Email{}, fmt.Errorf("failed to render organization invite email template: %w", err)
+	}
+
+	return Email{
+		To:       to,
+		Subject:  fmt.Sprintf("%s invited you to join %s on Lukaut", inviterName, organizationName),
+		HTMLBody: htmlBody,
+		TextBody: textBody,
+	}, nil
+}
+
+// renderTemplates renders the HTML (name.html) and plain text (name.txt)
+// templates of an email with the given data.
+func (r *renderer) renderTemplates(name string, data interface{}) (htmlBody, textBody string, err error) {
+	var html, text bytes.Buffer
+	if err := r.templates.ExecuteTemplate(&html, name+".html", data); err != nil {
+		return "", "", err
+	}
+	if err := r.textTemplates.ExecuteTemplate(&text, name+".txt", data); err != nil {
+		return "", "", err
+	}
+	return html.String(), text.String(), nil
+}
+
+// =============================================================================
+// Rendering EmailService
+// =============================================================================
+
+// sender delivers a rendered Email. Each provider implements it.
+type sender interface {
+	send(ctx context.Context, email Email) error
+}
+
+// renderingService implements EmailService by rendering each message with
+// the shared templates and handing it to a provider's sender. Providers
+// embed it to get the EmailService methods.
+type renderingService struct {
+	renderer *renderer
+	sender   sender
+}
+
+// SendVerificationEmail sends an email verification link to a new user.
+func (s *renderingService) SendVerificationEmail(ctx context.Context, to, name, token string) error {
+	email, err := s.renderer.verification(to, name, token)
+	if err != nil {
+		return Permanent(err)
+	}
+	return s.sender.send(ctx, email)
+}
+
+// SendVerificationReminderEmail reminds a user to verify their email address.
+func (s *renderingService) SendVerificationReminderEmail(ctx context.Context, to, name, token string) error {
+	email, err := s.renderer.verificationReminder(to, name, token)
+	if err != nil {
+		return Permanent(err)
+	}
+	return s.sender.send(ctx, email)
+}
+
+// SendPasswordResetEmail sends a password reset link to a user.
+func (s *renderingService) SendPasswordResetEmail(ctx context.Context, to, name, token string) error {
+	email, err := s.renderer.passwordReset(to, name, token)
+	if err != nil {
+		return Permanent(err)
+	}
+	return s.sender.send(ctx, email)
+}
+
+// SendReportReadyEmail notifies a user that their inspection report is ready.
+func (s *renderingService) SendReportReadyEmail(ctx context.Context, to, name, reportURL string) error {
+	email, err := s.renderer.reportReady(to, name, reportURL)
+	if err != nil {
+		return Permanent(err)
+	}
+	return s.sender.send(ctx, email)
+}
+
+// SendReportToClientEmail sends an inspection report to a client.
+func (s *renderingService) SendReportToClientEmail(ctx context.Context, to, inspectorName, inspectorCompany, siteName, reportURL, bcc, replyTo string) error {
+	email, err := s.renderer.reportToClient(to, inspectorName, inspectorCompany, siteName, reportURL, bcc, replyTo)
+	if err != nil {
+		return Permanent(err)
+	}
+	return s.sender.send(ctx, email)
+}
+
+// SendReportFailedEmail tells a user that their report could not be generated.
+func (s *renderingService) SendReportFailedEmail(ctx context.Context, to, name, inspectionURL string) error {
+	email, err := s.renderer.reportFailed(to, name, inspectionURL)
+	if err != nil {
+		return Permanent(err)
+	}
+	return s.sender.send(ctx, email)
+}
+
+// SendReportDelayedEmail apologizes to a client whose report could not be sent.
+func (s *renderingService) SendReportDelayedEmail(ctx context.Context, to, inspectorName, inspectorCompany, replyTo string) error {
+	email, err := s.renderer.reportDelayed(to, inspectorName, inspectorCompany, replyTo)
+	if err != nil {
+		return Permanent(err)
+	}
+	return s.sender.send(ctx, email)
+}
+
+// SendOrganizationInviteEmail invites a user to join an organization.
+func (s *renderingService) SendOrganizationInviteEmail(ctx context.Context, to, inviterName, organizationName, token string) error {
+	email, err := s.renderer.organizationInvite(to, inviterName, organizationName, token)
+	if err != nil {
+		return Permanent(err)
+	}
+	return s.sender.send(ctx, email)
+}
+
+// =============================================================================
+// Template Functions
+// =============================================================================
+
+// emailTemplateFuncs returns template functions available in email templates.
+func emailTemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"safeHTML": func(s string) template.HTML {
+			return template.HTML(s)
+		},
+		"currentYear": func() int {
+			return time.Now().Year()
+		},
+	}
+}
@@ -1,14 +1,15 @@
 package email
 
 import (
-	"bytes"
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
-	"html/template"
 	"log/slog"
+	"net"
 	"net/smtp"
-	"path/filepath"
-	"strings"
+	"net/textproto"
+	"strconv"
 	"time"
 )
 
@@ -24,12 +25,16 @@ import (
 // - Any standard SMTP server
 //
 // Email templates are loaded from the templates directory and rendered
-// with Go's html/template package.
+// with Go's html/template package. Each attempt is bounded by config.Timeout;
+// 5xx replies are reported as permanent failures, everything else as
+// retryable (see IsRetryable). Retryable failures are retried with
+// exponential backoff up to config.MaxAttempts, and a circuit breaker stops
+// contacting a server that keeps failing until config.BreakerCooldown passes.
 type SMTPEmailService struct {
-	config    SMTPConfig
-	baseURL   string
-	templates *template.Template
-	logger    *slog.Logger
+	renderingService
+	config  SMTPConfig
+	breaker *circuitBreaker
+	logger  *slog.Logger
 }
 
 // NewSMTPEmailService creates a new SMTP-based email service.
@@ -66,302 +71,207 @@ func NewSMTPEmailService(
 	if config.FromName == "" {
 		config.FromName = DefaultFromName
 	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultSendTimeout
+	}
+	if config.DialTimeout <= 0 {
+		config.DialTimeout = DefaultSMTPDialTimeout
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = DefaultSMTPMaxAttempts
+	}
+	if config.RetryBaseDelay <= 0 {
+		config.RetryBaseDelay = DefaultSMTPRetryBaseDelay
+	}
+	if config.BreakerThreshold == 0 {
+		config.BreakerThreshold = DefaultSMTPBreakerThreshold
+	}
+	if config.BreakerCooldown <= 0 {
+		config.BreakerCooldown = DefaultSMTPBreakerCooldown
+	}
 
-	// Load email templates
-	pattern := filepath.Join(templatesDir, "*.html")
-	templates, err := template.New("email").Funcs(emailTemplateFuncs()).ParseGlob(pattern)
+	renderer, err := newRenderer(baseURL, templatesDir, logger)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse email templates: %w", err)
+		return nil, err
 	}
 
-	return &SMTPEmailService{
-		config:    config,
-		baseURL:   strings.TrimSuffix(baseURL, "/"),
-		templates: templates,
-		logger:    logger,
-	}, nil
+	s := &SMTPEmailService{
+		config:  config,
+		breaker: newCircuitBreaker(config.BreakerThreshold, config.BreakerCooldown),
+		logger:  logger,
+	}
+	s.renderingService = renderingService{renderer: renderer, sender: s}
+	return s, nil
 }
 
 // =============================================================================
-// EmailService Interface Implementation
+// Internal Methods
 // =============================================================================
 
-// SendVerificationEmail sends an email verification link to a new user.
-func (s *SMTPEmailService) SendVerificationEmail(ctx context.Context, to, name, token string) error {
-	verifyURL := fmt.Sprintf("%s/verify-email?token=%s", s.baseURL, token)
-
-	data := map[string]interface{}{
-		"Name":      name,
-		"VerifyURL": verifyURL,
-		"Year":      time.Now().Year(),
-	}
-
-	htmlBody, err := s.renderTemplate("verification.html", data)
+// send sends an email via SMTP, retrying transient failures.
+//
+// Each attempt is logged. Retries wait RetryBaseDelay * 2^(attempt-1) and
+// stop early if ctx ends or the circuit breaker opens. While the breaker is
+// open, send fails with ErrCircuitOpen without contacting the server.
+func (s *SMTPEmailService) send(ctx context.Context, email Email) error {
+	msg, err := s.buildMessage(email)
 	if err != nil {
-		return fmt.Errorf("failed to render verification email template: %w", err)
+		return Permanent(fmt.Errorf("failed to build email: %w", err))
 	}
 
-	textBody := fmt.Sprintf(`Hi %s,
-
-Welcome to Lukaut! Please verify your email address by clicking the link below:
-
-%s
+	var lastErr error
+	for attempt := 1; attempt <= s.config.MaxAttempts; attempt++ {
+		if !s.breaker.allow() {
+			s.logger.Warn("email not sent: SMTP circuit breaker open",
+				"to", email.To,
+				"subject", email.Subject,
+			)
+			return Retryable(ErrCircuitOpen)
+		}
 
-This link will expire in 24 hours.
+		err := s.attempt(ctx, email, msg)
+		if err == nil {
+			s.breaker.success()
+			s.logger.Info("email sent",
+				"to", email.To,
+				"subject", email.Subject,
+				"attempt", attempt,
+			)
+			return nil
+		}
 
-If you didn't create an account with Lukaut, you can safely ignore this email.
+		lastErr = classifySMTPError(fmt.Errorf("failed to send email: %w", err))
+		s.logger.Warn("email send attempt failed",
+			"to", email.To,
+			"subject", email.Subject,
+			"attempt", attempt,
+			"max_attempts", s.config.MaxAttempts,
+			"retryable", IsRetryable(lastErr),
+			"error", err,
+		)
 
-Thanks,
-The Lukaut Team
-`, name, verifyURL)
+		if !IsRetryable(lastErr) {
+			// The server answered, so it is up; retrying will not help
+			s.breaker.success()
+			break
+		}
+		if s.breaker.failure() {
+			s.logger.Error("SMTP circuit breaker opened",
+				"host", s.config.Host,
+				"cooldown", s.config.BreakerCooldown,
+			)
+			break
+		}
+		if attempt == s.config.MaxAttempts {
+			break
+		}
 
-	email := Email{
-		To:       to,
-		Subject:  "Verify your Lukaut account",
-		HTMLBody: htmlBody,
-		TextBody: textBody,
+		// Exponential backoff: base * 2^(attempt-1)
+		delay := s.config.RetryBaseDelay * time.Duration(1<<(attempt-1))
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return Retryable(ctx.Err())
+		}
 	}
 
-	return s.send(ctx, email)
+	s.logger.Error("failed to send email",
+		"to", email.To,
+		"subject", email.Subject,
+		"error", lastErr,
+	)
+	return lastErr
 }
 
-// SendPasswordResetEmail sends a password reset link to a user.
-func (s *SMTPEmailService) SendPasswordResetEmail(ctx context.Context, to, name, token string) error {
-	resetURL := fmt.Sprintf("%s/reset-password?token=%s", s.baseURL, token)
-
-	data := map[string]interface{}{
-		"Name":     name,
-		"ResetURL": resetURL,
-		"Year":     time.Now().Year(),
-	}
+// attempt makes one delivery attempt bounded by the send timeout.
+func (s *SMTPEmailService) attempt(ctx context.Context, email Email, msg []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+	return s.deliver(ctx, email, msg)
+}
 
-	htmlBody, err := s.renderTemplate("password_reset.html", data)
+// deliver runs the SMTP conversation for a single built message.
+//
+// This mirrors smtp.SendMail (including STARTTLS when offered) but dials
+// with the context and sets a connection deadline, so a hung server cannot
+// stall the caller past the send timeout.
+func (s *SMTPEmailService) deliver(ctx context.Context, email Email, msg []byte) error {
+	addr := net.JoinHostPort(s.config.Host, strconv.Itoa(s.config.Port))
+
+	dialer := net.Dialer{Timeout: s.config.DialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
 	if err != nil {
-		return fmt.Errorf("failed to render password reset email template: %w", err)
-	}
-
-	textBody := fmt.Sprintf(`Hi %s,
-
-We received a request to reset your password. Click the link below to choose a new password:
-
-%s
-
-This link will expire in 1 hour.
-
-If you didn't request a password reset, you can safely ignore this email. Your password will not be changed.
-
-Thanks,
-The Lukaut Team
-`, name, resetURL)
-
-	email := Email{
-		To:       to,
-		Subject:  "Reset your Lukaut password",
-		HTMLBody: htmlBody,
-		TextBody: textBody,
+		return err
 	}
-
-	return s.send(ctx, email)
-}
-
-// SendReportReadyEmail notifies a user that their inspection report is ready.
-func (s *SMTPEmailService) SendReportReadyEmail(ctx context.Context, to, name, reportURL string) error {
-	data := map[string]interface{}{
-		"Name":      name,
-		"ReportURL": reportURL,
-		"Year":      time.Now().Year(),
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
 	}
 
-	htmlBody, err := s.renderTemplate("report_ready.html", data)
+	client, err := smtp.NewClient(conn, s.config.Host)
 	if err != nil {
-		return fmt.Errorf("failed to render report ready email template: %w", err)
+		conn.Close()
+		return err
 	}
+	defer client.Close()
 
-	textBody := fmt.Sprintf(`Hi %s,
-
-Your inspection report is ready! You can download it here:
-
-%s
-
-Thanks,
-The Lukaut Team
-`, name, reportURL)
-
-	email := Email{
-		To:       to,
-		Subject:  "Your inspection report is ready",
-		HTMLBody: htmlBody,
-		TextBody: textBody,
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.config.Host}); err != nil {
+			return err
+		}
 	}
 
-	return s.send(ctx, email)
-}
-
-// SendReportToClientEmail sends an inspection report to a client.
-func (s *SMTPEmailService) SendReportToClientEmail(
-	ctx context.Context,
-	to, inspectorName, inspectorCompany, siteName, reportURL string,
-) error {
-	// Use inspector company if available, otherwise fall back to inspector name
-	fromEntity := inspectorCompany
-	if fromEntity == "" {
-		fromEntity = inspectorName
+	// Authenticate if credentials are provided (not needed for Mailhog)
+	if s.config.Username != "" && s.config.Password != "" {
+		auth := smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
 	}
 
-	data := map[string]interface{}{
-		"InspectorName":    inspectorName,
-		"InspectorCompany": inspectorCompany,
-		"FromEntity":       fromEntity,
-		"SiteName":         siteName,
-		"ReportURL":        reportURL,
-		"Year":             time.Now().Year(),
+	if err := client.Mail(s.config.From); err != nil {
+		return err
 	}
-
-	htmlBody, err := s.renderTemplate("report_to_client.html", data)
-	if err != nil {
-		// Fall back to plain text if template doesn't exist
-		s.logger.Warn("Failed to render report_to_client template, using plain text",
-			"error", err,
-		)
-		htmlBody = ""
+	if err := client.Rcpt(email.To); err != nil {
+		return err
 	}
-
-	textBody := fmt.Sprintf(`Hello,
-
-A safety inspection report for %s is now available for your review.
-
-Inspector: %s
-%s
-You can download the report here:
-
-%s
-
-If you have any questions about this report, please contact the inspector directly.
-
-Best regards,
-The Lukaut Team
-`, siteName, inspectorName, func() string {
-		if inspectorCompany != "" {
-			return "Company: " + inspectorCompany + "\n"
+	// Bcc is an envelope recipient only; it never appears in the headers
+	if email.Bcc != "" {
+		if err := client.Rcpt(email.Bcc); err != nil {
+			return err
 		}
-		return ""
-	}(), reportURL)
-
-	subject := fmt.Sprintf("Safety Inspection Report for %s", siteName)
-	if siteName == "" {
-		subject = "Your Safety Inspection Report is Ready"
 	}
 
-	email := Email{
-		To:       to,
-		Subject:  subject,
-		HTMLBody: htmlBody,
-		TextBody: textBody,
+	w, err := client.Data()
+	if err != nil {
+		return err
 	}
-
-	return s.send(ctx, email)
-}
-
-// =============================================================================
-// Internal Methods
-// =============================================================================
-
-// send sends an email via SMTP.
-func (s *SMTPEmailService) send(ctx context.Context, email Email) error {
-	// Build the email message
-	msg := s.buildMessage(email)
-
-	// Create SMTP address
-	addr := fmt.Sprintf("%s:%d", s.config.Host, s.config.Port)
-
-	// Create auth if credentials are provided (not needed for Mailhog)
-	var auth smtp.Auth
-	if s.config.Username != "" && s.config.Password != "" {
-		auth = smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)
+	if _, err := w.Write(msg); err != nil {
+		return err
 	}
-
-	// Send the email
-	err := smtp.SendMail(addr, auth, s.config.From, []string{email.To}, msg)
-	if err != nil {
-		s.logger.Error("failed to send email",
-			"to", email.To,
-			"subject", email.Subject,
-			"error", err,
-		)
-		return fmt.Errorf("failed to send email: %w", err)
+	if err := w.Close(); err != nil {
+		return err
 	}
 
-	s.logger.Info("email sent",
-		"to", email.To,
-		"subject", email.Subject,
-	)
-
-	return nil
-}
-
-// buildMessage constructs the raw email message with headers.
-func (s *SMTPEmailService) buildMessage(email Email) []byte {
-	var buf bytes.Buffer
-
-	// From header with display name
-	fromHeader := fmt.Sprintf("%s <%s>", s.config.FromName, s.config.From)
-
-	// Write headers
-	buf.WriteString(fmt.Sprintf("From: %s\r\n", fromHeader))
-	buf.WriteString(fmt.Sprintf("To: %s\r\n", email.To))
-	buf.WriteString(fmt.Sprintf("Subject: %s\r\n", email.Subject))
-	buf.WriteString("MIME-Version: 1.0\r\n")
-
-	// Create multipart message for HTML + text
-	boundary := "===============LUKAUT_BOUNDARY==============="
-	buf.WriteString(fmt.Sprintf("Content-Type: multipart/alternative; boundary=\"%s\"\r\n", boundary))
-	buf.WriteString("\r\n")
-
-	// Plain text part
-	buf.WriteString(fmt.Sprintf("--%s\r\n", boundary))
-	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
-	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n")
-	buf.WriteString("\r\n")
-	buf.WriteString(email.TextBody)
-	buf.WriteString("\r\n")
-
-	// HTML part
-	buf.WriteString(fmt.Sprintf("--%s\r\n", boundary))
-	buf.WriteString("Content-Type: text/html; charset=utf-8\r\n")
-	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n")
-	buf.WriteString("\r\n")
-	buf.WriteString(email.HTMLBody)
-	buf.WriteString("\r\n")
-
-	// End boundary
-	buf.WriteString(fmt.Sprintf("--%s--\r\n", boundary))
-
-	return buf.Bytes()
+	return client.Quit()
 }
 
-// renderTemplate renders an email template with the given data.
-func (s *SMTPEmailService) renderTemplate(name string, data interface{}) (string, error) {
-	var buf bytes.Buffer
-	if err := s.templates.ExecuteTemplate(&buf, name, data); err != nil {
-		return "", err
+// classifySMTPError marks permanent (5xx) SMTP replies, such as rejected
+// recipients or failed authentication, as permanent. Transient (4xx) replies
+// and connection errors are retryable.
+func classifySMTPError(err error) error {
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) && protoErr.Code >= 500 {
+		return Permanent(err)
 	}
-	return buf.String(), nil
+	return Retryable(err)
 }
 
-// =============================================================================
-// Template Functions
-// =============================================================================
-
-// emailTemplateFuncs returns template functions available in email templates.
-func emailTemplateFuncs() template.FuncMap {
-	return template.FuncMap{
-		"safeHTML": func(s string) template.HTML {
-			return template.HTML(s)
-		},
-		"currentYear": func() int {
-			return time.Now().Year()
-		},
-	}
+// buildMessage constructs the raw email message with headers and a
+// multipart/alternative body holding the text and HTML versions.
+func (s *SMTPEmailService) buildMessage(email Email) ([]byte, error) {
+	// The address is always the configured sender, which also stays the
+	// envelope sender in deliver.
+	return formatMessage(email, s.config.FromName, s.config.From)
 }
 
 // =============================================================================
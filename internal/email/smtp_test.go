@@ -0,0 +1,316 @@
+package email
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"net/textproto"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// =============================================================================
+// Fake SMTP Server
+// =============================================================================
+
+// smtpCapture is what the fake server received for one message.
+type smtpCapture struct {
+	mailFrom string
+	rcptTo   []string
+	data     string
+}
+
+// startFakeSMTPServer accepts one SMTP conversation and sends what it
+// received on the returned channel. It offers no extensions, so the client
+// neither starts TLS nor authenticates.
+func startFakeSMTPServer(t *testing.T) (host string, port int, received <-chan smtpCapture) {
+	t.Helper()
+	host, port, received, _ = startFlakySMTPServer(t, 0, "")
+	return host, port, received
+}
+
+// startFlakySMTPServer is startFakeSMTPServer for a server that greets the
+// first failures connections with greeting and hangs up, then accepts
+// messages. It also returns a count of connections made.
+func startFlakySMTPServer(t *testing.T, failures int, greeting string) (host string, port int, received <-chan smtpCapture, connections *atomic.Int32) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	ch := make(chan smtpCapture, 1)
+	connections = &atomic.Int32{}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			if int(connections.Add(1)) <= failures {
+				_ = textproto.NewConn(conn).PrintfLine("%s", greeting)
+				conn.Close()
+				continue
+			}
+			serveFakeSMTP(conn, ch)
+		}
+	}()
+
+	addr := ln.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port, ch, connections
+}
+
+// serveFakeSMTP runs one SMTP conversation and sends what it received on ch.
+func serveFakeSMTP(conn net.Conn, ch chan<- smtpCapture) {
+	defer conn.Close()
+	tp := textproto.NewConn(conn)
+	var got smtpCapture
+
+	_ = tp.PrintfLine("220 fake ESMTP")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			_ = tp.PrintfLine("250 fake")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			got.mailFrom = strings.Trim(line[len("MAIL FROM:"):], "<> ")
+			_ = tp.PrintfLine("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			got.rcptTo = append(got.rcptTo, strings.Trim(line[len("RCPT TO:"):], "<> "))
+			_ = tp.PrintfLine("250 OK")
+		case cmd == "DATA":
+			_ = tp.PrintfLine("354 go ahead")
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			got.data = string(data)
+			_ = tp.PrintfLine("250 OK")
+		case cmd == "QUIT":
+			_ = tp.PrintfLine("221 bye")
+			ch <- got
+			return
+		default:
+			_ = tp.PrintfLine("502 not implemented")
+		}
+	}
+}
+
+func newTestSMTPService(t *testing.T, host string, port int) *SMTPEmailService {
+	t.Helper()
+	return newTestSMTPServiceWithConfig(t, SMTPConfig{Host: host, Port: port})
+}
+
+// newTestSMTPServiceWithConfig creates a service for config, filling in the
+// sender, a short timeout, and a fast retry delay.
+func newTestSMTPServiceWithConfig(t *testing.T, config SMTPConfig) *SMTPEmailService {
+	t.Helper()
+	config.From = "noreply@example.com"
+	config.FromName = "Lukaut"
+	config.Timeout = 2 * time.Second
+	if config.RetryBaseDelay == 0 {
+		config.RetryBaseDelay = time.Millisecond
+	}
+	svc, err := NewSMTPEmailService(config, "https://app.example.com", testTemplatesDir, newTestLogger())
+	if err != nil {
+		t.Fatalf("failed to create smtp service: %v", err)
+	}
+	return svc
+}
+
+// headers parses the header block of a raw message.
+func headers(t *testing.T, raw string) textproto.MIMEHeader {
+	t.Helper()
+	h, err := textproto.NewReader(bufio.NewReader(strings.NewReader(raw))).ReadMIMEHeader()
+	if err != nil {
+		t.Fatalf("failed to parse headers: %v", err)
+	}
+	return h
+}
+
+// =============================================================================
+// SMTP Tests
+// =============================================================================
+
+func TestSMTP_ReportToClientUsesFirmNameAndReplyTo(t *testing.T) {
+	host, port, received := startFakeSMTPServer(t)
+	svc := newTestSMTPService(t, host, port)
+
+	err := svc.SendReportToClientEmail(context.Background(),
+		"client@example.com", "Pat Inspector", "Acme Safety", "Main St", "https://app.example.com/r/1",
+		"pat@example.com", "office@acmesafety.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := <-received
+	if got.mailFrom != "noreply@example.com" {
+		t.Errorf("expected the envelope sender to stay the configured address, got %q", got.mailFrom)
+	}
+	h := headers(t, got.data)
+	if from := h.Get("From"); from != "Acme Safety <noreply@example.com>" {
+		t.Errorf("expected the firm's name on the configured address, got %q", from)
+	}
+	if replyTo := h.Get("Reply-To"); replyTo != "<office@acmesafety.com>" {
+		t.Errorf("expected Reply-To to be the firm's address, got %q", replyTo)
+	}
+	if bcc := h.Get("Bcc"); bcc != "" {
+		t.Errorf("expected no Bcc header, got %q", bcc)
+	}
+}
+
+func TestSMTP_DefaultSenderWithoutOverrides(t *testing.T) {
+	host, port, received := startFakeSMTPServer(t)
+	svc := newTestSMTPService(t, host, port)
+
+	if err := svc.SendPasswordResetEmail(context.Background(), "pat@example.com", "Pat", "tok_123"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := <-received
+	h := headers(t, got.data)
+	if from := h.Get("From"); from != "Lukaut <noreply@example.com>" {
+		t.Errorf("expected the default sender, got %q", from)
+	}
+	if _, ok := h["Reply-To"]; ok {
+		t.Errorf("expected no Reply-To header, got %q", h.Get("Reply-To"))
+	}
+}
+
+func TestBuildMessage_QuotesAndEncodesDisplayNames(t *testing.T) {
+	svc := &SMTPEmailService{config: SMTPConfig{From: "noreply@example.com", FromName: "Lukaut"}}
+
+	tests := []struct {
+		name     string
+		fromName string
+		replyTo  string
+		wantFrom string
+		wantRTo  string
+	}{
+		{
+			name:     "punctuation is quoted",
+			fromName: "Smith, Jones & Co.",
+			wantFrom: `"Smith, Jones & Co." <noreply@example.com>`,
+		},
+		{
+			name:     "header injection is encoded",
+			fromName: "Acme\r\nBcc: victim@example.com",
+			wantFrom: "=?utf-8?b?QWNtZQ0KQmNjOiB2aWN0aW1AZXhhbXBsZS5jb20=?= <noreply@example.com>",
+		},
+		{
+			name:     "malformed reply-to is dropped",
+			fromName: "Acme",
+			replyTo:  "not an address\r\nX-Evil: 1",
+			wantFrom: "Acme <noreply@example.com>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := svc.buildMessage(Email{To: "client@example.com", FromName: tt.fromName, ReplyTo: tt.replyTo, Subject: "Hi"})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			h := headers(t, string(msg))
+			if from := h.Get("From"); from != tt.wantFrom {
+				t.Errorf("expected From %q, got %q", tt.wantFrom, from)
+			}
+			if rto := h.Get("Reply-To"); rto != tt.wantRTo {
+				t.Errorf("expected Reply-To %q, got %q", tt.wantRTo, rto)
+			}
+			if h.Get("Bcc") != "" || h.Get("X-Evil") != "" {
+				t.Error("expected no injected headers")
+			}
+		})
+	}
+}
+
+func TestSMTP_RetriesTransientFailures(t *testing.T) {
+	host, port, received, connections := startFlakySMTPServer(t, 2, "421 try again later")
+	svc := newTestSMTPServiceWithConfig(t, SMTPConfig{Host: host, Port: port, MaxAttempts: 3})
+
+	if err := svc.SendPasswordResetEmail(context.Background(), "pat@example.com", "Pat", "tok_123"); err != nil {
+		t.Fatalf("expected the third attempt to succeed, got %v", err)
+	}
+
+	got := <-received
+	if len(got.rcptTo) != 1 || got.rcptTo[0] != "pat@example.com" {
+		t.Errorf("expected delivery to pat@example.com, got %v", got.rcptTo)
+	}
+	if n := connections.Load(); n != 3 {
+		t.Errorf("expected 3 attempts, got %d", n)
+	}
+}
+
+func TestSMTP_GivesUpAfterMaxAttempts(t *testing.T) {
+	host, port, _, connections := startFlakySMTPServer(t, 5, "421 try again later")
+	svc := newTestSMTPServiceWithConfig(t, SMTPConfig{Host: host, Port: port, MaxAttempts: 2})
+
+	err := svc.SendPasswordResetEmail(context.Background(), "pat@example.com", "Pat", "tok_123")
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if !IsRetryable(err) {
+		t.Errorf("expected a retryable error, got %v", err)
+	}
+	if n := connections.Load(); n != 2 {
+		t.Errorf("expected 2 attempts, got %d", n)
+	}
+}
+
+func TestSMTP_DoesNotRetryPermanentFailures(t *testing.T) {
+	host, port, _, connections := startFlakySMTPServer(t, 5, "554 no service")
+	svc := newTestSMTPServiceWithConfig(t, SMTPConfig{Host: host, Port: port, MaxAttempts: 3})
+
+	err := svc.SendPasswordResetEmail(context.Background(), "pat@example.com", "Pat", "tok_123")
+	if err == nil || IsRetryable(err) {
+		t.Fatalf("expected a permanent error, got %v", err)
+	}
+	if n := connections.Load(); n != 1 {
+		t.Errorf("expected 1 attempt, got %d", n)
+	}
+}
+
+func TestSMTP_CircuitBreakerStopsSends(t *testing.T) {
+	host, port, received, connections := startFlakySMTPServer(t, 2, "421 try again later")
+	svc := newTestSMTPServiceWithConfig(t, SMTPConfig{
+		Host:             host,
+		Port:             port,
+		MaxAttempts:      3,
+		BreakerThreshold: 2,
+		BreakerCooldown:  time.Minute,
+	})
+	now := time.Now()
+	svc.breaker.now = func() time.Time { return now }
+
+	// The second failure opens the breaker, ending the first send early
+	if err := svc.SendPasswordResetEmail(context.Background(), "pat@example.com", "Pat", "tok_123"); err == nil {
+		t.Fatal("expected the first send to fail")
+	}
+	if n := connections.Load(); n != 2 {
+		t.Fatalf("expected 2 attempts before the breaker opened, got %d", n)
+	}
+
+	err := svc.SendPasswordResetEmail(context.Background(), "pat@example.com", "Pat", "tok_123")
+	if !errors.Is(err, ErrCircuitOpen) || !IsRetryable(err) {
+		t.Fatalf("expected a retryable ErrCircuitOpen, got %v", err)
+	}
+	if n := connections.Load(); n != 2 {
+		t.Errorf("expected no attempt while the breaker is open, got %d connections", n)
+	}
+
+	// After the cooldown a trial send goes through and closes the breaker
+	now = now.Add(time.Minute)
+	if err := svc.SendPasswordResetEmail(context.Background(), "pat@example.com", "Pat", "tok_123"); err != nil {
+		t.Fatalf("expected the send after the cooldown to succeed, got %v", err)
+	}
+	<-received
+}
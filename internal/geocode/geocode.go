@@ -0,0 +1,78 @@
+// Package geocode validates and completes postal addresses.
+//
+// Geocoder is the extension point for an address provider. The default,
+// Noop, recognizes nothing, so addresses are stored exactly as entered and
+// the autocomplete shows no suggestions. New selects a provider from
+// configuration.
+package geocode
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// ErrNoMatch is returned by Validate when the address cannot be located.
+var ErrNoMatch = errors.New("geocode: no match for address")
+
+// ErrRateLimited is returned when the provider rejects a request for
+// exceeding its rate limit. Callers should retry later with backoff.
+var ErrRateLimited = errors.New("geocode: provider rate limit exceeded")
+
+// Suggestion is a candidate address offered by autocomplete.
+type Suggestion struct {
+	Label        string // Full address for display
+	AddressLine1 string // Street address
+	City         string
+	State        string // Two-letter state code
+	PostalCode   string
+	Latitude     float64
+	Longitude    float64
+}
+
+// Geocoder looks up addresses.
+//
+// Implementations:
+// - Noop: recognizes nothing (default)
+// - Nominatim: OpenStreetMap Nominatim search API
+// - Google: Google Maps Geocoding API
+type Geocoder interface {
+	// Validate locates address and returns its normalized form and
+	// coordinates. It returns ErrNoMatch if the address is not recognized.
+	Validate(ctx context.Context, address string) (normalized string, lat, lng float64, err error)
+
+	// Suggest returns up to limit addresses matching a partial query.
+	Suggest(ctx context.Context, query string, limit int) ([]Suggestion, error)
+}
+
+// Noop is a Geocoder that recognizes no addresses.
+type Noop struct{}
+
+// Validate always returns ErrNoMatch.
+func (Noop) Validate(ctx context.Context, address string) (string, float64, float64, error) {
+	return "", 0, 0, ErrNoMatch
+}
+
+// Suggest always returns no suggestions.
+func (Noop) Suggest(ctx context.Context, query string, limit int) ([]Suggestion, error) {
+	return nil, nil
+}
+
+var _ Geocoder = Noop{}
+
+// FormatAddress joins address fields into the single-line form passed to
+// Validate, e.g. "100 Main St, Suite 2, Boise, ID 83702". Empty fields and
+// extra whitespace are dropped.
+func FormatAddress(line1, line2, city, state, postalCode string) string {
+	var parts []string
+	for _, p := range []string{line1, line2, city} {
+		if p = strings.Join(strings.Fields(p), " "); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	stateZip := strings.TrimSpace(strings.Join(strings.Fields(state), " ") + " " + strings.Join(strings.Fields(postalCode), " "))
+	if stateZip != "" {
+		parts = append(parts, stateZip)
+	}
+	return strings.Join(parts, ", ")
+}
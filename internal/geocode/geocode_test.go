@@ -0,0 +1,61 @@
+package geocode
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestFormatAddress(t *testing.T) {
+	tests := []struct {
+		name                                  string
+		line1, line2, city, state, postalCode string
+		want                                  string
+	}{
+		{
+			name:  "all fields",
+			line1: "100 Main St", line2: "Suite 2", city: "Boise", state: "ID", postalCode: "83702",
+			want: "100 Main St, Suite 2, Boise, ID 83702",
+		},
+		{
+			name:  "no line 2",
+			line1: "100 Main St", city: "Boise", state: "ID", postalCode: "83702",
+			want: "100 Main St, Boise, ID 83702",
+		},
+		{
+			name:  "collapses whitespace",
+			line1: "  100   Main St ", line2: "   ", city: " Boise", state: "ID ", postalCode: " 83702",
+			want: "100 Main St, Boise, ID 83702",
+		},
+		{
+			name:  "missing postal code",
+			line1: "100 Main St", city: "Boise", state: "ID",
+			want: "100 Main St, Boise, ID",
+		},
+		{
+			name: "empty",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatAddress(tt.line1, tt.line2, tt.city, tt.state, tt.postalCode); got != tt.want {
+				t.Errorf("FormatAddress() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNoop(t *testing.T) {
+	ctx := context.Background()
+
+	if _, _, _, err := (Noop{}).Validate(ctx, "100 Main St, Boise, ID 83702"); !errors.Is(err, ErrNoMatch) {
+		t.Errorf("expected ErrNoMatch, got %v", err)
+	}
+
+	suggestions, err := Noop{}.Suggest(ctx, "100 Main", 5)
+	if err != nil || len(suggestions) != 0 {
+		t.Errorf("expected no suggestions, got %v, %v", suggestions, err)
+	}
+}
@@ -0,0 +1,175 @@
+package geocode
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// =============================================================================
+// Google Geocoder
+// =============================================================================
+
+// DefaultGoogleURL is the Google Maps Geocoding API endpoint.
+const DefaultGoogleURL = "https://maps.googleapis.com/maps/api/geocode/json"
+
+// GoogleConfig configures the Google geocoder.
+type GoogleConfig struct {
+	APIKey  string        // Required: Google Maps Platform API key
+	BaseURL string        // Endpoint (default: DefaultGoogleURL)
+	Timeout time.Duration // Per-request timeout (default: DefaultRequestTimeout)
+}
+
+// Google geocodes US addresses with the Google Maps Geocoding API.
+type Google struct {
+	config GoogleConfig
+	client *http.Client
+}
+
+// NewGoogle creates a Google geocoder.
+func NewGoogle(config GoogleConfig) *Google {
+	if config.BaseURL == "" {
+		config.BaseURL = DefaultGoogleURL
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultRequestTimeout
+	}
+	return &Google{config: config, client: &http.Client{}}
+}
+
+var _ Geocoder = (*Google)(nil)
+
+// googleResponse is the Geocoding API response body.
+type googleResponse struct {
+	Status       string         `json:"status"`
+	ErrorMessage string         `json:"error_message"`
+	Results      []googleResult `json:"results"`
+}
+
+type googleResult struct {
+	AddressComponents []struct {
+		LongName  string   `json:"long_name"`
+		ShortName string   `json:"short_name"`
+		Types     []string `json:"types"`
+	} `json:"address_components"`
+	Geometry struct {
+		Location struct {
+			Lat float64 `json:"lat"`
+			Lng float64 `json:"lng"`
+		} `json:"location"`
+	} `json:"geometry"`
+}
+
+// Validate returns the best match for address.
+func (g *Google) Validate(ctx context.Context, address string) (string, float64, float64, error) {
+	results, err := g.geocode(ctx, address)
+	if err != nil {
+		return "", 0, 0, err
+	}
+	if len(results) == 0 {
+		return "", 0, 0, ErrNoMatch
+	}
+
+	s := results[0].suggestion()
+	return s.Label, s.Latitude, s.Longitude, nil
+}
+
+// Suggest returns up to limit matches for a partial address.
+func (g *Google) Suggest(ctx context.Context, query string, limit int) ([]Suggestion, error) {
+	results, err := g.geocode(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var suggestions []Suggestion
+	for _, r := range results {
+		if len(suggestions) == limit {
+			break
+		}
+		suggestions = append(suggestions, r.suggestion())
+	}
+	return suggestions, nil
+}
+
+// geocode calls the API for US addresses matching address. ZERO_RESULTS
+// returns no results rather than an error.
+func (g *Google) geocode(ctx context.Context, address string) ([]googleResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, g.config.Timeout)
+	defer cancel()
+
+	params := url.Values{
+		"address":    {address},
+		"components": {"country:US"},
+		"key":        {g.config.APIKey},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.config.BaseURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("google geocode: build request: %w", err)
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		// The error includes the URL, which contains the API key
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			err = uerr.Err
+		}
+		return nil, fmt.Errorf("google geocode: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, fmt.Errorf("google geocode: %w", ErrRateLimited)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("google geocode: unexpected status %d", resp.StatusCode)
+	}
+
+	var body googleResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("google geocode: decode response: %w", err)
+	}
+
+	switch body.Status {
+	case "OK":
+		return body.Results, nil
+	case "ZERO_RESULTS":
+		return nil, nil
+	case "OVER_QUERY_LIMIT":
+		return nil, fmt.Errorf("google geocode: %w", ErrRateLimited)
+	default:
+		return nil, fmt.Errorf("google geocode: %s: %s", body.Status, body.ErrorMessage)
+	}
+}
+
+// suggestion converts a result to a Suggestion with a label in FormatAddress form.
+func (r googleResult) suggestion() Suggestion {
+	var number, street string
+	s := Suggestion{
+		Latitude:  r.Geometry.Location.Lat,
+		Longitude: r.Geometry.Location.Lng,
+	}
+	for _, c := range r.AddressComponents {
+		for _, t := range c.Types {
+			switch t {
+			case "street_number":
+				number = c.LongName
+			case "route":
+				street = c.ShortName
+			case "locality":
+				s.City = c.LongName
+			case "administrative_area_level_1":
+				s.State = c.ShortName
+			case "postal_code":
+				s.PostalCode = c.LongName
+			}
+		}
+	}
+	s.AddressLine1 = FormatAddress(number+" "+street, "", "", "", "")
+	s.Label = FormatAddress(s.AddressLine1, "", s.City, s.State, s.PostalCode)
+	return s
+}
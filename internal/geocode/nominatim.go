@@ -0,0 +1,164 @@
+package geocode
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// Nominatim Geocoder
+// =============================================================================
+
+// DefaultNominatimURL is the public OpenStreetMap Nominatim instance.
+const DefaultNominatimURL = "https://nominatim.openstreetmap.org"
+
+// NominatimMinInterval is the spacing the public instance's usage policy
+// requires between requests (at most one per second).
+const NominatimMinInterval = time.Second
+
+// DefaultRequestTimeout bounds a single provider request.
+const DefaultRequestTimeout = 10 * time.Second
+
+// NominatimConfig configures the Nominatim geocoder.
+type NominatimConfig struct {
+	BaseURL   string        // Instance URL (default: DefaultNominatimURL)
+	UserAgent string        // Identifies the application, as the usage policy requires
+	Timeout   time.Duration // Per-request timeout (default: DefaultRequestTimeout)
+}
+
+// Nominatim geocodes US addresses with the Nominatim /search API.
+//
+// Suggest returns no suggestions: the public instance's usage policy forbids
+// autocomplete. Wrap with Throttle to respect the one-request-per-second
+// limit.
+type Nominatim struct {
+	config NominatimConfig
+	client *http.Client
+}
+
+// NewNominatim creates a Nominatim geocoder.
+func NewNominatim(config NominatimConfig) *Nominatim {
+	if config.BaseURL == "" {
+		config.BaseURL = DefaultNominatimURL
+	}
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultRequestTimeout
+	}
+	return &Nominatim{config: config, client: &http.Client{}}
+}
+
+var _ Geocoder = (*Nominatim)(nil)
+
+// nominatimPlace is one /search result with addressdetails=1.
+type nominatimPlace struct {
+	Lat     string `json:"lat"`
+	Lon     string `json:"lon"`
+	Address struct {
+		HouseNumber string `json:"house_number"`
+		Road        string `json:"road"`
+		City        string `json:"city"`
+		Town        string `json:"town"`
+		Village     string `json:"village"`
+		StateCode   string `json:"ISO3166-2-lvl4"` // e.g. "US-ID"
+		Postcode    string `json:"postcode"`
+	} `json:"address"`
+}
+
+// Validate returns the best match for address.
+func (n *Nominatim) Validate(ctx context.Context, address string) (string, float64, float64, error) {
+	places, err := n.search(ctx, address, 1)
+	if err != nil {
+		return "", 0, 0, err
+	}
+	if len(places) == 0 {
+		return "", 0, 0, ErrNoMatch
+	}
+
+	s, err := places[0].suggestion()
+	if err != nil {
+		return "", 0, 0, err
+	}
+	return s.Label, s.Latitude, s.Longitude, nil
+}
+
+// Suggest always returns no suggestions; see Nominatim.
+func (n *Nominatim) Suggest(ctx context.Context, query string, limit int) ([]Suggestion, error) {
+	return nil, nil
+}
+
+// search calls /search for US addresses matching query.
+func (n *Nominatim) search(ctx context.Context, query string, limit int) ([]nominatimPlace, error) {
+	ctx, cancel := context.WithTimeout(ctx, n.config.Timeout)
+	defer cancel()
+
+	params := url.Values{
+		"q":              {query},
+		"format":         {"jsonv2"},
+		"addressdetails": {"1"},
+		"countrycodes":   {"us"},
+		"limit":          {strconv.Itoa(limit)},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, n.config.BaseURL+"/search?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("nominatim: build request: %w", err)
+	}
+	req.Header.Set("User-Agent", n.config.UserAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("nominatim: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return nil, fmt.Errorf("nominatim: %w", ErrRateLimited)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("nominatim: unexpected status %d", resp.StatusCode)
+	}
+
+	var places []nominatimPlace
+	if err := json.NewDecoder(resp.Body).Decode(&places); err != nil {
+		return nil, fmt.Errorf("nominatim: decode response: %w", err)
+	}
+	return places, nil
+}
+
+// suggestion converts a place to a Suggestion with a label in FormatAddress form.
+func (p nominatimPlace) suggestion() (Suggestion, error) {
+	lat, err := strconv.ParseFloat(p.Lat, 64)
+	if err != nil {
+		return Suggestion{}, fmt.Errorf("nominatim: invalid latitude %q", p.Lat)
+	}
+	lng, err := strconv.ParseFloat(p.Lon, 64)
+	if err != nil {
+		return Suggestion{}, fmt.Errorf("nominatim: invalid longitude %q", p.Lon)
+	}
+
+	a := p.Address
+	city := a.City
+	if city == "" {
+		city = a.Town
+	}
+	if city == "" {
+		city = a.Village
+	}
+	s := Suggestion{
+		AddressLine1: strings.TrimSpace(a.HouseNumber + " " + a.Road),
+		City:         city,
+		State:        strings.TrimPrefix(a.StateCode, "US-"),
+		PostalCode:   a.Postcode,
+		Latitude:     lat,
+		Longitude:    lng,
+	}
+	s.Label = FormatAddress(s.AddressLine1, "", s.City, s.State, s.PostalCode)
+	return s, nil
+}
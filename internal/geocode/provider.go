@@ -0,0 +1,39 @@
+package geocode
+
+import (
+	"fmt"
+)
+
+// Provider names accepted by New.
+const (
+	ProviderNone      = "none"
+	ProviderNominatim = "nominatim"
+	ProviderGoogle    = "google"
+)
+
+// Config selects and configures a geocoding provider.
+type Config struct {
+	Provider  string // ProviderNone (default), ProviderNominatim, or ProviderGoogle
+	APIKey    string // Required for Google
+	URL       string // Optional endpoint override, e.g. a self-hosted Nominatim
+	UserAgent string // Sent to Nominatim to identify the application
+}
+
+// New returns the geocoder for cfg.Provider. Nominatim is throttled to
+// NominatimMinInterval.
+func New(cfg Config) (Geocoder, error) {
+	switch cfg.Provider {
+	case "", ProviderNone:
+		return Noop{}, nil
+	case ProviderNominatim:
+		n := NewNominatim(NominatimConfig{BaseURL: cfg.URL, UserAgent: cfg.UserAgent})
+		return Throttle(n, NominatimMinInterval), nil
+	case ProviderGoogle:
+		if cfg.APIKey == "" {
+			return nil, fmt.Errorf("geocode: API key is required for the google provider")
+		}
+		return NewGoogle(GoogleConfig{APIKey: cfg.APIKey, BaseURL: cfg.URL}), nil
+	default:
+		return nil, fmt.Errorf("geocode: unknown provider %q", cfg.Provider)
+	}
+}
@@ -0,0 +1,204 @@
+package geocode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNominatimValidate(t *testing.T) {
+	var gotQuery, gotUserAgent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.Query().Get("q")
+		gotUserAgent = r.Header.Get("User-Agent")
+		if strings.Contains(gotQuery, "Nowhere") {
+			_, _ = w.Write([]byte(`[]`))
+			return
+		}
+		_, _ = w.Write([]byte(`[{"lat":"43.6150","lon":"-116.2023","address":{"house_number":"100","road":"Main Street","city":"Boise","ISO3166-2-lvl4":"US-ID","postcode":"83702"}}]`))
+	}))
+	defer srv.Close()
+
+	n := NewNominatim(NominatimConfig{BaseURL: srv.URL + "/", UserAgent: "Lukaut (+https://app.example.com)"})
+
+	label, lat, lng, err := n.Validate(context.Background(), "100 Main St, Boise, ID 83702")
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if label != "100 Main Street, Boise, ID 83702" || lat != 43.6150 || lng != -116.2023 {
+		t.Errorf("unexpected match: %q %v %v", label, lat, lng)
+	}
+	if gotQuery != "100 Main St, Boise, ID 83702" || gotUserAgent != "Lukaut (+https://app.example.com)" {
+		t.Errorf("unexpected request: q=%q user-agent=%q", gotQuery, gotUserAgent)
+	}
+
+	if _, _, _, err := n.Validate(context.Background(), "9 Nowhere Rd"); !errors.Is(err, ErrNoMatch) {
+		t.Errorf("expected ErrNoMatch, got %v", err)
+	}
+}
+
+func TestNominatimValidate_RateLimited(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	_, _, _, err := NewNominatim(NominatimConfig{BaseURL: srv.URL}).Validate(context.Background(), "100 Main St")
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("expected ErrRateLimited, got %v", err)
+	}
+}
+
+func TestGoogleValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		status  int
+		wantErr error
+	}{
+		{
+			name: "match",
+			body: `{"status":"OK","results":[{"address_components":[
+				{"long_name":"100","short_name":"100","types":["street_number"]},
+				{"long_name":"Main Street","short_name":"Main St","types":["route"]},
+				{"long_name":"Boise","short_name":"Boise","types":["locality","political"]},
+				{"long_name":"Idaho","short_name":"ID","types":["administrative_area_level_1","political"]},
+				{"long_name":"83702","short_name":"83702","types":["postal_code"]}
+			],"geometry":{"location":{"lat":43.615,"lng":-116.2023}}}]}`,
+		},
+		{name: "zero results", body: `{"status":"ZERO_RESULTS","results":[]}`, wantErr: ErrNoMatch},
+		{name: "over query limit", body: `{"status":"OVER_QUERY_LIMIT"}`, wantErr: ErrRateLimited},
+		{name: "http 429", status: http.StatusTooManyRequests, wantErr: ErrRateLimited},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("key") != "test-key" {
+					t.Errorf("expected API key in request")
+				}
+				if tt.status != 0 {
+					w.WriteHeader(tt.status)
+					return
+				}
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			g := NewGoogle(GoogleConfig{APIKey: "test-key", BaseURL: srv.URL})
+			label, lat, lng, err := g.Validate(context.Background(), "100 Main St, Boise, ID")
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Validate failed: %v", err)
+			}
+			if label != "100 Main St, Boise, ID 83702" || lat != 43.615 || lng != -116.2023 {
+				t.Errorf("unexpected match: %q %v %v", label, lat, lng)
+			}
+		})
+	}
+}
+
+// countingGeocoder records call times.
+type countingGeocoder struct {
+	Noop
+	mu    sync.Mutex
+	calls []time.Time
+}
+
+func (g *countingGeocoder) Validate(ctx context.Context, address string) (string, float64, float64, error) {
+	g.mu.Lock()
+	g.calls = append(g.calls, time.Now())
+	g.mu.Unlock()
+	return g.Noop.Validate(ctx, address)
+}
+
+func TestThrottle(t *testing.T) {
+	const interval = 20 * time.Millisecond
+	inner := &countingGeocoder{}
+	g := Throttle(inner, interval)
+
+	var wg sync.WaitGroup
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, _, _ = g.Validate(context.Background(), "100 Main St")
+		}()
+	}
+	wg.Wait()
+
+	if len(inner.calls) != 3 {
+		t.Fatalf("expected 3 calls, got %d", len(inner.calls))
+	}
+	first, last := inner.calls[0], inner.calls[0]
+	for _, c := range inner.calls {
+		if c.Before(first) {
+			first = c
+		}
+		if c.After(last) {
+			last = c
+		}
+	}
+	if spread := last.Sub(first); spread < 2*interval-time.Millisecond {
+		t.Errorf("expected calls spaced %v apart, spread was %v", interval, spread)
+	}
+}
+
+func TestThrottle_ContextCanceled(t *testing.T) {
+	inner := &countingGeocoder{}
+	g := Throttle(inner, time.Hour)
+	_, _, _, _ = g.Validate(context.Background(), "first")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, _, err := g.Validate(ctx, "second"); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if len(inner.calls) != 1 {
+		t.Errorf("expected the waiting call to be dropped, got %d calls", len(inner.calls))
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		cfg     Config
+		want    string
+		wantErr bool
+	}{
+		{cfg: Config{}, want: "geocode.Noop"},
+		{cfg: Config{Provider: ProviderNone}, want: "geocode.Noop"},
+		{cfg: Config{Provider: ProviderNominatim}, want: "*geocode.Throttled"},
+		{cfg: Config{Provider: ProviderGoogle, APIKey: "key"}, want: "*geocode.Google"},
+		{cfg: Config{Provider: ProviderGoogle}, wantErr: true},
+		{cfg: Config{Provider: "mapbox"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		g, err := New(tt.cfg)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("New(%+v): expected error", tt.cfg)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("New(%+v): %v", tt.cfg, err)
+			continue
+		}
+		if got := fmt.Sprintf("%T", g); got != tt.want {
+			t.Errorf("New(%+v) = %s, want %s", tt.cfg, got, tt.want)
+		}
+	}
+}
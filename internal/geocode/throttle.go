@@ -0,0 +1,67 @@
+package geocode
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Throttled spaces calls to a Geocoder at least an interval apart, for
+// providers with a request-rate policy. Calls wait their turn in order; a
+// caller whose context ends while waiting gives up its turn with the
+// context's error. The limit applies per process.
+type Throttled struct {
+	next     Geocoder
+	interval time.Duration
+
+	mu   sync.Mutex
+	last time.Time // Time reserved for the most recent call
+}
+
+// Throttle wraps g so calls are at least interval apart.
+func Throttle(g Geocoder, interval time.Duration) *Throttled {
+	return &Throttled{next: g, interval: interval}
+}
+
+var _ Geocoder = (*Throttled)(nil)
+
+// Validate waits for a turn, then calls the wrapped geocoder.
+func (t *Throttled) Validate(ctx context.Context, address string) (string, float64, float64, error) {
+	if err := t.wait(ctx); err != nil {
+		return "", 0, 0, err
+	}
+	return t.next.Validate(ctx, address)
+}
+
+// Suggest waits for a turn, then calls the wrapped geocoder.
+func (t *Throttled) Suggest(ctx context.Context, query string, limit int) ([]Suggestion, error) {
+	if err := t.wait(ctx); err != nil {
+		return nil, err
+	}
+	return t.next.Suggest(ctx, query, limit)
+}
+
+// wait reserves the next free time slot and sleeps until it.
+func (t *Throttled) wait(ctx context.Context) error {
+	t.mu.Lock()
+	now := time.Now()
+	slot := t.last.Add(t.interval)
+	if slot.Before(now) {
+		slot = now
+	}
+	t.last = slot
+	t.mu.Unlock()
+
+	delay := time.Until(slot)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -1,30 +1,69 @@
 package handler
 
 import (
+	"database/sql"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/DukeRupert/lukaut/internal/auth"
 	"github.com/DukeRupert/lukaut/internal/domain"
 	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/DukeRupert/lukaut/internal/service"
+	"github.com/DukeRupert/lukaut/internal/templ/components/pagination"
 	"github.com/DukeRupert/lukaut/internal/templ/pages/admin"
 	"github.com/google/uuid"
 )
 
+const (
+	// adminJobsLimit is the number of recent jobs shown on the admin jobs page.
+	adminJobsLimit = 100
+
+	// adminUsersPerPage is the number of users on each page of the admin
+	// users list.
+	adminUsersPerPage = 50
+
+	// adminUsageMonths is the number of calendar months, including the
+	// current one, shown on the admin AI usage page.
+	adminUsageMonths = 6
+)
+
 // AdminHandler handles admin panel HTTP requests.
 type AdminHandler struct {
-	repo   *repository.Queries
-	logger *slog.Logger
+	repo              *repository.Queries
+	userService       service.UserService
+	auditService      service.AuditService
+	regulationImports service.RegulationImportService
+	logger            *slog.Logger
 }
 
 // NewAdminHandler creates a new AdminHandler.
-func NewAdminHandler(repo *repository.Queries, logger *slog.Logger) *AdminHandler {
+// Admin actions are recorded through auditService with the admin as actor.
+func NewAdminHandler(
+	repo *repository.Queries,
+	userService service.UserService,
+	auditService service.AuditService,
+	logger *slog.Logger,
+) *AdminHandler {
 	return &AdminHandler{
-		repo:   repo,
-		logger: logger,
+		repo:         repo,
+		userService:  userService,
+		auditService: auditService,
+		logger:       logger,
 	}
 }
 
+// WithRegulationImports enables uploading regulation files from the admin
+// panel.
+func (h *AdminHandler) WithRegulationImports(svc service.RegulationImportService) *AdminHandler {
+	h.regulationImports = svc
+	return h
+}
+
 // RegisterRoutes registers admin routes with the provided middleware.
 func (h *AdminHandler) RegisterRoutes(
 	mux *http.ServeMux,
@@ -33,6 +72,18 @@ func (h *AdminHandler) RegisterRoutes(
 	mux.Handle("GET /admin", requireAdmin(http.HandlerFunc(h.Dashboard)))
 	mux.Handle("GET /admin/users", requireAdmin(http.HandlerFunc(h.UsersList)))
 	mux.Handle("GET /admin/users/{id}", requireAdmin(http.HandlerFunc(h.UserDetail)))
+	mux.Handle("POST /admin/users/{id}/verify-email", requireAdmin(http.HandlerFunc(h.VerifyEmail)))
+	mux.Handle("POST /admin/users/{id}/disable", requireAdmin(http.HandlerFunc(h.DisableUser)))
+	mux.Handle("POST /admin/users/{id}/enable", requireAdmin(http.HandlerFunc(h.EnableUser)))
+	mux.Handle("POST /admin/users/{id}/quota", requireAdmin(http.HandlerFunc(h.UpdateQuotaOverride)))
+	mux.Handle("POST /admin/users/{id}/logout", requireAdmin(http.HandlerFunc(h.ForceLogout)))
+	mux.Handle("POST /admin/users/{id}/tier", requireAdmin(http.HandlerFunc(h.ChangeTier)))
+	mux.Handle("GET /admin/jobs", requireAdmin(http.HandlerFunc(h.JobsList)))
+	mux.Handle("POST /admin/jobs/{id}/retry", requireAdmin(http.HandlerFunc(h.RetryJob)))
+	mux.Handle("POST /admin/inspections/{id}/legal-hold", requireAdmin(http.HandlerFunc(h.SetLegalHold)))
+	mux.Handle("GET /admin/usage", requireAdmin(http.HandlerFunc(h.Usage)))
+	mux.Handle("GET /admin/regulations/import", requireAdmin(http.HandlerFunc(h.ShowRegulationImport)))
+	mux.Handle("POST /admin/regulations/import", requireAdmin(http.HandlerFunc(h.ImportRegulations)))
 }
 
 // Dashboard renders the admin dashboard with platform stats.
@@ -46,16 +97,14 @@ func (h *AdminHandler) Dashboard(w http.ResponseWriter, r *http.Request) {
 	// Fetch platform stats
 	stats, err := h.repo.AdminGetPlatformStats(r.Context())
 	if err != nil {
-		h.logger.Error("failed to fetch platform stats", "error", err)
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		ServerErrorResponse(w, r, h.logger, fmt.Errorf("fetch platform stats: %w", err))
 		return
 	}
 
 	// Fetch users with AI usage
 	users, err := h.repo.AdminListUsers(r.Context())
 	if err != nil {
-		h.logger.Error("failed to fetch users", "error", err)
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		ServerErrorResponse(w, r, h.logger, fmt.Errorf("fetch users: %w", err))
 		return
 	}
 
@@ -92,17 +141,36 @@ func (h *AdminHandler) Dashboard(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := admin.DashboardPage(data).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render admin dashboard", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render admin dashboard", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
 
-// UsersList renders the users list page.
+// UsersList renders a page of the users list.
+// The optional q query parameter filters by email or name; page selects the
+// page, starting at 1.
 func (h *AdminHandler) UsersList(w http.ResponseWriter, r *http.Request) {
-	users, err := h.repo.AdminListUsers(r.Context())
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	page := 1
+	if p, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && p > 0 {
+		page = p
+	}
+
+	// The search is matched with ILIKE, so wildcards typed in it are escaped
+	search := escapeLikePattern(query)
+	total, err := h.repo.AdminCountUsers(r.Context(), search)
 	if err != nil {
-		h.logger.Error("failed to fetch users", "error", err)
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		ServerErrorResponse(w, r, h.logger, fmt.Errorf("count users: %w", err))
+		return
+	}
+
+	users, err := h.repo.AdminSearchUsers(r.Context(), repository.AdminSearchUsersParams{
+		Search: search,
+		Limit:  adminUsersPerPage,
+		Offset: int32((page - 1) * adminUsersPerPage),
+	})
+	if err != nil {
+		ServerErrorResponse(w, r, h.logger, fmt.Errorf("fetch users: %w", err))
 		return
 	}
 
@@ -114,14 +182,31 @@ func (h *AdminHandler) UsersList(w http.ResponseWriter, r *http.Request) {
 			Name:               u.Name,
 			SubscriptionStatus: domain.NullStringValue(u.SubscriptionStatus),
 			EmailVerified:      u.EmailVerified.Valid && u.EmailVerified.Bool,
+			Disabled:           u.DisabledAt.Valid,
 			CreatedAt:          u.CreatedAt.Time,
 			TotalCostCents:     u.TotalCostCents,
 			InspectionCount:    u.InspectionCount,
 		})
 	}
 
-	if err := admin.UsersPage(userRows).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render users page", "error", err)
+	totalPages := int((total + adminUsersPerPage - 1) / adminUsersPerPage)
+	data := admin.UsersPageData{
+		Users: userRows,
+		Query: query,
+		Pagination: pagination.Data{
+			CurrentPage: page,
+			TotalPages:  totalPages,
+			PerPage:     adminUsersPerPage,
+			Total:       int(total),
+			HasPrevious: page > 1,
+			HasNext:     page < totalPages,
+			PrevPage:    page - 1,
+			NextPage:    page + 1,
+		},
+	}
+
+	if err := admin.UsersPage(data).Render(r.Context(), w); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to render users page", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -137,7 +222,7 @@ func (h *AdminHandler) UserDetail(w http.ResponseWriter, r *http.Request) {
 
 	user, err := h.repo.AdminGetUserByID(r.Context(), id)
 	if err != nil {
-		h.logger.Error("failed to fetch user", "error", err, "user_id", id)
+		h.logger.ErrorContext(r.Context(), "failed to fetch user", "error", err, "user_id", id)
 		http.Error(w, "User not found", http.StatusNotFound)
 		return
 	}
@@ -148,7 +233,7 @@ func (h *AdminHandler) UserDetail(w http.ResponseWriter, r *http.Request) {
 		Limit:  20,
 	})
 	if err != nil {
-		h.logger.Warn("failed to fetch user inspections", "error", err, "user_id", id)
+		h.logger.WarnContext(r.Context(), "failed to fetch user inspections", "error", err, "user_id", id)
 	}
 
 	// Fetch user's AI usage history
@@ -157,7 +242,7 @@ func (h *AdminHandler) UserDetail(w http.ResponseWriter, r *http.Request) {
 		Limit:  20,
 	})
 	if err != nil {
-		h.logger.Warn("failed to fetch AI usage", "error", err, "user_id", id)
+		h.logger.WarnContext(r.Context(), "failed to fetch AI usage", "error", err, "user_id", id)
 	}
 
 	inspectionRows := make([]admin.InspectionRow, 0, len(inspections))
@@ -168,6 +253,7 @@ func (h *AdminHandler) UserDetail(w http.ResponseWriter, r *http.Request) {
 			Status:         i.Status,
 			InspectionDate: i.InspectionDate,
 			CreatedAt:      i.CreatedAt.Time,
+			LegalHold:      i.LegalHold,
 		})
 	}
 
@@ -183,6 +269,17 @@ func (h *AdminHandler) UserDetail(w http.ResponseWriter, r *http.Request) {
 		})
 	}
 
+	override, err := h.repo.GetUserQuotaOverride(r.Context(), id)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		ServerErrorResponse(w, r, h.logger.With("user_id", id), fmt.Errorf("fetch quota override: %w", err))
+		return
+	}
+
+	sessions, err := h.repo.ListSessionsByUserID(r.Context(), id)
+	if err != nil {
+		h.logger.WarnContext(r.Context(), "failed to fetch sessions", "error", err, "user_id", id)
+	}
+
 	data := admin.UserDetailData{
 		ID:                 user.ID,
 		Email:              user.Email,
@@ -190,22 +287,516 @@ func (h *AdminHandler) UserDetail(w http.ResponseWriter, r *http.Request) {
 		SubscriptionStatus: domain.NullStringValue(user.SubscriptionStatus),
 		SubscriptionTier:   domain.NullStringValue(user.SubscriptionTier),
 		EmailVerified:      user.EmailVerified.Valid && user.EmailVerified.Bool,
+		DisabledAt:         domain.NullTimeValue(user.DisabledAt),
+		ActiveSessions:     len(sessions),
 		CreatedAt:          user.CreatedAt.Time,
 		TotalCostCents:     user.TotalCostCents,
 		TotalInputTokens:   user.TotalInputTokens,
 		TotalOutputTokens:  user.TotalOutputTokens,
 		InspectionCount:    user.InspectionCount,
 		ReportCount:        user.ReportCount,
+		Quota:              quotaOverrideData(domain.SubscriptionTier(domain.NullStringValue(user.SubscriptionTier)), override),
 		Inspections:        inspectionRows,
 		AIUsageHistory:     aiUsageRows,
 	}
 
 	if err := admin.UserDetailPage(data).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render user detail page", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render user detail page", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
 
+// VerifyEmail marks a user's email as verified.
+// POST /admin/users/{id}/verify-email
+func (h *AdminHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	adminUser := auth.GetUserFromRequest(r)
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.repo.GetUserByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "User not found", http.StatusNotFound)
+			return
+		}
+		ServerErrorResponse(w, r, h.logger.With("user_id", id), fmt.Errorf("fetch user: %w", err))
+		return
+	}
+
+	if !user.EmailVerified.Bool {
+		err = h.auditService.RecordChange(r.Context(), func(q *repository.Queries) error {
+			return q.UpdateUserEmailVerification(r.Context(), repository.UpdateUserEmailVerificationParams{
+				ID:              id,
+				EmailVerified:   sql.NullBool{Bool: true, Valid: true},
+				EmailVerifiedAt: sql.NullTime{Time: time.Now(), Valid: true},
+			})
+		}, domain.AuditEvent{
+			ActorUserID: adminUser.ID,
+			EntityType:  domain.AuditEntityUser,
+			EntityID:    id,
+			Action:      domain.AuditActionEmailVerified,
+			OldValues:   map[string]string{"email_verified": "false"},
+			NewValues:   map[string]string{"email_verified": "true"},
+		})
+		if err != nil {
+			ServerErrorResponse(w, r, h.logger.With("user_id", id), fmt.Errorf("verify user email: %w", err))
+			return
+		}
+		h.logger.InfoContext(r.Context(), "admin verified user email", "user_id", id, "admin_id", adminUser.ID)
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/admin/users/%s", id), http.StatusSeeOther)
+}
+
+// DisableUser locks a user out; their sessions stop working immediately.
+// POST /admin/users/{id}/disable
+func (h *AdminHandler) DisableUser(w http.ResponseWriter, r *http.Request) {
+	h.setUserDisabled(w, r, true)
+}
+
+// EnableUser re-enables a disabled account.
+// POST /admin/users/{id}/enable
+func (h *AdminHandler) EnableUser(w http.ResponseWriter, r *http.Request) {
+	h.setUserDisabled(w, r, false)
+}
+
+// setUserDisabled disables or enables the user in the path and records the change.
+func (h *AdminHandler) setUserDisabled(w http.ResponseWriter, r *http.Request, disable bool) {
+	adminUser := auth.GetUserFromRequest(r)
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	if disable && id == adminUser.ID {
+		http.Error(w, "You cannot disable your own account", http.StatusBadRequest)
+		return
+	}
+
+	action := domain.AuditActionAccountEnabled
+	if disable {
+		action = domain.AuditActionAccountDisabled
+	}
+
+	// The user service updates the account on its own connection; the event
+	// is recorded only once that has succeeded.
+	err = h.auditService.RecordChange(r.Context(), func(*repository.Queries) error {
+		if disable {
+			return h.userService.DisableUser(r.Context(), id)
+		}
+		return h.userService.EnableUser(r.Context(), id)
+	}, domain.AuditEvent{
+		ActorUserID: adminUser.ID,
+		EntityType:  domain.AuditEntityUser,
+		EntityID:    id,
+		Action:      action,
+		OldValues:   map[string]string{"disabled": strconv.FormatBool(!disable)},
+		NewValues:   map[string]string{"disabled": strconv.FormatBool(disable)},
+	})
+	if err != nil {
+		code := domain.ErrorCode(err)
+		if code == domain.EINTERNAL {
+			h.logger.ErrorContext(r.Context(), "failed to update account status", "error", err, "user_id", id)
+		}
+		http.Error(w, domain.ErrorMessage(err), ErrorCodeToHTTPStatus(code))
+		return
+	}
+
+	h.logger.InfoContext(r.Context(), "admin updated account status", "user_id", id, "disabled", disable, "admin_id", adminUser.ID)
+	http.Redirect(w, r, fmt.Sprintf("/admin/users/%s", id), http.StatusSeeOther)
+}
+
+// UpdateQuotaOverride sets or clears a user's monthly quota override.
+// POST /admin/users/{id}/quota
+//
+// Form fields (blank uses the tier limit; both blank clears the override):
+// - analysis_per_month: Analyses allowed per month
+// - reports_per_month: Reports allowed per month
+func (h *AdminHandler) UpdateQuotaOverride(w http.ResponseWriter, r *http.Request) {
+	adminUser := auth.GetUserFromRequest(r)
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	analysis, err := parseQuotaLimit(r.FormValue("analysis_per_month"))
+	if err != nil {
+		http.Error(w, "Analyses per month must be a whole number of at least 0", http.StatusBadRequest)
+		return
+	}
+	reports, err := parseQuotaLimit(r.FormValue("reports_per_month"))
+	if err != nil {
+		http.Error(w, "Reports per month must be a whole number of at least 0", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.repo.GetUserByID(r.Context(), id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "User not found", http.StatusNotFound)
+			return
+		}
+		ServerErrorResponse(w, r, h.logger.With("user_id", id), fmt.Errorf("fetch user: %w", err))
+		return
+	}
+
+	var oldValues map[string]string
+	existing, err := h.repo.GetUserQuotaOverride(r.Context(), id)
+	switch {
+	case err == nil:
+		oldValues = quotaOverrideValues(existing.AnalysisPerMonth, existing.ReportsPerMonth)
+	case !errors.Is(err, sql.ErrNoRows):
+		ServerErrorResponse(w, r, h.logger.With("user_id", id), fmt.Errorf("fetch quota override: %w", err))
+		return
+	}
+
+	err = h.auditService.RecordChange(r.Context(), func(q *repository.Queries) error {
+		if !analysis.Valid && !reports.Valid {
+			return q.DeleteUserQuotaOverride(r.Context(), id)
+		}
+		_, err := q.UpsertUserQuotaOverride(r.Context(), repository.UpsertUserQuotaOverrideParams{
+			UserID:           id,
+			AnalysisPerMonth: analysis,
+			ReportsPerMonth:  reports,
+		})
+		return err
+	}, domain.AuditEvent{
+		ActorUserID: adminUser.ID,
+		EntityType:  domain.AuditEntityUser,
+		EntityID:    id,
+		Action:      domain.AuditActionQuotaOverrideChanged,
+		OldValues:   oldValues,
+		NewValues:   quotaOverrideValues(analysis, reports),
+	})
+	if err != nil {
+		ServerErrorResponse(w, r, h.logger.With("user_id", id), fmt.Errorf("update quota override: %w", err))
+		return
+	}
+
+	h.logger.InfoContext(r.Context(), "admin updated quota override", "user_id", id, "admin_id", adminUser.ID)
+	http.Redirect(w, r, fmt.Sprintf("/admin/users/%s", id), http.StatusSeeOther)
+}
+
+// ForceLogout signs a user out of every session. The account stays enabled,
+// so the user can log in again.
+// POST /admin/users/{id}/logout
+func (h *AdminHandler) ForceLogout(w http.ResponseWriter, r *http.Request) {
+	adminUser := auth.GetUserFromRequest(r)
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.repo.GetUserByID(r.Context(), id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "User not found", http.StatusNotFound)
+			return
+		}
+		ServerErrorResponse(w, r, h.logger.With("user_id", id), fmt.Errorf("fetch user: %w", err))
+		return
+	}
+
+	// The user service signs the user out on its own connection; the event
+	// is recorded only once that has succeeded.
+	err = h.auditService.RecordChange(r.Context(), func(*repository.Queries) error {
+		return h.userService.LogoutAll(r.Context(), id)
+	}, domain.AuditEvent{
+		ActorUserID: adminUser.ID,
+		EntityType:  domain.AuditEntityUser,
+		EntityID:    id,
+		Action:      domain.AuditActionSessionsRevoked,
+	})
+	if err != nil {
+		ServerErrorResponse(w, r, h.logger.With("user_id", id), fmt.Errorf("revoke user sessions: %w", err))
+		return
+	}
+
+	h.logger.InfoContext(r.Context(), "admin signed user out of all sessions", "user_id", id, "admin_id", adminUser.ID)
+	http.Redirect(w, r, fmt.Sprintf("/admin/users/%s", id), http.StatusSeeOther)
+}
+
+// ChangeTier sets a user's subscription tier. The Stripe subscription is not
+// changed, so a later billing webhook may set the tier again.
+// POST /admin/users/{id}/tier
+//
+// Form fields:
+// - tier: free, starter or professional
+func (h *AdminHandler) ChangeTier(w http.ResponseWriter, r *http.Request) {
+	adminUser := auth.GetUserFromRequest(r)
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+	tier := domain.SubscriptionTier(r.FormValue("tier"))
+	if !tier.IsValid() {
+		http.Error(w, "Unknown subscription tier", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.repo.GetUserByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "User not found", http.StatusNotFound)
+			return
+		}
+		ServerErrorResponse(w, r, h.logger.With("user_id", id), fmt.Errorf("fetch user: %w", err))
+		return
+	}
+
+	oldTier := domain.NullStringValue(user.SubscriptionTier)
+	if oldTier != string(tier) {
+		err = h.auditService.RecordChange(r.Context(), func(q *repository.Queries) error {
+			_, err := q.AdminUpdateUserSubscriptionTier(r.Context(), repository.AdminUpdateUserSubscriptionTierParams{
+				ID:               id,
+				SubscriptionTier: sql.NullString{String: string(tier), Valid: true},
+			})
+			return err
+		}, domain.AuditEvent{
+			ActorUserID: adminUser.ID,
+			EntityType:  domain.AuditEntityUser,
+			EntityID:    id,
+			Action:      domain.AuditActionTierChanged,
+			OldValues:   map[string]string{"subscription_tier": oldTier},
+			NewValues:   map[string]string{"subscription_tier": string(tier)},
+		})
+		if err != nil {
+			ServerErrorResponse(w, r, h.logger.With("user_id", id), fmt.Errorf("change subscription tier: %w", err))
+			return
+		}
+		h.logger.InfoContext(r.Context(), "admin changed subscription tier", "user_id", id, "tier", tier, "admin_id", adminUser.ID)
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/admin/users/%s", id), http.StatusSeeOther)
+}
+
+// JobsList renders the most recent worker jobs.
+// GET /admin/jobs
+func (h *AdminHandler) JobsList(w http.ResponseWriter, r *http.Request) {
+	jobs, err := h.repo.AdminListRecentJobs(r.Context(), adminJobsLimit)
+	if err != nil {
+		ServerErrorResponse(w, r, h.logger, fmt.Errorf("fetch jobs: %w", err))
+		return
+	}
+
+	jobRows := make([]admin.JobRow, 0, len(jobs))
+	for _, j := range jobs {
+		jobRows = append(jobRows, admin.JobRow{
+			ID:           j.ID,
+			JobType:      j.JobType,
+			Status:       j.Status,
+			Attempts:     j.Attempts,
+			MaxAttempts:  j.MaxAttempts,
+			ErrorMessage: domain.NullStringValue(j.ErrorMessage),
+			ScheduledAt:  j.ScheduledAt,
+			CreatedAt:    j.CreatedAt.Time,
+		})
+	}
+
+	if err := admin.JobsPage(jobRows).Render(r.Context(), w); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to render jobs page", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// RetryJob re-enqueues a failed job with its attempts reset.
+// POST /admin/jobs/{id}/retry
+func (h *AdminHandler) RetryJob(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.retry_job"
+
+	adminUser := auth.GetUserFromRequest(r)
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid job ID", http.StatusBadRequest)
+		return
+	}
+
+	job, err := h.repo.GetJobByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Job not found", http.StatusNotFound)
+			return
+		}
+		ServerErrorResponse(w, r, h.logger.With("job_id", id), fmt.Errorf("fetch job: %w", err))
+		return
+	}
+
+	err = h.auditService.RecordChange(r.Context(), func(q *repository.Queries) error {
+		affected, err := q.RetryFailedJob(r.Context(), id)
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return domain.Invalid(op, "only failed jobs can be retried, and not while a copy of the job is queued")
+		}
+		return nil
+	}, domain.AuditEvent{
+		ActorUserID: adminUser.ID,
+		EntityType:  domain.AuditEntityJob,
+		EntityID:    id,
+		Action:      domain.AuditActionJobRetried,
+		OldValues: map[string]string{
+			"job_type": job.JobType,
+			"status":   job.Status,
+			"attempts": strconv.Itoa(int(job.Attempts)),
+		},
+		NewValues: map[string]string{"status": "pending"},
+	})
+	if err != nil {
+		if domain.ErrorCode(err) == domain.EINVALID {
+			http.Error(w, domain.ErrorMessage(err), http.StatusConflict)
+			return
+		}
+		ServerErrorResponse(w, r, h.logger.With("job_id", id), fmt.Errorf("retry job: %w", err))
+		return
+	}
+
+	h.logger.InfoContext(r.Context(), "admin retried job", "job_id", id, "job_type", job.JobType, "admin_id", adminUser.ID)
+	http.Redirect(w, r, "/admin/jobs", http.StatusSeeOther)
+}
+
+// SetLegalHold places or releases a legal hold on an inspection. Inspections
+// under legal hold are never removed by the data retention purge.
+// POST /admin/inspections/{id}/legal-hold
+//
+// Form fields:
+// - hold: "true" to place the hold, "false" to release it
+func (h *AdminHandler) SetLegalHold(w http.ResponseWriter, r *http.Request) {
+	adminUser := auth.GetUserFromRequest(r)
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid inspection ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+	hold, err := strconv.ParseBool(r.FormValue("hold"))
+	if err != nil {
+		http.Error(w, "hold must be true or false", http.StatusBadRequest)
+		return
+	}
+
+	inspection, err := h.repo.GetInspectionByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Inspection not found", http.StatusNotFound)
+			return
+		}
+		ServerErrorResponse(w, r, h.logger.With("inspection_id", id), fmt.Errorf("fetch inspection: %w", err))
+		return
+	}
+
+	if inspection.LegalHold != hold {
+		err = h.auditService.RecordChange(r.Context(), func(q *repository.Queries) error {
+			_, err := q.SetInspectionLegalHold(r.Context(), repository.SetInspectionLegalHoldParams{
+				ID:        id,
+				LegalHold: hold,
+			})
+			return err
+		}, domain.AuditEvent{
+			ActorUserID:  adminUser.ID,
+			InspectionID: &id,
+			EntityType:   domain.AuditEntityInspection,
+			EntityID:     id,
+			Action:       domain.AuditActionLegalHoldChanged,
+			OldValues:    map[string]string{"legal_hold": strconv.FormatBool(inspection.LegalHold)},
+			NewValues:    map[string]string{"legal_hold": strconv.FormatBool(hold)},
+		})
+		if err != nil {
+			ServerErrorResponse(w, r, h.logger.With("inspection_id", id), fmt.Errorf("set legal hold: %w", err))
+			return
+		}
+		h.logger.InfoContext(r.Context(), "admin updated legal hold", "inspection_id", id, "legal_hold", hold, "admin_id", adminUser.ID)
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/admin/users/%s", inspection.UserID), http.StatusSeeOther)
+}
+
+// quotaOverrideData builds the quota form data from the tier limits and an
+// override row, which is zero-valued when the user has no override.
+func quotaOverrideData(tier domain.SubscriptionTier, override repository.UserQuotaOverride) admin.QuotaOverrideData {
+	quota := domain.GetTierQuota(tier)
+	data := admin.QuotaOverrideData{
+		TierAnalysisLimit: "unlimited",
+		TierReportsLimit:  "unlimited",
+	}
+	if !quota.UnlimitedAnalysis {
+		data.TierAnalysisLimit = strconv.Itoa(quota.AnalysisPerMonth)
+	}
+	if !quota.UnlimitedReports {
+		data.TierReportsLimit = strconv.Itoa(quota.ReportsPerMonth)
+	}
+	if override.AnalysisPerMonth.Valid {
+		data.AnalysisOverride = strconv.Itoa(int(override.AnalysisPerMonth.Int32))
+	}
+	if override.ReportsPerMonth.Valid {
+		data.ReportsOverride = strconv.Itoa(int(override.ReportsPerMonth.Int32))
+	}
+	return data
+}
+
+// quotaOverrideValues returns the set override limits as audit values.
+func quotaOverrideValues(analysis, reports sql.NullInt32) map[string]string {
+	values := map[string]string{}
+	if analysis.Valid {
+		values["analysis_per_month"] = strconv.Itoa(int(analysis.Int32))
+	}
+	if reports.Valid {
+		values["reports_per_month"] = strconv.Itoa(int(reports.Int32))
+	}
+	return values
+}
+
+// escapeLikePattern escapes the LIKE wildcards % and _, and the backslash
+// escape character, so s matches only itself.
+func escapeLikePattern(s string) string {
+	return likePatternEscaper.Replace(s)
+}
+
+var likePatternEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// parseQuotaLimit parses an optional non-negative monthly limit.
+// A blank value is NULL, meaning the tier limit applies.
+func parseQuotaLimit(v string) (sql.NullInt32, error) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return sql.NullInt32{}, nil
+	}
+	n, err := strconv.ParseInt(v, 10, 32)
+	if err != nil {
+		return sql.NullInt32{}, err
+	}
+	if n < 0 {
+		return sql.NullInt32{}, errors.New("limit must not be negative")
+	}
+	return sql.NullInt32{Int32: int32(n), Valid: true}, nil
+}
+
 // toInt64 safely converts an interface{} to int64.
 // This handles the case where sqlc returns interface{} for COALESCE(SUM(...)).
 func toInt64(v interface{}) int64 {
@@ -225,3 +816,41 @@ func toInt64(v interface{}) int64 {
 		return 0
 	}
 }
+
+// Usage renders AI analysis cost per user per month.
+// GET /admin/usage
+func (h *AdminHandler) Usage(w http.ResponseWriter, r *http.Request) {
+	now := time.Now().UTC()
+	thisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	runs, err := h.repo.ListAnalysisCostByUserAndMonth(r.Context(), repository.ListAnalysisCostByUserAndMonthParams{
+		CreatedAt:   thisMonth.AddDate(0, -(adminUsageMonths - 1), 0),
+		CreatedAt_2: thisMonth.AddDate(0, 1, 0),
+	})
+	if err != nil {
+		ServerErrorResponse(w, r, h.logger, fmt.Errorf("fetch analysis usage: %w", err))
+		return
+	}
+
+	var totalCents int64
+	rows := make([]admin.UsageRow, 0, len(runs))
+	for _, run := range runs {
+		totalCents += run.CostCents
+		rows = append(rows, admin.UsageRow{
+			Month:            run.Month.UTC(),
+			UserID:           run.UserID,
+			Email:            run.Email,
+			Name:             run.Name,
+			RunCount:         run.RunCount,
+			ImagesAnalyzed:   run.ImagesAnalyzed,
+			PromptTokens:     run.PromptTokens,
+			CompletionTokens: run.CompletionTokens,
+			CostCents:        run.CostCents,
+		})
+	}
+
+	if err := admin.UsagePage(rows, adminUsageMonths, totalCents).Render(r.Context(), w); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to render usage page", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// Admin Jobs Tests
+// =============================================================================
+
+func serveRetryJob(h *AdminHandler, id uuid.UUID) *httptest.ResponseRecorder {
+	req := withAdminTestUser(httptest.NewRequest(http.MethodPost, "/admin/jobs/"+id.String()+"/retry", nil))
+	req.SetPathValue("id", id.String())
+	rr := httptest.NewRecorder()
+	h.RetryJob(rr, req)
+	return rr
+}
+
+func TestAdminJobsList(t *testing.T) {
+	f := &fakeAdminDB{jobs: []*fakeAdminJob{
+		{id: uuid.New(), jobType: "generate_report", status: "failed", attempts: 3, errorMessage: "storage unavailable"},
+		{id: uuid.New(), jobType: "analyze_inspection", status: "completed", attempts: 1},
+	}}
+	h, _ := newAdminUsersTestHandler(f)
+
+	req := withAdminTestUser(httptest.NewRequest(http.MethodGet, "/admin/jobs", nil))
+	rr := httptest.NewRecorder()
+	h.JobsList(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	body := rr.Body.String()
+	for _, want := range []string{"generate_report", "storage unavailable", "analyze_inspection"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected jobs page to contain %q", want)
+		}
+	}
+	if !strings.Contains(body, "/admin/jobs/"+f.jobs[0].id.String()+"/retry") {
+		t.Error("expected a retry action for the failed job")
+	}
+}
+
+func TestAdminRetryJob(t *testing.T) {
+	job := &fakeAdminJob{id: uuid.New(), jobType: "generate_report", status: "failed", attempts: 3, errorMessage: "storage unavailable"}
+	f := &fakeAdminDB{jobs: []*fakeAdminJob{job}}
+	h, audit := newAdminUsersTestHandler(f)
+
+	rr := serveRetryJob(h, job.id)
+
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("expected status 303, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if job.status != "pending" || job.attempts != 0 {
+		t.Errorf("expected the job pending with its attempts reset, got %s after %d attempts", job.status, job.attempts)
+	}
+	if len(audit.events) != 1 {
+		t.Fatalf("expected one audit event, got %+v", audit.events)
+	}
+	event := audit.events[0]
+	if event.Action != domain.AuditActionJobRetried || event.EntityID != job.id || event.OldValues["attempts"] != "3" {
+		t.Errorf("expected a job retried audit event, got %+v", event)
+	}
+}
+
+func TestAdminRetryJob_OnlyFailedJobs(t *testing.T) {
+	running := &fakeAdminJob{id: uuid.New(), jobType: "generate_report", status: "running", attempts: 1}
+	requeued := &fakeAdminJob{id: uuid.New(), jobType: "generate_report", status: "failed", attempts: 3, queuedCopy: true}
+	f := &fakeAdminDB{jobs: []*fakeAdminJob{running, requeued}}
+	h, audit := newAdminUsersTestHandler(f)
+
+	for name, job := range map[string]*fakeAdminJob{"running": running, "requeued": requeued} {
+		if rr := serveRetryJob(h, job.id); rr.Code != http.StatusConflict {
+			t.Errorf("expected status 409 for the %s job, got %d", name, rr.Code)
+		}
+	}
+	if running.status != "running" || requeued.status != "failed" {
+		t.Errorf("expected the jobs unchanged, got %s and %s", running.status, requeued.status)
+	}
+	if len(audit.events) != 0 {
+		t.Errorf("expected no audit events, got %+v", audit.events)
+	}
+
+	if rr := serveRetryJob(h, uuid.New()); rr.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for an unknown job, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/DukeRupert/lukaut/internal/auth"
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/templ/pages/admin"
+)
+
+// regulationImportMaxBytes caps regulation uploads. A full OSHA export is a
+// few megabytes.
+const regulationImportMaxBytes = 32 << 20
+
+// ShowRegulationImport renders the regulation upload form.
+// GET /admin/regulations/import
+func (h *AdminHandler) ShowRegulationImport(w http.ResponseWriter, r *http.Request) {
+	h.renderRegulationImport(w, r, http.StatusOK, admin.RegulationImportData{})
+}
+
+// ImportRegulations upserts the standards in an uploaded CSV or JSON file
+// by standard number and renders what changed, including the rows that were
+// skipped. Standards missing from the file are only deactivated when
+// deactivate_missing is set.
+// POST /admin/regulations/import
+func (h *AdminHandler) ImportRegulations(w http.ResponseWriter, r *http.Request) {
+	adminUser := auth.GetUserFromRequest(r)
+
+	r.Body = http.MaxBytesReader(w, r.Body, regulationImportMaxBytes)
+	if err := r.ParseMultipartForm(regulationImportMaxBytes); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			h.renderRegulationImport(w, r, http.StatusRequestEntityTooLarge, admin.RegulationImportData{
+				Error: fmt.Sprintf("The file is larger than %d MB.", regulationImportMaxBytes>>20),
+			})
+			return
+		}
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+	defer func() { _ = r.MultipartForm.RemoveAll() }()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		h.renderRegulationImport(w, r, http.StatusBadRequest, admin.RegulationImportData{
+			Error: "Choose a CSV or JSON file to import.",
+		})
+		return
+	}
+	defer func() { _ = file.Close() }()
+
+	source := filepath.Base(header.Filename)
+	format := domain.RegulationImportFormat(strings.TrimPrefix(strings.ToLower(filepath.Ext(source)), "."))
+	if !format.IsValid() {
+		h.renderRegulationImport(w, r, http.StatusUnprocessableEntity, admin.RegulationImportData{
+			Error: "The file must have a .csv or .json extension.",
+		})
+		return
+	}
+
+	deactivateMissing := r.FormValue("deactivate_missing") != ""
+	importFile := h.regulationImports.Merge
+	if deactivateMissing {
+		importFile = h.regulationImports.Import
+	}
+
+	summary, err := importFile(r.Context(), source, format, file)
+	switch domain.ErrorCode(err) {
+	case "":
+	case domain.EINVALID:
+		h.renderRegulationImport(w, r, http.StatusUnprocessableEntity, admin.RegulationImportData{
+			Error: domain.ErrorMessage(err),
+		})
+		return
+	default:
+		ServerErrorResponse(w, r, h.logger, fmt.Errorf("import regulations: %w", err))
+		return
+	}
+
+	h.logger.InfoContext(r.Context(), "admin imported regulations",
+		"import_id", summary.ID,
+		"source", source,
+		"deactivate_missing", deactivateMissing,
+		"admin_id", adminUser.ID,
+	)
+	h.renderRegulationImport(w, r, http.StatusOK, admin.RegulationImportData{
+		Result: &admin.RegulationImportResult{
+			Source:      summary.Source,
+			Added:       summary.Added,
+			Updated:     summary.Updated,
+			Unchanged:   summary.Unchanged,
+			Deactivated: summary.Deactivated,
+			Errors:      summary.Errors,
+		},
+	})
+}
+
+// renderRegulationImport renders the regulation import page with status.
+func (h *AdminHandler) renderRegulationImport(w http.ResponseWriter, r *http.Request, status int, data admin.RegulationImportData) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if err := admin.RegulationImportPage(data).Render(r.Context(), w); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to render regulation import page", "error", err)
+	}
+}
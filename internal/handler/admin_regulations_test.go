@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DukeRupert/lukaut/internal/auth"
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/service"
+	"github.com/google/uuid"
+)
+
+// fakeRegulationImports records which import mode ran and returns a fixed
+// result.
+type fakeRegulationImports struct {
+	service.RegulationImportService
+	called  string // "import" or "merge"
+	source  string
+	format  domain.RegulationImportFormat
+	content string
+	summary *domain.RegulationImportSummary
+	err     error
+}
+
+func (f *fakeRegulationImports) run(mode, source string, format domain.RegulationImportFormat, r io.Reader) (*domain.RegulationImportSummary, error) {
+	content, _ := io.ReadAll(r)
+	f.called, f.source, f.format, f.content = mode, source, format, string(content)
+	return f.summary, f.err
+}
+
+func (f *fakeRegulationImports) Import(_ context.Context, source string, format domain.RegulationImportFormat, r io.Reader) (*domain.RegulationImportSummary, error) {
+	return f.run("import", source, format, r)
+}
+
+func (f *fakeRegulationImports) Merge(_ context.Context, source string, format domain.RegulationImportFormat, r io.Reader) (*domain.RegulationImportSummary, error) {
+	return f.run("merge", source, format, r)
+}
+
+// newRegulationUploadRequest builds an admin's multipart upload of content
+// as filename, with extra form fields.
+func newRegulationUploadRequest(t *testing.T, filename, content string, fields map[string]string) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for name, value := range fields {
+		_ = mw.WriteField(name, value)
+	}
+	if filename != "" {
+		part, err := mw.CreateFormFile("file", filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = io.WriteString(part, content)
+	}
+	_ = mw.Close()
+
+	req := httptest.NewRequest("POST", "/admin/regulations/import", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req.WithContext(auth.SetUser(req.Context(), &domain.User{ID: uuid.New(), Email: "admin@example.com"}))
+}
+
+func newTestAdminHandler(imports *fakeRegulationImports) *AdminHandler {
+	return NewAdminHandler(nil, nil, nil, newTestLogger()).WithRegulationImports(imports)
+}
+
+func TestImportRegulations_MergesByDefault(t *testing.T) {
+	imports := &fakeRegulationImports{summary: &domain.RegulationImportSummary{
+		Source:  "corrections.csv",
+		Added:   1,
+		Updated: 2,
+		Errors:  []string{"line 4 (1926.502(d)): title is required"},
+	}}
+	h := newTestAdminHandler(imports)
+	csv := "standard_number,title,category,full_text\n1926.501(b)(1),Unprotected Sides and Edges,Fall Protection,Each employee...\n"
+	rec := httptest.NewRecorder()
+
+	h.ImportRegulations(rec, newRegulationUploadRequest(t, "corrections.csv", csv, nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status code = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if imports.called != "merge" {
+		t.Errorf("expected a merge, got %q", imports.called)
+	}
+	if imports.source != "corrections.csv" || imports.format != domain.RegulationImportFormatCSV || imports.content != csv {
+		t.Errorf("expected the uploaded CSV, got %q as %q: %q", imports.source, imports.format, imports.content)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{"1 added, 2 updated, 0 unchanged, 0 deactivated, 1 skipped", "line 4 (1926.502(d)): title is required"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected the page to contain %q", want)
+		}
+	}
+}
+
+func TestImportRegulations_DeactivateMissingRunsFullImport(t *testing.T) {
+	imports := &fakeRegulationImports{summary: &domain.RegulationImportSummary{Source: "standards.json"}}
+	h := newTestAdminHandler(imports)
+	rec := httptest.NewRecorder()
+
+	h.ImportRegulations(rec, newRegulationUploadRequest(t, "standards.JSON", "[]", map[string]string{"deactivate_missing": "1"}))
+
+	if imports.called != "import" || imports.format != domain.RegulationImportFormatJSON {
+		t.Errorf("expected a full JSON import, got %q as %q", imports.called, imports.format)
+	}
+}
+
+func TestImportRegulations_RejectedUploads(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		err      error
+		wantCode int
+		wantBody string
+	}{
+		{"no file", "", nil, http.StatusBadRequest, "Choose a CSV or JSON file"},
+		{"unsupported extension", "standards.xlsx", nil, http.StatusUnprocessableEntity, "must have a .csv or .json extension"},
+		{"no valid rows", "standards.csv", domain.Invalid("regulation.import", "no valid regulations in import (2 malformed rows)"), http.StatusUnprocessableEntity, "no valid regulations in import"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			imports := &fakeRegulationImports{err: tt.err}
+			h := newTestAdminHandler(imports)
+			rec := httptest.NewRecorder()
+
+			h.ImportRegulations(rec, newRegulationUploadRequest(t, tt.filename, "standard_number\n", nil))
+
+			if rec.Code != tt.wantCode {
+				t.Errorf("status code = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("expected the page to contain %q", tt.wantBody)
+			}
+			if tt.err == nil && imports.called != "" {
+				t.Errorf("expected no import, got %q", imports.called)
+			}
+		})
+	}
+}
+
+func TestAdminRoutes_RegulationImportRequiresAdmin(t *testing.T) {
+	imports := &fakeRegulationImports{summary: &domain.RegulationImportSummary{}}
+	mux := http.NewServeMux()
+	denyAll := func(http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+		})
+	}
+	newTestAdminHandler(imports).RegisterRoutes(mux, denyAll)
+	rec := httptest.NewRecorder()
+
+	mux.ServeHTTP(rec, newRegulationUploadRequest(t, "standards.csv", "standard_number\n", nil))
+
+	if rec.Code != http.StatusForbidden || imports.called != "" {
+		t.Errorf("expected the upload to go through requireAdmin, got %d and %q", rec.Code, imports.called)
+	}
+}
@@ -0,0 +1,565 @@
+package handler
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/auth"
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/DukeRupert/lukaut/internal/service"
+	"github.com/DukeRupert/lukaut/internal/testutil/fakedb"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// In-Memory Admin Database
+// =============================================================================
+
+// fakeAdminUser holds the columns the admin user console reads.
+type fakeAdminUser struct {
+	id          uuid.UUID
+	email, name string
+	createdAt   time.Time
+	verified    bool
+	disabled    bool
+}
+
+// fakeAdminJob holds the columns the admin jobs page reads.
+type fakeAdminJob struct {
+	id           uuid.UUID
+	jobType      string
+	status       string
+	attempts     int64
+	errorMessage string
+	queuedCopy   bool // A copy of the job is pending or running
+}
+
+// fakeAdminDB answers the sqlc queries used by the admin user console. Users
+// are kept newest first, the order the search returns them in.
+type fakeAdminDB struct {
+	users        []*fakeAdminUser
+	sessions     map[uuid.UUID]int             // Session count per user
+	overrides    map[uuid.UUID][2]driver.Value // Analysis and reports quota overrides per user; nil is no limit
+	jobs         []*fakeAdminJob               // Newest first
+	failOverride bool                          // Fail reading quota overrides
+}
+
+// user returns the user with the ID bound to the query's first parameter.
+func (f *fakeAdminDB) user(q fakedb.Query) *fakeAdminUser {
+	for _, u := range f.users {
+		if u.id.String() == q.Arg(0).(string) {
+			return u
+		}
+	}
+	return nil
+}
+
+// job returns the job with the ID bound to the query's first parameter.
+func (f *fakeAdminDB) job(q fakedb.Query) *fakeAdminJob {
+	for _, j := range f.jobs {
+		if j.id.String() == q.Arg(0).(string) {
+			return j
+		}
+	}
+	return nil
+}
+
+// jobRow returns a job in repository.Job column order.
+func jobRow(j *fakeAdminJob) []driver.Value {
+	var errorMessage driver.Value
+	if j.errorMessage != "" {
+		errorMessage = j.errorMessage
+	}
+	return []driver.Value{
+		j.id.String(), j.jobType, []byte("{}"), j.status, int64(0), j.attempts, int64(3), time.Now(), nil, nil, errorMessage, time.Now(), nil, nil, nil,
+	}
+}
+
+// search mirrors the AdminSearchUsers WHERE clause, matching the term as a
+// case-insensitive LIKE pattern anywhere in the email or name.
+func (f *fakeAdminDB) search(term string) []*fakeAdminUser {
+	var pattern strings.Builder
+	pattern.WriteString("(?is)")
+	for i := 0; i < len(term); i++ {
+		switch c := term[i]; {
+		case c == '\\' && i+1 < len(term):
+			i++
+			pattern.WriteString(regexp.QuoteMeta(term[i : i+1]))
+		case c == '%':
+			pattern.WriteString(".*")
+		case c == '_':
+			pattern.WriteString(".")
+		default:
+			pattern.WriteString(regexp.QuoteMeta(term[i : i+1]))
+		}
+	}
+	re := regexp.MustCompile(pattern.String())
+
+	var matched []*fakeAdminUser
+	for _, u := range f.users {
+		if term == "" || re.MatchString(u.email) || re.MatchString(u.name) {
+			matched = append(matched, u)
+		}
+	}
+	return matched
+}
+
+func (f *fakeAdminDB) Query(q fakedb.Query) (*fakedb.Rows, error) {
+	args := q.Args
+	name := q.Name
+	switch name {
+	case "AdminCountUsers":
+		return &fakedb.Rows{Columns: 1, Values: [][]driver.Value{{int64(len(f.search(args[0].Value.(string))))}}}, nil
+	case "AdminSearchUsers":
+		matched := f.search(args[0].Value.(string))
+		limit, offset := int(args[1].Value.(int64)), int(args[2].Value.(int64))
+		rows := &fakedb.Rows{Columns: 10}
+		for i := offset; i < len(matched) && i < offset+limit; i++ {
+			u := matched[i]
+			rows.Values = append(rows.Values, []driver.Value{
+				u.id.String(), u.email, u.name, "active", "starter", true, nil, u.createdAt, int64(0), int64(0),
+			})
+		}
+		return rows, nil
+	case "GetUserByID", "AdminGetUserByID":
+		// AdminGetUserByID adds six usage totals to the user's columns
+		columns := 29
+		if name == "AdminGetUserByID" {
+			columns = 35
+		}
+		rows := &fakedb.Rows{Columns: columns}
+		if u := f.user(q); u != nil {
+			row := make([]driver.Value, columns)
+			row[0], row[1], row[2], row[3] = u.id.String(), u.email, "hash", u.name
+			row[10] = u.verified
+			row[26] = int64(0) // verification_reminder_count
+			for i := 29; i < columns; i++ {
+				row[i] = int64(0)
+			}
+			rows.Values = append(rows.Values, row)
+		}
+		return rows, nil
+	case "AdminGetUserInspections", "AdminGetUserAIUsageHistory", "ListSessionsByUserID":
+		return &fakedb.Rows{}, nil
+	case "GetUserQuotaOverride":
+		if f.failOverride {
+			return nil, fmt.Errorf("fakeAdminDB: %s failed", name)
+		}
+		rows := &fakedb.Rows{Columns: 4}
+		id := uuid.MustParse(args[0].Value.(string))
+		if o, ok := f.overrides[id]; ok {
+			rows.Values = append(rows.Values, []driver.Value{id.String(), o[0], o[1], time.Now()})
+		}
+		return rows, nil
+	case "UpsertUserQuotaOverride":
+		id := uuid.MustParse(args[0].Value.(string))
+		f.overrides[id] = [2]driver.Value{args[1].Value, args[2].Value}
+		return fakedb.Row(id.String(), args[1].Value, args[2].Value, time.Now()), nil
+	case "AdminListRecentJobs":
+		rows := &fakedb.Rows{Columns: 15}
+		for _, j := range f.jobs {
+			rows.Values = append(rows.Values, jobRow(j))
+		}
+		return rows, nil
+	case "GetJobByID":
+		rows := &fakedb.Rows{Columns: 15}
+		if j := f.job(q); j != nil {
+			rows.Values = append(rows.Values, jobRow(j))
+		}
+		return rows, nil
+	}
+	return nil, fmt.Errorf("fakeAdminDB: unexpected query %q", name)
+}
+
+func (f *fakeAdminDB) Exec(q fakedb.Query) (int64, error) {
+	args := q.Args
+	name := q.Name
+	switch name {
+	case "DeleteUserSessions":
+		id := uuid.MustParse(args[0].Value.(string))
+		deleted := f.sessions[id]
+		delete(f.sessions, id)
+		return int64(deleted), nil
+	case "UpdateUserEmailVerification":
+		if u := f.user(q); u != nil {
+			u.verified = args[1].Value.(bool)
+			return 1, nil
+		}
+		return 0, nil
+	case "AdminUpdateUserDisabled":
+		if u := f.user(q); u != nil {
+			u.disabled = args[1].Value != nil
+			return 1, nil
+		}
+		return 0, nil
+	case "DeleteUserQuotaOverride":
+		delete(f.overrides, uuid.MustParse(args[0].Value.(string)))
+		return 1, nil
+	case "RetryFailedJob":
+		// Mirrors the WHERE clause
+		if j := f.job(q); j != nil && j.status == "failed" && !j.queuedCopy {
+			j.status, j.attempts, j.errorMessage = "pending", 0, ""
+			return 1, nil
+		}
+		return 0, nil
+	}
+	return 0, fmt.Errorf("fakeAdminDB: unexpected exec %q", name)
+}
+
+// recordingAdminAudit applies changes without a transaction and keeps the
+// events they record.
+type recordingAdminAudit struct {
+	service.AuditService
+	queries *repository.Queries
+	events  []domain.AuditEvent
+}
+
+func (a *recordingAdminAudit) RecordChange(ctx context.Context, change func(q *repository.Queries) error, events ...domain.AuditEvent) error {
+	if err := change(a.queries); err != nil {
+		return err
+	}
+	a.events = append(a.events, events...)
+	return nil
+}
+
+func newAdminUsersTestHandler(f *fakeAdminDB) (*AdminHandler, *recordingAdminAudit) {
+	queries := repository.New(fakedb.Open(f))
+	audit := &recordingAdminAudit{queries: queries}
+	users := service.NewUserService(queries, repository.NewUnitOfWork(queries), newTestLogger())
+	return NewAdminHandler(queries, users, audit, newTestLogger()), audit
+}
+
+func withAdminTestUser(req *http.Request) *http.Request {
+	return req.WithContext(auth.SetUser(req.Context(), &domain.User{ID: uuid.New(), Email: "admin@example.com"}))
+}
+
+// =============================================================================
+// Admin User Console Tests
+// =============================================================================
+
+func TestAdminUsersList_Search(t *testing.T) {
+	now := time.Now()
+	f := &fakeAdminDB{users: []*fakeAdminUser{
+		{id: uuid.New(), email: "pat@acme.example", name: "Pat Smith", createdAt: now},
+		{id: uuid.New(), email: "lee@example.com", name: "Lee Acme", createdAt: now.Add(-time.Hour)},
+		{id: uuid.New(), email: "sam@example.com", name: "Sam Jones", createdAt: now.Add(-2 * time.Hour)},
+	}}
+	h, _ := newAdminUsersTestHandler(f)
+
+	req := withAdminTestUser(httptest.NewRequest(http.MethodGet, "/admin/users?q=ACME", nil))
+	rr := httptest.NewRecorder()
+	h.UsersList(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	body := rr.Body.String()
+	for _, want := range []string{"pat@acme.example", "Lee Acme", "2 users matching &#34;ACME&#34;"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected users page to contain %q", want)
+		}
+	}
+	if strings.Contains(body, "sam@example.com") {
+		t.Error("expected users not matching the search to be left out")
+	}
+}
+
+func TestEscapeLikePattern(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"pat@example.com", "pat@example.com"},
+		{"50%", `50\%`},
+		{"first_last", `first\_last`},
+		{`back\slash`, `back\\slash`},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := escapeLikePattern(tt.in); got != tt.want {
+			t.Errorf("escapeLikePattern(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestAdminUsersList_SearchMatchesWildcardsLiterally(t *testing.T) {
+	f := &fakeAdminDB{users: []*fakeAdminUser{
+		{id: uuid.New(), email: "pat_smith@example.com", name: "Pat Smith", createdAt: time.Now()},
+		{id: uuid.New(), email: "patxsmith@example.com", name: "Pat X", createdAt: time.Now()},
+	}}
+	h, _ := newAdminUsersTestHandler(f)
+
+	req := withAdminTestUser(httptest.NewRequest(http.MethodGet, "/admin/users?q=pat_smith", nil))
+	rr := httptest.NewRecorder()
+	h.UsersList(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	body := rr.Body.String()
+	if !strings.Contains(body, "pat_smith@example.com") {
+		t.Error("expected the user whose email contains the search")
+	}
+	if strings.Contains(body, "patxsmith@example.com") {
+		t.Error("expected _ in the search not to match any character")
+	}
+}
+
+func TestAdminUsersList_Paginates(t *testing.T) {
+	f := &fakeAdminDB{}
+	start := time.Now()
+	for i := 0; i < adminUsersPerPage+2; i++ {
+		f.users = append(f.users, &fakeAdminUser{
+			id:        uuid.New(),
+			email:     fmt.Sprintf("user%03d@example.com", i),
+			name:      fmt.Sprintf("User %d", i),
+			createdAt: start.Add(-time.Duration(i) * time.Minute),
+		})
+	}
+	h, _ := newAdminUsersTestHandler(f)
+
+	req := withAdminTestUser(httptest.NewRequest(http.MethodGet, "/admin/users?q=example&page=2", nil))
+	rr := httptest.NewRecorder()
+	h.UsersList(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	body := rr.Body.String()
+	last := fmt.Sprintf("user%03d@example.com", adminUsersPerPage+1)
+	if !strings.Contains(body, last) {
+		t.Errorf("expected page 2 to contain %s", last)
+	}
+	if strings.Contains(body, "user000@example.com") {
+		t.Error("expected the first page's users to be left off page 2")
+	}
+	// Paging links keep the search
+	if !strings.Contains(body, "/admin/users?q=example&amp;page=1") {
+		t.Error("expected a link back to page 1 of the search")
+	}
+}
+
+func TestAdminForceLogout(t *testing.T) {
+	target := &fakeAdminUser{id: uuid.New(), email: "pat@example.com", name: "Pat"}
+	other := uuid.New()
+	f := &fakeAdminDB{
+		users:    []*fakeAdminUser{target},
+		sessions: map[uuid.UUID]int{target.id: 3, other: 1},
+	}
+	h, audit := newAdminUsersTestHandler(f)
+
+	req := withAdminTestUser(httptest.NewRequest(http.MethodPost, "/admin/users/"+target.id.String()+"/logout", nil))
+	req.SetPathValue("id", target.id.String())
+	rr := httptest.NewRecorder()
+	h.ForceLogout(rr, req)
+
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("expected status 303, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if loc := rr.Header().Get("Location"); loc != "/admin/users/"+target.id.String() {
+		t.Errorf("expected redirect to the user detail page, got %q", loc)
+	}
+	if f.sessions[target.id] != 0 {
+		t.Errorf("expected the user's sessions to be deleted, %d left", f.sessions[target.id])
+	}
+	if f.sessions[other] != 1 {
+		t.Error("expected other users' sessions to be kept")
+	}
+	if len(audit.events) != 1 || audit.events[0].Action != domain.AuditActionSessionsRevoked || audit.events[0].EntityID != target.id {
+		t.Errorf("expected a sessions revoked audit event, got %+v", audit.events)
+	}
+}
+
+func TestAdminForceLogout_UnknownUser(t *testing.T) {
+	f := &fakeAdminDB{}
+	h, audit := newAdminUsersTestHandler(f)
+
+	id := uuid.New()
+	req := withAdminTestUser(httptest.NewRequest(http.MethodPost, "/admin/users/"+id.String()+"/logout", nil))
+	req.SetPathValue("id", id.String())
+	rr := httptest.NewRecorder()
+	h.ForceLogout(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", rr.Code)
+	}
+	if len(audit.events) != 0 {
+		t.Errorf("expected no audit event, got %+v", audit.events)
+	}
+}
+
+// serveAdminUserAction posts the form to an admin action on the user.
+func serveAdminUserAction(action func(http.ResponseWriter, *http.Request), id uuid.UUID, path string, form url.Values) *httptest.ResponseRecorder {
+	req := withAdminTestUser(httptest.NewRequest(http.MethodPost, "/admin/users/"+id.String()+path, strings.NewReader(form.Encode())))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetPathValue("id", id.String())
+	rr := httptest.NewRecorder()
+	action(rr, req)
+	return rr
+}
+
+func TestAdminDisableUser(t *testing.T) {
+	target := &fakeAdminUser{id: uuid.New(), email: "pat@example.com", name: "Pat"}
+	f := &fakeAdminDB{
+		users:    []*fakeAdminUser{target},
+		sessions: map[uuid.UUID]int{target.id: 2},
+	}
+	h, audit := newAdminUsersTestHandler(f)
+
+	rr := serveAdminUserAction(h.DisableUser, target.id, "/disable", nil)
+
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("expected status 303, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if !target.disabled {
+		t.Error("expected the user to be disabled")
+	}
+	if f.sessions[target.id] != 0 {
+		t.Errorf("expected the user's sessions to be deleted, %d left", f.sessions[target.id])
+	}
+	if len(audit.events) != 1 || audit.events[0].Action != domain.AuditActionAccountDisabled || audit.events[0].EntityID != target.id {
+		t.Errorf("expected an account disabled audit event, got %+v", audit.events)
+	}
+
+	rr = serveAdminUserAction(h.EnableUser, target.id, "/enable", nil)
+
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("expected status 303, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if target.disabled {
+		t.Error("expected the user to be enabled")
+	}
+	if len(audit.events) != 2 || audit.events[1].Action != domain.AuditActionAccountEnabled {
+		t.Errorf("expected an account enabled audit event, got %+v", audit.events)
+	}
+}
+
+func TestAdminDisableUser_UnknownUser(t *testing.T) {
+	f := &fakeAdminDB{}
+	h, audit := newAdminUsersTestHandler(f)
+
+	rr := serveAdminUserAction(h.DisableUser, uuid.New(), "/disable", nil)
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", rr.Code)
+	}
+	if len(audit.events) != 0 {
+		t.Errorf("expected no audit event, got %+v", audit.events)
+	}
+}
+
+func TestAdminVerifyEmail(t *testing.T) {
+	target := &fakeAdminUser{id: uuid.New(), email: "pat@example.com", name: "Pat"}
+	f := &fakeAdminDB{users: []*fakeAdminUser{target}}
+	h, audit := newAdminUsersTestHandler(f)
+
+	rr := serveAdminUserAction(h.VerifyEmail, target.id, "/verify-email", nil)
+
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("expected status 303, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if !target.verified {
+		t.Error("expected the user's email to be verified")
+	}
+	if len(audit.events) != 1 || audit.events[0].Action != domain.AuditActionEmailVerified || audit.events[0].EntityID != target.id {
+		t.Errorf("expected an email verified audit event, got %+v", audit.events)
+	}
+
+	// Verifying again changes nothing
+	rr = serveAdminUserAction(h.VerifyEmail, target.id, "/verify-email", nil)
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("expected status 303, got %d", rr.Code)
+	}
+	if len(audit.events) != 1 {
+		t.Errorf("expected no audit event for an already verified email, got %+v", audit.events)
+	}
+
+	if rr := serveAdminUserAction(h.VerifyEmail, uuid.New(), "/verify-email", nil); rr.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for an unknown user, got %d", rr.Code)
+	}
+}
+
+func TestAdminUpdateQuotaOverride(t *testing.T) {
+	target := &fakeAdminUser{id: uuid.New(), email: "pat@example.com", name: "Pat"}
+	f := &fakeAdminDB{
+		users:     []*fakeAdminUser{target},
+		overrides: map[uuid.UUID][2]driver.Value{target.id: {int64(5), nil}},
+	}
+	h, audit := newAdminUsersTestHandler(f)
+
+	rr := serveAdminUserAction(h.UpdateQuotaOverride, target.id, "/quota", url.Values{"analysis_per_month": {"40"}, "reports_per_month": {"10"}})
+
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("expected status 303, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if got := f.overrides[target.id]; got != [2]driver.Value{int64(40), int64(10)} {
+		t.Errorf("expected the override set to 40 analyses and 10 reports, got %v", got)
+	}
+	if len(audit.events) != 1 {
+		t.Fatalf("expected one audit event, got %+v", audit.events)
+	}
+	event := audit.events[0]
+	if event.Action != domain.AuditActionQuotaOverrideChanged || event.OldValues["analysis_per_month"] != "5" || event.NewValues["reports_per_month"] != "10" {
+		t.Errorf("expected the old and new limits recorded, got %+v", event)
+	}
+
+	// Both blank clears the override
+	rr = serveAdminUserAction(h.UpdateQuotaOverride, target.id, "/quota", url.Values{"analysis_per_month": {""}, "reports_per_month": {" "}})
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("expected status 303, got %d", rr.Code)
+	}
+	if _, ok := f.overrides[target.id]; ok {
+		t.Error("expected the override to be cleared")
+	}
+}
+
+func TestAdminUpdateQuotaOverride_RejectsInvalidLimits(t *testing.T) {
+	target := &fakeAdminUser{id: uuid.New(), email: "pat@example.com", name: "Pat"}
+	f := &fakeAdminDB{users: []*fakeAdminUser{target}, overrides: map[uuid.UUID][2]driver.Value{}}
+	h, audit := newAdminUsersTestHandler(f)
+
+	for _, limit := range []string{"-1", "ten"} {
+		rr := serveAdminUserAction(h.UpdateQuotaOverride, target.id, "/quota", url.Values{"analysis_per_month": {limit}})
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400 for %q, got %d", limit, rr.Code)
+		}
+	}
+	if len(f.overrides) != 0 || len(audit.events) != 0 {
+		t.Errorf("expected nothing changed, got overrides %v and events %+v", f.overrides, audit.events)
+	}
+
+	if rr := serveAdminUserAction(h.UpdateQuotaOverride, uuid.New(), "/quota", url.Values{"analysis_per_month": {"4"}}); rr.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for an unknown user, got %d", rr.Code)
+	}
+}
+
+func TestAdminUserDetail_QuotaOverrideErrorFails(t *testing.T) {
+	target := &fakeAdminUser{id: uuid.New(), email: "pat@example.com", name: "Pat"}
+	f := &fakeAdminDB{users: []*fakeAdminUser{target}, failOverride: true}
+	h, _ := newAdminUsersTestHandler(f)
+
+	req := withAdminTestUser(httptest.NewRequest(http.MethodGet, "/admin/users/"+target.id.String(), nil))
+	req.SetPathValue("id", target.id.String())
+	rr := httptest.NewRecorder()
+	h.UserDetail(rr, req)
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("expected status 500 rather than showing the tier limits, got %d", rr.Code)
+	}
+
+	// Without an override the tier limits are shown
+	f.failOverride = false
+	rr = httptest.NewRecorder()
+	h.UserDetail(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Errorf("expected status 200 without an override, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
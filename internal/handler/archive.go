@@ -0,0 +1,343 @@
+// Package handler contains HTTP handlers for the Lukaut application.
+//
+// This file implements the inspection archive download: a single ZIP with
+// the latest report PDF, every original image, and a JSON manifest.
+package handler
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/auth"
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/service"
+	"github.com/DukeRupert/lukaut/internal/storage"
+	"github.com/google/uuid"
+)
+
+// Entry names inside the archive.
+const (
+	archiveManifestName = "manifest.json"
+	archiveReportName   = "report.pdf"
+	archiveImagesDir    = "images/"
+)
+
+// ArchiveHandler serves inspection archives.
+type ArchiveHandler struct {
+	inspectionService service.InspectionService
+	imageService      service.ImageService
+	violationService  service.ViolationService
+	reportService     service.ReportService
+	storage           storage.Storage
+	logger            *slog.Logger
+}
+
+// NewArchiveHandler creates a new ArchiveHandler.
+func NewArchiveHandler(
+	inspectionService service.InspectionService,
+	imageService service.ImageService,
+	violationService service.ViolationService,
+	reportService service.ReportService,
+	storage storage.Storage,
+	logger *slog.Logger,
+) *ArchiveHandler {
+	return &ArchiveHandler{
+		inspectionService: inspectionService,
+		imageService:      imageService,
+		violationService:  violationService,
+		reportService:     reportService,
+		storage:           storage,
+		logger:            logger,
+	}
+}
+
+// =============================================================================
+// Manifest
+// =============================================================================
+
+// archiveManifest describes the archive contents. File paths are relative to
+// the archive root.
+type archiveManifest struct {
+	Version     int                `json:"version"`
+	GeneratedAt time.Time          `json:"generated_at"`
+	Inspection  archiveInspection  `json:"inspection"`
+	Report      *archiveReport     `json:"report,omitempty"`
+	Images      []archiveImage     `json:"images"`
+	Violations  []archiveViolation `json:"violations"`
+}
+
+type archiveInspection struct {
+	ID                uuid.UUID `json:"id"`
+	Title             string    `json:"title"`
+	Status            string    `json:"status"`
+	InspectionDate    string    `json:"inspection_date"`
+	ClientName        string    `json:"client_name,omitempty"`
+	AddressLine1      string    `json:"address_line1"`
+	AddressLine2      string    `json:"address_line2,omitempty"`
+	City              string    `json:"city"`
+	State             string    `json:"state"`
+	PostalCode        string    `json:"postal_code"`
+	Latitude          *float64  `json:"latitude,omitempty"`
+	Longitude         *float64  `json:"longitude,omitempty"`
+	WeatherConditions string    `json:"weather_conditions,omitempty"`
+	Temperature       string    `json:"temperature,omitempty"`
+	InspectorNotes    string    `json:"inspector_notes,omitempty"`
+}
+
+type archiveReport struct {
+	ID             uuid.UUID `json:"id"`
+	File           string    `json:"file,omitempty"`
+	GeneratedAt    time.Time `json:"generated_at"`
+	ViolationCount int       `json:"violation_count"`
+	Missing        bool      `json:"missing,omitempty"` // Storage object could not be read
+}
+
+type archiveImage struct {
+	ID               uuid.UUID `json:"id"`
+	File             string    `json:"file,omitempty"`
+	OriginalFilename string    `json:"original_filename"`
+	ContentType      string    `json:"content_type"`
+	SizeBytes        int64     `json:"size_bytes"`
+	Width            int32     `json:"width,omitempty"`
+	Height           int32     `json:"height,omitempty"`
+	UploadedAt       time.Time `json:"uploaded_at"`
+	Missing          bool      `json:"missing,omitempty"` // Storage object could not be read
+}
+
+type archiveViolation struct {
+	ID             uuid.UUID  `json:"id"`
+	ImageID        *uuid.UUID `json:"image_id,omitempty"`
+	Description    string     `json:"description"`
+	Severity       string     `json:"severity"`
+	Status         string     `json:"status"`
+	InspectorNotes string     `json:"inspector_notes,omitempty"`
+}
+
+// =============================================================================
+// GET /inspections/{id}/archive.zip - Download Archive
+// =============================================================================
+
+// Download streams a ZIP of the inspection's latest PDF report, original
+// images, and a manifest. Entries are copied straight from storage into the
+// response, so large image sets are never held in memory.
+//
+// Objects missing from storage are skipped and flagged in the manifest,
+// which is written last. Once streaming has started the status can no longer
+// change, so a storage failure mid-copy truncates the download.
+func (h *ArchiveHandler) Download(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUserFromRequest(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid inspection ID", http.StatusBadRequest)
+		return
+	}
+
+	// Ownership check: another user's inspection is reported as not found
+	inspection, err := h.inspectionService.GetByID(r.Context(), id, user.ID)
+	if err != nil {
+		ErrorResponse(w, r, h.logger, err)
+		return
+	}
+
+	images, err := h.imageService.ListByInspection(r.Context(), id, user.ID)
+	if err != nil {
+		ErrorResponse(w, r, h.logger, err)
+		return
+	}
+
+	violations, err := h.violationService.ListByInspection(r.Context(), id, user.ID, nil)
+	if err != nil {
+		ErrorResponse(w, r, h.logger, err)
+		return
+	}
+
+	reports, err := h.reportService.ListByInspection(r.Context(), id, user.ID)
+	if err != nil {
+		ErrorResponse(w, r, h.logger, err)
+		return
+	}
+
+	manifest := newArchiveManifest(inspection, violations)
+
+	filename := fmt.Sprintf("inspection-%s.zip", inspection.ID.String()[:8])
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+
+	zw := zip.NewWriter(w)
+
+	// Reports are listed newest first
+	for _, report := range reports {
+		if report.IsFailed() || !report.HasPDF() {
+			continue
+		}
+		entry := &archiveReport{
+			ID:             report.ID,
+			GeneratedAt:    report.GeneratedAt,
+			ViolationCount: report.ViolationCount,
+		}
+		ok, err := h.copyObject(r, zw, report.PDFStorageKey, archiveReportName, report.GeneratedAt)
+		if err != nil {
+			h.logger.ErrorContext(r.Context(), "failed to stream archive report", "error", err, "inspection_id", id, "report_id", report.ID)
+			return
+		}
+		if ok {
+			entry.File = archiveReportName
+		} else {
+			entry.Missing = true
+		}
+		manifest.Report = entry
+		break
+	}
+
+	for i, img := range images {
+		entry := archiveImage{
+			ID:               img.ID,
+			OriginalFilename: img.OriginalFilename,
+			ContentType:      img.ContentType,
+			SizeBytes:        img.SizeBytes,
+			Width:            img.Width,
+			Height:           img.Height,
+			UploadedAt:       img.CreatedAt,
+		}
+		name := archiveImageName(i, img)
+		ok, err := h.copyObject(r, zw, img.StorageKey, name, img.CreatedAt)
+		if err != nil {
+			h.logger.ErrorContext(r.Context(), "failed to stream archive image", "error", err, "inspection_id", id, "image_id", img.ID)
+			return
+		}
+		if ok {
+			entry.File = name
+		} else {
+			entry.Missing = true
+		}
+		manifest.Images = append(manifest.Images, entry)
+	}
+
+	mw, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     archiveManifestName,
+		Method:   zip.Deflate,
+		Modified: manifest.GeneratedAt,
+	})
+	if err == nil {
+		enc := json.NewEncoder(mw)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(manifest)
+	}
+	if err == nil {
+		err = zw.Close()
+	}
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to finish archive", "error", err, "inspection_id", id)
+		return
+	}
+
+	h.logger.InfoContext(r.Context(), "Inspection archive downloaded",
+		"inspection_id", id,
+		"user_id", user.ID,
+		"images", len(images),
+	)
+}
+
+// copyObject copies a storage object into the archive as name. It returns
+// false without writing an entry if the object does not exist.
+func (h *ArchiveHandler) copyObject(r *http.Request, zw *zip.Writer, key, name string, modified time.Time) (bool, error) {
+	reader, _, err := h.storage.Get(r.Context(), key)
+	if err != nil {
+		if storage.IsNotFound(err) {
+			h.logger.WarnContext(r.Context(), "archive object missing from storage", "storage_key", key)
+			return false, nil
+		}
+		return false, err
+	}
+	defer func() { _ = reader.Close() }()
+
+	// Images and PDFs are already compressed, so store them as-is
+	fw, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Store,
+		Modified: modified,
+	})
+	if err != nil {
+		return false, err
+	}
+	if _, err := io.Copy(fw, reader); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RegisterRoutes registers archive routes on the provided ServeMux.
+func (h *ArchiveHandler) RegisterRoutes(mux *http.ServeMux, requireUser func(http.Handler) http.Handler) {
+	mux.Handle("GET /inspections/{id}/archive.zip", requireUser(http.HandlerFunc(h.Download)))
+}
+
+// =============================================================================
+// Helper Functions
+// =============================================================================
+
+// newArchiveManifest builds the manifest fields known before streaming.
+func newArchiveManifest(i *domain.Inspection, violations []domain.Violation) *archiveManifest {
+	m := &archiveManifest{
+		Version:     1,
+		GeneratedAt: time.Now().UTC(),
+		Inspection: archiveInspection{
+			ID:                i.ID,
+			Title:             i.Title,
+			Status:            string(i.Status),
+			InspectionDate:    i.InspectionDate.Format("2006-01-02"),
+			ClientName:        i.ClientName,
+			AddressLine1:      i.AddressLine1,
+			AddressLine2:      i.AddressLine2,
+			City:              i.City,
+			State:             i.State,
+			PostalCode:        i.PostalCode,
+			Latitude:          i.Latitude,
+			Longitude:         i.Longitude,
+			WeatherConditions: i.WeatherConditions,
+			Temperature:       i.Temperature,
+			InspectorNotes:    i.InspectorNotes,
+		},
+		Images:     []archiveImage{},
+		Violations: make([]archiveViolation, 0, len(violations)),
+	}
+	for _, v := range violations {
+		m.Violations = append(m.Violations, archiveViolation{
+			ID:             v.ID,
+			ImageID:        v.ImageID,
+			Description:    v.Description,
+			Severity:       string(v.Severity),
+			Status:         string(v.Status),
+			InspectorNotes: v.InspectorNotes,
+		})
+	}
+	return m
+}
+
+// archiveImageName returns the archive path for the i-th image, e.g.
+// "images/001-IMG_0042.jpg". The index keeps names unique when uploads share
+// a filename.
+func archiveImageName(i int, img domain.Image) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		}
+		return '_'
+	}, path.Base(strings.ReplaceAll(img.OriginalFilename, "\\", "/")))
+	if name == "" || name == "." || name == "_" {
+		name = img.ID.String()
+	}
+	return fmt.Sprintf("%s%03d-%s", archiveImagesDir, i+1, name)
+}
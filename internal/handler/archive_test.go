@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/auth"
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/service"
+	"github.com/google/uuid"
+)
+
+// fakeArchiveInspectionService returns one inspection to its owner.
+type fakeArchiveInspectionService struct {
+	service.InspectionService
+	inspection *domain.Inspection
+}
+
+func (f *fakeArchiveInspectionService) GetByID(ctx context.Context, id, userID uuid.UUID) (*domain.Inspection, error) {
+	if id != f.inspection.ID || userID != f.inspection.UserID {
+		return nil, domain.NotFound("InspectionService.GetByID", "inspection", id.String())
+	}
+	return f.inspection, nil
+}
+
+// fakeArchiveViolationService returns fixed violations.
+type fakeArchiveViolationService struct {
+	service.ViolationService
+	violations []domain.Violation
+}
+
+func (f *fakeArchiveViolationService) ListByInspection(ctx context.Context, inspectionID, userID uuid.UUID, filter *domain.ViolationFilter) ([]domain.Violation, error) {
+	return f.violations, nil
+}
+
+// newArchiveTestHandler returns a handler for an inspection with two images,
+// one violation, and a PDF report. The second image is missing from storage.
+func newArchiveTestHandler(inspection *domain.Inspection) *ArchiveHandler {
+	imageID := uuid.New()
+	images := &mockImageService{
+		ListByInspectionFunc: func(ctx context.Context, inspectionID, userID uuid.UUID) ([]domain.Image, error) {
+			return []domain.Image{
+				{ID: imageID, StorageKey: "images/a.jpg", OriginalFilename: "IMG_0042.jpg", ContentType: "image/jpeg", SizeBytes: 5, CreatedAt: time.Now()},
+				{ID: uuid.New(), StorageKey: "images/gone.jpg", OriginalFilename: "../../etc/passwd", ContentType: "image/jpeg", CreatedAt: time.Now()},
+			}, nil
+		},
+	}
+	reports := &mockReportService{
+		ListByInspectionFunc: func(ctx context.Context, inspectionID, userID uuid.UUID) ([]domain.Report, error) {
+			failedAt := time.Now()
+			return []domain.Report{
+				{ID: uuid.New(), FailedAt: &failedAt, FailedFormat: domain.ReportFormatPDF},
+				{ID: uuid.New(), DOCXStorageKey: "reports/new.docx", GeneratedAt: time.Now()},
+				{ID: uuid.New(), PDFStorageKey: "reports/r.pdf", ViolationCount: 1, GeneratedAt: time.Now()},
+			}, nil
+		},
+	}
+	violations := &fakeArchiveViolationService{violations: []domain.Violation{
+		{ID: uuid.New(), ImageID: &imageID, Description: "Missing guardrail", Severity: domain.ViolationSeveritySerious, Status: domain.ViolationStatusConfirmed},
+	}}
+	store := &mockStorage{Objects: map[string][]byte{
+		"images/a.jpg":  []byte("jpeg!"),
+		"reports/r.pdf": []byte("%PDF-1.7"),
+	}}
+	return NewArchiveHandler(&fakeArchiveInspectionService{inspection: inspection}, images, violations, reports, store, newTestLogger())
+}
+
+func serveArchive(h *ArchiveHandler, inspectionID uuid.UUID, user *domain.User) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/inspections/"+inspectionID.String()+"/archive.zip", nil)
+	req.SetPathValue("id", inspectionID.String())
+	req = req.WithContext(auth.SetUser(req.Context(), user))
+	rr := httptest.NewRecorder()
+	h.Download(rr, req)
+	return rr
+}
+
+func TestArchiveDownload_ContainsReportImagesAndManifest(t *testing.T) {
+	owner := &domain.User{ID: uuid.New()}
+	inspection := &domain.Inspection{ID: uuid.New(), UserID: owner.ID, Title: "Warehouse walk", Status: domain.InspectionStatusCompleted}
+	h := newArchiveTestHandler(inspection)
+
+	rr := serveArchive(h, inspection.ID, owner)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "application/zip" {
+		t.Errorf("expected application/zip, got %q", ct)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(rr.Body.Bytes()), int64(rr.Body.Len()))
+	if err != nil {
+		t.Fatalf("response is not a valid ZIP: %v", err)
+	}
+	entries := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", f.Name, err)
+		}
+		data, _ := io.ReadAll(rc)
+		_ = rc.Close()
+		entries[f.Name] = string(data)
+	}
+
+	if len(entries) != 3 {
+		t.Errorf("expected report, one image, and manifest, got %v", len(entries))
+	}
+	if entries["report.pdf"] != "%PDF-1.7" {
+		t.Errorf("expected latest PDF report, got %q", entries["report.pdf"])
+	}
+	if entries["images/001-IMG_0042.jpg"] != "jpeg!" {
+		t.Errorf("expected original image, got entries %v", entries)
+	}
+
+	var manifest archiveManifest
+	if err := json.Unmarshal([]byte(entries["manifest.json"]), &manifest); err != nil {
+		t.Fatalf("invalid manifest: %v", err)
+	}
+	if manifest.Inspection.ID != inspection.ID || manifest.Inspection.Title != "Warehouse walk" {
+		t.Errorf("unexpected manifest inspection: %+v", manifest.Inspection)
+	}
+	if manifest.Report == nil || manifest.Report.File != "report.pdf" || manifest.Report.ViolationCount != 1 {
+		t.Errorf("unexpected manifest report: %+v", manifest.Report)
+	}
+	if len(manifest.Images) != 2 {
+		t.Fatalf("expected 2 images in manifest, got %d", len(manifest.Images))
+	}
+	if manifest.Images[0].File != "images/001-IMG_0042.jpg" || manifest.Images[0].Missing {
+		t.Errorf("unexpected first image entry: %+v", manifest.Images[0])
+	}
+	if manifest.Images[1].File != "" || !manifest.Images[1].Missing {
+		t.Errorf("expected missing image to be flagged, got %+v", manifest.Images[1])
+	}
+	if len(manifest.Violations) != 1 || manifest.Violations[0].Description != "Missing guardrail" {
+		t.Errorf("unexpected manifest violations: %+v", manifest.Violations)
+	}
+}
+
+func TestArchiveDownload_ForeignInspectionNotFound(t *testing.T) {
+	owner := &domain.User{ID: uuid.New()}
+	inspection := &domain.Inspection{ID: uuid.New(), UserID: owner.ID}
+	h := newArchiveTestHandler(inspection)
+
+	rr := serveArchive(h, inspection.ID, &domain.User{ID: uuid.New()})
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", rr.Code)
+	}
+	if rr.Header().Get("Content-Type") == "application/zip" {
+		t.Error("expected no archive for another user's inspection")
+	}
+}
+
+func TestArchiveImageName(t *testing.T) {
+	id := uuid.New()
+	tests := []struct {
+		filename string
+		want     string
+	}{
+		{"IMG_0042.jpg", "images/001-IMG_0042.jpg"},
+		{"site photo (1).png", "images/001-site_photo__1_.png"},
+		{"../../etc/passwd", "images/001-passwd"},
+		{`C:\Users\pat\roof.jpg`, "images/001-roof.jpg"},
+		{"", "images/001-" + id.String()},
+	}
+	for _, tt := range tests {
+		if got := archiveImageName(0, domain.Image{ID: id, OriginalFilename: tt.filename}); got != tt.want {
+			t.Errorf("archiveImageName(%q) = %q, want %q", tt.filename, got, tt.want)
+		}
+	}
+}
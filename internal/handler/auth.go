@@ -51,57 +51,64 @@ type AuthRateLimiter interface {
 	RecordFailedLogin(ip string)
 	// ResetLogin clears the rate limit for an IP after successful login.
 	ResetLogin(ip string)
+	// AllowVerificationResend reports whether the user may be sent another
+	// verification email.
+	AllowVerificationResend(userID string) bool
 }
 
 // AuthHandler handles authentication-related HTTP requests.
 //
 // Dependencies:
 // - userService: Business logic for user operations (registration, login, logout)
-// - emailService: Service for sending transactional emails
+// - emailQueue: Queue for transactional emails (delivered by the background worker)
 // - inviteValidator: Validates invite codes for MVP testing
 // - logger: Structured logging for request handling
 // - isSecure: Whether to set Secure flag on cookies (true in production)
 //
 // Routes handled:
-// - GET  /register -> ShowRegisterTempl
-// - POST /register -> RegisterTempl
-// - GET  /login    -> ShowLoginTempl
-// - POST /login    -> LoginTempl
-// - POST /logout   -> Logout
+// - GET  /register   -> ShowRegisterTempl
+// - POST /register   -> RegisterTempl
+// - GET  /login      -> ShowLoginTempl
+// - POST /login      -> LoginTempl
+// - POST /logout     -> Logout
+// - GET  /logout     -> ConfirmLogout
+// - POST /logout/all -> LogoutAll
 type AuthHandler struct {
 	userService     service.UserService
-	emailService    email.EmailService
+	emailQueue      email.Queue
 	inviteValidator *invite.Validator
 	rateLimiter     AuthRateLimiter
 	logger          *slog.Logger
 	isSecure        bool
+	sessionCookie   session.Options
 }
 
 // NewAuthHandler creates a new AuthHandler with the required dependencies.
 //
 // Parameters:
 // - userService: Service for user-related operations
-// - emailService: Service for sending transactional emails
+// - emailQueue: Queue for transactional emails
 // - inviteValidator: Validator for invite codes (MVP testing)
 // - logger: Structured logger for request logging
 // - isSecure: Set to true in production (enables Secure cookie flag)
 //
 // Example usage in main.go:
 //
-//	authHandler := handler.NewAuthHandler(userService, emailService, inviteValidator, logger, cfg.Env != "development")
+//	authHandler := handler.NewAuthHandler(userService, emailQueue, inviteValidator, logger, cfg.Env != "development")
 func NewAuthHandler(
 	userService service.UserService,
-	emailService email.EmailService,
+	emailQueue email.Queue,
 	inviteValidator *invite.Validator,
 	logger *slog.Logger,
 	isSecure bool,
 ) *AuthHandler {
 	return &AuthHandler{
 		userService:     userService,
-		emailService:    emailService,
+		emailQueue:      emailQueue,
 		inviteValidator: inviteValidator,
 		logger:          logger,
 		isSecure:        isSecure,
+		sessionCookie:   session.Options{Secure: isSecure, MaxAge: service.SessionDuration(0)},
 	}
 }
 
@@ -112,6 +119,14 @@ func (h *AuthHandler) WithRateLimiter(rl AuthRateLimiter) *AuthHandler {
 	return h
 }
 
+// WithSessionDuration sets the session cookie's lifetime to the user
+// service's session duration for the same configured value, so the cookie
+// expires with the session. Without it the cookie lasts DefaultSessionDuration.
+func (h *AuthHandler) WithSessionDuration(d time.Duration) *AuthHandler {
+	h.sessionCookie.MaxAge = service.SessionDuration(d)
+	return h
+}
+
 // =============================================================================
 // Template Data Types
 // =============================================================================
@@ -158,49 +173,106 @@ type AuthPageData struct {
 // - Always redirect to login (don't show error pages)
 func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	// Get session token from cookie
-	cookie, err := r.Cookie(session.CookieName)
-	if err == nil && cookie.Value != "" {
+	if token, ok := session.Read(r); ok {
 		// Invalidate session in database
-		if err := h.userService.Logout(r.Context(), cookie.Value); err != nil {
+		if err := h.userService.Logout(r.Context(), token); err != nil {
 			// Log error but continue - cookie will be cleared anyway
-			h.logger.Warn("failed to invalidate session in database", "error", err)
+			h.logger.WarnContext(r.Context(), "failed to invalidate session in database", "error", err)
 		}
 	}
 
 	// Clear session cookie (always, regardless of database result)
-	clearSessionCookie(w, h.isSecure)
+	session.Clear(w, h.sessionCookie)
 
 	// Log logout
-	h.logger.Debug("user logged out")
+	h.logger.DebugContext(r.Context(), "user logged out")
 
 	// Redirect to login with success message
 	http.Redirect(w, r, "/login?logout=1", http.StatusSeeOther)
 }
 
+// =============================================================================
+// GET /logout - Confirm Logout
+// =============================================================================
+
+// ConfirmLogout handles sign-out links and bookmarks.
+//
+// Any site can make the browser follow a GET link, so only same-origin
+// navigations (Sec-Fetch-Site: same-origin) sign out directly. Anything
+// else gets a confirmation page whose forms post to /logout and
+// /logout/all. Without a session there is nothing to sign out of.
+func (h *AuthHandler) ConfirmLogout(w http.ResponseWriter, r *http.Request) {
+	if _, ok := session.Read(r); !ok {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	if r.Header.Get("Sec-Fetch-Site") == "same-origin" {
+		h.Logout(w, r)
+		return
+	}
+
+	data := auth.LogoutPageData{
+		CSRFToken: csrf.EnsureToken(w, r, h.isSecure),
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := auth.LogoutPage(data).Render(r.Context(), w); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to render logout page", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// =============================================================================
+// POST /logout/all - Log Out of All Devices
+// =============================================================================
+
+// LogoutAll invalidates every session of the user, including this one, and
+// clears the session cookie. Use it when a device is lost or the password
+// may have leaked.
+func (h *AuthHandler) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	user := authpkg.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	if !csrf.ValidateRequest(r) {
+		http.Error(w, "Invalid form submission. Please refresh the page and try again.", http.StatusForbidden)
+		return
+	}
+
+	if err := h.userService.LogoutAll(r.Context(), user.ID); err != nil {
+		ErrorResponse(w, r, h.logger, err)
+		return
+	}
+
+	session.Clear(w, h.sessionCookie)
+	http.Redirect(w, r, "/login?logout=all", http.StatusSeeOther)
+}
+
 // =============================================================================
 // Email Helpers
 // =============================================================================
 
-// sendVerificationEmail creates a verification token and sends the verification email.
+// sendVerificationEmail creates a verification token and queues the
+// verification email.
 //
-// This is run asynchronously (via goroutine) to not block the HTTP response.
-// The context passed should be from the original request; we create a new
-// background context with timeout for the async operation.
+// The email is delivered by the background worker, which retries failed
+// sends, so this only blocks the request for the token insert and enqueue.
+// Failures are logged; the user can request another email from the
+// verification reminder page.
 //
 // Parameters:
-// - ctx: Original request context (used only to capture any relevant values)
+// - ctx: Request context
 // - userID: ID of the user to send verification to
 // - email: User's email address
 // - name: User's name for personalization
 func (h *AuthHandler) sendVerificationEmail(ctx context.Context, userID uuid.UUID, emailAddr, name string) {
-	// Create a new context with timeout for the async operation
-	asyncCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
 	// Create verification token
-	result, err := h.userService.CreateEmailVerificationToken(asyncCtx, userID)
+	result, err := h.userService.CreateEmailVerificationToken(ctx, userID)
 	if err != nil {
-		h.logger.Error("failed to create verification token",
+		h.logger.ErrorContext(ctx, "failed to create verification token",
 			"error", err,
 			"user_id", userID,
 			"email", emailAddr,
@@ -208,67 +280,36 @@ func (h *AuthHandler) sendVerificationEmail(ctx context.Context, userID uuid.UUI
 		return
 	}
 
-	// Send the verification email
-	if err := h.emailService.SendVerificationEmail(asyncCtx, emailAddr, name, result.Token); err != nil {
-		h.logger.Error("failed to send verification email",
+	h.queueEmail(ctx, email.TemplateVerification, emailAddr, name, result.Token)
+}
+
+// queueEmail queues a token email (verification or password reset) for
+// delivery, logging rather than returning failures so auth responses never
+// reveal whether an email was sent.
+func (h *AuthHandler) queueEmail(ctx context.Context, template, emailAddr, name, token string) {
+	err := h.emailQueue.Enqueue(ctx, email.Message{
+		Template: template,
+		To:       emailAddr,
+		Data: map[string]string{
+			email.DataName:  name,
+			email.DataToken: token,
+		},
+	})
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to queue email",
 			"error", err,
-			"user_id", userID,
+			"template", template,
 			"email", emailAddr,
 		)
 		return
 	}
 
-	h.logger.Info("verification email sent",
-		"user_id", userID,
+	h.logger.InfoContext(ctx, "email queued",
+		"template", template,
 		"email", emailAddr,
 	)
 }
 
-// =============================================================================
-// Session Cookie Helpers
-// =============================================================================
-
-// setSessionCookie sets the session cookie on the response.
-//
-// Cookie Settings:
-// - HttpOnly: true - Prevents JavaScript access (XSS protection)
-// - Secure: configurable - Set true in production (HTTPS only)
-// - SameSite: Lax - Prevents CSRF while allowing normal navigation
-// - Path: / - Cookie sent with all requests
-// - MaxAge: 7 days - Matches session duration
-//
-// Parameters:
-// - w: Response writer to set cookie on
-// - token: Raw session token (64-char hex string)
-// - isSecure: Whether to set Secure flag (true in production)
-func setSessionCookie(w http.ResponseWriter, token string, isSecure bool) {
-	http.SetCookie(w, &http.Cookie{
-		Name:     session.CookieName,
-		Value:    token,
-		Path:     session.CookiePath,
-		MaxAge:   session.CookieMaxAge,
-		HttpOnly: true,
-		Secure:   isSecure,
-		SameSite: http.SameSiteLaxMode,
-	})
-}
-
-// clearSessionCookie removes the session cookie from the client.
-//
-// This is done by setting MaxAge to -1, which tells the browser to delete
-// the cookie immediately.
-func clearSessionCookie(w http.ResponseWriter, isSecure bool) {
-	http.SetCookie(w, &http.Cookie{
-		Name:     session.CookieName,
-		Value:    "",
-		Path:     session.CookiePath,
-		MaxAge:   -1, // Delete immediately
-		HttpOnly: true,
-		Secure:   isSecure,
-		SameSite: http.SameSiteLaxMode,
-	})
-}
-
 // =============================================================================
 // Helper Functions
 // =============================================================================
@@ -335,6 +376,15 @@ func isSafeRedirectURL(rawURL string) bool {
 	return true
 }
 
+// sessionClientContext returns the request context carrying the client
+// details recorded on a new session.
+func sessionClientContext(r *http.Request) context.Context {
+	return authpkg.SetSessionClient(r.Context(), domain.SessionClient{
+		UserAgent: r.UserAgent(),
+		IPAddress: getClientIP(r),
+	})
+}
+
 // getClientIP extracts the client IP from the request, considering proxy headers.
 func getClientIP(r *http.Request) string {
 	// Check X-Forwarded-For first (most common proxy header)
@@ -377,6 +427,7 @@ func getClientIP(r *http.Request) string {
 // - GET  /login               -> ShowLoginTempl
 // - POST /login               -> LoginTempl
 // - POST /logout              -> Logout (same as before)
+// - GET  /logout              -> ConfirmLogout
 // - GET  /verify-email        -> ShowVerifyEmailTempl
 // - GET  /resend-verification -> ShowResendVerificationTempl
 // - POST /resend-verification -> ResendVerificationTempl
@@ -410,6 +461,15 @@ func (h *AuthHandler) RegisterTemplRoutes(mux *http.ServeMux) {
 
 	// Logout doesn't need rate limiting (requires valid session)
 	mux.HandleFunc("POST /logout", h.Logout)
+	mux.HandleFunc("GET /logout", h.ConfirmLogout)
+}
+
+// RegisterLogoutAllRoutes registers the log-out-of-all-devices route.
+//
+// Call this separately from RegisterTemplRoutes because it needs the
+// requireUser middleware to know whose sessions to revoke.
+func (h *AuthHandler) RegisterLogoutAllRoutes(mux *http.ServeMux, requireUser func(http.Handler) http.Handler) {
+	mux.Handle("POST /logout/all", requireUser(http.HandlerFunc(h.LogoutAll)))
 }
 
 // RegisterVerifyEmailReminderRoutes registers the email verification reminder routes.
@@ -448,6 +508,11 @@ func (h *AuthHandler) ShowLoginTempl(w http.ResponseWriter, r *http.Request) {
 			Type:    shared.FlashSuccess,
 			Message: "You have been signed out.",
 		}
+	} else if r.URL.Query().Get("logout") == "all" {
+		flash = &shared.Flash{
+			Type:    shared.FlashSuccess,
+			Message: "You have been signed out of all devices.",
+		}
 	}
 
 	// Get return_to from query params
@@ -462,7 +527,7 @@ func (h *AuthHandler) ShowLoginTempl(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := auth.LoginPage(data).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render login page", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render login page", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -473,11 +538,11 @@ func (h *AuthHandler) ShowLoginTempl(w http.ResponseWriter, r *http.Request) {
 
 // LoginTempl processes the login form submission with CSRF validation.
 func (h *AuthHandler) LoginTempl(w http.ResponseWriter, r *http.Request) {
-	h.logger.Info("login attempt", "method", r.Method, "path", r.URL.Path)
+	h.logger.InfoContext(r.Context(), "login attempt", "method", r.Method, "path", r.URL.Path)
 
 	// Parse form data
 	if err := r.ParseForm(); err != nil {
-		h.logger.Error("failed to parse form", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to parse form", "error", err)
 		h.renderLoginTemplError(w, r, auth.FormData{}, nil, &shared.Flash{
 			Type:    shared.FlashError,
 			Message: "Invalid form submission. Please try again.",
@@ -487,7 +552,7 @@ func (h *AuthHandler) LoginTempl(w http.ResponseWriter, r *http.Request) {
 
 	// Validate CSRF token
 	if !csrf.ValidateRequest(r) {
-		h.logger.Warn("CSRF validation failed")
+		h.logger.WarnContext(r.Context(), "CSRF validation failed")
 		h.renderLoginTemplError(w, r, auth.FormData{}, nil, &shared.Flash{
 			Type:    shared.FlashError,
 			Message: "Invalid security token. Please try again.",
@@ -522,7 +587,7 @@ func (h *AuthHandler) LoginTempl(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Call UserService.Login
-	loginResult, err := h.userService.Login(r.Context(), email, password)
+	loginResult, err := h.userService.Login(sessionClientContext(r), email, password)
 	if err != nil {
 		// Record failed login attempt for rate limiting
 		if h.rateLimiter != nil {
@@ -533,13 +598,15 @@ func (h *AuthHandler) LoginTempl(w http.ResponseWriter, r *http.Request) {
 		code := domain.ErrorCode(err)
 		switch code {
 		case domain.EUNAUTHORIZED:
-			h.logger.Info("login failed: invalid credentials", "email", email)
+			// The service message is either the generic invalid-credentials
+			// message or, after a correct password, the account-disabled one.
+			h.logger.InfoContext(r.Context(), "login failed: unauthorized", "email", email)
 			h.renderLoginTemplError(w, r, formValues, nil, &shared.Flash{
 				Type:    shared.FlashError,
-				Message: "Invalid email or password",
+				Message: domain.ErrorMessage(err),
 			})
 		default:
-			h.logger.Error("login failed", "error", err, "email", email)
+			h.logger.ErrorContext(r.Context(), "login failed", "error", err, "email", email)
 			h.renderLoginTemplError(w, r, formValues, nil, &shared.Flash{
 				Type:    shared.FlashError,
 				Message: "Login failed. Please try again later.",
@@ -555,13 +622,13 @@ func (h *AuthHandler) LoginTempl(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Set session cookie
-	setSessionCookie(w, loginResult.Token, h.isSecure)
+	session.Set(w, loginResult.Token, h.sessionCookie)
 
 	// Refresh CSRF token after successful login
 	csrf.RefreshToken(w, h.isSecure)
 
 	// Log successful login
-	h.logger.Info("user logged in",
+	h.logger.InfoContext(r.Context(), "user logged in",
 		"user_id", loginResult.User.ID,
 		"email", loginResult.User.Email,
 	)
@@ -608,14 +675,14 @@ func (h *AuthHandler) renderLoginTemplError(
 	// For htmx requests, return just the form partial
 	if r.Header.Get("HX-Request") == "true" {
 		if err := auth.LoginForm(data).Render(r.Context(), w); err != nil {
-			h.logger.Error("failed to render login form", "error", err)
+			h.logger.ErrorContext(r.Context(), "failed to render login form", "error", err)
 			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 		}
 		return
 	}
 
 	if err := auth.LoginPage(data).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render login page", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render login page", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -642,7 +709,7 @@ func (h *AuthHandler) ShowRegisterTempl(w http.ResponseWriter, r *http.Request)
 	}
 
 	if err := auth.RegisterPage(data).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render register page", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render register page", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -655,7 +722,7 @@ func (h *AuthHandler) ShowRegisterTempl(w http.ResponseWriter, r *http.Request)
 func (h *AuthHandler) RegisterTempl(w http.ResponseWriter, r *http.Request) {
 	// Parse form data
 	if err := r.ParseForm(); err != nil {
-		h.logger.Error("failed to parse form", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to parse form", "error", err)
 		h.renderRegisterTemplError(w, r, auth.FormData{}, nil, &shared.Flash{
 			Type:    shared.FlashError,
 			Message: "Invalid form submission. Please try again.",
@@ -665,7 +732,7 @@ func (h *AuthHandler) RegisterTempl(w http.ResponseWriter, r *http.Request) {
 
 	// Validate CSRF token
 	if !csrf.ValidateRequest(r) {
-		h.logger.Warn("CSRF validation failed")
+		h.logger.WarnContext(r.Context(), "CSRF validation failed")
 		h.renderRegisterTemplError(w, r, auth.FormData{}, nil, &shared.Flash{
 			Type:    shared.FlashError,
 			Message: "Invalid security token. Please try again.",
@@ -746,12 +813,17 @@ func (h *AuthHandler) RegisterTempl(w http.ResponseWriter, r *http.Request) {
 			errors["email"] = "An account with this email already exists"
 			h.renderRegisterTemplError(w, r, formValues, errors, nil)
 		case domain.EINVALID:
+			if service.IsWeakPassword(err) {
+				errors["password"] = domain.ErrorMessage(err)
+				h.renderRegisterTemplError(w, r, formValues, errors, nil)
+				return
+			}
 			h.renderRegisterTemplError(w, r, formValues, nil, &shared.Flash{
 				Type:    shared.FlashError,
 				Message: domain.ErrorMessage(err),
 			})
 		default:
-			h.logger.Error("registration failed", "error", err, "email", email)
+			h.logger.ErrorContext(r.Context(), "registration failed", "error", err, "email", email)
 			h.renderRegisterTemplError(w, r, formValues, nil, &shared.Flash{
 				Type:    shared.FlashError,
 				Message: "Registration failed. Please try again later.",
@@ -761,24 +833,24 @@ func (h *AuthHandler) RegisterTempl(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Create verification token and send email
-	go h.sendVerificationEmail(r.Context(), user.ID, user.Email, user.Name)
+	h.sendVerificationEmail(r.Context(), user.ID, user.Email, user.Name)
 
 	// Registration successful - log the user in automatically
-	loginResult, err := h.userService.Login(r.Context(), email, password)
+	loginResult, err := h.userService.Login(sessionClientContext(r), email, password)
 	if err != nil {
-		h.logger.Error("auto-login after registration failed", "error", err, "email", email)
+		h.logger.ErrorContext(r.Context(), "auto-login after registration failed", "error", err, "email", email)
 		http.Redirect(w, r, "/login?registered=1", http.StatusSeeOther)
 		return
 	}
 
 	// Set session cookie
-	setSessionCookie(w, loginResult.Token, h.isSecure)
+	session.Set(w, loginResult.Token, h.sessionCookie)
 
 	// Refresh CSRF token after successful registration/login
 	csrf.RefreshToken(w, h.isSecure)
 
 	// Log successful registration
-	h.logger.Info("user registered and logged in",
+	h.logger.InfoContext(r.Context(), "user registered and logged in",
 		"user_id", loginResult.User.ID,
 		"email", loginResult.User.Email,
 	)
@@ -816,7 +888,7 @@ func (h *AuthHandler) renderRegisterTemplError(
 	}
 
 	if err := auth.RegisterPage(data).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render register page", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render register page", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -844,7 +916,7 @@ func (h *AuthHandler) ShowVerifyEmailTempl(w http.ResponseWriter, r *http.Reques
 		case domain.ECONFLICT:
 			h.renderVerifyEmailTemplSuccess(w, r, "Your email is already verified. You can sign in to your account.")
 		default:
-			h.logger.Error("email verification failed", "error", err)
+			h.logger.ErrorContext(r.Context(), "email verification failed", "error", err)
 			h.renderVerifyEmailTemplError(w, r, "Verification failed. Please try again later.")
 		}
 		return
@@ -860,7 +932,7 @@ func (h *AuthHandler) renderVerifyEmailTemplSuccess(w http.ResponseWriter, r *ht
 		Message: message,
 	}
 	if err := auth.VerifyEmailPage(data).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render verify email page", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render verify email page", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -871,7 +943,7 @@ func (h *AuthHandler) renderVerifyEmailTemplError(w http.ResponseWriter, r *http
 		Message: message,
 	}
 	if err := auth.VerifyEmailPage(data).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render verify email page", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render verify email page", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -892,7 +964,7 @@ func (h *AuthHandler) ShowResendVerificationTempl(w http.ResponseWriter, r *http
 	}
 
 	if err := auth.ResendVerificationPage(data).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render resend verification page", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render resend verification page", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -911,7 +983,7 @@ func (h *AuthHandler) ResendVerificationTempl(w http.ResponseWriter, r *http.Req
 
 	// Validate CSRF token
 	if !csrf.ValidateRequest(r) {
-		h.logger.Warn("CSRF validation failed")
+		h.logger.WarnContext(r.Context(), "CSRF validation failed")
 		h.renderResendVerificationTemplError(w, r, "Invalid security token. Please try again.")
 		return
 	}
@@ -927,30 +999,20 @@ func (h *AuthHandler) ResendVerificationTempl(w http.ResponseWriter, r *http.Req
 	result, err := h.userService.ResendVerificationEmail(r.Context(), emailAddr)
 	if err != nil {
 		// Log error but show generic success (prevents enumeration)
-		h.logger.Debug("resend verification failed", "error", err, "email", emailAddr)
+		h.logger.DebugContext(r.Context(), "resend verification failed", "error", err, "email", emailAddr)
 	} else {
 		// Get user name for the email
 		user, err := h.userService.GetByID(r.Context(), result.UserID)
 		if err != nil {
-			h.logger.Error("failed to get user for resend verification", "error", err, "user_id", result.UserID)
+			h.logger.ErrorContext(r.Context(), "failed to get user for resend verification", "error", err, "user_id", result.UserID)
 		} else {
-			// Send the email asynchronously
-			go func() {
-				ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-				defer cancel()
-
-				if err := h.emailService.SendVerificationEmail(ctx, emailAddr, user.Name, result.Token); err != nil {
-					h.logger.Error("failed to send verification email", "error", err, "email", emailAddr)
-				} else {
-					h.logger.Info("verification email sent", "email", emailAddr)
-				}
-			}()
+			h.queueEmail(r.Context(), email.TemplateVerification, emailAddr, user.Name, result.Token)
 		}
 	}
 
 	// Always show success (don't reveal if email exists)
 	if err := auth.ResendVerificationSentPage().Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render resend verification sent page", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render resend verification sent page", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -969,7 +1031,7 @@ func (h *AuthHandler) renderResendVerificationTemplError(w http.ResponseWriter,
 	}
 
 	if err := auth.ResendVerificationPage(data).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render resend verification page", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render resend verification page", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -1011,7 +1073,7 @@ func (h *AuthHandler) ShowVerifyEmailReminderTempl(w http.ResponseWriter, r *htt
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	if err := auth.VerifyEmailReminderPage(data).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render verify email reminder page", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render verify email reminder page", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -1029,6 +1091,7 @@ func (h *AuthHandler) ShowVerifyEmailReminderTempl(w http.ResponseWriter, r *htt
 //
 // Outputs (htmx):
 //   - Returns the VerifyEmailReminderResent partial (replaces the resend button)
+//   - Returns 429 if the user has asked for too many emails recently
 //
 // Side effects:
 //   - Creates a new verification token
@@ -1048,10 +1111,17 @@ func (h *AuthHandler) ResendVerificationForCurrentUserTempl(w http.ResponseWrite
 		return
 	}
 
-	// Send verification email asynchronously
-	go h.sendVerificationEmail(r.Context(), user.ID, user.Email, user.Name)
+	// Rate limit by user, since the request carries no email to key on
+	if h.rateLimiter != nil && !h.rateLimiter.AllowVerificationResend(user.ID.String()) {
+		h.logger.WarnContext(r.Context(), "verification email resend rate limited", "user_id", user.ID)
+		http.Error(w, "Too many verification emails requested. Please try again later.", http.StatusTooManyRequests)
+		return
+	}
 
-	h.logger.Info("verification email resend requested from reminder page",
+	// Queue verification email
+	h.sendVerificationEmail(r.Context(), user.ID, user.Email, user.Name)
+
+	h.logger.InfoContext(r.Context(), "verification email resend requested from reminder page",
 		"user_id", user.ID,
 		"email", user.Email,
 	)
@@ -1059,7 +1129,7 @@ func (h *AuthHandler) ResendVerificationForCurrentUserTempl(w http.ResponseWrite
 	// Return the "sent" confirmation partial for htmx swap
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	if err := auth.VerifyEmailReminderResent().Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render verification resent partial", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render verification resent partial", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -1080,7 +1150,7 @@ func (h *AuthHandler) ShowForgotPasswordTempl(w http.ResponseWriter, r *http.Req
 	}
 
 	if err := auth.ForgotPasswordPage(data).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render forgot password page", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render forgot password page", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -1093,7 +1163,7 @@ func (h *AuthHandler) ShowForgotPasswordTempl(w http.ResponseWriter, r *http.Req
 func (h *AuthHandler) ForgotPasswordTempl(w http.ResponseWriter, r *http.Request) {
 	// Parse form
 	if err := r.ParseForm(); err != nil {
-		h.logger.Error("failed to parse form", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to parse form", "error", err)
 		h.renderForgotPasswordTemplError(w, r, auth.FormData{}, nil, &shared.Flash{
 			Type:    shared.FlashError,
 			Message: "Invalid form submission. Please try again.",
@@ -1103,7 +1173,7 @@ func (h *AuthHandler) ForgotPasswordTempl(w http.ResponseWriter, r *http.Request
 
 	// Validate CSRF token
 	if !csrf.ValidateRequest(r) {
-		h.logger.Warn("CSRF validation failed")
+		h.logger.WarnContext(r.Context(), "CSRF validation failed")
 		h.renderForgotPasswordTemplError(w, r, auth.FormData{}, nil, &shared.Flash{
 			Type:    shared.FlashError,
 			Message: "Invalid security token. Please try again.",
@@ -1138,30 +1208,20 @@ func (h *AuthHandler) ForgotPasswordTempl(w http.ResponseWriter, r *http.Request
 	result, err := h.userService.CreatePasswordResetToken(r.Context(), emailAddr)
 	if err != nil {
 		// Log error but show generic success (prevents enumeration)
-		h.logger.Debug("password reset token creation failed", "error", err, "email", emailAddr)
+		h.logger.DebugContext(r.Context(), "password reset token creation failed", "error", err, "email", emailAddr)
 	} else {
 		// Get user name for the email
 		user, err := h.userService.GetByID(r.Context(), result.UserID)
 		if err != nil {
-			h.logger.Error("failed to get user for password reset", "error", err, "user_id", result.UserID)
+			h.logger.ErrorContext(r.Context(), "failed to get user for password reset", "error", err, "user_id", result.UserID)
 		} else {
-			// Send the email asynchronously
-			go func() {
-				ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-				defer cancel()
-
-				if err := h.emailService.SendPasswordResetEmail(ctx, emailAddr, user.Name, result.Token); err != nil {
-					h.logger.Error("failed to send password reset email", "error", err, "email", emailAddr)
-				} else {
-					h.logger.Info("password reset email sent", "email", emailAddr)
-				}
-			}()
+			h.queueEmail(r.Context(), email.TemplatePasswordReset, emailAddr, user.Name, result.Token)
 		}
 	}
 
 	// Always show success (don't reveal if email exists)
 	if err := auth.ForgotPasswordSentPage().Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render forgot password sent page", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render forgot password sent page", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -1187,7 +1247,7 @@ func (h *AuthHandler) renderForgotPasswordTemplError(
 	}
 
 	if err := auth.ForgotPasswordPage(data).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render forgot password page", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render forgot password page", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -1215,7 +1275,7 @@ func (h *AuthHandler) ShowResetPasswordTempl(w http.ResponseWriter, r *http.Requ
 		case domain.EINVALID:
 			h.renderResetPasswordTemplInvalid(w, r, "This reset link is invalid. Please request a new password reset.")
 		default:
-			h.logger.Error("password reset token validation failed", "error", err)
+			h.logger.ErrorContext(r.Context(), "password reset token validation failed", "error", err)
 			h.renderResetPasswordTemplInvalid(w, r, "Something went wrong. Please request a new password reset.")
 		}
 		return
@@ -1233,14 +1293,14 @@ func (h *AuthHandler) ShowResetPasswordTempl(w http.ResponseWriter, r *http.Requ
 	}
 
 	if err := auth.ResetPasswordPage(data).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render reset password page", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render reset password page", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
 
 func (h *AuthHandler) renderResetPasswordTemplInvalid(w http.ResponseWriter, r *http.Request, message string) {
 	if err := auth.ResetPasswordInvalidPage(message).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render reset password invalid page", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render reset password invalid page", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -1253,14 +1313,14 @@ func (h *AuthHandler) renderResetPasswordTemplInvalid(w http.ResponseWriter, r *
 func (h *AuthHandler) ResetPasswordTempl(w http.ResponseWriter, r *http.Request) {
 	// Parse form
 	if err := r.ParseForm(); err != nil {
-		h.logger.Error("failed to parse form", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to parse form", "error", err)
 		h.renderResetPasswordTemplInvalid(w, r, "Invalid form submission. Please try again.")
 		return
 	}
 
 	// Validate CSRF token
 	if !csrf.ValidateRequest(r) {
-		h.logger.Warn("CSRF validation failed")
+		h.logger.WarnContext(r.Context(), "CSRF validation failed")
 		h.renderResetPasswordTemplInvalid(w, r, "Invalid security token. Please try again.")
 		return
 	}
@@ -1302,7 +1362,7 @@ func (h *AuthHandler) ResetPasswordTempl(w http.ResponseWriter, r *http.Request)
 			Flash:     nil,
 		}
 		if err := auth.ResetPasswordPage(data).Render(r.Context(), w); err != nil {
-			h.logger.Error("failed to render reset password page", "error", err)
+			h.logger.ErrorContext(r.Context(), "failed to render reset password page", "error", err)
 			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 		}
 		return
@@ -1325,24 +1385,28 @@ func (h *AuthHandler) ResetPasswordTempl(w http.ResponseWriter, r *http.Request)
 				Token:     token,
 				Form:      auth.FormData{},
 				Errors:    make(map[string]string),
-				Flash: &shared.Flash{
+			}
+			if service.IsWeakPassword(err) {
+				data.Errors["password"] = domain.ErrorMessage(err)
+			} else {
+				data.Flash = &shared.Flash{
 					Type:    shared.FlashError,
 					Message: domain.ErrorMessage(err),
-				},
+				}
 			}
 			if err := auth.ResetPasswordPage(data).Render(r.Context(), w); err != nil {
-				h.logger.Error("failed to render reset password page", "error", err)
+				h.logger.ErrorContext(r.Context(), "failed to render reset password page", "error", err)
 				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 			}
 		default:
-			h.logger.Error("password reset failed", "error", err)
+			h.logger.ErrorContext(r.Context(), "password reset failed", "error", err)
 			h.renderResetPasswordTemplInvalid(w, r, "Something went wrong. Please try again.")
 		}
 		return
 	}
 
 	// Success - redirect to login with success message
-	h.logger.Info("password reset completed")
+	h.logger.InfoContext(r.Context(), "password reset completed")
 	http.Redirect(w, r, "/login?reset=1", http.StatusSeeOther)
 }
 
@@ -1355,7 +1419,7 @@ To integrate this handler into main.go:
 
 1. Initialize the UserService (already done in service layer):
 
-   userService := service.NewUserService(repo, logger)
+   userService := service.NewUserService(repo, repository.NewUnitOfWork(repo), logger)
 
 2. Create the AuthHandler:
 
@@ -1405,7 +1469,7 @@ Example complete main.go changes:
    // In run() function, after initializing repository...
 
    // Initialize services
-   userService := service.NewUserService(repo, logger)
+   userService := service.NewUserService(repo, repository.NewUnitOfWork(repo), logger)
 
    // Initialize middleware
    isSecure := cfg.Env != "development"
@@ -6,12 +6,17 @@ import (
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"strings"
 	"testing"
 
+	authpkg "github.com/DukeRupert/lukaut/internal/auth"
+	"github.com/DukeRupert/lukaut/internal/csrf"
 	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/email"
 	"github.com/DukeRupert/lukaut/internal/invite"
+	"github.com/DukeRupert/lukaut/internal/service"
 	"github.com/DukeRupert/lukaut/internal/session"
 	"github.com/google/uuid"
 )
@@ -31,6 +36,12 @@ type mockUserService struct {
 	UpdateBusinessProfileFunc                func(ctx context.Context, params domain.BusinessProfileUpdateParams) error
 	ChangePasswordFunc                       func(ctx context.Context, params domain.PasswordChangeParams) error
 	DeleteExpiredSessionsFunc                func(ctx context.Context) error
+	DisableUserFunc                          func(ctx context.Context, id uuid.UUID) error
+	EnableUserFunc                           func(ctx context.Context, id uuid.UUID) error
+	ListSessionsFunc                         func(ctx context.Context, userID uuid.UUID) ([]domain.Session, error)
+	RevokeSessionFunc                        func(ctx context.Context, userID, sessionID uuid.UUID) error
+	RevokeOtherSessionsFunc                  func(ctx context.Context, userID uuid.UUID) (int64, error)
+	LogoutAllFunc                            func(ctx context.Context, userID uuid.UUID) error
 	CreateEmailVerificationTokenFunc         func(ctx context.Context, userID uuid.UUID) (*domain.EmailVerificationResult, error)
 	VerifyEmailFunc                          func(ctx context.Context, token string) error
 	ResendVerificationEmailFunc              func(ctx context.Context, email string) (*domain.EmailVerificationResult, error)
@@ -42,6 +53,7 @@ type mockUserService struct {
 	UpdateStripeCustomerFunc                 func(ctx context.Context, userID uuid.UUID, stripeCustomerID string) error
 	UpdateSubscriptionFunc                   func(ctx context.Context, userID uuid.UUID, status, tier, subscriptionID string) error
 	GetByStripeCustomerIDFunc                func(ctx context.Context, stripeCustomerID string) (*domain.User, error)
+	CheckEmailVerificationFunc               func(user *domain.User) error
 }
 
 func (m *mockUserService) Register(ctx context.Context, params domain.RegisterParams) (*domain.User, error) {
@@ -107,6 +119,48 @@ func (m *mockUserService) DeleteExpiredSessions(ctx context.Context) error {
 	return errors.New("DeleteExpiredSessionsFunc not implemented")
 }
 
+func (m *mockUserService) DisableUser(ctx context.Context, id uuid.UUID) error {
+	if m.DisableUserFunc != nil {
+		return m.DisableUserFunc(ctx, id)
+	}
+	return errors.New("DisableUserFunc not implemented")
+}
+
+func (m *mockUserService) EnableUser(ctx context.Context, id uuid.UUID) error {
+	if m.EnableUserFunc != nil {
+		return m.EnableUserFunc(ctx, id)
+	}
+	return errors.New("EnableUserFunc not implemented")
+}
+
+func (m *mockUserService) ListSessions(ctx context.Context, userID uuid.UUID) ([]domain.Session, error) {
+	if m.ListSessionsFunc != nil {
+		return m.ListSessionsFunc(ctx, userID)
+	}
+	return nil, errors.New("ListSessionsFunc not implemented")
+}
+
+func (m *mockUserService) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	if m.RevokeSessionFunc != nil {
+		return m.RevokeSessionFunc(ctx, userID, sessionID)
+	}
+	return errors.New("RevokeSessionFunc not implemented")
+}
+
+func (m *mockUserService) RevokeOtherSessions(ctx context.Context, userID uuid.UUID) (int64, error) {
+	if m.RevokeOtherSessionsFunc != nil {
+		return m.RevokeOtherSessionsFunc(ctx, userID)
+	}
+	return 0, errors.New("RevokeOtherSessionsFunc not implemented")
+}
+
+func (m *mockUserService) LogoutAll(ctx context.Context, userID uuid.UUID) error {
+	if m.LogoutAllFunc != nil {
+		return m.LogoutAllFunc(ctx, userID)
+	}
+	return errors.New("LogoutAllFunc not implemented")
+}
+
 func (m *mockUserService) CreateEmailVerificationToken(ctx context.Context, userID uuid.UUID) (*domain.EmailVerificationResult, error) {
 	if m.CreateEmailVerificationTokenFunc != nil {
 		return m.CreateEmailVerificationTokenFunc(ctx, userID)
@@ -184,16 +238,27 @@ func (m *mockUserService) GetByStripeCustomerID(ctx context.Context, stripeCusto
 	return nil, errors.New("GetByStripeCustomerIDFunc not implemented")
 }
 
+func (m *mockUserService) CheckEmailVerification(user *domain.User) error {
+	if m.CheckEmailVerificationFunc != nil {
+		return m.CheckEmailVerificationFunc(user)
+	}
+	return nil
+}
+
 // =============================================================================
 // Mock Email Service Implementation
 // =============================================================================
 
 // mockEmailService implements the email.EmailService interface for testing.
 type mockEmailService struct {
-	SendVerificationEmailFunc   func(ctx context.Context, to, name, token string) error
-	SendPasswordResetEmailFunc  func(ctx context.Context, to, name, token string) error
-	SendReportReadyEmailFunc    func(ctx context.Context, to, name, reportURL string) error
-	SendReportToClientEmailFunc func(ctx context.Context, to, inspectorName, inspectorCompany, siteName, reportURL string) error
+	SendVerificationEmailFunc         func(ctx context.Context, to, name, token string) error
+	SendVerificationReminderEmailFunc func(ctx context.Context, to, name, token string) error
+	SendPasswordResetEmailFunc        func(ctx context.Context, to, name, token string) error
+	SendReportReadyEmailFunc          func(ctx context.Context, to, name, reportURL string) error
+	SendReportToClientEmailFunc       func(ctx context.Context, to, inspectorName, inspectorCompany, siteName, reportURL, bcc, replyTo string) error
+	SendReportFailedEmailFunc         func(ctx context.Context, to, name, inspectionURL string) error
+	SendReportDelayedEmailFunc        func(ctx context.Context, to, inspectorName, inspectorCompany, replyTo string) error
+	SendOrganizationInviteEmailFunc   func(ctx context.Context, to, inviterName, organizationName, token string) error
 }
 
 func (m *mockEmailService) SendVerificationEmail(ctx context.Context, to, name, token string) error {
@@ -203,6 +268,13 @@ func (m *mockEmailService) SendVerificationEmail(ctx context.Context, to, name,
 	return nil // Default: no-op for tests
 }
 
+func (m *mockEmailService) SendVerificationReminderEmail(ctx context.Context, to, name, token string) error {
+	if m.SendVerificationReminderEmailFunc != nil {
+		return m.SendVerificationReminderEmailFunc(ctx, to, name, token)
+	}
+	return nil
+}
+
 func (m *mockEmailService) SendPasswordResetEmail(ctx context.Context, to, name, token string) error {
 	if m.SendPasswordResetEmailFunc != nil {
 		return m.SendPasswordResetEmailFunc(ctx, to, name, token)
@@ -217,9 +289,30 @@ func (m *mockEmailService) SendReportReadyEmail(ctx context.Context, to, name, r
 	return nil
 }
 
-func (m *mockEmailService) SendReportToClientEmail(ctx context.Context, to, inspectorName, inspectorCompany, siteName, reportURL string) error {
+func (m *mockEmailService) SendReportToClientEmail(ctx context.Context, to, inspectorName, inspectorCompany, siteName, reportURL, bcc, replyTo string) error {
 	if m.SendReportToClientEmailFunc != nil {
-		return m.SendReportToClientEmailFunc(ctx, to, inspectorName, inspectorCompany, siteName, reportURL)
+		return m.SendReportToClientEmailFunc(ctx, to, inspectorName, inspectorCompany, siteName, reportURL, bcc, replyTo)
+	}
+	return nil
+}
+
+func (m *mockEmailService) SendReportFailedEmail(ctx context.Context, to, name, inspectionURL string) error {
+	if m.SendReportFailedEmailFunc != nil {
+		return m.SendReportFailedEmailFunc(ctx, to, name, inspectionURL)
+	}
+	return nil
+}
+
+func (m *mockEmailService) SendReportDelayedEmail(ctx context.Context, to, inspectorName, inspectorCompany, replyTo string) error {
+	if m.SendReportDelayedEmailFunc != nil {
+		return m.SendReportDelayedEmailFunc(ctx, to, inspectorName, inspectorCompany, replyTo)
+	}
+	return nil
+}
+
+func (m *mockEmailService) SendOrganizationInviteEmail(ctx context.Context, to, inviterName, organizationName, token string) error {
+	if m.SendOrganizationInviteEmailFunc != nil {
+		return m.SendOrganizationInviteEmailFunc(ctx, to, inviterName, organizationName, token)
 	}
 	return nil
 }
@@ -239,7 +332,7 @@ func newTestLogger() *slog.Logger {
 func newTestAuthHandler(mock *mockUserService) *AuthHandler {
 	// Create a disabled invite validator for tests (no invite code required)
 	inviteValidator := invite.New(false, nil)
-	return NewAuthHandler(mock, &mockEmailService{}, inviteValidator, newTestLogger(), false)
+	return NewAuthHandler(mock, email.NewSyncQueue(&mockEmailService{}), inviteValidator, newTestLogger(), false)
 }
 
 // =============================================================================
@@ -320,6 +413,228 @@ func TestLogout_POST_RedirectsToLogin(t *testing.T) {
 	}
 }
 
+func TestConfirmLogout_GET(t *testing.T) {
+	tests := []struct {
+		name         string
+		cookie       bool
+		fetchSite    string
+		wantLogout   bool
+		wantCode     int
+		wantLocation string
+	}{
+		{"no session", false, "same-origin", false, http.StatusSeeOther, "/login"},
+		{"same-origin link", true, "same-origin", true, http.StatusSeeOther, "/login?logout=1"},
+		{"cross-site link", true, "cross-site", false, http.StatusOK, ""},
+		{"typed URL", true, "none", false, http.StatusOK, ""},
+		{"no fetch metadata", true, "", false, http.StatusOK, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loggedOut := false
+			handler := newTestAuthHandler(&mockUserService{
+				LogoutFunc: func(ctx context.Context, token string) error {
+					loggedOut = true
+					return nil
+				},
+			})
+
+			req := httptest.NewRequest("GET", "/logout", nil)
+			if tt.cookie {
+				req.AddCookie(&http.Cookie{Name: session.CookieName, Value: "session-token-123"})
+			}
+			if tt.fetchSite != "" {
+				req.Header.Set("Sec-Fetch-Site", tt.fetchSite)
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ConfirmLogout(rec, req)
+
+			if loggedOut != tt.wantLogout {
+				t.Errorf("logged out = %v, want %v", loggedOut, tt.wantLogout)
+			}
+			if rec.Code != tt.wantCode {
+				t.Errorf("status code = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if got := rec.Header().Get("Location"); got != tt.wantLocation {
+				t.Errorf("Location = %q, want %q", got, tt.wantLocation)
+			}
+			if tt.wantCode == http.StatusOK {
+				body := rec.Body.String()
+				if !strings.Contains(body, `action="/logout"`) || !strings.Contains(body, `action="/logout/all"`) {
+					t.Error("expected the confirmation page to offer both sign-out forms")
+				}
+			}
+		})
+	}
+}
+
+func TestLogoutAll_RevokesSessionsAndClearsCookie(t *testing.T) {
+	user := &domain.User{ID: uuid.New()}
+	var revoked uuid.UUID
+	handler := newTestAuthHandler(&mockUserService{
+		LogoutAllFunc: func(ctx context.Context, userID uuid.UUID) error {
+			revoked = userID
+			return nil
+		},
+	})
+
+	req := newPasswordFormRequest("/logout/all", url.Values{})
+	req = req.WithContext(authpkg.SetUser(req.Context(), user))
+	rec := httptest.NewRecorder()
+
+	handler.LogoutAll(rec, req)
+
+	if revoked != user.ID {
+		t.Errorf("revoked sessions of %v, want %v", revoked, user.ID)
+	}
+	if location := rec.Header().Get("Location"); location != "/login?logout=all" {
+		t.Errorf("Location = %q, want /login?logout=all", location)
+	}
+	var cleared bool
+	for _, cookie := range rec.Result().Cookies() {
+		if cookie.Name == session.CookieName && cookie.MaxAge == -1 {
+			cleared = true
+		}
+	}
+	if !cleared {
+		t.Error("expected the session cookie to be cleared")
+	}
+}
+
+func TestLogoutAll_RequiresCSRF(t *testing.T) {
+	handler := newTestAuthHandler(&mockUserService{
+		LogoutAllFunc: func(ctx context.Context, userID uuid.UUID) error {
+			t.Error("expected no sessions to be revoked")
+			return nil
+		},
+	})
+
+	req := httptest.NewRequest("POST", "/logout/all", nil)
+	req = req.WithContext(authpkg.SetUser(req.Context(), &domain.User{ID: uuid.New()}))
+	rec := httptest.NewRecorder()
+
+	handler.LogoutAll(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("status code = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+}
+
+// =============================================================================
+// Verify Email Reminder Resend Tests
+// =============================================================================
+
+// fakeAuthRateLimiter allows a fixed number of verification resends per user.
+type fakeAuthRateLimiter struct {
+	resendLimit int
+	resends     map[string]int
+}
+
+func (f *fakeAuthRateLimiter) LimitLogin(next http.Handler) http.Handler         { return next }
+func (f *fakeAuthRateLimiter) LimitRegister(next http.Handler) http.Handler      { return next }
+func (f *fakeAuthRateLimiter) LimitPasswordReset(next http.Handler) http.Handler { return next }
+func (f *fakeAuthRateLimiter) RecordFailedLogin(ip string)                       {}
+func (f *fakeAuthRateLimiter) ResetLogin(ip string)                              {}
+
+func (f *fakeAuthRateLimiter) AllowVerificationResend(userID string) bool {
+	if f.resends == nil {
+		f.resends = make(map[string]int)
+	}
+	f.resends[userID]++
+	return f.resends[userID] <= f.resendLimit
+}
+
+func TestResendVerificationForCurrentUser_SendsToSessionUser(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Email: "pat@example.com", Name: "Pat", EmailVerified: false}
+
+	var tokenFor uuid.UUID
+	mock := &mockUserService{
+		CreateEmailVerificationTokenFunc: func(ctx context.Context, userID uuid.UUID) (*domain.EmailVerificationResult, error) {
+			tokenFor = userID
+			return &domain.EmailVerificationResult{Token: "verify-token-123"}, nil
+		},
+	}
+	var sentTo, sentToken string
+	emails := &mockEmailService{
+		SendVerificationEmailFunc: func(ctx context.Context, to, name, token string) error {
+			sentTo, sentToken = to, token
+			return nil
+		},
+	}
+	handler := NewAuthHandler(mock, email.NewSyncQueue(emails), invite.New(false, nil), newTestLogger(), false).
+		WithRateLimiter(&fakeAuthRateLimiter{resendLimit: 3})
+
+	// The form's email field must be ignored in favour of the session user
+	req := httptest.NewRequest("POST", "/verify-email-reminder/resend", strings.NewReader("email=other%40example.com"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req = req.WithContext(authpkg.SetUser(req.Context(), user))
+	rec := httptest.NewRecorder()
+
+	handler.ResendVerificationForCurrentUserTempl(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status code = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if tokenFor != user.ID {
+		t.Errorf("token created for %v, want %v", tokenFor, user.ID)
+	}
+	if sentTo != user.Email || sentToken != "verify-token-123" {
+		t.Errorf("email sent to %q with token %q, want %q with the new token", sentTo, sentToken, user.Email)
+	}
+	if !strings.Contains(rec.Body.String(), "Verification email sent") {
+		t.Error("expected the sent confirmation partial")
+	}
+}
+
+func TestResendVerificationForCurrentUser_RateLimited(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Email: "pat@example.com", EmailVerified: false}
+
+	tokens := 0
+	mock := &mockUserService{
+		CreateEmailVerificationTokenFunc: func(ctx context.Context, userID uuid.UUID) (*domain.EmailVerificationResult, error) {
+			tokens++
+			return &domain.EmailVerificationResult{Token: "verify-token-123"}, nil
+		},
+	}
+	handler := newTestAuthHandler(mock).WithRateLimiter(&fakeAuthRateLimiter{resendLimit: 1})
+
+	codes := make([]int, 2)
+	for i := range codes {
+		req := httptest.NewRequest("POST", "/verify-email-reminder/resend", nil)
+		req = req.WithContext(authpkg.SetUser(req.Context(), user))
+		rec := httptest.NewRecorder()
+		handler.ResendVerificationForCurrentUserTempl(rec, req)
+		codes[i] = rec.Code
+	}
+
+	if codes[0] != http.StatusOK || codes[1] != http.StatusTooManyRequests {
+		t.Errorf("status codes = %v, want [200 429]", codes)
+	}
+	if tokens != 1 {
+		t.Errorf("created %d tokens, want 1", tokens)
+	}
+}
+
+func TestResendVerificationForCurrentUser_AlreadyVerified(t *testing.T) {
+	handler := newTestAuthHandler(&mockUserService{
+		CreateEmailVerificationTokenFunc: func(ctx context.Context, userID uuid.UUID) (*domain.EmailVerificationResult, error) {
+			t.Error("expected no token for a verified user")
+			return nil, nil
+		},
+	})
+
+	req := httptest.NewRequest("POST", "/verify-email-reminder/resend", nil)
+	req = req.WithContext(authpkg.SetUser(req.Context(), &domain.User{ID: uuid.New(), EmailVerified: true}))
+	rec := httptest.NewRecorder()
+
+	handler.ResendVerificationForCurrentUserTempl(rec, req)
+
+	if got := rec.Header().Get("HX-Redirect"); got != "/dashboard" {
+		t.Errorf("HX-Redirect = %q, want /dashboard", got)
+	}
+}
+
 // =============================================================================
 // isSafeRedirectURL Tests (P0)
 // =============================================================================
@@ -429,3 +744,64 @@ func TestIsSafeRedirectURL_NoLeadingSlash_Unsafe(t *testing.T) {
 		})
 	}
 }
+
+// =============================================================================
+// Password Rejection Tests
+// =============================================================================
+
+// newPasswordFormRequest builds a POST with a valid CSRF cookie and token.
+func newPasswordFormRequest(target string, form url.Values) *http.Request {
+	form.Set(csrf.FormFieldName, "csrf-token-123")
+	req := httptest.NewRequest("POST", target, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.AddCookie(&http.Cookie{Name: csrf.CookieName, Value: "csrf-token-123"})
+	return req
+}
+
+func TestRegisterTempl_WeakPasswordShownOnPasswordField(t *testing.T) {
+	mock := &mockUserService{
+		RegisterFunc: func(ctx context.Context, params domain.RegisterParams) (*domain.User, error) {
+			return nil, domain.Wrap(service.ErrWeakPassword, domain.EINVALID, "UserService.Register", service.ErrMsgPasswordBreached)
+		},
+	}
+	handler := newTestAuthHandler(mock)
+
+	req := newPasswordFormRequest("/register", url.Values{
+		"name":                  {"Pat"},
+		"email":                 {"pat@example.com"},
+		"password":              {"Summer2016x"},
+		"password_confirmation": {"Summer2016x"},
+		"terms":                 {"on"},
+	})
+	rec := httptest.NewRecorder()
+
+	handler.RegisterTempl(rec, req)
+
+	body := rec.Body.String()
+	if !strings.Contains(body, `data-slot="error" class="mt-2 text-sm/6 text-destructive" role="alert">`+service.ErrMsgPasswordBreached) {
+		t.Errorf("expected breached password message as a password field error, got body:\n%s", body)
+	}
+}
+
+func TestResetPasswordTempl_WeakPasswordShownOnPasswordField(t *testing.T) {
+	mock := &mockUserService{
+		ResetPasswordFunc: func(ctx context.Context, params domain.ResetPasswordParams) error {
+			return domain.Wrap(service.ErrWeakPassword, domain.EINVALID, "", service.ErrMsgPasswordTooCommon)
+		},
+	}
+	handler := newTestAuthHandler(mock)
+
+	req := newPasswordFormRequest("/reset-password", url.Values{
+		"token":                 {strings.Repeat("a", 64)},
+		"password":              {"Password1"},
+		"password_confirmation": {"Password1"},
+	})
+	rec := httptest.NewRecorder()
+
+	handler.ResetPasswordTempl(rec, req)
+
+	body := rec.Body.String()
+	if !strings.Contains(body, `data-slot="error" class="mt-2 text-sm/6 text-destructive" role="alert">`+service.ErrMsgPasswordTooCommon) {
+		t.Errorf("expected common password message as a password field error, got body:\n%s", body)
+	}
+}
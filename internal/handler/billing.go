@@ -4,8 +4,8 @@
 //
 // Routes handled:
 //   - GET  /settings/billing           -> ShowBilling
-//   - POST /settings/billing/checkout  -> CreateCheckout
-//   - POST /settings/billing/portal    -> OpenPortal
+//   - POST /billing/checkout           -> CreateCheckout
+//   - POST /billing/portal             -> OpenPortal
 //   - POST /settings/billing/cancel    -> CancelSubscription
 //   - POST /settings/billing/reactivate -> ReactivateSubscription
 //   - GET  /settings/billing/success   -> CheckoutSuccess
@@ -49,8 +49,8 @@ func NewBillingHandler(billingService billing.Service, userService service.UserS
 func (h *BillingHandler) RegisterRoutes(mux *http.ServeMux, requireUser func(http.Handler) http.Handler) {
 	mux.Handle("GET /settings/billing", requireUser(http.HandlerFunc(h.ShowBilling)))
 	mux.Handle("GET /settings/billing/success", requireUser(http.HandlerFunc(h.CheckoutSuccess)))
-	mux.Handle("POST /settings/billing/checkout", requireUser(http.HandlerFunc(h.CreateCheckout)))
-	mux.Handle("POST /settings/billing/portal", requireUser(http.HandlerFunc(h.OpenPortal)))
+	mux.Handle("POST /billing/checkout", requireUser(http.HandlerFunc(h.CreateCheckout)))
+	mux.Handle("POST /billing/portal", requireUser(http.HandlerFunc(h.OpenPortal)))
 	mux.Handle("POST /settings/billing/cancel", requireUser(http.HandlerFunc(h.CancelSubscription)))
 	mux.Handle("POST /settings/billing/reactivate", requireUser(http.HandlerFunc(h.ReactivateSubscription)))
 }
@@ -72,7 +72,7 @@ func (h *BillingHandler) ShowBilling(w http.ResponseWriter, r *http.Request) {
 	if h.billing != nil && user.SubscriptionID != "" {
 		sub, err := h.billing.GetSubscription(user.SubscriptionID)
 		if err != nil {
-			h.logger.Warn("failed to fetch stripe subscription", "error", err, "subscription_id", user.SubscriptionID)
+			h.logger.WarnContext(r.Context(), "failed to fetch stripe subscription", "error", err, "subscription_id", user.SubscriptionID)
 		} else {
 			plan.PeriodEnd = time.Unix(sub.CurrentPeriodEnd, 0).Format("January 2, 2006")
 			plan.CancelAtEnd = sub.CancelAtPeriodEnd
@@ -85,12 +85,6 @@ func (h *BillingHandler) ShowBilling(w http.ResponseWriter, r *http.Request) {
 		User:        domainUserToDisplay(user),
 		ActiveTab:   settings.TabBilling,
 		Plan:        plan,
-		Prices: settings.PriceConfig{
-			StarterMonthlyPriceID:      h.prices.StarterMonthlyPriceID,
-			StarterYearlyPriceID:       h.prices.StarterYearlyPriceID,
-			ProfessionalMonthlyPriceID: h.prices.ProfessionalMonthlyPriceID,
-			ProfessionalYearlyPriceID:  h.prices.ProfessionalYearlyPriceID,
-		},
 	}
 
 	var flash *shared.Flash
@@ -110,12 +104,15 @@ func (h *BillingHandler) ShowBilling(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	if err := settings.BillingPage(data).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render billing page", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render billing page", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
 
 // CreateCheckout creates a Stripe Checkout session and redirects to it.
+//
+// Form fields: tier ("starter" or "professional") and interval ("monthly" or
+// "yearly"). Only configured prices can be purchased.
 func (h *BillingHandler) CreateCheckout(w http.ResponseWriter, r *http.Request) {
 	user := auth.GetUser(r.Context())
 	if user == nil {
@@ -124,15 +121,20 @@ func (h *BillingHandler) CreateCheckout(w http.ResponseWriter, r *http.Request)
 	}
 
 	if h.billing == nil {
-		h.logger.Warn("checkout attempted but Stripe is not configured")
+		h.logger.WarnContext(r.Context(), "checkout attempted but Stripe is not configured")
 		http.Redirect(w, r, "/settings/billing", http.StatusSeeOther)
 		return
 	}
 
 	_ = r.ParseForm()
-	priceID := r.FormValue("price_id")
-	if priceID == "" {
-		h.logger.Warn("checkout attempted without price_id", "user_id", user.ID)
+	tier := r.FormValue("tier")
+	interval := r.FormValue("interval")
+	if interval == "" {
+		interval = billing.IntervalMonthly
+	}
+	priceID := h.prices.PriceID(tier, interval)
+	if !h.prices.IsKnown(priceID) {
+		h.logger.WarnContext(r.Context(), "checkout attempted for unknown plan", "user_id", user.ID, "tier", tier, "interval", interval)
 		http.Redirect(w, r, "/settings/billing", http.StatusSeeOther)
 		return
 	}
@@ -143,22 +145,20 @@ func (h *BillingHandler) CreateCheckout(w http.ResponseWriter, r *http.Request)
 		var err error
 		customerID, err = h.billing.CreateCustomer(user.Email, user.Name)
 		if err != nil {
-			h.logger.Error("failed to create stripe customer", "error", err, "user_id", user.ID)
-			http.Error(w, "Failed to initialize billing", http.StatusInternalServerError)
+			ServerErrorResponse(w, r, h.logger.With("user_id", user.ID), fmt.Errorf("create stripe customer: %w", err))
 			return
 		}
 		if err := h.userService.UpdateStripeCustomer(r.Context(), user.ID, customerID); err != nil {
-			h.logger.Error("failed to save stripe customer ID", "error", err, "user_id", user.ID)
+			h.logger.ErrorContext(r.Context(), "failed to save stripe customer ID", "error", err, "user_id", user.ID)
 		}
 	}
 
 	successURL := fmt.Sprintf("%s/settings/billing/success?session_id={CHECKOUT_SESSION_ID}", h.baseURL)
 	cancelURL := fmt.Sprintf("%s/settings/billing", h.baseURL)
 
-	checkoutURL, err := h.billing.CreateCheckoutSession(customerID, priceID, successURL, cancelURL)
+	checkoutURL, err := h.billing.CreateCheckoutSession(customerID, user.ID.String(), priceID, successURL, cancelURL)
 	if err != nil {
-		h.logger.Error("failed to create checkout session", "error", err, "user_id", user.ID)
-		http.Error(w, "Failed to create checkout session", http.StatusInternalServerError)
+		ServerErrorResponse(w, r, h.logger.With("user_id", user.ID), fmt.Errorf("create checkout session: %w", err))
 		return
 	}
 
@@ -174,13 +174,13 @@ func (h *BillingHandler) OpenPortal(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if h.billing == nil {
-		h.logger.Warn("portal requested but Stripe is not configured")
+		h.logger.WarnContext(r.Context(), "portal requested but Stripe is not configured")
 		http.Redirect(w, r, "/settings/billing", http.StatusSeeOther)
 		return
 	}
 
 	if user.StripeCustomerID == "" {
-		h.logger.Warn("portal requested but user has no stripe customer", "user_id", user.ID)
+		h.logger.WarnContext(r.Context(), "portal requested but user has no stripe customer", "user_id", user.ID)
 		http.Redirect(w, r, "/settings/billing", http.StatusSeeOther)
 		return
 	}
@@ -188,8 +188,7 @@ func (h *BillingHandler) OpenPortal(w http.ResponseWriter, r *http.Request) {
 	returnURL := fmt.Sprintf("%s/settings/billing", h.baseURL)
 	portalURL, err := h.billing.CreatePortalSession(user.StripeCustomerID, returnURL)
 	if err != nil {
-		h.logger.Error("failed to create portal session", "error", err, "user_id", user.ID)
-		http.Error(w, "Failed to open billing portal", http.StatusInternalServerError)
+		ServerErrorResponse(w, r, h.logger.With("user_id", user.ID), fmt.Errorf("create portal session: %w", err))
 		return
 	}
 
@@ -217,7 +216,7 @@ func (h *BillingHandler) CancelSubscription(w http.ResponseWriter, r *http.Reque
 	}
 
 	if err := h.billing.CancelSubscription(user.SubscriptionID); err != nil {
-		h.logger.Error("failed to cancel subscription", "error", err, "user_id", user.ID)
+		h.logger.ErrorContext(r.Context(), "failed to cancel subscription", "error", err, "user_id", user.ID)
 		w.Header().Set("HX-Trigger", `{"showToast": {"message": "Failed to cancel subscription. Please try again.", "type": "error"}}`)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
@@ -248,7 +247,7 @@ func (h *BillingHandler) ReactivateSubscription(w http.ResponseWriter, r *http.R
 	}
 
 	if err := h.billing.ReactivateSubscription(user.SubscriptionID); err != nil {
-		h.logger.Error("failed to reactivate subscription", "error", err, "user_id", user.ID)
+		h.logger.ErrorContext(r.Context(), "failed to reactivate subscription", "error", err, "user_id", user.ID)
 		w.Header().Set("HX-Trigger", `{"showToast": {"message": "Failed to reactivate subscription. Please try again.", "type": "error"}}`)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
@@ -268,7 +267,7 @@ func (h *BillingHandler) CheckoutSuccess(w http.ResponseWriter, r *http.Request)
 	}
 
 	sessionID := r.URL.Query().Get("session_id")
-	h.logger.Info("checkout success return", "user_id", user.ID, "session_id", sessionID)
+	h.logger.InfoContext(r.Context(), "checkout success return", "user_id", user.ID, "session_id", sessionID)
 
 	http.Redirect(w, r, "/settings/billing?updated=1", http.StatusSeeOther)
 }
@@ -0,0 +1,202 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/DukeRupert/lukaut/internal/auth"
+	"github.com/DukeRupert/lukaut/internal/billing"
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stripe/stripe-go/v79"
+)
+
+// =============================================================================
+// Mock Billing Service Implementation
+// =============================================================================
+
+// mockBillingService implements billing.Service for testing.
+type mockBillingService struct {
+	CreateCustomerFunc        func(email, name string) (string, error)
+	CreateCheckoutSessionFunc func(customerID, clientReferenceID, priceID, successURL, cancelURL string) (string, error)
+	CreatePortalSessionFunc   func(customerID, returnURL string) (string, error)
+
+	// Track calls that would reach Stripe
+	CheckoutCalled bool
+}
+
+func (m *mockBillingService) CreateCustomer(email, name string) (string, error) {
+	if m.CreateCustomerFunc != nil {
+		return m.CreateCustomerFunc(email, name)
+	}
+	return "", errors.New("CreateCustomerFunc not implemented")
+}
+
+func (m *mockBillingService) CreateCheckoutSession(customerID, clientReferenceID, priceID, successURL, cancelURL string) (string, error) {
+	m.CheckoutCalled = true
+	if m.CreateCheckoutSessionFunc != nil {
+		return m.CreateCheckoutSessionFunc(customerID, clientReferenceID, priceID, successURL, cancelURL)
+	}
+	return "", errors.New("CreateCheckoutSessionFunc not implemented")
+}
+
+func (m *mockBillingService) CreatePortalSession(customerID, returnURL string) (string, error) {
+	if m.CreatePortalSessionFunc != nil {
+		return m.CreatePortalSessionFunc(customerID, returnURL)
+	}
+	return "", errors.New("CreatePortalSessionFunc not implemented")
+}
+
+func (m *mockBillingService) GetSubscription(subscriptionID string) (*stripe.Subscription, error) {
+	return nil, errors.New("GetSubscription not implemented")
+}
+
+func (m *mockBillingService) CancelSubscription(subscriptionID string) error {
+	return errors.New("CancelSubscription not implemented")
+}
+
+func (m *mockBillingService) ReactivateSubscription(subscriptionID string) error {
+	return errors.New("ReactivateSubscription not implemented")
+}
+
+func (m *mockBillingService) VerifyWebhookSignature(payload []byte, signature string) (stripe.Event, error) {
+	return stripe.Event{}, errors.New("VerifyWebhookSignature not implemented")
+}
+
+func (m *mockBillingService) TierForPriceID(priceID string) string {
+	return ""
+}
+
+// =============================================================================
+// Test Helpers
+// =============================================================================
+
+var testPrices = billing.PriceConfig{
+	StarterMonthlyPriceID:      "price_starter_monthly",
+	StarterYearlyPriceID:       "price_starter_yearly",
+	ProfessionalMonthlyPriceID: "price_professional_monthly",
+	ProfessionalYearlyPriceID:  "price_professional_yearly",
+}
+
+func newTestBillingHandler(svc *mockBillingService, users *mockUserService) *BillingHandler {
+	return NewBillingHandler(svc, users, "https://app.example.com", testPrices, newTestLogger())
+}
+
+// newBillingFormRequest builds an authenticated form POST for the user.
+func newBillingFormRequest(path string, form url.Values, user *domain.User) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req.WithContext(auth.SetUser(req.Context(), user))
+}
+
+// =============================================================================
+// CreateCheckout Tests
+// =============================================================================
+
+func TestCreateCheckout_RedirectsToSessionForTier(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Email: "inspector@example.com", StripeCustomerID: "cus_existing"}
+	var gotCustomer, gotReference, gotPrice string
+
+	svc := &mockBillingService{
+		CreateCheckoutSessionFunc: func(customerID, clientReferenceID, priceID, successURL, cancelURL string) (string, error) {
+			gotCustomer, gotReference, gotPrice = customerID, clientReferenceID, priceID
+			return "https://checkout.stripe.com/c/pay/cs_test_123", nil
+		},
+	}
+	h := newTestBillingHandler(svc, &mockUserService{})
+
+	rr := httptest.NewRecorder()
+	h.CreateCheckout(rr, newBillingFormRequest("/billing/checkout", url.Values{
+		"tier":     {"professional"},
+		"interval": {"yearly"},
+	}, user))
+
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("expected status 303, got %d", rr.Code)
+	}
+	if loc := rr.Header().Get("Location"); loc != "https://checkout.stripe.com/c/pay/cs_test_123" {
+		t.Errorf("expected redirect to checkout, got %q", loc)
+	}
+	if gotCustomer != "cus_existing" || gotReference != user.ID.String() || gotPrice != "price_professional_yearly" {
+		t.Errorf("unexpected checkout params: customer=%q reference=%q price=%q", gotCustomer, gotReference, gotPrice)
+	}
+}
+
+func TestCreateCheckout_CreatesCustomerWhenMissing(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Email: "inspector@example.com", Name: "Pat Inspector"}
+	var savedCustomer string
+
+	svc := &mockBillingService{
+		CreateCustomerFunc: func(email, name string) (string, error) {
+			return "cus_new", nil
+		},
+		CreateCheckoutSessionFunc: func(customerID, clientReferenceID, priceID, successURL, cancelURL string) (string, error) {
+			if customerID != "cus_new" {
+				t.Errorf("expected new customer to be used, got %q", customerID)
+			}
+			return "https://checkout.stripe.com/c/pay/cs_test_456", nil
+		},
+	}
+	users := &mockUserService{
+		UpdateStripeCustomerFunc: func(ctx context.Context, userID uuid.UUID, customerID string) error {
+			savedCustomer = customerID
+			return nil
+		},
+	}
+	h := newTestBillingHandler(svc, users)
+
+	rr := httptest.NewRecorder()
+	h.CreateCheckout(rr, newBillingFormRequest("/billing/checkout", url.Values{"tier": {"starter"}}, user))
+
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("expected status 303, got %d", rr.Code)
+	}
+	if savedCustomer != "cus_new" {
+		t.Errorf("expected customer ID to be saved, got %q", savedCustomer)
+	}
+}
+
+func TestCreateCheckout_RejectsUnknownTier(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), StripeCustomerID: "cus_existing"}
+	svc := &mockBillingService{}
+	h := newTestBillingHandler(svc, &mockUserService{})
+
+	rr := httptest.NewRecorder()
+	h.CreateCheckout(rr, newBillingFormRequest("/billing/checkout", url.Values{"tier": {"enterprise"}}, user))
+
+	if rr.Code != http.StatusSeeOther || rr.Header().Get("Location") != "/settings/billing" {
+		t.Errorf("expected redirect back to billing page, got %d %q", rr.Code, rr.Header().Get("Location"))
+	}
+	if svc.CheckoutCalled {
+		t.Error("expected no checkout session for an unknown tier")
+	}
+}
+
+// =============================================================================
+// OpenPortal Tests
+// =============================================================================
+
+func TestOpenPortal_RedirectsToPortal(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), StripeCustomerID: "cus_existing"}
+	svc := &mockBillingService{
+		CreatePortalSessionFunc: func(customerID, returnURL string) (string, error) {
+			if returnURL != "https://app.example.com/settings/billing" {
+				t.Errorf("unexpected return URL %q", returnURL)
+			}
+			return "https://billing.stripe.com/p/session/test_789", nil
+		},
+	}
+	h := newTestBillingHandler(svc, &mockUserService{})
+
+	rr := httptest.NewRecorder()
+	h.OpenPortal(rr, newBillingFormRequest("/billing/portal", nil, user))
+
+	if loc := rr.Header().Get("Location"); loc != "https://billing.stripe.com/p/session/test_789" {
+		t.Errorf("expected redirect to portal, got %d %q", rr.Code, loc)
+	}
+}
@@ -84,14 +84,14 @@ func NewClientHandler(
 func (h *ClientHandler) Create(w http.ResponseWriter, r *http.Request) {
 	user := auth.GetUserFromRequest(r)
 	if user == nil {
-		h.logger.Error("create handler called without authenticated user")
+		h.logger.ErrorContext(r.Context(), "create handler called without authenticated user")
 		http.Redirect(w, r, "/login", http.StatusSeeOther)
 		return
 	}
 
 	// Parse form
 	if err := r.ParseForm(); err != nil {
-		h.logger.Error("failed to parse form", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to parse form", "error", err)
 		h.renderFormError(w, r, user, nil, nil, nil, "Invalid form submission.", false)
 		return
 	}
@@ -141,7 +141,7 @@ func (h *ClientHandler) Create(w http.ResponseWriter, r *http.Request) {
 		case domain.EINVALID:
 			h.renderFormError(w, r, user, formValues, nil, nil, domain.ErrorMessage(err), false)
 		default:
-			h.logger.Error("failed to create client", "error", err, "user_id", user.ID)
+			h.logger.ErrorContext(r.Context(), "failed to create client", "error", err, "user_id", user.ID)
 			h.renderFormError(w, r, user, formValues, nil, nil, "Failed to create client. Please try again.", false)
 		}
 		return
@@ -159,7 +159,7 @@ func (h *ClientHandler) Create(w http.ResponseWriter, r *http.Request) {
 func (h *ClientHandler) Update(w http.ResponseWriter, r *http.Request) {
 	user := auth.GetUserFromRequest(r)
 	if user == nil {
-		h.logger.Error("update handler called without authenticated user")
+		h.logger.ErrorContext(r.Context(), "update handler called without authenticated user")
 		http.Redirect(w, r, "/login", http.StatusSeeOther)
 		return
 	}
@@ -174,7 +174,7 @@ func (h *ClientHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 	// Parse form
 	if err := r.ParseForm(); err != nil {
-		h.logger.Error("failed to parse form", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to parse form", "error", err)
 		h.renderError(w, r, "Invalid form submission.")
 		return
 	}
@@ -234,7 +234,7 @@ func (h *ClientHandler) Update(w http.ResponseWriter, r *http.Request) {
 		case domain.ENOTFOUND:
 			NotFoundResponse(w, r, h.logger)
 		default:
-			h.logger.Error("failed to update client", "error", err, "client_id", id)
+			h.logger.ErrorContext(r.Context(), "failed to update client", "error", err, "client_id", id)
 			h.renderFormError(w, r, user, formValues, nil, client, "Failed to update client. Please try again.", true)
 		}
 		return
@@ -260,7 +260,7 @@ func (h *ClientHandler) Update(w http.ResponseWriter, r *http.Request) {
 func (h *ClientHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	user := auth.GetUserFromRequest(r)
 	if user == nil {
-		h.logger.Error("delete handler called without authenticated user")
+		h.logger.ErrorContext(r.Context(), "delete handler called without authenticated user")
 		http.Redirect(w, r, "/login", http.StatusSeeOther)
 		return
 	}
@@ -282,10 +282,10 @@ func (h *ClientHandler) Delete(w http.ResponseWriter, r *http.Request) {
 			NotFoundResponse(w, r, h.logger)
 		case domain.EINVALID:
 			// Client has associated sites
-			h.logger.Warn("cannot delete client with sites", "client_id", id)
+			h.logger.WarnContext(r.Context(), "cannot delete client with sites", "client_id", id)
 			h.renderError(w, r, domain.ErrorMessage(err))
 		default:
-			h.logger.Error("failed to delete client", "error", err, "client_id", id)
+			h.logger.ErrorContext(r.Context(), "failed to delete client", "error", err, "client_id", id)
 			h.renderError(w, r, "Failed to delete client. Please try again.")
 		}
 		return
@@ -321,7 +321,7 @@ func (h *ClientHandler) QuickCreateForm(w http.ResponseWriter, r *http.Request)
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	if err := partials.QuickClientForm(data).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render quick client form", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render quick client form", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -339,7 +339,7 @@ func (h *ClientHandler) QuickCreate(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := r.ParseForm(); err != nil {
-		h.logger.Error("failed to parse form", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to parse form", "error", err)
 		http.Error(w, "Invalid form", http.StatusBadRequest)
 		return
 	}
@@ -369,7 +369,7 @@ func (h *ClientHandler) QuickCreate(w http.ResponseWriter, r *http.Request) {
 		}
 		w.Header().Set("Content-Type", "text/html; charset=utf-8")
 		if err := partials.QuickClientForm(data).Render(r.Context(), w); err != nil {
-			h.logger.Error("failed to render form with errors", "error", err)
+			h.logger.ErrorContext(r.Context(), "failed to render form with errors", "error", err)
 		}
 		return
 	}
@@ -384,7 +384,7 @@ func (h *ClientHandler) QuickCreate(w http.ResponseWriter, r *http.Request) {
 
 	client, err := h.clientService.Create(r.Context(), params)
 	if err != nil {
-		h.logger.Error("failed to create client", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to create client", "error", err)
 		errors["name"] = "Failed to create client. Please try again."
 		data := partials.QuickClientFormData{
 			Form: partials.QuickClientFormValues{
@@ -396,7 +396,7 @@ func (h *ClientHandler) QuickCreate(w http.ResponseWriter, r *http.Request) {
 		}
 		w.Header().Set("Content-Type", "text/html; charset=utf-8")
 		if err := partials.QuickClientForm(data).Render(r.Context(), w); err != nil {
-			h.logger.Error("failed to render quick client form", "error", err)
+			h.logger.ErrorContext(r.Context(), "failed to render quick client form", "error", err)
 		}
 		return
 	}
@@ -408,8 +408,7 @@ func (h *ClientHandler) QuickCreate(w http.ResponseWriter, r *http.Request) {
 		Offset: 0,
 	})
 	if err != nil {
-		h.logger.Error("failed to list clients", "error", err)
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		ServerErrorResponse(w, r, h.logger, fmt.Errorf("list clients: %w", err))
 		return
 	}
 
@@ -427,7 +426,7 @@ func (h *ClientHandler) QuickCreate(w http.ResponseWriter, r *http.Request) {
 
 	// Render success message that closes the form
 	if err := partials.QuickClientSuccess(client.Name).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render success message", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render success message", "error", err)
 	}
 
 	// Render the updated select (OOB swap)
@@ -436,7 +435,7 @@ func (h *ClientHandler) QuickCreate(w http.ResponseWriter, r *http.Request) {
 		SelectedID: client.ID.String(), // Auto-select the new client
 	}
 	if err := partials.ClientSelectOptions(selectData).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render select options", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render select options", "error", err)
 	}
 }
 
@@ -460,7 +459,7 @@ func (h *ClientHandler) renderError(w http.ResponseWriter, r *http.Request, mess
 	}
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	if err := clients.IndexPage(data).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render clients index error", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render clients index error", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -520,7 +519,7 @@ func (h *ClientHandler) renderFormError(
 		renderErr = clients.NewPage(data).Render(r.Context(), w)
 	}
 	if renderErr != nil {
-		h.logger.Error("failed to render form error", "error", renderErr)
+		h.logger.ErrorContext(r.Context(), "failed to render form error", "error", renderErr)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -45,7 +45,7 @@ func (h *ClientHandler) IndexTempl(w http.ResponseWriter, r *http.Request) {
 		Offset: offset,
 	})
 	if err != nil {
-		h.logger.Error("failed to list clients", "error", err, "user_id", user.ID)
+		h.logger.ErrorContext(r.Context(), "failed to list clients", "error", err, "user_id", user.ID)
 		h.renderIndexErrorTempl(w, r, user, "Failed to load clients. Please try again.")
 		return
 	}
@@ -84,7 +84,7 @@ func (h *ClientHandler) IndexTempl(w http.ResponseWriter, r *http.Request) {
 			BaseURL:    "/clients",
 		}
 		if err := clients.TablePartial(partialData).Render(r.Context(), w); err != nil {
-			h.logger.Error("failed to render clients table partial", "error", err)
+			h.logger.ErrorContext(r.Context(), "failed to render clients table partial", "error", err)
 			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 		}
 		return
@@ -101,7 +101,7 @@ func (h *ClientHandler) IndexTempl(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := clients.IndexPage(data).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render clients index", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render clients index", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -127,7 +127,7 @@ func (h *ClientHandler) NewTempl(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	if err := clients.NewPage(data).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render new client page", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render new client page", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -153,7 +153,7 @@ func (h *ClientHandler) ShowTempl(w http.ResponseWriter, r *http.Request) {
 		if code == domain.ENOTFOUND {
 			NotFoundResponse(w, r, h.logger)
 		} else {
-			h.logger.Error("failed to get client", "error", err, "client_id", id)
+			h.logger.ErrorContext(r.Context(), "failed to get client", "error", err, "client_id", id)
 			h.renderIndexErrorTempl(w, r, user, "Failed to load client. Please try again.")
 		}
 		return
@@ -169,7 +169,7 @@ func (h *ClientHandler) ShowTempl(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	if err := clients.ShowPage(data).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render client show page", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render client show page", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -195,7 +195,7 @@ func (h *ClientHandler) EditTempl(w http.ResponseWriter, r *http.Request) {
 		if code == domain.ENOTFOUND {
 			NotFoundResponse(w, r, h.logger)
 		} else {
-			h.logger.Error("failed to get client", "error", err, "client_id", id)
+			h.logger.ErrorContext(r.Context(), "failed to get client", "error", err, "client_id", id)
 			h.renderIndexErrorTempl(w, r, user, "Failed to load client. Please try again.")
 		}
 		return
@@ -224,7 +224,7 @@ func (h *ClientHandler) EditTempl(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	if err := clients.EditPage(data).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render edit client page", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render edit client page", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -302,7 +302,7 @@ func (h *ClientHandler) renderIndexErrorTempl(w http.ResponseWriter, r *http.Req
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	if err := clients.IndexPage(data).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render clients index error", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render clients index error", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
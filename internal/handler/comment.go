@@ -0,0 +1,174 @@
+// Package handler contains HTTP handlers for the Lukaut application.
+//
+// This file implements inspection comment handlers: the owner and their
+// organization's members discuss an inspection, and authors can delete
+// their own comments.
+package handler
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/DukeRupert/lukaut/internal/auth"
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/service"
+	"github.com/DukeRupert/lukaut/internal/templ/partials"
+	"github.com/google/uuid"
+)
+
+// CommentHandler handles HTTP requests related to inspection comments.
+type CommentHandler struct {
+	inspectionService service.InspectionService
+	logger            *slog.Logger
+}
+
+// NewCommentHandler creates a new CommentHandler.
+func NewCommentHandler(inspectionService service.InspectionService, logger *slog.Logger) *CommentHandler {
+	return &CommentHandler{
+		inspectionService: inspectionService,
+		logger:            logger,
+	}
+}
+
+// Create posts a comment and renders the updated discussion.
+// POST /inspections/{id}/comments
+//
+// Form fields:
+// - body: Comment text
+func (h *CommentHandler) Create(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUserFromRequest(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	inspectionID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid inspection ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	data := partials.InspectionCommentsData{InspectionID: inspectionID.String()}
+	_, err = h.inspectionService.AddComment(r.Context(), domain.AddCommentParams{
+		InspectionID: inspectionID,
+		UserID:       user.ID,
+		Body:         r.FormValue("body"),
+	})
+	if err != nil {
+		code := domain.ErrorCode(err)
+		if code != domain.EINVALID {
+			if code == domain.EINTERNAL {
+				h.logger.ErrorContext(r.Context(), "failed to add comment", "error", err, "inspection_id", inspectionID)
+			}
+			http.Error(w, domain.ErrorMessage(err), ErrorCodeToHTTPStatus(code))
+			return
+		}
+		data.Error = domain.ErrorMessage(err)
+		data.Body = r.FormValue("body")
+	}
+
+	h.renderComments(w, r, data)
+}
+
+// List renders the inspection's discussion.
+// GET /inspections/{id}/comments
+func (h *CommentHandler) List(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUserFromRequest(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	inspectionID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid inspection ID", http.StatusBadRequest)
+		return
+	}
+
+	h.renderComments(w, r, partials.InspectionCommentsData{InspectionID: inspectionID.String()})
+}
+
+// Delete deletes the user's own comment and renders the remaining discussion.
+// DELETE /inspections/{id}/comments/{commentID}
+func (h *CommentHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUserFromRequest(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	inspectionID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid inspection ID", http.StatusBadRequest)
+		return
+	}
+	commentID, err := uuid.Parse(r.PathValue("commentID"))
+	if err != nil {
+		http.Error(w, "Invalid comment ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.inspectionService.DeleteComment(r.Context(), inspectionID, commentID, user.ID); err != nil {
+		code := domain.ErrorCode(err)
+		if code == domain.EINTERNAL {
+			h.logger.ErrorContext(r.Context(), "failed to delete comment", "error", err, "comment_id", commentID)
+		}
+		http.Error(w, domain.ErrorMessage(err), ErrorCodeToHTTPStatus(code))
+		return
+	}
+
+	h.renderComments(w, r, partials.InspectionCommentsData{InspectionID: inspectionID.String()})
+}
+
+// renderComments loads the inspection's comments into data and renders the partial.
+func (h *CommentHandler) renderComments(w http.ResponseWriter, r *http.Request, data partials.InspectionCommentsData) {
+	user := auth.GetUserFromRequest(r)
+	inspectionID, _ := uuid.Parse(data.InspectionID)
+
+	comments, err := h.inspectionService.ListComments(r.Context(), inspectionID, user.ID)
+	if err != nil {
+		if domain.ErrorCode(err) == domain.ENOTFOUND {
+			http.Error(w, domain.ErrorMessage(err), http.StatusNotFound)
+			return
+		}
+		ServerErrorResponse(w, r, h.logger.With("inspection_id", inspectionID), fmt.Errorf("list comments: %w", err))
+		return
+	}
+	data.Comments = commentsToPartial(comments, user.ID)
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := partials.InspectionComments(data).Render(r.Context(), w); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to render comments", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// commentsToPartial converts comments to display data. Only the viewer's own
+// comments can be deleted.
+func commentsToPartial(comments []domain.InspectionComment, viewerID uuid.UUID) []partials.InspectionCommentItem {
+	items := make([]partials.InspectionCommentItem, len(comments))
+	for i, c := range comments {
+		items[i] = partials.InspectionCommentItem{
+			ID:         c.ID.String(),
+			AuthorName: c.AuthorName,
+			Body:       c.Body,
+			CreatedAt:  c.CreatedAt.Format("Jan 2, 2006 3:04 PM"),
+			CanDelete:  c.AuthorID == viewerID,
+		}
+	}
+	return items
+}
+
+// RegisterRoutes registers inspection comment routes on the provided ServeMux,
+// wrapped with requireUser.
+func (h *CommentHandler) RegisterRoutes(mux *http.ServeMux, requireUser func(http.Handler) http.Handler) {
+	mux.Handle("POST /inspections/{id}/comments", requireUser(http.HandlerFunc(h.Create)))
+	mux.Handle("GET /inspections/{id}/comments", requireUser(http.HandlerFunc(h.List)))
+	mux.Handle("DELETE /inspections/{id}/comments/{commentID}", requireUser(http.HandlerFunc(h.Delete)))
+}
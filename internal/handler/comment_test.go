@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/auth"
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/service"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// Fake Comment Store
+// =============================================================================
+
+// fakeCommentInspectionService keeps comments for one inspection in memory.
+// Members stands in for the owner and their organization. Only the comment
+// methods are implemented; the embedded interface is nil.
+type fakeCommentInspectionService struct {
+	service.InspectionService
+	inspectionID uuid.UUID
+	members      map[uuid.UUID]string // user ID -> name
+	comments     []domain.InspectionComment
+}
+
+func (f *fakeCommentInspectionService) visible(inspectionID, userID uuid.UUID) error {
+	if _, ok := f.members[userID]; !ok || inspectionID != f.inspectionID {
+		return domain.NotFound("test", "inspection", inspectionID.String())
+	}
+	return nil
+}
+
+func (f *fakeCommentInspectionService) AddComment(ctx context.Context, params domain.AddCommentParams) (*domain.InspectionComment, error) {
+	if err := f.visible(params.InspectionID, params.UserID); err != nil {
+		return nil, err
+	}
+	body := strings.TrimSpace(params.Body)
+	if body == "" {
+		return nil, domain.Invalid("test", "comment cannot be empty")
+	}
+	comment := domain.InspectionComment{
+		ID:           uuid.New(),
+		InspectionID: params.InspectionID,
+		AuthorID:     params.UserID,
+		AuthorName:   f.members[params.UserID],
+		Body:         body,
+		CreatedAt:    time.Now(),
+	}
+	f.comments = append(f.comments, comment)
+	return &comment, nil
+}
+
+func (f *fakeCommentInspectionService) ListComments(ctx context.Context, inspectionID, userID uuid.UUID) ([]domain.InspectionComment, error) {
+	if err := f.visible(inspectionID, userID); err != nil {
+		return nil, err
+	}
+	return f.comments, nil
+}
+
+func (f *fakeCommentInspectionService) DeleteComment(ctx context.Context, inspectionID, commentID, userID uuid.UUID) error {
+	if err := f.visible(inspectionID, userID); err != nil {
+		return err
+	}
+	for i, c := range f.comments {
+		if c.ID == commentID && c.AuthorID == userID {
+			f.comments = append(f.comments[:i], f.comments[i+1:]...)
+			return nil
+		}
+	}
+	return domain.NotFound("test", "comment", commentID.String())
+}
+
+// =============================================================================
+// Test Helpers
+// =============================================================================
+
+// newCommentTestMux returns a comment store with an owner and a teammate, and
+// a mux serving the comment routes with a pass-through auth wrapper.
+func newCommentTestMux() (svc *fakeCommentInspectionService, mux *http.ServeMux, owner, teammate *domain.User) {
+	owner = &domain.User{ID: uuid.New(), Name: "Olive Owner"}
+	teammate = &domain.User{ID: uuid.New(), Name: "Tess Teammate"}
+	svc = &fakeCommentInspectionService{
+		inspectionID: uuid.New(),
+		members:      map[uuid.UUID]string{owner.ID: owner.Name, teammate.ID: teammate.Name},
+	}
+
+	mux = http.NewServeMux()
+	NewCommentHandler(svc, newTestLogger()).RegisterRoutes(mux, func(next http.Handler) http.Handler { return next })
+	return svc, mux, owner, teammate
+}
+
+func postComment(mux *http.ServeMux, user *domain.User, inspectionID uuid.UUID, body string) *httptest.ResponseRecorder {
+	form := url.Values{"body": {body}}
+	req := httptest.NewRequest(http.MethodPost, "/inspections/"+inspectionID.String()+"/comments", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req = req.WithContext(auth.SetUser(req.Context(), user))
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	return rr
+}
+
+// =============================================================================
+// Comment Tests
+// =============================================================================
+
+func TestCommentHandler_PostRendersDiscussionInOrder(t *testing.T) {
+	svc, mux, owner, teammate := newCommentTestMux()
+
+	if rr := postComment(mux, owner, svc.inspectionID, "First pass done"); rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	rr := postComment(mux, teammate, svc.inspectionID, "Reviewed the ladder photos")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	body := rr.Body.String()
+	first, second := strings.Index(body, "First pass done"), strings.Index(body, "Reviewed the ladder photos")
+	if first < 0 || second < 0 || first > second {
+		t.Errorf("expected both comments oldest first, got %s", body)
+	}
+	if !strings.Contains(body, "Tess Teammate") {
+		t.Error("expected the author's name in the discussion")
+	}
+}
+
+func TestCommentHandler_DeleteButtonOnlyOnOwnComments(t *testing.T) {
+	svc, mux, owner, teammate := newCommentTestMux()
+	ownComment, _ := svc.AddComment(context.Background(), domain.AddCommentParams{InspectionID: svc.inspectionID, UserID: owner.ID, Body: "Mine"})
+	otherComment, _ := svc.AddComment(context.Background(), domain.AddCommentParams{InspectionID: svc.inspectionID, UserID: teammate.ID, Body: "Theirs"})
+
+	rr := serveAs(mux, owner, http.MethodGet, "/inspections/"+svc.inspectionID.String()+"/comments")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	body := rr.Body.String()
+	if !strings.Contains(body, "/comments/"+ownComment.ID.String()) {
+		t.Error("expected a delete button on the user's own comment")
+	}
+	if strings.Contains(body, "/comments/"+otherComment.ID.String()) {
+		t.Error("expected no delete button on a teammate's comment")
+	}
+}
+
+func TestCommentHandler_DeleteRequiresAuthor(t *testing.T) {
+	svc, mux, owner, teammate := newCommentTestMux()
+	comment, _ := svc.AddComment(context.Background(), domain.AddCommentParams{InspectionID: svc.inspectionID, UserID: teammate.ID, Body: "Blurry photo"})
+	path := "/inspections/" + svc.inspectionID.String() + "/comments/" + comment.ID.String()
+
+	if rr := serveAs(mux, owner, http.MethodDelete, path); rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404 deleting another user's comment, got %d", rr.Code)
+	}
+	if len(svc.comments) != 1 {
+		t.Fatalf("expected the comment kept, got %d comments", len(svc.comments))
+	}
+
+	rr := serveAs(mux, teammate, http.MethodDelete, path)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected author delete to succeed, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if len(svc.comments) != 0 || strings.Contains(rr.Body.String(), "Blurry photo") {
+		t.Errorf("expected the comment removed, got %s", rr.Body.String())
+	}
+}
+
+func TestCommentHandler_InvalidCommentShowsError(t *testing.T) {
+	svc, mux, owner, _ := newCommentTestMux()
+
+	rr := postComment(mux, owner, svc.inspectionID, "   ")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected the form re-rendered with 200, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), "comment cannot be empty") {
+		t.Errorf("expected the validation error, got %s", rr.Body.String())
+	}
+}
+
+func TestCommentHandler_OutsiderGetsNotFound(t *testing.T) {
+	svc, mux, _, _ := newCommentTestMux()
+	outsider := &domain.User{ID: uuid.New()}
+
+	if rr := serveAs(mux, outsider, http.MethodGet, "/inspections/"+svc.inspectionID.String()+"/comments"); rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404 listing, got %d", rr.Code)
+	}
+	if rr := postComment(mux, outsider, svc.inspectionID, "Hi"); rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404 posting, got %d", rr.Code)
+	}
+	if len(svc.comments) != 0 {
+		t.Errorf("expected no comments stored, got %d", len(svc.comments))
+	}
+}
+
+func TestCommentHandler_InvalidIDs(t *testing.T) {
+	svc, mux, owner, _ := newCommentTestMux()
+
+	if rr := serveAs(mux, owner, http.MethodGet, "/inspections/not-a-uuid/comments"); rr.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for invalid inspection ID, got %d", rr.Code)
+	}
+	if rr := serveAs(mux, owner, http.MethodDelete, "/inspections/"+svc.inspectionID.String()+"/comments/nope"); rr.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for invalid comment ID, got %d", rr.Code)
+	}
+}
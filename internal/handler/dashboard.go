@@ -5,13 +5,16 @@
 package handler
 
 import (
+	"encoding/json"
 	"log/slog"
 	"net/http"
 	"time"
 
 	"github.com/DukeRupert/lukaut/internal/auth"
 	"github.com/DukeRupert/lukaut/internal/csrf"
+	"github.com/DukeRupert/lukaut/internal/domain"
 	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/DukeRupert/lukaut/internal/service"
 	"github.com/DukeRupert/lukaut/internal/templ/layouts"
 	"github.com/DukeRupert/lukaut/internal/templ/pages/dashboard"
 	"github.com/google/uuid"
@@ -25,32 +28,38 @@ import (
 //
 // Dependencies:
 // - repo: Repository for database queries
+// - inspectionService: Business logic for inspection locations
 // - logger: Structured logging for request handling
 //
 // Routes handled:
-// - GET /dashboard -> ShowTempl
+// - GET /dashboard          -> ShowTempl
+// - GET /dashboard/map-data -> MapData
 type DashboardHandler struct {
-	repo   *repository.Queries
-	logger *slog.Logger
+	repo              *repository.Queries
+	inspectionService service.InspectionService
+	logger            *slog.Logger
 }
 
 // NewDashboardHandler creates a new DashboardHandler with the required dependencies.
 //
 // Parameters:
 // - repo: Repository for database access
+// - inspectionService: Service for inspection locations
 // - logger: Structured logger for request logging
 //
 // Example usage in main.go:
 //
-//	dashboardHandler := handler.NewDashboardHandler(repo, logger)
+//	dashboardHandler := handler.NewDashboardHandler(repo, inspectionService, logger)
 //	mux.Handle("GET /dashboard", requireUser(http.HandlerFunc(dashboardHandler.ShowTempl)))
 func NewDashboardHandler(
 	repo *repository.Queries,
+	inspectionService service.InspectionService,
 	logger *slog.Logger,
 ) *DashboardHandler {
 	return &DashboardHandler{
-		repo:   repo,
-		logger: logger,
+		repo:              repo,
+		inspectionService: inspectionService,
+		logger:            logger,
 	}
 }
 
@@ -101,7 +110,7 @@ func (h *DashboardHandler) ShowTempl(w http.ResponseWriter, r *http.Request) {
 	// Get authenticated user from context
 	user := auth.GetUserFromRequest(r)
 	if user == nil {
-		h.logger.Error("dashboard handler called without authenticated user")
+		h.logger.ErrorContext(r.Context(), "dashboard handler called without authenticated user")
 		http.Redirect(w, r, "/login", http.StatusSeeOther)
 		return
 	}
@@ -119,28 +128,28 @@ func (h *DashboardHandler) ShowTempl(w http.ResponseWriter, r *http.Request) {
 
 	// Fetch total inspections
 	if count, err := h.repo.CountInspectionsByUserID(r.Context(), user.ID); err != nil {
-		h.logger.Warn("failed to fetch total inspections count", "error", err, "user_id", user.ID)
+		h.logger.WarnContext(r.Context(), "failed to fetch total inspections count", "error", err, "user_id", user.ID)
 	} else {
 		stats.TotalInspections = count
 	}
 
 	// Fetch total reports
 	if count, err := h.repo.CountReportsByUserID(r.Context(), user.ID); err != nil {
-		h.logger.Warn("failed to fetch total reports count", "error", err, "user_id", user.ID)
+		h.logger.WarnContext(r.Context(), "failed to fetch total reports count", "error", err, "user_id", user.ID)
 	} else {
 		stats.TotalReports = count
 	}
 
 	// Fetch total violations
 	if count, err := h.repo.CountViolationsByUserID(r.Context(), user.ID); err != nil {
-		h.logger.Warn("failed to fetch total violations count", "error", err, "user_id", user.ID)
+		h.logger.WarnContext(r.Context(), "failed to fetch total violations count", "error", err, "user_id", user.ID)
 	} else {
 		stats.TotalViolations = count
 	}
 
 	// Fetch monthly reports
 	if count, err := h.repo.CountReportsThisMonthByUserID(r.Context(), user.ID); err != nil {
-		h.logger.Warn("failed to fetch monthly reports count", "error", err, "user_id", user.ID)
+		h.logger.WarnContext(r.Context(), "failed to fetch monthly reports count", "error", err, "user_id", user.ID)
 	} else {
 		stats.MonthlyReports = count
 	}
@@ -156,7 +165,7 @@ func (h *DashboardHandler) ShowTempl(w http.ResponseWriter, r *http.Request) {
 
 	recentInspections := make([]dashboard.DashboardInspection, 0, len(recentRows))
 	if err != nil {
-		h.logger.Warn("failed to fetch recent inspections", "error", err, "user_id", user.ID)
+		h.logger.WarnContext(r.Context(), "failed to fetch recent inspections", "error", err, "user_id", user.ID)
 	} else {
 		for _, row := range recentRows {
 			recentInspections = append(recentInspections, dashboard.DashboardInspection{
@@ -182,11 +191,90 @@ func (h *DashboardHandler) ShowTempl(w http.ResponseWriter, r *http.Request) {
 		Flash:              nil, // TODO: Get flash from session
 		CSRFToken:          csrfToken,
 		SubscriptionStatus: string(user.SubscriptionStatus),
+		EmailUnverified:    !user.EmailVerified,
 	}
 
 	// Render dashboard using templ
 	if err := dashboard.DashboardPage(data).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render dashboard page", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render dashboard page", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
+
+// =============================================================================
+// GET /dashboard/map-data - Inspection Locations as GeoJSON
+// =============================================================================
+
+// geoJSONFeatureCollection is a GeoJSON FeatureCollection (RFC 7946).
+type geoJSONFeatureCollection struct {
+	Type     string           `json:"type"` // Always "FeatureCollection"
+	Features []geoJSONFeature `json:"features"`
+}
+
+// geoJSONFeature is a GeoJSON Feature with a Point geometry.
+type geoJSONFeature struct {
+	Type       string                    `json:"type"` // Always "Feature"
+	Geometry   geoJSONPoint              `json:"geometry"`
+	Properties geoJSONInspectionProperty `json:"properties"`
+}
+
+// geoJSONPoint is a GeoJSON Point. Coordinates are [longitude, latitude].
+type geoJSONPoint struct {
+	Type        string     `json:"type"` // Always "Point"
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+// geoJSONInspectionProperty describes the inspection shown in a map popup.
+type geoJSONInspectionProperty struct {
+	ID             string `json:"id"`
+	Title          string `json:"title"`
+	Status         string `json:"status"`
+	InspectionDate string `json:"inspection_date"` // YYYY-MM-DD
+	Address        string `json:"address"`
+	URL            string `json:"url"`
+}
+
+// inspectionLocationsGeoJSON converts inspection locations to a FeatureCollection.
+func inspectionLocationsGeoJSON(locations []domain.InspectionLocation) geoJSONFeatureCollection {
+	features := make([]geoJSONFeature, 0, len(locations))
+	for _, loc := range locations {
+		features = append(features, geoJSONFeature{
+			Type: "Feature",
+			Geometry: geoJSONPoint{
+				Type:        "Point",
+				Coordinates: [2]float64{loc.Longitude, loc.Latitude},
+			},
+			Properties: geoJSONInspectionProperty{
+				ID:             loc.ID.String(),
+				Title:          loc.Title,
+				Status:         string(loc.Status),
+				InspectionDate: loc.InspectionDate.Format("2006-01-02"),
+				Address:        loc.Address,
+				URL:            "/inspections/" + loc.ID.String(),
+			},
+		})
+	}
+	return geoJSONFeatureCollection{Type: "FeatureCollection", Features: features}
+}
+
+// MapData returns the user's geocoded inspections as GeoJSON for the
+// dashboard map. Inspections without coordinates are omitted.
+func (h *DashboardHandler) MapData(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUserFromRequest(r)
+	if user == nil {
+		writeJSONError(w, http.StatusUnauthorized, domain.EUNAUTHORIZED, "Authentication required")
+		return
+	}
+
+	locations, err := h.inspectionService.ListLocations(r.Context(), user.ID)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to list inspection locations", "error", err, "user_id", user.ID)
+		writeJSONError(w, http.StatusInternalServerError, domain.EINTERNAL, "Failed to load map data")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/geo+json")
+	if err := json.NewEncoder(w).Encode(inspectionLocationsGeoJSON(locations)); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to encode map data", "error", err)
+	}
+}
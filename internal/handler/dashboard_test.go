@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/auth"
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/service"
+	"github.com/google/uuid"
+)
+
+// fakeLocationsInspectionService returns fixed inspection locations.
+type fakeLocationsInspectionService struct {
+	service.InspectionService
+	locations []domain.InspectionLocation
+	err       error
+}
+
+func (f *fakeLocationsInspectionService) ListLocations(ctx context.Context, userID uuid.UUID) ([]domain.InspectionLocation, error) {
+	return f.locations, f.err
+}
+
+func serveMapData(svc service.InspectionService) *httptest.ResponseRecorder {
+	h := NewDashboardHandler(nil, svc, newTestLogger())
+	req := httptest.NewRequest(http.MethodGet, "/dashboard/map-data", nil)
+	req = req.WithContext(auth.SetUser(req.Context(), &domain.User{ID: uuid.New()}))
+	rr := httptest.NewRecorder()
+	h.MapData(rr, req)
+	return rr
+}
+
+func TestMapData_ReturnsGeoJSONFeatureCollection(t *testing.T) {
+	id := uuid.New()
+	svc := &fakeLocationsInspectionService{locations: []domain.InspectionLocation{{
+		ID:             id,
+		Title:          "Warehouse <walk>",
+		Status:         domain.InspectionStatusCompleted,
+		InspectionDate: time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC),
+		Address:        "100 Main St, Boise, ID",
+		Latitude:       43.6150,
+		Longitude:      -116.2023,
+	}}}
+
+	rr := serveMapData(svc)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "application/geo+json" {
+		t.Errorf("expected application/geo+json, got %q", ct)
+	}
+
+	var fc struct {
+		Type     string `json:"type"`
+		Features []struct {
+			Type     string `json:"type"`
+			Geometry struct {
+				Type        string    `json:"type"`
+				Coordinates []float64 `json:"coordinates"`
+			} `json:"geometry"`
+			Properties map[string]string `json:"properties"`
+		} `json:"features"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &fc); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, rr.Body.String())
+	}
+
+	if fc.Type != "FeatureCollection" || len(fc.Features) != 1 {
+		t.Fatalf("expected a FeatureCollection with one feature, got %s", rr.Body.String())
+	}
+	f := fc.Features[0]
+	if f.Type != "Feature" || f.Geometry.Type != "Point" {
+		t.Errorf("expected a Point feature, got %s", rr.Body.String())
+	}
+	// GeoJSON positions are [longitude, latitude]
+	if len(f.Geometry.Coordinates) != 2 || f.Geometry.Coordinates[0] != -116.2023 || f.Geometry.Coordinates[1] != 43.6150 {
+		t.Errorf("expected coordinates [-116.2023, 43.615], got %v", f.Geometry.Coordinates)
+	}
+	want := map[string]string{
+		"id":              id.String(),
+		"title":           "Warehouse <walk>",
+		"status":          "completed",
+		"inspection_date": "2026-03-14",
+		"address":         "100 Main St, Boise, ID",
+		"url":             "/inspections/" + id.String(),
+	}
+	for key, value := range want {
+		if f.Properties[key] != value {
+			t.Errorf("properties[%q] = %q, want %q", key, f.Properties[key], value)
+		}
+	}
+}
+
+func TestMapData_NoLocationsReturnsEmptyFeatures(t *testing.T) {
+	rr := serveMapData(&fakeLocationsInspectionService{})
+
+	// features must be an empty array, never null
+	if got := rr.Body.String(); got != `{"type":"FeatureCollection","features":[]}`+"\n" {
+		t.Errorf("unexpected body: %s", got)
+	}
+}
+
+func TestMapData_ServiceError(t *testing.T) {
+	rr := serveMapData(&fakeLocationsInspectionService{err: errors.New("connection refused")})
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500, got %d", rr.Code)
+	}
+}
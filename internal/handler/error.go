@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"log/slog"
@@ -8,6 +9,8 @@ import (
 	"strings"
 
 	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/requestid"
+	"github.com/DukeRupert/lukaut/internal/templ/pages/public"
 )
 
 // ErrorResponse writes an error response to the client.
@@ -22,6 +25,12 @@ func ErrorResponse(w http.ResponseWriter, r *http.Request, logger *slog.Logger,
 	// Map to HTTP status
 	status := ErrorCodeToHTTPStatus(code)
 
+	// Internal errors get the error page and a request ID
+	if status == http.StatusInternalServerError {
+		ServerErrorResponse(w, r, logger, err)
+		return
+	}
+
 	// Log error with context
 	logError(logger, r, err, code, op, status)
 
@@ -54,7 +63,7 @@ func ErrorCodeToHTTPStatus(code string) int {
 		return http.StatusGone // 410
 	case domain.ETOOLARGE:
 		return http.StatusRequestEntityTooLarge // 413
-	case domain.ERATELIMIT:
+	case domain.ERATELIMIT, domain.EQUOTA:
 		return http.StatusTooManyRequests // 429
 	case domain.EINTERNAL:
 		return http.StatusInternalServerError // 500
@@ -76,7 +85,7 @@ func ValidationErrorResponse(w http.ResponseWriter, r *http.Request, logger *slo
 		return
 	}
 
-	logger.Info("validation error",
+	logger.InfoContext(r.Context(), "validation error",
 		"op", ve.Op,
 		"field_count", len(ve.Fields),
 		"path", r.URL.Path,
@@ -124,6 +133,60 @@ func InternalErrorResponse(w http.ResponseWriter, r *http.Request, logger *slog.
 	ErrorResponse(w, r, logger, wrappedErr)
 }
 
+// serverErrorMessage is shown for every 500 in place of the error itself.
+const serverErrorMessage = "An internal error occurred. Please try again later."
+
+// timeoutErrorMessage is shown in its place when the request ran out of time.
+const timeoutErrorMessage = "The request took too long to complete. Please try again."
+
+// ServerErrorResponse logs the error and writes a 500 that hides its details.
+// API requests get a JSON error and browsers a styled error page; both carry
+// the request ID so a reported failure can be matched to its log entry.
+// A request whose context deadline passed gets a 503 instead, as it is
+// worth retrying.
+func ServerErrorResponse(w http.ResponseWriter, r *http.Request, logger *slog.Logger, err error) {
+	status := http.StatusInternalServerError
+	message := serverErrorMessage
+	if errors.Is(r.Context().Err(), context.DeadlineExceeded) {
+		status = http.StatusServiceUnavailable
+		message = timeoutErrorMessage
+	}
+	id := requestid.FromContext(r.Context())
+
+	// Logged with the request context, which adds the request ID
+	logError(logger, r, err, domain.EINTERNAL, domain.ErrorOp(err), status)
+
+	if acceptsJSON(r) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		var body JSONError
+		body.Error.Code = domain.EINTERNAL
+		body.Error.Message = message
+		body.Error.RequestID = id
+		_ = json.NewEncoder(w).Encode(body)
+		return
+	}
+
+	// htmx doesn't swap error responses, so a page would go unseen
+	if r.Header.Get("HX-Request") == "true" {
+		if id != "" {
+			message += " Reference: " + id
+		}
+		http.Error(w, message, status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if err := public.ServerErrorPage(public.ServerErrorPageData{
+		CurrentPath: r.URL.Path,
+		Message:     message,
+		RequestID:   id,
+	}).Render(r.Context(), w); err != nil {
+		logger.ErrorContext(r.Context(), "failed to render server error page", "error", err)
+	}
+}
+
 // logError logs the error with appropriate level based on status code.
 func logError(logger *slog.Logger, r *http.Request, err error, code, op string, status int) {
 	attrs := []any{
@@ -143,9 +206,9 @@ func logError(logger *slog.Logger, r *http.Request, err error, code, op string,
 	// - 5xx errors are warnings/errors (server-side issues)
 	// - 4xx errors are info (client errors, expected)
 	if status >= 500 {
-		logger.Error("server error", attrs...)
+		logger.ErrorContext(r.Context(), "server error", attrs...)
 	} else if status >= 400 {
-		logger.Info("client error", attrs...)
+		logger.InfoContext(r.Context(), "client error", attrs...)
 	}
 }
 
@@ -192,8 +255,9 @@ func writeJSONError(w http.ResponseWriter, status int, code, message string) {
 // JSONError is a typed response structure for API errors.
 type JSONError struct {
 	Error struct {
-		Code    string            `json:"code"`
-		Message string            `json:"message"`
-		Fields  map[string]string `json:"fields,omitempty"`
+		Code      string            `json:"code"`
+		Message   string            `json:"message"`
+		Fields    map[string]string `json:"fields,omitempty"`
+		RequestID string            `json:"request_id,omitempty"`
 	} `json:"error"`
 }
@@ -1,14 +1,20 @@
 package handler
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/requestid"
 )
 
 // =============================================================================
@@ -222,6 +228,111 @@ func TestErrorResponse_UnwrappedErrorReturnsGeneric(t *testing.T) {
 	}
 }
 
+// =============================================================================
+// Server Error Response Tests
+// =============================================================================
+
+// serveServerError calls ServerErrorResponse for a request carrying a request
+// ID and returns the response and the log output.
+func serveServerError(t *testing.T, configure func(*http.Request)) (*httptest.ResponseRecorder, string) {
+	t.Helper()
+	var logs bytes.Buffer
+	logger := slog.New(requestid.NewLogHandler(slog.NewTextHandler(&logs, nil)))
+	dbErr := &mockDatabaseError{message: "pq: relation \"inspections\" does not exist"}
+
+	req := httptest.NewRequest("GET", "/inspections", nil)
+	req = req.WithContext(requestid.NewContext(req.Context(), "req-500-abc"))
+	configure(req)
+	rec := httptest.NewRecorder()
+
+	ServerErrorResponse(rec, req, logger, fmt.Errorf("list inspections: %w", dbErr))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", rec.Code)
+	}
+	if strings.Contains(rec.Body.String(), "pq:") {
+		t.Errorf("response exposes the underlying error: %s", rec.Body.String())
+	}
+	return rec, logs.String()
+}
+
+func TestServerErrorResponse_HTMLPage(t *testing.T) {
+	rec, logs := serveServerError(t, func(r *http.Request) {
+		r.Header.Set("Accept", "text/html")
+	})
+
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Errorf("expected an HTML page, got Content-Type %q", ct)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{"Something went wrong", "internal error", "req-500-abc"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected page to contain %q, got: %s", want, body)
+		}
+	}
+	if !strings.Contains(logs, "request_id=req-500-abc") || !strings.Contains(logs, "list inspections") {
+		t.Errorf("expected the error logged with the request ID, got: %s", logs)
+	}
+}
+
+func TestServerErrorResponse_JSON(t *testing.T) {
+	rec, logs := serveServerError(t, func(r *http.Request) {
+		r.Header.Set("Accept", "application/json")
+	})
+
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected JSON, got Content-Type %q", ct)
+	}
+	var body JSONError
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("expected a JSON error body: %v", err)
+	}
+	if body.Error.Code != domain.EINTERNAL {
+		t.Errorf("expected code %q, got %q", domain.EINTERNAL, body.Error.Code)
+	}
+	if body.Error.RequestID != "req-500-abc" {
+		t.Errorf("expected request ID req-500-abc, got %q", body.Error.RequestID)
+	}
+	if !strings.Contains(body.Error.Message, "internal error") {
+		t.Errorf("expected a generic message, got %q", body.Error.Message)
+	}
+	if !strings.Contains(logs, "request_id=req-500-abc") {
+		t.Errorf("expected the error logged with the request ID, got: %s", logs)
+	}
+}
+
+func TestServerErrorResponse_HTMXGetsPlainText(t *testing.T) {
+	rec, _ := serveServerError(t, func(r *http.Request) {
+		r.Header.Set("HX-Request", "true")
+	})
+
+	body := rec.Body.String()
+	if strings.Contains(body, "<html") {
+		t.Errorf("expected no page for an htmx request, got: %s", body)
+	}
+	if !strings.Contains(body, "Reference: req-500-abc") {
+		t.Errorf("expected the request ID in the message, got: %s", body)
+	}
+}
+
+func TestServerErrorResponse_TimedOutRequestIs503(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), -time.Second)
+	defer cancel()
+	req := httptest.NewRequest("GET", "/inspections", nil).WithContext(ctx)
+	req.Header.Set("HX-Request", "true")
+	rec := httptest.NewRecorder()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	ServerErrorResponse(rec, req, logger, fmt.Errorf("list inspections: %w", context.DeadlineExceeded))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "took too long") {
+		t.Errorf("expected the timeout message, got: %s", rec.Body.String())
+	}
+}
+
 // mockDatabaseError simulates a database error for testing
 type mockDatabaseError struct {
 	message string
@@ -0,0 +1,72 @@
+// Package handler contains HTTP handlers for the Lukaut application.
+//
+// This file implements the address autocomplete endpoint used by the
+// inspection forms.
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/DukeRupert/lukaut/internal/geocode"
+	"github.com/DukeRupert/lukaut/internal/templ/partials"
+)
+
+const (
+	// geocodeMinQueryLength avoids looking up addresses after one or two keystrokes.
+	geocodeMinQueryLength = 3
+
+	// geocodeMaxSuggestions limits the autocomplete list.
+	geocodeMaxSuggestions = 5
+)
+
+// GeocodeHandler serves address suggestions.
+type GeocodeHandler struct {
+	geocoder geocode.Geocoder
+	logger   *slog.Logger
+}
+
+// NewGeocodeHandler creates a new GeocodeHandler.
+func NewGeocodeHandler(geocoder geocode.Geocoder, logger *slog.Logger) *GeocodeHandler {
+	return &GeocodeHandler{
+		geocoder: geocoder,
+		logger:   logger,
+	}
+}
+
+// Suggest renders address suggestions for a partial address.
+// GET /geocode?q=
+//
+// Autocomplete is a convenience, so lookup failures render an empty list
+// rather than an error.
+func (h *GeocodeHandler) Suggest(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+
+	var suggestions []partials.AddressSuggestion
+	if len(query) >= geocodeMinQueryLength {
+		results, err := h.geocoder.Suggest(r.Context(), query, geocodeMaxSuggestions)
+		if err != nil {
+			h.logger.WarnContext(r.Context(), "address suggestion lookup failed", "error", err)
+		}
+		for _, s := range results {
+			suggestions = append(suggestions, partials.AddressSuggestion{
+				Label:        s.Label,
+				AddressLine1: s.AddressLine1,
+				City:         s.City,
+				State:        s.State,
+				PostalCode:   s.PostalCode,
+			})
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := partials.AddressSuggestions(suggestions).Render(r.Context(), w); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to render address suggestions", "error", err)
+	}
+}
+
+// RegisterRoutes registers the geocode routes.
+func (h *GeocodeHandler) RegisterRoutes(mux *http.ServeMux, requireUser func(http.Handler) http.Handler) {
+	mux.Handle("GET /geocode", requireUser(http.HandlerFunc(h.Suggest)))
+}
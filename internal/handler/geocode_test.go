@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DukeRupert/lukaut/internal/geocode"
+)
+
+// stubGeocoder returns fixed suggestions and records the queries it received.
+type stubGeocoder struct {
+	suggestions []geocode.Suggestion
+	err         error
+	queries     []string
+}
+
+func (g *stubGeocoder) Validate(ctx context.Context, address string) (string, float64, float64, error) {
+	return "", 0, 0, geocode.ErrNoMatch
+}
+
+func (g *stubGeocoder) Suggest(ctx context.Context, query string, limit int) ([]geocode.Suggestion, error) {
+	g.queries = append(g.queries, query)
+	return g.suggestions, g.err
+}
+
+func serveGeocode(g *stubGeocoder, query string) *httptest.ResponseRecorder {
+	h := NewGeocodeHandler(g, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	req := httptest.NewRequest(http.MethodGet, "/geocode?q="+query, nil)
+	rr := httptest.NewRecorder()
+	h.Suggest(rr, req)
+	return rr
+}
+
+func TestGeocodeSuggest_RendersSuggestions(t *testing.T) {
+	g := &stubGeocoder{suggestions: []geocode.Suggestion{{
+		Label:        "100 Main St, Boise, ID 83702",
+		AddressLine1: "100 Main St",
+		City:         "Boise",
+		State:        "ID",
+		PostalCode:   "83702",
+	}}}
+
+	rr := serveGeocode(g, "100+Main")
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if len(g.queries) != 1 || g.queries[0] != "100 Main" {
+		t.Errorf("expected geocoder to receive %q, got %v", "100 Main", g.queries)
+	}
+	body := rr.Body.String()
+	for _, want := range []string{
+		"100 Main St, Boise, ID 83702",
+		`data-address_line1="100 Main St"`,
+		`data-city="Boise"`,
+		`data-state="ID"`,
+		`data-postal_code="83702"`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected response to contain %q, got:\n%s", want, body)
+		}
+	}
+}
+
+func TestGeocodeSuggest_ShortQuerySkipsLookup(t *testing.T) {
+	g := &stubGeocoder{}
+
+	rr := serveGeocode(g, "10")
+
+	if len(g.queries) != 0 {
+		t.Errorf("expected no lookup for a short query, got %v", g.queries)
+	}
+	if strings.Contains(rr.Body.String(), "<button") {
+		t.Errorf("expected no suggestions, got:\n%s", rr.Body.String())
+	}
+}
+
+func TestGeocodeSuggest_LookupFailureRendersEmptyList(t *testing.T) {
+	g := &stubGeocoder{err: errors.New("provider unavailable")}
+
+	rr := serveGeocode(g, "100+Main")
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("expected 200, got %d", rr.Code)
+	}
+	if strings.Contains(rr.Body.String(), "<button") {
+		t.Errorf("expected no suggestions, got:\n%s", rr.Body.String())
+	}
+}
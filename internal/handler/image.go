@@ -4,9 +4,14 @@
 package handler
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"log/slog"
+	"mime/multipart"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/DukeRupert/lukaut/internal/auth"
 	"github.com/DukeRupert/lukaut/internal/domain"
@@ -28,12 +33,44 @@ type ImageGalleryData struct {
 	IsAnalyzing  bool           // Whether analysis is currently running (for polling)
 }
 
+// annotationResponse is the JSON form of an image annotation. Coordinates
+// are normalized to the image size (0-1 from the top left).
+type annotationResponse struct {
+	ID          string    `json:"id"`
+	ViolationID string    `json:"violation_id"`
+	ImageID     string    `json:"image_id"`
+	X           float64   `json:"x"`
+	Y           float64   `json:"y"`
+	Width       float64   `json:"width"`
+	Height      float64   `json:"height"`
+	Label       string    `json:"label"`
+	Source      string    `json:"source"` // "ai" or "manual"
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// annotationToResponse converts a domain.ImageAnnotation to its JSON form.
+func annotationToResponse(a domain.ImageAnnotation) annotationResponse {
+	return annotationResponse{
+		ID:          a.ID.String(),
+		ViolationID: a.ViolationID.String(),
+		ImageID:     a.ImageID.String(),
+		X:           a.Box.X,
+		Y:           a.Box.Y,
+		Width:       a.Box.Width,
+		Height:      a.Box.Height,
+		Label:       a.Label,
+		Source:      string(a.Source),
+		CreatedAt:   a.CreatedAt,
+	}
+}
+
 // ImageDisplay represents an image for display in the gallery.
 type ImageDisplay struct {
 	ID               uuid.UUID // Image ID
 	ThumbnailURL     string    // URL for thumbnail
 	OriginalFilename string    // Original filename
 	AnalysisStatus   string    // Analysis status (pending, analyzing, completed, failed)
+	AnalysisError    string    // Why analysis failed, if it did
 	SizeMB           float64   // File size in megabytes
 }
 
@@ -46,6 +83,8 @@ type ImageHandler struct {
 	imageService      service.ImageService
 	inspectionService service.InspectionService
 	logger            *slog.Logger
+	serveDirect       bool                // Stream image bytes instead of redirecting to storage URLs
+	uploadLimits      domain.UploadLimits // Photo sizes and types accepted for upload
 }
 
 // NewImageHandler creates a new ImageHandler.
@@ -58,9 +97,25 @@ func NewImageHandler(
 		imageService:      imageService,
 		inspectionService: inspectionService,
 		logger:            logger,
+		uploadLimits:      domain.DefaultUploadLimits(),
 	}
 }
 
+// WithUploadLimits sets the photo sizes and types accepted for upload, in
+// place of domain.DefaultUploadLimits.
+func (h *ImageHandler) WithUploadLimits(limits domain.UploadLimits) *ImageHandler {
+	h.uploadLimits = limits
+	return h
+}
+
+// WithDirectServing makes the thumbnail and original endpoints stream image
+// bytes with entity tags instead of redirecting to storage URLs. Use it with
+// local storage, whose URLs are served without conditional request support.
+func (h *ImageHandler) WithDirectServing() *ImageHandler {
+	h.serveDirect = true
+	return h
+}
+
 // =============================================================================
 // Route Registration
 // =============================================================================
@@ -72,15 +127,23 @@ func NewImageHandler(
 // Routes:
 // - POST   /inspections/{id}/images         -> Upload
 // - DELETE /inspections/{id}/images/{imageId} -> Delete
+// - PUT    /inspections/{id}/images/order   -> Reorder
 // - GET    /images/{id}/thumbnail           -> ServeThumbnail
 // - GET    /images/{id}/original            -> ServeOriginal
 // - GET    /inspections/{id}/images         -> ListImages
+// - GET    /images/{id}/status              -> Status
+//
+// POST /images/{id}/reanalyze (Reanalyze) is registered in main.go behind the
+// email verification gate, alongside the other analysis routes.
 func (h *ImageHandler) RegisterRoutes(mux *http.ServeMux, requireUser func(http.Handler) http.Handler) {
 	mux.Handle("POST /inspections/{id}/images", requireUser(http.HandlerFunc(h.Upload)))
 	mux.Handle("DELETE /inspections/{id}/images/{imageId}", requireUser(http.HandlerFunc(h.Delete)))
+	mux.Handle("PUT /inspections/{id}/images/order", requireUser(http.HandlerFunc(h.Reorder)))
 	mux.Handle("GET /images/{id}/thumbnail", requireUser(http.HandlerFunc(h.ServeThumbnail)))
 	mux.Handle("GET /images/{id}/original", requireUser(http.HandlerFunc(h.ServeOriginal)))
 	mux.Handle("GET /inspections/{id}/images", requireUser(http.HandlerFunc(h.ListImages)))
+	mux.Handle("GET /images/{id}/status", requireUser(http.HandlerFunc(h.Status)))
+	mux.Handle("GET /images/{id}/annotations", requireUser(http.HandlerFunc(h.Annotations)))
 }
 
 // =============================================================================
@@ -91,7 +154,7 @@ func (h *ImageHandler) RegisterRoutes(mux *http.ServeMux, requireUser func(http.
 func (h *ImageHandler) Upload(w http.ResponseWriter, r *http.Request) {
 	user := auth.GetUserFromRequest(r)
 	if user == nil {
-		h.logger.Error("upload handler called without authenticated user")
+		h.logger.ErrorContext(r.Context(), "upload handler called without authenticated user")
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
@@ -106,11 +169,14 @@ func (h *ImageHandler) Upload(w http.ResponseWriter, r *http.Request) {
 
 	// Parse multipart form (32MB memory limit)
 	if err := r.ParseMultipartForm(32 << 20); err != nil {
-		h.logger.Error("failed to parse multipart form", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to parse multipart form", "error", err)
 		http.Error(w, "Failed to parse form", http.StatusBadRequest)
 		return
 	}
 
+	// Remove any files spilled to disk once the batch is processed
+	defer func() { _ = r.MultipartForm.RemoveAll() }()
+
 	// Get uploaded files
 	files := r.MultipartForm.File["images"]
 	if len(files) == 0 {
@@ -122,15 +188,43 @@ func (h *ImageHandler) Upload(w http.ResponseWriter, r *http.Request) {
 	var uploadErrors []string
 	successCount := 0
 
-	// Process each file
+	// Refuse the whole batch up front if it doesn't fit, rather than storing
+	// the first files and failing the rest
+	if err := h.imageService.CheckUploadCapacity(r.Context(), inspectionID, user.ID, len(files)); err != nil {
+		switch domain.ErrorCode(err) {
+		case domain.EINVALID:
+			uploadErrors = append(uploadErrors, domain.ErrorMessage(err))
+			files = nil
+		case domain.ENOTFOUND:
+			http.Error(w, "Inspection not found", http.StatusNotFound)
+			return
+		default:
+			ServerErrorResponse(w, r, h.logger.With("inspection_id", inspectionID), fmt.Errorf("check upload capacity: %w", err))
+			return
+		}
+	}
+
+	// Process each file independently; one bad file doesn't fail the batch
 	for _, fileHeader := range files {
 		file, err := fileHeader.Open()
 		if err != nil {
-			h.logger.Error("failed to open uploaded file", "error", err, "filename", fileHeader.Filename)
+			h.logger.ErrorContext(r.Context(), "failed to open uploaded file", "error", err, "filename", fileHeader.Filename)
 			uploadErrors = append(uploadErrors, fmt.Sprintf("%s: Failed to open file", fileHeader.Filename))
 			continue
 		}
 
+		// Refuse files over the size limit or of a type the deployment
+		// doesn't accept before any processing
+		if err := h.checkUploadLimits(file, fileHeader); err != nil {
+			_ = file.Close()
+			h.logger.InfoContext(r.Context(), "rejected uploaded image",
+				"filename", fileHeader.Filename,
+				"reason", domain.ErrorMessage(err),
+			)
+			uploadErrors = append(uploadErrors, fmt.Sprintf("%s: %s", fileHeader.Filename, domain.ErrorMessage(err)))
+			continue
+		}
+
 		// Upload image
 		_, err = h.imageService.Upload(r.Context(), file, fileHeader, inspectionID, user.ID)
 		_ = file.Close()
@@ -138,7 +232,7 @@ func (h *ImageHandler) Upload(w http.ResponseWriter, r *http.Request) {
 		if err != nil {
 			code := domain.ErrorCode(err)
 			msg := domain.ErrorMessage(err)
-			h.logger.Error("failed to upload image",
+			h.logger.ErrorContext(r.Context(), "failed to upload image",
 				"error", err,
 				"filename", fileHeader.Filename,
 				"code", code,
@@ -150,7 +244,7 @@ func (h *ImageHandler) Upload(w http.ResponseWriter, r *http.Request) {
 		successCount++
 	}
 
-	h.logger.Info("image upload completed",
+	h.logger.InfoContext(r.Context(), "image upload completed",
 		"inspection_id", inspectionID,
 		"success_count", successCount,
 		"error_count", len(uploadErrors),
@@ -162,16 +256,16 @@ func (h *ImageHandler) Upload(w http.ResponseWriter, r *http.Request) {
 		// Check if there's already a pending or running analysis job
 		hasPending, err := h.inspectionService.HasPendingAnalysisJob(r.Context(), inspectionID)
 		if err != nil {
-			h.logger.Warn("failed to check pending analysis jobs", "error", err, "inspection_id", inspectionID)
+			h.logger.WarnContext(r.Context(), "failed to check pending analysis jobs", "error", err, "inspection_id", inspectionID)
 			// Continue anyway - don't fail the upload response
 		} else if !hasPending {
 			// Enqueue the analysis job via service
 			err = h.inspectionService.TriggerAnalysis(r.Context(), inspectionID, user.ID)
 			if err != nil {
-				h.logger.Warn("failed to enqueue auto-analysis job", "error", err, "inspection_id", inspectionID)
+				h.logger.WarnContext(r.Context(), "failed to enqueue auto-analysis job", "error", err, "inspection_id", inspectionID)
 				// Continue anyway - don't fail the upload response
 			} else {
-				h.logger.Info("Auto-analysis job enqueued", "inspection_id", inspectionID, "user_id", user.ID)
+				h.logger.InfoContext(r.Context(), "Auto-analysis job enqueued", "inspection_id", inspectionID, "user_id", user.ID)
 				analysisEnqueued = true
 			}
 		} else {
@@ -183,25 +277,23 @@ func (h *ImageHandler) Upload(w http.ResponseWriter, r *http.Request) {
 	// Fetch updated image list
 	images, err := h.imageService.ListByInspection(r.Context(), inspectionID, user.ID)
 	if err != nil {
-		h.logger.Error("failed to fetch images after upload", "error", err)
-		http.Error(w, "Upload completed but failed to refresh gallery", http.StatusInternalServerError)
+		ServerErrorResponse(w, r, h.logger, fmt.Errorf("fetch images after upload: %w", err))
 		return
 	}
 
 	// Get inspection to check if user can upload
 	inspection, err := h.inspectionService.GetByID(r.Context(), inspectionID, user.ID)
 	if err != nil {
-		h.logger.Error("failed to fetch inspection", "error", err)
-		http.Error(w, "Failed to fetch inspection", http.StatusInternalServerError)
+		ServerErrorResponse(w, r, h.logger, fmt.Errorf("fetch inspection: %w", err))
 		return
 	}
 
 	// Populate thumbnail URLs
 	imageDisplays := make([]ImageDisplay, 0, len(images))
 	for _, img := range images {
-		thumbnailURL, err := h.imageService.GetThumbnailURL(r.Context(), img.ID, user.ID)
+		thumbnailURL, err := h.imageService.GetThumbnailURL(r.Context(), img.ID, user.ID, domain.DefaultThumbnailSize)
 		if err != nil {
-			h.logger.Error("failed to generate thumbnail URL", "error", err, "image_id", img.ID)
+			h.logger.ErrorContext(r.Context(), "failed to generate thumbnail URL", "error", err, "image_id", img.ID)
 			thumbnailURL = "" // Show broken image
 		}
 
@@ -210,6 +302,7 @@ func (h *ImageHandler) Upload(w http.ResponseWriter, r *http.Request) {
 			ThumbnailURL:     thumbnailURL,
 			OriginalFilename: img.OriginalFilename,
 			AnalysisStatus:   string(img.AnalysisStatus),
+			AnalysisError:    img.AnalysisError,
 			SizeMB:           img.SizeMB(),
 		})
 	}
@@ -230,8 +323,23 @@ func (h *ImageHandler) Upload(w http.ResponseWriter, r *http.Request) {
 	templData := toTemplImageGalleryData(data)
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	if err := partials.ImageGallery(templData).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render image gallery", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render image gallery", "error", err)
+	}
+}
+
+// checkUploadLimits checks an uploaded file's size and sniffed content type
+// against the handler's upload limits, leaving the file positioned at its
+// start.
+func (h *ImageHandler) checkUploadLimits(file multipart.File, header *multipart.FileHeader) error {
+	sniff := make([]byte, 512)
+	n, err := io.ReadFull(file, sniff)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return domain.Internal(err, "image.check_upload_limits", "Failed to read file")
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return domain.Internal(err, "image.check_upload_limits", "Failed to read file")
 	}
+	return h.uploadLimits.Check(header.Size, service.DetectImageContentType(sniff[:n]))
 }
 
 // =============================================================================
@@ -242,7 +350,7 @@ func (h *ImageHandler) Upload(w http.ResponseWriter, r *http.Request) {
 func (h *ImageHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	user := auth.GetUserFromRequest(r)
 	if user == nil {
-		h.logger.Error("delete handler called without authenticated user")
+		h.logger.ErrorContext(r.Context(), "delete handler called without authenticated user")
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
@@ -269,8 +377,7 @@ func (h *ImageHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		if code == domain.ENOTFOUND {
 			http.Error(w, "Image not found", http.StatusNotFound)
 		} else {
-			h.logger.Error("failed to delete image", "error", err, "image_id", imageID)
-			http.Error(w, "Failed to delete image", http.StatusInternalServerError)
+			ServerErrorResponse(w, r, h.logger.With("image_id", imageID), fmt.Errorf("delete image: %w", err))
 		}
 		return
 	}
@@ -278,25 +385,23 @@ func (h *ImageHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	// Fetch updated image list
 	images, err := h.imageService.ListByInspection(r.Context(), inspectionID, user.ID)
 	if err != nil {
-		h.logger.Error("failed to fetch images after delete", "error", err)
-		http.Error(w, "Delete completed but failed to refresh gallery", http.StatusInternalServerError)
+		ServerErrorResponse(w, r, h.logger, fmt.Errorf("fetch images after delete: %w", err))
 		return
 	}
 
 	// Get inspection to check if user can upload
 	inspection, err := h.inspectionService.GetByID(r.Context(), inspectionID, user.ID)
 	if err != nil {
-		h.logger.Error("failed to fetch inspection", "error", err)
-		http.Error(w, "Failed to fetch inspection", http.StatusInternalServerError)
+		ServerErrorResponse(w, r, h.logger, fmt.Errorf("fetch inspection: %w", err))
 		return
 	}
 
 	// Populate thumbnail URLs
 	imageDisplays := make([]ImageDisplay, 0, len(images))
 	for _, img := range images {
-		thumbnailURL, err := h.imageService.GetThumbnailURL(r.Context(), img.ID, user.ID)
+		thumbnailURL, err := h.imageService.GetThumbnailURL(r.Context(), img.ID, user.ID, domain.DefaultThumbnailSize)
 		if err != nil {
-			h.logger.Error("failed to generate thumbnail URL", "error", err, "image_id", img.ID)
+			h.logger.ErrorContext(r.Context(), "failed to generate thumbnail URL", "error", err, "image_id", img.ID)
 			thumbnailURL = "" // Show broken image
 		}
 
@@ -305,6 +410,7 @@ func (h *ImageHandler) Delete(w http.ResponseWriter, r *http.Request) {
 			ThumbnailURL:     thumbnailURL,
 			OriginalFilename: img.OriginalFilename,
 			AnalysisStatus:   string(img.AnalysisStatus),
+			AnalysisError:    img.AnalysisError,
 			SizeMB:           img.SizeMB(),
 		})
 	}
@@ -324,19 +430,79 @@ func (h *ImageHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	templData := toTemplImageGalleryData(data)
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	if err := partials.ImageGallery(templData).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render image gallery", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render image gallery", "error", err)
 	}
 }
 
+// =============================================================================
+// PUT /inspections/{id}/images/order - Reorder Images
+// =============================================================================
+
+// Reorder saves the gallery order after the inspector drags an image to a new
+// position. The form lists every image_id of the inspection in its new order.
+//
+// The gallery has already been rearranged in the browser, so a successful
+// reorder responds with no content.
+func (h *ImageHandler) Reorder(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUserFromRequest(r)
+	if user == nil {
+		h.logger.ErrorContext(r.Context(), "reorder handler called without authenticated user")
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Parse inspection ID
+	idStr := r.PathValue("id")
+	inspectionID, err := uuid.Parse(idStr)
+	if err != nil {
+		http.Error(w, "Invalid inspection ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	// Parse image IDs in their new order
+	imageIDs := make([]uuid.UUID, 0, len(r.PostForm["image_id"]))
+	for _, raw := range r.PostForm["image_id"] {
+		imageID, err := uuid.Parse(raw)
+		if err != nil {
+			http.Error(w, "Invalid image ID", http.StatusBadRequest)
+			return
+		}
+		imageIDs = append(imageIDs, imageID)
+	}
+
+	if err := h.imageService.Reorder(r.Context(), inspectionID, user.ID, imageIDs); err != nil {
+		code := domain.ErrorCode(err)
+		switch code {
+		case domain.ENOTFOUND:
+			http.Error(w, "Inspection not found", http.StatusNotFound)
+		case domain.EINVALID:
+			http.Error(w, domain.ErrorMessage(err), http.StatusBadRequest)
+		default:
+			ServerErrorResponse(w, r, h.logger.With("inspection_id", inspectionID), fmt.Errorf("reorder images: %w", err))
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // =============================================================================
 // GET /images/{id}/thumbnail - Serve Thumbnail
 // =============================================================================
 
 // ServeThumbnail redirects to the thumbnail URL.
+// An optional ?size= query parameter (in pixels) selects the variant, and an
+// optional ?crop=x,y,width,height (fractions of the image) serves a close-up
+// of that region instead.
 func (h *ImageHandler) ServeThumbnail(w http.ResponseWriter, r *http.Request) {
 	user := auth.GetUserFromRequest(r)
 	if user == nil {
-		h.logger.Error("serve thumbnail handler called without authenticated user")
+		h.logger.ErrorContext(r.Context(), "serve thumbnail handler called without authenticated user")
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
@@ -349,15 +515,48 @@ func (h *ImageHandler) ServeThumbnail(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Optional ?size= selects a thumbnail variant; omitted means the default
+	size := 0
+	if sizeStr := r.URL.Query().Get("size"); sizeStr != "" {
+		size, err = strconv.Atoi(sizeStr)
+		if err != nil || size <= 0 {
+			http.Error(w, "Invalid thumbnail size", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if crop := r.URL.Query().Get("crop"); crop != "" {
+		box, err := domain.ParseAnnotationBox(crop)
+		if err != nil {
+			http.Error(w, "Invalid crop region", http.StatusBadRequest)
+			return
+		}
+		h.serveRegion(w, r, imageID, user.ID, box, size)
+		return
+	}
+
+	if h.serveDirect {
+		obj, err := h.imageService.ThumbnailObject(r.Context(), imageID, user.ID, size)
+		if err != nil {
+			if domain.ErrorCode(err) == domain.ENOTFOUND {
+				http.Error(w, "Image not found", http.StatusNotFound)
+			} else {
+				ServerErrorResponse(w, r, h.logger.With("image_id", imageID), fmt.Errorf("get thumbnail object: %w", err))
+			}
+			return
+		}
+		h.serveObject(w, r, imageID, obj)
+		return
+	}
+
 	// Get thumbnail URL
-	url, err := h.imageService.GetThumbnailURL(r.Context(), imageID, user.ID)
+	url, err := h.imageService.GetThumbnailURL(r.Context(), imageID, user.ID, size)
 	if err != nil {
 		code := domain.ErrorCode(err)
 		if code == domain.ENOTFOUND {
 			http.Error(w, "Image not found", http.StatusNotFound)
 		} else {
-			h.logger.Error("failed to get thumbnail URL", "error", err, "image_id", imageID)
-			http.Error(w, "Failed to get thumbnail", http.StatusInternalServerError)
+			ServerErrorResponse(w, r, h.logger.With("image_id", imageID), fmt.Errorf("get thumbnail URL: %w", err))
 		}
 		return
 	}
@@ -374,7 +573,7 @@ func (h *ImageHandler) ServeThumbnail(w http.ResponseWriter, r *http.Request) {
 func (h *ImageHandler) ServeOriginal(w http.ResponseWriter, r *http.Request) {
 	user := auth.GetUserFromRequest(r)
 	if user == nil {
-		h.logger.Error("serve original handler called without authenticated user")
+		h.logger.ErrorContext(r.Context(), "serve original handler called without authenticated user")
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
@@ -387,6 +586,20 @@ func (h *ImageHandler) ServeOriginal(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if h.serveDirect {
+		obj, err := h.imageService.OriginalObject(r.Context(), imageID, user.ID)
+		if err != nil {
+			if domain.ErrorCode(err) == domain.ENOTFOUND {
+				http.Error(w, "Image not found", http.StatusNotFound)
+			} else {
+				ServerErrorResponse(w, r, h.logger.With("image_id", imageID), fmt.Errorf("get original object: %w", err))
+			}
+			return
+		}
+		h.serveObject(w, r, imageID, obj)
+		return
+	}
+
 	// Get original URL
 	url, err := h.imageService.GetOriginalURL(r.Context(), imageID, user.ID)
 	if err != nil {
@@ -394,8 +607,7 @@ func (h *ImageHandler) ServeOriginal(w http.ResponseWriter, r *http.Request) {
 		if code == domain.ENOTFOUND {
 			http.Error(w, "Image not found", http.StatusNotFound)
 		} else {
-			h.logger.Error("failed to get original URL", "error", err, "image_id", imageID)
-			http.Error(w, "Failed to get original", http.StatusInternalServerError)
+			ServerErrorResponse(w, r, h.logger.With("image_id", imageID), fmt.Errorf("get original URL: %w", err))
 		}
 		return
 	}
@@ -404,6 +616,61 @@ func (h *ImageHandler) ServeOriginal(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, url, http.StatusFound)
 }
 
+// imageCacheControl lets browsers reuse a served image for an hour before
+// revalidating it. Stored image objects are never rewritten.
+const imageCacheControl = "private, max-age=3600"
+
+// serveRegion writes a close-up of the part of an image inside box. Close-ups
+// are cut on request rather than stored, so they are always served directly.
+func (h *ImageHandler) serveRegion(w http.ResponseWriter, r *http.Request, imageID, userID uuid.UUID, box domain.AnnotationBox, size int) {
+	data, err := h.imageService.RegionThumbnail(r.Context(), imageID, userID, box, size)
+	if err != nil {
+		if domain.ErrorCode(err) == domain.ENOTFOUND {
+			http.Error(w, "Image not found", http.StatusNotFound)
+		} else {
+			ServerErrorResponse(w, r, h.logger.With("image_id", imageID), fmt.Errorf("crop thumbnail: %w", err))
+		}
+		return
+	}
+
+	w.Header().Set("Cache-Control", imageCacheControl)
+	w.Header().Set("Content-Type", "image/jpeg")
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	if _, err := w.Write(data); err != nil {
+		h.logger.WarnContext(r.Context(), "failed to write image region", "error", err, "image_id", imageID)
+	}
+}
+
+// serveObject streams a stored image object, answering a matching
+// If-None-Match with 304 before the object is opened.
+func (h *ImageHandler) serveObject(w http.ResponseWriter, r *http.Request, imageID uuid.UUID, obj *domain.ImageObject) {
+	w.Header().Set("Cache-Control", imageCacheControl)
+	if checkNotModified(w, r, obj.ETag) {
+		return
+	}
+
+	body, info, err := h.imageService.OpenObject(r.Context(), obj)
+	if err != nil {
+		if domain.ErrorCode(err) == domain.ENOTFOUND {
+			http.Error(w, "Image not found", http.StatusNotFound)
+		} else {
+			ServerErrorResponse(w, r, h.logger.With("image_id", imageID), fmt.Errorf("open image: %w", err))
+		}
+		return
+	}
+	defer func() { _ = body.Close() }()
+
+	if info.ContentType != "" {
+		w.Header().Set("Content-Type", info.ContentType)
+	}
+	if info.Size > 0 {
+		w.Header().Set("Content-Length", strconv.FormatInt(info.Size, 10))
+	}
+	if _, err := io.Copy(w, body); err != nil {
+		h.logger.WarnContext(r.Context(), "failed to stream image", "error", err, "image_id", imageID, "key", obj.Key)
+	}
+}
+
 // =============================================================================
 // GET /inspections/{id}/images - List Images (for htmx refresh)
 // =============================================================================
@@ -412,7 +679,7 @@ func (h *ImageHandler) ServeOriginal(w http.ResponseWriter, r *http.Request) {
 func (h *ImageHandler) ListImages(w http.ResponseWriter, r *http.Request) {
 	user := auth.GetUserFromRequest(r)
 	if user == nil {
-		h.logger.Error("list images handler called without authenticated user")
+		h.logger.ErrorContext(r.Context(), "list images handler called without authenticated user")
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
@@ -432,8 +699,7 @@ func (h *ImageHandler) ListImages(w http.ResponseWriter, r *http.Request) {
 		if code == domain.ENOTFOUND {
 			http.Error(w, "Inspection not found", http.StatusNotFound)
 		} else {
-			h.logger.Error("failed to fetch images", "error", err, "inspection_id", inspectionID)
-			http.Error(w, "Failed to fetch images", http.StatusInternalServerError)
+			ServerErrorResponse(w, r, h.logger.With("inspection_id", inspectionID), fmt.Errorf("fetch images: %w", err))
 		}
 		return
 	}
@@ -441,8 +707,7 @@ func (h *ImageHandler) ListImages(w http.ResponseWriter, r *http.Request) {
 	// Get inspection to check if user can upload
 	inspection, err := h.inspectionService.GetByID(r.Context(), inspectionID, user.ID)
 	if err != nil {
-		h.logger.Error("failed to fetch inspection", "error", err)
-		http.Error(w, "Failed to fetch inspection", http.StatusInternalServerError)
+		ServerErrorResponse(w, r, h.logger, fmt.Errorf("fetch inspection: %w", err))
 		return
 	}
 
@@ -450,9 +715,9 @@ func (h *ImageHandler) ListImages(w http.ResponseWriter, r *http.Request) {
 	imageDisplays := make([]ImageDisplay, 0, len(images))
 	isAnalyzing := false
 	for _, img := range images {
-		thumbnailURL, err := h.imageService.GetThumbnailURL(r.Context(), img.ID, user.ID)
+		thumbnailURL, err := h.imageService.GetThumbnailURL(r.Context(), img.ID, user.ID, domain.DefaultThumbnailSize)
 		if err != nil {
-			h.logger.Error("failed to generate thumbnail URL", "error", err, "image_id", img.ID)
+			h.logger.ErrorContext(r.Context(), "failed to generate thumbnail URL", "error", err, "image_id", img.ID)
 			thumbnailURL = "" // Show broken image
 		}
 
@@ -466,6 +731,7 @@ func (h *ImageHandler) ListImages(w http.ResponseWriter, r *http.Request) {
 			ThumbnailURL:     thumbnailURL,
 			OriginalFilename: img.OriginalFilename,
 			AnalysisStatus:   string(img.AnalysisStatus),
+			AnalysisError:    img.AnalysisError,
 			SizeMB:           img.SizeMB(),
 		})
 	}
@@ -480,7 +746,7 @@ func (h *ImageHandler) ListImages(w http.ResponseWriter, r *http.Request) {
 	if !isAnalyzing {
 		hasPendingJob, err := h.inspectionService.HasPendingAnalysisJob(r.Context(), inspectionID)
 		if err != nil {
-			h.logger.Warn("failed to check pending analysis job", "error", err)
+			h.logger.WarnContext(r.Context(), "failed to check pending analysis job", "error", err)
 		} else if hasPendingJob {
 			isAnalyzing = true
 		}
@@ -499,14 +765,151 @@ func (h *ImageHandler) ListImages(w http.ResponseWriter, r *http.Request) {
 	templData := toTemplImageGalleryData(data)
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	if err := partials.ImageGallery(templData).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render image gallery", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render image gallery", "error", err)
+	}
+}
+
+// =============================================================================
+// GET /images/{id}/status - Image Analysis Status (for htmx polling)
+// =============================================================================
+
+// Status returns the analysis status partial for a single image.
+func (h *ImageHandler) Status(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUserFromRequest(r)
+	if user == nil {
+		h.logger.ErrorContext(r.Context(), "image status handler called without authenticated user")
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Parse image ID
+	idStr := r.PathValue("id")
+	imageID, err := uuid.Parse(idStr)
+	if err != nil {
+		http.Error(w, "Invalid image ID", http.StatusBadRequest)
+		return
+	}
+
+	image, err := h.imageService.GetByID(r.Context(), imageID, user.ID)
+	if err != nil {
+		code := domain.ErrorCode(err)
+		if code == domain.ENOTFOUND {
+			http.Error(w, "Image not found", http.StatusNotFound)
+		} else {
+			ServerErrorResponse(w, r, h.logger.With("image_id", imageID), fmt.Errorf("get image: %w", err))
+		}
+		return
 	}
+
+	h.renderImageStatus(w, r, image)
+}
+
+// =============================================================================
+// GET /images/{id}/annotations - Image Annotations (JSON)
+// =============================================================================
+
+// Annotations returns the boxes drawn on an image as JSON, so the frontend
+// can draw them over the photo.
+func (h *ImageHandler) Annotations(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUserFromRequest(r)
+	if user == nil {
+		writeJSONError(w, http.StatusUnauthorized, domain.EUNAUTHORIZED, "Authentication required")
+		return
+	}
+
+	imageID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, domain.EINVALID, "Invalid image ID")
+		return
+	}
+
+	annotations, err := h.imageService.ListAnnotations(r.Context(), imageID, user.ID)
+	if err != nil {
+		if domain.ErrorCode(err) == domain.ENOTFOUND {
+			writeJSONError(w, http.StatusNotFound, domain.ENOTFOUND, "Image not found")
+		} else {
+			h.logger.ErrorContext(r.Context(), "failed to list image annotations", "error", err, "image_id", imageID)
+			writeJSONError(w, http.StatusInternalServerError, domain.EINTERNAL, "Failed to load annotations")
+		}
+		return
+	}
+
+	resp := struct {
+		Annotations []annotationResponse `json:"annotations"`
+	}{Annotations: make([]annotationResponse, len(annotations))}
+	for i, a := range annotations {
+		resp.Annotations[i] = annotationToResponse(a)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to encode image annotations", "error", err)
+	}
+}
+
+// =============================================================================
+// POST /images/{id}/reanalyze - Retry Failed Image Analysis
+// =============================================================================
+
+// Reanalyze re-enqueues analysis for a single failed image and returns its
+// updated status partial, which polls until analysis finishes.
+func (h *ImageHandler) Reanalyze(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUserFromRequest(r)
+	if user == nil {
+		h.logger.ErrorContext(r.Context(), "reanalyze handler called without authenticated user")
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Parse image ID
+	idStr := r.PathValue("id")
+	imageID, err := uuid.Parse(idStr)
+	if err != nil {
+		http.Error(w, "Invalid image ID", http.StatusBadRequest)
+		return
+	}
+
+	image, err := h.imageService.ReanalyzeImage(r.Context(), imageID, user.ID)
+	if err != nil {
+		code := domain.ErrorCode(err)
+		switch code {
+		case domain.ENOTFOUND:
+			http.Error(w, "Image not found", http.StatusNotFound)
+		case domain.EINVALID:
+			http.Error(w, domain.ErrorMessage(err), http.StatusBadRequest)
+		case domain.ERATELIMIT:
+			http.Error(w, domain.ErrorMessage(err), http.StatusTooManyRequests)
+		default:
+			ServerErrorResponse(w, r, h.logger.With("image_id", imageID), fmt.Errorf("reanalyze image: %w", err))
+		}
+		return
+	}
+
+	h.logger.InfoContext(r.Context(), "Image reanalysis requested", "image_id", imageID, "user_id", user.ID)
+
+	h.renderImageStatus(w, r, image)
 }
 
 // =============================================================================
 // Helper Functions
 // =============================================================================
 
+// renderImageStatus renders the per-image status partial, polling while the
+// image is still waiting for or undergoing analysis.
+func (h *ImageHandler) renderImageStatus(w http.ResponseWriter, r *http.Request, image *domain.Image) {
+	data := partials.ImageStatusData{
+		ImageID: image.ID.String(),
+		Status:  string(image.AnalysisStatus),
+		Error:   image.AnalysisError,
+		Poll:    image.IsPending() || image.AnalysisStatus == domain.ImageAnalysisStatusAnalyzing,
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := partials.ImageStatus(data).Render(r.Context(), w); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to render image status", "error", err)
+	}
+}
+
 // toTemplImageGalleryData converts ImageGalleryData to partials.ImageGalleryData
 func toTemplImageGalleryData(data ImageGalleryData) partials.ImageGalleryData {
 	images := make([]partials.ImageDisplay, len(data.Images))
@@ -516,6 +919,7 @@ func toTemplImageGalleryData(data ImageGalleryData) partials.ImageGalleryData {
 			ThumbnailURL:     img.ThumbnailURL,
 			OriginalFilename: img.OriginalFilename,
 			AnalysisStatus:   img.AnalysisStatus,
+			AnalysisError:    img.AnalysisError,
 			SizeMB:           img.SizeMB,
 		}
 	}
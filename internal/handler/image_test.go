@@ -0,0 +1,799 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/DukeRupert/lukaut/internal/auth"
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/service"
+	"github.com/DukeRupert/lukaut/internal/storage"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// Mock ImageService Implementation
+// =============================================================================
+
+// mockImageService implements the service.ImageService interface for testing.
+type mockImageService struct {
+	UploadFunc              func(ctx context.Context, file multipart.File, header *multipart.FileHeader, inspectionID, userID uuid.UUID) (*domain.Image, error)
+	CheckUploadCapacityFunc func(ctx context.Context, inspectionID, userID uuid.UUID, count int) error
+	DeleteFunc              func(ctx context.Context, imageID, userID uuid.UUID) error
+	GetByIDFunc             func(ctx context.Context, imageID, userID uuid.UUID) (*domain.Image, error)
+	ListByInspectionFunc    func(ctx context.Context, inspectionID, userID uuid.UUID) ([]domain.Image, error)
+	GetThumbnailURLFunc     func(ctx context.Context, imageID, userID uuid.UUID, size int) (string, error)
+	GetOriginalURLFunc      func(ctx context.Context, imageID, userID uuid.UUID) (string, error)
+	ReanalyzeImageFunc      func(ctx context.Context, imageID, userID uuid.UUID) (*domain.Image, error)
+	ListAnnotationsFunc     func(ctx context.Context, imageID, userID uuid.UUID) ([]domain.ImageAnnotation, error)
+	ReorderFunc             func(ctx context.Context, inspectionID, userID uuid.UUID, imageIDs []uuid.UUID) error
+	ThumbnailObjectFunc     func(ctx context.Context, imageID, userID uuid.UUID, size int) (*domain.ImageObject, error)
+	OriginalObjectFunc      func(ctx context.Context, imageID, userID uuid.UUID) (*domain.ImageObject, error)
+	OpenObjectFunc          func(ctx context.Context, obj *domain.ImageObject) (io.ReadCloser, storage.ObjectInfo, error)
+	RegionThumbnailFunc     func(ctx context.Context, imageID, userID uuid.UUID, box domain.AnnotationBox, size int) ([]byte, error)
+}
+
+func (m *mockImageService) Upload(ctx context.Context, file multipart.File, header *multipart.FileHeader, inspectionID, userID uuid.UUID) (*domain.Image, error) {
+	if m.UploadFunc != nil {
+		return m.UploadFunc(ctx, file, header, inspectionID, userID)
+	}
+	return nil, errors.New("UploadFunc not implemented")
+}
+
+func (m *mockImageService) CheckUploadCapacity(ctx context.Context, inspectionID, userID uuid.UUID, count int) error {
+	if m.CheckUploadCapacityFunc != nil {
+		return m.CheckUploadCapacityFunc(ctx, inspectionID, userID, count)
+	}
+	return nil
+}
+
+func (m *mockImageService) Delete(ctx context.Context, imageID, userID uuid.UUID) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, imageID, userID)
+	}
+	return errors.New("DeleteFunc not implemented")
+}
+
+func (m *mockImageService) GetByID(ctx context.Context, imageID, userID uuid.UUID) (*domain.Image, error) {
+	if m.GetByIDFunc != nil {
+		return m.GetByIDFunc(ctx, imageID, userID)
+	}
+	return nil, errors.New("GetByIDFunc not implemented")
+}
+
+func (m *mockImageService) ListByInspection(ctx context.Context, inspectionID, userID uuid.UUID) ([]domain.Image, error) {
+	if m.ListByInspectionFunc != nil {
+		return m.ListByInspectionFunc(ctx, inspectionID, userID)
+	}
+	return nil, errors.New("ListByInspectionFunc not implemented")
+}
+
+func (m *mockImageService) GetThumbnailURL(ctx context.Context, imageID, userID uuid.UUID, size int) (string, error) {
+	if m.GetThumbnailURLFunc != nil {
+		return m.GetThumbnailURLFunc(ctx, imageID, userID, size)
+	}
+	return "", errors.New("GetThumbnailURLFunc not implemented")
+}
+
+func (m *mockImageService) GetOriginalURL(ctx context.Context, imageID, userID uuid.UUID) (string, error) {
+	if m.GetOriginalURLFunc != nil {
+		return m.GetOriginalURLFunc(ctx, imageID, userID)
+	}
+	return "", errors.New("GetOriginalURLFunc not implemented")
+}
+
+func (m *mockImageService) ReanalyzeImage(ctx context.Context, imageID, userID uuid.UUID) (*domain.Image, error) {
+	if m.ReanalyzeImageFunc != nil {
+		return m.ReanalyzeImageFunc(ctx, imageID, userID)
+	}
+	return nil, errors.New("ReanalyzeImageFunc not implemented")
+}
+
+func (m *mockImageService) ListAnnotations(ctx context.Context, imageID, userID uuid.UUID) ([]domain.ImageAnnotation, error) {
+	if m.ListAnnotationsFunc != nil {
+		return m.ListAnnotationsFunc(ctx, imageID, userID)
+	}
+	return nil, errors.New("ListAnnotationsFunc not implemented")
+}
+
+func (m *mockImageService) Reorder(ctx context.Context, inspectionID, userID uuid.UUID, imageIDs []uuid.UUID) error {
+	if m.ReorderFunc != nil {
+		return m.ReorderFunc(ctx, inspectionID, userID, imageIDs)
+	}
+	return errors.New("ReorderFunc not implemented")
+}
+
+func (m *mockImageService) ThumbnailObject(ctx context.Context, imageID, userID uuid.UUID, size int) (*domain.ImageObject, error) {
+	if m.ThumbnailObjectFunc != nil {
+		return m.ThumbnailObjectFunc(ctx, imageID, userID, size)
+	}
+	return nil, errors.New("ThumbnailObjectFunc not implemented")
+}
+
+func (m *mockImageService) OriginalObject(ctx context.Context, imageID, userID uuid.UUID) (*domain.ImageObject, error) {
+	if m.OriginalObjectFunc != nil {
+		return m.OriginalObjectFunc(ctx, imageID, userID)
+	}
+	return nil, errors.New("OriginalObjectFunc not implemented")
+}
+
+func (m *mockImageService) OpenObject(ctx context.Context, obj *domain.ImageObject) (io.ReadCloser, storage.ObjectInfo, error) {
+	if m.OpenObjectFunc != nil {
+		return m.OpenObjectFunc(ctx, obj)
+	}
+	return nil, storage.ObjectInfo{}, errors.New("OpenObjectFunc not implemented")
+}
+
+func (m *mockImageService) RegionThumbnail(ctx context.Context, imageID, userID uuid.UUID, box domain.AnnotationBox, size int) ([]byte, error) {
+	if m.RegionThumbnailFunc != nil {
+		return m.RegionThumbnailFunc(ctx, imageID, userID, box, size)
+	}
+	return nil, errors.New("RegionThumbnailFunc not implemented")
+}
+
+// =============================================================================
+// Test Helpers
+// =============================================================================
+
+// ownedImageService returns a mock that only finds images owned by ownerID,
+// mirroring the service's authorization check.
+func ownedImageService(ownerID uuid.UUID, status domain.ImageAnalysisStatus) *mockImageService {
+	return &mockImageService{
+		GetByIDFunc: func(ctx context.Context, imageID, userID uuid.UUID) (*domain.Image, error) {
+			if userID != ownerID {
+				return nil, domain.NotFound("image.get", "image", imageID.String())
+			}
+			return &domain.Image{ID: imageID, InspectionID: uuid.New(), AnalysisStatus: status}, nil
+		},
+	}
+}
+
+// newImageRequest builds an authenticated request for an image route.
+func newImageRequest(method, path string, imageID, userID uuid.UUID) *http.Request {
+	req := httptest.NewRequest(method, path, nil)
+	req.SetPathValue("id", imageID.String())
+	user := &domain.User{ID: userID, Email: "inspector@example.com"}
+	return req.WithContext(auth.SetUser(req.Context(), user))
+}
+
+// =============================================================================
+// Status Tests
+// =============================================================================
+
+func TestImageStatus_FailedRendersRetryButton(t *testing.T) {
+	ownerID := uuid.New()
+	imageID := uuid.New()
+	h := NewImageHandler(ownedImageService(ownerID, domain.ImageAnalysisStatusFailed), nil, newTestLogger())
+
+	rr := httptest.NewRecorder()
+	h.Status(rr, newImageRequest(http.MethodGet, "/images/"+imageID.String()+"/status", imageID, ownerID))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	body := rr.Body.String()
+	if !strings.Contains(body, "Failed") {
+		t.Error("expected failed badge")
+	}
+	if !strings.Contains(body, `hx-post="/images/`+imageID.String()+`/reanalyze"`) {
+		t.Errorf("expected retry button wired to reanalyze, got %q", body)
+	}
+	if strings.Contains(body, "every 3s") {
+		t.Error("expected failed image not to poll")
+	}
+}
+
+func TestImageStatus_FailedShowsErrorOnHover(t *testing.T) {
+	ownerID := uuid.New()
+	imageID := uuid.New()
+	svc := &mockImageService{
+		GetByIDFunc: func(ctx context.Context, id, userID uuid.UUID) (*domain.Image, error) {
+			return &domain.Image{
+				ID:             id,
+				AnalysisStatus: domain.ImageAnalysisStatusFailed,
+				AnalysisError:  "The AI provider timed out. Retry to try again.",
+			}, nil
+		},
+	}
+	h := NewImageHandler(svc, nil, newTestLogger())
+
+	rr := httptest.NewRecorder()
+	h.Status(rr, newImageRequest(http.MethodGet, "/images/"+imageID.String()+"/status", imageID, ownerID))
+
+	if !strings.Contains(rr.Body.String(), `title="The AI provider timed out. Retry to try again."`) {
+		t.Errorf("expected failure reason on the failed badge, got %q", rr.Body.String())
+	}
+}
+
+func TestImageStatus_AnalyzingPollsWithoutRetry(t *testing.T) {
+	ownerID := uuid.New()
+	imageID := uuid.New()
+	h := NewImageHandler(ownedImageService(ownerID, domain.ImageAnalysisStatusAnalyzing), nil, newTestLogger())
+
+	rr := httptest.NewRecorder()
+	h.Status(rr, newImageRequest(http.MethodGet, "/images/"+imageID.String()+"/status", imageID, ownerID))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	body := rr.Body.String()
+	if !strings.Contains(body, `hx-get="/images/`+imageID.String()+`/status"`) {
+		t.Error("expected analyzing image to poll its status")
+	}
+	if strings.Contains(body, "/reanalyze") {
+		t.Error("expected no retry button while analyzing")
+	}
+}
+
+func TestImageStatus_OtherUsersImageNotFound(t *testing.T) {
+	imageID := uuid.New()
+	h := NewImageHandler(ownedImageService(uuid.New(), domain.ImageAnalysisStatusFailed), nil, newTestLogger())
+
+	rr := httptest.NewRecorder()
+	h.Status(rr, newImageRequest(http.MethodGet, "/images/"+imageID.String()+"/status", imageID, uuid.New()))
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", rr.Code)
+	}
+}
+
+// =============================================================================
+// Reanalyze Tests
+// =============================================================================
+
+func TestReanalyze_RendersPollingPendingStatus(t *testing.T) {
+	ownerID := uuid.New()
+	imageID := uuid.New()
+	svc := &mockImageService{
+		ReanalyzeImageFunc: func(ctx context.Context, id, userID uuid.UUID) (*domain.Image, error) {
+			return &domain.Image{ID: id, AnalysisStatus: domain.ImageAnalysisStatusPending}, nil
+		},
+	}
+	h := NewImageHandler(svc, nil, newTestLogger())
+
+	rr := httptest.NewRecorder()
+	h.Reanalyze(rr, newImageRequest(http.MethodPost, "/images/"+imageID.String()+"/reanalyze", imageID, ownerID))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	body := rr.Body.String()
+	if !strings.Contains(body, "Pending") || !strings.Contains(body, "every 3s") {
+		t.Errorf("expected polling pending status, got %q", body)
+	}
+}
+
+func TestReanalyze_OtherUsersImageNotFound(t *testing.T) {
+	ownerID := uuid.New()
+	imageID := uuid.New()
+	svc := &mockImageService{
+		ReanalyzeImageFunc: func(ctx context.Context, id, userID uuid.UUID) (*domain.Image, error) {
+			if userID != ownerID {
+				return nil, domain.NotFound("image.reanalyze", "image", id.String())
+			}
+			return &domain.Image{ID: id, AnalysisStatus: domain.ImageAnalysisStatusPending}, nil
+		},
+	}
+	h := NewImageHandler(svc, nil, newTestLogger())
+
+	rr := httptest.NewRecorder()
+	h.Reanalyze(rr, newImageRequest(http.MethodPost, "/images/"+imageID.String()+"/reanalyze", imageID, uuid.New()))
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", rr.Code)
+	}
+}
+
+func TestReanalyze_RejectsImageThatHasNotFailed(t *testing.T) {
+	imageID := uuid.New()
+	svc := &mockImageService{
+		ReanalyzeImageFunc: func(ctx context.Context, id, userID uuid.UUID) (*domain.Image, error) {
+			return nil, domain.Invalid("image.reanalyze", "Only images whose analysis failed can be retried")
+		},
+	}
+	h := NewImageHandler(svc, nil, newTestLogger())
+
+	rr := httptest.NewRecorder()
+	h.Reanalyze(rr, newImageRequest(http.MethodPost, "/images/"+imageID.String()+"/reanalyze", imageID, uuid.New()))
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", rr.Code)
+	}
+}
+
+// =============================================================================
+// Annotations Tests
+// =============================================================================
+
+func TestImageAnnotations_ReturnsBoxesAsJSON(t *testing.T) {
+	ownerID := uuid.New()
+	imageID := uuid.New()
+	svc := &mockImageService{
+		ListAnnotationsFunc: func(ctx context.Context, id, userID uuid.UUID) ([]domain.ImageAnnotation, error) {
+			return []domain.ImageAnnotation{{
+				ID:          uuid.New(),
+				ViolationID: uuid.New(),
+				ImageID:     id,
+				Box:         domain.AnnotationBox{X: 0.1, Y: 0.2, Width: 0.3, Height: 0.4},
+				Label:       "east stair",
+				Source:      domain.AnnotationSourceAI,
+			}}, nil
+		},
+	}
+	h := NewImageHandler(svc, nil, newTestLogger())
+
+	rr := httptest.NewRecorder()
+	h.Annotations(rr, newImageRequest(http.MethodGet, "/images/"+imageID.String()+"/annotations", imageID, ownerID))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected JSON content type, got %q", ct)
+	}
+	body := rr.Body.String()
+	for _, want := range []string{`"x":0.1`, `"height":0.4`, `"label":"east stair"`, `"source":"ai"`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %s in response, got %s", want, body)
+		}
+	}
+}
+
+func TestImageAnnotations_OtherUsersImageReturns404(t *testing.T) {
+	imageID := uuid.New()
+	svc := &mockImageService{
+		ListAnnotationsFunc: func(ctx context.Context, id, userID uuid.UUID) ([]domain.ImageAnnotation, error) {
+			return nil, domain.NotFound("image.list_annotations", "image", id.String())
+		},
+	}
+	h := NewImageHandler(svc, nil, newTestLogger())
+
+	rr := httptest.NewRecorder()
+	h.Annotations(rr, newImageRequest(http.MethodGet, "/images/"+imageID.String()+"/annotations", imageID, uuid.New()))
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", rr.Code)
+	}
+}
+
+// =============================================================================
+// Reorder Tests
+// =============================================================================
+
+// newReorderRequest builds an authenticated request saving the given order.
+func newReorderRequest(inspectionID, userID uuid.UUID, imageIDs ...string) *http.Request {
+	form := url.Values{"image_id": imageIDs}
+	req := httptest.NewRequest(http.MethodPut, "/inspections/"+inspectionID.String()+"/images/order", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetPathValue("id", inspectionID.String())
+	user := &domain.User{ID: userID, Email: "inspector@example.com"}
+	return req.WithContext(auth.SetUser(req.Context(), user))
+}
+
+func TestReorderImages_SavesOrderFromForm(t *testing.T) {
+	inspectionID, userID := uuid.New(), uuid.New()
+	first, second := uuid.New(), uuid.New()
+	var got []uuid.UUID
+	svc := &mockImageService{
+		ReorderFunc: func(ctx context.Context, id, uid uuid.UUID, imageIDs []uuid.UUID) error {
+			if id != inspectionID || uid != userID {
+				t.Errorf("unexpected inspection %s or user %s", id, uid)
+			}
+			got = imageIDs
+			return nil
+		},
+	}
+	h := NewImageHandler(svc, nil, newTestLogger())
+
+	rr := httptest.NewRecorder()
+	h.Reorder(rr, newReorderRequest(inspectionID, userID, second.String(), first.String()))
+
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if len(got) != 2 || got[0] != second || got[1] != first {
+		t.Errorf("expected the images in form order, got %v", got)
+	}
+}
+
+func TestReorderImages_RejectsMalformedImageID(t *testing.T) {
+	svc := &mockImageService{
+		ReorderFunc: func(ctx context.Context, id, uid uuid.UUID, imageIDs []uuid.UUID) error {
+			t.Error("expected the service not to be called")
+			return nil
+		},
+	}
+	h := NewImageHandler(svc, nil, newTestLogger())
+
+	rr := httptest.NewRecorder()
+	h.Reorder(rr, newReorderRequest(uuid.New(), uuid.New(), "not-a-uuid"))
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", rr.Code)
+	}
+}
+
+func TestReorderImages_MapsServiceErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"incomplete order", domain.Invalid("image.reorder", "The image order must include every image of the inspection."), http.StatusBadRequest},
+		{"other user's inspection", domain.NotFound("image.reorder", "inspection", ""), http.StatusNotFound},
+		{"database failure", domain.Internal(errors.New("boom"), "image.reorder", "failed to update image order"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockImageService{
+				ReorderFunc: func(ctx context.Context, id, uid uuid.UUID, imageIDs []uuid.UUID) error {
+					return tt.err
+				},
+			}
+			h := NewImageHandler(svc, nil, newTestLogger())
+
+			rr := httptest.NewRecorder()
+			h.Reorder(rr, newReorderRequest(uuid.New(), uuid.New(), uuid.New().String()))
+
+			if rr.Code != tt.want {
+				t.Errorf("expected status %d, got %d", tt.want, rr.Code)
+			}
+		})
+	}
+}
+
+// =============================================================================
+// Upload Tests
+// =============================================================================
+
+// fakeUploadInspectionService reports a draft inspection with analysis
+// already queued, so uploads don't enqueue another job.
+type fakeUploadInspectionService struct {
+	service.InspectionService
+}
+
+func (f *fakeUploadInspectionService) HasPendingAnalysisJob(ctx context.Context, inspectionID uuid.UUID) (bool, error) {
+	return true, nil
+}
+
+func (f *fakeUploadInspectionService) GetByID(ctx context.Context, id, userID uuid.UUID) (*domain.Inspection, error) {
+	return &domain.Inspection{ID: id, UserID: userID, Status: domain.InspectionStatusDraft}, nil
+}
+
+// testJPEGHeader is the start of a JPEG file, enough for content sniffing.
+const testJPEGHeader = "\xff\xd8\xff\xe0\x00\x10JFIF\x00"
+
+// uploadFile is one file in a test upload.
+type uploadFile struct {
+	name string
+	data string
+}
+
+// newUploadRequest builds an authenticated multipart upload of the named
+// files, each a small JPEG.
+func newUploadRequest(t *testing.T, inspectionID uuid.UUID, filenames ...string) *http.Request {
+	t.Helper()
+	files := make([]uploadFile, 0, len(filenames))
+	for _, name := range filenames {
+		files = append(files, uploadFile{name: name, data: testJPEGHeader + "image data for " + name})
+	}
+	return newUploadRequestWithFiles(t, inspectionID, files...)
+}
+
+// newUploadRequestWithFiles builds an authenticated multipart upload of files.
+func newUploadRequestWithFiles(t *testing.T, inspectionID uuid.UUID, files ...uploadFile) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for _, f := range files {
+		part, err := mw.CreateFormFile("images", f.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = part.Write([]byte(f.data))
+	}
+	if err := mw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/inspections/"+inspectionID.String()+"/images", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	req.SetPathValue("id", inspectionID.String())
+	user := &domain.User{ID: uuid.New(), Email: "inspector@example.com"}
+	return req.WithContext(auth.SetUser(req.Context(), user))
+}
+
+// batchUploadImageService stores every file except those named in fail,
+// which are rejected as the service rejects an unsupported type.
+func batchUploadImageService(fail ...string) (*mockImageService, *[]string) {
+	var stored []string
+	var images []domain.Image
+	svc := &mockImageService{
+		UploadFunc: func(ctx context.Context, file multipart.File, header *multipart.FileHeader, inspectionID, userID uuid.UUID) (*domain.Image, error) {
+			if slices.Contains(fail, header.Filename) {
+				return nil, domain.Invalid("image.upload", "Unsupported image type: image/heic. Only JPEG and PNG are supported.")
+			}
+			stored = append(stored, header.Filename)
+			img := domain.Image{ID: uuid.New(), InspectionID: inspectionID, OriginalFilename: header.Filename, AnalysisStatus: domain.ImageAnalysisStatusPending}
+			images = append(images, img)
+			return &img, nil
+		},
+		ListByInspectionFunc: func(ctx context.Context, inspectionID, userID uuid.UUID) ([]domain.Image, error) {
+			return images, nil
+		},
+		GetThumbnailURLFunc: func(ctx context.Context, imageID, userID uuid.UUID, size int) (string, error) {
+			return "/images/" + imageID.String() + "/thumbnail", nil
+		},
+	}
+	return svc, &stored
+}
+
+func TestUploadImages_ReportsPerFileErrors(t *testing.T) {
+	svc, stored := batchUploadImageService("IMG_0002.heic")
+	h := NewImageHandler(svc, &fakeUploadInspectionService{}, newTestLogger())
+
+	rr := httptest.NewRecorder()
+	h.Upload(rr, newUploadRequest(t, uuid.New(), "IMG_0001.jpg", "IMG_0002.heic", "IMG_0003.jpg"))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	if len(*stored) != 2 || (*stored)[0] != "IMG_0001.jpg" || (*stored)[1] != "IMG_0003.jpg" {
+		t.Errorf("expected the files around the bad one to be stored, got %v", *stored)
+	}
+
+	body := rr.Body.String()
+	for _, want := range []string{
+		"Upload errors",
+		"IMG_0002.heic: Unsupported image type: image/heic.",
+		"IMG_0001.jpg",
+		"IMG_0003.jpg",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected gallery to contain %q", want)
+		}
+	}
+}
+
+func TestUploadImages_RefusesBatchOverCapacity(t *testing.T) {
+	svc, stored := batchUploadImageService()
+	var checked int
+	svc.CheckUploadCapacityFunc = func(ctx context.Context, inspectionID, userID uuid.UUID, count int) error {
+		checked = count
+		return domain.Invalid("image.check_upload_capacity", "3 photos were selected, but only 1 more can be added to this inspection (maximum 200).")
+	}
+	h := NewImageHandler(svc, &fakeUploadInspectionService{}, newTestLogger())
+
+	rr := httptest.NewRecorder()
+	h.Upload(rr, newUploadRequest(t, uuid.New(), "a.jpg", "b.jpg", "c.jpg"))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	if checked != 3 {
+		t.Errorf("expected capacity to be checked for 3 files, got %d", checked)
+	}
+	if len(*stored) != 0 {
+		t.Errorf("expected no files to be stored, got %v", *stored)
+	}
+	if !strings.Contains(rr.Body.String(), "only 1 more can be added") {
+		t.Error("expected the capacity error in the gallery")
+	}
+}
+
+func TestUploadImages_OtherUsersInspectionNotFound(t *testing.T) {
+	svc, stored := batchUploadImageService()
+	svc.CheckUploadCapacityFunc = func(ctx context.Context, inspectionID, userID uuid.UUID, count int) error {
+		return domain.NotFound("image.check_upload_capacity", "inspection", inspectionID.String())
+	}
+	h := NewImageHandler(svc, &fakeUploadInspectionService{}, newTestLogger())
+
+	rr := httptest.NewRecorder()
+	h.Upload(rr, newUploadRequest(t, uuid.New(), "a.jpg"))
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", rr.Code)
+	}
+	if len(*stored) != 0 {
+		t.Errorf("expected no files to be stored, got %v", *stored)
+	}
+}
+
+func TestUploadImages_RejectsOversizedFile(t *testing.T) {
+	svc, stored := batchUploadImageService()
+	h := NewImageHandler(svc, &fakeUploadInspectionService{}, newTestLogger()).
+		WithUploadLimits(domain.UploadLimits{MaxSize: 1024 * 1024, ContentTypes: []string{"image/jpeg"}})
+
+	rr := httptest.NewRecorder()
+	h.Upload(rr, newUploadRequestWithFiles(t, uuid.New(),
+		uploadFile{name: "small.jpg", data: testJPEGHeader},
+		uploadFile{name: "large.jpg", data: testJPEGHeader + strings.Repeat("x", 1536*1024)},
+	))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	if !slices.Equal(*stored, []string{"small.jpg"}) {
+		t.Errorf("expected only the small file stored, got %v", *stored)
+	}
+	want := "large.jpg: File is 1.5 MB, over the 1 MB limit."
+	if !strings.Contains(rr.Body.String(), want) {
+		t.Errorf("expected %q in the gallery, got: %s", want, rr.Body.String())
+	}
+}
+
+func TestUploadImages_RejectsDisallowedType(t *testing.T) {
+	svc, stored := batchUploadImageService()
+	h := NewImageHandler(svc, &fakeUploadInspectionService{}, newTestLogger()).
+		WithUploadLimits(domain.UploadLimits{MaxSize: domain.MaxImageSize, ContentTypes: []string{"image/jpeg", "image/png"}})
+
+	rr := httptest.NewRecorder()
+	h.Upload(rr, newUploadRequestWithFiles(t, uuid.New(),
+		uploadFile{name: "photo.jpg", data: testJPEGHeader},
+		uploadFile{name: "animation.jpg", data: "GIF89a\x01\x00\x01\x00"},
+	))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	if !slices.Equal(*stored, []string{"photo.jpg"}) {
+		t.Errorf("expected only the JPEG stored, got %v", *stored)
+	}
+	want := "animation.jpg: Unsupported image type: image/gif. Only JPEG and PNG are supported."
+	if !strings.Contains(rr.Body.String(), want) {
+		t.Errorf("expected %q in the gallery, got: %s", want, rr.Body.String())
+	}
+}
+
+// =============================================================================
+// Direct Serving Tests
+// =============================================================================
+
+// thumbnailImageService serves one thumbnail object, counting how often it
+// is opened.
+func thumbnailImageService(etag string, opened *int) *mockImageService {
+	return &mockImageService{
+		ThumbnailObjectFunc: func(ctx context.Context, imageID, userID uuid.UUID, size int) (*domain.ImageObject, error) {
+			return &domain.ImageObject{Key: "inspections/x/thumbnails/y_320.jpg", ETag: etag}, nil
+		},
+		OpenObjectFunc: func(ctx context.Context, obj *domain.ImageObject) (io.ReadCloser, storage.ObjectInfo, error) {
+			*opened++
+			return io.NopCloser(strings.NewReader("jpeg bytes")), storage.ObjectInfo{Key: obj.Key, Size: 10, ContentType: "image/jpeg"}, nil
+		},
+	}
+}
+
+func TestServeThumbnail_DirectServesWithETag(t *testing.T) {
+	imageID := uuid.New()
+	var opened int
+	h := NewImageHandler(thumbnailImageService(`"abc123"`, &opened), nil, newTestLogger()).WithDirectServing()
+
+	rr := httptest.NewRecorder()
+	h.ServeThumbnail(rr, newImageRequest(http.MethodGet, "/images/"+imageID.String()+"/thumbnail", imageID, uuid.New()))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	if got := rr.Header().Get("ETag"); got != `"abc123"` {
+		t.Errorf("expected ETag %q, got %q", `"abc123"`, got)
+	}
+	if got := rr.Header().Get("Cache-Control"); got != imageCacheControl {
+		t.Errorf("expected Cache-Control %q, got %q", imageCacheControl, got)
+	}
+	if got := rr.Header().Get("Content-Type"); got != "image/jpeg" {
+		t.Errorf("expected Content-Type image/jpeg, got %q", got)
+	}
+	if rr.Body.String() != "jpeg bytes" {
+		t.Errorf("expected thumbnail bytes, got %q", rr.Body.String())
+	}
+}
+
+func TestServeThumbnail_DirectNotModified(t *testing.T) {
+	imageID := uuid.New()
+	var opened int
+	h := NewImageHandler(thumbnailImageService(`"abc123"`, &opened), nil, newTestLogger()).WithDirectServing()
+
+	req := newImageRequest(http.MethodGet, "/images/"+imageID.String()+"/thumbnail", imageID, uuid.New())
+	req.Header.Set("If-None-Match", `"stale", "abc123"`)
+	rr := httptest.NewRecorder()
+	h.ServeThumbnail(rr, req)
+
+	if rr.Code != http.StatusNotModified {
+		t.Fatalf("expected status 304, got %d", rr.Code)
+	}
+	if rr.Body.Len() != 0 {
+		t.Errorf("expected empty body, got %q", rr.Body.String())
+	}
+	if got := rr.Header().Get("ETag"); got != `"abc123"` {
+		t.Errorf("expected ETag on 304, got %q", got)
+	}
+	if opened != 0 {
+		t.Errorf("expected the object not to be opened, opened %d times", opened)
+	}
+}
+
+func TestServeThumbnail_DirectWithoutHashSendsNoETag(t *testing.T) {
+	imageID := uuid.New()
+	var opened int
+	h := NewImageHandler(thumbnailImageService("", &opened), nil, newTestLogger()).WithDirectServing()
+
+	req := newImageRequest(http.MethodGet, "/images/"+imageID.String()+"/thumbnail", imageID, uuid.New())
+	req.Header.Set("If-None-Match", "*")
+	rr := httptest.NewRecorder()
+	h.ServeThumbnail(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	if got := rr.Header().Get("ETag"); got != "" {
+		t.Errorf("expected no ETag for an image without a content hash, got %q", got)
+	}
+}
+
+func TestServeThumbnail_RedirectsByDefault(t *testing.T) {
+	imageID := uuid.New()
+	svc := &mockImageService{
+		GetThumbnailURLFunc: func(ctx context.Context, imageID, userID uuid.UUID, size int) (string, error) {
+			return "https://files.example.com/thumb.jpg", nil
+		},
+	}
+	h := NewImageHandler(svc, nil, newTestLogger())
+
+	rr := httptest.NewRecorder()
+	h.ServeThumbnail(rr, newImageRequest(http.MethodGet, "/images/"+imageID.String()+"/thumbnail", imageID, uuid.New()))
+
+	if rr.Code != http.StatusFound {
+		t.Fatalf("expected status 302, got %d", rr.Code)
+	}
+}
+
+func TestServeThumbnail_CropServesRegion(t *testing.T) {
+	imageID := uuid.New()
+	var gotBox domain.AnnotationBox
+	svc := &mockImageService{
+		RegionThumbnailFunc: func(ctx context.Context, id, userID uuid.UUID, box domain.AnnotationBox, size int) ([]byte, error) {
+			gotBox = box
+			return []byte("cropped jpeg"), nil
+		},
+	}
+	h := NewImageHandler(svc, nil, newTestLogger())
+
+	rr := httptest.NewRecorder()
+	h.ServeThumbnail(rr, newImageRequest(http.MethodGet, "/images/"+imageID.String()+"/thumbnail?crop=0.1,0.2,0.3,0.4", imageID, uuid.New()))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200 rather than a redirect, got %d", rr.Code)
+	}
+	if want := (domain.AnnotationBox{X: 0.1, Y: 0.2, Width: 0.3, Height: 0.4}); gotBox != want {
+		t.Errorf("expected region %v, got %v", want, gotBox)
+	}
+	if got := rr.Header().Get("Content-Type"); got != "image/jpeg" {
+		t.Errorf("expected Content-Type image/jpeg, got %q", got)
+	}
+	if rr.Body.String() != "cropped jpeg" {
+		t.Errorf("expected cropped bytes, got %q", rr.Body.String())
+	}
+}
+
+func TestServeThumbnail_RejectsInvalidCrop(t *testing.T) {
+	imageID := uuid.New()
+	h := NewImageHandler(&mockImageService{}, nil, newTestLogger())
+
+	for _, crop := range []string{"0.1,0.2,0.3", "a,b,c,d", "1.5,0,0.2,0.2", "0.1,0.1,0,0.2"} {
+		rr := httptest.NewRecorder()
+		h.ServeThumbnail(rr, newImageRequest(http.MethodGet, "/images/"+imageID.String()+"/thumbnail?crop="+crop, imageID, uuid.New()))
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("crop %q: expected status 400, got %d", crop, rr.Code)
+		}
+	}
+}
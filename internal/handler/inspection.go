@@ -9,11 +9,13 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/DukeRupert/lukaut/internal/auth"
 	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/jobevents"
 	"github.com/DukeRupert/lukaut/internal/service"
 	"github.com/DukeRupert/lukaut/internal/templ/components/pagination"
 	"github.com/DukeRupert/lukaut/internal/templ/pages/inspections"
@@ -75,6 +77,7 @@ type AnalysisStatusData struct {
 	PendingImages  int64                   // Number of images pending analysis
 	TotalImages    int64                   // Total number of images in inspection
 	AnalyzedImages int64                   // Number of images analyzed (completed/failed)
+	FailedImages   int64                   // Number of images whose analysis failed
 	ViolationCount int64                   // Number of violations found
 	Message        string                  // Status message to display
 	PollingEnabled bool                    // Whether to enable htmx polling
@@ -135,7 +138,13 @@ type InspectionHandler struct {
 	violationService  service.ViolationService
 	clientService     service.ClientService
 	reportService     service.ReportService
+	quotaService      service.QuotaService
+	orgService        service.OrganizationService
+	preferences       service.PreferenceService
+	regulations       service.RegulationService // Optional: offers bookmarked regulations when adding a violation
+	jobEvents         *jobevents.Broker         // Optional: streams analysis progress; nil means the page polls
 	logger            *slog.Logger
+	uploadLimits      domain.UploadLimits // Photo sizes and types shown on the upload control
 }
 
 // NewInspectionHandler creates a new InspectionHandler.
@@ -145,6 +154,7 @@ func NewInspectionHandler(
 	violationService service.ViolationService,
 	clientService service.ClientService,
 	reportService service.ReportService,
+	quotaService service.QuotaService,
 	logger *slog.Logger,
 ) *InspectionHandler {
 	return &InspectionHandler{
@@ -153,10 +163,48 @@ func NewInspectionHandler(
 		violationService:  violationService,
 		clientService:     clientService,
 		reportService:     reportService,
+		quotaService:      quotaService,
 		logger:            logger,
+		uploadLimits:      domain.DefaultUploadLimits(),
 	}
 }
 
+// WithUploadLimits sets the photo sizes and types the upload control
+// accepts. Pass the limits given to ImageHandler.WithUploadLimits.
+func (h *InspectionHandler) WithUploadLimits(limits domain.UploadLimits) *InspectionHandler {
+	h.uploadLimits = limits
+	return h
+}
+
+// WithOrganizations enables the list of the user's whole organization's
+// inspections. Call this after NewInspectionHandler.
+func (h *InspectionHandler) WithOrganizations(orgService service.OrganizationService) *InspectionHandler {
+	h.orgService = orgService
+	return h
+}
+
+// WithPreferences remembers the page size users choose for the inspections
+// list. Call this after NewInspectionHandler.
+func (h *InspectionHandler) WithPreferences(preferences service.PreferenceService) *InspectionHandler {
+	h.preferences = preferences
+	return h
+}
+
+// WithRegulations offers the user's bookmarked regulations for linking
+// when adding a violation by hand. Call this after NewInspectionHandler.
+func (h *InspectionHandler) WithRegulations(regulations service.RegulationService) *InspectionHandler {
+	h.regulations = regulations
+	return h
+}
+
+// WithJobEvents sets the broker the background worker publishes job state
+// changes to, enabling the analysis progress stream. Without it, pages poll
+// for analysis status.
+func (h *InspectionHandler) WithJobEvents(broker *jobevents.Broker) *InspectionHandler {
+	h.jobEvents = broker
+	return h
+}
+
 // =============================================================================
 // POST /inspections - Create Inspection
 // =============================================================================
@@ -165,14 +213,14 @@ func NewInspectionHandler(
 func (h *InspectionHandler) Create(w http.ResponseWriter, r *http.Request) {
 	user := auth.GetUserFromRequest(r)
 	if user == nil {
-		h.logger.Error("create handler called without authenticated user")
+		h.logger.ErrorContext(r.Context(), "create handler called without authenticated user")
 		http.Redirect(w, r, "/login", http.StatusSeeOther)
 		return
 	}
 
 	// Parse form
 	if err := r.ParseForm(); err != nil {
-		h.logger.Error("failed to parse form", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to parse form", "error", err)
 		h.renderFormError(w, r, user, nil, nil, nil, "Invalid form submission.", false)
 		return
 	}
@@ -240,6 +288,7 @@ func (h *InspectionHandler) Create(w http.ResponseWriter, r *http.Request) {
 		InspectionDate:    inspectionDate,
 		WeatherConditions: weatherConditions,
 		Temperature:       temperature,
+		Units:             h.displayFormat(r, user.ID).Units,
 		InspectorNotes:    inspectorNotes,
 	}
 
@@ -254,16 +303,72 @@ func (h *InspectionHandler) Create(w http.ResponseWriter, r *http.Request) {
 				"client_id": "Selected client not found",
 			}, nil, "", false)
 		default:
-			h.logger.Error("failed to create inspection", "error", err, "user_id", user.ID)
+			h.logger.ErrorContext(r.Context(), "failed to create inspection", "error", err, "user_id", user.ID)
 			h.renderFormError(w, r, user, formValues, nil, nil, "Failed to create inspection. Please try again.", false)
 		}
 		return
 	}
 
+	// The autosaved draft became this inspection
+	if err := h.inspectionService.DeleteDraft(r.Context(), user.ID); err != nil {
+		h.logger.WarnContext(r.Context(), "failed to delete inspection draft", "error", err, "user_id", user.ID)
+	}
+
 	// Redirect to inspection detail page
 	http.Redirect(w, r, fmt.Sprintf("/inspections/%s", inspection.ID), http.StatusSeeOther)
 }
 
+// =============================================================================
+// PATCH /inspections/drafts - Autosave Draft
+// =============================================================================
+
+// SaveDraft autosaves the new-inspection form. Only fields present in the
+// request are saved, so a client may send just the fields that changed,
+// including changes queued while offline. Responds 204 No Content.
+func (h *InspectionHandler) SaveDraft(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUserFromRequest(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form submission", http.StatusBadRequest)
+		return
+	}
+
+	// sent returns the field's value, or nil if the request did not include it
+	sent := func(name string) *string {
+		if _, ok := r.PostForm[name]; !ok {
+			return nil
+		}
+		value := r.PostForm.Get(name)
+		return &value
+	}
+
+	params := domain.SaveInspectionDraftParams{
+		UserID:            user.ID,
+		Title:             sent("title"),
+		ClientID:          sent("client_id"),
+		AddressLine1:      sent("address_line1"),
+		AddressLine2:      sent("address_line2"),
+		City:              sent("city"),
+		State:             sent("state"),
+		PostalCode:        sent("postal_code"),
+		InspectionDate:    sent("inspection_date"),
+		WeatherConditions: sent("weather_conditions"),
+		Temperature:       sent("temperature"),
+		InspectorNotes:    sent("inspector_notes"),
+	}
+
+	if err := h.inspectionService.SaveDraft(r.Context(), params); err != nil {
+		ErrorResponse(w, r, h.logger, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // =============================================================================
 // PUT /inspections/{id} - Update Inspection
 // =============================================================================
@@ -272,7 +377,7 @@ func (h *InspectionHandler) Create(w http.ResponseWriter, r *http.Request) {
 func (h *InspectionHandler) Update(w http.ResponseWriter, r *http.Request) {
 	user := auth.GetUserFromRequest(r)
 	if user == nil {
-		h.logger.Error("update handler called without authenticated user")
+		h.logger.ErrorContext(r.Context(), "update handler called without authenticated user")
 		http.Redirect(w, r, "/login", http.StatusSeeOther)
 		return
 	}
@@ -287,7 +392,7 @@ func (h *InspectionHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 	// Parse form
 	if err := r.ParseForm(); err != nil {
-		h.logger.Error("failed to parse form", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to parse form", "error", err)
 		h.renderError(w, r, "Invalid form submission.")
 		return
 	}
@@ -305,6 +410,8 @@ func (h *InspectionHandler) Update(w http.ResponseWriter, r *http.Request) {
 	temperature := strings.TrimSpace(r.FormValue("temperature"))
 	inspectorNotes := strings.TrimSpace(r.FormValue("inspector_notes"))
 
+	versionStr := r.FormValue("version")
+
 	// Store form values for re-rendering
 	formValues := map[string]string{
 		"title":              title,
@@ -318,6 +425,7 @@ func (h *InspectionHandler) Update(w http.ResponseWriter, r *http.Request) {
 		"weather_conditions": weatherConditions,
 		"temperature":        temperature,
 		"inspector_notes":    inspectorNotes,
+		"version":            versionStr,
 	}
 
 	// Fetch current inspection for re-rendering on error
@@ -349,6 +457,10 @@ func (h *InspectionHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// A missing or malformed version never matches, so the save is reported
+	// as a conflict rather than overwriting blindly
+	version, _ := strconv.ParseInt(versionStr, 10, 32)
+
 	// Update inspection
 	params := domain.UpdateInspectionParams{
 		ID:                id,
@@ -363,7 +475,9 @@ func (h *InspectionHandler) Update(w http.ResponseWriter, r *http.Request) {
 		InspectionDate:    inspectionDate,
 		WeatherConditions: weatherConditions,
 		Temperature:       temperature,
+		Units:             h.displayFormat(r, user.ID).Units,
 		InspectorNotes:    inspectorNotes,
+		Version:           int32(version),
 	}
 
 	err = h.inspectionService.Update(r.Context(), params)
@@ -374,8 +488,10 @@ func (h *InspectionHandler) Update(w http.ResponseWriter, r *http.Request) {
 			h.renderFormError(w, r, user, formValues, nil, inspection, domain.ErrorMessage(err), true)
 		case domain.ENOTFOUND:
 			NotFoundResponse(w, r, h.logger)
+		case domain.ECONFLICT:
+			h.renderEditConflict(w, r, user, formValues, id, domain.ErrorMessage(err))
 		default:
-			h.logger.Error("failed to update inspection", "error", err, "inspection_id", id)
+			h.logger.ErrorContext(r.Context(), "failed to update inspection", "error", err, "inspection_id", id)
 			h.renderFormError(w, r, user, formValues, nil, inspection, "Failed to update inspection. Please try again.", true)
 		}
 		return
@@ -401,7 +517,7 @@ func (h *InspectionHandler) Update(w http.ResponseWriter, r *http.Request) {
 func (h *InspectionHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	user := auth.GetUserFromRequest(r)
 	if user == nil {
-		h.logger.Error("delete handler called without authenticated user")
+		h.logger.ErrorContext(r.Context(), "delete handler called without authenticated user")
 		http.Redirect(w, r, "/login", http.StatusSeeOther)
 		return
 	}
@@ -421,7 +537,7 @@ func (h *InspectionHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		if code == domain.ENOTFOUND {
 			NotFoundResponse(w, r, h.logger)
 		} else {
-			h.logger.Error("failed to delete inspection", "error", err, "inspection_id", id)
+			h.logger.ErrorContext(r.Context(), "failed to delete inspection", "error", err, "inspection_id", id)
 			h.renderError(w, r, "Failed to delete inspection. Please try again.")
 		}
 		return
@@ -446,7 +562,7 @@ func (h *InspectionHandler) Delete(w http.ResponseWriter, r *http.Request) {
 func (h *InspectionHandler) TriggerAnalysis(w http.ResponseWriter, r *http.Request) {
 	user := auth.GetUserFromRequest(r)
 	if user == nil {
-		h.logger.Error("trigger analysis handler called without authenticated user")
+		h.logger.ErrorContext(r.Context(), "trigger analysis handler called without authenticated user")
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
@@ -466,38 +582,47 @@ func (h *InspectionHandler) TriggerAnalysis(w http.ResponseWriter, r *http.Reque
 		if code == domain.ENOTFOUND {
 			http.Error(w, "Inspection not found", http.StatusNotFound)
 		} else {
-			h.logger.Error("failed to get analysis status", "error", err, "inspection_id", id)
-			http.Error(w, "Failed to check analysis eligibility", http.StatusInternalServerError)
+			ServerErrorResponse(w, r, h.logger.With("inspection_id", id), fmt.Errorf("get analysis status: %w", err))
 		}
 		return
 	}
 
-	if !analysisStatus.CanAnalyze {
-		if analysisStatus.IsAnalyzing {
-			http.Error(w, analysisStatus.Message, http.StatusConflict)
-		} else {
-			http.Error(w, analysisStatus.Message, http.StatusBadRequest)
-		}
+	if !analysisStatus.CanAnalyze && !analysisStatus.IsAnalyzing {
+		http.Error(w, analysisStatus.Message, http.StatusBadRequest)
 		return
 	}
 
-	// Enqueue the analysis job via service
-	err = h.inspectionService.TriggerAnalysis(r.Context(), id, user.ID)
-	if err != nil {
-		h.logger.Error("failed to enqueue analysis job", "error", err, "inspection_id", id)
-		http.Error(w, "Failed to start analysis", http.StatusInternalServerError)
-		return
+	// Enqueue the analysis job via service. An analysis that is already
+	// queued (e.g. the button was clicked twice) counts as started, and the
+	// status below shows it running; requests racing past this check get
+	// the queued job back from the enqueue.
+	var quotaMessage string
+	if analysisStatus.IsAnalyzing {
+		h.logger.InfoContext(r.Context(), "Analysis already queued", "inspection_id", id, "user_id", user.ID)
+	} else if err := h.inspectionService.TriggerAnalysis(r.Context(), id, user.ID); err != nil {
+		code := domain.ErrorCode(err)
+		if code != domain.EQUOTA && code != domain.ERATELIMIT {
+			ServerErrorResponse(w, r, h.logger.With("inspection_id", id), fmt.Errorf("enqueue analysis job: %w", err))
+			return
+		}
+		// Quota exhausted or too many jobs queued: explain when analysis is
+		// available again in the status panel instead of failing the request
+		h.logger.InfoContext(r.Context(), "analysis blocked by quota or queue limit", "inspection_id", id, "user_id", user.ID, "code", code)
+		quotaMessage = domain.ErrorMessage(err)
+	} else {
+		h.logger.InfoContext(r.Context(), "Analysis job enqueued", "inspection_id", id, "user_id", user.ID, "pending_images", analysisStatus.PendingImages)
 	}
 
-	h.logger.Info("Analysis job enqueued", "inspection_id", id, "user_id", user.ID, "pending_images", analysisStatus.PendingImages)
-
 	// Build and render the updated status
 	statusData, err := h.buildAnalysisStatusData(r.Context(), id, user.ID)
 	if err != nil {
-		h.logger.Error("failed to build status data", "error", err, "inspection_id", id)
-		http.Error(w, "Failed to load status", http.StatusInternalServerError)
+		ServerErrorResponse(w, r, h.logger.With("inspection_id", id), fmt.Errorf("build status data: %w", err))
 		return
 	}
+	if quotaMessage != "" {
+		statusData.CanAnalyze = false
+		statusData.Message = quotaMessage
+	}
 
 	// Render templ partial
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
@@ -508,12 +633,13 @@ func (h *InspectionHandler) TriggerAnalysis(w http.ResponseWriter, r *http.Reque
 		IsAnalyzing:    statusData.IsAnalyzing,
 		HasImages:      statusData.HasImages,
 		PendingImages:  statusData.PendingImages,
+		FailedImages:   statusData.FailedImages,
 		ViolationCount: statusData.ViolationCount,
 		Message:        statusData.Message,
 		PollingEnabled: statusData.PollingEnabled,
 	}
 	if err := partials.AnalysisStatus(templData).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render analysis status", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render analysis status", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -526,7 +652,7 @@ func (h *InspectionHandler) TriggerAnalysis(w http.ResponseWriter, r *http.Reque
 func (h *InspectionHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
 	user := auth.GetUserFromRequest(r)
 	if user == nil {
-		h.logger.Error("get status handler called without authenticated user")
+		h.logger.ErrorContext(r.Context(), "get status handler called without authenticated user")
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
@@ -546,12 +672,23 @@ func (h *InspectionHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
 		if code == domain.ENOTFOUND {
 			http.Error(w, "Inspection not found", http.StatusNotFound)
 		} else {
-			h.logger.Error("failed to build status data", "error", err, "inspection_id", id)
-			http.Error(w, "Failed to load status", http.StatusInternalServerError)
+			ServerErrorResponse(w, r, h.logger.With("inspection_id", id), fmt.Errorf("build status data: %w", err))
 		}
 		return
 	}
 
+	// Polls usually find nothing new, so answer those with 304. Clients must
+	// revalidate every time, as the status changes while analysis runs.
+	w.Header().Set("Cache-Control", "private, no-cache")
+	etag, err := weakETag(statusData)
+	if err != nil {
+		ServerErrorResponse(w, r, h.logger.With("inspection_id", id), fmt.Errorf("compute status etag: %w", err))
+		return
+	}
+	if checkNotModified(w, r, etag) {
+		return
+	}
+
 	// Render templ partial
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 
@@ -567,12 +704,13 @@ func (h *InspectionHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
 		IsAnalyzing:    statusData.IsAnalyzing,
 		HasImages:      statusData.HasImages,
 		PendingImages:  statusData.PendingImages,
+		FailedImages:   statusData.FailedImages,
 		ViolationCount: statusData.ViolationCount,
 		Message:        statusData.Message,
 		PollingEnabled: statusData.PollingEnabled,
 	}
 	if err := partials.AnalysisStatus(templData).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render analysis status", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render analysis status", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -585,7 +723,7 @@ func (h *InspectionHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
 func (h *InspectionHandler) ViolationsSummary(w http.ResponseWriter, r *http.Request) {
 	user := auth.GetUserFromRequest(r)
 	if user == nil {
-		h.logger.Error("violations summary handler called without authenticated user")
+		h.logger.ErrorContext(r.Context(), "violations summary handler called without authenticated user")
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
@@ -605,16 +743,15 @@ func (h *InspectionHandler) ViolationsSummary(w http.ResponseWriter, r *http.Req
 		if code == domain.ENOTFOUND {
 			http.Error(w, "Inspection not found", http.StatusNotFound)
 		} else {
-			h.logger.Error("failed to fetch inspection", "error", err)
-			http.Error(w, "Failed to fetch inspection", http.StatusInternalServerError)
+			ServerErrorResponse(w, r, h.logger, fmt.Errorf("fetch inspection: %w", err))
 		}
 		return
 	}
 
 	// Fetch violations
-	violations, err := h.violationService.ListByInspection(r.Context(), id, user.ID)
+	violations, err := h.violationService.ListByInspection(r.Context(), id, user.ID, nil)
 	if err != nil {
-		h.logger.Error("failed to list violations", "error", err, "inspection_id", id)
+		h.logger.ErrorContext(r.Context(), "failed to list violations", "error", err, "inspection_id", id)
 		violations = []domain.Violation{} // Continue with empty list
 	}
 
@@ -637,11 +774,138 @@ func (h *InspectionHandler) ViolationsSummary(w http.ResponseWriter, r *http.Req
 		},
 	}
 	if err := partials.ViolationsSummary(templData).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render violations summary", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render violations summary", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
 
+// =============================================================================
+// GET /inspections/{id}/timeline - Activity Timeline Partial
+// =============================================================================
+
+// Timeline returns the activity timeline partial for an inspection.
+func (h *InspectionHandler) Timeline(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUserFromRequest(r)
+	if user == nil {
+		h.logger.ErrorContext(r.Context(), "timeline handler called without authenticated user")
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Parse inspection ID
+	idStr := r.PathValue("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		http.Error(w, "Invalid inspection ID", http.StatusBadRequest)
+		return
+	}
+
+	entries, err := h.inspectionService.Timeline(r.Context(), id, user.ID)
+	if err != nil {
+		code := domain.ErrorCode(err)
+		if code == domain.ENOTFOUND {
+			http.Error(w, "Inspection not found", http.StatusNotFound)
+		} else {
+			ServerErrorResponse(w, r, h.logger.With("inspection_id", id), fmt.Errorf("build inspection timeline: %w", err))
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := partials.InspectionTimeline(timelineEntriesToPartial(id.String(), entries)).Render(r.Context(), w); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to render inspection timeline", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// =============================================================================
+// GET /inspections/{id}/events - Live Activity Feed Partial
+// =============================================================================
+
+// Events returns the live activity feed entries recorded after the "after"
+// cursor, or the most recent entries without one. While the inspection is
+// being analyzed the partial ends with a poller for the next entries.
+func (h *InspectionHandler) Events(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUserFromRequest(r)
+	if user == nil {
+		h.logger.ErrorContext(r.Context(), "events handler called without authenticated user")
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Parse inspection ID
+	idStr := r.PathValue("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		http.Error(w, "Invalid inspection ID", http.StatusBadRequest)
+		return
+	}
+
+	var after int64
+	if afterStr := r.URL.Query().Get("after"); afterStr != "" {
+		after, err = strconv.ParseInt(afterStr, 10, 64)
+		if err != nil || after < 0 {
+			http.Error(w, "Invalid cursor", http.StatusBadRequest)
+			return
+		}
+	}
+
+	events, err := h.inspectionService.Events(r.Context(), id, user.ID, after)
+	if err != nil {
+		code := domain.ErrorCode(err)
+		if code == domain.ENOTFOUND {
+			http.Error(w, "Inspection not found", http.StatusNotFound)
+		} else {
+			ServerErrorResponse(w, r, h.logger.With("inspection_id", id), fmt.Errorf("list inspection events: %w", err))
+		}
+		return
+	}
+
+	status, err := h.inspectionService.GetAnalysisStatus(r.Context(), id, user.ID)
+	if err != nil {
+		ServerErrorResponse(w, r, h.logger.With("inspection_id", id), fmt.Errorf("get analysis status: %w", err))
+		return
+	}
+
+	data := partials.InspectionEventsData{
+		InspectionID: id.String(),
+		Events:       make([]partials.InspectionEventItem, len(events)),
+		Cursor:       after,
+		Polling:      status.IsAnalyzing,
+	}
+	for i, e := range events {
+		data.Events[i] = partials.InspectionEventItem{
+			Kind:      e.Kind.String(),
+			Summary:   e.Summary(),
+			Timestamp: e.CreatedAt.Format("3:04:05 PM"),
+		}
+		data.Cursor = e.ID
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := partials.InspectionEvents(data).Render(r.Context(), w); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to render inspection events", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// timelineEntriesToPartial converts timeline entries to display data.
+func timelineEntriesToPartial(inspectionID string, entries []domain.TimelineEntry) partials.InspectionTimelineData {
+	items := make([]partials.TimelineEntry, len(entries))
+	for i, e := range entries {
+		items[i] = partials.TimelineEntry{
+			Kind:      e.Kind.String(),
+			Summary:   e.Summary,
+			ActorName: e.ActorName,
+			Timestamp: e.OccurredAt.Format("Jan 2, 2006 3:04 PM"),
+		}
+	}
+	return partials.InspectionTimelineData{
+		InspectionID: inspectionID,
+		Entries:      items,
+	}
+}
+
 // =============================================================================
 // Helper Functions
 // =============================================================================
@@ -680,7 +944,7 @@ func (h *InspectionHandler) renderError(w http.ResponseWriter, r *http.Request,
 	}
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	if err := inspections.IndexPage(data).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render inspections index error", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render inspections index error", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -696,10 +960,59 @@ func (h *InspectionHandler) renderFormError(
 	flashMessage string,
 	isEdit bool,
 ) {
-	// Fetch clients for dropdown
-	clients, err := h.fetchClientOptions(r.Context(), user.ID)
+	data := h.formErrorPageData(r, user, formValues, fieldErrors, inspection, flashMessage, isEdit)
+	h.renderFormPage(w, r, data)
+}
+
+// renderEditConflict re-renders the edit form after a concurrent edit. The
+// fields keep the user's attempted values, the latest saved values are shown
+// alongside, and the form carries the latest version so saving again applies
+// the user's values deliberately.
+func (h *InspectionHandler) renderEditConflict(
+	w http.ResponseWriter,
+	r *http.Request,
+	user *domain.User,
+	formValues map[string]string,
+	id uuid.UUID,
+	flashMessage string,
+) {
+	latest, err := h.inspectionService.GetByID(r.Context(), id, user.ID)
+	if err != nil {
+		if domain.ErrorCode(err) == domain.ENOTFOUND {
+			NotFoundResponse(w, r, h.logger)
+			return
+		}
+		h.logger.ErrorContext(r.Context(), "failed to get inspection after edit conflict", "error", err, "inspection_id", id)
+		h.renderError(w, r, "Failed to load inspection. Please try again.")
+		return
+	}
+
+	formValues["version"] = strconv.Itoa(int(latest.Version))
+	data := h.formErrorPageData(r, user, formValues, nil, latest, flashMessage, true)
+	saved := domainInspectionToFormValues(latest)
+	data.Latest = &saved
+	h.renderFormPage(w, r, data)
+}
+
+// formErrorPageData builds the form page data for re-rendering with errors.
+func (h *InspectionHandler) formErrorPageData(
+	r *http.Request,
+	user *domain.User,
+	formValues map[string]string,
+	fieldErrors map[string]string,
+	inspection *domain.Inspection,
+	flashMessage string,
+	isEdit bool,
+) inspections.FormPageData {
+	// Fetch clients for dropdown; an existing inspection offers its
+	// owner's clients
+	clientOwnerID := user.ID
+	if inspection != nil {
+		clientOwnerID = inspection.UserID
+	}
+	clients, err := h.fetchClientOptions(r.Context(), clientOwnerID)
 	if err != nil {
-		h.logger.Error("failed to fetch clients", "error", err, "user_id", user.ID)
+		h.logger.ErrorContext(r.Context(), "failed to fetch clients", "error", err, "user_id", clientOwnerID)
 		clients = []ClientOption{} // Empty list on error
 	}
 
@@ -718,7 +1031,7 @@ func (h *InspectionHandler) renderFormError(
 		}
 	}
 
-	data := inspections.FormPageData{
+	return inspections.FormPageData{
 		CurrentPath: r.URL.Path,
 		CSRFToken:   "",
 		User:        domainUserToInspectionDisplay(user),
@@ -736,21 +1049,32 @@ func (h *InspectionHandler) renderFormError(
 			WeatherConditions: formValues["weather_conditions"],
 			Temperature:       formValues["temperature"],
 			InspectorNotes:    formValues["inspector_notes"],
+			Version:           formValues["version"],
 		},
 		Errors: fieldErrors,
 		Flash:  flash,
 		IsEdit: isEdit,
 	}
+}
+
+// renderFormPage renders the new or edit form page. The edit form submits
+// with hx-swap="none", so htmx requests are retargeted to the page body for
+// the errors to be seen.
+func (h *InspectionHandler) renderFormPage(w http.ResponseWriter, r *http.Request, data inspections.FormPageData) {
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("HX-Retarget", "body")
+		w.Header().Set("HX-Reswap", "innerHTML")
+	}
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	var renderErr error
-	if isEdit {
+	if data.IsEdit {
 		renderErr = inspections.EditPage(data).Render(r.Context(), w)
 	} else {
 		renderErr = inspections.NewPage(data).Render(r.Context(), w)
 	}
 	if renderErr != nil {
-		h.logger.Error("failed to render form error", "error", renderErr)
+		h.logger.ErrorContext(r.Context(), "failed to render form error", "error", renderErr)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -771,6 +1095,7 @@ func (h *InspectionHandler) buildAnalysisStatusData(ctx context.Context, inspect
 		PendingImages:  status.PendingImages,
 		TotalImages:    status.TotalImages,
 		AnalyzedImages: status.AnalyzedImages,
+		FailedImages:   status.FailedImages,
 		ViolationCount: status.ViolationCount,
 		Message:        status.Message,
 		PollingEnabled: status.PollingEnabled,
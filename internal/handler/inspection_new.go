@@ -5,6 +5,7 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -25,12 +26,42 @@ import (
 
 // IndexTempl displays a paginated list of inspections using templ.
 func (h *InspectionHandler) IndexTempl(w http.ResponseWriter, r *http.Request) {
+	h.renderIndexTempl(w, r, false)
+}
+
+// OrganizationIndexTempl displays a paginated list of the inspections of
+// everyone in the user's organization, with who owns each.
+func (h *InspectionHandler) OrganizationIndexTempl(w http.ResponseWriter, r *http.Request) {
+	h.renderIndexTempl(w, r, true)
+}
+
+// renderIndexTempl renders the user's inspections, or their organization's
+// when organization is true.
+func (h *InspectionHandler) renderIndexTempl(w http.ResponseWriter, r *http.Request, organization bool) {
 	user := auth.GetUserFromRequest(r)
 	if user == nil {
 		http.Redirect(w, r, "/login", http.StatusSeeOther)
 		return
 	}
 
+	// The organization list is only offered to members of a stored
+	// organization; personal accounts see their own inspections
+	inOrganization := false
+	if h.orgService != nil {
+		membership, err := h.orgService.Membership(r.Context(), user.ID)
+		if err != nil {
+			h.logger.ErrorContext(r.Context(), "failed to get organization", "error", err, "user_id", user.ID)
+		} else {
+			inOrganization = !membership.Personal
+		}
+	}
+	organization = organization && inOrganization
+
+	baseURL := "/inspections"
+	if organization {
+		baseURL = "/inspections/organization"
+	}
+
 	// Parse pagination parameters
 	page := 1
 	if pageStr := r.URL.Query().Get("page"); pageStr != "" {
@@ -39,17 +70,28 @@ func (h *InspectionHandler) IndexTempl(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	perPage := int32(20)
+	perPage := int32(resolvePerPage(r, h.preferences, user.ID, h.logger))
 	offset := int32((page - 1) * int(perPage))
 
+	// Parse sort parameters; unknown values fall back to newest first
+	sortBy, descending := domain.ParseInspectionSort(r.URL.Query().Get("sort"), r.URL.Query().Get("dir"))
+	sortState := inspections.SortState{
+		BaseURL: baseURL,
+		Column:  string(sortBy),
+		Desc:    descending,
+	}
+
 	// Fetch inspections
 	result, err := h.inspectionService.List(r.Context(), domain.ListInspectionsParams{
-		UserID: user.ID,
-		Limit:  perPage,
-		Offset: offset,
+		UserID:       user.ID,
+		Limit:        perPage,
+		Offset:       offset,
+		Organization: organization,
+		Sort:         sortBy,
+		Descending:   descending,
 	})
 	if err != nil {
-		h.logger.Error("failed to list inspections", "error", err, "user_id", user.ID)
+		h.logger.ErrorContext(r.Context(), "failed to list inspections", "error", err, "user_id", user.ID)
 		h.renderIndexErrorTempl(w, r, user, "Failed to load inspections. Please try again.")
 		return
 	}
@@ -79,10 +121,11 @@ func (h *InspectionHandler) IndexTempl(w http.ResponseWriter, r *http.Request) {
 		partialData := inspections.TablePartialData{
 			Inspections: displayInspections,
 			Pagination:  sharedPagination,
-			BaseURL:     "/inspections",
+			Sort:        sortState,
+			ShowOwner:   organization,
 		}
 		if err := inspections.TablePartial(partialData).Render(r.Context(), w); err != nil {
-			h.logger.Error("failed to render inspections table partial", "error", err)
+			h.logger.ErrorContext(r.Context(), "failed to render inspections table partial", "error", err)
 			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 		}
 		return
@@ -90,16 +133,19 @@ func (h *InspectionHandler) IndexTempl(w http.ResponseWriter, r *http.Request) {
 
 	// Full page render
 	data := inspections.ListPageData{
-		CurrentPath: r.URL.Path,
-		CSRFToken:   "",
-		User:        domainUserToInspectionDisplay(user),
-		Inspections: displayInspections,
-		Pagination:  sharedPagination,
-		Flash:       nil,
+		CurrentPath:    r.URL.Path,
+		CSRFToken:      "",
+		User:           domainUserToInspectionDisplay(user),
+		Inspections:    displayInspections,
+		Pagination:     sharedPagination,
+		Sort:           sortState,
+		Flash:          nil,
+		InOrganization: inOrganization,
+		Organization:   organization,
 	}
 
 	if err := inspections.IndexPage(data).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render inspections index", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render inspections index", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -115,7 +161,7 @@ func (h *InspectionHandler) NewTempl(w http.ResponseWriter, r *http.Request) {
 	// Fetch clients for dropdown
 	clientOptions, err := h.fetchClientOptions(r.Context(), user.ID)
 	if err != nil {
-		h.logger.Error("failed to fetch clients", "error", err, "user_id", user.ID)
+		h.logger.ErrorContext(r.Context(), "failed to fetch clients", "error", err, "user_id", user.ID)
 		h.renderIndexErrorTempl(w, r, user, "Failed to load clients. Please try again.")
 		return
 	}
@@ -134,9 +180,22 @@ func (h *InspectionHandler) NewTempl(w http.ResponseWriter, r *http.Request) {
 		IsEdit: false,
 	}
 
+	// Restore the autosaved draft, if any
+	draft, err := h.inspectionService.GetDraft(r.Context(), user.ID)
+	switch {
+	case err == nil:
+		data.Form = draftToFormValues(draft, data.Form.InspectionDate)
+		data.Flash = &shared.Flash{
+			Type:    shared.FlashInfo,
+			Message: "Restored your unsaved draft from " + draft.UpdatedAt.Format("Jan 2 at 3:04 PM") + ".",
+		}
+	case domain.ErrorCode(err) != domain.ENOTFOUND:
+		h.logger.WarnContext(r.Context(), "failed to load inspection draft", "error", err, "user_id", user.ID)
+	}
+
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	if err := inspections.NewPage(data).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render new inspection page", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render new inspection page", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -162,51 +221,35 @@ func (h *InspectionHandler) EditTempl(w http.ResponseWriter, r *http.Request) {
 		if code == domain.ENOTFOUND {
 			http.Redirect(w, r, "/inspections", http.StatusSeeOther)
 		} else {
-			h.logger.Error("failed to get inspection", "error", err, "inspection_id", id)
+			h.logger.ErrorContext(r.Context(), "failed to get inspection", "error", err, "inspection_id", id)
 			h.renderIndexErrorTempl(w, r, user, "Failed to load inspection. Please try again.")
 		}
 		return
 	}
 
-	// Fetch clients for dropdown
-	clientOptions, err := h.fetchClientOptions(r.Context(), user.ID)
+	// Fetch clients for dropdown. A teammate's inspection can only be
+	// assigned the owner's clients.
+	clientOptions, err := h.fetchClientOptions(r.Context(), inspection.UserID)
 	if err != nil {
-		h.logger.Error("failed to fetch clients", "error", err, "user_id", user.ID)
+		h.logger.ErrorContext(r.Context(), "failed to fetch clients", "error", err, "user_id", inspection.UserID)
 		clientOptions = []ClientOption{}
 	}
 
-	clientIDStr := ""
-	if inspection.ClientID != nil {
-		clientIDStr = inspection.ClientID.String()
-	}
-
 	data := inspections.FormPageData{
 		CurrentPath: r.URL.Path,
 		CSRFToken:   "",
 		User:        domainUserToInspectionDisplay(user),
 		Inspection:  domainInspectionToDisplay(inspection),
 		Clients:     domainClientsToOptions(clientOptions),
-		Form: inspections.InspectionFormValues{
-			Title:             inspection.Title,
-			ClientID:          clientIDStr,
-			AddressLine1:      inspection.AddressLine1,
-			AddressLine2:      inspection.AddressLine2,
-			City:              inspection.City,
-			State:             inspection.State,
-			PostalCode:        inspection.PostalCode,
-			InspectionDate:    inspection.InspectionDate.Format("2006-01-02"),
-			WeatherConditions: inspection.WeatherConditions,
-			Temperature:       inspection.Temperature,
-			InspectorNotes:    inspection.InspectorNotes,
-		},
-		Errors: make(map[string]string),
-		Flash:  nil,
-		IsEdit: true,
+		Form:        domainInspectionToFormValues(inspection),
+		Errors:      make(map[string]string),
+		Flash:       nil,
+		IsEdit:      true,
 	}
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	if err := inspections.EditPage(data).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render edit inspection page", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render edit inspection page", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -232,7 +275,7 @@ func (h *InspectionHandler) ShowTempl(w http.ResponseWriter, r *http.Request) {
 		if code == domain.ENOTFOUND {
 			NotFoundResponse(w, r, h.logger)
 		} else {
-			h.logger.Error("failed to get inspection", "error", err, "inspection_id", id)
+			h.logger.ErrorContext(r.Context(), "failed to get inspection", "error", err, "inspection_id", id)
 			h.renderIndexErrorTempl(w, r, user, "Failed to load inspection. Please try again.")
 		}
 		return
@@ -241,16 +284,16 @@ func (h *InspectionHandler) ShowTempl(w http.ResponseWriter, r *http.Request) {
 	// Fetch images for this inspection
 	images, err := h.imageService.ListByInspection(r.Context(), id, user.ID)
 	if err != nil {
-		h.logger.Error("failed to fetch images", "error", err, "inspection_id", id)
+		h.logger.ErrorContext(r.Context(), "failed to fetch images", "error", err, "inspection_id", id)
 		images = []domain.Image{}
 	}
 
 	// Populate thumbnail URLs for gallery
 	imageDisplays := make([]inspections.ImageDisplay, 0, len(images))
 	for _, img := range images {
-		thumbnailURL, err := h.imageService.GetThumbnailURL(r.Context(), img.ID, user.ID)
+		thumbnailURL, err := h.imageService.GetThumbnailURL(r.Context(), img.ID, user.ID, domain.DefaultThumbnailSize)
 		if err != nil {
-			h.logger.Error("failed to generate thumbnail URL", "error", err, "image_id", img.ID)
+			h.logger.ErrorContext(r.Context(), "failed to generate thumbnail URL", "error", err, "image_id", img.ID)
 			thumbnailURL = ""
 		}
 
@@ -259,6 +302,7 @@ func (h *InspectionHandler) ShowTempl(w http.ResponseWriter, r *http.Request) {
 			ThumbnailURL:     thumbnailURL,
 			OriginalFilename: img.OriginalFilename,
 			AnalysisStatus:   string(img.AnalysisStatus),
+			AnalysisError:    img.AnalysisError,
 			SizeMB:           fmt.Sprintf("%.2f", img.SizeMB()),
 		})
 	}
@@ -266,7 +310,7 @@ func (h *InspectionHandler) ShowTempl(w http.ResponseWriter, r *http.Request) {
 	// Build analysis status data
 	analysisStatus, err := h.buildAnalysisStatusData(r.Context(), id, user.ID)
 	if err != nil {
-		h.logger.Error("failed to build analysis status", "error", err, "inspection_id", id)
+		h.logger.ErrorContext(r.Context(), "failed to build analysis status", "error", err, "inspection_id", id)
 		analysisStatus = &AnalysisStatusData{
 			InspectionID: id,
 			Status:       inspection.Status,
@@ -275,9 +319,9 @@ func (h *InspectionHandler) ShowTempl(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Fetch violations
-	violations, err := h.violationService.ListByInspection(r.Context(), id, user.ID)
+	violations, err := h.violationService.ListByInspection(r.Context(), id, user.ID, nil)
 	if err != nil {
-		h.logger.Error("failed to list violations", "error", err, "inspection_id", id)
+		h.logger.ErrorContext(r.Context(), "failed to list violations", "error", err, "inspection_id", id)
 		violations = []domain.Violation{}
 	}
 
@@ -293,7 +337,7 @@ func (h *InspectionHandler) ShowTempl(w http.ResponseWriter, r *http.Request) {
 	// Fetch reports for this inspection via service
 	reports, err := h.reportService.ListByInspection(r.Context(), id, user.ID)
 	if err != nil {
-		h.logger.Error("failed to list reports", "error", err, "inspection_id", id)
+		h.logger.ErrorContext(r.Context(), "failed to list reports", "error", err, "inspection_id", id)
 		reports = []domain.Report{}
 	}
 
@@ -306,27 +350,36 @@ func (h *InspectionHandler) ShowTempl(w http.ResponseWriter, r *http.Request) {
 			ViolationCount: report.ViolationCount,
 			HasPDF:         report.HasPDF(),
 			HasDOCX:        report.HasDOCX(),
+			Failed:         report.IsFailed(),
+			FailedFormat:   string(report.FailedFormat),
+			Recipients:     report.RecipientEmails,
 		})
 	}
 
-	// Fetch client email if inspection has a client
+	// Fetch client email if inspection has a client; the client belongs to
+	// the inspection's owner
 	var clientEmail string
 	if inspection.ClientID != nil {
-		client, err := h.clientService.GetByID(r.Context(), *inspection.ClientID, user.ID)
+		client, err := h.clientService.GetByID(r.Context(), *inspection.ClientID, inspection.UserID)
 		if err == nil && client.Email != "" {
 			clientEmail = client.Email
 		}
 	}
 
 	// Can generate report if there are confirmed violations and inspection is in review or completed status
-	canGenerateReport := counts.Confirmed > 0 && (inspection.Status == domain.InspectionStatusReview || inspection.Status == domain.InspectionStatusCompleted)
+	canGenerateReport := counts.Confirmed > 0 && inspection.Status.AllowsReportGeneration()
+
+	display := domainInspectionToDisplay(inspection)
+	applyDisplayFormat(display, inspection, h.displayFormat(r, user.ID))
 
 	data := inspections.ShowPageData{
 		CurrentPath:  r.URL.Path,
 		CSRFToken:    "",
 		User:         domainUserToInspectionDisplay(user),
-		Inspection:   domainInspectionToDisplay(inspection),
+		Inspection:   display,
 		InspectionID: id.String(),
+		Assignee:     h.assigneeField(r.Context(), user.ID, inspection),
+		Template:     templateField(user.ID, inspection),
 		CanUpload:    inspection.CanAddPhotos(),
 		IsAnalyzing:  analysisStatus.IsAnalyzing,
 		GalleryData: inspections.ImageGalleryData{
@@ -342,12 +395,17 @@ func (h *InspectionHandler) ShowTempl(w http.ResponseWriter, r *http.Request) {
 		Reports:           reportDisplays,
 		ClientEmail:       clientEmail,
 		CanGenerateReport: canGenerateReport,
+		CanShare:          inspection.Status == domain.InspectionStatusCompleted,
+		CanAddViolation:   inspection.IsEditable(),
+		ManualViolation:   h.manualViolationForm(r.Context(), id, user.ID),
+		QuotaWarning:      h.analysisQuotaWarning(r.Context(), user),
+		UploadLimits:      uploadLimitsToTempl(h.uploadLimits),
 		Flash:             nil,
 	}
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	if err := inspections.ShowPage(data).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render inspection show page", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render inspection show page", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -359,7 +417,9 @@ func (h *InspectionHandler) ReviewTempl(w http.ResponseWriter, r *http.Request)
 }
 
 // ReviewQueueTempl displays the keyboard-focused queue-based violation review page using templ.
-// Supports htmx partial requests via ?pos=N query param.
+// Supports htmx partial requests via ?pos=N query param, and narrows the queue
+// with ?severity=a,b&confidence=c&sort=severity_desc|confidence_asc.
+// ?violation=ID opens the queue at that violation, e.g. from violation search.
 func (h *InspectionHandler) ReviewQueueTempl(w http.ResponseWriter, r *http.Request) {
 	user := auth.GetUserFromRequest(r)
 	if user == nil {
@@ -380,61 +440,50 @@ func (h *InspectionHandler) ReviewQueueTempl(w http.ResponseWriter, r *http.Requ
 		if code == domain.ENOTFOUND {
 			NotFoundResponse(w, r, h.logger)
 		} else {
-			h.logger.Error("failed to fetch inspection", "error", err, "inspection_id", id)
+			h.logger.ErrorContext(r.Context(), "failed to fetch inspection", "error", err, "inspection_id", id)
 			h.renderIndexErrorTempl(w, r, user, "Failed to load inspection. Please try again.")
 		}
 		return
 	}
 
-	// Fetch violations
-	violations, err := h.violationService.ListByInspection(r.Context(), id, user.ID)
+	// Fetch the working set of violations, narrowed by any queue filter
+	filter := parseViolationFilter(r.URL.Query())
+	violations, err := h.violationService.ListByInspection(r.Context(), id, user.ID, &filter)
 	if err != nil {
-		h.logger.Error("failed to list violations", "error", err, "inspection_id", id)
+		h.logger.ErrorContext(r.Context(), "failed to list violations", "error", err, "inspection_id", id)
 		h.renderIndexErrorTempl(w, r, user, "Failed to load violations. Please try again.")
 		return
 	}
 
-	// Calculate counts
-	domainCounts := domain.CalculateViolationCounts(violations)
-	counts := inspections.ViolationCountsData{
-		Total:     domainCounts.Total,
-		Pending:   domainCounts.Pending,
-		Confirmed: domainCounts.Confirmed,
-		Rejected:  domainCounts.Rejected,
-	}
-
-	// Parse position from query param (defaults to first pending or 0)
-	position := 0
-	if posStr := r.URL.Query().Get("pos"); posStr != "" {
-		if p, err := strconv.Atoi(posStr); err == nil && p >= 0 && p < len(violations) {
-			position = p
-		}
-	} else {
-		// Default to first pending violation
-		for i, v := range violations {
-			if v.Status == domain.ViolationStatusPending {
-				position = i
-				break
-			}
+	// Resolve position, counts, and completion against the filtered set
+	queue := buildReviewQueueState(violations, r.URL.Query().Get("pos"))
+	if violationID, err := uuid.Parse(r.URL.Query().Get("violation")); err == nil {
+		if pos := positionOfViolation(queue.Violations, violationID); pos >= 0 {
+			queue.Position = pos
 		}
 	}
-
-	// Check if all violations have been reviewed (complete state)
-	isComplete := len(violations) > 0 && counts.Pending == 0
+	counts := inspections.ViolationCountsData{
+		Total:     queue.Counts.Total,
+		Pending:   queue.Counts.Pending,
+		Confirmed: queue.Counts.Confirmed,
+		Rejected:  queue.Counts.Rejected,
+	}
 
 	// Determine if this is an htmx request
 	isHTMX := r.Header.Get("HX-Request") == "true"
 
 	// Build current violation data (if any)
 	var currentViolation *inspections.ViolationDisplay
-	if len(violations) > 0 && position < len(violations) {
-		v := h.domainViolationToDisplay(r.Context(), violations[position], user.ID)
+	if len(queue.Violations) > 0 && queue.Position < len(queue.Violations) {
+		v := h.domainViolationToDisplay(r.Context(), queue.Violations[queue.Position], user.ID)
 		currentViolation = &v
 	}
 
+	shortcuts := h.reviewShortcuts(r, user.ID)
+
 	// For htmx requests, return just the partials
 	if isHTMX {
-		h.renderQueuePartials(w, r, id.String(), violations, position, counts, isComplete, currentViolation)
+		h.renderQueuePartials(w, r, id.String(), queue, filter, currentViolation, shortcuts)
 		return
 	}
 
@@ -445,22 +494,27 @@ func (h *InspectionHandler) ReviewQueueTempl(w http.ResponseWriter, r *http.Requ
 		User:            domainUserToInspectionDisplay(user),
 		Inspection:      domainInspectionToDisplay(inspection),
 		Violation:       currentViolation,
-		Position:        position,
-		TotalCount:      len(violations),
+		Position:        queue.Position,
+		TotalCount:      len(queue.Violations),
 		ViolationCounts: counts,
-		IsComplete:      isComplete,
+		IsComplete:      queue.IsComplete,
+		FilterQuery:     violationFilterQuery(filter),
+		FilterLabel:     violationFilterLabel(filter),
+		Shortcuts:       reviewShortcutDisplays(shortcuts),
 		Flash:           nil,
 	}
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	if err := inspections.ReviewQueuePage(data).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render review queue page", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render review queue page", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
 
 // ReviewQueueUpdateStatus handles PUT requests to update a violation's status in queue context.
 // PUT /inspections/{id}/review/queue/violations/{vid}/status?status=confirmed|rejected&pos=N
+// Accepts the same severity/confidence/sort params as the queue page so that
+// next-pending navigation stays within the filtered set.
 func (h *InspectionHandler) ReviewQueueUpdateStatus(w http.ResponseWriter, r *http.Request) {
 	user := auth.GetUserFromRequest(r)
 	if user == nil {
@@ -510,66 +564,118 @@ func (h *InspectionHandler) ReviewQueueUpdateStatus(w http.ResponseWriter, r *ht
 		if code == domain.ENOTFOUND {
 			http.Error(w, "Violation not found", http.StatusNotFound)
 		} else {
-			h.logger.Error("failed to update violation status", "error", err, "violation_id", violationID)
-			http.Error(w, "Failed to update status", http.StatusInternalServerError)
+			ServerErrorResponse(w, r, h.logger.With("violation_id", violationID), fmt.Errorf("update violation status: %w", err))
 		}
 		return
 	}
 
-	// Fetch all violations to recalculate counts and find next pending
-	violations, err := h.violationService.ListByInspection(r.Context(), inspectionID, user.ID)
+	// Re-fetch the filtered working set to recalculate counts and find next pending
+	filter := parseViolationFilter(r.URL.Query())
+	violations, err := h.violationService.ListByInspection(r.Context(), inspectionID, user.ID, &filter)
 	if err != nil {
-		h.logger.Error("failed to list violations after status update", "error", err, "inspection_id", inspectionID)
-		http.Error(w, "Failed to refresh violations", http.StatusInternalServerError)
+		ServerErrorResponse(w, r, h.logger.With("inspection_id", inspectionID), fmt.Errorf("list violations after status update: %w", err))
 		return
 	}
 
-	// Calculate updated counts
-	domainCounts := domain.CalculateViolationCounts(violations)
-	counts := inspections.ViolationCountsData{
-		Total:     domainCounts.Total,
-		Pending:   domainCounts.Pending,
-		Confirmed: domainCounts.Confirmed,
-		Rejected:  domainCounts.Rejected,
-	}
+	queue := buildReviewQueueState(violations, strconv.Itoa(currentPos))
 
-	// Find next pending violation
-	nextPos := -1
-	// First, look for pending violations after current position
-	for i := currentPos + 1; i < len(violations); i++ {
-		if violations[i].Status == domain.ViolationStatusPending {
-			nextPos = i
-			break
+	// If complete, stay on the current position; otherwise advance to next pending
+	if !queue.IsComplete {
+		if nextPos := nextPendingPosition(queue.Violations, currentPos); nextPos >= 0 {
+			queue.Position = nextPos
 		}
 	}
-	// If none found, wrap around to beginning
-	if nextPos == -1 {
-		for i := 0; i < currentPos; i++ {
-			if violations[i].Status == domain.ViolationStatusPending {
-				nextPos = i
-				break
-			}
+
+	// Build current violation data (if any and not complete)
+	var currentViolation *inspections.ViolationDisplay
+	if len(queue.Violations) > 0 && queue.Position < len(queue.Violations) && !queue.IsComplete {
+		v := h.domainViolationToDisplay(r.Context(), queue.Violations[queue.Position], user.ID)
+		currentViolation = &v
+	}
+
+	// Render partials, followed by the undo bar for this decision
+	shortcuts := h.reviewShortcuts(r, user.ID)
+	h.renderQueuePartials(w, r, inspectionID.String(), queue, filter, currentViolation, shortcuts)
+	h.renderQueueUndo(w, r, partials.QueueUndoData{
+		InspectionID: inspectionID.String(),
+		ViolationID:  violationID.String(),
+		NewStatus:    string(newStatus),
+		Position:     currentPos,
+		FilterQuery:  violationFilterQuery(filter),
+		UndoKey:      shortcuts[domain.ReviewActionUndo],
+	})
+}
+
+// ReviewQueueUndoStatus reverts the most recent review decision in queue context.
+// POST /inspections/{id}/review/queue/violations/{vid}/undo?pos=N
+// Restores the status recorded in the audit history before the decision and
+// returns the queue partials positioned on the violation. Only the single most
+// recent decision is tracked, via the undo bar rendered after each status update.
+func (h *InspectionHandler) ReviewQueueUndoStatus(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUserFromRequest(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	inspectionIDStr := r.PathValue("id")
+	inspectionID, err := uuid.Parse(inspectionIDStr)
+	if err != nil {
+		http.Error(w, "Invalid inspection ID", http.StatusBadRequest)
+		return
+	}
+
+	violationIDStr := r.PathValue("vid")
+	violationID, err := uuid.Parse(violationIDStr)
+	if err != nil {
+		http.Error(w, "Invalid violation ID", http.StatusBadRequest)
+		return
+	}
+
+	// Restore the status recorded before the last decision
+	restoreStatus, err := h.violationService.UndoStatus(r.Context(), inspectionID, violationID, user.ID)
+	if err != nil {
+		switch domain.ErrorCode(err) {
+		case domain.ENOTFOUND:
+			http.Error(w, "Violation not found", http.StatusNotFound)
+		case domain.ECONFLICT:
+			http.Error(w, domain.ErrorMessage(err), http.StatusConflict)
+		default:
+			ServerErrorResponse(w, r, h.logger.With("violation_id", violationID), fmt.Errorf("undo violation status: %w", err))
 		}
+		return
 	}
 
-	// Check if all violations have been reviewed (complete state)
-	isComplete := counts.Pending == 0
+	filter := parseViolationFilter(r.URL.Query())
+	violations, err := h.violationService.ListByInspection(r.Context(), inspectionID, user.ID, &filter)
+	if err != nil {
+		ServerErrorResponse(w, r, h.logger.With("inspection_id", inspectionID), fmt.Errorf("list violations after undo: %w", err))
+		return
+	}
 
-	// If complete, use current position; otherwise use next pending
-	position := currentPos
-	if !isComplete && nextPos >= 0 {
-		position = nextPos
+	// Position on the restored violation; fall back to the submitted position
+	// in case the violation no longer matches the filter
+	pos := positionOfViolation(violations, violationID)
+	if pos < 0 {
+		pos, _ = strconv.Atoi(r.URL.Query().Get("pos"))
 	}
+	queue := buildReviewQueueState(violations, strconv.Itoa(pos))
 
-	// Build current violation data (if any and not complete)
 	var currentViolation *inspections.ViolationDisplay
-	if len(violations) > 0 && position < len(violations) && !isComplete {
-		v := h.domainViolationToDisplay(r.Context(), violations[position], user.ID)
+	if len(queue.Violations) > 0 && queue.Position < len(queue.Violations) && !queue.IsComplete {
+		v := h.domainViolationToDisplay(r.Context(), queue.Violations[queue.Position], user.ID)
 		currentViolation = &v
 	}
 
-	// Render partials
-	h.renderQueuePartials(w, r, inspectionID.String(), violations, position, counts, isComplete, currentViolation)
+	// Render partials and clear the undo bar; the decision has been reverted
+	h.renderQueuePartials(w, r, inspectionID.String(), queue, filter, currentViolation, h.reviewShortcuts(r, user.ID))
+	h.renderQueueUndo(w, r, partials.QueueUndoData{})
+
+	h.logger.InfoContext(r.Context(), "review decision undone",
+		"violation_id", violationID,
+		"user_id", user.ID,
+		"status", restoreStatus,
+	)
 }
 
 // =============================================================================
@@ -612,81 +718,68 @@ func (h *InspectionHandler) GenerateReport(w http.ResponseWriter, r *http.Reques
 		if code == domain.ENOTFOUND {
 			http.Error(w, "Inspection not found", http.StatusNotFound)
 		} else {
-			h.logger.Error("failed to fetch inspection", "error", err, "inspection_id", id)
-			http.Error(w, "Failed to fetch inspection", http.StatusInternalServerError)
+			ServerErrorResponse(w, r, h.logger.With("inspection_id", id), fmt.Errorf("fetch inspection: %w", err))
 		}
 		return
 	}
 
 	// Verify inspection status allows report generation (review or completed)
-	if inspection.Status != domain.InspectionStatusReview && inspection.Status != domain.InspectionStatusCompleted {
+	if !inspection.Status.AllowsReportGeneration() {
 		http.Error(w, "Inspection must be in 'review' or 'completed' status to generate a report", http.StatusBadRequest)
 		return
 	}
 
-	// Get recipient email from form (optional)
-	recipientEmail := r.FormValue("recipient_email")
+	// Get recipient emails from form (optional; comma separated)
+	recipientEmails, err := domain.ParseReportRecipients(r.FormValue("recipient_emails"))
+	if err != nil {
+		h.renderReportError(w, r, domain.ErrorMessage(err))
+		return
+	}
 
 	// Enqueue the report generation job via service
-	err = h.reportService.TriggerGeneration(r.Context(), id, user.ID, format, recipientEmail)
+	jobID, err := h.reportService.TriggerGeneration(r.Context(), id, user.ID, format, recipientEmails)
 	if err != nil {
-		h.logger.Error("failed to enqueue report generation job", "error", err, "inspection_id", id)
-		http.Error(w, "Failed to start report generation", http.StatusInternalServerError)
+		if code := domain.ErrorCode(err); code == domain.EQUOTA || code == domain.ERATELIMIT {
+			h.renderReportError(w, r, domain.ErrorMessage(err))
+			return
+		}
+		ServerErrorResponse(w, r, h.logger.With("inspection_id", id), fmt.Errorf("enqueue report generation job: %w", err))
 		return
 	}
 
-	h.logger.Info("Report generation job enqueued",
+	h.logger.InfoContext(r.Context(), "Report generation job enqueued",
 		"inspection_id", id,
 		"user_id", user.ID,
 		"format", format,
-		"recipient_email", recipientEmail,
+		"recipient_count", len(recipientEmails),
+		"job_id", jobID,
 	)
 
-	// Return success response (htmx partial)
+	// Return success response (htmx partial); the reports list polls for the
+	// report on reportQueued
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	w.Header().Set("HX-Trigger", "reportQueued")
+	data := partials.ReportQueuedData{
+		JobID:      jobID.String(),
+		Format:     format,
+		Recipients: recipientEmails,
+	}
+	if err := partials.ReportQueued(data).Render(r.Context(), w); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to render report queued message", "error", err)
+	}
+}
 
-	// Start polling and stop after 60 seconds
-	pollingScript := `<script>
-		document.body.classList.add('report-polling');
-		setTimeout(() => document.body.classList.remove('report-polling'), 60000);
-	</script>`
-
-	// Customize message based on whether recipient email was provided
-	if recipientEmail != "" {
-		_, _ = fmt.Fprintf(w, `<div class="rounded-md bg-green-50 p-4">
-			<div class="flex">
-				<div class="flex-shrink-0">
-					<svg class="h-5 w-5 text-green-400" viewBox="0 0 20 20" fill="currentColor">
-						<path fill-rule="evenodd" d="M10 18a8 8 0 100-16 8 8 0 000 16zm3.857-9.809a.75.75 0 00-1.214-.882l-3.483 4.79-1.88-1.88a.75.75 0 10-1.06 1.061l2.5 2.5a.75.75 0 001.137-.089l4-5.5z" clip-rule="evenodd"/>
-					</svg>
-				</div>
-				<div class="ml-3">
-					<p class="text-sm font-medium text-green-800">
-						Report generation started! The %s report will be emailed to %s when ready.
-					</p>
-				</div>
-			</div>
-		</div>%s`, format, recipientEmail, pollingScript)
-	} else {
-		_, _ = fmt.Fprintf(w, `<div class="rounded-md bg-green-50 p-4">
-			<div class="flex">
-				<div class="flex-shrink-0">
-					<svg class="h-5 w-5 text-green-400" viewBox="0 0 20 20" fill="currentColor">
-						<path fill-rule="evenodd" d="M10 18a8 8 0 100-16 8 8 0 000 16zm3.857-9.809a.75.75 0 00-1.214-.882l-3.483 4.79-1.88-1.88a.75.75 0 10-1.06 1.061l2.5 2.5a.75.75 0 001.137-.089l4-5.5z" clip-rule="evenodd"/>
-					</svg>
-				</div>
-				<div class="ml-3">
-					<p class="text-sm font-medium text-green-800">
-						Report generation started! The %s report will appear below when ready.
-					</p>
-				</div>
-			</div>
-		</div>%s`, format, pollingScript)
+// renderReportError renders why a report could not be queued.
+func (h *InspectionHandler) renderReportError(w http.ResponseWriter, r *http.Request, message string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := partials.ReportError(message).Render(r.Context(), w); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to render report error", "error", err)
 	}
 }
 
 // UpdateStatusTempl handles updating an inspection's status via htmx.
+// Completing an inspection that isn't ready renders what blocks it, with a
+// form to force completion by typing domain.CompletionForceConfirmation.
 // PUT /inspections/{id}/status
 func (h *InspectionHandler) UpdateStatusTempl(w http.ResponseWriter, r *http.Request) {
 	user := auth.GetUserFromRequest(r)
@@ -721,12 +814,20 @@ func (h *InspectionHandler) UpdateStatusTempl(w http.ResponseWriter, r *http.Req
 	}
 
 	// Update the status
-	err = h.inspectionService.UpdateStatus(r.Context(), domain.UpdateInspectionStatusParams{
-		ID:     id,
-		UserID: user.ID,
-		Status: newStatus,
-	})
+	params := domain.UpdateInspectionStatusParams{
+		ID:           id,
+		UserID:       user.ID,
+		Status:       newStatus,
+		Force:        r.FormValue("force") == "true",
+		Confirmation: r.FormValue("confirmation"),
+	}
+	err = h.inspectionService.UpdateStatus(r.Context(), params)
 	if err != nil {
+		var blockers domain.CompletionBlockers
+		if errors.As(err, &blockers) {
+			h.renderCompletionBlocked(w, r, params, blockers, err)
+			return
+		}
 		code := domain.ErrorCode(err)
 		switch code {
 		case domain.ENOTFOUND:
@@ -734,13 +835,12 @@ func (h *InspectionHandler) UpdateStatusTempl(w http.ResponseWriter, r *http.Req
 		case domain.EINVALID:
 			http.Error(w, domain.ErrorMessage(err), http.StatusBadRequest)
 		default:
-			h.logger.Error("failed to update inspection status", "error", err, "inspection_id", id)
-			http.Error(w, "Failed to update status", http.StatusInternalServerError)
+			ServerErrorResponse(w, r, h.logger.With("inspection_id", id), fmt.Errorf("update inspection status: %w", err))
 		}
 		return
 	}
 
-	h.logger.Info("inspection status updated via htmx",
+	h.logger.InfoContext(r.Context(), "inspection status updated via htmx",
 		"inspection_id", id,
 		"user_id", user.ID,
 		"new_status", newStatus,
@@ -761,7 +861,7 @@ func (h *InspectionHandler) UpdateStatusTempl(w http.ResponseWriter, r *http.Req
 				</div>
 				<div class="ml-3">
 					<p class="text-sm font-medium text-green-800">
-						Inspection marked as completed. You can now generate the final report.
+						Inspection marked as completed. You can now share its report with your client.
 					</p>
 				</div>
 			</div>
@@ -783,6 +883,7 @@ func (h *InspectionHandler) UpdateStatusTempl(w http.ResponseWriter, r *http.Req
 			</div>
 		</div>`)
 	default:
+		w.Header().Set("HX-Trigger", "inspectionStatusChanged")
 		_, _ = fmt.Fprintf(w, `<div class="rounded-md bg-blue-50 p-4">
 			<div class="flex">
 				<div class="ml-3">
@@ -795,6 +896,178 @@ func (h *InspectionHandler) UpdateStatusTempl(w http.ResponseWriter, r *http.Req
 	}
 }
 
+// renderCompletionBlocked renders why an inspection can't be completed, with
+// links to the review queue and the reports section.
+func (h *InspectionHandler) renderCompletionBlocked(w http.ResponseWriter, r *http.Request, params domain.UpdateInspectionStatusParams, blockers domain.CompletionBlockers, err error) {
+	data := partials.InspectionCompletionBlockedData{
+		InspectionID: params.ID.String(),
+		Confirmation: params.Confirmation,
+	}
+	if pending := blockers.PendingReason(); pending != "" {
+		data.Blockers = append(data.Blockers, partials.CompletionBlockerDisplay{
+			Reason:    pending,
+			LinkURL:   fmt.Sprintf("/inspections/%s/review/queue", params.ID),
+			LinkLabel: "Review violations",
+		})
+	}
+	if blockers.MissingReport {
+		data.Blockers = append(data.Blockers, partials.CompletionBlockerDisplay{
+			Reason:    domain.MissingReportReason,
+			LinkURL:   "#reports",
+			LinkLabel: "Generate a report",
+		})
+	}
+	if params.Force {
+		data.Error = domain.ErrorMessage(err)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := partials.InspectionCompletionBlocked(data).Render(r.Context(), w); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to render completion blockers", "error", err, "inspection_id", params.ID)
+	}
+}
+
+// AllowedStatuses renders buttons for the statuses the inspection can move to.
+// GET /inspections/{id}/allowed-statuses
+func (h *InspectionHandler) AllowedStatuses(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUserFromRequest(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	idStr := r.PathValue("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		http.Error(w, "Invalid inspection ID", http.StatusBadRequest)
+		return
+	}
+
+	inspection, err := h.inspectionService.GetByID(r.Context(), id, user.ID)
+	if err != nil {
+		if domain.ErrorCode(err) == domain.ENOTFOUND {
+			http.Error(w, "Inspection not found", http.StatusNotFound)
+		} else {
+			ServerErrorResponse(w, r, h.logger.With("inspection_id", id), fmt.Errorf("get inspection: %w", err))
+		}
+		return
+	}
+
+	allowed := inspection.Status.AllowedTransitions()
+	statuses := make([]string, len(allowed))
+	for i, status := range allowed {
+		statuses[i] = status.String()
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := partials.InspectionStatusActions(partials.InspectionStatusActionsData{
+		InspectionID: id.String(),
+		Status:       inspection.Status.String(),
+		Statuses:     statuses,
+	}).Render(r.Context(), w); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to render inspection status actions", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// Assign assigns the inspection to an organization member, or unassigns it
+// when assignee_id is empty, and renders the updated assignee field.
+// POST /inspections/{id}/assignee
+func (h *InspectionHandler) Assign(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUserFromRequest(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	idStr := r.PathValue("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		http.Error(w, "Invalid inspection ID", http.StatusBadRequest)
+		return
+	}
+
+	params := domain.AssignInspectionParams{ID: id, UserID: user.ID}
+	if assigneeStr := r.FormValue("assignee_id"); assigneeStr != "" {
+		assigneeID, err := uuid.Parse(assigneeStr)
+		if err != nil {
+			http.Error(w, "Invalid assignee", http.StatusBadRequest)
+			return
+		}
+		params.AssigneeID = &assigneeID
+	}
+
+	assignErr := h.inspectionService.Assign(r.Context(), params)
+	switch domain.ErrorCode(assignErr) {
+	case "", domain.EINVALID:
+	case domain.ENOTFOUND:
+		http.Error(w, "Inspection not found", http.StatusNotFound)
+		return
+	default:
+		ServerErrorResponse(w, r, h.logger.With("inspection_id", id), fmt.Errorf("assign inspection: %w", assignErr))
+		return
+	}
+
+	inspection, err := h.inspectionService.GetByID(r.Context(), id, user.ID)
+	if err != nil {
+		ServerErrorResponse(w, r, h.logger.With("inspection_id", id), fmt.Errorf("get inspection: %w", err))
+		return
+	}
+
+	data := h.assigneeField(r.Context(), user.ID, inspection)
+	if assignErr != nil {
+		data.Error = domain.ErrorMessage(assignErr)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := inspections.AssigneeField(data).Render(r.Context(), w); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to render assignee field", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// assigneeField builds the inspection's assignee field. The members of the
+// user's organization are offered as assignees; personal accounts get none,
+// so the field only names an existing assignee.
+func (h *InspectionHandler) assigneeField(ctx context.Context, userID uuid.UUID, inspection *domain.Inspection) inspections.AssigneeFieldData {
+	data := inspections.AssigneeFieldData{
+		InspectionID: inspection.ID.String(),
+		AssigneeName: inspection.AssigneeName,
+	}
+	if inspection.AssignedTo != nil {
+		data.AssigneeID = inspection.AssignedTo.String()
+	}
+	if h.orgService == nil {
+		return data
+	}
+
+	membership, err := h.orgService.Membership(ctx, userID)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to get organization", "error", err, "user_id", userID)
+		return data
+	}
+	if membership.Personal {
+		return data
+	}
+
+	members, err := h.orgService.ListMembers(ctx, userID)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list organization members", "error", err, "user_id", userID)
+		return data
+	}
+	for _, member := range members {
+		name := member.Name
+		if name == "" {
+			name = member.Email
+		}
+		data.Members = append(data.Members, inspections.AssigneeOption{
+			ID:   member.UserID.String(),
+			Name: name,
+		})
+	}
+	return data
+}
+
 // =============================================================================
 // Templ Route Registration
 // =============================================================================
@@ -802,8 +1075,10 @@ func (h *InspectionHandler) UpdateStatusTempl(w http.ResponseWriter, r *http.Req
 // RegisterTemplRoutes registers templ-based inspection routes on the provided ServeMux.
 func (h *InspectionHandler) RegisterTemplRoutes(mux *http.ServeMux, requireUser func(http.Handler) http.Handler) {
 	mux.Handle("GET /inspections", requireUser(http.HandlerFunc(h.IndexTempl)))
+	mux.Handle("GET /inspections/organization", requireUser(http.HandlerFunc(h.OrganizationIndexTempl)))
 	mux.Handle("GET /inspections/new", requireUser(http.HandlerFunc(h.NewTempl)))
 	mux.Handle("POST /inspections", requireUser(http.HandlerFunc(h.Create)))
+	mux.Handle("PATCH /inspections/drafts", requireUser(http.HandlerFunc(h.SaveDraft)))
 	mux.Handle("GET /inspections/{id}", requireUser(http.HandlerFunc(h.ShowTempl)))
 	mux.Handle("GET /inspections/{id}/edit", requireUser(http.HandlerFunc(h.EditTempl)))
 	mux.Handle("PUT /inspections/{id}", requireUser(http.HandlerFunc(h.Update)))
@@ -812,8 +1087,18 @@ func (h *InspectionHandler) RegisterTemplRoutes(mux *http.ServeMux, requireUser
 	mux.Handle("GET /inspections/{id}/review", requireUser(http.HandlerFunc(h.ReviewTempl)))
 	mux.Handle("GET /inspections/{id}/review/queue", requireUser(http.HandlerFunc(h.ReviewQueueTempl)))
 	mux.Handle("PUT /inspections/{id}/review/queue/violations/{vid}/status", requireUser(http.HandlerFunc(h.ReviewQueueUpdateStatus)))
+	mux.Handle("POST /inspections/{id}/review/queue/violations/{vid}/undo", requireUser(http.HandlerFunc(h.ReviewQueueUndoStatus)))
 	mux.Handle("GET /inspections/{id}/violations-summary", requireUser(http.HandlerFunc(h.ViolationsSummary)))
+	mux.Handle("GET /inspections/{id}/timeline", requireUser(http.HandlerFunc(h.Timeline)))
+	mux.Handle("GET /inspections/{id}/events", requireUser(http.HandlerFunc(h.Events)))
+	mux.Handle("GET /inspections/{id}/progress", requireUser(http.HandlerFunc(h.Progress)))
 	mux.Handle("PUT /inspections/{id}/status", requireUser(http.HandlerFunc(h.UpdateStatusTempl)))
+	mux.Handle("GET /inspections/{id}/allowed-statuses", requireUser(http.HandlerFunc(h.AllowedStatuses)))
+	mux.Handle("POST /inspections/{id}/assignee", requireUser(http.HandlerFunc(h.Assign)))
+	mux.Handle("POST /inspections/{id}/template", requireUser(http.HandlerFunc(h.SaveAsTemplate)))
+	mux.Handle("DELETE /inspections/{id}/template", requireUser(http.HandlerFunc(h.RemoveTemplate)))
+	mux.Handle("GET /templates", requireUser(http.HandlerFunc(h.TemplatesTempl)))
+	mux.Handle("POST /templates/{id}/inspections", requireUser(http.HandlerFunc(h.CreateFromTemplate)))
 }
 
 // =============================================================================
@@ -843,6 +1128,8 @@ func domainInspectionToListItem(i *domain.Inspection) inspections.InspectionList
 		InspectionDate: i.InspectionDate.Format("Jan 2, 2006"),
 		Status:         string(i.Status),
 		ViolationCount: i.ViolationCount,
+		OwnerName:      i.OwnerName,
+		AssigneeName:   i.AssigneeName,
 	}
 }
 
@@ -872,11 +1159,69 @@ func domainInspectionToDisplay(i *domain.Inspection) *inspections.InspectionDisp
 		WeatherConditions: i.WeatherConditions,
 		Temperature:       i.Temperature,
 		InspectorNotes:    i.InspectorNotes,
+		Latitude:          i.Latitude,
+		Longitude:         i.Longitude,
 		CreatedAt:         i.CreatedAt.Format("Jan 2, 2006"),
 		UpdatedAt:         i.UpdatedAt.Format("Jan 2, 2006"),
 	}
 }
 
+// applyDisplayFormat rewrites the dates and temperature of an inspection's
+// display in the reader's date locale and units.
+func applyDisplayFormat(d *inspections.InspectionDisplay, i *domain.Inspection, format domain.DisplayFormat) {
+	d.InspectionDate = format.DateLocale.FormatShortDate(i.InspectionDate)
+	d.Temperature = domain.FormatTemperature(i.Temperature, format.Units)
+	d.CreatedAt = format.DateLocale.FormatShortDate(i.CreatedAt)
+	d.UpdatedAt = format.DateLocale.FormatShortDate(i.UpdatedAt)
+}
+
+// domainInspectionToFormValues converts domain.Inspection to the values of
+// the edit form, including the version the edit is based on.
+func domainInspectionToFormValues(i *domain.Inspection) inspections.InspectionFormValues {
+	clientID := ""
+	if i.ClientID != nil {
+		clientID = i.ClientID.String()
+	}
+
+	return inspections.InspectionFormValues{
+		Title:             i.Title,
+		ClientID:          clientID,
+		AddressLine1:      i.AddressLine1,
+		AddressLine2:      i.AddressLine2,
+		City:              i.City,
+		State:             i.State,
+		PostalCode:        i.PostalCode,
+		InspectionDate:    i.InspectionDate.Format("2006-01-02"),
+		WeatherConditions: i.WeatherConditions,
+		Temperature:       i.Temperature,
+		InspectorNotes:    i.InspectorNotes,
+		Version:           strconv.Itoa(int(i.Version)),
+	}
+}
+
+// draftToFormValues converts an autosaved draft to form values. An empty
+// draft date falls back to defaultDate.
+func draftToFormValues(d *domain.InspectionDraft, defaultDate string) inspections.InspectionFormValues {
+	date := d.InspectionDate
+	if date == "" {
+		date = defaultDate
+	}
+
+	return inspections.InspectionFormValues{
+		Title:             d.Title,
+		ClientID:          d.ClientID,
+		AddressLine1:      d.AddressLine1,
+		AddressLine2:      d.AddressLine2,
+		City:              d.City,
+		State:             d.State,
+		PostalCode:        d.PostalCode,
+		InspectionDate:    date,
+		WeatherConditions: d.WeatherConditions,
+		Temperature:       d.Temperature,
+		InspectorNotes:    d.InspectorNotes,
+	}
+}
+
 // domainClientsToOptions converts []ClientOption to []inspections.ClientOption
 func domainClientsToOptions(clients []ClientOption) []inspections.ClientOption {
 	options := make([]inspections.ClientOption, len(clients))
@@ -889,6 +1234,69 @@ func domainClientsToOptions(clients []ClientOption) []inspections.ClientOption {
 	return options
 }
 
+// analysisQuotaWarning returns banner data when the user is close to or at
+// their monthly analysis limit, or nil otherwise. Lookup failures hide the
+// banner rather than failing the page.
+func (h *InspectionHandler) analysisQuotaWarning(ctx context.Context, user *domain.User) *partials.QuotaWarningData {
+	if h.quotaService == nil {
+		return nil
+	}
+	usage, err := h.quotaService.GetUsage(ctx, user.ID, user.QuotaTier())
+	if err != nil {
+		h.logger.WarnContext(ctx, "failed to get quota usage", "error", err, "user_id", user.ID)
+		return nil
+	}
+	return quotaWarningData(usage)
+}
+
+// manualViolationForm returns the data for the form adding a violation by
+// hand, offering the user's bookmarked regulations. Lookup failures leave
+// the regulations out rather than failing the page.
+func (h *InspectionHandler) manualViolationForm(ctx context.Context, inspectionID, userID uuid.UUID) inspections.ManualViolationFormData {
+	data := inspections.ManualViolationFormData{InspectionID: inspectionID.String()}
+	if h.regulations == nil {
+		return data
+	}
+	bookmarks, err := h.regulations.ListBookmarks(ctx, userID)
+	if err != nil {
+		h.logger.WarnContext(ctx, "failed to list bookmarked regulations", "error", err, "user_id", userID)
+		return data
+	}
+	for _, reg := range bookmarks {
+		data.Regulations = append(data.Regulations, inspections.RegulationOption{
+			ID:             reg.ID.String(),
+			StandardNumber: reg.StandardNumber,
+			Title:          reg.Title,
+		})
+	}
+	return data
+}
+
+// quotaWarningData converts quota usage to banner data, returning nil when
+// the user still has comfortable headroom.
+func quotaWarningData(usage *domain.QuotaUsage) *partials.QuotaWarningData {
+	if usage == nil || !usage.AnalysisNearLimit() {
+		return nil
+	}
+	return &partials.QuotaWarningData{
+		Remaining: usage.AnalysisRemaining(),
+		Limit:     usage.AnalysisLimit,
+		ResetsAt:  usage.ResetsAt.Format("January 2, 2006"),
+		Exhausted: usage.AnalysisExhausted(),
+	}
+}
+
+// uploadLimitsToTempl converts domain.UploadLimits to inspections.UploadLimitsData
+func uploadLimitsToTempl(l domain.UploadLimits) inspections.UploadLimitsData {
+	return inspections.UploadLimitsData{
+		Accept:       l.Accept(),
+		ContentTypes: l.ContentTypes,
+		MaxSize:      l.MaxSize,
+		MaxSizeLabel: l.MaxSizeLabel(),
+		Description:  l.TypeNames() + " up to " + l.MaxSizeLabel(),
+	}
+}
+
 // domainAnalysisStatusToTempl converts AnalysisStatusData to inspections.AnalysisStatusData
 func domainAnalysisStatusToTempl(a *AnalysisStatusData) inspections.AnalysisStatusData {
 	if a == nil {
@@ -903,6 +1311,7 @@ func domainAnalysisStatusToTempl(a *AnalysisStatusData) inspections.AnalysisStat
 		PendingImages:  a.PendingImages,
 		TotalImages:    a.TotalImages,
 		AnalyzedImages: a.AnalyzedImages,
+		FailedImages:   a.FailedImages,
 		ViolationCount: a.ViolationCount,
 		Message:        a.Message,
 		PollingEnabled: a.PollingEnabled,
@@ -914,22 +1323,38 @@ func (h *InspectionHandler) domainViolationToDisplay(ctx context.Context, v doma
 	// Get regulations for this violation
 	_, regulations, err := h.violationService.GetByIDWithRegulations(ctx, v.ID, userID)
 	if err != nil {
-		h.logger.Warn("failed to get regulations for violation", "error", err, "violation_id", v.ID)
+		h.logger.WarnContext(ctx, "failed to get regulations for violation", "error", err, "violation_id", v.ID)
 		regulations = []domain.ViolationRegulation{}
 	}
 
-	// Get thumbnail URL if violation has an image
+	// Get thumbnail URL and annotations if violation has an image
 	thumbnailURL := ""
 	originalURL := ""
 	imageID := ""
+	annotationDisplays := []inspections.ViolationAnnotationDisplay{}
 	if v.ImageID != nil {
 		imageID = v.ImageID.String()
-		thumbnailURL, err = h.imageService.GetThumbnailURL(ctx, *v.ImageID, userID)
+		thumbnailURL, err = h.imageService.GetThumbnailURL(ctx, *v.ImageID, userID, domain.DefaultThumbnailSize)
 		if err != nil {
-			h.logger.Warn("failed to generate thumbnail URL", "error", err, "image_id", *v.ImageID)
+			h.logger.WarnContext(ctx, "failed to generate thumbnail URL", "error", err, "image_id", *v.ImageID)
 		}
 		// Build original URL path for linking to full image
 		originalURL = fmt.Sprintf("/images/%s/original", imageID)
+
+		annotations, err := h.violationService.ListAnnotations(ctx, v.ID, userID)
+		if err != nil {
+			h.logger.WarnContext(ctx, "failed to list violation annotations", "error", err, "violation_id", v.ID)
+		}
+		for _, a := range annotations {
+			annotationDisplays = append(annotationDisplays, inspections.ViolationAnnotationDisplay{
+				Left:   a.Box.X * 100,
+				Top:    a.Box.Y * 100,
+				Width:  a.Box.Width * 100,
+				Height: a.Box.Height * 100,
+				Label:  a.Label,
+				Source: string(a.Source),
+			})
+		}
 	}
 
 	// Convert regulations
@@ -949,12 +1374,15 @@ func (h *InspectionHandler) domainViolationToDisplay(ctx context.Context, v doma
 		AIDescription:  v.AIDescription,
 		Status:         string(v.Status),
 		Severity:       string(v.Severity),
+		AISeverity:     string(v.AISeverity),
 		Confidence:     string(v.Confidence),
 		InspectorNotes: v.InspectorNotes,
 		ThumbnailURL:   thumbnailURL,
 		OriginalURL:    originalURL,
+		RegionURL:      v.RegionThumbnailURL(""),
 		ImageID:        imageID,
 		Regulations:    regDisplays,
+		Annotations:    annotationDisplays,
 	}
 }
 
@@ -974,7 +1402,7 @@ func (h *InspectionHandler) renderIndexErrorTempl(w http.ResponseWriter, r *http
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	if err := inspections.IndexPage(data).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render inspections index error", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render inspections index error", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -985,48 +1413,56 @@ func (h *InspectionHandler) renderQueuePartials(
 	w http.ResponseWriter,
 	r *http.Request,
 	inspectionID string,
-	violations []domain.Violation,
-	position int,
-	counts inspections.ViolationCountsData,
-	isComplete bool,
+	queue reviewQueueState,
+	filter domain.ViolationFilter,
 	currentViolation *inspections.ViolationDisplay,
+	shortcuts domain.ReviewShortcuts,
 ) {
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 
+	filterQuery := violationFilterQuery(filter)
+	headerData := partials.QueueHeaderData{
+		InspectionID: inspectionID,
+		Position:     queue.Position,
+		TotalCount:   len(queue.Violations),
+		Counts: partials.ViolationCounts{
+			Total:     queue.Counts.Total,
+			Pending:   queue.Counts.Pending,
+			Confirmed: queue.Counts.Confirmed,
+			Rejected:  queue.Counts.Rejected,
+		},
+		FilterLabel: violationFilterLabel(filter),
+	}
+
 	// Empty state
-	if len(violations) == 0 {
+	if len(queue.Violations) == 0 {
+		if !filter.IsEmpty() {
+			if err := partials.QueueHeaderOOB(headerData).Render(r.Context(), w); err != nil {
+				h.logger.ErrorContext(r.Context(), "failed to render queue header OOB", "error", err)
+			}
+		}
 		if err := partials.QueueEmptyState(inspectionID).Render(r.Context(), w); err != nil {
-			h.logger.Error("failed to render queue empty state", "error", err)
+			h.logger.ErrorContext(r.Context(), "failed to render queue empty state", "error", err)
 			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 		}
 		return
 	}
 
 	// Completion screen
-	if isComplete {
+	if queue.IsComplete {
 		// Render OOB header update first
-		headerData := partials.QueueHeaderData{
-			InspectionID: inspectionID,
-			Position:     position,
-			TotalCount:   len(violations),
-			Counts: partials.ViolationCounts{
-				Total:     counts.Total,
-				Pending:   counts.Pending,
-				Confirmed: counts.Confirmed,
-				Rejected:  counts.Rejected,
-			},
-		}
 		if err := partials.QueueHeaderOOB(headerData).Render(r.Context(), w); err != nil {
-			h.logger.Error("failed to render queue header OOB", "error", err)
+			h.logger.ErrorContext(r.Context(), "failed to render queue header OOB", "error", err)
 		}
 
 		completionData := partials.QueueCompletionData{
 			InspectionID:   inspectionID,
-			ConfirmedCount: counts.Confirmed,
-			RejectedCount:  counts.Rejected,
+			ConfirmedCount: queue.Counts.Confirmed,
+			RejectedCount:  queue.Counts.Rejected,
+			IsFiltered:     !filter.IsEmpty(),
 		}
 		if err := partials.QueueCompletion(completionData).Render(r.Context(), w); err != nil {
-			h.logger.Error("failed to render queue completion", "error", err)
+			h.logger.ErrorContext(r.Context(), "failed to render queue completion", "error", err)
 			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 		}
 		return
@@ -1035,19 +1471,8 @@ func (h *InspectionHandler) renderQueuePartials(
 	// Normal violation view
 	if currentViolation != nil {
 		// Render OOB header update first
-		headerData := partials.QueueHeaderData{
-			InspectionID: inspectionID,
-			Position:     position,
-			TotalCount:   len(violations),
-			Counts: partials.ViolationCounts{
-				Total:     counts.Total,
-				Pending:   counts.Pending,
-				Confirmed: counts.Confirmed,
-				Rejected:  counts.Rejected,
-			},
-		}
 		if err := partials.QueueHeaderOOB(headerData).Render(r.Context(), w); err != nil {
-			h.logger.Error("failed to render queue header OOB", "error", err)
+			h.logger.ErrorContext(r.Context(), "failed to render queue header OOB", "error", err)
 		}
 
 		// Convert inspections.ViolationDisplay to partials.QueueViolationDisplay
@@ -1061,6 +1486,11 @@ func (h *InspectionHandler) renderQueuePartials(
 			}
 		}
 
+		queueAnnotations := make([]partials.QueueAnnotationDisplay, len(currentViolation.Annotations))
+		for i, a := range currentViolation.Annotations {
+			queueAnnotations[i] = partials.QueueAnnotationDisplay(a)
+		}
+
 		violationData := partials.QueueViolationViewData{
 			InspectionID: inspectionID,
 			Violation: partials.QueueViolationDisplay{
@@ -1069,21 +1499,76 @@ func (h *InspectionHandler) renderQueuePartials(
 				AIDescription:  currentViolation.AIDescription,
 				Status:         currentViolation.Status,
 				Severity:       currentViolation.Severity,
+				AISeverity:     currentViolation.AISeverity,
 				Confidence:     currentViolation.Confidence,
 				InspectorNotes: currentViolation.InspectorNotes,
 				ThumbnailURL:   currentViolation.ThumbnailURL,
 				OriginalURL:    currentViolation.OriginalURL,
+				RegionURL:      currentViolation.RegionURL,
 				ImageID:        currentViolation.ImageID,
 				Regulations:    queueRegs,
+				Annotations:    queueAnnotations,
 			},
-			Position:   position,
-			TotalCount: len(violations),
-			HasPrev:    position > 0,
-			HasNext:    position < len(violations)-1,
+			Position:    queue.Position,
+			TotalCount:  len(queue.Violations),
+			HasPrev:     queue.Position > 0,
+			HasNext:     queue.Position < len(queue.Violations)-1,
+			FilterQuery: filterQuery,
+			Keys:        queueShortcutKeys(shortcuts),
 		}
 		if err := partials.QueueViolationView(violationData).Render(r.Context(), w); err != nil {
-			h.logger.Error("failed to render queue violation view", "error", err)
+			h.logger.ErrorContext(r.Context(), "failed to render queue violation view", "error", err)
 			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 		}
 	}
 }
+
+// reviewShortcuts returns the user's review queue keys, or the defaults when
+// no preference service is configured.
+func (h *InspectionHandler) reviewShortcuts(r *http.Request, userID uuid.UUID) domain.ReviewShortcuts {
+	if h.preferences == nil {
+		return domain.DefaultReviewShortcuts()
+	}
+	return h.preferences.ReviewShortcuts(r.Context(), userID)
+}
+
+// displayFormat returns the user's unit system and date locale, or the
+// defaults when no preference service is configured.
+func (h *InspectionHandler) displayFormat(r *http.Request, userID uuid.UUID) domain.DisplayFormat {
+	if h.preferences == nil {
+		return domain.DefaultDisplayFormat()
+	}
+	return h.preferences.DisplayFormat(r.Context(), userID)
+}
+
+// reviewShortcutDisplays lists the review keys in help overlay order.
+func reviewShortcutDisplays(shortcuts domain.ReviewShortcuts) []inspections.ReviewShortcutDisplay {
+	displays := make([]inspections.ReviewShortcutDisplay, len(domain.ReviewActions))
+	for i, action := range domain.ReviewActions {
+		displays[i] = inspections.ReviewShortcutDisplay{
+			Action: string(action),
+			Key:    shortcuts[action],
+			Label:  action.Label(),
+		}
+	}
+	return displays
+}
+
+// queueShortcutKeys returns the keys shown as hints on the queue buttons.
+func queueShortcutKeys(shortcuts domain.ReviewShortcuts) partials.QueueShortcutKeys {
+	return partials.QueueShortcutKeys{
+		Confirm: shortcuts[domain.ReviewActionConfirm],
+		Reject:  shortcuts[domain.ReviewActionReject],
+		Edit:    shortcuts[domain.ReviewActionEdit],
+		Undo:    shortcuts[domain.ReviewActionUndo],
+		Next:    shortcuts[domain.ReviewActionNext],
+		Prev:    shortcuts[domain.ReviewActionPrev],
+	}
+}
+
+// renderQueueUndo renders the out-of-band undo bar for the review queue.
+func (h *InspectionHandler) renderQueueUndo(w http.ResponseWriter, r *http.Request, data partials.QueueUndoData) {
+	if err := partials.QueueUndoOOB(data).Render(r.Context(), w); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to render queue undo", "error", err)
+	}
+}
@@ -0,0 +1,159 @@
+// Package handler contains HTTP handlers for the Lukaut application.
+//
+// This file implements the analysis progress stream: server-sent events that
+// tell the inspection page to refresh its analysis status as the background
+// worker reports progress, in place of polling.
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/auth"
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/jobevents"
+	"github.com/DukeRupert/lukaut/internal/worker"
+	"github.com/google/uuid"
+)
+
+// Event names the analysis status partial listens for: job state changes,
+// and progress as each image finishes.
+const (
+	analysisEventName = "analysis"
+	imageEventName    = "image"
+)
+
+// sseHeartbeatInterval is how often an idle stream sends a comment, so
+// proxies and load balancers don't close it.
+const sseHeartbeatInterval = 20 * time.Second
+
+// =============================================================================
+// GET /inspections/{id}/progress - Analysis Progress Stream
+// =============================================================================
+
+// Progress streams the inspection's analysis progress as server-sent events:
+// an "image" event as each image finishes, and an "analysis" event per job
+// state change with the state as data. The stream ends after the analysis
+// completes or fails, or right away with a "completed" event if no analysis
+// is running.
+//
+// Without a broker (the worker runs elsewhere) or a writer that can flush,
+// it answers 204 No Content, which tells EventSource not to reconnect; the
+// page falls back to polling.
+func (h *InspectionHandler) Progress(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUserFromRequest(r)
+	if user == nil {
+		h.logger.ErrorContext(r.Context(), "progress handler called without authenticated user")
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid inspection ID", http.StatusBadRequest)
+		return
+	}
+
+	if h.jobEvents == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	// Subscribe before reading the status so a job finishing in between
+	// isn't missed
+	events, unsubscribe := h.jobEvents.Subscribe(id)
+	defer unsubscribe()
+
+	status, err := h.inspectionService.GetAnalysisStatus(r.Context(), id, user.ID)
+	if err != nil {
+		if domain.ErrorCode(err) == domain.ENOTFOUND {
+			http.Error(w, "Inspection not found", http.StatusNotFound)
+		} else {
+			ServerErrorResponse(w, r, h.logger.With("inspection_id", id), fmt.Errorf("get analysis status: %w", err))
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // Stop nginx buffering the stream
+	rc := http.NewResponseController(w)
+	if err := rc.Flush(); errors.Is(err, http.ErrNotSupported) {
+		w.Header().Del("Content-Type")
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if !status.IsAnalyzing {
+		_ = writeSSE(w, analysisEventName, string(jobevents.StateCompleted))
+		_ = rc.Flush()
+		return
+	}
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e, ok := <-events:
+			if !ok {
+				// The server is shutting down; EventSource reconnects
+				return
+			}
+			if e.JobType != worker.JobTypeAnalyzeInspection {
+				continue
+			}
+			if err := writeAnalysisEvent(w, e); err != nil {
+				return
+			}
+			if err := rc.Flush(); err != nil || e.State.Done() {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := io.WriteString(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// imageProgressEvent is the data of an "image" event.
+type imageProgressEvent struct {
+	ImageID  uuid.UUID `json:"image_id"`
+	Failed   bool      `json:"failed"`
+	Finished int       `json:"finished"`
+	Total    int       `json:"total"`
+}
+
+// writeAnalysisEvent writes an analysis job event: an "image" event with
+// the image's progress for progress events that name an image, or an
+// "analysis" event with the job state.
+func writeAnalysisEvent(w io.Writer, e jobevents.Event) error {
+	if e.State != jobevents.StateProgress || e.ImageID == uuid.Nil {
+		return writeSSE(w, analysisEventName, string(e.State))
+	}
+	data, err := json.Marshal(imageProgressEvent{
+		ImageID:  e.ImageID,
+		Failed:   e.ImageFailed,
+		Finished: e.Finished,
+		Total:    e.Total,
+	})
+	if err != nil {
+		return err
+	}
+	return writeSSE(w, imageEventName, string(data))
+}
+
+// writeSSE writes a single-line server-sent event.
+func writeSSE(w io.Writer, event, data string) error {
+	_, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+	return err
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/auth"
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/jobevents"
+	"github.com/DukeRupert/lukaut/internal/worker"
+	"github.com/google/uuid"
+)
+
+// newProgressStreamRequest returns an EventSource request for the
+// inspection's analysis progress as userID.
+func newProgressStreamRequest(inspectionID, userID uuid.UUID) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/inspections/"+inspectionID.String()+"/progress", nil)
+	req.Header.Set("Accept", "text/event-stream")
+	req.SetPathValue("id", inspectionID.String())
+	return req.WithContext(auth.SetUser(req.Context(), &domain.User{ID: userID}))
+}
+
+func TestInspectionProgress_StreamsAnalysisUntilCompleted(t *testing.T) {
+	svc := &fakeEventsInspectionService{inspectionID: uuid.New(), userID: uuid.New(), analyzing: true}
+	broker := jobevents.NewBroker()
+	h := NewInspectionHandler(svc, nil, nil, nil, nil, nil, newTestLogger()).WithJobEvents(broker)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.SetPathValue("id", svc.inspectionID.String())
+		h.Progress(w, r.WithContext(auth.SetUser(r.Context(), &domain.User{ID: svc.userID})))
+	}))
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("expected an event stream, got %d %q", resp.StatusCode, ct)
+	}
+
+	// The handler subscribed before sending headers
+	imageID := uuid.New()
+	broker.Publish(jobevents.Event{InspectionID: svc.inspectionID, JobType: worker.JobTypeAnalyzeInspection, State: jobevents.StateRunning})
+	broker.Publish(jobevents.Event{
+		InspectionID: svc.inspectionID,
+		JobType:      worker.JobTypeAnalyzeInspection,
+		State:        jobevents.StateProgress,
+		ImageID:      imageID,
+		Finished:     1,
+		Total:        2,
+	})
+	broker.Publish(jobevents.Event{InspectionID: svc.inspectionID, JobType: worker.JobTypeGeocodeInspection, State: jobevents.StateCompleted})
+	broker.Publish(jobevents.Event{InspectionID: svc.inspectionID, JobType: worker.JobTypeAnalyzeInspection, State: jobevents.StateCompleted})
+
+	done := make(chan string)
+	go func() {
+		var body strings.Builder
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			body.WriteString(scanner.Text() + "\n")
+		}
+		done <- body.String()
+	}()
+
+	select {
+	case body := <-done:
+		want := "event: analysis\ndata: running\n\n" +
+			"event: image\ndata: {\"image_id\":\"" + imageID.String() + "\",\"failed\":false,\"finished\":1,\"total\":2}\n\n" +
+			"event: analysis\ndata: completed\n\n"
+		if body != want {
+			t.Errorf("expected running, the image's progress, then completed, ignoring other jobs, got %q", body)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the stream to end once analysis completed")
+	}
+}
+
+func TestInspectionProgress_StreamEndsWhenNotAnalyzing(t *testing.T) {
+	svc := &fakeEventsInspectionService{inspectionID: uuid.New(), userID: uuid.New()}
+	h := NewInspectionHandler(svc, nil, nil, nil, nil, nil, newTestLogger()).WithJobEvents(jobevents.NewBroker())
+
+	rr := httptest.NewRecorder()
+	h.Progress(rr, newProgressStreamRequest(svc.inspectionID, svc.userID))
+
+	if rr.Code != http.StatusOK || rr.Body.String() != "event: analysis\ndata: completed\n\n" {
+		t.Errorf("expected an immediate completed event, got %d %q", rr.Code, rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	h.Progress(rr, newProgressStreamRequest(svc.inspectionID, uuid.New()))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for another user's inspection, got %d", rr.Code)
+	}
+}
+
+func TestInspectionProgress_NoStreamWithoutBroker(t *testing.T) {
+	svc := &fakeEventsInspectionService{inspectionID: uuid.New(), userID: uuid.New(), analyzing: true}
+	h := NewInspectionHandler(svc, nil, nil, nil, nil, nil, newTestLogger())
+
+	rr := httptest.NewRecorder()
+	h.Progress(rr, newProgressStreamRequest(svc.inspectionID, svc.userID))
+
+	// 204 tells EventSource not to reconnect, so the page keeps polling
+	if rr.Code != http.StatusNoContent {
+		t.Errorf("expected 204, got %d", rr.Code)
+	}
+}
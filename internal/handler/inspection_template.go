@@ -0,0 +1,179 @@
+// Package handler contains HTTP handlers for the Lukaut application.
+//
+// This file implements inspection template handlers: saving an inspection
+// as a template, listing templates, and starting new inspections from them.
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/DukeRupert/lukaut/internal/auth"
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/templ/pages/inspections"
+	"github.com/google/uuid"
+)
+
+// TemplatesTempl lists the user's inspection templates.
+// GET /templates
+func (h *InspectionHandler) TemplatesTempl(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUserFromRequest(r)
+	if user == nil {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	templates, err := h.inspectionService.ListTemplates(r.Context(), user.ID)
+	if err != nil {
+		ServerErrorResponse(w, r, h.logger.With("user_id", user.ID), fmt.Errorf("list templates: %w", err))
+		return
+	}
+
+	items := make([]inspections.TemplateListItem, 0, len(templates))
+	for _, t := range templates {
+		inspection := domain.Inspection{
+			AddressLine1: t.AddressLine1,
+			AddressLine2: t.AddressLine2,
+			City:         t.City,
+			State:        t.State,
+			PostalCode:   t.PostalCode,
+		}
+		items = append(items, inspections.TemplateListItem{
+			ID:         t.ID.String(),
+			Name:       t.Name,
+			Title:      t.Title,
+			ClientName: t.ClientName,
+			Address:    inspection.FullAddress(),
+			UpdatedAt:  t.UpdatedAt.Format("Jan 2, 2006"),
+		})
+	}
+
+	data := inspections.TemplatesPageData{
+		CurrentPath: r.URL.Path,
+		User:        domainUserToInspectionDisplay(user),
+		Templates:   items,
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := inspections.TemplatesPage(data).Render(r.Context(), w); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to render templates page", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// CreateFromTemplate creates a draft inspection from a template and
+// redirects to it.
+// POST /templates/{id}/inspections
+func (h *InspectionHandler) CreateFromTemplate(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUserFromRequest(r)
+	if user == nil {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	templateID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid template ID", http.StatusBadRequest)
+		return
+	}
+
+	inspection, err := h.inspectionService.CreateFromTemplate(r.Context(), templateID, user.ID)
+	switch domain.ErrorCode(err) {
+	case "":
+	case domain.ENOTFOUND:
+		http.Error(w, "Template not found", http.StatusNotFound)
+		return
+	case domain.EINVALID:
+		// The template's fields no longer validate, e.g. its client was removed
+		http.Error(w, domain.ErrorMessage(err), http.StatusUnprocessableEntity)
+		return
+	default:
+		ServerErrorResponse(w, r, h.logger.With("template_id", templateID), fmt.Errorf("create from template: %w", err))
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/inspections/%s", inspection.ID), http.StatusSeeOther)
+}
+
+// SaveAsTemplate saves the inspection as a template under template_name,
+// or renames it, and renders the updated template section.
+// POST /inspections/{id}/template
+func (h *InspectionHandler) SaveAsTemplate(w http.ResponseWriter, r *http.Request) {
+	h.updateTemplate(w, r, true)
+}
+
+// RemoveTemplate stops using the inspection as a template and renders the
+// updated template section.
+// DELETE /inspections/{id}/template
+func (h *InspectionHandler) RemoveTemplate(w http.ResponseWriter, r *http.Request) {
+	h.updateTemplate(w, r, false)
+}
+
+// updateTemplate saves or removes the inspection's template and renders its
+// template section, with the error if the change was refused.
+func (h *InspectionHandler) updateTemplate(w http.ResponseWriter, r *http.Request, save bool) {
+	user := auth.GetUserFromRequest(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid inspection ID", http.StatusBadRequest)
+		return
+	}
+
+	var updateErr error
+	if save {
+		updateErr = h.inspectionService.SaveAsTemplate(r.Context(), domain.SaveInspectionTemplateParams{
+			ID:     id,
+			UserID: user.ID,
+			Name:   r.FormValue("template_name"),
+		})
+	} else {
+		updateErr = h.inspectionService.RemoveTemplate(r.Context(), id, user.ID)
+	}
+	switch domain.ErrorCode(updateErr) {
+	case "", domain.EINVALID:
+	case domain.ENOTFOUND:
+		http.Error(w, "Inspection not found", http.StatusNotFound)
+		return
+	default:
+		ServerErrorResponse(w, r, h.logger.With("inspection_id", id), fmt.Errorf("update template: %w", updateErr))
+		return
+	}
+
+	inspection, err := h.inspectionService.GetByID(r.Context(), id, user.ID)
+	if err != nil {
+		ServerErrorResponse(w, r, h.logger.With("inspection_id", id), fmt.Errorf("get inspection: %w", err))
+		return
+	}
+
+	data := templateField(user.ID, inspection)
+	if updateErr != nil {
+		data.Error = domain.ErrorMessage(updateErr)
+		data.Name = r.FormValue("template_name")
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := inspections.TemplateSection(data).Render(r.Context(), w); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to render template section", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// templateField builds the inspection's template section. Only the owner
+// can save it as a template; the title is suggested as the name.
+func templateField(userID uuid.UUID, inspection *domain.Inspection) inspections.TemplateFieldData {
+	data := inspections.TemplateFieldData{
+		InspectionID: inspection.ID.String(),
+		CanEdit:      inspection.UserID == userID,
+		IsTemplate:   inspection.IsTemplate,
+		Name:         inspection.TemplateName,
+	}
+	if !inspection.IsTemplate {
+		data.Name = inspection.Title
+	}
+	return data
+}
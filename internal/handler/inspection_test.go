@@ -0,0 +1,1138 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/auth"
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/service"
+	"github.com/google/uuid"
+)
+
+// fakeEditInspectionService stores one inspection and applies updates only
+// at the current version, like the repository query.
+type fakeEditInspectionService struct {
+	service.InspectionService
+	inspection domain.Inspection
+}
+
+func (f *fakeEditInspectionService) GetByID(ctx context.Context, id, userID uuid.UUID) (*domain.Inspection, error) {
+	if id != f.inspection.ID || userID != f.inspection.UserID {
+		return nil, domain.NotFound("InspectionService.GetByID", "inspection", id.String())
+	}
+	i := f.inspection
+	return &i, nil
+}
+
+func (f *fakeEditInspectionService) Update(ctx context.Context, params domain.UpdateInspectionParams) error {
+	if params.Version != f.inspection.Version {
+		return domain.Conflict("InspectionService.Update", service.ErrMsgInspectionEditConflict)
+	}
+	f.inspection.Title = params.Title
+	f.inspection.InspectorNotes = params.InspectorNotes
+	f.inspection.Version++
+	return nil
+}
+
+// fakeNoClientsService has no clients.
+type fakeNoClientsService struct {
+	service.ClientService
+}
+
+func (fakeNoClientsService) ListAll(ctx context.Context, userID uuid.UUID) ([]domain.Client, error) {
+	return nil, nil
+}
+
+func newEditTestHandler(svc *fakeEditInspectionService) *InspectionHandler {
+	return NewInspectionHandler(svc, nil, nil, fakeNoClientsService{}, nil, nil, newTestLogger())
+}
+
+func newEditRequest(svc *fakeEditInspectionService, user *domain.User, title, notes, version string) *http.Request {
+	form := url.Values{
+		"title":           {title},
+		"address_line1":   {"100 Main St"},
+		"city":            {"Boise"},
+		"state":           {"ID"},
+		"postal_code":     {"83702"},
+		"inspection_date": {"2026-03-14"},
+		"inspector_notes": {notes},
+		"version":         {version},
+	}
+	id := svc.inspection.ID.String()
+	req := httptest.NewRequest(http.MethodPut, "/inspections/"+id, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("HX-Request", "true")
+	req.SetPathValue("id", id)
+	return req.WithContext(auth.SetUser(req.Context(), user))
+}
+
+func TestInspectionUpdate_SavesAtCurrentVersion(t *testing.T) {
+	user := &domain.User{ID: uuid.New()}
+	svc := &fakeEditInspectionService{inspection: domain.Inspection{ID: uuid.New(), UserID: user.ID, Title: "Site walk", Version: 4}}
+	h := newEditTestHandler(svc)
+
+	rr := httptest.NewRecorder()
+	h.Update(rr, newEditRequest(svc, user, "Renamed walk", "", "4"))
+
+	if rr.Header().Get("HX-Redirect") != "/inspections/"+svc.inspection.ID.String() {
+		t.Fatalf("expected redirect after save, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if svc.inspection.Title != "Renamed walk" || svc.inspection.Version != 5 {
+		t.Errorf("expected save at version 5, got %q at %d", svc.inspection.Title, svc.inspection.Version)
+	}
+}
+
+func TestInspectionUpdate_ConflictShowsLatestAndAttemptedValues(t *testing.T) {
+	user := &domain.User{ID: uuid.New()}
+	svc := &fakeEditInspectionService{inspection: domain.Inspection{
+		ID:             uuid.New(),
+		UserID:         user.ID,
+		Title:          "Phone title",
+		InspectorNotes: "Saved from phone",
+		InspectionDate: time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC),
+		AddressLine1:   "100 Main St",
+		City:           "Boise",
+		State:          "ID",
+		PostalCode:     "83702",
+		Version:        3,
+	}}
+	h := newEditTestHandler(svc)
+
+	// The laptop form was loaded at version 2
+	rr := httptest.NewRecorder()
+	h.Update(rr, newEditRequest(svc, user, "Laptop title", "Typed on laptop", "2"))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected form re-render, got %d", rr.Code)
+	}
+	if rr.Header().Get("HX-Redirect") != "" {
+		t.Fatal("expected no redirect on conflict")
+	}
+	if rr.Header().Get("HX-Retarget") != "body" {
+		t.Error("expected htmx request to be retargeted so the conflict is shown")
+	}
+	if svc.inspection.Title != "Phone title" {
+		t.Errorf("expected stale save to be rejected, got title %q", svc.inspection.Title)
+	}
+
+	body := rr.Body.String()
+	for _, want := range []string{
+		"changed by someone else",  // flash
+		`value="Laptop title"`,     // attempted value kept in the field
+		"Typed on laptop",          // attempted notes kept
+		"Latest saved value",       // latest shown alongside
+		"Phone title",              // latest title
+		"Saved from phone",         // latest notes
+		`name="version" value="3"`, // resubmitting applies at the latest version
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected conflict page to contain %q", want)
+		}
+	}
+	// Unchanged fields have no saved-value hint
+	if n := strings.Count(body, "Latest saved value"); n != 2 {
+		t.Errorf("expected hints for the 2 conflicting fields, got %d", n)
+	}
+}
+
+// editFormVersion loads the edit form and returns the version token it embeds.
+func editFormVersion(t *testing.T, h *InspectionHandler, id uuid.UUID, user *domain.User) string {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/inspections/"+id.String()+"/edit", nil)
+	req.SetPathValue("id", id.String())
+	req = req.WithContext(auth.SetUser(req.Context(), user))
+	rr := httptest.NewRecorder()
+	h.EditTempl(rr, req)
+
+	m := regexp.MustCompile(`name="version" value="(\d+)"`).FindStringSubmatch(rr.Body.String())
+	if m == nil {
+		t.Fatalf("expected the edit form to embed a version token, got %d: %s", rr.Code, rr.Body.String())
+	}
+	return m[1]
+}
+
+func TestInspectionEdit_TwoTabsSecondSaveConflicts(t *testing.T) {
+	user := &domain.User{ID: uuid.New()}
+	svc := &fakeEditInspectionService{inspection: domain.Inspection{ID: uuid.New(), UserID: user.ID, Title: "Site walk", Version: 7}}
+	h := newEditTestHandler(svc)
+
+	// Both tabs open the edit form before either saves
+	tabA := editFormVersion(t, h, svc.inspection.ID, user)
+	tabB := editFormVersion(t, h, svc.inspection.ID, user)
+	if tabA != "7" || tabB != "7" {
+		t.Fatalf("expected both forms at version 7, got %s and %s", tabA, tabB)
+	}
+
+	rr := httptest.NewRecorder()
+	h.Update(rr, newEditRequest(svc, user, "Saved in tab A", "", tabA))
+	if rr.Header().Get("HX-Redirect") == "" {
+		t.Fatalf("expected the first save to succeed, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	h.Update(rr, newEditRequest(svc, user, "Saved in tab B", "", tabB))
+	if rr.Header().Get("HX-Redirect") != "" {
+		t.Fatal("expected the second tab's save to be rejected")
+	}
+	if !strings.Contains(rr.Body.String(), "changed by someone else") {
+		t.Error("expected the second tab to be told the inspection changed elsewhere")
+	}
+	if svc.inspection.Title != "Saved in tab A" {
+		t.Errorf("expected tab A's save to be kept, got %q", svc.inspection.Title)
+	}
+}
+
+// fakeDraftInspectionService keeps one user's draft and records autosaves.
+type fakeDraftInspectionService struct {
+	service.InspectionService
+	draft   *domain.InspectionDraft
+	saved   []domain.SaveInspectionDraftParams
+	created int
+}
+
+func (f *fakeDraftInspectionService) SaveDraft(ctx context.Context, params domain.SaveInspectionDraftParams) error {
+	f.saved = append(f.saved, params)
+	return nil
+}
+
+func (f *fakeDraftInspectionService) GetDraft(ctx context.Context, userID uuid.UUID) (*domain.InspectionDraft, error) {
+	if f.draft == nil || f.draft.UserID != userID {
+		return nil, domain.NotFound("InspectionService.GetDraft", "inspection draft", userID.String())
+	}
+	return f.draft, nil
+}
+
+func (f *fakeDraftInspectionService) DeleteDraft(ctx context.Context, userID uuid.UUID) error {
+	f.draft = nil
+	return nil
+}
+
+func (f *fakeDraftInspectionService) Create(ctx context.Context, params domain.CreateInspectionParams) (*domain.Inspection, error) {
+	f.created++
+	return &domain.Inspection{ID: uuid.New(), UserID: params.UserID, Title: params.Title}, nil
+}
+
+func TestInspectionSaveDraft_OnlySentFields(t *testing.T) {
+	user := &domain.User{ID: uuid.New()}
+	svc := &fakeDraftInspectionService{}
+	h := NewInspectionHandler(svc, nil, nil, fakeNoClientsService{}, nil, nil, newTestLogger())
+
+	req := httptest.NewRequest(http.MethodPatch, "/inspections/drafts", strings.NewReader("title=Roof+check&city="))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req = req.WithContext(auth.SetUser(req.Context(), user))
+	rr := httptest.NewRecorder()
+	h.SaveDraft(rr, req)
+
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", rr.Code)
+	}
+	if len(svc.saved) != 1 {
+		t.Fatalf("expected one autosave, got %d", len(svc.saved))
+	}
+	got := svc.saved[0]
+	if got.UserID != user.ID {
+		t.Errorf("expected draft for the current user")
+	}
+	if got.Title == nil || *got.Title != "Roof check" {
+		t.Errorf("expected title sent, got %v", got.Title)
+	}
+	if got.City == nil || *got.City != "" {
+		t.Errorf("expected empty city sent to clear it, got %v", got.City)
+	}
+	if got.State != nil || got.InspectorNotes != nil || got.InspectionDate != nil {
+		t.Error("expected unsent fields to be nil so the saved values are kept")
+	}
+}
+
+func TestInspectionNew_RestoresDraft(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Email: "pat@example.com"}
+	svc := &fakeDraftInspectionService{draft: &domain.InspectionDraft{
+		UserID:         user.ID,
+		Title:          "Roof check",
+		InspectorNotes: "Ladder missing feet",
+		UpdatedAt:      time.Now(),
+	}}
+	h := NewInspectionHandler(svc, nil, nil, fakeNoClientsService{}, nil, nil, newTestLogger())
+
+	req := httptest.NewRequest(http.MethodGet, "/inspections/new", nil)
+	req = req.WithContext(auth.SetUser(req.Context(), user))
+	rr := httptest.NewRecorder()
+	h.NewTempl(rr, req)
+
+	body := rr.Body.String()
+	for _, want := range []string{
+		`value="Roof check"`,
+		"Ladder missing feet",
+		`value="` + time.Now().Format("2006-01-02") + `"`, // empty draft date keeps the default
+		"Restored your unsaved draft",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected new form to contain %q", want)
+		}
+	}
+}
+
+func TestInspectionCreate_DeletesDraft(t *testing.T) {
+	user := &domain.User{ID: uuid.New()}
+	svc := &fakeDraftInspectionService{draft: &domain.InspectionDraft{UserID: user.ID, Title: "Roof check"}}
+	h := NewInspectionHandler(svc, nil, nil, fakeNoClientsService{}, nil, nil, newTestLogger())
+
+	create := func(date string) {
+		form := url.Values{
+			"title":           {"Roof check"},
+			"address_line1":   {"100 Main St"},
+			"city":            {"Boise"},
+			"state":           {"ID"},
+			"postal_code":     {"83702"},
+			"inspection_date": {date},
+		}
+		req := httptest.NewRequest(http.MethodPost, "/inspections", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req = req.WithContext(auth.SetUser(req.Context(), user))
+		h.Create(httptest.NewRecorder(), req)
+	}
+
+	// A failed submission keeps the draft
+	create("not-a-date")
+	if svc.draft == nil {
+		t.Fatal("expected draft kept after a failed create")
+	}
+
+	create("2026-03-14")
+	if svc.created != 1 {
+		t.Fatalf("expected inspection created, got %d creates", svc.created)
+	}
+	if svc.draft != nil {
+		t.Error("expected draft deleted after create")
+	}
+}
+
+func TestInspectionAllowedStatuses_RendersOnlyValidButtons(t *testing.T) {
+	tests := []struct {
+		status domain.InspectionStatus
+		want   []string
+	}{
+		{domain.InspectionStatusDraft, nil},                   // Analyzing is entered by starting analysis
+		{domain.InspectionStatusAnalyzing, []string{"draft"}}, // Review is entered when analysis finishes
+		{domain.InspectionStatusReview, []string{"draft", "completed"}},
+		{domain.InspectionStatusCompleted, []string{"draft", "review"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			user := &domain.User{ID: uuid.New()}
+			svc := &fakeEditInspectionService{inspection: domain.Inspection{ID: uuid.New(), UserID: user.ID, Status: tt.status}}
+			h := newEditTestHandler(svc)
+
+			id := svc.inspection.ID.String()
+			req := httptest.NewRequest(http.MethodGet, "/inspections/"+id+"/allowed-statuses", nil)
+			req.SetPathValue("id", id)
+			req = req.WithContext(auth.SetUser(req.Context(), user))
+			rr := httptest.NewRecorder()
+			h.AllowedStatuses(rr, req)
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d", rr.Code)
+			}
+			var got []string
+			for _, status := range []string{"draft", "analyzing", "review", "completed"} {
+				if strings.Contains(rr.Body.String(), `data-status="`+status+`"`) {
+					got = append(got, status)
+				}
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("expected buttons for %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestInspectionAllowedStatuses_NotFound(t *testing.T) {
+	user := &domain.User{ID: uuid.New()}
+	h := newEditTestHandler(&fakeEditInspectionService{inspection: domain.Inspection{ID: uuid.New(), UserID: uuid.New()}})
+
+	id := uuid.New().String()
+	req := httptest.NewRequest(http.MethodGet, "/inspections/"+id+"/allowed-statuses", nil)
+	req.SetPathValue("id", id)
+	req = req.WithContext(auth.SetUser(req.Context(), user))
+	rr := httptest.NewRecorder()
+	h.AllowedStatuses(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", rr.Code)
+	}
+}
+
+// fakeEventsInspectionService serves a fixed activity feed for one inspection.
+type fakeEventsInspectionService struct {
+	service.InspectionService
+	inspectionID uuid.UUID
+	userID       uuid.UUID
+	events       []domain.InspectionEvent
+	analyzing    bool
+	gotAfter     int64
+}
+
+func (f *fakeEventsInspectionService) Events(ctx context.Context, inspectionID, userID uuid.UUID, after int64) ([]domain.InspectionEvent, error) {
+	if inspectionID != f.inspectionID || userID != f.userID {
+		return nil, domain.NotFound("InspectionService.Events", "inspection", inspectionID.String())
+	}
+	f.gotAfter = after
+	var events []domain.InspectionEvent
+	for _, e := range f.events {
+		if e.ID > after {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+func (f *fakeEventsInspectionService) GetAnalysisStatus(ctx context.Context, inspectionID, userID uuid.UUID) (*domain.AnalysisStatus, error) {
+	if inspectionID != f.inspectionID || userID != f.userID {
+		return nil, domain.NotFound("InspectionService.GetAnalysisStatus", "inspection", inspectionID.String())
+	}
+	return &domain.AnalysisStatus{IsAnalyzing: f.analyzing}, nil
+}
+
+func serveEvents(svc *fakeEventsInspectionService, userID uuid.UUID, query string) *httptest.ResponseRecorder {
+	h := NewInspectionHandler(svc, nil, nil, nil, nil, nil, newTestLogger())
+	id := svc.inspectionID.String()
+	req := httptest.NewRequest(http.MethodGet, "/inspections/"+id+"/events"+query, nil)
+	req.SetPathValue("id", id)
+	req = req.WithContext(auth.SetUser(req.Context(), &domain.User{ID: userID}))
+	rr := httptest.NewRecorder()
+	h.Events(rr, req)
+	return rr
+}
+
+func TestInspectionEvents_PollsFromLastEventWhileAnalyzing(t *testing.T) {
+	svc := &fakeEventsInspectionService{
+		inspectionID: uuid.New(),
+		userID:       uuid.New(),
+		analyzing:    true,
+		events: []domain.InspectionEvent{
+			{ID: 7, Kind: domain.InspectionEventAnalysisStarted, Data: map[string]string{"images": "3"}, CreatedAt: time.Now()},
+			{ID: 9, Kind: domain.InspectionEventImageAnalyzed, Data: map[string]string{"filename": "roof.jpg", "violations": "2"}, CreatedAt: time.Now()},
+		},
+	}
+
+	rr := serveEvents(svc, svc.userID, "?after=7")
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if svc.gotAfter != 7 {
+		t.Errorf("expected cursor 7 passed to the service, got %d", svc.gotAfter)
+	}
+	body := rr.Body.String()
+	if strings.Contains(body, "Analysis started") {
+		t.Error("expected events at or before the cursor to be omitted")
+	}
+	if !strings.Contains(body, "Analyzed photo roof.jpg: 2 potential violation(s)") {
+		t.Errorf("expected the new event, got %s", body)
+	}
+	if !strings.Contains(body, "/inspections/"+svc.inspectionID.String()+"/events?after=9") {
+		t.Error("expected the poller to continue from the last event")
+	}
+}
+
+func TestInspectionEvents_StopsPollingWhenAnalysisEnds(t *testing.T) {
+	svc := &fakeEventsInspectionService{inspectionID: uuid.New(), userID: uuid.New()}
+
+	rr := serveEvents(svc, svc.userID, "?after=12")
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if strings.Contains(rr.Body.String(), "hx-get") {
+		t.Error("expected no poller once the inspection is no longer analyzing")
+	}
+}
+
+func TestInspectionEvents_RejectsOtherUsersAndBadCursors(t *testing.T) {
+	svc := &fakeEventsInspectionService{inspectionID: uuid.New(), userID: uuid.New(), analyzing: true}
+
+	if rr := serveEvents(svc, uuid.New(), ""); rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for another user's inspection, got %d", rr.Code)
+	}
+	if rr := serveEvents(svc, svc.userID, "?after=abc"); rr.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a malformed cursor, got %d", rr.Code)
+	}
+}
+
+// fakeTriggerInspectionService reports a fixed analysis status and counts
+// the analyses it is asked to start.
+type fakeTriggerInspectionService struct {
+	service.InspectionService
+	status    domain.AnalysisStatus
+	triggered int
+}
+
+func (f *fakeTriggerInspectionService) GetAnalysisStatus(ctx context.Context, inspectionID, userID uuid.UUID) (*domain.AnalysisStatus, error) {
+	status := f.status
+	status.InspectionID = inspectionID
+	return &status, nil
+}
+
+func (f *fakeTriggerInspectionService) TriggerAnalysis(ctx context.Context, inspectionID, userID uuid.UUID) error {
+	f.triggered++
+	return nil
+}
+
+func serveTriggerAnalysis(svc *fakeTriggerInspectionService) *httptest.ResponseRecorder {
+	h := NewInspectionHandler(svc, nil, nil, nil, nil, nil, newTestLogger())
+	id := uuid.NewString()
+	req := httptest.NewRequest(http.MethodPost, "/inspections/"+id+"/analyze", nil)
+	req.SetPathValue("id", id)
+	req = req.WithContext(auth.SetUser(req.Context(), &domain.User{ID: uuid.New()}))
+	rr := httptest.NewRecorder()
+	h.TriggerAnalysis(rr, req)
+	return rr
+}
+
+func TestTriggerAnalysis_StartsAnalysis(t *testing.T) {
+	svc := &fakeTriggerInspectionService{status: domain.AnalysisStatus{CanAnalyze: true, HasImages: true, PendingImages: 2}}
+
+	rr := serveTriggerAnalysis(svc)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if svc.triggered != 1 {
+		t.Errorf("expected one analysis started, got %d", svc.triggered)
+	}
+}
+
+func TestTriggerAnalysis_AlreadyQueuedIsSuccess(t *testing.T) {
+	svc := &fakeTriggerInspectionService{status: domain.AnalysisStatus{
+		IsAnalyzing:    true,
+		PollingEnabled: true,
+		HasImages:      true,
+		Message:        "Analysis in progress...",
+	}}
+
+	rr := serveTriggerAnalysis(svc)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected a repeated request to succeed, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if svc.triggered != 0 {
+		t.Errorf("expected no second analysis, got %d", svc.triggered)
+	}
+	if body := rr.Body.String(); !strings.Contains(body, `id="analysis-status"`) || !strings.Contains(body, "Analyzing...") {
+		t.Errorf("expected the running analysis status partial, got %s", body)
+	}
+}
+
+func TestTriggerAnalysis_NothingToAnalyze(t *testing.T) {
+	svc := &fakeTriggerInspectionService{status: domain.AnalysisStatus{Message: "Upload photos to analyze"}}
+
+	if rr := serveTriggerAnalysis(svc); rr.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rr.Code)
+	}
+}
+
+// fakeListInspectionService returns one page of a longer inspection list and
+// records the list parameters.
+type fakeListInspectionService struct {
+	service.InspectionService
+	got domain.ListInspectionsParams
+}
+
+func (f *fakeListInspectionService) List(ctx context.Context, params domain.ListInspectionsParams) (*domain.ListInspectionsResult, error) {
+	f.got = params
+	return &domain.ListInspectionsResult{
+		Inspections: []domain.Inspection{{ID: uuid.New(), Title: "Roof check", ClientName: "Acme", InspectionDate: time.Now()}},
+		Total:       45,
+		Limit:       params.Limit,
+		Offset:      params.Offset,
+	}, nil
+}
+
+func serveInspectionList(svc *fakeListInspectionService, query string) *httptest.ResponseRecorder {
+	h := NewInspectionHandler(svc, nil, nil, nil, nil, nil, newTestLogger())
+	req := httptest.NewRequest(http.MethodGet, "/inspections"+query, nil)
+	req.Header.Set("HX-Request", "true")
+	req = req.WithContext(auth.SetUser(req.Context(), &domain.User{ID: uuid.New()}))
+	rr := httptest.NewRecorder()
+	h.IndexTempl(rr, req)
+	return rr
+}
+
+func serveStatus(svc *fakeEventsInspectionService, ifNoneMatch string) *httptest.ResponseRecorder {
+	h := NewInspectionHandler(svc, nil, nil, nil, nil, nil, newTestLogger())
+	id := svc.inspectionID.String()
+	req := httptest.NewRequest(http.MethodGet, "/inspections/"+id+"/status", nil)
+	req.SetPathValue("id", id)
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	req = req.WithContext(auth.SetUser(req.Context(), &domain.User{ID: svc.userID}))
+	rr := httptest.NewRecorder()
+	h.GetStatus(rr, req)
+	return rr
+}
+
+func TestInspectionGetStatus_SendsETag(t *testing.T) {
+	svc := &fakeEventsInspectionService{inspectionID: uuid.New(), userID: uuid.New(), analyzing: true}
+
+	rr := serveStatus(svc, "")
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	etag := rr.Header().Get("ETag")
+	if !strings.HasPrefix(etag, `W/"`) {
+		t.Errorf("expected a weak ETag, got %q", etag)
+	}
+	if got := rr.Header().Get("Cache-Control"); got != "private, no-cache" {
+		t.Errorf("expected Cache-Control private, no-cache, got %q", got)
+	}
+	if rr.Body.Len() == 0 {
+		t.Error("expected the status partial")
+	}
+}
+
+func TestInspectionGetStatus_NotModifiedUntilStatusChanges(t *testing.T) {
+	svc := &fakeEventsInspectionService{inspectionID: uuid.New(), userID: uuid.New(), analyzing: true}
+	etag := serveStatus(svc, "").Header().Get("ETag")
+
+	rr := serveStatus(svc, etag)
+	if rr.Code != http.StatusNotModified {
+		t.Fatalf("expected 304 for an unchanged status, got %d", rr.Code)
+	}
+	if rr.Body.Len() != 0 {
+		t.Errorf("expected an empty body, got %q", rr.Body.String())
+	}
+
+	svc.analyzing = false
+	rr = serveStatus(svc, etag)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 once the status changes, got %d", rr.Code)
+	}
+	if rr.Header().Get("ETag") == etag {
+		t.Error("expected a new ETag once the status changes")
+	}
+}
+
+func TestInspectionIndex_SortsAndKeepsSortWhenPaging(t *testing.T) {
+	svc := &fakeListInspectionService{}
+
+	rr := serveInspectionList(svc, "?sort=violation_count&dir=asc")
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if svc.got.Sort != domain.InspectionSortViolationCount || svc.got.Descending {
+		t.Errorf("expected ascending violation count sort, got %q descending=%v", svc.got.Sort, svc.got.Descending)
+	}
+	body := rr.Body.String()
+	for _, want := range []string{
+		`href="/inspections?dir=asc&amp;sort=violation_count&amp;page=2"`,
+		`aria-sort="ascending"`,
+		`href="/inspections?dir=desc&amp;sort=violation_count"`,
+		`href="/inspections?dir=asc&amp;sort=client_name"`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected table to contain %s", want)
+		}
+	}
+}
+
+func TestInspectionIndex_InvalidSortFallsBackToNewestFirst(t *testing.T) {
+	svc := &fakeListInspectionService{}
+
+	rr := serveInspectionList(svc, "?sort=id;DROP+TABLE+inspections&dir=sideways")
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if svc.got.Sort != domain.InspectionSortCreatedAt || !svc.got.Descending {
+		t.Errorf("expected newest first, got %q descending=%v", svc.got.Sort, svc.got.Descending)
+	}
+	if !strings.Contains(rr.Body.String(), `href="/inspections?page=2"`) {
+		t.Error("expected the default order to be left out of page links")
+	}
+}
+
+// fakeAssignInspectionService assigns one inspection, accepting only
+// assignees in members.
+type fakeAssignInspectionService struct {
+	service.InspectionService
+	inspection domain.Inspection
+	members    map[uuid.UUID]string
+}
+
+func (f *fakeAssignInspectionService) GetByID(ctx context.Context, id, userID uuid.UUID) (*domain.Inspection, error) {
+	i := f.inspection
+	return &i, nil
+}
+
+func (f *fakeAssignInspectionService) Assign(ctx context.Context, params domain.AssignInspectionParams) error {
+	if params.AssigneeID == nil {
+		f.inspection.AssignedTo, f.inspection.AssigneeName = nil, ""
+		return nil
+	}
+	name, ok := f.members[*params.AssigneeID]
+	if !ok {
+		return domain.Invalid("inspection.assign", "Inspections can only be assigned to members of your organization.")
+	}
+	f.inspection.AssignedTo, f.inspection.AssigneeName = params.AssigneeID, name
+	return nil
+}
+
+func serveAssign(svc *fakeAssignInspectionService, userID uuid.UUID, assigneeID string) *httptest.ResponseRecorder {
+	orgs := &mockOrganizationService{
+		MembershipFunc: func(ctx context.Context, userID uuid.UUID) (*domain.Membership, error) {
+			return &domain.Membership{OrganizationName: "Acme Safety", Role: domain.OrganizationRoleMember}, nil
+		},
+		ListMembersFunc: func(ctx context.Context, userID uuid.UUID) ([]domain.OrganizationMember, error) {
+			var members []domain.OrganizationMember
+			for id, name := range svc.members {
+				members = append(members, domain.OrganizationMember{UserID: id, Name: name})
+			}
+			return members, nil
+		},
+	}
+	h := NewInspectionHandler(svc, nil, nil, nil, nil, nil, newTestLogger()).WithOrganizations(orgs)
+
+	id := svc.inspection.ID.String()
+	form := url.Values{"assignee_id": {assigneeID}}
+	req := httptest.NewRequest(http.MethodPost, "/inspections/"+id+"/assignee", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetPathValue("id", id)
+	req = req.WithContext(auth.SetUser(req.Context(), &domain.User{ID: userID}))
+	rr := httptest.NewRecorder()
+	h.Assign(rr, req)
+	return rr
+}
+
+func TestInspectionAssign_RendersSelectedTeammate(t *testing.T) {
+	teammate := uuid.New()
+	svc := &fakeAssignInspectionService{
+		inspection: domain.Inspection{ID: uuid.New(), UserID: uuid.New()},
+		members:    map[uuid.UUID]string{teammate: "Tess Teammate"},
+	}
+
+	rr := serveAssign(svc, svc.inspection.UserID, teammate.String())
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if svc.inspection.AssignedTo == nil || *svc.inspection.AssignedTo != teammate {
+		t.Fatalf("expected the inspection assigned to the teammate, got %v", svc.inspection.AssignedTo)
+	}
+	body := rr.Body.String()
+	if !strings.Contains(body, `<option value="`+teammate.String()+`" selected>Tess Teammate</option>`) {
+		t.Errorf("expected the teammate selected, got %s", body)
+	}
+}
+
+func TestInspectionAssign_NonMemberShowsError(t *testing.T) {
+	svc := &fakeAssignInspectionService{
+		inspection: domain.Inspection{ID: uuid.New(), UserID: uuid.New()},
+		members:    map[uuid.UUID]string{uuid.New(): "Tess Teammate"},
+	}
+
+	rr := serveAssign(svc, svc.inspection.UserID, uuid.New().String())
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if svc.inspection.AssignedTo != nil {
+		t.Error("expected the inspection to stay unassigned")
+	}
+	if !strings.Contains(rr.Body.String(), "only be assigned to members of your organization") {
+		t.Errorf("expected the assignment error, got %s", rr.Body.String())
+	}
+}
+
+func TestInspectionAssign_RejectsMalformedAssignee(t *testing.T) {
+	svc := &fakeAssignInspectionService{inspection: domain.Inspection{ID: uuid.New(), UserID: uuid.New()}}
+
+	rr := serveAssign(svc, svc.inspection.UserID, "not-a-uuid")
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rr.Code)
+	}
+}
+
+// fakeTemplateInspectionService keeps one inspection and creates
+// inspections from it once it is a template.
+type fakeTemplateInspectionService struct {
+	service.InspectionService
+	inspection domain.Inspection
+	created    *domain.Inspection
+}
+
+func (f *fakeTemplateInspectionService) GetByID(ctx context.Context, id, userID uuid.UUID) (*domain.Inspection, error) {
+	i := f.inspection
+	return &i, nil
+}
+
+func (f *fakeTemplateInspectionService) SaveAsTemplate(ctx context.Context, params domain.SaveInspectionTemplateParams) error {
+	if strings.TrimSpace(params.Name) == "" {
+		return domain.Invalid("inspection.save_as_template", "template name is required")
+	}
+	f.inspection.IsTemplate, f.inspection.TemplateName = true, params.Name
+	return nil
+}
+
+func (f *fakeTemplateInspectionService) CreateFromTemplate(ctx context.Context, templateID, userID uuid.UUID) (*domain.Inspection, error) {
+	if templateID != f.inspection.ID || !f.inspection.IsTemplate {
+		return nil, domain.NotFound("inspection.create_from_template", "template", templateID.String())
+	}
+	f.created = &domain.Inspection{ID: uuid.New(), UserID: userID, Title: f.inspection.Title}
+	return f.created, nil
+}
+
+func serveSaveAsTemplate(svc *fakeTemplateInspectionService, name string) *httptest.ResponseRecorder {
+	h := NewInspectionHandler(svc, nil, nil, nil, nil, nil, newTestLogger())
+
+	id := svc.inspection.ID.String()
+	form := url.Values{"template_name": {name}}
+	req := httptest.NewRequest(http.MethodPost, "/inspections/"+id+"/template", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetPathValue("id", id)
+	req = req.WithContext(auth.SetUser(req.Context(), &domain.User{ID: svc.inspection.UserID}))
+	rr := httptest.NewRecorder()
+	h.SaveAsTemplate(rr, req)
+	return rr
+}
+
+func TestInspectionSaveAsTemplate_RendersSavedTemplate(t *testing.T) {
+	svc := &fakeTemplateInspectionService{inspection: domain.Inspection{ID: uuid.New(), UserID: uuid.New(), Title: "Site walk"}}
+
+	rr := serveSaveAsTemplate(svc, "Weekly walk")
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	body := rr.Body.String()
+	if !strings.Contains(body, "saved as the template") || !strings.Contains(body, "Weekly walk") {
+		t.Errorf("expected the saved template name, got %s", body)
+	}
+}
+
+func TestInspectionSaveAsTemplate_EmptyNameShowsError(t *testing.T) {
+	svc := &fakeTemplateInspectionService{inspection: domain.Inspection{ID: uuid.New(), UserID: uuid.New(), Title: "Site walk"}}
+
+	rr := serveSaveAsTemplate(svc, " ")
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if svc.inspection.IsTemplate {
+		t.Error("expected the inspection not to become a template")
+	}
+	if !strings.Contains(rr.Body.String(), "template name is required") {
+		t.Errorf("expected the validation error, got %s", rr.Body.String())
+	}
+}
+
+func TestInspectionCreateFromTemplate_RedirectsToNewInspection(t *testing.T) {
+	svc := &fakeTemplateInspectionService{inspection: domain.Inspection{ID: uuid.New(), UserID: uuid.New(), IsTemplate: true}}
+	h := NewInspectionHandler(svc, nil, nil, nil, nil, nil, newTestLogger())
+	mux := http.NewServeMux()
+	h.RegisterTemplRoutes(mux, func(next http.Handler) http.Handler { return next })
+
+	serve := func(templateID uuid.UUID) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/templates/"+templateID.String()+"/inspections", nil)
+		req = req.WithContext(auth.SetUser(req.Context(), &domain.User{ID: svc.inspection.UserID}))
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := serve(svc.inspection.ID)
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("expected 303, got %d", rr.Code)
+	}
+	if svc.created == nil || rr.Header().Get("Location") != "/inspections/"+svc.created.ID.String() {
+		t.Errorf("expected a redirect to the new inspection, got %q", rr.Header().Get("Location"))
+	}
+
+	if rr := serve(uuid.New()); rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown template, got %d", rr.Code)
+	}
+}
+
+// fakeCompletionInspectionService refuses to complete an inspection with
+// blockers unless the params confirm forcing it.
+type fakeCompletionInspectionService struct {
+	service.InspectionService
+	blockers domain.CompletionBlockers
+	params   *domain.UpdateInspectionStatusParams
+}
+
+func (f *fakeCompletionInspectionService) UpdateStatus(ctx context.Context, params domain.UpdateInspectionStatusParams) error {
+	if f.blockers.Any() && !params.ConfirmsForce() {
+		message := f.blockers.Error()
+		if params.Force {
+			message = "type COMPLETE to complete the inspection anyway"
+		}
+		return domain.Wrap(f.blockers, domain.EINVALID, "inspection.update_status", message)
+	}
+	f.params = &params
+	return nil
+}
+
+func serveCompletion(svc *fakeCompletionInspectionService, form url.Values) *httptest.ResponseRecorder {
+	h := NewInspectionHandler(svc, nil, nil, nil, nil, nil, newTestLogger())
+
+	id := uuid.New().String()
+	req := httptest.NewRequest(http.MethodPut, "/inspections/"+id+"/status", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetPathValue("id", id)
+	req = req.WithContext(auth.SetUser(req.Context(), &domain.User{ID: uuid.New()}))
+	rr := httptest.NewRecorder()
+	h.UpdateStatusTempl(rr, req)
+	return rr
+}
+
+func TestInspectionUpdateStatus_RendersCompletionBlockers(t *testing.T) {
+	svc := &fakeCompletionInspectionService{blockers: domain.CompletionBlockers{PendingViolations: 3, MissingReport: true}}
+
+	rr := serveCompletion(svc, url.Values{"status": {"completed"}})
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	body := rr.Body.String()
+	for _, want := range []string{
+		"3 violations pending review",
+		"/review/queue",
+		"No report has been generated",
+		`href="#reports"`,
+		`name="force" value="true"`,
+		"Type COMPLETE to complete it anyway",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected blockers partial to contain %q, got %s", want, body)
+		}
+	}
+	if rr.Header().Get("HX-Trigger") != "" {
+		t.Error("expected no status change event for a refused completion")
+	}
+}
+
+func TestInspectionUpdateStatus_ForceCompletion(t *testing.T) {
+	svc := &fakeCompletionInspectionService{blockers: domain.CompletionBlockers{PendingViolations: 1}}
+
+	rr := serveCompletion(svc, url.Values{"status": {"completed"}, "force": {"true"}, "confirmation": {"yes"}})
+	if !strings.Contains(rr.Body.String(), "type COMPLETE to complete the inspection anyway") {
+		t.Errorf("expected the confirmation error, got %s", rr.Body.String())
+	}
+	if !strings.Contains(rr.Body.String(), "1 violation pending review") || svc.params != nil {
+		t.Fatal("expected completion refused with the blockers shown again")
+	}
+
+	rr = serveCompletion(svc, url.Values{"status": {"completed"}, "force": {"true"}, "confirmation": {"COMPLETE"}})
+	if svc.params == nil || !svc.params.Force {
+		t.Fatal("expected the forced completion to reach the service")
+	}
+	if got := rr.Header().Get("HX-Trigger"); got != "inspectionCompleted" {
+		t.Errorf("expected inspectionCompleted, got %q", got)
+	}
+}
+
+// fakeTeamInspectionService shares one inspection with the owner's
+// organization.
+type fakeTeamInspectionService struct {
+	service.InspectionService
+	inspection domain.Inspection
+	members    map[uuid.UUID]bool
+}
+
+func (f *fakeTeamInspectionService) GetByID(ctx context.Context, id, userID uuid.UUID) (*domain.Inspection, error) {
+	if id != f.inspection.ID || !f.members[userID] {
+		return nil, domain.NotFound("InspectionService.GetByID", "inspection", id.String())
+	}
+	i := f.inspection
+	return &i, nil
+}
+
+func (f *fakeTeamInspectionService) GetAnalysisStatus(ctx context.Context, inspectionID, userID uuid.UUID) (*domain.AnalysisStatus, error) {
+	return &domain.AnalysisStatus{InspectionID: inspectionID, Status: f.inspection.Status}, nil
+}
+
+// fakeOwnedClientsService keeps clients by the user who owns them.
+type fakeOwnedClientsService struct {
+	service.ClientService
+	clients map[uuid.UUID][]domain.Client
+}
+
+func (f fakeOwnedClientsService) ListAll(ctx context.Context, userID uuid.UUID) ([]domain.Client, error) {
+	return f.clients[userID], nil
+}
+
+func (f fakeOwnedClientsService) GetByID(ctx context.Context, id, userID uuid.UUID) (*domain.Client, error) {
+	for _, c := range f.clients[userID] {
+		if c.ID == id {
+			return &c, nil
+		}
+	}
+	return nil, domain.NotFound("ClientService.GetByID", "client", id.String())
+}
+
+// fakeConfirmedViolationService lists one confirmed violation.
+type fakeConfirmedViolationService struct {
+	service.ViolationService
+}
+
+func (fakeConfirmedViolationService) ListByInspection(ctx context.Context, inspectionID, userID uuid.UUID, filter *domain.ViolationFilter) ([]domain.Violation, error) {
+	return []domain.Violation{{ID: uuid.New(), InspectionID: inspectionID, Status: domain.ViolationStatusConfirmed}}, nil
+}
+
+// fakeNoImagesService lists no images.
+type fakeNoImagesService struct {
+	service.ImageService
+}
+
+func (fakeNoImagesService) ListByInspection(ctx context.Context, inspectionID, userID uuid.UUID) ([]domain.Image, error) {
+	return nil, nil
+}
+
+// fakeNoReportsService lists no reports.
+type fakeNoReportsService struct {
+	service.ReportService
+}
+
+func (fakeNoReportsService) ListByInspection(ctx context.Context, inspectionID, userID uuid.UUID) ([]domain.Report, error) {
+	return nil, nil
+}
+
+func TestInspectionEdit_TeammateSeesOwnersClient(t *testing.T) {
+	owner, teammate := uuid.New(), uuid.New()
+	client := domain.Client{ID: uuid.New(), UserID: owner, Name: "Acme Builders", Email: "site@acme.example"}
+	svc := &fakeTeamInspectionService{
+		inspection: domain.Inspection{
+			ID:       uuid.New(),
+			UserID:   owner,
+			ClientID: &client.ID,
+			Title:    "Site walk",
+			Status:   domain.InspectionStatusReview,
+			Version:  2,
+		},
+		members: map[uuid.UUID]bool{owner: true, teammate: true},
+	}
+	clients := fakeOwnedClientsService{clients: map[uuid.UUID][]domain.Client{
+		owner:    {client},
+		teammate: {{ID: uuid.New(), UserID: teammate, Name: "Teammate's own client"}},
+	}}
+	h := NewInspectionHandler(svc, fakeNoImagesService{}, fakeConfirmedViolationService{}, clients, fakeNoReportsService{}, nil, newTestLogger())
+	id := svc.inspection.ID.String()
+
+	serve := func(handler http.HandlerFunc, path string) string {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.SetPathValue("id", id)
+		req = req.WithContext(auth.SetUser(req.Context(), &domain.User{ID: teammate}))
+		rr := httptest.NewRecorder()
+		handler(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("GET %s: expected 200, got %d", path, rr.Code)
+		}
+		return rr.Body.String()
+	}
+
+	edit := serve(h.EditTempl, "/inspections/"+id+"/edit")
+	if want := `<option value="` + client.ID.String() + `" selected>Acme Builders</option>`; !strings.Contains(edit, want) {
+		t.Errorf("expected the edit form to keep the owner's client selected, got %s", edit)
+	}
+	if strings.Contains(edit, "Teammate's own client") {
+		t.Error("expected the teammate's own clients not to be offered for the owner's inspection")
+	}
+
+	show := serve(h.ShowTempl, "/inspections/"+id)
+	if !strings.Contains(show, `value="site@acme.example"`) {
+		t.Errorf("expected the show page to prefill the client's email")
+	}
+
+	// A rejected save re-renders the form with the owner's clients
+	form := url.Values{"title": {"Site walk"}, "client_id": {client.ID.String()}, "inspection_date": {"not a date"}, "version": {"2"}}
+	req := httptest.NewRequest(http.MethodPut, "/inspections/"+id, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetPathValue("id", id)
+	req = req.WithContext(auth.SetUser(req.Context(), &domain.User{ID: teammate}))
+	rr := httptest.NewRecorder()
+	h.Update(rr, req)
+	if body := rr.Body.String(); !strings.Contains(body, `<option value="`+client.ID.String()+`" selected>Acme Builders</option>`) {
+		t.Errorf("expected the re-rendered form to keep the owner's client selected, got %d: %s", rr.Code, body)
+	}
+}
+
+// fakeTimelineInspectionService serves a fixed timeline for one inspection.
+type fakeTimelineInspectionService struct {
+	service.InspectionService
+	inspectionID uuid.UUID
+	userID       uuid.UUID
+	entries      []domain.TimelineEntry
+}
+
+func (f *fakeTimelineInspectionService) Timeline(ctx context.Context, inspectionID, userID uuid.UUID) ([]domain.TimelineEntry, error) {
+	if inspectionID != f.inspectionID || userID != f.userID {
+		return nil, domain.NotFound("InspectionService.Timeline", "inspection", inspectionID.String())
+	}
+	return f.entries, nil
+}
+
+func serveTimeline(svc *fakeTimelineInspectionService, userID uuid.UUID) *httptest.ResponseRecorder {
+	h := NewInspectionHandler(svc, nil, nil, nil, nil, nil, newTestLogger())
+	id := svc.inspectionID.String()
+	req := httptest.NewRequest(http.MethodGet, "/inspections/"+id+"/timeline", nil)
+	req.SetPathValue("id", id)
+	req = req.WithContext(auth.SetUser(req.Context(), &domain.User{ID: userID}))
+	rr := httptest.NewRecorder()
+	h.Timeline(rr, req)
+	return rr
+}
+
+func TestInspectionTimeline_RendersEntriesInOrder(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	svc := &fakeTimelineInspectionService{
+		inspectionID: uuid.New(),
+		userID:       uuid.New(),
+		entries: []domain.TimelineEntry{
+			{Kind: domain.TimelineCreated, Summary: "Inspection created", OccurredAt: start},
+			{Kind: domain.TimelineViolationReviewed, Summary: "Violation confirmed", ActorName: "Pat Inspector", OccurredAt: start.Add(20 * time.Minute)},
+			{Kind: domain.TimelineStatusChanged, Summary: "Inspection status changed from review to completed", OccurredAt: start.Add(time.Hour)},
+		},
+	}
+
+	rr := serveTimeline(svc, svc.userID)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	body := rr.Body.String()
+	last := -1
+	for _, e := range svc.entries {
+		i := strings.Index(body, e.Summary)
+		if i < 0 {
+			t.Fatalf("expected %q in the timeline, got %s", e.Summary, body)
+		}
+		if i < last {
+			t.Errorf("expected %q after the entries before it", e.Summary)
+		}
+		last = i
+	}
+	if !strings.Contains(body, "Pat Inspector") {
+		t.Error("expected the actor's name on the reviewed violation")
+	}
+}
+
+func TestInspectionTimeline_OtherUserNotFound(t *testing.T) {
+	svc := &fakeTimelineInspectionService{inspectionID: uuid.New(), userID: uuid.New()}
+
+	if rr := serveTimeline(svc, uuid.New()); rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for another user's inspection, got %d", rr.Code)
+	}
+}
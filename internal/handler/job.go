@@ -0,0 +1,152 @@
+// Package handler contains HTTP handlers for the Lukaut application.
+//
+// This file implements the job status endpoint, which lets the browser
+// follow a background job it started, such as report generation.
+//
+// Route:
+//   - GET /jobs/{id} -> Show (JSON or an htmx partial)
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/auth"
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/service"
+	"github.com/DukeRupert/lukaut/internal/templ/partials"
+	"github.com/DukeRupert/lukaut/internal/worker"
+	"github.com/google/uuid"
+)
+
+// JobHandler handles HTTP requests for background job status.
+type JobHandler struct {
+	jobService service.JobService
+	logger     *slog.Logger
+}
+
+// NewJobHandler creates a new JobHandler.
+func NewJobHandler(jobService service.JobService, logger *slog.Logger) *JobHandler {
+	return &JobHandler{
+		jobService: jobService,
+		logger:     logger,
+	}
+}
+
+// RegisterRoutes registers job routes on the provided ServeMux.
+func (h *JobHandler) RegisterRoutes(mux *http.ServeMux, requireUser func(http.Handler) http.Handler) {
+	mux.Handle("GET /jobs/{id}", requireUser(http.HandlerFunc(h.Show)))
+}
+
+// jobResponse is the JSON form of a job.
+type jobResponse struct {
+	ID          uuid.UUID        `json:"id"`
+	Type        string           `json:"type"`
+	Status      domain.JobStatus `json:"status"`
+	Attempts    int              `json:"attempts"`
+	MaxAttempts int              `json:"max_attempts"`
+	Result      json.RawMessage  `json:"result"`
+	Error       string           `json:"error,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+	FinishedAt  *time.Time       `json:"finished_at"`
+}
+
+// Show returns the status of a job enqueued for the user: JSON for API
+// clients, otherwise a partial that polls until the job is done. Jobs of
+// other users are reported as not found.
+// GET /jobs/{id}
+func (h *JobHandler) Show(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUserFromRequest(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	jobID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid job ID", http.StatusBadRequest)
+		return
+	}
+
+	job, err := h.jobService.Get(r.Context(), jobID, user.ID)
+	if err != nil {
+		ErrorResponse(w, r, h.logger.With("job_id", jobID), err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+
+	if acceptsJSON(r) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(jobResponse{
+			ID:          job.ID,
+			Type:        job.Type,
+			Status:      job.Status,
+			Attempts:    job.Attempts,
+			MaxAttempts: job.MaxAttempts,
+			Result:      job.Result,
+			Error:       job.Error,
+			CreatedAt:   job.CreatedAt,
+			FinishedAt:  job.FinishedAt,
+		}); err != nil {
+			h.logger.ErrorContext(r.Context(), "failed to write job status", "error", err, "job_id", jobID)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := partials.JobStatus(jobStatusData(job)).Render(r.Context(), w); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to render job status", "error", err, "job_id", jobID)
+	}
+}
+
+// jobStatusData describes a job for the job status partial. The raw error
+// of a failed job is left out; it is meant for operators, not users.
+func jobStatusData(job *domain.Job) partials.JobStatusData {
+	data := partials.JobStatusData{
+		JobID:  job.ID.String(),
+		Status: string(job.Status),
+		Poll:   !job.Status.Done(),
+	}
+
+	switch job.Type {
+	case worker.JobTypeAnalyzeInspection:
+		data.Message = jobStatusMessage(job.Status, "Analysis queued", "Analyzing photos...", "Analysis finished", "Analysis failed")
+		var result worker.AnalyzeInspectionResult
+		if job.Status.Done() && json.Unmarshal(job.Result, &result) == nil {
+			data.Message = fmt.Sprintf("%s: %d photo(s) analyzed, %d potential violation(s) found", data.Message, result.ImagesAnalyzed, result.ViolationsFound)
+			data.Error = strings.Join(result.Errors, " ")
+			data.LinkURL = fmt.Sprintf("/inspections/%s", result.InspectionID)
+			data.LinkText = "View inspection"
+		}
+	case worker.JobTypeGenerateReport:
+		data.Message = jobStatusMessage(job.Status, "Report queued", "Generating report...", "Report ready", "Report generation failed")
+		var result worker.GenerateReportResult
+		if job.Status == domain.JobStatusCompleted && json.Unmarshal(job.Result, &result) == nil {
+			data.LinkURL = fmt.Sprintf("/reports/%s/download?format=%s", result.ReportID, result.Format)
+			data.LinkText = "Download " + strings.ToUpper(result.Format)
+		}
+	default:
+		data.Message = jobStatusMessage(job.Status, "Queued", "Running...", "Done", "Failed")
+	}
+
+	return data
+}
+
+// jobStatusMessage picks the message for the job's status.
+func jobStatusMessage(status domain.JobStatus, pending, running, completed, failed string) string {
+	switch status {
+	case domain.JobStatusRunning:
+		return running
+	case domain.JobStatusCompleted:
+		return completed
+	case domain.JobStatusFailed:
+		return failed
+	default:
+		return pending
+	}
+}
@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/worker"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// Fake Job Service
+// =============================================================================
+
+// fakeJobService keeps jobs in memory with their owners, scoped the same way
+// as the SQL query.
+type fakeJobService struct {
+	jobs   map[uuid.UUID]*domain.Job
+	owners map[uuid.UUID]uuid.UUID
+}
+
+func newFakeJobService() *fakeJobService {
+	return &fakeJobService{
+		jobs:   map[uuid.UUID]*domain.Job{},
+		owners: map[uuid.UUID]uuid.UUID{},
+	}
+}
+
+func (f *fakeJobService) add(userID uuid.UUID, job *domain.Job) *domain.Job {
+	job.ID = uuid.New()
+	f.jobs[job.ID] = job
+	f.owners[job.ID] = userID
+	return job
+}
+
+func (f *fakeJobService) Get(ctx context.Context, jobID, userID uuid.UUID) (*domain.Job, error) {
+	job, ok := f.jobs[jobID]
+	if !ok || f.owners[jobID] != userID {
+		return nil, domain.NotFound("test", "job", jobID.String())
+	}
+	return job, nil
+}
+
+// =============================================================================
+// Test Helpers
+// =============================================================================
+
+func serveJob(t *testing.T, jobs *fakeJobService, user *domain.User, jobID string, accept string) *httptest.ResponseRecorder {
+	t.Helper()
+	mux := http.NewServeMux()
+	NewJobHandler(jobs, newTestLogger()).RegisterRoutes(mux, func(next http.Handler) http.Handler { return next })
+
+	req := withSessionsTestUser(httptest.NewRequest(http.MethodGet, "/jobs/"+jobID, nil), user)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func mustMarshalJSON(t *testing.T, v any) json.RawMessage {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return data
+}
+
+// =============================================================================
+// GET /jobs/{id}
+// =============================================================================
+
+func TestJobShow_ReturnsJSON(t *testing.T) {
+	user := &domain.User{ID: uuid.New()}
+	jobs := newFakeJobService()
+	finishedAt := time.Date(2025, time.March, 5, 14, 30, 0, 0, time.UTC)
+	job := jobs.add(user.ID, &domain.Job{
+		Type:        worker.JobTypeAnalyzeInspection,
+		Status:      domain.JobStatusFailed,
+		Attempts:    3,
+		MaxAttempts: 3,
+		Result:      mustMarshalJSON(t, worker.AnalyzeInspectionResult{ImagesFailed: 2, Errors: []string{"The photo could not be read."}}),
+		Error:       "analysis interrupted: context deadline exceeded",
+		FinishedAt:  &finishedAt,
+	})
+
+	rec := serveJob(t, jobs, user, job.ID.String(), "application/json")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected JSON content type, got %q", ct)
+	}
+	var got struct {
+		ID          uuid.UUID                      `json:"id"`
+		Status      string                         `json:"status"`
+		Attempts    int                            `json:"attempts"`
+		MaxAttempts int                            `json:"max_attempts"`
+		Result      worker.AnalyzeInspectionResult `json:"result"`
+		Error       string                         `json:"error"`
+		FinishedAt  *time.Time                     `json:"finished_at"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if got.ID != job.ID || got.Status != "failed" || got.Attempts != 3 || got.MaxAttempts != 3 {
+		t.Errorf("unexpected job fields: %+v", got)
+	}
+	if got.Result.ImagesFailed != 2 || len(got.Result.Errors) != 1 {
+		t.Errorf("expected the stored result, got %+v", got.Result)
+	}
+	if got.Error != job.Error {
+		t.Errorf("expected error %q, got %q", job.Error, got.Error)
+	}
+	if got.FinishedAt == nil || !got.FinishedAt.Equal(finishedAt) {
+		t.Errorf("expected finished_at %v, got %v", finishedAt, got.FinishedAt)
+	}
+}
+
+func TestJobShow_JSONResultIsNullWithoutResult(t *testing.T) {
+	user := &domain.User{ID: uuid.New()}
+	jobs := newFakeJobService()
+	job := jobs.add(user.ID, &domain.Job{Type: worker.JobTypeGenerateReport, Status: domain.JobStatusPending, MaxAttempts: 3})
+
+	rec := serveJob(t, jobs, user, job.ID.String(), "application/json")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, `"result":null`) || !strings.Contains(body, `"finished_at":null`) {
+		t.Errorf("expected null result and finished_at, got %s", body)
+	}
+}
+
+func TestJobShow_RunningPartialPolls(t *testing.T) {
+	user := &domain.User{ID: uuid.New()}
+	jobs := newFakeJobService()
+	job := jobs.add(user.ID, &domain.Job{Type: worker.JobTypeGenerateReport, Status: domain.JobStatusRunning, Attempts: 1, MaxAttempts: 3})
+
+	rec := serveJob(t, jobs, user, job.ID.String(), "")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, `hx-get="/jobs/`+job.ID.String()+`"`) || !strings.Contains(body, "every 2s") {
+		t.Errorf("expected the partial to poll the job, got %s", body)
+	}
+	if !strings.Contains(body, "Generating report...") {
+		t.Errorf("expected the running message, got %s", body)
+	}
+}
+
+func TestJobShow_CompletedReportLinksToDownload(t *testing.T) {
+	user := &domain.User{ID: uuid.New()}
+	jobs := newFakeJobService()
+	reportID := uuid.New()
+	job := jobs.add(user.ID, &domain.Job{
+		Type:   worker.JobTypeGenerateReport,
+		Status: domain.JobStatusCompleted,
+		Result: mustMarshalJSON(t, worker.GenerateReportResult{ReportID: reportID, Format: "pdf", ViolationCount: 4}),
+	})
+
+	rec := serveJob(t, jobs, user, job.ID.String(), "")
+
+	body := rec.Body.String()
+	if strings.Contains(body, "hx-trigger") {
+		t.Errorf("expected a finished job to stop polling, got %s", body)
+	}
+	if want := "/reports/" + reportID.String() + "/download?format=pdf"; !strings.Contains(body, want) {
+		t.Errorf("expected a link to %s, got %s", want, body)
+	}
+}
+
+func TestJobShow_FailedPartialHidesRawError(t *testing.T) {
+	user := &domain.User{ID: uuid.New()}
+	jobs := newFakeJobService()
+	job := jobs.add(user.ID, &domain.Job{
+		Type:   worker.JobTypeGenerateReport,
+		Status: domain.JobStatusFailed,
+		Error:  "upload report to storage: connection refused",
+	})
+
+	rec := serveJob(t, jobs, user, job.ID.String(), "")
+
+	body := rec.Body.String()
+	if !strings.Contains(body, "Report generation failed") {
+		t.Errorf("expected the failure message, got %s", body)
+	}
+	if strings.Contains(body, "connection refused") {
+		t.Errorf("expected the raw error to be left out, got %s", body)
+	}
+}
+
+func TestJobShow_OtherUsersJobIsNotFound(t *testing.T) {
+	owner := &domain.User{ID: uuid.New()}
+	other := &domain.User{ID: uuid.New()}
+	jobs := newFakeJobService()
+	job := jobs.add(owner.ID, &domain.Job{Type: worker.JobTypeGenerateReport, Status: domain.JobStatusPending})
+
+	if rec := serveJob(t, jobs, other, job.ID.String(), "application/json"); rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for another user's job, got %d", rec.Code)
+	}
+	if rec := serveJob(t, jobs, owner, uuid.NewString(), ""); rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for a missing job, got %d", rec.Code)
+	}
+}
+
+func TestJobShow_InvalidID(t *testing.T) {
+	user := &domain.User{ID: uuid.New()}
+
+	if rec := serveJob(t, newFakeJobService(), user, "not-a-uuid", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,43 @@
+// Package handler contains HTTP handlers for the Lukaut application.
+//
+// This file implements the page size choice shared by paginated list pages.
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/service"
+	"github.com/google/uuid"
+)
+
+// resolvePerPage returns the page size for a list request.
+//
+// A per_page query parameter that is one of domain.PerPageOptions is used and
+// remembered as the user's preference, so the choice sticks across pages and
+// sessions. Without one, the saved preference is used. Any other value falls
+// back to domain.DefaultPerPage rather than failing the request. prefs may be
+// nil, in which case nothing is remembered.
+func resolvePerPage(r *http.Request, prefs service.PreferenceService, userID uuid.UUID, logger *slog.Logger) int {
+	param := r.URL.Query().Get("per_page")
+	if param == "" {
+		if prefs == nil {
+			return domain.DefaultPerPage
+		}
+		return prefs.PerPage(r.Context(), userID)
+	}
+
+	n, err := strconv.Atoi(param)
+	if err != nil || !domain.IsValidPerPage(n) {
+		return domain.DefaultPerPage
+	}
+
+	if prefs != nil {
+		if err := prefs.SetPerPage(r.Context(), userID, n); err != nil {
+			logger.ErrorContext(r.Context(), "failed to save page size", "error", err, "user_id", userID)
+		}
+	}
+	return n
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/google/uuid"
+)
+
+// mockPreferenceService is a mock implementation of service.PreferenceService.
+type mockPreferenceService struct {
+	perPage   int
+	saved     []int
+	saveErr   error
+	shortcuts domain.ReviewShortcuts
+	format    *domain.DisplayFormat
+}
+
+func (m *mockPreferenceService) PerPage(ctx context.Context, userID uuid.UUID) int {
+	return domain.NormalizePerPage(m.perPage)
+}
+
+func (m *mockPreferenceService) SetPerPage(ctx context.Context, userID uuid.UUID, perPage int) error {
+	m.saved = append(m.saved, perPage)
+	return m.saveErr
+}
+
+func (m *mockPreferenceService) ReviewShortcuts(ctx context.Context, userID uuid.UUID) domain.ReviewShortcuts {
+	if m.shortcuts != nil {
+		return m.shortcuts
+	}
+	return domain.DefaultReviewShortcuts()
+}
+
+func (m *mockPreferenceService) SetReviewShortcuts(ctx context.Context, userID uuid.UUID, custom map[domain.ReviewAction]string) error {
+	shortcuts, err := domain.MergeReviewShortcuts(custom)
+	if err != nil {
+		return err
+	}
+	m.shortcuts = shortcuts
+	return nil
+}
+
+func (m *mockPreferenceService) DisplayFormat(ctx context.Context, userID uuid.UUID) domain.DisplayFormat {
+	if m.format != nil {
+		return *m.format
+	}
+	return domain.DefaultDisplayFormat()
+}
+
+func (m *mockPreferenceService) SetDisplayFormat(ctx context.Context, userID uuid.UUID, format domain.DisplayFormat) error {
+	if err := format.Validate(); err != nil {
+		return err
+	}
+	m.format = &format
+	return nil
+}
+
+func TestResolvePerPage(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		saved     int
+		want      int
+		wantSaved []int
+	}{
+		{name: "no parameter uses the default", want: domain.DefaultPerPage},
+		{name: "no parameter uses the saved size", saved: 50, want: 50},
+		{name: "valid parameter is used and saved", query: "?per_page=100", saved: 50, want: 100, wantSaved: []int{100}},
+		{name: "default parameter replaces a saved size", query: "?per_page=20", saved: 50, want: 20, wantSaved: []int{20}},
+		{name: "size not offered falls back to the default", query: "?per_page=25", saved: 50, want: domain.DefaultPerPage},
+		{name: "garbage falls back to the default", query: "?per_page=all", saved: 50, want: domain.DefaultPerPage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefs := &mockPreferenceService{perPage: tt.saved}
+			req := httptest.NewRequest(http.MethodGet, "/inspections"+tt.query, nil)
+
+			got := resolvePerPage(req, prefs, uuid.New(), newTestLogger())
+
+			if got != tt.want {
+				t.Errorf("expected %d per page, got %d", tt.want, got)
+			}
+			if len(prefs.saved) != len(tt.wantSaved) || (len(tt.wantSaved) > 0 && prefs.saved[0] != tt.wantSaved[0]) {
+				t.Errorf("expected saved sizes %v, got %v", tt.wantSaved, prefs.saved)
+			}
+		})
+	}
+}
+
+func TestResolvePerPage_WithoutPreferences(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/inspections?per_page=50", nil)
+
+	if got := resolvePerPage(req, nil, uuid.New(), newTestLogger()); got != 50 {
+		t.Errorf("expected 50 per page, got %d", got)
+	}
+}
+
+func TestResolvePerPage_SaveFailureStillHonorsChoice(t *testing.T) {
+	prefs := &mockPreferenceService{saveErr: domain.Internal(nil, "preference.set_per_page", "boom")}
+	req := httptest.NewRequest(http.MethodGet, "/inspections?per_page=10", nil)
+
+	if got := resolvePerPage(req, prefs, uuid.New(), newTestLogger()); got != 10 {
+		t.Errorf("expected 10 per page, got %d", got)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/DukeRupert/lukaut/internal/auth"
 	"github.com/DukeRupert/lukaut/internal/domain"
 	"github.com/DukeRupert/lukaut/internal/service"
+	"github.com/DukeRupert/lukaut/internal/templ/partials"
 	"github.com/google/uuid"
 )
 
@@ -46,6 +47,8 @@ type RegulationSummary struct {
 	Summary         string
 	SeverityTypical string
 	Rank            float32 // Search relevance rank (only populated for search results)
+	UseCount        int64   // Times the user linked it (only populated for most-used lists)
+	IsBookmarked    bool    // Whether the user has bookmarked it
 }
 
 // RegulationDetail represents full regulation details.
@@ -66,6 +69,8 @@ type RegulationDetail struct {
 // RegulationSearchPartialData contains data for htmx search results partial.
 type RegulationSearchPartialData struct {
 	Regulations  []RegulationSummary // Search results
+	Bookmarks    []RegulationSummary // User's bookmarks, shown when the query is empty
+	MostUsed     []RegulationSummary // User's most-linked regulations, shown when the query is empty
 	Filter       RegulationFilter    // Current filter
 	Pagination   PaginationData      // Pagination info
 	ViolationID  *uuid.UUID          // Optional violation ID for modal context
@@ -87,6 +92,7 @@ type RegulationDetailPartialData struct {
 type RegulationHandler struct {
 	regulationService service.RegulationService
 	violationService  service.ViolationService
+	preferences       service.PreferenceService
 	logger            *slog.Logger
 }
 
@@ -103,6 +109,13 @@ func NewRegulationHandler(
 	}
 }
 
+// WithPreferences remembers the page size users choose for regulation
+// browsing. Call this after NewRegulationHandler.
+func (h *RegulationHandler) WithPreferences(preferences service.PreferenceService) *RegulationHandler {
+	h.preferences = preferences
+	return h
+}
+
 // =============================================================================
 // POST /violations/{vid}/regulations/{rid} - Add Regulation to Violation
 // =============================================================================
@@ -195,45 +208,131 @@ func (h *RegulationHandler) RemoveFromViolation(w http.ResponseWriter, r *http.R
 	_, _ = w.Write([]byte("Regulation removed successfully"))
 }
 
+// =============================================================================
+// POST /regulations/{id}/bookmark - Add Regulation to Bookmarks
+// DELETE /regulations/{id}/bookmark - Remove Regulation from Bookmarks
+// =============================================================================
+
+// AddBookmark bookmarks a regulation for the user and renders the toggled bookmark button.
+func (h *RegulationHandler) AddBookmark(w http.ResponseWriter, r *http.Request) {
+	h.setBookmark(w, r, true)
+}
+
+// RemoveBookmark removes a regulation from the user's bookmarks and renders the toggled bookmark button.
+func (h *RegulationHandler) RemoveBookmark(w http.ResponseWriter, r *http.Request) {
+	h.setBookmark(w, r, false)
+}
+
+// setBookmark adds or removes the regulation in the path from the user's
+// bookmarks.
+func (h *RegulationHandler) setBookmark(w http.ResponseWriter, r *http.Request, bookmarked bool) {
+	user := auth.GetUserFromRequest(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid regulation ID", http.StatusBadRequest)
+		return
+	}
+
+	if bookmarked {
+		err = h.regulationService.AddBookmark(r.Context(), user.ID, id)
+	} else {
+		err = h.regulationService.RemoveBookmark(r.Context(), user.ID, id)
+	}
+	if err != nil {
+		h.handleServiceError(w, err, "update regulation bookmark")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := partials.RegulationBookmarkToggle(id.String(), bookmarked).Render(r.Context(), w); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to render bookmark toggle", "error", err)
+	}
+}
+
 // =============================================================================
 // Helper Functions - Regulation Queries
 // =============================================================================
 
-// searchRegulations performs a full-text search on regulations.
-func (h *RegulationHandler) searchRegulations(r *http.Request, query string, limit, offset int32) ([]RegulationSummary, int64, error) {
-	result, err := h.regulationService.Search(r.Context(), query, limit, offset)
+// searchRegulations performs a full-text search on regulations within the filter.
+func (h *RegulationHandler) searchRegulations(r *http.Request, query string, filter domain.RegulationFilter, limit, offset int32) ([]RegulationSummary, int64, error) {
+	result, err := h.regulationService.Search(r.Context(), query, filter, limit, offset)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	// Convert domain type to handler type
-	summaries := make([]RegulationSummary, len(result.Regulations))
-	for i, reg := range result.Regulations {
-		summaries[i] = RegulationSummary{
-			ID:              reg.ID,
-			StandardNumber:  reg.StandardNumber,
-			Title:           reg.Title,
-			Category:        reg.Category,
-			Subcategory:     reg.Subcategory,
-			Summary:         reg.Summary,
-			SeverityTypical: reg.SeverityTypical,
-			Rank:            reg.Rank,
-		}
+	return toRegulationSummaries(result.Regulations), result.Total, nil
+}
+
+// browseRegulations lists regulations within the filter.
+func (h *RegulationHandler) browseRegulations(r *http.Request, filter domain.RegulationFilter, limit, offset int32) ([]RegulationSummary, int64, error) {
+	result, err := h.regulationService.Browse(r.Context(), filter, limit, offset)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	return summaries, result.Total, nil
+	return toRegulationSummaries(result.Regulations), result.Total, nil
 }
 
-// browseRegulations lists regulations optionally filtered by category.
-func (h *RegulationHandler) browseRegulations(r *http.Request, category string, limit, offset int32) ([]RegulationSummary, int64, error) {
-	result, err := h.regulationService.Browse(r.Context(), category, limit, offset)
+// shortlistLimit caps the most-used regulations offered before a search.
+const shortlistLimit = 10
+
+// loadShortlist returns the user's bookmarked and most-used regulations. The
+// shortlist is a convenience, so failures are logged and yield empty lists.
+func (h *RegulationHandler) loadShortlist(r *http.Request, userID uuid.UUID) (bookmarks, mostUsed []RegulationSummary) {
+	bookmarked, err := h.regulationService.ListBookmarks(r.Context(), userID)
 	if err != nil {
-		return nil, 0, err
+		h.logger.ErrorContext(r.Context(), "failed to list bookmarked regulations", "error", err)
+	}
+	used, err := h.regulationService.ListMostUsed(r.Context(), userID, shortlistLimit)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to list most used regulations", "error", err)
 	}
 
-	// Convert domain type to handler type
-	summaries := make([]RegulationSummary, len(result.Regulations))
-	for i, reg := range result.Regulations {
+	bookmarks = toRegulationSummaries(bookmarked)
+	for i := range bookmarks {
+		bookmarks[i].IsBookmarked = true
+	}
+	return bookmarks, toRegulationSummaries(used)
+}
+
+// loadRecent returns the regulations the user viewed most recently, marked
+// when bookmarked. Failures are logged and yield an empty list.
+func (h *RegulationHandler) loadRecent(r *http.Request, userID uuid.UUID) []RegulationSummary {
+	viewed, err := h.regulationService.ListRecentlyViewed(r.Context(), userID)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to list recently viewed regulations", "error", err)
+		return nil
+	}
+	recent := toRegulationSummaries(viewed)
+	h.markBookmarks(r, userID, recent)
+	return recent
+}
+
+// markBookmarks sets IsBookmarked on the regulations the user has bookmarked.
+// Failures are logged and leave every regulation unmarked.
+func (h *RegulationHandler) markBookmarks(r *http.Request, userID uuid.UUID, regs []RegulationSummary) {
+	if len(regs) == 0 {
+		return
+	}
+	bookmarks, err := h.regulationService.ListBookmarkIDs(r.Context(), userID)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to list bookmarked regulations", "error", err)
+		return
+	}
+	for i := range regs {
+		regs[i].IsBookmarked = bookmarks[regs[i].ID]
+	}
+}
+
+// toRegulationSummaries converts domain regulation summaries to handler types.
+func toRegulationSummaries(regs []domain.RegulationSummary) []RegulationSummary {
+	summaries := make([]RegulationSummary, len(regs))
+	for i, reg := range regs {
 		summaries[i] = RegulationSummary{
 			ID:              reg.ID,
 			StandardNumber:  reg.StandardNumber,
@@ -243,10 +342,10 @@ func (h *RegulationHandler) browseRegulations(r *http.Request, category string,
 			Summary:         reg.Summary,
 			SeverityTypical: reg.SeverityTypical,
 			Rank:            reg.Rank,
+			UseCount:        reg.UseCount,
 		}
 	}
-
-	return summaries, result.Total, nil
+	return summaries
 }
 
 // =============================================================================
@@ -25,7 +25,7 @@ import (
 func (h *RegulationHandler) IndexTempl(w http.ResponseWriter, r *http.Request) {
 	user := auth.GetUserFromRequest(r)
 	if user == nil {
-		h.logger.Error("index handler called without authenticated user")
+		h.logger.ErrorContext(r.Context(), "index handler called without authenticated user")
 		http.Redirect(w, r, "/login", http.StatusSeeOther)
 		return
 	}
@@ -33,6 +33,8 @@ func (h *RegulationHandler) IndexTempl(w http.ResponseWriter, r *http.Request) {
 	// Parse query parameters
 	query := strings.TrimSpace(r.URL.Query().Get("q"))
 	category := r.URL.Query().Get("category")
+	subcategory := r.URL.Query().Get("subcategory")
+	severity := parseRegulationSeverity(r)
 	page := 1
 	if pageStr := r.URL.Query().Get("page"); pageStr != "" {
 		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
@@ -40,33 +42,34 @@ func (h *RegulationHandler) IndexTempl(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	perPage := int32(20)
+	perPage := int32(resolvePerPage(r, h.preferences, user.ID, h.logger))
 	offset := int32((page - 1) * int(perPage))
 
-	// Fetch categories for dropdown
-	categories, err := h.regulationService.ListCategories(r.Context())
+	// Fetch category and subcategory counts for the filters
+	facets, err := h.regulationService.ListFacets(r.Context(), query, category)
 	if err != nil {
-		h.logger.Error("failed to fetch categories", "error", err)
-		categories = []string{} // Continue with empty list
+		h.logger.ErrorContext(r.Context(), "failed to fetch regulation facets", "error", err)
+		facets = &domain.RegulationFacets{} // Continue with empty filters
 	}
 
 	// Determine whether to search or browse
+	filter := domain.RegulationFilter{Category: category, Subcategory: subcategory, Severity: severity}
 	var regs []RegulationSummary
 	var total int64
 
 	if query != "" {
 		// Search mode
-		regs, total, err = h.searchRegulations(r, query, perPage, offset)
+		regs, total, err = h.searchRegulations(r, query, filter, perPage, offset)
 		if err != nil {
-			h.logger.Error("failed to search regulations", "error", err, "query", query)
+			h.logger.ErrorContext(r.Context(), "failed to search regulations", "error", err, "query", query)
 			h.renderIndexErrorTempl(w, r, user, "Failed to search regulations. Please try again.")
 			return
 		}
 	} else {
-		// Browse mode (optionally filtered by category)
-		regs, total, err = h.browseRegulations(r, category, perPage, offset)
+		// Browse mode (optionally filtered by category, subcategory, and severity)
+		regs, total, err = h.browseRegulations(r, filter, perPage, offset)
 		if err != nil {
-			h.logger.Error("failed to browse regulations", "error", err, "category", category)
+			h.logger.ErrorContext(r.Context(), "failed to browse regulations", "error", err, "category", category)
 			h.renderIndexErrorTempl(w, r, user, "Failed to load regulations. Please try again.")
 			return
 		}
@@ -85,6 +88,14 @@ func (h *RegulationHandler) IndexTempl(w http.ResponseWriter, r *http.Request) {
 		NextPage:    page + 1,
 	}
 
+	// Mark the user's bookmarks, and offer the shortlist on an unfiltered first page
+	h.markBookmarks(r, user.ID, regs)
+	var recent, bookmarks, mostUsed []RegulationSummary
+	if query == "" && category == "" && severity == "" && page == 1 {
+		recent = h.loadRecent(r, user.ID)
+		bookmarks, mostUsed = h.loadShortlist(r, user.ID)
+	}
+
 	// Convert to display types
 	displayRegs := regulationsToDisplay(regs)
 
@@ -93,10 +104,15 @@ func (h *RegulationHandler) IndexTempl(w http.ResponseWriter, r *http.Request) {
 		CSRFToken:   "",
 		User:        domainUserToRegulationDisplay(user),
 		Regulations: displayRegs,
-		Categories:  categories,
+		Recent:      regulationsToDisplay(recent),
+		Bookmarks:   regulationsToDisplay(bookmarks),
+		MostUsed:    regulationsToDisplay(mostUsed),
+		Facets:      facetsToDisplay(facets, filter),
 		Filter: regulations.FilterData{
-			Query:    query,
-			Category: category,
+			Query:       query,
+			Category:    category,
+			Subcategory: subcategory,
+			Severity:    string(severity),
 		},
 		Pagination: pagination,
 		Flash:      nil,
@@ -104,7 +120,42 @@ func (h *RegulationHandler) IndexTempl(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	if err := regulations.IndexPage(data).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render regulations index", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render regulations index", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// BookmarksTempl displays the regulations the user has bookmarked.
+func (h *RegulationHandler) BookmarksTempl(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUserFromRequest(r)
+	if user == nil {
+		h.logger.ErrorContext(r.Context(), "bookmarks handler called without authenticated user")
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	bookmarked, err := h.regulationService.ListBookmarks(r.Context(), user.ID)
+	if err != nil {
+		ServerErrorResponse(w, r, h.logger, fmt.Errorf("list bookmarked regulations: %w", err))
+		return
+	}
+
+	regs := toRegulationSummaries(bookmarked)
+	for i := range regs {
+		regs[i].IsBookmarked = true
+	}
+
+	data := regulations.BookmarksPageData{
+		CurrentPath: r.URL.Path,
+		CSRFToken:   "",
+		User:        domainUserToRegulationDisplay(user),
+		Regulations: regulationsToDisplay(regs),
+		Flash:       nil,
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := regulations.BookmarksPage(data).Render(r.Context(), w); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to render bookmarked regulations", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -120,6 +171,8 @@ func (h *RegulationHandler) SearchTempl(w http.ResponseWriter, r *http.Request)
 	// Parse query parameters
 	query := strings.TrimSpace(r.URL.Query().Get("q"))
 	category := r.URL.Query().Get("category")
+	subcategory := r.URL.Query().Get("subcategory")
+	severity := parseRegulationSeverity(r)
 	violationIDStr := r.URL.Query().Get("violation_id")
 	page := 1
 	if pageStr := r.URL.Query().Get("page"); pageStr != "" {
@@ -128,7 +181,7 @@ func (h *RegulationHandler) SearchTempl(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
-	perPage := int32(20)
+	perPage := int32(resolvePerPage(r, h.preferences, user.ID, h.logger))
 	offset := int32((page - 1) * int(perPage))
 
 	// Parse optional violation_id for modal context
@@ -141,24 +194,31 @@ func (h *RegulationHandler) SearchTempl(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Determine whether to search or browse
+	filter := domain.RegulationFilter{Category: category, Subcategory: subcategory, Severity: severity}
 	var regs []RegulationSummary
 	var total int64
 	var err error
 
 	if query != "" {
 		// Search mode
-		regs, total, err = h.searchRegulations(r, query, perPage, offset)
+		regs, total, err = h.searchRegulations(r, query, filter, perPage, offset)
 	} else {
-		// Browse mode (optionally filtered by category)
-		regs, total, err = h.browseRegulations(r, category, perPage, offset)
+		// Browse mode (optionally filtered by category, subcategory, and severity)
+		regs, total, err = h.browseRegulations(r, filter, perPage, offset)
 	}
 
 	if err != nil {
-		h.logger.Error("failed to fetch regulations", "error", err, "query", query, "category", category)
-		http.Error(w, "Failed to load regulations", http.StatusInternalServerError)
+		ServerErrorResponse(w, r, h.logger.With("query", query, "category", category), fmt.Errorf("fetch regulations: %w", err))
 		return
 	}
 
+	// Refresh the facet counts for the new query
+	facets, err := h.regulationService.ListFacets(r.Context(), query, category)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to fetch regulation facets", "error", err)
+		facets = &domain.RegulationFacets{} // Continue with empty filters
+	}
+
 	// Build pagination data
 	totalPages := int((total + int64(perPage) - 1) / int64(perPage))
 	pagination := regulations.PaginationData{
@@ -176,8 +236,20 @@ func (h *RegulationHandler) SearchTempl(w http.ResponseWriter, r *http.Request)
 	emptyMessage := "No regulations found."
 	if query != "" {
 		emptyMessage = fmt.Sprintf("No regulations found matching \"%s\".", query)
+	} else if subcategory != "" && category != "" {
+		emptyMessage = fmt.Sprintf("No regulations found in \"%s / %s\".", category, subcategory)
 	} else if category != "" {
 		emptyMessage = fmt.Sprintf("No regulations found in category \"%s\".", category)
+	} else if severity != "" {
+		emptyMessage = fmt.Sprintf("No regulations found with %s severity.", severity)
+	}
+
+	// Mark the user's bookmarks, and offer the shortlist on an unfiltered first page
+	h.markBookmarks(r, user.ID, regs)
+	var recent, bookmarks, mostUsed []RegulationSummary
+	if query == "" && category == "" && severity == "" && page == 1 {
+		recent = h.loadRecent(r, user.ID)
+		bookmarks, mostUsed = h.loadShortlist(r, user.ID)
 	}
 
 	// Convert to display types
@@ -185,9 +257,15 @@ func (h *RegulationHandler) SearchTempl(w http.ResponseWriter, r *http.Request)
 
 	data := regulations.SearchResultsData{
 		Regulations: displayRegs,
+		Recent:      regulationsToDisplay(recent),
+		Bookmarks:   regulationsToDisplay(bookmarks),
+		MostUsed:    regulationsToDisplay(mostUsed),
+		Facets:      facetsToDisplay(facets, filter),
 		Filter: regulations.FilterData{
-			Query:    query,
-			Category: category,
+			Query:       query,
+			Category:    category,
+			Subcategory: subcategory,
+			Severity:    string(severity),
 		},
 		Pagination:   pagination,
 		ViolationID:  violationID,
@@ -196,7 +274,7 @@ func (h *RegulationHandler) SearchTempl(w http.ResponseWriter, r *http.Request)
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	if err := regulations.SearchResultsPartial(data).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render search results", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render search results", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -232,35 +310,19 @@ func (h *RegulationHandler) InlineSearchTempl(w http.ResponseWriter, r *http.Req
 	perPage := int32(8)
 	offset := int32(0)
 
-	// Determine whether to search or return empty
-	var regs []RegulationSummary
+	// Search, or offer the user's shortlist before they type
+	var regs, bookmarks, mostUsed []RegulationSummary
 
 	if query != "" {
 		// Search mode
-		regs, _, err = h.searchRegulations(r, query, perPage, offset)
+		regs, _, err = h.searchRegulations(r, query, domain.RegulationFilter{}, perPage, offset)
 		if err != nil {
-			h.logger.Error("failed to search regulations", "error", err, "query", query)
-			http.Error(w, "Failed to search regulations", http.StatusInternalServerError)
+			ServerErrorResponse(w, r, h.logger.With("query", query), fmt.Errorf("search regulations: %w", err))
 			return
 		}
+		h.markBookmarks(r, user.ID, regs)
 	} else {
-		// No query - return empty results
-		regs = []RegulationSummary{}
-	}
-
-	// Convert to inline display types
-	inlineRegs := make([]partials.InlineRegulationDisplay, len(regs))
-	for i, reg := range regs {
-		// Truncate title if too long
-		title := reg.Title
-		if len(title) > 80 {
-			title = title[:77] + "..."
-		}
-		inlineRegs[i] = partials.InlineRegulationDisplay{
-			RegulationID:   reg.ID.String(),
-			StandardNumber: reg.StandardNumber,
-			Title:          title,
-		}
+		bookmarks, mostUsed = h.loadShortlist(r, user.ID)
 	}
 
 	// Determine empty message
@@ -270,14 +332,75 @@ func (h *RegulationHandler) InlineSearchTempl(w http.ResponseWriter, r *http.Req
 	}
 
 	data := partials.InlineRegulationSearchResultsData{
-		Regulations:  inlineRegs,
+		Regulations:  regulationsToInlineDisplay(regs),
+		Bookmarks:    regulationsToInlineDisplay(bookmarks),
+		MostUsed:     regulationsToInlineDisplay(mostUsed),
 		ViolationID:  vid.String(),
 		EmptyMessage: emptyMessage,
 	}
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	if err := partials.InlineRegulationSearchResults(data).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render inline search results", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render inline search results", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// suggestionLimit caps the regulations suggested for a violation.
+const suggestionLimit = 8
+
+// SuggestedRegulationsTempl returns regulations matching the violation's AI
+// description, best match first, as an htmx partial with one-click add.
+// GET /violations/{vid}/suggested-regulations
+func (h *RegulationHandler) SuggestedRegulationsTempl(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUserFromRequest(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Parse violation ID from path
+	vid, err := uuid.Parse(r.PathValue("vid"))
+	if err != nil {
+		http.Error(w, "Invalid violation ID", http.StatusBadRequest)
+		return
+	}
+
+	// Fetch the violation (verifying ownership) with its linked regulations
+	violation, linked, err := h.violationService.GetByIDWithRegulations(r.Context(), vid, user.ID)
+	if err != nil {
+		h.handleServiceError(w, err, "get violation for suggestions")
+		return
+	}
+
+	var regs []RegulationSummary
+	if query := violation.RegulationSuggestionQuery(); query != "" {
+		regs, _, err = h.searchRegulations(r, query, domain.RegulationFilter{}, suggestionLimit, 0)
+		if err != nil {
+			ServerErrorResponse(w, r, h.logger.With("violation_id", vid), fmt.Errorf("search suggested regulations: %w", err))
+			return
+		}
+		h.markBookmarks(r, user.ID, regs)
+	}
+
+	linkedIDs := make(map[string]bool, len(linked))
+	for _, l := range linked {
+		linkedIDs[l.RegulationID.String()] = true
+	}
+	display := regulationsToInlineDisplay(regs)
+	for i := range display {
+		display[i].IsLinked = linkedIDs[display[i].RegulationID]
+	}
+
+	data := partials.SuggestedRegulationsData{
+		Regulations:  display,
+		ViolationID:  vid.String(),
+		EmptyMessage: "No matching regulations found. Try searching instead.",
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := partials.SuggestedRegulations(data).Render(r.Context(), w); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to render suggested regulations", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -317,6 +440,12 @@ func (h *RegulationHandler) GetDetailTempl(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	// Remember the view for the recently viewed list; failing to is not worth
+	// an error page
+	if err := h.regulationService.RecordView(r.Context(), user.ID, reg.ID); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to record regulation view", "error", err, "regulation_id", reg.ID)
+	}
+
 	// Convert to display type
 	regulation := regulations.RegulationDetailDisplay{
 		ID:              reg.ID.String(),
@@ -348,7 +477,7 @@ func (h *RegulationHandler) GetDetailTempl(w http.ResponseWriter, r *http.Reques
 			// Check if regulation is already linked
 			alreadyLinked, err = h.regulationService.IsLinkedToViolation(r.Context(), *violationUUID, id)
 			if err != nil {
-				h.logger.Error("failed to check regulation link", "error", err)
+				h.logger.ErrorContext(r.Context(), "failed to check regulation link", "error", err)
 				// Non-fatal, just show as not linked
 				alreadyLinked = false
 			}
@@ -363,7 +492,7 @@ func (h *RegulationHandler) GetDetailTempl(w http.ResponseWriter, r *http.Reques
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	if err := regulations.DetailPartial(data).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render regulation detail", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render regulation detail", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -376,9 +505,14 @@ func (h *RegulationHandler) GetDetailTempl(w http.ResponseWriter, r *http.Reques
 func (h *RegulationHandler) RegisterTemplRoutes(mux *http.ServeMux, requireUser func(http.Handler) http.Handler) {
 	mux.Handle("GET /regulations", requireUser(http.HandlerFunc(h.IndexTempl)))
 	mux.Handle("GET /regulations/search", requireUser(http.HandlerFunc(h.SearchTempl)))
+	mux.Handle("GET /regulations/bookmarks", requireUser(http.HandlerFunc(h.BookmarksTempl)))
 	mux.Handle("GET /regulations/{id}", requireUser(http.HandlerFunc(h.GetDetailTempl)))
+	// Bookmarks (return the toggled bookmark button)
+	mux.Handle("POST /regulations/{id}/bookmark", requireUser(http.HandlerFunc(h.AddBookmark)))
+	mux.Handle("DELETE /regulations/{id}/bookmark", requireUser(http.HandlerFunc(h.RemoveBookmark)))
 	// Inline search for violation cards/queue view
 	mux.Handle("GET /violations/{vid}/regulations/search", requireUser(http.HandlerFunc(h.InlineSearchTempl)))
+	mux.Handle("GET /violations/{vid}/suggested-regulations", requireUser(http.HandlerFunc(h.SuggestedRegulationsTempl)))
 	// Keep violation linking routes as-is (they return text, not HTML)
 	mux.Handle("POST /violations/{vid}/regulations/{rid}", requireUser(http.HandlerFunc(h.AddToViolation)))
 	mux.Handle("DELETE /violations/{vid}/regulations/{rid}", requireUser(http.HandlerFunc(h.RemoveFromViolation)))
@@ -413,6 +547,69 @@ func regulationsToDisplay(regs []RegulationSummary) []regulations.RegulationDisp
 			Summary:         r.Summary,
 			SeverityTypical: r.SeverityTypical,
 			Rank:            r.Rank,
+			UseCount:        r.UseCount,
+			IsBookmarked:    r.IsBookmarked,
+		}
+	}
+	return display
+}
+
+// facetsToDisplay converts facet counts to filter options. The selected
+// category and subcategory are kept as options even when nothing matches the
+// query, so the filters don't silently reset.
+func facetsToDisplay(facets *domain.RegulationFacets, filter domain.RegulationFilter) regulations.FacetsData {
+	data := regulations.FacetsData{
+		Categories: facetOptions(facets.Categories, filter.Category),
+	}
+	if filter.Category != "" {
+		data.Subcategories = facetOptions(facets.Subcategories, filter.Subcategory)
+	}
+	for _, severity := range domain.RegulationSeverities {
+		data.Severities = append(data.Severities, string(severity))
+	}
+	return data
+}
+
+// facetOptions converts facets to display options, adding selected with a
+// zero count if it is missing.
+func facetOptions(facets []domain.RegulationFacet, selected string) []regulations.FacetDisplay {
+	options := make([]regulations.FacetDisplay, 0, len(facets)+1)
+	found := selected == ""
+	for _, f := range facets {
+		options = append(options, regulations.FacetDisplay{Name: f.Name, Count: f.Count})
+		found = found || f.Name == selected
+	}
+	if !found {
+		options = append(options, regulations.FacetDisplay{Name: selected})
+	}
+	return options
+}
+
+// parseRegulationSeverity reads the severity filter from the query string.
+// Unknown severities are ignored rather than matching nothing.
+func parseRegulationSeverity(r *http.Request) domain.ViolationSeverity {
+	severity := domain.ViolationSeverity(strings.ToLower(r.URL.Query().Get("severity")))
+	if !severity.IsValid() {
+		return ""
+	}
+	return severity
+}
+
+// regulationsToInlineDisplay converts a slice of RegulationSummary to
+// compact inline search results.
+func regulationsToInlineDisplay(regs []RegulationSummary) []partials.InlineRegulationDisplay {
+	display := make([]partials.InlineRegulationDisplay, len(regs))
+	for i, reg := range regs {
+		// Truncate title if too long
+		title := reg.Title
+		if len(title) > 80 {
+			title = title[:77] + "..."
+		}
+		display[i] = partials.InlineRegulationDisplay{
+			RegulationID:   reg.ID.String(),
+			StandardNumber: reg.StandardNumber,
+			Title:          title,
+			IsBookmarked:   reg.IsBookmarked,
 		}
 	}
 	return display
@@ -425,7 +622,7 @@ func (h *RegulationHandler) renderIndexErrorTempl(w http.ResponseWriter, r *http
 		CSRFToken:   "",
 		User:        domainUserToRegulationDisplay(user),
 		Regulations: []regulations.RegulationDisplay{},
-		Categories:  []string{},
+		Facets:      regulations.FacetsData{},
 		Filter:      regulations.FilterData{},
 		Pagination:  regulations.PaginationData{},
 		Flash: &shared.Flash{
@@ -436,7 +633,7 @@ func (h *RegulationHandler) renderIndexErrorTempl(w http.ResponseWriter, r *http
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	if err := regulations.IndexPage(data).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render regulations index error", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render regulations index error", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
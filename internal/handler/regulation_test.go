@@ -0,0 +1,446 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/DukeRupert/lukaut/internal/auth"
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/service"
+	"github.com/google/uuid"
+)
+
+// fakeBookmarksRegulationService keeps bookmarks in memory and returns a
+// fixed most-used list.
+type fakeBookmarksRegulationService struct {
+	service.RegulationService
+	regulations map[uuid.UUID]domain.RegulationSummary
+	bookmarks   map[uuid.UUID]bool
+	mostUsed    []domain.RegulationSummary
+}
+
+func (f *fakeBookmarksRegulationService) AddBookmark(ctx context.Context, userID, regulationID uuid.UUID) error {
+	if _, ok := f.regulations[regulationID]; !ok {
+		return domain.NotFound("regulation.get", "regulation", regulationID.String())
+	}
+	f.bookmarks[regulationID] = true
+	return nil
+}
+
+func (f *fakeBookmarksRegulationService) RemoveBookmark(ctx context.Context, userID, regulationID uuid.UUID) error {
+	delete(f.bookmarks, regulationID)
+	return nil
+}
+
+func (f *fakeBookmarksRegulationService) ListBookmarks(ctx context.Context, userID uuid.UUID) ([]domain.RegulationSummary, error) {
+	var bookmarked []domain.RegulationSummary
+	for id := range f.bookmarks {
+		bookmarked = append(bookmarked, f.regulations[id])
+	}
+	return bookmarked, nil
+}
+
+func (f *fakeBookmarksRegulationService) ListBookmarkIDs(ctx context.Context, userID uuid.UUID) (map[uuid.UUID]bool, error) {
+	return f.bookmarks, nil
+}
+
+func (f *fakeBookmarksRegulationService) ListMostUsed(ctx context.Context, userID uuid.UUID, limit int32) ([]domain.RegulationSummary, error) {
+	return f.mostUsed, nil
+}
+
+// fakeOwnedViolationService reports every violation as owned by the user.
+type fakeOwnedViolationService struct {
+	service.ViolationService
+}
+
+func (f *fakeOwnedViolationService) GetByID(ctx context.Context, id, userID uuid.UUID) (*domain.Violation, error) {
+	return &domain.Violation{ID: id}, nil
+}
+
+func newBookmarksTestHandler() (*RegulationHandler, *fakeBookmarksRegulationService, domain.RegulationSummary) {
+	guardrails := domain.RegulationSummary{ID: uuid.New(), StandardNumber: "1926.502(b)", Title: "Guardrail systems"}
+	svc := &fakeBookmarksRegulationService{
+		regulations: map[uuid.UUID]domain.RegulationSummary{guardrails.ID: guardrails},
+		bookmarks:   map[uuid.UUID]bool{},
+	}
+	return NewRegulationHandler(svc, &fakeOwnedViolationService{}, newTestLogger()), svc, guardrails
+}
+
+func serveBookmark(h *RegulationHandler, method string, regulationID uuid.UUID) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/regulations/"+regulationID.String()+"/bookmark", nil)
+	req.SetPathValue("id", regulationID.String())
+	req = req.WithContext(auth.SetUser(req.Context(), &domain.User{ID: uuid.New()}))
+	rr := httptest.NewRecorder()
+	if method == http.MethodPost {
+		h.AddBookmark(rr, req)
+	} else {
+		h.RemoveBookmark(rr, req)
+	}
+	return rr
+}
+
+func TestRegulationBookmark_Toggle(t *testing.T) {
+	h, svc, reg := newBookmarksTestHandler()
+
+	rr := serveBookmark(h, http.MethodPost, reg.ID)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if !svc.bookmarks[reg.ID] {
+		t.Error("expected regulation to be bookmarked")
+	}
+	if !strings.Contains(rr.Body.String(), "hx-delete=\"/regulations/"+reg.ID.String()+"/bookmark\"") {
+		t.Errorf("expected a toggle that removes the bookmark, got:\n%s", rr.Body.String())
+	}
+
+	rr = serveBookmark(h, http.MethodDelete, reg.ID)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if svc.bookmarks[reg.ID] {
+		t.Error("expected regulation to be unbookmarked")
+	}
+	if !strings.Contains(rr.Body.String(), "hx-post=\"/regulations/"+reg.ID.String()+"/bookmark\"") {
+		t.Errorf("expected a toggle that adds the bookmark, got:\n%s", rr.Body.String())
+	}
+}
+
+func TestRegulationBookmark_UnknownRegulation(t *testing.T) {
+	h, _, _ := newBookmarksTestHandler()
+
+	rr := serveBookmark(h, http.MethodPost, uuid.New())
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", rr.Code)
+	}
+}
+
+func TestInlineSearch_EmptyQueryShowsShortlist(t *testing.T) {
+	h, svc, reg := newBookmarksTestHandler()
+	svc.bookmarks[reg.ID] = true
+	svc.mostUsed = []domain.RegulationSummary{{ID: uuid.New(), StandardNumber: "1926.451(g)", Title: "Fall protection", UseCount: 7}}
+
+	vid := uuid.New()
+	req := httptest.NewRequest(http.MethodGet, "/violations/"+vid.String()+"/regulations/search?q=", nil)
+	req.SetPathValue("vid", vid.String())
+	req = req.WithContext(auth.SetUser(req.Context(), &domain.User{ID: uuid.New()}))
+	rr := httptest.NewRecorder()
+	h.InlineSearchTempl(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	body := rr.Body.String()
+	bookmarks, mostUsed := strings.Index(body, "Bookmarks"), strings.Index(body, "Most used")
+	if bookmarks < 0 || mostUsed < bookmarks {
+		t.Fatalf("expected bookmarks before most used, got:\n%s", body)
+	}
+	for _, want := range []string{"1926.502(b)", "1926.451(g)"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected shortlist to contain %q", want)
+		}
+	}
+}
+
+func TestRegulationBookmarks_ListPage(t *testing.T) {
+	h, _, reg := newBookmarksTestHandler()
+
+	serve := func() string {
+		req := httptest.NewRequest(http.MethodGet, "/regulations/bookmarks", nil)
+		req = req.WithContext(auth.SetUser(req.Context(), &domain.User{ID: uuid.New()}))
+		rr := httptest.NewRecorder()
+		h.BookmarksTempl(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rr.Code)
+		}
+		return rr.Body.String()
+	}
+
+	if body := serve(); strings.Contains(body, reg.StandardNumber) {
+		t.Errorf("expected no bookmarks before bookmarking, got:\n%s", body)
+	}
+
+	serveBookmark(h, http.MethodPost, reg.ID)
+	body := serve()
+	if !strings.Contains(body, reg.StandardNumber) {
+		t.Errorf("expected bookmarked regulation %q to be listed", reg.StandardNumber)
+	}
+	if !strings.Contains(body, "hx-delete=\"/regulations/"+reg.ID.String()+"/bookmark\"") {
+		t.Error("expected listed regulation to show as bookmarked")
+	}
+}
+
+// fakeFacetRegulationService records the filters it is asked for and returns
+// one page of a larger result set.
+type fakeFacetRegulationService struct {
+	service.RegulationService
+	query  string
+	filter domain.RegulationFilter
+}
+
+func (f *fakeFacetRegulationService) Search(ctx context.Context, query string, filter domain.RegulationFilter, limit, offset int32) (*domain.RegulationSearchResult, error) {
+	f.query, f.filter = query, filter
+	return &domain.RegulationSearchResult{
+		Regulations: []domain.RegulationSummary{{ID: uuid.New(), StandardNumber: "1926.502(b)", Title: "Guardrail systems"}},
+		Total:       45,
+	}, nil
+}
+
+func (f *fakeFacetRegulationService) Browse(ctx context.Context, filter domain.RegulationFilter, limit, offset int32) (*domain.RegulationSearchResult, error) {
+	f.query, f.filter = "", filter
+	return &domain.RegulationSearchResult{
+		Regulations: []domain.RegulationSummary{{ID: uuid.New(), StandardNumber: "1926.502(b)", Title: "Guardrail systems", SeverityTypical: "serious"}},
+		Total:       45,
+	}, nil
+}
+
+func (f *fakeFacetRegulationService) ListFacets(ctx context.Context, query, category string) (*domain.RegulationFacets, error) {
+	return &domain.RegulationFacets{
+		Categories:    []domain.RegulationFacet{{Name: "Fall Protection", Count: 45}, {Name: "Scaffolding", Count: 3}},
+		Subcategories: []domain.RegulationFacet{{Name: "Guardrails & Nets", Count: 45}},
+	}, nil
+}
+
+func (f *fakeFacetRegulationService) ListBookmarkIDs(ctx context.Context, userID uuid.UUID) (map[uuid.UUID]bool, error) {
+	return map[uuid.UUID]bool{}, nil
+}
+
+func TestSearchTempl_FacetsAppliedWithQuery(t *testing.T) {
+	svc := &fakeFacetRegulationService{}
+	h := NewRegulationHandler(svc, &fakeOwnedViolationService{}, newTestLogger())
+
+	req := httptest.NewRequest(http.MethodGet, "/regulations/search?q=guardrail&category=Fall+Protection&subcategory=Guardrails+%26+Nets", nil)
+	req = req.WithContext(auth.SetUser(req.Context(), &domain.User{ID: uuid.New()}))
+	rr := httptest.NewRecorder()
+	h.SearchTempl(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	want := domain.RegulationFilter{Category: "Fall Protection", Subcategory: "Guardrails & Nets"}
+	if svc.query != "guardrail" || svc.filter != want {
+		t.Errorf("expected search for %q in %+v, got %q in %+v", "guardrail", want, svc.query, svc.filter)
+	}
+
+	body := rr.Body.String()
+	for _, want := range []string{
+		// Page 2 keeps the query and both facets
+		`hx-get="/regulations/search?category=Fall+Protection&amp;page=2&amp;q=guardrail&amp;subcategory=Guardrails+%26+Nets"`,
+		// Facet counts are swapped in alongside the results
+		`id="facet-filters"`,
+		`hx-swap-oob="true"`,
+		"Scaffolding (3)",
+		"Guardrails &amp; Nets (45)",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected response to contain %q", want)
+		}
+	}
+}
+
+func TestSearchTempl_SeverityCombinedWithFacets(t *testing.T) {
+	svc := &fakeFacetRegulationService{}
+	h := NewRegulationHandler(svc, &fakeOwnedViolationService{}, newTestLogger())
+
+	req := httptest.NewRequest(http.MethodGet, "/regulations/search?category=Fall+Protection&subcategory=Guardrails+%26+Nets&severity=Serious", nil)
+	req = req.WithContext(auth.SetUser(req.Context(), &domain.User{ID: uuid.New()}))
+	rr := httptest.NewRecorder()
+	h.SearchTempl(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	want := domain.RegulationFilter{Category: "Fall Protection", Subcategory: "Guardrails & Nets", Severity: domain.ViolationSeveritySerious}
+	if svc.filter != want {
+		t.Errorf("expected browse in %+v, got %+v", want, svc.filter)
+	}
+
+	body := rr.Body.String()
+	for _, want := range []string{
+		// The total is shown even on the first page
+		`id="result-count"`,
+		"45 regulations",
+		// Page 2 keeps all three filters
+		`hx-get="/regulations/search?category=Fall+Protection&amp;page=2&amp;severity=serious&amp;subcategory=Guardrails+%26+Nets"`,
+		`<option value="serious" selected>Serious</option>`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected response to contain %q", want)
+		}
+	}
+}
+
+func TestSearchTempl_UnknownSeverityIgnored(t *testing.T) {
+	svc := &fakeFacetRegulationService{}
+	h := NewRegulationHandler(svc, &fakeOwnedViolationService{}, newTestLogger())
+
+	req := httptest.NewRequest(http.MethodGet, "/regulations/search?q=guardrail&severity=extreme", nil)
+	req = req.WithContext(auth.SetUser(req.Context(), &domain.User{ID: uuid.New()}))
+	rr := httptest.NewRecorder()
+	h.SearchTempl(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if svc.filter != (domain.RegulationFilter{}) {
+		t.Errorf("expected unknown severity to be dropped, got %+v", svc.filter)
+	}
+	if strings.Contains(rr.Body.String(), "severity=") {
+		t.Error("expected pagination links without a severity")
+	}
+}
+
+// fakeRecentRegulationService records detail views in memory, most recent
+// first, and browses like fakeFacetRegulationService.
+type fakeRecentRegulationService struct {
+	fakeFacetRegulationService
+	regulations map[uuid.UUID]domain.Regulation
+	recent      []uuid.UUID
+}
+
+func (f *fakeRecentRegulationService) GetByID(ctx context.Context, id uuid.UUID) (*domain.Regulation, error) {
+	reg, ok := f.regulations[id]
+	if !ok {
+		return nil, domain.NotFound("regulation.get", "regulation", id.String())
+	}
+	return &reg, nil
+}
+
+func (f *fakeRecentRegulationService) RecordView(ctx context.Context, userID, regulationID uuid.UUID) error {
+	f.recent = append([]uuid.UUID{regulationID}, slices.DeleteFunc(f.recent, func(id uuid.UUID) bool { return id == regulationID })...)
+	return nil
+}
+
+func (f *fakeRecentRegulationService) ListRecentlyViewed(ctx context.Context, userID uuid.UUID) ([]domain.RegulationSummary, error) {
+	recent := make([]domain.RegulationSummary, len(f.recent))
+	for i, id := range f.recent {
+		recent[i] = domain.RegulationSummary{ID: id, StandardNumber: f.regulations[id].StandardNumber, Title: f.regulations[id].Title}
+	}
+	return recent, nil
+}
+
+func (f *fakeRecentRegulationService) ListBookmarks(ctx context.Context, userID uuid.UUID) ([]domain.RegulationSummary, error) {
+	return nil, nil
+}
+
+func (f *fakeRecentRegulationService) ListMostUsed(ctx context.Context, userID uuid.UUID, limit int32) ([]domain.RegulationSummary, error) {
+	return nil, nil
+}
+
+func TestRegulationDetail_ShowsInRecentlyViewed(t *testing.T) {
+	ladders := domain.Regulation{ID: uuid.New(), StandardNumber: "1926.1053(b)", Title: "Ladder use"}
+	stairs := domain.Regulation{ID: uuid.New(), StandardNumber: "1926.1052(c)", Title: "Stairrails and handrails"}
+	svc := &fakeRecentRegulationService{regulations: map[uuid.UUID]domain.Regulation{ladders.ID: ladders, stairs.ID: stairs}}
+	h := NewRegulationHandler(svc, &fakeOwnedViolationService{}, newTestLogger())
+	user := &domain.User{ID: uuid.New()}
+
+	for _, reg := range []domain.Regulation{ladders, stairs} {
+		req := httptest.NewRequest(http.MethodGet, "/regulations/"+reg.ID.String(), nil)
+		req.SetPathValue("id", reg.ID.String())
+		req = req.WithContext(auth.SetUser(req.Context(), user))
+		rr := httptest.NewRecorder()
+		h.GetDetailTempl(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200 viewing %s, got %d", reg.StandardNumber, rr.Code)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/regulations/search", nil)
+	req = req.WithContext(auth.SetUser(req.Context(), user))
+	rr := httptest.NewRecorder()
+	h.SearchTempl(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	body := rr.Body.String()
+	heading, all := strings.Index(body, "Recently viewed"), strings.Index(body, "All regulations")
+	if heading < 0 || all < heading {
+		t.Fatalf("expected a recently viewed section above the results, got:\n%s", body)
+	}
+	// The latest view comes first
+	recent := body[heading:all]
+	first, second := strings.Index(recent, stairs.StandardNumber), strings.Index(recent, ladders.StandardNumber)
+	if first < 0 || second < first {
+		t.Errorf("expected %s before %s in recently viewed, got:\n%s", stairs.StandardNumber, ladders.StandardNumber, recent)
+	}
+}
+
+// fakeSuggestionRegulationService returns search results in rank order and
+// records the query it was given.
+type fakeSuggestionRegulationService struct {
+	service.RegulationService
+	results []domain.RegulationSummary
+	query   string
+}
+
+func (f *fakeSuggestionRegulationService) Search(ctx context.Context, query string, filter domain.RegulationFilter, limit, offset int32) (*domain.RegulationSearchResult, error) {
+	f.query = query
+	return &domain.RegulationSearchResult{Regulations: f.results, Total: int64(len(f.results))}, nil
+}
+
+func (f *fakeSuggestionRegulationService) ListBookmarkIDs(ctx context.Context, userID uuid.UUID) (map[uuid.UUID]bool, error) {
+	return map[uuid.UUID]bool{}, nil
+}
+
+// fakeLinkedViolationService returns a violation with the given links.
+type fakeLinkedViolationService struct {
+	service.ViolationService
+	violation domain.Violation
+	linked    []domain.ViolationRegulation
+}
+
+func (f *fakeLinkedViolationService) GetByIDWithRegulations(ctx context.Context, id, userID uuid.UUID) (*domain.Violation, []domain.ViolationRegulation, error) {
+	v := f.violation
+	v.ID = id
+	return &v, f.linked, nil
+}
+
+func TestSuggestedRegulations_RankedAndMarksLinked(t *testing.T) {
+	guardrails := domain.RegulationSummary{ID: uuid.New(), StandardNumber: "1926.502(b)", Title: "Guardrail systems", Rank: 0.9}
+	edges := domain.RegulationSummary{ID: uuid.New(), StandardNumber: "1926.501(b)(1)", Title: "Unprotected sides and edges", Rank: 0.6}
+	training := domain.RegulationSummary{ID: uuid.New(), StandardNumber: "1926.503(a)", Title: "Training program", Rank: 0.2}
+	regSvc := &fakeSuggestionRegulationService{results: []domain.RegulationSummary{guardrails, edges, training}}
+	violationSvc := &fakeLinkedViolationService{
+		violation: domain.Violation{AIDescription: "Missing guardrail along roof edge"},
+		linked:    []domain.ViolationRegulation{{RegulationID: edges.ID}},
+	}
+	h := NewRegulationHandler(regSvc, violationSvc, newTestLogger())
+
+	vid := uuid.New()
+	req := httptest.NewRequest(http.MethodGet, "/violations/"+vid.String()+"/suggested-regulations", nil)
+	req.SetPathValue("vid", vid.String())
+	req = req.WithContext(auth.SetUser(req.Context(), &domain.User{ID: uuid.New()}))
+	rr := httptest.NewRecorder()
+	h.SuggestedRegulationsTempl(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if regSvc.query != "missing or guardrail or roof or edge" {
+		t.Errorf("expected keyword query from the AI description, got %q", regSvc.query)
+	}
+
+	body := rr.Body.String()
+	first, second, third := strings.Index(body, guardrails.StandardNumber), strings.Index(body, edges.StandardNumber), strings.Index(body, training.StandardNumber)
+	if first < 0 || second < first || third < second {
+		t.Fatalf("expected suggestions in rank order, got:\n%s", body)
+	}
+
+	addURL := func(reg domain.RegulationSummary) string {
+		return "hx-post=\"/violations/" + vid.String() + "/regulations/" + reg.ID.String() + "\""
+	}
+	if !strings.Contains(body, addURL(guardrails)) || !strings.Contains(body, addURL(training)) {
+		t.Error("expected unlinked suggestions to offer Add")
+	}
+	if strings.Contains(body, addURL(edges)) {
+		t.Error("expected already-linked suggestion not to offer Add")
+	}
+	if !strings.Contains(body[second:third], "Linked") {
+		t.Error("expected already-linked suggestion to be marked")
+	}
+}
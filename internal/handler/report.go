@@ -4,14 +4,19 @@
 package handler
 
 import (
+	"bytes"
+	"encoding/csv"
 	"fmt"
+	"html"
 	"io"
 	"log/slog"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/DukeRupert/lukaut/internal/auth"
 	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/report"
 	"github.com/DukeRupert/lukaut/internal/service"
 	"github.com/DukeRupert/lukaut/internal/storage"
 	reporttempl "github.com/DukeRupert/lukaut/internal/templ/report"
@@ -22,6 +27,7 @@ import (
 type ReportHandler struct {
 	reportService service.ReportService
 	storage       storage.Storage
+	pdfConverter  report.Converter // Renders violations summaries to PDF
 	logger        *slog.Logger
 }
 
@@ -34,6 +40,7 @@ func NewReportHandler(
 	return &ReportHandler{
 		reportService: reportService,
 		storage:       storage,
+		pdfConverter:  report.NewWeasyPrintConverter(),
 		logger:        logger,
 	}
 }
@@ -68,7 +75,7 @@ func (h *ReportHandler) Download(w http.ResponseWriter, r *http.Request) {
 	// Fetch report with user authorization via service
 	report, err := h.reportService.GetByID(r.Context(), id, user.ID)
 	if err != nil {
-		h.logger.Error("failed to fetch report", "error", err, "report_id", id)
+		h.logger.ErrorContext(r.Context(), "failed to fetch report", "error", err, "report_id", id)
 		http.Error(w, "Report not found", http.StatusNotFound)
 		return
 	}
@@ -88,8 +95,7 @@ func (h *ReportHandler) Download(w http.ResponseWriter, r *http.Request) {
 	// Fetch from storage
 	reader, info, err := h.storage.Get(r.Context(), storageKey)
 	if err != nil {
-		h.logger.Error("failed to fetch report from storage", "error", err, "storage_key", storageKey)
-		http.Error(w, "Failed to retrieve report", http.StatusInternalServerError)
+		ServerErrorResponse(w, r, h.logger.With("storage_key", storageKey), fmt.Errorf("fetch report from storage: %w", err))
 		return
 	}
 	defer func() { _ = reader.Close() }()
@@ -105,11 +111,11 @@ func (h *ReportHandler) Download(w http.ResponseWriter, r *http.Request) {
 	// Stream the file
 	_, err = io.Copy(w, reader)
 	if err != nil {
-		h.logger.Error("failed to stream report", "error", err, "report_id", id)
+		h.logger.ErrorContext(r.Context(), "failed to stream report", "error", err, "report_id", id)
 		return
 	}
 
-	h.logger.Info("Report downloaded",
+	h.logger.InfoContext(r.Context(), "Report downloaded",
 		"report_id", id,
 		"user_id", user.ID,
 		"format", format,
@@ -146,7 +152,7 @@ func (h *ReportHandler) GetDownloadURL(w http.ResponseWriter, r *http.Request) {
 	// Fetch report with user authorization via service
 	report, err := h.reportService.GetByID(r.Context(), id, user.ID)
 	if err != nil {
-		h.logger.Error("failed to fetch report", "error", err, "report_id", id)
+		h.logger.ErrorContext(r.Context(), "failed to fetch report", "error", err, "report_id", id)
 		http.Error(w, "Report not found", http.StatusNotFound)
 		return
 	}
@@ -166,8 +172,7 @@ func (h *ReportHandler) GetDownloadURL(w http.ResponseWriter, r *http.Request) {
 	// Generate presigned URL (valid for 1 hour)
 	url, err := h.storage.URL(r.Context(), storageKey, time.Hour)
 	if err != nil {
-		h.logger.Error("failed to generate presigned URL", "error", err, "storage_key", storageKey)
-		http.Error(w, "Failed to generate download URL", http.StatusInternalServerError)
+		ServerErrorResponse(w, r, h.logger.With("storage_key", storageKey), fmt.Errorf("generate presigned URL: %w", err))
 		return
 	}
 
@@ -195,8 +200,7 @@ func (h *ReportHandler) ListByInspection(w http.ResponseWriter, r *http.Request)
 	// Fetch reports for this inspection via service (already filtered by user)
 	reports, err := h.reportService.ListByInspection(r.Context(), id, user.ID)
 	if err != nil {
-		h.logger.Error("failed to list reports", "error", err, "inspection_id", id)
-		http.Error(w, "Failed to list reports", http.StatusInternalServerError)
+		ServerErrorResponse(w, r, h.logger.With("inspection_id", id), fmt.Errorf("list reports: %w", err))
 		return
 	}
 
@@ -210,10 +214,25 @@ func (h *ReportHandler) ListByInspection(w http.ResponseWriter, r *http.Request)
 	// Add report-ready class to signal polling should stop
 	_, _ = fmt.Fprint(w, `<div class="space-y-2 report-ready">`)
 	for _, report := range reports {
+		if report.IsFailed() {
+			_, _ = fmt.Fprintf(w, `<div class="flex items-center justify-between bg-red-50 p-3 rounded-md">`)
+			_, _ = fmt.Fprintf(w, `<div>`)
+			_, _ = fmt.Fprintf(w, `<span class="text-sm font-medium text-red-800">Generation failed — retry</span>`)
+			_, _ = fmt.Fprintf(w, `<span class="ml-2 text-xs text-red-600">%s</span>`, report.GeneratedAt.Format("Jan 2, 2006 3:04 PM"))
+			_, _ = fmt.Fprintf(w, `</div>`)
+			if report.FailedFormat.IsValid() {
+				_, _ = fmt.Fprintf(w, `<button type="button" hx-post="/inspections/%s/reports" hx-vals='{"format": "%s"}' hx-encoding="multipart/form-data" hx-target="#report-message" hx-swap="innerHTML" class="inline-flex items-center rounded-md bg-white px-2 py-1 text-xs font-medium text-red-700 ring-1 ring-inset ring-red-600/20 hover:bg-red-100">Retry</button>`, id, report.FailedFormat)
+			}
+			_, _ = fmt.Fprintf(w, `</div>`)
+			continue
+		}
 		_, _ = fmt.Fprintf(w, `<div class="flex items-center justify-between bg-gray-50 p-3 rounded-md">`)
 		_, _ = fmt.Fprintf(w, `<div>`)
 		_, _ = fmt.Fprintf(w, `<span class="text-sm font-medium text-gray-900">Report generated %s</span>`, report.GeneratedAt.Format("Jan 2, 2006 3:04 PM"))
 		_, _ = fmt.Fprintf(w, `<span class="ml-2 text-xs text-gray-500">%d violations</span>`, report.ViolationCount)
+		if len(report.RecipientEmails) > 0 {
+			_, _ = fmt.Fprintf(w, `<p class="mt-1 text-xs text-gray-500">Sent to %s</p>`, html.EscapeString(strings.Join(report.RecipientEmails, ", ")))
+		}
 		_, _ = fmt.Fprintf(w, `</div>`)
 		_, _ = fmt.Fprintf(w, `<div class="flex gap-2">`)
 		if report.HasPDF() {
@@ -228,7 +247,10 @@ func (h *ReportHandler) ListByInspection(w http.ResponseWriter, r *http.Request)
 	_, _ = fmt.Fprint(w, `</div>`)
 }
 
-// Preview renders an HTML preview of the report without generating PDF/DOCX.
+// Preview shows the inspection's report as a PDF in the browser, so
+// inspectors can check it before sending it to a client. Small reports are
+// rendered on the spot without creating a report record; larger ones show the
+// latest generated report, or offer to generate one with GeneratePreview.
 // GET /inspections/{id}/reports/preview
 func (h *ReportHandler) Preview(w http.ResponseWriter, r *http.Request) {
 	user := auth.GetUserFromRequest(r)
@@ -245,31 +267,206 @@ func (h *ReportHandler) Preview(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Prepare report data via service
-	reportData, err := h.reportService.PrepareReportData(r.Context(), inspectionID, user.ID)
+	// Render or find the preview via service (enforces the review/completed status gate)
+	preview, err := h.reportService.Preview(r.Context(), inspectionID, user.ID)
 	if err != nil {
-		h.logger.Error("failed to prepare report data for preview", "error", err, "inspection_id", inspectionID)
-		http.Error(w, "Failed to load report data", http.StatusInternalServerError)
+		code := domain.ErrorCode(err)
+		if code == domain.EINTERNAL {
+			ServerErrorResponse(w, r, h.logger.With("inspection_id", inspectionID), fmt.Errorf("preview report: %w", err))
+			return
+		}
+		http.Error(w, domain.ErrorMessage(err), ErrorCodeToHTTPStatus(code))
 		return
 	}
 
-	// Render HTML template
-	templateData := &reporttempl.ReportTemplateData{
-		ReportData: reportData,
+	if preview.IsTooLarge() {
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := reporttempl.PreviewTooLarge(inspectionID.String(), preview.ViolationCount, domain.MaxInlinePreviewViolations).Render(r.Context(), w); err != nil {
+			h.logger.ErrorContext(r.Context(), "failed to render too large preview page", "error", err, "inspection_id", inspectionID)
+		}
+		return
 	}
 
+	// Previews change as violations are reviewed, so they are never cached
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Type", domain.ReportFormatPDF.ContentType())
+
+	if preview.PDF != nil {
+		w.Header().Set("Content-Length", fmt.Sprintf("%d", len(preview.PDF)))
+		w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=\"preview-%s.pdf\"", inspectionID.String()[:8]))
+		_, _ = w.Write(preview.PDF)
+		return
+	}
+
+	reader, info, err := h.storage.Get(r.Context(), preview.Report.PDFStorageKey)
+	if err != nil {
+		ServerErrorResponse(w, r, h.logger.With("storage_key", preview.Report.PDFStorageKey), fmt.Errorf("fetch report from storage: %w", err))
+		return
+	}
+	defer func() { _ = reader.Close() }()
+
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", info.Size))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=\"report-%s.pdf\"", preview.Report.ID.String()[:8]))
+	if _, err := io.Copy(w, reader); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to stream report preview", "error", err, "report_id", preview.Report.ID)
+	}
+}
+
+// GeneratePreview generates the PDF report of an inspection too large to
+// preview inline in the background. The user is emailed when it is ready.
+// POST /inspections/{id}/reports/preview
+func (h *ReportHandler) GeneratePreview(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUserFromRequest(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Parse inspection ID
+	idStr := r.PathValue("id")
+	inspectionID, err := uuid.Parse(idStr)
+	if err != nil {
+		http.Error(w, "Invalid inspection ID", http.StatusBadRequest)
+		return
+	}
+
+	jobID, err := h.reportService.TriggerGeneration(r.Context(), inspectionID, user.ID, domain.ReportFormatPDF.String(), nil)
+	if err != nil {
+		code := domain.ErrorCode(err)
+		if code == domain.EINTERNAL {
+			ServerErrorResponse(w, r, h.logger.With("inspection_id", inspectionID), fmt.Errorf("generate previewed report: %w", err))
+			return
+		}
+		http.Error(w, domain.ErrorMessage(err), ErrorCodeToHTTPStatus(code))
+		return
+	}
+
+	h.logger.InfoContext(r.Context(), "Report preview too large to render inline, generating in background",
+		"inspection_id", inspectionID,
+		"user_id", user.ID,
+		"job_id", jobID,
+	)
+
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-	if err := reporttempl.Report(templateData).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render report preview", "error", err, "inspection_id", inspectionID)
-		http.Error(w, "Failed to render preview", http.StatusInternalServerError)
+	w.WriteHeader(http.StatusAccepted)
+	if err := reporttempl.PreviewQueued(inspectionID.String(), domain.MaxInlinePreviewViolations).Render(r.Context(), w); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to render queued preview page", "error", err, "inspection_id", inspectionID)
+	}
+}
+
+// Summary exports violation counts by severity and status across the
+// inspections of the user and their organization dated within a range.
+// GET /reports/summary?from=YYYY-MM-DD&to=YYYY-MM-DD&format=csv|pdf
+func (h *ReportHandler) Summary(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUserFromRequest(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	query := r.URL.Query()
+	format := query.Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "pdf" {
+		http.Error(w, "Invalid format: must be 'csv' or 'pdf'", http.StatusBadRequest)
+		return
+	}
+
+	from, to, err := domain.ParseSummaryRange(query.Get("from"), query.Get("to"))
+	if err != nil {
+		http.Error(w, domain.ErrorMessage(err), http.StatusBadRequest)
+		return
+	}
+
+	summary, err := h.reportService.Summary(r.Context(), user.ID, from, to)
+	if err != nil {
+		code := domain.ErrorCode(err)
+		if code == domain.EINTERNAL {
+			ServerErrorResponse(w, r, h.logger, fmt.Errorf("summarize violations: %w", err))
+			return
+		}
+		http.Error(w, domain.ErrorMessage(err), ErrorCodeToHTTPStatus(code))
+		return
+	}
+
+	// Build the whole file before writing so a failure can still be reported
+	var buf bytes.Buffer
+	contentType := "text/csv; charset=utf-8"
+	if format == "pdf" {
+		var html bytes.Buffer
+		if err := reporttempl.Summary(summary).Render(r.Context(), &html); err != nil {
+			ServerErrorResponse(w, r, h.logger, fmt.Errorf("render violations summary: %w", err))
+			return
+		}
+		if err := h.pdfConverter.Convert(r.Context(), html.Bytes(), &buf); err != nil {
+			ServerErrorResponse(w, r, h.logger, fmt.Errorf("convert violations summary to pdf: %w", err))
+			return
+		}
+		contentType = domain.ReportFormatPDF.ContentType()
+	} else if err := writeSummaryCSV(&buf, summary); err != nil {
+		ServerErrorResponse(w, r, h.logger, fmt.Errorf("write violations summary csv: %w", err))
 		return
 	}
+
+	filename := fmt.Sprintf("violations-summary-%s-to-%s.%s",
+		from.Format(domain.SummaryDateLayout), to.Format(domain.SummaryDateLayout), format)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", buf.Len()))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	_, _ = w.Write(buf.Bytes())
+}
+
+// writeSummaryCSV writes a summary as a table with a row per severity, a
+// column per status, and totals in the last row and column.
+func writeSummaryCSV(w io.Writer, summary *domain.ViolationSummary) error {
+	cw := csv.NewWriter(w)
+
+	header := []string{"severity"}
+	for _, status := range domain.SummaryStatuses {
+		header = append(header, string(status))
+	}
+	header = append(header, "total")
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, severity := range domain.SummarySeverities {
+		name := string(severity)
+		if severity == "" {
+			name = "unrated"
+		}
+		record := []string{name}
+		for _, status := range domain.SummaryStatuses {
+			record = append(record, fmt.Sprintf("%d", summary.Count(severity, status)))
+		}
+		record = append(record, fmt.Sprintf("%d", summary.SeverityTotal(severity)))
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	totals := []string{"total"}
+	for _, status := range domain.SummaryStatuses {
+		totals = append(totals, fmt.Sprintf("%d", summary.StatusTotal(status)))
+	}
+	totals = append(totals, fmt.Sprintf("%d", summary.Total()))
+	if err := cw.Write(totals); err != nil {
+		return err
+	}
+
+	cw.Flush()
+	return cw.Error()
 }
 
 // RegisterRoutes registers report routes on the provided ServeMux.
 func (h *ReportHandler) RegisterRoutes(mux *http.ServeMux, requireUser func(http.Handler) http.Handler) {
+	mux.Handle("GET /reports/summary", requireUser(http.HandlerFunc(h.Summary)))
 	mux.Handle("GET /reports/{id}/download", requireUser(http.HandlerFunc(h.Download)))
 	mux.Handle("GET /reports/{id}/url", requireUser(http.HandlerFunc(h.GetDownloadURL)))
 	mux.Handle("GET /inspections/{id}/reports", requireUser(http.HandlerFunc(h.ListByInspection)))
 	mux.Handle("GET /inspections/{id}/reports/preview", requireUser(http.HandlerFunc(h.Preview)))
+	mux.Handle("POST /inspections/{id}/reports/preview", requireUser(http.HandlerFunc(h.GeneratePreview)))
 }
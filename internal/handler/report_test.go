@@ -0,0 +1,546 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/auth"
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/report"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/DukeRupert/lukaut/internal/service"
+	"github.com/DukeRupert/lukaut/internal/storage"
+	"github.com/DukeRupert/lukaut/internal/testutil/fakedb"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// Mock ReportService Implementation
+// =============================================================================
+
+// mockReportService implements the service.ReportService interface for testing.
+type mockReportService struct {
+	PrepareReportDataFunc func(ctx context.Context, inspectionID, userID uuid.UUID) (*domain.ReportData, error)
+	PreviewReportDataFunc func(ctx context.Context, inspectionID, userID uuid.UUID) (*domain.ReportData, error)
+	PreviewFunc           func(ctx context.Context, inspectionID, userID uuid.UUID) (*domain.ReportPreview, error)
+	RenderFunc            func(ctx context.Context, data *domain.ReportData, format domain.ReportFormat, w io.Writer) (int64, error)
+	GetByIDFunc           func(ctx context.Context, id, userID uuid.UUID) (*domain.Report, error)
+	ListByInspectionFunc  func(ctx context.Context, inspectionID, userID uuid.UUID) ([]domain.Report, error)
+	TriggerGenerationFunc func(ctx context.Context, inspectionID, userID uuid.UUID, format string, recipientEmails []string) (uuid.UUID, error)
+	SummaryFunc           func(ctx context.Context, userID uuid.UUID, from, to time.Time) (*domain.ViolationSummary, error)
+
+	// Track calls that would persist a report
+	TriggerGenerationCalled bool
+}
+
+func (m *mockReportService) PrepareReportData(ctx context.Context, inspectionID, userID uuid.UUID) (*domain.ReportData, error) {
+	if m.PrepareReportDataFunc != nil {
+		return m.PrepareReportDataFunc(ctx, inspectionID, userID)
+	}
+	return nil, errors.New("PrepareReportDataFunc not implemented")
+}
+
+func (m *mockReportService) PreviewReportData(ctx context.Context, inspectionID, userID uuid.UUID) (*domain.ReportData, error) {
+	if m.PreviewReportDataFunc != nil {
+		return m.PreviewReportDataFunc(ctx, inspectionID, userID)
+	}
+	return nil, errors.New("PreviewReportDataFunc not implemented")
+}
+
+func (m *mockReportService) Preview(ctx context.Context, inspectionID, userID uuid.UUID) (*domain.ReportPreview, error) {
+	if m.PreviewFunc != nil {
+		return m.PreviewFunc(ctx, inspectionID, userID)
+	}
+	return nil, errors.New("PreviewFunc not implemented")
+}
+
+func (m *mockReportService) Render(ctx context.Context, data *domain.ReportData, format domain.ReportFormat, w io.Writer) (int64, error) {
+	if m.RenderFunc != nil {
+		return m.RenderFunc(ctx, data, format, w)
+	}
+	return 0, errors.New("RenderFunc not implemented")
+}
+
+func (m *mockReportService) GetByID(ctx context.Context, id, userID uuid.UUID) (*domain.Report, error) {
+	if m.GetByIDFunc != nil {
+		return m.GetByIDFunc(ctx, id, userID)
+	}
+	return nil, errors.New("GetByIDFunc not implemented")
+}
+
+func (m *mockReportService) ListByInspection(ctx context.Context, inspectionID, userID uuid.UUID) ([]domain.Report, error) {
+	if m.ListByInspectionFunc != nil {
+		return m.ListByInspectionFunc(ctx, inspectionID, userID)
+	}
+	return nil, errors.New("ListByInspectionFunc not implemented")
+}
+
+func (m *mockReportService) TriggerGeneration(ctx context.Context, inspectionID, userID uuid.UUID, format string, recipientEmails []string) (uuid.UUID, error) {
+	m.TriggerGenerationCalled = true
+	if m.TriggerGenerationFunc != nil {
+		return m.TriggerGenerationFunc(ctx, inspectionID, userID, format, recipientEmails)
+	}
+	return uuid.New(), nil
+}
+
+func (m *mockReportService) Summary(ctx context.Context, userID uuid.UUID, from, to time.Time) (*domain.ViolationSummary, error) {
+	if m.SummaryFunc != nil {
+		return m.SummaryFunc(ctx, userID, from, to)
+	}
+	return nil, errors.New("SummaryFunc not implemented")
+}
+
+// =============================================================================
+// Mock Storage Implementation
+// =============================================================================
+
+// mockStorage implements storage.Storage, records writes, and serves the
+// contents of Objects.
+type mockStorage struct {
+	PutKeys []string
+	Objects map[string][]byte
+}
+
+func (m *mockStorage) Put(ctx context.Context, key string, data io.Reader, opts storage.PutOptions) error {
+	m.PutKeys = append(m.PutKeys, key)
+	return nil
+}
+
+func (m *mockStorage) Get(ctx context.Context, key string) (io.ReadCloser, storage.ObjectInfo, error) {
+	data, ok := m.Objects[key]
+	if !ok {
+		return nil, storage.ObjectInfo{}, storage.ErrNotFound
+	}
+	return io.NopCloser(bytes.NewReader(data)), storage.ObjectInfo{Key: key, Size: int64(len(data))}, nil
+}
+
+func (m *mockStorage) Delete(ctx context.Context, key string) error {
+	return nil
+}
+
+func (m *mockStorage) URL(ctx context.Context, key string, expires time.Duration) (string, error) {
+	return "", storage.ErrNotFound
+}
+
+func (m *mockStorage) Exists(ctx context.Context, key string) (bool, error) {
+	return false, nil
+}
+
+// =============================================================================
+// Test Helpers
+// =============================================================================
+
+func newTestReportHandler(svc *mockReportService, store *mockStorage) *ReportHandler {
+	return NewReportHandler(svc, store, newTestLogger())
+}
+
+// newPreviewRequest builds an authenticated preview request for the inspection.
+func newPreviewRequest(inspectionID uuid.UUID) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/inspections/"+inspectionID.String()+"/reports/preview", nil)
+	req.SetPathValue("id", inspectionID.String())
+	user := &domain.User{ID: uuid.New(), Email: "inspector@example.com"}
+	return req.WithContext(auth.SetUser(req.Context(), user))
+}
+
+// =============================================================================
+// Preview Tests
+// =============================================================================
+
+func TestPreview_RejectsInspectionNotReadyForReport(t *testing.T) {
+	svc := &mockReportService{
+		PreviewFunc: func(ctx context.Context, inspectionID, userID uuid.UUID) (*domain.ReportPreview, error) {
+			return nil, domain.Invalid("report.preview", "Inspection must be in 'review' or 'completed' status to preview a report")
+		},
+	}
+	store := &mockStorage{}
+	h := newTestReportHandler(svc, store)
+
+	rr := httptest.NewRecorder()
+	h.Preview(rr, newPreviewRequest(uuid.New()))
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), "'review' or 'completed'") {
+		t.Errorf("expected status gate message, got %q", rr.Body.String())
+	}
+}
+
+func TestPreview_NotFound(t *testing.T) {
+	svc := &mockReportService{
+		PreviewFunc: func(ctx context.Context, inspectionID, userID uuid.UUID) (*domain.ReportPreview, error) {
+			return nil, domain.NotFound("report.preview", "inspection", inspectionID.String())
+		},
+	}
+	h := newTestReportHandler(svc, &mockStorage{})
+
+	rr := httptest.NewRecorder()
+	h.Preview(rr, newPreviewRequest(uuid.New()))
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", rr.Code)
+	}
+}
+
+// fakePreviewDB answers the queries preparing the report of one inspection
+// in review, with no violations, and counts the report rows written and the
+// jobs queued.
+type fakePreviewDB struct {
+	inspectionID uuid.UUID
+	reports      int // Report rows written
+	jobs         int // Job rows written
+}
+
+func (f *fakePreviewDB) Query(q fakedb.Query) (*fakedb.Rows, error) {
+	switch q.Name {
+	case "GetInspectionOwnerIDForUser":
+		// Not shared with an organization: the user is the owner
+		return &fakedb.Rows{Columns: 1}, nil
+	case "GetInspectionByIDAndUserID":
+		values := make([]driver.Value, 23)
+		values[0], values[1], values[2], values[3] = f.inspectionID.String(), q.Arg(1), "Site walk", string(domain.InspectionStatusReview)
+		values[4], values[8], values[9] = time.Now(), time.Now(), time.Now()
+		values[10], values[12], values[13], values[14] = "1 Main St", "Springfield", "IL", "62701"
+		values[18], values[20], values[22] = int64(1), false, false
+		return &fakedb.Rows{Columns: 23, Values: [][]driver.Value{values}}, nil
+	case "GetInspectionWithClientByIDAndUserID":
+		values := make([]driver.Value, 22)
+		values[0], values[1], values[3], values[4] = f.inspectionID.String(), q.Arg(1), "Site walk", string(domain.InspectionStatusReview)
+		values[5], values[9], values[11], values[12], values[13] = time.Now(), "1 Main St", "Springfield", "IL", "62701"
+		values[16], values[18], values[20], values[21] = int64(1), false, "", ""
+		return &fakedb.Rows{Columns: 22, Values: [][]driver.Value{values}}, nil
+	case "GetUserByID":
+		values := make([]driver.Value, 29)
+		values[0], values[1], values[2], values[3] = q.Arg(0), "inspector@example.com", "hash", "Ida Inspector"
+		values[26] = int64(0)
+		return &fakedb.Rows{Columns: 29, Values: [][]driver.Value{values}}, nil
+	case "ListConfirmedViolationsByInspectionIDAndUserID":
+		return &fakedb.Rows{Columns: 14}, nil
+	case "GetReportSettings":
+		return &fakedb.Rows{Columns: 5}, nil
+	case "CreateReport", "CreateFailedReport":
+		f.reports++
+		return nil, errors.New("fakePreviewDB: report rows are not served")
+	}
+	return nil, fmt.Errorf("fakePreviewDB: unexpected query %q", q.Name)
+}
+
+// The fake is also the report service's job enqueuer, counting queued jobs.
+
+func (f *fakePreviewDB) EnqueueAnalyzeInspection(context.Context, uuid.UUID, uuid.UUID) (repository.Job, error) {
+	f.jobs++
+	return repository.Job{ID: uuid.New()}, nil
+}
+
+func (f *fakePreviewDB) EnqueueAnalyzeImages(context.Context, uuid.UUID, uuid.UUID, []uuid.UUID, bool) (repository.Job, error) {
+	f.jobs++
+	return repository.Job{ID: uuid.New()}, nil
+}
+
+func (f *fakePreviewDB) EnqueueGenerateReport(context.Context, uuid.UUID, uuid.UUID, string, []string) (repository.Job, error) {
+	f.jobs++
+	return repository.Job{ID: uuid.New()}, nil
+}
+
+// stubWeasyPrint puts a weasyprint on the PATH that writes the report's HTML
+// as its PDF.
+func stubWeasyPrint(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "weasyprint"), []byte("#!/bin/sh\ncp \"$1\" \"$2\"\n"), 0o755); err != nil {
+		t.Fatalf("write weasyprint stub: %v", err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestPreview_RendersPDFInlineWithoutPersistingReport(t *testing.T) {
+	stubWeasyPrint(t)
+	inspectionID := uuid.New()
+	f := &fakePreviewDB{inspectionID: inspectionID}
+	queries := repository.New(fakedb.Open(f))
+	store := &mockStorage{}
+	svc := service.NewReportService(queries, store, f, nil, nil, nil, report.DefaultBranding(), newTestLogger())
+	h := NewReportHandler(svc, store, newTestLogger())
+
+	rr := httptest.NewRecorder()
+	h.Preview(rr, newPreviewRequest(inspectionID))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "application/pdf" {
+		t.Errorf("expected PDF content type, got %q", ct)
+	}
+	if cd := rr.Header().Get("Content-Disposition"); !strings.HasPrefix(cd, "inline;") {
+		t.Errorf("expected inline content disposition, got %q", cd)
+	}
+	if !strings.Contains(rr.Body.String(), "Site walk") {
+		t.Error("expected the inspection's report rendered")
+	}
+	if f.reports != 0 {
+		t.Errorf("expected preview not to write a report row, got %d", f.reports)
+	}
+	if f.jobs != 0 {
+		t.Errorf("expected preview not to write a job row, got %d", f.jobs)
+	}
+	if len(store.PutKeys) != 0 {
+		t.Errorf("expected preview not to write to storage, got keys %v", store.PutKeys)
+	}
+}
+
+func TestPreview_StreamsLatestReportInline(t *testing.T) {
+	report := &domain.Report{ID: uuid.New(), PDFStorageKey: "inspections/x/reports/y.pdf", GeneratedAt: time.Now()}
+	svc := &mockReportService{
+		PreviewFunc: func(ctx context.Context, inspectionID, userID uuid.UUID) (*domain.ReportPreview, error) {
+			return &domain.ReportPreview{Report: report}, nil
+		},
+	}
+	store := &mockStorage{Objects: map[string][]byte{report.PDFStorageKey: []byte("%PDF-1.7 stored")}}
+	h := newTestReportHandler(svc, store)
+
+	rr := httptest.NewRecorder()
+	h.Preview(rr, newPreviewRequest(uuid.New()))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if cd := rr.Header().Get("Content-Disposition"); !strings.HasPrefix(cd, "inline;") {
+		t.Errorf("expected inline content disposition, got %q", cd)
+	}
+	if rr.Body.String() != "%PDF-1.7 stored" {
+		t.Errorf("expected the stored report, got %q", rr.Body.String())
+	}
+}
+
+func TestPreview_LargeReportOffersGenerationWithoutQueueing(t *testing.T) {
+	inspectionID := uuid.New()
+	svc := &mockReportService{
+		PreviewFunc: func(ctx context.Context, inspectionID, userID uuid.UUID) (*domain.ReportPreview, error) {
+			return &domain.ReportPreview{ViolationCount: 40}, nil
+		},
+	}
+	h := newTestReportHandler(svc, &mockStorage{})
+
+	rr := httptest.NewRecorder()
+	h.Preview(rr, newPreviewRequest(inspectionID))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	if ct := rr.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Errorf("expected HTML content type, got %q", ct)
+	}
+	body := rr.Body.String()
+	if !strings.Contains(body, "too large for an inline preview") || !strings.Contains(body, `method="post" action="/inspections/`+inspectionID.String()+`/reports/preview"`) {
+		t.Errorf("expected the too large notice with a generate button, got %s", body)
+	}
+	if svc.TriggerGenerationCalled {
+		t.Error("expected viewing the preview not to queue a report")
+	}
+}
+
+func TestGeneratePreview_QueuesPDFReport(t *testing.T) {
+	inspectionID := uuid.New()
+	var format string
+	svc := &mockReportService{
+		TriggerGenerationFunc: func(ctx context.Context, id, userID uuid.UUID, f string, recipientEmails []string) (uuid.UUID, error) {
+			format = f
+			return uuid.New(), nil
+		},
+	}
+	h := newTestReportHandler(svc, &mockStorage{})
+
+	req := newPreviewRequest(inspectionID)
+	req.Method = http.MethodPost
+	rr := httptest.NewRecorder()
+	h.GeneratePreview(rr, req)
+
+	if rr.Code != http.StatusAccepted {
+		t.Fatalf("expected status 202, got %d", rr.Code)
+	}
+	if format != "pdf" {
+		t.Errorf("expected a PDF report queued, got %q", format)
+	}
+	body := rr.Body.String()
+	if !strings.Contains(body, "being generated") || !strings.Contains(body, "/inspections/"+inspectionID.String()) {
+		t.Errorf("expected the background generation notice with a link back, got %s", body)
+	}
+}
+
+func TestGeneratePreview_QuotaExceeded(t *testing.T) {
+	svc := &mockReportService{
+		TriggerGenerationFunc: func(ctx context.Context, id, userID uuid.UUID, f string, recipientEmails []string) (uuid.UUID, error) {
+			return uuid.Nil, domain.Errorf(domain.EQUOTA, "report.trigger_generation", "Report limit reached.")
+		},
+	}
+	h := newTestReportHandler(svc, &mockStorage{})
+
+	req := newPreviewRequest(uuid.New())
+	req.Method = http.MethodPost
+	rr := httptest.NewRecorder()
+	h.GeneratePreview(rr, req)
+
+	if rr.Code != http.StatusTooManyRequests {
+		t.Errorf("expected status 429, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), "Report limit reached.") {
+		t.Errorf("expected the quota message, got %q", rr.Body.String())
+	}
+}
+
+func TestPreview_RequiresAuthentication(t *testing.T) {
+	svc := &mockReportService{}
+	h := newTestReportHandler(svc, &mockStorage{})
+
+	req := httptest.NewRequest(http.MethodGet, "/inspections/"+uuid.New().String()+"/reports/preview", nil)
+	rr := httptest.NewRecorder()
+	h.Preview(rr, req)
+
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("expected status 401, got %d", rr.Code)
+	}
+}
+
+// =============================================================================
+// Summary Tests
+// =============================================================================
+
+// fakePDFConverter records the HTML it is given and writes a stub PDF.
+type fakePDFConverter struct {
+	html []byte
+}
+
+func (c *fakePDFConverter) Convert(ctx context.Context, html []byte, w io.Writer) error {
+	c.html = html
+	_, err := w.Write([]byte("%PDF-1.7 stub"))
+	return err
+}
+
+func (c *fakePDFConverter) Format() domain.ReportFormat { return domain.ReportFormatPDF }
+
+// newSummaryRequest builds an authenticated summary request with the query.
+func newSummaryRequest(query string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/reports/summary?"+query, nil)
+	user := &domain.User{ID: uuid.New(), Email: "inspector@example.com"}
+	return req.WithContext(auth.SetUser(req.Context(), user))
+}
+
+// summaryService returns a fixed summary for whatever range is requested.
+func summaryService() *mockReportService {
+	return &mockReportService{
+		SummaryFunc: func(ctx context.Context, userID uuid.UUID, from, to time.Time) (*domain.ViolationSummary, error) {
+			return &domain.ViolationSummary{
+				From: from,
+				To:   to,
+				Counts: []domain.ViolationSummaryCount{
+					{Severity: domain.ViolationSeverityCritical, Status: domain.ViolationStatusConfirmed, Count: 3},
+					{Severity: domain.ViolationSeveritySerious, Status: domain.ViolationStatusConfirmed, Count: 5},
+					{Severity: domain.ViolationSeveritySerious, Status: domain.ViolationStatusRejected, Count: 1},
+					{Severity: "", Status: domain.ViolationStatusPending, Count: 2},
+				},
+				GeneratedAt: time.Now(),
+			}, nil
+		},
+	}
+}
+
+func TestSummary_CSV(t *testing.T) {
+	h := newTestReportHandler(summaryService(), &mockStorage{})
+
+	rr := httptest.NewRecorder()
+	h.Summary(rr, newSummaryRequest("from=2025-01-01&to=2025-03-31&format=csv"))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if ct := rr.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/csv") {
+		t.Errorf("expected CSV content type, got %q", ct)
+	}
+	if cd := rr.Header().Get("Content-Disposition"); !strings.Contains(cd, "violations-summary-2025-01-01-to-2025-03-31.csv") {
+		t.Errorf("expected summary filename, got %q", cd)
+	}
+
+	want := strings.Join([]string{
+		"severity,confirmed,pending,rejected,total",
+		"critical,3,0,0,3",
+		"serious,5,0,1,6",
+		"other,0,0,0,0",
+		"recommendation,0,0,0,0",
+		"unrated,0,2,0,2",
+		"total,8,2,1,11",
+	}, "\n") + "\n"
+	if got := rr.Body.String(); got != want {
+		t.Errorf("unexpected CSV:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestSummary_PDF(t *testing.T) {
+	h := newTestReportHandler(summaryService(), &mockStorage{})
+	converter := &fakePDFConverter{}
+	h.pdfConverter = converter
+
+	rr := httptest.NewRecorder()
+	h.Summary(rr, newSummaryRequest("from=2025-01-01&to=2025-03-31&format=pdf"))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "application/pdf" {
+		t.Errorf("expected PDF content type, got %q", ct)
+	}
+	if !strings.HasPrefix(rr.Body.String(), "%PDF") {
+		t.Errorf("expected converter output, got %q", rr.Body.String())
+	}
+	html := string(converter.html)
+	for _, want := range []string{"Violations Summary", "January 1, 2025", "March 31, 2025", "Unrated", "<td>11</td>"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected summary HTML to contain %q", want)
+		}
+	}
+}
+
+func TestSummary_RejectsInvalidRequests(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "unknown format", query: "from=2025-01-01&to=2025-01-31&format=docx"},
+		{name: "missing dates", query: "format=csv"},
+		{name: "malformed date", query: "from=01/01/2025&to=2025-01-31"},
+		{name: "from after to", query: "from=2025-02-01&to=2025-01-31"},
+		{name: "span too long", query: "from=2024-01-01&to=2025-06-30"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := summaryService()
+			called := false
+			svc.SummaryFunc = func(ctx context.Context, userID uuid.UUID, from, to time.Time) (*domain.ViolationSummary, error) {
+				called = true
+				return nil, nil
+			}
+			h := newTestReportHandler(svc, &mockStorage{})
+
+			rr := httptest.NewRecorder()
+			h.Summary(rr, newSummaryRequest(tt.query))
+
+			if rr.Code != http.StatusBadRequest {
+				t.Errorf("expected status 400, got %d", rr.Code)
+			}
+			if called {
+				t.Error("expected invalid request not to reach the service")
+			}
+		})
+	}
+}
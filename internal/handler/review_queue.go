@@ -0,0 +1,180 @@
+// Package handler contains HTTP handlers for the Lukaut application.
+//
+// This file contains helpers for the queue-based violation review page:
+// parsing filter query params and resolving the working set and position.
+package handler
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/google/uuid"
+)
+
+// reviewQueueState is the resolved view of the review queue for a request.
+type reviewQueueState struct {
+	Violations []domain.Violation     // Filtered, ordered working set used for navigation
+	Counts     domain.ViolationCounts // Counts across the working set
+	Position   int                    // Current position within Violations (0-indexed)
+	IsComplete bool                   // True if no pending violations remain in the working set
+}
+
+// buildReviewQueueState resolves the requested position against the working
+// set of violations (already narrowed by the queue filter).
+//
+// When posParam is empty or out of range, the position defaults to the first
+// pending violation in the working set (or 0 if none are pending).
+func buildReviewQueueState(working []domain.Violation, posParam string) reviewQueueState {
+	position := 0
+	if p, err := strconv.Atoi(posParam); err == nil && p >= 0 && p < len(working) {
+		position = p
+	} else if next := nextPendingPosition(working, -1); next >= 0 {
+		position = next
+	}
+
+	counts := domain.CalculateViolationCounts(working)
+
+	return reviewQueueState{
+		Violations: working,
+		Counts:     counts,
+		Position:   position,
+		IsComplete: len(working) > 0 && counts.Pending == 0,
+	}
+}
+
+// nextPendingPosition returns the index of the first pending violation after
+// currentPos, wrapping around to the start of the list. Returns -1 if no
+// other violation is pending. Pass -1 to search from the beginning.
+func nextPendingPosition(violations []domain.Violation, currentPos int) int {
+	for i := currentPos + 1; i < len(violations); i++ {
+		if violations[i].Status == domain.ViolationStatusPending {
+			return i
+		}
+	}
+	for i := 0; i < currentPos && i < len(violations); i++ {
+		if violations[i].Status == domain.ViolationStatusPending {
+			return i
+		}
+	}
+	return -1
+}
+
+// positionOfViolation returns the index of the violation with the given ID,
+// or -1 if it is not in the list (e.g. excluded by the queue filter).
+func positionOfViolation(violations []domain.Violation, id uuid.UUID) int {
+	for i, v := range violations {
+		if v.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// parseViolationFilter reads the severity, confidence, and sort query params.
+// Severity and confidence accept comma-separated values; unrecognized values
+// are ignored rather than rejected so stale bookmarks still load.
+func parseViolationFilter(q url.Values) domain.ViolationFilter {
+	var filter domain.ViolationFilter
+
+	for _, s := range splitQueryList(q.Get("severity")) {
+		severity := domain.ViolationSeverity(s)
+		if severity.IsValid() {
+			filter.Severities = append(filter.Severities, severity)
+		}
+	}
+
+	for _, c := range splitQueryList(q.Get("confidence")) {
+		confidence := domain.ViolationConfidence(c)
+		if confidence.IsValid() {
+			filter.Confidences = append(filter.Confidences, confidence)
+		}
+	}
+
+	if sort := domain.ViolationSort(q.Get("sort")); sort.IsValid() {
+		filter.Sort = sort
+	}
+
+	return filter
+}
+
+// violationFilterQuery encodes a filter as query params suitable for appending
+// to a URL that already has a query string (e.g. "&severity=critical").
+// Returns an empty string for an empty filter.
+func violationFilterQuery(filter domain.ViolationFilter) string {
+	if filter.IsEmpty() {
+		return ""
+	}
+
+	values := url.Values{}
+	if len(filter.Severities) > 0 {
+		parts := make([]string, len(filter.Severities))
+		for i, s := range filter.Severities {
+			parts[i] = string(s)
+		}
+		values.Set("severity", strings.Join(parts, ","))
+	}
+	if len(filter.Confidences) > 0 {
+		parts := make([]string, len(filter.Confidences))
+		for i, c := range filter.Confidences {
+			parts[i] = string(c)
+		}
+		values.Set("confidence", strings.Join(parts, ","))
+	}
+	if filter.Sort != domain.ViolationSortDefault {
+		values.Set("sort", string(filter.Sort))
+	}
+
+	return "&" + values.Encode()
+}
+
+// violationFilterLabel describes an active filter for display, e.g.
+// "Critical, Serious · High confidence · Most severe first".
+// Returns an empty string for an empty filter.
+func violationFilterLabel(filter domain.ViolationFilter) string {
+	var parts []string
+
+	if len(filter.Severities) > 0 {
+		names := make([]string, len(filter.Severities))
+		for i, s := range filter.Severities {
+			names[i] = capitalize(string(s))
+		}
+		parts = append(parts, strings.Join(names, ", "))
+	}
+	if len(filter.Confidences) > 0 {
+		names := make([]string, len(filter.Confidences))
+		for i, c := range filter.Confidences {
+			names[i] = capitalize(string(c))
+		}
+		parts = append(parts, strings.Join(names, ", ")+" confidence")
+	}
+	switch filter.Sort {
+	case domain.ViolationSortSeverityDesc:
+		parts = append(parts, "Most severe first")
+	case domain.ViolationSortConfidenceAsc:
+		parts = append(parts, "Least confident first")
+	}
+
+	return strings.Join(parts, " · ")
+}
+
+// capitalize upper-cases the first letter of an ASCII word.
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// splitQueryList splits a comma-separated query value, dropping empty entries.
+func splitQueryList(raw string) []string {
+	var result []string
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part != "" {
+			result = append(result, part)
+		}
+	}
+	return result
+}
@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func testQueueViolations() []domain.Violation {
+	return []domain.Violation{
+		{ID: uuid.New(), Status: domain.ViolationStatusConfirmed, Severity: domain.ViolationSeverityCritical, Confidence: domain.ViolationConfidenceHigh},
+		{ID: uuid.New(), Status: domain.ViolationStatusPending, Severity: domain.ViolationSeverityOther, Confidence: domain.ViolationConfidenceLow},
+		{ID: uuid.New(), Status: domain.ViolationStatusPending, Severity: domain.ViolationSeverityCritical, Confidence: domain.ViolationConfidenceMedium},
+		{ID: uuid.New(), Status: domain.ViolationStatusRejected, Severity: domain.ViolationSeveritySerious, Confidence: domain.ViolationConfidenceHigh},
+		{ID: uuid.New(), Status: domain.ViolationStatusPending, Severity: domain.ViolationSeverityCritical, Confidence: domain.ViolationConfidenceLow},
+	}
+}
+
+func TestBuildReviewQueueState_CountsReflectFilteredSet(t *testing.T) {
+	violations := testQueueViolations()
+	filter := domain.ViolationFilter{Severities: []domain.ViolationSeverity{domain.ViolationSeverityCritical}}
+
+	state := buildReviewQueueState(filter.Apply(violations), "")
+
+	assert.Len(t, state.Violations, 3)
+	assert.Equal(t, domain.ViolationCounts{Total: 3, Pending: 2, Confirmed: 1}, state.Counts)
+}
+
+func TestBuildReviewQueueState_DefaultPositionIsFirstPendingInFilteredSet(t *testing.T) {
+	violations := testQueueViolations()
+	filter := domain.ViolationFilter{Severities: []domain.ViolationSeverity{domain.ViolationSeverityCritical}}
+
+	state := buildReviewQueueState(filter.Apply(violations), "")
+
+	// Filtered set is [confirmed critical, pending critical, pending critical]
+	assert.Equal(t, 1, state.Position)
+	assert.Equal(t, violations[2].ID, state.Violations[state.Position].ID)
+}
+
+func TestBuildReviewQueueState_PositionIndexesFilteredSet(t *testing.T) {
+	violations := testQueueViolations()
+	filter := domain.ViolationFilter{Severities: []domain.ViolationSeverity{domain.ViolationSeverityCritical}}
+
+	state := buildReviewQueueState(filter.Apply(violations), "2")
+
+	assert.Equal(t, 2, state.Position)
+	assert.Equal(t, violations[4].ID, state.Violations[state.Position].ID)
+}
+
+func TestBuildReviewQueueState_OutOfRangePositionFallsBack(t *testing.T) {
+	violations := testQueueViolations()
+	filter := domain.ViolationFilter{Confidences: []domain.ViolationConfidence{domain.ViolationConfidenceHigh}}
+
+	// Filtered set has two entries, neither pending; pos=3 is out of range
+	state := buildReviewQueueState(filter.Apply(violations), "3")
+
+	assert.Len(t, state.Violations, 2)
+	assert.Equal(t, 0, state.Position)
+}
+
+var criticalOnly = domain.ViolationFilter{Severities: []domain.ViolationSeverity{domain.ViolationSeverityCritical}}
+
+func TestBuildReviewQueueState_CompletionIsRelativeToFilteredSet(t *testing.T) {
+	violations := testQueueViolations()
+	filter := domain.ViolationFilter{Confidences: []domain.ViolationConfidence{domain.ViolationConfidenceHigh}}
+
+	// The filtered set has no pending violations; the full set has three
+	assert.True(t, buildReviewQueueState(filter.Apply(violations), "").IsComplete)
+	assert.False(t, buildReviewQueueState(violations, "").IsComplete)
+}
+
+func TestBuildReviewQueueState_EmptyIsNotComplete(t *testing.T) {
+	state := buildReviewQueueState(criticalOnly.Apply(nil), "")
+
+	assert.False(t, state.IsComplete)
+}
+
+func TestBuildReviewQueueState_SortedNavigation(t *testing.T) {
+	violations := testQueueViolations()
+	filter := domain.ViolationFilter{Sort: domain.ViolationSortConfidenceAsc}
+
+	state := buildReviewQueueState(filter.Apply(violations), "")
+
+	// Low-confidence violations come first; the first of them is pending
+	assert.Equal(t, 0, state.Position)
+	assert.Equal(t, violations[1].ID, state.Violations[0].ID)
+	assert.Equal(t, violations[4].ID, state.Violations[1].ID)
+}
+
+func TestNextPendingPosition(t *testing.T) {
+	working := criticalOnly.Apply(testQueueViolations())
+
+	// Filtered set is [confirmed critical, pending critical, pending critical]
+	assert.Equal(t, 1, nextPendingPosition(working, -1))
+	assert.Equal(t, 2, nextPendingPosition(working, 1))
+	assert.Equal(t, 1, nextPendingPosition(working, 2), "wraps around")
+}
+
+func TestNextPendingPosition_NonePending(t *testing.T) {
+	working := []domain.Violation{{ID: uuid.New(), Status: domain.ViolationStatusConfirmed}}
+
+	assert.Equal(t, -1, nextPendingPosition(working, 0))
+}
+
+func TestParseViolationFilter(t *testing.T) {
+	q := url.Values{}
+	q.Set("severity", "critical, Serious,bogus")
+	q.Set("confidence", "low")
+	q.Set("sort", "severity_desc")
+
+	filter := parseViolationFilter(q)
+
+	assert.Equal(t, []domain.ViolationSeverity{domain.ViolationSeverityCritical, domain.ViolationSeveritySerious}, filter.Severities)
+	assert.Equal(t, []domain.ViolationConfidence{domain.ViolationConfidenceLow}, filter.Confidences)
+	assert.Equal(t, domain.ViolationSortSeverityDesc, filter.Sort)
+}
+
+func TestParseViolationFilter_IgnoresInvalidSort(t *testing.T) {
+	q := url.Values{}
+	q.Set("sort", "random")
+
+	filter := parseViolationFilter(q)
+
+	assert.True(t, filter.IsEmpty())
+}
+
+func TestViolationFilterQuery_RoundTrips(t *testing.T) {
+	filter := domain.ViolationFilter{
+		Severities:  []domain.ViolationSeverity{domain.ViolationSeverityCritical, domain.ViolationSeveritySerious},
+		Confidences: []domain.ViolationConfidence{domain.ViolationConfidenceHigh},
+		Sort:        domain.ViolationSortConfidenceAsc,
+	}
+
+	encoded := violationFilterQuery(filter)
+	assert.Equal(t, "&", encoded[:1])
+
+	q, err := url.ParseQuery(encoded[1:])
+	assert.NoError(t, err)
+	assert.Equal(t, filter, parseViolationFilter(q))
+}
+
+func TestViolationFilterQuery_EmptyFilter(t *testing.T) {
+	assert.Equal(t, "", violationFilterQuery(domain.ViolationFilter{}))
+}
+
+func TestViolationFilterLabel(t *testing.T) {
+	filter := domain.ViolationFilter{
+		Severities:  []domain.ViolationSeverity{domain.ViolationSeverityCritical, domain.ViolationSeveritySerious},
+		Confidences: []domain.ViolationConfidence{domain.ViolationConfidenceHigh},
+		Sort:        domain.ViolationSortSeverityDesc,
+	}
+
+	assert.Equal(t, "Critical, Serious · High confidence · Most severe first", violationFilterLabel(filter))
+	assert.Equal(t, "", violationFilterLabel(domain.ViolationFilter{}))
+}
+
+func TestPositionOfViolation(t *testing.T) {
+	violations := testQueueViolations()
+	working := criticalOnly.Apply(violations)
+
+	if got := positionOfViolation(working, violations[4].ID); got != 2 {
+		t.Errorf("expected position 2 in filtered set, got %d", got)
+	}
+	if got := positionOfViolation(working, violations[1].ID); got != -1 {
+		t.Errorf("expected -1 for violation excluded by filter, got %d", got)
+	}
+}
+
+func TestReviewShortcutDisplays_HelpOverlayOrder(t *testing.T) {
+	shortcuts, err := domain.MergeReviewShortcuts(map[domain.ReviewAction]string{domain.ReviewActionConfirm: "y"})
+	if err != nil {
+		t.Fatalf("MergeReviewShortcuts failed: %v", err)
+	}
+
+	displays := reviewShortcutDisplays(shortcuts)
+
+	if len(displays) != len(domain.ReviewActions) {
+		t.Fatalf("expected %d shortcuts, got %d", len(domain.ReviewActions), len(displays))
+	}
+	for i, action := range domain.ReviewActions {
+		if displays[i].Action != string(action) || displays[i].Label != action.Label() {
+			t.Errorf("shortcut %d: expected %s, got %+v", i, action, displays[i])
+		}
+	}
+	if displays[0].Key != "y" {
+		t.Errorf("expected the custom confirm key, got %q", displays[0].Key)
+	}
+	if keys := queueShortcutKeys(shortcuts); keys.Confirm != "y" || keys.Reject != "r" {
+		t.Errorf("expected custom confirm and default reject hints, got %+v", keys)
+	}
+}
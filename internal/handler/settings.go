@@ -4,6 +4,7 @@
 package handler
 
 import (
+	"errors"
 	"log/slog"
 	"net/http"
 	"strings"
@@ -22,22 +23,70 @@ import (
 // - POST /settings/profile  -> UpdateProfile
 // - GET  /settings/password -> ShowPasswordTempl
 // - POST /settings/password -> ChangePassword
+// - GET  /settings/business -> ShowBusinessTempl
+// - POST /settings/business -> UpdateBusiness
+// - GET  /settings/business/logo -> ShowBusinessLogo
+// - GET  /account/business  -> ShowBusinessTempl (alias of /settings/business)
+// - POST /account/business  -> UpdateBusiness
+// - GET  /settings/usage    -> ShowUsageTempl
+// - GET  /settings/sessions -> ShowSessionsTempl
+// - POST /settings/sessions/{id}/revoke   -> RevokeSession
+// - POST /settings/sessions/revoke-others -> RevokeOtherSessions
+// - GET  /settings/webhooks -> ShowWebhooksTempl
+// - POST /settings/webhooks -> CreateWebhook
+// - POST /settings/webhooks/{id}/delete   -> DeleteWebhook
+// - GET  /settings/preferences -> ShowPreferencesTempl
+// - POST /settings/preferences -> UpdatePreferences
+// - GET  /settings/report-template -> ShowReportTemplateTempl
+// - POST /settings/report-template -> UpdateReportTemplate
+// - POST /settings/report-template/preview -> PreviewReportTemplate
 type SettingsHandler struct {
-	userService service.UserService
-	logger      *slog.Logger
+	userService     service.UserService
+	quotaService    service.QuotaService
+	webhookService  service.WebhookService
+	brandingService service.BrandingService
+	orgService      service.OrganizationService
+	preferences     service.PreferenceService
+	reportTemplates service.ReportTemplateService
+	logger          *slog.Logger
+	isSecure        bool // Whether to set Secure flag on cookies (true in production)
 }
 
 // NewSettingsHandler creates a new SettingsHandler with the required dependencies.
 func NewSettingsHandler(
 	userService service.UserService,
+	quotaService service.QuotaService,
+	webhookService service.WebhookService,
+	brandingService service.BrandingService,
+	orgService service.OrganizationService,
 	logger *slog.Logger,
+	isSecure bool,
 ) *SettingsHandler {
 	return &SettingsHandler{
-		userService: userService,
-		logger:      logger,
+		userService:     userService,
+		quotaService:    quotaService,
+		webhookService:  webhookService,
+		brandingService: brandingService,
+		orgService:      orgService,
+		logger:          logger,
+		isSecure:        isSecure,
 	}
 }
 
+// WithPreferences sets the preference service behind the units and dates
+// settings. Without it the page shows the defaults and cannot be saved.
+func (h *SettingsHandler) WithPreferences(preferences service.PreferenceService) *SettingsHandler {
+	h.preferences = preferences
+	return h
+}
+
+// WithReportTemplates sets the service behind the report template settings.
+// Without it the page shows the default sections and cannot be saved.
+func (h *SettingsHandler) WithReportTemplates(reportTemplates service.ReportTemplateService) *SettingsHandler {
+	h.reportTemplates = reportTemplates
+	return h
+}
+
 // SettingsPageData contains data for settings pages.
 type SettingsPageData struct {
 	CurrentPath string
@@ -63,7 +112,7 @@ func (h *SettingsHandler) UpdateProfile(w http.ResponseWriter, r *http.Request)
 
 	// Parse form data
 	if err := r.ParseForm(); err != nil {
-		h.logger.Error("failed to parse form", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to parse form", "error", err)
 		h.renderProfileError(w, r, user, nil, nil, &Flash{
 			Type:    "error",
 			Message: "Invalid form submission. Please try again.",
@@ -115,7 +164,7 @@ func (h *SettingsHandler) UpdateProfile(w http.ResponseWriter, r *http.Request)
 				Message: domain.ErrorMessage(err),
 			})
 		default:
-			h.logger.Error("profile update failed", "error", err, "user_id", user.ID)
+			h.logger.ErrorContext(r.Context(), "profile update failed", "error", err, "user_id", user.ID)
 			h.renderProfileError(w, r, user, formValues, nil, &Flash{
 				Type:    "error",
 				Message: "Failed to update profile. Please try again later.",
@@ -125,7 +174,7 @@ func (h *SettingsHandler) UpdateProfile(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Log successful update
-	h.logger.Info("user profile updated", "user_id", user.ID)
+	h.logger.InfoContext(r.Context(), "user profile updated", "user_id", user.ID)
 
 	// Redirect with success message
 	http.Redirect(w, r, "/settings?updated=1", http.StatusSeeOther)
@@ -173,7 +222,7 @@ func (h *SettingsHandler) renderProfileError(
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	if err := settings.ProfilePage(data).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render profile error page", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render profile error page", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -192,7 +241,7 @@ func (h *SettingsHandler) ChangePassword(w http.ResponseWriter, r *http.Request)
 
 	// Parse form data
 	if err := r.ParseForm(); err != nil {
-		h.logger.Error("failed to parse form", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to parse form", "error", err)
 		h.renderPasswordError(w, r, user, nil, &Flash{
 			Type:    "error",
 			Message: "Invalid form submission. Please try again.",
@@ -248,7 +297,7 @@ func (h *SettingsHandler) ChangePassword(w http.ResponseWriter, r *http.Request)
 				Message: domain.ErrorMessage(err),
 			})
 		default:
-			h.logger.Error("password change failed", "error", err, "user_id", user.ID)
+			h.logger.ErrorContext(r.Context(), "password change failed", "error", err, "user_id", user.ID)
 			h.renderPasswordError(w, r, user, nil, &Flash{
 				Type:    "error",
 				Message: "Failed to change password. Please try again later.",
@@ -258,7 +307,7 @@ func (h *SettingsHandler) ChangePassword(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Log successful password change
-	h.logger.Info("user password changed", "user_id", user.ID)
+	h.logger.InfoContext(r.Context(), "user password changed", "user_id", user.ID)
 
 	// Password change invalidates all sessions, so redirect to login
 	http.Redirect(w, r, "/login?reset=1", http.StatusSeeOther)
@@ -295,7 +344,7 @@ func (h *SettingsHandler) renderPasswordError(
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	if err := settings.PasswordPage(data).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render password error page", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render password error page", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -304,7 +353,12 @@ func (h *SettingsHandler) renderPasswordError(
 // POST /settings/business - Update Business
 // =============================================================================
 
-// UpdateBusiness processes the business settings form submission.
+// businessFormMaxMemory is the part of a business form, logo included, held
+// in memory while parsing.
+const businessFormMaxMemory = 4 << 20
+
+// UpdateBusiness processes the business settings form submission. The form
+// is multipart when it carries a new logo.
 func (h *SettingsHandler) UpdateBusiness(w http.ResponseWriter, r *http.Request) {
 	user := auth.GetUser(r.Context())
 	if user == nil {
@@ -313,8 +367,8 @@ func (h *SettingsHandler) UpdateBusiness(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Parse form data
-	if err := r.ParseForm(); err != nil {
-		h.logger.Error("failed to parse form", "error", err)
+	if err := r.ParseMultipartForm(businessFormMaxMemory); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+		h.logger.ErrorContext(r.Context(), "failed to parse form", "error", err)
 		h.renderBusinessError(w, r, user, nil, nil, &Flash{
 			Type:    "error",
 			Message: "Invalid form submission. Please try again.",
@@ -332,6 +386,7 @@ func (h *SettingsHandler) UpdateBusiness(w http.ResponseWriter, r *http.Request)
 	state := strings.TrimSpace(r.FormValue("state"))
 	postalCode := strings.TrimSpace(r.FormValue("postal_code"))
 	licenseNumber := strings.TrimSpace(r.FormValue("license_number"))
+	brandColor := strings.TrimSpace(r.FormValue("brand_color"))
 
 	// Store form values for re-rendering
 	formValues := map[string]string{
@@ -344,7 +399,8 @@ func (h *SettingsHandler) UpdateBusiness(w http.ResponseWriter, r *http.Request)
 		"BusinessState":         state,
 		"BusinessPostalCode":    postalCode,
 		"BusinessLicenseNumber": licenseNumber,
-		"BusinessLogoURL":       user.BusinessLogoURL, // Preserve existing logo
+		"BusinessLogoURL":       businessLogoPreviewURL(user), // Preserve existing logo
+		"BusinessBrandColor":    brandColor,
 	}
 
 	// Validate form fields
@@ -370,14 +426,11 @@ func (h *SettingsHandler) UpdateBusiness(w http.ResponseWriter, r *http.Request)
 		errors["license_number"] = "License number must be 100 characters or less"
 	}
 
-	// If validation errors, re-render form
-	if len(errors) > 0 {
-		h.renderBusinessError(w, r, user, formValues, errors, nil)
-		return
+	if brandColor != "" && !domain.IsValidBrandColor(brandColor) {
+		errors["brand_color"] = "Brand color must be a hex color like #1E3A5F"
 	}
 
-	// Call UserService.UpdateBusinessProfile
-	err := h.userService.UpdateBusinessProfile(r.Context(), domain.BusinessProfileUpdateParams{
+	params := domain.BusinessProfileUpdateParams{
 		UserID:        user.ID,
 		BusinessName:  businessName,
 		BusinessEmail: businessEmail,
@@ -389,7 +442,40 @@ func (h *SettingsHandler) UpdateBusiness(w http.ResponseWriter, r *http.Request)
 		PostalCode:    postalCode,
 		LicenseNumber: licenseNumber,
 		LogoURL:       user.BusinessLogoURL, // Preserve existing logo
-	})
+		BrandColor:    brandColor,
+	}
+	for _, field := range params.MissingFields() {
+		errors[field.Name] = field.Label + " is required"
+	}
+
+	// If validation errors, re-render form
+	if len(errors) > 0 {
+		h.renderBusinessError(w, r, user, formValues, errors, nil)
+		return
+	}
+
+	// Store the new logo, if one was chosen
+	if file, header, err := r.FormFile("logo"); err == nil {
+		err = h.brandingService.UploadLogo(r.Context(), user.ID, file, header)
+		_ = file.Close()
+		if err != nil {
+			switch domain.ErrorCode(err) {
+			case domain.EINVALID, domain.ETOOLARGE:
+				errors["logo"] = domain.ErrorMessage(err)
+				h.renderBusinessError(w, r, user, formValues, errors, nil)
+			default:
+				h.logger.ErrorContext(r.Context(), "business logo upload failed", "error", err, "user_id", user.ID)
+				h.renderBusinessError(w, r, user, formValues, nil, &Flash{
+					Type:    "error",
+					Message: "Failed to upload logo. Please try again later.",
+				})
+			}
+			return
+		}
+	}
+
+	// Call UserService.UpdateBusinessProfile
+	err := h.userService.UpdateBusinessProfile(r.Context(), params)
 	if err != nil {
 		code := domain.ErrorCode(err)
 		switch code {
@@ -399,7 +485,7 @@ func (h *SettingsHandler) UpdateBusiness(w http.ResponseWriter, r *http.Request)
 				Message: domain.ErrorMessage(err),
 			})
 		default:
-			h.logger.Error("business profile update failed", "error", err, "user_id", user.ID)
+			h.logger.ErrorContext(r.Context(), "business profile update failed", "error", err, "user_id", user.ID)
 			h.renderBusinessError(w, r, user, formValues, nil, &Flash{
 				Type:    "error",
 				Message: "Failed to update business information. Please try again later.",
@@ -409,12 +495,34 @@ func (h *SettingsHandler) UpdateBusiness(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Log successful update
-	h.logger.Info("user business profile updated", "user_id", user.ID)
+	h.logger.InfoContext(r.Context(), "user business profile updated", "user_id", user.ID)
 
 	// Redirect with success message
 	http.Redirect(w, r, "/settings/business?updated=1", http.StatusSeeOther)
 }
 
+// =============================================================================
+// GET /settings/business/logo - Show Business Logo
+// =============================================================================
+
+// ShowBusinessLogo redirects to the user's uploaded logo, for the preview on
+// the business settings page.
+func (h *SettingsHandler) ShowBusinessLogo(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	url, err := h.brandingService.LogoURL(r.Context(), user)
+	if err != nil {
+		ErrorResponse(w, r, h.logger, err)
+		return
+	}
+
+	http.Redirect(w, r, url, http.StatusTemporaryRedirect)
+}
+
 // renderBusinessError re-renders the business form with errors using templ.
 func (h *SettingsHandler) renderBusinessError(
 	w http.ResponseWriter,
@@ -435,7 +543,8 @@ func (h *SettingsHandler) renderBusinessError(
 			"BusinessState":         user.BusinessState,
 			"BusinessPostalCode":    user.BusinessPostalCode,
 			"BusinessLicenseNumber": user.BusinessLicenseNumber,
-			"BusinessLogoURL":       user.BusinessLogoURL,
+			"BusinessLogoURL":       businessLogoPreviewURL(user),
+			"BusinessBrandColor":    user.BusinessBrandColor,
 		}
 	}
 	if errors == nil {
@@ -465,6 +574,7 @@ func (h *SettingsHandler) renderBusinessError(
 			BusinessPostalCode:    formValues["BusinessPostalCode"],
 			BusinessLicenseNumber: formValues["BusinessLicenseNumber"],
 			BusinessLogoURL:       formValues["BusinessLogoURL"],
+			BusinessBrandColor:    formValues["BusinessBrandColor"],
 		},
 		Errors:    errors,
 		Flash:     templFlash,
@@ -473,7 +583,7 @@ func (h *SettingsHandler) renderBusinessError(
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	if err := settings.BusinessPage(data).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render business error page", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render business error page", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DukeRupert/lukaut/internal/auth"
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/google/uuid"
+)
+
+// mockBrandingService implements the service.BrandingService interface for testing.
+type mockBrandingService struct {
+	UploadLogoFunc func(ctx context.Context, userID uuid.UUID, file multipart.File, header *multipart.FileHeader) error
+	LogoURLFunc    func(ctx context.Context, user *domain.User) (string, error)
+}
+
+func (m *mockBrandingService) UploadLogo(ctx context.Context, userID uuid.UUID, file multipart.File, header *multipart.FileHeader) error {
+	if m.UploadLogoFunc != nil {
+		return m.UploadLogoFunc(ctx, userID, file, header)
+	}
+	return nil
+}
+
+func (m *mockBrandingService) LogoURL(ctx context.Context, user *domain.User) (string, error) {
+	if m.LogoURLFunc != nil {
+		return m.LogoURLFunc(ctx, user)
+	}
+	return "", domain.NotFound("branding.logo_url", "logo", user.ID.String())
+}
+
+// businessFormRequest builds a multipart business settings submission.
+func businessFormRequest(t *testing.T, user *domain.User, fields map[string]string, logo []byte) *http.Request {
+	t.Helper()
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for name, value := range fields {
+		_ = mw.WriteField(name, value)
+	}
+	if logo != nil {
+		part, err := mw.CreateFormFile("logo", "logo.png")
+		if err != nil {
+			t.Fatalf("create logo part: %v", err)
+		}
+		_, _ = part.Write(logo)
+	}
+	_ = mw.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/settings/business", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req.WithContext(auth.SetUser(req.Context(), user))
+}
+
+func TestUpdateBusiness_UploadsLogoAndBrandColor(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Email: "pat@example.com"}
+
+	var gotLogo []byte
+	branding := &mockBrandingService{
+		UploadLogoFunc: func(ctx context.Context, userID uuid.UUID, file multipart.File, header *multipart.FileHeader) error {
+			if userID != user.ID {
+				t.Errorf("uploaded logo for %s, want %s", userID, user.ID)
+			}
+			gotLogo, _ = io.ReadAll(file)
+			return nil
+		},
+	}
+	var gotParams domain.BusinessProfileUpdateParams
+	users := &mockUserService{
+		UpdateBusinessProfileFunc: func(ctx context.Context, params domain.BusinessProfileUpdateParams) error {
+			gotParams = params
+			return nil
+		},
+	}
+	h := NewSettingsHandler(users, &mockQuotaService{}, nil, branding, nil, newTestLogger(), false)
+
+	rr := httptest.NewRecorder()
+	h.UpdateBusiness(rr, businessFormRequest(t, user, map[string]string{
+		"business_name": "Acme Safety Consultants",
+		"brand_color":   "#0055AA",
+	}, []byte("logo-bytes")))
+
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("expected redirect, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if string(gotLogo) != "logo-bytes" {
+		t.Errorf("uploaded logo %q, want the submitted file", gotLogo)
+	}
+	if gotParams.BusinessName != "Acme Safety Consultants" || gotParams.BrandColor != "#0055AA" {
+		t.Errorf("unexpected profile update: %+v", gotParams)
+	}
+}
+
+func TestUpdateBusiness_RejectedLogoKeepsProfile(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Email: "pat@example.com"}
+
+	branding := &mockBrandingService{
+		UploadLogoFunc: func(ctx context.Context, userID uuid.UUID, file multipart.File, header *multipart.FileHeader) error {
+			return domain.Invalid("branding.upload_logo", "Logo must be a JPEG or PNG image.")
+		},
+	}
+	users := &mockUserService{
+		UpdateBusinessProfileFunc: func(ctx context.Context, params domain.BusinessProfileUpdateParams) error {
+			t.Error("profile updated despite the rejected logo")
+			return nil
+		},
+	}
+	h := NewSettingsHandler(users, &mockQuotaService{}, nil, branding, nil, newTestLogger(), false)
+
+	rr := httptest.NewRecorder()
+	h.UpdateBusiness(rr, businessFormRequest(t, user, map[string]string{
+		"business_name": "Acme Safety Consultants",
+	}, []byte("GIF89a")))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected the form re-rendered, got %d", rr.Code)
+	}
+	body := rr.Body.String()
+	if !strings.Contains(body, "Logo must be a JPEG or PNG image.") {
+		t.Error("expected the logo error on the form")
+	}
+	if !strings.Contains(body, `value="Acme Safety Consultants"`) {
+		t.Error("expected the submitted business name kept on the form")
+	}
+}
+
+func TestUpdateBusiness_InvalidBrandColor(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Email: "pat@example.com"}
+	h := NewSettingsHandler(&mockUserService{}, &mockQuotaService{}, nil, &mockBrandingService{}, nil, newTestLogger(), false)
+
+	rr := httptest.NewRecorder()
+	h.UpdateBusiness(rr, businessFormRequest(t, user, map[string]string{"brand_color": "orange"}, nil))
+
+	if !strings.Contains(rr.Body.String(), "Brand color must be a hex color") {
+		t.Error("expected the brand color error on the form")
+	}
+}
+
+func TestAccountBusinessRoutes(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Email: "pat@example.com", BusinessName: "Acme Safety Consultants", BusinessLicenseNumber: "CSP-12345"}
+
+	var updated bool
+	users := &mockUserService{
+		UpdateBusinessProfileFunc: func(ctx context.Context, params domain.BusinessProfileUpdateParams) error {
+			updated = true
+			return nil
+		},
+	}
+	h := NewSettingsHandler(users, &mockQuotaService{}, nil, &mockBrandingService{}, nil, newTestLogger(), false)
+	mux := http.NewServeMux()
+	h.RegisterTemplRoutes(mux, func(next http.Handler) http.Handler { return next })
+
+	req := httptest.NewRequest(http.MethodGet, "/account/business", nil)
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req.WithContext(auth.SetUser(req.Context(), user)))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("GET /account/business: expected 200, got %d", rr.Code)
+	}
+	for _, want := range []string{`value="Acme Safety Consultants"`, `value="CSP-12345"`, `name="logo"`} {
+		if !strings.Contains(rr.Body.String(), want) {
+			t.Errorf("business form does not contain %q", want)
+		}
+	}
+
+	req = businessFormRequest(t, user, map[string]string{"business_name": "Acme Safety Consultants"}, nil)
+	req.URL.Path = "/account/business"
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusSeeOther || !updated {
+		t.Errorf("POST /account/business: expected the profile updated and a redirect, got %d", rr.Code)
+	}
+}
+
+func TestUpdateBusiness_RequiresCompleteAddress(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Email: "pat@example.com"}
+	users := &mockUserService{
+		UpdateBusinessProfileFunc: func(ctx context.Context, params domain.BusinessProfileUpdateParams) error {
+			t.Error("profile updated with missing required fields")
+			return nil
+		},
+	}
+	h := NewSettingsHandler(users, &mockQuotaService{}, nil, &mockBrandingService{}, nil, newTestLogger(), false)
+
+	rr := httptest.NewRecorder()
+	h.UpdateBusiness(rr, businessFormRequest(t, user, map[string]string{"city": "Tampa"}, nil))
+
+	body := rr.Body.String()
+	for _, want := range []string{"Business name is required", "Street address is required", "State is required", "ZIP code is required"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %q on the form", want)
+		}
+	}
+}
@@ -4,6 +4,7 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/DukeRupert/lukaut/internal/auth"
@@ -48,7 +49,7 @@ func (h *SettingsHandler) ShowProfileTempl(w http.ResponseWriter, r *http.Reques
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	if err := settings.ProfilePage(data).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render profile page", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render profile page", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -81,7 +82,7 @@ func (h *SettingsHandler) ShowPasswordTempl(w http.ResponseWriter, r *http.Reque
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	if err := settings.PasswordPage(data).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render password page", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render password page", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -104,7 +105,7 @@ func (h *SettingsHandler) ShowBusinessTempl(w http.ResponseWriter, r *http.Reque
 	}
 
 	data := settings.BusinessPageData{
-		CurrentPath: r.URL.Path,
+		CurrentPath: "/settings/business",
 		CSRFToken:   "",
 		User:        domainUserToDisplay(user),
 		Form:        domainUserToBusinessForm(user),
@@ -115,7 +116,35 @@ func (h *SettingsHandler) ShowBusinessTempl(w http.ResponseWriter, r *http.Reque
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	if err := settings.BusinessPage(data).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render business page", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render business page", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// ShowUsageTempl renders the quota usage page for the current period.
+func (h *SettingsHandler) ShowUsageTempl(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	usage, err := h.quotaService.GetUsage(r.Context(), user.ID, user.QuotaTier())
+	if err != nil {
+		ServerErrorResponse(w, r, h.logger.With("user_id", user.ID), fmt.Errorf("get quota usage: %w", err))
+		return
+	}
+
+	data := settings.UsagePageData{
+		CurrentPath: r.URL.Path,
+		User:        domainUserToDisplay(user),
+		Usage:       quotaUsageToDisplay(usage),
+		ActiveTab:   settings.TabUsage,
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := settings.UsagePage(data).Render(r.Context(), w); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to render usage page", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -132,6 +161,26 @@ func (h *SettingsHandler) RegisterTemplRoutes(mux *http.ServeMux, requireUser fu
 	mux.Handle("POST /settings/password", requireUser(http.HandlerFunc(h.ChangePassword)))
 	mux.Handle("GET /settings/business", requireUser(http.HandlerFunc(h.ShowBusinessTempl)))
 	mux.Handle("POST /settings/business", requireUser(http.HandlerFunc(h.UpdateBusiness)))
+	mux.Handle("GET /settings/business/logo", requireUser(http.HandlerFunc(h.ShowBusinessLogo)))
+	mux.Handle("GET /account/business", requireUser(http.HandlerFunc(h.ShowBusinessTempl)))
+	mux.Handle("POST /account/business", requireUser(http.HandlerFunc(h.UpdateBusiness)))
+	mux.Handle("GET /settings/usage", requireUser(http.HandlerFunc(h.ShowUsageTempl)))
+	mux.Handle("GET /settings/sessions", requireUser(http.HandlerFunc(h.ShowSessionsTempl)))
+	mux.Handle("POST /settings/sessions/{id}/revoke", requireUser(http.HandlerFunc(h.RevokeSession)))
+	mux.Handle("POST /settings/sessions/revoke-others", requireUser(http.HandlerFunc(h.RevokeOtherSessions)))
+	mux.Handle("GET /settings/webhooks", requireUser(http.HandlerFunc(h.ShowWebhooksTempl)))
+	mux.Handle("POST /settings/webhooks", requireUser(http.HandlerFunc(h.CreateWebhook)))
+	mux.Handle("POST /settings/webhooks/{id}/delete", requireUser(http.HandlerFunc(h.DeleteWebhook)))
+	mux.Handle("GET /settings/organization", requireUser(http.HandlerFunc(h.ShowOrganizationTempl)))
+	mux.Handle("POST /settings/organization/invites", requireUser(http.HandlerFunc(h.InviteMember)))
+	mux.Handle("POST /settings/organization/members/{id}/remove", requireUser(http.HandlerFunc(h.RemoveMember)))
+	mux.Handle("GET /settings/preferences", requireUser(http.HandlerFunc(h.ShowPreferencesTempl)))
+	mux.Handle("POST /settings/preferences", requireUser(http.HandlerFunc(h.UpdatePreferences)))
+	mux.Handle("GET /settings/report-template", requireUser(http.HandlerFunc(h.ShowReportTemplateTempl)))
+	mux.Handle("POST /settings/report-template", requireUser(http.HandlerFunc(h.UpdateReportTemplate)))
+	mux.Handle("POST /settings/report-template/preview", requireUser(http.HandlerFunc(h.PreviewReportTemplate)))
+	mux.Handle("GET /organization/join", requireUser(http.HandlerFunc(h.ShowJoinOrganization)))
+	mux.Handle("POST /organization/join", requireUser(http.HandlerFunc(h.JoinOrganization)))
 }
 
 // =============================================================================
@@ -162,6 +211,38 @@ func domainUserToBusinessForm(u *domain.User) settings.BusinessFormData {
 		BusinessState:         u.BusinessState,
 		BusinessPostalCode:    u.BusinessPostalCode,
 		BusinessLicenseNumber: u.BusinessLicenseNumber,
-		BusinessLogoURL:       u.BusinessLogoURL,
+		BusinessLogoURL:       businessLogoPreviewURL(u),
+		BusinessBrandColor:    u.BusinessBrandColor,
+	}
+}
+
+// businessLogoPreviewURL returns where the settings page loads the user's
+// logo from: the uploaded logo if any, otherwise the linked one.
+func businessLogoPreviewURL(u *domain.User) string {
+	if u.HasBusinessLogo() {
+		return "/settings/business/logo"
+	}
+	return u.BusinessLogoURL
+}
+
+// quotaUsageToDisplay converts domain.QuotaUsage to settings.UsageDisplay.
+func quotaUsageToDisplay(u *domain.QuotaUsage) settings.UsageDisplay {
+	return settings.UsageDisplay{
+		IsUnlimited: u.IsUnlimited,
+		ResetsAt:    u.ResetsAt.Format("January 2, 2006"),
+		Analysis: settings.QuotaMeter{
+			Used:        u.AnalysisUsed,
+			Limit:       u.AnalysisLimit,
+			Remaining:   u.AnalysisRemaining(),
+			PercentUsed: u.AnalysisPercentUsed(),
+			NearLimit:   u.AnalysisNearLimit(),
+		},
+		Reports: settings.QuotaMeter{
+			Used:        u.ReportsUsed,
+			Limit:       u.ReportsLimit,
+			Remaining:   u.ReportsRemaining(),
+			PercentUsed: u.ReportsPercentUsed(),
+			NearLimit:   u.ReportsNearLimit(),
+		},
 	}
 }
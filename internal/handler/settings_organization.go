@@ -0,0 +1,278 @@
+// Package handler contains HTTP handlers for the Lukaut application.
+//
+// This file implements the organization settings page, where owners invite
+// inspectors to share inspections with, and the page invitees use to join.
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/DukeRupert/lukaut/internal/auth"
+	"github.com/DukeRupert/lukaut/internal/csrf"
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/templ/pages/settings"
+	"github.com/DukeRupert/lukaut/internal/templ/shared"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// GET /settings/organization - Show Organization
+// =============================================================================
+
+// ShowOrganizationTempl renders the user's organization, its members, and,
+// for owners, pending invitations and the invite form.
+func (h *SettingsHandler) ShowOrganizationTempl(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	var flash *shared.Flash
+	switch {
+	case r.URL.Query().Get("invited") == "1":
+		flash = &shared.Flash{
+			Type:    shared.FlashSuccess,
+			Message: "Invitation sent.",
+		}
+	case r.URL.Query().Get("removed") == "1":
+		flash = &shared.Flash{
+			Type:    shared.FlashSuccess,
+			Message: "Member removed.",
+		}
+	case r.URL.Query().Get("joined") == "1":
+		flash = &shared.Flash{
+			Type:    shared.FlashSuccess,
+			Message: "You've joined the organization.",
+		}
+	}
+
+	h.renderOrganization(w, r, user, "", nil, flash, http.StatusOK)
+}
+
+// =============================================================================
+// POST /settings/organization/invites - Invite Member
+// =============================================================================
+
+// InviteMember emails an invitation to join the user's organization.
+func (h *SettingsHandler) InviteMember(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	if !csrf.ValidateRequest(r) {
+		http.Error(w, "Invalid form submission. Please refresh the page and try again.", http.StatusForbidden)
+		return
+	}
+
+	address := strings.TrimSpace(r.FormValue("email"))
+	_, err := h.orgService.Invite(r.Context(), domain.InviteMemberParams{
+		InviterID: user.ID,
+		Email:     address,
+	})
+	if err != nil {
+		if domain.ErrorCode(err) == domain.EINVALID {
+			h.renderOrganization(w, r, user, address, map[string]string{"email": domain.ErrorMessage(err)}, nil, http.StatusUnprocessableEntity)
+			return
+		}
+		ErrorResponse(w, r, h.logger, err)
+		return
+	}
+
+	http.Redirect(w, r, "/settings/organization?invited=1", http.StatusSeeOther)
+}
+
+// =============================================================================
+// POST /settings/organization/members/{id}/remove - Remove Member
+// =============================================================================
+
+// RemoveMember removes a member from the owner's organization.
+func (h *SettingsHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	if !csrf.ValidateRequest(r) {
+		http.Error(w, "Invalid form submission. Please refresh the page and try again.", http.StatusForbidden)
+		return
+	}
+
+	memberID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		NotFoundResponse(w, r, h.logger)
+		return
+	}
+
+	if err := h.orgService.RemoveMember(r.Context(), user.ID, memberID); err != nil {
+		ErrorResponse(w, r, h.logger, err)
+		return
+	}
+
+	http.Redirect(w, r, "/settings/organization?removed=1", http.StatusSeeOther)
+}
+
+// =============================================================================
+// GET /organization/join - Show Invitation
+// =============================================================================
+
+// ShowJoinOrganization renders the invitation for a token from an invite
+// email and asks the user to confirm joining.
+func (h *SettingsHandler) ShowJoinOrganization(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	token := r.URL.Query().Get("token")
+	data := settings.OrganizationJoinPageData{
+		CurrentPath: "/organization/join",
+		CSRFToken:   csrf.EnsureToken(w, r, h.isSecure),
+		User:        domainUserToDisplay(user),
+		Token:       token,
+	}
+
+	invite, err := h.orgService.GetInvite(r.Context(), token)
+	if err != nil {
+		if domain.ErrorCode(err) != domain.ENOTFOUND {
+			ErrorResponse(w, r, h.logger, err)
+			return
+		}
+		data.Error = "This invitation link is invalid, has already been used, or has expired. Ask the organization owner to send a new one."
+		h.renderJoinOrganization(w, r, data, http.StatusNotFound)
+		return
+	}
+
+	data.OrganizationName = invite.OrganizationName
+	data.InviteEmail = invite.Email
+	h.renderJoinOrganization(w, r, data, http.StatusOK)
+}
+
+// =============================================================================
+// POST /organization/join - Accept Invitation
+// =============================================================================
+
+// JoinOrganization accepts an invitation for the signed-in user.
+func (h *SettingsHandler) JoinOrganization(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	if !csrf.ValidateRequest(r) {
+		http.Error(w, "Invalid form submission. Please refresh the page and try again.", http.StatusForbidden)
+		return
+	}
+
+	token := r.FormValue("token")
+	if _, err := h.orgService.AcceptInvite(r.Context(), token, user.ID); err != nil {
+		data := settings.OrganizationJoinPageData{
+			CurrentPath: "/organization/join",
+			CSRFToken:   csrf.EnsureToken(w, r, h.isSecure),
+			User:        domainUserToDisplay(user),
+			Token:       token,
+		}
+		switch domain.ErrorCode(err) {
+		case domain.ENOTFOUND:
+			data.Error = "This invitation link is invalid, has already been used, or has expired. Ask the organization owner to send a new one."
+			h.renderJoinOrganization(w, r, data, http.StatusNotFound)
+		case domain.EFORBIDDEN:
+			data.Error = domain.ErrorMessage(err)
+			h.renderJoinOrganization(w, r, data, http.StatusForbidden)
+		case domain.ECONFLICT:
+			data.Error = domain.ErrorMessage(err)
+			h.renderJoinOrganization(w, r, data, http.StatusConflict)
+		default:
+			ErrorResponse(w, r, h.logger, err)
+		}
+		return
+	}
+
+	http.Redirect(w, r, "/settings/organization?joined=1", http.StatusSeeOther)
+}
+
+// =============================================================================
+// Helper Functions
+// =============================================================================
+
+// renderOrganization renders the organization page with the given form state.
+func (h *SettingsHandler) renderOrganization(
+	w http.ResponseWriter,
+	r *http.Request,
+	user *domain.User,
+	formEmail string,
+	errors map[string]string,
+	flash *shared.Flash,
+	status int,
+) {
+	membership, err := h.orgService.Membership(r.Context(), user.ID)
+	if err != nil {
+		ServerErrorResponse(w, r, h.logger.With("user_id", user.ID), fmt.Errorf("get organization: %w", err))
+		return
+	}
+	members, err := h.orgService.ListMembers(r.Context(), user.ID)
+	if err != nil {
+		ServerErrorResponse(w, r, h.logger.With("user_id", user.ID), fmt.Errorf("list organization members: %w", err))
+		return
+	}
+	invites, err := h.orgService.ListPendingInvites(r.Context(), user.ID)
+	if err != nil {
+		ServerErrorResponse(w, r, h.logger.With("user_id", user.ID), fmt.Errorf("list organization invitations: %w", err))
+		return
+	}
+
+	data := settings.OrganizationPageData{
+		CurrentPath:      "/settings/organization",
+		CSRFToken:        csrf.EnsureToken(w, r, h.isSecure),
+		User:             domainUserToDisplay(user),
+		OrganizationName: membership.OrganizationName,
+		IsOwner:          membership.IsOwner(),
+		Members:          make([]settings.OrganizationMemberDisplay, 0, len(members)),
+		Invites:          make([]settings.OrganizationInviteDisplay, 0, len(invites)),
+		FormEmail:        formEmail,
+		Errors:           errors,
+		Flash:            flash,
+		ActiveTab:        settings.TabOrganization,
+	}
+	for _, m := range members {
+		display := settings.OrganizationMemberDisplay{
+			ID:        m.UserID.String(),
+			Name:      m.Name,
+			Email:     m.Email,
+			Role:      string(m.Role),
+			Removable: membership.IsOwner() && m.Role != domain.OrganizationRoleOwner,
+		}
+		if !m.JoinedAt.IsZero() {
+			display.JoinedAt = m.JoinedAt.Format("Jan 2, 2006")
+		}
+		data.Members = append(data.Members, display)
+	}
+	for _, inv := range invites {
+		data.Invites = append(data.Invites, settings.OrganizationInviteDisplay{
+			Email:     inv.Email,
+			ExpiresAt: inv.ExpiresAt.Format("Jan 2, 2006"),
+		})
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if err := settings.OrganizationPage(data).Render(r.Context(), w); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to render organization page", "error", err)
+	}
+}
+
+// renderJoinOrganization renders the page for accepting an invitation.
+func (h *SettingsHandler) renderJoinOrganization(w http.ResponseWriter, r *http.Request, data settings.OrganizationJoinPageData, status int) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if err := settings.OrganizationJoinPage(data).Render(r.Context(), w); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to render join organization page", "error", err)
+	}
+}
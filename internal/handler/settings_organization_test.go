@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/google/uuid"
+)
+
+// mockOrganizationService is a mock implementation of service.OrganizationService.
+// Unset functions behave like a user alone in a personal organization.
+type mockOrganizationService struct {
+	MembershipFunc         func(ctx context.Context, userID uuid.UUID) (*domain.Membership, error)
+	ListMembersFunc        func(ctx context.Context, userID uuid.UUID) ([]domain.OrganizationMember, error)
+	ListPendingInvitesFunc func(ctx context.Context, userID uuid.UUID) ([]domain.OrganizationInvite, error)
+	InviteFunc             func(ctx context.Context, params domain.InviteMemberParams) (*domain.OrganizationInvite, error)
+	GetInviteFunc          func(ctx context.Context, token string) (*domain.OrganizationInvite, error)
+	AcceptInviteFunc       func(ctx context.Context, token string, userID uuid.UUID) (*domain.Membership, error)
+	RemoveMemberFunc       func(ctx context.Context, ownerID, memberID uuid.UUID) error
+}
+
+func (m *mockOrganizationService) Membership(ctx context.Context, userID uuid.UUID) (*domain.Membership, error) {
+	if m.MembershipFunc != nil {
+		return m.MembershipFunc(ctx, userID)
+	}
+	return &domain.Membership{OrganizationName: "Personal", Role: domain.OrganizationRoleOwner, Personal: true}, nil
+}
+
+func (m *mockOrganizationService) ListMembers(ctx context.Context, userID uuid.UUID) ([]domain.OrganizationMember, error) {
+	if m.ListMembersFunc != nil {
+		return m.ListMembersFunc(ctx, userID)
+	}
+	return []domain.OrganizationMember{{UserID: userID, Role: domain.OrganizationRoleOwner}}, nil
+}
+
+func (m *mockOrganizationService) ListPendingInvites(ctx context.Context, userID uuid.UUID) ([]domain.OrganizationInvite, error) {
+	if m.ListPendingInvitesFunc != nil {
+		return m.ListPendingInvitesFunc(ctx, userID)
+	}
+	return []domain.OrganizationInvite{}, nil
+}
+
+func (m *mockOrganizationService) Invite(ctx context.Context, params domain.InviteMemberParams) (*domain.OrganizationInvite, error) {
+	if m.InviteFunc != nil {
+		return m.InviteFunc(ctx, params)
+	}
+	return &domain.OrganizationInvite{Email: params.Email}, nil
+}
+
+func (m *mockOrganizationService) GetInvite(ctx context.Context, token string) (*domain.OrganizationInvite, error) {
+	if m.GetInviteFunc != nil {
+		return m.GetInviteFunc(ctx, token)
+	}
+	return nil, domain.NotFound("organization.get_invite", "invitation", "")
+}
+
+func (m *mockOrganizationService) AcceptInvite(ctx context.Context, token string, userID uuid.UUID) (*domain.Membership, error) {
+	if m.AcceptInviteFunc != nil {
+		return m.AcceptInviteFunc(ctx, token, userID)
+	}
+	return nil, domain.NotFound("organization.accept_invite", "invitation", "")
+}
+
+func (m *mockOrganizationService) RemoveMember(ctx context.Context, ownerID, memberID uuid.UUID) error {
+	if m.RemoveMemberFunc != nil {
+		return m.RemoveMemberFunc(ctx, ownerID, memberID)
+	}
+	return nil
+}
+
+func TestShowOrganizationTempl_OwnerSeesMembersAndInvites(t *testing.T) {
+	owner, member := uuid.New(), uuid.New()
+	orgs := &mockOrganizationService{
+		MembershipFunc: func(ctx context.Context, userID uuid.UUID) (*domain.Membership, error) {
+			return &domain.Membership{OrganizationID: uuid.New(), OrganizationName: "Acme Safety", Role: domain.OrganizationRoleOwner}, nil
+		},
+		ListMembersFunc: func(ctx context.Context, userID uuid.UUID) ([]domain.OrganizationMember, error) {
+			return []domain.OrganizationMember{
+				{UserID: owner, Name: "Olive Owner", Email: "olive@example.com", Role: domain.OrganizationRoleOwner, JoinedAt: time.Now()},
+				{UserID: member, Name: "Max Member", Email: "max@example.com", Role: domain.OrganizationRoleMember, JoinedAt: time.Now()},
+			}, nil
+		},
+		ListPendingInvitesFunc: func(ctx context.Context, userID uuid.UUID) ([]domain.OrganizationInvite, error) {
+			return []domain.OrganizationInvite{{Email: "pat@example.com", ExpiresAt: time.Now().Add(domain.OrganizationInviteTTL)}}, nil
+		},
+	}
+	h := NewSettingsHandler(&mockUserService{}, &mockQuotaService{}, nil, nil, orgs, newTestLogger(), false)
+
+	req := httptest.NewRequest(http.MethodGet, "/settings/organization", nil)
+	req = withSessionsTestUser(req, &domain.User{ID: owner, Email: "olive@example.com"})
+	rr := httptest.NewRecorder()
+	h.ShowOrganizationTempl(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	body := rr.Body.String()
+	for _, want := range []string{
+		"Acme Safety",
+		"Max Member",
+		"pat@example.com",
+		"/settings/organization/members/" + member.String() + "/remove",
+		"Send invitation",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected organization page to contain %q", want)
+		}
+	}
+	if strings.Contains(body, "/settings/organization/members/"+owner.String()+"/remove") {
+		t.Error("expected the owner not to be removable")
+	}
+}
+
+func TestShowOrganizationTempl_MemberCannotInvite(t *testing.T) {
+	orgs := &mockOrganizationService{
+		MembershipFunc: func(ctx context.Context, userID uuid.UUID) (*domain.Membership, error) {
+			return &domain.Membership{OrganizationID: uuid.New(), OrganizationName: "Acme Safety", Role: domain.OrganizationRoleMember}, nil
+		},
+	}
+	h := NewSettingsHandler(&mockUserService{}, &mockQuotaService{}, nil, nil, orgs, newTestLogger(), false)
+
+	req := httptest.NewRequest(http.MethodGet, "/settings/organization", nil)
+	req = withSessionsTestUser(req, &domain.User{ID: uuid.New()})
+	rr := httptest.NewRecorder()
+	h.ShowOrganizationTempl(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	if strings.Contains(rr.Body.String(), "Send invitation") {
+		t.Error("expected members not to see the invite form")
+	}
+}
+
+func TestInviteMember_InvalidEmailRerendersForm(t *testing.T) {
+	orgs := &mockOrganizationService{
+		InviteFunc: func(ctx context.Context, params domain.InviteMemberParams) (*domain.OrganizationInvite, error) {
+			return nil, domain.Invalid("organization.invite", "Please enter a valid email address.")
+		},
+	}
+	h := NewSettingsHandler(&mockUserService{}, &mockQuotaService{}, nil, nil, orgs, newTestLogger(), false)
+
+	req := newPasswordFormRequest("/settings/organization/invites", url.Values{"email": {"not-an-email"}})
+	req = withSessionsTestUser(req, &domain.User{ID: uuid.New()})
+	rr := httptest.NewRecorder()
+	h.InviteMember(rr, req)
+
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status 422, got %d", rr.Code)
+	}
+	body := rr.Body.String()
+	if !strings.Contains(body, "Please enter a valid email address.") || !strings.Contains(body, `value="not-an-email"`) {
+		t.Error("expected the error and submitted email to be shown")
+	}
+}
+
+func TestInviteMember_Success(t *testing.T) {
+	inviter := uuid.New()
+	var got domain.InviteMemberParams
+	orgs := &mockOrganizationService{
+		InviteFunc: func(ctx context.Context, params domain.InviteMemberParams) (*domain.OrganizationInvite, error) {
+			got = params
+			return &domain.OrganizationInvite{Email: params.Email}, nil
+		},
+	}
+	h := NewSettingsHandler(&mockUserService{}, &mockQuotaService{}, nil, nil, orgs, newTestLogger(), false)
+
+	req := newPasswordFormRequest("/settings/organization/invites", url.Values{"email": {" pat@example.com "}})
+	req = withSessionsTestUser(req, &domain.User{ID: inviter})
+	rr := httptest.NewRecorder()
+	h.InviteMember(rr, req)
+
+	if rr.Code != http.StatusSeeOther || rr.Header().Get("Location") != "/settings/organization?invited=1" {
+		t.Fatalf("expected redirect to organization page, got %d %q", rr.Code, rr.Header().Get("Location"))
+	}
+	if got.InviterID != inviter || got.Email != "pat@example.com" {
+		t.Errorf("unexpected invite params: %+v", got)
+	}
+}
+
+func TestShowJoinOrganization_InvalidToken(t *testing.T) {
+	h := NewSettingsHandler(&mockUserService{}, &mockQuotaService{}, nil, nil, &mockOrganizationService{}, newTestLogger(), false)
+
+	req := httptest.NewRequest(http.MethodGet, "/organization/join?token=bogus", nil)
+	req = withSessionsTestUser(req, &domain.User{ID: uuid.New()})
+	rr := httptest.NewRecorder()
+	h.ShowJoinOrganization(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), "Invitation unavailable") {
+		t.Error("expected the invitation to be reported unavailable")
+	}
+}
+
+func TestJoinOrganization_WrongAddress(t *testing.T) {
+	orgs := &mockOrganizationService{
+		AcceptInviteFunc: func(ctx context.Context, token string, userID uuid.UUID) (*domain.Membership, error) {
+			return nil, domain.Forbidden("organization.accept_invite", "This invitation was sent to pat@example.com. Sign in with that address to accept it.")
+		},
+	}
+	h := NewSettingsHandler(&mockUserService{}, &mockQuotaService{}, nil, nil, orgs, newTestLogger(), false)
+
+	req := newPasswordFormRequest("/organization/join", url.Values{"token": {"abc"}})
+	req = withSessionsTestUser(req, &domain.User{ID: uuid.New(), Email: "other@example.com"})
+	rr := httptest.NewRecorder()
+	h.JoinOrganization(rr, req)
+
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected status 403, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), "Sign in with that address") {
+		t.Error("expected the address mismatch to be explained")
+	}
+}
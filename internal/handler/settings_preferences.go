@@ -0,0 +1,141 @@
+// Package handler contains HTTP handlers for the Lukaut application.
+//
+// This file implements the preferences settings page, where users choose the
+// units and date format of their inspections and reports.
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/auth"
+	"github.com/DukeRupert/lukaut/internal/csrf"
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/templ/pages/settings"
+	"github.com/DukeRupert/lukaut/internal/templ/shared"
+)
+
+// preferenceExampleDate is the date each date format is shown with.
+var preferenceExampleDate = time.Date(2025, time.March, 5, 0, 0, 0, 0, time.UTC)
+
+// =============================================================================
+// GET /settings/preferences - Units and Dates
+// =============================================================================
+
+// ShowPreferencesTempl renders the user's unit system and date format.
+func (h *SettingsHandler) ShowPreferencesTempl(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	format := domain.DefaultDisplayFormat()
+	if h.preferences != nil {
+		format = h.preferences.DisplayFormat(r.Context(), user.ID)
+	}
+
+	var flash *shared.Flash
+	if r.URL.Query().Get("updated") == "1" {
+		flash = &shared.Flash{
+			Type:    shared.FlashSuccess,
+			Message: "Preferences saved.",
+		}
+	}
+
+	h.renderPreferences(w, r, user, format, nil, flash, http.StatusOK)
+}
+
+// =============================================================================
+// POST /settings/preferences - Save Units and Dates
+// =============================================================================
+
+// UpdatePreferences saves the user's unit system and date format.
+func (h *SettingsHandler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	if !csrf.ValidateRequest(r) {
+		http.Error(w, "Invalid form submission. Please refresh the page and try again.", http.StatusForbidden)
+		return
+	}
+
+	if h.preferences == nil {
+		NotFoundResponse(w, r, h.logger)
+		return
+	}
+
+	format := domain.DisplayFormat{
+		Units:      domain.UnitSystem(r.FormValue("units")),
+		DateLocale: domain.DateLocale(r.FormValue("date_locale")),
+	}
+
+	errors := make(map[string]string)
+	if !format.Units.IsValid() {
+		errors["units"] = "Choose a unit system"
+	}
+	if !format.DateLocale.IsValid() {
+		errors["date_locale"] = "Choose a date format"
+	}
+	if len(errors) > 0 {
+		h.renderPreferences(w, r, user, format, errors, nil, http.StatusUnprocessableEntity)
+		return
+	}
+
+	if err := h.preferences.SetDisplayFormat(r.Context(), user.ID, format); err != nil {
+		ErrorResponse(w, r, h.logger, err)
+		return
+	}
+
+	http.Redirect(w, r, "/settings/preferences?updated=1", http.StatusSeeOther)
+}
+
+// =============================================================================
+// Helper Functions
+// =============================================================================
+
+// renderPreferences renders the preferences page with the given selection.
+func (h *SettingsHandler) renderPreferences(
+	w http.ResponseWriter,
+	r *http.Request,
+	user *domain.User,
+	format domain.DisplayFormat,
+	errors map[string]string,
+	flash *shared.Flash,
+	status int,
+) {
+	data := settings.PreferencesPageData{
+		CurrentPath:   "/settings/preferences",
+		CSRFToken:     csrf.EnsureToken(w, r, h.isSecure),
+		User:          domainUserToDisplay(user),
+		Units:         string(format.Units),
+		DateLocale:    string(format.DateLocale),
+		UnitOptions:   make([]settings.PreferenceOption, 0, len(domain.UnitSystems)),
+		LocaleOptions: make([]settings.PreferenceOption, 0, len(domain.DateLocales)),
+		Errors:        errors,
+		Flash:         flash,
+		ActiveTab:     settings.TabPreferences,
+	}
+	for _, units := range domain.UnitSystems {
+		data.UnitOptions = append(data.UnitOptions, settings.PreferenceOption{
+			Value: string(units),
+			Label: units.Label(),
+		})
+	}
+	for _, locale := range domain.DateLocales {
+		data.LocaleOptions = append(data.LocaleOptions, settings.PreferenceOption{
+			Value:   string(locale),
+			Label:   locale.Label(),
+			Example: locale.FormatDate(preferenceExampleDate),
+		})
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if err := settings.PreferencesPage(data).Render(r.Context(), w); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to render preferences page", "error", err)
+	}
+}
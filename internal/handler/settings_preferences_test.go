@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/google/uuid"
+)
+
+func TestShowPreferencesTempl_ShowsSavedFormat(t *testing.T) {
+	prefs := &mockPreferenceService{format: &domain.DisplayFormat{Units: domain.UnitsMetric, DateLocale: domain.DateLocaleDeDE}}
+	h := NewSettingsHandler(&mockUserService{}, &mockQuotaService{}, nil, nil, nil, newTestLogger(), false).
+		WithPreferences(prefs)
+
+	req := httptest.NewRequest(http.MethodGet, "/settings/preferences", nil)
+	req = withSessionsTestUser(req, &domain.User{ID: uuid.New()})
+	rr := httptest.NewRecorder()
+	h.ShowPreferencesTempl(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	body := rr.Body.String()
+	for _, want := range []string{
+		`<option value="metric" selected>`,
+		`<option value="de-DE" selected>`,
+		"5. März 2025",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected preferences page to contain %q", want)
+		}
+	}
+}
+
+func TestUpdatePreferences(t *testing.T) {
+	tests := []struct {
+		name       string
+		units      string
+		locale     string
+		wantStatus int
+		wantSaved  *domain.DisplayFormat
+	}{
+		{
+			name:       "valid format is saved",
+			units:      "metric",
+			locale:     "en-GB",
+			wantStatus: http.StatusSeeOther,
+			wantSaved:  &domain.DisplayFormat{Units: domain.UnitsMetric, DateLocale: domain.DateLocaleEnGB},
+		},
+		{name: "unknown unit system", units: "kelvin", locale: "en-US", wantStatus: http.StatusUnprocessableEntity},
+		{name: "unknown locale", units: "imperial", locale: "xx-XX", wantStatus: http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefs := &mockPreferenceService{}
+			h := NewSettingsHandler(&mockUserService{}, &mockQuotaService{}, nil, nil, nil, newTestLogger(), false).
+				WithPreferences(prefs)
+
+			req := newPasswordFormRequest("/settings/preferences", url.Values{
+				"units":       {tt.units},
+				"date_locale": {tt.locale},
+			})
+			req = withSessionsTestUser(req, &domain.User{ID: uuid.New()})
+			rr := httptest.NewRecorder()
+			h.UpdatePreferences(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rr.Code, rr.Body.String())
+			}
+			if tt.wantSaved == nil {
+				if prefs.format != nil {
+					t.Errorf("expected nothing saved, got %+v", *prefs.format)
+				}
+				return
+			}
+			if prefs.format == nil || *prefs.format != *tt.wantSaved {
+				t.Errorf("expected %+v saved, got %+v", *tt.wantSaved, prefs.format)
+			}
+			if loc := rr.Header().Get("Location"); loc != "/settings/preferences?updated=1" {
+				t.Errorf("expected redirect to preferences page, got %q", loc)
+			}
+		})
+	}
+}
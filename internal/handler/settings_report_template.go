@@ -0,0 +1,191 @@
+// Package handler contains HTTP handlers for the Lukaut application.
+//
+// This file implements the report template settings page, where users edit
+// the introduction, disclaimer, and signature block of their reports.
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/auth"
+	"github.com/DukeRupert/lukaut/internal/csrf"
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/templ/pages/settings"
+	"github.com/DukeRupert/lukaut/internal/templ/shared"
+)
+
+// reportTemplateSampleClient is the client name the preview is shown with.
+const reportTemplateSampleClient = "Acme Construction"
+
+// =============================================================================
+// GET /settings/report-template - Report Boilerplate
+// =============================================================================
+
+// ShowReportTemplateTempl renders the user's report sections.
+func (h *SettingsHandler) ShowReportTemplateTempl(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	tmpl := domain.DefaultReportTemplate()
+	if h.reportTemplates != nil {
+		tmpl = h.reportTemplates.Get(r.Context(), user.ID)
+	}
+
+	var flash *shared.Flash
+	if r.URL.Query().Get("updated") == "1" {
+		flash = &shared.Flash{
+			Type:    shared.FlashSuccess,
+			Message: "Report template saved.",
+		}
+	}
+
+	h.renderReportTemplate(w, r, user, tmpl, nil, flash, http.StatusOK)
+}
+
+// =============================================================================
+// POST /settings/report-template - Save Report Boilerplate
+// =============================================================================
+
+// UpdateReportTemplate saves the user's report sections. Sections with an
+// unknown placeholder are rejected with the form shown again.
+func (h *SettingsHandler) UpdateReportTemplate(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	if !csrf.ValidateRequest(r) {
+		http.Error(w, "Invalid form submission. Please refresh the page and try again.", http.StatusForbidden)
+		return
+	}
+
+	if h.reportTemplates == nil {
+		NotFoundResponse(w, r, h.logger)
+		return
+	}
+
+	tmpl := reportTemplateFromForm(r)
+	if errors := reportTemplateErrors(tmpl); len(errors) > 0 {
+		h.renderReportTemplate(w, r, user, tmpl, errors, nil, http.StatusUnprocessableEntity)
+		return
+	}
+
+	if err := h.reportTemplates.Save(r.Context(), user.ID, tmpl); err != nil {
+		ErrorResponse(w, r, h.logger, err)
+		return
+	}
+
+	http.Redirect(w, r, "/settings/report-template?updated=1", http.StatusSeeOther)
+}
+
+// =============================================================================
+// POST /settings/report-template/preview - Preview Report Boilerplate
+// =============================================================================
+
+// PreviewReportTemplate renders the submitted sections with sample values,
+// for the live preview as the user types. Nothing is saved.
+func (h *SettingsHandler) PreviewReportTemplate(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUser(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	preview := h.reportTemplatePreview(r.Context(), user, reportTemplateFromForm(r))
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := settings.ReportTemplatePreview(preview).Render(r.Context(), w); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to render report template preview", "error", err)
+	}
+}
+
+// =============================================================================
+// Helper Functions
+// =============================================================================
+
+// renderReportTemplate renders the report template page with the given sections.
+func (h *SettingsHandler) renderReportTemplate(
+	w http.ResponseWriter,
+	r *http.Request,
+	user *domain.User,
+	tmpl domain.ReportTemplate,
+	errors map[string]string,
+	flash *shared.Flash,
+	status int,
+) {
+	data := settings.ReportTemplatePageData{
+		CurrentPath: "/settings/report-template",
+		CSRFToken:   csrf.EnsureToken(w, r, h.isSecure),
+		User:        domainUserToDisplay(user),
+		Form: settings.ReportTemplateFormData{
+			Intro:      tmpl.Intro,
+			Disclaimer: tmpl.Disclaimer,
+			Signature:  tmpl.Signature,
+		},
+		Placeholders: domain.ReportPlaceholders,
+		Preview:      h.reportTemplatePreview(r.Context(), user, tmpl),
+		Errors:       errors,
+		Flash:        flash,
+		ActiveTab:    settings.TabReports,
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if err := settings.ReportTemplatePage(data).Render(r.Context(), w); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to render report template page", "error", err)
+	}
+}
+
+// reportTemplatePreview renders tmpl with a sample client, today's date in
+// the user's date format, and the user's name.
+func (h *SettingsHandler) reportTemplatePreview(ctx context.Context, user *domain.User, tmpl domain.ReportTemplate) settings.ReportTemplatePreviewData {
+	format := domain.DefaultDisplayFormat()
+	if h.preferences != nil {
+		format = h.preferences.DisplayFormat(ctx, user.ID)
+	}
+
+	sample := &domain.ReportData{
+		InspectorName:  user.Name,
+		ClientName:     reportTemplateSampleClient,
+		InspectionDate: time.Now(),
+		DateLocale:     format.DateLocale,
+	}
+	rendered := tmpl.Render(sample.PlaceholderValues())
+
+	return settings.ReportTemplatePreviewData{
+		Intro:      rendered.Intro,
+		Disclaimer: rendered.Disclaimer,
+		Signature:  rendered.Signature,
+		Errors:     reportTemplateErrors(tmpl),
+	}
+}
+
+// reportTemplateFromForm reads the report sections from the form.
+func reportTemplateFromForm(r *http.Request) domain.ReportTemplate {
+	return domain.ReportTemplate{
+		Intro:      r.FormValue("intro"),
+		Disclaimer: r.FormValue("disclaimer"),
+		Signature:  r.FormValue("signature"),
+	}
+}
+
+// reportTemplateErrors validates each section, keyed by form field.
+func reportTemplateErrors(tmpl domain.ReportTemplate) map[string]string {
+	errors := make(map[string]string)
+	for field, text := range map[string]string{
+		"intro":      tmpl.Intro,
+		"disclaimer": tmpl.Disclaimer,
+		"signature":  tmpl.Signature,
+	} {
+		if err := domain.ValidateReportTemplateText(text); err != nil {
+			errors[field] = domain.ErrorMessage(err)
+		}
+	}
+	return errors
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/google/uuid"
+)
+
+// mockReportTemplateService records saved report templates.
+type mockReportTemplateService struct {
+	tmpl  *domain.ReportTemplate
+	saved *domain.ReportTemplate
+}
+
+func (m *mockReportTemplateService) Get(ctx context.Context, userID uuid.UUID) domain.ReportTemplate {
+	if m.tmpl != nil {
+		return *m.tmpl
+	}
+	return domain.DefaultReportTemplate()
+}
+
+func (m *mockReportTemplateService) Save(ctx context.Context, userID uuid.UUID, tmpl domain.ReportTemplate) error {
+	if err := tmpl.Validate(); err != nil {
+		return err
+	}
+	m.saved = &tmpl
+	return nil
+}
+
+func newReportTemplateTestHandler(templates *mockReportTemplateService) *SettingsHandler {
+	return NewSettingsHandler(&mockUserService{}, &mockQuotaService{}, nil, nil, nil, newTestLogger(), false).
+		WithReportTemplates(templates)
+}
+
+func TestShowReportTemplateTempl_PreviewsSavedSections(t *testing.T) {
+	templates := &mockReportTemplateService{tmpl: &domain.ReportTemplate{
+		Intro:     "Prepared for {{ClientName}}.",
+		Signature: "Signed, {{InspectorName}}",
+	}}
+	h := newReportTemplateTestHandler(templates)
+
+	req := httptest.NewRequest(http.MethodGet, "/settings/report-template", nil)
+	req = withSessionsTestUser(req, &domain.User{ID: uuid.New(), Name: "Pat Inspector"})
+	rr := httptest.NewRecorder()
+	h.ShowReportTemplateTempl(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	body := rr.Body.String()
+	for _, want := range []string{
+		"Prepared for {{ClientName}}.</textarea>",
+		"Prepared for Acme Construction.",
+		"Signed, Pat Inspector",
+		"Left out of the report",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected report template page to contain %q", want)
+		}
+	}
+}
+
+func TestUpdateReportTemplate(t *testing.T) {
+	tests := []struct {
+		name       string
+		intro      string
+		wantStatus int
+		wantError  string
+	}{
+		{name: "known placeholders are saved", intro: "Inspected for {{ ClientName }} on {{InspectionDate}}.", wantStatus: http.StatusSeeOther},
+		{name: "unknown placeholder", intro: "Site: {{SiteName}}", wantStatus: http.StatusUnprocessableEntity, wantError: "{{SiteName}} is not a placeholder"},
+		{name: "unclosed placeholder", intro: "Prepared for {{ClientName", wantStatus: http.StatusUnprocessableEntity, wantError: "is not closed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			templates := &mockReportTemplateService{}
+			h := newReportTemplateTestHandler(templates)
+
+			req := newPasswordFormRequest("/settings/report-template", url.Values{
+				"intro":      {tt.intro},
+				"disclaimer": {"No warranty."},
+				"signature":  {""},
+			})
+			req = withSessionsTestUser(req, &domain.User{ID: uuid.New()})
+			rr := httptest.NewRecorder()
+			h.UpdateReportTemplate(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rr.Code, rr.Body.String())
+			}
+			if tt.wantError != "" {
+				if templates.saved != nil {
+					t.Errorf("expected nothing saved, got %+v", *templates.saved)
+				}
+				if !strings.Contains(rr.Body.String(), tt.wantError) {
+					t.Errorf("expected error %q in response", tt.wantError)
+				}
+				return
+			}
+			want := domain.ReportTemplate{Intro: tt.intro, Disclaimer: "No warranty."}
+			if templates.saved == nil || *templates.saved != want {
+				t.Errorf("expected %+v saved, got %+v", want, templates.saved)
+			}
+			if loc := rr.Header().Get("Location"); loc != "/settings/report-template?updated=1" {
+				t.Errorf("expected redirect to report template page, got %q", loc)
+			}
+		})
+	}
+}
+
+func TestPreviewReportTemplate_EscapesAndFlagsErrors(t *testing.T) {
+	h := newReportTemplateTestHandler(&mockReportTemplateService{})
+
+	req := newPasswordFormRequest("/settings/report-template/preview", url.Values{
+		"intro":      {"<b>Hello</b> {{ClientName}}"},
+		"disclaimer": {"{{Weather}}"},
+		"signature":  {"{{InspectorName}}"},
+	})
+	req = withSessionsTestUser(req, &domain.User{ID: uuid.New(), Name: "Pat & Co"})
+	rr := httptest.NewRecorder()
+	h.PreviewReportTemplate(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	body := rr.Body.String()
+	for _, want := range []string{
+		"&lt;b&gt;Hello&lt;/b&gt; Acme Construction",
+		"{{Weather}} is not a placeholder",
+		"Pat &amp; Co",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected preview to contain %q, got %s", want, body)
+		}
+	}
+	if strings.Contains(body, "<b>Hello</b>") {
+		t.Error("preview rendered section text as markup")
+	}
+}
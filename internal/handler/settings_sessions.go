@@ -0,0 +1,215 @@
+// Package handler contains HTTP handlers for the Lukaut application.
+//
+// This file implements the session management settings page, where users see
+// where they are signed in and revoke sessions.
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/DukeRupert/lukaut/internal/auth"
+	"github.com/DukeRupert/lukaut/internal/csrf"
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/session"
+	"github.com/DukeRupert/lukaut/internal/templ/pages/settings"
+	"github.com/DukeRupert/lukaut/internal/templ/shared"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// GET /settings/sessions - List Sessions
+// =============================================================================
+
+// ShowSessionsTempl renders the user's active sessions.
+func (h *SettingsHandler) ShowSessionsTempl(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	sessions, err := h.userService.ListSessions(r.Context(), user.ID)
+	if err != nil {
+		ServerErrorResponse(w, r, h.logger.With("user_id", user.ID), fmt.Errorf("list sessions: %w", err))
+		return
+	}
+
+	// Check for success flash from query params
+	var flash *shared.Flash
+	if r.URL.Query().Get("revoked") == "1" {
+		flash = &shared.Flash{
+			Type:    shared.FlashSuccess,
+			Message: "Session signed out.",
+		}
+	} else if n, err := strconv.Atoi(r.URL.Query().Get("revoked_others")); err == nil {
+		noun := "sessions"
+		if n == 1 {
+			noun = "session"
+		}
+		flash = &shared.Flash{
+			Type:    shared.FlashSuccess,
+			Message: fmt.Sprintf("Signed out of %d other %s.", n, noun),
+		}
+	}
+
+	display := make([]settings.SessionDisplay, 0, len(sessions))
+	for _, s := range sessions {
+		display = append(display, domainSessionToDisplay(s))
+	}
+
+	data := settings.SessionsPageData{
+		CurrentPath: r.URL.Path,
+		CSRFToken:   csrf.EnsureToken(w, r, h.isSecure),
+		User:        domainUserToDisplay(user),
+		Sessions:    display,
+		Flash:       flash,
+		ActiveTab:   settings.TabSessions,
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := settings.SessionsPage(data).Render(r.Context(), w); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to render sessions page", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// =============================================================================
+// POST /settings/sessions/{id}/revoke - Revoke Session
+// =============================================================================
+
+// RevokeSession signs out one session. Revoking the current session signs
+// the user out and redirects to the login page.
+func (h *SettingsHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	if !csrf.ValidateRequest(r) {
+		http.Error(w, "Invalid form submission. Please refresh the page and try again.", http.StatusForbidden)
+		return
+	}
+
+	sessionID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		NotFoundResponse(w, r, h.logger)
+		return
+	}
+
+	// Find out whether this is the current session before it is gone
+	current := false
+	if sessions, err := h.userService.ListSessions(r.Context(), user.ID); err == nil {
+		for _, s := range sessions {
+			if s.ID == sessionID && s.Current {
+				current = true
+			}
+		}
+	}
+
+	if err := h.userService.RevokeSession(r.Context(), user.ID, sessionID); err != nil {
+		ErrorResponse(w, r, h.logger, err)
+		return
+	}
+
+	if current {
+		session.Clear(w, session.Options{Secure: h.isSecure})
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	http.Redirect(w, r, "/settings/sessions?revoked=1", http.StatusSeeOther)
+}
+
+// =============================================================================
+// POST /settings/sessions/revoke-others - Sign Out Everywhere Else
+// =============================================================================
+
+// RevokeOtherSessions signs out every session except the current one.
+func (h *SettingsHandler) RevokeOtherSessions(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	if !csrf.ValidateRequest(r) {
+		http.Error(w, "Invalid form submission. Please refresh the page and try again.", http.StatusForbidden)
+		return
+	}
+
+	n, err := h.userService.RevokeOtherSessions(r.Context(), user.ID)
+	if err != nil {
+		ErrorResponse(w, r, h.logger, err)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/settings/sessions?revoked_others=%d", n), http.StatusSeeOther)
+}
+
+// =============================================================================
+// Helper Functions
+// =============================================================================
+
+// domainSessionToDisplay converts a domain.Session to settings.SessionDisplay.
+func domainSessionToDisplay(s domain.Session) settings.SessionDisplay {
+	ip := s.IPAddress
+	if ip == "" {
+		ip = "Unknown location"
+	}
+	return settings.SessionDisplay{
+		ID:         s.ID.String(),
+		Device:     describeUserAgent(s.UserAgent),
+		IPAddress:  ip,
+		LastSeenAt: s.LastSeenAt.Format("Jan 2, 2006 3:04 PM"),
+		CreatedAt:  s.CreatedAt.Format("Jan 2, 2006"),
+		Current:    s.Current,
+	}
+}
+
+// describeUserAgent summarizes a User-Agent header as "Browser on OS".
+// It recognizes the common browsers and platforms only.
+func describeUserAgent(ua string) string {
+	if ua == "" {
+		return "Unknown device"
+	}
+
+	// Order matters: Edge and Opera also send "Chrome", Chrome also sends "Safari"
+	browser := "Unknown browser"
+	for _, b := range []struct{ token, name string }{
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+	} {
+		if strings.Contains(ua, b.token) {
+			browser = b.name
+			break
+		}
+	}
+
+	// iPhone and iPad UAs contain "Mac OS X"; Android UAs contain "Linux"
+	os := ""
+	for _, o := range []struct{ token, name string }{
+		{"iPhone", "iOS"},
+		{"iPad", "iPadOS"},
+		{"Android", "Android"},
+		{"Windows", "Windows"},
+		{"Mac OS X", "macOS"},
+		{"Linux", "Linux"},
+	} {
+		if strings.Contains(ua, o.token) {
+			os = o.name
+			break
+		}
+	}
+
+	if os == "" {
+		return browser
+	}
+	return browser + " on " + os
+}
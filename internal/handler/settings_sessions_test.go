@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/auth"
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/session"
+	"github.com/google/uuid"
+)
+
+// withSessionsTestUser returns a request authenticated as user.
+func withSessionsTestUser(req *http.Request, user *domain.User) *http.Request {
+	return req.WithContext(auth.SetUser(req.Context(), user))
+}
+
+// twoSessionsService returns a mock whose user has a current laptop session
+// and a phone session.
+func twoSessionsService(current, other uuid.UUID) *mockUserService {
+	return &mockUserService{
+		ListSessionsFunc: func(ctx context.Context, userID uuid.UUID) ([]domain.Session, error) {
+			return []domain.Session{
+				{
+					ID:         current,
+					UserAgent:  "Mozilla/5.0 (Macintosh; Intel Mac OS X 14_5) AppleWebKit/537.36 Chrome/126.0 Safari/537.36",
+					IPAddress:  "203.0.113.7",
+					LastSeenAt: time.Now(),
+					CreatedAt:  time.Now(),
+					Current:    true,
+				},
+				{
+					ID:         other,
+					UserAgent:  "Mozilla/5.0 (iPhone; CPU iPhone OS 17_5 like Mac OS X) AppleWebKit/605.1.15 Version/17.5 Mobile/15E148 Safari/604.1",
+					LastSeenAt: time.Now().Add(-time.Hour),
+					CreatedAt:  time.Now().Add(-24 * time.Hour),
+				},
+			}, nil
+		},
+	}
+}
+
+func TestShowSessionsTempl_HighlightsCurrentSession(t *testing.T) {
+	current, other := uuid.New(), uuid.New()
+	h := NewSettingsHandler(twoSessionsService(current, other), &mockQuotaService{}, nil, nil, nil, newTestLogger(), false)
+
+	req := httptest.NewRequest(http.MethodGet, "/settings/sessions", nil)
+	req = withSessionsTestUser(req, &domain.User{ID: uuid.New(), Email: "inspector@example.com"})
+	rr := httptest.NewRecorder()
+	h.ShowSessionsTempl(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	body := rr.Body.String()
+	for _, want := range []string{
+		"Chrome on macOS",
+		"Safari on iOS",
+		"203.0.113.7",
+		"This device",
+		"/settings/sessions/" + other.String() + "/revoke",
+		"Sign out everywhere else",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected sessions page to contain %q", want)
+		}
+	}
+	if strings.Count(body, "This device") != 1 {
+		t.Error("expected only the current session to be marked")
+	}
+}
+
+func TestRevokeSession_OtherSession(t *testing.T) {
+	current, other := uuid.New(), uuid.New()
+	mock := twoSessionsService(current, other)
+	var revoked uuid.UUID
+	mock.RevokeSessionFunc = func(ctx context.Context, userID, sessionID uuid.UUID) error {
+		revoked = sessionID
+		return nil
+	}
+	h := NewSettingsHandler(mock, &mockQuotaService{}, nil, nil, nil, newTestLogger(), false)
+
+	req := newPasswordFormRequest("/settings/sessions/"+other.String()+"/revoke", url.Values{})
+	req.SetPathValue("id", other.String())
+	req = withSessionsTestUser(req, &domain.User{ID: uuid.New()})
+	rr := httptest.NewRecorder()
+	h.RevokeSession(rr, req)
+
+	if rr.Code != http.StatusSeeOther || rr.Header().Get("Location") != "/settings/sessions?revoked=1" {
+		t.Fatalf("expected redirect to sessions page, got %d %q", rr.Code, rr.Header().Get("Location"))
+	}
+	if revoked != other {
+		t.Errorf("expected session %s revoked, got %s", other, revoked)
+	}
+	for _, c := range rr.Result().Cookies() {
+		if c.Name == session.CookieName {
+			t.Error("expected session cookie to be kept")
+		}
+	}
+}
+
+func TestRevokeSession_CurrentSessionSignsOut(t *testing.T) {
+	current, other := uuid.New(), uuid.New()
+	mock := twoSessionsService(current, other)
+	mock.RevokeSessionFunc = func(ctx context.Context, userID, sessionID uuid.UUID) error { return nil }
+	h := NewSettingsHandler(mock, &mockQuotaService{}, nil, nil, nil, newTestLogger(), false)
+
+	req := newPasswordFormRequest("/settings/sessions/"+current.String()+"/revoke", url.Values{})
+	req.SetPathValue("id", current.String())
+	req = withSessionsTestUser(req, &domain.User{ID: uuid.New()})
+	rr := httptest.NewRecorder()
+	h.RevokeSession(rr, req)
+
+	if rr.Code != http.StatusSeeOther || rr.Header().Get("Location") != "/login" {
+		t.Fatalf("expected redirect to login, got %d %q", rr.Code, rr.Header().Get("Location"))
+	}
+	cleared := false
+	for _, c := range rr.Result().Cookies() {
+		if c.Name == session.CookieName && c.MaxAge < 0 {
+			cleared = true
+		}
+	}
+	if !cleared {
+		t.Error("expected session cookie to be cleared")
+	}
+}
+
+func TestRevokeSession_RequiresCSRF(t *testing.T) {
+	called := false
+	mock := &mockUserService{
+		RevokeSessionFunc: func(ctx context.Context, userID, sessionID uuid.UUID) error {
+			called = true
+			return nil
+		},
+	}
+	h := NewSettingsHandler(mock, &mockQuotaService{}, nil, nil, nil, newTestLogger(), false)
+
+	id := uuid.New().String()
+	req := httptest.NewRequest(http.MethodPost, "/settings/sessions/"+id+"/revoke", nil)
+	req.SetPathValue("id", id)
+	req = withSessionsTestUser(req, &domain.User{ID: uuid.New()})
+	rr := httptest.NewRecorder()
+	h.RevokeSession(rr, req)
+
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("expected status 403, got %d", rr.Code)
+	}
+	if called {
+		t.Error("expected no session to be revoked")
+	}
+}
+
+func TestRevokeOtherSessions_RedirectsWithCount(t *testing.T) {
+	mock := &mockUserService{
+		RevokeOtherSessionsFunc: func(ctx context.Context, userID uuid.UUID) (int64, error) { return 3, nil },
+	}
+	h := NewSettingsHandler(mock, &mockQuotaService{}, nil, nil, nil, newTestLogger(), false)
+
+	req := newPasswordFormRequest("/settings/sessions/revoke-others", url.Values{})
+	req = withSessionsTestUser(req, &domain.User{ID: uuid.New()})
+	rr := httptest.NewRecorder()
+	h.RevokeOtherSessions(rr, req)
+
+	if rr.Code != http.StatusSeeOther || rr.Header().Get("Location") != "/settings/sessions?revoked_others=3" {
+		t.Errorf("expected redirect with count, got %d %q", rr.Code, rr.Header().Get("Location"))
+	}
+}
+
+func TestDescribeUserAgent(t *testing.T) {
+	tests := []struct {
+		ua   string
+		want string
+	}{
+		{"", "Unknown device"},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 Chrome/126.0 Safari/537.36 Edg/126.0", "Edge on Windows"},
+		{"Mozilla/5.0 (X11; Linux x86_64; rv:127.0) Gecko/20100101 Firefox/127.0", "Firefox on Linux"},
+		{"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 Chrome/126.0 Mobile Safari/537.36", "Chrome on Android"},
+		{"curl/8.5.0", "Unknown browser"},
+	}
+	for _, tt := range tests {
+		if got := describeUserAgent(tt.ua); got != tt.want {
+			t.Errorf("describeUserAgent(%q) = %q, want %q", tt.ua, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/auth"
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// Mock QuotaService Implementation
+// =============================================================================
+
+// mockQuotaService implements the service.QuotaService interface for testing.
+type mockQuotaService struct {
+	GetUsageFunc           func(ctx context.Context, userID uuid.UUID, tier domain.SubscriptionTier) (*domain.QuotaUsage, error)
+	CheckAnalysisQuotaFunc func(ctx context.Context, userID uuid.UUID, tier domain.SubscriptionTier) error
+	CheckReportQuotaFunc   func(ctx context.Context, userID uuid.UUID, tier domain.SubscriptionTier) error
+}
+
+func (m *mockQuotaService) GetUsage(ctx context.Context, userID uuid.UUID, tier domain.SubscriptionTier) (*domain.QuotaUsage, error) {
+	if m.GetUsageFunc != nil {
+		return m.GetUsageFunc(ctx, userID, tier)
+	}
+	return nil, errors.New("GetUsageFunc not implemented")
+}
+
+func (m *mockQuotaService) CheckAnalysisQuota(ctx context.Context, userID uuid.UUID, tier domain.SubscriptionTier) error {
+	if m.CheckAnalysisQuotaFunc != nil {
+		return m.CheckAnalysisQuotaFunc(ctx, userID, tier)
+	}
+	return nil
+}
+
+func (m *mockQuotaService) CheckReportQuota(ctx context.Context, userID uuid.UUID, tier domain.SubscriptionTier) error {
+	if m.CheckReportQuotaFunc != nil {
+		return m.CheckReportQuotaFunc(ctx, userID, tier)
+	}
+	return nil
+}
+
+// =============================================================================
+// Usage Page Tests
+// =============================================================================
+
+func TestShowUsageTempl_RendersCurrentPeriod(t *testing.T) {
+	var gotTier domain.SubscriptionTier
+	quota := &mockQuotaService{
+		GetUsageFunc: func(ctx context.Context, userID uuid.UUID, tier domain.SubscriptionTier) (*domain.QuotaUsage, error) {
+			gotTier = tier
+			return &domain.QuotaUsage{
+				AnalysisUsed:  2,
+				AnalysisLimit: 3,
+				ReportsUsed:   0,
+				ReportsLimit:  2,
+				ResetsAt:      time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC),
+			}, nil
+		},
+	}
+	h := NewSettingsHandler(&mockUserService{}, quota, nil, nil, nil, newTestLogger(), false)
+
+	req := httptest.NewRequest(http.MethodGet, "/settings/usage", nil)
+	user := &domain.User{ID: uuid.New(), Email: "inspector@example.com", SubscriptionStatus: domain.SubscriptionStatusCanceled}
+	req = req.WithContext(auth.SetUser(req.Context(), user))
+	rr := httptest.NewRecorder()
+	h.ShowUsageTempl(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if gotTier != domain.SubscriptionTierFree {
+		t.Errorf("expected free tier for canceled subscription, got %q", gotTier)
+	}
+	body := rr.Body.String()
+	for _, want := range []string{"2 of 3 used", "1 remaining", "resets on February 1, 2026", `aria-valuenow="66"`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected usage page to contain %q", want)
+		}
+	}
+}
+
+func TestShowUsageTempl_Unlimited(t *testing.T) {
+	quota := &mockQuotaService{
+		GetUsageFunc: func(ctx context.Context, userID uuid.UUID, tier domain.SubscriptionTier) (*domain.QuotaUsage, error) {
+			return &domain.QuotaUsage{IsUnlimited: true}, nil
+		},
+	}
+	h := NewSettingsHandler(&mockUserService{}, quota, nil, nil, nil, newTestLogger(), false)
+
+	req := httptest.NewRequest(http.MethodGet, "/settings/usage", nil)
+	req = req.WithContext(auth.SetUser(req.Context(), &domain.User{ID: uuid.New()}))
+	rr := httptest.NewRecorder()
+	h.ShowUsageTempl(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), "unlimited analyses and reports") {
+		t.Error("expected unlimited plan message")
+	}
+}
+
+// =============================================================================
+// Quota Warning Banner Tests
+// =============================================================================
+
+func TestQuotaWarningData(t *testing.T) {
+	resetsAt := time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)
+
+	if got := quotaWarningData(&domain.QuotaUsage{AnalysisUsed: 1, AnalysisLimit: 10, ResetsAt: resetsAt}); got != nil {
+		t.Errorf("expected no banner with plenty of quota left, got %+v", got)
+	}
+	if got := quotaWarningData(&domain.QuotaUsage{IsUnlimited: true}); got != nil {
+		t.Errorf("expected no banner for unlimited plans, got %+v", got)
+	}
+
+	got := quotaWarningData(&domain.QuotaUsage{AnalysisUsed: 2, AnalysisLimit: 3, ResetsAt: resetsAt})
+	if got == nil {
+		t.Fatal("expected banner when one analysis remains")
+	}
+	if got.Remaining != 1 || got.Limit != 3 || got.Exhausted || got.ResetsAt != "February 1, 2026" {
+		t.Errorf("unexpected banner data: %+v", got)
+	}
+
+	got = quotaWarningData(&domain.QuotaUsage{AnalysisUsed: 3, AnalysisLimit: 3, ResetsAt: resetsAt})
+	if got == nil || !got.Exhausted {
+		t.Errorf("expected exhausted banner, got %+v", got)
+	}
+}
+
+func TestErrorCodeToHTTPStatus_Quota(t *testing.T) {
+	if got := ErrorCodeToHTTPStatus(domain.EQUOTA); got != http.StatusTooManyRequests {
+		t.Errorf("expected 429 for quota errors, got %d", got)
+	}
+}
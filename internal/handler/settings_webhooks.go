@@ -0,0 +1,178 @@
+// Package handler contains HTTP handlers for the Lukaut application.
+//
+// This file implements the webhooks settings page, where users register URLs
+// to be notified when analyses complete and reports are ready.
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/DukeRupert/lukaut/internal/auth"
+	"github.com/DukeRupert/lukaut/internal/csrf"
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/templ/pages/settings"
+	"github.com/DukeRupert/lukaut/internal/templ/shared"
+	"github.com/google/uuid"
+)
+
+// webhookDeliveriesShown is the number of recent deliveries on the webhooks page.
+const webhookDeliveriesShown = 20
+
+// =============================================================================
+// GET /settings/webhooks - List Webhooks
+// =============================================================================
+
+// ShowWebhooksTempl renders the user's webhook URLs and recent deliveries.
+func (h *SettingsHandler) ShowWebhooksTempl(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	var flash *shared.Flash
+	switch {
+	case r.URL.Query().Get("added") == "1":
+		flash = &shared.Flash{
+			Type:    shared.FlashSuccess,
+			Message: "Webhook added. Use its signing secret to verify the Lukaut-Signature header.",
+		}
+	case r.URL.Query().Get("removed") == "1":
+		flash = &shared.Flash{
+			Type:    shared.FlashSuccess,
+			Message: "Webhook removed.",
+		}
+	}
+
+	h.renderWebhooks(w, r, user, "", nil, flash, http.StatusOK)
+}
+
+// =============================================================================
+// POST /settings/webhooks - Add Webhook
+// =============================================================================
+
+// CreateWebhook registers a webhook URL for the user.
+func (h *SettingsHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	if !csrf.ValidateRequest(r) {
+		http.Error(w, "Invalid form submission. Please refresh the page and try again.", http.StatusForbidden)
+		return
+	}
+
+	rawURL := strings.TrimSpace(r.FormValue("url"))
+	if _, err := h.webhookService.CreateEndpoint(r.Context(), user.ID, rawURL); err != nil {
+		if domain.ErrorCode(err) == domain.EINVALID {
+			h.renderWebhooks(w, r, user, rawURL, map[string]string{"url": domain.ErrorMessage(err)}, nil, http.StatusUnprocessableEntity)
+			return
+		}
+		ErrorResponse(w, r, h.logger, err)
+		return
+	}
+
+	http.Redirect(w, r, "/settings/webhooks?added=1", http.StatusSeeOther)
+}
+
+// =============================================================================
+// POST /settings/webhooks/{id}/delete - Remove Webhook
+// =============================================================================
+
+// DeleteWebhook removes one of the user's webhook URLs.
+func (h *SettingsHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	if !csrf.ValidateRequest(r) {
+		http.Error(w, "Invalid form submission. Please refresh the page and try again.", http.StatusForbidden)
+		return
+	}
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		NotFoundResponse(w, r, h.logger)
+		return
+	}
+
+	if err := h.webhookService.DeleteEndpoint(r.Context(), id, user.ID); err != nil {
+		ErrorResponse(w, r, h.logger, err)
+		return
+	}
+
+	http.Redirect(w, r, "/settings/webhooks?removed=1", http.StatusSeeOther)
+}
+
+// =============================================================================
+// Helper Functions
+// =============================================================================
+
+// renderWebhooks renders the webhooks page with the given form state.
+func (h *SettingsHandler) renderWebhooks(
+	w http.ResponseWriter,
+	r *http.Request,
+	user *domain.User,
+	formURL string,
+	errors map[string]string,
+	flash *shared.Flash,
+	status int,
+) {
+	endpoints, err := h.webhookService.ListEndpoints(r.Context(), user.ID)
+	if err != nil {
+		ServerErrorResponse(w, r, h.logger.With("user_id", user.ID), fmt.Errorf("list webhooks: %w", err))
+		return
+	}
+	deliveries, err := h.webhookService.ListRecentDeliveries(r.Context(), user.ID, webhookDeliveriesShown)
+	if err != nil {
+		ServerErrorResponse(w, r, h.logger.With("user_id", user.ID), fmt.Errorf("list webhook deliveries: %w", err))
+		return
+	}
+
+	data := settings.WebhooksPageData{
+		CurrentPath:  "/settings/webhooks",
+		CSRFToken:    csrf.EnsureToken(w, r, h.isSecure),
+		User:         domainUserToDisplay(user),
+		Endpoints:    make([]settings.WebhookEndpointDisplay, 0, len(endpoints)),
+		Deliveries:   make([]settings.WebhookDeliveryDisplay, 0, len(deliveries)),
+		MaxEndpoints: domain.MaxWebhookEndpoints,
+		FormURL:      formURL,
+		Errors:       errors,
+		Flash:        flash,
+		ActiveTab:    settings.TabWebhooks,
+	}
+	for _, e := range endpoints {
+		data.Endpoints = append(data.Endpoints, settings.WebhookEndpointDisplay{
+			ID:        e.ID.String(),
+			URL:       e.URL,
+			Secret:    e.Secret,
+			CreatedAt: e.CreatedAt.Format("Jan 2, 2006"),
+		})
+	}
+	for _, d := range deliveries {
+		display := settings.WebhookDeliveryDisplay{
+			Event:     d.Event,
+			URL:       d.EndpointURL,
+			Status:    string(d.Status),
+			Attempts:  d.Attempts,
+			Error:     d.ErrorMessage,
+			CreatedAt: d.CreatedAt.Format("Jan 2, 3:04 PM"),
+		}
+		if d.ResponseStatus != nil {
+			display.Response = fmt.Sprintf("HTTP %d", *d.ResponseStatus)
+		}
+		data.Deliveries = append(data.Deliveries, display)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if err := settings.WebhooksPage(data).Render(r.Context(), w); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to render webhooks page", "error", err)
+	}
+}
@@ -0,0 +1,255 @@
+// Package handler contains HTTP handlers for the Lukaut application.
+//
+// This file implements share link handlers: inspectors create and revoke
+// expiring links, and clients open them without an account.
+package handler
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/auth"
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/service"
+	"github.com/DukeRupert/lukaut/internal/templ/partials"
+	reporttempl "github.com/DukeRupert/lukaut/internal/templ/report"
+	"github.com/google/uuid"
+)
+
+// ShareHandler handles HTTP requests related to inspection share links.
+type ShareHandler struct {
+	inspectionService service.InspectionService
+	reportService     service.ReportService
+	baseURL           string
+	logger            *slog.Logger
+}
+
+// NewShareHandler creates a new ShareHandler.
+func NewShareHandler(
+	inspectionService service.InspectionService,
+	reportService service.ReportService,
+	baseURL string,
+	logger *slog.Logger,
+) *ShareHandler {
+	return &ShareHandler{
+		inspectionService: inspectionService,
+		reportService:     reportService,
+		baseURL:           strings.TrimSuffix(baseURL, "/"),
+		logger:            logger,
+	}
+}
+
+// Create mints a share link for a completed inspection.
+// POST /inspections/{id}/share
+//
+// Form fields:
+// - expires_in_days: Days until the link expires (default 7, max 30)
+// - max_views: Optional view limit
+func (h *ShareHandler) Create(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUserFromRequest(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	inspectionID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid inspection ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	params := domain.CreateShareLinkParams{
+		InspectionID: inspectionID,
+		UserID:       user.ID,
+	}
+	var formErr string
+	if v := strings.TrimSpace(r.FormValue("expires_in_days")); v != "" {
+		days, err := strconv.Atoi(v)
+		if err != nil || days < 1 {
+			formErr = "Expiry must be a whole number of days"
+		}
+		params.TTL = time.Duration(days) * 24 * time.Hour
+	}
+	if v := strings.TrimSpace(r.FormValue("max_views")); v != "" {
+		maxViews, err := strconv.Atoi(v)
+		if err != nil {
+			formErr = "View limit must be a whole number"
+		}
+		params.MaxViews = &maxViews
+	}
+
+	data := partials.ShareLinksData{InspectionID: inspectionID.String()}
+	if formErr != "" {
+		data.Error = formErr
+	} else {
+		result, err := h.inspectionService.CreateShareLink(r.Context(), params)
+		if err != nil {
+			code := domain.ErrorCode(err)
+			if code != domain.EINVALID {
+				h.logger.ErrorContext(r.Context(), "failed to create share link", "error", err, "inspection_id", inspectionID)
+				http.Error(w, domain.ErrorMessage(err), ErrorCodeToHTTPStatus(code))
+				return
+			}
+			data.Error = domain.ErrorMessage(err)
+		} else {
+			data.NewURL = h.shareURL(result.Token)
+		}
+	}
+
+	h.renderLinks(w, r, data)
+}
+
+// List renders the inspection's active share links.
+// GET /inspections/{id}/shares
+func (h *ShareHandler) List(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUserFromRequest(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	inspectionID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid inspection ID", http.StatusBadRequest)
+		return
+	}
+
+	h.renderLinks(w, r, partials.ShareLinksData{InspectionID: inspectionID.String()})
+}
+
+// Revoke revokes a share link and renders the inspection's remaining links.
+// DELETE /shares/{id}
+//
+// Links are addressed by ID rather than token: only the token's hash is
+// stored, so the owner cannot present the raw token after creation.
+func (h *ShareHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUserFromRequest(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	linkID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid share link ID", http.StatusBadRequest)
+		return
+	}
+
+	link, err := h.inspectionService.RevokeShareLink(r.Context(), linkID, user.ID)
+	if err != nil {
+		code := domain.ErrorCode(err)
+		if code == domain.EINTERNAL {
+			h.logger.ErrorContext(r.Context(), "failed to revoke share link", "error", err, "share_link_id", linkID)
+		}
+		http.Error(w, domain.ErrorMessage(err), ErrorCodeToHTTPStatus(code))
+		return
+	}
+
+	h.renderLinks(w, r, partials.ShareLinksData{InspectionID: link.InspectionID.String()})
+}
+
+// View renders the read-only report for a share token. No authentication.
+// GET /shared/{token}
+func (h *ShareHandler) View(w http.ResponseWriter, r *http.Request) {
+	link, err := h.inspectionService.ResolveShareToken(r.Context(), r.PathValue("token"))
+	if err != nil {
+		if domain.ErrorCode(err) == domain.ENOTFOUND {
+			NotFoundResponse(w, r, h.logger)
+			return
+		}
+		InternalErrorResponse(w, r, h.logger, err)
+		return
+	}
+
+	reportData, err := h.reportService.PrepareReportData(r.Context(), link.InspectionID, link.UserID)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to prepare shared report data", "error", err, "share_link_id", link.ID)
+		InternalErrorResponse(w, r, h.logger, err)
+		return
+	}
+
+	// Count the view only once there is a report to show
+	if err := h.inspectionService.RecordShareLinkView(r.Context(), link); err != nil {
+		if domain.ErrorCode(err) == domain.ENOTFOUND {
+			NotFoundResponse(w, r, h.logger)
+			return
+		}
+		InternalErrorResponse(w, r, h.logger, err)
+		return
+	}
+
+	// Shared reports must not be cached by intermediaries or indexed, since
+	// the link can be revoked.
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Robots-Tag", "noindex, nofollow")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := reporttempl.Report(&reporttempl.ReportTemplateData{ReportData: reportData}).Render(r.Context(), w); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to render shared report", "error", err, "share_link_id", link.ID)
+		http.Error(w, "Failed to render report", http.StatusInternalServerError)
+	}
+}
+
+// renderLinks loads the inspection's share links into data and renders the partial.
+func (h *ShareHandler) renderLinks(w http.ResponseWriter, r *http.Request, data partials.ShareLinksData) {
+	user := auth.GetUserFromRequest(r)
+	inspectionID, _ := uuid.Parse(data.InspectionID)
+
+	links, err := h.inspectionService.ListShareLinks(r.Context(), inspectionID, user.ID)
+	if err != nil {
+		ServerErrorResponse(w, r, h.logger.With("inspection_id", inspectionID), fmt.Errorf("list share links: %w", err))
+		return
+	}
+	data.Links = shareLinksToPartial(links)
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := partials.ShareLinks(data).Render(r.Context(), w); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to render share links", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// shareURL builds the public URL for a raw share token.
+func (h *ShareHandler) shareURL(token string) string {
+	return fmt.Sprintf("%s/shared/%s", h.baseURL, token)
+}
+
+// shareLinksToPartial converts share links to display data.
+func shareLinksToPartial(links []domain.ShareLink) []partials.ShareLinkItem {
+	items := make([]partials.ShareLinkItem, len(links))
+	for i, l := range links {
+		views := fmt.Sprintf("%d views", l.ViewCount)
+		if l.ViewCount == 1 {
+			views = "1 view"
+		}
+		if l.MaxViews != nil {
+			views = fmt.Sprintf("%d of %d views", l.ViewCount, *l.MaxViews)
+		}
+
+		items[i] = partials.ShareLinkItem{
+			ID:        l.ID.String(),
+			ExpiresAt: l.ExpiresAt.Format("Jan 2, 2006 3:04 PM"),
+			Views:     views,
+			CreatedAt: l.CreatedAt.Format("Jan 2, 2006"),
+		}
+	}
+	return items
+}
+
+// RegisterRoutes registers share link routes on the provided ServeMux.
+// The owner routes are wrapped with requireUser; GET /shared/{token} is public.
+func (h *ShareHandler) RegisterRoutes(mux *http.ServeMux, requireUser func(http.Handler) http.Handler) {
+	mux.Handle("POST /inspections/{id}/share", requireUser(http.HandlerFunc(h.Create)))
+	mux.Handle("GET /inspections/{id}/shares", requireUser(http.HandlerFunc(h.List)))
+	mux.Handle("DELETE /shares/{id}", requireUser(http.HandlerFunc(h.Revoke)))
+	mux.HandleFunc("GET /shared/{token}", h.View)
+}
@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/auth"
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/service"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// Fake Share Link Store
+// =============================================================================
+
+// fakeShareInspectionService keeps share links in memory with the same
+// ownership and usability rules as the SQL queries. Only the share link
+// methods are implemented; the embedded interface is nil.
+type fakeShareInspectionService struct {
+	service.InspectionService
+	links  map[string]*domain.ShareLink // keyed by raw token
+	nextID int
+}
+
+func newFakeShareInspectionService() *fakeShareInspectionService {
+	return &fakeShareInspectionService{links: map[string]*domain.ShareLink{}}
+}
+
+// addLink stores an active link and returns its raw token.
+func (f *fakeShareInspectionService) addLink(inspectionID, userID uuid.UUID) (string, *domain.ShareLink) {
+	f.nextID++
+	token := strings.Repeat("a", 63) + string(rune('0'+f.nextID))
+	link := &domain.ShareLink{
+		ID:           uuid.New(),
+		InspectionID: inspectionID,
+		UserID:       userID,
+		ExpiresAt:    time.Now().Add(domain.DefaultShareLinkTTL),
+		CreatedAt:    time.Now(),
+	}
+	f.links[token] = link
+	return token, link
+}
+
+func (f *fakeShareInspectionService) ResolveShareToken(ctx context.Context, token string) (*domain.ShareLink, error) {
+	link, ok := f.links[token]
+	if !ok || !link.IsUsable(time.Now()) {
+		return nil, domain.NotFound("test", "share link", "")
+	}
+	return link, nil
+}
+
+func (f *fakeShareInspectionService) RecordShareLinkView(ctx context.Context, link *domain.ShareLink) error {
+	if !link.IsUsable(time.Now()) {
+		return domain.NotFound("test", "share link", link.ID.String())
+	}
+	link.ViewCount++
+	return nil
+}
+
+func (f *fakeShareInspectionService) ListShareLinks(ctx context.Context, inspectionID, userID uuid.UUID) ([]domain.ShareLink, error) {
+	var links []domain.ShareLink
+	for _, l := range f.links {
+		if l.InspectionID == inspectionID && l.UserID == userID && l.IsUsable(time.Now()) {
+			links = append(links, *l)
+		}
+	}
+	return links, nil
+}
+
+func (f *fakeShareInspectionService) RevokeShareLink(ctx context.Context, linkID, userID uuid.UUID) (*domain.ShareLink, error) {
+	for _, l := range f.links {
+		if l.ID == linkID && l.UserID == userID && !l.IsRevoked() {
+			now := time.Now()
+			l.RevokedAt = &now
+			return l, nil
+		}
+	}
+	return nil, domain.NotFound("test", "share link", linkID.String())
+}
+
+// =============================================================================
+// Test Helpers
+// =============================================================================
+
+// newShareTestMux registers share routes with a pass-through auth wrapper;
+// the user is put in the request context by serveAs.
+func newShareTestMux(svc *fakeShareInspectionService) *http.ServeMux {
+	reportSvc := &mockReportService{
+		PrepareReportDataFunc: func(ctx context.Context, inspectionID, userID uuid.UUID) (*domain.ReportData, error) {
+			return &domain.ReportData{
+				InspectionID:    inspectionID,
+				InspectionTitle: "Riverside Tower Level 3",
+				GeneratedAt:     time.Now(),
+			}, nil
+		},
+	}
+	h := NewShareHandler(svc, reportSvc, "https://app.example.com", newTestLogger())
+
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux, func(next http.Handler) http.Handler { return next })
+	return mux
+}
+
+func serveAs(mux *http.ServeMux, user *domain.User, method, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if user != nil {
+		req = req.WithContext(auth.SetUser(req.Context(), user))
+	}
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	return rr
+}
+
+// =============================================================================
+// Share Link Tests
+// =============================================================================
+
+func TestShareHandler_RevocationInvalidatesAccess(t *testing.T) {
+	svc := newFakeShareInspectionService()
+	owner := &domain.User{ID: uuid.New()}
+	token, link := svc.addLink(uuid.New(), owner.ID)
+	mux := newShareTestMux(svc)
+
+	if rr := serveAs(mux, nil, http.MethodGet, "/shared/"+token); rr.Code != http.StatusOK {
+		t.Fatalf("expected shared report before revocation, got %d", rr.Code)
+	}
+
+	if rr := serveAs(mux, owner, http.MethodDelete, "/shares/"+link.ID.String()); rr.Code != http.StatusOK {
+		t.Fatalf("expected revoke to succeed, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	if rr := serveAs(mux, nil, http.MethodGet, "/shared/"+token); rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404 after revocation, got %d", rr.Code)
+	}
+}
+
+func TestShareHandler_RevokeRequiresOwner(t *testing.T) {
+	svc := newFakeShareInspectionService()
+	owner := &domain.User{ID: uuid.New()}
+	other := &domain.User{ID: uuid.New()}
+	token, link := svc.addLink(uuid.New(), owner.ID)
+	mux := newShareTestMux(svc)
+
+	if rr := serveAs(mux, other, http.MethodDelete, "/shares/"+link.ID.String()); rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404 revoking another user's link, got %d", rr.Code)
+	}
+
+	if rr := serveAs(mux, nil, http.MethodGet, "/shared/"+token); rr.Code != http.StatusOK {
+		t.Errorf("expected link to keep working, got %d", rr.Code)
+	}
+}
+
+func TestShareHandler_ListShowsOnlyOwnersLinks(t *testing.T) {
+	svc := newFakeShareInspectionService()
+	inspectionID := uuid.New()
+	owner := &domain.User{ID: uuid.New()}
+	other := &domain.User{ID: uuid.New()}
+	_, ownLink := svc.addLink(inspectionID, owner.ID)
+	_, otherLink := svc.addLink(inspectionID, other.ID)
+	mux := newShareTestMux(svc)
+
+	rr := serveAs(mux, owner, http.MethodGet, "/inspections/"+inspectionID.String()+"/shares")
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	body := rr.Body.String()
+	if !strings.Contains(body, ownLink.ID.String()) {
+		t.Error("expected owner's link in list")
+	}
+	if strings.Contains(body, otherLink.ID.String()) {
+		t.Error("expected other user's link to be hidden")
+	}
+}
+
+func TestShareHandler_ViewCountedOnlyWhenReportRenders(t *testing.T) {
+	svc := newFakeShareInspectionService()
+	token, link := svc.addLink(uuid.New(), uuid.New())
+	reportSvc := &mockReportService{
+		PrepareReportDataFunc: func(ctx context.Context, inspectionID, userID uuid.UUID) (*domain.ReportData, error) {
+			return nil, domain.Internal(errors.New("storage unavailable"), "test", "failed to prepare report")
+		},
+	}
+	mux := http.NewServeMux()
+	NewShareHandler(svc, reportSvc, "https://app.example.com", newTestLogger()).RegisterRoutes(mux, func(next http.Handler) http.Handler { return next })
+
+	if rr := serveAs(mux, nil, http.MethodGet, "/shared/"+token); rr.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500 when the report can't be prepared, got %d", rr.Code)
+	}
+	if link.ViewCount != 0 {
+		t.Errorf("expected the failed view not to be counted, got %d", link.ViewCount)
+	}
+
+	if rr := serveAs(newShareTestMux(svc), nil, http.MethodGet, "/shared/"+token); rr.Code != http.StatusOK {
+		t.Fatalf("expected shared report, got %d", rr.Code)
+	}
+	if link.ViewCount != 1 {
+		t.Errorf("expected the rendered view to be counted, got %d", link.ViewCount)
+	}
+}
+
+func TestShareLinksToPartial_Views(t *testing.T) {
+	limit := 5
+	links := []domain.ShareLink{
+		{ViewCount: 0},
+		{ViewCount: 1},
+		{ViewCount: 2, MaxViews: &limit},
+	}
+
+	items := shareLinksToPartial(links)
+
+	want := []string{"0 views", "1 view", "2 of 5 views"}
+	for i, w := range want {
+		if items[i].Views != w {
+			t.Errorf("link %d: expected %q, got %q", i, w, items[i].Views)
+		}
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/handler"
 	"github.com/DukeRupert/lukaut/internal/middleware"
 	"github.com/DukeRupert/lukaut/internal/session"
 	"github.com/google/uuid"
@@ -20,7 +21,8 @@ import (
 
 // testUserService is a minimal mock implementing service.UserService for integration tests.
 type testUserService struct {
-	getBySessionTokenFunc func(ctx context.Context, token string) (*domain.User, error)
+	getBySessionTokenFunc      func(ctx context.Context, token string) (*domain.User, error)
+	checkEmailVerificationFunc func(user *domain.User) error
 }
 
 func (m *testUserService) Register(ctx context.Context, params domain.RegisterParams) (*domain.User, error) {
@@ -49,6 +51,24 @@ func (m *testUserService) ChangePassword(ctx context.Context, params domain.Pass
 	return nil
 }
 func (m *testUserService) DeleteExpiredSessions(ctx context.Context) error { return nil }
+func (m *testUserService) DisableUser(ctx context.Context, id uuid.UUID) error {
+	return errors.New("not implemented")
+}
+func (m *testUserService) EnableUser(ctx context.Context, id uuid.UUID) error {
+	return errors.New("not implemented")
+}
+func (m *testUserService) ListSessions(ctx context.Context, userID uuid.UUID) ([]domain.Session, error) {
+	return nil, errors.New("not implemented")
+}
+func (m *testUserService) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	return errors.New("not implemented")
+}
+func (m *testUserService) RevokeOtherSessions(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return 0, errors.New("not implemented")
+}
+func (m *testUserService) LogoutAll(ctx context.Context, userID uuid.UUID) error {
+	return errors.New("not implemented")
+}
 func (m *testUserService) CreateEmailVerificationToken(ctx context.Context, userID uuid.UUID) (*domain.EmailVerificationResult, error) {
 	return nil, errors.New("not implemented")
 }
@@ -81,6 +101,13 @@ func (m *testUserService) GetByStripeCustomerID(ctx context.Context, stripeCusto
 	return nil, errors.New("not implemented")
 }
 
+func (m *testUserService) CheckEmailVerification(user *domain.User) error {
+	if m.checkEmailVerificationFunc != nil {
+		return m.checkEmailVerificationFunc(user)
+	}
+	return nil
+}
+
 func testLogger() *slog.Logger {
 	return slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
 }
@@ -235,3 +262,42 @@ func TestRouteEnforcement_Unauthenticated_DashboardRedirectsToLogin(t *testing.T
 		t.Errorf("Location = %q, want prefix /login", loc)
 	}
 }
+
+func TestRouteEnforcement_NonAdmin_AdminActionsForbidden(t *testing.T) {
+	mock := &testUserService{
+		getBySessionTokenFunc: func(ctx context.Context, token string) (*domain.User, error) {
+			return &domain.User{
+				ID:            uuid.New(),
+				Email:         "user@example.com",
+				Name:          "Regular User",
+				EmailVerified: true,
+			}, nil
+		},
+	}
+
+	logger := testLogger()
+	authMw := middleware.NewAuthMiddleware(mock, logger, false).WithAdminEmails([]string{"admin@example.com"})
+	requireAdmin := middleware.Stack(authMw.WithUser, authMw.RequireUser, authMw.RequireAdmin)
+
+	// The handler has no repository: a request reaching it would panic
+	mux := http.NewServeMux()
+	handler.NewAdminHandler(nil, nil, nil, logger).RegisterRoutes(mux, requireAdmin)
+
+	id := uuid.NewString()
+	for _, route := range []struct{ method, path string }{
+		{http.MethodPost, "/admin/users/" + id + "/verify-email"},
+		{http.MethodPost, "/admin/users/" + id + "/quota"},
+		{http.MethodGet, "/admin/jobs"},
+		{http.MethodPost, "/admin/jobs/" + id + "/retry"},
+	} {
+		req := httptest.NewRequest(route.method, route.path, nil)
+		req.AddCookie(&http.Cookie{Name: session.CookieName, Value: "valid-token"})
+		rec := httptest.NewRecorder()
+
+		mux.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("%s %s: status code = %d, want %d", route.method, route.path, rec.Code, http.StatusForbidden)
+		}
+	}
+}
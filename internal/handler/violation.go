@@ -5,10 +5,13 @@
 package handler
 
 import (
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/DukeRupert/lukaut/internal/auth"
 	"github.com/DukeRupert/lukaut/internal/domain"
@@ -67,16 +70,29 @@ func NewViolationHandler(
 // - POST   /inspections/{id}/violations -> Create
 // - PUT    /violations/{id}             -> Update
 // - PUT    /violations/{id}/status      -> UpdateStatus
+// - PUT    /violations/{id}/severity    -> UpdateSeverity
+// - PUT    /violations/{id}/notes       -> UpdateNotes
 // - DELETE /violations/{id}             -> Delete
+// - GET    /violations                  -> Search
 // - GET    /violations/{id}/card        -> GetCard
 // - PUT    /violations/batch/status     -> BatchUpdateStatus
+// - GET    /violations/{vid}/images           -> ListImages
+// - POST   /violations/{vid}/images/{imageID} -> AddImage
+// - DELETE /violations/{vid}/images/{imageID} -> RemoveImage
 func (h *ViolationHandler) RegisterRoutes(mux *http.ServeMux, requireUser func(http.Handler) http.Handler) {
 	mux.Handle("POST /inspections/{id}/violations", requireUser(http.HandlerFunc(h.Create)))
+	mux.Handle("GET /violations", requireUser(http.HandlerFunc(h.Search)))
 	mux.Handle("PUT /violations/{id}", requireUser(http.HandlerFunc(h.Update)))
 	mux.Handle("PUT /violations/{id}/status", requireUser(http.HandlerFunc(h.UpdateStatus)))
+	mux.Handle("PUT /violations/{id}/severity", requireUser(http.HandlerFunc(h.UpdateSeverity)))
+	mux.Handle("PUT /violations/{id}/notes", requireUser(http.HandlerFunc(h.UpdateNotes)))
+	mux.Handle("POST /violations/{id}/annotations", requireUser(http.HandlerFunc(h.CreateAnnotation)))
 	mux.Handle("PUT /violations/batch/status", requireUser(http.HandlerFunc(h.BatchUpdateStatus)))
 	mux.Handle("DELETE /violations/{id}", requireUser(http.HandlerFunc(h.Delete)))
 	mux.Handle("GET /violations/{id}/card", requireUser(http.HandlerFunc(h.GetCard)))
+	mux.Handle("GET /violations/{vid}/images", requireUser(http.HandlerFunc(h.ListImages)))
+	mux.Handle("POST /violations/{vid}/images/{imageID}", requireUser(http.HandlerFunc(h.AddImage)))
+	mux.Handle("DELETE /violations/{vid}/images/{imageID}", requireUser(http.HandlerFunc(h.RemoveImage)))
 }
 
 // =============================================================================
@@ -87,7 +103,7 @@ func (h *ViolationHandler) RegisterRoutes(mux *http.ServeMux, requireUser func(h
 func (h *ViolationHandler) Create(w http.ResponseWriter, r *http.Request) {
 	user := auth.GetUserFromRequest(r)
 	if user == nil {
-		h.logger.Error("create violation handler called without authenticated user")
+		h.logger.ErrorContext(r.Context(), "create violation handler called without authenticated user")
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
@@ -102,7 +118,7 @@ func (h *ViolationHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 	// Parse form
 	if err := r.ParseForm(); err != nil {
-		h.logger.Error("failed to parse form", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to parse form", "error", err)
 		http.Error(w, "Invalid form submission", http.StatusBadRequest)
 		return
 	}
@@ -119,6 +135,17 @@ func (h *ViolationHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Parse regulations to link, primary first
+	var regulationIDs []uuid.UUID
+	for _, value := range r.Form["regulation_id"] {
+		regulationID, err := uuid.Parse(value)
+		if err != nil {
+			http.Error(w, "Invalid regulation ID", http.StatusBadRequest)
+			return
+		}
+		regulationIDs = append(regulationIDs, regulationID)
+	}
+
 	// Create violation
 	params := domain.CreateViolationParams{
 		InspectionID:   inspectionID,
@@ -127,6 +154,7 @@ func (h *ViolationHandler) Create(w http.ResponseWriter, r *http.Request) {
 		Description:    description,
 		Severity:       severity,
 		InspectorNotes: inspectorNotes,
+		RegulationIDs:  regulationIDs,
 	}
 
 	violation, err := h.violationService.Create(r.Context(), params)
@@ -136,25 +164,34 @@ func (h *ViolationHandler) Create(w http.ResponseWriter, r *http.Request) {
 		case domain.EINVALID:
 			http.Error(w, domain.ErrorMessage(err), http.StatusBadRequest)
 		case domain.ENOTFOUND:
-			http.Error(w, "Inspection not found", http.StatusNotFound)
+			http.Error(w, domain.ErrorMessage(err), http.StatusNotFound)
 		default:
-			h.logger.Error("failed to create violation", "error", err, "inspection_id", inspectionID)
-			http.Error(w, "Failed to create violation", http.StatusInternalServerError)
+			ServerErrorResponse(w, r, h.logger.With("inspection_id", inspectionID), fmt.Errorf("create violation: %w", err))
 		}
 		return
 	}
 
+	// Load the regulations just linked
+	regulations := []domain.ViolationRegulation{}
+	if len(regulationIDs) > 0 {
+		_, regulations, err = h.violationService.GetByIDWithRegulations(r.Context(), violation.ID, user.ID)
+		if err != nil {
+			ServerErrorResponse(w, r, h.logger.With("violation_id", violation.ID), fmt.Errorf("get violation regulations: %w", err))
+			return
+		}
+	}
+
 	// Render the violation card partial
 	data := ViolationCardData{
 		Violation:   violation,
-		Regulations: []domain.ViolationRegulation{}, // No regulations for manual violations
+		Regulations: regulations,
 		CanEdit:     true,
 	}
 
 	templData := toTemplViolationCardData(data, "")
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	if err := partials.ViolationCard(templData).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render violation card", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render violation card", "error", err)
 	}
 }
 
@@ -166,7 +203,7 @@ func (h *ViolationHandler) Create(w http.ResponseWriter, r *http.Request) {
 func (h *ViolationHandler) Update(w http.ResponseWriter, r *http.Request) {
 	user := auth.GetUserFromRequest(r)
 	if user == nil {
-		h.logger.Error("update violation handler called without authenticated user")
+		h.logger.ErrorContext(r.Context(), "update violation handler called without authenticated user")
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
@@ -181,7 +218,7 @@ func (h *ViolationHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 	// Parse form
 	if err := r.ParseForm(); err != nil {
-		h.logger.Error("failed to parse form", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to parse form", "error", err)
 		http.Error(w, "Invalid form submission", http.StatusBadRequest)
 		return
 	}
@@ -216,8 +253,7 @@ func (h *ViolationHandler) Update(w http.ResponseWriter, r *http.Request) {
 		case domain.ENOTFOUND:
 			http.Error(w, "Violation not found", http.StatusNotFound)
 		default:
-			h.logger.Error("failed to update violation", "error", err, "violation_id", id)
-			http.Error(w, "Failed to update violation", http.StatusInternalServerError)
+			ServerErrorResponse(w, r, h.logger.With("violation_id", id), fmt.Errorf("update violation: %w", err))
 		}
 		return
 	}
@@ -225,8 +261,7 @@ func (h *ViolationHandler) Update(w http.ResponseWriter, r *http.Request) {
 	// Get updated violation with regulations
 	violation, regulations, err := h.violationService.GetByIDWithRegulations(r.Context(), id, user.ID)
 	if err != nil {
-		h.logger.Error("failed to get updated violation", "error", err, "violation_id", id)
-		http.Error(w, "Failed to load updated violation", http.StatusInternalServerError)
+		ServerErrorResponse(w, r, h.logger.With("violation_id", id), fmt.Errorf("get updated violation: %w", err))
 		return
 	}
 
@@ -242,7 +277,7 @@ func (h *ViolationHandler) Update(w http.ResponseWriter, r *http.Request) {
 	templData := toTemplViolationCardData(data, "")
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	if err := partials.ViolationCard(templData).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render violation card", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render violation card", "error", err)
 	}
 }
 
@@ -254,7 +289,7 @@ func (h *ViolationHandler) Update(w http.ResponseWriter, r *http.Request) {
 func (h *ViolationHandler) UpdateStatus(w http.ResponseWriter, r *http.Request) {
 	user := auth.GetUserFromRequest(r)
 	if user == nil {
-		h.logger.Error("update status handler called without authenticated user")
+		h.logger.ErrorContext(r.Context(), "update status handler called without authenticated user")
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
@@ -269,7 +304,7 @@ func (h *ViolationHandler) UpdateStatus(w http.ResponseWriter, r *http.Request)
 
 	// Parse form
 	if err := r.ParseForm(); err != nil {
-		h.logger.Error("failed to parse form", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to parse form", "error", err)
 		http.Error(w, "Invalid form submission", http.StatusBadRequest)
 		return
 	}
@@ -298,8 +333,77 @@ func (h *ViolationHandler) UpdateStatus(w http.ResponseWriter, r *http.Request)
 		case domain.ENOTFOUND:
 			http.Error(w, "Violation not found", http.StatusNotFound)
 		default:
-			h.logger.Error("failed to update violation status", "error", err, "violation_id", id)
-			http.Error(w, "Failed to update status", http.StatusInternalServerError)
+			ServerErrorResponse(w, r, h.logger.With("violation_id", id), fmt.Errorf("update violation status: %w", err))
+		}
+		return
+	}
+
+	// Get updated violation with regulations
+	violation, regulations, err := h.violationService.GetByIDWithRegulations(r.Context(), id, user.ID)
+	if err != nil {
+		ServerErrorResponse(w, r, h.logger.With("violation_id", id), fmt.Errorf("get updated violation: %w", err))
+		return
+	}
+
+	// Render the updated violation card (htmx response)
+	data := ViolationCardData{
+		Violation:   violation,
+		Regulations: regulations,
+		CanEdit:     true,
+	}
+
+	templData := toTemplViolationCardData(data, "")
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := partials.ViolationCard(templData).Render(r.Context(), w); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to render violation card", "error", err)
+	}
+}
+
+// =============================================================================
+// PUT /violations/{id}/severity - Override Violation Severity
+// =============================================================================
+
+// UpdateSeverity overrides the severity the AI assigned to a violation.
+// The new severity is read from the "value" parameter. Responds with the
+// updated violation card and triggers violationSeverityChanged so the review
+// queue can refresh its view.
+func (h *ViolationHandler) UpdateSeverity(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUserFromRequest(r)
+	if user == nil {
+		h.logger.ErrorContext(r.Context(), "update severity handler called without authenticated user")
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Parse violation ID
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid violation ID", http.StatusBadRequest)
+		return
+	}
+
+	// Parse form
+	if err := r.ParseForm(); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to parse form", "error", err)
+		http.Error(w, "Invalid form submission", http.StatusBadRequest)
+		return
+	}
+
+	// Extract severity
+	severity := domain.ViolationSeverity(r.FormValue("value"))
+	if !severity.IsValid() {
+		http.Error(w, "Invalid severity", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.violationService.UpdateSeverity(r.Context(), id, user.ID, severity); err != nil {
+		switch domain.ErrorCode(err) {
+		case domain.EINVALID:
+			http.Error(w, domain.ErrorMessage(err), http.StatusBadRequest)
+		case domain.ENOTFOUND:
+			http.Error(w, "Violation not found", http.StatusNotFound)
+		default:
+			ServerErrorResponse(w, r, h.logger.With("violation_id", id), fmt.Errorf("update violation severity: %w", err))
 		}
 		return
 	}
@@ -307,8 +411,7 @@ func (h *ViolationHandler) UpdateStatus(w http.ResponseWriter, r *http.Request)
 	// Get updated violation with regulations
 	violation, regulations, err := h.violationService.GetByIDWithRegulations(r.Context(), id, user.ID)
 	if err != nil {
-		h.logger.Error("failed to get updated violation", "error", err, "violation_id", id)
-		http.Error(w, "Failed to load updated violation", http.StatusInternalServerError)
+		ServerErrorResponse(w, r, h.logger.With("violation_id", id), fmt.Errorf("get updated violation: %w", err))
 		return
 	}
 
@@ -320,9 +423,124 @@ func (h *ViolationHandler) UpdateStatus(w http.ResponseWriter, r *http.Request)
 	}
 
 	templData := toTemplViolationCardData(data, "")
+	w.Header().Set("HX-Trigger", "violationSeverityChanged")
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	if err := partials.ViolationCard(templData).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render violation card", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render violation card", "error", err)
+	}
+}
+
+// =============================================================================
+// PUT /violations/{id}/notes - Autosave Inspector Notes
+// =============================================================================
+
+// UpdateNotes saves a violation's inspector notes as the inspector types and
+// responds with a small saved indicator. The violation's status is unchanged.
+func (h *ViolationHandler) UpdateNotes(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUserFromRequest(r)
+	if user == nil {
+		h.logger.ErrorContext(r.Context(), "update notes handler called without authenticated user")
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Parse violation ID
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid violation ID", http.StatusBadRequest)
+		return
+	}
+
+	// Parse form
+	if err := r.ParseForm(); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to parse form", "error", err)
+		http.Error(w, "Invalid form submission", http.StatusBadRequest)
+		return
+	}
+
+	notes := strings.TrimSpace(r.FormValue("inspector_notes"))
+	if err := h.violationService.UpdateNotes(r.Context(), id, user.ID, notes); err != nil {
+		switch domain.ErrorCode(err) {
+		case domain.ENOTFOUND:
+			http.Error(w, "Violation not found", http.StatusNotFound)
+		default:
+			ServerErrorResponse(w, r, h.logger.With("violation_id", id), fmt.Errorf("save violation notes: %w", err))
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := partials.ViolationNotesSaved(time.Now().Format("3:04 PM")).Render(r.Context(), w); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to render notes saved indicator", "error", err)
+	}
+}
+
+// =============================================================================
+// POST /violations/{id}/annotations - Draw a Box on the Violation's Photo
+// =============================================================================
+
+// CreateAnnotation stores a box the inspector drew on a violation's photo.
+// Accepts form fields x, y, width, and height, normalized to the image size,
+// and an optional label. Responds with the saved annotation as JSON.
+func (h *ViolationHandler) CreateAnnotation(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUserFromRequest(r)
+	if user == nil {
+		writeJSONError(w, http.StatusUnauthorized, domain.EUNAUTHORIZED, "Authentication required")
+		return
+	}
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, domain.EINVALID, "Invalid violation ID")
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		writeJSONError(w, http.StatusBadRequest, domain.EINVALID, "Invalid form submission")
+		return
+	}
+
+	var box domain.AnnotationBox
+	for _, field := range []struct {
+		name  string
+		value *float64
+	}{
+		{"x", &box.X},
+		{"y", &box.Y},
+		{"width", &box.Width},
+		{"height", &box.Height},
+	} {
+		v, err := strconv.ParseFloat(r.FormValue(field.name), 64)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, domain.EINVALID, field.name+" must be a number")
+			return
+		}
+		*field.value = v
+	}
+
+	annotation, err := h.violationService.AddAnnotation(r.Context(), domain.CreateImageAnnotationParams{
+		ViolationID: id,
+		UserID:      user.ID,
+		Box:         box,
+		Label:       r.FormValue("label"),
+	})
+	if err != nil {
+		switch domain.ErrorCode(err) {
+		case domain.ENOTFOUND:
+			writeJSONError(w, http.StatusNotFound, domain.ENOTFOUND, "Violation not found")
+		case domain.EINVALID:
+			writeJSONError(w, http.StatusBadRequest, domain.EINVALID, domain.ErrorMessage(err))
+		default:
+			h.logger.ErrorContext(r.Context(), "failed to add annotation", "error", err, "violation_id", id)
+			writeJSONError(w, http.StatusInternalServerError, domain.EINTERNAL, "Failed to save annotation")
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(annotationToResponse(*annotation)); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to encode annotation", "error", err)
 	}
 }
 
@@ -336,14 +554,14 @@ func (h *ViolationHandler) UpdateStatus(w http.ResponseWriter, r *http.Request)
 func (h *ViolationHandler) BatchUpdateStatus(w http.ResponseWriter, r *http.Request) {
 	user := auth.GetUserFromRequest(r)
 	if user == nil {
-		h.logger.Error("batch update status handler called without authenticated user")
+		h.logger.ErrorContext(r.Context(), "batch update status handler called without authenticated user")
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
 
 	// Parse form data
 	if err := r.ParseForm(); err != nil {
-		h.logger.Error("failed to parse batch update form", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to parse batch update form", "error", err)
 		http.Error(w, "Invalid form submission", http.StatusBadRequest)
 		return
 	}
@@ -391,7 +609,7 @@ func (h *ViolationHandler) BatchUpdateStatus(w http.ResponseWriter, r *http.Requ
 			if code == domain.ENOTFOUND {
 				updateErrors = append(updateErrors, "Violation not found: "+idStr)
 			} else {
-				h.logger.Error("failed to update violation status", "error", err, "violation_id", id)
+				h.logger.ErrorContext(r.Context(), "failed to update violation status", "error", err, "violation_id", id)
 				updateErrors = append(updateErrors, "Failed to update: "+idStr)
 			}
 			continue
@@ -400,7 +618,7 @@ func (h *ViolationHandler) BatchUpdateStatus(w http.ResponseWriter, r *http.Requ
 		updated = append(updated, id)
 	}
 
-	h.logger.Info("batch violation status update completed",
+	h.logger.InfoContext(r.Context(), "batch violation status update completed",
 		"user_id", user.ID,
 		"status", status,
 		"updated_count", len(updated),
@@ -434,7 +652,7 @@ func (h *ViolationHandler) BatchUpdateStatus(w http.ResponseWriter, r *http.Requ
 func (h *ViolationHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	user := auth.GetUserFromRequest(r)
 	if user == nil {
-		h.logger.Error("delete violation handler called without authenticated user")
+		h.logger.ErrorContext(r.Context(), "delete violation handler called without authenticated user")
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
@@ -454,8 +672,7 @@ func (h *ViolationHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		if code == domain.ENOTFOUND {
 			http.Error(w, "Violation not found", http.StatusNotFound)
 		} else {
-			h.logger.Error("failed to delete violation", "error", err, "violation_id", id)
-			http.Error(w, "Failed to delete violation", http.StatusInternalServerError)
+			ServerErrorResponse(w, r, h.logger.With("violation_id", id), fmt.Errorf("delete violation: %w", err))
 		}
 		return
 	}
@@ -473,7 +690,7 @@ func (h *ViolationHandler) Delete(w http.ResponseWriter, r *http.Request) {
 func (h *ViolationHandler) GetCard(w http.ResponseWriter, r *http.Request) {
 	user := auth.GetUserFromRequest(r)
 	if user == nil {
-		h.logger.Error("get card handler called without authenticated user")
+		h.logger.ErrorContext(r.Context(), "get card handler called without authenticated user")
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
@@ -493,8 +710,7 @@ func (h *ViolationHandler) GetCard(w http.ResponseWriter, r *http.Request) {
 		if code == domain.ENOTFOUND {
 			http.Error(w, "Violation not found", http.StatusNotFound)
 		} else {
-			h.logger.Error("failed to get violation", "error", err, "violation_id", id)
-			http.Error(w, "Failed to load violation", http.StatusInternalServerError)
+			ServerErrorResponse(w, r, h.logger.With("violation_id", id), fmt.Errorf("get violation: %w", err))
 		}
 		return
 	}
@@ -509,7 +725,7 @@ func (h *ViolationHandler) GetCard(w http.ResponseWriter, r *http.Request) {
 	templData := toTemplViolationCardData(data, "")
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	if err := partials.ViolationCard(templData).Render(r.Context(), w); err != nil {
-		h.logger.Error("failed to render violation card", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to render violation card", "error", err)
 	}
 }
 
@@ -535,6 +751,7 @@ func toTemplViolationCardData(data ViolationCardData, thumbnailURL string) parti
 			Description:    data.Violation.Description,
 			AIDescription:  data.Violation.AIDescription,
 			Severity:       string(data.Violation.Severity),
+			AISeverity:     string(data.Violation.AISeverity),
 			Status:         string(data.Violation.Status),
 			Confidence:     string(data.Violation.Confidence),
 			InspectorNotes: data.Violation.InspectorNotes,
@@ -0,0 +1,177 @@
+// Package handler contains HTTP handlers for the Lukaut application.
+//
+// This file implements the photo picker for attaching more of an
+// inspection's photos to a violation as evidence.
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/DukeRupert/lukaut/internal/auth"
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/templ/partials"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// GET /violations/{vid}/images - Violation Photo Picker
+// =============================================================================
+
+// ListImages renders the violation's additional photos and the picker of
+// the inspection's other photos.
+func (h *ViolationHandler) ListImages(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUserFromRequest(r)
+	if user == nil {
+		h.logger.ErrorContext(r.Context(), "list violation images handler called without authenticated user")
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	violationID, err := uuid.Parse(r.PathValue("vid"))
+	if err != nil {
+		http.Error(w, "Invalid violation ID", http.StatusBadRequest)
+		return
+	}
+
+	h.renderViolationPhotos(w, r, violationID, user.ID)
+}
+
+// =============================================================================
+// POST /violations/{vid}/images/{imageID} - Attach Photo
+// =============================================================================
+
+// AddImage attaches another of the inspection's photos to the violation and
+// renders the updated picker.
+func (h *ViolationHandler) AddImage(w http.ResponseWriter, r *http.Request) {
+	h.changeImage(w, r, "attach", h.violationService.AddImage)
+}
+
+// =============================================================================
+// DELETE /violations/{vid}/images/{imageID} - Detach Photo
+// =============================================================================
+
+// RemoveImage detaches one of the violation's additional photos and renders
+// the updated picker.
+func (h *ViolationHandler) RemoveImage(w http.ResponseWriter, r *http.Request) {
+	h.changeImage(w, r, "detach", h.violationService.RemoveImage)
+}
+
+// =============================================================================
+// Helper Functions
+// =============================================================================
+
+// changeImage parses the violation and image IDs, applies change, and
+// renders the updated picker.
+func (h *ViolationHandler) changeImage(
+	w http.ResponseWriter,
+	r *http.Request,
+	action string,
+	change func(ctx context.Context, violationID, imageID, userID uuid.UUID) error,
+) {
+	user := auth.GetUserFromRequest(r)
+	if user == nil {
+		h.logger.ErrorContext(r.Context(), action+" violation image handler called without authenticated user")
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	violationID, err := uuid.Parse(r.PathValue("vid"))
+	if err != nil {
+		http.Error(w, "Invalid violation ID", http.StatusBadRequest)
+		return
+	}
+	imageID, err := uuid.Parse(r.PathValue("imageID"))
+	if err != nil {
+		http.Error(w, "Invalid image ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := change(r.Context(), violationID, imageID, user.ID); err != nil {
+		switch domain.ErrorCode(err) {
+		case domain.EINVALID:
+			http.Error(w, domain.ErrorMessage(err), http.StatusBadRequest)
+		case domain.ENOTFOUND:
+			http.Error(w, domain.ErrorMessage(err), http.StatusNotFound)
+		default:
+			ServerErrorResponse(w, r, h.logger.With("violation_id", violationID, "image_id", imageID), fmt.Errorf("%s violation image: %w", action, err))
+		}
+		return
+	}
+
+	h.renderViolationPhotos(w, r, violationID, user.ID)
+}
+
+// renderViolationPhotos renders the photo picker for a violation. The
+// inspection's photos other than the violation's primary photo are split
+// into those attached, in attached order, and those that can be attached.
+func (h *ViolationHandler) renderViolationPhotos(w http.ResponseWriter, r *http.Request, violationID, userID uuid.UUID) {
+	ctx := r.Context()
+	logger := h.logger.With("violation_id", violationID)
+
+	violation, err := h.violationService.GetByID(ctx, violationID, userID)
+	if err != nil {
+		if domain.ErrorCode(err) == domain.ENOTFOUND {
+			http.Error(w, "Violation not found", http.StatusNotFound)
+			return
+		}
+		ServerErrorResponse(w, r, logger, fmt.Errorf("get violation: %w", err))
+		return
+	}
+
+	attached, err := h.violationService.ListImages(ctx, violationID, userID)
+	if err != nil {
+		ServerErrorResponse(w, r, logger, fmt.Errorf("list violation images: %w", err))
+		return
+	}
+
+	images, err := h.imageService.ListByInspection(ctx, violation.InspectionID, userID)
+	if err != nil {
+		ServerErrorResponse(w, r, logger, fmt.Errorf("list inspection images: %w", err))
+		return
+	}
+
+	photos := make(map[uuid.UUID]partials.ViolationPhotoDisplay, len(images))
+	for _, img := range images {
+		thumbnailURL, err := h.imageService.GetThumbnailURL(ctx, img.ID, userID, domain.DefaultThumbnailSize)
+		if err != nil {
+			logger.WarnContext(ctx, "failed to generate thumbnail URL", "error", err, "image_id", img.ID)
+		}
+		photos[img.ID] = partials.ViolationPhotoDisplay{
+			ImageID:      img.ID.String(),
+			ThumbnailURL: thumbnailURL,
+			Filename:     img.OriginalFilename,
+		}
+	}
+
+	data := partials.ViolationPhotosData{
+		ViolationID: violationID.String(),
+		Attached:    make([]partials.ViolationPhotoDisplay, 0, len(attached)),
+		Available:   []partials.ViolationPhotoDisplay{},
+		PhotoCount:  len(attached),
+		MaxPhotos:   domain.MaxViolationImages,
+	}
+	if violation.ImageID != nil {
+		data.PhotoCount++
+	}
+
+	isAttached := make(map[uuid.UUID]bool, len(attached))
+	for _, a := range attached {
+		isAttached[a.ImageID] = true
+		if photo, ok := photos[a.ImageID]; ok {
+			data.Attached = append(data.Attached, photo)
+		}
+	}
+	for _, img := range images {
+		if isAttached[img.ID] || (violation.ImageID != nil && *violation.ImageID == img.ID) {
+			continue
+		}
+		data.Available = append(data.Available, photos[img.ID])
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := partials.ViolationPhotos(data).Render(ctx, w); err != nil {
+		h.logger.ErrorContext(ctx, "failed to render violation photos", "error", err)
+	}
+}
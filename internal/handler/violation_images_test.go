@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DukeRupert/lukaut/internal/auth"
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/service"
+	"github.com/google/uuid"
+)
+
+// fakePhotosViolationService holds one violation with a primary photo and
+// the additional photos attached to it.
+type fakePhotosViolationService struct {
+	service.ViolationService
+	violation domain.Violation
+	owner     uuid.UUID
+	attached  []uuid.UUID
+}
+
+func (f *fakePhotosViolationService) GetByID(ctx context.Context, id, userID uuid.UUID) (*domain.Violation, error) {
+	if id != f.violation.ID || userID != f.owner {
+		return nil, domain.NotFound("violation.get", "violation", id.String())
+	}
+	v := f.violation
+	return &v, nil
+}
+
+func (f *fakePhotosViolationService) ListImages(ctx context.Context, violationID, userID uuid.UUID) ([]domain.ViolationImage, error) {
+	images := make([]domain.ViolationImage, len(f.attached))
+	for i, id := range f.attached {
+		images[i] = domain.ViolationImage{ImageID: id, Position: i + 1}
+	}
+	return images, nil
+}
+
+func (f *fakePhotosViolationService) AddImage(ctx context.Context, violationID, imageID, userID uuid.UUID) error {
+	if _, err := f.GetByID(ctx, violationID, userID); err != nil {
+		return err
+	}
+	if len(f.attached)+1 >= domain.MaxViolationImages {
+		return domain.Invalid("violation.add_image", "a violation can have at most 5 photos")
+	}
+	f.attached = append(f.attached, imageID)
+	return nil
+}
+
+func (f *fakePhotosViolationService) RemoveImage(ctx context.Context, violationID, imageID, userID uuid.UUID) error {
+	for i, id := range f.attached {
+		if id == imageID {
+			f.attached = append(f.attached[:i], f.attached[i+1:]...)
+			return nil
+		}
+	}
+	return domain.NotFound("violation.remove_image", "violation photo", imageID.String())
+}
+
+// newPhotosTestImageService lists the given photos of an inspection, named
+// after their position.
+func newPhotosTestImageService(imageIDs []uuid.UUID) *mockImageService {
+	return &mockImageService{
+		ListByInspectionFunc: func(ctx context.Context, inspectionID, userID uuid.UUID) ([]domain.Image, error) {
+			images := make([]domain.Image, len(imageIDs))
+			for i, id := range imageIDs {
+				images[i] = domain.Image{ID: id, InspectionID: inspectionID, OriginalFilename: []string{"close-up.jpg", "wide.jpg", "ladder.jpg"}[i]}
+			}
+			return images, nil
+		},
+		GetThumbnailURLFunc: func(ctx context.Context, imageID, userID uuid.UUID, size int) (string, error) {
+			return "https://cdn.example.com/" + imageID.String() + ".jpg", nil
+		},
+	}
+}
+
+func servePhotosRequest(h *ViolationHandler, method string, violationID, imageID uuid.UUID, user *domain.User) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/violations/"+violationID.String()+"/images/"+imageID.String(), nil)
+	req.SetPathValue("vid", violationID.String())
+	req.SetPathValue("imageID", imageID.String())
+	req = req.WithContext(auth.SetUser(req.Context(), user))
+	rr := httptest.NewRecorder()
+	switch method {
+	case http.MethodPost:
+		h.AddImage(rr, req)
+	case http.MethodDelete:
+		h.RemoveImage(rr, req)
+	}
+	return rr
+}
+
+func TestViolationAddImage_RendersPicker(t *testing.T) {
+	owner := &domain.User{ID: uuid.New()}
+	imageIDs := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	svc := &fakePhotosViolationService{
+		violation: domain.Violation{ID: uuid.New(), InspectionID: uuid.New(), ImageID: &imageIDs[0]},
+		owner:     owner.ID,
+	}
+	h := NewViolationHandler(svc, nil, newPhotosTestImageService(imageIDs), newTestLogger())
+
+	rr := servePhotosRequest(h, http.MethodPost, svc.violation.ID, imageIDs[1], owner)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if len(svc.attached) != 1 || svc.attached[0] != imageIDs[1] {
+		t.Errorf("expected the wide shot attached, got %v", svc.attached)
+	}
+
+	body := rr.Body.String()
+	for _, want := range []string{
+		"2 of 5 photos",
+		`hx-delete="/violations/` + svc.violation.ID.String() + `/images/` + imageIDs[1].String() + `"`,
+		`hx-post="/violations/` + svc.violation.ID.String() + `/images/` + imageIDs[2].String() + `"`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected picker to contain %q, got:\n%s", want, body)
+		}
+	}
+	// The primary photo is neither attached nor offered
+	if strings.Contains(body, imageIDs[0].String()) {
+		t.Error("expected the primary photo to be left out of the picker")
+	}
+}
+
+func TestViolationAddImage_Errors(t *testing.T) {
+	owner := &domain.User{ID: uuid.New()}
+	imageIDs := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+
+	tests := []struct {
+		name     string
+		user     *domain.User
+		attached []uuid.UUID
+		want     int
+	}{
+		{name: "another user's violation", user: &domain.User{ID: uuid.New()}, want: http.StatusNotFound},
+		{name: "photo limit reached", user: owner, attached: []uuid.UUID{uuid.New(), uuid.New(), uuid.New(), uuid.New()}, want: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &fakePhotosViolationService{
+				violation: domain.Violation{ID: uuid.New(), InspectionID: uuid.New(), ImageID: &imageIDs[0]},
+				owner:     owner.ID,
+				attached:  tt.attached,
+			}
+			h := NewViolationHandler(svc, nil, newPhotosTestImageService(imageIDs), newTestLogger())
+
+			rr := servePhotosRequest(h, http.MethodPost, svc.violation.ID, imageIDs[1], tt.user)
+
+			if rr.Code != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, rr.Code)
+			}
+			if len(svc.attached) != len(tt.attached) {
+				t.Errorf("expected no photo attached, got %v", svc.attached)
+			}
+		})
+	}
+}
+
+func TestViolationRemoveImage_OffersPhotoAgain(t *testing.T) {
+	owner := &domain.User{ID: uuid.New()}
+	imageIDs := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	svc := &fakePhotosViolationService{
+		violation: domain.Violation{ID: uuid.New(), InspectionID: uuid.New(), ImageID: &imageIDs[0]},
+		owner:     owner.ID,
+		attached:  []uuid.UUID{imageIDs[1]},
+	}
+	h := NewViolationHandler(svc, nil, newPhotosTestImageService(imageIDs), newTestLogger())
+
+	rr := servePhotosRequest(h, http.MethodDelete, svc.violation.ID, imageIDs[1], owner)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	body := rr.Body.String()
+	if !strings.Contains(body, "1 of 5 photos") {
+		t.Errorf("expected only the primary photo counted, got:\n%s", body)
+	}
+	if !strings.Contains(body, `hx-post="/violations/`+svc.violation.ID.String()+`/images/`+imageIDs[1].String()+`"`) {
+		t.Error("expected the detached photo to be offered again")
+	}
+
+	rr = servePhotosRequest(h, http.MethodDelete, svc.violation.ID, imageIDs[1], owner)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404 detaching a photo that isn't attached, got %d", rr.Code)
+	}
+}
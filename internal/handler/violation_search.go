@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/auth"
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/templ/components/pagination"
+	"github.com/DukeRupert/lukaut/internal/templ/pages/inspections"
+)
+
+// violationSearchDateLayout is the format of the search's date range, as
+// sent by date inputs.
+const violationSearchDateLayout = "2006-01-02"
+
+// Search renders the search across all of the user's violations. htmx
+// requests get just the results.
+// GET /violations
+//
+// Query parameters (all optional):
+// - q: Text matched against the description, AI description and notes
+// - severity: Only this severity
+// - status: Only this review status
+// - from, to: Inspection date range (YYYY-MM-DD), inclusive
+// - standard: Regulation standard number prefix, e.g. 1926.501
+// - page: Page number, starting at 1
+func (h *ViolationHandler) Search(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUserFromRequest(r)
+	if user == nil {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := inspections.ViolationSearchFilter{
+		Query:    strings.TrimSpace(query.Get("q")),
+		Severity: query.Get("severity"),
+		Status:   query.Get("status"),
+		From:     query.Get("from"),
+		To:       query.Get("to"),
+		Standard: strings.TrimSpace(query.Get("standard")),
+	}
+	page := 1
+	if p, err := strconv.Atoi(query.Get("page")); err == nil && p > 0 {
+		page = p
+	}
+
+	data := inspections.ViolationSearchPageData{
+		CurrentPath: r.URL.Path,
+		User:        domainUserToInspectionDisplay(user),
+		Filter:      filter,
+	}
+
+	params := domain.SearchViolationsParams{
+		UserID:         user.ID,
+		Query:          filter.Query,
+		Severity:       domain.ViolationSeverity(filter.Severity),
+		Status:         domain.ViolationStatus(filter.Status),
+		StandardNumber: filter.Standard,
+		Limit:          domain.DefaultPerPage,
+		Offset:         int32((page - 1) * domain.DefaultPerPage),
+	}
+	var err error
+	if params.From, err = parseViolationSearchDate(filter.From); err != nil {
+		data.Error = "The start date must be a date like 2026-01-31."
+	} else if params.To, err = parseViolationSearchDate(filter.To); err != nil {
+		data.Error = "The end date must be a date like 2026-12-31."
+	}
+
+	if data.Error == "" {
+		result, err := h.violationService.Search(r.Context(), params)
+		switch {
+		case err == nil:
+			data.Results = violationSearchItems(result.Results)
+			data.Pagination = pagination.Data{
+				CurrentPage: result.CurrentPage(),
+				TotalPages:  result.TotalPages(),
+				PerPage:     int(result.Limit),
+				Total:       int(result.Total),
+				HasPrevious: result.HasPrevious(),
+				HasNext:     result.HasMore(),
+				PrevPage:    result.CurrentPage() - 1,
+				NextPage:    result.CurrentPage() + 1,
+			}
+		case domain.ErrorCode(err) == domain.EINVALID:
+			data.Error = domain.ErrorMessage(err)
+		default:
+			h.logger.ErrorContext(r.Context(), "failed to search violations", "error", err, "user_id", user.ID)
+			data.Error = "Failed to search violations. Please try again."
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	if r.Header.Get("HX-Request") == "true" {
+		if err := inspections.ViolationSearchResults(data).Render(r.Context(), w); err != nil {
+			h.logger.ErrorContext(r.Context(), "failed to render violation search results", "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if err := inspections.ViolationSearchPage(data).Render(r.Context(), w); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to render violation search page", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// parseViolationSearchDate parses a date of the search's range; an empty
+// value leaves that end of the range open.
+func parseViolationSearchDate(v string) (*time.Time, error) {
+	if v == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(violationSearchDateLayout, v)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// violationSearchItems formats search results for display. Violations with
+// an AI-detected region show a close-up of it.
+func violationSearchItems(results []domain.ViolationSearchResult) []inspections.ViolationSearchItem {
+	items := make([]inspections.ViolationSearchItem, 0, len(results))
+	for _, res := range results {
+		v := res.Violation
+		thumbnailURL := ""
+		if v.ImageID != nil {
+			thumbnailURL = v.RegionThumbnailURL(domain.ThumbnailURL(*v.ImageID))
+		}
+		items = append(items, inspections.ViolationSearchItem{
+			ID:              v.ID.String(),
+			Description:     v.Description,
+			Severity:        string(v.Severity),
+			Status:          string(v.Status),
+			StandardNumber:  res.StandardNumber,
+			ThumbnailURL:    thumbnailURL,
+			InspectionTitle: res.InspectionTitle,
+			InspectionDate:  res.InspectionDate.Format("Jan 2, 2006"),
+			InspectionURL:   "/inspections/" + v.InspectionID.String(),
+			ReviewURL:       res.ReviewURL(),
+		})
+	}
+	return items
+}
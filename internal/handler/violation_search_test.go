@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/auth"
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/service"
+	"github.com/google/uuid"
+)
+
+// fakeSearchViolationService returns one result and records what was
+// searched for.
+type fakeSearchViolationService struct {
+	service.ViolationService
+	calls  int
+	params domain.SearchViolationsParams
+	result domain.ViolationSearchResult
+}
+
+func (f *fakeSearchViolationService) Search(ctx context.Context, params domain.SearchViolationsParams) (*domain.SearchViolationsResult, error) {
+	f.calls++
+	f.params = params
+	return &domain.SearchViolationsResult{
+		Results: []domain.ViolationSearchResult{f.result},
+		Total:   1,
+		Limit:   params.Limit,
+		Offset:  params.Offset,
+	}, nil
+}
+
+func newFakeSearchViolationService() *fakeSearchViolationService {
+	return &fakeSearchViolationService{result: domain.ViolationSearchResult{
+		Violation: domain.Violation{
+			ID:           uuid.New(),
+			InspectionID: uuid.New(),
+			Description:  "Missing guardrail on north edge",
+			Status:       domain.ViolationStatusConfirmed,
+			Severity:     domain.ViolationSeveritySerious,
+		},
+		InspectionTitle: "Warehouse roof",
+		InspectionDate:  time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC),
+		StandardNumber:  "1926.501(b)(1)",
+	}}
+}
+
+func serveViolationSearch(svc service.ViolationService, user *domain.User, target string, htmx bool) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	if htmx {
+		req.Header.Set("HX-Request", "true")
+	}
+	req = req.WithContext(auth.SetUser(req.Context(), user))
+	rr := httptest.NewRecorder()
+	NewViolationHandler(svc, nil, nil, newTestLogger()).Search(rr, req)
+	return rr
+}
+
+func TestViolationSearch_ParsesFiltersAndRendersResults(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Name: "Pat"}
+	svc := newFakeSearchViolationService()
+
+	rr := serveViolationSearch(svc, user, "/violations?q=+guardrail+&severity=serious&status=confirmed&from=2026-03-01&to=2026-03-31&standard=1926.501&page=2", false)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	p := svc.params
+	if p.UserID != user.ID {
+		t.Errorf("expected the search to be scoped to the signed in user, got %s", p.UserID)
+	}
+	if p.Query != "guardrail" || p.Severity != domain.ViolationSeveritySerious || p.Status != domain.ViolationStatusConfirmed || p.StandardNumber != "1926.501" {
+		t.Errorf("unexpected search params %+v", p)
+	}
+	if p.From == nil || p.From.Format("2006-01-02") != "2026-03-01" || p.To == nil || p.To.Format("2006-01-02") != "2026-03-31" {
+		t.Errorf("expected the date range to be parsed, got %v to %v", p.From, p.To)
+	}
+	if p.Offset != domain.DefaultPerPage {
+		t.Errorf("expected page 2 to start at %d, got %d", domain.DefaultPerPage, p.Offset)
+	}
+
+	body := rr.Body.String()
+	v := svc.result.Violation
+	for _, want := range []string{
+		"Missing guardrail on north edge",
+		"Warehouse roof",
+		"Mar 14, 2026",
+		"1926.501(b)(1)",
+		"/inspections/" + v.InspectionID.String() + "/review/queue?violation=" + v.ID.String(),
+		`id="violation-search-q"`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected search page to contain %q", want)
+		}
+	}
+}
+
+func TestViolationSearch_InvalidDate(t *testing.T) {
+	svc := newFakeSearchViolationService()
+
+	rr := serveViolationSearch(svc, &domain.User{ID: uuid.New()}, "/violations?from=03/01/2026", true)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	if svc.calls != 0 {
+		t.Error("expected no search with an invalid date")
+	}
+	if !strings.Contains(rr.Body.String(), "The start date must be a date like 2026-01-31.") {
+		t.Errorf("expected the date error, got %s", rr.Body.String())
+	}
+}
+
+func TestViolationSearch_HtmxRendersResultsOnly(t *testing.T) {
+	svc := newFakeSearchViolationService()
+
+	rr := serveViolationSearch(svc, &domain.User{ID: uuid.New()}, "/violations?q=guardrail", true)
+
+	body := rr.Body.String()
+	if !strings.Contains(body, "Missing guardrail on north edge") {
+		t.Error("expected the results in the partial")
+	}
+	if strings.Contains(body, `id="violation-search-q"`) || strings.Contains(body, "<html") {
+		t.Error("expected only the results, not the page")
+	}
+}
@@ -0,0 +1,230 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/DukeRupert/lukaut/internal/auth"
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/service"
+	"github.com/google/uuid"
+)
+
+// fakeNotesViolationService stores notes for violations owned by owner.
+type fakeNotesViolationService struct {
+	service.ViolationService
+	owner uuid.UUID
+	notes map[uuid.UUID]string
+}
+
+func (f *fakeNotesViolationService) UpdateNotes(ctx context.Context, id, userID uuid.UUID, notes string) error {
+	if userID != f.owner {
+		return domain.NotFound("violation.update_notes", "violation", id.String())
+	}
+	f.notes[id] = notes
+	return nil
+}
+
+func serveUpdateNotes(svc service.ViolationService, violationID uuid.UUID, user *domain.User, notes string) *httptest.ResponseRecorder {
+	form := url.Values{"inspector_notes": {notes}}
+	req := httptest.NewRequest(http.MethodPut, "/violations/"+violationID.String()+"/notes", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetPathValue("id", violationID.String())
+	req = req.WithContext(auth.SetUser(req.Context(), user))
+	rr := httptest.NewRecorder()
+	NewViolationHandler(svc, nil, nil, newTestLogger()).UpdateNotes(rr, req)
+	return rr
+}
+
+func TestViolationUpdateNotes_SavesAndRendersIndicator(t *testing.T) {
+	owner := &domain.User{ID: uuid.New()}
+	svc := &fakeNotesViolationService{owner: owner.ID, notes: map[uuid.UUID]string{}}
+	violationID := uuid.New()
+
+	rr := serveUpdateNotes(svc, violationID, owner, "  Guardrail missing on east side ")
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if svc.notes[violationID] != "Guardrail missing on east side" {
+		t.Errorf("expected trimmed notes to be saved, got %q", svc.notes[violationID])
+	}
+	if !strings.Contains(rr.Body.String(), "Saved") {
+		t.Errorf("expected saved indicator, got:\n%s", rr.Body.String())
+	}
+}
+
+func TestViolationUpdateNotes_OtherUsersViolation(t *testing.T) {
+	svc := &fakeNotesViolationService{owner: uuid.New(), notes: map[uuid.UUID]string{}}
+
+	rr := serveUpdateNotes(svc, uuid.New(), &domain.User{ID: uuid.New()}, "Overwritten")
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", rr.Code)
+	}
+	if len(svc.notes) != 0 {
+		t.Errorf("expected no notes to be saved, got %v", svc.notes)
+	}
+}
+
+// fakeSeverityViolationService holds a single AI-detected violation whose
+// severity can be overridden.
+type fakeSeverityViolationService struct {
+	service.ViolationService
+	violation domain.Violation
+	calls     int
+}
+
+func (f *fakeSeverityViolationService) UpdateSeverity(ctx context.Context, id, userID uuid.UUID, severity domain.ViolationSeverity) error {
+	f.calls++
+	if id != f.violation.ID {
+		return domain.NotFound("violation.update_severity", "violation", id.String())
+	}
+	f.violation.Severity = severity
+	return nil
+}
+
+func (f *fakeSeverityViolationService) GetByIDWithRegulations(ctx context.Context, id, userID uuid.UUID) (*domain.Violation, []domain.ViolationRegulation, error) {
+	v := f.violation
+	return &v, nil, nil
+}
+
+func serveUpdateSeverity(svc service.ViolationService, violationID uuid.UUID, value string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPut, "/violations/"+violationID.String()+"/severity?value="+url.QueryEscape(value), nil)
+	req.SetPathValue("id", violationID.String())
+	req = req.WithContext(auth.SetUser(req.Context(), &domain.User{ID: uuid.New()}))
+	rr := httptest.NewRecorder()
+	NewViolationHandler(svc, nil, nil, newTestLogger()).UpdateSeverity(rr, req)
+	return rr
+}
+
+func newSeverityTestService() *fakeSeverityViolationService {
+	return &fakeSeverityViolationService{violation: domain.Violation{
+		ID:            uuid.New(),
+		Description:   "Missing guardrail",
+		AIDescription: "Missing guardrail (Location: east stair)",
+		Status:        domain.ViolationStatusPending,
+		Severity:      domain.ViolationSeveritySerious,
+		AISeverity:    domain.ViolationSeveritySerious,
+	}}
+}
+
+func TestViolationUpdateSeverity_OverridesAndRendersCard(t *testing.T) {
+	svc := newSeverityTestService()
+
+	rr := serveUpdateSeverity(svc, svc.violation.ID, "critical")
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if svc.violation.Severity != domain.ViolationSeverityCritical {
+		t.Errorf("expected severity critical, got %q", svc.violation.Severity)
+	}
+	if svc.violation.AISeverity != domain.ViolationSeveritySerious {
+		t.Errorf("expected AI severity to stay serious, got %q", svc.violation.AISeverity)
+	}
+	if rr.Header().Get("HX-Trigger") != "violationSeverityChanged" {
+		t.Errorf("expected violationSeverityChanged trigger, got %q", rr.Header().Get("HX-Trigger"))
+	}
+	body := rr.Body.String()
+	if !strings.Contains(body, "Critical") || !strings.Contains(body, "AI assessed: serious") {
+		t.Errorf("expected card with overridden severity and AI original, got:\n%s", body)
+	}
+}
+
+func TestViolationUpdateSeverity_InvalidValue(t *testing.T) {
+	svc := newSeverityTestService()
+
+	rr := serveUpdateSeverity(svc, svc.violation.ID, "catastrophic")
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rr.Code)
+	}
+	if svc.calls != 0 {
+		t.Errorf("expected service not to be called, got %d calls", svc.calls)
+	}
+	if svc.violation.Severity != domain.ViolationSeveritySerious {
+		t.Errorf("expected severity to be unchanged, got %q", svc.violation.Severity)
+	}
+}
+
+// fakeAnnotationViolationService validates boxes the way the real service does
+// and records the annotations it accepts.
+type fakeAnnotationViolationService struct {
+	service.ViolationService
+	added []domain.CreateImageAnnotationParams
+}
+
+func (f *fakeAnnotationViolationService) AddAnnotation(ctx context.Context, params domain.CreateImageAnnotationParams) (*domain.ImageAnnotation, error) {
+	box, err := params.Box.Normalize()
+	if err != nil {
+		return nil, domain.Invalid("violation.add_annotation", err.Error())
+	}
+	f.added = append(f.added, params)
+	return &domain.ImageAnnotation{
+		ID:          uuid.New(),
+		ViolationID: params.ViolationID,
+		ImageID:     uuid.New(),
+		Box:         box,
+		Label:       params.Label,
+		Source:      domain.AnnotationSourceManual,
+	}, nil
+}
+
+func serveCreateAnnotation(svc service.ViolationService, violationID uuid.UUID, form url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/violations/"+violationID.String()+"/annotations", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetPathValue("id", violationID.String())
+	req = req.WithContext(auth.SetUser(req.Context(), &domain.User{ID: uuid.New()}))
+	rr := httptest.NewRecorder()
+	NewViolationHandler(svc, nil, nil, newTestLogger()).CreateAnnotation(rr, req)
+	return rr
+}
+
+func TestViolationCreateAnnotation_StoresManualBox(t *testing.T) {
+	svc := &fakeAnnotationViolationService{}
+
+	rr := serveCreateAnnotation(svc, uuid.New(), url.Values{
+		"x": {"0.25"}, "y": {"0.5"}, "width": {"0.2"}, "height": {"0.1"}, "label": {"guardrail"},
+	})
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if len(svc.added) != 1 || svc.added[0].Box.X != 0.25 || svc.added[0].Label != "guardrail" {
+		t.Errorf("expected box to be passed to the service, got %+v", svc.added)
+	}
+	if !strings.Contains(rr.Body.String(), `"source":"manual"`) {
+		t.Errorf("expected manual annotation in response, got %s", rr.Body.String())
+	}
+}
+
+func TestViolationCreateAnnotation_RejectsBadCoordinates(t *testing.T) {
+	tests := []struct {
+		name string
+		form url.Values
+	}{
+		{"non-numeric", url.Values{"x": {"left"}, "y": {"0.5"}, "width": {"0.2"}, "height": {"0.1"}}},
+		{"outside image", url.Values{"x": {"1.5"}, "y": {"0.5"}, "width": {"0.2"}, "height": {"0.1"}}},
+		{"empty box", url.Values{"x": {"0.1"}, "y": {"0.5"}, "width": {"0"}, "height": {"0.1"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &fakeAnnotationViolationService{}
+
+			rr := serveCreateAnnotation(svc, uuid.New(), tt.form)
+
+			if rr.Code != http.StatusBadRequest {
+				t.Errorf("expected 400, got %d", rr.Code)
+			}
+			if len(svc.added) != 0 {
+				t.Errorf("expected nothing to be stored, got %+v", svc.added)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+// Package handler contains HTTP handlers for the Lukaut application.
+//
+// This file implements the weather suggestion shown on the inspection edit
+// form.
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/DukeRupert/lukaut/internal/auth"
+	"github.com/DukeRupert/lukaut/internal/service"
+	"github.com/DukeRupert/lukaut/internal/templ/partials"
+	"github.com/google/uuid"
+)
+
+// WeatherHandler serves weather suggestions for inspections.
+type WeatherHandler struct {
+	weatherService service.WeatherService
+	logger         *slog.Logger
+}
+
+// NewWeatherHandler creates a new WeatherHandler.
+func NewWeatherHandler(weatherService service.WeatherService, logger *slog.Logger) *WeatherHandler {
+	return &WeatherHandler{
+		weatherService: weatherService,
+		logger:         logger,
+	}
+}
+
+// Suggest renders the weather at the inspection's site on its inspection date.
+// GET /inspections/{id}/weather-suggestion
+//
+// The suggestion is a convenience, so lookup failures and missing data
+// render an empty partial rather than an error.
+func (h *WeatherHandler) Suggest(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUserFromRequest(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid inspection ID", http.StatusBadRequest)
+		return
+	}
+
+	var suggestion *partials.WeatherSuggestion
+	result, err := h.weatherService.Suggest(r.Context(), id, user.ID)
+	if err != nil {
+		h.logger.WarnContext(r.Context(), "weather suggestion lookup failed", "error", err, "inspection_id", id)
+	} else if result != nil {
+		suggestion = &partials.WeatherSuggestion{
+			Conditions:  result.Conditions,
+			Temperature: result.Temperature,
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := partials.WeatherSuggestionPrompt(suggestion).Render(r.Context(), w); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to render weather suggestion", "error", err)
+	}
+}
+
+// RegisterRoutes registers the weather routes.
+func (h *WeatherHandler) RegisterRoutes(mux *http.ServeMux, requireUser func(http.Handler) http.Handler) {
+	mux.Handle("GET /inspections/{id}/weather-suggestion", requireUser(http.HandlerFunc(h.Suggest)))
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DukeRupert/lukaut/internal/auth"
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/service"
+	"github.com/google/uuid"
+)
+
+// fakeWeatherService returns a fixed suggestion.
+type fakeWeatherService struct {
+	service.WeatherService
+	suggestion *domain.WeatherSuggestion
+	err        error
+}
+
+func (s *fakeWeatherService) Suggest(ctx context.Context, inspectionID, userID uuid.UUID) (*domain.WeatherSuggestion, error) {
+	return s.suggestion, s.err
+}
+
+func serveWeatherSuggestion(s *fakeWeatherService) *httptest.ResponseRecorder {
+	id := uuid.New()
+	req := httptest.NewRequest(http.MethodGet, "/inspections/"+id.String()+"/weather-suggestion", nil)
+	req.SetPathValue("id", id.String())
+	req = req.WithContext(auth.SetUser(req.Context(), &domain.User{ID: uuid.New()}))
+	rr := httptest.NewRecorder()
+	NewWeatherHandler(s, newTestLogger()).Suggest(rr, req)
+	return rr
+}
+
+func TestWeatherSuggest_RendersSuggestion(t *testing.T) {
+	rr := serveWeatherSuggestion(&fakeWeatherService{suggestion: &domain.WeatherSuggestion{
+		Conditions:  "Partially cloudy",
+		Temperature: "72°F",
+	}})
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	body := rr.Body.String()
+	for _, want := range []string{
+		`data-weather_conditions="Partially cloudy"`,
+		`data-temperature="72°F"`,
+		"Use",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected response to contain %q, got:\n%s", want, body)
+		}
+	}
+}
+
+func TestWeatherSuggest_NoSuggestionRendersNothing(t *testing.T) {
+	for name, s := range map[string]*fakeWeatherService{
+		"no data":       {},
+		"lookup failed": {err: domain.Internal(errors.New("timeout"), "weather.suggest", "failed to look up weather")},
+	} {
+		t.Run(name, func(t *testing.T) {
+			rr := serveWeatherSuggestion(s)
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d", rr.Code)
+			}
+			if strings.TrimSpace(rr.Body.String()) != "" {
+				t.Errorf("expected an empty partial, got:\n%s", rr.Body.String())
+			}
+		})
+	}
+}
@@ -7,11 +7,18 @@
 //
 // This route is PUBLIC (no auth middleware) because Stripe calls it directly.
 // Authentication is via the Stripe webhook signature verification.
+//
+// Stripe retries a delivery until it receives a 2xx response, so every event
+// is recorded by ID once it has been applied and later deliveries are
+// acknowledged without being applied again. Failures that a retry could fix
+// (e.g. database errors) return 500 without recording the event, so Stripe
+// delivers it again.
 package handler
 
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
@@ -19,23 +26,26 @@ import (
 	"github.com/DukeRupert/lukaut/internal/billing"
 	"github.com/DukeRupert/lukaut/internal/domain"
 	"github.com/DukeRupert/lukaut/internal/service"
+	"github.com/google/uuid"
 	"github.com/stripe/stripe-go/v79"
 )
 
 // WebhookHandler handles incoming webhook events from Stripe.
 type WebhookHandler struct {
-	billing     billing.Service
-	userService service.UserService
-	logger      *slog.Logger
+	billing      billing.Service
+	userService  service.UserService
+	eventService service.WebhookEventService
+	logger       *slog.Logger
 }
 
 // NewWebhookHandler creates a new WebhookHandler.
 // billingService may be nil when Stripe is not configured.
-func NewWebhookHandler(billingService billing.Service, userService service.UserService, logger *slog.Logger) *WebhookHandler {
+func NewWebhookHandler(billingService billing.Service, userService service.UserService, eventService service.WebhookEventService, logger *slog.Logger) *WebhookHandler {
 	return &WebhookHandler{
-		billing:     billingService,
-		userService: userService,
-		logger:      logger,
+		billing:      billingService,
+		userService:  userService,
+		eventService: eventService,
+		logger:       logger,
 	}
 }
 
@@ -48,7 +58,7 @@ func (h *WebhookHandler) RegisterRoutes(mux *http.ServeMux) {
 // HandleStripeWebhook processes incoming Stripe webhook events.
 func (h *WebhookHandler) HandleStripeWebhook(w http.ResponseWriter, r *http.Request) {
 	if h.billing == nil {
-		h.logger.Warn("stripe webhook received but billing is not configured")
+		h.logger.WarnContext(r.Context(), "stripe webhook received but billing is not configured")
 		w.WriteHeader(http.StatusOK)
 		return
 	}
@@ -56,7 +66,7 @@ func (h *WebhookHandler) HandleStripeWebhook(w http.ResponseWriter, r *http.Requ
 	// Read body (limit to 64KB)
 	body, err := io.ReadAll(io.LimitReader(r.Body, 65536))
 	if err != nil {
-		h.logger.Error("failed to read webhook body", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to read webhook body", "error", err)
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
@@ -65,183 +75,247 @@ func (h *WebhookHandler) HandleStripeWebhook(w http.ResponseWriter, r *http.Requ
 	signature := r.Header.Get("Stripe-Signature")
 	event, err := h.billing.VerifyWebhookSignature(body, signature)
 	if err != nil {
-		h.logger.Warn("webhook signature verification failed", "error", err)
+		h.logger.WarnContext(r.Context(), "webhook signature verification failed", "error", err)
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
 
-	h.logger.Info("stripe webhook received", "type", event.Type, "id", event.ID)
+	h.logger.InfoContext(r.Context(), "stripe webhook received", "type", event.Type, "id", event.ID)
+
+	// Skip events a previous delivery already applied
+	processed, err := h.eventService.Processed(r.Context(), event.ID)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to check webhook event", "error", err, "id", event.ID)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if processed {
+		h.logger.InfoContext(r.Context(), "duplicate stripe webhook ignored", "type", event.Type, "id", event.ID)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if err := h.processEvent(r.Context(), event); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to process webhook event", "error", err, "type", event.Type, "id", event.ID)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// Record the event only once it has been applied; if this fails, the
+	// retry applies it again, which is harmless
+	if err := h.eventService.MarkProcessed(r.Context(), event.ID, string(event.Type)); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to record webhook event", "error", err, "id", event.ID)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
 
-	// Route to event-specific handler
+// processEvent routes an event to its handler. Returned errors are
+// retryable; events that can never succeed (malformed payloads, unknown
+// customers) are logged and return nil.
+func (h *WebhookHandler) processEvent(ctx context.Context, event stripe.Event) error {
 	switch event.Type {
 	case "checkout.session.completed":
-		h.handleCheckoutCompleted(event)
+		return h.handleCheckoutCompleted(ctx, event)
 	case "customer.subscription.created":
-		h.handleSubscriptionCreated(event)
+		return h.processSubscriptionEvent(ctx, event, "created")
 	case "customer.subscription.updated":
-		h.handleSubscriptionUpdated(event)
+		return h.processSubscriptionEvent(ctx, event, "updated")
 	case "customer.subscription.deleted":
-		h.handleSubscriptionDeleted(event)
+		return h.handleSubscriptionDeleted(ctx, event)
 	case "invoice.payment_succeeded":
-		h.handlePaymentSucceeded(event)
+		return h.handlePaymentSucceeded(ctx, event)
 	case "invoice.payment_failed":
-		h.handlePaymentFailed(event)
+		return h.handlePaymentFailed(ctx, event)
 	default:
-		h.logger.Debug("unhandled webhook event type", "type", event.Type)
+		h.logger.DebugContext(ctx, "unhandled webhook event type", "type", event.Type)
+		return nil
 	}
-
-	w.WriteHeader(http.StatusOK)
 }
 
-func (h *WebhookHandler) handleCheckoutCompleted(event stripe.Event) {
+func (h *WebhookHandler) handleCheckoutCompleted(ctx context.Context, event stripe.Event) error {
 	var session stripe.CheckoutSession
 	if err := json.Unmarshal(event.Data.Raw, &session); err != nil {
-		h.logger.Error("failed to parse checkout session", "error", err)
-		return
+		h.logger.ErrorContext(ctx, "failed to parse checkout session", "error", err)
+		return nil
 	}
 
 	if session.Customer == nil || session.Subscription == nil {
-		h.logger.Warn("checkout session missing customer or subscription", "session_id", session.ID)
-		return
+		h.logger.WarnContext(ctx, "checkout session missing customer or subscription", "session_id", session.ID)
+		return nil
 	}
 
 	customerID := session.Customer.ID
 	subscriptionID := session.Subscription.ID
 
-	// Look up user by Stripe customer ID, or by email if customer is new
-	user, err := h.userService.GetByStripeCustomerID(r_ctx(), customerID)
+	user, err := h.userForCheckout(ctx, customerID, session.ClientReferenceID)
 	if err != nil {
-		h.logger.Info("user not found by customer ID, checkout may update on subscription event",
-			"customer_id", customerID, "subscription_id", subscriptionID)
-		return
+		if domain.ErrorCode(err) == domain.ENOTFOUND {
+			h.logger.WarnContext(ctx, "user not found for checkout session",
+				"customer_id", customerID, "client_reference_id", session.ClientReferenceID, "session_id", session.ID)
+			return nil
+		}
+		return err
 	}
 
-	// Update subscription info
-	if err := h.userService.UpdateSubscription(r_ctx(), user.ID, string(domain.SubscriptionStatusActive), "", subscriptionID); err != nil {
-		h.logger.Error("failed to update subscription on checkout", "error", err, "user_id", user.ID)
+	// Link the customer if checkout created it before we saved the ID
+	if user.StripeCustomerID != customerID {
+		if err := h.userService.UpdateStripeCustomer(ctx, user.ID, customerID); err != nil {
+			return fmt.Errorf("link stripe customer: %w", err)
+		}
 	}
-}
 
-func (h *WebhookHandler) handleSubscriptionCreated(event stripe.Event) {
-	h.processSubscriptionEvent(event, "created")
+	// Keep the current tier if the session predates tier metadata
+	tier := session.Metadata[billing.MetadataTier]
+	if tier == "" {
+		tier = string(user.SubscriptionTier)
+	}
+
+	if err := h.userService.UpdateSubscription(ctx, user.ID, string(domain.SubscriptionStatusActive), tier, subscriptionID); err != nil {
+		return fmt.Errorf("update subscription on checkout: %w", err)
+	}
+
+	h.logger.InfoContext(ctx, "checkout completed", "user_id", user.ID, "tier", tier, "subscription_id", subscriptionID)
+	return nil
 }
 
-func (h *WebhookHandler) handleSubscriptionUpdated(event stripe.Event) {
-	h.processSubscriptionEvent(event, "updated")
+// userForCheckout finds the user for a completed checkout by Stripe customer
+// ID, falling back to the client reference ID (our user ID) set at checkout.
+func (h *WebhookHandler) userForCheckout(ctx context.Context, customerID, clientReferenceID string) (*domain.User, error) {
+	user, err := h.userService.GetByStripeCustomerID(ctx, customerID)
+	if err == nil || domain.ErrorCode(err) != domain.ENOTFOUND {
+		return user, err
+	}
+
+	userID, parseErr := uuid.Parse(clientReferenceID)
+	if parseErr != nil {
+		return nil, err
+	}
+	return h.userService.GetByID(ctx, userID)
 }
 
-func (h *WebhookHandler) processSubscriptionEvent(event stripe.Event, action string) {
+func (h *WebhookHandler) processSubscriptionEvent(ctx context.Context, event stripe.Event, action string) error {
 	var sub stripe.Subscription
 	if err := json.Unmarshal(event.Data.Raw, &sub); err != nil {
-		h.logger.Error("failed to parse subscription event", "error", err, "action", action)
-		return
+		h.logger.ErrorContext(ctx, "failed to parse subscription event", "error", err, "action", action)
+		return nil
 	}
 
 	if sub.Customer == nil {
-		h.logger.Warn("subscription event missing customer", "subscription_id", sub.ID, "action", action)
-		return
+		h.logger.WarnContext(ctx, "subscription event missing customer", "subscription_id", sub.ID, "action", action)
+		return nil
 	}
 
-	user, err := h.userService.GetByStripeCustomerID(r_ctx(), sub.Customer.ID)
-	if err != nil {
-		h.logger.Warn("user not found for subscription event",
-			"customer_id", sub.Customer.ID, "subscription_id", sub.ID, "action", action)
-		return
+	user, ok, err := h.userForCustomer(ctx, sub.Customer.ID)
+	if !ok {
+		return err
 	}
 
 	// Determine tier from price
 	tier := ""
-	if len(sub.Items.Data) > 0 && sub.Items.Data[0].Price != nil {
+	if sub.Items != nil && len(sub.Items.Data) > 0 && sub.Items.Data[0].Price != nil {
 		tier = h.billing.TierForPriceID(sub.Items.Data[0].Price.ID)
 	}
 
 	status := string(sub.Status)
-	if err := h.userService.UpdateSubscription(r_ctx(), user.ID, status, tier, sub.ID); err != nil {
-		h.logger.Error("failed to update subscription", "error", err, "user_id", user.ID, "action", action)
+	if err := h.userService.UpdateSubscription(ctx, user.ID, status, tier, sub.ID); err != nil {
+		return fmt.Errorf("update subscription (%s): %w", action, err)
 	}
 
-	h.logger.Info("subscription event processed",
+	h.logger.InfoContext(ctx, "subscription event processed",
 		"user_id", user.ID, "action", action, "status", status, "tier", tier)
+	return nil
 }
 
-func (h *WebhookHandler) handleSubscriptionDeleted(event stripe.Event) {
+func (h *WebhookHandler) handleSubscriptionDeleted(ctx context.Context, event stripe.Event) error {
 	var sub stripe.Subscription
 	if err := json.Unmarshal(event.Data.Raw, &sub); err != nil {
-		h.logger.Error("failed to parse subscription deleted event", "error", err)
-		return
+		h.logger.ErrorContext(ctx, "failed to parse subscription deleted event", "error", err)
+		return nil
 	}
 
 	if sub.Customer == nil {
-		h.logger.Warn("subscription deleted event missing customer", "subscription_id", sub.ID)
-		return
+		h.logger.WarnContext(ctx, "subscription deleted event missing customer", "subscription_id", sub.ID)
+		return nil
 	}
 
-	user, err := h.userService.GetByStripeCustomerID(r_ctx(), sub.Customer.ID)
-	if err != nil {
-		h.logger.Warn("user not found for subscription deletion", "customer_id", sub.Customer.ID)
-		return
+	user, ok, err := h.userForCustomer(ctx, sub.Customer.ID)
+	if !ok {
+		return err
 	}
 
-	if err := h.userService.UpdateSubscription(r_ctx(), user.ID, string(domain.SubscriptionStatusInactive), "", ""); err != nil {
-		h.logger.Error("failed to deactivate subscription", "error", err, "user_id", user.ID)
+	if err := h.userService.UpdateSubscription(ctx, user.ID, string(domain.SubscriptionStatusInactive), "", ""); err != nil {
+		return fmt.Errorf("deactivate subscription: %w", err)
 	}
 
-	h.logger.Info("subscription deleted", "user_id", user.ID, "subscription_id", sub.ID)
+	h.logger.InfoContext(ctx, "subscription deleted", "user_id", user.ID, "subscription_id", sub.ID)
+	return nil
 }
 
-func (h *WebhookHandler) handlePaymentSucceeded(event stripe.Event) {
+func (h *WebhookHandler) handlePaymentSucceeded(ctx context.Context, event stripe.Event) error {
 	var invoice stripe.Invoice
 	if err := json.Unmarshal(event.Data.Raw, &invoice); err != nil {
-		h.logger.Error("failed to parse invoice payment succeeded event", "error", err)
-		return
+		h.logger.ErrorContext(ctx, "failed to parse invoice payment succeeded event", "error", err)
+		return nil
 	}
 
 	if invoice.Customer == nil {
-		return
+		return nil
 	}
 
-	user, err := h.userService.GetByStripeCustomerID(r_ctx(), invoice.Customer.ID)
-	if err != nil {
-		h.logger.Debug("user not found for payment succeeded", "customer_id", invoice.Customer.ID)
-		return
+	user, ok, err := h.userForCustomer(ctx, invoice.Customer.ID)
+	if !ok {
+		return err
 	}
 
 	// Ensure status is active (recovery from past_due)
 	if user.SubscriptionStatus != domain.SubscriptionStatusActive {
-		if err := h.userService.UpdateSubscription(r_ctx(), user.ID,
+		if err := h.userService.UpdateSubscription(ctx, user.ID,
 			string(domain.SubscriptionStatusActive), string(user.SubscriptionTier), user.SubscriptionID); err != nil {
-			h.logger.Error("failed to reactivate on payment success", "error", err, "user_id", user.ID)
+			return fmt.Errorf("reactivate on payment success: %w", err)
 		}
 	}
+	return nil
 }
 
-func (h *WebhookHandler) handlePaymentFailed(event stripe.Event) {
+func (h *WebhookHandler) handlePaymentFailed(ctx context.Context, event stripe.Event) error {
 	var invoice stripe.Invoice
 	if err := json.Unmarshal(event.Data.Raw, &invoice); err != nil {
-		h.logger.Error("failed to parse invoice payment failed event", "error", err)
-		return
+		h.logger.ErrorContext(ctx, "failed to parse invoice payment failed event", "error", err)
+		return nil
 	}
 
 	if invoice.Customer == nil {
-		return
+		return nil
 	}
 
-	user, err := h.userService.GetByStripeCustomerID(r_ctx(), invoice.Customer.ID)
-	if err != nil {
-		h.logger.Debug("user not found for payment failed", "customer_id", invoice.Customer.ID)
-		return
+	user, ok, err := h.userForCustomer(ctx, invoice.Customer.ID)
+	if !ok {
+		return err
 	}
 
-	if err := h.userService.UpdateSubscription(r_ctx(), user.ID,
+	if err := h.userService.UpdateSubscription(ctx, user.ID,
 		string(domain.SubscriptionStatusPastDue), string(user.SubscriptionTier), user.SubscriptionID); err != nil {
-		h.logger.Error("failed to set past_due on payment failure", "error", err, "user_id", user.ID)
+		return fmt.Errorf("set past_due on payment failure: %w", err)
 	}
 
-	h.logger.Warn("payment failed", "user_id", user.ID, "customer_id", invoice.Customer.ID)
+	h.logger.WarnContext(ctx, "payment failed", "user_id", user.ID, "customer_id", invoice.Customer.ID)
+	return nil
 }
 
-// r_ctx returns a background context for webhook processing.
-// Webhooks are async events and don't have a request context from a user session.
-func r_ctx() context.Context {
-	return context.Background()
+// userForCustomer looks up the user for a Stripe customer. ok is false when
+// the event should not be applied; err is non-nil only if a retry could help.
+// Unknown customers are logged and skipped, since retrying cannot fix them.
+func (h *WebhookHandler) userForCustomer(ctx context.Context, customerID string) (*domain.User, bool, error) {
+	user, err := h.userService.GetByStripeCustomerID(ctx, customerID)
+	if err != nil {
+		if domain.ErrorCode(err) == domain.ENOTFOUND {
+			h.logger.WarnContext(ctx, "user not found for stripe customer", "customer_id", customerID)
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("look up stripe customer: %w", err)
+	}
+	return user, true, nil
 }
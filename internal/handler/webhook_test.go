@@ -0,0 +1,342 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/DukeRupert/lukaut/internal/billing"
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stripe/stripe-go/v79/webhook"
+)
+
+// Recorded payloads in testdata/stripe reference these IDs.
+const (
+	testWebhookSecret     = "whsec_test_secret"
+	testStripeCustomerID  = "cus_QbR4tYh2nK8wLm"
+	testSubscriptionID    = "sub_1PkQ2vLkdIwHu7ixQz3aB8Rt"
+	testClientReferenceID = "8d3f2c1a-6b4e-4f7a-9c2d-1e5b7a9c3f40"
+)
+
+// =============================================================================
+// Mock WebhookEventService Implementation
+// =============================================================================
+
+// mockWebhookEventService implements service.WebhookEventService in memory.
+type mockWebhookEventService struct {
+	mu           sync.Mutex
+	processed    map[string]bool
+	ProcessedErr error
+	MarkErr      error
+}
+
+func newMockWebhookEventService() *mockWebhookEventService {
+	return &mockWebhookEventService{processed: make(map[string]bool)}
+}
+
+func (m *mockWebhookEventService) Processed(ctx context.Context, eventID string) (bool, error) {
+	if m.ProcessedErr != nil {
+		return false, m.ProcessedErr
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.processed[eventID], nil
+}
+
+func (m *mockWebhookEventService) MarkProcessed(ctx context.Context, eventID, eventType string) error {
+	if m.MarkErr != nil {
+		return m.MarkErr
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.processed[eventID] = true
+	return nil
+}
+
+// =============================================================================
+// Test Helpers
+// =============================================================================
+
+// subscriptionUpdate records a call to UserService.UpdateSubscription.
+type subscriptionUpdate struct {
+	UserID         uuid.UUID
+	Status         string
+	Tier           string
+	SubscriptionID string
+}
+
+// newTestWebhookHandler wires a webhook handler with real signature
+// verification and records subscription updates.
+func newTestWebhookHandler(users *mockUserService, events *mockWebhookEventService) *WebhookHandler {
+	billingService := billing.NewStripeService("sk_test_unused", testWebhookSecret, billing.PriceConfig{
+		StarterMonthlyPriceID:      "price_starter_monthly",
+		ProfessionalMonthlyPriceID: "price_professional_monthly",
+	})
+	return NewWebhookHandler(billingService, users, events, newTestLogger())
+}
+
+// recordUpdates makes users record UpdateSubscription calls into updates.
+func recordUpdates(users *mockUserService, updates *[]subscriptionUpdate) {
+	users.UpdateSubscriptionFunc = func(ctx context.Context, userID uuid.UUID, status, tier, subscriptionID string) error {
+		*updates = append(*updates, subscriptionUpdate{userID, status, tier, subscriptionID})
+		return nil
+	}
+}
+
+// loadStripeEvent reads a recorded Stripe event payload.
+func loadStripeEvent(t *testing.T, name string) []byte {
+	t.Helper()
+	payload, err := os.ReadFile(filepath.Join("testdata", "stripe", name))
+	if err != nil {
+		t.Fatalf("failed to read recorded event: %v", err)
+	}
+	return payload
+}
+
+// newSignedWebhookRequest builds a webhook request signed with the test secret.
+func newSignedWebhookRequest(payload []byte) *http.Request {
+	signed := webhook.GenerateTestSignedPayload(&webhook.UnsignedPayload{
+		Payload: payload,
+		Secret:  testWebhookSecret,
+	})
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/stripe", bytes.NewReader(payload))
+	req.Header.Set("Stripe-Signature", signed.Header)
+	return req
+}
+
+// deliver sends a signed payload to the handler and returns the status code.
+func deliver(h *WebhookHandler, payload []byte) int {
+	rr := httptest.NewRecorder()
+	h.HandleStripeWebhook(rr, newSignedWebhookRequest(payload))
+	return rr.Code
+}
+
+// =============================================================================
+// HandleStripeWebhook Tests
+// =============================================================================
+
+func TestStripeWebhook_CheckoutCompletedLinksCustomerAndSetsTier(t *testing.T) {
+	userID := uuid.MustParse(testClientReferenceID)
+	var updates []subscriptionUpdate
+	var linkedCustomer string
+
+	users := &mockUserService{
+		// Customer ID was not saved before checkout completed
+		GetByStripeCustomerIDFunc: func(ctx context.Context, customerID string) (*domain.User, error) {
+			return nil, domain.NotFound("test", "user", customerID)
+		},
+		GetByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+			if id != userID {
+				t.Errorf("expected lookup by client reference ID, got %s", id)
+			}
+			return &domain.User{ID: userID, SubscriptionTier: domain.SubscriptionTierFree}, nil
+		},
+		UpdateStripeCustomerFunc: func(ctx context.Context, id uuid.UUID, customerID string) error {
+			linkedCustomer = customerID
+			return nil
+		},
+	}
+	recordUpdates(users, &updates)
+	h := newTestWebhookHandler(users, newMockWebhookEventService())
+
+	if code := deliver(h, loadStripeEvent(t, "checkout_session_completed.json")); code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", code)
+	}
+
+	if linkedCustomer != testStripeCustomerID {
+		t.Errorf("expected customer %s to be linked, got %q", testStripeCustomerID, linkedCustomer)
+	}
+	want := subscriptionUpdate{userID, "active", "starter", testSubscriptionID}
+	if len(updates) != 1 || updates[0] != want {
+		t.Errorf("expected update %+v, got %+v", want, updates)
+	}
+}
+
+func TestStripeWebhook_SubscriptionUpdatedSetsTierFromPrice(t *testing.T) {
+	userID := uuid.New()
+	var updates []subscriptionUpdate
+
+	users := &mockUserService{
+		GetByStripeCustomerIDFunc: func(ctx context.Context, customerID string) (*domain.User, error) {
+			return &domain.User{ID: userID, StripeCustomerID: customerID}, nil
+		},
+	}
+	recordUpdates(users, &updates)
+	h := newTestWebhookHandler(users, newMockWebhookEventService())
+
+	if code := deliver(h, loadStripeEvent(t, "customer_subscription_updated.json")); code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", code)
+	}
+
+	want := subscriptionUpdate{userID, "active", "professional", testSubscriptionID}
+	if len(updates) != 1 || updates[0] != want {
+		t.Errorf("expected update %+v, got %+v", want, updates)
+	}
+}
+
+func TestStripeWebhook_SubscriptionDeletedDeactivates(t *testing.T) {
+	userID := uuid.New()
+	var updates []subscriptionUpdate
+
+	users := &mockUserService{
+		GetByStripeCustomerIDFunc: func(ctx context.Context, customerID string) (*domain.User, error) {
+			return &domain.User{ID: userID, StripeCustomerID: customerID}, nil
+		},
+	}
+	recordUpdates(users, &updates)
+	h := newTestWebhookHandler(users, newMockWebhookEventService())
+
+	if code := deliver(h, loadStripeEvent(t, "customer_subscription_deleted.json")); code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", code)
+	}
+
+	want := subscriptionUpdate{userID, "inactive", "", ""}
+	if len(updates) != 1 || updates[0] != want {
+		t.Errorf("expected update %+v, got %+v", want, updates)
+	}
+}
+
+func TestStripeWebhook_DuplicateDeliveryAppliedOnce(t *testing.T) {
+	var updates []subscriptionUpdate
+
+	users := &mockUserService{
+		GetByStripeCustomerIDFunc: func(ctx context.Context, customerID string) (*domain.User, error) {
+			return &domain.User{ID: uuid.New(), StripeCustomerID: customerID}, nil
+		},
+	}
+	recordUpdates(users, &updates)
+	h := newTestWebhookHandler(users, newMockWebhookEventService())
+
+	payload := loadStripeEvent(t, "customer_subscription_updated.json")
+	for i := 0; i < 3; i++ {
+		if code := deliver(h, payload); code != http.StatusOK {
+			t.Fatalf("delivery %d: expected status 200, got %d", i+1, code)
+		}
+	}
+
+	if len(updates) != 1 {
+		t.Errorf("expected retried event to be applied once, got %d updates", len(updates))
+	}
+}
+
+func TestStripeWebhook_FailedProcessingIsRetried(t *testing.T) {
+	var updates []subscriptionUpdate
+	failNext := true
+
+	users := &mockUserService{
+		GetByStripeCustomerIDFunc: func(ctx context.Context, customerID string) (*domain.User, error) {
+			return &domain.User{ID: uuid.New(), StripeCustomerID: customerID}, nil
+		},
+		UpdateSubscriptionFunc: func(ctx context.Context, userID uuid.UUID, status, tier, subscriptionID string) error {
+			if failNext {
+				failNext = false
+				return errors.New("connection refused")
+			}
+			updates = append(updates, subscriptionUpdate{userID, status, tier, subscriptionID})
+			return nil
+		},
+	}
+	events := newMockWebhookEventService()
+	h := newTestWebhookHandler(users, events)
+
+	payload := loadStripeEvent(t, "customer_subscription_deleted.json")
+	if code := deliver(h, payload); code != http.StatusInternalServerError {
+		t.Fatalf("expected status 500 so Stripe retries, got %d", code)
+	}
+	if len(events.processed) != 0 {
+		t.Fatal("expected the failed event not to be recorded")
+	}
+	if code := deliver(h, payload); code != http.StatusOK {
+		t.Fatalf("expected retry to succeed, got %d", code)
+	}
+
+	if len(updates) != 1 {
+		t.Errorf("expected retry to apply the event, got %d updates", len(updates))
+	}
+}
+
+func TestStripeWebhook_UnknownCustomerAcknowledged(t *testing.T) {
+	var updates []subscriptionUpdate
+
+	users := &mockUserService{
+		GetByStripeCustomerIDFunc: func(ctx context.Context, customerID string) (*domain.User, error) {
+			return nil, domain.NotFound("test", "user", customerID)
+		},
+	}
+	recordUpdates(users, &updates)
+	h := newTestWebhookHandler(users, newMockWebhookEventService())
+
+	if code := deliver(h, loadStripeEvent(t, "customer_subscription_updated.json")); code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", code)
+	}
+	if len(updates) != 0 {
+		t.Errorf("expected no updates for unknown customer, got %+v", updates)
+	}
+}
+
+func TestStripeWebhook_RejectsInvalidSignature(t *testing.T) {
+	events := newMockWebhookEventService()
+	h := newTestWebhookHandler(&mockUserService{}, events)
+
+	payload := loadStripeEvent(t, "customer_subscription_deleted.json")
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/stripe", bytes.NewReader(payload))
+	req.Header.Set("Stripe-Signature", "t=1722950400,v1=deadbeef")
+	rr := httptest.NewRecorder()
+	h.HandleStripeWebhook(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", rr.Code)
+	}
+	if len(events.processed) != 0 {
+		t.Error("expected unverified event not to be recorded")
+	}
+}
+
+func TestStripeWebhook_LookupFailureIsRetried(t *testing.T) {
+	events := newMockWebhookEventService()
+	events.ProcessedErr = errors.New("database unavailable")
+	h := newTestWebhookHandler(&mockUserService{}, events)
+
+	if code := deliver(h, loadStripeEvent(t, "customer_subscription_deleted.json")); code != http.StatusInternalServerError {
+		t.Errorf("expected status 500, got %d", code)
+	}
+}
+
+func TestStripeWebhook_RecordFailureIsRetried(t *testing.T) {
+	var updates []subscriptionUpdate
+	userID := uuid.New()
+
+	users := &mockUserService{
+		GetByStripeCustomerIDFunc: func(ctx context.Context, customerID string) (*domain.User, error) {
+			return &domain.User{ID: userID, StripeCustomerID: customerID}, nil
+		},
+	}
+	recordUpdates(users, &updates)
+	events := newMockWebhookEventService()
+	events.MarkErr = errors.New("database unavailable")
+	h := newTestWebhookHandler(users, events)
+
+	payload := loadStripeEvent(t, "customer_subscription_deleted.json")
+	if code := deliver(h, payload); code != http.StatusInternalServerError {
+		t.Fatalf("expected status 500 so Stripe retries, got %d", code)
+	}
+
+	// The retry applies the event again, which sets the same state
+	events.MarkErr = nil
+	if code := deliver(h, payload); code != http.StatusOK {
+		t.Fatalf("expected retry to succeed, got %d", code)
+	}
+	if len(updates) != 2 || updates[0] != updates[1] {
+		t.Errorf("expected the retry to apply the same update, got %+v", updates)
+	}
+	if !events.processed["evt_1PkS9aLkdIwHu7ixJ4vR8dLe"] {
+		t.Error("expected the event recorded after the retry")
+	}
+}
@@ -0,0 +1,123 @@
+// Package jobevents fans out background job state changes to subscribers in
+// the same process, such as the server-sent event stream of an inspection's
+// analysis progress. Events are hints to re-read state from the database,
+// not a durable log: a slow subscriber may miss some.
+package jobevents
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// State is a job's state as reported to subscribers.
+type State string
+
+const (
+	StateRunning   State = "running"   // A worker picked up the job
+	StateProgress  State = "progress"  // The job made progress, e.g. finished an image
+	StateRetrying  State = "retrying"  // The job failed and will be retried
+	StateCompleted State = "completed" // The job succeeded
+	StateFailed    State = "failed"    // The job failed for the last time
+)
+
+// Done reports whether the job will not change state again.
+func (s State) Done() bool {
+	return s == StateCompleted || s == StateFailed
+}
+
+// Event reports a job state change for an inspection.
+type Event struct {
+	InspectionID uuid.UUID
+	JobType      string
+	State        State
+
+	// For StateProgress events of an analysis, the image that finished and
+	// how far the job has got
+	ImageID     uuid.UUID
+	ImageFailed bool
+	Finished    int // Images finished so far, including this one
+	Total       int // Images the job analyzes
+}
+
+// subscriberBuffer is how many events a subscriber may fall behind before
+// further events are dropped for it.
+const subscriberBuffer = 16
+
+// Broker delivers published events to the subscribers of the event's
+// inspection. The zero value is not usable; create one with NewBroker.
+type Broker struct {
+	mu     sync.Mutex
+	subs   map[uuid.UUID]map[chan Event]struct{}
+	closed bool
+}
+
+// NewBroker creates a Broker with no subscribers.
+func NewBroker() *Broker {
+	return &Broker{subs: make(map[uuid.UUID]map[chan Event]struct{})}
+}
+
+// Subscribe returns a channel of the events published for inspectionID and
+// a function that unsubscribes. The channel is closed when unsubscribing or
+// when the broker is closed.
+func (b *Broker) Subscribe(inspectionID uuid.UUID) (<-chan Event, func()) {
+	ch := make(chan Event, subscriberBuffer)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	if b.subs[inspectionID] == nil {
+		b.subs[inspectionID] = make(map[chan Event]struct{})
+	}
+	b.subs[inspectionID][ch] = struct{}{}
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() { b.unsubscribe(inspectionID, ch) })
+	}
+}
+
+// unsubscribe removes and closes ch, unless Close already did.
+func (b *Broker) unsubscribe(inspectionID uuid.UUID, ch chan Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.subs[inspectionID][ch]; !ok {
+		return
+	}
+	delete(b.subs[inspectionID], ch)
+	if len(b.subs[inspectionID]) == 0 {
+		delete(b.subs, inspectionID)
+	}
+	close(ch)
+}
+
+// Publish sends e to the subscribers of e.InspectionID without blocking.
+// Subscribers whose buffer is full miss the event.
+func (b *Broker) Publish(e Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs[e.InspectionID] {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// Close closes every subscriber's channel, ending their streams, and makes
+// later subscriptions return a closed channel. Call it on shutdown so open
+// streams don't hold up draining HTTP requests.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for id, chans := range b.subs {
+		for ch := range chans {
+			close(ch)
+		}
+		delete(b.subs, id)
+	}
+}
@@ -0,0 +1,70 @@
+package jobevents
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestBroker_DeliversToInspectionSubscribers(t *testing.T) {
+	b := NewBroker()
+	inspectionID := uuid.New()
+	events, unsubscribe := b.Subscribe(inspectionID)
+	defer unsubscribe()
+	other, unsubscribeOther := b.Subscribe(uuid.New())
+	defer unsubscribeOther()
+
+	b.Publish(Event{InspectionID: inspectionID, JobType: "analyze_inspection", State: StateCompleted})
+
+	select {
+	case e := <-events:
+		if e.State != StateCompleted || !e.State.Done() {
+			t.Errorf("expected a completed event, got %+v", e)
+		}
+	default:
+		t.Fatal("expected the subscriber to receive the event")
+	}
+	select {
+	case e := <-other:
+		t.Errorf("expected another inspection's subscriber to receive nothing, got %+v", e)
+	default:
+	}
+}
+
+func TestBroker_SlowSubscriberDoesNotBlock(t *testing.T) {
+	b := NewBroker()
+	inspectionID := uuid.New()
+	events, unsubscribe := b.Subscribe(inspectionID)
+	defer unsubscribe()
+
+	for i := 0; i < subscriberBuffer*2; i++ {
+		b.Publish(Event{InspectionID: inspectionID, State: StateProgress})
+	}
+	if len(events) != subscriberBuffer {
+		t.Errorf("expected %d buffered events, got %d", subscriberBuffer, len(events))
+	}
+}
+
+func TestBroker_UnsubscribeAndClose(t *testing.T) {
+	b := NewBroker()
+	inspectionID := uuid.New()
+
+	events, unsubscribe := b.Subscribe(inspectionID)
+	unsubscribe()
+	unsubscribe() // Idempotent
+	if _, ok := <-events; ok {
+		t.Error("expected the channel to be closed after unsubscribing")
+	}
+	b.Publish(Event{InspectionID: inspectionID, State: StateRunning}) // No subscribers left
+
+	events, unsubscribe = b.Subscribe(inspectionID)
+	b.Close()
+	unsubscribe() // Safe after Close
+	if _, ok := <-events; ok {
+		t.Error("expected Close to close the channel")
+	}
+	late, _ := b.Subscribe(inspectionID)
+	if _, ok := <-late; ok {
+		t.Error("expected subscriptions after Close to be closed")
+	}
+}
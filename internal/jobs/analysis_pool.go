@@ -0,0 +1,109 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/google/uuid"
+)
+
+// defaultAnalysisConcurrency limits concurrent AI API calls to avoid rate limiting
+// when no concurrency is configured.
+const defaultAnalysisConcurrency = 3
+
+// imageAnalysisResult records the outcome of analyzing a single image.
+type imageAnalysisResult struct {
+	ImageID uuid.UUID
+	Err     error // nil on success
+	Skipped bool  // True if the image was never started because ctx was canceled
+}
+
+// imageAnalysisSummary aggregates per-image results for an inspection job.
+type imageAnalysisSummary struct {
+	Results   []imageAnalysisResult // One entry per image, in input order
+	Succeeded int
+	Failed    int
+	Skipped   int
+}
+
+// analyzeImagesConcurrently runs analyze for each image using a bounded pool of
+// at most concurrency workers. A failing image is recorded in its result and
+// does not stop the others. Once ctx is canceled, images that have not started
+// are skipped; images already in flight are left to observe ctx themselves.
+func analyzeImagesConcurrently(
+	ctx context.Context,
+	images []repository.Image,
+	concurrency int,
+	analyze func(ctx context.Context, img repository.Image) error,
+) imageAnalysisSummary {
+	if concurrency < 1 {
+		concurrency = defaultAnalysisConcurrency
+	}
+	if concurrency > len(images) {
+		concurrency = len(images)
+	}
+
+	results := make([]imageAnalysisResult, len(images))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = imageAnalysisResult{
+					ImageID: images[i].ID,
+					Err:     analyze(ctx, images[i]),
+				}
+			}
+		}()
+	}
+
+	// Feed work until every image is dispatched or ctx is canceled
+	dispatched := 0
+feed:
+	for ; dispatched < len(images); dispatched++ {
+		select {
+		case <-ctx.Done():
+			break feed
+		case indexes <- dispatched:
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	for i := dispatched; i < len(images); i++ {
+		results[i] = imageAnalysisResult{ImageID: images[i].ID, Err: ctx.Err(), Skipped: true}
+	}
+
+	summary := imageAnalysisSummary{Results: results}
+	for _, r := range results {
+		switch {
+		case r.Skipped:
+			summary.Skipped++
+		case r.Err != nil:
+			summary.Failed++
+		default:
+			summary.Succeeded++
+		}
+	}
+	return summary
+}
+
+// filterImagesByID returns the images whose IDs are in ids, preserving order.
+func filterImagesByID(images []repository.Image, ids []uuid.UUID) []repository.Image {
+	wanted := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+
+	filtered := make([]repository.Image, 0, len(ids))
+	for _, img := range images {
+		if wanted[img.ID] {
+			filtered = append(filtered, img)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,171 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/google/uuid"
+)
+
+// fakeAnalyzer simulates per-image AI latency and tracks peak concurrency.
+type fakeAnalyzer struct {
+	latency time.Duration
+	failIDs map[uuid.UUID]bool
+
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+	calls    atomic.Int32
+}
+
+func (f *fakeAnalyzer) analyze(ctx context.Context, img repository.Image) error {
+	f.calls.Add(1)
+	f.mu.Lock()
+	f.inFlight++
+	if f.inFlight > f.peak {
+		f.peak = f.inFlight
+	}
+	f.mu.Unlock()
+
+	defer func() {
+		f.mu.Lock()
+		f.inFlight--
+		f.mu.Unlock()
+	}()
+
+	select {
+	case <-time.After(f.latency):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if f.failIDs[img.ID] {
+		return errors.New("simulated AI failure")
+	}
+	return nil
+}
+
+func testImages(n int) []repository.Image {
+	images := make([]repository.Image, n)
+	for i := range images {
+		images[i] = repository.Image{ID: uuid.New()}
+	}
+	return images
+}
+
+func TestAnalyzeImagesConcurrently_PartialFailure(t *testing.T) {
+	images := testImages(8)
+	analyzer := &fakeAnalyzer{
+		latency: 20 * time.Millisecond,
+		failIDs: map[uuid.UUID]bool{images[3].ID: true},
+	}
+
+	summary := analyzeImagesConcurrently(context.Background(), images, 3, analyzer.analyze)
+
+	if summary.Succeeded != 7 || summary.Failed != 1 || summary.Skipped != 0 {
+		t.Errorf("expected 7 succeeded, 1 failed, 0 skipped; got %+v", summary)
+	}
+	if int(analyzer.calls.Load()) != len(images) {
+		t.Errorf("expected every image to be analyzed, got %d calls", analyzer.calls.Load())
+	}
+	for i, r := range summary.Results {
+		if r.ImageID != images[i].ID {
+			t.Fatalf("expected results in input order, result %d has image %s", i, r.ImageID)
+		}
+		if (r.Err != nil) != (i == 3) {
+			t.Errorf("unexpected error state for image %d: %v", i, r.Err)
+		}
+	}
+}
+
+func TestAnalyzeImagesConcurrently_BoundsConcurrency(t *testing.T) {
+	images := testImages(10)
+	analyzer := &fakeAnalyzer{latency: 20 * time.Millisecond}
+
+	start := time.Now()
+	analyzeImagesConcurrently(context.Background(), images, 3, analyzer.analyze)
+	elapsed := time.Since(start)
+
+	if analyzer.peak > 3 {
+		t.Errorf("expected at most 3 concurrent analyses, got %d", analyzer.peak)
+	}
+	if analyzer.peak < 2 {
+		t.Errorf("expected images to be analyzed in parallel, peak concurrency was %d", analyzer.peak)
+	}
+	// Sequential processing would take 10 * 20ms
+	if elapsed >= 200*time.Millisecond {
+		t.Errorf("expected parallel analysis to finish faster than sequential, took %v", elapsed)
+	}
+}
+
+func TestAnalyzeImagesConcurrently_DefaultConcurrency(t *testing.T) {
+	images := testImages(6)
+	analyzer := &fakeAnalyzer{latency: 10 * time.Millisecond}
+
+	summary := analyzeImagesConcurrently(context.Background(), images, 0, analyzer.analyze)
+
+	if summary.Succeeded != 6 {
+		t.Errorf("expected 6 succeeded, got %d", summary.Succeeded)
+	}
+	if analyzer.peak > defaultAnalysisConcurrency {
+		t.Errorf("expected at most %d concurrent analyses, got %d", defaultAnalysisConcurrency, analyzer.peak)
+	}
+}
+
+func TestAnalyzeImagesConcurrently_RespectsCancellation(t *testing.T) {
+	images := testImages(10)
+	analyzer := &fakeAnalyzer{latency: time.Second}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(30*time.Millisecond, cancel)
+
+	start := time.Now()
+	summary := analyzeImagesConcurrently(ctx, images, 2, analyzer.analyze)
+	elapsed := time.Since(start)
+
+	if elapsed >= time.Second {
+		t.Errorf("expected cancellation to stop analysis promptly, took %v", elapsed)
+	}
+	if summary.Succeeded != 0 {
+		t.Errorf("expected no successes after cancellation, got %d", summary.Succeeded)
+	}
+	if summary.Skipped == 0 {
+		t.Error("expected images not yet started to be skipped")
+	}
+	if summary.Failed+summary.Skipped != len(images) {
+		t.Errorf("expected every image to be accounted for, got %+v", summary)
+	}
+	for _, r := range summary.Results {
+		if !errors.Is(r.Err, context.Canceled) {
+			t.Errorf("expected context.Canceled for image %s, got %v", r.ImageID, r.Err)
+		}
+	}
+}
+
+func TestAnalyzeImagesConcurrently_NoImages(t *testing.T) {
+	analyzer := &fakeAnalyzer{}
+
+	summary := analyzeImagesConcurrently(context.Background(), nil, 3, analyzer.analyze)
+
+	if len(summary.Results) != 0 || analyzer.calls.Load() != 0 {
+		t.Errorf("expected no work for empty image list, got %+v", summary)
+	}
+}
+
+func TestFilterImagesByID(t *testing.T) {
+	images := testImages(4)
+
+	filtered := filterImagesByID(images, []uuid.UUID{images[2].ID, images[0].ID, uuid.New()})
+
+	if len(filtered) != 2 {
+		t.Fatalf("expected 2 images, got %d", len(filtered))
+	}
+	if filtered[0].ID != images[0].ID || filtered[1].ID != images[2].ID {
+		t.Errorf("expected images in original order, got %v and %v", filtered[0].ID, filtered[1].ID)
+	}
+}
@@ -8,53 +8,79 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"slices"
+	"strconv"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/DukeRupert/lukaut/internal/ai"
 	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/jobevents"
 	"github.com/DukeRupert/lukaut/internal/metrics"
 	"github.com/DukeRupert/lukaut/internal/repository"
 	"github.com/DukeRupert/lukaut/internal/service"
 	"github.com/DukeRupert/lukaut/internal/storage"
+	"github.com/DukeRupert/lukaut/internal/webhook"
 	"github.com/DukeRupert/lukaut/internal/worker"
 	"github.com/google/uuid"
 	"github.com/sqlc-dev/pqtype"
 )
 
-// maxConcurrentAnalysis limits concurrent AI API calls to avoid rate limiting
-const maxConcurrentAnalysis = 3
-
 // AnalyzeInspectionHandler processes jobs that analyze inspection images for violations.
 // It sends images to the AI service and creates violation records based on the results.
 type AnalyzeInspectionHandler struct {
 	queries           *repository.Queries
+	uow               repository.UnitOfWork // Stores each image's results in one transaction
 	aiProvider        ai.AIProvider
 	storage           storage.Storage
 	inspectionService service.InspectionService
 	violationService  service.ViolationService
+	events            service.InspectionEventService
+	webhookService    service.WebhookService // Optional: notifies the user's webhooks on completion
+	concurrency       int                    // Maximum images analyzed in parallel per job
+	notifier          worker.Notifier        // Optional: told as each image finishes
 	logger            *slog.Logger
 }
 
 // NewAnalyzeInspectionHandler creates a new handler for inspection analysis jobs.
+// concurrency bounds the number of images analyzed in parallel; values below 1
+// use the default. webhookService may be nil, in which case no webhooks are sent.
 func NewAnalyzeInspectionHandler(
 	queries *repository.Queries,
+	uow repository.UnitOfWork,
 	aiProvider ai.AIProvider,
 	storage storage.Storage,
 	inspectionService service.InspectionService,
 	violationService service.ViolationService,
+	webhookService service.WebhookService,
+	concurrency int,
 	logger *slog.Logger,
 ) *AnalyzeInspectionHandler {
+	if concurrency < 1 {
+		concurrency = defaultAnalysisConcurrency
+	}
 	return &AnalyzeInspectionHandler{
 		queries:           queries,
+		uow:               uow,
 		aiProvider:        aiProvider,
 		storage:           storage,
 		inspectionService: inspectionService,
 		violationService:  violationService,
+		events:            service.NewInspectionEventService(queries, logger),
+		webhookService:    webhookService,
+		concurrency:       concurrency,
 		logger:            logger,
 	}
 }
 
+// WithNotifier sets the notifier told each time an image finishes analysis,
+// so live progress can be shown before the job completes.
+func (h *AnalyzeInspectionHandler) WithNotifier(n worker.Notifier) *AnalyzeInspectionHandler {
+	h.notifier = n
+	return h
+}
+
 // Type returns the job type identifier.
 func (h *AnalyzeInspectionHandler) Type() string {
 	return worker.JobTypeAnalyzeInspection
@@ -62,7 +88,8 @@ func (h *AnalyzeInspectionHandler) Type() string {
 
 // Handle executes the inspection analysis job.
 // It processes all pending images for an inspection, analyzes them with AI,
-// and creates violation records with linked regulations.
+// and creates violation records with linked regulations. Images that were
+// already analyzed are skipped unless the payload forces a reanalysis.
 func (h *AnalyzeInspectionHandler) Handle(ctx context.Context, payload []byte) error {
 	// Unmarshal the payload
 	var p worker.AnalyzeInspectionPayload
@@ -70,129 +97,266 @@ func (h *AnalyzeInspectionHandler) Handle(ctx context.Context, payload []byte) e
 		return worker.NewPermanentError(fmt.Errorf("invalid payload: %w", err))
 	}
 
-	h.logger.Info("Analyzing inspection",
+	h.logger.InfoContext(ctx, "Analyzing inspection",
 		"inspection_id", p.InspectionID,
 		"user_id", p.UserID,
 	)
 
+	// Jobs limited to specific images (e.g. retrying a failed image) leave the
+	// inspection status alone, since it may already be in review
+	imagesOnly := len(p.ImageIDs) > 0
+
 	// 1. Transition inspection to analyzing status via service
 	// StartAnalysis validates ownership, checks status, and is idempotent for retries
-	if err := h.inspectionService.StartAnalysis(ctx, p.InspectionID, p.UserID); err != nil {
-		code := domain.ErrorCode(err)
-		if code == domain.ENOTFOUND || code == domain.EINVALID {
-			return worker.NewPermanentError(fmt.Errorf("start analysis: %w", err))
+	if !imagesOnly {
+		if err := h.inspectionService.StartAnalysis(ctx, p.InspectionID, p.UserID); err != nil {
+			code := domain.ErrorCode(err)
+			if code == domain.ENOTFOUND || code == domain.EINVALID {
+				return worker.NewPermanentError(fmt.Errorf("start analysis: %w", err))
+			}
+			return fmt.Errorf("start analysis: %w", err)
 		}
-		return fmt.Errorf("start analysis: %w", err)
 	}
 
-	// 3. Fetch pending images (with authorization check for defense in depth)
-	images, err := h.queries.ListPendingImagesByInspectionIDAndUserID(ctx, repository.ListPendingImagesByInspectionIDAndUserIDParams{
-		ID:     p.InspectionID,
-		UserID: p.UserID,
-	})
+	// 3. Fetch images to analyze (with authorization check for defense in depth)
+	images, err := h.listImages(ctx, p)
 	if err != nil {
-		return fmt.Errorf("fetch pending images: %w", err)
+		return err
+	}
+	if imagesOnly {
+		images = filterImagesByID(images, p.ImageIDs)
 	}
 
-	h.logger.Info("Found pending images", "inspection_id", p.InspectionID, "count", len(images))
+	h.logger.InfoContext(ctx, "Found images to analyze", "inspection_id", p.InspectionID, "count", len(images), "force", p.Force)
+	h.events.Record(ctx, p.InspectionID, domain.InspectionEventAnalysisStarted, map[string]string{
+		"images": strconv.Itoa(len(images)),
+	})
 
-	// 4. Process images in parallel with limited concurrency
-	var successCount, failCount atomic.Int32
-	sem := make(chan struct{}, maxConcurrentAnalysis) // Semaphore to limit concurrent API calls
-	var wg sync.WaitGroup
+	// 4. Process images in parallel with a bounded worker pool
+	startTime := time.Now()
+	usage := &analysisRunUsage{}
+	progress := &imageProgress{total: len(images)}
+	summary := analyzeImagesConcurrently(ctx, images, h.concurrency, func(ctx context.Context, img repository.Image) error {
+		return h.processImage(ctx, img, p.InspectionID, p.UserID, usage, progress)
+	})
 
-	for _, img := range images {
-		wg.Add(1)
-		sem <- struct{}{} // Acquire semaphore slot
+	// Record what the run cost, including runs that were interrupted, since
+	// the images already analyzed were billed
+	h.recordAnalysisRun(context.WithoutCancel(ctx), p.InspectionID, p.UserID, usage, time.Since(startTime))
+	worker.SetResult(ctx, analyzeInspectionResult(p.InspectionID, summary, usage))
+
+	// Stop here if the job was canceled (e.g. shutdown or timeout). Images that
+	// were not finished are back to pending, so a retry picks them up.
+	if err := ctx.Err(); err != nil {
+		h.logger.WarnContext(ctx, "Inspection analysis interrupted",
+			"inspection_id", p.InspectionID,
+			"total_images", len(images),
+			"success", summary.Succeeded,
+			"failed", summary.Failed,
+			"skipped", summary.Skipped,
+		)
+		return fmt.Errorf("analysis interrupted: %w", err)
+	}
 
-		go func(img repository.Image) {
-			defer wg.Done()
-			defer func() { <-sem }() // Release semaphore slot
+	// 5. Transition inspection to review status via service
+	if !imagesOnly {
+		if err := h.inspectionService.CompleteAnalysis(ctx, p.InspectionID, p.UserID); err != nil {
+			return fmt.Errorf("complete analysis: %w", err)
+		}
+	}
 
-			imgLogger := h.logger.With("image_id", img.ID, "inspection_id", p.InspectionID)
-			imgLogger.Info("Processing image", "storage_key", img.StorageKey)
+	h.logger.InfoContext(ctx, "Inspection analysis completed",
+		"inspection_id", p.InspectionID,
+		"total_images", len(images),
+		"success", summary.Succeeded,
+		"failed", summary.Failed,
+	)
+	h.events.Record(ctx, p.InspectionID, domain.InspectionEventAnalysisCompleted, map[string]string{
+		"analyzed":   strconv.Itoa(summary.Succeeded),
+		"failed":     strconv.Itoa(summary.Failed),
+		"violations": strconv.Itoa(usage.violationsFound()),
+	})
 
-			// Mark image as analyzing (with authorization check)
-			if err := h.queries.UpdateImageAnalysisStatusWithAuth(ctx, repository.UpdateImageAnalysisStatusWithAuthParams{
-				ID:                  img.ID,
-				UserID:              p.UserID,
-				AnalysisStatus:      sql.NullString{String: domain.ImageAnalysisStatusAnalyzing.String(), Valid: true},
-				AnalysisCompletedAt: sql.NullTime{},
-			}); err != nil {
-				imgLogger.Error("Failed to mark image as analyzing", "error", err)
-				failCount.Add(1)
-				return
-			}
+	// 6. Notify the user's webhooks (optional - don't fail the job)
+	if h.webhookService != nil {
+		if err := h.webhookService.Notify(ctx, p.UserID, webhook.EventAnalysisCompleted, webhook.AnalysisCompletedData{
+			InspectionID:   p.InspectionID,
+			ImagesAnalyzed: summary.Succeeded,
+			ImagesFailed:   summary.Failed,
+		}); err != nil {
+			h.logger.ErrorContext(ctx, "Failed to queue analysis webhooks", "error", err, "inspection_id", p.InspectionID)
+		}
+	}
 
-			// Analyze the image
-			if err := h.analyzeImage(ctx, img, p.InspectionID, p.UserID, imgLogger); err != nil {
-				imgLogger.Error("Image analysis failed", "error", err)
-				failCount.Add(1)
-				metrics.ImagesAnalyzed.WithLabelValues("error").Inc()
+	return nil
+}
 
-				// Mark image as failed (with authorization check)
-				if markErr := h.markImageFailed(ctx, img.ID, p.UserID); markErr != nil {
-					imgLogger.Error("Failed to mark image as failed", "error", markErr)
-				}
-				return
-			}
+// maxResultErrors caps the distinct failure reasons in an analysis job's
+// result.
+const maxResultErrors = 5
+
+// analyzeInspectionResult summarizes an analysis run for its job row. The
+// failure reasons are those stored on the failed images.
+func analyzeInspectionResult(inspectionID uuid.UUID, summary imageAnalysisSummary, usage *analysisRunUsage) worker.AnalyzeInspectionResult {
+	result := worker.AnalyzeInspectionResult{
+		InspectionID:    inspectionID,
+		ImagesAnalyzed:  summary.Succeeded,
+		ImagesFailed:    summary.Failed,
+		ViolationsFound: usage.violationsFound(),
+	}
+	for _, r := range summary.Results {
+		if r.Err == nil || r.Skipped || len(result.Errors) == maxResultErrors {
+			continue
+		}
+		if msg := imageAnalysisErrorMessage(r.Err); !slices.Contains(result.Errors, msg) {
+			result.Errors = append(result.Errors, msg)
+		}
+	}
+	return result
+}
 
-			// Mark image as completed (with authorization check)
-			if err := h.markImageCompleted(ctx, img.ID, p.UserID); err != nil {
-				imgLogger.Error("Failed to mark image as completed", "error", err)
-				// Don't fail - image was analyzed successfully
-			}
+// listImages returns the inspection's pending images, or with Force all of its
+// images so that ones already analyzed are analyzed again.
+func (h *AnalyzeInspectionHandler) listImages(ctx context.Context, p worker.AnalyzeInspectionPayload) ([]repository.Image, error) {
+	if p.Force {
+		images, err := h.queries.ListImagesByInspectionIDAndUserID(ctx, repository.ListImagesByInspectionIDAndUserIDParams{
+			ID:     p.InspectionID,
+			UserID: p.UserID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("fetch images: %w", err)
+		}
+		return images, nil
+	}
 
-			successCount.Add(1)
-			metrics.ImagesAnalyzed.WithLabelValues("success").Inc()
-			imgLogger.Info("Image analysis completed successfully")
-		}(img)
+	images, err := h.queries.ListPendingImagesByInspectionIDAndUserID(ctx, repository.ListPendingImagesByInspectionIDAndUserIDParams{
+		ID:     p.InspectionID,
+		UserID: p.UserID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetch pending images: %w", err)
 	}
+	return images, nil
+}
 
-	// Wait for all image analyses to complete
-	wg.Wait()
+// processImage analyzes a single image and records its outcome on the image row.
+// Failures are recorded per image and returned for aggregation; they never
+// abort the other images in the job.
+func (h *AnalyzeInspectionHandler) processImage(ctx context.Context, img repository.Image, inspectionID, userID uuid.UUID, usage *analysisRunUsage, progress *imageProgress) error {
+	imgLogger := h.logger.With("image_id", img.ID, "inspection_id", inspectionID)
+	imgLogger.Info("Processing image", "storage_key", img.StorageKey)
 
-	// 5. Transition inspection to review status via service
-	if err := h.inspectionService.CompleteAnalysis(ctx, p.InspectionID, p.UserID); err != nil {
-		return fmt.Errorf("complete analysis: %w", err)
+	// Mark image as analyzing (with authorization check)
+	if err := h.queries.UpdateImageAnalysisStatusWithAuth(ctx, repository.UpdateImageAnalysisStatusWithAuthParams{
+		ID:                  img.ID,
+		UserID:              userID,
+		AnalysisStatus:      sql.NullString{String: domain.ImageAnalysisStatusAnalyzing.String(), Valid: true},
+		AnalysisCompletedAt: sql.NullTime{},
+	}); err != nil {
+		imgLogger.Error("Failed to mark image as analyzing", "error", err)
+		return fmt.Errorf("mark image analyzing: %w", err)
 	}
 
-	h.logger.Info("Inspection analysis completed",
-		"inspection_id", p.InspectionID,
-		"total_images", len(images),
-		"success", successCount.Load(),
-		"failed", failCount.Load(),
-	)
+	// Analyze the image
+	violations, err := h.analyzeImage(ctx, img, inspectionID, userID, usage, imgLogger)
+	if err != nil {
+		// Canceled mid-analysis: return the image to pending so a retry
+		// re-analyzes it. The job context is done, so update without it.
+		if ctx.Err() != nil {
+			imgLogger.Warn("Image analysis canceled", "error", err)
+			if resetErr := h.markImagePending(context.WithoutCancel(ctx), img.ID, userID); resetErr != nil {
+				imgLogger.Error("Failed to reset image to pending", "error", resetErr)
+			}
+			return err
+		}
 
+		imgLogger.Error("Image analysis failed", "error", err)
+		metrics.ImagesAnalyzed.WithLabelValues("error").Inc()
+
+		// Mark image as failed with the reason (with authorization check)
+		if markErr := h.markImageFailed(ctx, img.ID, userID, err); markErr != nil {
+			imgLogger.Error("Failed to mark image as failed", "error", markErr)
+		}
+		h.events.Record(ctx, inspectionID, domain.InspectionEventImageFailed, map[string]string{
+			"filename": img.OriginalFilename.String,
+		})
+		h.notifyProgress(inspectionID, img.ID, true, progress)
+		return err
+	}
+
+	metrics.ImagesAnalyzed.WithLabelValues("success").Inc()
+	h.events.Record(ctx, inspectionID, domain.InspectionEventImageAnalyzed, map[string]string{
+		"filename":   img.OriginalFilename.String,
+		"violations": strconv.Itoa(violations),
+	})
+	h.notifyProgress(inspectionID, img.ID, false, progress)
+	imgLogger.Info("Image analysis completed successfully")
 	return nil
 }
 
-// analyzeImage downloads and analyzes a single image, creating violation records.
+// imageProgress counts the images of a job that finished analysis. Images
+// are analyzed concurrently, so the count is atomic.
+type imageProgress struct {
+	total    int
+	finished atomic.Int64
+}
+
+// notifyProgress tells the notifier, if any, that one of the inspection's
+// images finished analysis, successfully or not, and how many of the job's
+// images have.
+func (h *AnalyzeInspectionHandler) notifyProgress(inspectionID, imageID uuid.UUID, failed bool, progress *imageProgress) {
+	finished := int(progress.finished.Add(1))
+	if h.notifier == nil {
+		return
+	}
+	h.notifier.Publish(jobevents.Event{
+		InspectionID: inspectionID,
+		JobType:      worker.JobTypeAnalyzeInspection,
+		State:        jobevents.StateProgress,
+		ImageID:      imageID,
+		ImageFailed:  failed,
+		Finished:     finished,
+		Total:        progress.total,
+	})
+}
+
+// analyzeImage downloads and analyzes a single image, creating violation
+// records and marking the image completed, and returns the number of
+// potential violations found. Violations already stored for the image by an
+// earlier analysis are updated instead of created again.
 func (h *AnalyzeInspectionHandler) analyzeImage(
 	ctx context.Context,
 	img repository.Image,
 	inspectionID uuid.UUID,
 	userID uuid.UUID,
+	usage *analysisRunUsage,
 	logger *slog.Logger,
-) error {
+) (int, error) {
 	// Download image from storage
 	reader, objInfo, err := h.storage.Get(ctx, img.StorageKey)
 	if err != nil {
-		return fmt.Errorf("download image from storage: %w", err)
+		return 0, fmt.Errorf("download image from storage: %w", err)
 	}
 	defer func() { _ = reader.Close() }()
 
 	// Read image data into memory
 	imageData, err := io.ReadAll(reader)
 	if err != nil {
-		return fmt.Errorf("read image data: %w", err)
+		return 0, fmt.Errorf("read image data: %w", err)
 	}
 
-	logger.Info("Downloaded image from storage",
+	logger.InfoContext(ctx, "Downloaded image from storage",
 		"size_bytes", len(imageData),
 		"content_type", objInfo.ContentType,
 	)
 
+	// Load violations from earlier analyses before paying for a new one
+	existing, err := h.queries.ListAIViolationMatchesByImageID(ctx, uuid.NullUUID{UUID: img.ID, Valid: true})
+	if err != nil {
+		return 0, fmt.Errorf("list existing violations: %w", err)
+	}
+	matcher := newViolationMatcher(existing)
+
 	// Call AI provider to analyze the image
 	analysisResult, err := h.aiProvider.AnalyzeImage(ctx, ai.AnalyzeImageParams{
 		ImageData:    imageData,
@@ -206,65 +370,170 @@ func (h *AnalyzeInspectionHandler) analyzeImage(
 		// Check if this is a retryable AI error
 		if ai.IsRetryable(err) {
 			// Retryable errors like rate limits should propagate up
-			return fmt.Errorf("ai analysis (retryable): %w", err)
+			return 0, fmt.Errorf("ai analysis (retryable): %w", err)
 		}
 		// Invalid image or content policy violations are permanent
 		if errors.Is(err, ai.ErrAIInvalidImage) || errors.Is(err, ai.ErrAIContentPolicy) {
-			return worker.NewPermanentError(fmt.Errorf("ai analysis (permanent): %w", err))
+			return 0, worker.NewPermanentError(fmt.Errorf("ai analysis (permanent): %w", err))
 		}
 		// Other AI errors
-		return fmt.Errorf("ai analysis: %w", err)
+		return 0, fmt.Errorf("ai analysis: %w", err)
 	}
 
-	logger.Info("AI analysis completed",
+	usage.add(analysisResult.Usage, len(analysisResult.Violations))
+
+	logger.InfoContext(ctx, "AI analysis completed",
 		"violations_found", len(analysisResult.Violations),
 		"input_tokens", analysisResult.Usage.InputTokens,
 		"output_tokens", analysisResult.Usage.OutputTokens,
 		"cost_cents", analysisResult.Usage.CostCents,
 	)
 
-	// Store each violation
-	for i, violation := range analysisResult.Violations {
-		if err := h.storeViolation(ctx, violation, img.ID, inspectionID, i+1, logger); err != nil {
-			// Log but don't fail the whole image analysis
-			logger.Error("Failed to store violation", "error", err, "violation_index", i)
+	// Store each violation, updating ones found by an earlier analysis, and
+	// mark the image completed in one transaction. A failure part way leaves
+	// nothing stored, so the image fails and a retry starts clean.
+	var created []storedViolation
+	err = h.uow.Do(ctx, func(q *repository.Queries) error {
+		created = created[:0]
+		for i, violation := range analysisResult.Violations {
+			if existingID, ok := matcher.match(violation); ok {
+				if err := h.updateViolation(ctx, q, violation, existingID, img.ID, logger); err != nil {
+					return err
+				}
+				continue
+			}
+			violationID, err := h.storeViolation(ctx, q, violation, img.ID, inspectionID, i+1, logger)
+			if err != nil {
+				return err
+			}
+			created = append(created, storedViolation{id: violationID, finding: violation})
+		}
+		if err := q.MarkImageAnalysisCompletedWithAuth(ctx, repository.MarkImageAnalysisCompletedWithAuthParams{
+			ID:     img.ID,
+			UserID: userID,
+		}); err != nil {
+			return fmt.Errorf("mark image completed: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("store analysis results: %w", err)
+	}
+	metrics.ViolationsDetected.Add(float64(len(created)))
+
+	// Link regulations once the violations are stored. Links are search
+	// results the inspector reviews, so a failure leaves them to be added by
+	// hand rather than failing the image.
+	for _, v := range created {
+		if err := h.violationService.LinkRegulations(ctx, v.id, v.finding.SuggestedRegulations, v.finding.Description, v.finding.Category); err != nil {
+			logger.ErrorContext(ctx, "Failed to link regulations", "error", err, "violation_id", v.id)
 		}
 	}
 
-	return nil
+	return len(analysisResult.Violations), nil
+}
+
+// storedViolation is a violation created from an AI finding.
+type storedViolation struct {
+	id      uuid.UUID
+	finding ai.PotentialViolation
+}
+
+// analysisRunUsage accumulates the AI usage of the images analyzed in one job
+// run, and the potential violations they turned up. Images are analyzed
+// concurrently, so updates are locked.
+type analysisRunUsage struct {
+	mu           sync.Mutex
+	provider     string
+	model        string
+	images       int
+	inputTokens  int
+	outputTokens int
+	costCents    int
+	violations   int
 }
 
-// storeViolation creates a violation record and links it to relevant regulations.
+// add records the usage of one analyzed image and the violations found in it.
+func (u *analysisRunUsage) add(usage ai.UsageInfo, violations int) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.provider == "" {
+		u.provider, u.model = usage.Provider, usage.Model
+	}
+	u.images++
+	u.inputTokens += usage.InputTokens
+	u.outputTokens += usage.OutputTokens
+	u.costCents += usage.CostCents
+	u.violations += violations
+}
+
+// violationsFound returns the potential violations found so far in the run.
+func (u *analysisRunUsage) violationsFound() int {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.violations
+}
+
+// recordAnalysisRun stores the run's usage and cost. Runs in which the AI
+// provider analyzed no images cost nothing and are not recorded. Failures are
+// logged; the analysis itself has already succeeded or failed on its own.
+func (h *AnalyzeInspectionHandler) recordAnalysisRun(
+	ctx context.Context,
+	inspectionID uuid.UUID,
+	userID uuid.UUID,
+	usage *analysisRunUsage,
+	duration time.Duration,
+) {
+	usage.mu.Lock()
+	defer usage.mu.Unlock()
+	if usage.images == 0 {
+		return
+	}
+
+	if _, err := h.queries.CreateAnalysisRun(ctx, repository.CreateAnalysisRunParams{
+		InspectionID:     uuid.NullUUID{UUID: inspectionID, Valid: true},
+		UserID:           userID,
+		Provider:         usage.provider,
+		Model:            usage.model,
+		ImagesAnalyzed:   int32(usage.images),
+		PromptTokens:     int32(usage.inputTokens),
+		CompletionTokens: int32(usage.outputTokens),
+		CostCents:        int32(usage.costCents),
+		DurationMs:       int32(duration.Milliseconds()),
+	}); err != nil {
+		h.logger.ErrorContext(ctx, "Failed to record analysis run", "error", err, "inspection_id", inspectionID)
+		return
+	}
+
+	h.logger.InfoContext(ctx, "Analysis run recorded",
+		"inspection_id", inspectionID,
+		"images_analyzed", usage.images,
+		"cost_cents", usage.costCents,
+	)
+}
+
+// storeViolation creates a violation record, with an annotation for its
+// region, and returns its ID.
 func (h *AnalyzeInspectionHandler) storeViolation(
 	ctx context.Context,
+	q *repository.Queries,
 	violation ai.PotentialViolation,
 	imageID uuid.UUID,
 	inspectionID uuid.UUID,
 	sortOrder int,
 	logger *slog.Logger,
-) error {
-	// Convert bounding box to JSON if present
-	var boundingBoxJSON pqtype.NullRawMessage
-	if violation.BoundingBox != nil {
-		data, err := json.Marshal(violation.BoundingBox)
-		if err != nil {
-			return fmt.Errorf("marshal bounding box: %w", err)
-		}
-		boundingBoxJSON = pqtype.NullRawMessage{
-			RawMessage: data,
-			Valid:      true,
-		}
+) (uuid.UUID, error) {
+	boundingBoxJSON, err := boundingBoxToJSON(violation.BoundingBox)
+	if err != nil {
+		return uuid.Nil, err
 	}
 
 	// Create the violation record
-	createdViolation, err := h.queries.CreateViolation(ctx, repository.CreateViolationParams{
-		InspectionID: inspectionID,
-		ImageID:      uuid.NullUUID{UUID: imageID, Valid: true},
-		Description:  violation.Description,
-		AiDescription: sql.NullString{
-			String: violation.Description + " (Location: " + violation.Location + ")",
-			Valid:  true,
-		},
+	createdViolation, err := q.CreateViolation(ctx, repository.CreateViolationParams{
+		InspectionID:  inspectionID,
+		ImageID:       uuid.NullUUID{UUID: imageID, Valid: true},
+		Description:   violation.Description,
+		AiDescription: aiViolationDescription(violation),
 		Confidence: sql.NullString{
 			String: string(violation.Confidence),
 			Valid:  true,
@@ -280,44 +549,183 @@ func (h *AnalyzeInspectionHandler) storeViolation(
 			Int32: int32(sortOrder),
 			Valid: true,
 		},
+		// Kept unchanged when the inspector overrides severity during review
+		AiSeverity: sql.NullString{
+			String: string(violation.Severity),
+			Valid:  true,
+		},
 	})
 	if err != nil {
-		return fmt.Errorf("create violation: %w", err)
+		return uuid.Nil, fmt.Errorf("create violation: %w", err)
 	}
 
-	logger.Info("Created violation record",
+	logger.InfoContext(ctx, "Created violation record",
 		"violation_id", createdViolation.ID,
 		"description", violation.Description,
 		"confidence", violation.Confidence,
 		"severity", violation.Severity,
 	)
-	metrics.ViolationsDetected.Inc()
 
-	// Link regulations to the violation
-	if err := h.violationService.LinkRegulations(ctx, createdViolation.ID, violation.SuggestedRegulations, violation.Description, violation.Category); err != nil {
-		// Log but don't fail - violation was created successfully
-		logger.Error("Failed to link regulations", "error", err, "violation_id", createdViolation.ID)
+	// Store the region as an annotation so review can draw it over the photo
+	if violation.BoundingBox != nil {
+		if err := h.storeAnnotation(ctx, q, violation, createdViolation.ID, imageID, logger); err != nil {
+			return uuid.Nil, err
+		}
+	}
+
+	return createdViolation.ID, nil
+}
+
+// updateViolation refreshes a stored violation with a new finding for it.
+// The inspector's review is kept: the description and severity only change
+// while the violation is pending. The AI's annotation is replaced; regulation
+// links are kept, since the violation was matched by its regulation.
+func (h *AnalyzeInspectionHandler) updateViolation(
+	ctx context.Context,
+	q *repository.Queries,
+	violation ai.PotentialViolation,
+	violationID uuid.UUID,
+	imageID uuid.UUID,
+	logger *slog.Logger,
+) error {
+	boundingBoxJSON, err := boundingBoxToJSON(violation.BoundingBox)
+	if err != nil {
+		return err
+	}
+
+	if err := q.UpdateViolationFromReanalysis(ctx, repository.UpdateViolationFromReanalysisParams{
+		ID:            violationID,
+		AiDescription: aiViolationDescription(violation),
+		Confidence:    sql.NullString{String: string(violation.Confidence), Valid: true},
+		BoundingBox:   boundingBoxJSON,
+		AiSeverity:    sql.NullString{String: string(violation.Severity), Valid: true},
+		Description:   violation.Description,
+	}); err != nil {
+		return fmt.Errorf("update violation: %w", err)
+	}
+
+	logger.InfoContext(ctx, "Updated violation from reanalysis",
+		"violation_id", violationID,
+		"description", violation.Description,
+		"confidence", violation.Confidence,
+		"severity", violation.Severity,
+	)
+
+	if err := q.DeleteImageAnnotationsByViolationIDAndSource(ctx, repository.DeleteImageAnnotationsByViolationIDAndSourceParams{
+		ViolationID: violationID,
+		Source:      string(domain.AnnotationSourceAI),
+	}); err != nil {
+		return fmt.Errorf("remove previous AI annotation: %w", err)
+	}
+	if violation.BoundingBox != nil {
+		return h.storeAnnotation(ctx, q, violation, violationID, imageID, logger)
 	}
 
 	return nil
 }
 
-// markImageFailed updates an image's analysis status to failed (with authorization check).
-func (h *AnalyzeInspectionHandler) markImageFailed(ctx context.Context, imageID, userID uuid.UUID) error {
-	return h.queries.UpdateImageAnalysisStatusWithAuth(ctx, repository.UpdateImageAnalysisStatusWithAuthParams{
-		ID:                  imageID,
-		UserID:              userID,
-		AnalysisStatus:      sql.NullString{String: domain.ImageAnalysisStatusFailed.String(), Valid: true},
-		AnalysisCompletedAt: sql.NullTime{},
+// aiViolationDescription is the AI's own description of a violation, kept
+// alongside the editable description.
+func aiViolationDescription(violation ai.PotentialViolation) sql.NullString {
+	return sql.NullString{
+		String: violation.Description + " (Location: " + violation.Location + ")",
+		Valid:  true,
+	}
+}
+
+// boundingBoxToJSON converts a bounding box to JSON, or null if there is none.
+func boundingBoxToJSON(box *ai.BoundingBox) (pqtype.NullRawMessage, error) {
+	if box == nil {
+		return pqtype.NullRawMessage{}, nil
+	}
+	data, err := json.Marshal(box)
+	if err != nil {
+		return pqtype.NullRawMessage{}, fmt.Errorf("marshal bounding box: %w", err)
+	}
+	return pqtype.NullRawMessage{RawMessage: data, Valid: true}, nil
+}
+
+// storeAnnotation records the region the AI reported for a violation.
+// Regions outside the image are skipped; the violation is kept either way.
+func (h *AnalyzeInspectionHandler) storeAnnotation(
+	ctx context.Context,
+	q *repository.Queries,
+	violation ai.PotentialViolation,
+	violationID uuid.UUID,
+	imageID uuid.UUID,
+	logger *slog.Logger,
+) error {
+	box, err := domain.AnnotationBox{
+		X:      violation.BoundingBox.X,
+		Y:      violation.BoundingBox.Y,
+		Width:  violation.BoundingBox.Width,
+		Height: violation.BoundingBox.Height,
+	}.Normalize()
+	if err != nil {
+		logger.WarnContext(ctx, "Skipping invalid AI bounding box", "error", err, "violation_id", violationID)
+		return nil
+	}
+
+	label := []rune(violation.Location)
+	if len(label) > domain.MaxAnnotationLabelLength {
+		label = label[:domain.MaxAnnotationLabelLength]
+	}
+
+	if _, err := q.CreateImageAnnotation(ctx, repository.CreateImageAnnotationParams{
+		ViolationID: violationID,
+		ImageID:     imageID,
+		X:           box.X,
+		Y:           box.Y,
+		Width:       box.Width,
+		Height:      box.Height,
+		Label:       string(label),
+		Source:      string(domain.AnnotationSourceAI),
+	}); err != nil {
+		return fmt.Errorf("store AI annotation: %w", err)
+	}
+	return nil
+}
+
+// markImageFailed updates an image's analysis status to failed and records
+// why (with authorization check).
+func (h *AnalyzeInspectionHandler) markImageFailed(ctx context.Context, imageID, userID uuid.UUID, analysisErr error) error {
+	return h.queries.MarkImageAnalysisFailedWithAuth(ctx, repository.MarkImageAnalysisFailedWithAuthParams{
+		ID:            imageID,
+		UserID:        userID,
+		AnalysisError: sql.NullString{String: imageAnalysisErrorMessage(analysisErr), Valid: true},
 	})
 }
 
-// markImageCompleted updates an image's analysis status to completed (with authorization check).
-func (h *AnalyzeInspectionHandler) markImageCompleted(ctx context.Context, imageID, userID uuid.UUID) error {
+// markImagePending returns an image to the pending state (with authorization check).
+func (h *AnalyzeInspectionHandler) markImagePending(ctx context.Context, imageID, userID uuid.UUID) error {
 	return h.queries.UpdateImageAnalysisStatusWithAuth(ctx, repository.UpdateImageAnalysisStatusWithAuthParams{
 		ID:                  imageID,
 		UserID:              userID,
-		AnalysisStatus:      sql.NullString{String: domain.ImageAnalysisStatusCompleted.String(), Valid: true},
+		AnalysisStatus:      sql.NullString{String: domain.ImageAnalysisStatusPending.String(), Valid: true},
 		AnalysisCompletedAt: sql.NullTime{},
 	})
 }
+
+// maxImageAnalysisErrorLength caps the failure reason stored on an image.
+const maxImageAnalysisErrorLength = 500
+
+// imageAnalysisErrorMessage describes why an image's analysis failed in terms
+// the inspector can act on. Unrecognized errors are kept as-is, truncated.
+func imageAnalysisErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, ai.ErrAIInvalidImage):
+		return "The photo could not be read. It may be corrupt or in an unsupported format."
+	case errors.Is(err, ai.ErrAIContentPolicy):
+		return "The photo was declined by the AI provider's content policy."
+	case errors.Is(err, ai.ErrAITimeout), errors.Is(err, context.DeadlineExceeded):
+		return "The AI provider timed out. Retry to try again."
+	case errors.Is(err, ai.ErrAIRateLimit), errors.Is(err, ai.ErrAIUnavailable):
+		return "The AI provider was unavailable. Retry in a few minutes."
+	}
+
+	msg := []rune(err.Error())
+	if len(msg) > maxImageAnalysisErrorLength {
+		msg = append(msg[:maxImageAnalysisErrorLength-1], '…')
+	}
+	return string(msg)
+}
@@ -0,0 +1,700 @@
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/ai"
+	"github.com/DukeRupert/lukaut/internal/ai/mock"
+	"github.com/DukeRupert/lukaut/internal/jobevents"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/DukeRupert/lukaut/internal/service"
+	"github.com/DukeRupert/lukaut/internal/storage"
+	"github.com/DukeRupert/lukaut/internal/testutil/fakedb"
+	"github.com/DukeRupert/lukaut/internal/worker"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// In-Memory Analysis Database
+// =============================================================================
+
+// fakeAnalysisRun is an analysis_runs row written by the job.
+type fakeAnalysisRun struct {
+	inspectionID     string
+	userID           string
+	provider         string
+	model            string
+	imagesAnalyzed   int64
+	promptTokens     int64
+	completionTokens int64
+	costCents        int64
+}
+
+// fakeViolation is a violations row, with the standard number of its primary
+// regulation.
+type fakeViolation struct {
+	id          uuid.UUID
+	imageID     string
+	description string
+	regulation  string
+	updates     int
+}
+
+// fakeAnalysisDB holds the images of one inspection. It answers the sqlc
+// queries used by the analysis job and records the analysis runs and
+// violations it writes. Images are analyzed concurrently, so each
+// transaction undoes only its own writes on rollback.
+type fakeAnalysisDB struct {
+	mu           sync.Mutex
+	inspectionID uuid.UUID
+	userID       uuid.UUID
+	imageIDs     []uuid.UUID
+	imageStatus  map[string]string
+	imageErrors  map[string]string
+	runs         []fakeAnalysisRun
+	violations   []*fakeViolation
+	annotations  int
+	events       []fakeInspectionEvent
+	failExec     string // Exec that fails, by query name
+}
+
+// fakeInspectionEvent is an inspection_events row written by the job.
+type fakeInspectionEvent struct {
+	kind string
+	data map[string]string
+}
+
+// imageRows returns the inspection's images, only the pending ones if pendingOnly.
+func (f *fakeAnalysisDB) imageRows(pendingOnly bool) *fakedb.Rows {
+	rows := &fakedb.Rows{Columns: 15}
+	for i, id := range f.imageIDs {
+		status := f.imageStatus[id.String()]
+		if pendingOnly && status != "pending" {
+			continue
+		}
+		rows.Values = append(rows.Values, []driver.Value{
+			id.String(), f.inspectionID.String(), "images/" + id.String() + ".jpg", nil, nil,
+			"image/jpeg", int64(4), nil, nil, status, nil, time.Now(), nil, int64(i + 1), nil,
+		})
+	}
+	return rows
+}
+
+// onRollback registers fn to revert a write if q's transaction rolls back.
+// The violation service stub reads violations outside queries, so fn runs
+// locked.
+func (f *fakeAnalysisDB) onRollback(q fakedb.Query, fn func()) {
+	q.OnRollback(func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		fn()
+	})
+}
+
+// setImageStatus sets an image's status and error, reverting both if q's
+// transaction rolls back.
+func (f *fakeAnalysisDB) setImageStatus(q fakedb.Query, id, status string, analysisErr *string) {
+	oldStatus := f.imageStatus[id]
+	oldErr, hadErr := f.imageErrors[id]
+	f.onRollback(q, func() {
+		f.imageStatus[id] = oldStatus
+		if hadErr {
+			f.imageErrors[id] = oldErr
+		} else {
+			delete(f.imageErrors, id)
+		}
+	})
+	f.imageStatus[id] = status
+	if analysisErr != nil {
+		f.imageErrors[id] = *analysisErr
+	} else if status == "completed" {
+		delete(f.imageErrors, id)
+	}
+}
+
+func (f *fakeAnalysisDB) Query(q fakedb.Query) (*fakedb.Rows, error) {
+	args := q.Args
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	name := q.Name
+	switch name {
+	case "ListPendingImagesByInspectionIDAndUserID":
+		return f.imageRows(true), nil
+	case "ListImagesByInspectionIDAndUserID":
+		return f.imageRows(false), nil
+	case "ListAIViolationMatchesByImageID":
+		rows := &fakedb.Rows{Columns: 3}
+		for _, v := range f.violations {
+			if v.imageID != args[0].Value.(string) {
+				continue
+			}
+			var regulation driver.Value
+			if v.regulation != "" {
+				regulation = v.regulation
+			}
+			rows.Values = append(rows.Values, []driver.Value{v.id.String(), v.description, regulation})
+		}
+		return rows, nil
+	case "CreateViolation":
+		v := &fakeViolation{
+			id:          uuid.New(),
+			imageID:     args[1].Value.(string),
+			description: args[2].Value.(string),
+		}
+		f.violations = append(f.violations, v)
+		f.onRollback(q, func() {
+			f.violations = slices.DeleteFunc(f.violations, func(other *fakeViolation) bool { return other == v })
+		})
+		values := []driver.Value{
+			v.id.String(), args[0].Value, args[1].Value, args[2].Value, args[3].Value, args[4].Value,
+			args[5].Value, args[6].Value, args[7].Value, args[8].Value, args[9].Value, time.Now(), time.Now(), args[10].Value,
+		}
+		return &fakedb.Rows{Columns: len(values), Values: [][]driver.Value{values}}, nil
+	case "CreateImageAnnotation":
+		f.annotations++
+		f.onRollback(q, func() { f.annotations-- })
+		values := []driver.Value{
+			uuid.NewString(), args[0].Value, args[1].Value, args[2].Value, args[3].Value,
+			args[4].Value, args[5].Value, args[6].Value, args[7].Value, time.Now(),
+		}
+		return &fakedb.Rows{Columns: len(values), Values: [][]driver.Value{values}}, nil
+	case "CreateAnalysisRun":
+		run := fakeAnalysisRun{
+			inspectionID:     args[0].Value.(string),
+			userID:           args[1].Value.(string),
+			provider:         args[2].Value.(string),
+			model:            args[3].Value.(string),
+			imagesAnalyzed:   args[4].Value.(int64),
+			promptTokens:     args[5].Value.(int64),
+			completionTokens: args[6].Value.(int64),
+			costCents:        args[7].Value.(int64),
+		}
+		f.runs = append(f.runs, run)
+		values := []driver.Value{
+			uuid.NewString(), run.inspectionID, run.userID, run.provider, run.model,
+			run.imagesAnalyzed, run.promptTokens, run.completionTokens, run.costCents, args[8].Value, time.Now(),
+		}
+		return &fakedb.Rows{Columns: len(values), Values: [][]driver.Value{values}}, nil
+	}
+	return nil, fmt.Errorf("fakeAnalysisDB: unexpected query %q", name)
+}
+
+func (f *fakeAnalysisDB) Exec(q fakedb.Query) (int64, error) {
+	args := q.Args
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	name := q.Name
+	if name == f.failExec {
+		return 0, fmt.Errorf("fakeAnalysisDB: %s failed", name)
+	}
+	switch name {
+	case "UpdateImageAnalysisStatusWithAuth":
+		f.setImageStatus(q, args[0].Value.(string), args[2].Value.(string), nil)
+	case "MarkImageAnalysisFailedWithAuth":
+		analysisErr := args[2].Value.(string)
+		f.setImageStatus(q, args[0].Value.(string), "failed", &analysisErr)
+	case "MarkImageAnalysisCompletedWithAuth":
+		f.setImageStatus(q, args[0].Value.(string), "completed", nil)
+	case "UpdateViolationFromReanalysis":
+		for _, v := range f.violations {
+			if v.id.String() == args[0].Value.(string) {
+				v.updates++
+				f.onRollback(q, func() { v.updates-- })
+			}
+		}
+	case "DeleteImageAnnotationsByViolationIDAndSource":
+	case "CreateInspectionEvent":
+		event := fakeInspectionEvent{kind: args[1].Value.(string)}
+		if raw, ok := args[2].Value.([]byte); ok {
+			_ = json.Unmarshal(raw, &event.data)
+		}
+		f.events = append(f.events, event)
+	default:
+		return 0, fmt.Errorf("fakeAnalysisDB: unexpected exec %q", name)
+	}
+	return 1, nil
+}
+
+// stubLinkingViolationService links each violation to its first suggested
+// regulation, as its primary regulation.
+type stubLinkingViolationService struct {
+	service.ViolationService
+	db *fakeAnalysisDB
+}
+
+func (s stubLinkingViolationService) LinkRegulations(_ context.Context, violationID uuid.UUID, suggested []string, _, _ string) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	for _, v := range s.db.violations {
+		if v.id == violationID && len(suggested) > 0 {
+			v.regulation = suggested[0]
+		}
+	}
+	return nil
+}
+
+// stubImageStorage serves the same small JPEG for every key.
+type stubImageStorage struct{}
+
+func (stubImageStorage) Put(context.Context, string, io.Reader, storage.PutOptions) error { return nil }
+func (stubImageStorage) Delete(context.Context, string) error                             { return nil }
+func (stubImageStorage) Exists(context.Context, string) (bool, error)                     { return true, nil }
+
+func (stubImageStorage) Get(_ context.Context, key string) (io.ReadCloser, storage.ObjectInfo, error) {
+	return io.NopCloser(bytes.NewReader([]byte{0xFF, 0xD8, 0xFF, 0xD9})), storage.ObjectInfo{Key: key, ContentType: "image/jpeg"}, nil
+}
+
+func (stubImageStorage) URL(context.Context, string, time.Duration) (string, error) {
+	return "", nil
+}
+
+// stubAnalysisInspectionService accepts every status transition.
+type stubAnalysisInspectionService struct {
+	service.InspectionService
+}
+
+func (stubAnalysisInspectionService) StartAnalysis(context.Context, uuid.UUID, uuid.UUID) error {
+	return nil
+}
+
+func (stubAnalysisInspectionService) CompleteAnalysis(context.Context, uuid.UUID, uuid.UUID) error {
+	return nil
+}
+
+func newAnalysisTestDB(images int) *fakeAnalysisDB {
+	f := &fakeAnalysisDB{
+		inspectionID: uuid.New(),
+		userID:       uuid.New(),
+		imageStatus:  make(map[string]string),
+		imageErrors:  make(map[string]string),
+	}
+	for i := 0; i < images; i++ {
+		id := uuid.New()
+		f.imageIDs = append(f.imageIDs, id)
+		f.imageStatus[id.String()] = "pending"
+	}
+	return f
+}
+
+func runAnalysisJob(t *testing.T, f *fakeAnalysisDB, provider ai.AIProvider, force bool) error {
+	t.Helper()
+	return runAnalysisJobContext(t, context.Background(), f, provider, force)
+}
+
+func runAnalysisJobContext(t *testing.T, ctx context.Context, f *fakeAnalysisDB, provider ai.AIProvider, force bool) error {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	db := fakedb.Open(f)
+	t.Cleanup(func() { _ = db.Close() })
+
+	queries := repository.New(db)
+	h := NewAnalyzeInspectionHandler(queries, repository.NewUnitOfWork(queries), provider, stubImageStorage{},
+		stubAnalysisInspectionService{}, stubLinkingViolationService{db: f}, nil, 2, logger)
+	payload, err := json.Marshal(worker.AnalyzeInspectionPayload{
+		InspectionID: f.inspectionID,
+		UserID:       f.userID,
+		Force:        force,
+	})
+	if err != nil {
+		t.Fatalf("marshal payload: %v", err)
+	}
+	return h.Handle(ctx, payload)
+}
+
+// =============================================================================
+// Analysis Run Cost Tests
+// =============================================================================
+
+func TestAnalyzeInspection_RecordsOneRunWithSummedUsage(t *testing.T) {
+	f := newAnalysisTestDB(3)
+	provider := mock.New(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	provider.AnalyzeImageResponse = &ai.AnalysisResult{
+		Usage: ai.UsageInfo{
+			Provider:     mock.ProviderName,
+			Model:        "mock-ai-v1",
+			InputTokens:  1250,
+			OutputTokens: 850,
+			CostCents:    5,
+		},
+	}
+
+	if err := runAnalysisJob(t, f, provider, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(f.runs) != 1 {
+		t.Fatalf("expected one analysis run, got %d", len(f.runs))
+	}
+	want := fakeAnalysisRun{
+		inspectionID:     f.inspectionID.String(),
+		userID:           f.userID.String(),
+		provider:         "mock",
+		model:            "mock-ai-v1",
+		imagesAnalyzed:   3,
+		promptTokens:     3 * 1250,
+		completionTokens: 3 * 850,
+		costCents:        3 * 5,
+	}
+	if f.runs[0] != want {
+		t.Errorf("expected run %+v, got %+v", want, f.runs[0])
+	}
+}
+
+func TestAnalyzeInspection_FailedImagesAreNotBilled(t *testing.T) {
+	f := newAnalysisTestDB(2)
+	provider := mock.New(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	provider.AnalyzeImageError = ai.ErrAIInvalidImage
+
+	if err := runAnalysisJob(t, f, provider, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(f.runs) != 0 {
+		t.Errorf("expected no analysis run when the provider analyzed nothing, got %+v", f.runs)
+	}
+}
+
+// =============================================================================
+// Activity Feed Tests
+// =============================================================================
+
+func TestAnalyzeInspection_RecordsActivityFeedEvents(t *testing.T) {
+	f := newAnalysisTestDB(2)
+	provider := reanalysisProvider()
+
+	if err := runAnalysisJob(t, f, provider, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(f.events) != 4 {
+		t.Fatalf("expected 4 events, got %+v", f.events)
+	}
+	if first := f.events[0]; first.kind != "analysis_started" || first.data["images"] != "2" {
+		t.Errorf("expected analysis_started for 2 images first, got %+v", first)
+	}
+	for _, event := range f.events[1:3] {
+		if event.kind != "image_analyzed" || event.data["violations"] != "2" {
+			t.Errorf("expected image_analyzed with 2 violations, got %+v", event)
+		}
+	}
+	last := f.events[3]
+	if last.kind != "analysis_completed" || last.data["analyzed"] != "2" || last.data["failed"] != "0" || last.data["violations"] != "4" {
+		t.Errorf("expected analysis_completed with totals last, got %+v", last)
+	}
+}
+
+func TestAnalyzeInspection_RecordsFailedImageEvents(t *testing.T) {
+	f := newAnalysisTestDB(1)
+	provider := mock.New(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	provider.AnalyzeImageError = ai.ErrAIInvalidImage
+
+	if err := runAnalysisJob(t, f, provider, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	kinds := make([]string, 0, len(f.events))
+	for _, event := range f.events {
+		kinds = append(kinds, event.kind)
+	}
+	if got := strings.Join(kinds, ","); got != "analysis_started,image_failed,analysis_completed" {
+		t.Errorf("unexpected events: %s", got)
+	}
+	if failed := f.events[len(f.events)-1].data["failed"]; failed != "1" {
+		t.Errorf("expected 1 failed image in the summary, got %q", failed)
+	}
+}
+
+// recordingNotifier records the job events the analysis publishes. Images
+// are analyzed concurrently, so it locks.
+type recordingNotifier struct {
+	mu     sync.Mutex
+	events []jobevents.Event
+}
+
+func (n *recordingNotifier) Publish(e jobevents.Event) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.events = append(n.events, e)
+}
+
+func TestAnalyzeInspection_PublishesProgressPerImage(t *testing.T) {
+	f := newAnalysisTestDB(3)
+	provider := mock.New(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	provider.AnalyzeImageError = ai.ErrAIInvalidImage
+	notifier := &recordingNotifier{}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	db := fakedb.Open(f)
+	t.Cleanup(func() { _ = db.Close() })
+	queries := repository.New(db)
+	h := NewAnalyzeInspectionHandler(queries, repository.NewUnitOfWork(queries), provider, stubImageStorage{},
+		stubAnalysisInspectionService{}, stubLinkingViolationService{db: f}, nil, 2, logger).WithNotifier(notifier)
+	payload, _ := json.Marshal(worker.AnalyzeInspectionPayload{InspectionID: f.inspectionID, UserID: f.userID})
+	if err := h.Handle(context.Background(), payload); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(notifier.events) != 3 {
+		t.Fatalf("expected one progress event per image, got %+v", notifier.events)
+	}
+	images := make(map[uuid.UUID]bool)
+	for i, e := range notifier.events {
+		if e.InspectionID != f.inspectionID || e.JobType != worker.JobTypeAnalyzeInspection || e.State != jobevents.StateProgress {
+			t.Errorf("unexpected event %+v", e)
+		}
+		if e.Finished != i+1 || e.Total != 3 || !e.ImageFailed {
+			t.Errorf("expected failed image %d of 3, got %+v", i+1, e)
+		}
+		images[e.ImageID] = true
+	}
+	for _, id := range f.imageIDs {
+		if !images[id] {
+			t.Errorf("expected a progress event for image %s", id)
+		}
+	}
+}
+
+// =============================================================================
+// Job Result Tests
+// =============================================================================
+
+func TestAnalyzeInspection_SetsJobResult(t *testing.T) {
+	f := newAnalysisTestDB(2)
+	ctx, result := worker.ContextWithResult(context.Background())
+
+	if err := runAnalysisJobContext(t, ctx, f, reanalysisProvider(), false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, ok := result().(worker.AnalyzeInspectionResult)
+	if !ok {
+		t.Fatalf("expected an AnalyzeInspectionResult, got %#v", result())
+	}
+	want := worker.AnalyzeInspectionResult{
+		InspectionID:    f.inspectionID,
+		ImagesAnalyzed:  2,
+		ViolationsFound: 4,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected result %+v, got %+v", want, got)
+	}
+}
+
+func TestAnalyzeInspection_JobResultListsDistinctErrors(t *testing.T) {
+	f := newAnalysisTestDB(2)
+	provider := mock.New(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	provider.AnalyzeImageError = ai.ErrAIInvalidImage
+	ctx, result := worker.ContextWithResult(context.Background())
+
+	if err := runAnalysisJobContext(t, ctx, f, provider, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, ok := result().(worker.AnalyzeInspectionResult)
+	if !ok {
+		t.Fatalf("expected an AnalyzeInspectionResult, got %#v", result())
+	}
+	if got.ImagesFailed != 2 || got.ImagesAnalyzed != 0 {
+		t.Errorf("expected 2 failed and 0 analyzed images, got %+v", got)
+	}
+	want := []string{imageAnalysisErrorMessage(ai.ErrAIInvalidImage)}
+	if !reflect.DeepEqual(got.Errors, want) {
+		t.Errorf("expected errors %q, got %q", want, got.Errors)
+	}
+}
+
+// =============================================================================
+// Reanalysis Tests
+// =============================================================================
+
+// reanalysisProvider reports the same two violations for every image.
+func reanalysisProvider() *mock.Provider {
+	provider := mock.New(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	provider.AnalyzeImageResponse = &ai.AnalysisResult{
+		Violations: []ai.PotentialViolation{
+			{
+				Description:          "Worker on roof edge without fall protection",
+				Confidence:           ai.ConfidenceHigh,
+				Severity:             ai.SeveritySerious,
+				SuggestedRegulations: []string{"1926.501(b)(13)"},
+			},
+			{
+				Description:          "Ladder not extended 3 feet above landing",
+				Confidence:           ai.ConfidenceMedium,
+				Severity:             ai.SeverityOther,
+				SuggestedRegulations: []string{"1926.1053(b)(1)"},
+			},
+		},
+		Usage: ai.UsageInfo{Provider: mock.ProviderName, Model: "mock-ai-v1"},
+	}
+	return provider
+}
+
+func TestAnalyzeInspection_SecondRunSkipsAnalyzedImages(t *testing.T) {
+	f := newAnalysisTestDB(2)
+	provider := reanalysisProvider()
+
+	for run := 1; run <= 2; run++ {
+		if err := runAnalysisJob(t, f, provider, false); err != nil {
+			t.Fatalf("run %d: unexpected error: %v", run, err)
+		}
+	}
+
+	if len(f.violations) != 4 {
+		t.Errorf("expected 4 violations after two runs, got %d", len(f.violations))
+	}
+	if provider.AnalyzeImageCalls != 2 {
+		t.Errorf("expected analyzed images to be skipped on the second run, got %d AI calls", provider.AnalyzeImageCalls)
+	}
+}
+
+func TestAnalyzeInspection_ForceUpdatesMatchedViolations(t *testing.T) {
+	f := newAnalysisTestDB(2)
+	provider := reanalysisProvider()
+
+	if err := runAnalysisJob(t, f, provider, false); err != nil {
+		t.Fatalf("first run: unexpected error: %v", err)
+	}
+	if err := runAnalysisJob(t, f, provider, true); err != nil {
+		t.Fatalf("forced run: unexpected error: %v", err)
+	}
+
+	if provider.AnalyzeImageCalls != 4 {
+		t.Errorf("expected force to reanalyze both images, got %d AI calls", provider.AnalyzeImageCalls)
+	}
+	if len(f.violations) != 4 {
+		t.Fatalf("expected reanalysis not to duplicate violations, got %d", len(f.violations))
+	}
+	for _, v := range f.violations {
+		if v.updates != 1 {
+			t.Errorf("expected violation %q to be updated once, got %d", v.description, v.updates)
+		}
+	}
+}
+
+func TestAnalyzeInspection_ForceRecreatesRemovedViolations(t *testing.T) {
+	f := newAnalysisTestDB(1)
+	provider := reanalysisProvider()
+
+	if err := runAnalysisJob(t, f, provider, false); err != nil {
+		t.Fatalf("first run: unexpected error: %v", err)
+	}
+	// The inspector deletes the ladder violation
+	removed := f.violations[1].description
+	f.violations = f.violations[:1]
+
+	if err := runAnalysisJob(t, f, provider, true); err != nil {
+		t.Fatalf("forced run: unexpected error: %v", err)
+	}
+
+	if len(f.violations) != 2 {
+		t.Fatalf("expected 2 violations after reanalysis, got %d", len(f.violations))
+	}
+	if f.violations[0].updates != 1 {
+		t.Errorf("expected the kept violation to be updated, got %d updates", f.violations[0].updates)
+	}
+	if f.violations[1].description != removed || f.violations[1].updates != 0 {
+		t.Errorf("expected the removed violation to be created again, got %+v", f.violations[1])
+	}
+}
+
+// =============================================================================
+// Failed Image Tests
+// =============================================================================
+
+func TestAnalyzeInspection_FailedImageRecordsErrorUntilRetrySucceeds(t *testing.T) {
+	f := newAnalysisTestDB(1)
+	image := f.imageIDs[0].String()
+	provider := mock.New(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	provider.AnalyzeImageError = ai.ErrAIInvalidImage
+
+	if err := runAnalysisJob(t, f, provider, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f.imageStatus[image] != "failed" {
+		t.Fatalf("expected image to be failed, got %q", f.imageStatus[image])
+	}
+	if want := imageAnalysisErrorMessage(ai.ErrAIInvalidImage); f.imageErrors[image] != want {
+		t.Errorf("expected error %q, got %q", want, f.imageErrors[image])
+	}
+
+	// Retrying the image resets it to pending and analyzes it again
+	f.imageStatus[image] = "pending"
+	provider.AnalyzeImageError = nil
+	if err := runAnalysisJob(t, f, provider, false); err != nil {
+		t.Fatalf("unexpected error on retry: %v", err)
+	}
+	if f.imageStatus[image] != "completed" {
+		t.Errorf("expected image to be completed after retry, got %q", f.imageStatus[image])
+	}
+	if msg, ok := f.imageErrors[image]; ok {
+		t.Errorf("expected error to be cleared after retry, got %q", msg)
+	}
+}
+
+func TestAnalyzeInspection_FailedStoreRollsBackViolations(t *testing.T) {
+	f := newAnalysisTestDB(1)
+	image := f.imageIDs[0].String()
+	provider := mock.New(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	// The violations and annotations are written before marking the image
+	// completed fails
+	f.failExec = "MarkImageAnalysisCompletedWithAuth"
+	if err := runAnalysisJob(t, f, provider, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(f.violations) != 0 || f.annotations != 0 {
+		t.Errorf("expected no violations or annotations stored, got %d and %d", len(f.violations), f.annotations)
+	}
+	if f.imageStatus[image] != "failed" {
+		t.Errorf("expected image to be failed, got %q", f.imageStatus[image])
+	}
+
+	// A retry stores the violations once
+	f.failExec = ""
+	f.imageStatus[image] = "pending"
+	if err := runAnalysisJob(t, f, provider, false); err != nil {
+		t.Fatalf("unexpected error on retry: %v", err)
+	}
+	if len(f.violations) == 0 || f.annotations != len(f.violations) {
+		t.Errorf("expected each violation stored once with its annotation, got %d violations and %d annotations", len(f.violations), f.annotations)
+	}
+	if f.imageStatus[image] != "completed" {
+		t.Errorf("expected image to be completed after retry, got %q", f.imageStatus[image])
+	}
+}
+
+func TestImageAnalysisErrorMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"timeout", fmt.Errorf("ai analysis (retryable): %w", ai.ErrAITimeout), "The AI provider timed out. Retry to try again."},
+		{"deadline", context.DeadlineExceeded, "The AI provider timed out. Retry to try again."},
+		{"other", fmt.Errorf("download image from storage: %w", io.ErrUnexpectedEOF), "download image from storage: unexpected EOF"},
+		{"long", fmt.Errorf("%s", strings.Repeat("x", 600)), strings.Repeat("x", maxImageAnalysisErrorLength-1) + "…"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := imageAnalysisErrorMessage(tt.err); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
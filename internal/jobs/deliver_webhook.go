@@ -0,0 +1,124 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/DukeRupert/lukaut/internal/webhook"
+	"github.com/DukeRupert/lukaut/internal/worker"
+	"github.com/google/uuid"
+)
+
+// DeliverWebhookHandler processes jobs that send a stored webhook delivery to
+// its endpoint. The client retries within a job; when every attempt fails the
+// job fails too and the worker retries it later with backoff. The outcome of
+// each job is recorded on the delivery so users can see it in settings.
+type DeliverWebhookHandler struct {
+	queries *repository.Queries
+	client  *webhook.Client
+	logger  *slog.Logger
+}
+
+// NewDeliverWebhookHandler creates a new handler for webhook delivery jobs.
+func NewDeliverWebhookHandler(queries *repository.Queries, client *webhook.Client, logger *slog.Logger) *DeliverWebhookHandler {
+	return &DeliverWebhookHandler{
+		queries: queries,
+		client:  client,
+		logger:  logger,
+	}
+}
+
+// Type returns the job type identifier.
+func (h *DeliverWebhookHandler) Type() string {
+	return worker.JobTypeDeliverWebhook
+}
+
+// Handle sends the delivery named in the job payload.
+func (h *DeliverWebhookHandler) Handle(ctx context.Context, payload []byte) error {
+	var p worker.DeliverWebhookPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return worker.NewPermanentError(fmt.Errorf("invalid payload: %w", err))
+	}
+
+	delivery, err := h.queries.GetWebhookDeliveryForSend(ctx, p.DeliveryID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// The endpoint was deleted along with its deliveries
+			h.logger.InfoContext(ctx, "Webhook delivery no longer exists, skipping", "delivery_id", p.DeliveryID)
+			return nil
+		}
+		return fmt.Errorf("get webhook delivery: %w", err)
+	}
+	if delivery.Status != string(domain.WebhookDeliveryPending) {
+		return nil
+	}
+
+	result, sendErr := h.client.Send(ctx, webhook.Request{
+		URL:        delivery.Url,
+		Secret:     delivery.Secret,
+		Event:      webhook.Event(delivery.Event),
+		DeliveryID: delivery.ID.String(),
+		Body:       delivery.Payload,
+	})
+
+	// Record the outcome even if the job was canceled mid-retry
+	if err := h.recordAttempt(context.WithoutCancel(ctx), delivery.ID, result, sendErr); err != nil {
+		h.logger.ErrorContext(ctx, "Failed to record webhook delivery attempt", "error", err, "delivery_id", delivery.ID)
+	}
+
+	if sendErr != nil {
+		h.logger.WarnContext(ctx, "Webhook delivery failed",
+			"delivery_id", delivery.ID,
+			"event", delivery.Event,
+			"attempts", result.Attempts,
+			"status_code", result.StatusCode,
+			"error", sendErr,
+		)
+		return fmt.Errorf("deliver webhook: %w", sendErr)
+	}
+
+	h.logger.InfoContext(ctx, "Webhook delivered",
+		"delivery_id", delivery.ID,
+		"event", delivery.Event,
+		"attempts", result.Attempts,
+		"status_code", result.StatusCode,
+	)
+	return nil
+}
+
+// OnPermanentFailure marks the delivery failed once the job is out of retries.
+func (h *DeliverWebhookHandler) OnPermanentFailure(ctx context.Context, payload []byte, jobErr error) {
+	var p worker.DeliverWebhookPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return
+	}
+	if err := h.queries.MarkWebhookDeliveryFailed(ctx, p.DeliveryID); err != nil {
+		h.logger.ErrorContext(ctx, "Failed to mark webhook delivery failed", "error", err, "delivery_id", p.DeliveryID)
+	}
+}
+
+// recordAttempt stores the result of a Send on the delivery.
+func (h *DeliverWebhookHandler) recordAttempt(ctx context.Context, deliveryID uuid.UUID, result webhook.Result, sendErr error) error {
+	params := repository.RecordWebhookDeliveryAttemptParams{
+		ID:       deliveryID,
+		Attempts: int32(result.Attempts),
+		Status:   string(domain.WebhookDeliveryDelivered),
+	}
+	if result.StatusCode != 0 {
+		params.ResponseStatus = sql.NullInt32{Int32: int32(result.StatusCode), Valid: true}
+	}
+	if sendErr != nil {
+		params.Status = string(domain.WebhookDeliveryPending)
+		params.ErrorMessage = domain.ToNullString(sendErr.Error())
+	} else {
+		params.DeliveredAt = sql.NullTime{Time: time.Now(), Valid: true}
+	}
+	return h.queries.RecordWebhookDeliveryAttempt(ctx, params)
+}
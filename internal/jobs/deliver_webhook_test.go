@@ -0,0 +1,116 @@
+package jobs
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/DukeRupert/lukaut/internal/testutil/fakedb"
+	"github.com/DukeRupert/lukaut/internal/webhook"
+	"github.com/DukeRupert/lukaut/internal/worker"
+	"github.com/google/uuid"
+)
+
+// fakeWebhookDB is a fake database holding one webhook delivery. It
+// records the attempts written by the delivery job.
+type fakeWebhookDB struct {
+	id       uuid.UUID
+	url      string
+	status   string
+	attempts []fakeWebhookAttempt
+}
+
+// fakeWebhookAttempt is a RecordWebhookDeliveryAttempt call.
+type fakeWebhookAttempt struct {
+	attempts       int64
+	status         string
+	responseStatus driver.Value
+	delivered      bool
+}
+
+func (f *fakeWebhookDB) Query(q fakedb.Query) (*fakedb.Rows, error) {
+	name := q.Name
+	if name != "GetWebhookDeliveryForSend" {
+		return nil, fmt.Errorf("fakeWebhookDB: unexpected query %q", name)
+	}
+	return &fakedb.Rows{Columns: 6, Values: [][]driver.Value{{
+		f.id.String(), string(webhook.EventReportReady), []byte(`{"id":"x"}`), f.status, f.url, "whsec_test",
+	}}}, nil
+}
+
+func (f *fakeWebhookDB) Exec(q fakedb.Query) (int64, error) {
+	args := q.Args
+	name := q.Name
+	if name != "RecordWebhookDeliveryAttempt" {
+		return 0, fmt.Errorf("fakeWebhookDB: unexpected exec %q", name)
+	}
+	f.attempts = append(f.attempts, fakeWebhookAttempt{
+		attempts:       args[1].Value.(int64),
+		status:         args[2].Value.(string),
+		responseStatus: args[3].Value,
+		delivered:      args[5].Value != nil,
+	})
+	return 1, nil
+}
+
+func runDeliverWebhook(t *testing.T, status int) (*fakeWebhookDB, error) {
+	t.Helper()
+
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get(webhook.HeaderSignature) == "" {
+			t.Error("request is not signed")
+		}
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	f := &fakeWebhookDB{id: uuid.New(), url: srv.URL, status: "pending"}
+	db := fakedb.Open(f)
+	defer func() { _ = db.Close() }()
+
+	client := webhook.NewClient(webhook.ClientConfig{MaxAttempts: 2, RetryBaseDelay: time.Millisecond})
+	h := NewDeliverWebhookHandler(repository.New(db), client, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	payload, _ := json.Marshal(worker.DeliverWebhookPayload{DeliveryID: f.id})
+	return f, h.Handle(context.Background(), payload)
+}
+
+func TestDeliverWebhook_RecordsSuccess(t *testing.T) {
+	f, err := runDeliverWebhook(t, http.StatusNoContent)
+	if err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+
+	if len(f.attempts) != 1 {
+		t.Fatalf("recorded %d attempts, want 1", len(f.attempts))
+	}
+	got := f.attempts[0]
+	if got.status != "delivered" || got.attempts != 1 || got.responseStatus != int64(http.StatusNoContent) || !got.delivered {
+		t.Errorf("recorded %+v, want delivered after 1 attempt with 204", got)
+	}
+}
+
+func TestDeliverWebhook_FailureStaysPendingForRetry(t *testing.T) {
+	f, err := runDeliverWebhook(t, http.StatusInternalServerError)
+	if err == nil {
+		t.Fatal("Handle succeeded, want an error so the worker retries")
+	}
+
+	if len(f.attempts) != 1 {
+		t.Fatalf("recorded %d attempts, want 1", len(f.attempts))
+	}
+	got := f.attempts[0]
+	if got.status != "pending" || got.attempts != 2 || got.responseStatus != int64(http.StatusInternalServerError) || got.delivered {
+		t.Errorf("recorded %+v, want pending after 2 attempts with 500", got)
+	}
+}
@@ -0,0 +1,59 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/service"
+	"github.com/DukeRupert/lukaut/internal/worker"
+)
+
+// FillInspectionWeatherHandler processes jobs that fill in a new
+// inspection's empty weather fields from its location and date.
+//
+// Missing weather data is not an error: the fields stay empty for the
+// inspector. Provider failures are returned so the worker retries with
+// backoff.
+type FillInspectionWeatherHandler struct {
+	weatherService service.WeatherService
+	logger         *slog.Logger
+}
+
+// NewFillInspectionWeatherHandler creates a new handler for weather auto-fill jobs.
+func NewFillInspectionWeatherHandler(weatherService service.WeatherService, logger *slog.Logger) *FillInspectionWeatherHandler {
+	return &FillInspectionWeatherHandler{
+		weatherService: weatherService,
+		logger:         logger,
+	}
+}
+
+// Type returns the job type identifier.
+func (h *FillInspectionWeatherHandler) Type() string {
+	return worker.JobTypeFillInspectionWeather
+}
+
+// Handle fills in the weather for the inspection named in the payload.
+func (h *FillInspectionWeatherHandler) Handle(ctx context.Context, payload []byte) error {
+	var p worker.FillInspectionWeatherPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return worker.NewPermanentError(fmt.Errorf("unmarshal payload: %w", err))
+	}
+
+	filled, err := h.weatherService.FillEmpty(ctx, p.InspectionID, p.UserID)
+	if err != nil {
+		if domain.ErrorCode(err) == domain.ENOTFOUND {
+			h.logger.InfoContext(ctx, "Inspection deleted before weather fill", "inspection_id", p.InspectionID)
+			return nil
+		}
+		return fmt.Errorf("fill inspection weather: %w", err)
+	}
+
+	h.logger.InfoContext(ctx, "Inspection weather fill complete",
+		"inspection_id", p.InspectionID,
+		"filled", filled,
+	)
+	return nil
+}
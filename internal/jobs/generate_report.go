@@ -8,48 +8,55 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
 
 	"github.com/DukeRupert/lukaut/internal/domain"
 	"github.com/DukeRupert/lukaut/internal/email"
 	"github.com/DukeRupert/lukaut/internal/metrics"
-	"github.com/DukeRupert/lukaut/internal/report"
 	"github.com/DukeRupert/lukaut/internal/repository"
 	"github.com/DukeRupert/lukaut/internal/service"
 	"github.com/DukeRupert/lukaut/internal/storage"
+	"github.com/DukeRupert/lukaut/internal/webhook"
 	"github.com/DukeRupert/lukaut/internal/worker"
 )
 
 // GenerateReportHandler processes jobs that generate PDF or DOCX reports.
 // It creates a formatted report from inspection data and uploads it to storage.
 type GenerateReportHandler struct {
-	queries       *repository.Queries
-	storage       storage.Storage
-	emailService  email.EmailService
-	reportService service.ReportService
-	pdfGen        report.Generator
-	docxGen       report.Generator
-	logger        *slog.Logger
-	baseURL       string
+	queries        *repository.Queries
+	storage        storage.Storage
+	emailQueue     email.Queue
+	reportService  service.ReportService
+	auditService   service.AuditService
+	events         service.InspectionEventService
+	webhookService service.WebhookService // Optional: notifies the user's webhooks when a report is ready
+	logger         *slog.Logger
+	baseURL        string
 }
 
 // NewGenerateReportHandler creates a new handler for report generation jobs.
+// webhookService may be nil, in which case no webhooks are sent. Reports are
+// rendered by reportService, which the inline preview shares.
 func NewGenerateReportHandler(
 	queries *repository.Queries,
 	storage storage.Storage,
-	emailService email.EmailService,
+	emailQueue email.Queue,
 	reportService service.ReportService,
+	auditService service.AuditService,
+	webhookService service.WebhookService,
 	logger *slog.Logger,
 	baseURL string,
 ) *GenerateReportHandler {
 	return &GenerateReportHandler{
-		queries:       queries,
-		storage:       storage,
-		emailService:  emailService,
-		reportService: reportService,
-		pdfGen:        report.NewHTMLPDFGenerator(logger),
-		docxGen:       report.NewHTMLDOCXGenerator(logger),
-		logger:        logger,
-		baseURL:       baseURL,
+		queries:        queries,
+		storage:        storage,
+		emailQueue:     emailQueue,
+		reportService:  reportService,
+		auditService:   auditService,
+		events:         service.NewInspectionEventService(queries, logger),
+		webhookService: webhookService,
+		logger:         logger,
+		baseURL:        baseURL,
 	}
 }
 
@@ -72,7 +79,7 @@ func (h *GenerateReportHandler) Handle(ctx context.Context, payload []byte) erro
 		return worker.NewPermanentError(fmt.Errorf("invalid format: %s (must be 'pdf' or 'docx')", p.Format))
 	}
 
-	h.logger.Info("Generating report",
+	h.logger.InfoContext(ctx, "Generating report",
 		"inspection_id", p.InspectionID,
 		"user_id", p.UserID,
 		"format", p.Format,
@@ -107,29 +114,21 @@ func (h *GenerateReportHandler) Handle(ctx context.Context, payload []byte) erro
 		return fmt.Errorf("aggregate report data: %w", err)
 	}
 
-	// 6. Select generator based on format
-	var gen report.Generator
-	if format == domain.ReportFormatPDF {
-		gen = h.pdfGen
-	} else {
-		gen = h.docxGen
-	}
-
-	// 7. Generate report to buffer
+	// 6. Generate report to buffer
 	var buf bytes.Buffer
-	bytesWritten, err := gen.Generate(ctx, reportData, &buf)
+	bytesWritten, err := h.reportService.Render(ctx, reportData, format, &buf)
 	if err != nil {
 		return fmt.Errorf("generate %s: %w", format, err)
 	}
 
-	h.logger.Info("Report generated",
+	h.logger.InfoContext(ctx, "Report generated",
 		"inspection_id", p.InspectionID,
 		"format", format,
 		"size_bytes", bytesWritten,
 		"violation_count", len(reportData.Violations),
 	)
 
-	// 8. Upload to storage
+	// 7. Upload to storage
 	storageKey := storage.ReportKey(p.InspectionID, p.Format)
 	err = h.storage.Put(ctx, storageKey, &buf, storage.PutOptions{
 		ContentType: format.ContentType(),
@@ -139,11 +138,13 @@ func (h *GenerateReportHandler) Handle(ctx context.Context, payload []byte) erro
 		return fmt.Errorf("upload report to storage: %w", err)
 	}
 
-	// 9. Create report record in database
+	// 8. Create report record in database
+	recipients := p.Recipients()
 	createParams := repository.CreateReportParams{
-		InspectionID:   p.InspectionID,
-		UserID:         p.UserID,
-		ViolationCount: int32(len(reportData.Violations)),
+		InspectionID:    p.InspectionID,
+		UserID:          p.UserID,
+		ViolationCount:  int32(len(reportData.Violations)),
+		RecipientEmails: append([]string{}, recipients...),
 	}
 	if format == domain.ReportFormatPDF {
 		createParams.PdfStorageKey = domain.ToNullString(storageKey)
@@ -156,55 +157,112 @@ func (h *GenerateReportHandler) Handle(ctx context.Context, payload []byte) erro
 		return fmt.Errorf("create report record: %w", err)
 	}
 	metrics.ReportsGenerated.WithLabelValues(p.Format).Inc()
+	worker.SetResult(ctx, worker.GenerateReportResult{
+		ReportID:       dbReport.ID,
+		InspectionID:   p.InspectionID,
+		Format:         p.Format,
+		ViolationCount: int(dbReport.ViolationCount),
+	})
+
+	// A successful run supersedes earlier failures (don't fail - report exists)
+	if err := h.queries.DeleteFailedReportsByInspectionID(ctx, repository.DeleteFailedReportsByInspectionIDParams{
+		InspectionID: p.InspectionID,
+		UserID:       p.UserID,
+	}); err != nil {
+		h.logger.ErrorContext(ctx, "Failed to clear failed report records",
+			"error", err,
+			"inspection_id", p.InspectionID,
+		)
+	}
+
+	// Record the generated report in the audit log (don't fail - report exists)
+	if err := h.auditService.Record(ctx, domain.AuditEvent{
+		ActorUserID:  p.UserID,
+		InspectionID: &p.InspectionID,
+		EntityType:   domain.AuditEntityReport,
+		EntityID:     dbReport.ID,
+		Action:       domain.AuditActionReportGenerated,
+		NewValues: map[string]string{
+			"format":          string(format),
+			"violation_count": fmt.Sprintf("%d", dbReport.ViolationCount),
+		},
+	}); err != nil {
+		h.logger.ErrorContext(ctx, "Failed to record report audit event",
+			"error", err,
+			"report_id", dbReport.ID,
+		)
+	}
+
+	h.events.Record(ctx, p.InspectionID, domain.InspectionEventReportGenerated, map[string]string{
+		"format": string(format),
+	})
 
-	// 10. Send email notification to inspector (optional - don't fail job if email fails)
+	// 9. Queue email notification to inspector when the report isn't being
+	// sent to anyone else; otherwise they get a blind copy of each recipient's
+	// email instead (optional - don't fail job if email fails)
 	reportURL := fmt.Sprintf("%s/reports/%s/download?format=%s", h.baseURL, dbReport.ID, p.Format)
-	if h.emailService != nil && reportData.InspectorEmail != "" {
-		if err := h.emailService.SendReportReadyEmail(
-			ctx,
-			reportData.InspectorEmail,
-			reportData.InspectorName,
-			reportURL,
-		); err != nil {
+	if h.emailQueue != nil && reportData.InspectorEmail != "" && len(recipients) == 0 {
+		if err := h.emailQueue.Enqueue(ctx, email.Message{
+			Template: email.TemplateReportReady,
+			To:       reportData.InspectorEmail,
+			Data: map[string]string{
+				email.DataName:      reportData.InspectorName,
+				email.DataReportURL: reportURL,
+			},
+		}); err != nil {
 			// Log error but don't fail the job - report was generated successfully
-			h.logger.Error("Failed to send report ready email to inspector",
+			h.logger.ErrorContext(ctx, "Failed to queue report ready email to inspector",
 				"error", err,
 				"user_id", p.UserID,
 				"report_id", dbReport.ID,
 			)
-		} else {
-			h.logger.Info("Report ready email sent to inspector",
-				"user_id", p.UserID,
-				"email", reportData.InspectorEmail,
-			)
 		}
 	}
 
-	// 11. Send report to client/recipient if email was provided
-	if h.emailService != nil && p.RecipientEmail != "" {
-		if err := h.emailService.SendReportToClientEmail(
-			ctx,
-			p.RecipientEmail,
-			reportData.InspectorName,
-			reportData.InspectorCompany,
-			reportData.SiteName,
-			reportURL,
-		); err != nil {
+	// 10. Queue one report email per recipient, so a bounce only affects its
+	// own delivery
+	for _, recipient := range recipients {
+		if h.emailQueue == nil {
+			break
+		}
+		bcc := reportData.InspectorEmail
+		if strings.EqualFold(bcc, recipient) {
+			bcc = ""
+		}
+		if err := h.emailQueue.Enqueue(ctx, email.Message{
+			Template: email.TemplateReportToClient,
+			To:       recipient,
+			Data: map[string]string{
+				email.DataInspectorName:    reportData.InspectorName,
+				email.DataInspectorCompany: reportData.InspectorCompany,
+				email.DataSiteName:         reportData.SiteName,
+				email.DataReportURL:        reportURL,
+				email.DataBcc:              bcc,
+				email.DataReplyTo:          reportData.InspectorEmail,
+			},
+		}); err != nil {
 			// Log error but don't fail the job - report was generated successfully
-			h.logger.Error("Failed to send report to client",
+			h.logger.ErrorContext(ctx, "Failed to queue report email to client",
 				"error", err,
-				"recipient_email", p.RecipientEmail,
-				"report_id", dbReport.ID,
-			)
-		} else {
-			h.logger.Info("Report sent to client",
-				"recipient_email", p.RecipientEmail,
+				"recipient_email", recipient,
 				"report_id", dbReport.ID,
 			)
 		}
 	}
 
-	h.logger.Info("Report generation completed",
+	// 11. Notify the user's webhooks (optional - don't fail the job)
+	if h.webhookService != nil {
+		if err := h.webhookService.Notify(ctx, p.UserID, webhook.EventReportReady, webhook.ReportReadyData{
+			ReportID:     dbReport.ID,
+			InspectionID: p.InspectionID,
+			Format:       p.Format,
+			DownloadURL:  reportURL,
+		}); err != nil {
+			h.logger.ErrorContext(ctx, "Failed to queue report webhooks", "error", err, "report_id", dbReport.ID)
+		}
+	}
+
+	h.logger.InfoContext(ctx, "Report generation completed",
 		"report_id", dbReport.ID,
 		"inspection_id", p.InspectionID,
 		"storage_key", storageKey,
@@ -213,3 +271,94 @@ func (h *GenerateReportHandler) Handle(ctx context.Context, payload []byte) erro
 
 	return nil
 }
+
+// OnPermanentFailure records the failed generation on a report row, so the
+// inspection page shows a retry option, and tells the user by email. If the
+// report was to be sent to recipients, each gets an apology that gives no
+// details of the failure.
+func (h *GenerateReportHandler) OnPermanentFailure(ctx context.Context, payload []byte, jobErr error) {
+	var p worker.GenerateReportPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		h.logger.ErrorContext(ctx, "Cannot report failed report generation: invalid payload", "error", err)
+		return
+	}
+
+	var failedFormat sql.NullString
+	if domain.ReportFormat(p.Format).IsValid() {
+		failedFormat = domain.ToNullString(p.Format)
+	}
+
+	dbReport, err := h.queries.CreateFailedReport(ctx, repository.CreateFailedReportParams{
+		InspectionID: p.InspectionID,
+		UserID:       p.UserID,
+		FailedFormat: failedFormat,
+	})
+	if err != nil {
+		// Keep going: the email still matters if the row can't be written,
+		// e.g. because the inspection was deleted
+		h.logger.ErrorContext(ctx, "Failed to record failed report",
+			"error", err,
+			"inspection_id", p.InspectionID,
+		)
+	}
+
+	if h.emailQueue == nil {
+		return
+	}
+
+	user, err := h.queries.GetUserByID(ctx, p.UserID)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "Failed to load user for report failure email",
+			"error", err,
+			"user_id", p.UserID,
+		)
+		return
+	}
+
+	inspectionURL := fmt.Sprintf("%s/inspections/%s", h.baseURL, p.InspectionID)
+	if err := h.emailQueue.Enqueue(ctx, email.Message{
+		Template: email.TemplateReportFailed,
+		To:       user.Email,
+		Data: map[string]string{
+			email.DataName:          user.Name,
+			email.DataInspectionURL: inspectionURL,
+		},
+	}); err != nil {
+		h.logger.ErrorContext(ctx, "Failed to queue report failed email",
+			"error", err,
+			"user_id", p.UserID,
+			"report_id", dbReport.ID,
+		)
+	}
+
+	// Client replies go to the business email, as on the report itself
+	replyTo := domain.NullStringValue(user.BusinessEmail)
+	if replyTo == "" {
+		replyTo = user.Email
+	}
+	for _, recipient := range p.Recipients() {
+		if err := h.emailQueue.Enqueue(ctx, email.Message{
+			Template: email.TemplateReportDelayed,
+			To:       recipient,
+			Data: map[string]string{
+				email.DataInspectorName:    user.Name,
+				email.DataInspectorCompany: domain.NullStringValue(user.BusinessName),
+				email.DataReplyTo:          replyTo,
+			},
+		}); err != nil {
+			h.logger.ErrorContext(ctx, "Failed to queue report delayed email to client",
+				"error", err,
+				"recipient_email", recipient,
+			)
+		}
+	}
+
+	h.logger.InfoContext(ctx, "Report generation failure reported",
+		"inspection_id", p.InspectionID,
+		"user_id", p.UserID,
+		"format", p.Format,
+		"error", jobErr,
+	)
+}
+
+var _ worker.FailureHandler = (*GenerateReportHandler)(nil)
@@ -0,0 +1,197 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/email"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/DukeRupert/lukaut/internal/testutil/fakedb"
+	"github.com/DukeRupert/lukaut/internal/worker"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// In-Memory Reports Database
+// =============================================================================
+
+// fakeReportsDB answers the sqlc queries used when a report generation job
+// fails.
+type fakeReportsDB struct {
+	userID  uuid.UUID
+	failed  []repository.Report
+	nextErr error
+}
+
+func (f *fakeReportsDB) Query(q fakedb.Query) (*fakedb.Rows, error) {
+	args := q.Args
+	name := q.Name
+	switch name {
+	case "CreateFailedReport":
+		if f.nextErr != nil {
+			return nil, f.nextErr
+		}
+		r := repository.Report{
+			ID:           uuid.New(),
+			InspectionID: uuid.MustParse(args[0].Value.(string)),
+			UserID:       uuid.MustParse(args[1].Value.(string)),
+			FailedAt:     sql.NullTime{Time: time.Now(), Valid: true},
+		}
+		if format, ok := args[2].Value.(string); ok {
+			r.FailedFormat = sql.NullString{String: format, Valid: true}
+		}
+		f.failed = append(f.failed, r)
+		return &fakedb.Rows{Columns: 10, Values: [][]driver.Value{{
+			r.ID.String(), r.InspectionID.String(), r.UserID.String(), nil, nil, int64(0), time.Now(), r.FailedAt.Time, r.FailedFormat.String, "{}",
+		}}}, nil
+	case "GetUserByID":
+		if args[0].Value.(string) != f.userID.String() {
+			return &fakedb.Rows{Columns: 29}, nil
+		}
+		values := make([]driver.Value, 29)
+		values[0] = f.userID.String()
+		values[1] = "pat@example.com"
+		values[2] = "hash"
+		values[3] = "Pat Inspector"
+		values[14] = "Acme Safety"
+		values[26] = int64(0)
+		return &fakedb.Rows{Columns: 29, Values: [][]driver.Value{values}}, nil
+	}
+	return nil, fmt.Errorf("fakeReportsDB: unexpected query %q", name)
+}
+
+// recordingQueue records enqueued messages.
+type recordingQueue struct {
+	messages []email.Message
+}
+
+func (q *recordingQueue) Enqueue(ctx context.Context, msg email.Message) error {
+	q.messages = append(q.messages, msg)
+	return nil
+}
+
+func newFailureTestHandler(f *fakeReportsDB, queue email.Queue) *GenerateReportHandler {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewGenerateReportHandler(repository.New(fakedb.Open(f)), nil, queue, nil, nil, nil, logger, "https://app.example.com")
+}
+
+func newGenerateReportPayload(t *testing.T, p worker.GenerateReportPayload) []byte {
+	t.Helper()
+	payload, err := json.Marshal(p)
+	if err != nil {
+		t.Fatalf("marshal payload: %v", err)
+	}
+	return payload
+}
+
+// =============================================================================
+// Permanent Failure Tests
+// =============================================================================
+
+func TestGenerateReportHandler_PermanentFailureFlagsRowAndEmails(t *testing.T) {
+	f := &fakeReportsDB{userID: uuid.New()}
+	queue := &recordingQueue{}
+	h := newFailureTestHandler(f, queue)
+	inspectionID := uuid.New()
+
+	h.OnPermanentFailure(context.Background(), newGenerateReportPayload(t, worker.GenerateReportPayload{
+		InspectionID:   inspectionID,
+		UserID:         f.userID,
+		Format:         "pdf",
+		RecipientEmail: "client@example.com",
+	}), errors.New("chromium crashed"))
+
+	if len(f.failed) != 1 {
+		t.Fatalf("expected one failed report row, got %d", len(f.failed))
+	}
+	if row := f.failed[0]; row.InspectionID != inspectionID || row.FailedFormat.String != "pdf" || !row.FailedAt.Valid {
+		t.Errorf("unexpected failed report row: %+v", row)
+	}
+
+	if len(queue.messages) != 2 {
+		t.Fatalf("expected failure and apology emails, got %+v", queue.messages)
+	}
+	failed := queue.messages[0]
+	if failed.Template != email.TemplateReportFailed || failed.To != "pat@example.com" {
+		t.Errorf("expected report failed email to the inspector, got %+v", failed)
+	}
+	wantURL := "https://app.example.com/inspections/" + inspectionID.String()
+	if failed.Data[email.DataInspectionURL] != wantURL {
+		t.Errorf("expected inspection URL %q, got %q", wantURL, failed.Data[email.DataInspectionURL])
+	}
+
+	apology := queue.messages[1]
+	if apology.Template != email.TemplateReportDelayed || apology.To != "client@example.com" {
+		t.Errorf("expected report delayed email to the client, got %+v", apology)
+	}
+	if apology.Data[email.DataInspectorCompany] != "Acme Safety" {
+		t.Errorf("expected inspector company in apology, got %+v", apology.Data)
+	}
+	if strings.Contains(fmt.Sprint(apology.Data), "chromium") {
+		t.Error("apology must not include failure details")
+	}
+}
+
+func TestGenerateReportHandler_PermanentFailureWithoutRecipient(t *testing.T) {
+	f := &fakeReportsDB{userID: uuid.New()}
+	queue := &recordingQueue{}
+	h := newFailureTestHandler(f, queue)
+
+	h.OnPermanentFailure(context.Background(), newGenerateReportPayload(t, worker.GenerateReportPayload{
+		InspectionID: uuid.New(),
+		UserID:       f.userID,
+		Format:       "docx",
+	}), errors.New("boom"))
+
+	if len(queue.messages) != 1 || queue.messages[0].Template != email.TemplateReportFailed {
+		t.Errorf("expected only the report failed email, got %+v", queue.messages)
+	}
+}
+
+func TestGenerateReportHandler_PermanentFailureEmailsWhenRowFails(t *testing.T) {
+	f := &fakeReportsDB{userID: uuid.New(), nextErr: errors.New("insert or update violates foreign key constraint")}
+	queue := &recordingQueue{}
+	h := newFailureTestHandler(f, queue)
+
+	h.OnPermanentFailure(context.Background(), newGenerateReportPayload(t, worker.GenerateReportPayload{
+		InspectionID: uuid.New(),
+		UserID:       f.userID,
+		Format:       "pdf",
+	}), errors.New("boom"))
+
+	if len(queue.messages) != 1 {
+		t.Errorf("expected the failure email even without a report row, got %+v", queue.messages)
+	}
+}
+
+func TestGenerateReportHandler_PermanentFailureApologizesToEachRecipient(t *testing.T) {
+	f := &fakeReportsDB{userID: uuid.New()}
+	queue := &recordingQueue{}
+	h := newFailureTestHandler(f, queue)
+
+	h.OnPermanentFailure(context.Background(), newGenerateReportPayload(t, worker.GenerateReportPayload{
+		InspectionID:    uuid.New(),
+		UserID:          f.userID,
+		Format:          "pdf",
+		RecipientEmails: []string{"owner@example.com", "gc@example.com"},
+	}), errors.New("boom"))
+
+	var apologies []string
+	for _, msg := range queue.messages {
+		if msg.Template == email.TemplateReportDelayed {
+			apologies = append(apologies, msg.To)
+		}
+	}
+	if strings.Join(apologies, ",") != "owner@example.com,gc@example.com" {
+		t.Errorf("expected an apology to each recipient, got %v", apologies)
+	}
+}
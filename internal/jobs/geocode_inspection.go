@@ -0,0 +1,95 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/geocode"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/DukeRupert/lukaut/internal/worker"
+)
+
+// GeocodeInspectionHandler processes jobs that look up the coordinates of an
+// inspection's address and store them on the inspection.
+//
+// An unrecognized address is not an error: the coordinates stay empty.
+// Provider failures, including rate limiting, are returned so the worker
+// retries with backoff; if every attempt fails the coordinates stay empty.
+type GeocodeInspectionHandler struct {
+	queries  *repository.Queries
+	geocoder geocode.Geocoder
+	logger   *slog.Logger
+}
+
+// NewGeocodeInspectionHandler creates a new handler for geocoding jobs.
+func NewGeocodeInspectionHandler(queries *repository.Queries, geocoder geocode.Geocoder, logger *slog.Logger) *GeocodeInspectionHandler {
+	return &GeocodeInspectionHandler{
+		queries:  queries,
+		geocoder: geocoder,
+		logger:   logger,
+	}
+}
+
+// Type returns the job type identifier.
+func (h *GeocodeInspectionHandler) Type() string {
+	return worker.JobTypeGeocodeInspection
+}
+
+// Handle geocodes the inspection address named in the payload.
+func (h *GeocodeInspectionHandler) Handle(ctx context.Context, payload []byte) error {
+	var p worker.GeocodeInspectionPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return worker.NewPermanentError(fmt.Errorf("unmarshal payload: %w", err))
+	}
+
+	row, err := h.queries.GetInspectionByIDAndUserID(ctx, repository.GetInspectionByIDAndUserIDParams{
+		ID:     p.InspectionID,
+		UserID: p.UserID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			h.logger.InfoContext(ctx, "Inspection deleted before geocoding", "inspection_id", p.InspectionID)
+			return nil
+		}
+		return fmt.Errorf("get inspection: %w", err)
+	}
+
+	// A later edit changed the address and enqueued its own job
+	address := geocode.FormatAddress(row.AddressLine1, domain.NullStringValue(row.AddressLine2), row.City, row.State, row.PostalCode)
+	if address != p.Address {
+		h.logger.InfoContext(ctx, "Inspection address changed, skipping stale geocoding", "inspection_id", p.InspectionID)
+		return nil
+	}
+
+	params := repository.UpdateInspectionLocationByIDAndUserIDParams{ID: p.InspectionID, UserID: p.UserID}
+	_, lat, lng, err := h.geocoder.Validate(ctx, address)
+	switch {
+	case err == nil:
+		params.Latitude = sql.NullFloat64{Float64: lat, Valid: true}
+		params.Longitude = sql.NullFloat64{Float64: lng, Valid: true}
+	case errors.Is(err, geocode.ErrNoMatch):
+		h.logger.InfoContext(ctx, "Inspection address not recognized", "inspection_id", p.InspectionID)
+	default:
+		h.logger.WarnContext(ctx, "Geocoding attempt failed",
+			"inspection_id", p.InspectionID,
+			"rate_limited", errors.Is(err, geocode.ErrRateLimited),
+			"error", err,
+		)
+		return fmt.Errorf("geocode address: %w", err)
+	}
+
+	if err := h.queries.UpdateInspectionLocationByIDAndUserID(ctx, params); err != nil {
+		return fmt.Errorf("store inspection location: %w", err)
+	}
+
+	h.logger.InfoContext(ctx, "Inspection geocoded",
+		"inspection_id", p.InspectionID,
+		"found", params.Latitude.Valid,
+	)
+	return nil
+}
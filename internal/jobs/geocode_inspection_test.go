@@ -0,0 +1,196 @@
+package jobs
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/geocode"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/DukeRupert/lukaut/internal/testutil/fakedb"
+	"github.com/DukeRupert/lukaut/internal/worker"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// In-Memory Inspection Database
+// =============================================================================
+
+// fakeGeocodeDB is a fake database holding a single inspection. It
+// answers the sqlc queries used by the geocoding job.
+type fakeGeocodeDB struct {
+	id, userID          uuid.UUID
+	line1, city, state  string
+	postal              string
+	latitude, longitude *float64
+	locationWrites      int
+}
+
+func (f *fakeGeocodeDB) Query(q fakedb.Query) (*fakedb.Rows, error) {
+	args := q.Args
+	name := q.Name
+	if name != "GetInspectionByIDAndUserID" {
+		return nil, fmt.Errorf("fakeGeocodeDB: unexpected query %q", name)
+	}
+	if args[0].Value.(string) != f.id.String() || args[1].Value.(string) != f.userID.String() {
+		return &fakedb.Rows{Columns: 23}, nil
+	}
+	values := make([]driver.Value, 23)
+	values[0] = f.id.String()
+	values[1] = f.userID.String()
+	values[2] = "Site walk"
+	values[3] = "draft"
+	values[4] = time.Now()
+	values[8] = time.Now()
+	values[9] = time.Now()
+	values[10] = f.line1
+	values[12] = f.city
+	values[13] = f.state
+	values[14] = f.postal
+	values[18] = int64(1)
+	values[20] = false
+	values[22] = false
+	return &fakedb.Rows{Columns: 23, Values: [][]driver.Value{values}}, nil
+}
+
+func (f *fakeGeocodeDB) Exec(q fakedb.Query) (int64, error) {
+	args := q.Args
+	name := q.Name
+	if name != "UpdateInspectionLocationByIDAndUserID" {
+		return 0, fmt.Errorf("fakeGeocodeDB: unexpected exec %q", name)
+	}
+	f.locationWrites++
+	f.latitude, f.longitude = nil, nil
+	if lat, ok := args[2].Value.(float64); ok {
+		lng := args[3].Value.(float64)
+		f.latitude, f.longitude = &lat, &lng
+	}
+	return 1, nil
+}
+
+// stubGeocoder returns fixed coordinates, or err when set.
+type stubGeocoder struct {
+	lat, lng float64
+	err      error
+	calls    int
+}
+
+func (g *stubGeocoder) Validate(ctx context.Context, address string) (string, float64, float64, error) {
+	g.calls++
+	if g.err != nil {
+		return "", 0, 0, g.err
+	}
+	return address, g.lat, g.lng, nil
+}
+
+func (g *stubGeocoder) Suggest(ctx context.Context, query string, limit int) ([]geocode.Suggestion, error) {
+	return nil, nil
+}
+
+func newGeocodeTestDB() *fakeGeocodeDB {
+	return &fakeGeocodeDB{
+		id:     uuid.New(),
+		userID: uuid.New(),
+		line1:  "100 Main St",
+		city:   "Boise",
+		state:  "ID",
+		postal: "83702",
+	}
+}
+
+func runGeocodeJob(t *testing.T, f *fakeGeocodeDB, geocoder geocode.Geocoder, address string) error {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	h := NewGeocodeInspectionHandler(repository.New(fakedb.Open(f)), geocoder, logger)
+	payload, err := json.Marshal(worker.GeocodeInspectionPayload{
+		InspectionID: f.id,
+		UserID:       f.userID,
+		Address:      address,
+	})
+	if err != nil {
+		t.Fatalf("marshal payload: %v", err)
+	}
+	return h.Handle(context.Background(), payload)
+}
+
+// =============================================================================
+// Geocode Inspection Tests
+// =============================================================================
+
+func TestGeocodeInspection_StoresCoordinates(t *testing.T) {
+	f := newGeocodeTestDB()
+	geocoder := &stubGeocoder{lat: 43.6150, lng: -116.2023}
+
+	if err := runGeocodeJob(t, f, geocoder, "100 Main St, Boise, ID 83702"); err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+
+	if f.latitude == nil || *f.latitude != 43.6150 || *f.longitude != -116.2023 {
+		t.Errorf("expected coordinates to be stored, got %v, %v", f.latitude, f.longitude)
+	}
+}
+
+func TestGeocodeInspection_NoMatchLeavesCoordinatesEmpty(t *testing.T) {
+	f := newGeocodeTestDB()
+
+	if err := runGeocodeJob(t, f, geocode.Noop{}, "100 Main St, Boise, ID 83702"); err != nil {
+		t.Fatalf("expected no match to succeed, got %v", err)
+	}
+
+	if f.latitude != nil || f.locationWrites != 1 {
+		t.Errorf("expected empty coordinates to be stored once, got %v after %d writes", f.latitude, f.locationWrites)
+	}
+}
+
+func TestGeocodeInspection_ProviderFailureRetries(t *testing.T) {
+	f := newGeocodeTestDB()
+	geocoder := &stubGeocoder{err: fmt.Errorf("nominatim: %w", geocode.ErrRateLimited)}
+
+	err := runGeocodeJob(t, f, geocoder, "100 Main St, Boise, ID 83702")
+
+	if !errors.Is(err, geocode.ErrRateLimited) {
+		t.Fatalf("expected rate limit error to be returned for retry, got %v", err)
+	}
+	if worker.IsPermanent(err) {
+		t.Error("expected a retryable error")
+	}
+	if f.locationWrites != 0 {
+		t.Errorf("expected no location writes, got %d", f.locationWrites)
+	}
+}
+
+func TestGeocodeInspection_SkipsStaleAddress(t *testing.T) {
+	f := newGeocodeTestDB()
+	geocoder := &stubGeocoder{lat: 43.6150, lng: -116.2023}
+
+	// The inspection moved to 100 Main St after this job was queued
+	if err := runGeocodeJob(t, f, geocoder, "9 Old Rd, Boise, ID 83702"); err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+
+	if geocoder.calls != 0 || f.locationWrites != 0 {
+		t.Errorf("expected stale job to be skipped, got %d lookups and %d writes", geocoder.calls, f.locationWrites)
+	}
+}
+
+func TestGeocodeInspection_DeletedInspection(t *testing.T) {
+	f := newGeocodeTestDB()
+	geocoder := &stubGeocoder{}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	h := NewGeocodeInspectionHandler(repository.New(fakedb.Open(f)), geocoder, logger)
+	payload, _ := json.Marshal(worker.GeocodeInspectionPayload{InspectionID: uuid.New(), UserID: f.userID})
+
+	if err := h.Handle(context.Background(), payload); err != nil {
+		t.Fatalf("expected deleted inspection to be skipped, got %v", err)
+	}
+	if geocoder.calls != 0 {
+		t.Errorf("expected no lookup, got %d", geocoder.calls)
+	}
+}
@@ -0,0 +1,219 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/DukeRupert/lukaut/internal/service"
+	"github.com/DukeRupert/lukaut/internal/storage"
+	"github.com/DukeRupert/lukaut/internal/worker"
+)
+
+// Defaults for PurgeInspectionsConfig.
+const (
+	DefaultPurgeInspectionsInterval = 24 * time.Hour
+	purgeInspectionsBatchSize       = 100
+)
+
+// errInspectionNotPurged is returned from the purge transaction when the
+// inspection is no longer due, so the audit event is rolled back with it.
+var errInspectionNotPurged = errors.New("inspection no longer due for purge")
+
+// PurgeInspectionsConfig configures the data retention purge.
+type PurgeInspectionsConfig struct {
+	Months         int           // Completed inspections untouched this many months are purged; zero disables the purge
+	DryRun         bool          // Log what would be purged without deleting anything
+	Interval       time.Duration // Time between sweeps (default: DefaultPurgeInspectionsInterval)
+	ThumbnailSizes []int         // Thumbnail variant sizes deleted with each photo (default: domain.DefaultThumbnailSizes)
+}
+
+// PurgeInspectionsHandler processes the recurring data retention sweep that
+// deletes completed inspections last changed more than Months ago, along
+// with their photos, thumbnails, and generated reports.
+//
+// Inspections under legal hold, templates, and inspections in any other
+// status are never purged. The database row is deleted first, re-checking
+// that the inspection is still due, so a hold placed mid-sweep is honored;
+// storage objects are removed only once the row is gone. Each purge is
+// recorded in the audit log, which outlives the inspection.
+//
+// In dry-run mode the sweep only logs what it would delete. After each
+// sweep the handler schedules the next one Interval later.
+type PurgeInspectionsHandler struct {
+	queries      *repository.Queries
+	storage      storage.Storage
+	auditService service.AuditService
+	config       PurgeInspectionsConfig
+	logger       *slog.Logger
+}
+
+// NewPurgeInspectionsHandler creates a new handler for data retention
+// purges.
+func NewPurgeInspectionsHandler(
+	queries *repository.Queries,
+	storage storage.Storage,
+	auditService service.AuditService,
+	config PurgeInspectionsConfig,
+	logger *slog.Logger,
+) *PurgeInspectionsHandler {
+	if config.Interval <= 0 {
+		config.Interval = DefaultPurgeInspectionsInterval
+	}
+	if len(config.ThumbnailSizes) == 0 {
+		config.ThumbnailSizes = domain.DefaultThumbnailSizes
+	}
+	return &PurgeInspectionsHandler{
+		queries:      queries,
+		storage:      storage,
+		auditService: auditService,
+		config:       config,
+		logger:       logger,
+	}
+}
+
+// Type returns the job type identifier.
+func (h *PurgeInspectionsHandler) Type() string {
+	return worker.JobTypePurgeInspections
+}
+
+// Handle purges the inspections that are due, then schedules the next
+// sweep. Per-inspection failures are logged and do not fail the sweep.
+// When retention is disabled the sweep does nothing and is not rescheduled.
+func (h *PurgeInspectionsHandler) Handle(ctx context.Context, payload []byte) error {
+	if h.config.Months <= 0 {
+		// Retention was disabled after this sweep was scheduled; end the chain
+		return nil
+	}
+	defer h.scheduleNext(ctx)
+
+	cutoff := sql.NullTime{Time: time.Now().AddDate(0, -h.config.Months, 0), Valid: true}
+	inspections, err := h.queries.ListInspectionsDueForPurge(ctx, repository.ListInspectionsDueForPurgeParams{
+		Cutoff:    cutoff,
+		BatchSize: purgeInspectionsBatchSize,
+	})
+	if err != nil {
+		return fmt.Errorf("list inspections due for purge: %w", err)
+	}
+
+	purged := 0
+	for _, insp := range inspections {
+		if h.purge(ctx, insp, cutoff) {
+			purged++
+		}
+	}
+
+	if h.config.DryRun && len(inspections) > 0 {
+		h.logger.InfoContext(ctx, "Retention dry run: inspections would be purged", "count", len(inspections), "cutoff", cutoff.Time)
+	} else if purged > 0 {
+		h.logger.InfoContext(ctx, "Expired inspections purged", "count", purged, "cutoff", cutoff.Time)
+	}
+	return nil
+}
+
+// purge deletes one inspection and its storage objects, or logs what would
+// be deleted in dry-run mode. It reports whether the inspection was deleted.
+func (h *PurgeInspectionsHandler) purge(ctx context.Context, insp repository.ListInspectionsDueForPurgeRow, cutoff sql.NullTime) bool {
+	logger := h.logger.With("inspection_id", insp.ID, "user_id", insp.UserID)
+
+	// Collected before the delete, which cascades to the image and report rows
+	keys, err := h.storageKeys(ctx, insp)
+	if err != nil {
+		logger.ErrorContext(ctx, "failed to list storage objects for purge", "error", err)
+		return false
+	}
+
+	if h.config.DryRun {
+		logger.InfoContext(ctx, "Retention dry run: inspection would be purged",
+			"title", insp.Title,
+			"updated_at", insp.UpdatedAt.Time,
+			"storage_objects", len(keys),
+		)
+		return false
+	}
+
+	err = h.auditService.RecordChange(ctx, func(q *repository.Queries) error {
+		deleted, err := q.PurgeInspection(ctx, repository.PurgeInspectionParams{
+			ID:     insp.ID,
+			Cutoff: cutoff,
+		})
+		if err != nil {
+			return err
+		}
+		if deleted == 0 {
+			return errInspectionNotPurged
+		}
+		return nil
+	}, domain.AuditEvent{
+		ActorUserID:  insp.UserID,
+		InspectionID: &insp.ID,
+		EntityType:   domain.AuditEntityInspection,
+		EntityID:     insp.ID,
+		Action:       domain.AuditActionPurged,
+		OldValues: map[string]string{
+			"status": string(domain.InspectionStatusCompleted),
+			"title":  insp.Title,
+		},
+	})
+	if errors.Is(err, errInspectionNotPurged) {
+		// Put under legal hold, reopened, or edited since it was listed
+		return false
+	}
+	if err != nil {
+		logger.ErrorContext(ctx, "failed to purge inspection", "error", err)
+		return false
+	}
+
+	// The row is gone, so a failed delete only leaves an orphaned object
+	for _, key := range keys {
+		if err := h.storage.Delete(ctx, key); err != nil {
+			logger.ErrorContext(ctx, "failed to delete purged object from storage", "error", err, "key", key)
+		}
+	}
+	return true
+}
+
+// storageKeys returns the keys of every stored object belonging to the
+// inspection: photo originals, thumbnail variants, and report files.
+func (h *PurgeInspectionsHandler) storageKeys(ctx context.Context, insp repository.ListInspectionsDueForPurgeRow) ([]string, error) {
+	images, err := h.queries.ListImagesByInspectionID(ctx, insp.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list images: %w", err)
+	}
+	reports, err := h.queries.ListReportsByInspectionID(ctx, insp.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list reports: %w", err)
+	}
+
+	var keys []string
+	for _, img := range images {
+		keys = append(keys, img.StorageKey)
+		if img.ThumbnailKey.Valid && img.ThumbnailKey.String != "" {
+			keys = append(keys, domain.ThumbnailKeys(img.ThumbnailKey.String, h.config.ThumbnailSizes)...)
+		}
+	}
+	for _, r := range reports {
+		for _, key := range []sql.NullString{r.PdfStorageKey, r.DocxStorageKey} {
+			if key.Valid && key.String != "" {
+				keys = append(keys, key.String)
+			}
+		}
+	}
+	return keys, nil
+}
+
+// scheduleNext enqueues the next sweep. It uses a fresh context so the next
+// run is scheduled even when the job's context has been canceled.
+func (h *PurgeInspectionsHandler) scheduleNext(ctx context.Context) {
+	scheduleCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer cancel()
+
+	if _, err := worker.SchedulePurgeInspections(scheduleCtx, h.queries, worker.WithDelay(h.config.Interval)); err != nil {
+		h.logger.ErrorContext(ctx, "failed to schedule next inspection purge", "error", err)
+	}
+}
@@ -0,0 +1,297 @@
+package jobs
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/DukeRupert/lukaut/internal/service"
+	"github.com/DukeRupert/lukaut/internal/storage"
+	"github.com/DukeRupert/lukaut/internal/testutil/fakedb"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// In-Memory Inspections Database
+// =============================================================================
+
+// fakePurgeInspection holds the columns the retention purge reads, plus the
+// storage keys of the inspection's photo and report.
+type fakePurgeInspection struct {
+	id           uuid.UUID
+	userID       uuid.UUID
+	status       string
+	legalHold    bool
+	isTemplate   bool
+	updatedAt    time.Time
+	imageKey     string
+	thumbnailKey string
+	reportKey    string
+}
+
+// fakePurgeDB answers the sqlc queries used by the retention purge. Enqueued
+// sweeps are recorded but never run.
+type fakePurgeDB struct {
+	inspections []*fakePurgeInspection
+	scheduled   int
+}
+
+// find returns the inspection with the given ID argument, or nil.
+func (f *fakePurgeDB) find(arg driver.NamedValue) *fakePurgeInspection {
+	for _, insp := range f.inspections {
+		if insp.id.String() == arg.Value.(string) {
+			return insp
+		}
+	}
+	return nil
+}
+
+// due mirrors the WHERE clause shared by the list and delete queries.
+func (insp *fakePurgeInspection) due(cutoff time.Time) bool {
+	return insp.status == "completed" && !insp.legalHold && !insp.isTemplate && insp.updatedAt.Before(cutoff)
+}
+
+func (f *fakePurgeDB) Query(q fakedb.Query) (*fakedb.Rows, error) {
+	args := q.Args
+	name := q.Name
+	switch name {
+	case "ListInspectionsDueForPurge":
+		cutoff := args[0].Value.(time.Time)
+		rows := &fakedb.Rows{Columns: 4}
+		for _, insp := range f.inspections {
+			if insp.due(cutoff) {
+				rows.Values = append(rows.Values, []driver.Value{insp.id.String(), insp.userID.String(), "Site walk", insp.updatedAt})
+			}
+		}
+		return rows, nil
+	case "ListImagesByInspectionID":
+		rows := &fakedb.Rows{Columns: 15}
+		if insp := f.find(args[0]); insp != nil && insp.imageKey != "" {
+			rows.Values = append(rows.Values, []driver.Value{
+				uuid.New().String(), insp.id.String(), insp.imageKey, insp.thumbnailKey, nil, "image/jpeg", int64(1024),
+				nil, nil, "completed", nil, time.Now(), nil, int64(0), nil,
+			})
+		}
+		return rows, nil
+	case "ListReportsByInspectionID":
+		rows := &fakedb.Rows{Columns: 10}
+		if insp := f.find(args[0]); insp != nil && insp.reportKey != "" {
+			rows.Values = append(rows.Values, []driver.Value{
+				uuid.New().String(), insp.id.String(), insp.userID.String(), insp.reportKey, nil, int64(2), time.Now(), nil, nil, nil,
+			})
+		}
+		return rows, nil
+	case "HasPendingJobOfType":
+		return &fakedb.Rows{Columns: 1, Values: [][]driver.Value{{f.scheduled > 0}}}, nil
+	case "EnqueueJob":
+		f.scheduled++
+		return &fakedb.Rows{Columns: 15, Values: [][]driver.Value{{
+			uuid.New().String(), args[0].Value, args[1].Value, "pending", args[2].Value, int64(0), args[3].Value, args[4].Value, nil, nil, nil, time.Now(),
+			nil, nil, args[5].Value,
+		}}}, nil
+	}
+	return nil, fmt.Errorf("fakePurgeDB: unexpected query %q", name)
+}
+
+func (f *fakePurgeDB) Exec(q fakedb.Query) (int64, error) {
+	args := q.Args
+	name := q.Name
+	if name != "PurgeInspection" {
+		return 0, fmt.Errorf("fakePurgeDB: unexpected exec %q", name)
+	}
+	cutoff := args[1].Value.(time.Time)
+	for i, insp := range f.inspections {
+		if insp.id.String() == args[0].Value.(string) && insp.due(cutoff) {
+			f.inspections = append(f.inspections[:i], f.inspections[i+1:]...)
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+// recordingAudit runs changes directly on the queries and records the events
+// of committed changes.
+type recordingAudit struct {
+	service.AuditService
+	queries *repository.Queries
+	events  []domain.AuditEvent
+}
+
+func (a *recordingAudit) RecordChange(ctx context.Context, change func(q *repository.Queries) error, events ...domain.AuditEvent) error {
+	if err := change(a.queries); err != nil {
+		return err
+	}
+	a.events = append(a.events, events...)
+	return nil
+}
+
+// deletingStorage records deleted keys.
+type deletingStorage struct {
+	storage.Storage
+	deleted []string
+}
+
+func (s *deletingStorage) Delete(ctx context.Context, key string) error {
+	s.deleted = append(s.deleted, key)
+	return nil
+}
+
+func newPurgeTestHandler(f *fakePurgeDB, store *deletingStorage, dryRun bool) (*PurgeInspectionsHandler, *recordingAudit) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	queries := repository.New(fakedb.Open(f))
+	audit := &recordingAudit{queries: queries}
+	return NewPurgeInspectionsHandler(queries, store, audit, PurgeInspectionsConfig{
+		Months:         12,
+		DryRun:         dryRun,
+		ThumbnailSizes: []int{200},
+	}, logger), audit
+}
+
+// expired returns a completed inspection last changed well past the
+// 12-month retention period.
+func expired() *fakePurgeInspection {
+	return &fakePurgeInspection{
+		id:        uuid.New(),
+		userID:    uuid.New(),
+		status:    "completed",
+		updatedAt: time.Now().AddDate(-2, 0, 0),
+	}
+}
+
+// remaining returns the IDs left in the database.
+func (f *fakePurgeDB) remaining() map[uuid.UUID]bool {
+	ids := make(map[uuid.UUID]bool, len(f.inspections))
+	for _, insp := range f.inspections {
+		ids[insp.id] = true
+	}
+	return ids
+}
+
+// =============================================================================
+// Retention Purge Tests
+// =============================================================================
+
+func TestPurgeInspections_SelectsOnlyExpiredCompletedInspections(t *testing.T) {
+	old := expired()
+	recent := expired()
+	recent.updatedAt = time.Now().AddDate(0, -6, 0)
+	inProgress := expired()
+	inProgress.status = "in_progress"
+	template := expired()
+	template.isTemplate = true
+
+	f := &fakePurgeDB{inspections: []*fakePurgeInspection{old, recent, inProgress, template}}
+	h, audit := newPurgeTestHandler(f, &deletingStorage{}, false)
+
+	if err := h.Handle(context.Background(), []byte(`{}`)); err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+
+	left := f.remaining()
+	if left[old.id] {
+		t.Error("expected the expired completed inspection to be purged")
+	}
+	for _, kept := range []*fakePurgeInspection{recent, inProgress, template} {
+		if !left[kept.id] {
+			t.Errorf("expected inspection (status %s, template %t) to be kept", kept.status, kept.isTemplate)
+		}
+	}
+	if len(audit.events) != 1 || audit.events[0].Action != domain.AuditActionPurged || audit.events[0].EntityID != old.id {
+		t.Errorf("expected one purge audit event for the expired inspection, got %+v", audit.events)
+	}
+	if f.scheduled != 1 {
+		t.Errorf("expected the next sweep to be scheduled, got %d", f.scheduled)
+	}
+}
+
+func TestPurgeInspections_SparesLegalHold(t *testing.T) {
+	held := expired()
+	held.legalHold = true
+	held.imageKey = "inspections/held/images/a.jpg"
+
+	f := &fakePurgeDB{inspections: []*fakePurgeInspection{held}}
+	store := &deletingStorage{}
+	h, audit := newPurgeTestHandler(f, store, false)
+
+	if err := h.Handle(context.Background(), []byte(`{}`)); err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+
+	if !f.remaining()[held.id] {
+		t.Error("expected the held inspection to be kept")
+	}
+	if len(store.deleted) != 0 || len(audit.events) != 0 {
+		t.Errorf("expected nothing deleted or audited, got keys %v and events %+v", store.deleted, audit.events)
+	}
+}
+
+func TestPurgeInspections_DeletesStorageObjects(t *testing.T) {
+	insp := expired()
+	insp.imageKey = "inspections/x/images/a.jpg"
+	insp.thumbnailKey = "inspections/x/thumbnails/a_200.jpg"
+	insp.reportKey = "reports/x/report.pdf"
+
+	f := &fakePurgeDB{inspections: []*fakePurgeInspection{insp}}
+	store := &deletingStorage{}
+	h, _ := newPurgeTestHandler(f, store, false)
+
+	if err := h.Handle(context.Background(), []byte(`{}`)); err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+
+	want := []string{
+		"inspections/x/images/a.jpg",
+		"inspections/x/thumbnails/a_200.jpg",
+		"inspections/x/thumbnails/a_200.webp",
+		"reports/x/report.pdf",
+	}
+	sort.Strings(store.deleted)
+	if strings.Join(store.deleted, ",") != strings.Join(want, ",") {
+		t.Errorf("deleted keys = %v, want %v", store.deleted, want)
+	}
+}
+
+func TestPurgeInspections_DryRunDeletesNothing(t *testing.T) {
+	insp := expired()
+	insp.imageKey = "inspections/x/images/a.jpg"
+
+	f := &fakePurgeDB{inspections: []*fakePurgeInspection{insp}}
+	store := &deletingStorage{}
+	h, audit := newPurgeTestHandler(f, store, true)
+
+	if err := h.Handle(context.Background(), []byte(`{}`)); err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+
+	if !f.remaining()[insp.id] {
+		t.Error("expected the dry run to keep the inspection")
+	}
+	if len(store.deleted) != 0 || len(audit.events) != 0 {
+		t.Errorf("expected nothing deleted or audited, got keys %v and events %+v", store.deleted, audit.events)
+	}
+	if f.scheduled != 1 {
+		t.Errorf("expected the next sweep to be scheduled, got %d", f.scheduled)
+	}
+}
+
+func TestPurgeInspections_DisabledDoesNothing(t *testing.T) {
+	f := &fakePurgeDB{inspections: []*fakePurgeInspection{expired()}}
+	h, _ := newPurgeTestHandler(f, &deletingStorage{}, false)
+	h.config.Months = 0
+
+	if err := h.Handle(context.Background(), []byte(`{}`)); err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+
+	if len(f.inspections) != 1 || f.scheduled != 0 {
+		t.Errorf("expected no purge and no next sweep, got %d inspections and %d scheduled", len(f.inspections), f.scheduled)
+	}
+}
@@ -0,0 +1,73 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/DukeRupert/lukaut/internal/email"
+	"github.com/DukeRupert/lukaut/internal/worker"
+)
+
+// SendEmailHandler processes jobs that render and send transactional emails.
+// Retryable delivery failures are returned to the worker so they are retried
+// with backoff; malformed payloads, unknown templates, and failures the
+// provider classifies as permanent (see email.IsRetryable) are not retried.
+type SendEmailHandler struct {
+	emailService email.EmailService
+	logger       *slog.Logger
+}
+
+// NewSendEmailHandler creates a new handler for email delivery jobs.
+func NewSendEmailHandler(emailService email.EmailService, logger *slog.Logger) *SendEmailHandler {
+	return &SendEmailHandler{
+		emailService: emailService,
+		logger:       logger,
+	}
+}
+
+// Type returns the job type identifier.
+func (h *SendEmailHandler) Type() string {
+	return worker.JobTypeSendEmail
+}
+
+// Handle sends the email described by the job payload.
+func (h *SendEmailHandler) Handle(ctx context.Context, payload []byte) error {
+	var p worker.SendEmailPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return worker.NewPermanentError(fmt.Errorf("unmarshal payload: %w", err))
+	}
+
+	if p.To == "" {
+		return worker.NewPermanentError(fmt.Errorf("email job missing recipient"))
+	}
+
+	msg := email.Message{Template: p.Template, To: p.To, Data: p.Data}
+	if err := email.Send(ctx, h.emailService, msg); err != nil {
+		var unknown *email.UnknownTemplateError
+		if errors.As(err, &unknown) {
+			return worker.NewPermanentError(err)
+		}
+
+		retryable := email.IsRetryable(err)
+		h.logger.WarnContext(ctx, "Email delivery attempt failed",
+			"template", p.Template,
+			"to", p.To,
+			"retryable", retryable,
+			"error", err,
+		)
+		err = fmt.Errorf("send %s email: %w", p.Template, err)
+		if !retryable {
+			return worker.NewPermanentError(err)
+		}
+		return err
+	}
+
+	h.logger.InfoContext(ctx, "Email sent",
+		"template", p.Template,
+		"to", p.To,
+	)
+	return nil
+}
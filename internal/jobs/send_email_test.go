@@ -0,0 +1,141 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/DukeRupert/lukaut/internal/email"
+	"github.com/DukeRupert/lukaut/internal/worker"
+)
+
+// recordingEmailService records sent emails and fails while err is set.
+type recordingEmailService struct {
+	err  error
+	sent []string
+}
+
+func (s *recordingEmailService) record(kind string, fields ...string) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.sent = append(s.sent, kind+":"+fields[0])
+	return nil
+}
+
+func (s *recordingEmailService) SendVerificationEmail(ctx context.Context, to, name, token string) error {
+	return s.record("verification", to, name, token)
+}
+
+func (s *recordingEmailService) SendVerificationReminderEmail(ctx context.Context, to, name, token string) error {
+	return s.record("verification_reminder", to, name, token)
+}
+
+func (s *recordingEmailService) SendPasswordResetEmail(ctx context.Context, to, name, token string) error {
+	return s.record("password_reset", to, name, token)
+}
+
+func (s *recordingEmailService) SendReportReadyEmail(ctx context.Context, to, name, reportURL string) error {
+	return s.record("report_ready", to, name, reportURL)
+}
+
+func (s *recordingEmailService) SendReportToClientEmail(ctx context.Context, to, inspectorName, inspectorCompany, siteName, reportURL, bcc, replyTo string) error {
+	return s.record("report_to_client", to, inspectorName, inspectorCompany, siteName, reportURL)
+}
+
+func (s *recordingEmailService) SendReportFailedEmail(ctx context.Context, to, name, inspectionURL string) error {
+	return s.record("report_failed", to, name, inspectionURL)
+}
+
+func (s *recordingEmailService) SendReportDelayedEmail(ctx context.Context, to, inspectorName, inspectorCompany, replyTo string) error {
+	return s.record("report_delayed", to, inspectorName, inspectorCompany)
+}
+
+func (s *recordingEmailService) SendOrganizationInviteEmail(ctx context.Context, to, inviterName, organizationName, token string) error {
+	return s.record("organization_invite", to, inviterName, organizationName, token)
+}
+
+func newSendEmailPayload(t *testing.T, template, to string) []byte {
+	t.Helper()
+	payload, err := json.Marshal(worker.SendEmailPayload{
+		Template: template,
+		To:       to,
+		Data:     map[string]string{email.DataName: "Pat", email.DataToken: "tok_123"},
+	})
+	if err != nil {
+		t.Fatalf("marshal payload: %v", err)
+	}
+	return payload
+}
+
+func newSendEmailTestHandler(svc email.EmailService) *SendEmailHandler {
+	return NewSendEmailHandler(svc, slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError})))
+}
+
+func TestSendEmailHandler_SendsTemplate(t *testing.T) {
+	svc := &recordingEmailService{}
+	h := newSendEmailTestHandler(svc)
+
+	if err := h.Handle(context.Background(), newSendEmailPayload(t, email.TemplatePasswordReset, "pat@example.com")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(svc.sent) != 1 || svc.sent[0] != "password_reset:pat@example.com" {
+		t.Errorf("expected password reset email to pat@example.com, got %v", svc.sent)
+	}
+}
+
+func TestSendEmailHandler_DeliveryFailureIsRetryable(t *testing.T) {
+	svc := &recordingEmailService{err: errors.New("421 service not available")}
+	h := newSendEmailTestHandler(svc)
+
+	err := h.Handle(context.Background(), newSendEmailPayload(t, email.TemplateVerification, "pat@example.com"))
+
+	if err == nil {
+		t.Fatal("expected delivery error")
+	}
+	if worker.IsPermanent(err) {
+		t.Error("expected SMTP failure to be retried")
+	}
+}
+
+func TestSendEmailHandler_PermanentFailures(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload []byte
+	}{
+		{"unknown template", newSendEmailPayload(t, "welcome_back", "pat@example.com")},
+		{"missing recipient", newSendEmailPayload(t, email.TemplateVerification, "")},
+		{"malformed payload", []byte(`{"template":`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &recordingEmailService{}
+			h := newSendEmailTestHandler(svc)
+
+			err := h.Handle(context.Background(), tt.payload)
+
+			if !worker.IsPermanent(err) {
+				t.Errorf("expected permanent error, got %v", err)
+			}
+			if len(svc.sent) != 0 {
+				t.Errorf("expected nothing sent, got %v", svc.sent)
+			}
+		})
+	}
+}
+
+func TestSendEmailHandler_ProviderRejectionIsPermanent(t *testing.T) {
+	svc := &recordingEmailService{err: email.Permanent(errors.New("422 inactive recipient"))}
+	h := newSendEmailTestHandler(svc)
+
+	err := h.Handle(context.Background(), newSendEmailPayload(t, email.TemplateVerification, "bounced@example.com"))
+
+	if !worker.IsPermanent(err) {
+		t.Errorf("expected permanent error for rejected recipient, got %v", err)
+	}
+}
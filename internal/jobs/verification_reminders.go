@@ -0,0 +1,154 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/email"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/DukeRupert/lukaut/internal/service"
+	"github.com/DukeRupert/lukaut/internal/worker"
+)
+
+// Defaults for VerificationRemindersConfig.
+const (
+	DefaultVerificationReminderDelay    = 24 * time.Hour
+	DefaultVerificationReminderInterval = time.Hour
+	verificationReminderBatchSize       = 100
+)
+
+// VerificationRemindersConfig configures the verification reminder sweep.
+type VerificationRemindersConfig struct {
+	Delay        time.Duration // Wait after registration, and between reminders (default: DefaultVerificationReminderDelay)
+	MaxReminders int           // Reminders sent to each unverified user; zero disables reminders
+	Interval     time.Duration // Time between sweeps (default: DefaultVerificationReminderInterval)
+}
+
+// VerificationRemindersHandler processes the recurring sweep that emails a
+// fresh verification link to users who registered more than Delay ago and
+// have not verified their email address.
+//
+// Each user receives at most MaxReminders reminders, at least Delay apart.
+// A reminder is recorded on the user before it is queued, so concurrent or
+// repeated sweeps never send the same reminder twice. After each sweep the
+// handler schedules the next one Interval later.
+type VerificationRemindersHandler struct {
+	queries     *repository.Queries
+	userService service.UserService
+	emailQueue  email.Queue
+	config      VerificationRemindersConfig
+	logger      *slog.Logger
+}
+
+// NewVerificationRemindersHandler creates a new handler for verification
+// reminder sweeps.
+func NewVerificationRemindersHandler(
+	queries *repository.Queries,
+	userService service.UserService,
+	emailQueue email.Queue,
+	config VerificationRemindersConfig,
+	logger *slog.Logger,
+) *VerificationRemindersHandler {
+	if config.Delay <= 0 {
+		config.Delay = DefaultVerificationReminderDelay
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultVerificationReminderInterval
+	}
+	return &VerificationRemindersHandler{
+		queries:     queries,
+		userService: userService,
+		emailQueue:  emailQueue,
+		config:      config,
+		logger:      logger,
+	}
+}
+
+// Type returns the job type identifier.
+func (h *VerificationRemindersHandler) Type() string {
+	return worker.JobTypeVerificationReminders
+}
+
+// Handle sends reminders to the users that are due one, then schedules the
+// next sweep. Per-user failures are logged and do not fail the sweep.
+// When reminders are disabled the sweep does nothing and is not rescheduled.
+func (h *VerificationRemindersHandler) Handle(ctx context.Context, payload []byte) error {
+	if h.config.MaxReminders <= 0 {
+		// Reminders were disabled after this sweep was scheduled; end the chain
+		return nil
+	}
+	defer h.scheduleNext(ctx)
+
+	users, err := h.queries.ListUsersDueVerificationReminder(ctx, repository.ListUsersDueVerificationReminderParams{
+		Cutoff:       sql.NullTime{Time: time.Now().Add(-h.config.Delay), Valid: true},
+		MaxReminders: int32(h.config.MaxReminders),
+		BatchSize:    verificationReminderBatchSize,
+	})
+	if err != nil {
+		return fmt.Errorf("list users due a verification reminder: %w", err)
+	}
+
+	sent := 0
+	for _, u := range users {
+		if h.remind(ctx, u) {
+			sent++
+		}
+	}
+
+	if sent > 0 {
+		h.logger.InfoContext(ctx, "Verification reminders queued", "count", sent)
+	}
+	return nil
+}
+
+// remind records and queues one reminder. It reports whether the reminder
+// was queued.
+func (h *VerificationRemindersHandler) remind(ctx context.Context, u repository.ListUsersDueVerificationReminderRow) bool {
+	claimed, err := h.queries.ClaimVerificationReminder(ctx, repository.ClaimVerificationReminderParams{
+		ID:                        u.ID,
+		VerificationReminderCount: u.VerificationReminderCount,
+	})
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to record verification reminder", "error", err, "user_id", u.ID)
+		return false
+	}
+	if claimed == 0 {
+		// Another sweep sent it, or the user verified in the meantime
+		return false
+	}
+
+	// A fresh token replaces the one from registration, which may have expired
+	result, err := h.userService.CreateEmailVerificationToken(ctx, u.ID)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to create verification token for reminder", "error", err, "user_id", u.ID)
+		return false
+	}
+
+	err = h.emailQueue.Enqueue(ctx, email.Message{
+		Template: email.TemplateVerificationReminder,
+		To:       u.Email,
+		Data: map[string]string{
+			email.DataName:  u.Name,
+			email.DataToken: result.Token,
+		},
+	})
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to queue verification reminder", "error", err, "user_id", u.ID)
+		return false
+	}
+	return true
+}
+
+// scheduleNext enqueues the next sweep. It uses a fresh context so the next
+// run is scheduled even when the job's context has been canceled.
+func (h *VerificationRemindersHandler) scheduleNext(ctx context.Context) {
+	scheduleCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer cancel()
+
+	if _, err := worker.ScheduleVerificationReminders(scheduleCtx, h.queries, worker.WithDelay(h.config.Interval)); err != nil {
+		h.logger.ErrorContext(ctx, "failed to schedule next verification reminder sweep", "error", err)
+	}
+}
@@ -0,0 +1,209 @@
+package jobs
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/email"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/DukeRupert/lukaut/internal/service"
+	"github.com/DukeRupert/lukaut/internal/testutil/fakedb"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// In-Memory Users Database
+// =============================================================================
+
+// fakeReminderUser holds the columns the reminder sweep reads and writes.
+type fakeReminderUser struct {
+	id            uuid.UUID
+	email, name   string
+	verified      bool
+	createdAt     time.Time
+	reminderAt    *time.Time
+	reminderCount int64
+}
+
+// fakeRemindersDB answers the sqlc queries used by the verification reminder
+// sweep. Enqueued sweeps are recorded but never run.
+type fakeRemindersDB struct {
+	users     []*fakeReminderUser
+	scheduled []time.Time // scheduled_at of each enqueued sweep
+}
+
+func (f *fakeRemindersDB) Query(q fakedb.Query) (*fakedb.Rows, error) {
+	args := q.Args
+	name := q.Name
+	switch name {
+	case "ListUsersDueVerificationReminder":
+		// Mirrors the WHERE clause
+		cutoff := args[0].Value.(time.Time)
+		maxReminders := args[1].Value.(int64)
+		rows := &fakedb.Rows{Columns: 4}
+		for _, u := range f.users {
+			if u.verified || !u.createdAt.Before(cutoff) || u.reminderCount >= maxReminders {
+				continue
+			}
+			if u.reminderAt != nil && !u.reminderAt.Before(cutoff) {
+				continue
+			}
+			rows.Values = append(rows.Values, []driver.Value{u.id.String(), u.email, u.name, u.reminderCount})
+		}
+		return rows, nil
+	case "HasPendingJobOfType":
+		return &fakedb.Rows{Columns: 1, Values: [][]driver.Value{{len(f.scheduled) > 0}}}, nil
+	case "EnqueueJob":
+		scheduledAt := args[4].Value.(time.Time)
+		f.scheduled = append(f.scheduled, scheduledAt)
+		return &fakedb.Rows{Columns: 15, Values: [][]driver.Value{{
+			uuid.New().String(), args[0].Value, args[1].Value, "pending", args[2].Value, int64(0), args[3].Value, scheduledAt, nil, nil, nil, time.Now(),
+			nil, nil, args[5].Value,
+		}}}, nil
+	}
+	return nil, fmt.Errorf("fakeRemindersDB: unexpected query %q", name)
+}
+
+func (f *fakeRemindersDB) Exec(q fakedb.Query) (int64, error) {
+	args := q.Args
+	name := q.Name
+	if name != "ClaimVerificationReminder" {
+		return 0, fmt.Errorf("fakeRemindersDB: unexpected exec %q", name)
+	}
+	for _, u := range f.users {
+		if u.id.String() == args[0].Value.(string) && u.reminderCount == args[1].Value.(int64) && !u.verified {
+			now := time.Now()
+			u.reminderAt = &now
+			u.reminderCount++
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+// fakeTokenUserService issues verification tokens.
+type fakeTokenUserService struct {
+	service.UserService
+	issued int
+}
+
+func (s *fakeTokenUserService) CreateEmailVerificationToken(ctx context.Context, userID uuid.UUID) (*domain.EmailVerificationResult, error) {
+	s.issued++
+	return &domain.EmailVerificationResult{Token: fmt.Sprintf("tok_%d", s.issued), UserID: userID}, nil
+}
+
+func newRemindersTestHandler(f *fakeRemindersDB, queue email.Queue, maxReminders int) *VerificationRemindersHandler {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewVerificationRemindersHandler(repository.New(fakedb.Open(f)), &fakeTokenUserService{}, queue, VerificationRemindersConfig{
+		Delay:        24 * time.Hour,
+		MaxReminders: maxReminders,
+	}, logger)
+}
+
+// =============================================================================
+// Verification Reminder Tests
+// =============================================================================
+
+func TestVerificationReminders_SendsReminderOnce(t *testing.T) {
+	user := &fakeReminderUser{
+		id:        uuid.New(),
+		email:     "pat@example.com",
+		name:      "Pat",
+		createdAt: time.Now().Add(-25 * time.Hour),
+	}
+	f := &fakeRemindersDB{users: []*fakeReminderUser{user}}
+	queue := &recordingQueue{}
+	h := newRemindersTestHandler(f, queue, 2)
+
+	for range 2 {
+		if err := h.Handle(context.Background(), []byte(`{}`)); err != nil {
+			t.Fatalf("Handle failed: %v", err)
+		}
+	}
+
+	if len(queue.messages) != 1 {
+		t.Fatalf("expected one reminder, got %d", len(queue.messages))
+	}
+	msg := queue.messages[0]
+	if msg.Template != email.TemplateVerificationReminder || msg.To != "pat@example.com" {
+		t.Errorf("unexpected message: %+v", msg)
+	}
+	if msg.Data[email.DataName] != "Pat" || msg.Data[email.DataToken] == "" {
+		t.Errorf("expected name and token in message data, got %v", msg.Data)
+	}
+	if user.reminderCount != 1 || user.reminderAt == nil {
+		t.Errorf("expected the reminder to be recorded, got count %d", user.reminderCount)
+	}
+	if len(f.scheduled) != 1 || time.Until(f.scheduled[0]) < 50*time.Minute {
+		t.Errorf("expected a single next sweep about an hour out, got %v", f.scheduled)
+	}
+}
+
+func TestVerificationReminders_SkipsRecentAndVerifiedUsers(t *testing.T) {
+	f := &fakeRemindersDB{users: []*fakeReminderUser{
+		{id: uuid.New(), email: "new@example.com", createdAt: time.Now().Add(-time.Hour)},
+		{id: uuid.New(), email: "done@example.com", verified: true, createdAt: time.Now().Add(-48 * time.Hour)},
+	}}
+	queue := &recordingQueue{}
+
+	if err := newRemindersTestHandler(f, queue, 2).Handle(context.Background(), []byte(`{}`)); err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+
+	if len(queue.messages) != 0 {
+		t.Errorf("expected no reminders, got %+v", queue.messages)
+	}
+}
+
+func TestVerificationReminders_StopsAtMaxReminders(t *testing.T) {
+	lastReminder := time.Now().Add(-25 * time.Hour)
+	user := &fakeReminderUser{
+		id:            uuid.New(),
+		email:         "pat@example.com",
+		createdAt:     time.Now().Add(-72 * time.Hour),
+		reminderAt:    &lastReminder,
+		reminderCount: 1,
+	}
+	f := &fakeRemindersDB{users: []*fakeReminderUser{user}}
+	queue := &recordingQueue{}
+	h := newRemindersTestHandler(f, queue, 2)
+
+	// The second reminder is due a day after the first
+	if err := h.Handle(context.Background(), []byte(`{}`)); err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+	if len(queue.messages) != 1 || user.reminderCount != 2 {
+		t.Fatalf("expected the second reminder, got %d messages, count %d", len(queue.messages), user.reminderCount)
+	}
+
+	// A day later the limit has been reached
+	lastReminder = time.Now().Add(-25 * time.Hour)
+	user.reminderAt = &lastReminder
+	if err := h.Handle(context.Background(), []byte(`{}`)); err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+	if len(queue.messages) != 1 {
+		t.Errorf("expected no reminder past the limit, got %d messages", len(queue.messages))
+	}
+}
+
+func TestVerificationReminders_DisabledEndsSchedule(t *testing.T) {
+	f := &fakeRemindersDB{users: []*fakeReminderUser{
+		{id: uuid.New(), email: "pat@example.com", createdAt: time.Now().Add(-48 * time.Hour)},
+	}}
+	queue := &recordingQueue{}
+
+	if err := newRemindersTestHandler(f, queue, 0).Handle(context.Background(), []byte(`{}`)); err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+
+	if len(queue.messages) != 0 || len(f.scheduled) != 0 {
+		t.Errorf("expected no reminders and no next sweep, got %d messages and %d sweeps", len(queue.messages), len(f.scheduled))
+	}
+}
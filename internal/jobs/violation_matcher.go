@@ -0,0 +1,74 @@
+package jobs
+
+import (
+	"strings"
+
+	"github.com/DukeRupert/lukaut/internal/ai"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/google/uuid"
+)
+
+// violationMatcher pairs the violations of a new analysis of an image with the
+// violations stored by earlier analyses of the same image, so reanalysis
+// updates them instead of adding duplicates.
+//
+// Violations are keyed by image and regulation: a new violation matches a
+// stored one whose primary regulation is among its suggested regulations,
+// trying the suggestions in order. Failing that, it matches a stored violation
+// with the same description. Each stored violation is matched at most once.
+type violationMatcher struct {
+	byRegulation  map[string][]uuid.UUID
+	byDescription map[string][]uuid.UUID
+	matched       map[uuid.UUID]bool
+}
+
+// newViolationMatcher indexes the stored AI violations of one image.
+func newViolationMatcher(existing []repository.ListAIViolationMatchesByImageIDRow) *violationMatcher {
+	m := &violationMatcher{
+		byRegulation:  make(map[string][]uuid.UUID),
+		byDescription: make(map[string][]uuid.UUID),
+		matched:       make(map[uuid.UUID]bool),
+	}
+	for _, row := range existing {
+		if row.StandardNumber.Valid {
+			key := regulationMatchKey(row.StandardNumber.String)
+			m.byRegulation[key] = append(m.byRegulation[key], row.ID)
+		}
+		key := descriptionMatchKey(row.Description)
+		m.byDescription[key] = append(m.byDescription[key], row.ID)
+	}
+	return m
+}
+
+// match returns the stored violation that v is a new finding of, if any, and
+// marks it matched.
+func (m *violationMatcher) match(v ai.PotentialViolation) (uuid.UUID, bool) {
+	for _, reg := range v.SuggestedRegulations {
+		if id, ok := m.take(m.byRegulation[regulationMatchKey(reg)]); ok {
+			return id, true
+		}
+	}
+	return m.take(m.byDescription[descriptionMatchKey(v.Description)])
+}
+
+// take returns the first candidate not matched yet.
+func (m *violationMatcher) take(candidates []uuid.UUID) (uuid.UUID, bool) {
+	for _, id := range candidates {
+		if !m.matched[id] {
+			m.matched[id] = true
+			return id, true
+		}
+	}
+	return uuid.Nil, false
+}
+
+// regulationMatchKey normalizes a standard number, ignoring case and spacing
+// (e.g. "1926.501 (b)(1)" and "1926.501(B)(1)" match).
+func regulationMatchKey(standardNumber string) string {
+	return strings.ToLower(strings.Join(strings.Fields(standardNumber), ""))
+}
+
+// descriptionMatchKey normalizes a description, ignoring case and runs of spaces.
+func descriptionMatchKey(description string) string {
+	return strings.ToLower(strings.Join(strings.Fields(description), " "))
+}
@@ -3,6 +3,8 @@ package internal
 import (
 	"io"
 	"log/slog"
+
+	"github.com/DukeRupert/lukaut/internal/requestid"
 )
 
 func NewLogger(w io.Writer, env string, level string) *slog.Logger {
@@ -31,5 +33,6 @@ func NewLogger(w io.Writer, env string, level string) *slog.Logger {
 		handler = slog.NewJSONHandler(w, opts)
 	}
 
-	return slog.New(handler)
+	// Records logged with a request context carry its request_id
+	return slog.New(requestid.NewLogHandler(handler))
 }
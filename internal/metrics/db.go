@@ -0,0 +1,16 @@
+package metrics
+
+import (
+	"database/sql"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+// RegisterDBStats exports the connection pool statistics of db (open, in
+// use, and idle connections, and time spent waiting for one) as
+// go_sql_* metrics, with the pool name, e.g. "http" or "worker", as the
+// db_name label.
+func RegisterDBStats(db *sql.DB, pool string) {
+	prometheus.MustRegister(collectors.NewDBStatsCollector(db, pool))
+}
@@ -66,6 +66,15 @@ var (
 		},
 		[]string{"type"},
 	)
+
+	JobsPermanentlyFailedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "jobs_permanently_failed_total",
+			Help:      "Total number of jobs that exhausted their attempts or failed permanently",
+		},
+		[]string{"type"},
+	)
 )
 
 // Business metrics
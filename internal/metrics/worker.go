@@ -23,3 +23,8 @@ func JobFailed(jobType string) {
 func JobRetried(jobType string) {
 	JobRetriesTotal.WithLabelValues(jobType).Inc()
 }
+
+// JobPermanentlyFailed records a job that failed and will not be retried
+func JobPermanentlyFailed(jobType string) {
+	JobsPermanentlyFailedTotal.WithLabelValues(jobType).Inc()
+}
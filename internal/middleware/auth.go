@@ -38,8 +38,8 @@ func GetUser(ctx context.Context) *domain.User {
 type AuthMiddleware struct {
 	userService service.UserService
 	logger      *slog.Logger
-	isSecure    bool     // Whether to set Secure flag on cookies (true in production)
-	adminEmails []string // List of email addresses with admin access
+	cookie      session.Options // Session cookie settings
+	adminEmails []string        // List of email addresses with admin access
 }
 
 // NewAuthMiddleware creates a new AuthMiddleware instance.
@@ -52,7 +52,7 @@ func NewAuthMiddleware(userService service.UserService, logger *slog.Logger, isS
 	return &AuthMiddleware{
 		userService: userService,
 		logger:      logger,
-		isSecure:    isSecure,
+		cookie:      session.Options{Secure: isSecure},
 		adminEmails: []string{},
 	}
 }
@@ -89,30 +89,36 @@ func (m *AuthMiddleware) WithAdminEmails(emails []string) *AuthMiddleware {
 //	           |
 //	           +-> Read cookie
 //	           +-> Validate session (if cookie exists)
-//	           +-> Set user in context (if valid)
+//	           +-> Set user and session token in context (if valid)
 //	           +-> Call next handler (always)
 func (m *AuthMiddleware) WithUser(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Get session cookie
-		cookie, err := r.Cookie(session.CookieName)
-		if err != nil {
+		token, ok := session.Read(r)
+		if !ok {
 			// No cookie found - continue without user
 			next.ServeHTTP(w, r)
 			return
 		}
 
-		// Validate session and get user
-		user, err := m.userService.GetBySessionToken(r.Context(), cookie.Value)
+		// Validate session and get user, recording the client on the session
+		ctx := auth.SetSessionClient(r.Context(), domain.SessionClient{
+			UserAgent: r.UserAgent(),
+			IPAddress: getClientIP(r),
+		})
+		user, err := m.userService.GetBySessionToken(ctx, token)
 		if err != nil {
 			// Invalid or expired session - clear the cookie and continue
-			clearSessionCookie(w, m.isSecure)
+			session.Clear(w, m.cookie)
 			next.ServeHTTP(w, r)
 			return
 		}
 
-		// Set user in context
-		ctx := auth.SetUser(r.Context(), user)
+		// Set user and session token in context
+		ctx = auth.SetUser(ctx, user)
+		ctx = auth.SetSessionToken(ctx, token)
 		r = r.WithContext(ctx)
+		setLogUser(ctx, user.ID)
 
 		// Call next handler with user in context
 		next.ServeHTTP(w, r)
@@ -212,7 +218,7 @@ func (m *AuthMiddleware) RequireEmailVerified(next http.Handler) http.Handler {
 		user := GetUser(r.Context())
 		if user == nil {
 			// This shouldn't happen if RequireUser is used before this middleware
-			m.logger.Error("RequireEmailVerified called without user in context")
+			m.logger.ErrorContext(r.Context(), "RequireEmailVerified called without user in context")
 			if isAPIRequest(r) {
 				handler.UnauthorizedResponse(w, r, m.logger)
 			} else {
@@ -240,6 +246,61 @@ func (m *AuthMiddleware) RequireEmailVerified(next http.Handler) http.Handler {
 	})
 }
 
+// =============================================================================
+// RequireEmailVerifiedAfterGrace Middleware
+// =============================================================================
+
+// RequireEmailVerifiedAfterGrace is middleware for features that unverified
+// users may only use during the grace period after registration.
+//
+// Unlike RequireEmailVerified, the decision is delegated to
+// UserService.CheckEmailVerification, which knows the configured grace
+// period. Blocked users are sent to the verification reminder page:
+// - htmx requests get an HX-Redirect header (a 303 would be swapped into the target)
+// - API requests get 403 Forbidden
+// - HTML requests are redirected
+//
+// IMPORTANT: Use this AFTER RequireUser in the middleware chain.
+//
+// Usage:
+//
+//	requireVerified := middleware.Stack(authMw.WithUser, authMw.RequireUser, authMw.RequireEmailVerifiedAfterGrace)
+//	mux.Handle("POST /inspections/{id}/analyze", requireVerified(analyzeHandler))
+func (m *AuthMiddleware) RequireEmailVerifiedAfterGrace(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Get user from context (should exist because RequireUser ran first)
+		user := GetUser(r.Context())
+		if user == nil {
+			// This shouldn't happen if RequireUser is used before this middleware
+			m.logger.ErrorContext(r.Context(), "RequireEmailVerifiedAfterGrace called without user in context")
+			if isAPIRequest(r) {
+				handler.UnauthorizedResponse(w, r, m.logger)
+			} else {
+				http.Redirect(w, r, "/login", http.StatusSeeOther)
+			}
+			return
+		}
+
+		if err := m.userService.CheckEmailVerification(user); err != nil {
+			if isAPIRequest(r) {
+				handler.ErrorResponse(w, r, m.logger, err)
+				return
+			}
+
+			if r.Header.Get("HX-Request") == "true" {
+				w.Header().Set("HX-Redirect", "/verify-email-reminder")
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+
+			http.Redirect(w, r, "/verify-email-reminder", http.StatusSeeOther)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
 // =============================================================================
 // RequireActiveSubscription Middleware
 // =============================================================================
@@ -266,7 +327,7 @@ func (m *AuthMiddleware) RequireActiveSubscription(next http.Handler) http.Handl
 		user := GetUser(r.Context())
 		if user == nil {
 			// This shouldn't happen if RequireUser is used before this middleware
-			m.logger.Error("RequireActiveSubscription called without user in context")
+			m.logger.ErrorContext(r.Context(), "RequireActiveSubscription called without user in context")
 			if isAPIRequest(r) {
 				handler.UnauthorizedResponse(w, r, m.logger)
 			} else {
@@ -318,7 +379,7 @@ func (m *AuthMiddleware) RequireAdmin(next http.Handler) http.Handler {
 		user := GetUser(r.Context())
 		if user == nil {
 			// This shouldn't happen if RequireUser is used before this middleware
-			m.logger.Error("RequireAdmin called without user in context")
+			m.logger.ErrorContext(r.Context(), "RequireAdmin called without user in context")
 			if isAPIRequest(r) {
 				handler.UnauthorizedResponse(w, r, m.logger)
 			} else {
@@ -338,7 +399,7 @@ func (m *AuthMiddleware) RequireAdmin(next http.Handler) http.Handler {
 		}
 
 		if !isAdmin {
-			m.logger.Warn("non-admin user attempted to access admin route",
+			m.logger.WarnContext(r.Context(), "non-admin user attempted to access admin route",
 				"user_id", user.ID,
 				"user_email", user.Email,
 				"path", r.URL.Path)
@@ -361,56 +422,6 @@ func (m *AuthMiddleware) RequireAdmin(next http.Handler) http.Handler {
 	})
 }
 
-// =============================================================================
-// Cookie Helpers
-// =============================================================================
-
-// SetSessionCookie sets the session cookie on the response.
-//
-// Cookie Settings:
-// - HttpOnly: true - Prevents JavaScript access (XSS protection)
-// - Secure: configurable - Set true in production (HTTPS only)
-// - SameSite: Lax - Prevents CSRF while allowing normal navigation
-// - Path: / - Cookie sent with all requests
-// - MaxAge: 7 days - Matches session duration
-//
-// Parameters:
-// - w: Response writer to set cookie on
-// - token: Raw session token (64-char hex string)
-// - isSecure: Whether to set Secure flag (true in production)
-func SetSessionCookie(w http.ResponseWriter, token string, isSecure bool) {
-	http.SetCookie(w, &http.Cookie{
-		Name:     session.CookieName,
-		Value:    token,
-		Path:     session.CookiePath,
-		MaxAge:   session.CookieMaxAge,
-		HttpOnly: true,
-		Secure:   isSecure,
-		SameSite: http.SameSiteLaxMode,
-	})
-}
-
-// clearSessionCookie removes the session cookie from the client.
-//
-// This is done by setting MaxAge to -1, which tells the browser to delete
-// the cookie immediately.
-func clearSessionCookie(w http.ResponseWriter, isSecure bool) {
-	http.SetCookie(w, &http.Cookie{
-		Name:     session.CookieName,
-		Value:    "",
-		Path:     session.CookiePath,
-		MaxAge:   -1, // Delete immediately
-		HttpOnly: true,
-		Secure:   isSecure,
-		SameSite: http.SameSiteLaxMode,
-	})
-}
-
-// ClearSessionCookie is the exported version for use in logout handlers.
-func ClearSessionCookie(w http.ResponseWriter, isSecure bool) {
-	clearSessionCookie(w, isSecure)
-}
-
 // =============================================================================
 // Request Helpers
 // =============================================================================
@@ -443,7 +454,7 @@ func isAPIRequest(r *http.Request) bool {
 	}
 
 	// Check URL path (API routes)
-	if strings.HasPrefix(r.URL.Path, "/api/") {
+	if strings.HasPrefix(r.URL.Path, apiPathPrefix) {
 		return true
 	}
 
@@ -512,6 +523,7 @@ var (
 	_ func(http.Handler) http.Handler = (&AuthMiddleware{}).WithUser
 	_ func(http.Handler) http.Handler = (&AuthMiddleware{}).RequireUser
 	_ func(http.Handler) http.Handler = (&AuthMiddleware{}).RequireEmailVerified
+	_ func(http.Handler) http.Handler = (&AuthMiddleware{}).RequireEmailVerifiedAfterGrace
 	_ func(http.Handler) http.Handler = (&AuthMiddleware{}).RequireActiveSubscription
 	_ func(http.Handler) http.Handler = (&AuthMiddleware{}).RequireAdmin
 )
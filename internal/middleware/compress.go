@@ -0,0 +1,218 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// minCompressSize is the smallest response worth compressing. Smaller
+// responses are sent as they are, since gzip framing would outweigh savings.
+const minCompressSize = 1024
+
+// compressibleTypes are the non-text media types worth compressing. Every
+// text/* type is compressible; images, PDFs, and office documents are
+// already compressed and pass through untouched.
+var compressibleTypes = map[string]bool{
+	"application/json":          true,
+	"application/javascript":    true,
+	"application/xml":           true,
+	"application/manifest+json": true,
+	"application/problem+json":  true,
+	"image/svg+xml":             true,
+}
+
+var gzipWriterPool = sync.Pool{
+	New: func() any {
+		w, _ := gzip.NewWriterLevel(nil, gzip.DefaultCompression)
+		return w
+	},
+}
+
+// Compress returns middleware that gzips responses for clients that accept
+// it. A response is compressed only when its content type is text-like and
+// it is at least minCompressSize bytes, so image and report downloads keep
+// their Content-Length and bytes as written. Flushing (e.g. for streamed or
+// polled responses) flushes the compressed stream to the client.
+func Compress(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		// Range responses address bytes of the uncompressed body
+		if r.Method == http.MethodHead || r.Header.Get("Range") != "" || !acceptsGzip(r.Header.Get("Accept-Encoding")) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &compressWriter{ResponseWriter: w}
+		defer cw.close()
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip, either
+// by name or through a wildcard, with a non-zero quality.
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// isCompressible reports whether a Content-Type is worth compressing.
+func isCompressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(mediaType, "text/") || compressibleTypes[mediaType]
+}
+
+// compressWriter buffers the start of a response until it knows whether to
+// compress it: on reaching minCompressSize, on a flush, or when the handler
+// returns.
+type compressWriter struct {
+	http.ResponseWriter
+	status  int    // Status passed to WriteHeader, 0 if not yet called
+	buf     []byte // Body written before the decision
+	decided bool
+	gz      *gzip.Writer // Non-nil once compressing
+}
+
+// WriteHeader records the status. Responses that carry no body, or whose
+// Content-Length already settles the decision, are decided right away.
+func (cw *compressWriter) WriteHeader(code int) {
+	if cw.decided {
+		cw.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if code < http.StatusOK {
+		// Informational responses are sent through as they are
+		cw.ResponseWriter.WriteHeader(code)
+		return
+	}
+	cw.status = code
+
+	if code == http.StatusNoContent || code == http.StatusNotModified {
+		_ = cw.decide(false)
+		return
+	}
+	if length := cw.Header().Get("Content-Length"); length != "" {
+		n, err := strconv.Atoi(length)
+		_ = cw.decide(err == nil && n >= minCompressSize && cw.compressible())
+	}
+}
+
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	if !cw.decided && cw.status == 0 {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if !cw.decided {
+		cw.buf = append(cw.buf, p...)
+		if len(cw.buf) >= minCompressSize {
+			if err := cw.decide(cw.compressible()); err != nil {
+				return 0, err
+			}
+		}
+		return len(p), nil
+	}
+	if cw.gz != nil {
+		return cw.gz.Write(p)
+	}
+	return cw.ResponseWriter.Write(p)
+}
+
+// Flush sends everything written so far to the client, deciding on
+// compression first if the response is still buffered. A flushed response
+// is streaming, so its size so far doesn't count against compressing it.
+func (cw *compressWriter) Flush() {
+	if !cw.decided {
+		if cw.status == 0 {
+			cw.status = http.StatusOK
+		}
+		_ = cw.decide(cw.compressible())
+	}
+	if cw.gz != nil {
+		_ = cw.gz.Flush()
+	}
+	_ = http.NewResponseController(cw.ResponseWriter).Flush()
+}
+
+// Unwrap returns the underlying ResponseWriter for http.ResponseController.
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// compressible reports whether the response's content type is worth
+// compressing, sniffing it from the buffered body when the handler set none,
+// as net/http would.
+func (cw *compressWriter) compressible() bool {
+	h := cw.Header()
+	if h.Get("Content-Encoding") != "" {
+		return false
+	}
+	if _, ok := h["Content-Type"]; !ok && len(cw.buf) > 0 {
+		h.Set("Content-Type", http.DetectContentType(cw.buf))
+	}
+	return isCompressible(h.Get("Content-Type"))
+}
+
+// decide sends the headers, switching to gzip and weakening any strong ETag
+// when compress is true, and writes out the buffered body.
+func (cw *compressWriter) decide(compress bool) error {
+	cw.decided = true
+	if compress {
+		h := cw.Header()
+		h.Del("Content-Length")
+		h.Set("Content-Encoding", "gzip")
+		// The gzip body differs from the identity body, so it can't share a
+		// strong validator with it
+		if etag := h.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			h.Set("ETag", "W/"+etag)
+		}
+		cw.gz = gzipWriterPool.Get().(*gzip.Writer)
+		cw.gz.Reset(cw.ResponseWriter)
+	}
+	if cw.status != 0 {
+		cw.ResponseWriter.WriteHeader(cw.status)
+	}
+
+	buf := cw.buf
+	cw.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	var err error
+	if cw.gz != nil {
+		_, err = cw.gz.Write(buf)
+	} else {
+		_, err = cw.ResponseWriter.Write(buf)
+	}
+	return err
+}
+
+// close writes out a response still buffered when the handler returned,
+// which is below minCompressSize, and finishes the gzip stream.
+func (cw *compressWriter) close() {
+	if !cw.decided {
+		_ = cw.decide(false)
+	}
+	if cw.gz != nil {
+		_ = cw.gz.Close()
+		gzipWriterPool.Put(cw.gz)
+		cw.gz = nil
+	}
+}
@@ -0,0 +1,252 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/DukeRupert/lukaut/internal/templ/pages/regulations"
+)
+
+// =============================================================================
+// Compression Middleware Tests
+// =============================================================================
+
+// largeRegulationsPage renders a results list the size of a busy search page.
+func largeRegulationsPage(t testing.TB) []byte {
+	t.Helper()
+	regs := make([]regulations.RegulationDisplay, 200)
+	for i := range regs {
+		regs[i] = regulations.RegulationDisplay{
+			ID:              fmt.Sprintf("00000000-0000-0000-0000-%012d", i),
+			StandardNumber:  fmt.Sprintf("1926.%d(b)(1)", 500+i),
+			Title:           "Duty to have fall protection",
+			Category:        "Fall Protection",
+			Summary:         "Each employee on a walking/working surface with an unprotected side or edge six feet or more above a lower level shall be protected.",
+			SeverityTypical: "serious",
+		}
+	}
+	var buf bytes.Buffer
+	err := regulations.ResultsContent(regs, regulations.FilterData{}, regulations.PaginationData{CurrentPage: 1, TotalPages: 1, Total: len(regs)}, "").
+		Render(t.Context(), &buf)
+	if err != nil {
+		t.Fatalf("render page: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func gunzip(t testing.TB, data []byte) []byte {
+	t.Helper()
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("gzip reader: %v", err)
+	}
+	out, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("gunzip: %v", err)
+	}
+	return out
+}
+
+func serveCompressed(h http.Handler, acceptEncoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	rec := httptest.NewRecorder()
+	Compress(h).ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCompress_LargeTemplPage(t *testing.T) {
+	page := largeRegulationsPage(t)
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write(page)
+	})
+
+	rec := serveCompressed(h, "br, gzip, deflate")
+
+	if got := rec.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("expected gzip encoding, got %q", got)
+	}
+	if got := rec.Header().Get("Vary"); got != "Accept-Encoding" {
+		t.Errorf("expected Vary: Accept-Encoding, got %q", got)
+	}
+	if rec.Body.Len()*4 > len(page) {
+		t.Errorf("expected at least 4x compression of %d bytes, got %d", len(page), rec.Body.Len())
+	}
+	if !bytes.Equal(gunzip(t, rec.Body.Bytes()), page) {
+		t.Error("decompressed body differs from the rendered page")
+	}
+}
+
+func TestCompress_ThumbnailPassesThrough(t *testing.T) {
+	thumbnail := append([]byte{0xFF, 0xD8, 0xFF, 0xE0}, bytes.Repeat([]byte{0x42}, 8192)...)
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Header().Set("Content-Length", strconv.Itoa(len(thumbnail)))
+		_, _ = w.Write(thumbnail)
+	})
+
+	rec := serveCompressed(h, "gzip")
+
+	if got := rec.Header().Get("Content-Encoding"); got != "" {
+		t.Errorf("expected no encoding for an image, got %q", got)
+	}
+	if got := rec.Header().Get("Content-Length"); got != strconv.Itoa(len(thumbnail)) {
+		t.Errorf("expected Content-Length %d to be kept, got %q", len(thumbnail), got)
+	}
+	if !bytes.Equal(rec.Body.Bytes(), thumbnail) {
+		t.Error("expected thumbnail bytes unchanged")
+	}
+}
+
+func TestCompress_DropsContentLengthWhenCompressing(t *testing.T) {
+	body := bytes.Repeat([]byte("severity,confirmed\n"), 200)
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		_, _ = w.Write(body)
+	})
+
+	rec := serveCompressed(h, "gzip")
+
+	if got := rec.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("expected gzip encoding, got %q", got)
+	}
+	if got := rec.Header().Get("Content-Length"); got != "" {
+		t.Errorf("expected the uncompressed Content-Length to be dropped, got %q", got)
+	}
+	if !bytes.Equal(gunzip(t, rec.Body.Bytes()), body) {
+		t.Error("decompressed body differs")
+	}
+}
+
+func TestCompress_PassesThrough(t *testing.T) {
+	page := []byte("<p>small</p>")
+	large := bytes.Repeat([]byte("<p>row</p>"), 500)
+
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		body           []byte
+	}{
+		{name: "no accept-encoding", body: large},
+		{name: "gzip refused", acceptEncoding: "gzip;q=0, br", body: large},
+		{name: "below minimum size", acceptEncoding: "gzip", body: page},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/html; charset=utf-8")
+				_, _ = w.Write(tt.body)
+			})
+
+			rec := serveCompressed(h, tt.acceptEncoding)
+
+			if got := rec.Header().Get("Content-Encoding"); got != "" {
+				t.Errorf("expected no encoding, got %q", got)
+			}
+			if !bytes.Equal(rec.Body.Bytes(), tt.body) {
+				t.Error("expected body unchanged")
+			}
+		})
+	}
+}
+
+func TestCompress_FlushStreamsCompressedChunks(t *testing.T) {
+	chunk := []byte("data: inspection analysis started\n\n")
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write(chunk)
+		if err := http.NewResponseController(w).Flush(); err != nil {
+			t.Errorf("flush: %v", err)
+		}
+
+		// The first chunk must already be readable by the client
+		rec := w.(*compressWriter).ResponseWriter.(*httptest.ResponseRecorder)
+		if !rec.Flushed {
+			t.Error("expected the flush to reach the client")
+		}
+		zr, err := gzip.NewReader(bytes.NewReader(rec.Body.Bytes()))
+		if err != nil {
+			t.Fatalf("gzip reader: %v", err)
+		}
+		got := make([]byte, len(chunk))
+		if _, err := io.ReadFull(zr, got); err != nil || !bytes.Equal(got, chunk) {
+			t.Errorf("expected flushed chunk %q, got %q (%v)", chunk, got, err)
+		}
+
+		_, _ = w.Write(chunk)
+	})
+
+	rec := serveCompressed(h, "gzip")
+
+	if got := rec.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("expected gzip encoding, got %q", got)
+	}
+	if got := gunzip(t, rec.Body.Bytes()); !bytes.Equal(got, append(chunk, chunk...)) {
+		t.Errorf("unexpected stream %q", got)
+	}
+}
+
+func TestCompress_NoContentKeepsStatus(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	rec := serveCompressed(h, "gzip")
+
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("expected status 204, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Content-Encoding"); got != "" {
+		t.Errorf("expected no encoding, got %q", got)
+	}
+}
+
+func BenchmarkCompress_LargeTemplPage(b *testing.B) {
+	page := largeRegulationsPage(b)
+	h := Compress(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write(page)
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	b.SetBytes(int64(len(page)))
+	b.ReportAllocs()
+	for b.Loop() {
+		h.ServeHTTP(httptest.NewRecorder(), req)
+	}
+}
+
+func TestCompress_WeakensETagWhenCompressing(t *testing.T) {
+	page := largeRegulationsPage(t)
+	handler := func(etag string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Header().Set("ETag", etag)
+			_, _ = w.Write(page)
+		})
+	}
+
+	// The gzip body gets a weak validator; the identity body keeps its strong one
+	if got := serveCompressed(handler(`"abc123"`), "gzip").Header().Get("ETag"); got != `W/"abc123"` {
+		t.Errorf("expected weakened ETag on gzip response, got %q", got)
+	}
+	if got := serveCompressed(handler(`"abc123"`), "").Header().Get("ETag"); got != `"abc123"` {
+		t.Errorf("expected strong ETag on identity response, got %q", got)
+	}
+	// Already weak tags are left alone
+	if got := serveCompressed(handler(`W/"abc123"`), "gzip").Header().Get("ETag"); got != `W/"abc123"` {
+		t.Errorf("expected weak ETag to be kept, got %q", got)
+	}
+}
@@ -0,0 +1,170 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// apiPathPrefix is the URL prefix of the JSON API routes.
+const apiPathPrefix = "/api/"
+
+// Defaults used when CORSConfig leaves methods or headers empty.
+var (
+	DefaultCORSMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+	DefaultCORSHeaders = []string{"Content-Type", "Authorization"}
+)
+
+// DefaultCORSMaxAge is how long browsers may cache a preflight response.
+const DefaultCORSMaxAge = 10 * time.Minute
+
+// CORSConfig configures cross-origin access to the JSON API.
+type CORSConfig struct {
+	AllowedOrigins []string      // Exact origins (e.g., "https://app.example.com"), or "*" for any
+	AllowedMethods []string      // Methods allowed in preflight (default: DefaultCORSMethods)
+	AllowedHeaders []string      // Request headers allowed in preflight (default: DefaultCORSHeaders)
+	MaxAge         time.Duration // Preflight cache lifetime (default: DefaultCORSMaxAge)
+}
+
+// CORS adds Cross-Origin Resource Sharing headers to JSON API responses.
+//
+// Only requests under /api/ are affected; HTML pages stay same-origin. With
+// no allowed origins configured the middleware does nothing, so browsers
+// enforce the same-origin policy.
+//
+// Credentials are never allowed: cross-origin clients cannot use the session
+// cookie.
+type CORS struct {
+	origins    map[string]struct{}
+	anyOrigin  bool
+	methods    []string
+	headers    []string
+	allowedHdr map[string]struct{} // Lowercased AllowedHeaders for lookups
+	maxAge     string
+}
+
+// NewCORS creates a CORS middleware from cfg.
+func NewCORS(cfg CORSConfig) *CORS {
+	m := &CORS{
+		origins:    make(map[string]struct{}),
+		methods:    cfg.AllowedMethods,
+		headers:    cfg.AllowedHeaders,
+		allowedHdr: make(map[string]struct{}),
+	}
+	for _, origin := range cfg.AllowedOrigins {
+		if origin == "*" {
+			m.anyOrigin = true
+			continue
+		}
+		m.origins[strings.TrimRight(origin, "/")] = struct{}{}
+	}
+	if len(m.methods) == 0 {
+		m.methods = DefaultCORSMethods
+	}
+	if len(m.headers) == 0 {
+		m.headers = DefaultCORSHeaders
+	}
+	for _, h := range m.headers {
+		m.allowedHdr[strings.ToLower(h)] = struct{}{}
+	}
+	maxAge := cfg.MaxAge
+	if maxAge <= 0 {
+		maxAge = DefaultCORSMaxAge
+	}
+	m.maxAge = strconv.Itoa(int(maxAge.Seconds()))
+	return m
+}
+
+// Enabled reports whether any origin is allowed.
+func (m *CORS) Enabled() bool {
+	return m.anyOrigin || len(m.origins) > 0
+}
+
+// Handler returns middleware that answers preflight requests and adds CORS
+// headers to API responses for allowed origins.
+//
+// Preflight requests (OPTIONS with Access-Control-Request-Method) are
+// answered here with 204, or 403 if the origin, method, or a requested
+// header is not allowed. Other requests from disallowed origins are served
+// without CORS headers, so the browser withholds the response.
+func (m *CORS) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !m.Enabled() || !strings.HasPrefix(r.URL.Path, apiPathPrefix) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Responses differ by origin, so caches must key on it
+		w.Header().Add("Vary", "Origin")
+
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			m.preflight(w, r, origin)
+			return
+		}
+
+		if m.originAllowed(origin) {
+			w.Header().Set("Access-Control-Allow-Origin", m.allowOriginValue(origin))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// preflight answers a CORS preflight request.
+func (m *CORS) preflight(w http.ResponseWriter, r *http.Request, origin string) {
+	w.Header().Add("Vary", "Access-Control-Request-Method")
+	w.Header().Add("Vary", "Access-Control-Request-Headers")
+
+	if !m.originAllowed(origin) ||
+		!slices.Contains(m.methods, r.Header.Get("Access-Control-Request-Method")) ||
+		!m.headersAllowed(r.Header.Get("Access-Control-Request-Headers")) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", m.allowOriginValue(origin))
+	w.Header().Set("Access-Control-Allow-Methods", strings.Join(m.methods, ", "))
+	w.Header().Set("Access-Control-Allow-Headers", strings.Join(m.headers, ", "))
+	w.Header().Set("Access-Control-Max-Age", m.maxAge)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *CORS) originAllowed(origin string) bool {
+	if m.anyOrigin {
+		return true
+	}
+	_, ok := m.origins[origin]
+	return ok
+}
+
+// allowOriginValue returns the Access-Control-Allow-Origin value for an
+// allowed origin.
+func (m *CORS) allowOriginValue(origin string) string {
+	if m.anyOrigin {
+		return "*"
+	}
+	return origin
+}
+
+// headersAllowed reports whether every header in a comma-separated
+// Access-Control-Request-Headers value is allowed. Header names are
+// case-insensitive.
+func (m *CORS) headersAllowed(requested string) bool {
+	for _, h := range strings.Split(requested, ",") {
+		h = strings.ToLower(strings.TrimSpace(h))
+		if h == "" {
+			continue
+		}
+		if _, ok := m.allowedHdr[h]; !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,180 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// =============================================================================
+// CORS Middleware Tests
+// =============================================================================
+
+func newTestCORS(origins ...string) http.Handler {
+	mw := NewCORS(CORSConfig{
+		AllowedOrigins: origins,
+		AllowedMethods: []string{http.MethodGet, http.MethodPost},
+		AllowedHeaders: []string{"Content-Type", "X-Request-ID"},
+		MaxAge:         time.Hour,
+	})
+	return mw.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+func newPreflight(path, origin, method, headers string) *http.Request {
+	req := httptest.NewRequest(http.MethodOptions, path, nil)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", method)
+	if headers != "" {
+		req.Header.Set("Access-Control-Request-Headers", headers)
+	}
+	return req
+}
+
+func TestCORS_PreflightAllowed(t *testing.T) {
+	handler := newTestCORS("https://app.example.com")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, newPreflight("/api/inspections", "https://app.example.com", "POST", "content-type, x-request-id"))
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", rec.Code)
+	}
+	tests := []struct {
+		header   string
+		expected string
+	}{
+		{"Access-Control-Allow-Origin", "https://app.example.com"},
+		{"Access-Control-Allow-Methods", "GET, POST"},
+		{"Access-Control-Allow-Headers", "Content-Type, X-Request-ID"},
+		{"Access-Control-Max-Age", "3600"},
+	}
+	for _, tc := range tests {
+		if got := rec.Header().Get(tc.header); got != tc.expected {
+			t.Errorf("%s: expected %q, got %q", tc.header, tc.expected, got)
+		}
+	}
+	if rec.Header().Get("Access-Control-Allow-Credentials") != "" {
+		t.Error("expected credentials not to be allowed")
+	}
+}
+
+func TestCORS_PreflightRejected(t *testing.T) {
+	handler := newTestCORS("https://app.example.com")
+
+	tests := []struct {
+		name    string
+		origin  string
+		method  string
+		headers string
+	}{
+		{"unknown origin", "https://evil.example.com", "GET", ""},
+		{"method not allowed", "https://app.example.com", "DELETE", ""},
+		{"header not allowed", "https://app.example.com", "POST", "Content-Type, X-Secret"},
+		{"origin prefix", "https://app.example.com.evil.com", "GET", ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, newPreflight("/api/inspections", tc.origin, tc.method, tc.headers))
+
+			if rec.Code != http.StatusForbidden {
+				t.Errorf("expected 403, got %d", rec.Code)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+				t.Errorf("expected no Access-Control-Allow-Origin, got %q", got)
+			}
+		})
+	}
+}
+
+func TestCORS_ActualRequestOrigins(t *testing.T) {
+	handler := newTestCORS("https://app.example.com/")
+
+	tests := []struct {
+		origin   string
+		expected string
+	}{
+		{"https://app.example.com", "https://app.example.com"},
+		{"http://app.example.com", ""},
+		{"https://evil.example.com", ""},
+	}
+	for _, tc := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/api/inspections", nil)
+		req.Header.Set("Origin", tc.origin)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s: expected request to reach the handler, got %d", tc.origin, rec.Code)
+		}
+		if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tc.expected {
+			t.Errorf("%s: expected Access-Control-Allow-Origin %q, got %q", tc.origin, tc.expected, got)
+		}
+		if rec.Header().Get("Vary") != "Origin" {
+			t.Errorf("%s: expected Vary: Origin, got %q", tc.origin, rec.Header().Get("Vary"))
+		}
+	}
+}
+
+func TestCORS_AnyOrigin(t *testing.T) {
+	handler := newTestCORS("*")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, newPreflight("/api/inspections", "https://anywhere.example", "GET", ""))
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("expected wildcard origin, got %q", got)
+	}
+}
+
+func TestCORS_DisabledByDefault(t *testing.T) {
+	handler := newTestCORS()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, newPreflight("/api/inspections", "https://app.example.com", "GET", ""))
+
+	// Preflight falls through to the routes, and no CORS headers are added
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected preflight to pass through, got %d", rec.Code)
+	}
+	for _, h := range []string{"Access-Control-Allow-Origin", "Access-Control-Allow-Methods", "Vary"} {
+		if got := rec.Header().Get(h); got != "" {
+			t.Errorf("expected no %s without configured origins, got %q", h, got)
+		}
+	}
+}
+
+func TestCORS_OnlyAPIRoutes(t *testing.T) {
+	handler := newTestCORS("https://app.example.com")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, newPreflight("/inspections", "https://app.example.com", "GET", ""))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected non-API preflight to pass through, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("expected no CORS headers on HTML routes, got %q", got)
+	}
+}
+
+func TestCORS_Defaults(t *testing.T) {
+	mw := NewCORS(CORSConfig{AllowedOrigins: []string{"https://app.example.com"}})
+	handler := mw.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, newPreflight("/api/inspections", "https://app.example.com", "PATCH", "Authorization"))
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Access-Control-Max-Age"); got != "600" {
+		t.Errorf("expected default max age 600, got %q", got)
+	}
+}
@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// InFlightTracker counts requests currently being served so shutdown can
+// wait for them to finish (e.g. long uploads or report previews) before the
+// process exits.
+type InFlightTracker struct {
+	mu     sync.Mutex
+	active int
+	idle   chan struct{} // closed whenever active is zero
+}
+
+// NewInFlightTracker creates a tracker with no requests in flight.
+func NewInFlightTracker() *InFlightTracker {
+	idle := make(chan struct{})
+	close(idle)
+	return &InFlightTracker{idle: idle}
+}
+
+// Handler returns middleware that tracks each request until its handler returns.
+func (t *InFlightTracker) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.start()
+		defer t.done()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// InFlight returns the number of requests currently being served.
+func (t *InFlightTracker) InFlight() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.active
+}
+
+// Wait blocks until no requests are in flight or ctx is done, in which
+// case it returns ctx.Err(). Callers that need to report how many requests
+// drained should note InFlight before stopping the server.
+func (t *InFlightTracker) Wait(ctx context.Context) error {
+	t.mu.Lock()
+	idle := t.idle
+	t.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (t *InFlightTracker) start() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.active == 0 {
+		t.idle = make(chan struct{})
+	}
+	t.active++
+}
+
+func (t *InFlightTracker) done() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.active--
+	if t.active == 0 {
+		close(t.idle)
+	}
+}
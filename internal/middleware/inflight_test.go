@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// =============================================================================
+// In-Flight Tracker Tests
+// =============================================================================
+
+func TestInFlightTracker_ShutdownWaitsForSlowRequest(t *testing.T) {
+	tracker := NewInFlightTracker()
+
+	started := make(chan struct{})
+	var completed atomic.Bool
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(200 * time.Millisecond)
+		completed.Store(true)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	server := httptest.NewServer(tracker.Handler(slow))
+	defer server.Close()
+
+	// Issue a request and wait until the handler is running
+	respErr := make(chan error, 1)
+	go func() {
+		resp, err := http.Get(server.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		respErr <- err
+	}()
+	<-started
+
+	if got := tracker.InFlight(); got != 1 {
+		t.Fatalf("expected 1 request in flight, got %d", got)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := server.Config.Shutdown(ctx); err != nil {
+		t.Fatalf("unexpected shutdown error: %v", err)
+	}
+	if err := tracker.Wait(ctx); err != nil {
+		t.Fatalf("unexpected wait error: %v", err)
+	}
+
+	if !completed.Load() {
+		t.Error("expected shutdown to wait for the slow handler to finish")
+	}
+	if got := tracker.InFlight(); got != 0 {
+		t.Errorf("expected no requests in flight after draining, got %d", got)
+	}
+	if err := <-respErr; err != nil {
+		t.Errorf("expected slow request to complete successfully, got %v", err)
+	}
+}
+
+func TestInFlightTracker_WaitGivesUpAtDeadline(t *testing.T) {
+	tracker := NewInFlightTracker()
+
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan struct{})
+	stuck := tracker.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	}))
+
+	go stuck.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/upload", nil))
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := tracker.Wait(ctx)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("expected Wait to return at the deadline")
+	}
+	if got := tracker.InFlight(); got != 1 {
+		t.Errorf("expected stuck request still in flight, got %d", got)
+	}
+}
+
+func TestInFlightTracker_WaitReturnsImmediatelyWhenIdle(t *testing.T) {
+	tracker := NewInFlightTracker()
+
+	// Run a request to completion so the tracker has been used
+	tracker.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).
+		ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	if err := tracker.Wait(context.Background()); err != nil {
+		t.Errorf("expected no error when idle, got %v", err)
+	}
+}
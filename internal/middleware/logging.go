@@ -1,25 +1,72 @@
 package middleware
 
 import (
+	"bufio"
+	"context"
 	"log/slog"
+	"math/rand/v2"
+	"net"
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/google/uuid"
 )
 
-// RequestLoggingMiddleware logs HTTP requests with timing and status information.
+// DefaultSlowRequestThreshold is how long a request may take before it is
+// logged as slow.
+const DefaultSlowRequestThreshold = time.Second
+
+// RequestLoggingMiddleware writes an access log line for each request: the
+// method, route pattern, path, status, bytes written, duration, and the
+// signed-in user's ID. The request ID is added by requestid.LogHandler.
+//
+// Server errors are logged at Warn; client errors, redirects, and requests
+// slower than the slow threshold at Info; everything else at Debug. A sample
+// rate below 1 logs only that fraction of the Debug lines, for busy
+// deployments running at debug level. Health checks, metrics scrapes, and
+// static assets are never logged.
 type RequestLoggingMiddleware struct {
-	logger *slog.Logger
+	logger        *slog.Logger
+	routes        *http.ServeMux // Resolves route patterns; nil logs none
+	slowThreshold time.Duration
+	sampleRate    float64
 }
 
-// NewRequestLoggingMiddleware creates a new request logging middleware.
+// NewRequestLoggingMiddleware creates a new request logging middleware that
+// logs every request, with DefaultSlowRequestThreshold.
 func NewRequestLoggingMiddleware(logger *slog.Logger) *RequestLoggingMiddleware {
 	return &RequestLoggingMiddleware{
-		logger: logger,
+		logger:        logger,
+		slowThreshold: DefaultSlowRequestThreshold,
+		sampleRate:    1,
+	}
+}
+
+// WithRoutes logs each request's route pattern (e.g. "GET /images/{id}")
+// as mux resolves it, so requests for the same route can be grouped.
+func (m *RequestLoggingMiddleware) WithRoutes(mux *http.ServeMux) *RequestLoggingMiddleware {
+	m.routes = mux
+	return m
+}
+
+// WithSlowThreshold sets how long a request may take before it is logged at
+// Info as slow. Zero or less keeps DefaultSlowRequestThreshold.
+func (m *RequestLoggingMiddleware) WithSlowThreshold(threshold time.Duration) *RequestLoggingMiddleware {
+	if threshold > 0 {
+		m.slowThreshold = threshold
 	}
+	return m
 }
 
-// Handler returns middleware that logs all HTTP requests.
+// WithSampleRate sets the fraction (0-1) of fast, successful requests that
+// are logged. Errors, redirects, and slow requests are always logged.
+func (m *RequestLoggingMiddleware) WithSampleRate(rate float64) *RequestLoggingMiddleware {
+	m.sampleRate = min(max(rate, 0), 1)
+	return m
+}
+
+// Handler returns middleware that logs HTTP requests.
 func (m *RequestLoggingMiddleware) Handler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Skip logging for noisy endpoints
@@ -30,7 +77,19 @@ func (m *RequestLoggingMiddleware) Handler(next http.Handler) http.Handler {
 
 		start := time.Now()
 
-		// Wrap response writer to capture status code
+		// The route pattern is resolved up front, as handlers only see the
+		// pattern on their own copy of the request
+		var route string
+		if m.routes != nil {
+			_, route = m.routes.Handler(r)
+		}
+
+		// Authentication middleware runs inside the mux, so it records the
+		// user on the entry rather than in a context this handler can see
+		entry := &requestLogEntry{}
+		r = r.WithContext(context.WithValue(r.Context(), requestLogEntryKey{}, entry))
+
+		// Wrap response writer to capture status code and size
 		wrapped := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
 
 		// Process request
@@ -38,9 +97,22 @@ func (m *RequestLoggingMiddleware) Handler(next http.Handler) http.Handler {
 
 		// Calculate duration
 		duration := time.Since(start)
+		slow := duration >= m.slowThreshold
 
-		// Get client IP
-		clientIP := getClientIP(r)
+		// Choose the level; fast successful requests are sampled
+		level := slog.LevelDebug
+		switch {
+		case wrapped.statusCode >= 500:
+			level = slog.LevelWarn
+		case wrapped.statusCode >= 300 || wrapped.statusCode < 200 || slow:
+			level = slog.LevelInfo
+		}
+		if !m.logger.Enabled(r.Context(), level) {
+			return
+		}
+		if level == slog.LevelDebug && m.sampleRate < 1 && rand.Float64() >= m.sampleRate {
+			return
+		}
 
 		// Sanitize path to remove sensitive query params
 		safePath := sanitizePath(r.URL.Path, r.URL.RawQuery)
@@ -50,17 +122,22 @@ func (m *RequestLoggingMiddleware) Handler(next http.Handler) http.Handler {
 			"method", r.Method,
 			"path", safePath,
 			"status", wrapped.statusCode,
+			"bytes", wrapped.bytes,
 			"duration_ms", duration.Milliseconds(),
-			"ip", clientIP,
+			"ip", getClientIP(r),
 			"user_agent", r.UserAgent(),
 		}
-
-		// Log at appropriate level based on status code
-		if wrapped.statusCode >= 500 {
-			m.logger.Warn("request", attrs...)
-		} else {
-			m.logger.Info("request", attrs...)
+		if route != "" {
+			attrs = append(attrs, "route", route)
 		}
+		if entry.userID != uuid.Nil {
+			attrs = append(attrs, "user_id", entry.userID)
+		}
+		if slow {
+			attrs = append(attrs, "slow", true)
+		}
+
+		m.logger.Log(r.Context(), level, "request", attrs...)
 	})
 }
 
@@ -81,17 +158,65 @@ func (m *RequestLoggingMiddleware) shouldSkip(path string) bool {
 	return false
 }
 
-// responseWriter wraps http.ResponseWriter to capture the status code.
+// requestLogEntry collects details for a request's access log line that are
+// only known inside the handler chain.
+type requestLogEntry struct {
+	userID uuid.UUID
+}
+
+type requestLogEntryKey struct{}
+
+// setLogUser records the signed-in user on the request's access log entry,
+// if the request is being logged.
+func setLogUser(ctx context.Context, userID uuid.UUID) {
+	if entry, ok := ctx.Value(requestLogEntryKey{}).(*requestLogEntry); ok {
+		entry.userID = userID
+	}
+}
+
+// responseWriter wraps http.ResponseWriter to capture the status code and
+// the number of body bytes written. It keeps the Flusher and Hijacker
+// interfaces of the writer it wraps, for streamed and upgraded responses.
 type responseWriter struct {
 	http.ResponseWriter
-	statusCode int
+	statusCode  int
+	bytes       int64
+	wroteHeader bool
 }
 
 func (rw *responseWriter) WriteHeader(code int) {
-	rw.statusCode = code
+	// Informational responses precede the real status
+	if !rw.wroteHeader && code >= 200 {
+		rw.statusCode = code
+		rw.wroteHeader = true
+	}
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+func (rw *responseWriter) Write(p []byte) (int, error) {
+	rw.wroteHeader = true
+	n, err := rw.ResponseWriter.Write(p)
+	rw.bytes += int64(n)
+	return n, err
+}
+
+// Flush implements http.Flusher.
+func (rw *responseWriter) Flush() {
+	rw.wroteHeader = true
+	_ = http.NewResponseController(rw.ResponseWriter).Flush()
+}
+
+// Hijack implements http.Hijacker, failing if the wrapped writer can't.
+func (rw *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(rw.ResponseWriter).Hijack()
+}
+
+// Unwrap returns the underlying ResponseWriter so http.ResponseController
+// can reach its Flush.
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // sanitizePath removes sensitive query parameters from the path for logging.
 func sanitizePath(path, rawQuery string) string {
 	if rawQuery == "" {
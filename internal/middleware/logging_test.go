@@ -7,15 +7,24 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 // =============================================================================
 // Request Logging Middleware Tests
 // =============================================================================
 
+// newDebugLogger returns a text logger writing to buf at debug level, where
+// fast successful requests are logged.
+func newDebugLogger(buf *bytes.Buffer) *slog.Logger {
+	return slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+}
+
 func TestRequestLoggingMiddleware_LogsBasicInfo(t *testing.T) {
 	var buf bytes.Buffer
-	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	logger := newDebugLogger(&buf)
 
 	mw := NewRequestLoggingMiddleware(logger)
 
@@ -56,7 +65,7 @@ func TestRequestLoggingMiddleware_LogsBasicInfo(t *testing.T) {
 
 func TestRequestLoggingMiddleware_LogsClientIP(t *testing.T) {
 	var buf bytes.Buffer
-	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	logger := newDebugLogger(&buf)
 
 	mw := NewRequestLoggingMiddleware(logger)
 
@@ -83,7 +92,7 @@ func TestRequestLoggingMiddleware_LogsClientIP(t *testing.T) {
 
 func TestRequestLoggingMiddleware_LogsErrorStatus(t *testing.T) {
 	var buf bytes.Buffer
-	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	logger := newDebugLogger(&buf)
 
 	mw := NewRequestLoggingMiddleware(logger)
 
@@ -114,7 +123,7 @@ func TestRequestLoggingMiddleware_LogsErrorStatus(t *testing.T) {
 
 func TestRequestLoggingMiddleware_LogsUserAgent(t *testing.T) {
 	var buf bytes.Buffer
-	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	logger := newDebugLogger(&buf)
 
 	mw := NewRequestLoggingMiddleware(logger)
 
@@ -141,7 +150,7 @@ func TestRequestLoggingMiddleware_LogsUserAgent(t *testing.T) {
 
 func TestRequestLoggingMiddleware_DoesNotLogSensitiveQueryParams(t *testing.T) {
 	var buf bytes.Buffer
-	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	logger := newDebugLogger(&buf)
 
 	mw := NewRequestLoggingMiddleware(logger)
 
@@ -173,7 +182,7 @@ func TestRequestLoggingMiddleware_DoesNotLogSensitiveQueryParams(t *testing.T) {
 
 func TestRequestLoggingMiddleware_DoesNotLogPasswordResetToken(t *testing.T) {
 	var buf bytes.Buffer
-	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	logger := newDebugLogger(&buf)
 
 	mw := NewRequestLoggingMiddleware(logger)
 
@@ -199,7 +208,7 @@ func TestRequestLoggingMiddleware_DoesNotLogPasswordResetToken(t *testing.T) {
 
 func TestRequestLoggingMiddleware_PassesRequestThrough(t *testing.T) {
 	var buf bytes.Buffer
-	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	logger := newDebugLogger(&buf)
 
 	mw := NewRequestLoggingMiddleware(logger)
 
@@ -238,7 +247,7 @@ func TestRequestLoggingMiddleware_PassesRequestThrough(t *testing.T) {
 
 func TestRequestLoggingMiddleware_CapturesWrittenStatus(t *testing.T) {
 	var buf bytes.Buffer
-	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	logger := newDebugLogger(&buf)
 
 	mw := NewRequestLoggingMiddleware(logger)
 
@@ -264,7 +273,7 @@ func TestRequestLoggingMiddleware_CapturesWrittenStatus(t *testing.T) {
 
 func TestRequestLoggingMiddleware_ExcludesHealthCheck(t *testing.T) {
 	var buf bytes.Buffer
-	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	logger := newDebugLogger(&buf)
 
 	mw := NewRequestLoggingMiddleware(logger)
 
@@ -291,7 +300,7 @@ func TestRequestLoggingMiddleware_ExcludesHealthCheck(t *testing.T) {
 
 func TestRequestLoggingMiddleware_ExcludesMetrics(t *testing.T) {
 	var buf bytes.Buffer
-	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	logger := newDebugLogger(&buf)
 
 	mw := NewRequestLoggingMiddleware(logger)
 
@@ -314,3 +323,115 @@ func TestRequestLoggingMiddleware_ExcludesMetrics(t *testing.T) {
 		t.Errorf("metrics endpoint should not be logged, got: %s", logOutput)
 	}
 }
+
+func TestRequestLoggingMiddleware_LevelsByStatusAndDuration(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		delay     time.Duration
+		wantLevel string
+		wantSlow  bool
+	}{
+		{"fast success", http.StatusOK, 0, "level=DEBUG", false},
+		{"client error", http.StatusNotFound, 0, "level=INFO", false},
+		{"redirect", http.StatusSeeOther, 0, "level=INFO", false},
+		{"server error", http.StatusInternalServerError, 0, "level=WARN", false},
+		{"slow success", http.StatusOK, 20 * time.Millisecond, "level=INFO", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			mw := NewRequestLoggingMiddleware(newDebugLogger(&buf)).WithSlowThreshold(10 * time.Millisecond)
+			handler := mw.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(tt.delay)
+				w.WriteHeader(tt.status)
+			}))
+
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/dashboard", nil))
+
+			logOutput := buf.String()
+			if !strings.Contains(logOutput, tt.wantLevel) {
+				t.Errorf("expected %s, got: %s", tt.wantLevel, logOutput)
+			}
+			if got := strings.Contains(logOutput, "slow=true"); got != tt.wantSlow {
+				t.Errorf("expected slow=%t, got: %s", tt.wantSlow, logOutput)
+			}
+		})
+	}
+}
+
+func TestRequestLoggingMiddleware_CapturesStatusBytesRouteAndUser(t *testing.T) {
+	var buf bytes.Buffer
+	userID := uuid.New()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /images/{id}", func(w http.ResponseWriter, r *http.Request) {
+		setLogUser(r.Context(), userID)
+		w.WriteHeader(http.StatusAccepted)
+		w.WriteHeader(http.StatusInternalServerError) // Superfluous; not what the client got
+		_, _ = w.Write([]byte("hello"))
+	})
+	handler := NewRequestLoggingMiddleware(newDebugLogger(&buf)).WithRoutes(mux).Handler(mux)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/images/42", nil))
+
+	logOutput := buf.String()
+	for _, want := range []string{
+		"status=202",
+		"bytes=5",
+		`route="GET /images/{id}"`,
+		"user_id=" + userID.String(),
+	} {
+		if !strings.Contains(logOutput, want) {
+			t.Errorf("expected %s in the log, got: %s", want, logOutput)
+		}
+	}
+}
+
+func TestRequestLoggingMiddleware_KeepsFlusherAndHijacker(t *testing.T) {
+	var flusher, hijacker bool
+	handler := NewRequestLoggingMiddleware(newDebugLogger(&bytes.Buffer{})).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, flusher = w.(http.Flusher)
+		_, hijacker = w.(http.Hijacker)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/events", nil))
+
+	if !flusher || !hijacker {
+		t.Errorf("expected the wrapped writer to be a Flusher and Hijacker, got %t and %t", flusher, hijacker)
+	}
+}
+
+func TestRequestLoggingMiddleware_SampleRate(t *testing.T) {
+	var buf bytes.Buffer
+	mw := NewRequestLoggingMiddleware(newDebugLogger(&buf)).WithSampleRate(0)
+	handler := mw.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	for range 10 {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/dashboard", nil))
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected successful requests sampled out, got: %s", buf.String())
+	}
+
+	// Errors are always logged
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/missing", nil))
+	if !strings.Contains(buf.String(), "status=404") {
+		t.Errorf("expected the 404 logged despite sampling, got: %s", buf.String())
+	}
+
+	// A rate of 1 logs every request
+	buf.Reset()
+	mw.WithSampleRate(1)
+	for range 10 {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/dashboard", nil))
+	}
+	if n := strings.Count(buf.String(), "msg=request"); n != 10 {
+		t.Errorf("expected 10 requests logged, got %d", n)
+	}
+}
@@ -174,7 +174,7 @@ func (m *RateLimitMiddleware) Limit(next http.Handler) http.Handler {
 		clientIP := getClientIP(r)
 
 		if !m.limiter.Allow(clientIP) {
-			m.logger.Warn("rate limit exceeded",
+			m.logger.WarnContext(r.Context(), "rate limit exceeded",
 				"ip", clientIP,
 				"path", r.URL.Path,
 				"method", r.Method,
@@ -222,6 +222,7 @@ type AuthRateLimiter struct {
 	loginLimiter         *RateLimiter
 	registerLimiter      *RateLimiter
 	passwordResetLimiter *RateLimiter
+	resendLimiter        *RateLimiter
 	logger               *slog.Logger
 }
 
@@ -229,11 +230,13 @@ type AuthRateLimiter struct {
 // - Login: 5 attempts per 15 minutes
 // - Register: 3 attempts per hour
 // - Password reset: 3 attempts per hour
+// - Verification resend (per user): 3 attempts per hour
 func NewAuthRateLimiter(logger *slog.Logger) *AuthRateLimiter {
 	return &AuthRateLimiter{
 		loginLimiter:         NewRateLimiter(5, 15*time.Minute, logger),
 		registerLimiter:      NewRateLimiter(3, time.Hour, logger),
 		passwordResetLimiter: NewRateLimiter(3, time.Hour, logger),
+		resendLimiter:        NewRateLimiter(3, time.Hour, logger),
 		logger:               logger,
 	}
 }
@@ -256,6 +259,13 @@ func (a *AuthRateLimiter) LimitPasswordReset(next http.Handler) http.Handler {
 	return mw.Limit(next)
 }
 
+// AllowVerificationResend reports whether the user may be sent another
+// verification email. Unlike the other limits this is keyed by user ID, since
+// the logged-in resend endpoint acts on the session's user rather than an IP.
+func (a *AuthRateLimiter) AllowVerificationResend(userID string) bool {
+	return a.resendLimiter.Allow(userID)
+}
+
 // RecordFailedLogin records a failed login attempt for the given IP.
 // Call this when login fails to make failed attempts count against the limit.
 func (a *AuthRateLimiter) RecordFailedLogin(ip string) {
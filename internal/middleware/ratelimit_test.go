@@ -371,6 +371,9 @@ func TestNewAuthRateLimiter(t *testing.T) {
 	if arl.passwordResetLimiter == nil {
 		t.Error("expected password reset limiter to be created")
 	}
+	if arl.resendLimiter == nil {
+		t.Error("expected verification resend limiter to be created")
+	}
 }
 
 func TestAuthRateLimiter_Login(t *testing.T) {
@@ -457,6 +460,24 @@ func TestAuthRateLimiter_PasswordReset(t *testing.T) {
 	}
 }
 
+func TestAuthRateLimiter_VerificationResend(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	arl := NewAuthRateLimiter(logger)
+
+	// Default verification resend limit is 3 per hour per user
+	for i := 0; i < 3; i++ {
+		if !arl.AllowVerificationResend("user-1") {
+			t.Errorf("resend %d: expected to be allowed", i+1)
+		}
+	}
+	if arl.AllowVerificationResend("user-1") {
+		t.Error("resend 4: expected to be blocked")
+	}
+	if !arl.AllowVerificationResend("user-2") {
+		t.Error("expected another user's resend to be allowed")
+	}
+}
+
 func TestAuthRateLimiter_RecordFailedLogin(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
 	arl := NewAuthRateLimiter(logger)
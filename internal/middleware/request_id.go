@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/DukeRupert/lukaut/internal/requestid"
+)
+
+// RequestID returns middleware that assigns every request a correlation ID.
+//
+// A valid X-Request-ID from the client (e.g. set by a load balancer) is
+// reused; otherwise a new ID is generated. The ID is stored in the request
+// context, where requestid.LogHandler adds it to log records and the worker
+// enqueuer copies it into job payloads, and is echoed in the X-Request-ID
+// response header so users can quote it in bug reports.
+//
+// RequestID should wrap the request logging middleware so the access log
+// line carries the ID too.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestid.Header)
+		if !requestid.Valid(id) {
+			id = requestid.New()
+		}
+
+		w.Header().Set(requestid.Header, id)
+		next.ServeHTTP(w, r.WithContext(requestid.NewContext(r.Context(), id)))
+	})
+}
@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DukeRupert/lukaut/internal/requestid"
+)
+
+// =============================================================================
+// Request ID Middleware Tests
+// =============================================================================
+
+// serveWithRequestID runs req through RequestID and returns the response and
+// the ID the handler saw in its context.
+func serveWithRequestID(req *http.Request) (*httptest.ResponseRecorder, string) {
+	var seen string
+	handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = requestid.FromContext(r.Context())
+	}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec, seen
+}
+
+func TestRequestID_GeneratesIDInContextAndHeader(t *testing.T) {
+	rec, seen := serveWithRequestID(httptest.NewRequest("GET", "/dashboard", nil))
+
+	if seen == "" {
+		t.Fatal("expected request ID in context")
+	}
+	if got := rec.Header().Get("X-Request-ID"); got != seen {
+		t.Errorf("expected response header %q to echo context ID %q", got, seen)
+	}
+}
+
+func TestRequestID_UniquePerRequest(t *testing.T) {
+	_, first := serveWithRequestID(httptest.NewRequest("GET", "/", nil))
+	_, second := serveWithRequestID(httptest.NewRequest("GET", "/", nil))
+
+	if first == second {
+		t.Errorf("expected distinct IDs, got %q twice", first)
+	}
+}
+
+func TestRequestID_AcceptsClientID(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("X-Request-ID", "lb-7f3a.42")
+
+	rec, seen := serveWithRequestID(req)
+
+	if seen != "lb-7f3a.42" {
+		t.Errorf("expected client ID in context, got %q", seen)
+	}
+	if got := rec.Header().Get("X-Request-ID"); got != "lb-7f3a.42" {
+		t.Errorf("expected client ID echoed, got %q", got)
+	}
+}
+
+func TestRequestID_ReplacesInvalidClientID(t *testing.T) {
+	for _, bad := range []string{"has space", "<script>", strings.Repeat("a", 129)} {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("X-Request-ID", bad)
+
+		rec, seen := serveWithRequestID(req)
+
+		if seen == bad || seen == "" {
+			t.Errorf("expected %q to be replaced, got %q", bad, seen)
+		}
+		if got := rec.Header().Get("X-Request-ID"); got != seen {
+			t.Errorf("expected generated ID echoed, got %q", got)
+		}
+	}
+}
+
+func TestRequestID_CorrelatesRequestLog(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(requestid.NewLogHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	handler := RequestID(NewRequestLoggingMiddleware(logger).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger.InfoContext(r.Context(), "handler log")
+	})))
+	req := httptest.NewRequest("GET", "/dashboard", nil)
+	req.Header.Set("X-Request-ID", "abc123")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected handler and request log lines, got %q", buf.String())
+	}
+	for _, line := range lines {
+		if !strings.Contains(line, "request_id=abc123") {
+			t.Errorf("expected request_id on every line, got %q", line)
+		}
+	}
+}
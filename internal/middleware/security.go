@@ -1,9 +1,26 @@
 package middleware
 
 import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"log/slog"
 	"net/http"
+
+	"github.com/a-h/templ"
 )
 
+// CSPReportPath is where browsers send Content-Security-Policy violation
+// reports. The policy names it in its report-uri directive.
+const CSPReportPath = "/csp-report"
+
+// maxCSPReportSize caps the body accepted at CSPReportPath.
+const maxCSPReportSize = 64 << 10 // 64KB
+
+// cspNonceSize is the number of random bytes in a script nonce.
+const cspNonceSize = 16
+
 // SecurityHeadersMiddleware adds HTTP security headers to all responses.
 type SecurityHeadersMiddleware struct {
 	isSecure bool // Whether to enable HTTPS-specific headers (true in production)
@@ -18,8 +35,16 @@ func NewSecurityHeadersMiddleware(isSecure bool) *SecurityHeadersMiddleware {
 }
 
 // Handler returns middleware that sets security headers on all responses.
+// Each request gets a fresh script nonce, which templates read with
+// templ.GetNonce to mark their inline scripts as allowed.
 func (m *SecurityHeadersMiddleware) Handler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nonce, err := newCSPNonce()
+		if err != nil {
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+
 		// Prevent clickjacking - deny all framing
 		w.Header().Set("X-Frame-Options", "DENY")
 
@@ -40,37 +65,113 @@ func (m *SecurityHeadersMiddleware) Handler(next http.Handler) http.Handler {
 
 		// Content Security Policy
 		// Configured for Lukaut's tech stack:
-		// - htmx and Alpine.js from unpkg CDN
-		// - Tailwind CSS with inline styles
+		// - htmx, Alpine.js, and Leaflet from unpkg CDN
+		// - Inline scripts carrying the request's nonce
+		// - Tailwind CSS with inline styles, Leaflet CSS from unpkg
 		// - Images from R2 storage and data URIs
-		csp := buildCSP()
+		// Development relaxes it for local tooling.
+		csp := buildCSP(m.isSecure, nonce)
 		w.Header().Set("Content-Security-Policy", csp)
 
 		// Permissions Policy - disable browser features we don't need
 		w.Header().Set("Permissions-Policy", "geolocation=(), microphone=(), camera=()")
 
-		next.ServeHTTP(w, r)
+		next.ServeHTTP(w, r.WithContext(templ.WithNonce(r.Context(), nonce)))
 	})
 }
 
+// newCSPNonce returns a random, base64-encoded script nonce.
+func newCSPNonce() (string, error) {
+	b := make([]byte, cspNonceSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
 // buildCSP constructs the Content-Security-Policy header value.
-// This is configured specifically for Lukaut's tech stack.
-func buildCSP() string {
+// This is configured specifically for Lukaut's tech stack. Inline scripts
+// run only when they carry the nonce. Outside production (isSecure false) the
+// policy also allows inline scripts without it, eval, and connections to
+// localhost over HTTP and WebSocket, for live reload and dev builds of
+// Alpine. Violations are reported to CSPReportPath in both.
+func buildCSP(isSecure bool, nonce string) string {
+	scriptSrc := "'self' https://unpkg.com 'nonce-" + nonce + "'"
+	connectSrc := "'self'"
+	imgSrc := "'self' data: https:"
+	if !isSecure {
+		scriptSrc += " 'unsafe-inline' 'unsafe-eval'"
+		connectSrc += " ws://localhost:* http://localhost:*"
+		imgSrc += " http://localhost:*"
+	}
+
 	return "default-src 'self'; " +
-		// Scripts: self + htmx/Alpine from unpkg + unsafe-inline for Alpine's x-data
-		"script-src 'self' https://unpkg.com 'unsafe-inline'; " +
-		// Styles: self + unsafe-inline for Tailwind's inline styles
-		"style-src 'self' 'unsafe-inline'; " +
+		// Scripts: self + htmx/Alpine from unpkg + inline scripts with the nonce
+		"script-src " + scriptSrc + "; " +
+		// Styles: self + Leaflet CSS from unpkg + unsafe-inline for Tailwind's inline styles
+		"style-src 'self' https://unpkg.com 'unsafe-inline'; " +
 		// Images: self + data URIs + any HTTPS source (for R2/external images)
-		"img-src 'self' data: https:; " +
+		"img-src " + imgSrc + "; " +
 		// Fonts: self only
 		"font-src 'self'; " +
 		// Connect: self only (for htmx AJAX calls)
-		"connect-src 'self'; " +
+		"connect-src " + connectSrc + "; " +
 		// Prevent framing by any site
 		"frame-ancestors 'none'; " +
 		// Restrict base URI to prevent base tag injection
 		"base-uri 'self'; " +
 		// Restrict form actions to self
-		"form-action 'self'"
+		"form-action 'self'; " +
+		// Send violations to the report endpoint so the policy can be tightened
+		"report-uri " + CSPReportPath
+}
+
+// =============================================================================
+// CSP Violation Reports
+// =============================================================================
+
+// cspViolation is the body of a report-uri violation report.
+type cspViolation struct {
+	DocumentURI        string `json:"document-uri"`
+	ViolatedDirective  string `json:"violated-directive"`
+	EffectiveDirective string `json:"effective-directive"`
+	BlockedURI         string `json:"blocked-uri"`
+	SourceFile         string `json:"source-file"`
+	LineNumber         int    `json:"line-number"`
+	Disposition        string `json:"disposition"`
+}
+
+// CSPReportHandler returns a handler for POST CSPReportPath that logs each
+// violation report browsers send and answers 204. Reports are unauthenticated,
+// so the body is size-limited and malformed reports are rejected with 400.
+func CSPReportHandler(logger *slog.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxCSPReportSize+1))
+		if err != nil || len(body) > maxCSPReportSize {
+			http.Error(w, "Invalid report", http.StatusBadRequest)
+			return
+		}
+
+		var report struct {
+			Report *cspViolation `json:"csp-report"`
+		}
+		if err := json.Unmarshal(body, &report); err != nil || report.Report == nil {
+			http.Error(w, "Invalid report", http.StatusBadRequest)
+			return
+		}
+
+		v := report.Report
+		logger.WarnContext(r.Context(), "content security policy violation",
+			"document_uri", v.DocumentURI,
+			"violated_directive", v.ViolatedDirective,
+			"effective_directive", v.EffectiveDirective,
+			"blocked_uri", v.BlockedURI,
+			"source_file", v.SourceFile,
+			"line_number", v.LineNumber,
+			"disposition", v.Disposition,
+			"user_agent", r.UserAgent(),
+		)
+
+		w.WriteHeader(http.StatusNoContent)
+	})
 }
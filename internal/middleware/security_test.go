@@ -1,10 +1,14 @@
 package middleware
 
 import (
+	"bytes"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+
+	"github.com/a-h/templ"
 )
 
 // =============================================================================
@@ -263,3 +267,165 @@ func TestSecurityHeadersMiddleware_CSPAllowsR2Images(t *testing.T) {
 		t.Errorf("CSP should allow data: URIs for images: %s", csp)
 	}
 }
+
+func TestSecurityHeadersMiddleware_RepresentativeRoute(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /inspections/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(`<div hx-get="/inspections/1/status" hx-trigger="every 5s"></div>`))
+	})
+
+	req := httptest.NewRequest("GET", "/inspections/550e8400-e29b-41d4-a716-446655440000", nil)
+	rec := httptest.NewRecorder()
+	NewSecurityHeadersMiddleware(true).Handler(mux).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	for header, want := range map[string]string{
+		"X-Content-Type-Options":    "nosniff",
+		"Referrer-Policy":           "strict-origin-when-cross-origin",
+		"X-Frame-Options":           "DENY",
+		"Strict-Transport-Security": "max-age=31536000; includeSubDomains",
+	} {
+		if got := rec.Header().Get(header); got != want {
+			t.Errorf("%s: expected %q, got %q", header, want, got)
+		}
+	}
+	csp := rec.Header().Get("Content-Security-Policy")
+	for _, want := range []string{"frame-ancestors 'none'", "report-uri " + CSPReportPath} {
+		if !strings.Contains(csp, want) {
+			t.Errorf("CSP missing %q: %s", want, csp)
+		}
+	}
+}
+
+func TestSecurityHeadersMiddleware_DevelopmentPolicyRelaxed(t *testing.T) {
+	serve := func(isSecure bool) string {
+		rec := httptest.NewRecorder()
+		NewSecurityHeadersMiddleware(isSecure).Handler(http.NotFoundHandler()).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+		return rec.Header().Get("Content-Security-Policy")
+	}
+
+	dev, prod := serve(false), serve(true)
+	if !strings.Contains(cspDirective(dev, "script-src"), "'unsafe-inline'") {
+		t.Errorf("expected development CSP to allow inline scripts: %s", dev)
+	}
+	for _, relaxed := range []string{"'unsafe-eval'", "ws://localhost:*"} {
+		if !strings.Contains(dev, relaxed) {
+			t.Errorf("expected development CSP to allow %s: %s", relaxed, dev)
+		}
+		if strings.Contains(prod, relaxed) {
+			t.Errorf("expected production CSP not to allow %s: %s", relaxed, prod)
+		}
+	}
+	if !strings.Contains(dev, "report-uri "+CSPReportPath) {
+		t.Errorf("expected development CSP to report violations: %s", dev)
+	}
+}
+
+// cspDirective returns the value of the named directive in a policy.
+func cspDirective(csp, name string) string {
+	for _, directive := range strings.Split(csp, ";") {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(directive), name+" "); ok {
+			return value
+		}
+	}
+	return ""
+}
+
+func TestSecurityHeadersMiddleware_ScriptNonce(t *testing.T) {
+	var nonces []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nonces = append(nonces, templ.GetNonce(r.Context()))
+	})
+	mw := NewSecurityHeadersMiddleware(true).Handler(handler)
+
+	var scriptSrcs []string
+	for range 2 {
+		rec := httptest.NewRecorder()
+		mw.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+		scriptSrcs = append(scriptSrcs, cspDirective(rec.Header().Get("Content-Security-Policy"), "script-src"))
+	}
+
+	for i, scriptSrc := range scriptSrcs {
+		if nonces[i] == "" || !strings.Contains(scriptSrc, "'nonce-"+nonces[i]+"'") {
+			t.Errorf("expected script-src to allow the templates' nonce %q: %s", nonces[i], scriptSrc)
+		}
+		if strings.Contains(scriptSrc, "'unsafe-inline'") {
+			t.Errorf("expected production script-src not to allow unsafe-inline: %s", scriptSrc)
+		}
+	}
+	if nonces[0] == nonces[1] {
+		t.Errorf("expected a new nonce for each request, got %q twice", nonces[0])
+	}
+}
+
+// =============================================================================
+// CSP Report Handler Tests
+// =============================================================================
+
+func TestCSPReportHandler_LogsViolation(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	report := `{"csp-report": {
+		"document-uri": "https://lukaut.com/inspections/123",
+		"referrer": "",
+		"violated-directive": "script-src-elem",
+		"effective-directive": "script-src-elem",
+		"original-policy": "default-src 'self'; report-uri /csp-report",
+		"disposition": "enforce",
+		"blocked-uri": "https://evil.example.com/x.js",
+		"line-number": 12,
+		"source-file": "https://lukaut.com/inspections/123",
+		"status-code": 200
+	}}`
+	req := httptest.NewRequest("POST", CSPReportPath, strings.NewReader(report))
+	req.Header.Set("Content-Type", "application/csp-report")
+	rec := httptest.NewRecorder()
+
+	CSPReportHandler(logger).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", rec.Code)
+	}
+	logs := buf.String()
+	for _, want := range []string{
+		"content security policy violation",
+		"violated_directive=script-src-elem",
+		"blocked_uri=https://evil.example.com/x.js",
+		"line_number=12",
+	} {
+		if !strings.Contains(logs, want) {
+			t.Errorf("expected log to contain %q, got: %s", want, logs)
+		}
+	}
+}
+
+func TestCSPReportHandler_RejectsInvalidReports(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"not JSON", "directive=script-src"},
+		{"missing report", `{"something": "else"}`},
+		{"too large", `{"csp-report": {"blocked-uri": "` + strings.Repeat("a", maxCSPReportSize) + `"}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			req := httptest.NewRequest("POST", CSPReportPath, strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+
+			CSPReportHandler(slog.New(slog.NewTextHandler(&buf, nil))).ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("expected 400, got %d", rec.Code)
+			}
+			if buf.Len() != 0 {
+				t.Errorf("expected nothing logged, got: %s", buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// TimeoutConfig configures per-request deadlines.
+type TimeoutConfig struct {
+	Request     time.Duration // Context deadline for ordinary requests; 0 disables it
+	Long        time.Duration // Context and connection deadline for long-running routes
+	LongRunning []string      // ServeMux patterns of long-running routes (e.g., "POST /inspections/{id}/images")
+}
+
+// Timeout bounds how long a request's handler may run.
+//
+// Ordinary requests get a context that expires after Request, so database
+// queries and outbound calls made with r.Context() give up rather than pile
+// up behind a slow dependency. Routes matching a LongRunning pattern, such
+// as photo uploads and archive downloads, get Long instead. Their
+// connection read and write deadlines are extended to match, since the
+// server's WriteTimeout would otherwise cut them off.
+type Timeout struct {
+	request time.Duration
+	long    time.Duration
+	routes  *http.ServeMux // Matches LongRunning patterns
+}
+
+// NewTimeout creates a Timeout middleware from cfg. It panics if a
+// LongRunning pattern is invalid, as http.ServeMux.Handle does.
+func NewTimeout(cfg TimeoutConfig) *Timeout {
+	routes := http.NewServeMux()
+	for _, pattern := range cfg.LongRunning {
+		routes.Handle(pattern, http.NotFoundHandler())
+	}
+	return &Timeout{request: cfg.Request, long: cfg.Long, routes: routes}
+}
+
+// Handler returns middleware that applies the request's deadline.
+func (t *Timeout) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		timeout := t.request
+		if t.isLongRunning(r) {
+			timeout = t.long
+			if timeout > 0 {
+				// Writers that don't support deadlines (e.g. test
+				// recorders) keep the server's
+				deadline := time.Now().Add(timeout)
+				rc := http.NewResponseController(w)
+				_ = rc.SetReadDeadline(deadline)
+				_ = rc.SetWriteDeadline(deadline)
+			}
+		}
+		if timeout <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// isLongRunning reports whether r matches a LongRunning pattern.
+func (t *Timeout) isLongRunning(r *http.Request) bool {
+	_, pattern := t.routes.Handler(r)
+	return pattern != ""
+}
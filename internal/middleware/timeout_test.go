@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// =============================================================================
+// Timeout Middleware Tests
+// =============================================================================
+
+// deadlineFor runs req through a Timeout built from cfg and returns how long
+// the handler's context had left, or zero if it had no deadline.
+func deadlineFor(cfg TimeoutConfig, req *http.Request) time.Duration {
+	var remaining time.Duration
+	handler := NewTimeout(cfg).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if deadline, ok := r.Context().Deadline(); ok {
+			remaining = time.Until(deadline)
+		}
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	return remaining
+}
+
+var testTimeoutConfig = TimeoutConfig{
+	Request:     30 * time.Second,
+	Long:        10 * time.Minute,
+	LongRunning: []string{"POST /inspections/{id}/images"},
+}
+
+func TestTimeout_OrdinaryRequestGetsRequestTimeout(t *testing.T) {
+	remaining := deadlineFor(testTimeoutConfig, httptest.NewRequest("GET", "/inspections/abc", nil))
+
+	if remaining <= 0 || remaining > 30*time.Second {
+		t.Errorf("expected a deadline within 30s, got %s", remaining)
+	}
+}
+
+func TestTimeout_LongRunningRouteGetsLongTimeout(t *testing.T) {
+	remaining := deadlineFor(testTimeoutConfig, httptest.NewRequest("POST", "/inspections/abc/images", nil))
+
+	if remaining <= 30*time.Second || remaining > 10*time.Minute {
+		t.Errorf("expected the 10m upload deadline, got %s", remaining)
+	}
+
+	// Only the pattern's method is long-running
+	remaining = deadlineFor(testTimeoutConfig, httptest.NewRequest("DELETE", "/inspections/abc/images", nil))
+	if remaining > 30*time.Second {
+		t.Errorf("expected the request deadline for DELETE, got %s", remaining)
+	}
+}
+
+func TestTimeout_ZeroDisablesDeadline(t *testing.T) {
+	remaining := deadlineFor(TimeoutConfig{}, httptest.NewRequest("GET", "/", nil))
+
+	if remaining != 0 {
+		t.Errorf("expected no deadline, got %s", remaining)
+	}
+}
+
+func TestTimeout_LongRunningRouteOutlivesServerWriteTimeout(t *testing.T) {
+	handler := NewTimeout(TimeoutConfig{
+		Request:     time.Second,
+		Long:        5 * time.Second,
+		LongRunning: []string{"GET /slow"},
+	}).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		_, _ = io.WriteString(w, "done")
+	}))
+	srv := httptest.NewUnstartedServer(handler)
+	srv.Config.WriteTimeout = 50 * time.Millisecond
+	srv.Start()
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/slow")
+	if err != nil {
+		t.Fatalf("expected the long-running response to be written, got %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "done" {
+		t.Errorf("expected body %q, got %q", "done", body)
+	}
+
+	// An ordinary route is still cut off by the server's WriteTimeout
+	if resp, err := http.Get(srv.URL + "/fast"); err == nil {
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err == nil && string(body) == "done" {
+			t.Error("expected the server's WriteTimeout to cut off an ordinary route")
+		}
+	}
+}
@@ -0,0 +1,21 @@
+-- +goose Up
+-- Audit log of state changes on inspections, violations, and reports.
+-- Intentionally no foreign keys: audit rows must outlive the records and
+-- users they describe so history can be proven after deletion.
+CREATE TABLE audit_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    actor_user_id UUID NOT NULL,
+    inspection_id UUID,
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id UUID NOT NULL,
+    action VARCHAR(50) NOT NULL,
+    old_values JSONB,
+    new_values JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_events_inspection_id ON audit_events(inspection_id, created_at);
+CREATE INDEX idx_audit_events_entity ON audit_events(entity_type, entity_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS audit_events;
@@ -0,0 +1,12 @@
+-- +goose Up
+-- Stripe webhook events that have been processed, keyed by Stripe event ID.
+-- Stripe retries deliveries until it receives a 2xx, so the same event can
+-- arrive more than once; a row here means it must not be applied again.
+CREATE TABLE stripe_webhook_events (
+    event_id VARCHAR(255) PRIMARY KEY,
+    event_type VARCHAR(100) NOT NULL,
+    processed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS stripe_webhook_events;
@@ -0,0 +1,23 @@
+-- +goose Up
+-- Public, read-only links to a completed inspection's report for clients
+-- without an account. Only the SHA-256 hash of the token is stored; the raw
+-- token appears once, in the URL handed to the inspector.
+CREATE TABLE inspection_share_links (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    inspection_id UUID NOT NULL REFERENCES inspections(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    max_views INTEGER,
+    view_count INTEGER NOT NULL DEFAULT 0,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT uq_inspection_share_links_hash UNIQUE (token_hash),
+    CONSTRAINT chk_inspection_share_links_max_views CHECK (max_views IS NULL OR max_views > 0)
+);
+
+CREATE INDEX idx_inspection_share_links_inspection_id ON inspection_share_links(inspection_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS inspection_share_links;
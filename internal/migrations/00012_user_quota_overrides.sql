@@ -0,0 +1,15 @@
+-- +goose Up
+-- Per-user monthly quota overrides set by admins. A NULL limit falls back to
+-- the user's subscription tier; a row with both limits NULL is never kept.
+CREATE TABLE user_quota_overrides (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    analysis_per_month INTEGER,
+    reports_per_month INTEGER,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT chk_user_quota_overrides_analysis CHECK (analysis_per_month IS NULL OR analysis_per_month >= 0),
+    CONSTRAINT chk_user_quota_overrides_reports CHECK (reports_per_month IS NULL OR reports_per_month >= 0)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS user_quota_overrides;
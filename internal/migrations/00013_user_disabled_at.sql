@@ -0,0 +1,9 @@
+-- +goose Up
+-- Disabled accounts cannot log in and their existing sessions are rejected.
+-- Disabling keeps the user's data, unlike deleting the account.
+ALTER TABLE users ADD COLUMN disabled_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN users.disabled_at IS 'When the account was disabled; NULL if active';
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN IF EXISTS disabled_at;
@@ -0,0 +1,8 @@
+-- +goose Up
+-- Last authenticated request on the session, for the optional idle timeout.
+-- Existing sessions count as seen now so enabling the timeout does not log
+-- everyone out at once.
+ALTER TABLE sessions ADD COLUMN last_seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+-- +goose Down
+ALTER TABLE sessions DROP COLUMN IF EXISTS last_seen_at;
@@ -0,0 +1,13 @@
+-- +goose Up
+-- A report row with failed_at set records a generation job that failed for
+-- good, so the inspection page can offer a retry. failed_format is the format
+-- that was requested; failed rows have no storage keys.
+ALTER TABLE reports ADD COLUMN failed_at TIMESTAMPTZ;
+ALTER TABLE reports ADD COLUMN failed_format VARCHAR(10);
+
+COMMENT ON COLUMN reports.failed_at IS 'When the generation job failed permanently; NULL for generated reports';
+COMMENT ON COLUMN reports.failed_format IS 'Format requested by the failed generation job (pdf or docx)';
+
+-- +goose Down
+ALTER TABLE reports DROP COLUMN IF EXISTS failed_format;
+ALTER TABLE reports DROP COLUMN IF EXISTS failed_at;
@@ -0,0 +1,12 @@
+-- +goose Up
+-- Coordinates of the inspection address, filled in when the configured
+-- geocoder recognizes it. NULL when the address has not been geocoded.
+ALTER TABLE inspections ADD COLUMN latitude DOUBLE PRECISION;
+ALTER TABLE inspections ADD COLUMN longitude DOUBLE PRECISION;
+
+COMMENT ON COLUMN inspections.latitude IS 'Geocoded latitude of the address; NULL if not geocoded';
+COMMENT ON COLUMN inspections.longitude IS 'Geocoded longitude of the address; NULL if not geocoded';
+
+-- +goose Down
+ALTER TABLE inspections DROP COLUMN IF EXISTS longitude;
+ALTER TABLE inspections DROP COLUMN IF EXISTS latitude;
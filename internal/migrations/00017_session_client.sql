@@ -0,0 +1,12 @@
+-- +goose Up
+-- Client details shown on the session management page. Recorded at login and
+-- refreshed with last_seen_at. NULL for sessions created before this column.
+ALTER TABLE sessions ADD COLUMN user_agent TEXT;
+ALTER TABLE sessions ADD COLUMN ip_address VARCHAR(45);
+
+COMMENT ON COLUMN sessions.user_agent IS 'User-Agent of the most recent request on the session';
+COMMENT ON COLUMN sessions.ip_address IS 'Client IP of the most recent request on the session';
+
+-- +goose Down
+ALTER TABLE sessions DROP COLUMN IF EXISTS ip_address;
+ALTER TABLE sessions DROP COLUMN IF EXISTS user_agent;
@@ -0,0 +1,10 @@
+-- +goose Up
+-- Edit counter for optimistic locking. The edit form submits the version it
+-- loaded and the update only applies if it still matches, so a stale form
+-- cannot overwrite a newer save.
+ALTER TABLE inspections ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+COMMENT ON COLUMN inspections.version IS 'Incremented on every edit; used to detect concurrent edits';
+
+-- +goose Down
+ALTER TABLE inspections DROP COLUMN IF EXISTS version;
@@ -0,0 +1,22 @@
+-- +goose Up
+-- Autosaved values of the new-inspection form, one draft per user. Values
+-- are stored as typed and validated only when the inspection is created; a
+-- NULL column was never autosaved.
+CREATE TABLE inspection_drafts (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    title TEXT,
+    client_id TEXT,
+    address_line1 TEXT,
+    address_line2 TEXT,
+    city TEXT,
+    state TEXT,
+    postal_code TEXT,
+    inspection_date TEXT,
+    weather_conditions TEXT,
+    temperature TEXT,
+    inspector_notes TEXT,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS inspection_drafts;
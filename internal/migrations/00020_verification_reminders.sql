@@ -0,0 +1,16 @@
+-- +goose Up
+-- Tracks verification reminder emails so each unverified user receives a
+-- bounded number of reminders, spaced apart.
+ALTER TABLE users ADD COLUMN verification_reminder_sent_at TIMESTAMPTZ;
+ALTER TABLE users ADD COLUMN verification_reminder_count INTEGER NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN users.verification_reminder_sent_at IS 'When the last email verification reminder was sent';
+COMMENT ON COLUMN users.verification_reminder_count IS 'Number of email verification reminders sent';
+
+-- The reminder sweep only looks at unverified accounts
+CREATE INDEX idx_users_unverified_created_at ON users (created_at) WHERE email_verified IS NOT TRUE;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_users_unverified_created_at;
+ALTER TABLE users DROP COLUMN IF EXISTS verification_reminder_count;
+ALTER TABLE users DROP COLUMN IF EXISTS verification_reminder_sent_at;
@@ -0,0 +1,11 @@
+-- +goose Up
+-- Regulations a user has starred for quick linking to violations.
+CREATE TABLE user_regulation_favorites (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    regulation_id UUID NOT NULL REFERENCES regulations(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, regulation_id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS user_regulation_favorites;
@@ -0,0 +1,12 @@
+-- +goose Up
+-- Severity the AI originally assigned to a violation. The severity column
+-- holds the inspector's current value, which may override it during review.
+-- NULL for manual violations.
+ALTER TABLE violations ADD COLUMN ai_severity VARCHAR(50);
+
+UPDATE violations SET ai_severity = severity WHERE ai_description IS NOT NULL;
+
+COMMENT ON COLUMN violations.ai_severity IS 'Severity originally assigned by AI analysis; NULL for manual violations';
+
+-- +goose Down
+ALTER TABLE violations DROP COLUMN IF EXISTS ai_severity;
@@ -0,0 +1,25 @@
+-- +goose Up
+-- Standards dropped from an OSHA source import are deactivated rather than
+-- deleted so violation links to them stay valid. Inactive regulations are
+-- hidden from search and browse.
+ALTER TABLE regulations ADD COLUMN deactivated_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN regulations.deactivated_at IS 'When the standard was missing from an import; NULL if active';
+
+-- One row per regulation import run, for auditing what each import changed.
+CREATE TABLE regulation_imports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    source TEXT NOT NULL,
+    added_count INTEGER NOT NULL,
+    updated_count INTEGER NOT NULL,
+    unchanged_count INTEGER NOT NULL,
+    deactivated_count INTEGER NOT NULL,
+    errors TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_regulation_imports_created_at ON regulation_imports (created_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS regulation_imports;
+ALTER TABLE regulations DROP COLUMN IF EXISTS deactivated_at;
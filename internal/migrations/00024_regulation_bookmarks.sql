@@ -0,0 +1,13 @@
+-- +goose Up
+-- Regulation favorites are presented to users as bookmarks; rename the table
+-- and its constraints to match.
+ALTER TABLE user_regulation_favorites RENAME TO user_regulation_bookmarks;
+ALTER TABLE user_regulation_bookmarks RENAME CONSTRAINT user_regulation_favorites_pkey TO user_regulation_bookmarks_pkey;
+ALTER TABLE user_regulation_bookmarks RENAME CONSTRAINT user_regulation_favorites_user_id_fkey TO user_regulation_bookmarks_user_id_fkey;
+ALTER TABLE user_regulation_bookmarks RENAME CONSTRAINT user_regulation_favorites_regulation_id_fkey TO user_regulation_bookmarks_regulation_id_fkey;
+
+-- +goose Down
+ALTER TABLE user_regulation_bookmarks RENAME CONSTRAINT user_regulation_bookmarks_regulation_id_fkey TO user_regulation_favorites_regulation_id_fkey;
+ALTER TABLE user_regulation_bookmarks RENAME CONSTRAINT user_regulation_bookmarks_user_id_fkey TO user_regulation_favorites_user_id_fkey;
+ALTER TABLE user_regulation_bookmarks RENAME CONSTRAINT user_regulation_bookmarks_pkey TO user_regulation_favorites_pkey;
+ALTER TABLE user_regulation_bookmarks RENAME TO user_regulation_favorites;
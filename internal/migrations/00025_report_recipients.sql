@@ -0,0 +1,9 @@
+-- +goose Up
+-- Addresses a generated report was emailed to, so the inspection page can
+-- show who received it. Empty when the report was only sent to the inspector.
+ALTER TABLE reports ADD COLUMN recipient_emails TEXT[] NOT NULL DEFAULT '{}';
+
+COMMENT ON COLUMN reports.recipient_emails IS 'Addresses the report was emailed to, besides the inspector';
+
+-- +goose Down
+ALTER TABLE reports DROP COLUMN IF EXISTS recipient_emails;
@@ -0,0 +1,49 @@
+-- +goose Up
+-- Regions of a photo that show a violation, drawn over the image during
+-- review. AI regions come from the analysis; inspectors can draw their own.
+-- Coordinates are normalized to the image size (0-1 from the top left).
+CREATE TABLE image_annotations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    violation_id UUID NOT NULL REFERENCES violations(id) ON DELETE CASCADE,
+    image_id UUID NOT NULL REFERENCES images(id) ON DELETE CASCADE,
+    x DOUBLE PRECISION NOT NULL,
+    y DOUBLE PRECISION NOT NULL,
+    width DOUBLE PRECISION NOT NULL,
+    height DOUBLE PRECISION NOT NULL,
+    label VARCHAR(255) NOT NULL DEFAULT '',
+    source VARCHAR(10) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT image_annotations_source_check CHECK (source IN ('ai', 'manual')),
+    CONSTRAINT image_annotations_bounds_check CHECK (
+        x >= 0 AND x < 1 AND y >= 0 AND y < 1
+        AND width > 0 AND width <= 1 AND height > 0 AND height <= 1
+    )
+);
+
+CREATE INDEX idx_image_annotations_image_id ON image_annotations (image_id);
+CREATE INDEX idx_image_annotations_violation_id ON image_annotations (violation_id);
+
+-- Carry over regions stored on violations by earlier analyses
+-- (ai.BoundingBox marshaled without JSON tags)
+INSERT INTO image_annotations (violation_id, image_id, x, y, width, height, source, created_at)
+SELECT id, image_id, x, y, LEAST(width, 1 - x), LEAST(height, 1 - y), 'ai', created_at
+FROM (
+    SELECT
+        v.id,
+        v.image_id,
+        (v.bounding_box->>'X')::DOUBLE PRECISION AS x,
+        (v.bounding_box->>'Y')::DOUBLE PRECISION AS y,
+        (v.bounding_box->>'Width')::DOUBLE PRECISION AS width,
+        (v.bounding_box->>'Height')::DOUBLE PRECISION AS height,
+        COALESCE(v.created_at, NOW()) AS created_at
+    FROM violations v
+    WHERE v.image_id IS NOT NULL
+    AND jsonb_typeof(v.bounding_box->'X') = 'number'
+    AND jsonb_typeof(v.bounding_box->'Y') = 'number'
+    AND jsonb_typeof(v.bounding_box->'Width') = 'number'
+    AND jsonb_typeof(v.bounding_box->'Height') = 'number'
+) boxes
+WHERE x >= 0 AND x < 1 AND y >= 0 AND y < 1 AND width > 0 AND height > 0;
+
+-- +goose Down
+DROP TABLE IF EXISTS image_annotations;
@@ -0,0 +1,23 @@
+-- +goose Up
+-- One row per analysis job run with the AI usage and cost of the images it
+-- analyzed, for cost reporting per inspection and per user. Runs outlive
+-- their inspection so past costs still add up after a delete.
+CREATE TABLE analysis_runs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    inspection_id UUID REFERENCES inspections(id) ON DELETE SET NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(50) NOT NULL,
+    model VARCHAR(100) NOT NULL,
+    images_analyzed INTEGER NOT NULL,
+    prompt_tokens INTEGER NOT NULL,
+    completion_tokens INTEGER NOT NULL,
+    cost_cents INTEGER NOT NULL,
+    duration_ms INTEGER NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_analysis_runs_user_created ON analysis_runs (user_id, created_at);
+CREATE INDEX idx_analysis_runs_inspection_id ON analysis_runs (inspection_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS analysis_runs;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- Why an image's analysis last failed, shown on its failed badge so the
+-- inspector knows whether a retry is worthwhile. Cleared once it succeeds.
+ALTER TABLE images ADD COLUMN analysis_error TEXT;
+
+-- +goose Down
+ALTER TABLE images DROP COLUMN analysis_error;
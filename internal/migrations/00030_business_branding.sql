@@ -0,0 +1,14 @@
+-- +goose Up
+-- Report branding: the storage key of the logo the inspector uploaded and the
+-- hex color used for report headers. Both fall back to Lukaut's defaults.
+ALTER TABLE users
+ADD COLUMN business_logo_key VARCHAR(512),
+ADD COLUMN business_brand_color VARCHAR(7);
+
+COMMENT ON COLUMN users.business_logo_key IS 'Storage key of the uploaded business logo';
+COMMENT ON COLUMN users.business_brand_color IS 'Hex color (#RRGGBB) for report headers';
+
+-- +goose Down
+ALTER TABLE users
+DROP COLUMN IF EXISTS business_brand_color,
+DROP COLUMN IF EXISTS business_logo_key;
@@ -0,0 +1,16 @@
+-- +goose Up
+-- Activity on an inspection shown in the live feed on the inspection page:
+-- analysis progress, reports, and status changes. The serial id is the feed's
+-- cursor; polling for ids above the last one seen returns only new events.
+CREATE TABLE inspection_events (
+    id BIGSERIAL PRIMARY KEY,
+    inspection_id UUID NOT NULL REFERENCES inspections(id) ON DELETE CASCADE,
+    kind VARCHAR(50) NOT NULL,
+    data JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_inspection_events_inspection_id ON inspection_events(inspection_id, id);
+
+-- +goose Down
+DROP TABLE IF EXISTS inspection_events;
@@ -0,0 +1,11 @@
+-- +goose Up
+-- Per-user UI preferences, such as the list page size. Stored as a JSONB
+-- object so new preferences need no migration.
+CREATE TABLE user_preferences (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    preferences JSONB NOT NULL DEFAULT '{}',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS user_preferences;
@@ -0,0 +1,19 @@
+-- +goose Up
+-- Position of an image in its inspection's gallery, set by the inspector
+-- dragging images into order. Reports list violations in this order.
+ALTER TABLE images ADD COLUMN display_order INTEGER NOT NULL DEFAULT 0;
+
+-- Existing images keep the order they were uploaded in
+UPDATE images
+SET display_order = ordered.position
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY inspection_id ORDER BY created_at, id) AS position
+    FROM images
+) ordered
+WHERE images.id = ordered.id;
+
+CREATE INDEX idx_images_inspection_display_order ON images(inspection_id, display_order);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_images_inspection_display_order;
+ALTER TABLE images DROP COLUMN IF EXISTS display_order;
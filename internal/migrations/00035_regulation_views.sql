@@ -0,0 +1,14 @@
+-- +goose Up
+-- Regulations a user recently opened, newest first. Only the latest few per
+-- user are kept; older views are trimmed as new ones are recorded.
+CREATE TABLE user_regulation_views (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    regulation_id UUID NOT NULL REFERENCES regulations(id) ON DELETE CASCADE,
+    viewed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, regulation_id)
+);
+
+CREATE INDEX idx_user_regulation_views_user_viewed_at ON user_regulation_views(user_id, viewed_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS user_regulation_views;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- SHA-256 of the stored original, recorded at upload so entity tags can be
+-- served without reading the object back. NULL for images uploaded earlier.
+ALTER TABLE images ADD COLUMN content_hash TEXT;
+
+-- +goose Down
+ALTER TABLE images DROP COLUMN IF EXISTS content_hash;
@@ -0,0 +1,13 @@
+-- +goose Up
+-- An inspection may be assigned to a member of its owner's organization,
+-- who then works on it alongside the owner. Unassigned when the member's
+-- account is deleted.
+ALTER TABLE inspections ADD COLUMN assigned_to UUID REFERENCES users(id) ON DELETE SET NULL;
+
+COMMENT ON COLUMN inspections.assigned_to IS 'Organization member the inspection is assigned to; NULL if unassigned';
+
+CREATE INDEX idx_inspections_assigned_to ON inspections(assigned_to) WHERE assigned_to IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_inspections_assigned_to;
+ALTER TABLE inspections DROP COLUMN IF EXISTS assigned_to;
@@ -0,0 +1,16 @@
+-- +goose Up
+-- An inspection can be kept as a template: its title, client, address, and
+-- notes are copied into new draft inspections. Photos and violations are
+-- never copied.
+ALTER TABLE inspections ADD COLUMN is_template BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE inspections ADD COLUMN template_name TEXT;
+
+COMMENT ON COLUMN inspections.is_template IS 'Whether new inspections can be created from this one';
+COMMENT ON COLUMN inspections.template_name IS 'Name the template is listed under; NULL unless is_template';
+
+CREATE INDEX idx_inspections_templates ON inspections(user_id) WHERE is_template;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_inspections_templates;
+ALTER TABLE inspections DROP COLUMN IF EXISTS template_name;
+ALTER TABLE inspections DROP COLUMN IF EXISTS is_template;
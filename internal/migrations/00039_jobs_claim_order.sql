@@ -0,0 +1,11 @@
+-- +goose Up
+-- Workers claim ready jobs by priority, then in the order they were
+-- enqueued, so a retried job doesn't fall behind work queued after it.
+DROP INDEX IF EXISTS idx_jobs_pending;
+CREATE INDEX idx_jobs_pending ON jobs (priority DESC, created_at ASC)
+    WHERE status = 'pending';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_jobs_pending;
+CREATE INDEX idx_jobs_pending ON jobs (priority DESC, scheduled_at ASC)
+    WHERE status = 'pending';
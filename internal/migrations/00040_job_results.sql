@@ -0,0 +1,14 @@
+-- +goose Up
+-- What a job produced (e.g. the report it generated or the violations an
+-- analysis found), and when it stopped running for good: on completion or
+-- on its final failure.
+ALTER TABLE jobs
+    ADD COLUMN result JSONB,
+    ADD COLUMN finished_at TIMESTAMPTZ;
+
+UPDATE jobs SET finished_at = completed_at WHERE status = 'completed';
+
+-- +goose Down
+ALTER TABLE jobs
+    DROP COLUMN IF EXISTS finished_at,
+    DROP COLUMN IF EXISTS result;
@@ -0,0 +1,13 @@
+-- +goose Up
+-- Jobs that must not be queued twice (analyzing or reporting on the same
+-- inspection) carry a key naming what they do. Only one pending or running
+-- job can hold a key, so a repeated request finds the queued job instead of
+-- adding another; finished jobs release it.
+ALTER TABLE jobs ADD COLUMN idempotency_key TEXT;
+
+CREATE UNIQUE INDEX idx_jobs_idempotency_key ON jobs (idempotency_key)
+    WHERE status IN ('pending', 'running');
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_jobs_idempotency_key;
+ALTER TABLE jobs DROP COLUMN IF EXISTS idempotency_key;
@@ -0,0 +1,18 @@
+-- +goose Up
+-- Discussion on an inspection between the owner and their organization's
+-- members. Comments are kept when the author leaves the organization but
+-- are deleted with the inspection or the author's account.
+CREATE TABLE inspection_comments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    inspection_id UUID NOT NULL REFERENCES inspections(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    body TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT chk_inspection_comments_body CHECK (length(body) > 0)
+);
+
+CREATE INDEX idx_inspection_comments_inspection_id ON inspection_comments(inspection_id, created_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS inspection_comments;
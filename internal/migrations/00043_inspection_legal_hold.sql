@@ -0,0 +1,12 @@
+-- +goose Up
+-- Inspections under legal hold are never removed by the data retention purge,
+-- however old they are.
+ALTER TABLE inspections ADD COLUMN legal_hold BOOLEAN NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN inspections.legal_hold IS 'Exempts the inspection from the data retention purge';
+
+CREATE INDEX idx_inspections_retention ON inspections(updated_at) WHERE status = 'completed' AND NOT legal_hold;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_inspections_retention;
+ALTER TABLE inspections DROP COLUMN IF EXISTS legal_hold;
@@ -0,0 +1,14 @@
+-- +goose Up
+-- Per-user boilerplate sections of generated reports. Users without a row
+-- get the defaults from domain.DefaultReportTemplate. Sections are plain
+-- text and may contain placeholders such as {{ClientName}}.
+CREATE TABLE report_settings (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    intro TEXT NOT NULL,
+    disclaimer TEXT NOT NULL,
+    signature TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS report_settings;
@@ -0,0 +1,18 @@
+-- +goose Up
+-- Additional evidence photos of a violation, such as a close-up and a wide
+-- shot of the same hazard. violations.image_id stays the primary photo; this
+-- table holds the others, in the order they were attached. Links are removed
+-- with either the violation or the image.
+CREATE TABLE violation_images (
+    violation_id UUID NOT NULL REFERENCES violations(id) ON DELETE CASCADE,
+    image_id UUID NOT NULL REFERENCES images(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (violation_id, image_id)
+);
+
+CREATE INDEX idx_violation_images_image_id ON violation_images(image_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS violation_images;
@@ -0,0 +1,48 @@
+package report
+
+import "github.com/DukeRupert/lukaut/internal/domain"
+
+// Branding is the identity shown in a report's header and footer.
+// Generators use the inspector's business profile where it is filled in and
+// fall back to a default Branding for the rest.
+type Branding struct {
+	Name         string // Company name in the header and footer
+	LogoURL      string // Logo in the header; empty shows the name alone
+	PrimaryColor string // Hex color of the header and section headings
+}
+
+// DefaultBranding returns the Lukaut branding used for reports whose
+// inspector has not set up their own.
+func DefaultBranding() Branding {
+	return Branding{
+		Name:         "Lukaut Safety Inspection Platform",
+		PrimaryColor: BrandColors.Navy,
+	}
+}
+
+// For returns the branding of a report: the inspector's company name, logo
+// and brand color where set, and b's otherwise.
+func (b Branding) For(data *domain.ReportData) Branding {
+	if data.InspectorCompany != "" {
+		b.Name = data.InspectorCompany
+	}
+	if data.InspectorLogoURL != "" {
+		b.LogoURL = data.InspectorLogoURL
+	}
+	if domain.IsValidBrandColor(data.BrandColor) {
+		b.PrimaryColor = data.BrandColor
+	}
+	return b
+}
+
+// withDefaults fills the fields left empty with DefaultBranding's.
+func (b Branding) withDefaults() Branding {
+	def := DefaultBranding()
+	if b.Name == "" {
+		b.Name = def.Name
+	}
+	if !domain.IsValidBrandColor(b.PrimaryColor) {
+		b.PrimaryColor = def.PrimaryColor
+	}
+	return b
+}
@@ -22,16 +22,20 @@ type HTMLGenerator struct {
 	format        domain.ReportFormat
 	pdfConverter  Converter
 	docxConverter Converter
+	branding      Branding
 	logger        *slog.Logger
 }
 
-// NewHTMLGenerator creates a new HTML-based report generator.
-func NewHTMLGenerator(format domain.ReportFormat, logger *slog.Logger) *HTMLGenerator {
+// NewHTMLGenerator creates a new HTML-based report generator. Reports carry
+// the inspector's branding, falling back to branding for the fields they have
+// not set and to DefaultBranding for those branding leaves empty.
+func NewHTMLGenerator(format domain.ReportFormat, branding Branding, logger *slog.Logger) *HTMLGenerator {
 	if logger == nil {
 		logger = slog.Default()
 	}
 	return &HTMLGenerator{
 		format:        format,
+		branding:      branding.withDefaults(),
 		pdfConverter:  NewWeasyPrintConverter(),
 		docxConverter: NewPandocConverter(),
 		logger:        logger,
@@ -98,37 +102,72 @@ func (g *HTMLGenerator) Generate(ctx context.Context, data *domain.ReportData, w
 // prepareTemplateData converts domain.ReportData to reporttempl.ReportTemplateData,
 // optionally downloading and embedding images as base64 for DOCX generation.
 func (g *HTMLGenerator) prepareTemplateData(ctx context.Context, data *domain.ReportData) (*reporttempl.ReportTemplateData, error) {
+	branding := g.branding.For(data)
 	templateData := &reporttempl.ReportTemplateData{
 		ReportData: data,
+		Branding: reporttempl.Branding{
+			Name:         branding.Name,
+			LogoSrc:      branding.LogoURL,
+			PrimaryColor: branding.PrimaryColor,
+		},
+	}
+
+	// Close-ups are cut from the original image, so they are embedded for
+	// both formats
+	for _, v := range data.Violations {
+		if v.Region == nil || v.OriginalURL == "" {
+			continue
+		}
+		src, err := g.regionImageSrc(ctx, v)
+		if err != nil {
+			// The full thumbnail is shown instead
+			g.logger.Warn("Failed to prepare close-up of violation region",
+				"violation_number", v.Number,
+				"error", err,
+			)
+			continue
+		}
+		if templateData.RegionDataMap == nil {
+			templateData.RegionDataMap = make(map[string]string)
+		}
+		templateData.RegionDataMap[v.ThumbnailURL] = src
 	}
 
 	// For DOCX, we need to embed images as base64 because Pandoc can't fetch remote URLs
 	// For PDF, WeasyPrint can fetch URLs directly, so we skip this step
 	if g.format == domain.ReportFormatDOCX {
-		templateData.ImageDataMap = make(map[int]string)
+		templateData.ImageDataMap = make(map[string]string)
 
-		for _, v := range data.Violations {
-			if v.ThumbnailURL == "" {
-				continue
-			}
-
-			imgData, err := DownloadImage(ctx, v.ThumbnailURL)
+		if branding.LogoURL != "" {
+			logo, err := DownloadImage(ctx, branding.LogoURL)
 			if err != nil {
-				g.logger.Warn("Failed to download image for DOCX embedding",
-					"violation_number", v.Number,
-					"url", v.ThumbnailURL,
+				g.logger.Warn("Failed to download logo for DOCX embedding",
+					"url", branding.LogoURL,
 					"error", err,
 				)
-				continue
 			}
+			// Without the logo the header shows the company name alone
+			templateData.Branding.LogoSrc = ""
+			if logo != nil {
+				templateData.Branding.LogoSrc = imageDataURI(logo)
+			}
+		}
 
-			if imgData != nil {
-				// Convert to base64 data URI
-				dataURI := fmt.Sprintf("data:%s;base64,%s",
-					imgData.ContentType,
-					base64.StdEncoding.EncodeToString(imgData.Data),
-				)
-				templateData.ImageDataMap[v.Number] = dataURI
+		for _, v := range data.Violations {
+			for _, url := range v.PhotoURLs() {
+				imgData, err := DownloadImage(ctx, url)
+				if err != nil {
+					g.logger.Warn("Failed to download image for DOCX embedding",
+						"violation_number", v.Number,
+						"url", url,
+						"error", err,
+					)
+					continue
+				}
+
+				if imgData != nil {
+					templateData.ImageDataMap[url] = imageDataURI(imgData)
+				}
 			}
 		}
 
@@ -140,16 +179,38 @@ func (g *HTMLGenerator) prepareTemplateData(ctx context.Context, data *domain.Re
 	return templateData, nil
 }
 
+// regionImageSrc downloads a violation's original image and returns its
+// region as a data URI.
+func (g *HTMLGenerator) regionImageSrc(ctx context.Context, v domain.ReportViolation) (string, error) {
+	original, err := DownloadImage(ctx, v.OriginalURL)
+	if err != nil {
+		return "", err
+	}
+	region, err := CropImage(original, *v.Region, domain.LargeThumbnailSize)
+	if err != nil {
+		return "", err
+	}
+	return imageDataURI(region), nil
+}
+
+// imageDataURI encodes an image as a base64 data URI.
+func imageDataURI(img *ImageData) string {
+	return fmt.Sprintf("data:%s;base64,%s",
+		img.ContentType,
+		base64.StdEncoding.EncodeToString(img.Data),
+	)
+}
+
 // =============================================================================
 // Factory Functions for Backward Compatibility
 // =============================================================================
 
 // NewHTMLPDFGenerator creates an HTML generator for PDF output.
-func NewHTMLPDFGenerator(logger *slog.Logger) *HTMLGenerator {
-	return NewHTMLGenerator(domain.ReportFormatPDF, logger)
+func NewHTMLPDFGenerator(branding Branding, logger *slog.Logger) *HTMLGenerator {
+	return NewHTMLGenerator(domain.ReportFormatPDF, branding, logger)
 }
 
 // NewHTMLDOCXGenerator creates an HTML generator for DOCX output.
-func NewHTMLDOCXGenerator(logger *slog.Logger) *HTMLGenerator {
-	return NewHTMLGenerator(domain.ReportFormatDOCX, logger)
+func NewHTMLDOCXGenerator(branding Branding, logger *slog.Logger) *HTMLGenerator {
+	return NewHTMLGenerator(domain.ReportFormatDOCX, branding, logger)
 }
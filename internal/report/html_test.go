@@ -0,0 +1,266 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	reporttempl "github.com/DukeRupert/lukaut/internal/templ/report"
+	"github.com/disintegration/imaging"
+)
+
+// renderReport renders the HTML a generator would convert for data.
+func renderReport(t *testing.T, g *HTMLGenerator, data *domain.ReportData) string {
+	t.Helper()
+
+	templateData, err := g.prepareTemplateData(context.Background(), data)
+	if err != nil {
+		t.Fatalf("prepareTemplateData: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := reporttempl.Report(templateData).Render(context.Background(), &buf); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	return buf.String()
+}
+
+func testReportData() *domain.ReportData {
+	return &domain.ReportData{
+		InspectorName:   "Pat Inspector",
+		InspectionTitle: "Riverside Tower",
+		InspectionDate:  time.Date(2026, time.March, 3, 0, 0, 0, 0, time.UTC),
+		GeneratedAt:     time.Date(2026, time.March, 4, 9, 30, 0, 0, time.UTC),
+	}
+}
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestReport_RendersInspectorBranding(t *testing.T) {
+	data := testReportData()
+	data.InspectorCompany = "Acme Safety Consultants"
+	data.InspectorLogoURL = "https://cdn.example.com/acme-logo.png"
+	data.BrandColor = "#0055AA"
+
+	html := renderReport(t, NewHTMLPDFGenerator(DefaultBranding(), testLogger()), data)
+
+	for _, want := range []string{
+		`<div class="cover-brand-name">Acme Safety Consultants</div>`,
+		`src="https://cdn.example.com/acme-logo.png"`,
+		`alt="Acme Safety Consultants logo"`,
+		`--brand-primary: #0055AA;`,
+		`<p>Acme Safety Consultants</p>`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("report does not contain %q", want)
+		}
+	}
+	if strings.Contains(html, "Lukaut Safety Inspection Platform") {
+		t.Error("report shows the default branding alongside the inspector's")
+	}
+}
+
+func TestReport_FallsBackToDefaultBranding(t *testing.T) {
+	data := testReportData()
+	data.BrandColor = "red; } body { display: none"
+
+	html := renderReport(t, NewHTMLPDFGenerator(Branding{}, testLogger()), data)
+
+	for _, want := range []string{
+		`<div class="cover-brand-name">Lukaut Safety Inspection Platform</div>`,
+		`<p>Lukaut Safety Inspection Platform</p>`,
+		`--brand-primary: ` + BrandColors.Navy + `;`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("report does not contain %q", want)
+		}
+	}
+	if strings.Contains(html, `class="cover-logo"`) {
+		t.Error("report shows a logo without one configured")
+	}
+	if strings.Contains(html, "display: none") {
+		t.Error("report contains the invalid brand color")
+	}
+}
+
+func TestReport_CustomFallbackBranding(t *testing.T) {
+	fallback := Branding{Name: "Harbor Inspections", LogoURL: "https://cdn.example.com/harbor.png"}
+
+	html := renderReport(t, NewHTMLPDFGenerator(fallback, testLogger()), testReportData())
+
+	for _, want := range []string{
+		`<div class="cover-brand-name">Harbor Inspections</div>`,
+		`src="https://cdn.example.com/harbor.png"`,
+		`--brand-primary: ` + BrandColors.Navy + `;`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("report does not contain %q", want)
+		}
+	}
+}
+
+func TestReport_DOCXEmbedsLogo(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write([]byte("\x89PNG\r\n\x1a\nlogo"))
+	}))
+	defer srv.Close()
+
+	data := testReportData()
+	data.InspectorCompany = "Acme Safety Consultants"
+	data.InspectorLogoURL = srv.URL + "/logo.png"
+
+	html := renderReport(t, NewHTMLDOCXGenerator(DefaultBranding(), testLogger()), data)
+
+	if !strings.Contains(html, `src="data:image/png;base64,`) {
+		t.Error("DOCX report does not embed the logo as a data URI")
+	}
+	if strings.Contains(html, srv.URL) {
+		t.Error("DOCX report links the logo URL, which Pandoc cannot fetch")
+	}
+}
+
+func TestReport_WritesDatesInLocale(t *testing.T) {
+	data := testReportData()
+	data.DateLocale = domain.DateLocaleDeDE
+
+	html := renderReport(t, NewHTMLPDFGenerator(DefaultBranding(), testLogger()), data)
+
+	for _, want := range []string{"3. März 2026", "Generated: 4. März 2026 um 09:30"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("report does not contain %q", want)
+		}
+	}
+	if strings.Contains(html, "March 3, 2026") {
+		t.Error("report shows a US date despite the German locale")
+	}
+}
+
+func TestReport_RendersBoilerplateSections(t *testing.T) {
+	data := testReportData()
+	data.Boilerplate = domain.ReportTemplate{
+		Intro:     "Prepared for <b>Acme</b>.\nSecond line.",
+		Signature: "Pat Inspector",
+	}
+
+	html := renderReport(t, NewHTMLDOCXGenerator(DefaultBranding(), testLogger()), data)
+
+	for _, want := range []string{
+		`<h2 class="section-header">Introduction</h2>`,
+		`Prepared for &lt;b&gt;Acme&lt;/b&gt;.<br> Second line.`,
+		`<div class="signature-line"></div>`,
+		`Pat Inspector`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("report does not contain %q", want)
+		}
+	}
+	if strings.Contains(html, "<b>Acme</b>") {
+		t.Error("report renders boilerplate text as markup")
+	}
+	if strings.Contains(html, `>Disclaimer</h2>`) {
+		t.Error("report shows an empty disclaimer section")
+	}
+}
+
+func TestReport_RendersAllViolationPhotos(t *testing.T) {
+	data := testReportData()
+	data.Violations = []domain.ReportViolation{{
+		Number:       1,
+		Description:  "Unguarded floor opening",
+		Severity:     domain.ViolationSeveritySerious,
+		ThumbnailURL: "https://cdn.example.com/primary.jpg",
+		EvidenceURLs: []string{"https://cdn.example.com/evidence.jpg"},
+	}}
+
+	html := renderReport(t, NewHTMLPDFGenerator(DefaultBranding(), testLogger()), data)
+
+	primary := strings.Index(html, `src="https://cdn.example.com/primary.jpg" alt="Finding 1 photo 1 of 2"`)
+	evidence := strings.Index(html, `src="https://cdn.example.com/evidence.jpg" alt="Finding 1 photo 2 of 2"`)
+	if primary < 0 || evidence < 0 {
+		t.Fatalf("report does not show both photos:\n%s", html)
+	}
+	if evidence < primary {
+		t.Error("report shows the evidence photo before the primary photo")
+	}
+}
+
+func TestReport_ShowsRegionCloseUp(t *testing.T) {
+	original := image.NewRGBA(image.Rect(0, 0, 400, 200))
+	var png bytes.Buffer
+	if err := imaging.Encode(&png, original, imaging.PNG); err != nil {
+		t.Fatalf("encode original: %v", err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/original.png" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write(png.Bytes())
+	}))
+	defer srv.Close()
+
+	data := testReportData()
+	data.Violations = []domain.ReportViolation{
+		{
+			Number:       1,
+			Description:  "Unguarded floor opening",
+			ThumbnailURL: "https://cdn.example.com/primary.jpg",
+			Region:       &domain.AnnotationBox{X: 0.25, Y: 0.25, Width: 0.5, Height: 0.5},
+			OriginalURL:  srv.URL + "/original.png",
+		},
+		{
+			Number:       2,
+			Description:  "Missing guardrail",
+			ThumbnailURL: "https://cdn.example.com/second.jpg",
+			Region:       &domain.AnnotationBox{X: 0.25, Y: 0.25, Width: 0.5, Height: 0.5},
+			OriginalURL:  srv.URL + "/missing.png",
+		},
+	}
+
+	html := renderReport(t, NewHTMLPDFGenerator(DefaultBranding(), testLogger()), data)
+
+	if !strings.Contains(html, `src="data:image/jpeg;base64,`) {
+		t.Error("report does not show the close-up of the region")
+	}
+	if strings.Contains(html, "https://cdn.example.com/primary.jpg") {
+		t.Error("report shows the full thumbnail alongside the close-up")
+	}
+	if !strings.Contains(html, `src="https://cdn.example.com/second.jpg"`) {
+		t.Error("report does not fall back to the full thumbnail when the close-up cannot be made")
+	}
+}
+
+func TestCropImage(t *testing.T) {
+	original := image.NewRGBA(image.Rect(0, 0, 400, 200))
+	var png bytes.Buffer
+	if err := imaging.Encode(&png, original, imaging.PNG); err != nil {
+		t.Fatalf("encode original: %v", err)
+	}
+
+	got, err := CropImage(&ImageData{Data: png.Bytes(), ContentType: "image/png"}, domain.AnnotationBox{X: 0.5, Y: 0, Width: 0.25, Height: 1}, 100)
+	if err != nil {
+		t.Fatalf("CropImage: %v", err)
+	}
+	if got.ContentType != "image/jpeg" {
+		t.Errorf("expected a JPEG, got %q", got.ContentType)
+	}
+	cropped, err := imaging.Decode(bytes.NewReader(got.Data))
+	if err != nil {
+		t.Fatalf("decode close-up: %v", err)
+	}
+	// The 100x200 region is fit within 100x100
+	if b := cropped.Bounds(); b.Dx() != 50 || b.Dy() != 100 {
+		t.Errorf("expected a 50x100 close-up, got %dx%d", b.Dx(), b.Dy())
+	}
+}
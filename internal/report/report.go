@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/disintegration/imaging"
 )
 
 // =============================================================================
@@ -228,3 +229,20 @@ func (d *HTTPImageDownloader) Download(ctx context.Context, url string) (*ImageD
 		ContentType: contentType,
 	}, nil
 }
+
+// CropImage returns a JPEG of the part of img inside box, fit within
+// size x size, for showing a violation's region close up.
+func CropImage(img *ImageData, box domain.AnnotationBox, size int) (*ImageData, error) {
+	decoded, err := imaging.Decode(bytes.NewReader(img.Data))
+	if err != nil {
+		return nil, fmt.Errorf("decode image: %w", err)
+	}
+
+	region := imaging.Fit(imaging.Crop(decoded, box.Rect(decoded.Bounds())), size, size, imaging.Lanczos)
+
+	var buf bytes.Buffer
+	if err := imaging.Encode(&buf, region, imaging.JPEG, imaging.JPEGQuality(domain.ThumbnailJPEGQuality)); err != nil {
+		return nil, fmt.Errorf("encode image: %w", err)
+	}
+	return &ImageData{Data: buf.Bytes(), ContentType: "image/jpeg"}, nil
+}
@@ -13,6 +13,21 @@ import (
 	"github.com/google/uuid"
 )
 
+const adminCountUsers = `-- name: AdminCountUsers :one
+SELECT COUNT(*) FROM users u
+WHERE $1::text = ''
+   OR u.email ILIKE '%' || $1::text || '%'
+   OR u.name ILIKE '%' || $1::text || '%'
+`
+
+// Count the users AdminSearchUsers matches across all pages
+func (q *Queries) AdminCountUsers(ctx context.Context, search string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, adminCountUsers, search)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const adminGetPlatformStats = `-- name: AdminGetPlatformStats :one
 SELECT
     (SELECT COUNT(*) FROM users) as total_users,
@@ -164,7 +179,7 @@ func (q *Queries) AdminGetUserAIUsageHistory(ctx context.Context, arg AdminGetUs
 
 const adminGetUserByID = `-- name: AdminGetUserByID :one
 SELECT
-    u.id, u.email, u.password_hash, u.name, u.company_name, u.phone, u.stripe_customer_id, u.subscription_status, u.subscription_tier, u.subscription_id, u.email_verified, u.email_verified_at, u.created_at, u.updated_at, u.business_name, u.business_email, u.business_phone, u.business_address_line1, u.business_address_line2, u.business_city, u.business_state, u.business_postal_code, u.business_license_number, u.business_logo_url,
+    u.id, u.email, u.password_hash, u.name, u.company_name, u.phone, u.stripe_customer_id, u.subscription_status, u.subscription_tier, u.subscription_id, u.email_verified, u.email_verified_at, u.created_at, u.updated_at, u.business_name, u.business_email, u.business_phone, u.business_address_line1, u.business_address_line2, u.business_city, u.business_state, u.business_postal_code, u.business_license_number, u.business_logo_url, u.disabled_at, u.verification_reminder_sent_at, u.verification_reminder_count, u.business_logo_key, u.business_brand_color,
     COALESCE(SUM(a.input_tokens), 0)::bigint as total_input_tokens,
     COALESCE(SUM(a.output_tokens), 0)::bigint as total_output_tokens,
     COALESCE(SUM(a.cost_cents), 0)::bigint as total_cost_cents,
@@ -180,36 +195,41 @@ GROUP BY u.id
 `
 
 type AdminGetUserByIDRow struct {
-	ID                    uuid.UUID      `json:"id"`
-	Email                 string         `json:"email"`
-	PasswordHash          string         `json:"password_hash"`
-	Name                  string         `json:"name"`
-	CompanyName           sql.NullString `json:"company_name"`
-	Phone                 sql.NullString `json:"phone"`
-	StripeCustomerID      sql.NullString `json:"stripe_customer_id"`
-	SubscriptionStatus    sql.NullString `json:"subscription_status"`
-	SubscriptionTier      sql.NullString `json:"subscription_tier"`
-	SubscriptionID        sql.NullString `json:"subscription_id"`
-	EmailVerified         sql.NullBool   `json:"email_verified"`
-	EmailVerifiedAt       sql.NullTime   `json:"email_verified_at"`
-	CreatedAt             sql.NullTime   `json:"created_at"`
-	UpdatedAt             sql.NullTime   `json:"updated_at"`
-	BusinessName          sql.NullString `json:"business_name"`
-	BusinessEmail         sql.NullString `json:"business_email"`
-	BusinessPhone         sql.NullString `json:"business_phone"`
-	BusinessAddressLine1  sql.NullString `json:"business_address_line1"`
-	BusinessAddressLine2  sql.NullString `json:"business_address_line2"`
-	BusinessCity          sql.NullString `json:"business_city"`
-	BusinessState         sql.NullString `json:"business_state"`
-	BusinessPostalCode    sql.NullString `json:"business_postal_code"`
-	BusinessLicenseNumber sql.NullString `json:"business_license_number"`
-	BusinessLogoUrl       sql.NullString `json:"business_logo_url"`
-	TotalInputTokens      int64          `json:"total_input_tokens"`
-	TotalOutputTokens     int64          `json:"total_output_tokens"`
-	TotalCostCents        int64          `json:"total_cost_cents"`
-	AiRequestCount        int64          `json:"ai_request_count"`
-	InspectionCount       int64          `json:"inspection_count"`
-	ReportCount           int64          `json:"report_count"`
+	ID                         uuid.UUID      `json:"id"`
+	Email                      string         `json:"email"`
+	PasswordHash               string         `json:"password_hash"`
+	Name                       string         `json:"name"`
+	CompanyName                sql.NullString `json:"company_name"`
+	Phone                      sql.NullString `json:"phone"`
+	StripeCustomerID           sql.NullString `json:"stripe_customer_id"`
+	SubscriptionStatus         sql.NullString `json:"subscription_status"`
+	SubscriptionTier           sql.NullString `json:"subscription_tier"`
+	SubscriptionID             sql.NullString `json:"subscription_id"`
+	EmailVerified              sql.NullBool   `json:"email_verified"`
+	EmailVerifiedAt            sql.NullTime   `json:"email_verified_at"`
+	CreatedAt                  sql.NullTime   `json:"created_at"`
+	UpdatedAt                  sql.NullTime   `json:"updated_at"`
+	BusinessName               sql.NullString `json:"business_name"`
+	BusinessEmail              sql.NullString `json:"business_email"`
+	BusinessPhone              sql.NullString `json:"business_phone"`
+	BusinessAddressLine1       sql.NullString `json:"business_address_line1"`
+	BusinessAddressLine2       sql.NullString `json:"business_address_line2"`
+	BusinessCity               sql.NullString `json:"business_city"`
+	BusinessState              sql.NullString `json:"business_state"`
+	BusinessPostalCode         sql.NullString `json:"business_postal_code"`
+	BusinessLicenseNumber      sql.NullString `json:"business_license_number"`
+	BusinessLogoUrl            sql.NullString `json:"business_logo_url"`
+	DisabledAt                 sql.NullTime   `json:"disabled_at"`
+	VerificationReminderSentAt sql.NullTime   `json:"verification_reminder_sent_at"`
+	VerificationReminderCount  int32          `json:"verification_reminder_count"`
+	BusinessLogoKey            sql.NullString `json:"business_logo_key"`
+	BusinessBrandColor         sql.NullString `json:"business_brand_color"`
+	TotalInputTokens           int64          `json:"total_input_tokens"`
+	TotalOutputTokens          int64          `json:"total_output_tokens"`
+	TotalCostCents             int64          `json:"total_cost_cents"`
+	AiRequestCount             int64          `json:"ai_request_count"`
+	InspectionCount            int64          `json:"inspection_count"`
+	ReportCount                int64          `json:"report_count"`
 }
 
 // Get full user details for admin view
@@ -241,6 +261,11 @@ func (q *Queries) AdminGetUserByID(ctx context.Context, id uuid.UUID) (AdminGetU
 		&i.BusinessPostalCode,
 		&i.BusinessLicenseNumber,
 		&i.BusinessLogoUrl,
+		&i.DisabledAt,
+		&i.VerificationReminderSentAt,
+		&i.VerificationReminderCount,
+		&i.BusinessLogoKey,
+		&i.BusinessBrandColor,
 		&i.TotalInputTokens,
 		&i.TotalOutputTokens,
 		&i.TotalCostCents,
@@ -252,7 +277,7 @@ func (q *Queries) AdminGetUserByID(ctx context.Context, id uuid.UUID) (AdminGetU
 }
 
 const adminGetUserInspections = `-- name: AdminGetUserInspections :many
-SELECT id, title, status, inspection_date, created_at
+SELECT id, title, status, inspection_date, created_at, legal_hold
 FROM inspections
 WHERE user_id = $1
 ORDER BY created_at DESC
@@ -270,6 +295,7 @@ type AdminGetUserInspectionsRow struct {
 	Status         string       `json:"status"`
 	InspectionDate time.Time    `json:"inspection_date"`
 	CreatedAt      sql.NullTime `json:"created_at"`
+	LegalHold      bool         `json:"legal_hold"`
 }
 
 // Get inspections for a specific user (admin view)
@@ -288,6 +314,53 @@ func (q *Queries) AdminGetUserInspections(ctx context.Context, arg AdminGetUserI
 			&i.Status,
 			&i.InspectionDate,
 			&i.CreatedAt,
+			&i.LegalHold,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const adminListRecentJobs = `-- name: AdminListRecentJobs :many
+SELECT id, job_type, payload, status, priority, attempts, max_attempts, scheduled_at, started_at, completed_at, error_message, created_at, result, finished_at, idempotency_key FROM jobs
+ORDER BY created_at DESC
+LIMIT $1
+`
+
+// List the most recently created worker jobs for admin monitoring
+func (q *Queries) AdminListRecentJobs(ctx context.Context, limit int32) ([]Job, error) {
+	rows, err := q.db.QueryContext(ctx, adminListRecentJobs, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Job{}
+	for rows.Next() {
+		var i Job
+		if err := rows.Scan(
+			&i.ID,
+			&i.JobType,
+			&i.Payload,
+			&i.Status,
+			&i.Priority,
+			&i.Attempts,
+			&i.MaxAttempts,
+			&i.ScheduledAt,
+			&i.StartedAt,
+			&i.CompletedAt,
+			&i.ErrorMessage,
+			&i.CreatedAt,
+			&i.Result,
+			&i.FinishedAt,
+			&i.IdempotencyKey,
 		); err != nil {
 			return nil, err
 		}
@@ -377,14 +450,122 @@ func (q *Queries) AdminListUsers(ctx context.Context) ([]AdminListUsersRow, erro
 	return items, nil
 }
 
-const adminUpdateUserDisabled = `-- name: AdminUpdateUserDisabled :exec
+const adminSearchUsers = `-- name: AdminSearchUsers :many
+SELECT
+    u.id,
+    u.email,
+    u.name,
+    u.subscription_status,
+    u.subscription_tier,
+    u.email_verified,
+    u.disabled_at,
+    u.created_at,
+    COALESCE(SUM(a.cost_cents), 0)::bigint as total_cost_cents,
+    COUNT(DISTINCT i.id) as inspection_count
+FROM users u
+LEFT JOIN ai_usage a ON u.id = a.user_id
+LEFT JOIN inspections i ON u.id = i.user_id
+WHERE $1::text = ''
+   OR u.email ILIKE '%' || $1::text || '%'
+   OR u.name ILIKE '%' || $1::text || '%'
+GROUP BY u.id
+ORDER BY u.created_at DESC
+LIMIT $2 OFFSET $3
+`
+
+type AdminSearchUsersParams struct {
+	Search string `json:"search"`
+	Limit  int32  `json:"limit"`
+	Offset int32  `json:"offset"`
+}
+
+type AdminSearchUsersRow struct {
+	ID                 uuid.UUID      `json:"id"`
+	Email              string         `json:"email"`
+	Name               string         `json:"name"`
+	SubscriptionStatus sql.NullString `json:"subscription_status"`
+	SubscriptionTier   sql.NullString `json:"subscription_tier"`
+	EmailVerified      sql.NullBool   `json:"email_verified"`
+	DisabledAt         sql.NullTime   `json:"disabled_at"`
+	CreatedAt          sql.NullTime   `json:"created_at"`
+	TotalCostCents     int64          `json:"total_cost_cents"`
+	InspectionCount    int64          `json:"inspection_count"`
+}
+
+// Search users by email or name (case-insensitive), a page at a time; an empty search lists all users
+// The search is a LIKE pattern: escape % and _ to match them literally
+func (q *Queries) AdminSearchUsers(ctx context.Context, arg AdminSearchUsersParams) ([]AdminSearchUsersRow, error) {
+	rows, err := q.db.QueryContext(ctx, adminSearchUsers, arg.Search, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []AdminSearchUsersRow{}
+	for rows.Next() {
+		var i AdminSearchUsersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Name,
+			&i.SubscriptionStatus,
+			&i.SubscriptionTier,
+			&i.EmailVerified,
+			&i.DisabledAt,
+			&i.CreatedAt,
+			&i.TotalCostCents,
+			&i.InspectionCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const adminUpdateUserDisabled = `-- name: AdminUpdateUserDisabled :execrows
+UPDATE users
+SET disabled_at = $2,
+    updated_at = NOW()
+WHERE id = $1
+`
+
+type AdminUpdateUserDisabledParams struct {
+	ID         uuid.UUID    `json:"id"`
+	DisabledAt sql.NullTime `json:"disabled_at"`
+}
+
+// Enable or disable a user account; a NULL disabled_at re-enables it
+func (q *Queries) AdminUpdateUserDisabled(ctx context.Context, arg AdminUpdateUserDisabledParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, adminUpdateUserDisabled, arg.ID, arg.DisabledAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const adminUpdateUserSubscriptionTier = `-- name: AdminUpdateUserSubscriptionTier :execrows
 UPDATE users
-SET updated_at = NOW()
+SET subscription_tier = $2,
+    updated_at = NOW()
 WHERE id = $1
 `
 
-// Enable or disable a user account
-func (q *Queries) AdminUpdateUserDisabled(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, adminUpdateUserDisabled, id)
-	return err
+type AdminUpdateUserSubscriptionTierParams struct {
+	ID               uuid.UUID      `json:"id"`
+	SubscriptionTier sql.NullString `json:"subscription_tier"`
+}
+
+// Change a user's subscription tier without touching the Stripe subscription
+func (q *Queries) AdminUpdateUserSubscriptionTier(ctx context.Context, arg AdminUpdateUserSubscriptionTierParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, adminUpdateUserSubscriptionTier, arg.ID, arg.SubscriptionTier)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
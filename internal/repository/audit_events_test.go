@@ -0,0 +1,64 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/google/uuid"
+	"github.com/sqlc-dev/pqtype"
+)
+
+// =============================================================================
+// Violation Status History Tests
+// =============================================================================
+
+func TestGetLatestViolationStatusChange(t *testing.T) {
+	ctx := context.Background()
+	q := newTestQueries(t)
+	user := createTestUser(t, q)
+	inspection := createTestInspection(t, q, user.ID, domain.InspectionStatusReview)
+	violation, err := q.CreateViolation(ctx, repository.CreateViolationParams{
+		InspectionID: inspection.ID,
+		Description:  "Missing guardrail",
+		Status:       string(domain.ViolationStatusPending),
+	})
+	if err != nil {
+		t.Fatalf("CreateViolation failed: %v", err)
+	}
+
+	if _, err := q.GetLatestViolationStatusChange(ctx, violation.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected no rows before any status change, got %v", err)
+	}
+
+	record := func(action domain.AuditAction, from, to string) repository.AuditEvent {
+		t.Helper()
+		event, err := q.CreateAuditEvent(ctx, repository.CreateAuditEventParams{
+			ActorUserID:  user.ID,
+			InspectionID: uuid.NullUUID{UUID: inspection.ID, Valid: true},
+			EntityType:   string(domain.AuditEntityViolation),
+			EntityID:     violation.ID,
+			Action:       string(action),
+			OldValues:    pqtype.NullRawMessage{RawMessage: []byte(`{"status":"` + from + `"}`), Valid: true},
+			NewValues:    pqtype.NullRawMessage{RawMessage: []byte(`{"status":"` + to + `"}`), Valid: true},
+		})
+		if err != nil {
+			t.Fatalf("CreateAuditEvent failed: %v", err)
+		}
+		return event
+	}
+	record(domain.AuditActionStatusChanged, "pending", "rejected")
+	latest := record(domain.AuditActionStatusChanged, "rejected", "confirmed")
+	record(domain.AuditActionDeleted, "confirmed", "")
+
+	got, err := q.GetLatestViolationStatusChange(ctx, violation.ID)
+	if err != nil {
+		t.Fatalf("GetLatestViolationStatusChange failed: %v", err)
+	}
+	if got.ID != latest.ID {
+		t.Errorf("expected the latest status change, got %s", string(got.NewValues.RawMessage))
+	}
+}
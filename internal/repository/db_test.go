@@ -0,0 +1,62 @@
+package repository_test
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/DukeRupert/lukaut/internal"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/google/uuid"
+)
+
+var (
+	migrateOnce sync.Once
+	migrateErr  error
+)
+
+// newTestQueries returns queries over a transaction on the PostgreSQL
+// database named by DATABASE_URL, migrated to the latest schema. The
+// transaction is rolled back when the test ends, so tests leave no rows
+// behind. Tests using it are skipped when DATABASE_URL is unset.
+func newTestQueries(t *testing.T) *repository.Queries {
+	t.Helper()
+	url := os.Getenv("DATABASE_URL")
+	if url == "" {
+		t.Skip("DATABASE_URL not set; skipping PostgreSQL integration test")
+	}
+
+	ctx := context.Background()
+	db, err := internal.OpenDB(ctx, url, internal.PoolConfig{MaxOpenConns: 2, MaxIdleConns: 2}, nil)
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	migrateOnce.Do(func() { migrateErr = internal.RunMigrations(db) })
+	if migrateErr != nil {
+		t.Fatalf("run migrations: %v", migrateErr)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("begin transaction: %v", err)
+	}
+	t.Cleanup(func() { _ = tx.Rollback() })
+	return repository.New(tx)
+}
+
+// createTestUser inserts a user with a unique email address.
+func createTestUser(t *testing.T, q *repository.Queries) repository.User {
+	t.Helper()
+	user, err := q.CreateUser(context.Background(), repository.CreateUserParams{
+		Email:        uuid.NewString() + "@example.com",
+		PasswordHash: "hash",
+		Name:         "Test User",
+	})
+	if err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+	return user
+}
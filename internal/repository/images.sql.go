@@ -10,8 +10,23 @@ import (
 	"database/sql"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+const countFailedImagesByInspectionID = `-- name: CountFailedImagesByInspectionID :one
+SELECT COUNT(*) FROM images
+WHERE inspection_id = $1
+AND analysis_status = 'failed'
+`
+
+// Count images whose analysis failed and can be retried
+func (q *Queries) CountFailedImagesByInspectionID(ctx context.Context, inspectionID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countFailedImagesByInspectionID, inspectionID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const countImagesByInspectionID = `-- name: CountImagesByInspectionID :one
 SELECT COUNT(*) FROM images
 WHERE inspection_id = $1
@@ -48,11 +63,14 @@ INSERT INTO images (
     size_bytes,
     width,
     height,
-    analysis_status
+    analysis_status,
+    content_hash,
+    display_order
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10,
+    (SELECT COALESCE(MAX(display_order), 0) + 1 FROM images WHERE inspection_id = $1)
 )
-RETURNING id, inspection_id, storage_key, thumbnail_key, original_filename, content_type, size_bytes, width, height, analysis_status, analysis_completed_at, created_at
+RETURNING id, inspection_id, storage_key, thumbnail_key, original_filename, content_type, size_bytes, width, height, analysis_status, analysis_completed_at, created_at, analysis_error, display_order, content_hash
 `
 
 type CreateImageParams struct {
@@ -65,8 +83,10 @@ type CreateImageParams struct {
 	Width            sql.NullInt32  `json:"width"`
 	Height           sql.NullInt32  `json:"height"`
 	AnalysisStatus   sql.NullString `json:"analysis_status"`
+	ContentHash      sql.NullString `json:"content_hash"`
 }
 
+// Create an image at the end of its inspection's gallery order
 func (q *Queries) CreateImage(ctx context.Context, arg CreateImageParams) (Image, error) {
 	row := q.db.QueryRowContext(ctx, createImage,
 		arg.InspectionID,
@@ -78,6 +98,7 @@ func (q *Queries) CreateImage(ctx context.Context, arg CreateImageParams) (Image
 		arg.Width,
 		arg.Height,
 		arg.AnalysisStatus,
+		arg.ContentHash,
 	)
 	var i Image
 	err := row.Scan(
@@ -93,6 +114,9 @@ func (q *Queries) CreateImage(ctx context.Context, arg CreateImageParams) (Image
 		&i.AnalysisStatus,
 		&i.AnalysisCompletedAt,
 		&i.CreatedAt,
+		&i.AnalysisError,
+		&i.DisplayOrder,
+		&i.ContentHash,
 	)
 	return i, err
 }
@@ -108,7 +132,7 @@ func (q *Queries) DeleteImageByID(ctx context.Context, id uuid.UUID) error {
 }
 
 const getImageByID = `-- name: GetImageByID :one
-SELECT id, inspection_id, storage_key, thumbnail_key, original_filename, content_type, size_bytes, width, height, analysis_status, analysis_completed_at, created_at FROM images
+SELECT id, inspection_id, storage_key, thumbnail_key, original_filename, content_type, size_bytes, width, height, analysis_status, analysis_completed_at, created_at, analysis_error, display_order, content_hash FROM images
 WHERE id = $1
 `
 
@@ -128,12 +152,15 @@ func (q *Queries) GetImageByID(ctx context.Context, id uuid.UUID) (Image, error)
 		&i.AnalysisStatus,
 		&i.AnalysisCompletedAt,
 		&i.CreatedAt,
+		&i.AnalysisError,
+		&i.DisplayOrder,
+		&i.ContentHash,
 	)
 	return i, err
 }
 
 const getImageByIDAndInspectionID = `-- name: GetImageByIDAndInspectionID :one
-SELECT id, inspection_id, storage_key, thumbnail_key, original_filename, content_type, size_bytes, width, height, analysis_status, analysis_completed_at, created_at FROM images
+SELECT id, inspection_id, storage_key, thumbnail_key, original_filename, content_type, size_bytes, width, height, analysis_status, analysis_completed_at, created_at, analysis_error, display_order, content_hash FROM images
 WHERE id = $1 AND inspection_id = $2
 `
 
@@ -158,12 +185,15 @@ func (q *Queries) GetImageByIDAndInspectionID(ctx context.Context, arg GetImageB
 		&i.AnalysisStatus,
 		&i.AnalysisCompletedAt,
 		&i.CreatedAt,
+		&i.AnalysisError,
+		&i.DisplayOrder,
+		&i.ContentHash,
 	)
 	return i, err
 }
 
 const getImageByIDWithInspection = `-- name: GetImageByIDWithInspection :one
-SELECT i.id, i.inspection_id, i.storage_key, i.thumbnail_key, i.original_filename, i.content_type, i.size_bytes, i.width, i.height, i.analysis_status, i.analysis_completed_at, i.created_at, ins.user_id
+SELECT i.id, i.inspection_id, i.storage_key, i.thumbnail_key, i.original_filename, i.content_type, i.size_bytes, i.width, i.height, i.analysis_status, i.analysis_completed_at, i.created_at, i.analysis_error, i.display_order, i.content_hash, ins.user_id
 FROM images i
 JOIN inspections ins ON ins.id = i.inspection_id
 WHERE i.id = $1
@@ -182,6 +212,9 @@ type GetImageByIDWithInspectionRow struct {
 	AnalysisStatus      sql.NullString `json:"analysis_status"`
 	AnalysisCompletedAt sql.NullTime   `json:"analysis_completed_at"`
 	CreatedAt           sql.NullTime   `json:"created_at"`
+	AnalysisError       sql.NullString `json:"analysis_error"`
+	DisplayOrder        int32          `json:"display_order"`
+	ContentHash         sql.NullString `json:"content_hash"`
 	UserID              uuid.UUID      `json:"user_id"`
 }
 
@@ -201,15 +234,18 @@ func (q *Queries) GetImageByIDWithInspection(ctx context.Context, id uuid.UUID)
 		&i.AnalysisStatus,
 		&i.AnalysisCompletedAt,
 		&i.CreatedAt,
+		&i.AnalysisError,
+		&i.DisplayOrder,
+		&i.ContentHash,
 		&i.UserID,
 	)
 	return i, err
 }
 
 const listImagesByInspectionID = `-- name: ListImagesByInspectionID :many
-SELECT id, inspection_id, storage_key, thumbnail_key, original_filename, content_type, size_bytes, width, height, analysis_status, analysis_completed_at, created_at FROM images
+SELECT id, inspection_id, storage_key, thumbnail_key, original_filename, content_type, size_bytes, width, height, analysis_status, analysis_completed_at, created_at, analysis_error, display_order, content_hash FROM images
 WHERE inspection_id = $1
-ORDER BY created_at DESC
+ORDER BY display_order ASC, created_at ASC
 `
 
 func (q *Queries) ListImagesByInspectionID(ctx context.Context, inspectionID uuid.UUID) ([]Image, error) {
@@ -234,6 +270,62 @@ func (q *Queries) ListImagesByInspectionID(ctx context.Context, inspectionID uui
 			&i.AnalysisStatus,
 			&i.AnalysisCompletedAt,
 			&i.CreatedAt,
+			&i.AnalysisError,
+			&i.DisplayOrder,
+			&i.ContentHash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listImagesByInspectionIDAndUserID = `-- name: ListImagesByInspectionIDAndUserID :many
+SELECT img.id, img.inspection_id, img.storage_key, img.thumbnail_key, img.original_filename, img.content_type, img.size_bytes, img.width, img.height, img.analysis_status, img.analysis_completed_at, img.created_at, img.analysis_error, img.display_order, img.content_hash FROM images img
+JOIN inspections ins ON ins.id = img.inspection_id
+WHERE ins.id = $1
+AND ins.user_id = $2
+ORDER BY img.display_order ASC, img.created_at ASC
+`
+
+type ListImagesByInspectionIDAndUserIDParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+// List all images regardless of analysis status with user authorization check
+func (q *Queries) ListImagesByInspectionIDAndUserID(ctx context.Context, arg ListImagesByInspectionIDAndUserIDParams) ([]Image, error) {
+	rows, err := q.db.QueryContext(ctx, listImagesByInspectionIDAndUserID, arg.ID, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Image{}
+	for rows.Next() {
+		var i Image
+		if err := rows.Scan(
+			&i.ID,
+			&i.InspectionID,
+			&i.StorageKey,
+			&i.ThumbnailKey,
+			&i.OriginalFilename,
+			&i.ContentType,
+			&i.SizeBytes,
+			&i.Width,
+			&i.Height,
+			&i.AnalysisStatus,
+			&i.AnalysisCompletedAt,
+			&i.CreatedAt,
+			&i.AnalysisError,
+			&i.DisplayOrder,
+			&i.ContentHash,
 		); err != nil {
 			return nil, err
 		}
@@ -249,7 +341,7 @@ func (q *Queries) ListImagesByInspectionID(ctx context.Context, inspectionID uui
 }
 
 const listPendingImagesByInspectionID = `-- name: ListPendingImagesByInspectionID :many
-SELECT id, inspection_id, storage_key, thumbnail_key, original_filename, content_type, size_bytes, width, height, analysis_status, analysis_completed_at, created_at FROM images
+SELECT id, inspection_id, storage_key, thumbnail_key, original_filename, content_type, size_bytes, width, height, analysis_status, analysis_completed_at, created_at, analysis_error, display_order, content_hash FROM images
 WHERE inspection_id = $1
 AND analysis_status = 'pending'
 ORDER BY created_at ASC
@@ -277,6 +369,9 @@ func (q *Queries) ListPendingImagesByInspectionID(ctx context.Context, inspectio
 			&i.AnalysisStatus,
 			&i.AnalysisCompletedAt,
 			&i.CreatedAt,
+			&i.AnalysisError,
+			&i.DisplayOrder,
+			&i.ContentHash,
 		); err != nil {
 			return nil, err
 		}
@@ -292,7 +387,7 @@ func (q *Queries) ListPendingImagesByInspectionID(ctx context.Context, inspectio
 }
 
 const listPendingImagesByInspectionIDAndUserID = `-- name: ListPendingImagesByInspectionIDAndUserID :many
-SELECT img.id, img.inspection_id, img.storage_key, img.thumbnail_key, img.original_filename, img.content_type, img.size_bytes, img.width, img.height, img.analysis_status, img.analysis_completed_at, img.created_at FROM images img
+SELECT img.id, img.inspection_id, img.storage_key, img.thumbnail_key, img.original_filename, img.content_type, img.size_bytes, img.width, img.height, img.analysis_status, img.analysis_completed_at, img.created_at, img.analysis_error, img.display_order, img.content_hash FROM images img
 JOIN inspections ins ON ins.id = img.inspection_id
 WHERE ins.id = $1
 AND ins.user_id = $2
@@ -328,6 +423,9 @@ func (q *Queries) ListPendingImagesByInspectionIDAndUserID(ctx context.Context,
 			&i.AnalysisStatus,
 			&i.AnalysisCompletedAt,
 			&i.CreatedAt,
+			&i.AnalysisError,
+			&i.DisplayOrder,
+			&i.ContentHash,
 		); err != nil {
 			return nil, err
 		}
@@ -342,6 +440,52 @@ func (q *Queries) ListPendingImagesByInspectionIDAndUserID(ctx context.Context,
 	return items, nil
 }
 
+const markImageAnalysisCompletedWithAuth = `-- name: MarkImageAnalysisCompletedWithAuth :exec
+UPDATE images
+SET analysis_status = 'completed',
+    analysis_completed_at = NOW(),
+    analysis_error = NULL
+FROM inspections
+WHERE images.id = $1
+AND images.inspection_id = inspections.id
+AND inspections.user_id = $2
+`
+
+type MarkImageAnalysisCompletedWithAuthParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+// Mark image analysis completed and clear any earlier failure, with user
+// authorization check
+func (q *Queries) MarkImageAnalysisCompletedWithAuth(ctx context.Context, arg MarkImageAnalysisCompletedWithAuthParams) error {
+	_, err := q.db.ExecContext(ctx, markImageAnalysisCompletedWithAuth, arg.ID, arg.UserID)
+	return err
+}
+
+const markImageAnalysisFailedWithAuth = `-- name: MarkImageAnalysisFailedWithAuth :exec
+UPDATE images
+SET analysis_status = 'failed',
+    analysis_completed_at = NULL,
+    analysis_error = $3
+FROM inspections
+WHERE images.id = $1
+AND images.inspection_id = inspections.id
+AND inspections.user_id = $2
+`
+
+type MarkImageAnalysisFailedWithAuthParams struct {
+	ID            uuid.UUID      `json:"id"`
+	UserID        uuid.UUID      `json:"user_id"`
+	AnalysisError sql.NullString `json:"analysis_error"`
+}
+
+// Mark image analysis failed with the reason, with user authorization check
+func (q *Queries) MarkImageAnalysisFailedWithAuth(ctx context.Context, arg MarkImageAnalysisFailedWithAuthParams) error {
+	_, err := q.db.ExecContext(ctx, markImageAnalysisFailedWithAuth, arg.ID, arg.UserID, arg.AnalysisError)
+	return err
+}
+
 const updateImageAnalysisStatus = `-- name: UpdateImageAnalysisStatus :exec
 UPDATE images
 SET analysis_status = $2,
@@ -387,3 +531,26 @@ func (q *Queries) UpdateImageAnalysisStatusWithAuth(ctx context.Context, arg Upd
 	)
 	return err
 }
+
+const updateImageDisplayOrder = `-- name: UpdateImageDisplayOrder :execrows
+UPDATE images
+SET display_order = ordered.position
+FROM unnest($1::uuid[]) WITH ORDINALITY AS ordered(id, position)
+WHERE images.id = ordered.id
+AND images.inspection_id = $2
+`
+
+type UpdateImageDisplayOrderParams struct {
+	ImageIds     []uuid.UUID `json:"image_ids"`
+	InspectionID uuid.UUID   `json:"inspection_id"`
+}
+
+// Set the display order of an inspection's images to their position in
+// image_ids (starting at 1). Images of other inspections are not touched.
+func (q *Queries) UpdateImageDisplayOrder(ctx context.Context, arg UpdateImageDisplayOrderParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateImageDisplayOrder, pq.Array(arg.ImageIds), arg.InspectionID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -25,6 +25,23 @@ func (q *Queries) CountInspectionsByUserID(ctx context.Context, userID uuid.UUID
 	return count, err
 }
 
+const countOrganizationInspectionsByUserID = `-- name: CountOrganizationInspectionsByUserID :one
+SELECT COUNT(*) FROM inspections i
+WHERE (i.user_id = $1 OR i.user_id IN (
+    SELECT them.user_id FROM organization_members me
+    JOIN organization_members them ON them.organization_id = me.organization_id
+    WHERE me.user_id = $1
+))
+`
+
+// Count the inspections of the user and the members of their organization.
+func (q *Queries) CountOrganizationInspectionsByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countOrganizationInspectionsByUserID, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createInspection = `-- name: CreateInspection :one
 INSERT INTO inspections (
     user_id,
@@ -43,7 +60,7 @@ INSERT INTO inspections (
 ) VALUES (
     $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
 )
-RETURNING id, user_id, title, status, inspection_date, weather_conditions, temperature, inspector_notes, created_at, updated_at, address_line1, address_line2, city, state, postal_code, client_id
+RETURNING id, user_id, title, status, inspection_date, weather_conditions, temperature, inspector_notes, created_at, updated_at, address_line1, address_line2, city, state, postal_code, client_id, latitude, longitude, version, assigned_to, is_template, template_name, legal_hold
 `
 
 type CreateInspectionParams struct {
@@ -96,6 +113,13 @@ func (q *Queries) CreateInspection(ctx context.Context, arg CreateInspectionPara
 		&i.State,
 		&i.PostalCode,
 		&i.ClientID,
+		&i.Latitude,
+		&i.Longitude,
+		&i.Version,
+		&i.AssignedTo,
+		&i.IsTemplate,
+		&i.TemplateName,
+		&i.LegalHold,
 	)
 	return i, err
 }
@@ -126,7 +150,7 @@ func (q *Queries) DeleteInspectionByIDAndUserID(ctx context.Context, arg DeleteI
 }
 
 const getInspectionByID = `-- name: GetInspectionByID :one
-SELECT id, user_id, title, status, inspection_date, weather_conditions, temperature, inspector_notes, created_at, updated_at, address_line1, address_line2, city, state, postal_code, client_id FROM inspections
+SELECT id, user_id, title, status, inspection_date, weather_conditions, temperature, inspector_notes, created_at, updated_at, address_line1, address_line2, city, state, postal_code, client_id, latitude, longitude, version, assigned_to, is_template, template_name, legal_hold FROM inspections
 WHERE id = $1
 `
 
@@ -150,12 +174,19 @@ func (q *Queries) GetInspectionByID(ctx context.Context, id uuid.UUID) (Inspecti
 		&i.State,
 		&i.PostalCode,
 		&i.ClientID,
+		&i.Latitude,
+		&i.Longitude,
+		&i.Version,
+		&i.AssignedTo,
+		&i.IsTemplate,
+		&i.TemplateName,
+		&i.LegalHold,
 	)
 	return i, err
 }
 
 const getInspectionByIDAndUserID = `-- name: GetInspectionByIDAndUserID :one
-SELECT id, user_id, title, status, inspection_date, weather_conditions, temperature, inspector_notes, created_at, updated_at, address_line1, address_line2, city, state, postal_code, client_id FROM inspections
+SELECT id, user_id, title, status, inspection_date, weather_conditions, temperature, inspector_notes, created_at, updated_at, address_line1, address_line2, city, state, postal_code, client_id, latitude, longitude, version, assigned_to, is_template, template_name, legal_hold FROM inspections
 WHERE id = $1 AND user_id = $2
 `
 
@@ -184,6 +215,13 @@ func (q *Queries) GetInspectionByIDAndUserID(ctx context.Context, arg GetInspect
 		&i.State,
 		&i.PostalCode,
 		&i.ClientID,
+		&i.Latitude,
+		&i.Longitude,
+		&i.Version,
+		&i.AssignedTo,
+		&i.IsTemplate,
+		&i.TemplateName,
+		&i.LegalHold,
 	)
 	return i, err
 }
@@ -206,9 +244,15 @@ SELECT
     i.postal_code,
     i.created_at,
     i.updated_at,
-    COALESCE(c.name, '') AS client_name
+    i.version,
+    i.assigned_to,
+    i.is_template,
+    i.template_name,
+    COALESCE(c.name, '') AS client_name,
+    COALESCE(a.name, '') AS assignee_name
 FROM inspections i
 LEFT JOIN clients c ON c.id = i.client_id
+LEFT JOIN users a ON a.id = i.assigned_to
 WHERE i.id = $1 AND i.user_id = $2
 `
 
@@ -234,7 +278,12 @@ type GetInspectionWithClientByIDAndUserIDRow struct {
 	PostalCode        string         `json:"postal_code"`
 	CreatedAt         sql.NullTime   `json:"created_at"`
 	UpdatedAt         sql.NullTime   `json:"updated_at"`
+	Version           int32          `json:"version"`
+	AssignedTo        uuid.NullUUID  `json:"assigned_to"`
+	IsTemplate        bool           `json:"is_template"`
+	TemplateName      sql.NullString `json:"template_name"`
 	ClientName        string         `json:"client_name"`
+	AssigneeName      string         `json:"assignee_name"`
 }
 
 func (q *Queries) GetInspectionWithClientByIDAndUserID(ctx context.Context, arg GetInspectionWithClientByIDAndUserIDParams) (GetInspectionWithClientByIDAndUserIDRow, error) {
@@ -257,13 +306,154 @@ func (q *Queries) GetInspectionWithClientByIDAndUserID(ctx context.Context, arg
 		&i.PostalCode,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Version,
+		&i.AssignedTo,
+		&i.IsTemplate,
+		&i.TemplateName,
 		&i.ClientName,
+		&i.AssigneeName,
 	)
 	return i, err
 }
 
+const listInspectionLocationsByUserID = `-- name: ListInspectionLocationsByUserID :many
+SELECT
+    id,
+    title,
+    status,
+    inspection_date,
+    address_line1,
+    city,
+    state,
+    latitude,
+    longitude
+FROM inspections
+WHERE user_id = $1
+  AND latitude IS NOT NULL
+  AND longitude IS NOT NULL
+ORDER BY inspection_date DESC
+LIMIT $2
+`
+
+type ListInspectionLocationsByUserIDParams struct {
+	UserID uuid.UUID `json:"user_id"`
+	Limit  int32     `json:"limit"`
+}
+
+type ListInspectionLocationsByUserIDRow struct {
+	ID             uuid.UUID       `json:"id"`
+	Title          string          `json:"title"`
+	Status         string          `json:"status"`
+	InspectionDate time.Time       `json:"inspection_date"`
+	AddressLine1   string          `json:"address_line1"`
+	City           string          `json:"city"`
+	State          string          `json:"state"`
+	Latitude       sql.NullFloat64 `json:"latitude"`
+	Longitude      sql.NullFloat64 `json:"longitude"`
+}
+
+// Geocoded inspections for the dashboard map, most recent first.
+func (q *Queries) ListInspectionLocationsByUserID(ctx context.Context, arg ListInspectionLocationsByUserIDParams) ([]ListInspectionLocationsByUserIDRow, error) {
+	rows, err := q.db.QueryContext(ctx, listInspectionLocationsByUserID, arg.UserID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListInspectionLocationsByUserIDRow{}
+	for rows.Next() {
+		var i ListInspectionLocationsByUserIDRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Status,
+			&i.InspectionDate,
+			&i.AddressLine1,
+			&i.City,
+			&i.State,
+			&i.Latitude,
+			&i.Longitude,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInspectionTemplatesByUserID = `-- name: ListInspectionTemplatesByUserID :many
+SELECT
+    i.id,
+    i.template_name,
+    i.title,
+    i.address_line1,
+    i.address_line2,
+    i.city,
+    i.state,
+    i.postal_code,
+    i.updated_at,
+    COALESCE(c.name, '') AS client_name
+FROM inspections i
+LEFT JOIN clients c ON c.id = i.client_id
+WHERE i.user_id = $1 AND i.is_template
+ORDER BY LOWER(i.template_name), i.id
+`
+
+type ListInspectionTemplatesByUserIDRow struct {
+	ID           uuid.UUID      `json:"id"`
+	TemplateName sql.NullString `json:"template_name"`
+	Title        string         `json:"title"`
+	AddressLine1 string         `json:"address_line1"`
+	AddressLine2 sql.NullString `json:"address_line2"`
+	City         string         `json:"city"`
+	State        string         `json:"state"`
+	PostalCode   string         `json:"postal_code"`
+	UpdatedAt    sql.NullTime   `json:"updated_at"`
+	ClientName   string         `json:"client_name"`
+}
+
+// The user's templates, by name.
+func (q *Queries) ListInspectionTemplatesByUserID(ctx context.Context, userID uuid.UUID) ([]ListInspectionTemplatesByUserIDRow, error) {
+	rows, err := q.db.QueryContext(ctx, listInspectionTemplatesByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListInspectionTemplatesByUserIDRow{}
+	for rows.Next() {
+		var i ListInspectionTemplatesByUserIDRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.TemplateName,
+			&i.Title,
+			&i.AddressLine1,
+			&i.AddressLine2,
+			&i.City,
+			&i.State,
+			&i.PostalCode,
+			&i.UpdatedAt,
+			&i.ClientName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listInspectionsByUserID = `-- name: ListInspectionsByUserID :many
-SELECT id, user_id, title, status, inspection_date, weather_conditions, temperature, inspector_notes, created_at, updated_at, address_line1, address_line2, city, state, postal_code, client_id FROM inspections
+SELECT id, user_id, title, status, inspection_date, weather_conditions, temperature, inspector_notes, created_at, updated_at, address_line1, address_line2, city, state, postal_code, client_id, latitude, longitude, version, assigned_to, is_template, template_name, legal_hold FROM inspections
 WHERE user_id = $1
 ORDER BY created_at DESC
 LIMIT $2 OFFSET $3
@@ -301,6 +491,155 @@ func (q *Queries) ListInspectionsByUserID(ctx context.Context, arg ListInspectio
 			&i.State,
 			&i.PostalCode,
 			&i.ClientID,
+			&i.Latitude,
+			&i.Longitude,
+			&i.Version,
+			&i.AssignedTo,
+			&i.IsTemplate,
+			&i.TemplateName,
+			&i.LegalHold,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInspectionsDueForPurge = `-- name: ListInspectionsDueForPurge :many
+SELECT id, user_id, title, updated_at FROM inspections
+WHERE status = 'completed'
+AND NOT legal_hold
+AND NOT is_template
+AND updated_at < $1
+ORDER BY updated_at
+LIMIT $2
+`
+
+type ListInspectionsDueForPurgeParams struct {
+	Cutoff    sql.NullTime `json:"cutoff"`
+	BatchSize int32        `json:"batch_size"`
+}
+
+type ListInspectionsDueForPurgeRow struct {
+	ID        uuid.UUID    `json:"id"`
+	UserID    uuid.UUID    `json:"user_id"`
+	Title     string       `json:"title"`
+	UpdatedAt sql.NullTime `json:"updated_at"`
+}
+
+// Completed inspections last changed before the retention cutoff and not
+// under legal hold, oldest first
+func (q *Queries) ListInspectionsDueForPurge(ctx context.Context, arg ListInspectionsDueForPurgeParams) ([]ListInspectionsDueForPurgeRow, error) {
+	rows, err := q.db.QueryContext(ctx, listInspectionsDueForPurge, arg.Cutoff, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListInspectionsDueForPurgeRow{}
+	for rows.Next() {
+		var i ListInspectionsDueForPurgeRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Title,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInspectionsNear = `-- name: ListInspectionsNear :many
+SELECT id, title, status, inspection_date, address_line1, city, state, latitude, longitude, distance_meters FROM (
+    SELECT
+        id,
+        title,
+        status,
+        inspection_date,
+        address_line1,
+        city,
+        state,
+        latitude,
+        longitude,
+        (6371000 * 2 * ASIN(SQRT(
+            POWER(SIN(RADIANS(latitude - $1::float8) / 2), 2)
+            + COS(RADIANS($1::float8)) * COS(RADIANS(latitude))
+            * POWER(SIN(RADIANS(longitude - $2::float8) / 2), 2)
+        )))::float8 AS distance_meters
+    FROM inspections
+    WHERE user_id = $3
+      AND latitude IS NOT NULL
+      AND longitude IS NOT NULL
+) AS nearby
+WHERE distance_meters <= $4::float8
+ORDER BY distance_meters
+LIMIT $5
+`
+
+type ListInspectionsNearParams struct {
+	Latitude     float64   `json:"latitude"`
+	Longitude    float64   `json:"longitude"`
+	UserID       uuid.UUID `json:"user_id"`
+	RadiusMeters float64   `json:"radius_meters"`
+	MaxResults   int32     `json:"max_results"`
+}
+
+type ListInspectionsNearRow struct {
+	ID             uuid.UUID       `json:"id"`
+	Title          string          `json:"title"`
+	Status         string          `json:"status"`
+	InspectionDate time.Time       `json:"inspection_date"`
+	AddressLine1   string          `json:"address_line1"`
+	City           string          `json:"city"`
+	State          string          `json:"state"`
+	Latitude       sql.NullFloat64 `json:"latitude"`
+	Longitude      sql.NullFloat64 `json:"longitude"`
+	DistanceMeters float64         `json:"distance_meters"`
+}
+
+// The user's geocoded inspections within radius_meters of a point, nearest
+// first. Distance is the haversine great-circle distance in meters.
+func (q *Queries) ListInspectionsNear(ctx context.Context, arg ListInspectionsNearParams) ([]ListInspectionsNearRow, error) {
+	rows, err := q.db.QueryContext(ctx, listInspectionsNear,
+		arg.Latitude,
+		arg.Longitude,
+		arg.UserID,
+		arg.RadiusMeters,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListInspectionsNearRow{}
+	for rows.Next() {
+		var i ListInspectionsNearRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Status,
+			&i.InspectionDate,
+			&i.AddressLine1,
+			&i.City,
+			&i.State,
+			&i.Latitude,
+			&i.Longitude,
+			&i.DistanceMeters,
 		); err != nil {
 			return nil, err
 		}
@@ -343,14 +682,29 @@ GROUP BY i.id, i.user_id, i.client_id, i.title, i.status, i.inspection_date,
          i.weather_conditions, i.temperature, i.inspector_notes,
          i.address_line1, i.address_line2, i.city, i.state, i.postal_code,
          i.created_at, i.updated_at, c.name
-ORDER BY i.created_at DESC
+ORDER BY
+    CASE WHEN $4::text = 'inspection_date' AND NOT $5::bool THEN i.inspection_date END ASC,
+    CASE WHEN $4::text = 'inspection_date' AND $5::bool THEN i.inspection_date END DESC,
+    CASE WHEN $4::text = 'title' AND NOT $5::bool THEN LOWER(i.title) END ASC,
+    CASE WHEN $4::text = 'title' AND $5::bool THEN LOWER(i.title) END DESC,
+    CASE WHEN $4::text = 'client_name' AND NOT $5::bool THEN LOWER(COALESCE(c.name, '')) END ASC,
+    CASE WHEN $4::text = 'client_name' AND $5::bool THEN LOWER(COALESCE(c.name, '')) END DESC,
+    CASE WHEN $4::text = 'violation_count' AND NOT $5::bool THEN COUNT(v.id) END ASC,
+    CASE WHEN $4::text = 'violation_count' AND $5::bool THEN COUNT(v.id) END DESC,
+    CASE WHEN $4::text = 'status' AND NOT $5::bool THEN i.status END ASC,
+    CASE WHEN $4::text = 'status' AND $5::bool THEN i.status END DESC,
+    CASE WHEN $4::text = 'created_at' AND NOT $5::bool THEN i.created_at END ASC,
+    i.created_at DESC,
+    i.id
 LIMIT $2 OFFSET $3
 `
 
 type ListInspectionsWithClientByUserIDParams struct {
-	UserID uuid.UUID `json:"user_id"`
-	Limit  int32     `json:"limit"`
-	Offset int32     `json:"offset"`
+	UserID   uuid.UUID `json:"user_id"`
+	Limit    int32     `json:"limit"`
+	Offset   int32     `json:"offset"`
+	SortBy   string    `json:"sort_by"`
+	SortDesc bool      `json:"sort_desc"`
 }
 
 type ListInspectionsWithClientByUserIDRow struct {
@@ -374,8 +728,17 @@ type ListInspectionsWithClientByUserIDRow struct {
 	ViolationCount    int32          `json:"violation_count"`
 }
 
+// List the user's inspections ordered by sort_by, one of inspection_date,
+// created_at, title, client_name, violation_count, or status. Ties, and
+// unknown columns, fall back to newest first.
 func (q *Queries) ListInspectionsWithClientByUserID(ctx context.Context, arg ListInspectionsWithClientByUserIDParams) ([]ListInspectionsWithClientByUserIDRow, error) {
-	rows, err := q.db.QueryContext(ctx, listInspectionsWithClientByUserID, arg.UserID, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, listInspectionsWithClientByUserID,
+		arg.UserID,
+		arg.Limit,
+		arg.Offset,
+		arg.SortBy,
+		arg.SortDesc,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -416,6 +779,143 @@ func (q *Queries) ListInspectionsWithClientByUserID(ctx context.Context, arg Lis
 	return items, nil
 }
 
+const listOrganizationInspectionsWithClientByUserID = `-- name: ListOrganizationInspectionsWithClientByUserID :many
+SELECT
+    i.id,
+    i.user_id,
+    i.client_id,
+    i.title,
+    i.status,
+    i.inspection_date,
+    i.weather_conditions,
+    i.temperature,
+    i.inspector_notes,
+    i.address_line1,
+    i.address_line2,
+    i.city,
+    i.state,
+    i.postal_code,
+    i.created_at,
+    i.updated_at,
+    COALESCE(c.name, '') AS client_name,
+    u.name AS owner_name,
+    COALESCE(a.name, '') AS assignee_name,
+    COALESCE(COUNT(v.id), 0)::int AS violation_count
+FROM inspections i
+JOIN users u ON u.id = i.user_id
+LEFT JOIN users a ON a.id = i.assigned_to
+LEFT JOIN clients c ON c.id = i.client_id
+LEFT JOIN violations v ON v.inspection_id = i.id
+WHERE (i.user_id = $1 OR i.user_id IN (
+    SELECT them.user_id FROM organization_members me
+    JOIN organization_members them ON them.organization_id = me.organization_id
+    WHERE me.user_id = $1
+))
+GROUP BY i.id, i.user_id, i.client_id, i.title, i.status, i.inspection_date,
+         i.weather_conditions, i.temperature, i.inspector_notes,
+         i.address_line1, i.address_line2, i.city, i.state, i.postal_code,
+         i.created_at, i.updated_at, c.name, u.name, a.name
+ORDER BY
+    CASE WHEN $4::text = 'inspection_date' AND NOT $5::bool THEN i.inspection_date END ASC,
+    CASE WHEN $4::text = 'inspection_date' AND $5::bool THEN i.inspection_date END DESC,
+    CASE WHEN $4::text = 'title' AND NOT $5::bool THEN LOWER(i.title) END ASC,
+    CASE WHEN $4::text = 'title' AND $5::bool THEN LOWER(i.title) END DESC,
+    CASE WHEN $4::text = 'client_name' AND NOT $5::bool THEN LOWER(COALESCE(c.name, '')) END ASC,
+    CASE WHEN $4::text = 'client_name' AND $5::bool THEN LOWER(COALESCE(c.name, '')) END DESC,
+    CASE WHEN $4::text = 'violation_count' AND NOT $5::bool THEN COUNT(v.id) END ASC,
+    CASE WHEN $4::text = 'violation_count' AND $5::bool THEN COUNT(v.id) END DESC,
+    CASE WHEN $4::text = 'status' AND NOT $5::bool THEN i.status END ASC,
+    CASE WHEN $4::text = 'status' AND $5::bool THEN i.status END DESC,
+    CASE WHEN $4::text = 'created_at' AND NOT $5::bool THEN i.created_at END ASC,
+    i.created_at DESC,
+    i.id
+LIMIT $2 OFFSET $3
+`
+
+type ListOrganizationInspectionsWithClientByUserIDParams struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Limit    int32     `json:"limit"`
+	Offset   int32     `json:"offset"`
+	SortBy   string    `json:"sort_by"`
+	SortDesc bool      `json:"sort_desc"`
+}
+
+type ListOrganizationInspectionsWithClientByUserIDRow struct {
+	ID                uuid.UUID      `json:"id"`
+	UserID            uuid.UUID      `json:"user_id"`
+	ClientID          uuid.NullUUID  `json:"client_id"`
+	Title             string         `json:"title"`
+	Status            string         `json:"status"`
+	InspectionDate    time.Time      `json:"inspection_date"`
+	WeatherConditions sql.NullString `json:"weather_conditions"`
+	Temperature       sql.NullString `json:"temperature"`
+	InspectorNotes    sql.NullString `json:"inspector_notes"`
+	AddressLine1      string         `json:"address_line1"`
+	AddressLine2      sql.NullString `json:"address_line2"`
+	City              string         `json:"city"`
+	State             string         `json:"state"`
+	PostalCode        string         `json:"postal_code"`
+	CreatedAt         sql.NullTime   `json:"created_at"`
+	UpdatedAt         sql.NullTime   `json:"updated_at"`
+	ClientName        string         `json:"client_name"`
+	OwnerName         string         `json:"owner_name"`
+	AssigneeName      string         `json:"assignee_name"`
+	ViolationCount    int32          `json:"violation_count"`
+}
+
+// List the inspections of the user and the members of their organization,
+// with the names of each inspection's owner and assignee, ordered as in
+// ListInspectionsWithClientByUserID.
+func (q *Queries) ListOrganizationInspectionsWithClientByUserID(ctx context.Context, arg ListOrganizationInspectionsWithClientByUserIDParams) ([]ListOrganizationInspectionsWithClientByUserIDRow, error) {
+	rows, err := q.db.QueryContext(ctx, listOrganizationInspectionsWithClientByUserID,
+		arg.UserID,
+		arg.Limit,
+		arg.Offset,
+		arg.SortBy,
+		arg.SortDesc,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListOrganizationInspectionsWithClientByUserIDRow{}
+	for rows.Next() {
+		var i ListOrganizationInspectionsWithClientByUserIDRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.ClientID,
+			&i.Title,
+			&i.Status,
+			&i.InspectionDate,
+			&i.WeatherConditions,
+			&i.Temperature,
+			&i.InspectorNotes,
+			&i.AddressLine1,
+			&i.AddressLine2,
+			&i.City,
+			&i.State,
+			&i.PostalCode,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ClientName,
+			&i.OwnerName,
+			&i.AssigneeName,
+			&i.ViolationCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listRecentInspectionsWithViolationCount = `-- name: ListRecentInspectionsWithViolationCount :many
 SELECT
     i.id,
@@ -509,6 +1009,50 @@ func (q *Queries) ListRecentInspectionsWithViolationCount(ctx context.Context, a
 	return items, nil
 }
 
+const purgeInspection = `-- name: PurgeInspection :execrows
+DELETE FROM inspections
+WHERE id = $1
+AND status = 'completed'
+AND NOT legal_hold
+AND NOT is_template
+AND updated_at < $2
+`
+
+type PurgeInspectionParams struct {
+	ID     uuid.UUID    `json:"id"`
+	Cutoff sql.NullTime `json:"cutoff"`
+}
+
+// Deletes an inspection that is still due for purge; matches no row if it
+// was reopened, edited, or put under legal hold since it was listed
+func (q *Queries) PurgeInspection(ctx context.Context, arg PurgeInspectionParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeInspection, arg.ID, arg.Cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setInspectionLegalHold = `-- name: SetInspectionLegalHold :execrows
+UPDATE inspections
+SET legal_hold = $2
+WHERE id = $1
+`
+
+type SetInspectionLegalHoldParams struct {
+	ID        uuid.UUID `json:"id"`
+	LegalHold bool      `json:"legal_hold"`
+}
+
+// Leaves updated_at alone so the hold does not restart the retention period
+func (q *Queries) SetInspectionLegalHold(ctx context.Context, arg SetInspectionLegalHoldParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setInspectionLegalHold, arg.ID, arg.LegalHold)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const updateInspection = `-- name: UpdateInspection :exec
 UPDATE inspections
 SET title = $2,
@@ -559,7 +1103,29 @@ func (q *Queries) UpdateInspection(ctx context.Context, arg UpdateInspectionPara
 	return err
 }
 
-const updateInspectionByIDAndUserID = `-- name: UpdateInspectionByIDAndUserID :exec
+const updateInspectionAssigneeByIDAndUserID = `-- name: UpdateInspectionAssigneeByIDAndUserID :execrows
+UPDATE inspections
+SET assigned_to = $3,
+    updated_at = NOW()
+WHERE id = $1 AND user_id = $2
+`
+
+type UpdateInspectionAssigneeByIDAndUserIDParams struct {
+	ID         uuid.UUID     `json:"id"`
+	UserID     uuid.UUID     `json:"user_id"`
+	AssignedTo uuid.NullUUID `json:"assigned_to"`
+}
+
+// Assign the inspection to a user, or unassign it with NULL.
+func (q *Queries) UpdateInspectionAssigneeByIDAndUserID(ctx context.Context, arg UpdateInspectionAssigneeByIDAndUserIDParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateInspectionAssigneeByIDAndUserID, arg.ID, arg.UserID, arg.AssignedTo)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateInspectionByIDAndUserID = `-- name: UpdateInspectionByIDAndUserID :execrows
 UPDATE inspections
 SET title = $3,
     client_id = $4,
@@ -572,8 +1138,9 @@ SET title = $3,
     city = $11,
     state = $12,
     postal_code = $13,
+    version = version + 1,
     updated_at = NOW()
-WHERE id = $1 AND user_id = $2
+WHERE id = $1 AND user_id = $2 AND version = $14
 `
 
 type UpdateInspectionByIDAndUserIDParams struct {
@@ -590,10 +1157,13 @@ type UpdateInspectionByIDAndUserIDParams struct {
 	City              string         `json:"city"`
 	State             string         `json:"state"`
 	PostalCode        string         `json:"postal_code"`
+	Version           int32          `json:"version"`
 }
 
-func (q *Queries) UpdateInspectionByIDAndUserID(ctx context.Context, arg UpdateInspectionByIDAndUserIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateInspectionByIDAndUserID,
+// Apply an edit only if the row is still at the version the editor loaded.
+// Zero rows affected means someone else saved first (or the row is gone).
+func (q *Queries) UpdateInspectionByIDAndUserID(ctx context.Context, arg UpdateInspectionByIDAndUserIDParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateInspectionByIDAndUserID,
 		arg.ID,
 		arg.UserID,
 		arg.Title,
@@ -607,6 +1177,35 @@ func (q *Queries) UpdateInspectionByIDAndUserID(ctx context.Context, arg UpdateI
 		arg.City,
 		arg.State,
 		arg.PostalCode,
+		arg.Version,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateInspectionLocationByIDAndUserID = `-- name: UpdateInspectionLocationByIDAndUserID :exec
+UPDATE inspections
+SET latitude = $3,
+    longitude = $4
+WHERE id = $1 AND user_id = $2
+`
+
+type UpdateInspectionLocationByIDAndUserIDParams struct {
+	ID        uuid.UUID       `json:"id"`
+	UserID    uuid.UUID       `json:"user_id"`
+	Latitude  sql.NullFloat64 `json:"latitude"`
+	Longitude sql.NullFloat64 `json:"longitude"`
+}
+
+// Store (or clear, with NULLs) the geocoded coordinates of the address.
+func (q *Queries) UpdateInspectionLocationByIDAndUserID(ctx context.Context, arg UpdateInspectionLocationByIDAndUserIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateInspectionLocationByIDAndUserID,
+		arg.ID,
+		arg.UserID,
+		arg.Latitude,
+		arg.Longitude,
 	)
 	return err
 }
@@ -645,3 +1244,62 @@ func (q *Queries) UpdateInspectionStatusByIDAndUserID(ctx context.Context, arg U
 	_, err := q.db.ExecContext(ctx, updateInspectionStatusByIDAndUserID, arg.ID, arg.UserID, arg.Status)
 	return err
 }
+
+const updateInspectionTemplateByIDAndUserID = `-- name: UpdateInspectionTemplateByIDAndUserID :execrows
+UPDATE inspections
+SET is_template = $3,
+    template_name = $4,
+    updated_at = NOW()
+WHERE id = $1 AND user_id = $2
+`
+
+type UpdateInspectionTemplateByIDAndUserIDParams struct {
+	ID           uuid.UUID      `json:"id"`
+	UserID       uuid.UUID      `json:"user_id"`
+	IsTemplate   bool           `json:"is_template"`
+	TemplateName sql.NullString `json:"template_name"`
+}
+
+// Keep the inspection as a template under a name, or stop with false and NULL.
+func (q *Queries) UpdateInspectionTemplateByIDAndUserID(ctx context.Context, arg UpdateInspectionTemplateByIDAndUserIDParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateInspectionTemplateByIDAndUserID,
+		arg.ID,
+		arg.UserID,
+		arg.IsTemplate,
+		arg.TemplateName,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateInspectionWeatherIfEmptyByIDAndUserID = `-- name: UpdateInspectionWeatherIfEmptyByIDAndUserID :execrows
+UPDATE inspections
+SET weather_conditions = CASE WHEN COALESCE(weather_conditions, '') = '' THEN $3 ELSE weather_conditions END,
+    temperature = CASE WHEN COALESCE(temperature, '') = '' THEN $4 ELSE temperature END
+WHERE id = $1 AND user_id = $2
+AND (COALESCE(weather_conditions, '') = '' OR COALESCE(temperature, '') = '')
+`
+
+type UpdateInspectionWeatherIfEmptyByIDAndUserIDParams struct {
+	ID                uuid.UUID      `json:"id"`
+	UserID            uuid.UUID      `json:"user_id"`
+	WeatherConditions sql.NullString `json:"weather_conditions"`
+	Temperature       sql.NullString `json:"temperature"`
+}
+
+// Fill in weather fields the inspector left empty; entered values are kept.
+// Matches no row when both fields are already set.
+func (q *Queries) UpdateInspectionWeatherIfEmptyByIDAndUserID(ctx context.Context, arg UpdateInspectionWeatherIfEmptyByIDAndUserIDParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateInspectionWeatherIfEmptyByIDAndUserID,
+		arg.ID,
+		arg.UserID,
+		arg.WeatherConditions,
+		arg.Temperature,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,117 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/google/uuid"
+)
+
+// createTestInspection inserts an inspection of the user's in the given
+// status.
+func createTestInspection(t *testing.T, q *repository.Queries, userID uuid.UUID, status domain.InspectionStatus) repository.Inspection {
+	t.Helper()
+	inspection, err := q.CreateInspection(context.Background(), repository.CreateInspectionParams{
+		UserID:         userID,
+		Title:          "Site walk",
+		Status:         status.String(),
+		InspectionDate: time.Now(),
+		AddressLine1:   "1 Main St",
+		City:           "Springfield",
+		State:          "IL",
+		PostalCode:     "62701",
+	})
+	if err != nil {
+		t.Fatalf("CreateInspection failed: %v", err)
+	}
+	return inspection
+}
+
+// =============================================================================
+// Retention Purge Tests
+// =============================================================================
+
+func TestInspectionPurge_OnlyCompletedUnheldInspections(t *testing.T) {
+	ctx := context.Background()
+	q := newTestQueries(t)
+	user := createTestUser(t, q)
+
+	due := createTestInspection(t, q, user.ID, domain.InspectionStatusCompleted)
+	inReview := createTestInspection(t, q, user.ID, domain.InspectionStatusReview)
+	held := createTestInspection(t, q, user.ID, domain.InspectionStatusCompleted)
+	if _, err := q.SetInspectionLegalHold(ctx, repository.SetInspectionLegalHoldParams{ID: held.ID, LegalHold: true}); err != nil {
+		t.Fatalf("SetInspectionLegalHold failed: %v", err)
+	}
+	template := createTestInspection(t, q, user.ID, domain.InspectionStatusCompleted)
+	if _, err := q.UpdateInspectionTemplateByIDAndUserID(ctx, repository.UpdateInspectionTemplateByIDAndUserIDParams{
+		ID:           template.ID,
+		UserID:       user.ID,
+		IsTemplate:   true,
+		TemplateName: sql.NullString{String: "Weekly walk", Valid: true},
+	}); err != nil {
+		t.Fatalf("UpdateInspectionTemplateByIDAndUserID failed: %v", err)
+	}
+
+	cutoff := sql.NullTime{Time: time.Now().Add(time.Hour), Valid: true}
+	rows, err := q.ListInspectionsDueForPurge(ctx, repository.ListInspectionsDueForPurgeParams{Cutoff: cutoff, BatchSize: 1000})
+	if err != nil {
+		t.Fatalf("ListInspectionsDueForPurge failed: %v", err)
+	}
+	listed := make(map[uuid.UUID]bool)
+	for _, row := range rows {
+		listed[row.ID] = true
+	}
+	if !listed[due.ID] {
+		t.Errorf("expected the completed inspection to be due for purge")
+	}
+	for name, id := range map[string]uuid.UUID{"in review": inReview.ID, "held": held.ID, "template": template.ID} {
+		if listed[id] {
+			t.Errorf("expected the %s inspection not to be due for purge", name)
+		}
+		n, err := q.PurgeInspection(ctx, repository.PurgeInspectionParams{ID: id, Cutoff: cutoff})
+		if err != nil {
+			t.Fatalf("PurgeInspection failed: %v", err)
+		}
+		if n != 0 {
+			t.Errorf("expected the %s inspection not to be purged", name)
+		}
+	}
+
+	n, err := q.PurgeInspection(ctx, repository.PurgeInspectionParams{ID: due.ID, Cutoff: cutoff})
+	if err != nil {
+		t.Fatalf("PurgeInspection failed: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("expected the completed inspection to be purged, got %d rows", n)
+	}
+}
+
+func TestInspectionPurge_SkipsInspectionsChangedAfterCutoff(t *testing.T) {
+	ctx := context.Background()
+	q := newTestQueries(t)
+	user := createTestUser(t, q)
+	inspection := createTestInspection(t, q, user.ID, domain.InspectionStatusCompleted)
+
+	cutoff := sql.NullTime{Time: time.Now().Add(-time.Hour), Valid: true}
+	rows, err := q.ListInspectionsDueForPurge(ctx, repository.ListInspectionsDueForPurgeParams{Cutoff: cutoff, BatchSize: 1000})
+	if err != nil {
+		t.Fatalf("ListInspectionsDueForPurge failed: %v", err)
+	}
+	for _, row := range rows {
+		if row.ID == inspection.ID {
+			t.Errorf("expected an inspection changed after the cutoff not to be due for purge")
+		}
+	}
+
+	n, err := q.PurgeInspection(ctx, repository.PurgeInspectionParams{ID: inspection.ID, Cutoff: cutoff})
+	if err != nil {
+		t.Fatalf("PurgeInspection failed: %v", err)
+	}
+	if n != 0 {
+		t.Errorf("expected an inspection changed after the cutoff not to be purged")
+	}
+}
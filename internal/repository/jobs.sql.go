@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/sqlc-dev/pqtype"
 )
 
 const countCompletedJobsByUserAndType = `-- name: CountCompletedJobsByUserAndType :one
@@ -44,6 +45,41 @@ func (q *Queries) CountCompletedJobsByUserAndType(ctx context.Context, arg Count
 	return count, err
 }
 
+const countInFlightJobsByUserAndType = `-- name: CountInFlightJobsByUserAndType :one
+SELECT COUNT(*) as count
+FROM jobs
+WHERE job_type = $1
+AND status IN ('pending', 'running')
+AND payload->>'user_id' = $2::text
+`
+
+type CountInFlightJobsByUserAndTypeParams struct {
+	JobType string `json:"job_type"`
+	UserID  string `json:"user_id"`
+}
+
+// Count pending or running jobs of a type for a user (for the per-user enqueue cap)
+func (q *Queries) CountInFlightJobsByUserAndType(ctx context.Context, arg CountInFlightJobsByUserAndTypeParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countInFlightJobsByUserAndType, arg.JobType, arg.UserID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countPendingJobs = `-- name: CountPendingJobs :one
+SELECT COUNT(*) as count
+FROM jobs
+WHERE status = 'pending'
+`
+
+// Count jobs waiting to run across all users (for the queue-depth safeguard)
+func (q *Queries) CountPendingJobs(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countPendingJobs)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const deleteCompletedJobsOlderThan = `-- name: DeleteCompletedJobsOlderThan :exec
 DELETE FROM jobs
 WHERE status = 'completed'
@@ -56,16 +92,19 @@ func (q *Queries) DeleteCompletedJobsOlderThan(ctx context.Context, completedAt
 }
 
 const dequeueJob = `-- name: DequeueJob :one
-SELECT id, job_type, payload, status, priority, attempts, max_attempts, scheduled_at, started_at, completed_at, error_message, created_at FROM jobs
+SELECT id, job_type, payload, status, priority, attempts, max_attempts, scheduled_at, started_at, completed_at, error_message, created_at, result, finished_at, idempotency_key FROM jobs
 WHERE status = 'pending'
 AND scheduled_at <= NOW()
-ORDER BY priority DESC, scheduled_at ASC
+AND priority >= $1
+ORDER BY priority DESC, created_at ASC
 LIMIT 1
 FOR UPDATE SKIP LOCKED
 `
 
-func (q *Queries) DequeueJob(ctx context.Context) (Job, error) {
-	row := q.db.QueryRowContext(ctx, dequeueJob)
+// Claims the next ready job of at least priority $1: highest priority first,
+// then oldest first
+func (q *Queries) DequeueJob(ctx context.Context, priority int32) (Job, error) {
+	row := q.db.QueryRowContext(ctx, dequeueJob, priority)
 	var i Job
 	err := row.Scan(
 		&i.ID,
@@ -80,6 +119,9 @@ func (q *Queries) DequeueJob(ctx context.Context) (Job, error) {
 		&i.CompletedAt,
 		&i.ErrorMessage,
 		&i.CreatedAt,
+		&i.Result,
+		&i.FinishedAt,
+		&i.IdempotencyKey,
 	)
 	return i, err
 }
@@ -90,21 +132,25 @@ INSERT INTO jobs (
     payload,
     priority,
     max_attempts,
-    scheduled_at
+    scheduled_at,
+    idempotency_key
 ) VALUES (
-    $1, $2, $3, $4, $5
+    $1, $2, $3, $4, $5, $6
 )
-RETURNING id, job_type, payload, status, priority, attempts, max_attempts, scheduled_at, started_at, completed_at, error_message, created_at
+ON CONFLICT (idempotency_key) WHERE status IN ('pending', 'running') DO NOTHING
+RETURNING id, job_type, payload, status, priority, attempts, max_attempts, scheduled_at, started_at, completed_at, error_message, created_at, result, finished_at, idempotency_key
 `
 
 type EnqueueJobParams struct {
-	JobType     string          `json:"job_type"`
-	Payload     json.RawMessage `json:"payload"`
-	Priority    int32           `json:"priority"`
-	MaxAttempts int32           `json:"max_attempts"`
-	ScheduledAt time.Time       `json:"scheduled_at"`
+	JobType        string          `json:"job_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Priority       int32           `json:"priority"`
+	MaxAttempts    int32           `json:"max_attempts"`
+	ScheduledAt    time.Time       `json:"scheduled_at"`
+	IdempotencyKey sql.NullString  `json:"idempotency_key"`
 }
 
+// Returns no row if a pending or running job already holds the idempotency key
 func (q *Queries) EnqueueJob(ctx context.Context, arg EnqueueJobParams) (Job, error) {
 	row := q.db.QueryRowContext(ctx, enqueueJob,
 		arg.JobType,
@@ -112,7 +158,38 @@ func (q *Queries) EnqueueJob(ctx context.Context, arg EnqueueJobParams) (Job, er
 		arg.Priority,
 		arg.MaxAttempts,
 		arg.ScheduledAt,
+		arg.IdempotencyKey,
+	)
+	var i Job
+	err := row.Scan(
+		&i.ID,
+		&i.JobType,
+		&i.Payload,
+		&i.Status,
+		&i.Priority,
+		&i.Attempts,
+		&i.MaxAttempts,
+		&i.ScheduledAt,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.ErrorMessage,
+		&i.CreatedAt,
+		&i.Result,
+		&i.FinishedAt,
+		&i.IdempotencyKey,
 	)
+	return i, err
+}
+
+const getActiveJobByIdempotencyKey = `-- name: GetActiveJobByIdempotencyKey :one
+SELECT id, job_type, payload, status, priority, attempts, max_attempts, scheduled_at, started_at, completed_at, error_message, created_at, result, finished_at, idempotency_key FROM jobs
+WHERE idempotency_key = $1
+AND status IN ('pending', 'running')
+`
+
+// Get the pending or running job holding an idempotency key
+func (q *Queries) GetActiveJobByIdempotencyKey(ctx context.Context, idempotencyKey sql.NullString) (Job, error) {
+	row := q.db.QueryRowContext(ctx, getActiveJobByIdempotencyKey, idempotencyKey)
 	var i Job
 	err := row.Scan(
 		&i.ID,
@@ -127,12 +204,15 @@ func (q *Queries) EnqueueJob(ctx context.Context, arg EnqueueJobParams) (Job, er
 		&i.CompletedAt,
 		&i.ErrorMessage,
 		&i.CreatedAt,
+		&i.Result,
+		&i.FinishedAt,
+		&i.IdempotencyKey,
 	)
 	return i, err
 }
 
 const getJobByID = `-- name: GetJobByID :one
-SELECT id, job_type, payload, status, priority, attempts, max_attempts, scheduled_at, started_at, completed_at, error_message, created_at FROM jobs
+SELECT id, job_type, payload, status, priority, attempts, max_attempts, scheduled_at, started_at, completed_at, error_message, created_at, result, finished_at, idempotency_key FROM jobs
 WHERE id = $1
 `
 
@@ -152,6 +232,50 @@ func (q *Queries) GetJobByID(ctx context.Context, id uuid.UUID) (Job, error) {
 		&i.CompletedAt,
 		&i.ErrorMessage,
 		&i.CreatedAt,
+		&i.Result,
+		&i.FinishedAt,
+		&i.IdempotencyKey,
+	)
+	return i, err
+}
+
+const getJobByIDAndUserID = `-- name: GetJobByIDAndUserID :one
+SELECT id, job_type, payload, status, priority, attempts, max_attempts, scheduled_at, started_at, completed_at, error_message, created_at, result, finished_at, idempotency_key FROM jobs
+WHERE id = $1
+AND (payload->>'user_id' = $2::text OR payload->>'user_id' IN (
+    SELECT them.user_id::text FROM organization_members me
+    JOIN organization_members them ON them.organization_id = me.organization_id
+    WHERE me.user_id::text = $2::text
+))
+`
+
+type GetJobByIDAndUserIDParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID string    `json:"user_id"`
+}
+
+// Get a job enqueued for a user or a member of their organization (the
+// payload of user jobs names the user, which is the inspection owner for
+// inspection jobs)
+func (q *Queries) GetJobByIDAndUserID(ctx context.Context, arg GetJobByIDAndUserIDParams) (Job, error) {
+	row := q.db.QueryRowContext(ctx, getJobByIDAndUserID, arg.ID, arg.UserID)
+	var i Job
+	err := row.Scan(
+		&i.ID,
+		&i.JobType,
+		&i.Payload,
+		&i.Status,
+		&i.Priority,
+		&i.Attempts,
+		&i.MaxAttempts,
+		&i.ScheduledAt,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.ErrorMessage,
+		&i.CreatedAt,
+		&i.Result,
+		&i.FinishedAt,
+		&i.IdempotencyKey,
 	)
 	return i, err
 }
@@ -173,6 +297,23 @@ func (q *Queries) HasPendingAnalysisJob(ctx context.Context, dollar_1 string) (b
 	return has_pending, err
 }
 
+const hasPendingJobOfType = `-- name: HasPendingJobOfType :one
+SELECT EXISTS (
+    SELECT 1 FROM jobs
+    WHERE job_type = $1
+    AND status = 'pending'
+) AS has_pending
+`
+
+// Check if a job of the given type is waiting to run (used to keep a single
+// recurring job scheduled)
+func (q *Queries) HasPendingJobOfType(ctx context.Context, jobType string) (bool, error) {
+	row := q.db.QueryRowContext(ctx, hasPendingJobOfType, jobType)
+	var has_pending bool
+	err := row.Scan(&has_pending)
+	return has_pending, err
+}
+
 const recoverStaleJobs = `-- name: RecoverStaleJobs :execrows
 UPDATE jobs
 SET status = 'pending',
@@ -191,15 +332,52 @@ func (q *Queries) RecoverStaleJobs(ctx context.Context, secs float64) (int64, er
 	return result.RowsAffected()
 }
 
+const retryFailedJob = `-- name: RetryFailedJob :execrows
+UPDATE jobs
+SET status = 'pending',
+    attempts = 0,
+    error_message = NULL,
+    started_at = NULL,
+    completed_at = NULL,
+    finished_at = NULL,
+    result = NULL,
+    scheduled_at = NOW()
+WHERE id = $1
+AND status = 'failed'
+AND NOT EXISTS (
+    SELECT 1 FROM jobs active
+    WHERE active.idempotency_key = jobs.idempotency_key
+    AND active.status IN ('pending', 'running')
+)
+`
+
+// Re-enqueues a permanently failed job with a fresh set of attempts, unless
+// a copy of it (same idempotency key) has been queued since
+func (q *Queries) RetryFailedJob(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, retryFailedJob, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const updateJobCompleted = `-- name: UpdateJobCompleted :exec
 UPDATE jobs
 SET status = 'completed',
-    completed_at = NOW()
+    completed_at = NOW(),
+    finished_at = NOW(),
+    result = $2
 WHERE id = $1
 `
 
-func (q *Queries) UpdateJobCompleted(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, updateJobCompleted, id)
+type UpdateJobCompletedParams struct {
+	ID     uuid.UUID             `json:"id"`
+	Result pqtype.NullRawMessage `json:"result"`
+}
+
+// Marks a job completed with what it produced, if anything
+func (q *Queries) UpdateJobCompleted(ctx context.Context, arg UpdateJobCompletedParams) error {
+	_, err := q.db.ExecContext(ctx, updateJobCompleted, arg.ID, arg.Result)
 	return err
 }
 
@@ -210,21 +388,48 @@ SET status = CASE
     ELSE 'pending'
 END,
 error_message = $2,
+result = $3,
 scheduled_at = CASE
     WHEN attempts < max_attempts THEN NOW() + (LEAST(POWER(2, attempts - 1) * 30, 3600) * INTERVAL '1 second')
     ELSE scheduled_at
+END,
+finished_at = CASE
+    WHEN attempts >= max_attempts THEN NOW()
+    ELSE NULL
 END
 WHERE id = $1
 `
 
 type UpdateJobFailedParams struct {
-	ID           uuid.UUID      `json:"id"`
-	ErrorMessage sql.NullString `json:"error_message"`
+	ID           uuid.UUID             `json:"id"`
+	ErrorMessage sql.NullString        `json:"error_message"`
+	Result       pqtype.NullRawMessage `json:"result"`
 }
 
 // Updates a failed job with exponential backoff (30s * 2^attempts, max 1 hour)
 func (q *Queries) UpdateJobFailed(ctx context.Context, arg UpdateJobFailedParams) error {
-	_, err := q.db.ExecContext(ctx, updateJobFailed, arg.ID, arg.ErrorMessage)
+	_, err := q.db.ExecContext(ctx, updateJobFailed, arg.ID, arg.ErrorMessage, arg.Result)
+	return err
+}
+
+const updateJobPermanentlyFailed = `-- name: UpdateJobPermanentlyFailed :exec
+UPDATE jobs
+SET status = 'failed',
+    error_message = $2,
+    result = $3,
+    finished_at = NOW()
+WHERE id = $1
+`
+
+type UpdateJobPermanentlyFailedParams struct {
+	ID           uuid.UUID             `json:"id"`
+	ErrorMessage sql.NullString        `json:"error_message"`
+	Result       pqtype.NullRawMessage `json:"result"`
+}
+
+// Marks a job as failed without retrying, regardless of remaining attempts
+func (q *Queries) UpdateJobPermanentlyFailed(ctx context.Context, arg UpdateJobPermanentlyFailedParams) error {
+	_, err := q.db.ExecContext(ctx, updateJobPermanentlyFailed, arg.ID, arg.ErrorMessage, arg.Result)
 	return err
 }
 
@@ -25,6 +25,32 @@ type AiUsage struct {
 	CreatedAt    sql.NullTime  `json:"created_at"`
 }
 
+type AnalysisRun struct {
+	ID               uuid.UUID     `json:"id"`
+	InspectionID     uuid.NullUUID `json:"inspection_id"`
+	UserID           uuid.UUID     `json:"user_id"`
+	Provider         string        `json:"provider"`
+	Model            string        `json:"model"`
+	ImagesAnalyzed   int32         `json:"images_analyzed"`
+	PromptTokens     int32         `json:"prompt_tokens"`
+	CompletionTokens int32         `json:"completion_tokens"`
+	CostCents        int32         `json:"cost_cents"`
+	DurationMs       int32         `json:"duration_ms"`
+	CreatedAt        time.Time     `json:"created_at"`
+}
+
+type AuditEvent struct {
+	ID           uuid.UUID             `json:"id"`
+	ActorUserID  uuid.UUID             `json:"actor_user_id"`
+	InspectionID uuid.NullUUID         `json:"inspection_id"`
+	EntityType   string                `json:"entity_type"`
+	EntityID     uuid.UUID             `json:"entity_id"`
+	Action       string                `json:"action"`
+	OldValues    pqtype.NullRawMessage `json:"old_values"`
+	NewValues    pqtype.NullRawMessage `json:"new_values"`
+	CreatedAt    time.Time             `json:"created_at"`
+}
+
 type Client struct {
 	ID           uuid.UUID      `json:"id"`
 	UserID       uuid.UUID      `json:"user_id"`
@@ -62,6 +88,22 @@ type Image struct {
 	AnalysisStatus      sql.NullString `json:"analysis_status"`
 	AnalysisCompletedAt sql.NullTime   `json:"analysis_completed_at"`
 	CreatedAt           sql.NullTime   `json:"created_at"`
+	AnalysisError       sql.NullString `json:"analysis_error"`
+	DisplayOrder        int32          `json:"display_order"`
+	ContentHash         sql.NullString `json:"content_hash"`
+}
+
+type ImageAnnotation struct {
+	ID          uuid.UUID `json:"id"`
+	ViolationID uuid.UUID `json:"violation_id"`
+	ImageID     uuid.UUID `json:"image_id"`
+	X           float64   `json:"x"`
+	Y           float64   `json:"y"`
+	Width       float64   `json:"width"`
+	Height      float64   `json:"height"`
+	Label       string    `json:"label"`
+	Source      string    `json:"source"`
+	CreatedAt   time.Time `json:"created_at"`
 }
 
 type Inspection struct {
@@ -81,21 +123,107 @@ type Inspection struct {
 	State             string         `json:"state"`
 	PostalCode        string         `json:"postal_code"`
 	ClientID          uuid.NullUUID  `json:"client_id"`
+	// Geocoded latitude of the address; NULL if not geocoded
+	Latitude sql.NullFloat64 `json:"latitude"`
+	// Geocoded longitude of the address; NULL if not geocoded
+	Longitude sql.NullFloat64 `json:"longitude"`
+	// Incremented on every edit; used to detect concurrent edits
+	Version int32 `json:"version"`
+	// Organization member the inspection is assigned to; NULL if unassigned
+	AssignedTo uuid.NullUUID `json:"assigned_to"`
+	// Whether new inspections can be created from this one
+	IsTemplate bool `json:"is_template"`
+	// Name the template is listed under; NULL unless is_template
+	TemplateName sql.NullString `json:"template_name"`
+	// Exempts the inspection from the data retention purge
+	LegalHold bool `json:"legal_hold"`
+}
+
+type InspectionComment struct {
+	ID           uuid.UUID `json:"id"`
+	InspectionID uuid.UUID `json:"inspection_id"`
+	UserID       uuid.UUID `json:"user_id"`
+	Body         string    `json:"body"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type InspectionDraft struct {
+	UserID            uuid.UUID      `json:"user_id"`
+	Title             sql.NullString `json:"title"`
+	ClientID          sql.NullString `json:"client_id"`
+	AddressLine1      sql.NullString `json:"address_line1"`
+	AddressLine2      sql.NullString `json:"address_line2"`
+	City              sql.NullString `json:"city"`
+	State             sql.NullString `json:"state"`
+	PostalCode        sql.NullString `json:"postal_code"`
+	InspectionDate    sql.NullString `json:"inspection_date"`
+	WeatherConditions sql.NullString `json:"weather_conditions"`
+	Temperature       sql.NullString `json:"temperature"`
+	InspectorNotes    sql.NullString `json:"inspector_notes"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+}
+
+type InspectionEvent struct {
+	ID           int64                 `json:"id"`
+	InspectionID uuid.UUID             `json:"inspection_id"`
+	Kind         string                `json:"kind"`
+	Data         pqtype.NullRawMessage `json:"data"`
+	CreatedAt    time.Time             `json:"created_at"`
+}
+
+type InspectionShareLink struct {
+	ID           uuid.UUID     `json:"id"`
+	InspectionID uuid.UUID     `json:"inspection_id"`
+	UserID       uuid.UUID     `json:"user_id"`
+	TokenHash    string        `json:"token_hash"`
+	ExpiresAt    time.Time     `json:"expires_at"`
+	MaxViews     sql.NullInt32 `json:"max_views"`
+	ViewCount    int32         `json:"view_count"`
+	RevokedAt    sql.NullTime  `json:"revoked_at"`
+	CreatedAt    time.Time     `json:"created_at"`
 }
 
 type Job struct {
-	ID           uuid.UUID       `json:"id"`
-	JobType      string          `json:"job_type"`
-	Payload      json.RawMessage `json:"payload"`
-	Status       string          `json:"status"`
-	Priority     int32           `json:"priority"`
-	Attempts     int32           `json:"attempts"`
-	MaxAttempts  int32           `json:"max_attempts"`
-	ScheduledAt  time.Time       `json:"scheduled_at"`
-	StartedAt    sql.NullTime    `json:"started_at"`
-	CompletedAt  sql.NullTime    `json:"completed_at"`
-	ErrorMessage sql.NullString  `json:"error_message"`
-	CreatedAt    sql.NullTime    `json:"created_at"`
+	ID             uuid.UUID             `json:"id"`
+	JobType        string                `json:"job_type"`
+	Payload        json.RawMessage       `json:"payload"`
+	Status         string                `json:"status"`
+	Priority       int32                 `json:"priority"`
+	Attempts       int32                 `json:"attempts"`
+	MaxAttempts    int32                 `json:"max_attempts"`
+	ScheduledAt    time.Time             `json:"scheduled_at"`
+	StartedAt      sql.NullTime          `json:"started_at"`
+	CompletedAt    sql.NullTime          `json:"completed_at"`
+	ErrorMessage   sql.NullString        `json:"error_message"`
+	CreatedAt      sql.NullTime          `json:"created_at"`
+	Result         pqtype.NullRawMessage `json:"result"`
+	FinishedAt     sql.NullTime          `json:"finished_at"`
+	IdempotencyKey sql.NullString        `json:"idempotency_key"`
+}
+
+type Organization struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type OrganizationInvite struct {
+	ID             uuid.UUID    `json:"id"`
+	OrganizationID uuid.UUID    `json:"organization_id"`
+	Email          string       `json:"email"`
+	TokenHash      string       `json:"token_hash"`
+	InvitedBy      uuid.UUID    `json:"invited_by"`
+	ExpiresAt      time.Time    `json:"expires_at"`
+	AcceptedAt     sql.NullTime `json:"accepted_at"`
+	CreatedAt      time.Time    `json:"created_at"`
+}
+
+type OrganizationMember struct {
+	OrganizationID uuid.UUID `json:"organization_id"`
+	UserID         uuid.UUID `json:"user_id"`
+	Role           string    `json:"role"`
+	CreatedAt      time.Time `json:"created_at"`
 }
 
 type PasswordResetToken struct {
@@ -122,6 +250,18 @@ type Regulation struct {
 	SearchVector    interface{}    `json:"search_vector"`
 	CreatedAt       sql.NullTime   `json:"created_at"`
 	UpdatedAt       sql.NullTime   `json:"updated_at"`
+	DeactivatedAt   sql.NullTime   `json:"deactivated_at"`
+}
+
+type RegulationImport struct {
+	ID               uuid.UUID `json:"id"`
+	Source           string    `json:"source"`
+	AddedCount       int32     `json:"added_count"`
+	UpdatedCount     int32     `json:"updated_count"`
+	UnchangedCount   int32     `json:"unchanged_count"`
+	DeactivatedCount int32     `json:"deactivated_count"`
+	Errors           []string  `json:"errors"`
+	CreatedAt        time.Time `json:"created_at"`
 }
 
 type Report struct {
@@ -132,14 +272,39 @@ type Report struct {
 	DocxStorageKey sql.NullString `json:"docx_storage_key"`
 	ViolationCount int32          `json:"violation_count"`
 	GeneratedAt    sql.NullTime   `json:"generated_at"`
+	// When the generation job failed permanently; NULL for generated reports
+	FailedAt sql.NullTime `json:"failed_at"`
+	// Format requested by the failed generation job (pdf or docx)
+	FailedFormat sql.NullString `json:"failed_format"`
+	// Addresses the report was emailed to, besides the inspector
+	RecipientEmails []string `json:"recipient_emails"`
+}
+
+type ReportSetting struct {
+	UserID     uuid.UUID `json:"user_id"`
+	Intro      string    `json:"intro"`
+	Disclaimer string    `json:"disclaimer"`
+	Signature  string    `json:"signature"`
+	UpdatedAt  time.Time `json:"updated_at"`
 }
 
 type Session struct {
-	ID        uuid.UUID    `json:"id"`
-	UserID    uuid.UUID    `json:"user_id"`
-	TokenHash string       `json:"token_hash"`
-	ExpiresAt time.Time    `json:"expires_at"`
-	CreatedAt sql.NullTime `json:"created_at"`
+	ID         uuid.UUID    `json:"id"`
+	UserID     uuid.UUID    `json:"user_id"`
+	TokenHash  string       `json:"token_hash"`
+	ExpiresAt  time.Time    `json:"expires_at"`
+	CreatedAt  sql.NullTime `json:"created_at"`
+	LastSeenAt time.Time    `json:"last_seen_at"`
+	// User-Agent of the most recent request on the session
+	UserAgent sql.NullString `json:"user_agent"`
+	// Client IP of the most recent request on the session
+	IpAddress sql.NullString `json:"ip_address"`
+}
+
+type StripeWebhookEvent struct {
+	EventID     string    `json:"event_id"`
+	EventType   string    `json:"event_type"`
+	ProcessedAt time.Time `json:"processed_at"`
 }
 
 type User struct {
@@ -177,6 +342,41 @@ type User struct {
 	BusinessLicenseNumber sql.NullString `json:"business_license_number"`
 	// URL to uploaded business logo image
 	BusinessLogoUrl sql.NullString `json:"business_logo_url"`
+	// When the account was disabled; NULL if active
+	DisabledAt sql.NullTime `json:"disabled_at"`
+	// When the last email verification reminder was sent
+	VerificationReminderSentAt sql.NullTime `json:"verification_reminder_sent_at"`
+	// Number of email verification reminders sent
+	VerificationReminderCount int32 `json:"verification_reminder_count"`
+	// Storage key of the uploaded business logo
+	BusinessLogoKey sql.NullString `json:"business_logo_key"`
+	// Hex color (#RRGGBB) for report headers
+	BusinessBrandColor sql.NullString `json:"business_brand_color"`
+}
+
+type UserPreference struct {
+	UserID      uuid.UUID       `json:"user_id"`
+	Preferences json.RawMessage `json:"preferences"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+type UserQuotaOverride struct {
+	UserID           uuid.UUID     `json:"user_id"`
+	AnalysisPerMonth sql.NullInt32 `json:"analysis_per_month"`
+	ReportsPerMonth  sql.NullInt32 `json:"reports_per_month"`
+	UpdatedAt        time.Time     `json:"updated_at"`
+}
+
+type UserRegulationBookmark struct {
+	UserID       uuid.UUID `json:"user_id"`
+	RegulationID uuid.UUID `json:"regulation_id"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type UserRegulationView struct {
+	UserID       uuid.UUID `json:"user_id"`
+	RegulationID uuid.UUID `json:"regulation_id"`
+	ViewedAt     time.Time `json:"viewed_at"`
 }
 
 type Violation struct {
@@ -193,6 +393,14 @@ type Violation struct {
 	SortOrder      sql.NullInt32         `json:"sort_order"`
 	CreatedAt      sql.NullTime          `json:"created_at"`
 	UpdatedAt      sql.NullTime          `json:"updated_at"`
+	AiSeverity     sql.NullString        `json:"ai_severity"`
+}
+
+type ViolationImage struct {
+	ViolationID uuid.UUID `json:"violation_id"`
+	ImageID     uuid.UUID `json:"image_id"`
+	Position    int32     `json:"position"`
+	CreatedAt   time.Time `json:"created_at"`
 }
 
 type ViolationRegulation struct {
@@ -204,3 +412,24 @@ type ViolationRegulation struct {
 	IsPrimary      sql.NullBool    `json:"is_primary"`
 	CreatedAt      sql.NullTime    `json:"created_at"`
 }
+
+type WebhookDelivery struct {
+	ID             uuid.UUID       `json:"id"`
+	EndpointID     uuid.UUID       `json:"endpoint_id"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int32           `json:"attempts"`
+	ResponseStatus sql.NullInt32   `json:"response_status"`
+	ErrorMessage   sql.NullString  `json:"error_message"`
+	DeliveredAt    sql.NullTime    `json:"delivered_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+type WebhookEndpoint struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Url       string    `json:"url"`
+	Secret    string    `json:"secret"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,83 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+)
+
+// =============================================================================
+// Organization Access Tests
+// =============================================================================
+
+func TestOwnerIDForUser_OwnerAndTeammateOnly(t *testing.T) {
+	ctx := context.Background()
+	q := newTestQueries(t)
+	owner := createTestUser(t, q)
+	teammate := createTestUser(t, q)
+	outsider := createTestUser(t, q)
+
+	org, err := q.CreateOrganization(ctx, "Acme Inspections")
+	if err != nil {
+		t.Fatalf("CreateOrganization failed: %v", err)
+	}
+	for user, role := range map[*repository.User]domain.OrganizationRole{&owner: domain.OrganizationRoleOwner, &teammate: domain.OrganizationRoleMember} {
+		if err := q.CreateOrganizationMember(ctx, repository.CreateOrganizationMemberParams{
+			OrganizationID: org.ID,
+			UserID:         user.ID,
+			Role:           string(role),
+		}); err != nil {
+			t.Fatalf("CreateOrganizationMember failed: %v", err)
+		}
+	}
+
+	inspection := createTestInspection(t, q, owner.ID, domain.InspectionStatusDraft)
+	violation, err := q.CreateViolation(ctx, repository.CreateViolationParams{
+		InspectionID: inspection.ID,
+		Description:  "Missing guardrail",
+		Status:       string(domain.ViolationStatusPending),
+	})
+	if err != nil {
+		t.Fatalf("CreateViolation failed: %v", err)
+	}
+
+	for name, user := range map[string]repository.User{"owner": owner, "teammate": teammate} {
+		got, err := q.GetInspectionOwnerIDForUser(ctx, repository.GetInspectionOwnerIDForUserParams{ID: inspection.ID, UserID: user.ID})
+		if err != nil || got != owner.ID {
+			t.Errorf("expected the %s to resolve the inspection to its owner, got %s, %v", name, got, err)
+		}
+		got, err = q.GetViolationOwnerIDForUser(ctx, repository.GetViolationOwnerIDForUserParams{ID: violation.ID, UserID: user.ID})
+		if err != nil || got != owner.ID {
+			t.Errorf("expected the %s to resolve the violation to its owner, got %s, %v", name, got, err)
+		}
+	}
+
+	_, err = q.GetInspectionOwnerIDForUser(ctx, repository.GetInspectionOwnerIDForUserParams{ID: inspection.ID, UserID: outsider.ID})
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected no rows resolving the inspection for an outsider, got %v", err)
+	}
+	_, err = q.GetViolationOwnerIDForUser(ctx, repository.GetViolationOwnerIDForUserParams{ID: violation.ID, UserID: outsider.ID})
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected no rows resolving the violation for an outsider, got %v", err)
+	}
+
+	// A removed teammate loses access
+	n, err := q.DeleteOrganizationMember(ctx, repository.DeleteOrganizationMemberParams{OrganizationID: org.ID, UserID: teammate.ID})
+	if err != nil || n != 1 {
+		t.Fatalf("DeleteOrganizationMember failed: %d, %v", n, err)
+	}
+	_, err = q.GetInspectionOwnerIDForUser(ctx, repository.GetInspectionOwnerIDForUserParams{ID: inspection.ID, UserID: teammate.ID})
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected no rows resolving the inspection for a removed teammate, got %v", err)
+	}
+
+	// Owners cannot be removed
+	n, err = q.DeleteOrganizationMember(ctx, repository.DeleteOrganizationMemberParams{OrganizationID: org.ID, UserID: owner.ID})
+	if err != nil || n != 0 {
+		t.Errorf("expected the owner not to be removed, got %d, %v", n, err)
+	}
+}
@@ -16,10 +16,19 @@ import (
 const countRegulations = `-- name: CountRegulations :one
 SELECT COUNT(*) FROM regulations
 WHERE ($1::text IS NULL OR category = $1)
+AND ($2::text IS NULL OR subcategory = $2)
+AND ($3::text IS NULL OR severity_typical = $3)
+AND deactivated_at IS NULL
 `
 
-func (q *Queries) CountRegulations(ctx context.Context, category sql.NullString) (int64, error) {
-	row := q.db.QueryRowContext(ctx, countRegulations, category)
+type CountRegulationsParams struct {
+	Category    sql.NullString `json:"category"`
+	Subcategory sql.NullString `json:"subcategory"`
+	Severity    sql.NullString `json:"severity"`
+}
+
+func (q *Queries) CountRegulations(ctx context.Context, arg CountRegulationsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countRegulations, arg.Category, arg.Subcategory, arg.Severity)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
@@ -28,10 +37,26 @@ func (q *Queries) CountRegulations(ctx context.Context, category sql.NullString)
 const countSearchResults = `-- name: CountSearchResults :one
 SELECT COUNT(*) FROM regulations
 WHERE search_vector @@ websearch_to_tsquery('english', $1)
+AND ($2::text IS NULL OR category = $2)
+AND ($3::text IS NULL OR subcategory = $3)
+AND ($4::text IS NULL OR severity_typical = $4)
+AND deactivated_at IS NULL
 `
 
-func (q *Queries) CountSearchResults(ctx context.Context, websearchToTsquery string) (int64, error) {
-	row := q.db.QueryRowContext(ctx, countSearchResults, websearchToTsquery)
+type CountSearchResultsParams struct {
+	WebsearchToTsquery string         `json:"websearch_to_tsquery"`
+	Category           sql.NullString `json:"category"`
+	Subcategory        sql.NullString `json:"subcategory"`
+	Severity           sql.NullString `json:"severity"`
+}
+
+func (q *Queries) CountSearchResults(ctx context.Context, arg CountSearchResultsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countSearchResults,
+		arg.WebsearchToTsquery,
+		arg.Category,
+		arg.Subcategory,
+		arg.Severity,
+	)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
@@ -52,7 +77,7 @@ INSERT INTO regulations (
 ) VALUES (
     $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
 )
-RETURNING id, standard_number, title, category, subcategory, full_text, summary, severity_typical, parent_standard, effective_date, last_updated, search_vector, created_at, updated_at
+RETURNING id, standard_number, title, category, subcategory, full_text, summary, severity_typical, parent_standard, effective_date, last_updated, search_vector, created_at, updated_at, deactivated_at
 `
 
 type CreateRegulationParams struct {
@@ -97,12 +122,31 @@ func (q *Queries) CreateRegulation(ctx context.Context, arg CreateRegulationPara
 		&i.SearchVector,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeactivatedAt,
 	)
 	return i, err
 }
 
+const deactivateRegulationsNotIn = `-- name: DeactivateRegulationsNotIn :execrows
+UPDATE regulations
+SET deactivated_at = NOW(),
+    updated_at = NOW()
+WHERE deactivated_at IS NULL
+AND NOT (standard_number = ANY($1::text[]))
+`
+
+// Deactivate active regulations missing from an import. They are kept so
+// existing violation links stay valid.
+func (q *Queries) DeactivateRegulationsNotIn(ctx context.Context, standardNumbers []string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deactivateRegulationsNotIn, pq.Array(standardNumbers))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const getRegulationByID = `-- name: GetRegulationByID :one
-SELECT id, standard_number, title, category, subcategory, full_text, summary, severity_typical, parent_standard, effective_date, last_updated, search_vector, created_at, updated_at FROM regulations
+SELECT id, standard_number, title, category, subcategory, full_text, summary, severity_typical, parent_standard, effective_date, last_updated, search_vector, created_at, updated_at, deactivated_at FROM regulations
 WHERE id = $1
 `
 
@@ -124,12 +168,13 @@ func (q *Queries) GetRegulationByID(ctx context.Context, id uuid.UUID) (Regulati
 		&i.SearchVector,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeactivatedAt,
 	)
 	return i, err
 }
 
 const getRegulationByStandardNumber = `-- name: GetRegulationByStandardNumber :one
-SELECT id, standard_number, title, category, subcategory, full_text, summary, severity_typical, parent_standard, effective_date, last_updated, search_vector, created_at, updated_at FROM regulations
+SELECT id, standard_number, title, category, subcategory, full_text, summary, severity_typical, parent_standard, effective_date, last_updated, search_vector, created_at, updated_at, deactivated_at FROM regulations
 WHERE standard_number = $1
 `
 
@@ -151,6 +196,7 @@ func (q *Queries) GetRegulationByStandardNumber(ctx context.Context, standardNum
 		&i.SearchVector,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeactivatedAt,
 	)
 	return i, err
 }
@@ -196,9 +242,10 @@ func (q *Queries) GetRegulationDetail(ctx context.Context, id uuid.UUID) (GetReg
 }
 
 const getRegulationsByStandardNumbers = `-- name: GetRegulationsByStandardNumbers :many
-SELECT r.id, r.standard_number, r.title, r.category, r.subcategory, r.full_text, r.summary, r.severity_typical, r.parent_standard, r.effective_date, r.last_updated, r.search_vector, r.created_at, r.updated_at, array_position($1::text[], r.standard_number) as sort_order
+SELECT r.id, r.standard_number, r.title, r.category, r.subcategory, r.full_text, r.summary, r.severity_typical, r.parent_standard, r.effective_date, r.last_updated, r.search_vector, r.created_at, r.updated_at, r.deactivated_at, array_position($1::text[], r.standard_number) as sort_order
 FROM regulations r
 WHERE r.standard_number = ANY($1::text[])
+AND r.deactivated_at IS NULL
 ORDER BY array_position($1::text[], r.standard_number)
 `
 
@@ -217,6 +264,7 @@ type GetRegulationsByStandardNumbersRow struct {
 	SearchVector    interface{}    `json:"search_vector"`
 	CreatedAt       sql.NullTime   `json:"created_at"`
 	UpdatedAt       sql.NullTime   `json:"updated_at"`
+	DeactivatedAt   sql.NullTime   `json:"deactivated_at"`
 	SortOrder       int32          `json:"sort_order"`
 }
 
@@ -246,6 +294,7 @@ func (q *Queries) GetRegulationsByStandardNumbers(ctx context.Context, dollar_1
 			&i.SearchVector,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeactivatedAt,
 			&i.SortOrder,
 		); err != nil {
 			return nil, err
@@ -264,6 +313,7 @@ func (q *Queries) GetRegulationsByStandardNumbers(ctx context.Context, dollar_1
 const listAllCategories = `-- name: ListAllCategories :many
 SELECT DISTINCT category
 FROM regulations
+WHERE deactivated_at IS NULL
 ORDER BY category ASC
 `
 
@@ -290,18 +340,65 @@ func (q *Queries) ListAllCategories(ctx context.Context) ([]string, error) {
 	return items, nil
 }
 
+const listCategoryFacets = `-- name: ListCategoryFacets :many
+SELECT category, COUNT(*) AS regulation_count
+FROM regulations
+WHERE ($1::text IS NULL OR search_vector @@ websearch_to_tsquery('english', $1))
+AND deactivated_at IS NULL
+GROUP BY category
+ORDER BY category ASC
+`
+
+type ListCategoryFacetsRow struct {
+	Category        string `json:"category"`
+	RegulationCount int64  `json:"regulation_count"`
+}
+
+// Count active regulations per category. With a query, only regulations
+// matching it are counted, so the counts describe the search results.
+func (q *Queries) ListCategoryFacets(ctx context.Context, query sql.NullString) ([]ListCategoryFacetsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listCategoryFacets, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListCategoryFacetsRow{}
+	for rows.Next() {
+		var i ListCategoryFacetsRow
+		if err := rows.Scan(
+			&i.Category,
+			&i.RegulationCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listRegulations = `-- name: ListRegulations :many
 SELECT id, standard_number, title, category, subcategory, summary, severity_typical
 FROM regulations
 WHERE ($3::text IS NULL OR category = $3)
+AND ($4::text IS NULL OR subcategory = $4)
+AND ($5::text IS NULL OR severity_typical = $5)
+AND deactivated_at IS NULL
 ORDER BY category ASC, standard_number ASC
 LIMIT $1 OFFSET $2
 `
 
 type ListRegulationsParams struct {
-	Limit    int32          `json:"limit"`
-	Offset   int32          `json:"offset"`
-	Category sql.NullString `json:"category"`
+	Limit       int32          `json:"limit"`
+	Offset      int32          `json:"offset"`
+	Category    sql.NullString `json:"category"`
+	Subcategory sql.NullString `json:"subcategory"`
+	Severity    sql.NullString `json:"severity"`
 }
 
 type ListRegulationsRow struct {
@@ -315,7 +412,13 @@ type ListRegulationsRow struct {
 }
 
 func (q *Queries) ListRegulations(ctx context.Context, arg ListRegulationsParams) ([]ListRegulationsRow, error) {
-	rows, err := q.db.QueryContext(ctx, listRegulations, arg.Limit, arg.Offset, arg.Category)
+	rows, err := q.db.QueryContext(ctx, listRegulations,
+		arg.Limit,
+		arg.Offset,
+		arg.Category,
+		arg.Subcategory,
+		arg.Severity,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -346,9 +449,10 @@ func (q *Queries) ListRegulations(ctx context.Context, arg ListRegulationsParams
 }
 
 const listRegulationsByCategory = `-- name: ListRegulationsByCategory :many
-SELECT id, standard_number, title, category, subcategory, full_text, summary, severity_typical, parent_standard, effective_date, last_updated, search_vector, created_at, updated_at FROM regulations
+SELECT id, standard_number, title, category, subcategory, full_text, summary, severity_typical, parent_standard, effective_date, last_updated, search_vector, created_at, updated_at, deactivated_at FROM regulations
 WHERE category = $1
 AND ($2::text IS NULL OR subcategory = $2)
+AND deactivated_at IS NULL
 ORDER BY standard_number ASC
 `
 
@@ -381,6 +485,56 @@ func (q *Queries) ListRegulationsByCategory(ctx context.Context, arg ListRegulat
 			&i.SearchVector,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeactivatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSubcategoryFacets = `-- name: ListSubcategoryFacets :many
+SELECT subcategory, COUNT(*) AS regulation_count
+FROM regulations
+WHERE category = $1
+AND subcategory IS NOT NULL
+AND ($2::text IS NULL OR search_vector @@ websearch_to_tsquery('english', $2))
+AND deactivated_at IS NULL
+GROUP BY subcategory
+ORDER BY subcategory ASC
+`
+
+type ListSubcategoryFacetsParams struct {
+	Category string         `json:"category"`
+	Query    sql.NullString `json:"query"`
+}
+
+type ListSubcategoryFacetsRow struct {
+	Subcategory     sql.NullString `json:"subcategory"`
+	RegulationCount int64          `json:"regulation_count"`
+}
+
+// Count active regulations per subcategory of a category, optionally limited
+// to those matching a search query.
+func (q *Queries) ListSubcategoryFacets(ctx context.Context, arg ListSubcategoryFacetsParams) ([]ListSubcategoryFacetsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listSubcategoryFacets, arg.Category, arg.Query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListSubcategoryFacetsRow{}
+	for rows.Next() {
+		var i ListSubcategoryFacetsRow
+		if err := rows.Scan(
+			&i.Subcategory,
+			&i.RegulationCount,
 		); err != nil {
 			return nil, err
 		}
@@ -395,11 +549,25 @@ func (q *Queries) ListRegulationsByCategory(ctx context.Context, arg ListRegulat
 	return items, nil
 }
 
+const refreshRegulationSearchVectors = `-- name: RefreshRegulationSearchVectors :exec
+UPDATE regulations
+SET search_vector = NULL
+WHERE deactivated_at IS NULL
+`
+
+// Rebuild search_vector for active regulations. Any write to the row fires
+// the update_regulation_search_vector trigger, which recomputes the column.
+func (q *Queries) RefreshRegulationSearchVectors(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, refreshRegulationSearchVectors)
+	return err
+}
+
 const searchRegulations = `-- name: SearchRegulations :many
-SELECT id, standard_number, title, category, subcategory, full_text, summary, severity_typical, parent_standard, effective_date, last_updated, search_vector, created_at, updated_at,
+SELECT id, standard_number, title, category, subcategory, full_text, summary, severity_typical, parent_standard, effective_date, last_updated, search_vector, created_at, updated_at, deactivated_at,
     ts_rank(search_vector, websearch_to_tsquery('english', $1)) as rank
 FROM regulations
 WHERE search_vector @@ websearch_to_tsquery('english', $1)
+AND deactivated_at IS NULL
 ORDER BY rank DESC, standard_number ASC
 LIMIT $2
 `
@@ -424,6 +592,7 @@ type SearchRegulationsRow struct {
 	SearchVector    interface{}    `json:"search_vector"`
 	CreatedAt       sql.NullTime   `json:"created_at"`
 	UpdatedAt       sql.NullTime   `json:"updated_at"`
+	DeactivatedAt   sql.NullTime   `json:"deactivated_at"`
 	Rank            float32        `json:"rank"`
 }
 
@@ -451,6 +620,7 @@ func (q *Queries) SearchRegulations(ctx context.Context, arg SearchRegulationsPa
 			&i.SearchVector,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeactivatedAt,
 			&i.Rank,
 		); err != nil {
 			return nil, err
@@ -471,14 +641,21 @@ SELECT id, standard_number, title, category, subcategory, summary, severity_typi
     ts_rank(search_vector, websearch_to_tsquery('english', $1)) as rank
 FROM regulations
 WHERE search_vector @@ websearch_to_tsquery('english', $1)
+AND ($4::text IS NULL OR category = $4)
+AND ($5::text IS NULL OR subcategory = $5)
+AND ($6::text IS NULL OR severity_typical = $6)
+AND deactivated_at IS NULL
 ORDER BY rank DESC, standard_number ASC
 LIMIT $2 OFFSET $3
 `
 
 type SearchRegulationsWithOffsetParams struct {
-	WebsearchToTsquery string `json:"websearch_to_tsquery"`
-	Limit              int32  `json:"limit"`
-	Offset             int32  `json:"offset"`
+	WebsearchToTsquery string         `json:"websearch_to_tsquery"`
+	Limit              int32          `json:"limit"`
+	Offset             int32          `json:"offset"`
+	Category           sql.NullString `json:"category"`
+	Subcategory        sql.NullString `json:"subcategory"`
+	Severity           sql.NullString `json:"severity"`
 }
 
 type SearchRegulationsWithOffsetRow struct {
@@ -493,7 +670,14 @@ type SearchRegulationsWithOffsetRow struct {
 }
 
 func (q *Queries) SearchRegulationsWithOffset(ctx context.Context, arg SearchRegulationsWithOffsetParams) ([]SearchRegulationsWithOffsetRow, error) {
-	rows, err := q.db.QueryContext(ctx, searchRegulationsWithOffset, arg.WebsearchToTsquery, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, searchRegulationsWithOffset,
+		arg.WebsearchToTsquery,
+		arg.Limit,
+		arg.Offset,
+		arg.Category,
+		arg.Subcategory,
+		arg.Severity,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -523,3 +707,74 @@ func (q *Queries) SearchRegulationsWithOffset(ctx context.Context, arg SearchReg
 	}
 	return items, nil
 }
+
+const upsertRegulation = `-- name: UpsertRegulation :one
+INSERT INTO regulations (
+    standard_number,
+    title,
+    category,
+    subcategory,
+    full_text,
+    summary,
+    severity_typical,
+    parent_standard,
+    effective_date,
+    last_updated
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+)
+ON CONFLICT (standard_number) DO UPDATE SET
+    title = EXCLUDED.title,
+    category = EXCLUDED.category,
+    subcategory = EXCLUDED.subcategory,
+    full_text = EXCLUDED.full_text,
+    summary = EXCLUDED.summary,
+    severity_typical = EXCLUDED.severity_typical,
+    parent_standard = EXCLUDED.parent_standard,
+    effective_date = EXCLUDED.effective_date,
+    last_updated = EXCLUDED.last_updated,
+    deactivated_at = NULL,
+    updated_at = NOW()
+WHERE regulations.deactivated_at IS NOT NULL
+OR (regulations.title, regulations.category, regulations.subcategory, regulations.full_text,
+    regulations.summary, regulations.severity_typical, regulations.parent_standard,
+    regulations.effective_date, regulations.last_updated)
+IS DISTINCT FROM (EXCLUDED.title, EXCLUDED.category, EXCLUDED.subcategory, EXCLUDED.full_text,
+    EXCLUDED.summary, EXCLUDED.severity_typical, EXCLUDED.parent_standard,
+    EXCLUDED.effective_date, EXCLUDED.last_updated)
+RETURNING (xmax = 0) AS inserted
+`
+
+type UpsertRegulationParams struct {
+	StandardNumber  string         `json:"standard_number"`
+	Title           string         `json:"title"`
+	Category        string         `json:"category"`
+	Subcategory     sql.NullString `json:"subcategory"`
+	FullText        string         `json:"full_text"`
+	Summary         sql.NullString `json:"summary"`
+	SeverityTypical sql.NullString `json:"severity_typical"`
+	ParentStandard  sql.NullString `json:"parent_standard"`
+	EffectiveDate   sql.NullTime   `json:"effective_date"`
+	LastUpdated     sql.NullTime   `json:"last_updated"`
+}
+
+// Insert a regulation, or update the one with the same standard number and
+// reactivate it if it was deactivated. Returns no row when the stored
+// regulation already matches, so re-running an import changes nothing.
+func (q *Queries) UpsertRegulation(ctx context.Context, arg UpsertRegulationParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, upsertRegulation,
+		arg.StandardNumber,
+		arg.Title,
+		arg.Category,
+		arg.Subcategory,
+		arg.FullText,
+		arg.Summary,
+		arg.SeverityTypical,
+		arg.ParentStandard,
+		arg.EffectiveDate,
+		arg.LastUpdated,
+	)
+	var inserted bool
+	err := row.Scan(&inserted)
+	return inserted, err
+}
@@ -0,0 +1,58 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// Regulation Upsert Tests
+// =============================================================================
+
+func TestUpsertRegulation_InsertsThenUpdatesOnlyOnChange(t *testing.T) {
+	ctx := context.Background()
+	q := newTestQueries(t)
+
+	params := repository.UpsertRegulationParams{
+		StandardNumber: "1926.test-" + uuid.NewString()[:8],
+		Title:          "Guardrail systems",
+		Category:       "Fall Protection",
+		FullText:       "Guardrail systems shall be installed along open sides.",
+		Summary:        sql.NullString{String: "Guardrails on open sides", Valid: true},
+	}
+
+	inserted, err := q.UpsertRegulation(ctx, params)
+	if err != nil {
+		t.Fatalf("first UpsertRegulation failed: %v", err)
+	}
+	if !inserted {
+		t.Errorf("expected a new standard number to be inserted")
+	}
+
+	// Re-importing the same regulation matches no row
+	if _, err := q.UpsertRegulation(ctx, params); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows re-importing an unchanged regulation, got %v", err)
+	}
+
+	params.Title = "Guardrail systems (revised)"
+	inserted, err = q.UpsertRegulation(ctx, params)
+	if err != nil {
+		t.Fatalf("UpsertRegulation with a changed title failed: %v", err)
+	}
+	if inserted {
+		t.Errorf("expected a changed regulation to be updated, not inserted")
+	}
+
+	regulation, err := q.GetRegulationByStandardNumber(ctx, params.StandardNumber)
+	if err != nil {
+		t.Fatalf("GetRegulationByStandardNumber failed: %v", err)
+	}
+	if regulation.Title != params.Title {
+		t.Errorf("expected title %q, got %q", params.Title, regulation.Title)
+	}
+}
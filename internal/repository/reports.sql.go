@@ -10,11 +10,25 @@ import (
 	"database/sql"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+const countReportsByInspectionID = `-- name: CountReportsByInspectionID :one
+SELECT COUNT(*) FROM reports
+WHERE inspection_id = $1 AND failed_at IS NULL
+`
+
+// Count the reports generated for an inspection, leaving out failures.
+func (q *Queries) CountReportsByInspectionID(ctx context.Context, inspectionID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countReportsByInspectionID, inspectionID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const countReportsByUserID = `-- name: CountReportsByUserID :one
 SELECT COUNT(*) FROM reports
-WHERE user_id = $1
+WHERE user_id = $1 AND failed_at IS NULL
 `
 
 func (q *Queries) CountReportsByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
@@ -27,6 +41,7 @@ func (q *Queries) CountReportsByUserID(ctx context.Context, userID uuid.UUID) (i
 const countReportsThisMonthByUserID = `-- name: CountReportsThisMonthByUserID :one
 SELECT COUNT(*) FROM reports
 WHERE user_id = $1
+  AND failed_at IS NULL
   AND generated_at >= DATE_TRUNC('month', CURRENT_TIMESTAMP)
 `
 
@@ -37,25 +52,64 @@ func (q *Queries) CountReportsThisMonthByUserID(ctx context.Context, userID uuid
 	return count, err
 }
 
+const createFailedReport = `-- name: CreateFailedReport :one
+INSERT INTO reports (
+    inspection_id,
+    user_id,
+    failed_format,
+    failed_at
+) VALUES (
+    $1, $2, $3, NOW()
+)
+RETURNING id, inspection_id, user_id, pdf_storage_key, docx_storage_key, violation_count, generated_at, failed_at, failed_format, recipient_emails
+`
+
+type CreateFailedReportParams struct {
+	InspectionID uuid.UUID      `json:"inspection_id"`
+	UserID       uuid.UUID      `json:"user_id"`
+	FailedFormat sql.NullString `json:"failed_format"`
+}
+
+// Record a report generation job that failed permanently.
+func (q *Queries) CreateFailedReport(ctx context.Context, arg CreateFailedReportParams) (Report, error) {
+	row := q.db.QueryRowContext(ctx, createFailedReport, arg.InspectionID, arg.UserID, arg.FailedFormat)
+	var i Report
+	err := row.Scan(
+		&i.ID,
+		&i.InspectionID,
+		&i.UserID,
+		&i.PdfStorageKey,
+		&i.DocxStorageKey,
+		&i.ViolationCount,
+		&i.GeneratedAt,
+		&i.FailedAt,
+		&i.FailedFormat,
+		pq.Array(&i.RecipientEmails),
+	)
+	return i, err
+}
+
 const createReport = `-- name: CreateReport :one
 INSERT INTO reports (
     inspection_id,
     user_id,
     pdf_storage_key,
     docx_storage_key,
-    violation_count
+    violation_count,
+    recipient_emails
 ) VALUES (
-    $1, $2, $3, $4, $5
+    $1, $2, $3, $4, $5, $6
 )
-RETURNING id, inspection_id, user_id, pdf_storage_key, docx_storage_key, violation_count, generated_at
+RETURNING id, inspection_id, user_id, pdf_storage_key, docx_storage_key, violation_count, generated_at, failed_at, failed_format, recipient_emails
 `
 
 type CreateReportParams struct {
-	InspectionID   uuid.UUID      `json:"inspection_id"`
-	UserID         uuid.UUID      `json:"user_id"`
-	PdfStorageKey  sql.NullString `json:"pdf_storage_key"`
-	DocxStorageKey sql.NullString `json:"docx_storage_key"`
-	ViolationCount int32          `json:"violation_count"`
+	InspectionID    uuid.UUID      `json:"inspection_id"`
+	UserID          uuid.UUID      `json:"user_id"`
+	PdfStorageKey   sql.NullString `json:"pdf_storage_key"`
+	DocxStorageKey  sql.NullString `json:"docx_storage_key"`
+	ViolationCount  int32          `json:"violation_count"`
+	RecipientEmails []string       `json:"recipient_emails"`
 }
 
 func (q *Queries) CreateReport(ctx context.Context, arg CreateReportParams) (Report, error) {
@@ -65,6 +119,7 @@ func (q *Queries) CreateReport(ctx context.Context, arg CreateReportParams) (Rep
 		arg.PdfStorageKey,
 		arg.DocxStorageKey,
 		arg.ViolationCount,
+		pq.Array(arg.RecipientEmails),
 	)
 	var i Report
 	err := row.Scan(
@@ -75,12 +130,31 @@ func (q *Queries) CreateReport(ctx context.Context, arg CreateReportParams) (Rep
 		&i.DocxStorageKey,
 		&i.ViolationCount,
 		&i.GeneratedAt,
+		&i.FailedAt,
+		&i.FailedFormat,
+		pq.Array(&i.RecipientEmails),
 	)
 	return i, err
 }
 
+const deleteFailedReportsByInspectionID = `-- name: DeleteFailedReportsByInspectionID :exec
+DELETE FROM reports
+WHERE inspection_id = $1 AND user_id = $2 AND failed_at IS NOT NULL
+`
+
+type DeleteFailedReportsByInspectionIDParams struct {
+	InspectionID uuid.UUID `json:"inspection_id"`
+	UserID       uuid.UUID `json:"user_id"`
+}
+
+// Clear failure records once a report for the inspection generates.
+func (q *Queries) DeleteFailedReportsByInspectionID(ctx context.Context, arg DeleteFailedReportsByInspectionIDParams) error {
+	_, err := q.db.ExecContext(ctx, deleteFailedReportsByInspectionID, arg.InspectionID, arg.UserID)
+	return err
+}
+
 const getReportByID = `-- name: GetReportByID :one
-SELECT id, inspection_id, user_id, pdf_storage_key, docx_storage_key, violation_count, generated_at FROM reports
+SELECT id, inspection_id, user_id, pdf_storage_key, docx_storage_key, violation_count, generated_at, failed_at, failed_format, recipient_emails FROM reports
 WHERE id = $1
 `
 
@@ -95,12 +169,15 @@ func (q *Queries) GetReportByID(ctx context.Context, id uuid.UUID) (Report, erro
 		&i.DocxStorageKey,
 		&i.ViolationCount,
 		&i.GeneratedAt,
+		&i.FailedAt,
+		&i.FailedFormat,
+		pq.Array(&i.RecipientEmails),
 	)
 	return i, err
 }
 
 const getReportByIDAndUserID = `-- name: GetReportByIDAndUserID :one
-SELECT id, inspection_id, user_id, pdf_storage_key, docx_storage_key, violation_count, generated_at FROM reports
+SELECT id, inspection_id, user_id, pdf_storage_key, docx_storage_key, violation_count, generated_at, failed_at, failed_format, recipient_emails FROM reports
 WHERE id = $1 AND user_id = $2
 `
 
@@ -120,12 +197,15 @@ func (q *Queries) GetReportByIDAndUserID(ctx context.Context, arg GetReportByIDA
 		&i.DocxStorageKey,
 		&i.ViolationCount,
 		&i.GeneratedAt,
+		&i.FailedAt,
+		&i.FailedFormat,
+		pq.Array(&i.RecipientEmails),
 	)
 	return i, err
 }
 
 const listReportsByInspectionID = `-- name: ListReportsByInspectionID :many
-SELECT id, inspection_id, user_id, pdf_storage_key, docx_storage_key, violation_count, generated_at FROM reports
+SELECT id, inspection_id, user_id, pdf_storage_key, docx_storage_key, violation_count, generated_at, failed_at, failed_format, recipient_emails FROM reports
 WHERE inspection_id = $1
 ORDER BY generated_at DESC
 `
@@ -147,6 +227,9 @@ func (q *Queries) ListReportsByInspectionID(ctx context.Context, inspectionID uu
 			&i.DocxStorageKey,
 			&i.ViolationCount,
 			&i.GeneratedAt,
+			&i.FailedAt,
+			&i.FailedFormat,
+			pq.Array(&i.RecipientEmails),
 		); err != nil {
 			return nil, err
 		}
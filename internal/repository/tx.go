@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// TxBeginner starts database transactions. *sql.DB implements it.
+type TxBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// WithTx runs fn with queries bound to one transaction on q's database,
+// committing when fn returns nil and rolling back when it returns an error
+// or panics. fn's error is returned as is, so callers can return domain
+// errors from it.
+//
+// When q is already bound to a transaction, fn joins it and the outer
+// WithTx decides whether to commit.
+func WithTx(ctx context.Context, q *Queries, fn func(q *Queries) error) error {
+	if _, ok := q.db.(*sql.Tx); ok {
+		return fn(q)
+	}
+	db, ok := q.db.(TxBeginner)
+	if !ok {
+		return errors.New("repository: queries are not bound to a database that supports transactions")
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := fn(q.WithTx(tx)); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// UnitOfWork runs a function with queries bound to one transaction, so
+// services can make several writes atomically without knowing how the
+// transaction is started.
+type UnitOfWork interface {
+	// Do runs fn in a transaction, as WithTx does.
+	Do(ctx context.Context, fn func(q *Queries) error) error
+}
+
+// NewUnitOfWork returns a UnitOfWork running transactions on q's database.
+func NewUnitOfWork(q *Queries) UnitOfWork {
+	return queriesUnitOfWork{queries: q}
+}
+
+// queriesUnitOfWork implements UnitOfWork with WithTx.
+type queriesUnitOfWork struct {
+	queries *Queries
+}
+
+func (u queriesUnitOfWork) Do(ctx context.Context, fn func(q *Queries) error) error {
+	return WithTx(ctx, u.queries, fn)
+}
@@ -12,6 +12,30 @@ import (
 	"github.com/google/uuid"
 )
 
+const claimVerificationReminder = `-- name: ClaimVerificationReminder :execrows
+UPDATE users
+SET verification_reminder_sent_at = NOW(),
+    verification_reminder_count = verification_reminder_count + 1
+WHERE id = $1
+AND verification_reminder_count = $2
+AND email_verified IS NOT TRUE
+`
+
+type ClaimVerificationReminderParams struct {
+	ID                        uuid.UUID `json:"id"`
+	VerificationReminderCount int32     `json:"verification_reminder_count"`
+}
+
+// Records a reminder for the user; matches no row if another run already
+// recorded this reminder or the user has since verified
+func (q *Queries) ClaimVerificationReminder(ctx context.Context, arg ClaimVerificationReminderParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, claimVerificationReminder, arg.ID, arg.VerificationReminderCount)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (
     email,
@@ -22,7 +46,7 @@ INSERT INTO users (
 ) VALUES (
     $1, $2, $3, $4, $5
 )
-RETURNING id, email, password_hash, name, company_name, phone, stripe_customer_id, subscription_status, subscription_tier, subscription_id, email_verified, email_verified_at, created_at, updated_at, business_name, business_email, business_phone, business_address_line1, business_address_line2, business_city, business_state, business_postal_code, business_license_number, business_logo_url
+RETURNING id, email, password_hash, name, company_name, phone, stripe_customer_id, subscription_status, subscription_tier, subscription_id, email_verified, email_verified_at, created_at, updated_at, business_name, business_email, business_phone, business_address_line1, business_address_line2, business_city, business_state, business_postal_code, business_license_number, business_logo_url, disabled_at, verification_reminder_sent_at, verification_reminder_count, business_logo_key, business_brand_color
 `
 
 type CreateUserParams struct {
@@ -67,12 +91,17 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.BusinessPostalCode,
 		&i.BusinessLicenseNumber,
 		&i.BusinessLogoUrl,
+		&i.DisabledAt,
+		&i.VerificationReminderSentAt,
+		&i.VerificationReminderCount,
+		&i.BusinessLogoKey,
+		&i.BusinessBrandColor,
 	)
 	return i, err
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, email, password_hash, name, company_name, phone, stripe_customer_id, subscription_status, subscription_tier, subscription_id, email_verified, email_verified_at, created_at, updated_at, business_name, business_email, business_phone, business_address_line1, business_address_line2, business_city, business_state, business_postal_code, business_license_number, business_logo_url FROM users
+SELECT id, email, password_hash, name, company_name, phone, stripe_customer_id, subscription_status, subscription_tier, subscription_id, email_verified, email_verified_at, created_at, updated_at, business_name, business_email, business_phone, business_address_line1, business_address_line2, business_city, business_state, business_postal_code, business_license_number, business_logo_url, disabled_at, verification_reminder_sent_at, verification_reminder_count, business_logo_key, business_brand_color FROM users
 WHERE email = $1
 `
 
@@ -104,12 +133,17 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.BusinessPostalCode,
 		&i.BusinessLicenseNumber,
 		&i.BusinessLogoUrl,
+		&i.DisabledAt,
+		&i.VerificationReminderSentAt,
+		&i.VerificationReminderCount,
+		&i.BusinessLogoKey,
+		&i.BusinessBrandColor,
 	)
 	return i, err
 }
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, email, password_hash, name, company_name, phone, stripe_customer_id, subscription_status, subscription_tier, subscription_id, email_verified, email_verified_at, created_at, updated_at, business_name, business_email, business_phone, business_address_line1, business_address_line2, business_city, business_state, business_postal_code, business_license_number, business_logo_url FROM users
+SELECT id, email, password_hash, name, company_name, phone, stripe_customer_id, subscription_status, subscription_tier, subscription_id, email_verified, email_verified_at, created_at, updated_at, business_name, business_email, business_phone, business_address_line1, business_address_line2, business_city, business_state, business_postal_code, business_license_number, business_logo_url, disabled_at, verification_reminder_sent_at, verification_reminder_count, business_logo_key, business_brand_color FROM users
 WHERE id = $1
 `
 
@@ -141,12 +175,17 @@ func (q *Queries) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
 		&i.BusinessPostalCode,
 		&i.BusinessLicenseNumber,
 		&i.BusinessLogoUrl,
+		&i.DisabledAt,
+		&i.VerificationReminderSentAt,
+		&i.VerificationReminderCount,
+		&i.BusinessLogoKey,
+		&i.BusinessBrandColor,
 	)
 	return i, err
 }
 
 const getUserByStripeCustomerID = `-- name: GetUserByStripeCustomerID :one
-SELECT id, email, password_hash, name, company_name, phone, stripe_customer_id, subscription_status, subscription_tier, subscription_id, email_verified, email_verified_at, created_at, updated_at, business_name, business_email, business_phone, business_address_line1, business_address_line2, business_city, business_state, business_postal_code, business_license_number, business_logo_url FROM users
+SELECT id, email, password_hash, name, company_name, phone, stripe_customer_id, subscription_status, subscription_tier, subscription_id, email_verified, email_verified_at, created_at, updated_at, business_name, business_email, business_phone, business_address_line1, business_address_line2, business_city, business_state, business_postal_code, business_license_number, business_logo_url, disabled_at, verification_reminder_sent_at, verification_reminder_count, business_logo_key, business_brand_color FROM users
 WHERE stripe_customer_id = $1
 `
 
@@ -178,10 +217,86 @@ func (q *Queries) GetUserByStripeCustomerID(ctx context.Context, stripeCustomerI
 		&i.BusinessPostalCode,
 		&i.BusinessLicenseNumber,
 		&i.BusinessLogoUrl,
+		&i.DisabledAt,
+		&i.VerificationReminderSentAt,
+		&i.VerificationReminderCount,
+		&i.BusinessLogoKey,
+		&i.BusinessBrandColor,
 	)
 	return i, err
 }
 
+const listUsersDueVerificationReminder = `-- name: ListUsersDueVerificationReminder :many
+SELECT id, email, name, verification_reminder_count FROM users
+WHERE email_verified IS NOT TRUE
+AND disabled_at IS NULL
+AND created_at < $1
+AND (verification_reminder_sent_at IS NULL OR verification_reminder_sent_at < $1)
+AND verification_reminder_count < $2
+ORDER BY created_at
+LIMIT $3
+`
+
+type ListUsersDueVerificationReminderParams struct {
+	Cutoff       sql.NullTime `json:"cutoff"`
+	MaxReminders int32        `json:"max_reminders"`
+	BatchSize    int32        `json:"batch_size"`
+}
+
+type ListUsersDueVerificationReminderRow struct {
+	ID                        uuid.UUID `json:"id"`
+	Email                     string    `json:"email"`
+	Name                      string    `json:"name"`
+	VerificationReminderCount int32     `json:"verification_reminder_count"`
+}
+
+// Unverified, active users who registered before the cutoff and have not been
+// reminded since it, oldest first
+func (q *Queries) ListUsersDueVerificationReminder(ctx context.Context, arg ListUsersDueVerificationReminderParams) ([]ListUsersDueVerificationReminderRow, error) {
+	rows, err := q.db.QueryContext(ctx, listUsersDueVerificationReminder, arg.Cutoff, arg.MaxReminders, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListUsersDueVerificationReminderRow{}
+	for rows.Next() {
+		var i ListUsersDueVerificationReminderRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Name,
+			&i.VerificationReminderCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateUserBusinessLogo = `-- name: UpdateUserBusinessLogo :exec
+UPDATE users
+SET business_logo_key = $2,
+    updated_at = NOW()
+WHERE id = $1
+`
+
+type UpdateUserBusinessLogoParams struct {
+	ID              uuid.UUID      `json:"id"`
+	BusinessLogoKey sql.NullString `json:"business_logo_key"`
+}
+
+func (q *Queries) UpdateUserBusinessLogo(ctx context.Context, arg UpdateUserBusinessLogoParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserBusinessLogo, arg.ID, arg.BusinessLogoKey)
+	return err
+}
+
 const updateUserBusinessProfile = `-- name: UpdateUserBusinessProfile :exec
 UPDATE users
 SET business_name = $2,
@@ -194,6 +309,7 @@ SET business_name = $2,
     business_postal_code = $9,
     business_license_number = $10,
     business_logo_url = $11,
+    business_brand_color = $12,
     updated_at = NOW()
 WHERE id = $1
 `
@@ -210,6 +326,7 @@ type UpdateUserBusinessProfileParams struct {
 	BusinessPostalCode    sql.NullString `json:"business_postal_code"`
 	BusinessLicenseNumber sql.NullString `json:"business_license_number"`
 	BusinessLogoUrl       sql.NullString `json:"business_logo_url"`
+	BusinessBrandColor    sql.NullString `json:"business_brand_color"`
 }
 
 func (q *Queries) UpdateUserBusinessProfile(ctx context.Context, arg UpdateUserBusinessProfileParams) error {
@@ -225,6 +342,7 @@ func (q *Queries) UpdateUserBusinessProfile(ctx context.Context, arg UpdateUserB
 		arg.BusinessPostalCode,
 		arg.BusinessLicenseNumber,
 		arg.BusinessLogoUrl,
+		arg.BusinessBrandColor,
 	)
 	return err
 }
@@ -134,7 +134,7 @@ func (q *Queries) GetViolationRegulation(ctx context.Context, arg GetViolationRe
 
 const listRegulationsByViolationID = `-- name: ListRegulationsByViolationID :many
 SELECT
-    r.id, r.standard_number, r.title, r.category, r.subcategory, r.full_text, r.summary, r.severity_typical, r.parent_standard, r.effective_date, r.last_updated, r.search_vector, r.created_at, r.updated_at,
+    r.id, r.standard_number, r.title, r.category, r.subcategory, r.full_text, r.summary, r.severity_typical, r.parent_standard, r.effective_date, r.last_updated, r.search_vector, r.created_at, r.updated_at, r.deactivated_at,
     vr.relevance_score,
     vr.ai_explanation,
     vr.is_primary
@@ -159,6 +159,7 @@ type ListRegulationsByViolationIDRow struct {
 	SearchVector    interface{}     `json:"search_vector"`
 	CreatedAt       sql.NullTime    `json:"created_at"`
 	UpdatedAt       sql.NullTime    `json:"updated_at"`
+	DeactivatedAt   sql.NullTime    `json:"deactivated_at"`
 	RelevanceScore  sql.NullFloat64 `json:"relevance_score"`
 	AiExplanation   sql.NullString  `json:"ai_explanation"`
 	IsPrimary       sql.NullBool    `json:"is_primary"`
@@ -188,6 +189,7 @@ func (q *Queries) ListRegulationsByViolationID(ctx context.Context, violationID
 			&i.SearchVector,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeactivatedAt,
 			&i.RelevanceScore,
 			&i.AiExplanation,
 			&i.IsPrimary,
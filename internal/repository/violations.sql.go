@@ -8,11 +8,58 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/sqlc-dev/pqtype"
 )
 
+const countSearchViolationsByUserID = `-- name: CountSearchViolationsByUserID :one
+SELECT COUNT(*) FROM violations v
+JOIN inspections i ON i.id = v.inspection_id
+WHERE i.user_id = $1
+AND ($2::text = ''
+    OR v.description ILIKE '%' || $2::text || '%'
+    OR v.ai_description ILIKE '%' || $2::text || '%'
+    OR v.inspector_notes ILIKE '%' || $2::text || '%')
+AND ($3::text = '' OR v.severity = $3::text)
+AND ($4::text = '' OR v.status = $4::text)
+AND ($5::date IS NULL OR i.inspection_date >= $5::date)
+AND ($6::date IS NULL OR i.inspection_date <= $6::date)
+AND ($7::text = '' OR EXISTS (
+    SELECT 1 FROM violation_regulations vr
+    JOIN regulations r ON r.id = vr.regulation_id
+    WHERE vr.violation_id = v.id
+    AND r.standard_number LIKE $7::text || '%'
+))
+`
+
+type CountSearchViolationsByUserIDParams struct {
+	UserID         uuid.UUID    `json:"user_id"`
+	Search         string       `json:"search"`
+	Severity       string       `json:"severity"`
+	Status         string       `json:"status"`
+	FromDate       sql.NullTime `json:"from_date"`
+	ToDate         sql.NullTime `json:"to_date"`
+	StandardNumber string       `json:"standard_number"`
+}
+
+// Count the violations SearchViolationsByUserID matches across all pages.
+func (q *Queries) CountSearchViolationsByUserID(ctx context.Context, arg CountSearchViolationsByUserIDParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countSearchViolationsByUserID,
+		arg.UserID,
+		arg.Search,
+		arg.Severity,
+		arg.Status,
+		arg.FromDate,
+		arg.ToDate,
+		arg.StandardNumber,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const countViolationsByInspectionID = `-- name: CountViolationsByInspectionID :one
 SELECT COUNT(*) FROM violations
 WHERE inspection_id = $1
@@ -66,11 +113,12 @@ INSERT INTO violations (
     status,
     severity,
     inspector_notes,
-    sort_order
+    sort_order,
+    ai_severity
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
 )
-RETURNING id, inspection_id, image_id, description, ai_description, confidence, bounding_box, status, severity, inspector_notes, sort_order, created_at, updated_at
+RETURNING id, inspection_id, image_id, description, ai_description, confidence, bounding_box, status, severity, inspector_notes, sort_order, created_at, updated_at, ai_severity
 `
 
 type CreateViolationParams struct {
@@ -84,6 +132,7 @@ type CreateViolationParams struct {
 	Severity       sql.NullString        `json:"severity"`
 	InspectorNotes sql.NullString        `json:"inspector_notes"`
 	SortOrder      sql.NullInt32         `json:"sort_order"`
+	AiSeverity     sql.NullString        `json:"ai_severity"`
 }
 
 func (q *Queries) CreateViolation(ctx context.Context, arg CreateViolationParams) (Violation, error) {
@@ -98,6 +147,7 @@ func (q *Queries) CreateViolation(ctx context.Context, arg CreateViolationParams
 		arg.Severity,
 		arg.InspectorNotes,
 		arg.SortOrder,
+		arg.AiSeverity,
 	)
 	var i Violation
 	err := row.Scan(
@@ -114,6 +164,7 @@ func (q *Queries) CreateViolation(ctx context.Context, arg CreateViolationParams
 		&i.SortOrder,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.AiSeverity,
 	)
 	return i, err
 }
@@ -147,7 +198,7 @@ func (q *Queries) DeleteViolationByIDAndUserID(ctx context.Context, arg DeleteVi
 }
 
 const getViolationByID = `-- name: GetViolationByID :one
-SELECT id, inspection_id, image_id, description, ai_description, confidence, bounding_box, status, severity, inspector_notes, sort_order, created_at, updated_at FROM violations
+SELECT id, inspection_id, image_id, description, ai_description, confidence, bounding_box, status, severity, inspector_notes, sort_order, created_at, updated_at, ai_severity FROM violations
 WHERE id = $1
 `
 
@@ -168,12 +219,13 @@ func (q *Queries) GetViolationByID(ctx context.Context, id uuid.UUID) (Violation
 		&i.SortOrder,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.AiSeverity,
 	)
 	return i, err
 }
 
 const getViolationByIDAndInspectionID = `-- name: GetViolationByIDAndInspectionID :one
-SELECT id, inspection_id, image_id, description, ai_description, confidence, bounding_box, status, severity, inspector_notes, sort_order, created_at, updated_at FROM violations
+SELECT id, inspection_id, image_id, description, ai_description, confidence, bounding_box, status, severity, inspector_notes, sort_order, created_at, updated_at, ai_severity FROM violations
 WHERE id = $1 AND inspection_id = $2
 `
 
@@ -199,12 +251,13 @@ func (q *Queries) GetViolationByIDAndInspectionID(ctx context.Context, arg GetVi
 		&i.SortOrder,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.AiSeverity,
 	)
 	return i, err
 }
 
 const getViolationByIDAndUserID = `-- name: GetViolationByIDAndUserID :one
-SELECT v.id, v.inspection_id, v.image_id, v.description, v.ai_description, v.confidence, v.bounding_box, v.status, v.severity, v.inspector_notes, v.sort_order, v.created_at, v.updated_at FROM violations v
+SELECT v.id, v.inspection_id, v.image_id, v.description, v.ai_description, v.confidence, v.bounding_box, v.status, v.severity, v.inspector_notes, v.sort_order, v.created_at, v.updated_at, v.ai_severity FROM violations v
 JOIN inspections i ON i.id = v.inspection_id
 WHERE v.id = $1 AND i.user_id = $2
 `
@@ -231,13 +284,14 @@ func (q *Queries) GetViolationByIDAndUserID(ctx context.Context, arg GetViolatio
 		&i.SortOrder,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.AiSeverity,
 	)
 	return i, err
 }
 
 const getViolationWithImage = `-- name: GetViolationWithImage :one
 SELECT
-    v.id, v.inspection_id, v.image_id, v.description, v.ai_description, v.confidence, v.bounding_box, v.status, v.severity, v.inspector_notes, v.sort_order, v.created_at, v.updated_at,
+    v.id, v.inspection_id, v.image_id, v.description, v.ai_description, v.confidence, v.bounding_box, v.status, v.severity, v.inspector_notes, v.sort_order, v.created_at, v.updated_at, v.ai_severity,
     i.thumbnail_key,
     i.original_filename
 FROM violations v
@@ -259,6 +313,7 @@ type GetViolationWithImageRow struct {
 	SortOrder        sql.NullInt32         `json:"sort_order"`
 	CreatedAt        sql.NullTime          `json:"created_at"`
 	UpdatedAt        sql.NullTime          `json:"updated_at"`
+	AiSeverity       sql.NullString        `json:"ai_severity"`
 	ThumbnailKey     sql.NullString        `json:"thumbnail_key"`
 	OriginalFilename sql.NullString        `json:"original_filename"`
 }
@@ -280,14 +335,59 @@ func (q *Queries) GetViolationWithImage(ctx context.Context, id uuid.UUID) (GetV
 		&i.SortOrder,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.AiSeverity,
 		&i.ThumbnailKey,
 		&i.OriginalFilename,
 	)
 	return i, err
 }
 
+const listAIViolationMatchesByImageID = `-- name: ListAIViolationMatchesByImageID :many
+SELECT
+    v.id,
+    v.description,
+    r.standard_number
+FROM violations v
+LEFT JOIN violation_regulations vr ON vr.violation_id = v.id AND vr.is_primary = true
+LEFT JOIN regulations r ON r.id = vr.regulation_id
+WHERE v.image_id = $1
+AND v.ai_description IS NOT NULL
+ORDER BY v.sort_order ASC, v.created_at ASC
+`
+
+type ListAIViolationMatchesByImageIDRow struct {
+	ID             uuid.UUID      `json:"id"`
+	Description    string         `json:"description"`
+	StandardNumber sql.NullString `json:"standard_number"`
+}
+
+// List the AI-found violations of an image with the standard number of their
+// primary regulation, to match them against a new analysis of the image
+func (q *Queries) ListAIViolationMatchesByImageID(ctx context.Context, imageID uuid.NullUUID) ([]ListAIViolationMatchesByImageIDRow, error) {
+	rows, err := q.db.QueryContext(ctx, listAIViolationMatchesByImageID, imageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListAIViolationMatchesByImageIDRow{}
+	for rows.Next() {
+		var i ListAIViolationMatchesByImageIDRow
+		if err := rows.Scan(&i.ID, &i.Description, &i.StandardNumber); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listConfirmedViolationsByInspectionID = `-- name: ListConfirmedViolationsByInspectionID :many
-SELECT id, inspection_id, image_id, description, ai_description, confidence, bounding_box, status, severity, inspector_notes, sort_order, created_at, updated_at FROM violations
+SELECT id, inspection_id, image_id, description, ai_description, confidence, bounding_box, status, severity, inspector_notes, sort_order, created_at, updated_at, ai_severity FROM violations
 WHERE inspection_id = $1
 AND status = 'confirmed'
 ORDER BY sort_order ASC, created_at ASC
@@ -316,6 +416,7 @@ func (q *Queries) ListConfirmedViolationsByInspectionID(ctx context.Context, ins
 			&i.SortOrder,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.AiSeverity,
 		); err != nil {
 			return nil, err
 		}
@@ -331,12 +432,13 @@ func (q *Queries) ListConfirmedViolationsByInspectionID(ctx context.Context, ins
 }
 
 const listConfirmedViolationsByInspectionIDAndUserID = `-- name: ListConfirmedViolationsByInspectionIDAndUserID :many
-SELECT v.id, v.inspection_id, v.image_id, v.description, v.ai_description, v.confidence, v.bounding_box, v.status, v.severity, v.inspector_notes, v.sort_order, v.created_at, v.updated_at FROM violations v
+SELECT v.id, v.inspection_id, v.image_id, v.description, v.ai_description, v.confidence, v.bounding_box, v.status, v.severity, v.inspector_notes, v.sort_order, v.created_at, v.updated_at, v.ai_severity FROM violations v
 JOIN inspections i ON i.id = v.inspection_id
+LEFT JOIN images img ON img.id = v.image_id
 WHERE v.inspection_id = $1
 AND i.user_id = $2
 AND v.status = 'confirmed'
-ORDER BY v.sort_order ASC, v.created_at ASC
+ORDER BY img.display_order ASC NULLS LAST, v.sort_order ASC, v.created_at ASC
 `
 
 type ListConfirmedViolationsByInspectionIDAndUserIDParams struct {
@@ -344,7 +446,8 @@ type ListConfirmedViolationsByInspectionIDAndUserIDParams struct {
 	UserID       uuid.UUID `json:"user_id"`
 }
 
-// List confirmed violations with user authorization check (defense in depth)
+// List confirmed violations with user authorization check (defense in depth),
+// in the gallery order of their images; violations without an image come last
 func (q *Queries) ListConfirmedViolationsByInspectionIDAndUserID(ctx context.Context, arg ListConfirmedViolationsByInspectionIDAndUserIDParams) ([]Violation, error) {
 	rows, err := q.db.QueryContext(ctx, listConfirmedViolationsByInspectionIDAndUserID, arg.InspectionID, arg.UserID)
 	if err != nil {
@@ -368,6 +471,7 @@ func (q *Queries) ListConfirmedViolationsByInspectionIDAndUserID(ctx context.Con
 			&i.SortOrder,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.AiSeverity,
 		); err != nil {
 			return nil, err
 		}
@@ -383,7 +487,7 @@ func (q *Queries) ListConfirmedViolationsByInspectionIDAndUserID(ctx context.Con
 }
 
 const listViolationsByInspectionID = `-- name: ListViolationsByInspectionID :many
-SELECT id, inspection_id, image_id, description, ai_description, confidence, bounding_box, status, severity, inspector_notes, sort_order, created_at, updated_at FROM violations
+SELECT id, inspection_id, image_id, description, ai_description, confidence, bounding_box, status, severity, inspector_notes, sort_order, created_at, updated_at, ai_severity FROM violations
 WHERE inspection_id = $1
 ORDER BY sort_order ASC, created_at ASC
 `
@@ -411,6 +515,175 @@ func (q *Queries) ListViolationsByInspectionID(ctx context.Context, inspectionID
 			&i.SortOrder,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.AiSeverity,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchViolationsByUserID = `-- name: SearchViolationsByUserID :many
+SELECT
+    v.id, v.inspection_id, v.image_id, v.description, v.ai_description, v.confidence, v.bounding_box, v.status, v.severity, v.inspector_notes, v.sort_order, v.created_at, v.updated_at, v.ai_severity,
+    i.title AS inspection_title,
+    i.inspection_date,
+    COALESCE((
+        SELECT r.standard_number FROM violation_regulations vr
+        JOIN regulations r ON r.id = vr.regulation_id
+        WHERE vr.violation_id = v.id
+        ORDER BY vr.is_primary DESC NULLS LAST, vr.relevance_score DESC NULLS LAST
+        LIMIT 1
+    ), '')::text AS standard_number
+FROM violations v
+JOIN inspections i ON i.id = v.inspection_id
+WHERE i.user_id = $1
+AND ($2::text = ''
+    OR v.description ILIKE '%' || $2::text || '%'
+    OR v.ai_description ILIKE '%' || $2::text || '%'
+    OR v.inspector_notes ILIKE '%' || $2::text || '%')
+AND ($3::text = '' OR v.severity = $3::text)
+AND ($4::text = '' OR v.status = $4::text)
+AND ($5::date IS NULL OR i.inspection_date >= $5::date)
+AND ($6::date IS NULL OR i.inspection_date <= $6::date)
+AND ($7::text = '' OR EXISTS (
+    SELECT 1 FROM violation_regulations vr
+    JOIN regulations r ON r.id = vr.regulation_id
+    WHERE vr.violation_id = v.id
+    AND r.standard_number LIKE $7::text || '%'
+))
+ORDER BY i.inspection_date DESC, v.created_at DESC, v.id
+LIMIT $8 OFFSET $9
+`
+
+type SearchViolationsByUserIDParams struct {
+	UserID         uuid.UUID    `json:"user_id"`
+	Search         string       `json:"search"`
+	Severity       string       `json:"severity"`
+	Status         string       `json:"status"`
+	FromDate       sql.NullTime `json:"from_date"`
+	ToDate         sql.NullTime `json:"to_date"`
+	StandardNumber string       `json:"standard_number"`
+	Limit          int32        `json:"limit"`
+	Offset         int32        `json:"offset"`
+}
+
+type SearchViolationsByUserIDRow struct {
+	Violation       Violation `json:"violation"`
+	InspectionTitle string    `json:"inspection_title"`
+	InspectionDate  time.Time `json:"inspection_date"`
+	StandardNumber  string    `json:"standard_number"`
+}
+
+// Search the violations of the user's inspections, newest inspection first.
+// The search matches the description, AI description and inspector notes
+// (case-insensitive), and standard_number matches violations linked to a
+// regulation whose standard number starts with it. Empty filters match
+// everything. Each row carries the standard number of the violation's
+// primary regulation, or its most relevant one.
+func (q *Queries) SearchViolationsByUserID(ctx context.Context, arg SearchViolationsByUserIDParams) ([]SearchViolationsByUserIDRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchViolationsByUserID,
+		arg.UserID,
+		arg.Search,
+		arg.Severity,
+		arg.Status,
+		arg.FromDate,
+		arg.ToDate,
+		arg.StandardNumber,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SearchViolationsByUserIDRow{}
+	for rows.Next() {
+		var i SearchViolationsByUserIDRow
+		if err := rows.Scan(
+			&i.Violation.ID,
+			&i.Violation.InspectionID,
+			&i.Violation.ImageID,
+			&i.Violation.Description,
+			&i.Violation.AiDescription,
+			&i.Violation.Confidence,
+			&i.Violation.BoundingBox,
+			&i.Violation.Status,
+			&i.Violation.Severity,
+			&i.Violation.InspectorNotes,
+			&i.Violation.SortOrder,
+			&i.Violation.CreatedAt,
+			&i.Violation.UpdatedAt,
+			&i.Violation.AiSeverity,
+			&i.InspectionTitle,
+			&i.InspectionDate,
+			&i.StandardNumber,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const summarizeViolationsByUserID = `-- name: SummarizeViolationsByUserID :many
+SELECT
+    COALESCE(v.severity, '')::text AS severity,
+    v.status,
+    COUNT(*) AS violation_count
+FROM violations v
+JOIN inspections i ON i.id = v.inspection_id
+WHERE (i.user_id = $1 OR i.user_id IN (
+    SELECT them.user_id FROM organization_members me
+    JOIN organization_members them ON them.organization_id = me.organization_id
+    WHERE me.user_id = $1
+))
+AND i.inspection_date BETWEEN $2::date AND $3::date
+GROUP BY COALESCE(v.severity, ''), v.status
+ORDER BY COALESCE(v.severity, ''), v.status
+`
+
+type SummarizeViolationsByUserIDParams struct {
+	UserID   uuid.UUID `json:"user_id"`
+	FromDate time.Time `json:"from_date"`
+	ToDate   time.Time `json:"to_date"`
+}
+
+type SummarizeViolationsByUserIDRow struct {
+	Severity       string `json:"severity"`
+	Status         string `json:"status"`
+	ViolationCount int64  `json:"violation_count"`
+}
+
+// Count the violations of the inspections dated between from_date and
+// to_date, inclusive, by severity and status, across the user and the
+// members of their organization. Unrated violations have an empty severity.
+func (q *Queries) SummarizeViolationsByUserID(ctx context.Context, arg SummarizeViolationsByUserIDParams) ([]SummarizeViolationsByUserIDRow, error) {
+	rows, err := q.db.QueryContext(ctx, summarizeViolationsByUserID, arg.UserID, arg.FromDate, arg.ToDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SummarizeViolationsByUserIDRow{}
+	for rows.Next() {
+		var i SummarizeViolationsByUserIDRow
+		if err := rows.Scan(
+			&i.Severity,
+			&i.Status,
+			&i.ViolationCount,
 		); err != nil {
 			return nil, err
 		}
@@ -468,6 +741,41 @@ func (q *Queries) UpdateViolationDetails(ctx context.Context, arg UpdateViolatio
 	return err
 }
 
+const updateViolationFromReanalysis = `-- name: UpdateViolationFromReanalysis :exec
+UPDATE violations
+SET ai_description = $2,
+    confidence = $3,
+    bounding_box = $4,
+    ai_severity = $5,
+    description = CASE WHEN status = 'pending' THEN $6::text ELSE description END,
+    severity = CASE WHEN status = 'pending' THEN $5 ELSE severity END,
+    updated_at = NOW()
+WHERE id = $1
+`
+
+type UpdateViolationFromReanalysisParams struct {
+	ID            uuid.UUID             `json:"id"`
+	AiDescription sql.NullString        `json:"ai_description"`
+	Confidence    sql.NullString        `json:"confidence"`
+	BoundingBox   pqtype.NullRawMessage `json:"bounding_box"`
+	AiSeverity    sql.NullString        `json:"ai_severity"`
+	Description   string                `json:"description"`
+}
+
+// Refresh a violation with a new analysis of its image. The description and
+// severity are only replaced while the violation is still awaiting review.
+func (q *Queries) UpdateViolationFromReanalysis(ctx context.Context, arg UpdateViolationFromReanalysisParams) error {
+	_, err := q.db.ExecContext(ctx, updateViolationFromReanalysis,
+		arg.ID,
+		arg.AiDescription,
+		arg.Confidence,
+		arg.BoundingBox,
+		arg.AiSeverity,
+		arg.Description,
+	)
+	return err
+}
+
 const updateViolationNotes = `-- name: UpdateViolationNotes :exec
 UPDATE violations
 SET inspector_notes = $2,
@@ -0,0 +1,97 @@
+// Package requestid carries a per-request correlation ID through context and
+// into log output.
+//
+// This package is designed to be imported by middleware, handlers, services,
+// and the background worker without causing import cycles.
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+)
+
+// Header is the HTTP header that carries the request ID.
+const Header = "X-Request-ID"
+
+// LogKey is the log attribute key for the request ID.
+const LogKey = "request_id"
+
+// maxLength caps IDs accepted from clients so they cannot bloat logs.
+const maxLength = 128
+
+// contextKey is a custom type for context keys to avoid collisions.
+type contextKey struct{}
+
+// New returns a random 32-character hex request ID.
+func New() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Valid reports whether id is acceptable as a client-supplied request ID:
+// non-empty, at most 128 characters, and limited to letters, digits, and
+// "-", "_", ".", ":", "=" so it is safe to echo in headers and log lines.
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':', c == '=':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// NewContext returns a copy of ctx carrying the request ID.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID stored in ctx, or "" if there is none.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// =============================================================================
+// Log Handler
+// =============================================================================
+
+// LogHandler is a slog.Handler that adds the request ID from the record's
+// context to every record. Use the context-aware logging methods
+// (InfoContext, ErrorContext, ...) for the ID to be included.
+type LogHandler struct {
+	slog.Handler
+}
+
+// NewLogHandler wraps next so records logged with a request context carry a
+// request_id attribute.
+func NewLogHandler(next slog.Handler) *LogHandler {
+	return &LogHandler{Handler: next}
+}
+
+// Handle adds the request ID, if any, and passes the record on.
+func (h *LogHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := FromContext(ctx); id != "" {
+		r = r.Clone()
+		r.AddAttrs(slog.String(LogKey, id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+// WithAttrs returns a LogHandler whose wrapped handler has the attributes.
+func (h *LogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &LogHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup returns a LogHandler whose wrapped handler uses the group.
+func (h *LogHandler) WithGroup(name string) slog.Handler {
+	return &LogHandler{Handler: h.Handler.WithGroup(name)}
+}
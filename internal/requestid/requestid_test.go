@@ -0,0 +1,71 @@
+package requestid
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	id := New()
+	if len(id) != 32 || !Valid(id) {
+		t.Errorf("expected 32-character valid ID, got %q", id)
+	}
+	if New() == id {
+		t.Error("expected distinct IDs")
+	}
+}
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		id   string
+		want bool
+	}{
+		{"7f3a9c", true},
+		{"Root=1-67891233-abcdef012345678912345678", true},
+		{"req_01.abc:2", true},
+		{"", false},
+		{"has space", false},
+		{"line\nbreak", false},
+		{"quote\"", false},
+		{strings.Repeat("a", 128), true},
+		{strings.Repeat("a", 129), false},
+	}
+	for _, tt := range tests {
+		if got := Valid(tt.id); got != tt.want {
+			t.Errorf("Valid(%q) = %v, want %v", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestContext(t *testing.T) {
+	if got := FromContext(context.Background()); got != "" {
+		t.Errorf("expected no ID in empty context, got %q", got)
+	}
+	ctx := NewContext(context.Background(), "abc123")
+	if got := FromContext(ctx); got != "abc123" {
+		t.Errorf("expected abc123, got %q", got)
+	}
+}
+
+func TestLogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewLogHandler(slog.NewJSONHandler(&buf, nil))).With("component", "test")
+	ctx := NewContext(context.Background(), "abc123")
+
+	logger.InfoContext(ctx, "with request")
+	logger.Info("without request")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 log lines, got %q", buf.String())
+	}
+	if !strings.Contains(lines[0], `"request_id":"abc123"`) || !strings.Contains(lines[0], `"component":"test"`) {
+		t.Errorf("expected request_id and logger attrs, got %s", lines[0])
+	}
+	if strings.Contains(lines[1], "request_id") {
+		t.Errorf("expected no request_id without a request context, got %s", lines[1])
+	}
+}
@@ -0,0 +1,113 @@
+package scan
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// clamAVChunkSize is the size of each INSTREAM chunk. clamd's default
+// StreamMaxLength (25MB) bounds the total, not the chunk.
+const clamAVChunkSize = 64 * 1024
+
+// ClamAVConfig configures a ClamAV scanner.
+type ClamAVConfig struct {
+	Address string        // clamd TCP address, e.g. "clamav:3310"
+	Timeout time.Duration // Bounds a single scan (default: DefaultTimeout)
+}
+
+// ClamAV scans files with clamd's INSTREAM command over TCP.
+type ClamAV struct {
+	address string
+	timeout time.Duration
+	dialer  net.Dialer
+}
+
+// NewClamAV creates a ClamAV scanner.
+func NewClamAV(cfg ClamAVConfig) *ClamAV {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &ClamAV{address: cfg.Address, timeout: timeout}
+}
+
+var _ Scanner = (*ClamAV)(nil)
+
+// Scan streams r to clamd and reports its verdict.
+func (c *ClamAV) Scan(ctx context.Context, r io.Reader) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	conn, err := c.dialer.DialContext(ctx, "tcp", c.address)
+	if err != nil {
+		return fmt.Errorf("clamav: connect: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	if err := writeInstream(conn, r); err != nil {
+		return fmt.Errorf("clamav: send: %w", err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && !(errors.Is(err, io.EOF) && reply != "") {
+		return fmt.Errorf("clamav: read reply: %w", err)
+	}
+	return parseClamAVReply(strings.TrimRight(reply, "\x00\n"))
+}
+
+// writeInstream sends r as a null-terminated INSTREAM command: each chunk
+// is prefixed with its big-endian length, and a zero length ends the stream.
+func writeInstream(w io.Writer, r io.Reader) error {
+	if _, err := io.WriteString(w, "zINSTREAM\x00"); err != nil {
+		return err
+	}
+	buf := make([]byte, clamAVChunkSize)
+	var size [4]byte
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size[:], uint32(n))
+			if _, werr := w.Write(size[:]); werr != nil {
+				return werr
+			}
+			if _, werr := w.Write(buf[:n]); werr != nil {
+				return werr
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	binary.BigEndian.PutUint32(size[:], 0)
+	_, err := w.Write(size[:])
+	return err
+}
+
+// parseClamAVReply interprets a clamd reply such as "stream: OK" or
+// "stream: Eicar-Test-Signature FOUND".
+func parseClamAVReply(reply string) error {
+	result, ok := strings.CutPrefix(reply, "stream: ")
+	switch {
+	case ok && result == "OK":
+		return nil
+	case ok && strings.HasSuffix(result, " FOUND"):
+		return &ThreatError{Signature: strings.TrimSuffix(result, " FOUND")}
+	case strings.Contains(reply, "size limit exceeded"):
+		return fmt.Errorf("clamav: file exceeds clamd's StreamMaxLength")
+	default:
+		return fmt.Errorf("clamav: unexpected reply %q", reply)
+	}
+}
@@ -0,0 +1,140 @@
+package scan
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+)
+
+// eicar is the EICAR antivirus test file, which every scanner flags.
+const eicar = `X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`
+
+// fakeClamd serves one INSTREAM command per connection, flagging streams
+// that contain the EICAR string. It returns the listener's address and a
+// channel of the streams it received.
+func fakeClamd(t *testing.T) (string, <-chan []byte) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	received := make(chan []byte, 10)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer func() { _ = conn.Close() }()
+				r := bufio.NewReader(conn)
+				if cmd, err := r.ReadString(0); err != nil || cmd != "zINSTREAM\x00" {
+					_, _ = io.WriteString(conn, "UNKNOWN COMMAND\x00")
+					return
+				}
+				var stream bytes.Buffer
+				for {
+					var size uint32
+					if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+						return
+					}
+					if size == 0 {
+						break
+					}
+					if _, err := io.CopyN(&stream, r, int64(size)); err != nil {
+						return
+					}
+				}
+				received <- stream.Bytes()
+				if bytes.Contains(stream.Bytes(), []byte("EICAR-STANDARD-ANTIVIRUS-TEST-FILE")) {
+					_, _ = io.WriteString(conn, "stream: Eicar-Test-Signature FOUND\x00")
+					return
+				}
+				_, _ = io.WriteString(conn, "stream: OK\x00")
+			}()
+		}
+	}()
+	return ln.Addr().String(), received
+}
+
+func TestClamAVScan(t *testing.T) {
+	addr, received := fakeClamd(t)
+	scanner := NewClamAV(ClamAVConfig{Address: addr})
+
+	// Larger than a chunk, so the stream is sent in pieces
+	clean := bytes.Repeat([]byte("jpeg"), clamAVChunkSize/2)
+	if err := scanner.Scan(context.Background(), bytes.NewReader(clean)); err != nil {
+		t.Fatalf("expected a clean file to pass, got %v", err)
+	}
+	if got := <-received; !bytes.Equal(got, clean) {
+		t.Errorf("expected clamd to receive the whole file, got %d of %d bytes", len(got), len(clean))
+	}
+
+	err := scanner.Scan(context.Background(), strings.NewReader(eicar))
+	var threat *ThreatError
+	if !errors.As(err, &threat) {
+		t.Fatalf("expected a ThreatError, got %v", err)
+	}
+	if threat.Signature != "Eicar-Test-Signature" {
+		t.Errorf("expected the signature name, got %q", threat.Signature)
+	}
+}
+
+func TestClamAVScan_UnreachableIsNotAThreat(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	_ = ln.Close()
+
+	err = NewClamAV(ClamAVConfig{Address: addr}).Scan(context.Background(), strings.NewReader("data"))
+	var threat *ThreatError
+	if err == nil || errors.As(err, &threat) {
+		t.Errorf("expected a scan failure that isn't a threat, got %v", err)
+	}
+}
+
+func TestParseClamAVReply(t *testing.T) {
+	tests := []struct {
+		reply      string
+		wantErr    bool
+		wantThreat string
+	}{
+		{"stream: OK", false, ""},
+		{"stream: Win.Test.EICAR_HDB-1 FOUND", true, "Win.Test.EICAR_HDB-1"},
+		{"INSTREAM size limit exceeded. ERROR", true, ""},
+		{"stream: Can't allocate memory ERROR", true, ""},
+	}
+
+	for _, tt := range tests {
+		err := parseClamAVReply(tt.reply)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%q: expected error %t, got %v", tt.reply, tt.wantErr, err)
+		}
+		var threat *ThreatError
+		if errors.As(err, &threat) != (tt.wantThreat != "") || (threat != nil && threat.Signature != tt.wantThreat) {
+			t.Errorf("%q: expected threat %q, got %v", tt.reply, tt.wantThreat, err)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	if s, err := New(Config{}); err != nil || s != (Noop{}) {
+		t.Errorf("expected Noop by default, got %v, %v", s, err)
+	}
+	if _, err := New(Config{Provider: ProviderClamAV}); err == nil {
+		t.Error("expected an error without a clamd address")
+	}
+	if _, err := New(Config{Provider: "virustotal"}); err == nil {
+		t.Error("expected an error for an unknown provider")
+	}
+}
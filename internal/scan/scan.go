@@ -0,0 +1,73 @@
+// Package scan checks uploaded files for malware before they are stored.
+//
+// Scanner is the extension point for a malware scanner. The default, Noop,
+// accepts every file. New selects a scanner from configuration.
+package scan
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Provider names accepted by New.
+const (
+	ProviderNone   = "none"
+	ProviderClamAV = "clamav"
+)
+
+// DefaultTimeout bounds a single scan.
+const DefaultTimeout = 30 * time.Second
+
+// Scanner checks file contents for malware.
+//
+// Implementations:
+// - Noop: accepts every file (default)
+// - ClamAV: clamd over TCP
+type Scanner interface {
+	// Scan reads r to the end and returns a *ThreatError if the contents are
+	// flagged. Any other error means the file could not be scanned.
+	Scan(ctx context.Context, r io.Reader) error
+}
+
+// ThreatError is returned by Scan when a file is flagged.
+type ThreatError struct {
+	Signature string // Name of the matched signature, e.g. "Eicar-Test-Signature"
+}
+
+func (e *ThreatError) Error() string {
+	return fmt.Sprintf("scan: file flagged as %s", e.Signature)
+}
+
+// Noop is a Scanner that accepts every file.
+type Noop struct{}
+
+// Scan always returns nil.
+func (Noop) Scan(ctx context.Context, r io.Reader) error {
+	return nil
+}
+
+var _ Scanner = Noop{}
+
+// Config selects and configures a scanner.
+type Config struct {
+	Provider string        // ProviderNone (default) or ProviderClamAV
+	Address  string        // clamd TCP address, e.g. "clamav:3310"; required for ClamAV
+	Timeout  time.Duration // Bounds a single scan (default: DefaultTimeout)
+}
+
+// New returns the scanner for cfg.Provider.
+func New(cfg Config) (Scanner, error) {
+	switch cfg.Provider {
+	case "", ProviderNone:
+		return Noop{}, nil
+	case ProviderClamAV:
+		if cfg.Address == "" {
+			return nil, fmt.Errorf("scan: address is required for the clamav provider")
+		}
+		return NewClamAV(ClamAVConfig{Address: cfg.Address, Timeout: cfg.Timeout}), nil
+	default:
+		return nil, fmt.Errorf("scan: unknown provider %q", cfg.Provider)
+	}
+}
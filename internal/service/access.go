@@ -0,0 +1,74 @@
+// Package service contains the business logic layer.
+//
+// This file implements organization-aware access to inspections and the
+// images and violations in them.
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/google/uuid"
+)
+
+// orgAccess resolves whose inspection a user is working on.
+//
+// Inspections are stored under the inspector who created them, and the
+// services' queries are scoped to that owner. Members of an organization may
+// also work on each other's inspections, so before running an owner-scoped
+// query a service asks orgAccess for the owner and passes it in place of the
+// acting user. The acting user is still the one recorded in audit events.
+//
+// Users outside an organization resolve only to themselves, so single-user
+// accounts behave exactly as before.
+type orgAccess struct {
+	queries *repository.Queries
+}
+
+// inspectionOwner returns the owner of an inspection the user may work on.
+// When the user may not, or the inspection does not exist, it returns userID
+// so the caller's owner-scoped query finds nothing and reports not found.
+// Any other failure is returned as an internal error rather than running
+// the caller's query as the user.
+func (a orgAccess) inspectionOwner(ctx context.Context, inspectionID, userID uuid.UUID) (uuid.UUID, error) {
+	owner, err := a.queries.GetInspectionOwnerIDForUser(ctx, repository.GetInspectionOwnerIDForUserParams{
+		ID:     inspectionID,
+		UserID: userID,
+	})
+	return ownerOrUser(owner, userID, err, "orgAccess.inspectionOwner")
+}
+
+// imageOwner returns the owner of the inspection of an image the user may
+// work on, or userID as inspectionOwner does.
+func (a orgAccess) imageOwner(ctx context.Context, imageID, userID uuid.UUID) (uuid.UUID, error) {
+	owner, err := a.queries.GetImageOwnerIDForUser(ctx, repository.GetImageOwnerIDForUserParams{
+		ID:     imageID,
+		UserID: userID,
+	})
+	return ownerOrUser(owner, userID, err, "orgAccess.imageOwner")
+}
+
+// violationOwner returns the owner of the inspection of a violation the user
+// may work on, or userID as inspectionOwner does.
+func (a orgAccess) violationOwner(ctx context.Context, violationID, userID uuid.UUID) (uuid.UUID, error) {
+	owner, err := a.queries.GetViolationOwnerIDForUser(ctx, repository.GetViolationOwnerIDForUserParams{
+		ID:     violationID,
+		UserID: userID,
+	})
+	return ownerOrUser(owner, userID, err, "orgAccess.violationOwner")
+}
+
+// ownerOrUser returns the owner found by an owner lookup, userID when the
+// lookup found no row, or an internal error when it failed.
+func ownerOrUser(owner, userID uuid.UUID, err error, op string) (uuid.UUID, error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return userID, nil
+	case err != nil:
+		return uuid.Nil, domain.Internal(err, op, "failed to resolve the inspection owner")
+	}
+	return owner, nil
+}
@@ -0,0 +1,201 @@
+// Package service contains the business logic layer.
+//
+// This file implements the audit service for recording who changed
+// inspections, violations, and reports, and when.
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/google/uuid"
+	"github.com/sqlc-dev/pqtype"
+)
+
+// =============================================================================
+// Interface Definition
+// =============================================================================
+
+// AuditService defines operations for the audit log.
+type AuditService interface {
+	// Record writes an audit event on its own.
+	// Use RecordChange instead when the event describes a database mutation.
+	Record(ctx context.Context, event domain.AuditEvent) error
+
+	// RecordChange runs change and writes the audit events in one transaction,
+	// so a mutation is never committed without its audit records.
+	// Errors returned by change are passed through unchanged.
+	RecordChange(ctx context.Context, change func(q *repository.Queries) error, events ...domain.AuditEvent) error
+
+	// ListByInspection returns the audit events for an inspection owned by
+	// the user, oldest first.
+	ListByInspection(ctx context.Context, inspectionID, userID uuid.UUID) ([]domain.AuditEvent, error)
+}
+
+// =============================================================================
+// Implementation
+// =============================================================================
+
+// auditService implements the AuditService interface.
+type auditService struct {
+	queries *repository.Queries
+	uow     repository.UnitOfWork
+	logger  *slog.Logger
+}
+
+// NewAuditService creates a new AuditService.
+// Mutations and audit writes run in one transaction of the unit of work.
+func NewAuditService(
+	queries *repository.Queries,
+	uow repository.UnitOfWork,
+	logger *slog.Logger,
+) AuditService {
+	return &auditService{
+		queries: queries,
+		uow:     uow,
+		logger:  logger,
+	}
+}
+
+// =============================================================================
+// Record
+// =============================================================================
+
+// Record writes an audit event on its own.
+func (s *auditService) Record(ctx context.Context, event domain.AuditEvent) error {
+	const op = "audit.record"
+
+	if err := s.insert(ctx, s.queries, event); err != nil {
+		return domain.Internal(err, op, "failed to record audit event")
+	}
+	return nil
+}
+
+// RecordChange runs change and writes the audit events in one transaction.
+func (s *auditService) RecordChange(ctx context.Context, change func(q *repository.Queries) error, events ...domain.AuditEvent) error {
+	const op = "audit.record_change"
+
+	err := s.uow.Do(ctx, func(qtx *repository.Queries) error {
+		if err := change(qtx); err != nil {
+			return err
+		}
+		for _, event := range events {
+			if err := s.insert(ctx, qtx, event); err != nil {
+				return domain.Internal(err, op, "failed to record audit event")
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return txError(err, op)
+	}
+	return nil
+}
+
+// txError returns an error from a unit of work as a domain error.
+// Errors from the transaction's function already are; the rest come from
+// beginning or committing the transaction.
+func txError(err error, op string) error {
+	var domainErr *domain.Error
+	if errors.As(err, &domainErr) {
+		return err
+	}
+	return domain.Internal(err, op, "failed to run transaction")
+}
+
+// insert writes the audit row using the given queries.
+func (s *auditService) insert(ctx context.Context, q *repository.Queries, event domain.AuditEvent) error {
+	oldValues, err := marshalAuditValues(event.OldValues)
+	if err != nil {
+		return fmt.Errorf("marshal old values: %w", err)
+	}
+	newValues, err := marshalAuditValues(event.NewValues)
+	if err != nil {
+		return fmt.Errorf("marshal new values: %w", err)
+	}
+
+	var inspectionID uuid.NullUUID
+	if event.InspectionID != nil {
+		inspectionID = uuid.NullUUID{UUID: *event.InspectionID, Valid: true}
+	}
+
+	_, err = q.CreateAuditEvent(ctx, repository.CreateAuditEventParams{
+		ActorUserID:  event.ActorUserID,
+		InspectionID: inspectionID,
+		EntityType:   string(event.EntityType),
+		EntityID:     event.EntityID,
+		Action:       string(event.Action),
+		OldValues:    oldValues,
+		NewValues:    newValues,
+	})
+	return err
+}
+
+// =============================================================================
+// ListByInspection
+// =============================================================================
+
+// ListByInspection returns the audit events for an inspection, oldest first.
+func (s *auditService) ListByInspection(ctx context.Context, inspectionID, userID uuid.UUID) ([]domain.AuditEvent, error) {
+	const op = "audit.list"
+
+	rows, err := s.queries.ListAuditEventsByInspectionIDAndUserID(ctx, repository.ListAuditEventsByInspectionIDAndUserIDParams{
+		InspectionID: uuid.NullUUID{UUID: inspectionID, Valid: true},
+		UserID:       userID,
+	})
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to list audit events")
+	}
+
+	events := make([]domain.AuditEvent, 0, len(rows))
+	for _, row := range rows {
+		events = append(events, domain.AuditEvent{
+			ID:           row.ID,
+			ActorUserID:  row.ActorUserID,
+			ActorName:    domain.NullStringValue(row.ActorName),
+			InspectionID: nullUUIDToPtr(row.InspectionID),
+			EntityType:   domain.AuditEntityType(row.EntityType),
+			EntityID:     row.EntityID,
+			Action:       domain.AuditAction(row.Action),
+			OldValues:    unmarshalAuditValues(row.OldValues),
+			NewValues:    unmarshalAuditValues(row.NewValues),
+			CreatedAt:    row.CreatedAt,
+		})
+	}
+
+	return events, nil
+}
+
+// =============================================================================
+// Helper Functions
+// =============================================================================
+
+// marshalAuditValues encodes audit values as JSONB, storing NULL when empty.
+func marshalAuditValues(values map[string]string) (pqtype.NullRawMessage, error) {
+	if len(values) == 0 {
+		return pqtype.NullRawMessage{}, nil
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return pqtype.NullRawMessage{}, err
+	}
+	return pqtype.NullRawMessage{RawMessage: data, Valid: true}, nil
+}
+
+// unmarshalAuditValues decodes JSONB audit values. Malformed or NULL values
+// decode to nil rather than failing the whole history.
+func unmarshalAuditValues(raw pqtype.NullRawMessage) map[string]string {
+	if !raw.Valid {
+		return nil
+	}
+	var values map[string]string
+	if err := json.Unmarshal(raw.RawMessage, &values); err != nil {
+		return nil
+	}
+	return values
+}
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/DukeRupert/lukaut/internal/testutil/fakedb"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// In-Memory Audit Database
+// =============================================================================
+
+// fakeAuditDB answers the audit insert and an inspection status update, the
+// change the tests record. Writes made in a transaction are undone when it
+// rolls back.
+type fakeAuditDB struct {
+	statuses  map[string]string // Inspection statuses by ID
+	audits    int               // Audit rows written
+	failAudit bool              // Fail every audit insert
+}
+
+func (f *fakeAuditDB) Query(q fakedb.Query) (*fakedb.Rows, error) {
+	args := q.Args
+
+	if q.Name == "CreateAuditEvent" {
+		if f.failAudit {
+			return nil, errors.New("fakeAuditDB: audit insert failed")
+		}
+		f.audits++
+		q.OnRollback(func() { f.audits-- })
+		return fakedb.Row(
+			uuid.NewString(), args[0].Value, args[1].Value, args[2].Value, args[3].Value, args[4].Value, args[5].Value, args[6].Value, time.Now(),
+		), nil
+	}
+	return nil, fmt.Errorf("fakeAuditDB: unexpected query %q", q.Name)
+}
+
+func (f *fakeAuditDB) Exec(q fakedb.Query) (int64, error) {
+	args := q.Args
+
+	if q.Name == "UpdateInspectionStatusByIDAndUserID" {
+		id := args[0].Value.(string)
+		old := f.statuses[id]
+		f.statuses[id] = args[2].Value.(string)
+		q.OnRollback(func() { f.statuses[id] = old })
+		return 1, nil
+	}
+	return 0, fmt.Errorf("fakeAuditDB: unexpected exec %q", q.Name)
+}
+
+func newAuditTestService(f *fakeAuditDB) AuditService {
+	queries := repository.New(fakedb.Open(f))
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewAuditService(queries, repository.NewUnitOfWork(queries), logger)
+}
+
+// completeInspection returns a change completing the inspection, and the
+// audit event describing it.
+func completeInspection(inspectionID, userID uuid.UUID) (func(q *repository.Queries) error, domain.AuditEvent) {
+	change := func(q *repository.Queries) error {
+		return q.UpdateInspectionStatusByIDAndUserID(context.Background(), repository.UpdateInspectionStatusByIDAndUserIDParams{
+			ID:     inspectionID,
+			UserID: userID,
+			Status: domain.InspectionStatusCompleted.String(),
+		})
+	}
+	event := domain.AuditEvent{
+		ActorUserID:  userID,
+		InspectionID: &inspectionID,
+		EntityType:   domain.AuditEntityInspection,
+		EntityID:     inspectionID,
+		Action:       domain.AuditActionStatusChanged,
+		OldValues:    map[string]string{"status": domain.InspectionStatusReview.String()},
+		NewValues:    map[string]string{"status": domain.InspectionStatusCompleted.String()},
+	}
+	return change, event
+}
+
+// =============================================================================
+// RecordChange Tests
+// =============================================================================
+
+func TestAuditRecordChange_CommitsChangeAndAudit(t *testing.T) {
+	inspectionID, userID := uuid.New(), uuid.New()
+	f := &fakeAuditDB{statuses: map[string]string{inspectionID.String(): domain.InspectionStatusReview.String()}}
+	change, event := completeInspection(inspectionID, userID)
+
+	if err := newAuditTestService(f).RecordChange(context.Background(), change, event); err != nil {
+		t.Fatalf("RecordChange: %v", err)
+	}
+
+	if got := f.statuses[inspectionID.String()]; got != domain.InspectionStatusCompleted.String() {
+		t.Errorf("expected the inspection completed, got %q", got)
+	}
+	if f.audits != 1 {
+		t.Errorf("expected one audit row, got %d", f.audits)
+	}
+}
+
+func TestAuditRecordChange_FailedChangeWritesNoAudit(t *testing.T) {
+	inspectionID, userID := uuid.New(), uuid.New()
+	f := &fakeAuditDB{statuses: map[string]string{inspectionID.String(): domain.InspectionStatusReview.String()}}
+	complete, event := completeInspection(inspectionID, userID)
+	conflict := domain.Conflict("test", "inspection was changed")
+
+	// The change writes, then fails
+	change := func(q *repository.Queries) error {
+		if err := complete(q); err != nil {
+			return err
+		}
+		return conflict
+	}
+	err := newAuditTestService(f).RecordChange(context.Background(), change, event)
+
+	if !errors.Is(err, conflict) {
+		t.Errorf("expected the change's error passed through, got %v", err)
+	}
+	if got := f.statuses[inspectionID.String()]; got != domain.InspectionStatusReview.String() {
+		t.Errorf("expected the change rolled back, got status %q", got)
+	}
+	if f.audits != 0 {
+		t.Errorf("expected no audit rows, got %d", f.audits)
+	}
+}
+
+func TestAuditRecordChange_FailedAuditRollsBackChange(t *testing.T) {
+	inspectionID, userID := uuid.New(), uuid.New()
+	f := &fakeAuditDB{
+		statuses:  map[string]string{inspectionID.String(): domain.InspectionStatusReview.String()},
+		failAudit: true,
+	}
+	change, event := completeInspection(inspectionID, userID)
+
+	err := newAuditTestService(f).RecordChange(context.Background(), change, event)
+
+	if domain.ErrorCode(err) != domain.EINTERNAL {
+		t.Errorf("expected EINTERNAL, got %v", err)
+	}
+	if got := f.statuses[inspectionID.String()]; got != domain.InspectionStatusReview.String() {
+		t.Errorf("expected the change rolled back, got status %q", got)
+	}
+}
+
+// =============================================================================
+// Timeline Tests
+// =============================================================================
+
+// fakeTimelineDB adds the inspection's uploads to the inspections database.
+type fakeTimelineDB struct {
+	*fakeInspectionsDB
+}
+
+func (f *fakeTimelineDB) Query(q fakedb.Query) (*fakedb.Rows, error) {
+	if q.Name == "ListImagesByInspectionID" {
+		return &fakedb.Rows{Columns: 15}, nil
+	}
+	return f.fakeInspectionsDB.Query(q)
+}
+
+// fakeTimelineAudit serves a fixed audit history and records whose
+// inspections it was asked for.
+type fakeTimelineAudit struct {
+	AuditService
+	events     []domain.AuditEvent
+	listedUser *uuid.UUID
+}
+
+func (a *fakeTimelineAudit) ListByInspection(ctx context.Context, inspectionID, userID uuid.UUID) ([]domain.AuditEvent, error) {
+	a.listedUser = &userID
+	return a.events, nil
+}
+
+func TestInspectionTimeline_TeammateSeesOwnersHistoryInOrder(t *testing.T) {
+	owner, teammate, inspectionID := uuid.New(), uuid.New(), uuid.New()
+	org := uuid.New()
+	f := &fakeInspectionsDB{
+		inspections: map[uuid.UUID]*fakeInspectionRow{inspectionID: {id: inspectionID, userID: owner}},
+		orgs:        map[uuid.UUID]uuid.UUID{owner: org, teammate: org},
+	}
+	later := time.Now().Add(time.Hour)
+	audit := &fakeTimelineAudit{events: []domain.AuditEvent{
+		{
+			EntityType: domain.AuditEntityInspection,
+			Action:     domain.AuditActionStatusChanged,
+			OldValues:  map[string]string{"status": "review"},
+			NewValues:  map[string]string{"status": "completed"},
+			CreatedAt:  later.Add(time.Hour),
+		},
+		{
+			EntityType: domain.AuditEntityViolation,
+			Action:     domain.AuditActionStatusChanged,
+			OldValues:  map[string]string{"status": "pending"},
+			NewValues:  map[string]string{"status": "confirmed"},
+			CreatedAt:  later,
+		},
+	}}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	svc := NewInspectionService(repository.New(fakedb.Open(&fakeTimelineDB{f})), nil, nil, audit, nil, nil, logger)
+
+	entries, err := svc.Timeline(context.Background(), inspectionID, teammate)
+	if err != nil {
+		t.Fatalf("Timeline: %v", err)
+	}
+
+	if audit.listedUser == nil || *audit.listedUser != owner {
+		t.Errorf("expected the owner's audit history listed, got %v", audit.listedUser)
+	}
+	want := []string{"Inspection created", "Violation confirmed", "Inspection status changed from review to completed"}
+	if len(entries) != len(want) {
+		t.Fatalf("expected %d entries, got %+v", len(want), entries)
+	}
+	for i, summary := range want {
+		if entries[i].Summary != summary {
+			t.Errorf("entry %d: expected %q, got %q", i, summary, entries[i].Summary)
+		}
+	}
+}
+
+func TestInspectionTimeline_OutsiderNotFound(t *testing.T) {
+	owner, inspectionID := uuid.New(), uuid.New()
+	f := &fakeInspectionsDB{inspections: map[uuid.UUID]*fakeInspectionRow{inspectionID: {id: inspectionID, userID: owner}}}
+	audit := &fakeTimelineAudit{}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	svc := NewInspectionService(repository.New(fakedb.Open(&fakeTimelineDB{f})), nil, nil, audit, nil, nil, logger)
+
+	_, err := svc.Timeline(context.Background(), inspectionID, uuid.New())
+
+	if domain.ErrorCode(err) != domain.ENOTFOUND {
+		t.Errorf("expected ENOTFOUND, got %v", err)
+	}
+	if audit.listedUser != nil {
+		t.Error("expected the audit history not to be read for an outsider")
+	}
+}
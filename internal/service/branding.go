@@ -0,0 +1,134 @@
+// Package service contains the business logic layer.
+//
+// This file implements report branding: the business logo inspectors upload
+// to appear on their reports.
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/DukeRupert/lukaut/internal/storage"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// Interface Definition
+// =============================================================================
+
+// BrandingService manages the business logo shown on a user's reports.
+type BrandingService interface {
+	// UploadLogo stores an image as the user's business logo, replacing any
+	// previous upload.
+	// Returns domain.EINVALID if the file is not a JPEG or PNG image.
+	// Returns domain.ETOOLARGE if it exceeds domain.MaxBusinessLogoSize.
+	UploadLogo(ctx context.Context, userID uuid.UUID, file multipart.File, header *multipart.FileHeader) error
+
+	// LogoURL returns a presigned/public URL for the user's uploaded logo.
+	// Returns domain.ENOTFOUND if the user has not uploaded one.
+	LogoURL(ctx context.Context, user *domain.User) (string, error)
+}
+
+// =============================================================================
+// Implementation
+// =============================================================================
+
+type brandingService struct {
+	queries *repository.Queries
+	storage storage.Storage
+	logger  *slog.Logger
+}
+
+// NewBrandingService creates a new BrandingService.
+func NewBrandingService(queries *repository.Queries, storage storage.Storage, logger *slog.Logger) BrandingService {
+	return &brandingService{
+		queries: queries,
+		storage: storage,
+		logger:  logger,
+	}
+}
+
+// UploadLogo validates the image, stores it under a new key and points the
+// user at it. The previous logo is deleted once the user no longer refers to it.
+func (s *brandingService) UploadLogo(ctx context.Context, userID uuid.UUID, file multipart.File, header *multipart.FileHeader) error {
+	const op = "branding.upload_logo"
+
+	if header.Size > domain.MaxBusinessLogoSize {
+		return domain.Errorf(domain.ETOOLARGE, op, "Logo must be %dMB or smaller.", domain.MaxBusinessLogoSize/(1024*1024))
+	}
+	if header.Size == 0 {
+		return domain.Invalid(op, "Logo file is empty.")
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, domain.MaxBusinessLogoSize+1))
+	if err != nil {
+		return domain.Internal(err, op, "failed to read logo")
+	}
+	if len(data) > domain.MaxBusinessLogoSize {
+		return domain.Errorf(domain.ETOOLARGE, op, "Logo must be %dMB or smaller.", domain.MaxBusinessLogoSize/(1024*1024))
+	}
+
+	// Sniff the type rather than trusting the client; SVG is refused because
+	// it can carry scripts
+	contentType := http.DetectContentType(data)
+	if !domain.IsValidImageContentType(contentType) {
+		return domain.Invalid(op, "Logo must be a JPEG or PNG image.")
+	}
+	ext := ".jpg"
+	if contentType == "image/png" {
+		ext = ".png"
+	}
+
+	user, err := s.queries.GetUserByID(ctx, userID)
+	if err != nil {
+		return domain.Internal(err, op, "failed to fetch user")
+	}
+
+	key := fmt.Sprintf("users/%s/logos/%s%s", userID, uuid.New(), ext)
+	if err := s.storage.Put(ctx, key, bytes.NewReader(data), storage.PutOptions{
+		ContentType: contentType,
+		MaxSize:     domain.MaxBusinessLogoSize,
+	}); err != nil {
+		return domain.Internal(err, op, "failed to store logo")
+	}
+
+	if err := s.queries.UpdateUserBusinessLogo(ctx, repository.UpdateUserBusinessLogoParams{
+		ID:              userID,
+		BusinessLogoKey: domain.ToNullString(key),
+	}); err != nil {
+		_ = s.storage.Delete(ctx, key)
+		return domain.Internal(err, op, "failed to save logo")
+	}
+
+	if user.BusinessLogoKey.Valid {
+		if err := s.storage.Delete(ctx, user.BusinessLogoKey.String); err != nil {
+			s.logger.WarnContext(ctx, "failed to delete previous logo", "error", err, "key", user.BusinessLogoKey.String)
+		}
+	}
+
+	s.logger.InfoContext(ctx, "business logo uploaded", "user_id", userID, "size_bytes", len(data))
+	return nil
+}
+
+// LogoURL returns a URL for the user's uploaded logo.
+func (s *brandingService) LogoURL(ctx context.Context, user *domain.User) (string, error) {
+	const op = "branding.logo_url"
+
+	if !user.HasBusinessLogo() {
+		return "", domain.NotFound(op, "logo", user.ID.String())
+	}
+
+	url, err := s.storage.URL(ctx, user.BusinessLogoKey, time.Hour)
+	if err != nil {
+		return "", domain.Internal(err, op, "failed to generate logo URL")
+	}
+	return url, nil
+}
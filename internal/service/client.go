@@ -113,7 +113,7 @@ func (s *clientService) Create(ctx context.Context, params domain.CreateClientPa
 	// Convert to domain type
 	client := s.rowToClient(row)
 
-	s.logger.Info("client created",
+	s.logger.InfoContext(ctx, "client created",
 		"client_id", client.ID,
 		"user_id", params.UserID,
 		"name", params.Name,
@@ -260,7 +260,7 @@ func (s *clientService) Update(ctx context.Context, params domain.UpdateClientPa
 		return domain.Internal(err, op, "failed to update client")
 	}
 
-	s.logger.Info("client updated",
+	s.logger.InfoContext(ctx, "client updated",
 		"client_id", params.ID,
 		"user_id", params.UserID,
 	)
@@ -318,7 +318,7 @@ func (s *clientService) Delete(ctx context.Context, id, userID uuid.UUID) error
 		return domain.Internal(err, op, "failed to delete client")
 	}
 
-	s.logger.Info("client deleted",
+	s.logger.InfoContext(ctx, "client deleted",
 		"client_id", id,
 		"user_id", userID,
 	)
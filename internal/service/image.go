@@ -6,18 +6,20 @@ package service
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"mime/multipart"
-	"net/http"
 	"path/filepath"
 	"time"
 
 	"github.com/DukeRupert/lukaut/internal/domain"
 	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/DukeRupert/lukaut/internal/scan"
 	"github.com/DukeRupert/lukaut/internal/storage"
 	"github.com/google/uuid"
 )
@@ -29,12 +31,19 @@ import (
 // ImageService defines the interface for image-related operations.
 type ImageService interface {
 	// Upload uploads an image file to storage and creates a database record.
-	// This includes generating a thumbnail and storing both in the storage service.
+	// This includes generating the configured thumbnail variants and storing them
+	// alongside the original in the storage service.
 	// Returns domain.EINVALID for validation errors.
 	// Returns domain.ENOTFOUND if inspection doesn't exist or doesn't belong to user.
 	// Returns domain.EFORBIDDEN if inspection status doesn't allow uploads.
 	Upload(ctx context.Context, file multipart.File, header *multipart.FileHeader, inspectionID, userID uuid.UUID) (*domain.Image, error)
 
+	// CheckUploadCapacity checks that count more images fit in the inspection,
+	// so a batch upload can be refused before any file is stored.
+	// Returns domain.ENOTFOUND if inspection doesn't exist or doesn't belong to user.
+	// Returns domain.EINVALID if the images would exceed domain.MaxImagesPerInspection.
+	CheckUploadCapacity(ctx context.Context, inspectionID, userID uuid.UUID, count int) error
+
 	// Delete removes an image from storage and database.
 	// Returns domain.ENOTFOUND if image doesn't exist or doesn't belong to user.
 	Delete(ctx context.Context, imageID, userID uuid.UUID) error
@@ -43,15 +52,57 @@ type ImageService interface {
 	// Returns domain.ENOTFOUND if image doesn't exist or doesn't belong to user.
 	GetByID(ctx context.Context, imageID, userID uuid.UUID) (*domain.Image, error)
 
-	// ListByInspection retrieves all images for an inspection.
+	// ListByInspection retrieves all images for an inspection in display order.
 	// Returns domain.ENOTFOUND if inspection doesn't exist or doesn't belong to user.
 	ListByInspection(ctx context.Context, inspectionID, userID uuid.UUID) ([]domain.Image, error)
 
-	// GetThumbnailURL returns a presigned/public URL for the image thumbnail.
-	GetThumbnailURL(ctx context.Context, imageID, userID uuid.UUID) (string, error)
+	// Reorder sets the display order of an inspection's images to the order
+	// of imageIDs, which must list each of its images exactly once.
+	// Returns domain.ENOTFOUND if inspection doesn't exist or doesn't belong to user.
+	// Returns domain.EINVALID if imageIDs is not exactly the inspection's images.
+	Reorder(ctx context.Context, inspectionID, userID uuid.UUID, imageIDs []uuid.UUID) error
+
+	// GetThumbnailURL returns a presigned/public URL for the image thumbnail
+	// variant closest to size (in pixels). A size of zero selects the default.
+	GetThumbnailURL(ctx context.Context, imageID, userID uuid.UUID, size int) (string, error)
 
 	// GetOriginalURL returns a presigned/public URL for the original image.
 	GetOriginalURL(ctx context.Context, imageID, userID uuid.UUID) (string, error)
+
+	// ThumbnailObject resolves the thumbnail variant closest to size (in
+	// pixels) and its entity tag without reading it, so a conditional request
+	// can be answered before the object is opened.
+	// Returns domain.ENOTFOUND if image doesn't exist or doesn't belong to user.
+	ThumbnailObject(ctx context.Context, imageID, userID uuid.UUID, size int) (*domain.ImageObject, error)
+
+	// OriginalObject resolves the original image and its entity tag without
+	// reading it.
+	// Returns domain.ENOTFOUND if image doesn't exist or doesn't belong to user.
+	OriginalObject(ctx context.Context, imageID, userID uuid.UUID) (*domain.ImageObject, error)
+
+	// RegionThumbnail returns a JPEG thumbnail of the part of the image
+	// inside box, cut from the original and fit within the thumbnail variant
+	// closest to size (in pixels). A size of zero selects the default.
+	// Returns domain.ENOTFOUND if image doesn't exist or doesn't belong to user.
+	RegionThumbnail(ctx context.Context, imageID, userID uuid.UUID, box domain.AnnotationBox, size int) ([]byte, error)
+
+	// OpenObject opens an object resolved by ThumbnailObject or
+	// OriginalObject. The caller must close the returned reader.
+	// Returns domain.ENOTFOUND if the object is missing from storage.
+	OpenObject(ctx context.Context, obj *domain.ImageObject) (io.ReadCloser, storage.ObjectInfo, error)
+
+	// ReanalyzeImage resets a failed or analyzed image to pending and enqueues
+	// a job to analyze just that image. Violations found again are updated
+	// rather than duplicated.
+	// Returns domain.ENOTFOUND if image doesn't exist or doesn't belong to user.
+	// Returns domain.EINVALID if the image is still waiting for or undergoing analysis,
+	// or the inspection is no longer in draft or review.
+	ReanalyzeImage(ctx context.Context, imageID, userID uuid.UUID) (*domain.Image, error)
+
+	// ListAnnotations returns the boxes drawn on the image for all of its
+	// violations, oldest first.
+	// Returns domain.ENOTFOUND if image doesn't exist or doesn't belong to user.
+	ListAnnotations(ctx context.Context, imageID, userID uuid.UUID) ([]domain.ImageAnnotation, error)
 }
 
 // =============================================================================
@@ -61,22 +112,40 @@ type ImageService interface {
 // imageService implements the ImageService interface.
 type imageService struct {
 	queries            *repository.Queries
+	access             orgAccess
 	storage            storage.Storage
 	thumbnailProcessor ThumbnailProcessor
+	converter          ImageConverter
+	scanner            scan.Scanner
+	jobEnqueuer        JobEnqueuer
 	logger             *slog.Logger
 }
 
 // NewImageService creates a new ImageService.
+// Members of an organization can work on each other's images. HEIC/HEIF
+// uploads are converted to JPEG by converter; if nil, they are refused.
+// Uploads are checked by scanner before they are converted or stored; if
+// nil, scan.Noop accepts every file.
 func NewImageService(
 	queries *repository.Queries,
 	storage storage.Storage,
 	thumbnailProcessor ThumbnailProcessor,
+	converter ImageConverter,
+	scanner scan.Scanner,
+	jobEnqueuer JobEnqueuer,
 	logger *slog.Logger,
 ) ImageService {
+	if scanner == nil {
+		scanner = scan.Noop{}
+	}
 	return &imageService{
 		queries:            queries,
+		access:             orgAccess{queries: queries},
 		storage:            storage,
 		thumbnailProcessor: thumbnailProcessor,
+		converter:          converter,
+		scanner:            scanner,
+		jobEnqueuer:        jobEnqueuer,
 		logger:             logger,
 	}
 }
@@ -89,10 +158,14 @@ func NewImageService(
 func (s *imageService) Upload(ctx context.Context, file multipart.File, header *multipart.FileHeader, inspectionID, userID uuid.UUID) (*domain.Image, error) {
 	const op = "image.upload"
 
-	// Verify inspection exists and user owns it
+	// Verify inspection exists and user can access it
+	ownerID, err := s.access.inspectionOwner(ctx, inspectionID, userID)
+	if err != nil {
+		return nil, err
+	}
 	inspection, err := s.queries.GetInspectionByIDAndUserID(ctx, repository.GetInspectionByIDAndUserIDParams{
 		ID:     inspectionID,
-		UserID: userID,
+		UserID: ownerID,
 	})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -118,11 +191,15 @@ func (s *imageService) Upload(ctx context.Context, file multipart.File, header *
 	if err != nil && err != io.EOF {
 		return nil, domain.Internal(err, op, "failed to read file header")
 	}
-	contentType := http.DetectContentType(headerBytes[:n])
+	contentType := DetectImageContentType(headerBytes[:n])
 
-	// Validate content type
-	if !domain.IsValidImageContentType(contentType) {
-		return nil, domain.Invalid(op, fmt.Sprintf("Unsupported image type: %s. Only JPEG and PNG are supported.", contentType))
+	// Validate content type. HEIC/HEIF is accepted when it can be converted.
+	convert := domain.IsConvertibleImageContentType(contentType)
+	if convert && s.converter == nil {
+		return nil, domain.Invalid(op, "HEIC photos can't be converted on this server. Please upload a JPEG or PNG.")
+	}
+	if !convert && !domain.IsValidImageContentType(contentType) {
+		return nil, domain.Invalid(op, fmt.Sprintf("Unsupported image type: %s. Only JPEG, PNG, and HEIC are supported.", contentType))
 	}
 
 	// Reset file pointer to beginning after reading header
@@ -138,21 +215,51 @@ func (s *imageService) Upload(ctx context.Context, file multipart.File, header *
 		return nil, domain.Internal(err, op, "failed to read file data")
 	}
 
-	// Generate thumbnail
-	thumbnailBytes, width, height, err := s.thumbnailProcessor.GenerateThumbnail(
-		bytes.NewReader(fileData),
-		domain.ThumbnailMaxWidth,
-		domain.ThumbnailMaxHeight,
-	)
+	// Scan the upload before anything else decodes or stores it. A file
+	// that can't be scanned is refused rather than let through.
+	if err := s.scanner.Scan(ctx, bytes.NewReader(fileData)); err != nil {
+		var threat *scan.ThreatError
+		if errors.As(err, &threat) {
+			s.logger.WarnContext(ctx, "upload flagged by malware scanner",
+				"signature", threat.Signature,
+				"filename", header.Filename,
+				"inspection_id", inspectionID,
+				"user_id", userID,
+			)
+			return nil, domain.Invalid(op, "This file was flagged by the malware scanner and was not uploaded.")
+		}
+		return nil, domain.Internal(err, op, "failed to scan file")
+	}
+
+	// Convert HEIC/HEIF to JPEG. The stored type, extension, and size are the
+	// JPEG's; the original filename is kept for display.
+	ext := filepath.Ext(header.Filename)
+	sizeBytes := header.Size
+	if convert {
+		fileData, err = s.convertToJPEG(ctx, fileData)
+		if err != nil {
+			return nil, err
+		}
+		contentType = "image/jpeg"
+		ext = ".jpg"
+		sizeBytes = int64(len(fileData))
+	}
+
+	// Hash the stored bytes so entity tags never need the object read back
+	sum := sha256.Sum256(fileData)
+	contentHash := hex.EncodeToString(sum[:])
+
+	// Generate thumbnail variants
+	variants, width, height, err := s.thumbnailProcessor.GenerateThumbnails(ctx, bytes.NewReader(fileData))
 	if err != nil {
 		return nil, domain.Internal(err, op, "failed to generate thumbnail")
 	}
 
 	// Generate storage keys
-	ext := filepath.Ext(header.Filename)
 	imageID := uuid.New()
 	storageKey := fmt.Sprintf("inspections/%s/images/%s%s", inspectionID, imageID, ext)
-	thumbnailKey := fmt.Sprintf("inspections/%s/thumbnails/%s.jpg", inspectionID, imageID)
+	thumbnailBase := fmt.Sprintf("inspections/%s/thumbnails/%s", inspectionID, imageID)
+	thumbnailKey := domain.ThumbnailVariantKey(thumbnailBase, s.thumbnailProcessor.Config().DefaultSize(), domain.ThumbnailFormatJPEG)
 
 	// Upload original to storage
 	if err := s.storage.Put(ctx, storageKey, bytes.NewReader(fileData), storage.PutOptions{
@@ -164,16 +271,30 @@ func (s *imageService) Upload(ctx context.Context, file multipart.File, header *
 		return nil, domain.Internal(err, op, "failed to upload original image")
 	}
 
-	// Upload thumbnail to storage
-	if err := s.storage.Put(ctx, thumbnailKey, bytes.NewReader(thumbnailBytes), storage.PutOptions{
-		ContentType: "image/jpeg",
-		MaxSize:     0, // No limit for thumbnails
-		Overwrite:   false,
-		Public:      false,
-	}); err != nil {
-		// Clean up original image on thumbnail upload failure
-		_ = s.storage.Delete(ctx, storageKey)
-		return nil, domain.Internal(err, op, "failed to upload thumbnail")
+	// Upload every thumbnail variant to storage. Cleanup outlives the request
+	// context so a cancelled upload still removes what it wrote.
+	uploadedKeys := []string{storageKey}
+	cleanup := func() {
+		cleanupCtx := context.WithoutCancel(ctx)
+		for _, key := range uploadedKeys {
+			if err := s.storage.Delete(cleanupCtx, key); err != nil {
+				s.logger.ErrorContext(ctx, "failed to clean up uploaded image", "error", err, "key", key)
+			}
+		}
+	}
+	for _, variant := range variants {
+		key := domain.ThumbnailVariantKey(thumbnailBase, variant.Size, variant.Format)
+		if err := s.storage.Put(ctx, key, bytes.NewReader(variant.Data), storage.PutOptions{
+			ContentType: variant.Format.ContentType(),
+			MaxSize:     0, // No limit for thumbnails
+			Overwrite:   false,
+			Public:      false,
+		}); err != nil {
+			// Clean up original and uploaded variants on thumbnail upload failure
+			cleanup()
+			return nil, domain.Internal(err, op, "failed to upload thumbnail")
+		}
+		uploadedKeys = append(uploadedKeys, key)
 	}
 
 	// Create database record
@@ -189,7 +310,7 @@ func (s *imageService) Upload(ctx context.Context, file multipart.File, header *
 			Valid:  true,
 		},
 		ContentType: contentType,
-		SizeBytes:   int32(header.Size),
+		SizeBytes:   int32(sizeBytes),
 		Width: sql.NullInt32{
 			Int32: int32(width),
 			Valid: true,
@@ -202,11 +323,14 @@ func (s *imageService) Upload(ctx context.Context, file multipart.File, header *
 			String: string(domain.ImageAnalysisStatusPending),
 			Valid:  true,
 		},
+		ContentHash: sql.NullString{
+			String: contentHash,
+			Valid:  true,
+		},
 	})
 	if err != nil {
 		// Clean up storage on database error
-		_ = s.storage.Delete(ctx, storageKey)
-		_ = s.storage.Delete(ctx, thumbnailKey)
+		cleanup()
 		return nil, domain.Internal(err, op, "failed to create image record")
 	}
 
@@ -214,6 +338,78 @@ func (s *imageService) Upload(ctx context.Context, file multipart.File, header *
 	return s.toDomain(dbImage), nil
 }
 
+// convertToJPEG converts a HEIC/HEIF upload to JPEG. Photos too large to
+// decode safely and photos the converter rejects are reported as invalid so
+// the user sees which file failed.
+func (s *imageService) convertToJPEG(ctx context.Context, data []byte) ([]byte, error) {
+	const op = "image.convert"
+
+	width, height, ok := heifDimensions(data)
+	if !ok {
+		return nil, domain.Invalid(op, "This HEIC photo could not be read.")
+	}
+	if width*height > domain.MaxConvertedImagePixels {
+		return nil, domain.Invalid(op, fmt.Sprintf("This HEIC photo is %dx%d, larger than the %d megapixels that can be converted.",
+			width, height, domain.MaxConvertedImagePixels/1_000_000))
+	}
+
+	jpegData, err := s.converter.ConvertToJPEG(ctx, data)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, domain.Internal(err, op, "image conversion cancelled")
+		}
+		if domain.ErrorCode(err) == domain.ETOOLARGE {
+			return nil, err
+		}
+		s.logger.WarnContext(ctx, "failed to convert HEIC image", "error", err)
+		return nil, domain.Invalid(op, "This HEIC photo could not be converted to JPEG.")
+	}
+	if err := domain.ValidateImageSize(int64(len(jpegData))); err != nil {
+		return nil, err
+	}
+	return jpegData, nil
+}
+
+// =============================================================================
+// CheckUploadCapacity
+// =============================================================================
+
+// CheckUploadCapacity checks that count more images fit in the inspection.
+func (s *imageService) CheckUploadCapacity(ctx context.Context, inspectionID, userID uuid.UUID, count int) error {
+	const op = "image.check_upload_capacity"
+
+	// Verify inspection exists and user can access it
+	ownerID, err := s.access.inspectionOwner(ctx, inspectionID, userID)
+	if err != nil {
+		return err
+	}
+	_, err = s.queries.GetInspectionByIDAndUserID(ctx, repository.GetInspectionByIDAndUserIDParams{
+		ID:     inspectionID,
+		UserID: ownerID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.NotFound(op, "inspection", inspectionID.String())
+		}
+		return domain.Internal(err, op, "failed to fetch inspection")
+	}
+
+	existing, err := s.queries.CountImagesByInspectionID(ctx, inspectionID)
+	if err != nil {
+		return domain.Internal(err, op, "failed to count images")
+	}
+
+	remaining := domain.MaxImagesPerInspection - int(existing)
+	if count <= remaining {
+		return nil
+	}
+	if remaining <= 0 {
+		return domain.Invalid(op, fmt.Sprintf("This inspection already has the maximum of %d photos.", domain.MaxImagesPerInspection))
+	}
+	return domain.Invalid(op, fmt.Sprintf("%d photos were selected, but only %d more can be added to this inspection (maximum %d).",
+		count, remaining, domain.MaxImagesPerInspection))
+}
+
 // =============================================================================
 // Delete
 // =============================================================================
@@ -228,13 +424,15 @@ func (s *imageService) Delete(ctx context.Context, imageID, userID uuid.UUID) er
 		return err
 	}
 
-	// Delete from storage (both original and thumbnail)
+	// Delete from storage (original and every thumbnail variant)
 	// Continue even if storage deletion fails - we still want to remove DB record
 	if err := s.storage.Delete(ctx, image.StorageKey); err != nil {
-		s.logger.Error("failed to delete original image from storage", "error", err, "key", image.StorageKey)
+		s.logger.ErrorContext(ctx, "failed to delete original image from storage", "error", err, "key", image.StorageKey)
 	}
-	if err := s.storage.Delete(ctx, image.ThumbnailKey); err != nil {
-		s.logger.Error("failed to delete thumbnail from storage", "error", err, "key", image.ThumbnailKey)
+	for _, key := range s.thumbnailKeys(image.ThumbnailKey) {
+		if err := s.storage.Delete(ctx, key); err != nil {
+			s.logger.ErrorContext(ctx, "failed to delete thumbnail from storage", "error", err, "key", key)
+		}
 	}
 
 	// Delete from database
@@ -262,9 +460,15 @@ func (s *imageService) GetByID(ctx context.Context, imageID, userID uuid.UUID) (
 		return nil, domain.Internal(err, op, "failed to fetch image")
 	}
 
-	// Check authorization
+	// Check authorization: the user's own image, or one of their organization's
 	if row.UserID != userID {
-		return nil, domain.NotFound(op, "image", imageID.String())
+		ownerID, err := s.access.imageOwner(ctx, imageID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if ownerID != row.UserID {
+			return nil, domain.NotFound(op, "image", imageID.String())
+		}
 	}
 
 	// Convert to domain type (use only image fields)
@@ -281,6 +485,9 @@ func (s *imageService) GetByID(ctx context.Context, imageID, userID uuid.UUID) (
 		AnalysisStatus:      row.AnalysisStatus,
 		AnalysisCompletedAt: row.AnalysisCompletedAt,
 		CreatedAt:           row.CreatedAt,
+		AnalysisError:       row.AnalysisError,
+		DisplayOrder:        row.DisplayOrder,
+		ContentHash:         row.ContentHash,
 	}
 
 	return s.toDomain(dbImage), nil
@@ -290,14 +497,18 @@ func (s *imageService) GetByID(ctx context.Context, imageID, userID uuid.UUID) (
 // ListByInspection
 // =============================================================================
 
-// ListByInspection retrieves all images for an inspection.
+// ListByInspection retrieves all images for an inspection in display order.
 func (s *imageService) ListByInspection(ctx context.Context, inspectionID, userID uuid.UUID) ([]domain.Image, error) {
 	const op = "image.list"
 
-	// Verify inspection exists and user owns it
-	_, err := s.queries.GetInspectionByIDAndUserID(ctx, repository.GetInspectionByIDAndUserIDParams{
+	// Verify inspection exists and user can access it
+	ownerID, err := s.access.inspectionOwner(ctx, inspectionID, userID)
+	if err != nil {
+		return nil, err
+	}
+	_, err = s.queries.GetInspectionByIDAndUserID(ctx, repository.GetInspectionByIDAndUserIDParams{
 		ID:     inspectionID,
-		UserID: userID,
+		UserID: ownerID,
 	})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -321,12 +532,80 @@ func (s *imageService) ListByInspection(ctx context.Context, inspectionID, userI
 	return images, nil
 }
 
+// =============================================================================
+// Reorder
+// =============================================================================
+
+// Reorder sets the display order of an inspection's images.
+//
+// The new order is written in a single statement, so a failed reorder leaves
+// the previous order intact.
+func (s *imageService) Reorder(ctx context.Context, inspectionID, userID uuid.UUID, imageIDs []uuid.UUID) error {
+	const op = "image.reorder"
+
+	// Verify inspection exists and user can access it
+	ownerID, err := s.access.inspectionOwner(ctx, inspectionID, userID)
+	if err != nil {
+		return err
+	}
+	_, err = s.queries.GetInspectionByIDAndUserID(ctx, repository.GetInspectionByIDAndUserIDParams{
+		ID:     inspectionID,
+		UserID: ownerID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.NotFound(op, "inspection", inspectionID.String())
+		}
+		return domain.Internal(err, op, "failed to fetch inspection")
+	}
+
+	dbImages, err := s.queries.ListImagesByInspectionID(ctx, inspectionID)
+	if err != nil {
+		return domain.Internal(err, op, "failed to fetch images")
+	}
+
+	// The new order must name every image of the inspection exactly once
+	if len(imageIDs) != len(dbImages) {
+		return domain.Invalid(op, "The image order must include every image of the inspection.")
+	}
+	remaining := make(map[uuid.UUID]bool, len(dbImages))
+	for _, img := range dbImages {
+		remaining[img.ID] = true
+	}
+	for _, id := range imageIDs {
+		if !remaining[id] {
+			return domain.Invalid(op, "The image order must include every image of the inspection exactly once.")
+		}
+		delete(remaining, id)
+	}
+
+	affected, err := s.queries.UpdateImageDisplayOrder(ctx, repository.UpdateImageDisplayOrderParams{
+		ImageIds:     imageIDs,
+		InspectionID: inspectionID,
+	})
+	if err != nil {
+		return domain.Internal(err, op, "failed to update image order")
+	}
+
+	s.logger.InfoContext(ctx, "images reordered",
+		"inspection_id", inspectionID,
+		"user_id", userID,
+		"image_count", affected,
+	)
+
+	return nil
+}
+
 // =============================================================================
 // GetThumbnailURL
 // =============================================================================
 
 // GetThumbnailURL returns a presigned/public URL for the image thumbnail.
-func (s *imageService) GetThumbnailURL(ctx context.Context, imageID, userID uuid.UUID) (string, error) {
+//
+// The smallest configured size of at least size is chosen, preferring WebP
+// when enabled. Images uploaded before that variant was configured fall back
+// to the stored default thumbnail.
+func (s *imageService) GetThumbnailURL(ctx context.Context, imageID, userID uuid.UUID, size int) (string, error) {
 	const op = "image.thumbnail_url"
 
 	// Get image with authorization
@@ -335,8 +614,10 @@ func (s *imageService) GetThumbnailURL(ctx context.Context, imageID, userID uuid
 		return "", err
 	}
 
+	key := s.thumbnailVariantKey(ctx, image.ThumbnailKey, size)
+
 	// Generate URL with 1 hour expiry
-	url, err := s.storage.URL(ctx, image.ThumbnailKey, 1*time.Hour)
+	url, err := s.storage.URL(ctx, key, 1*time.Hour)
 	if err != nil {
 		return "", domain.Internal(err, op, "failed to generate thumbnail URL")
 	}
@@ -344,6 +625,41 @@ func (s *imageService) GetThumbnailURL(ctx context.Context, imageID, userID uuid
 	return url, nil
 }
 
+// thumbnailVariantKey resolves the storage key of the best thumbnail variant
+// for the requested size, falling back to storedKey when the variant cannot
+// be derived or does not exist.
+func (s *imageService) thumbnailVariantKey(ctx context.Context, storedKey string, size int) string {
+	base, ok := domain.ThumbnailKeyBase(storedKey)
+	if !ok {
+		// Legacy thumbnail without size variants
+		return storedKey
+	}
+
+	cfg := s.thumbnailProcessor.Config()
+	formats := cfg.Formats()
+	key := domain.ThumbnailVariantKey(base, cfg.SizeFor(size), formats[len(formats)-1])
+	if key == storedKey {
+		return key
+	}
+
+	// The variant may predate the current thumbnail configuration
+	exists, err := s.storage.Exists(ctx, key)
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to check thumbnail variant", "error", err, "key", key)
+		return storedKey
+	}
+	if !exists {
+		return storedKey
+	}
+	return key
+}
+
+// thumbnailKeys returns every thumbnail key that may exist for an image:
+// the stored key plus each variant of the current configuration.
+func (s *imageService) thumbnailKeys(storedKey string) []string {
+	return domain.ThumbnailKeys(storedKey, s.thumbnailProcessor.Config().Sizes)
+}
+
 // =============================================================================
 // GetOriginalURL
 // =============================================================================
@@ -367,6 +683,168 @@ func (s *imageService) GetOriginalURL(ctx context.Context, imageID, userID uuid.
 	return url, nil
 }
 
+// =============================================================================
+// Image Objects
+// =============================================================================
+
+// ThumbnailObject resolves the thumbnail variant for size and its entity tag.
+func (s *imageService) ThumbnailObject(ctx context.Context, imageID, userID uuid.UUID, size int) (*domain.ImageObject, error) {
+	image, err := s.GetByID(ctx, imageID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	key := s.thumbnailVariantKey(ctx, image.ThumbnailKey, size)
+	return &domain.ImageObject{Key: key, ETag: image.ETag(key)}, nil
+}
+
+// OriginalObject resolves the original image and its entity tag.
+func (s *imageService) OriginalObject(ctx context.Context, imageID, userID uuid.UUID) (*domain.ImageObject, error) {
+	image, err := s.GetByID(ctx, imageID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.ImageObject{Key: image.StorageKey, ETag: image.ETag(image.StorageKey)}, nil
+}
+
+// RegionThumbnail crops the original image to box. The original is used so
+// a small region still has enough pixels to fill the thumbnail.
+func (s *imageService) RegionThumbnail(ctx context.Context, imageID, userID uuid.UUID, box domain.AnnotationBox, size int) ([]byte, error) {
+	const op = "image.region_thumbnail"
+
+	obj, err := s.OriginalObject(ctx, imageID, userID)
+	if err != nil {
+		return nil, err
+	}
+	body, _, err := s.OpenObject(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = body.Close() }()
+
+	data, err := CropThumbnail(body, box, s.thumbnailProcessor.Config().SizeFor(size))
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to crop image")
+	}
+	return data, nil
+}
+
+// OpenObject opens a resolved image object from storage.
+func (s *imageService) OpenObject(ctx context.Context, obj *domain.ImageObject) (io.ReadCloser, storage.ObjectInfo, error) {
+	const op = "image.open"
+
+	body, info, err := s.storage.Get(ctx, obj.Key)
+	if err != nil {
+		if storage.IsNotFound(err) {
+			return nil, storage.ObjectInfo{}, domain.NotFound(op, "image", obj.Key)
+		}
+		return nil, storage.ObjectInfo{}, domain.Internal(err, op, "failed to open image")
+	}
+	return body, info, nil
+}
+
+// =============================================================================
+// ReanalyzeImage
+// =============================================================================
+
+// ReanalyzeImage resets a failed or analyzed image to pending and enqueues its
+// analysis. Analyzed images are reanalyzed with force so the job does not skip them.
+func (s *imageService) ReanalyzeImage(ctx context.Context, imageID, userID uuid.UUID) (*domain.Image, error) {
+	const op = "image.reanalyze"
+
+	if s.jobEnqueuer == nil {
+		return nil, domain.Internal(nil, op, "job enqueuer not configured")
+	}
+
+	// Get image with authorization
+	image, err := s.GetByID(ctx, imageID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !image.HasFailed() && !image.IsAnalyzed() {
+		return nil, domain.Invalid(op, "This image is already waiting for analysis")
+	}
+	force := image.IsAnalyzed()
+
+	// The analysis runs on behalf of the inspection's owner
+	ownerID, err := s.access.imageOwner(ctx, imageID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Findings of a completed inspection are final
+	inspection, err := s.queries.GetInspectionByIDAndUserID(ctx, repository.GetInspectionByIDAndUserIDParams{
+		ID:     image.InspectionID,
+		UserID: ownerID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.NotFound(op, "inspection", image.InspectionID.String())
+		}
+		return nil, domain.Internal(err, op, "failed to fetch inspection")
+	}
+	status := domain.InspectionStatus(inspection.Status)
+	if status != domain.InspectionStatusDraft && status != domain.InspectionStatusReview {
+		return nil, domain.Invalid(op, "Images can only be reanalyzed while the inspection is in draft or review")
+	}
+
+	// Reset to pending so the analysis job picks the image up
+	if err := s.queries.UpdateImageAnalysisStatusWithAuth(ctx, repository.UpdateImageAnalysisStatusWithAuthParams{
+		ID:                  imageID,
+		UserID:              ownerID,
+		AnalysisStatus:      sql.NullString{String: domain.ImageAnalysisStatusPending.String(), Valid: true},
+		AnalysisCompletedAt: sql.NullTime{},
+	}); err != nil {
+		return nil, domain.Internal(err, op, "failed to reset image analysis status")
+	}
+	image.AnalysisStatus = domain.ImageAnalysisStatusPending
+
+	if _, err := s.jobEnqueuer.EnqueueAnalyzeImages(ctx, image.InspectionID, ownerID, []uuid.UUID{imageID}, force); err != nil {
+		if domain.ErrorCode(err) == domain.ERATELIMIT {
+			return nil, err
+		}
+		return nil, domain.Internal(err, op, "failed to enqueue image analysis job")
+	}
+
+	s.logger.InfoContext(ctx, "Image reanalysis enqueued",
+		"image_id", imageID,
+		"inspection_id", image.InspectionID,
+		"user_id", userID,
+		"force", force,
+	)
+
+	return image, nil
+}
+
+// =============================================================================
+// ListAnnotations
+// =============================================================================
+
+// ListAnnotations returns the boxes drawn on an image, oldest first.
+func (s *imageService) ListAnnotations(ctx context.Context, imageID, userID uuid.UUID) ([]domain.ImageAnnotation, error) {
+	const op = "image.list_annotations"
+
+	if _, err := s.GetByID(ctx, imageID, userID); err != nil {
+		return nil, err
+	}
+
+	ownerID, err := s.access.imageOwner(ctx, imageID, userID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.queries.ListImageAnnotationsByImageIDAndUserID(ctx, repository.ListImageAnnotationsByImageIDAndUserIDParams{
+		ImageID: imageID,
+		UserID:  ownerID,
+	})
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to list annotations")
+	}
+
+	return annotationRowsToDomain(rows), nil
+}
+
 // =============================================================================
 // Helper Functions
 // =============================================================================
@@ -408,6 +886,9 @@ func (s *imageService) toDomain(dbImage repository.Image) *domain.Image {
 		Width:            getInt32(dbImage.Width),
 		Height:           getInt32(dbImage.Height),
 		AnalysisStatus:   domain.ImageAnalysisStatus(getString(dbImage.AnalysisStatus)),
+		AnalysisError:    getString(dbImage.AnalysisError),
+		DisplayOrder:     dbImage.DisplayOrder,
+		ContentHash:      getString(dbImage.ContentHash),
 		CreatedAt:        getTime(dbImage.CreatedAt),
 		UpdatedAt:        time.Time{}, // Not stored in DB (no updated_at column)
 		// ThumbnailURL and OriginalURL are populated on demand by the handler
@@ -0,0 +1,188 @@
+// Package service contains business logic for the Lukaut application.
+//
+// This file implements conversion of HEIC/HEIF photos to JPEG on upload.
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+)
+
+// =============================================================================
+// Interface Definition
+// =============================================================================
+
+// ImageConverter converts photos in formats the thumbnail processor can't
+// decode to JPEG before they are stored.
+type ImageConverter interface {
+	// ConvertToJPEG converts HEIC/HEIF data to JPEG, keeping its EXIF
+	// metadata where the converter supports it.
+	ConvertToJPEG(ctx context.Context, data []byte) ([]byte, error)
+}
+
+// =============================================================================
+// heif-convert Implementation
+// =============================================================================
+
+// DefaultHEIFConvertTimeout bounds a single conversion.
+const DefaultHEIFConvertTimeout = 30 * time.Second
+
+// HEIFConverter converts HEIC/HEIF to JPEG using libheif's heif-convert,
+// which copies the EXIF block into the JPEG.
+// Requires heif-convert to be installed: apt-get install libheif-examples
+type HEIFConverter struct {
+	// Command is the heif-convert command to execute. Defaults to "heif-convert".
+	Command string
+
+	// Timeout bounds a single conversion. Defaults to DefaultHEIFConvertTimeout.
+	Timeout time.Duration
+
+	// MaxSize is the largest JPEG accepted, in bytes: the configured upload
+	// size limit, so converted photos follow the same limit as direct
+	// uploads. Defaults to domain.MaxImageSize.
+	MaxSize int64
+}
+
+// NewHEIFConverter creates a new heif-convert converter that refuses JPEGs
+// larger than maxSize, the configured upload size limit.
+func NewHEIFConverter(command string, maxSize int64) *HEIFConverter {
+	if command == "" {
+		command = "heif-convert"
+	}
+	if maxSize <= 0 {
+		maxSize = domain.MaxImageSize
+	}
+	return &HEIFConverter{
+		Command: command,
+		Timeout: DefaultHEIFConvertTimeout,
+		MaxSize: maxSize,
+	}
+}
+
+// ConvertToJPEG converts HEIC/HEIF data to JPEG using heif-convert.
+// Output larger than MaxSize is refused with domain.ETOOLARGE.
+func (c *HEIFConverter) ConvertToJPEG(ctx context.Context, data []byte) ([]byte, error) {
+	const op = "image.convert"
+
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = DefaultHEIFConvertTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// Create temp directory for input/output files
+	tmpDir, err := os.MkdirTemp("", "image-heif-*")
+	if err != nil {
+		return nil, fmt.Errorf("create temp dir: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	inputPath := filepath.Join(tmpDir, "input.heic")
+	outputPath := filepath.Join(tmpDir, "output.jpg")
+
+	if err := os.WriteFile(inputPath, data, 0600); err != nil {
+		return nil, fmt.Errorf("write input file: %w", err)
+	}
+
+	// Execute heif-convert
+	cmd := exec.CommandContext(ctx, c.Command, "-q", "90", inputPath, outputPath)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("heif-convert failed: %w, stderr: %s", err, stderr.String())
+	}
+
+	info, err := os.Stat(outputPath)
+	if err != nil {
+		return nil, fmt.Errorf("stat output file: %w", err)
+	}
+	maxSize := c.MaxSize
+	if maxSize <= 0 {
+		maxSize = domain.MaxImageSize
+	}
+	if info.Size() > maxSize {
+		limits := domain.UploadLimits{MaxSize: maxSize}
+		return nil, domain.Errorf(domain.ETOOLARGE, op, "This HEIC photo is %.1f MB as a JPEG, over the %s limit.",
+			float64(info.Size())/(1024*1024), limits.MaxSizeLabel())
+	}
+
+	jpegData, err := os.ReadFile(outputPath)
+	if err != nil {
+		return nil, fmt.Errorf("read output file: %w", err)
+	}
+	return jpegData, nil
+}
+
+// IsHEIFConverterAvailable checks if the heif-convert command is installed
+// and accessible.
+func IsHEIFConverterAvailable(command string) bool {
+	_, err := exec.LookPath(command)
+	return err == nil
+}
+
+// =============================================================================
+// HEIF Container Inspection
+// =============================================================================
+
+// heicBrands are the ftyp brands of HEVC-coded HEIF files, which are
+// reported as image/heic rather than image/heif.
+var heicBrands = map[string]bool{
+	"heic": true, "heix": true, "heim": true, "heis": true,
+	"hevc": true, "hevx": true, "hevm": true, "hevs": true,
+}
+
+// DetectImageContentType returns the MIME type of an upload from its first
+// bytes. http.DetectContentType doesn't recognize HEIF, so its ftyp box is
+// checked first.
+func DetectImageContentType(header []byte) string {
+	if len(header) >= 12 && string(header[4:8]) == "ftyp" {
+		brand := string(header[8:12])
+		if heicBrands[brand] {
+			return "image/heic"
+		}
+		if brand == "mif1" || brand == "msf1" {
+			return "image/heif"
+		}
+	}
+	return http.DetectContentType(header)
+}
+
+// heifDimensions returns the largest width and height declared by the
+// image spatial extent ("ispe") properties of a HEIF file, without decoding
+// it. ok is false when no ispe property is found.
+//
+// A grid image declares both the tile size and the full size, so taking the
+// largest covers what a decoder will allocate.
+func heifDimensions(data []byte) (width, height int, ok bool) {
+	// ispe is a full box: size(4) "ispe"(4) version+flags(4) width(4) height(4)
+	for i := 0; ; {
+		j := bytes.Index(data[i:], []byte("ispe"))
+		if j < 0 {
+			break
+		}
+		i += j
+		if i < 4 || i+16 > len(data) {
+			i += 4
+			continue
+		}
+		w := int(binary.BigEndian.Uint32(data[i+8 : i+12]))
+		h := int(binary.BigEndian.Uint32(data[i+12 : i+16]))
+		if w*h > width*height {
+			width, height = w, h
+		}
+		ok = true
+		i += 16
+	}
+	return width, height, ok
+}
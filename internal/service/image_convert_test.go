@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+)
+
+// fakeHEIFConvert writes a stand-in for heif-convert that writes size bytes
+// to its output path ("heif-convert -q 90 input output").
+func fakeHEIFConvert(t *testing.T, size int) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a POSIX shell")
+	}
+	path := filepath.Join(t.TempDir(), "heif-convert")
+	script := fmt.Sprintf("#!/bin/sh\nhead -c %d /dev/zero > \"$4\"\n", size)
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestHEIFConverter_UsesConfiguredLimit(t *testing.T) {
+	command := fakeHEIFConvert(t, 2048)
+
+	jpeg, err := NewHEIFConverter(command, 4096).ConvertToJPEG(context.Background(), []byte("heic"))
+	if err != nil {
+		t.Fatalf("expected a JPEG under the limit to convert, got %v", err)
+	}
+	if len(jpeg) != 2048 {
+		t.Errorf("expected 2048 bytes, got %d", len(jpeg))
+	}
+
+	_, err = NewHEIFConverter(command, 1024).ConvertToJPEG(context.Background(), []byte("heic"))
+	if domain.ErrorCode(err) != domain.ETOOLARGE {
+		t.Fatalf("expected ETOOLARGE over the configured limit, got %v", err)
+	}
+}
+
+func TestNewHEIFConverter_DefaultsToMaxImageSize(t *testing.T) {
+	if c := NewHEIFConverter("", 0); c.MaxSize != domain.MaxImageSize || c.Command != "heif-convert" {
+		t.Errorf("expected defaults, got %+v", c)
+	}
+}
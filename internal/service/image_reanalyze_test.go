@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/DukeRupert/lukaut/internal/testutil/fakedb"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// In-Memory Reanalysis Database
+// =============================================================================
+
+// fakeReanalyzeDB adds one image, and resetting its analysis, to the images
+// database.
+type fakeReanalyzeDB struct {
+	*fakeImagesDB
+	imageID     uuid.UUID
+	imageStatus domain.ImageAnalysisStatus
+}
+
+func (f *fakeReanalyzeDB) Query(q fakedb.Query) (*fakedb.Rows, error) {
+	switch q.Name {
+	case "GetImageByIDWithInspection":
+		rows := &fakedb.Rows{Columns: 16}
+		if q.Arg(0).(string) == f.imageID.String() {
+			values := make([]driver.Value, 16)
+			values[0] = f.imageID.String()
+			values[1] = f.inspectionID.String()
+			values[2] = "images/" + f.imageID.String()
+			values[5] = "image/jpeg"
+			values[6] = int64(1024)
+			values[9] = string(f.imageStatus)
+			values[11] = time.Now()
+			values[13] = int64(1)
+			values[15] = f.ownerID.String()
+			rows.Values = append(rows.Values, values)
+		}
+		return rows, nil
+	case "GetImageOwnerIDForUser":
+		// No organization: only the owner may work on the image
+		if q.Arg(0).(string) != f.imageID.String() || q.Arg(1).(string) != f.ownerID.String() {
+			return &fakedb.Rows{Columns: 1}, nil
+		}
+		return fakedb.Row(f.ownerID.String()), nil
+	}
+	return f.fakeImagesDB.Query(q)
+}
+
+func (f *fakeReanalyzeDB) Exec(q fakedb.Query) (int64, error) {
+	if q.Name == "UpdateImageAnalysisStatusWithAuth" {
+		f.imageStatus = domain.ImageAnalysisStatus(q.Arg(2).(string))
+		return 1, nil
+	}
+	return f.fakeImagesDB.Exec(q)
+}
+
+func newReanalyzeTestService(f *fakeReanalyzeDB) ImageService {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewImageService(repository.New(fakedb.Open(f)), nil, nil, nil, nil, &fakeReportEnqueuer{}, logger)
+}
+
+// =============================================================================
+// Reanalyze Tests
+// =============================================================================
+
+func TestImageReanalyze_OnlyWhileInspectionEditable(t *testing.T) {
+	testCases := []struct {
+		status domain.InspectionStatus
+		want   string
+	}{
+		{domain.InspectionStatusDraft, ""},
+		{domain.InspectionStatusReview, ""},
+		{domain.InspectionStatusAnalyzing, domain.EINVALID},
+		{domain.InspectionStatusCompleted, domain.EINVALID},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.status.String(), func(t *testing.T) {
+			f := &fakeReanalyzeDB{
+				fakeImagesDB: &fakeImagesDB{inspectionID: uuid.New(), ownerID: uuid.New(), status: tc.status},
+				imageID:      uuid.New(),
+				imageStatus:  domain.ImageAnalysisStatusFailed,
+			}
+
+			_, err := newReanalyzeTestService(f).ReanalyzeImage(context.Background(), f.imageID, f.ownerID)
+
+			if domain.ErrorCode(err) != tc.want {
+				t.Fatalf("expected error code %q, got %v", tc.want, err)
+			}
+			want := domain.ImageAnalysisStatusPending
+			if tc.want != "" {
+				want = domain.ImageAnalysisStatusFailed
+			}
+			if f.imageStatus != want {
+				t.Errorf("expected the image %s, got %s", want, f.imageStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/DukeRupert/lukaut/internal/testutil/fakedb"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// In-Memory Images Database
+// =============================================================================
+
+// fakeImagesDB answers the sqlc queries used to upload and reorder an
+// inspection's images, dispatching on the "-- name:" header.
+type fakeImagesDB struct {
+	inspectionID uuid.UUID
+	ownerID      uuid.UUID
+	status       domain.InspectionStatus // Inspection status; empty is draft
+	order        []uuid.UUID             // image IDs in display order
+	createErr    error                   // returned by CreateImage
+	created      *repository.Image
+}
+
+func (f *fakeImagesDB) Query(q fakedb.Query) (*fakedb.Rows, error) {
+	args := q.Args
+
+	switch q.Name {
+	case "GetInspectionOwnerIDForUser":
+		// No organization: only the owner may work on the inspection
+		if args[0].Value.(string) != f.inspectionID.String() || args[1].Value.(string) != f.ownerID.String() {
+			return &fakedb.Rows{Columns: 1}, nil
+		}
+		return fakedb.Row(f.ownerID.String()), nil
+	case "GetInspectionByIDAndUserID":
+		// Mirrors the WHERE clause: only the owner's inspection matches
+		if args[0].Value.(string) != f.inspectionID.String() || args[1].Value.(string) != f.ownerID.String() {
+			return &fakedb.Rows{Columns: 23}, nil
+		}
+		return inspectionRows(&fakeInspectionRow{id: f.inspectionID, userID: f.ownerID, status: f.status}), nil
+	case "ListImagesByInspectionID":
+		rows := &fakedb.Rows{Columns: 15}
+		for i, id := range f.order {
+			values := make([]driver.Value, 15)
+			values[0] = id.String()
+			values[1] = f.inspectionID.String()
+			values[2] = "images/" + id.String()
+			values[5] = "image/jpeg"
+			values[6] = int64(1024)
+			values[11] = time.Now()
+			values[13] = int64(i + 1)
+			rows.Values = append(rows.Values, values)
+		}
+		return rows, nil
+	case "CountImagesByInspectionID":
+		return &fakedb.Rows{Columns: 1, Values: [][]driver.Value{{int64(len(f.order))}}}, nil
+	case "CreateImage":
+		if f.createErr != nil {
+			return nil, f.createErr
+		}
+		// $1 inspection, $2 storage key, $3 thumbnail key, $4 filename,
+		// $5 content type, $6 size, $7 width, $8 height, $9 status,
+		// $10 content hash
+		f.created = &repository.Image{
+			ID:               uuid.New(),
+			InspectionID:     f.inspectionID,
+			StorageKey:       args[1].Value.(string),
+			OriginalFilename: nullStringArg(args[3]),
+			ContentType:      args[4].Value.(string),
+			SizeBytes:        int32(args[5].Value.(int64)),
+			ContentHash:      nullStringArg(args[9]),
+		}
+		values := make([]driver.Value, 15)
+		values[0] = f.created.ID.String()
+		values[1] = f.inspectionID.String()
+		values[2] = f.created.StorageKey
+		values[4] = args[3].Value
+		values[5] = f.created.ContentType
+		values[6] = int64(f.created.SizeBytes)
+		values[13] = int64(len(f.order) + 1)
+		values[14] = args[9].Value
+		return &fakedb.Rows{Columns: 15, Values: [][]driver.Value{values}}, nil
+	}
+	return nil, fmt.Errorf("fakeImagesDB: unexpected query %q", q.Name)
+}
+
+func (f *fakeImagesDB) Exec(q fakedb.Query) (int64, error) {
+	args := q.Args
+
+	if q.Name != "UpdateImageDisplayOrder" {
+		return 0, fmt.Errorf("fakeImagesDB: unexpected exec %q", q.Name)
+	}
+	if args[1].Value.(string) != f.inspectionID.String() {
+		return 0, nil
+	}
+	// The image IDs arrive as a Postgres array literal: {"a","b"}
+	var order []uuid.UUID
+	for _, raw := range strings.Split(strings.Trim(args[0].Value.(string), "{}"), ",") {
+		order = append(order, uuid.MustParse(strings.Trim(raw, `"`)))
+	}
+	f.order = order
+	return int64(len(order)), nil
+}
+
+func newReorderTestService(f *fakeImagesDB) ImageService {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewImageService(repository.New(fakedb.Open(f)), nil, nil, nil, nil, nil, logger)
+}
+
+// =============================================================================
+// Reorder Tests
+// =============================================================================
+
+func TestImageReorder_SavesNewOrder(t *testing.T) {
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+	f := &fakeImagesDB{inspectionID: uuid.New(), ownerID: uuid.New(), order: []uuid.UUID{a, b, c}}
+	svc := newReorderTestService(f)
+
+	if err := svc.Reorder(context.Background(), f.inspectionID, f.ownerID, []uuid.UUID{c, a, b}); err != nil {
+		t.Fatalf("Reorder failed: %v", err)
+	}
+	if len(f.order) != 3 || f.order[0] != c || f.order[1] != a || f.order[2] != b {
+		t.Errorf("expected order [c a b], got %v", f.order)
+	}
+}
+
+func TestImageReorder_RejectsOrderThatIsNotTheInspectionsImages(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name  string
+		order []uuid.UUID
+	}{
+		{"missing image", []uuid.UUID{b}},
+		{"duplicate image", []uuid.UUID{a, a}},
+		{"image from another inspection", []uuid.UUID{a, uuid.New()}},
+		{"extra image", []uuid.UUID{a, b, uuid.New()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &fakeImagesDB{inspectionID: uuid.New(), ownerID: uuid.New(), order: []uuid.UUID{a, b}}
+			svc := newReorderTestService(f)
+
+			err := svc.Reorder(context.Background(), f.inspectionID, f.ownerID, tt.order)
+
+			if domain.ErrorCode(err) != domain.EINVALID {
+				t.Errorf("expected EINVALID, got %v", err)
+			}
+			if f.order[0] != a || f.order[1] != b {
+				t.Errorf("expected the order to be unchanged, got %v", f.order)
+			}
+		})
+	}
+}
+
+func TestImageReorder_OtherUsersInspection(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	f := &fakeImagesDB{inspectionID: uuid.New(), ownerID: uuid.New(), order: []uuid.UUID{a, b}}
+	svc := newReorderTestService(f)
+
+	err := svc.Reorder(context.Background(), f.inspectionID, uuid.New(), []uuid.UUID{b, a})
+
+	if domain.ErrorCode(err) != domain.ENOTFOUND {
+		t.Errorf("expected ENOTFOUND, got %v", err)
+	}
+	if f.order[0] != a {
+		t.Errorf("expected the order to be unchanged, got %v", f.order)
+	}
+}
@@ -0,0 +1,418 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/DukeRupert/lukaut/internal/scan"
+	"github.com/DukeRupert/lukaut/internal/storage"
+	"github.com/DukeRupert/lukaut/internal/testutil/fakedb"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// Upload Test Doubles
+// =============================================================================
+
+// fakeMemStorage keeps objects in memory. Like a network-backed store, it
+// refuses calls made with a cancelled context.
+type fakeMemStorage struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	onPut   func(key string) // called after each Put
+}
+
+func (s *fakeMemStorage) Put(ctx context.Context, key string, data io.Reader, opts storage.PutOptions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	b, err := io.ReadAll(data)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.objects[key] = b
+	s.mu.Unlock()
+	if s.onPut != nil {
+		s.onPut(key)
+	}
+	return nil
+}
+
+func (s *fakeMemStorage) Get(ctx context.Context, key string) (io.ReadCloser, storage.ObjectInfo, error) {
+	return nil, storage.ObjectInfo{}, errors.New("fakeMemStorage: Get not supported")
+}
+
+func (s *fakeMemStorage) Delete(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	delete(s.objects, key)
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *fakeMemStorage) URL(ctx context.Context, key string, expires time.Duration) (string, error) {
+	return "/" + key, nil
+}
+
+func (s *fakeMemStorage) Exists(ctx context.Context, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.objects[key]
+	return ok, nil
+}
+
+// fakeThumbnailProcessor returns a fixed JPEG and WebP variant without
+// decoding the image.
+type fakeThumbnailProcessor struct{}
+
+func (fakeThumbnailProcessor) GenerateThumbnails(context.Context, io.Reader) ([]ThumbnailVariant, int, int, error) {
+	return []ThumbnailVariant{
+		{Size: 200, Format: domain.ThumbnailFormatJPEG, Data: []byte("jpeg thumbnail")},
+		{Size: 200, Format: domain.ThumbnailFormatWebP, Data: []byte("webp thumbnail")},
+	}, 800, 600, nil
+}
+
+func (fakeThumbnailProcessor) Config() ThumbnailConfig {
+	return ThumbnailConfig{Sizes: []int{200}, WebP: true}
+}
+
+// uploadFile is an in-memory multipart.File.
+type uploadFile struct{ *bytes.Reader }
+
+func (uploadFile) Close() error { return nil }
+
+// pngUpload returns a file that sniffs as image/png and its header.
+func pngUpload(name string) (multipart.File, *multipart.FileHeader) {
+	data := append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 64)...)
+	return uploadFile{bytes.NewReader(data)}, &multipart.FileHeader{Filename: name, Size: int64(len(data))}
+}
+
+// heicUpload returns a file that sniffs as image/heic and declares the given
+// dimensions in an ispe property, and its header.
+func heicUpload(name string, width, height uint32) (multipart.File, *multipart.FileHeader) {
+	var data []byte
+	data = binary.BigEndian.AppendUint32(data, 24)
+	data = append(data, "ftypheic\x00\x00\x00\x00mif1heic"...)
+	data = binary.BigEndian.AppendUint32(data, 20)
+	data = append(data, "ispe\x00\x00\x00\x00"...)
+	data = binary.BigEndian.AppendUint32(data, width)
+	data = binary.BigEndian.AppendUint32(data, height)
+	data = append(data, make([]byte, 64)...)
+	return uploadFile{bytes.NewReader(data)}, &multipart.FileHeader{Filename: name, Size: int64(len(data))}
+}
+
+// fakeImageConverter returns fixed JPEG bytes or an error, counting calls.
+type fakeImageConverter struct {
+	jpeg  []byte
+	err   error
+	calls int
+}
+
+func (c *fakeImageConverter) ConvertToJPEG(ctx context.Context, data []byte) ([]byte, error) {
+	c.calls++
+	return c.jpeg, c.err
+}
+
+func newUploadTestService(f *fakeImagesDB, store *fakeMemStorage) ImageService {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewImageService(repository.New(fakedb.Open(f)), store, fakeThumbnailProcessor{}, nil, nil, nil, logger)
+}
+
+// =============================================================================
+// Upload Tests
+// =============================================================================
+
+func TestImageUpload_DatabaseFailureRemovesStoredObjects(t *testing.T) {
+	f := &fakeImagesDB{inspectionID: uuid.New(), ownerID: uuid.New(), createErr: errors.New("insert failed")}
+	store := &fakeMemStorage{objects: map[string][]byte{}}
+	svc := newUploadTestService(f, store)
+
+	file, header := pngUpload("IMG_0001.png")
+	_, err := svc.Upload(context.Background(), file, header, f.inspectionID, f.ownerID)
+
+	if domain.ErrorCode(err) != domain.EINTERNAL {
+		t.Fatalf("expected EINTERNAL, got %v", err)
+	}
+	if len(store.objects) != 0 {
+		t.Errorf("expected no orphaned objects, got %d", len(store.objects))
+	}
+}
+
+func TestImageUpload_CancelledRequestRemovesStoredObjects(t *testing.T) {
+	f := &fakeImagesDB{inspectionID: uuid.New(), ownerID: uuid.New()}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The client goes away once the last thumbnail is stored, so the insert
+	// fails with the cancelled context
+	puts := 0
+	store := &fakeMemStorage{objects: map[string][]byte{}}
+	store.onPut = func(string) {
+		puts++
+		if puts == 3 {
+			cancel()
+		}
+	}
+	svc := newUploadTestService(f, store)
+
+	file, header := pngUpload("IMG_0001.png")
+	if _, err := svc.Upload(ctx, file, header, f.inspectionID, f.ownerID); err == nil {
+		t.Fatal("expected the upload to fail")
+	}
+	if puts != 3 {
+		t.Fatalf("expected the original and two thumbnails to be stored, got %d puts", puts)
+	}
+	if len(store.objects) != 0 {
+		t.Errorf("expected no orphaned objects, got %d", len(store.objects))
+	}
+}
+
+// =============================================================================
+// HEIC Conversion Tests
+// =============================================================================
+
+func newConvertingTestService(f *fakeImagesDB, store *fakeMemStorage, converter ImageConverter) ImageService {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewImageService(repository.New(fakedb.Open(f)), store, fakeThumbnailProcessor{}, converter, nil, nil, logger)
+}
+
+func TestImageUpload_HEICStoredAsJPEG(t *testing.T) {
+	f := &fakeImagesDB{inspectionID: uuid.New(), ownerID: uuid.New()}
+	store := &fakeMemStorage{objects: map[string][]byte{}}
+	converter := &fakeImageConverter{jpeg: []byte("\xff\xd8\xff converted jpeg")}
+	svc := newConvertingTestService(f, store, converter)
+
+	file, header := heicUpload("IMG_0001.HEIC", 4032, 3024)
+	img, err := svc.Upload(context.Background(), file, header, f.inspectionID, f.ownerID)
+	if err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+
+	if converter.calls != 1 {
+		t.Errorf("expected one conversion, got %d", converter.calls)
+	}
+	created := f.created
+	if created.ContentType != "image/jpeg" {
+		t.Errorf("expected content type image/jpeg, got %q", created.ContentType)
+	}
+	if !strings.HasSuffix(created.StorageKey, ".jpg") {
+		t.Errorf("expected a .jpg storage key, got %q", created.StorageKey)
+	}
+	if int(created.SizeBytes) != len(converter.jpeg) {
+		t.Errorf("expected the converted size %d, got %d", len(converter.jpeg), created.SizeBytes)
+	}
+	if !bytes.Equal(store.objects[created.StorageKey], converter.jpeg) {
+		t.Error("expected the converted JPEG to be stored as the original")
+	}
+	// The hash is of the stored bytes, so entity tags match what is served
+	sum := sha256.Sum256(converter.jpeg)
+	if want := hex.EncodeToString(sum[:]); created.ContentHash.String != want || img.ContentHash != want {
+		t.Errorf("expected content hash %s, got %q (record) and %q (image)", want, created.ContentHash.String, img.ContentHash)
+	}
+	// The user still sees the name of the photo they picked
+	if img.OriginalFilename != "IMG_0001.HEIC" {
+		t.Errorf("expected original filename IMG_0001.HEIC, got %q", img.OriginalFilename)
+	}
+}
+
+func TestImageUpload_HEICRejected(t *testing.T) {
+	tests := []struct {
+		name          string
+		converter     *fakeImageConverter
+		width, height uint32
+		wantCalls     int
+	}{
+		{"conversion fails", &fakeImageConverter{err: errors.New("heif-convert: exit status 1")}, 4032, 3024, 1},
+		{"too many pixels", &fakeImageConverter{jpeg: []byte("jpeg")}, 20000, 20000, 0},
+		{"converted JPEG too large", &fakeImageConverter{jpeg: make([]byte, domain.MaxImageSize+1)}, 4032, 3024, 1},
+		{"no converter", nil, 4032, 3024, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &fakeImagesDB{inspectionID: uuid.New(), ownerID: uuid.New()}
+			store := &fakeMemStorage{objects: map[string][]byte{}}
+			var converter ImageConverter
+			if tt.converter != nil {
+				converter = tt.converter
+			}
+			svc := newConvertingTestService(f, store, converter)
+
+			file, header := heicUpload("IMG_0001.HEIC", tt.width, tt.height)
+			_, err := svc.Upload(context.Background(), file, header, f.inspectionID, f.ownerID)
+
+			// A per-file message, not an internal error
+			if code := domain.ErrorCode(err); code != domain.EINVALID && code != domain.ETOOLARGE {
+				t.Fatalf("expected EINVALID or ETOOLARGE, got %v", err)
+			}
+			if tt.converter != nil && tt.converter.calls != tt.wantCalls {
+				t.Errorf("expected %d conversions, got %d", tt.wantCalls, tt.converter.calls)
+			}
+			if len(store.objects) != 0 || f.created != nil {
+				t.Error("expected nothing stored for a rejected photo")
+			}
+		})
+	}
+}
+
+// =============================================================================
+// Malware Scanning Tests
+// =============================================================================
+
+// stubScanner flags files containing pattern, or fails with err.
+type stubScanner struct {
+	pattern []byte
+	err     error
+	scanned int
+}
+
+func (s *stubScanner) Scan(ctx context.Context, r io.Reader) error {
+	s.scanned++
+	if s.err != nil {
+		return s.err
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if bytes.Contains(data, s.pattern) {
+		return &scan.ThreatError{Signature: "Test-Signature"}
+	}
+	return nil
+}
+
+func newScanningTestService(f *fakeImagesDB, store *fakeMemStorage, scanner scan.Scanner) ImageService {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewImageService(repository.New(fakedb.Open(f)), store, fakeThumbnailProcessor{}, nil, scanner, nil, logger)
+}
+
+func TestImageUpload_FlaggedFileRejected(t *testing.T) {
+	f := &fakeImagesDB{inspectionID: uuid.New(), ownerID: uuid.New()}
+	store := &fakeMemStorage{objects: map[string][]byte{}}
+	scanner := &stubScanner{pattern: []byte("MALWARE")}
+	svc := newScanningTestService(f, store, scanner)
+
+	data := append([]byte("\x89PNG\r\n\x1a\nMALWARE"), make([]byte, 64)...)
+	header := &multipart.FileHeader{Filename: "IMG_0001.png", Size: int64(len(data))}
+	_, err := svc.Upload(context.Background(), uploadFile{bytes.NewReader(data)}, header, f.inspectionID, f.ownerID)
+
+	if domain.ErrorCode(err) != domain.EINVALID {
+		t.Fatalf("expected EINVALID, got %v", err)
+	}
+	if len(store.objects) != 0 || f.created != nil {
+		t.Error("expected nothing stored for a flagged file")
+	}
+
+	// A clean file goes through the same scanner
+	file, header := pngUpload("IMG_0002.png")
+	if _, err := svc.Upload(context.Background(), file, header, f.inspectionID, f.ownerID); err != nil {
+		t.Fatalf("expected a clean file to upload, got %v", err)
+	}
+	if scanner.scanned != 2 {
+		t.Errorf("expected both files to be scanned, got %d scans", scanner.scanned)
+	}
+}
+
+func TestImageUpload_ScannerFailureRejectsFile(t *testing.T) {
+	f := &fakeImagesDB{inspectionID: uuid.New(), ownerID: uuid.New()}
+	store := &fakeMemStorage{objects: map[string][]byte{}}
+	svc := newScanningTestService(f, store, &stubScanner{err: errors.New("clamav: connect: connection refused")})
+
+	file, header := pngUpload("IMG_0001.png")
+	_, err := svc.Upload(context.Background(), file, header, f.inspectionID, f.ownerID)
+
+	if domain.ErrorCode(err) != domain.EINTERNAL {
+		t.Fatalf("expected EINTERNAL, got %v", err)
+	}
+	if len(store.objects) != 0 || f.created != nil {
+		t.Error("expected nothing stored when the file could not be scanned")
+	}
+}
+
+func TestDetectImageContentType(t *testing.T) {
+	heic, _ := heicUpload("a.heic", 1, 1)
+	heicHeader := make([]byte, 32)
+	_, _ = heic.Read(heicHeader)
+
+	tests := []struct {
+		name   string
+		header []byte
+		want   string
+	}{
+		{"heic", heicHeader, "image/heic"},
+		{"heif", []byte("\x00\x00\x00\x18ftypmif1\x00\x00\x00\x00"), "image/heif"},
+		{"png", []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x00"), "image/png"},
+		{"jpeg", []byte("\xff\xd8\xff\xe0\x00\x10JFIF\x00"), "image/jpeg"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectImageContentType(tt.header); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+// =============================================================================
+// CheckUploadCapacity Tests
+// =============================================================================
+
+func TestImageCheckUploadCapacity(t *testing.T) {
+	existing := make([]uuid.UUID, domain.MaxImagesPerInspection-2)
+	for i := range existing {
+		existing[i] = uuid.New()
+	}
+
+	tests := []struct {
+		name     string
+		existing []uuid.UUID
+		count    int
+		want     string // expected error code, empty for success
+	}{
+		{"empty inspection", nil, 30, ""},
+		{"fills the inspection exactly", existing, 2, ""},
+		{"one too many", existing, 3, domain.EINVALID},
+		{"already full", append(existing, uuid.New(), uuid.New()), 1, domain.EINVALID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &fakeImagesDB{inspectionID: uuid.New(), ownerID: uuid.New(), order: tt.existing}
+			svc := newUploadTestService(f, &fakeMemStorage{objects: map[string][]byte{}})
+
+			err := svc.CheckUploadCapacity(context.Background(), f.inspectionID, f.ownerID, tt.count)
+
+			if tt.want == "" && err != nil {
+				t.Errorf("expected success, got %v", err)
+			}
+			if tt.want != "" && domain.ErrorCode(err) != tt.want {
+				t.Errorf("expected %s, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestImageCheckUploadCapacity_OtherUsersInspection(t *testing.T) {
+	f := &fakeImagesDB{inspectionID: uuid.New(), ownerID: uuid.New()}
+	svc := newUploadTestService(f, &fakeMemStorage{objects: map[string][]byte{}})
+
+	err := svc.CheckUploadCapacity(context.Background(), f.inspectionID, uuid.New(), 1)
+
+	if domain.ErrorCode(err) != domain.ENOTFOUND {
+		t.Errorf("expected ENOTFOUND, got %v", err)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/geocode"
 	"github.com/DukeRupert/lukaut/internal/metrics"
 	"github.com/DukeRupert/lukaut/internal/repository"
 	"github.com/google/uuid"
@@ -29,8 +30,29 @@ type JobEnqueuer interface {
 	// EnqueueAnalyzeInspection enqueues a job to analyze an inspection's images.
 	EnqueueAnalyzeInspection(ctx context.Context, inspectionID, userID uuid.UUID) (repository.Job, error)
 
+	// EnqueueAnalyzeImages enqueues a job to analyze only the given images of an inspection.
+	// With force, images that were already analyzed are analyzed again.
+	EnqueueAnalyzeImages(ctx context.Context, inspectionID, userID uuid.UUID, imageIDs []uuid.UUID, force bool) (repository.Job, error)
+
 	// EnqueueGenerateReport enqueues a job to generate a report for an inspection.
-	EnqueueGenerateReport(ctx context.Context, inspectionID, userID uuid.UUID, format, recipientEmail string) (repository.Job, error)
+	EnqueueGenerateReport(ctx context.Context, inspectionID, ownerID uuid.UUID, format string, recipientEmails []string) (repository.Job, error)
+}
+
+// GeocodeEnqueuer schedules background geocoding of inspection addresses.
+// It is separate from JobEnqueuer so geocoding can be left unconfigured.
+type GeocodeEnqueuer interface {
+	// EnqueueGeocodeInspection enqueues a job to store the coordinates of
+	// address on the inspection.
+	EnqueueGeocodeInspection(ctx context.Context, inspectionID, userID uuid.UUID, address string) (repository.Job, error)
+}
+
+// WeatherEnqueuer schedules background weather auto-fill for new
+// inspections. It is separate from JobEnqueuer so weather can be left
+// unconfigured.
+type WeatherEnqueuer interface {
+	// EnqueueFillInspectionWeather enqueues a job to fill in the
+	// inspection's empty weather fields.
+	EnqueueFillInspectionWeather(ctx context.Context, inspectionID, userID uuid.UUID) (repository.Job, error)
 }
 
 // =============================================================================
@@ -50,26 +72,46 @@ type InspectionService interface {
 	Create(ctx context.Context, params domain.CreateInspectionParams) (*domain.Inspection, error)
 
 	// GetByID retrieves an inspection by ID and user ID (for authorization).
-	// Returns domain.ENOTFOUND if inspection does not exist or doesn't belong to user.
+	// Members of an organization can get each other's inspections.
+	// Returns domain.ENOTFOUND if inspection does not exist or the user cannot access it.
 	GetByID(ctx context.Context, id, userID uuid.UUID) (*domain.Inspection, error)
 
-	// List retrieves a paginated list of inspections for a user.
+	// List retrieves a paginated list of inspections for a user, or with
+	// params.Organization for the user and their organization's members,
+	// along with each inspection's owner name. Results are ordered by
+	// params.Sort; an empty or unknown column lists the newest first.
 	// Returns empty result if user has no inspections.
 	List(ctx context.Context, params domain.ListInspectionsParams) (*domain.ListInspectionsResult, error)
 
+	// ListLocations returns the user's most recent geocoded inspections for the
+	// dashboard map. Inspections without coordinates are omitted.
+	ListLocations(ctx context.Context, userID uuid.UUID) ([]domain.InspectionLocation, error)
+
 	// Update updates an existing inspection.
-	// Returns domain.ENOTFOUND if inspection does not exist or doesn't belong to user.
+	// Returns domain.ENOTFOUND if inspection does not exist or the user cannot access it.
 	// Returns domain.EINVALID for validation errors or if inspection is not editable.
+	// Returns domain.ECONFLICT if the inspection was edited after params.Version.
 	Update(ctx context.Context, params domain.UpdateInspectionParams) error
 
-	// Delete deletes an inspection by ID.
+	// Assign assigns an inspection to its owner or a member of the owner's
+	// organization, or unassigns it when params.AssigneeID is nil. Anyone who
+	// can work on the inspection can assign it.
+	// Returns domain.ENOTFOUND if inspection does not exist or the user cannot access it.
+	// Returns domain.EINVALID if the assignee is not in the owner's organization.
+	Assign(ctx context.Context, params domain.AssignInspectionParams) error
+
+	// Delete deletes an inspection by ID. Only the inspector who owns the
+	// inspection can delete it, not the other members of their organization.
 	// Returns domain.ENOTFOUND if inspection does not exist or doesn't belong to user.
 	// This cascades to delete all associated photos and violations.
 	Delete(ctx context.Context, id, userID uuid.UUID) error
 
-	// UpdateStatus updates the status of an inspection.
-	// Returns domain.ENOTFOUND if inspection does not exist or doesn't belong to user.
-	// Returns domain.EINVALID if status transition is invalid.
+	// UpdateStatus updates the status of an inspection. Completing it requires
+	// no violations pending review and a generated report, unless params
+	// confirm forcing it.
+	// Returns domain.ENOTFOUND if inspection does not exist or the user cannot access it.
+	// Returns domain.EINVALID if status transition is invalid, or wrapping
+	// domain.CompletionBlockers if the inspection is not ready to complete.
 	UpdateStatus(ctx context.Context, params domain.UpdateInspectionStatusParams) error
 
 	// GetAnalysisStatus returns the computed analysis status for an inspection.
@@ -91,6 +133,84 @@ type InspectionService interface {
 
 	// HasPendingAnalysisJob checks if there is a pending or running analysis job for the inspection.
 	HasPendingAnalysisJob(ctx context.Context, inspectionID uuid.UUID) (bool, error)
+
+	// Timeline returns the inspection's activity timeline, oldest first.
+	// Returns domain.ENOTFOUND if inspection does not exist or doesn't belong to user.
+	Timeline(ctx context.Context, inspectionID, userID uuid.UUID) ([]domain.TimelineEntry, error)
+
+	// Events returns the inspection's live activity feed, oldest first: events
+	// recorded after the given event ID, or the most recent ones when after is 0.
+	// Returns domain.ENOTFOUND if inspection does not exist or the user cannot access it.
+	Events(ctx context.Context, inspectionID, userID uuid.UUID, after int64) ([]domain.InspectionEvent, error)
+
+	// CreateShareLink mints an expiring public link to the inspection's report.
+	// Returns domain.ENOTFOUND if inspection does not exist or doesn't belong to user.
+	// Returns domain.EINVALID if the inspection is not completed or the options are invalid.
+	CreateShareLink(ctx context.Context, params domain.CreateShareLinkParams) (*domain.ShareLinkResult, error)
+
+	// ResolveShareToken returns the share link for a raw token without counting a view.
+	// Returns domain.ENOTFOUND if the token is unknown, expired, revoked, out of
+	// views, or the inspection is no longer completed.
+	ResolveShareToken(ctx context.Context, token string) (*domain.ShareLink, error)
+
+	// RecordShareLinkView counts a view of a resolved share link.
+	// Returns domain.ENOTFOUND if the link was revoked, expired, or used up its
+	// views since it was resolved.
+	RecordShareLinkView(ctx context.Context, link *domain.ShareLink) error
+
+	// ListShareLinks returns the user's active share links for an inspection, newest first.
+	// Revoked, expired, and used-up links are omitted.
+	ListShareLinks(ctx context.Context, inspectionID, userID uuid.UUID) ([]domain.ShareLink, error)
+
+	// RevokeShareLink revokes a share link so its URL stops working, and returns it.
+	// Returns domain.ENOTFOUND if the link does not exist, belongs to another user, or is already revoked.
+	RevokeShareLink(ctx context.Context, linkID, userID uuid.UUID) (*domain.ShareLink, error)
+
+	// AddComment posts a comment on an inspection as the user.
+	// Returns domain.ENOTFOUND if inspection does not exist or the user cannot access it.
+	// Returns domain.EINVALID if the comment is empty or too long.
+	AddComment(ctx context.Context, params domain.AddCommentParams) (*domain.InspectionComment, error)
+
+	// ListComments returns an inspection's comments, oldest first.
+	// Returns domain.ENOTFOUND if inspection does not exist or the user cannot access it.
+	ListComments(ctx context.Context, inspectionID, userID uuid.UUID) ([]domain.InspectionComment, error)
+
+	// DeleteComment deletes a comment the user wrote on an inspection.
+	// Returns domain.ENOTFOUND if the inspection or comment does not exist, the
+	// user cannot access the inspection, or someone else wrote the comment.
+	DeleteComment(ctx context.Context, inspectionID, commentID, userID uuid.UUID) error
+
+	// SaveDraft merges autosaved new-inspection form fields into the user's draft.
+	// Nil fields keep their saved values; empty strings clear them.
+	// Returns domain.EINVALID if a field is too long.
+	SaveDraft(ctx context.Context, params domain.SaveInspectionDraftParams) error
+
+	// GetDraft returns the user's autosaved new-inspection draft.
+	// Returns domain.ENOTFOUND if the user has no draft.
+	GetDraft(ctx context.Context, userID uuid.UUID) (*domain.InspectionDraft, error)
+
+	// DeleteDraft discards the user's draft, e.g. once the inspection is created.
+	DeleteDraft(ctx context.Context, userID uuid.UUID) error
+
+	// SaveAsTemplate keeps an inspection as a template under a name, or
+	// renames a template. Only the owner can do this.
+	// Returns domain.ENOTFOUND if inspection does not exist or doesn't belong to user.
+	// Returns domain.EINVALID if the name is empty or too long.
+	SaveAsTemplate(ctx context.Context, params domain.SaveInspectionTemplateParams) error
+
+	// RemoveTemplate stops keeping an inspection as a template. The
+	// inspection itself is kept.
+	// Returns domain.ENOTFOUND if inspection does not exist or doesn't belong to user.
+	RemoveTemplate(ctx context.Context, id, userID uuid.UUID) error
+
+	// ListTemplates returns the user's templates, by name.
+	ListTemplates(ctx context.Context, userID uuid.UUID) ([]domain.InspectionTemplate, error)
+
+	// CreateFromTemplate creates a draft inspection dated today from one of
+	// the user's templates. See domain.Inspection.TemplateCreateParams for
+	// the fields that are copied.
+	// Returns domain.ENOTFOUND if the template does not exist or doesn't belong to user.
+	CreateFromTemplate(ctx context.Context, templateID, userID uuid.UUID) (*domain.Inspection, error)
 }
 
 // =============================================================================
@@ -100,8 +220,13 @@ type InspectionService interface {
 // inspectionService implements the InspectionService interface.
 type inspectionService struct {
 	queries      *repository.Queries
+	access       orgAccess
 	jobEnqueuer  JobEnqueuer
 	quotaService QuotaService
+	audit        AuditService
+	events       InspectionEventService
+	geocoding    GeocodeEnqueuer
+	weather      WeatherEnqueuer
 	logger       *slog.Logger
 }
 
@@ -111,21 +236,32 @@ type inspectionService struct {
 // - queries: Repository queries for database access
 // - jobEnqueuer: Job enqueuer for background jobs (can be nil if job enqueueing not needed)
 // - quotaService: Quota service for rate limiting (can be nil to disable quota checks)
+// - audit: Audit service recording status changes and deletions
+// - geocoding: Enqueuer for address geocoding jobs (can be nil to skip geocoding)
+// - weather: Enqueuer for weather auto-fill jobs (can be nil to skip weather auto-fill)
 // - logger: Structured logger for operation logging
 //
 // Example usage:
 //
-//	inspectionService := service.NewInspectionService(repo, jobEnqueuer, quotaService, logger)
+//	inspectionService := service.NewInspectionService(repo, jobEnqueuer, quotaService, auditService, geocodeEnqueuer, weatherEnqueuer, logger)
 func NewInspectionService(
 	queries *repository.Queries,
 	jobEnqueuer JobEnqueuer,
 	quotaService QuotaService,
+	audit AuditService,
+	geocoding GeocodeEnqueuer,
+	weather WeatherEnqueuer,
 	logger *slog.Logger,
 ) InspectionService {
 	return &inspectionService{
 		queries:      queries,
+		access:       orgAccess{queries: queries},
 		jobEnqueuer:  jobEnqueuer,
 		quotaService: quotaService,
+		audit:        audit,
+		events:       NewInspectionEventService(queries, logger),
+		geocoding:    geocoding,
+		weather:      weather,
 		logger:       logger,
 	}
 }
@@ -165,7 +301,7 @@ func (s *inspectionService) Create(ctx context.Context, params domain.CreateInsp
 		Status:            string(domain.InspectionStatusDraft),
 		InspectionDate:    params.InspectionDate,
 		WeatherConditions: domain.ToNullString(params.WeatherConditions),
-		Temperature:       domain.ToNullString(params.Temperature),
+		Temperature:       domain.ToNullString(domain.NormalizeTemperature(params.Temperature, params.Units)),
 		InspectorNotes:    domain.ToNullString(params.InspectorNotes),
 		AddressLine1:      params.AddressLine1,
 		AddressLine2:      domain.ToNullString(params.AddressLine2),
@@ -180,7 +316,16 @@ func (s *inspectionService) Create(ctx context.Context, params domain.CreateInsp
 	// Convert to domain type
 	inspection := s.rowToInspection(row)
 
-	s.logger.Info("inspection created",
+	// Coordinates are looked up in the background
+	s.enqueueGeocode(ctx, inspection.ID, params.UserID,
+		geocode.FormatAddress(params.AddressLine1, params.AddressLine2, params.City, params.State, params.PostalCode))
+
+	// Weather the inspector left blank is looked up in the background
+	if strings.TrimSpace(params.WeatherConditions) == "" && strings.TrimSpace(params.Temperature) == "" {
+		s.enqueueFillWeather(ctx, inspection.ID, params.UserID)
+	}
+
+	s.logger.InfoContext(ctx, "inspection created",
 		"inspection_id", inspection.ID,
 		"user_id", params.UserID,
 		"title", params.Title,
@@ -235,9 +380,13 @@ func (s *inspectionService) GetByID(ctx context.Context, id, userID uuid.UUID) (
 	const op = "inspection.get"
 
 	// Get inspection with client information
+	ownerID, err := s.access.inspectionOwner(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
 	row, err := s.queries.GetInspectionWithClientByIDAndUserID(ctx, repository.GetInspectionWithClientByIDAndUserIDParams{
 		ID:     id,
-		UserID: userID,
+		UserID: ownerID,
 	})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -273,7 +422,12 @@ func (s *inspectionService) GetByID(ctx context.Context, id, userID uuid.UUID) (
 		PostalCode:        row.PostalCode,
 		CreatedAt:         createdAt,
 		UpdatedAt:         updatedAt,
+		Version:           row.Version,
+		AssignedTo:        nullUUIDToPtr(row.AssignedTo),
+		IsTemplate:        row.IsTemplate,
+		TemplateName:      domain.NullStringValue(row.TemplateName),
 		ClientName:        row.ClientName,
+		AssigneeName:      row.AssigneeName,
 	}
 
 	return inspection, nil
@@ -287,6 +441,15 @@ func (s *inspectionService) GetByID(ctx context.Context, id, userID uuid.UUID) (
 func (s *inspectionService) List(ctx context.Context, params domain.ListInspectionsParams) (*domain.ListInspectionsResult, error) {
 	const op = "inspection.list"
 
+	if !params.Sort.IsValid() {
+		params.Sort = domain.DefaultInspectionSort
+		params.Descending = params.Sort.DefaultDescending()
+	}
+
+	if params.Organization {
+		return s.listOrganization(ctx, params)
+	}
+
 	// Get total count
 	total, err := s.queries.CountInspectionsByUserID(ctx, params.UserID)
 	if err != nil {
@@ -295,9 +458,11 @@ func (s *inspectionService) List(ctx context.Context, params domain.ListInspecti
 
 	// Get paginated results
 	rows, err := s.queries.ListInspectionsWithClientByUserID(ctx, repository.ListInspectionsWithClientByUserIDParams{
-		UserID: params.UserID,
-		Limit:  params.Limit,
-		Offset: params.Offset,
+		UserID:   params.UserID,
+		Limit:    params.Limit,
+		Offset:   params.Offset,
+		SortBy:   string(params.Sort),
+		SortDesc: params.Descending,
 	})
 	if err != nil {
 		return nil, domain.Internal(err, op, "failed to list inspections")
@@ -342,11 +507,116 @@ func (s *inspectionService) List(ctx context.Context, params domain.ListInspecti
 	}, nil
 }
 
+// listOrganization lists the inspections of the user and the other members
+// of their organization, with each inspection's owner name.
+func (s *inspectionService) listOrganization(ctx context.Context, params domain.ListInspectionsParams) (*domain.ListInspectionsResult, error) {
+	const op = "inspection.list_organization"
+
+	total, err := s.queries.CountOrganizationInspectionsByUserID(ctx, params.UserID)
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to count inspections")
+	}
+
+	rows, err := s.queries.ListOrganizationInspectionsWithClientByUserID(ctx, repository.ListOrganizationInspectionsWithClientByUserIDParams{
+		UserID:   params.UserID,
+		Limit:    params.Limit,
+		Offset:   params.Offset,
+		SortBy:   string(params.Sort),
+		SortDesc: params.Descending,
+	})
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to list inspections")
+	}
+
+	inspections := make([]domain.Inspection, 0, len(rows))
+	for _, row := range rows {
+		createdAt := time.Time{}
+		if row.CreatedAt.Valid {
+			createdAt = row.CreatedAt.Time
+		}
+		updatedAt := time.Time{}
+		if row.UpdatedAt.Valid {
+			updatedAt = row.UpdatedAt.Time
+		}
+
+		inspections = append(inspections, domain.Inspection{
+			ID:             row.ID,
+			UserID:         row.UserID,
+			ClientID:       nullUUIDToPtr(row.ClientID),
+			Title:          row.Title,
+			Status:         domain.InspectionStatus(row.Status),
+			InspectionDate: row.InspectionDate,
+			AddressLine1:   row.AddressLine1,
+			AddressLine2:   domain.NullStringValue(row.AddressLine2),
+			City:           row.City,
+			State:          row.State,
+			PostalCode:     row.PostalCode,
+			CreatedAt:      createdAt,
+			UpdatedAt:      updatedAt,
+			ClientName:     row.ClientName,
+			OwnerName:      row.OwnerName,
+			AssigneeName:   row.AssigneeName,
+			ViolationCount: int(row.ViolationCount),
+		})
+	}
+
+	return &domain.ListInspectionsResult{
+		Inspections: inspections,
+		Total:       total,
+		Limit:       params.Limit,
+		Offset:      params.Offset,
+	}, nil
+}
+
+// =============================================================================
+// List Locations
+// =============================================================================
+
+// maxMapLocations caps the number of inspections plotted on the dashboard map.
+const maxMapLocations = 500
+
+// ListLocations retrieves geocoded inspections for the dashboard map.
+func (s *inspectionService) ListLocations(ctx context.Context, userID uuid.UUID) ([]domain.InspectionLocation, error) {
+	const op = "inspection.list_locations"
+
+	rows, err := s.queries.ListInspectionLocationsByUserID(ctx, repository.ListInspectionLocationsByUserIDParams{
+		UserID: userID,
+		Limit:  maxMapLocations,
+	})
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to list inspection locations")
+	}
+
+	locations := make([]domain.InspectionLocation, 0, len(rows))
+	for _, row := range rows {
+		if !row.Latitude.Valid || !row.Longitude.Valid {
+			continue
+		}
+		locations = append(locations, domain.InspectionLocation{
+			ID:             row.ID,
+			Title:          row.Title,
+			Status:         domain.InspectionStatus(row.Status),
+			InspectionDate: row.InspectionDate,
+			Address:        row.AddressLine1 + ", " + row.City + ", " + row.State,
+			Latitude:       row.Latitude.Float64,
+			Longitude:      row.Longitude.Float64,
+		})
+	}
+
+	return locations, nil
+}
+
 // =============================================================================
 // Update
 // =============================================================================
 
-// Update updates an existing inspection.
+// ErrMsgInspectionEditConflict is returned when an edit is based on an
+// outdated version of the inspection.
+const ErrMsgInspectionEditConflict = "This inspection was changed by someone else while you were editing it. Review the latest saved values below and save again to keep your changes."
+
+// Update updates an existing inspection. The update is applied only if the
+// stored version still equals params.Version, so a save from a stale form
+// cannot silently overwrite a newer one.
 func (s *inspectionService) Update(ctx context.Context, params domain.UpdateInspectionParams) error {
 	const op = "inspection.update"
 
@@ -355,10 +625,15 @@ func (s *inspectionService) Update(ctx context.Context, params domain.UpdateInsp
 		return err
 	}
 
-	// Get existing inspection to verify it's editable
+	// Get existing inspection to verify it's editable. Organization members
+	// edit it on behalf of its owner.
+	ownerID, err := s.access.inspectionOwner(ctx, params.ID, params.UserID)
+	if err != nil {
+		return err
+	}
 	existing, err := s.queries.GetInspectionByIDAndUserID(ctx, repository.GetInspectionByIDAndUserIDParams{
 		ID:     params.ID,
-		UserID: params.UserID,
+		UserID: ownerID,
 	})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -373,11 +648,11 @@ func (s *inspectionService) Update(ctx context.Context, params domain.UpdateInsp
 		return domain.Invalid(op, "cannot edit inspection while analysis is in progress")
 	}
 
-	// If client_id is provided, verify it exists and belongs to the user
+	// If client_id is provided, verify it exists and belongs to the owner
 	if params.ClientID != nil {
 		_, err := s.queries.GetClientByIDAndUserID(ctx, repository.GetClientByIDAndUserIDParams{
 			ID:     *params.ClientID,
-			UserID: params.UserID,
+			UserID: ownerID,
 		})
 		if err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
@@ -387,27 +662,48 @@ func (s *inspectionService) Update(ctx context.Context, params domain.UpdateInsp
 		}
 	}
 
-	// Update the inspection
-	err = s.queries.UpdateInspectionByIDAndUserID(ctx, repository.UpdateInspectionByIDAndUserIDParams{
+	// Update the inspection if nobody else saved since the form was loaded
+	rows, err := s.queries.UpdateInspectionByIDAndUserID(ctx, repository.UpdateInspectionByIDAndUserIDParams{
 		ID:                params.ID,
-		UserID:            params.UserID,
+		UserID:            ownerID,
 		Title:             params.Title,
 		ClientID:          domain.ToNullUUID(params.ClientID),
 		InspectionDate:    params.InspectionDate,
 		WeatherConditions: domain.ToNullString(params.WeatherConditions),
-		Temperature:       domain.ToNullString(params.Temperature),
+		Temperature:       domain.ToNullString(domain.NormalizeTemperature(params.Temperature, params.Units)),
 		InspectorNotes:    domain.ToNullString(params.InspectorNotes),
 		AddressLine1:      params.AddressLine1,
 		AddressLine2:      domain.ToNullString(params.AddressLine2),
 		City:              params.City,
 		State:             params.State,
 		PostalCode:        params.PostalCode,
+		Version:           params.Version,
 	})
 	if err != nil {
 		return domain.Internal(err, op, "failed to update inspection")
 	}
+	if rows == 0 {
+		return domain.Conflict(op, ErrMsgInspectionEditConflict)
+	}
+
+	// Refresh coordinates when the address changed or was never recognized.
+	// Coordinates of the old address are cleared right away, so a failed
+	// lookup leaves none rather than a pin in the wrong place.
+	address := geocode.FormatAddress(params.AddressLine1, params.AddressLine2, params.City, params.State, params.PostalCode)
+	previous := geocode.FormatAddress(existing.AddressLine1, existing.AddressLine2.String, existing.City, existing.State, existing.PostalCode)
+	if address != previous && existing.Latitude.Valid {
+		if err := s.queries.UpdateInspectionLocationByIDAndUserID(ctx, repository.UpdateInspectionLocationByIDAndUserIDParams{
+			ID:     params.ID,
+			UserID: ownerID,
+		}); err != nil {
+			s.logger.ErrorContext(ctx, "failed to clear inspection location", "error", err, "inspection_id", params.ID)
+		}
+	}
+	if address != previous || !existing.Latitude.Valid {
+		s.enqueueGeocode(ctx, params.ID, ownerID, address)
+	}
 
-	s.logger.Info("inspection updated",
+	s.logger.InfoContext(ctx, "inspection updated",
 		"inspection_id", params.ID,
 		"user_id", params.UserID,
 	)
@@ -415,6 +711,84 @@ func (s *inspectionService) Update(ctx context.Context, params domain.UpdateInsp
 	return nil
 }
 
+// =============================================================================
+// Assign
+// =============================================================================
+
+// Assign assigns an inspection to a member of its owner's organization.
+func (s *inspectionService) Assign(ctx context.Context, params domain.AssignInspectionParams) error {
+	const op = "inspection.assign"
+
+	ownerID, err := s.access.inspectionOwner(ctx, params.ID, params.UserID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.queries.GetInspectionByIDAndUserID(ctx, repository.GetInspectionByIDAndUserIDParams{
+		ID:     params.ID,
+		UserID: ownerID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.NotFound(op, "inspection", params.ID.String())
+		}
+		return domain.Internal(err, op, "failed to get inspection")
+	}
+
+	// The assignee must be able to work on the inspection themselves: its
+	// owner, or a member of the owner's organization
+	if params.AssigneeID != nil {
+		assigneeOwnerID, err := s.access.inspectionOwner(ctx, params.ID, *params.AssigneeID)
+		if err != nil {
+			return err
+		}
+		if assigneeOwnerID != ownerID {
+			return domain.Invalid(op, "Inspections can only be assigned to members of your organization.")
+		}
+	}
+
+	rows, err := s.queries.UpdateInspectionAssigneeByIDAndUserID(ctx, repository.UpdateInspectionAssigneeByIDAndUserIDParams{
+		ID:         params.ID,
+		UserID:     ownerID,
+		AssignedTo: domain.ToNullUUID(params.AssigneeID),
+	})
+	if err != nil {
+		return domain.Internal(err, op, "failed to assign inspection")
+	}
+	if rows == 0 {
+		return domain.NotFound(op, "inspection", params.ID.String())
+	}
+
+	s.logger.InfoContext(ctx, "inspection assigned",
+		"inspection_id", params.ID,
+		"user_id", params.UserID,
+		"assignee_id", params.AssigneeID,
+	)
+
+	return nil
+}
+
+// enqueueGeocode schedules a lookup of the inspection's coordinates.
+// Geocoding is best-effort: an enqueue failure is logged and never fails
+// the create or update.
+func (s *inspectionService) enqueueGeocode(ctx context.Context, id, userID uuid.UUID, address string) {
+	if s.geocoding == nil {
+		return
+	}
+	if _, err := s.geocoding.EnqueueGeocodeInspection(ctx, id, userID, address); err != nil {
+		s.logger.WarnContext(ctx, "failed to enqueue inspection geocoding", "error", err, "inspection_id", id)
+	}
+}
+
+// enqueueFillWeather schedules a weather auto-fill for a new inspection.
+// Like geocoding it is best-effort: an enqueue failure is only logged.
+func (s *inspectionService) enqueueFillWeather(ctx context.Context, id, userID uuid.UUID) {
+	if s.weather == nil {
+		return
+	}
+	if _, err := s.weather.EnqueueFillInspectionWeather(ctx, id, userID); err != nil {
+		s.logger.WarnContext(ctx, "failed to enqueue inspection weather fill", "error", err, "inspection_id", id)
+	}
+}
+
 // validateUpdateParams validates inspection update parameters.
 func (s *inspectionService) validateUpdateParams(params domain.UpdateInspectionParams) error {
 	const op = "inspection.validate"
@@ -460,7 +834,7 @@ func (s *inspectionService) Delete(ctx context.Context, id, userID uuid.UUID) er
 	const op = "inspection.delete"
 
 	// Verify inspection exists and belongs to user
-	_, err := s.queries.GetInspectionByIDAndUserID(ctx, repository.GetInspectionByIDAndUserIDParams{
+	existing, err := s.queries.GetInspectionByIDAndUserID(ctx, repository.GetInspectionByIDAndUserIDParams{
 		ID:     id,
 		UserID: userID,
 	})
@@ -471,16 +845,32 @@ func (s *inspectionService) Delete(ctx context.Context, id, userID uuid.UUID) er
 		return domain.Internal(err, op, "failed to get inspection")
 	}
 
-	// Delete the inspection (cascades to photos, violations, etc.)
-	err = s.queries.DeleteInspectionByIDAndUserID(ctx, repository.DeleteInspectionByIDAndUserIDParams{
-		ID:     id,
-		UserID: userID,
+	// Delete the inspection (cascades to photos, violations, etc.) and record it
+	// atomically. The audit row is kept after the inspection is gone.
+	err = s.audit.RecordChange(ctx, func(q *repository.Queries) error {
+		if err := q.DeleteInspectionByIDAndUserID(ctx, repository.DeleteInspectionByIDAndUserIDParams{
+			ID:     id,
+			UserID: userID,
+		}); err != nil {
+			return domain.Internal(err, op, "failed to delete inspection")
+		}
+		return nil
+	}, domain.AuditEvent{
+		ActorUserID:  userID,
+		InspectionID: &id,
+		EntityType:   domain.AuditEntityInspection,
+		EntityID:     id,
+		Action:       domain.AuditActionDeleted,
+		OldValues: map[string]string{
+			"status": existing.Status,
+			"title":  existing.Title,
+		},
 	})
 	if err != nil {
-		return domain.Internal(err, op, "failed to delete inspection")
+		return err
 	}
 
-	s.logger.Info("inspection deleted",
+	s.logger.InfoContext(ctx, "inspection deleted",
 		"inspection_id", id,
 		"user_id", userID,
 	)
@@ -502,9 +892,13 @@ func (s *inspectionService) UpdateStatus(ctx context.Context, params domain.Upda
 	}
 
 	// Get existing inspection
+	ownerID, err := s.access.inspectionOwner(ctx, params.ID, params.UserID)
+	if err != nil {
+		return err
+	}
 	existing, err := s.queries.GetInspectionByIDAndUserID(ctx, repository.GetInspectionByIDAndUserIDParams{
 		ID:     params.ID,
-		UserID: params.UserID,
+		UserID: ownerID,
 	})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -519,26 +913,77 @@ func (s *inspectionService) UpdateStatus(ctx context.Context, params domain.Upda
 		return domain.Invalid(op, fmt.Sprintf("cannot transition from %s to %s", currentStatus, params.Status))
 	}
 
-	// Update status
-	err = s.queries.UpdateInspectionStatusByIDAndUserID(ctx, repository.UpdateInspectionStatusByIDAndUserIDParams{
-		ID:     params.ID,
-		UserID: params.UserID,
-		Status: string(params.Status),
+	newValues := map[string]string{"status": string(params.Status)}
+	if params.Status == domain.InspectionStatusCompleted {
+		blockers, err := s.completionBlockers(ctx, op, params.ID)
+		if err != nil {
+			return err
+		}
+		if blockers.Any() {
+			if !params.Force {
+				return domain.Wrap(blockers, domain.EINVALID, op, blockers.Error())
+			}
+			if !params.ConfirmsForce() {
+				return domain.Wrap(blockers, domain.EINVALID, op,
+					fmt.Sprintf("type %s to complete the inspection anyway", domain.CompletionForceConfirmation))
+			}
+			newValues["forced"] = strings.Join(blockers.Reasons(), "; ")
+		}
+	}
+
+	// Update status and record the change atomically
+	inspectionID := params.ID
+	err = s.audit.RecordChange(ctx, func(q *repository.Queries) error {
+		if err := q.UpdateInspectionStatusByIDAndUserID(ctx, repository.UpdateInspectionStatusByIDAndUserIDParams{
+			ID:     params.ID,
+			UserID: ownerID,
+			Status: string(params.Status),
+		}); err != nil {
+			return domain.Internal(err, op, "failed to update inspection status")
+		}
+		return nil
+	}, domain.AuditEvent{
+		ActorUserID:  params.UserID,
+		InspectionID: &inspectionID,
+		EntityType:   domain.AuditEntityInspection,
+		EntityID:     params.ID,
+		Action:       domain.AuditActionStatusChanged,
+		OldValues:    map[string]string{"status": string(currentStatus)},
+		NewValues:    newValues,
 	})
 	if err != nil {
-		return domain.Internal(err, op, "failed to update inspection status")
+		return err
 	}
 
-	s.logger.Info("inspection status updated",
+	s.events.Record(ctx, params.ID, domain.InspectionEventStatusChanged, map[string]string{"status": string(params.Status)})
+
+	s.logger.InfoContext(ctx, "inspection status updated",
 		"inspection_id", params.ID,
 		"user_id", params.UserID,
 		"old_status", currentStatus,
 		"new_status", params.Status,
+		"forced", newValues["forced"] != "",
 	)
 
 	return nil
 }
 
+// completionBlockers checks what keeps an inspection from being completed.
+func (s *inspectionService) completionBlockers(ctx context.Context, op string, inspectionID uuid.UUID) (domain.CompletionBlockers, error) {
+	pending, err := s.queries.CountViolationsByStatus(ctx, repository.CountViolationsByStatusParams{
+		InspectionID: inspectionID,
+		Status:       string(domain.ViolationStatusPending),
+	})
+	if err != nil {
+		return domain.CompletionBlockers{}, domain.Internal(err, op, "failed to count pending violations")
+	}
+	reports, err := s.queries.CountReportsByInspectionID(ctx, inspectionID)
+	if err != nil {
+		return domain.CompletionBlockers{}, domain.Internal(err, op, "failed to count reports")
+	}
+	return domain.CompletionBlockers{PendingViolations: int(pending), MissingReport: reports == 0}, nil
+}
+
 // =============================================================================
 // Helper Functions
 // =============================================================================
@@ -569,8 +1014,13 @@ func (s *inspectionService) rowToInspection(row repository.Inspection) *domain.I
 		City:              row.City,
 		State:             row.State,
 		PostalCode:        row.PostalCode,
+		Latitude:          nullFloat64ToPtr(row.Latitude),
+		Longitude:         nullFloat64ToPtr(row.Longitude),
 		CreatedAt:         createdAt,
 		UpdatedAt:         updatedAt,
+		Version:           row.Version,
+		IsTemplate:        row.IsTemplate,
+		TemplateName:      domain.NullStringValue(row.TemplateName),
 	}
 }
 
@@ -597,6 +1047,13 @@ func (s *inspectionService) GetAnalysisStatus(ctx context.Context, inspectionID,
 		return nil, domain.Internal(err, op, "failed to count images")
 	}
 
+	// Failed images are not pending, so they don't hold the inspection back
+	// from review; they are counted so they can be retried one by one
+	failedCount, err := s.queries.CountFailedImagesByInspectionID(ctx, inspectionID)
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to count failed images")
+	}
+
 	violationCount, err := s.queries.CountViolationsByInspectionID(ctx, inspectionID)
 	if err != nil {
 		return nil, domain.Internal(err, op, "failed to count violations")
@@ -618,6 +1075,7 @@ func (s *inspectionService) GetAnalysisStatus(ctx context.Context, inspectionID,
 		PendingImages:  pendingCount,
 		TotalImages:    totalCount,
 		AnalyzedImages: totalCount - pendingCount,
+		FailedImages:   failedCount,
 		ViolationCount: violationCount,
 		Message:        message,
 		PollingEnabled: hasPendingJob,
@@ -642,22 +1100,19 @@ func (s *inspectionService) StartAnalysis(ctx context.Context, inspectionID, use
 		return nil
 	}
 
+	oldStatus := inspection.Status
 	if err := inspection.TransitionTo(domain.InspectionStatusAnalyzing); err != nil {
 		return domain.Invalid(op, err.Error())
 	}
 
-	if err := s.queries.UpdateInspectionStatusByIDAndUserID(ctx, repository.UpdateInspectionStatusByIDAndUserIDParams{
-		ID:     inspectionID,
-		UserID: userID,
-		Status: string(domain.InspectionStatusAnalyzing),
-	}); err != nil {
-		return domain.Internal(err, op, "failed to update inspection status")
+	if err := s.updateStatusAudited(ctx, op, inspectionID, userID, oldStatus, domain.InspectionStatusAnalyzing); err != nil {
+		return err
 	}
 
-	s.logger.Info("inspection analysis started",
+	s.logger.InfoContext(ctx, "inspection analysis started",
 		"inspection_id", inspectionID,
 		"user_id", userID,
-		"old_status", inspection.Status,
+		"old_status", oldStatus,
 	)
 
 	return nil
@@ -676,19 +1131,16 @@ func (s *inspectionService) CompleteAnalysis(ctx context.Context, inspectionID,
 		return err
 	}
 
+	oldStatus := inspection.Status
 	if err := inspection.TransitionTo(domain.InspectionStatusReview); err != nil {
 		return domain.Invalid(op, err.Error())
 	}
 
-	if err := s.queries.UpdateInspectionStatusByIDAndUserID(ctx, repository.UpdateInspectionStatusByIDAndUserIDParams{
-		ID:     inspectionID,
-		UserID: userID,
-		Status: string(domain.InspectionStatusReview),
-	}); err != nil {
-		return domain.Internal(err, op, "failed to update inspection status")
+	if err := s.updateStatusAudited(ctx, op, inspectionID, userID, oldStatus, domain.InspectionStatusReview); err != nil {
+		return err
 	}
 
-	s.logger.Info("inspection analysis completed",
+	s.logger.InfoContext(ctx, "inspection analysis completed",
 		"inspection_id", inspectionID,
 		"user_id", userID,
 	)
@@ -696,6 +1148,83 @@ func (s *inspectionService) CompleteAnalysis(ctx context.Context, inspectionID,
 	return nil
 }
 
+// updateStatusAudited sets the inspection status on behalf of a background
+// transition and records the change in the audit log in one transaction.
+func (s *inspectionService) updateStatusAudited(ctx context.Context, op string, inspectionID, userID uuid.UUID, oldStatus, newStatus domain.InspectionStatus) error {
+	ownerID, err := s.access.inspectionOwner(ctx, inspectionID, userID)
+	if err != nil {
+		return err
+	}
+	err = s.audit.RecordChange(ctx, func(q *repository.Queries) error {
+		if err := q.UpdateInspectionStatusByIDAndUserID(ctx, repository.UpdateInspectionStatusByIDAndUserIDParams{
+			ID:     inspectionID,
+			UserID: ownerID,
+			Status: string(newStatus),
+		}); err != nil {
+			return domain.Internal(err, op, "failed to update inspection status")
+		}
+		return nil
+	}, domain.AuditEvent{
+		ActorUserID:  userID,
+		InspectionID: &inspectionID,
+		EntityType:   domain.AuditEntityInspection,
+		EntityID:     inspectionID,
+		Action:       domain.AuditActionStatusChanged,
+		OldValues:    map[string]string{"status": string(oldStatus)},
+		NewValues:    map[string]string{"status": string(newStatus)},
+	})
+	if err != nil {
+		return err
+	}
+
+	s.events.Record(ctx, inspectionID, domain.InspectionEventStatusChanged, map[string]string{"status": string(newStatus)})
+	return nil
+}
+
+// =============================================================================
+// Timeline
+// =============================================================================
+
+// Timeline returns the inspection's activity timeline, oldest first.
+func (s *inspectionService) Timeline(ctx context.Context, inspectionID, userID uuid.UUID) ([]domain.TimelineEntry, error) {
+	const op = "inspection.timeline"
+
+	inspection, err := s.GetByID(ctx, inspectionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	images, err := s.queries.ListImagesByInspectionID(ctx, inspectionID)
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to list images")
+	}
+	uploads := make([]time.Time, 0, len(images))
+	for _, img := range images {
+		if img.CreatedAt.Valid {
+			uploads = append(uploads, img.CreatedAt.Time)
+		}
+	}
+
+	events, err := s.audit.ListByInspection(ctx, inspectionID, inspection.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.BuildInspectionTimeline(inspection, uploads, events), nil
+}
+
+// =============================================================================
+// Events
+// =============================================================================
+
+// Events returns the inspection's live activity feed, oldest first.
+func (s *inspectionService) Events(ctx context.Context, inspectionID, userID uuid.UUID, after int64) ([]domain.InspectionEvent, error) {
+	if _, err := s.GetByID(ctx, inspectionID, userID); err != nil {
+		return nil, err
+	}
+	return s.events.List(ctx, inspectionID, userID, after)
+}
+
 // nullUUIDToPtr converts a uuid.NullUUID to a *uuid.UUID.
 func nullUUIDToPtr(nu uuid.NullUUID) *uuid.UUID {
 	if !nu.Valid {
@@ -704,6 +1233,14 @@ func nullUUIDToPtr(nu uuid.NullUUID) *uuid.UUID {
 	return &nu.UUID
 }
 
+// nullFloat64ToPtr converts a sql.NullFloat64 to a *float64.
+func nullFloat64ToPtr(nf sql.NullFloat64) *float64 {
+	if !nf.Valid {
+		return nil
+	}
+	return &nf.Float64
+}
+
 // =============================================================================
 // TriggerAnalysis
 // =============================================================================
@@ -716,10 +1253,17 @@ func (s *inspectionService) TriggerAnalysis(ctx context.Context, inspectionID, u
 		return domain.Internal(nil, op, "job enqueuer not configured")
 	}
 
+	// The analysis runs on behalf of, and counts against the quota of, the
+	// inspection's owner, even when an organization member starts it
+	ownerID, err := s.access.inspectionOwner(ctx, inspectionID, userID)
+	if err != nil {
+		return err
+	}
+
 	// Check quota if quota service is configured
 	if s.quotaService != nil {
-		// Get user's subscription tier
-		user, err := s.queries.GetUserByID(ctx, userID)
+		// Get owner's subscription tier
+		user, err := s.queries.GetUserByID(ctx, ownerID)
 		if err != nil {
 			return domain.Internal(err, op, "failed to get user")
 		}
@@ -734,17 +1278,20 @@ func (s *inspectionService) TriggerAnalysis(ctx context.Context, inspectionID, u
 			}
 		}
 
-		if err := s.quotaService.CheckAnalysisQuota(ctx, userID, tier); err != nil {
+		if err := s.quotaService.CheckAnalysisQuota(ctx, ownerID, tier); err != nil {
 			return err
 		}
 	}
 
-	_, err := s.jobEnqueuer.EnqueueAnalyzeInspection(ctx, inspectionID, userID)
+	_, err = s.jobEnqueuer.EnqueueAnalyzeInspection(ctx, inspectionID, ownerID)
 	if err != nil {
+		if domain.ErrorCode(err) == domain.ERATELIMIT {
+			return err
+		}
 		return domain.Internal(err, op, "failed to enqueue analysis job")
 	}
 
-	s.logger.Info("Analysis job enqueued",
+	s.logger.InfoContext(ctx, "Analysis job enqueued",
 		"inspection_id", inspectionID,
 		"user_id", userID,
 	)
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/google/uuid"
+)
+
+// newTeamInspectionsDB returns an inspections database with an organization
+// of owner and teammate, and an outsider who works alone.
+func newTeamInspectionsDB() (f *fakeInspectionsDB, owner, teammate, outsider uuid.UUID) {
+	owner, teammate, outsider = uuid.New(), uuid.New(), uuid.New()
+	org := uuid.New()
+	f = &fakeInspectionsDB{
+		inspections: map[uuid.UUID]*fakeInspectionRow{},
+		orgs:        map[uuid.UUID]uuid.UUID{owner: org, teammate: org},
+		names:       map[uuid.UUID]string{owner: "Olive Owner", teammate: "Tess Teammate", outsider: "Oscar Outsider"},
+	}
+	return f, owner, teammate, outsider
+}
+
+// =============================================================================
+// Organization Access Tests
+// =============================================================================
+
+func TestInspectionAccess_TeammateCanGetListAndUpdate(t *testing.T) {
+	ctx := context.Background()
+	f, owner, teammate, _ := newTeamInspectionsDB()
+	svc := newGeocodeTestInspectionService(f, nil)
+
+	inspection, err := svc.Create(ctx, geocodeTestCreateParams(owner))
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	got, err := svc.GetByID(ctx, inspection.ID, teammate)
+	if err != nil {
+		t.Fatalf("expected teammate to get the inspection, got %v", err)
+	}
+	if got.UserID != owner {
+		t.Errorf("expected the inspection to stay the owner's, got %s", got.UserID)
+	}
+
+	result, err := svc.List(ctx, domain.ListInspectionsParams{UserID: teammate, Organization: true, Limit: 20})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if result.Total != 1 || len(result.Inspections) != 1 || result.Inspections[0].OwnerName != "Olive Owner" {
+		t.Errorf("expected the owner's inspection in the teammate's organization list, got %+v", result)
+	}
+
+	edit := editParams(inspection, "200 Teammate Way", inspection.Version)
+	edit.UserID = teammate
+	if err := svc.Update(ctx, edit); err != nil {
+		t.Fatalf("expected teammate to update the inspection, got %v", err)
+	}
+	if row := f.inspections[inspection.ID]; row.line1 != "200 Teammate Way" || row.userID != owner {
+		t.Errorf("expected the edit saved on the owner's inspection, got %q owned by %s", row.line1, row.userID)
+	}
+}
+
+func TestInspectionAccess_NonMemberDenied(t *testing.T) {
+	ctx := context.Background()
+	f, owner, _, outsider := newTeamInspectionsDB()
+	svc := newGeocodeTestInspectionService(f, nil)
+
+	inspection, err := svc.Create(ctx, geocodeTestCreateParams(owner))
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	if _, err := svc.GetByID(ctx, inspection.ID, outsider); domain.ErrorCode(err) != domain.ENOTFOUND {
+		t.Errorf("expected ENOTFOUND getting another organization's inspection, got %v", err)
+	}
+
+	result, err := svc.List(ctx, domain.ListInspectionsParams{UserID: outsider, Organization: true, Limit: 20})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if result.Total != 0 || len(result.Inspections) != 0 {
+		t.Errorf("expected no inspections listed for an outsider, got %+v", result)
+	}
+
+	edit := editParams(inspection, "666 Intruder Rd", inspection.Version)
+	edit.UserID = outsider
+	if err := svc.Update(ctx, edit); domain.ErrorCode(err) != domain.ENOTFOUND {
+		t.Errorf("expected ENOTFOUND updating another organization's inspection, got %v", err)
+	}
+	if row := f.inspections[inspection.ID]; row.line1 == "666 Intruder Rd" {
+		t.Error("expected the outsider's edit not to be saved")
+	}
+
+	assignee := outsider
+	if err := svc.Assign(ctx, domain.AssignInspectionParams{ID: inspection.ID, UserID: outsider, AssigneeID: &assignee}); domain.ErrorCode(err) != domain.ENOTFOUND {
+		t.Errorf("expected ENOTFOUND assigning another organization's inspection, got %v", err)
+	}
+}
+
+// =============================================================================
+// Assignment Tests
+// =============================================================================
+
+func TestInspectionAssign_ToTeammate(t *testing.T) {
+	ctx := context.Background()
+	f, owner, teammate, _ := newTeamInspectionsDB()
+	svc := newGeocodeTestInspectionService(f, nil)
+
+	inspection, err := svc.Create(ctx, geocodeTestCreateParams(owner))
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	if err := svc.Assign(ctx, domain.AssignInspectionParams{ID: inspection.ID, UserID: owner, AssigneeID: &teammate}); err != nil {
+		t.Fatalf("Assign failed: %v", err)
+	}
+
+	got, err := svc.GetByID(ctx, inspection.ID, teammate)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if got.AssignedTo == nil || *got.AssignedTo != teammate || got.AssigneeName != "Tess Teammate" {
+		t.Errorf("expected the inspection assigned to the teammate, got %v %q", got.AssignedTo, got.AssigneeName)
+	}
+
+	// The assignee can hand it back by unassigning it
+	if err := svc.Assign(ctx, domain.AssignInspectionParams{ID: inspection.ID, UserID: teammate}); err != nil {
+		t.Fatalf("unassign failed: %v", err)
+	}
+	if f.inspections[inspection.ID].assignedTo != nil {
+		t.Error("expected the inspection to be unassigned")
+	}
+}
+
+func TestInspectionAssign_RejectsNonMember(t *testing.T) {
+	ctx := context.Background()
+	f, owner, _, outsider := newTeamInspectionsDB()
+	svc := newGeocodeTestInspectionService(f, nil)
+
+	inspection, err := svc.Create(ctx, geocodeTestCreateParams(owner))
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	err = svc.Assign(ctx, domain.AssignInspectionParams{ID: inspection.ID, UserID: owner, AssigneeID: &outsider})
+	if domain.ErrorCode(err) != domain.EINVALID {
+		t.Fatalf("expected EINVALID assigning to a non-member, got %v", err)
+	}
+	if f.inspections[inspection.ID].assignedTo != nil {
+		t.Error("expected the inspection to stay unassigned")
+	}
+}
+
+func TestInspectionAccess_OwnerLookupErrorIsInternal(t *testing.T) {
+	ctx := context.Background()
+	f, owner, teammate, _ := newTeamInspectionsDB()
+	svc := newGeocodeTestInspectionService(f, nil)
+
+	inspection, err := svc.Create(ctx, geocodeTestCreateParams(owner))
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	// A failed lookup must not fall back to treating the caller as the owner
+	f.failQuery = "GetInspectionOwnerIDForUser"
+	edit := editParams(inspection, "200 Teammate Way", inspection.Version)
+	edit.UserID = teammate
+	if err := svc.Update(ctx, edit); domain.ErrorCode(err) != domain.EINTERNAL {
+		t.Errorf("expected EINTERNAL when the owner lookup fails, got %v", err)
+	}
+	if row := f.inspections[inspection.ID]; row.line1 == "200 Teammate Way" {
+		t.Error("expected the edit not to be saved")
+	}
+}
@@ -0,0 +1,108 @@
+// Package service contains the business logic layer.
+//
+// This file implements inspection comments. Anyone who can open an
+// inspection can read and add to its discussion; only a comment's author
+// can delete it.
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/google/uuid"
+)
+
+// AddComment posts a comment on an inspection as the given user.
+func (s *inspectionService) AddComment(ctx context.Context, params domain.AddCommentParams) (*domain.InspectionComment, error) {
+	const op = "inspection.add_comment"
+
+	body := strings.TrimSpace(params.Body)
+	if body == "" {
+		return nil, domain.Invalid(op, "comment cannot be empty")
+	}
+	if utf8.RuneCountInString(body) > domain.MaxCommentLength {
+		return nil, domain.Invalid(op, fmt.Sprintf("comment must be %d characters or less", domain.MaxCommentLength))
+	}
+
+	if _, err := s.GetByID(ctx, params.InspectionID, params.UserID); err != nil {
+		return nil, err
+	}
+
+	row, err := s.queries.CreateInspectionComment(ctx, repository.CreateInspectionCommentParams{
+		InspectionID: params.InspectionID,
+		UserID:       params.UserID,
+		Body:         body,
+	})
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to create comment")
+	}
+
+	s.logger.InfoContext(ctx, "inspection comment added", "inspection_id", params.InspectionID, "comment_id", row.ID)
+
+	return &domain.InspectionComment{
+		ID:           row.ID,
+		InspectionID: row.InspectionID,
+		AuthorID:     row.UserID,
+		Body:         row.Body,
+		CreatedAt:    row.CreatedAt,
+	}, nil
+}
+
+// ListComments returns an inspection's comments, oldest first.
+func (s *inspectionService) ListComments(ctx context.Context, inspectionID, userID uuid.UUID) ([]domain.InspectionComment, error) {
+	const op = "inspection.list_comments"
+
+	inspection, err := s.GetByID(ctx, inspectionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.queries.ListInspectionComments(ctx, repository.ListInspectionCommentsParams{
+		InspectionID: inspectionID,
+		UserID:       inspection.UserID,
+	})
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to list comments")
+	}
+
+	comments := make([]domain.InspectionComment, len(rows))
+	for i, row := range rows {
+		comments[i] = domain.InspectionComment{
+			ID:           row.ID,
+			InspectionID: row.InspectionID,
+			AuthorID:     row.UserID,
+			AuthorName:   row.AuthorName,
+			Body:         row.Body,
+			CreatedAt:    row.CreatedAt,
+		}
+	}
+	return comments, nil
+}
+
+// DeleteComment deletes the user's own comment on an inspection.
+func (s *inspectionService) DeleteComment(ctx context.Context, inspectionID, commentID, userID uuid.UUID) error {
+	const op = "inspection.delete_comment"
+
+	if _, err := s.GetByID(ctx, inspectionID, userID); err != nil {
+		return err
+	}
+
+	affected, err := s.queries.DeleteInspectionCommentByAuthor(ctx, repository.DeleteInspectionCommentByAuthorParams{
+		ID:           commentID,
+		InspectionID: inspectionID,
+		UserID:       userID,
+	})
+	if err != nil {
+		return domain.Internal(err, op, "failed to delete comment")
+	}
+	if affected == 0 {
+		return domain.NotFound(op, "comment", commentID.String())
+	}
+
+	s.logger.InfoContext(ctx, "inspection comment deleted", "inspection_id", inspectionID, "comment_id", commentID)
+	return nil
+}
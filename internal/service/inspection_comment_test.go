@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+)
+
+// =============================================================================
+// Inspection Comment Tests
+// =============================================================================
+
+func TestInspectionComments_TeamPostsAndListsOldestFirst(t *testing.T) {
+	ctx := context.Background()
+	f, owner, teammate, _ := newTeamInspectionsDB()
+	svc := newGeocodeTestInspectionService(f, nil)
+
+	inspection, err := svc.Create(ctx, geocodeTestCreateParams(owner))
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	posts := []domain.AddCommentParams{
+		{InspectionID: inspection.ID, UserID: owner, Body: "Can you check the north stairwell?"},
+		{InspectionID: inspection.ID, UserID: teammate, Body: "  Guardrail is missing on level 2.  "},
+		{InspectionID: inspection.ID, UserID: owner, Body: "Thanks, adding it."},
+	}
+	for _, p := range posts {
+		if _, err := svc.AddComment(ctx, p); err != nil {
+			t.Fatalf("AddComment by %s failed: %v", p.UserID, err)
+		}
+	}
+
+	comments, err := svc.ListComments(ctx, inspection.ID, teammate)
+	if err != nil {
+		t.Fatalf("ListComments failed: %v", err)
+	}
+	if len(comments) != 3 {
+		t.Fatalf("expected 3 comments, got %d", len(comments))
+	}
+	for i, c := range comments {
+		if c.AuthorID != posts[i].UserID || c.Body != strings.TrimSpace(posts[i].Body) {
+			t.Errorf("comment %d: expected %q by %s, got %q by %s", i, strings.TrimSpace(posts[i].Body), posts[i].UserID, c.Body, c.AuthorID)
+		}
+	}
+	if comments[1].AuthorName != "Tess Teammate" {
+		t.Errorf("expected the teammate's name on their comment, got %q", comments[1].AuthorName)
+	}
+}
+
+func TestInspectionComments_OutsiderDenied(t *testing.T) {
+	ctx := context.Background()
+	f, owner, _, outsider := newTeamInspectionsDB()
+	svc := newGeocodeTestInspectionService(f, nil)
+
+	inspection, err := svc.Create(ctx, geocodeTestCreateParams(owner))
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if _, err := svc.AddComment(ctx, domain.AddCommentParams{InspectionID: inspection.ID, UserID: owner, Body: "Owner only"}); err != nil {
+		t.Fatalf("AddComment failed: %v", err)
+	}
+
+	_, err = svc.AddComment(ctx, domain.AddCommentParams{InspectionID: inspection.ID, UserID: outsider, Body: "Hello"})
+	if domain.ErrorCode(err) != domain.ENOTFOUND {
+		t.Errorf("expected ENOTFOUND commenting on another organization's inspection, got %v", err)
+	}
+	if _, err := svc.ListComments(ctx, inspection.ID, outsider); domain.ErrorCode(err) != domain.ENOTFOUND {
+		t.Errorf("expected ENOTFOUND listing another organization's comments, got %v", err)
+	}
+	if len(f.comments) != 1 {
+		t.Errorf("expected only the owner's comment stored, got %d", len(f.comments))
+	}
+}
+
+func TestInspectionComments_RejectsEmptyAndTooLong(t *testing.T) {
+	ctx := context.Background()
+	f, owner, _, _ := newTeamInspectionsDB()
+	svc := newGeocodeTestInspectionService(f, nil)
+
+	inspection, err := svc.Create(ctx, geocodeTestCreateParams(owner))
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	for _, body := range []string{"   ", strings.Repeat("a", domain.MaxCommentLength+1)} {
+		_, err := svc.AddComment(ctx, domain.AddCommentParams{InspectionID: inspection.ID, UserID: owner, Body: body})
+		if domain.ErrorCode(err) != domain.EINVALID {
+			t.Errorf("expected EINVALID for a %d character comment, got %v", len(body), err)
+		}
+	}
+	if len(f.comments) != 0 {
+		t.Errorf("expected no comments stored, got %d", len(f.comments))
+	}
+}
+
+func TestInspectionComments_OnlyAuthorCanDelete(t *testing.T) {
+	ctx := context.Background()
+	f, owner, teammate, _ := newTeamInspectionsDB()
+	svc := newGeocodeTestInspectionService(f, nil)
+
+	inspection, err := svc.Create(ctx, geocodeTestCreateParams(owner))
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	comment, err := svc.AddComment(ctx, domain.AddCommentParams{InspectionID: inspection.ID, UserID: teammate, Body: "Photo 3 is blurry"})
+	if err != nil {
+		t.Fatalf("AddComment failed: %v", err)
+	}
+
+	// Owning the inspection does not grant deleting a teammate's comment
+	if err := svc.DeleteComment(ctx, inspection.ID, comment.ID, owner); domain.ErrorCode(err) != domain.ENOTFOUND {
+		t.Errorf("expected ENOTFOUND deleting another user's comment, got %v", err)
+	}
+	if len(f.comments) != 1 {
+		t.Fatalf("expected the comment kept, got %d comments", len(f.comments))
+	}
+
+	if err := svc.DeleteComment(ctx, inspection.ID, comment.ID, teammate); err != nil {
+		t.Fatalf("expected the author to delete their comment, got %v", err)
+	}
+	if len(f.comments) != 0 {
+		t.Errorf("expected the comment deleted, got %d comments", len(f.comments))
+	}
+}
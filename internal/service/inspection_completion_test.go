@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/google/uuid"
+)
+
+// recordingAudit applies changes without a transaction and keeps the events
+// they record.
+type recordingAudit struct {
+	AuditService
+	queries *repository.Queries
+	events  []domain.AuditEvent
+}
+
+func (a *recordingAudit) RecordChange(ctx context.Context, change func(q *repository.Queries) error, events ...domain.AuditEvent) error {
+	if err := change(a.queries); err != nil {
+		return err
+	}
+	a.events = append(a.events, events...)
+	return nil
+}
+
+// newCompletionTestService returns an inspection service with one inspection
+// in review, set up by row.
+func newCompletionTestService(row fakeInspectionRow) (InspectionService, *recordingAudit, *fakeInspectionRow) {
+	f := &fakeInspectionsDB{inspections: map[uuid.UUID]*fakeInspectionRow{}}
+	row.id = uuid.New()
+	row.userID = uuid.New()
+	row.status = domain.InspectionStatusReview
+	f.inspections[row.id] = &row
+
+	svc := newGeocodeTestInspectionService(f, nil).(*inspectionService)
+	audit := &recordingAudit{queries: svc.queries}
+	svc.audit = audit
+	return svc, audit, &row
+}
+
+func completeParams(row *fakeInspectionRow) domain.UpdateInspectionStatusParams {
+	return domain.UpdateInspectionStatusParams{ID: row.id, UserID: row.userID, Status: domain.InspectionStatusCompleted}
+}
+
+func TestInspectionUpdateStatus_CompletionBlockers(t *testing.T) {
+	tests := []struct {
+		name        string
+		row         fakeInspectionRow
+		wantMessage string
+	}{
+		{
+			name:        "violations pending review",
+			row:         fakeInspectionRow{pendingViolations: 3, reports: 1},
+			wantMessage: "cannot complete inspection: 3 violations pending review",
+		},
+		{
+			name:        "no report generated",
+			row:         fakeInspectionRow{},
+			wantMessage: "cannot complete inspection: no report has been generated",
+		},
+		{
+			name:        "both",
+			row:         fakeInspectionRow{pendingViolations: 1},
+			wantMessage: "cannot complete inspection: 1 violation pending review; no report has been generated",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, audit, row := newCompletionTestService(tt.row)
+
+			err := svc.UpdateStatus(context.Background(), completeParams(row))
+
+			if domain.ErrorCode(err) != domain.EINVALID {
+				t.Fatalf("expected EINVALID, got %v", err)
+			}
+			if got := domain.ErrorMessage(err); got != tt.wantMessage {
+				t.Errorf("expected message %q, got %q", tt.wantMessage, got)
+			}
+			var blockers domain.CompletionBlockers
+			if !errors.As(err, &blockers) || blockers.PendingViolations != int(tt.row.pendingViolations) {
+				t.Errorf("expected the blockers in the error, got %+v", blockers)
+			}
+			if row.status != domain.InspectionStatusReview || len(audit.events) != 0 {
+				t.Errorf("expected the inspection to stay in review, got %s", row.status)
+			}
+		})
+	}
+}
+
+func TestInspectionUpdateStatus_CompletesWhenReady(t *testing.T) {
+	svc, audit, row := newCompletionTestService(fakeInspectionRow{reports: 1})
+
+	if err := svc.UpdateStatus(context.Background(), completeParams(row)); err != nil {
+		t.Fatalf("UpdateStatus failed: %v", err)
+	}
+	if row.status != domain.InspectionStatusCompleted {
+		t.Errorf("expected completed, got %s", row.status)
+	}
+	if len(audit.events) != 1 || audit.events[0].NewValues["forced"] != "" {
+		t.Errorf("expected an unforced status change, got %+v", audit.events)
+	}
+}
+
+func TestInspectionUpdateStatus_ForceCompletion(t *testing.T) {
+	svc, audit, row := newCompletionTestService(fakeInspectionRow{pendingViolations: 2})
+
+	// Forcing needs the confirmation typed exactly
+	params := completeParams(row)
+	params.Force = true
+	params.Confirmation = "complete"
+	err := svc.UpdateStatus(context.Background(), params)
+	if domain.ErrorCode(err) != domain.EINVALID {
+		t.Fatalf("expected EINVALID for a mistyped confirmation, got %v", err)
+	}
+	if got := domain.ErrorMessage(err); got != "type COMPLETE to complete the inspection anyway" {
+		t.Errorf("unexpected message %q", got)
+	}
+	if row.status != domain.InspectionStatusReview {
+		t.Fatalf("expected the inspection to stay in review, got %s", row.status)
+	}
+
+	params.Confirmation = " COMPLETE "
+	if err := svc.UpdateStatus(context.Background(), params); err != nil {
+		t.Fatalf("forced UpdateStatus failed: %v", err)
+	}
+	if row.status != domain.InspectionStatusCompleted {
+		t.Errorf("expected completed, got %s", row.status)
+	}
+	want := "2 violations pending review; no report has been generated"
+	if len(audit.events) != 1 || audit.events[0].NewValues["forced"] != want {
+		t.Errorf("expected the audit event to record what was overridden, got %+v", audit.events)
+	}
+}
@@ -0,0 +1,119 @@
+// Package service contains the business logic layer.
+//
+// This file implements inspection drafts: the new-inspection form is
+// autosaved field by field so work survives a lost connection or a closed
+// tab, and is restored the next time the form is opened.
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/google/uuid"
+)
+
+// maxDraftFieldLength caps each autosaved value. Drafts are not validated,
+// so this only guards against oversized requests.
+const maxDraftFieldLength = 10000
+
+// =============================================================================
+// SaveDraft
+// =============================================================================
+
+// SaveDraft merges the sent fields into the user's draft, creating it if
+// needed. Fields left nil keep their saved values.
+func (s *inspectionService) SaveDraft(ctx context.Context, params domain.SaveInspectionDraftParams) error {
+	const op = "inspection.save_draft"
+
+	fields := []*string{
+		params.Title, params.ClientID, params.AddressLine1, params.AddressLine2,
+		params.City, params.State, params.PostalCode, params.InspectionDate,
+		params.WeatherConditions, params.Temperature, params.InspectorNotes,
+	}
+	for _, f := range fields {
+		if f != nil && len(*f) > maxDraftFieldLength {
+			return domain.Invalid(op, "draft field is too long")
+		}
+	}
+
+	_, err := s.queries.UpsertInspectionDraft(ctx, repository.UpsertInspectionDraftParams{
+		UserID:            params.UserID,
+		Title:             draftValue(params.Title),
+		ClientID:          draftValue(params.ClientID),
+		AddressLine1:      draftValue(params.AddressLine1),
+		AddressLine2:      draftValue(params.AddressLine2),
+		City:              draftValue(params.City),
+		State:             draftValue(params.State),
+		PostalCode:        draftValue(params.PostalCode),
+		InspectionDate:    draftValue(params.InspectionDate),
+		WeatherConditions: draftValue(params.WeatherConditions),
+		Temperature:       draftValue(params.Temperature),
+		InspectorNotes:    draftValue(params.InspectorNotes),
+	})
+	if err != nil {
+		return domain.Internal(err, op, "failed to save inspection draft")
+	}
+
+	return nil
+}
+
+// draftValue converts an autosaved field to a query argument. Unlike
+// domain.ToNullString, an empty string stays valid so it clears the saved
+// value; only a field that was not sent becomes NULL.
+func draftValue(s *string) sql.NullString {
+	if s == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *s, Valid: true}
+}
+
+// =============================================================================
+// GetDraft
+// =============================================================================
+
+// GetDraft returns the user's autosaved draft.
+func (s *inspectionService) GetDraft(ctx context.Context, userID uuid.UUID) (*domain.InspectionDraft, error) {
+	const op = "inspection.get_draft"
+
+	row, err := s.queries.GetInspectionDraftByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.NotFound(op, "inspection draft", userID.String())
+		}
+		return nil, domain.Internal(err, op, "failed to get inspection draft")
+	}
+
+	return &domain.InspectionDraft{
+		UserID:            row.UserID,
+		Title:             domain.NullStringValue(row.Title),
+		ClientID:          domain.NullStringValue(row.ClientID),
+		AddressLine1:      domain.NullStringValue(row.AddressLine1),
+		AddressLine2:      domain.NullStringValue(row.AddressLine2),
+		City:              domain.NullStringValue(row.City),
+		State:             domain.NullStringValue(row.State),
+		PostalCode:        domain.NullStringValue(row.PostalCode),
+		InspectionDate:    domain.NullStringValue(row.InspectionDate),
+		WeatherConditions: domain.NullStringValue(row.WeatherConditions),
+		Temperature:       domain.NullStringValue(row.Temperature),
+		InspectorNotes:    domain.NullStringValue(row.InspectorNotes),
+		UpdatedAt:         row.UpdatedAt,
+	}, nil
+}
+
+// =============================================================================
+// DeleteDraft
+// =============================================================================
+
+// DeleteDraft discards the user's draft. Deleting a missing draft is not an error.
+func (s *inspectionService) DeleteDraft(ctx context.Context, userID uuid.UUID) error {
+	const op = "inspection.delete_draft"
+
+	if err := s.queries.DeleteInspectionDraftByUserID(ctx, userID); err != nil {
+		return domain.Internal(err, op, "failed to delete inspection draft")
+	}
+
+	return nil
+}
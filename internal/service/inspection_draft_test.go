@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/DukeRupert/lukaut/internal/testutil/fakedb"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// In-Memory Drafts Database
+// =============================================================================
+
+// draftColumns is the number of inspection_drafts columns.
+const draftColumns = 13
+
+// fakeDraftsDB answers the inspection draft queries. Each draft is stored in
+// repository.InspectionDraft column order; nil means NULL.
+type fakeDraftsDB struct {
+	drafts map[string][]driver.Value
+}
+
+func (f *fakeDraftsDB) Query(q fakedb.Query) (*fakedb.Rows, error) {
+	args := q.Args
+
+	userID := args[0].Value.(string)
+	switch q.Name {
+	case "GetInspectionDraftByUserID":
+		rows := &fakedb.Rows{Columns: draftColumns}
+		if draft, ok := f.drafts[userID]; ok {
+			rows.Values = append(rows.Values, draft)
+		}
+		return rows, nil
+	case "UpsertInspectionDraft":
+		// Mirrors ON CONFLICT ... COALESCE(EXCLUDED.col, inspection_drafts.col)
+		draft, ok := f.drafts[userID]
+		if !ok {
+			draft = make([]driver.Value, draftColumns)
+			draft[0] = userID
+			f.drafts[userID] = draft
+		}
+		for i := 1; i < len(args); i++ {
+			if args[i].Value != nil {
+				draft[i] = args[i].Value
+			}
+		}
+		draft[draftColumns-1] = time.Now()
+		return &fakedb.Rows{Columns: draftColumns, Values: [][]driver.Value{draft}}, nil
+	}
+	return nil, fmt.Errorf("fakeDraftsDB: unexpected query %q", q.Name)
+}
+
+func (f *fakeDraftsDB) Exec(q fakedb.Query) (int64, error) {
+	args := q.Args
+
+	if q.Name == "DeleteInspectionDraftByUserID" {
+		delete(f.drafts, args[0].Value.(string))
+		return 1, nil
+	}
+	return 0, fmt.Errorf("fakeDraftsDB: unexpected exec %q", q.Name)
+}
+
+func newDraftTestService() InspectionService {
+	db := fakedb.Open(&fakeDraftsDB{drafts: map[string][]driver.Value{}})
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewInspectionService(repository.New(db), nil, nil, nil, nil, nil, logger)
+}
+
+func strPtr(s string) *string { return &s }
+
+// =============================================================================
+// Tests
+// =============================================================================
+
+func TestSaveDraft_MergesPartialFields(t *testing.T) {
+	svc := newDraftTestService()
+	ctx := context.Background()
+	userID := uuid.New()
+
+	if err := svc.SaveDraft(ctx, domain.SaveInspectionDraftParams{UserID: userID, Title: strPtr("Roof check"), City: strPtr("Boise")}); err != nil {
+		t.Fatalf("first save: %v", err)
+	}
+	// Later autosave sends only the field that changed
+	if err := svc.SaveDraft(ctx, domain.SaveInspectionDraftParams{UserID: userID, InspectorNotes: strPtr("Ladder missing feet")}); err != nil {
+		t.Fatalf("second save: %v", err)
+	}
+
+	draft, err := svc.GetDraft(ctx, userID)
+	if err != nil {
+		t.Fatalf("GetDraft: %v", err)
+	}
+	if draft.Title != "Roof check" || draft.City != "Boise" {
+		t.Errorf("expected earlier fields kept, got title %q city %q", draft.Title, draft.City)
+	}
+	if draft.InspectorNotes != "Ladder missing feet" {
+		t.Errorf("expected notes saved, got %q", draft.InspectorNotes)
+	}
+	if draft.State != "" || draft.AddressLine1 != "" {
+		t.Errorf("expected unsent fields empty, got state %q line1 %q", draft.State, draft.AddressLine1)
+	}
+}
+
+func TestSaveDraft_EmptyStringClearsField(t *testing.T) {
+	svc := newDraftTestService()
+	ctx := context.Background()
+	userID := uuid.New()
+
+	_ = svc.SaveDraft(ctx, domain.SaveInspectionDraftParams{UserID: userID, Title: strPtr("Roof check"), City: strPtr("Boise")})
+	if err := svc.SaveDraft(ctx, domain.SaveInspectionDraftParams{UserID: userID, City: strPtr("")}); err != nil {
+		t.Fatalf("SaveDraft: %v", err)
+	}
+
+	draft, err := svc.GetDraft(ctx, userID)
+	if err != nil {
+		t.Fatalf("GetDraft: %v", err)
+	}
+	if draft.City != "" {
+		t.Errorf("expected city cleared, got %q", draft.City)
+	}
+	if draft.Title != "Roof check" {
+		t.Errorf("expected title kept, got %q", draft.Title)
+	}
+}
+
+func TestSaveDraft_OneDraftPerUser(t *testing.T) {
+	svc := newDraftTestService()
+	ctx := context.Background()
+	alice, bob := uuid.New(), uuid.New()
+
+	_ = svc.SaveDraft(ctx, domain.SaveInspectionDraftParams{UserID: alice, Title: strPtr("Alice's walk")})
+	_ = svc.SaveDraft(ctx, domain.SaveInspectionDraftParams{UserID: bob, Title: strPtr("Bob's walk")})
+
+	draft, err := svc.GetDraft(ctx, alice)
+	if err != nil {
+		t.Fatalf("GetDraft: %v", err)
+	}
+	if draft.Title != "Alice's walk" {
+		t.Errorf("expected Alice's draft untouched, got %q", draft.Title)
+	}
+}
+
+func TestSaveDraft_RejectsOversizedField(t *testing.T) {
+	svc := newDraftTestService()
+	long := string(make([]byte, maxDraftFieldLength+1))
+
+	err := svc.SaveDraft(context.Background(), domain.SaveInspectionDraftParams{UserID: uuid.New(), InspectorNotes: &long})
+
+	if domain.ErrorCode(err) != domain.EINVALID {
+		t.Errorf("expected EINVALID, got %v", err)
+	}
+}
+
+func TestDeleteDraft(t *testing.T) {
+	svc := newDraftTestService()
+	ctx := context.Background()
+	userID := uuid.New()
+
+	if _, err := svc.GetDraft(ctx, userID); domain.ErrorCode(err) != domain.ENOTFOUND {
+		t.Fatalf("expected ENOTFOUND without a draft, got %v", err)
+	}
+
+	_ = svc.SaveDraft(ctx, domain.SaveInspectionDraftParams{UserID: userID, Title: strPtr("Roof check")})
+	if err := svc.DeleteDraft(ctx, userID); err != nil {
+		t.Fatalf("DeleteDraft: %v", err)
+	}
+
+	if _, err := svc.GetDraft(ctx, userID); domain.ErrorCode(err) != domain.ENOTFOUND {
+		t.Errorf("expected draft deleted, got %v", err)
+	}
+}
@@ -0,0 +1,122 @@
+// Package service contains the business logic layer.
+//
+// This file implements the inspection event service, which records the
+// activity shown in an inspection's live feed while it is analyzed.
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// Interface Definition
+// =============================================================================
+
+// InspectionEventService defines operations for an inspection's activity feed.
+type InspectionEventService interface {
+	// Record adds an event to the inspection's feed. The feed is informational,
+	// so failures are logged rather than returned and never fail the caller.
+	Record(ctx context.Context, inspectionID uuid.UUID, kind domain.InspectionEventKind, data map[string]string)
+
+	// List returns the events of an inspection the user may work on, oldest
+	// first. With after > 0 only events with a greater ID are returned;
+	// otherwise the most recent events are. At most
+	// domain.InspectionEventPageSize events are returned.
+	List(ctx context.Context, inspectionID, userID uuid.UUID, after int64) ([]domain.InspectionEvent, error)
+}
+
+// =============================================================================
+// Implementation
+// =============================================================================
+
+// inspectionEventService implements the InspectionEventService interface.
+type inspectionEventService struct {
+	queries *repository.Queries
+	access  orgAccess
+	logger  *slog.Logger
+}
+
+// NewInspectionEventService creates a new InspectionEventService.
+func NewInspectionEventService(queries *repository.Queries, logger *slog.Logger) InspectionEventService {
+	return &inspectionEventService{
+		queries: queries,
+		access:  orgAccess{queries: queries},
+		logger:  logger,
+	}
+}
+
+// =============================================================================
+// Record
+// =============================================================================
+
+// Record adds an event to the inspection's feed.
+func (s *inspectionEventService) Record(ctx context.Context, inspectionID uuid.UUID, kind domain.InspectionEventKind, data map[string]string) {
+	values, err := marshalAuditValues(data)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to encode inspection event", "error", err, "inspection_id", inspectionID, "kind", kind)
+		return
+	}
+
+	if err := s.queries.CreateInspectionEvent(ctx, repository.CreateInspectionEventParams{
+		InspectionID: inspectionID,
+		Kind:         string(kind),
+		Data:         values,
+	}); err != nil {
+		s.logger.ErrorContext(ctx, "failed to record inspection event", "error", err, "inspection_id", inspectionID, "kind", kind)
+	}
+}
+
+// =============================================================================
+// List
+// =============================================================================
+
+// List returns the inspection's events, oldest first.
+func (s *inspectionEventService) List(ctx context.Context, inspectionID, userID uuid.UUID, after int64) ([]domain.InspectionEvent, error) {
+	const op = "inspection_event.list"
+
+	ownerID, err := s.access.inspectionOwner(ctx, inspectionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []repository.InspectionEvent
+	if after > 0 {
+		rows, err = s.queries.ListInspectionEventsAfterID(ctx, repository.ListInspectionEventsAfterIDParams{
+			InspectionID: inspectionID,
+			UserID:       ownerID,
+			ID:           after,
+			Limit:        domain.InspectionEventPageSize,
+		})
+	} else {
+		rows, err = s.queries.ListRecentInspectionEvents(ctx, repository.ListRecentInspectionEventsParams{
+			InspectionID: inspectionID,
+			UserID:       ownerID,
+			Limit:        domain.InspectionEventPageSize,
+		})
+		// Recent events come newest first; the feed reads oldest first
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to list inspection events")
+	}
+
+	events := make([]domain.InspectionEvent, 0, len(rows))
+	for _, row := range rows {
+		events = append(events, domain.InspectionEvent{
+			ID:           row.ID,
+			InspectionID: row.InspectionID,
+			Kind:         domain.InspectionEventKind(row.Kind),
+			Data:         unmarshalAuditValues(row.Data),
+			CreatedAt:    row.CreatedAt,
+		})
+	}
+
+	return events, nil
+}
@@ -0,0 +1,611 @@
+package service
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/DukeRupert/lukaut/internal/testutil/fakedb"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// In-Memory Inspections Database
+// =============================================================================
+
+// fakeInspectionRow holds the columns the geocoding, weather, and template
+// tests care about.
+type fakeInspectionRow struct {
+	id, userID           uuid.UUID
+	clientID             *uuid.UUID
+	title, notes         string
+	line1, line2         string
+	city, state, postal  string
+	date                 time.Time
+	latitude, longitude  *float64
+	weather, temperature string
+	version              int64
+	locationWrites       int
+	assignedTo           *uuid.UUID
+	isTemplate           bool
+	templateName         string
+	legalHold            bool
+	status               domain.InspectionStatus // Empty is draft
+	pendingViolations    int64
+	reports              int64 // Generated reports
+}
+
+// fakeInspectionsDB answers the sqlc queries used to create and update an
+// inspection, dispatching on the "-- name:" header.
+type fakeInspectionsDB struct {
+	inspections map[uuid.UUID]*fakeInspectionRow
+	orgs        map[uuid.UUID]uuid.UUID // Organization of each member; others work alone
+	names       map[uuid.UUID]string    // User names, for assignees and owners
+	clients     map[uuid.UUID]uuid.UUID // Owner of each client
+	comments    []*fakeCommentRow       // Inspection comments, in the order posted
+	reportRows  []fakeReportRow         // Generated reports, for report listings
+	failQuery   string                  // Query that fails, by query name
+}
+
+// fakeReportRow holds a generated report of an inspection.
+type fakeReportRow struct {
+	id, inspectionID, userID uuid.UUID
+}
+
+// fakeCommentRow holds an inspection comment.
+type fakeCommentRow struct {
+	id, inspectionID, authorID uuid.UUID
+	body                       string
+	createdAt                  time.Time
+}
+
+// canAccess mirrors the organization check of GetInspectionOwnerIDForUser:
+// the owner, or a member of the owner's organization.
+func (f *fakeInspectionsDB) canAccess(row *fakeInspectionRow, userID uuid.UUID) bool {
+	if row.userID == userID {
+		return true
+	}
+	org, ok := f.orgs[userID]
+	return ok && f.orgs[row.userID] == org
+}
+
+func (f *fakeInspectionsDB) Query(q fakedb.Query) (*fakedb.Rows, error) {
+	args := q.Args
+
+	if q.Name == f.failQuery {
+		return nil, fmt.Errorf("fakeInspectionsDB: %s failed", f.failQuery)
+	}
+
+	switch q.Name {
+	case "CreateInspection":
+		row := &fakeInspectionRow{
+			id:          uuid.New(),
+			userID:      uuid.MustParse(args[0].Value.(string)),
+			title:       args[2].Value.(string),
+			date:        args[4].Value.(time.Time),
+			weather:     nullableString(args[5].Value),
+			temperature: nullableString(args[6].Value),
+			notes:       nullableString(args[7].Value),
+			line1:       args[8].Value.(string),
+			line2:       nullableString(args[9].Value),
+			city:        args[10].Value.(string),
+			state:       args[11].Value.(string),
+			postal:      args[12].Value.(string),
+			version:     1,
+		}
+		if id, ok := args[1].Value.(string); ok {
+			clientID := uuid.MustParse(id)
+			row.clientID = &clientID
+		}
+		f.inspections[row.id] = row
+		return inspectionRows(row), nil
+	case "GetClientByIDAndUserID":
+		// Mirrors the WHERE clause: only the owner's client matches
+		id := uuid.MustParse(args[0].Value.(string))
+		if owner, ok := f.clients[id]; ok && owner.String() == args[1].Value.(string) {
+			return &fakedb.Rows{Columns: 13, Values: [][]driver.Value{{
+				id.String(), owner.String(), "Acme Builders", nil, nil, nil, nil, nil, nil, nil, nil, time.Now(), time.Now(),
+			}}}, nil
+		}
+		return &fakedb.Rows{Columns: 13}, nil
+	case "ListInspectionTemplatesByUserID":
+		// Ignores the ordering
+		rows := &fakedb.Rows{Columns: 10}
+		for _, r := range f.inspections {
+			if r.userID.String() != args[0].Value.(string) || !r.isTemplate {
+				continue
+			}
+			rows.Values = append(rows.Values, []driver.Value{
+				r.id.String(), r.templateName, r.title, r.line1, nil, r.city, r.state, r.postal, time.Now(), "",
+			})
+		}
+		return rows, nil
+	case "GetInspectionByIDAndUserID":
+		// Mirrors the WHERE clause: only the owner's inspection matches
+		if row, ok := f.inspections[uuid.MustParse(args[0].Value.(string))]; ok && row.userID.String() == args[1].Value.(string) {
+			return inspectionRows(row), nil
+		}
+		return &fakedb.Rows{Columns: 23}, nil
+	case "CreateInspectionComment":
+		comment := &fakeCommentRow{
+			id:           uuid.New(),
+			inspectionID: uuid.MustParse(args[0].Value.(string)),
+			authorID:     uuid.MustParse(args[1].Value.(string)),
+			body:         args[2].Value.(string),
+			createdAt:    time.Now(),
+		}
+		f.comments = append(f.comments, comment)
+		return &fakedb.Rows{Columns: 5, Values: [][]driver.Value{{
+			comment.id.String(), comment.inspectionID.String(), comment.authorID.String(), comment.body, comment.createdAt,
+		}}}, nil
+	case "ListInspectionComments":
+		// Mirrors the owner check; comments are stored oldest first
+		rows := &fakedb.Rows{Columns: 6}
+		row, ok := f.inspections[uuid.MustParse(args[0].Value.(string))]
+		if !ok || row.userID.String() != args[1].Value.(string) {
+			return rows, nil
+		}
+		for _, c := range f.comments {
+			if c.inspectionID == row.id {
+				rows.Values = append(rows.Values, []driver.Value{
+					c.id.String(), c.inspectionID.String(), c.authorID.String(), c.body, c.createdAt, f.names[c.authorID],
+				})
+			}
+		}
+		return rows, nil
+	case "CountViolationsByStatus":
+		var n int64
+		if row, ok := f.inspections[uuid.MustParse(args[0].Value.(string))]; ok && args[1].Value.(string) == string(domain.ViolationStatusPending) {
+			n = row.pendingViolations
+		}
+		return &fakedb.Rows{Columns: 1, Values: [][]driver.Value{{n}}}, nil
+	case "CountReportsByInspectionID":
+		var n int64
+		if row, ok := f.inspections[uuid.MustParse(args[0].Value.(string))]; ok {
+			n = row.reports
+		}
+		return &fakedb.Rows{Columns: 1, Values: [][]driver.Value{{n}}}, nil
+	case "GetInspectionOwnerIDForUser":
+		if row, ok := f.inspections[uuid.MustParse(args[0].Value.(string))]; ok && f.canAccess(row, uuid.MustParse(args[1].Value.(string))) {
+			return &fakedb.Rows{Columns: 1, Values: [][]driver.Value{{row.userID.String()}}}, nil
+		}
+		return &fakedb.Rows{Columns: 1}, nil
+	case "GetInspectionWithClientByIDAndUserID":
+		row, ok := f.inspections[uuid.MustParse(args[0].Value.(string))]
+		if !ok || row.userID.String() != args[1].Value.(string) {
+			return &fakedb.Rows{Columns: 22}, nil
+		}
+		values := make([]driver.Value, 22)
+		values[0] = row.id.String()
+		values[1] = row.userID.String()
+		values[3] = "Site walk"
+		values[4] = string(domain.InspectionStatusDraft)
+		if row.status != "" {
+			values[4] = string(row.status)
+		}
+		values[5] = time.Now()
+		values[9] = row.line1
+		values[11] = row.city
+		values[12] = row.state
+		values[13] = row.postal
+		values[16] = row.version
+		values[18] = row.isTemplate
+		values[20] = ""
+		values[21] = ""
+		if row.assignedTo != nil {
+			values[17] = row.assignedTo.String()
+			values[21] = f.names[*row.assignedTo]
+		}
+		return &fakedb.Rows{Columns: 22, Values: [][]driver.Value{values}}, nil
+	case "GetUserByID":
+		id := uuid.MustParse(args[0].Value.(string))
+		name, ok := f.names[id]
+		if !ok {
+			return &fakedb.Rows{Columns: 29}, nil
+		}
+		values := make([]driver.Value, 29)
+		values[0] = id.String()
+		values[1] = id.String() + "@example.com"
+		values[2] = "hash"
+		values[3] = name
+		values[26] = int64(0)
+		return &fakedb.Rows{Columns: 29, Values: [][]driver.Value{values}}, nil
+	case "ListConfirmedViolationsByInspectionIDAndUserID":
+		return &fakedb.Rows{Columns: 14}, nil
+	case "GetReportByID", "ListReportsByInspectionID":
+		// $1 is the report ID or the inspection ID
+		rows := &fakedb.Rows{Columns: 10}
+		for _, r := range f.reportRows {
+			if r.id.String() == args[0].Value.(string) || r.inspectionID.String() == args[0].Value.(string) {
+				rows.Values = append(rows.Values, []driver.Value{
+					r.id.String(), r.inspectionID.String(), r.userID.String(), nil, nil, int64(0), time.Now(), nil, nil, "{}",
+				})
+			}
+		}
+		return rows, nil
+	case "CountOrganizationInspectionsByUserID":
+		var n int64
+		for _, r := range f.inspections {
+			if f.canAccess(r, uuid.MustParse(args[0].Value.(string))) {
+				n++
+			}
+		}
+		return &fakedb.Rows{Columns: 1, Values: [][]driver.Value{{n}}}, nil
+	case "ListOrganizationInspectionsWithClientByUserID":
+		// Ignores paging and sorting
+		rows := &fakedb.Rows{Columns: 20}
+		for _, r := range f.inspections {
+			if !f.canAccess(r, uuid.MustParse(args[0].Value.(string))) {
+				continue
+			}
+			assignee := ""
+			if r.assignedTo != nil {
+				assignee = f.names[*r.assignedTo]
+			}
+			rows.Values = append(rows.Values, []driver.Value{
+				r.id.String(), r.userID.String(), nil, "Site walk", string(domain.InspectionStatusDraft), time.Now(),
+				nil, nil, nil, r.line1, nil, r.city, r.state, r.postal, time.Now(), time.Now(),
+				"", f.names[r.userID], assignee, int64(0),
+			})
+		}
+		return rows, nil
+	case "ListInspectionLocationsByUserID":
+		// Returns every row for the user, ignoring the coordinate filter, so
+		// tests can check that the service drops rows without coordinates.
+		rows := &fakedb.Rows{Columns: 9}
+		userID := args[0].Value.(string)
+		for _, r := range f.inspections {
+			if r.userID.String() != userID {
+				continue
+			}
+			var lat, lng driver.Value
+			if r.latitude != nil {
+				lat, lng = *r.latitude, *r.longitude
+			}
+			rows.Values = append(rows.Values, []driver.Value{
+				r.id.String(), "Site walk", string(domain.InspectionStatusDraft), time.Now(), r.line1, r.city, r.state, lat, lng,
+			})
+		}
+		return rows, nil
+	}
+	return nil, fmt.Errorf("fakeInspectionsDB: unexpected query %q", q.Name)
+}
+
+func (f *fakeInspectionsDB) Exec(q fakedb.Query) (int64, error) {
+	args := q.Args
+
+	if q.Name == "DeleteInspectionCommentByAuthor" {
+		// Mirrors the WHERE clause: the comment, on that inspection, by that author
+		for i, c := range f.comments {
+			if c.id.String() == args[0].Value.(string) && c.inspectionID.String() == args[1].Value.(string) && c.authorID.String() == args[2].Value.(string) {
+				f.comments = append(f.comments[:i], f.comments[i+1:]...)
+				return 1, nil
+			}
+		}
+		return 0, nil
+	}
+
+	// Mirrors the WHERE clauses: only the owner's inspection matches
+	row, ok := f.inspections[uuid.MustParse(args[0].Value.(string))]
+	if !ok || row.userID.String() != args[1].Value.(string) {
+		return 0, nil
+	}
+
+	switch q.Name {
+	case "UpdateInspectionByIDAndUserID":
+		// Mirrors the WHERE clause: only the version the edit was based on matches
+		if args[13].Value.(int64) != row.version {
+			return 0, nil
+		}
+		row.version++
+		row.line1 = args[8].Value.(string)
+		row.city = args[10].Value.(string)
+		row.state = args[11].Value.(string)
+		row.postal = args[12].Value.(string)
+		return 1, nil
+	case "UpdateInspectionStatusByIDAndUserID":
+		row.status = domain.InspectionStatus(args[2].Value.(string))
+		return 1, nil
+	case "UpdateInspectionAssigneeByIDAndUserID":
+		row.assignedTo = nil
+		if id, ok := args[2].Value.(string); ok {
+			assignee := uuid.MustParse(id)
+			row.assignedTo = &assignee
+		}
+		return 1, nil
+	case "UpdateInspectionLocationByIDAndUserID":
+		row.locationWrites++
+		row.latitude, row.longitude = nil, nil
+		if lat, ok := args[2].Value.(float64); ok {
+			lng := args[3].Value.(float64)
+			row.latitude, row.longitude = &lat, &lng
+		}
+		return 1, nil
+	case "UpdateInspectionTemplateByIDAndUserID":
+		row.isTemplate = args[2].Value.(bool)
+		row.templateName = nullableString(args[3].Value)
+		return 1, nil
+	case "UpdateInspectionWeatherIfEmptyByIDAndUserID":
+		// Mirrors the WHERE clause and CASE expressions
+		if row.weather != "" && row.temperature != "" {
+			return 0, nil
+		}
+		if row.weather == "" {
+			row.weather = nullableString(args[2].Value)
+		}
+		if row.temperature == "" {
+			row.temperature = nullableString(args[3].Value)
+		}
+		return 1, nil
+	}
+	return 0, fmt.Errorf("fakeInspectionsDB: unexpected exec %q", q.Name)
+}
+
+// inspectionRows returns an inspections row in repository.Inspection column order.
+func inspectionRows(r *fakeInspectionRow) *fakedb.Rows {
+	values := make([]driver.Value, 23)
+	values[0] = r.id.String()
+	values[1] = r.userID.String()
+	values[2] = r.title
+	values[3] = string(domain.InspectionStatusDraft)
+	if r.status != "" {
+		values[3] = string(r.status)
+	}
+	values[4] = r.date
+	if r.weather != "" {
+		values[5] = r.weather
+	}
+	if r.temperature != "" {
+		values[6] = r.temperature
+	}
+	if r.notes != "" {
+		values[7] = r.notes
+	}
+	values[8] = time.Now()
+	values[9] = time.Now()
+	values[10] = r.line1
+	if r.line2 != "" {
+		values[11] = r.line2
+	}
+	values[12] = r.city
+	values[13] = r.state
+	values[14] = r.postal
+	if r.latitude != nil {
+		values[16] = *r.latitude
+		values[17] = *r.longitude
+	}
+	if r.clientID != nil {
+		values[15] = r.clientID.String()
+	}
+	values[18] = r.version
+	if r.assignedTo != nil {
+		values[19] = r.assignedTo.String()
+	}
+	values[20] = r.isTemplate
+	if r.templateName != "" {
+		values[21] = r.templateName
+	}
+	values[22] = r.legalHold
+	return &fakedb.Rows{Columns: 23, Values: [][]driver.Value{values}}
+}
+
+// nullableString returns the string bound for a nullable text parameter.
+func nullableString(v driver.Value) string {
+	s, _ := v.(string)
+	return s
+}
+
+// fakeGeocodeEnqueuer records geocoding jobs instead of queueing them.
+type fakeGeocodeEnqueuer struct {
+	mu        sync.Mutex
+	addresses map[uuid.UUID][]string
+	err       error
+}
+
+func (e *fakeGeocodeEnqueuer) EnqueueGeocodeInspection(ctx context.Context, inspectionID, userID uuid.UUID, address string) (repository.Job, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.err != nil {
+		return repository.Job{}, e.err
+	}
+	if e.addresses == nil {
+		e.addresses = map[uuid.UUID][]string{}
+	}
+	e.addresses[inspectionID] = append(e.addresses[inspectionID], address)
+	return repository.Job{ID: uuid.New()}, nil
+}
+
+func newGeocodeTestInspectionService(f *fakeInspectionsDB, geocoding GeocodeEnqueuer) InspectionService {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewInspectionService(repository.New(fakedb.Open(f)), nil, nil, nil, geocoding, nil, logger)
+}
+
+func geocodeTestCreateParams(userID uuid.UUID) domain.CreateInspectionParams {
+	return domain.CreateInspectionParams{
+		UserID:         userID,
+		Title:          "Site walk",
+		InspectionDate: time.Now(),
+		AddressLine1:   " 100  Main St",
+		City:           "Boise",
+		State:          "ID",
+		PostalCode:     "83702",
+	}
+}
+
+// =============================================================================
+// Inspection Geocoding Tests
+// =============================================================================
+
+func TestInspectionCreate_EnqueuesGeocoding(t *testing.T) {
+	f := &fakeInspectionsDB{inspections: map[uuid.UUID]*fakeInspectionRow{}}
+	enqueuer := &fakeGeocodeEnqueuer{}
+	svc := newGeocodeTestInspectionService(f, enqueuer)
+
+	inspection, err := svc.Create(context.Background(), geocodeTestCreateParams(uuid.New()))
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	// The job receives the normalized single-line address
+	got := enqueuer.addresses[inspection.ID]
+	if len(got) != 1 || got[0] != "100 Main St, Boise, ID 83702" {
+		t.Errorf("expected one geocoding job for the normalized address, got %q", got)
+	}
+	if inspection.HasLocation() {
+		t.Error("expected no coordinates before the job runs")
+	}
+}
+
+func TestInspectionCreate_GeocodingUnavailableStillSaves(t *testing.T) {
+	for name, enqueuer := range map[string]GeocodeEnqueuer{
+		"enqueue fails": &fakeGeocodeEnqueuer{err: errors.New("queue unavailable")},
+		"disabled":      nil,
+	} {
+		t.Run(name, func(t *testing.T) {
+			f := &fakeInspectionsDB{inspections: map[uuid.UUID]*fakeInspectionRow{}}
+			svc := newGeocodeTestInspectionService(f, enqueuer)
+
+			inspection, err := svc.Create(context.Background(), geocodeTestCreateParams(uuid.New()))
+			if err != nil {
+				t.Fatalf("expected create to succeed without geocoding, got %v", err)
+			}
+			if inspection.HasLocation() {
+				t.Error("expected no coordinates")
+			}
+			if writes := f.inspections[inspection.ID].locationWrites; writes != 0 {
+				t.Errorf("expected no location writes, got %d", writes)
+			}
+		})
+	}
+}
+
+func TestInspectionUpdate_AddressChangeClearsLocationAndEnqueues(t *testing.T) {
+	ctx := context.Background()
+	f := &fakeInspectionsDB{inspections: map[uuid.UUID]*fakeInspectionRow{}}
+	enqueuer := &fakeGeocodeEnqueuer{}
+	svc := newGeocodeTestInspectionService(f, enqueuer)
+
+	userID := uuid.New()
+	inspection, err := svc.Create(ctx, geocodeTestCreateParams(userID))
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	// Simulate the job having stored coordinates for the original address
+	lat, lng := 43.6150, -116.2023
+	f.inspections[inspection.ID].latitude = &lat
+	f.inspections[inspection.ID].longitude = &lng
+
+	update := domain.UpdateInspectionParams{
+		ID:             inspection.ID,
+		UserID:         userID,
+		Title:          "Site walk",
+		InspectionDate: time.Now(),
+		AddressLine1:   "200 Main St",
+		City:           "Boise",
+		State:          "ID",
+		PostalCode:     "83702",
+		Version:        inspection.Version,
+	}
+	if err := svc.Update(ctx, update); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	if row := f.inspections[inspection.ID]; row.latitude != nil {
+		t.Errorf("expected stale coordinates to be cleared, got %v", *row.latitude)
+	}
+	got := enqueuer.addresses[inspection.ID]
+	if len(got) != 2 || got[1] != "200 Main St, Boise, ID 83702" {
+		t.Errorf("expected a geocoding job for the new address, got %q", got)
+	}
+}
+
+func TestInspectionUpdate_UnchangedGeocodedAddressSkipsGeocoding(t *testing.T) {
+	ctx := context.Background()
+	f := &fakeInspectionsDB{inspections: map[uuid.UUID]*fakeInspectionRow{}}
+	enqueuer := &fakeGeocodeEnqueuer{}
+	svc := newGeocodeTestInspectionService(f, enqueuer)
+
+	userID := uuid.New()
+	params := geocodeTestCreateParams(userID)
+	inspection, err := svc.Create(ctx, params)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	lat, lng := 43.6150, -116.2023
+	f.inspections[inspection.ID].latitude = &lat
+	f.inspections[inspection.ID].longitude = &lng
+
+	err = svc.Update(ctx, domain.UpdateInspectionParams{
+		ID:             inspection.ID,
+		UserID:         userID,
+		Title:          "Renamed walk",
+		InspectionDate: time.Now(),
+		AddressLine1:   params.AddressLine1,
+		City:           params.City,
+		State:          params.State,
+		PostalCode:     params.PostalCode,
+		Version:        inspection.Version,
+	})
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	row := f.inspections[inspection.ID]
+	if row.latitude == nil || row.locationWrites != 0 {
+		t.Errorf("expected coordinates to be kept, got %v after %d writes", row.latitude, row.locationWrites)
+	}
+	if got := enqueuer.addresses[inspection.ID]; len(got) != 1 {
+		t.Errorf("expected only the create to enqueue geocoding, got %q", got)
+	}
+}
+
+func TestInspectionListLocations_OnlyGeocodedInspections(t *testing.T) {
+	ctx := context.Background()
+	f := &fakeInspectionsDB{inspections: map[uuid.UUID]*fakeInspectionRow{}}
+	svc := newGeocodeTestInspectionService(f, nil)
+
+	userID := uuid.New()
+	geocoded, err := svc.Create(ctx, geocodeTestCreateParams(userID))
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	lat, lng := 43.6150, -116.2023
+	f.inspections[geocoded.ID].latitude = &lat
+	f.inspections[geocoded.ID].longitude = &lng
+	unrecognized := geocodeTestCreateParams(userID)
+	unrecognized.AddressLine1 = "9 Nowhere Rd"
+	if _, err := svc.Create(ctx, unrecognized); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	// Another user's geocoded inspection
+	other, err := svc.Create(ctx, geocodeTestCreateParams(uuid.New()))
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	f.inspections[other.ID].latitude = &lat
+	f.inspections[other.ID].longitude = &lng
+
+	locations, err := svc.ListLocations(ctx, userID)
+	if err != nil {
+		t.Fatalf("ListLocations failed: %v", err)
+	}
+
+	if len(locations) != 1 {
+		t.Fatalf("expected only the geocoded inspection, got %+v", locations)
+	}
+	loc := locations[0]
+	if loc.ID != geocoded.ID || loc.Latitude != 43.6150 || loc.Longitude != -116.2023 {
+		t.Errorf("unexpected location: %+v", loc)
+	}
+	if loc.Address != " 100  Main St, Boise, ID" {
+		t.Errorf("unexpected address: %q", loc.Address)
+	}
+}
@@ -0,0 +1,143 @@
+// Package service contains the business logic layer.
+//
+// This file implements inspection templates: an inspection kept as a
+// template is the starting point for new inspections of the same site or
+// scope, so standard setups aren't retyped for every visit.
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// SaveAsTemplate
+// =============================================================================
+
+// SaveAsTemplate keeps the inspection as a template under params.Name.
+func (s *inspectionService) SaveAsTemplate(ctx context.Context, params domain.SaveInspectionTemplateParams) error {
+	const op = "inspection.save_as_template"
+
+	name := strings.TrimSpace(params.Name)
+	if name == "" {
+		return domain.Invalid(op, "template name is required")
+	}
+	if len(name) > domain.MaxTemplateNameLength {
+		return domain.Invalid(op, "template name must be 100 characters or less")
+	}
+
+	n, err := s.queries.UpdateInspectionTemplateByIDAndUserID(ctx, repository.UpdateInspectionTemplateByIDAndUserIDParams{
+		ID:           params.ID,
+		UserID:       params.UserID,
+		IsTemplate:   true,
+		TemplateName: domain.ToNullString(name),
+	})
+	if err != nil {
+		return domain.Internal(err, op, "failed to save template")
+	}
+	if n == 0 {
+		return domain.NotFound(op, "inspection", params.ID.String())
+	}
+
+	s.logger.InfoContext(ctx, "inspection saved as template",
+		"inspection_id", params.ID,
+		"user_id", params.UserID,
+	)
+	return nil
+}
+
+// =============================================================================
+// RemoveTemplate
+// =============================================================================
+
+// RemoveTemplate stops keeping the inspection as a template.
+func (s *inspectionService) RemoveTemplate(ctx context.Context, id, userID uuid.UUID) error {
+	const op = "inspection.remove_template"
+
+	n, err := s.queries.UpdateInspectionTemplateByIDAndUserID(ctx, repository.UpdateInspectionTemplateByIDAndUserIDParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		return domain.Internal(err, op, "failed to remove template")
+	}
+	if n == 0 {
+		return domain.NotFound(op, "inspection", id.String())
+	}
+	return nil
+}
+
+// =============================================================================
+// ListTemplates
+// =============================================================================
+
+// ListTemplates returns the user's templates.
+func (s *inspectionService) ListTemplates(ctx context.Context, userID uuid.UUID) ([]domain.InspectionTemplate, error) {
+	const op = "inspection.list_templates"
+
+	rows, err := s.queries.ListInspectionTemplatesByUserID(ctx, userID)
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to list templates")
+	}
+
+	templates := make([]domain.InspectionTemplate, 0, len(rows))
+	for _, row := range rows {
+		templates = append(templates, domain.InspectionTemplate{
+			ID:           row.ID,
+			Name:         domain.NullStringValue(row.TemplateName),
+			Title:        row.Title,
+			ClientName:   row.ClientName,
+			AddressLine1: row.AddressLine1,
+			AddressLine2: domain.NullStringValue(row.AddressLine2),
+			City:         row.City,
+			State:        row.State,
+			PostalCode:   row.PostalCode,
+			UpdatedAt:    row.UpdatedAt.Time,
+		})
+	}
+	return templates, nil
+}
+
+// =============================================================================
+// CreateFromTemplate
+// =============================================================================
+
+// CreateFromTemplate creates a draft inspection from the user's template.
+// The new inspection goes through Create, so it is validated, geocoded, and
+// has its weather filled in like one entered by hand.
+func (s *inspectionService) CreateFromTemplate(ctx context.Context, templateID, userID uuid.UUID) (*domain.Inspection, error) {
+	const op = "inspection.create_from_template"
+
+	row, err := s.queries.GetInspectionByIDAndUserID(ctx, repository.GetInspectionByIDAndUserIDParams{
+		ID:     templateID,
+		UserID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.NotFound(op, "template", templateID.String())
+		}
+		return nil, domain.Internal(err, op, "failed to get template")
+	}
+	if !row.IsTemplate {
+		return nil, domain.NotFound(op, "template", templateID.String())
+	}
+
+	inspection, err := s.Create(ctx, s.rowToInspection(row).TemplateCreateParams(userID, time.Now()))
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.InfoContext(ctx, "inspection created from template",
+		"inspection_id", inspection.ID,
+		"template_id", templateID,
+		"user_id", userID,
+	)
+	return inspection, nil
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// Inspection Template Tests
+// =============================================================================
+
+// newTemplateTestDB returns a database holding one of owner's inspections,
+// filled in as after a site visit, for a client of owner's.
+func newTemplateTestDB(owner uuid.UUID) (*fakeInspectionsDB, *fakeInspectionRow) {
+	clientID := uuid.New()
+	lat, lng := 43.6, -116.2
+	assignee := uuid.New()
+	row := &fakeInspectionRow{
+		id:          uuid.New(),
+		userID:      owner,
+		clientID:    &clientID,
+		title:       "Monthly scaffold check",
+		notes:       "Check tie-ins on every lift.",
+		line1:       "100 Main St",
+		line2:       "Tower B",
+		city:        "Boise",
+		state:       "ID",
+		postal:      "83702",
+		date:        time.Now().AddDate(0, -2, 0),
+		latitude:    &lat,
+		longitude:   &lng,
+		weather:     "Overcast",
+		temperature: "41°F",
+		version:     7,
+		assignedTo:  &assignee,
+	}
+	f := &fakeInspectionsDB{
+		inspections: map[uuid.UUID]*fakeInspectionRow{row.id: row},
+		clients:     map[uuid.UUID]uuid.UUID{clientID: owner},
+	}
+	return f, row
+}
+
+func TestInspectionCreateFromTemplate_CopiesOnlyTemplateFields(t *testing.T) {
+	owner := uuid.New()
+	f, template := newTemplateTestDB(owner)
+	svc := newGeocodeTestInspectionService(f, nil)
+	ctx := context.Background()
+
+	if err := svc.SaveAsTemplate(ctx, domain.SaveInspectionTemplateParams{ID: template.id, UserID: owner, Name: " Scaffold "}); err != nil {
+		t.Fatalf("SaveAsTemplate failed: %v", err)
+	}
+	if template.templateName != "Scaffold" {
+		t.Errorf("expected the trimmed name to be saved, got %q", template.templateName)
+	}
+
+	inspection, err := svc.CreateFromTemplate(ctx, template.id, owner)
+	if err != nil {
+		t.Fatalf("CreateFromTemplate failed: %v", err)
+	}
+	created := f.inspections[inspection.ID]
+	if created == nil || created.id == template.id {
+		t.Fatal("expected a new inspection")
+	}
+
+	// Copied
+	if created.title != template.title || created.notes != template.notes {
+		t.Errorf("expected the title and notes to be copied, got %q and %q", created.title, created.notes)
+	}
+	if created.clientID == nil || *created.clientID != *template.clientID {
+		t.Errorf("expected the client to be copied, got %v", created.clientID)
+	}
+	if created.line1 != template.line1 || created.line2 != template.line2 || created.city != template.city ||
+		created.state != template.state || created.postal != template.postal {
+		t.Errorf("expected the address to be copied, got %q %q %q %q %q", created.line1, created.line2, created.city, created.state, created.postal)
+	}
+
+	// Not copied
+	if created.weather != "" || created.temperature != "" {
+		t.Errorf("expected no weather, got %q and %q", created.weather, created.temperature)
+	}
+	if created.latitude != nil || created.assignedTo != nil || created.isTemplate {
+		t.Error("expected no coordinates, assignee, or template flag")
+	}
+	if y, m, d := created.date.Date(); y != time.Now().Year() || m != time.Now().Month() || d != time.Now().Day() {
+		t.Errorf("expected the inspection to be dated today, got %s", created.date)
+	}
+	if inspection.Status != domain.InspectionStatusDraft {
+		t.Errorf("expected a draft, got %s", inspection.Status)
+	}
+}
+
+func TestInspectionCreateFromTemplate_RequiresOwnTemplate(t *testing.T) {
+	owner := uuid.New()
+	f, template := newTemplateTestDB(owner)
+	svc := newGeocodeTestInspectionService(f, nil)
+	ctx := context.Background()
+
+	// Not a template yet
+	if _, err := svc.CreateFromTemplate(ctx, template.id, owner); domain.ErrorCode(err) != domain.ENOTFOUND {
+		t.Errorf("expected ENOTFOUND for an inspection that isn't a template, got %v", err)
+	}
+
+	if err := svc.SaveAsTemplate(ctx, domain.SaveInspectionTemplateParams{ID: template.id, UserID: owner, Name: "Scaffold"}); err != nil {
+		t.Fatalf("SaveAsTemplate failed: %v", err)
+	}
+	if _, err := svc.CreateFromTemplate(ctx, template.id, uuid.New()); domain.ErrorCode(err) != domain.ENOTFOUND {
+		t.Errorf("expected ENOTFOUND for another user's template, got %v", err)
+	}
+
+	// Removed templates can't be used
+	if err := svc.RemoveTemplate(ctx, template.id, owner); err != nil {
+		t.Fatalf("RemoveTemplate failed: %v", err)
+	}
+	if _, err := svc.CreateFromTemplate(ctx, template.id, owner); domain.ErrorCode(err) != domain.ENOTFOUND {
+		t.Errorf("expected ENOTFOUND for a removed template, got %v", err)
+	}
+	if len(f.inspections) != 1 {
+		t.Errorf("expected no inspections to be created, got %d", len(f.inspections)-1)
+	}
+}
+
+func TestInspectionSaveAsTemplate_Validation(t *testing.T) {
+	owner := uuid.New()
+	f, template := newTemplateTestDB(owner)
+	svc := newGeocodeTestInspectionService(f, nil)
+	ctx := context.Background()
+
+	for _, name := range []string{"", "   ", strings.Repeat("a", domain.MaxTemplateNameLength+1)} {
+		err := svc.SaveAsTemplate(ctx, domain.SaveInspectionTemplateParams{ID: template.id, UserID: owner, Name: name})
+		if domain.ErrorCode(err) != domain.EINVALID {
+			t.Errorf("name %q: expected EINVALID, got %v", name, err)
+		}
+	}
+
+	err := svc.SaveAsTemplate(ctx, domain.SaveInspectionTemplateParams{ID: template.id, UserID: uuid.New(), Name: "Scaffold"})
+	if domain.ErrorCode(err) != domain.ENOTFOUND {
+		t.Errorf("expected ENOTFOUND for another user's inspection, got %v", err)
+	}
+	if template.isTemplate {
+		t.Error("expected the inspection not to become a template")
+	}
+}
+
+func TestInspectionListTemplates_OnlyUsersTemplates(t *testing.T) {
+	owner := uuid.New()
+	f, template := newTemplateTestDB(owner)
+	other := &fakeInspectionRow{id: uuid.New(), userID: uuid.New(), isTemplate: true, templateName: "Theirs"}
+	f.inspections[other.id] = other
+	plain := &fakeInspectionRow{id: uuid.New(), userID: owner}
+	f.inspections[plain.id] = plain
+	svc := newGeocodeTestInspectionService(f, nil)
+
+	if err := svc.SaveAsTemplate(context.Background(), domain.SaveInspectionTemplateParams{ID: template.id, UserID: owner, Name: "Scaffold"}); err != nil {
+		t.Fatalf("SaveAsTemplate failed: %v", err)
+	}
+
+	templates, err := svc.ListTemplates(context.Background(), owner)
+	if err != nil {
+		t.Fatalf("ListTemplates failed: %v", err)
+	}
+	if len(templates) != 1 || templates[0].ID != template.id || templates[0].Name != "Scaffold" {
+		t.Errorf("expected only the owner's template, got %+v", templates)
+	}
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/google/uuid"
+)
+
+// editParams returns an edit of inspection that sets the street address.
+func editParams(inspection *domain.Inspection, addressLine1 string, version int32) domain.UpdateInspectionParams {
+	return domain.UpdateInspectionParams{
+		ID:             inspection.ID,
+		UserID:         inspection.UserID,
+		Title:          "Site walk",
+		InspectionDate: time.Now(),
+		AddressLine1:   addressLine1,
+		City:           "Boise",
+		State:          "ID",
+		PostalCode:     "83702",
+		Version:        version,
+	}
+}
+
+// =============================================================================
+// Optimistic Locking Tests
+// =============================================================================
+
+func TestInspectionUpdate_StaleVersionConflicts(t *testing.T) {
+	ctx := context.Background()
+	f := &fakeInspectionsDB{inspections: map[uuid.UUID]*fakeInspectionRow{}}
+	svc := newGeocodeTestInspectionService(f, nil)
+
+	inspection, err := svc.Create(ctx, geocodeTestCreateParams(uuid.New()))
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if inspection.Version != 1 {
+		t.Fatalf("expected new inspection at version 1, got %d", inspection.Version)
+	}
+
+	// Both editors opened the form at version 1; the phone saves first
+	if err := svc.Update(ctx, editParams(inspection, "200 Phone Ave", 1)); err != nil {
+		t.Fatalf("first save failed: %v", err)
+	}
+
+	err = svc.Update(ctx, editParams(inspection, "300 Laptop Blvd", 1))
+	if domain.ErrorCode(err) != domain.ECONFLICT {
+		t.Fatalf("expected ECONFLICT for stale save, got %v", err)
+	}
+	if domain.ErrorMessage(err) != ErrMsgInspectionEditConflict {
+		t.Errorf("unexpected conflict message: %q", domain.ErrorMessage(err))
+	}
+
+	row := f.inspections[inspection.ID]
+	if row.line1 != "200 Phone Ave" || row.version != 2 {
+		t.Errorf("expected first save to be kept at version 2, got %q at %d", row.line1, row.version)
+	}
+
+	// Saving again from the latest version applies the edit
+	if err := svc.Update(ctx, editParams(inspection, "300 Laptop Blvd", 2)); err != nil {
+		t.Fatalf("save at latest version failed: %v", err)
+	}
+	if row.line1 != "300 Laptop Blvd" || row.version != 3 {
+		t.Errorf("expected retried save at version 3, got %q at %d", row.line1, row.version)
+	}
+}
+
+func TestInspectionUpdate_ConcurrentSavesOnlyOneWins(t *testing.T) {
+	ctx := context.Background()
+	f := &fakeInspectionsDB{inspections: map[uuid.UUID]*fakeInspectionRow{}}
+	svc := newGeocodeTestInspectionService(f, nil)
+
+	inspection, err := svc.Create(ctx, geocodeTestCreateParams(uuid.New()))
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	const editors = 8
+	errs := make([]error, editors)
+	var wg sync.WaitGroup
+	for i := range editors {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = svc.Update(ctx, editParams(inspection, "Editor address", inspection.Version))
+		}()
+	}
+	wg.Wait()
+
+	saved, conflicts := 0, 0
+	for _, err := range errs {
+		switch domain.ErrorCode(err) {
+		case "":
+			saved++
+		case domain.ECONFLICT:
+			conflicts++
+		default:
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	if saved != 1 || conflicts != editors-1 {
+		t.Errorf("expected 1 save and %d conflicts, got %d and %d", editors-1, saved, conflicts)
+	}
+	if v := f.inspections[inspection.ID].version; v != 2 {
+		t.Errorf("expected version 2 after one save, got %d", v)
+	}
+}
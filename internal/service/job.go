@@ -0,0 +1,82 @@
+// Package service contains the business logic layer.
+//
+// This file implements the job service, which lets users follow the
+// background jobs enqueued for them.
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// Interface Definition
+// =============================================================================
+
+// JobService reads background jobs for their owners.
+type JobService interface {
+	// Get returns a job enqueued for the user or a member of their
+	// organization, such as a report requested on an inspection shared with
+	// them. Returns ENOTFOUND if the job doesn't exist or belongs to someone
+	// else.
+	Get(ctx context.Context, jobID, userID uuid.UUID) (*domain.Job, error)
+}
+
+// =============================================================================
+// Implementation
+// =============================================================================
+
+type jobService struct {
+	queries *repository.Queries
+	logger  *slog.Logger
+}
+
+// NewJobService creates a new JobService.
+func NewJobService(queries *repository.Queries, logger *slog.Logger) JobService {
+	return &jobService{
+		queries: queries,
+		logger:  logger,
+	}
+}
+
+// Get returns a job enqueued for the user.
+func (s *jobService) Get(ctx context.Context, jobID, userID uuid.UUID) (*domain.Job, error) {
+	const op = "job.get"
+
+	row, err := s.queries.GetJobByIDAndUserID(ctx, repository.GetJobByIDAndUserIDParams{
+		ID:     jobID,
+		UserID: userID.String(),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.NotFound(op, "job", jobID.String())
+		}
+		return nil, domain.Internal(err, op, "failed to get job")
+	}
+
+	return repoJobToDomain(row), nil
+}
+
+// repoJobToDomain converts a repository.Job to a domain.Job.
+func repoJobToDomain(row repository.Job) *domain.Job {
+	job := &domain.Job{
+		ID:          row.ID,
+		Type:        row.JobType,
+		Status:      domain.JobStatus(row.Status),
+		Attempts:    int(row.Attempts),
+		MaxAttempts: int(row.MaxAttempts),
+		Error:       row.ErrorMessage.String,
+		CreatedAt:   row.CreatedAt.Time,
+		FinishedAt:  domain.NullTimeValue(row.FinishedAt),
+	}
+	if row.Result.Valid {
+		job.Result = row.Result.RawMessage
+	}
+	return job
+}
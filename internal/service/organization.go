@@ -0,0 +1,490 @@
+// Package service contains the business logic layer.
+//
+// This file implements organizations: inspectors who share their
+// inspections, joined by emailed invitations.
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/email"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// Interface Definition
+// =============================================================================
+
+// OrganizationService manages organizations and their members.
+//
+// Every user works in an organization. Users who have not created or joined
+// one work in an implicit personal organization, which is not stored; it
+// becomes a real organization, owned by the user, when they first invite
+// someone.
+type OrganizationService interface {
+	// Membership returns the organization the user works in.
+	Membership(ctx context.Context, userID uuid.UUID) (*domain.Membership, error)
+
+	// ListMembers returns the members of the user's organization, owners
+	// first. A personal organization's only member is the user.
+	ListMembers(ctx context.Context, userID uuid.UUID) ([]domain.OrganizationMember, error)
+
+	// ListPendingInvites returns the invitations of the user's organization
+	// that can still be accepted, newest first.
+	ListPendingInvites(ctx context.Context, userID uuid.UUID) ([]domain.OrganizationInvite, error)
+
+	// Invite emails an invitation to join the inviter's organization, first
+	// creating it from their personal organization if needed.
+	// Returns domain.EINVALID if the email is invalid or already a member's.
+	// Returns domain.EFORBIDDEN if the inviter is not an owner.
+	Invite(ctx context.Context, params domain.InviteMemberParams) (*domain.OrganizationInvite, error)
+
+	// GetInvite returns the invitation for a raw token.
+	// Returns domain.ENOTFOUND if the token is unknown, used, or expired.
+	GetInvite(ctx context.Context, token string) (*domain.OrganizationInvite, error)
+
+	// AcceptInvite adds the user to the invitation's organization.
+	// Returns domain.ENOTFOUND if the token is unknown, used, or expired.
+	// Returns domain.EFORBIDDEN if the invitation was sent to another address.
+	// Returns domain.ECONFLICT if the user already belongs to an organization.
+	AcceptInvite(ctx context.Context, token string, userID uuid.UUID) (*domain.Membership, error)
+
+	// RemoveMember removes a member from the owner's organization. The
+	// member's inspections stay theirs and are no longer shared.
+	// Returns domain.EFORBIDDEN if ownerID is not an owner.
+	// Returns domain.ENOTFOUND if memberID is not a member that can be removed.
+	RemoveMember(ctx context.Context, ownerID, memberID uuid.UUID) error
+}
+
+// =============================================================================
+// Implementation
+// =============================================================================
+
+type organizationService struct {
+	db         *sql.DB
+	queries    *repository.Queries
+	emailQueue email.Queue
+	logger     *slog.Logger
+}
+
+// NewOrganizationService creates a new OrganizationService.
+// Invitations are delivered through emailQueue.
+func NewOrganizationService(db *sql.DB, queries *repository.Queries, emailQueue email.Queue, logger *slog.Logger) OrganizationService {
+	return &organizationService{
+		db:         db,
+		queries:    queries,
+		emailQueue: emailQueue,
+		logger:     logger,
+	}
+}
+
+// =============================================================================
+// Membership
+// =============================================================================
+
+// Membership returns the user's organization, or their personal one.
+func (s *organizationService) Membership(ctx context.Context, userID uuid.UUID) (*domain.Membership, error) {
+	const op = "organization.membership"
+
+	row, err := s.queries.GetOrganizationMembershipByUserID(ctx, userID)
+	if err == nil {
+		return &domain.Membership{
+			OrganizationID:   row.OrganizationID,
+			OrganizationName: row.OrganizationName,
+			Role:             domain.OrganizationRole(row.Role),
+		}, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.Internal(err, op, "failed to get membership")
+	}
+
+	user, err := s.queries.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to get user")
+	}
+	return &domain.Membership{
+		OrganizationName: personalOrganizationName(user),
+		Role:             domain.OrganizationRoleOwner,
+		Personal:         true,
+	}, nil
+}
+
+// =============================================================================
+// ListMembers / ListPendingInvites
+// =============================================================================
+
+// ListMembers returns the members of the user's organization.
+func (s *organizationService) ListMembers(ctx context.Context, userID uuid.UUID) ([]domain.OrganizationMember, error) {
+	const op = "organization.list_members"
+
+	membership, err := s.Membership(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if membership.Personal {
+		user, err := s.queries.GetUserByID(ctx, userID)
+		if err != nil {
+			return nil, domain.Internal(err, op, "failed to get user")
+		}
+		return []domain.OrganizationMember{{
+			UserID: user.ID,
+			Name:   user.Name,
+			Email:  user.Email,
+			Role:   domain.OrganizationRoleOwner,
+		}}, nil
+	}
+
+	rows, err := s.queries.ListOrganizationMembers(ctx, membership.OrganizationID)
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to list members")
+	}
+
+	members := make([]domain.OrganizationMember, 0, len(rows))
+	for _, row := range rows {
+		members = append(members, domain.OrganizationMember{
+			UserID:   row.UserID,
+			Name:     row.Name,
+			Email:    row.Email,
+			Role:     domain.OrganizationRole(row.Role),
+			JoinedAt: row.CreatedAt,
+		})
+	}
+	return members, nil
+}
+
+// ListPendingInvites returns the organization's unexpired, unaccepted invitations.
+func (s *organizationService) ListPendingInvites(ctx context.Context, userID uuid.UUID) ([]domain.OrganizationInvite, error) {
+	const op = "organization.list_pending_invites"
+
+	membership, err := s.Membership(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if membership.Personal {
+		return []domain.OrganizationInvite{}, nil
+	}
+
+	rows, err := s.queries.ListPendingOrganizationInvites(ctx, membership.OrganizationID)
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to list invitations")
+	}
+
+	invites := make([]domain.OrganizationInvite, 0, len(rows))
+	for _, row := range rows {
+		invite := repoInviteToDomain(row)
+		invite.OrganizationName = membership.OrganizationName
+		invites = append(invites, *invite)
+	}
+	return invites, nil
+}
+
+// =============================================================================
+// Invite
+// =============================================================================
+
+// Invite creates an invitation and queues its email.
+func (s *organizationService) Invite(ctx context.Context, params domain.InviteMemberParams) (*domain.OrganizationInvite, error) {
+	const op = "organization.invite"
+
+	address := strings.ToLower(strings.TrimSpace(params.Email))
+	if err := validateEmail(address); err != nil {
+		return nil, domain.Invalid(op, domain.ErrorMessage(err))
+	}
+
+	inviter, err := s.queries.GetUserByID(ctx, params.InviterID)
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to get inviter")
+	}
+	if strings.EqualFold(inviter.Email, address) {
+		return nil, domain.Invalid(op, "You're already a member of your organization.")
+	}
+
+	membership, err := s.Membership(ctx, params.InviterID)
+	if err != nil {
+		return nil, err
+	}
+	if !membership.IsOwner() {
+		return nil, domain.Forbidden(op, "Only organization owners can invite members.")
+	}
+
+	if membership.Personal {
+		membership, err = s.createOrganization(ctx, inviter)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		members, err := s.queries.ListOrganizationMembers(ctx, membership.OrganizationID)
+		if err != nil {
+			return nil, domain.Internal(err, op, "failed to list members")
+		}
+		for _, m := range members {
+			if strings.EqualFold(m.Email, address) {
+				return nil, domain.Invalid(op, address+" is already a member of your organization.")
+			}
+		}
+	}
+
+	token, err := generateShareToken()
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to generate invitation token")
+	}
+
+	row, err := s.queries.CreateOrganizationInvite(ctx, repository.CreateOrganizationInviteParams{
+		OrganizationID: membership.OrganizationID,
+		Email:          address,
+		TokenHash:      hashSessionToken(token),
+		InvitedBy:      params.InviterID,
+		ExpiresAt:      time.Now().Add(domain.OrganizationInviteTTL),
+	})
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to create invitation")
+	}
+
+	if err := s.emailQueue.Enqueue(ctx, email.Message{
+		Template: email.TemplateOrganizationInvite,
+		To:       address,
+		Data: map[string]string{
+			email.DataInviterName:      inviter.Name,
+			email.DataOrganizationName: membership.OrganizationName,
+			email.DataToken:            token,
+		},
+	}); err != nil {
+		return nil, domain.Internal(err, op, "failed to queue invitation email")
+	}
+
+	s.logger.InfoContext(ctx, "organization invitation sent",
+		"organization_id", membership.OrganizationID,
+		"invite_id", row.ID,
+		"invited_by", params.InviterID,
+	)
+
+	invite := repoInviteToDomain(row)
+	invite.OrganizationName = membership.OrganizationName
+	return invite, nil
+}
+
+// createOrganization turns the user's personal organization into a stored
+// one, with the user as its owner.
+func (s *organizationService) createOrganization(ctx context.Context, owner repository.User) (*domain.Membership, error) {
+	const op = "organization.create"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to begin transaction")
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	qtx := s.queries.WithTx(tx)
+
+	org, err := qtx.CreateOrganization(ctx, personalOrganizationName(owner))
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to create organization")
+	}
+	if err := qtx.CreateOrganizationMember(ctx, repository.CreateOrganizationMemberParams{
+		OrganizationID: org.ID,
+		UserID:         owner.ID,
+		Role:           string(domain.OrganizationRoleOwner),
+	}); err != nil {
+		return nil, domain.Internal(err, op, "failed to add organization owner")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, domain.Internal(err, op, "failed to commit transaction")
+	}
+
+	s.logger.InfoContext(ctx, "organization created", "organization_id", org.ID, "owner_id", owner.ID)
+
+	return &domain.Membership{
+		OrganizationID:   org.ID,
+		OrganizationName: org.Name,
+		Role:             domain.OrganizationRoleOwner,
+	}, nil
+}
+
+// =============================================================================
+// GetInvite / AcceptInvite
+// =============================================================================
+
+// GetInvite resolves a raw token to its invitation.
+//
+// Unknown, accepted, and expired invitations all return the same not-found
+// error so a visitor cannot tell which applies.
+func (s *organizationService) GetInvite(ctx context.Context, token string) (*domain.OrganizationInvite, error) {
+	const op = "organization.get_invite"
+
+	if token == "" {
+		return nil, domain.NotFound(op, "invitation", "")
+	}
+
+	row, err := s.queries.GetOrganizationInviteByTokenHash(ctx, hashSessionToken(token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.NotFound(op, "invitation", "")
+		}
+		return nil, domain.Internal(err, op, "failed to get invitation")
+	}
+
+	invite := &domain.OrganizationInvite{
+		ID:               row.ID,
+		OrganizationID:   row.OrganizationID,
+		OrganizationName: row.OrganizationName,
+		Email:            row.Email,
+		ExpiresAt:        row.ExpiresAt,
+		CreatedAt:        row.CreatedAt,
+	}
+	if row.AcceptedAt.Valid {
+		invite.AcceptedAt = &row.AcceptedAt.Time
+	}
+	if !invite.IsUsable(time.Now()) {
+		return nil, domain.NotFound(op, "invitation", invite.ID.String())
+	}
+	return invite, nil
+}
+
+// AcceptInvite marks the invitation used and adds the user as a member in
+// one transaction.
+func (s *organizationService) AcceptInvite(ctx context.Context, token string, userID uuid.UUID) (*domain.Membership, error) {
+	const op = "organization.accept_invite"
+
+	invite, err := s.GetInvite(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.queries.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to get user")
+	}
+	if !strings.EqualFold(user.Email, invite.Email) {
+		return nil, domain.Forbidden(op, "This invitation was sent to "+invite.Email+". Sign in with that address to accept it.")
+	}
+
+	membership, err := s.Membership(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !membership.Personal {
+		if membership.OrganizationID == invite.OrganizationID {
+			return membership, nil
+		}
+		return nil, domain.Conflict(op, "You already belong to "+membership.OrganizationName+". Ask its owner to remove you before joining another organization.")
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to begin transaction")
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	qtx := s.queries.WithTx(tx)
+
+	// The update re-checks that the invitation is unused and unexpired, so
+	// two concurrent accepts cannot both succeed
+	affected, err := qtx.AcceptOrganizationInvite(ctx, invite.ID)
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to accept invitation")
+	}
+	if affected == 0 {
+		return nil, domain.NotFound(op, "invitation", invite.ID.String())
+	}
+	if err := qtx.CreateOrganizationMember(ctx, repository.CreateOrganizationMemberParams{
+		OrganizationID: invite.OrganizationID,
+		UserID:         userID,
+		Role:           string(domain.OrganizationRoleMember),
+	}); err != nil {
+		return nil, domain.Internal(err, op, "failed to add organization member")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, domain.Internal(err, op, "failed to commit transaction")
+	}
+
+	s.logger.InfoContext(ctx, "organization invitation accepted",
+		"organization_id", invite.OrganizationID,
+		"invite_id", invite.ID,
+		"user_id", userID,
+	)
+
+	return &domain.Membership{
+		OrganizationID:   invite.OrganizationID,
+		OrganizationName: invite.OrganizationName,
+		Role:             domain.OrganizationRoleMember,
+	}, nil
+}
+
+// =============================================================================
+// RemoveMember
+// =============================================================================
+
+// RemoveMember removes a member from the owner's organization.
+func (s *organizationService) RemoveMember(ctx context.Context, ownerID, memberID uuid.UUID) error {
+	const op = "organization.remove_member"
+
+	membership, err := s.Membership(ctx, ownerID)
+	if err != nil {
+		return err
+	}
+	if membership.Personal {
+		return domain.NotFound(op, "member", memberID.String())
+	}
+	if !membership.IsOwner() {
+		return domain.Forbidden(op, "Only organization owners can remove members.")
+	}
+
+	affected, err := s.queries.DeleteOrganizationMember(ctx, repository.DeleteOrganizationMemberParams{
+		OrganizationID: membership.OrganizationID,
+		UserID:         memberID,
+	})
+	if err != nil {
+		return domain.Internal(err, op, "failed to remove member")
+	}
+	if affected == 0 {
+		return domain.NotFound(op, "member", memberID.String())
+	}
+
+	s.logger.InfoContext(ctx, "organization member removed",
+		"organization_id", membership.OrganizationID,
+		"member_id", memberID,
+		"removed_by", ownerID,
+	)
+	return nil
+}
+
+// =============================================================================
+// Helper Functions
+// =============================================================================
+
+// personalOrganizationName names a user's personal organization after their
+// business, falling back to their company and then their own name.
+func personalOrganizationName(user repository.User) string {
+	switch {
+	case user.BusinessName.Valid && user.BusinessName.String != "":
+		return user.BusinessName.String
+	case user.CompanyName.Valid && user.CompanyName.String != "":
+		return user.CompanyName.String
+	default:
+		return user.Name
+	}
+}
+
+// repoInviteToDomain converts a repository.OrganizationInvite to domain.OrganizationInvite.
+func repoInviteToDomain(row repository.OrganizationInvite) *domain.OrganizationInvite {
+	invite := &domain.OrganizationInvite{
+		ID:             row.ID,
+		OrganizationID: row.OrganizationID,
+		Email:          row.Email,
+		ExpiresAt:      row.ExpiresAt,
+		CreatedAt:      row.CreatedAt,
+	}
+	if row.AcceptedAt.Valid {
+		invite.AcceptedAt = &row.AcceptedAt.Time
+	}
+	return invite
+}
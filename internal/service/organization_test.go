@@ -0,0 +1,407 @@
+package service
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/email"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/DukeRupert/lukaut/internal/testutil/fakedb"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// In-Memory Organizations Database
+// =============================================================================
+
+// fakeOrgDB answers the sqlc queries used by organizations and their
+// invitations.
+type fakeOrgDB struct {
+	users   map[uuid.UUID]string         // Email of each user
+	orgs    map[uuid.UUID]string         // Name of each organization
+	members map[uuid.UUID]fakeOrgMember  // Membership of each user
+	invites map[uuid.UUID]*fakeOrgInvite // Invitations by ID
+	commits int                          // Committed transactions
+
+	// beforeAccept runs before AcceptOrganizationInvite, e.g. to let a
+	// concurrent request accept the invitation first.
+	beforeAccept func()
+}
+
+type fakeOrgMember struct {
+	orgID uuid.UUID
+	role  domain.OrganizationRole
+}
+
+type fakeOrgInvite struct {
+	id, orgID, invitedBy uuid.UUID
+	email, tokenHash     string
+	expiresAt            time.Time
+	acceptedAt           *time.Time
+}
+
+func newFakeOrgDB() *fakeOrgDB {
+	return &fakeOrgDB{
+		users:   map[uuid.UUID]string{},
+		orgs:    map[uuid.UUID]string{},
+		members: map[uuid.UUID]fakeOrgMember{},
+		invites: map[uuid.UUID]*fakeOrgInvite{},
+	}
+}
+
+func (f *fakeOrgDB) addUser(email string) uuid.UUID {
+	id := uuid.New()
+	f.users[id] = email
+	return id
+}
+
+// addInvite stores an invitation to orgID for email and returns its raw token.
+func (f *fakeOrgDB) addInvite(orgID uuid.UUID, email string) string {
+	token := uuid.NewString()
+	id := uuid.New()
+	f.invites[id] = &fakeOrgInvite{
+		id:        id,
+		orgID:     orgID,
+		email:     email,
+		tokenHash: hashSessionToken(token),
+		expiresAt: time.Now().Add(domain.OrganizationInviteTTL),
+	}
+	return token
+}
+
+// Begin snapshots organizations, members, and invitation acceptance, which
+// rollback restores.
+func (f *fakeOrgDB) Begin() (commit, rollback func()) {
+	orgs := map[uuid.UUID]string{}
+	for id, name := range f.orgs {
+		orgs[id] = name
+	}
+	members := map[uuid.UUID]fakeOrgMember{}
+	for id, m := range f.members {
+		members[id] = m
+	}
+	accepted := map[uuid.UUID]*time.Time{}
+	for id, inv := range f.invites {
+		accepted[id] = inv.acceptedAt
+	}
+
+	commit = func() { f.commits++ }
+	rollback = func() {
+		f.orgs = orgs
+		f.members = members
+		for id, at := range accepted {
+			f.invites[id].acceptedAt = at
+		}
+	}
+	return commit, rollback
+}
+
+func (f *fakeOrgDB) Query(q fakedb.Query) (*fakedb.Rows, error) {
+	switch q.Name {
+	case "GetUserByID":
+		id := uuid.MustParse(q.Arg(0).(string))
+		if email, ok := f.users[id]; ok {
+			return userRows(&fakeUserRow{id: id, email: email}), nil
+		}
+	case "GetOrganizationMembershipByUserID":
+		if m, ok := f.members[uuid.MustParse(q.Arg(0).(string))]; ok {
+			return fakedb.Row(m.orgID.String(), f.orgs[m.orgID], string(m.role)), nil
+		}
+	case "ListOrganizationMembers":
+		rows := &fakedb.Rows{Columns: 5}
+		for userID, m := range f.members {
+			if m.orgID.String() == q.Arg(0).(string) {
+				rows.Values = append(rows.Values, []driver.Value{userID.String(), "Test User", f.users[userID], string(m.role), time.Now()})
+			}
+		}
+		return rows, nil
+	case "CreateOrganization":
+		id := uuid.New()
+		f.orgs[id] = q.Arg(0).(string)
+		return fakedb.Row(id.String(), f.orgs[id], time.Now(), time.Now()), nil
+	case "CreateOrganizationInvite":
+		inv := &fakeOrgInvite{
+			id:        uuid.New(),
+			orgID:     uuid.MustParse(q.Arg(0).(string)),
+			email:     q.Arg(1).(string),
+			tokenHash: q.Arg(2).(string),
+			invitedBy: uuid.MustParse(q.Arg(3).(string)),
+			expiresAt: q.Arg(4).(time.Time),
+		}
+		f.invites[inv.id] = inv
+		return fakedb.Row(inv.id.String(), inv.orgID.String(), inv.email, inv.tokenHash, inv.invitedBy.String(), inv.expiresAt, nil, time.Now()), nil
+	case "GetOrganizationInviteByTokenHash":
+		for _, inv := range f.invites {
+			if inv.tokenHash == q.Arg(0).(string) {
+				var acceptedAt driver.Value
+				if inv.acceptedAt != nil {
+					acceptedAt = *inv.acceptedAt
+				}
+				return fakedb.Row(inv.id.String(), inv.orgID.String(), inv.email, inv.tokenHash, inv.invitedBy.String(), inv.expiresAt, acceptedAt, time.Now(), f.orgs[inv.orgID]), nil
+			}
+		}
+	default:
+		return nil, fmt.Errorf("fakeOrgDB: unexpected query %q", q.Name)
+	}
+	return &fakedb.Rows{}, nil
+}
+
+func (f *fakeOrgDB) Exec(q fakedb.Query) (int64, error) {
+	switch q.Name {
+	case "CreateOrganizationMember":
+		userID := uuid.MustParse(q.Arg(1).(string))
+		if _, ok := f.members[userID]; ok {
+			return 0, fmt.Errorf("fakeOrgDB: duplicate key value violates unique constraint \"organization_members_pkey\"")
+		}
+		f.members[userID] = fakeOrgMember{orgID: uuid.MustParse(q.Arg(0).(string)), role: domain.OrganizationRole(q.Arg(2).(string))}
+		return 1, nil
+	case "AcceptOrganizationInvite":
+		if f.beforeAccept != nil {
+			f.beforeAccept()
+		}
+		inv, ok := f.invites[uuid.MustParse(q.Arg(0).(string))]
+		if !ok || inv.acceptedAt != nil || !inv.expiresAt.After(time.Now()) {
+			return 0, nil
+		}
+		now := time.Now()
+		inv.acceptedAt = &now
+		return 1, nil
+	case "DeleteOrganizationMember":
+		userID := uuid.MustParse(q.Arg(1).(string))
+		m, ok := f.members[userID]
+		if !ok || m.orgID.String() != q.Arg(0).(string) || m.role == domain.OrganizationRoleOwner {
+			return 0, nil
+		}
+		delete(f.members, userID)
+		return 1, nil
+	}
+	return 0, fmt.Errorf("fakeOrgDB: unexpected exec %q", q.Name)
+}
+
+// recordingEmailQueue records the messages it is asked to send.
+type recordingEmailQueue struct {
+	messages []email.Message
+}
+
+func (q *recordingEmailQueue) Enqueue(_ context.Context, msg email.Message) error {
+	q.messages = append(q.messages, msg)
+	return nil
+}
+
+func newTestOrganizationService(f *fakeOrgDB, queue email.Queue) OrganizationService {
+	db := fakedb.Open(f)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewOrganizationService(db, repository.New(db), queue, logger)
+}
+
+// newTestOrganization stores an organization owned by a new user.
+func newTestOrganization(f *fakeOrgDB) (orgID, ownerID uuid.UUID) {
+	orgID, ownerID = uuid.New(), f.addUser("owner@example.com")
+	f.orgs[orgID] = "Acme Inspections"
+	f.members[ownerID] = fakeOrgMember{orgID: orgID, role: domain.OrganizationRoleOwner}
+	return orgID, ownerID
+}
+
+// =============================================================================
+// Invite Tests
+// =============================================================================
+
+func TestOrganizationInvite_CreatesOrganizationFromPersonal(t *testing.T) {
+	ctx := context.Background()
+	f := newFakeOrgDB()
+	inviter := f.addUser("solo@example.com")
+	queue := &recordingEmailQueue{}
+	svc := newTestOrganizationService(f, queue)
+
+	invite, err := svc.Invite(ctx, domain.InviteMemberParams{InviterID: inviter, Email: " New@Example.com "})
+	if err != nil {
+		t.Fatalf("Invite failed: %v", err)
+	}
+
+	m, ok := f.members[inviter]
+	if !ok || m.role != domain.OrganizationRoleOwner || m.orgID != invite.OrganizationID {
+		t.Errorf("expected the inviter to own the new organization, got %+v", m)
+	}
+	if stored := f.invites[invite.ID]; stored == nil || stored.email != "new@example.com" {
+		t.Errorf("expected the invitation stored for the normalized address, got %+v", stored)
+	}
+	if len(queue.messages) != 1 {
+		t.Fatalf("expected one invitation email, got %d", len(queue.messages))
+	}
+	msg := queue.messages[0]
+	if msg.Template != email.TemplateOrganizationInvite || msg.To != "new@example.com" {
+		t.Errorf("expected an invitation email to new@example.com, got %+v", msg)
+	}
+	if hashSessionToken(msg.Data[email.DataToken]) != f.invites[invite.ID].tokenHash {
+		t.Error("expected the emailed token to match the stored invitation")
+	}
+}
+
+func TestOrganizationInvite_Rejections(t *testing.T) {
+	ctx := context.Background()
+	f := newFakeOrgDB()
+	orgID, owner := newTestOrganization(f)
+	member := f.addUser("member@example.com")
+	f.members[member] = fakeOrgMember{orgID: orgID, role: domain.OrganizationRoleMember}
+	queue := &recordingEmailQueue{}
+	svc := newTestOrganizationService(f, queue)
+
+	tests := []struct {
+		name    string
+		inviter uuid.UUID
+		email   string
+		code    string
+	}{
+		{"invalid email", owner, "not-an-email", domain.EINVALID},
+		{"own email", owner, "OWNER@example.com", domain.EINVALID},
+		{"existing member", owner, "member@example.com", domain.EINVALID},
+		{"not an owner", member, "new@example.com", domain.EFORBIDDEN},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.Invite(ctx, domain.InviteMemberParams{InviterID: tt.inviter, Email: tt.email})
+			if domain.ErrorCode(err) != tt.code {
+				t.Errorf("expected %s, got %v", tt.code, err)
+			}
+		})
+	}
+
+	if len(f.invites) != 0 || len(queue.messages) != 0 {
+		t.Errorf("expected no invitations, got %d stored and %d emailed", len(f.invites), len(queue.messages))
+	}
+}
+
+// =============================================================================
+// AcceptInvite Tests
+// =============================================================================
+
+func TestOrganizationAcceptInvite_AddsMember(t *testing.T) {
+	ctx := context.Background()
+	f := newFakeOrgDB()
+	orgID, _ := newTestOrganization(f)
+	invitee := f.addUser("new@example.com")
+	token := f.addInvite(orgID, "new@example.com")
+	svc := newTestOrganizationService(f, &recordingEmailQueue{})
+
+	membership, err := svc.AcceptInvite(ctx, token, invitee)
+	if err != nil {
+		t.Fatalf("AcceptInvite failed: %v", err)
+	}
+	if membership.OrganizationID != orgID || membership.Role != domain.OrganizationRoleMember {
+		t.Errorf("expected membership of the organization, got %+v", membership)
+	}
+	if m := f.members[invitee]; m.orgID != orgID || m.role != domain.OrganizationRoleMember {
+		t.Errorf("expected the invitee stored as a member, got %+v", m)
+	}
+
+	// The invitation can only be used once
+	if _, err := svc.GetInvite(ctx, token); domain.ErrorCode(err) != domain.ENOTFOUND {
+		t.Errorf("expected ENOTFOUND for a used invitation, got %v", err)
+	}
+}
+
+func TestOrganizationAcceptInvite_WrongEmail(t *testing.T) {
+	ctx := context.Background()
+	f := newFakeOrgDB()
+	orgID, _ := newTestOrganization(f)
+	other := f.addUser("other@example.com")
+	token := f.addInvite(orgID, "new@example.com")
+	svc := newTestOrganizationService(f, &recordingEmailQueue{})
+
+	if _, err := svc.AcceptInvite(ctx, token, other); domain.ErrorCode(err) != domain.EFORBIDDEN {
+		t.Fatalf("expected EFORBIDDEN accepting another address's invitation, got %v", err)
+	}
+	if _, ok := f.members[other]; ok {
+		t.Error("expected the user not to be added")
+	}
+	for _, inv := range f.invites {
+		if inv.acceptedAt != nil {
+			t.Error("expected the invitation to stay unused")
+		}
+	}
+}
+
+func TestOrganizationAcceptInvite_AlreadyInAnotherOrganization(t *testing.T) {
+	ctx := context.Background()
+	f := newFakeOrgDB()
+	orgID, _ := newTestOrganization(f)
+	otherOrg := uuid.New()
+	f.orgs[otherOrg] = "Other Co"
+	invitee := f.addUser("new@example.com")
+	f.members[invitee] = fakeOrgMember{orgID: otherOrg, role: domain.OrganizationRoleMember}
+	token := f.addInvite(orgID, "new@example.com")
+	svc := newTestOrganizationService(f, &recordingEmailQueue{})
+
+	if _, err := svc.AcceptInvite(ctx, token, invitee); domain.ErrorCode(err) != domain.ECONFLICT {
+		t.Fatalf("expected ECONFLICT for a member of another organization, got %v", err)
+	}
+	if m := f.members[invitee]; m.orgID != otherOrg {
+		t.Errorf("expected the user to stay in their organization, got %+v", m)
+	}
+}
+
+func TestOrganizationAcceptInvite_ConcurrentAcceptLoses(t *testing.T) {
+	ctx := context.Background()
+	f := newFakeOrgDB()
+	orgID, _ := newTestOrganization(f)
+	invitee := f.addUser("new@example.com")
+	token := f.addInvite(orgID, "new@example.com")
+	svc := newTestOrganizationService(f, &recordingEmailQueue{})
+
+	// Another request accepts the invitation after this one has read it
+	f.beforeAccept = func() {
+		now := time.Now()
+		for _, inv := range f.invites {
+			inv.acceptedAt = &now
+		}
+	}
+
+	if _, err := svc.AcceptInvite(ctx, token, invitee); domain.ErrorCode(err) != domain.ENOTFOUND {
+		t.Fatalf("expected ENOTFOUND when the invitation was accepted concurrently, got %v", err)
+	}
+	if _, ok := f.members[invitee]; ok {
+		t.Error("expected the user not to be added")
+	}
+	if f.commits != 0 {
+		t.Errorf("expected the transaction to roll back, got %d commits", f.commits)
+	}
+}
+
+// =============================================================================
+// RemoveMember Tests
+// =============================================================================
+
+func TestOrganizationRemoveMember(t *testing.T) {
+	ctx := context.Background()
+	f := newFakeOrgDB()
+	orgID, owner := newTestOrganization(f)
+	member := f.addUser("member@example.com")
+	f.members[member] = fakeOrgMember{orgID: orgID, role: domain.OrganizationRoleMember}
+	svc := newTestOrganizationService(f, &recordingEmailQueue{})
+
+	if err := svc.RemoveMember(ctx, member, owner); domain.ErrorCode(err) != domain.EFORBIDDEN {
+		t.Errorf("expected EFORBIDDEN for a member removing the owner, got %v", err)
+	}
+	if err := svc.RemoveMember(ctx, owner, owner); domain.ErrorCode(err) != domain.ENOTFOUND {
+		t.Errorf("expected ENOTFOUND for the owner removing themselves, got %v", err)
+	}
+	if err := svc.RemoveMember(ctx, owner, uuid.New()); domain.ErrorCode(err) != domain.ENOTFOUND {
+		t.Errorf("expected ENOTFOUND for a non-member, got %v", err)
+	}
+
+	if err := svc.RemoveMember(ctx, owner, member); err != nil {
+		t.Fatalf("RemoveMember failed: %v", err)
+	}
+	if _, ok := f.members[member]; ok {
+		t.Error("expected the member to be removed")
+	}
+	if _, ok := f.members[owner]; !ok {
+		t.Error("expected the owner to stay")
+	}
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	_ "embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+)
+
+// =============================================================================
+// Password Rejection
+// =============================================================================
+
+// Messages returned when a password is rejected for being guessable. They are
+// shown to the user, so each names the reason.
+const (
+	ErrMsgPasswordTooCommon = "Password is too common. Please choose a more unique password."
+	ErrMsgPasswordBreached  = "This password has appeared in a data breach. Please choose a different password."
+)
+
+// ErrWeakPassword is wrapped by every password validation error, so handlers
+// can show the message next to the password field with errors.Is.
+var ErrWeakPassword = errors.New("password rejected")
+
+// IsWeakPassword reports whether err rejected a password.
+func IsWeakPassword(err error) bool {
+	return errors.Is(err, ErrWeakPassword)
+}
+
+// weakPassword returns an EINVALID error for a rejected password.
+func weakPassword(message string) error {
+	return domain.Wrap(ErrWeakPassword, domain.EINVALID, "", message)
+}
+
+// =============================================================================
+// Common Password List
+// =============================================================================
+
+// commonPasswordsFile is the bundled list of common passwords, one per line.
+// Lines starting with # are comments.
+//
+//go:embed common_passwords.txt
+var commonPasswordsFile string
+
+var (
+	commonPasswordsOnce sync.Once
+	commonPasswords     map[string]struct{}
+)
+
+// isCommonPassword checks if the password is in the bundled common password
+// list. The comparison is case-insensitive.
+func isCommonPassword(password string) bool {
+	commonPasswordsOnce.Do(func() {
+		commonPasswords = make(map[string]struct{})
+		for _, line := range strings.Split(commonPasswordsFile, "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			commonPasswords[strings.ToLower(line)] = struct{}{}
+		}
+	})
+
+	_, ok := commonPasswords[strings.ToLower(password)]
+	return ok
+}
+
+// =============================================================================
+// Breached Password Check
+// =============================================================================
+
+// BreachedPasswordChecker reports whether a password appears in known data
+// breaches.
+//
+// Implementations:
+// - PwnedPasswordsChecker: HaveIBeenPwned Pwned Passwords range API
+type BreachedPasswordChecker interface {
+	IsBreached(ctx context.Context, password string) (bool, error)
+}
+
+const (
+	// DefaultPwnedPasswordsURL is the HaveIBeenPwned range API endpoint.
+	DefaultPwnedPasswordsURL = "https://api.pwnedpasswords.com/range/"
+
+	// DefaultBreachCheckTimeout bounds each range request. Registration
+	// should not wait long on a third party.
+	DefaultBreachCheckTimeout = 2 * time.Second
+)
+
+// PwnedPasswordsChecker checks passwords against the HaveIBeenPwned range
+// API using k-anonymity: only the first five hex characters of the
+// password's SHA-1 hash leave the server.
+type PwnedPasswordsChecker struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewPwnedPasswordsChecker creates a checker for the range API at baseURL
+// (DefaultPwnedPasswordsURL if empty). A timeout of zero uses
+// DefaultBreachCheckTimeout.
+func NewPwnedPasswordsChecker(baseURL string, timeout time.Duration) *PwnedPasswordsChecker {
+	if baseURL == "" {
+		baseURL = DefaultPwnedPasswordsURL
+	}
+	if timeout <= 0 {
+		timeout = DefaultBreachCheckTimeout
+	}
+	return &PwnedPasswordsChecker{
+		baseURL: baseURL,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// IsBreached reports whether password appears in the Pwned Passwords corpus.
+func (c *PwnedPasswordsChecker) IsBreached(ctx context.Context, password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:5], hash[5:]
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+prefix, nil)
+	if err != nil {
+		return false, fmt.Errorf("build range request: %w", err)
+	}
+	// Padding hides the real response size from observers
+	req.Header.Set("Add-Padding", "true")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("range request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("range request: unexpected status %d", resp.StatusCode)
+	}
+
+	// Each line is "SUFFIX:COUNT"; padding entries have a count of zero
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		lineSuffix, count, ok := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if !ok || lineSuffix != suffix {
+			continue
+		}
+		n, err := strconv.Atoi(count)
+		return err == nil && n > 0, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return false, fmt.Errorf("read range response: %w", err)
+	}
+	return false, nil
+}
+
+var _ BreachedPasswordChecker = (*PwnedPasswordsChecker)(nil)
+
+// checkPassword validates password and, when a breach checker is configured,
+// rejects passwords found in known breaches. The breach check fails open: if
+// the service cannot be reached the password is accepted.
+func (s *userService) checkPassword(ctx context.Context, password string) error {
+	if err := validatePassword(password); err != nil {
+		return err
+	}
+	if s.breachChecker == nil {
+		return nil
+	}
+
+	breached, err := s.breachChecker.IsBreached(ctx, password)
+	if err != nil {
+		s.logger.WarnContext(ctx, "breached password check failed, accepting password", "error", err)
+		return nil
+	}
+	if breached {
+		return weakPassword(ErrMsgPasswordBreached)
+	}
+	return nil
+}
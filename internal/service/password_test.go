@@ -1,8 +1,16 @@
 package service
 
 import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/DukeRupert/lukaut/internal/domain"
 )
@@ -166,3 +174,216 @@ func TestValidatePassword_ErrorMessages(t *testing.T) {
 		})
 	}
 }
+
+func TestValidatePassword_RejectionsAreWeakPasswordErrors(t *testing.T) {
+	for _, pw := range []string{"Ab1", "12345678", "Abcdefgh", "Password1"} {
+		err := validatePassword(pw)
+		if domain.ErrorCode(err) != domain.EINVALID {
+			t.Errorf("%q: expected EINVALID, got %v", pw, err)
+		}
+		if !IsWeakPassword(err) {
+			t.Errorf("%q: expected IsWeakPassword to be true", pw)
+		}
+	}
+}
+
+func TestIsCommonPassword_BundledList(t *testing.T) {
+	for _, pw := range []string{"password123", "QWERTY123", "Abcd1234", "iloveyou1"} {
+		if !isCommonPassword(pw) {
+			t.Errorf("expected %q to be in the bundled list", pw)
+		}
+	}
+	if isCommonPassword("# Common passwords, one per line, compared case-insensitively.") {
+		t.Error("comment lines must not be loaded as passwords")
+	}
+}
+
+// =============================================================================
+// Breached Password Tests
+// =============================================================================
+
+// pwnedRangeServer serves a range response containing suffix with count,
+// plus a padding entry, and records the requested prefix.
+func pwnedRangeServer(t *testing.T, password string, count int) (*httptest.Server, *string) {
+	t.Helper()
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	var gotPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		fmt.Fprintf(w, "0018A45C4D1DEF81644B54AB7F969B88D65:1\r\n")
+		fmt.Fprintf(w, "%s:%d\r\n", hash[5:], count)
+		fmt.Fprintf(w, "00D4F6E8FA6EECAD2A3AA415EEC418D38EC:0\r\n")
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &gotPath
+}
+
+func TestPwnedPasswordsChecker_SendsOnlyHashPrefix(t *testing.T) {
+	srv, gotPath := pwnedRangeServer(t, "Summer2016x", 42)
+	checker := NewPwnedPasswordsChecker(srv.URL+"/range/", time.Second)
+
+	breached, err := checker.IsBreached(context.Background(), "Summer2016x")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !breached {
+		t.Error("expected password to be reported as breached")
+	}
+
+	sum := sha1.Sum([]byte("Summer2016x"))
+	wantPath := "/range/" + strings.ToUpper(hex.EncodeToString(sum[:]))[:5]
+	if *gotPath != wantPath {
+		t.Errorf("expected request to %q, got %q", wantPath, *gotPath)
+	}
+}
+
+func TestPwnedPasswordsChecker_NotBreached(t *testing.T) {
+	// A zero count is a padding entry, not a breach
+	srv, _ := pwnedRangeServer(t, "Randoms7ring", 0)
+	checker := NewPwnedPasswordsChecker(srv.URL+"/range/", time.Second)
+
+	breached, err := checker.IsBreached(context.Background(), "Randoms7ring")
+	if err != nil || breached {
+		t.Errorf("expected not breached, got %v, %v", breached, err)
+	}
+}
+
+func TestPwnedPasswordsChecker_ErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+	checker := NewPwnedPasswordsChecker(srv.URL+"/range/", time.Second)
+
+	if _, err := checker.IsBreached(context.Background(), "Randoms7ring"); err == nil {
+		t.Error("expected error for non-200 response")
+	}
+}
+
+// stubBreachChecker returns fixed results.
+type stubBreachChecker struct {
+	breached bool
+	err      error
+}
+
+func (s stubBreachChecker) IsBreached(ctx context.Context, password string) (bool, error) {
+	return s.breached, s.err
+}
+
+func TestCheckPassword_Breached(t *testing.T) {
+	svc := newFakeDBUserService(newFakeUsersDB(), UserServiceConfig{
+		BreachedPasswordChecker: stubBreachChecker{breached: true},
+	}).(*userService)
+
+	err := svc.checkPassword(context.Background(), "Randoms7ring")
+	if domain.ErrorMessage(err) != ErrMsgPasswordBreached {
+		t.Errorf("expected %q, got %v", ErrMsgPasswordBreached, err)
+	}
+	if !IsWeakPassword(err) {
+		t.Error("expected IsWeakPassword to be true")
+	}
+
+	// Basic rules are checked first, without calling the API
+	err = svc.checkPassword(context.Background(), "Password1")
+	if domain.ErrorMessage(err) != ErrMsgPasswordTooCommon {
+		t.Errorf("expected %q, got %v", ErrMsgPasswordTooCommon, err)
+	}
+}
+
+func TestCheckPassword_FailsOpen(t *testing.T) {
+	svc := newFakeDBUserService(newFakeUsersDB(), UserServiceConfig{
+		BreachedPasswordChecker: stubBreachChecker{err: errors.New("dial tcp: i/o timeout")},
+	}).(*userService)
+
+	if err := svc.checkPassword(context.Background(), "Randoms7ring"); err != nil {
+		t.Errorf("expected password to be accepted when the API is unreachable, got %v", err)
+	}
+}
+
+func TestRegister_PasswordErrorMessagePassedThrough(t *testing.T) {
+	svc := newFakeDBUserService(newFakeUsersDB(), UserServiceConfig{
+		BreachedPasswordChecker: stubBreachChecker{breached: true},
+	})
+
+	_, err := svc.Register(context.Background(), domain.RegisterParams{
+		Email:    "pat@example.com",
+		Name:     "Pat",
+		Password: "Randoms7ring",
+	})
+	if domain.ErrorMessage(err) != ErrMsgPasswordBreached {
+		t.Errorf("expected %q, got %q", ErrMsgPasswordBreached, domain.ErrorMessage(err))
+	}
+	if !IsWeakPassword(err) {
+		t.Error("expected IsWeakPassword to be true through the Register wrapper")
+	}
+}
+
+// =============================================================================
+// ChangePassword Tests
+// =============================================================================
+
+func TestChangePassword_EndsSessions(t *testing.T) {
+	ctx := context.Background()
+	f := newFakeUsersDB()
+	user := f.addUser(t, "inspector@example.com", "correct-horse-1")
+	svc := newFakeDBUserService(f, UserServiceConfig{})
+
+	login, err := svc.Login(ctx, user.email, "correct-horse-1")
+	if err != nil {
+		t.Fatalf("login failed: %v", err)
+	}
+
+	if err := svc.ChangePassword(ctx, domain.PasswordChangeParams{
+		UserID:          user.id,
+		CurrentPassword: "correct-horse-1",
+		NewPassword:     "battery-staple-2",
+	}); err != nil {
+		t.Fatalf("ChangePassword failed: %v", err)
+	}
+
+	if f.commits != 1 {
+		t.Errorf("expected the change committed in one transaction, got %d commits", f.commits)
+	}
+	if _, err := svc.GetBySessionToken(ctx, login.Token); err == nil {
+		t.Error("expected the old session to end with the password change")
+	}
+	if _, err := svc.Login(ctx, user.email, "battery-staple-2"); err != nil {
+		t.Errorf("expected login with the new password, got %v", err)
+	}
+}
+
+func TestChangePassword_RollsBackWhenSessionsCannotBeEnded(t *testing.T) {
+	ctx := context.Background()
+	f := newFakeUsersDB()
+	user := f.addUser(t, "inspector@example.com", "correct-horse-1")
+	svc := newFakeDBUserService(f, UserServiceConfig{})
+
+	login, err := svc.Login(ctx, user.email, "correct-horse-1")
+	if err != nil {
+		t.Fatalf("login failed: %v", err)
+	}
+
+	f.failExec = "DeleteUserSessions"
+	err = svc.ChangePassword(ctx, domain.PasswordChangeParams{
+		UserID:          user.id,
+		CurrentPassword: "correct-horse-1",
+		NewPassword:     "battery-staple-2",
+	})
+	if domain.ErrorCode(err) != domain.EINTERNAL {
+		t.Fatalf("expected EINTERNAL when sessions can't be ended, got %v", err)
+	}
+	f.failExec = ""
+
+	// The password update made before the failure was rolled back
+	if _, err := svc.Login(ctx, user.email, "correct-horse-1"); err != nil {
+		t.Errorf("expected the old password to still work, got %v", err)
+	}
+	if _, err := svc.Login(ctx, user.email, "battery-staple-2"); err == nil {
+		t.Error("expected the new password not to be saved")
+	}
+	if _, err := svc.GetBySessionToken(ctx, login.Token); err != nil {
+		t.Errorf("expected the existing session to stay valid, got %v", err)
+	}
+}
@@ -0,0 +1,190 @@
+// Package service contains the business logic layer.
+//
+// This file implements the preference service for remembering per-user UI
+// choices across sessions.
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// Interface Definition
+// =============================================================================
+
+// PreferenceService defines operations for user preferences.
+type PreferenceService interface {
+	// PerPage returns the user's list page size. Users who have not chosen
+	// one, or whose stored value is no longer offered, get
+	// domain.DefaultPerPage. Lookup failures are logged and also fall back
+	// to the default, since a preference should never break a page.
+	PerPage(ctx context.Context, userID uuid.UUID) int
+
+	// SetPerPage remembers the user's list page size.
+	// Returns domain.EINVALID if perPage is not one of domain.PerPageOptions.
+	SetPerPage(ctx context.Context, userID uuid.UUID, perPage int) error
+
+	// ReviewShortcuts returns the user's review queue keys: the defaults with
+	// any custom mapping applied. A stored mapping that no longer validates
+	// is ignored, and lookup failures fall back to the defaults as PerPage
+	// does.
+	ReviewShortcuts(ctx context.Context, userID uuid.UUID) domain.ReviewShortcuts
+
+	// SetReviewShortcuts remembers custom review queue keys, merged over the
+	// defaults.
+	// Returns domain.EINVALID if domain.MergeReviewShortcuts rejects them.
+	SetReviewShortcuts(ctx context.Context, userID uuid.UUID, custom map[domain.ReviewAction]string) error
+
+	// DisplayFormat returns the user's unit system and date locale. Values
+	// that are unset or no longer supported are replaced by the defaults,
+	// and lookup failures fall back to domain.DefaultDisplayFormat as
+	// PerPage does.
+	DisplayFormat(ctx context.Context, userID uuid.UUID) domain.DisplayFormat
+
+	// SetDisplayFormat remembers the user's unit system and date locale.
+	// Returns domain.EINVALID if either is not supported.
+	SetDisplayFormat(ctx context.Context, userID uuid.UUID, format domain.DisplayFormat) error
+}
+
+// =============================================================================
+// Implementation
+// =============================================================================
+
+// preferenceService implements the PreferenceService interface.
+type preferenceService struct {
+	queries *repository.Queries
+	logger  *slog.Logger
+}
+
+// NewPreferenceService creates a new PreferenceService.
+func NewPreferenceService(queries *repository.Queries, logger *slog.Logger) PreferenceService {
+	return &preferenceService{
+		queries: queries,
+		logger:  logger,
+	}
+}
+
+// userPreferences is the stored preferences document of a user.
+type userPreferences struct {
+	PerPage         int                            `json:"per_page"`
+	ReviewShortcuts map[domain.ReviewAction]string `json:"review_shortcuts"`
+	DisplayFormat   domain.DisplayFormat           `json:"display_format"`
+}
+
+// load returns the user's stored preferences. Users without any, and lookup
+// or decoding failures, get the zero value; failures are logged.
+func (s *preferenceService) load(ctx context.Context, userID uuid.UUID) userPreferences {
+	var prefs userPreferences
+
+	raw, err := s.queries.GetUserPreferences(ctx, userID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			s.logger.ErrorContext(ctx, "failed to get user preferences", "error", err, "user_id", userID)
+		}
+		return prefs
+	}
+
+	if err := json.Unmarshal(raw, &prefs); err != nil {
+		s.logger.WarnContext(ctx, "ignoring malformed user preferences", "error", err, "user_id", userID)
+		return userPreferences{}
+	}
+	return prefs
+}
+
+// PerPage returns the user's list page size.
+func (s *preferenceService) PerPage(ctx context.Context, userID uuid.UUID) int {
+	return domain.NormalizePerPage(s.load(ctx, userID).PerPage)
+}
+
+// SetPerPage remembers the user's list page size.
+func (s *preferenceService) SetPerPage(ctx context.Context, userID uuid.UUID, perPage int) error {
+	const op = "preference.set_per_page"
+
+	if !domain.IsValidPerPage(perPage) {
+		return domain.Invalid(op, fmt.Sprintf("page size must be one of %v", domain.PerPageOptions))
+	}
+
+	if err := s.queries.SetUserPreference(ctx, repository.SetUserPreferenceParams{
+		UserID: userID,
+		Key:    domain.PreferencePerPage,
+		Value:  json.RawMessage(strconv.Itoa(perPage)),
+	}); err != nil {
+		return domain.Internal(err, op, "failed to save page size")
+	}
+	return nil
+}
+
+// ReviewShortcuts returns the user's review queue keys.
+func (s *preferenceService) ReviewShortcuts(ctx context.Context, userID uuid.UUID) domain.ReviewShortcuts {
+	custom := s.load(ctx, userID).ReviewShortcuts
+	if len(custom) == 0 {
+		return domain.DefaultReviewShortcuts()
+	}
+
+	shortcuts, err := domain.MergeReviewShortcuts(custom)
+	if err != nil {
+		s.logger.WarnContext(ctx, "ignoring invalid review shortcuts", "error", err, "user_id", userID)
+		return domain.DefaultReviewShortcuts()
+	}
+	return shortcuts
+}
+
+// SetReviewShortcuts remembers custom review queue keys.
+func (s *preferenceService) SetReviewShortcuts(ctx context.Context, userID uuid.UUID, custom map[domain.ReviewAction]string) error {
+	const op = "preference.set_review_shortcuts"
+
+	shortcuts, err := domain.MergeReviewShortcuts(custom)
+	if err != nil {
+		return err
+	}
+
+	value, err := json.Marshal(shortcuts)
+	if err != nil {
+		return domain.Internal(err, op, "failed to encode review shortcuts")
+	}
+	if err := s.queries.SetUserPreference(ctx, repository.SetUserPreferenceParams{
+		UserID: userID,
+		Key:    domain.PreferenceReviewShortcuts,
+		Value:  json.RawMessage(value),
+	}); err != nil {
+		return domain.Internal(err, op, "failed to save review shortcuts")
+	}
+	return nil
+}
+
+// DisplayFormat returns the user's unit system and date locale.
+func (s *preferenceService) DisplayFormat(ctx context.Context, userID uuid.UUID) domain.DisplayFormat {
+	return s.load(ctx, userID).DisplayFormat.Normalize()
+}
+
+// SetDisplayFormat remembers the user's unit system and date locale.
+func (s *preferenceService) SetDisplayFormat(ctx context.Context, userID uuid.UUID, format domain.DisplayFormat) error {
+	const op = "preference.set_display_format"
+
+	if err := format.Validate(); err != nil {
+		return err
+	}
+
+	value, err := json.Marshal(format)
+	if err != nil {
+		return domain.Internal(err, op, "failed to encode display format")
+	}
+	if err := s.queries.SetUserPreference(ctx, repository.SetUserPreferenceParams{
+		UserID: userID,
+		Key:    domain.PreferenceDisplayFormat,
+		Value:  json.RawMessage(value),
+	}); err != nil {
+		return domain.Internal(err, op, "failed to save display format")
+	}
+	return nil
+}
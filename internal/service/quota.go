@@ -7,6 +7,7 @@ package service
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"log/slog"
 	"time"
 
@@ -27,15 +28,18 @@ const (
 
 // QuotaService defines operations for checking quota limits.
 type QuotaService interface {
-	// GetUsage returns the current quota usage for a user.
+	// GetUsage returns the current quota usage for a user, including when
+	// the current period resets.
 	GetUsage(ctx context.Context, userID uuid.UUID, tier domain.SubscriptionTier) (*domain.QuotaUsage, error)
 
 	// CheckAnalysisQuota checks if the user has quota remaining for analysis jobs.
-	// Returns nil if quota is available, or QuotaExceeded error if not.
+	// Returns nil if quota is available, or a domain.EQUOTA error naming the
+	// reset date if not.
 	CheckAnalysisQuota(ctx context.Context, userID uuid.UUID, tier domain.SubscriptionTier) error
 
 	// CheckReportQuota checks if the user has quota remaining for report jobs.
-	// Returns nil if quota is available, or QuotaExceeded error if not.
+	// Returns nil if quota is available, or a domain.EQUOTA error naming the
+	// reset date if not.
 	CheckReportQuota(ctx context.Context, userID uuid.UUID, tier domain.SubscriptionTier) error
 }
 
@@ -60,7 +64,10 @@ func NewQuotaService(queries *repository.Queries, logger *slog.Logger) QuotaServ
 func (s *quotaService) GetUsage(ctx context.Context, userID uuid.UUID, tier domain.SubscriptionTier) (*domain.QuotaUsage, error) {
 	const op = "quota.get_usage"
 
-	quota := domain.GetTierQuota(tier)
+	quota, err := s.quotaFor(ctx, userID, tier)
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to get quota override")
+	}
 
 	// If unlimited, return early
 	if quota.UnlimitedAnalysis && quota.UnlimitedReports {
@@ -100,6 +107,7 @@ func (s *quotaService) GetUsage(ctx context.Context, userID uuid.UUID, tier doma
 		ReportsUsed:   reportCount,
 		ReportsLimit:  int64(quota.ReportsPerMonth),
 		IsUnlimited:   false,
+		ResetsAt:      endOfMonth,
 	}, nil
 }
 
@@ -107,7 +115,10 @@ func (s *quotaService) GetUsage(ctx context.Context, userID uuid.UUID, tier doma
 func (s *quotaService) CheckAnalysisQuota(ctx context.Context, userID uuid.UUID, tier domain.SubscriptionTier) error {
 	const op = "quota.check_analysis"
 
-	quota := domain.GetTierQuota(tier)
+	quota, err := s.quotaFor(ctx, userID, tier)
+	if err != nil {
+		return domain.Internal(err, op, "failed to get quota override")
+	}
 
 	// Unlimited tier - always allow
 	if quota.UnlimitedAnalysis {
@@ -130,13 +141,13 @@ func (s *quotaService) CheckAnalysisQuota(ctx context.Context, userID uuid.UUID,
 
 	limit := int64(quota.AnalysisPerMonth)
 	if count >= limit {
-		s.logger.Info("Analysis quota exceeded",
+		s.logger.InfoContext(ctx, "Analysis quota exceeded",
 			"user_id", userID,
 			"tier", tier,
 			"used", count,
 			"limit", limit,
 		)
-		return domain.QuotaExceeded(op, domain.QuotaTypeAnalysis, count, limit)
+		return domain.QuotaExceeded(op, domain.QuotaTypeAnalysis, count, limit, endOfMonth)
 	}
 
 	return nil
@@ -146,7 +157,10 @@ func (s *quotaService) CheckAnalysisQuota(ctx context.Context, userID uuid.UUID,
 func (s *quotaService) CheckReportQuota(ctx context.Context, userID uuid.UUID, tier domain.SubscriptionTier) error {
 	const op = "quota.check_report"
 
-	quota := domain.GetTierQuota(tier)
+	quota, err := s.quotaFor(ctx, userID, tier)
+	if err != nil {
+		return domain.Internal(err, op, "failed to get quota override")
+	}
 
 	// Unlimited tier - always allow
 	if quota.UnlimitedReports {
@@ -169,18 +183,46 @@ func (s *quotaService) CheckReportQuota(ctx context.Context, userID uuid.UUID, t
 
 	limit := int64(quota.ReportsPerMonth)
 	if count >= limit {
-		s.logger.Info("Report quota exceeded",
+		s.logger.InfoContext(ctx, "Report quota exceeded",
 			"user_id", userID,
 			"tier", tier,
 			"used", count,
 			"limit", limit,
 		)
-		return domain.QuotaExceeded(op, domain.QuotaTypeReport, count, limit)
+		return domain.QuotaExceeded(op, domain.QuotaTypeReport, count, limit, endOfMonth)
 	}
 
 	return nil
 }
 
+// quotaFor returns the tier's quota with any admin override for the user applied.
+func (s *quotaService) quotaFor(ctx context.Context, userID uuid.UUID, tier domain.SubscriptionTier) (domain.TierQuota, error) {
+	quota := domain.GetTierQuota(tier)
+
+	row, err := s.queries.GetUserQuotaOverride(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return quota, nil
+		}
+		return quota, err
+	}
+	return quota.WithOverride(repoQuotaOverrideToDomain(row)), nil
+}
+
+// repoQuotaOverrideToDomain converts a repository.UserQuotaOverride to domain.QuotaOverride.
+func repoQuotaOverrideToDomain(row repository.UserQuotaOverride) *domain.QuotaOverride {
+	override := &domain.QuotaOverride{}
+	if row.AnalysisPerMonth.Valid {
+		limit := int(row.AnalysisPerMonth.Int32)
+		override.AnalysisPerMonth = &limit
+	}
+	if row.ReportsPerMonth.Valid {
+		limit := int(row.ReportsPerMonth.Int32)
+		override.ReportsPerMonth = &limit
+	}
+	return override
+}
+
 // getCurrentMonthBoundaries returns the start and end times for the current month in UTC.
 func getCurrentMonthBoundaries() (start, end time.Time) {
 	now := time.Now().UTC()
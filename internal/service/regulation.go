@@ -28,17 +28,22 @@ type RegulationService interface {
 	// Returns domain.ENOTFOUND if regulation doesn't exist.
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Regulation, error)
 
-	// Search performs full-text search on regulations.
+	// Search performs full-text search on regulations within the filter.
 	// Returns paginated results with total count.
-	Search(ctx context.Context, query string, limit, offset int32) (*domain.RegulationSearchResult, error)
+	Search(ctx context.Context, query string, filter domain.RegulationFilter, limit, offset int32) (*domain.RegulationSearchResult, error)
 
-	// Browse lists regulations with optional category filter.
+	// Browse lists regulations within the filter.
 	// Returns paginated results with total count.
-	Browse(ctx context.Context, category string, limit, offset int32) (*domain.RegulationSearchResult, error)
+	Browse(ctx context.Context, filter domain.RegulationFilter, limit, offset int32) (*domain.RegulationSearchResult, error)
 
 	// ListCategories returns all unique regulation categories.
 	ListCategories(ctx context.Context) ([]string, error)
 
+	// ListFacets returns the regulation count per category and, if category
+	// is set, per subcategory within it. A non-empty query limits the counts
+	// to regulations matching it.
+	ListFacets(ctx context.Context, query, category string) (*domain.RegulationFacets, error)
+
 	// LinkToViolation links a regulation to a violation.
 	// Idempotent: succeeds silently if already linked.
 	// Returns domain.ENOTFOUND if violation or regulation doesn't exist.
@@ -53,6 +58,33 @@ type RegulationService interface {
 
 	// IsLinkedToViolation checks if a regulation is linked to a violation.
 	IsLinkedToViolation(ctx context.Context, violationID, regulationID uuid.UUID) (bool, error)
+
+	// AddBookmark bookmarks a regulation for the user.
+	// Idempotent: succeeds silently if already bookmarked.
+	// Returns domain.ENOTFOUND if regulation doesn't exist.
+	AddBookmark(ctx context.Context, userID, regulationID uuid.UUID) error
+
+	// RemoveBookmark removes a regulation from the user's bookmarks.
+	// Idempotent: succeeds silently if not bookmarked.
+	RemoveBookmark(ctx context.Context, userID, regulationID uuid.UUID) error
+
+	// ListBookmarks returns the user's bookmarked regulations by standard number.
+	ListBookmarks(ctx context.Context, userID uuid.UUID) ([]domain.RegulationSummary, error)
+
+	// ListBookmarkIDs returns the IDs of the user's bookmarked regulations.
+	ListBookmarkIDs(ctx context.Context, userID uuid.UUID) (map[uuid.UUID]bool, error)
+
+	// ListMostUsed returns up to limit regulations most often linked to the
+	// user's violations, excluding bookmarks. UseCount is populated.
+	ListMostUsed(ctx context.Context, userID uuid.UUID, limit int32) ([]domain.RegulationSummary, error)
+
+	// RecordView records that the user viewed a regulation, keeping only the
+	// latest domain.RecentRegulationViewsLimit views.
+	RecordView(ctx context.Context, userID, regulationID uuid.UUID) error
+
+	// ListRecentlyViewed returns the user's recently viewed regulations,
+	// most recent first.
+	ListRecentlyViewed(ctx context.Context, userID uuid.UUID) ([]domain.RegulationSummary, error)
 }
 
 // =============================================================================
@@ -99,22 +131,32 @@ func (s *regulationService) GetByID(ctx context.Context, id uuid.UUID) (*domain.
 // Search
 // =============================================================================
 
-// Search performs full-text search on regulations.
-func (s *regulationService) Search(ctx context.Context, query string, limit, offset int32) (*domain.RegulationSearchResult, error) {
+// Search performs full-text search on regulations within the filter.
+func (s *regulationService) Search(ctx context.Context, query string, filter domain.RegulationFilter, limit, offset int32) (*domain.RegulationSearchResult, error) {
 	const op = "regulation.search"
 
+	category, subcategory, severity := regulationFilterArgs(filter)
+
 	// Get search results
 	results, err := s.queries.SearchRegulationsWithOffset(ctx, repository.SearchRegulationsWithOffsetParams{
 		WebsearchToTsquery: query,
 		Limit:              limit,
 		Offset:             offset,
+		Category:           category,
+		Subcategory:        subcategory,
+		Severity:           severity,
 	})
 	if err != nil {
 		return nil, domain.Internal(err, op, "failed to search regulations")
 	}
 
 	// Get total count
-	total, err := s.queries.CountSearchResults(ctx, query)
+	total, err := s.queries.CountSearchResults(ctx, repository.CountSearchResultsParams{
+		WebsearchToTsquery: query,
+		Category:           category,
+		Subcategory:        subcategory,
+		Severity:           severity,
+	})
 	if err != nil {
 		return nil, domain.Internal(err, op, "failed to count search results")
 	}
@@ -144,27 +186,30 @@ func (s *regulationService) Search(ctx context.Context, query string, limit, off
 // Browse
 // =============================================================================
 
-// Browse lists regulations with optional category filter.
-func (s *regulationService) Browse(ctx context.Context, category string, limit, offset int32) (*domain.RegulationSearchResult, error) {
+// Browse lists regulations within the filter.
+func (s *regulationService) Browse(ctx context.Context, filter domain.RegulationFilter, limit, offset int32) (*domain.RegulationSearchResult, error) {
 	const op = "regulation.browse"
 
-	var categoryFilter sql.NullString
-	if category != "" {
-		categoryFilter = sql.NullString{String: category, Valid: true}
-	}
+	category, subcategory, severity := regulationFilterArgs(filter)
 
 	// Get regulations
 	results, err := s.queries.ListRegulations(ctx, repository.ListRegulationsParams{
-		Category: categoryFilter,
-		Limit:    limit,
-		Offset:   offset,
+		Category:    category,
+		Subcategory: subcategory,
+		Severity:    severity,
+		Limit:       limit,
+		Offset:      offset,
 	})
 	if err != nil {
 		return nil, domain.Internal(err, op, "failed to list regulations")
 	}
 
 	// Get total count
-	total, err := s.queries.CountRegulations(ctx, categoryFilter)
+	total, err := s.queries.CountRegulations(ctx, repository.CountRegulationsParams{
+		Category:    category,
+		Subcategory: subcategory,
+		Severity:    severity,
+	})
 	if err != nil {
 		return nil, domain.Internal(err, op, "failed to count regulations")
 	}
@@ -206,6 +251,61 @@ func (s *regulationService) ListCategories(ctx context.Context) ([]string, error
 	return categories, nil
 }
 
+// =============================================================================
+// ListFacets
+// =============================================================================
+
+// ListFacets returns category and subcategory counts for the query.
+func (s *regulationService) ListFacets(ctx context.Context, query, category string) (*domain.RegulationFacets, error) {
+	const op = "regulation.list_facets"
+
+	queryFilter := domain.ToNullString(query)
+
+	categoryRows, err := s.queries.ListCategoryFacets(ctx, queryFilter)
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to count regulations by category")
+	}
+
+	facets := &domain.RegulationFacets{
+		Categories:    make([]domain.RegulationFacet, len(categoryRows)),
+		Subcategories: []domain.RegulationFacet{},
+	}
+	for i, r := range categoryRows {
+		facets.Categories[i] = domain.RegulationFacet{Name: r.Category, Count: r.RegulationCount}
+	}
+
+	if category == "" {
+		return facets, nil
+	}
+
+	subcategoryRows, err := s.queries.ListSubcategoryFacets(ctx, repository.ListSubcategoryFacetsParams{
+		Category: category,
+		Query:    queryFilter,
+	})
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to count regulations by subcategory")
+	}
+	for _, r := range subcategoryRows {
+		facets.Subcategories = append(facets.Subcategories, domain.RegulationFacet{
+			Name:  domain.NullStringValue(r.Subcategory),
+			Count: r.RegulationCount,
+		})
+	}
+
+	return facets, nil
+}
+
+// regulationFilterArgs converts a filter to the nullable query arguments.
+// The subcategory is dropped without a category, as it is only meaningful
+// within one.
+func regulationFilterArgs(filter domain.RegulationFilter) (category, subcategory, severity sql.NullString) {
+	severity = domain.ToNullString(string(filter.Severity))
+	if filter.Category == "" {
+		return sql.NullString{}, sql.NullString{}, severity
+	}
+	return domain.ToNullString(filter.Category), domain.ToNullString(filter.Subcategory), severity
+}
+
 // =============================================================================
 // LinkToViolation
 // =============================================================================
@@ -243,7 +343,7 @@ func (s *regulationService) LinkToViolation(ctx context.Context, params domain.L
 	})
 	if err == nil {
 		// Already linked - success (idempotent)
-		s.logger.Debug("regulation already linked to violation",
+		s.logger.DebugContext(ctx, "regulation already linked to violation",
 			"violation_id", params.ViolationID,
 			"regulation_id", params.RegulationID,
 		)
@@ -275,7 +375,7 @@ func (s *regulationService) LinkToViolation(ctx context.Context, params domain.L
 		return domain.Internal(err, op, "failed to link regulation to violation")
 	}
 
-	s.logger.Info("regulation linked to violation",
+	s.logger.InfoContext(ctx, "regulation linked to violation",
 		"violation_id", params.ViolationID,
 		"regulation_id", params.RegulationID,
 		"user_id", params.UserID,
@@ -314,7 +414,7 @@ func (s *regulationService) UnlinkFromViolation(ctx context.Context, params doma
 		return domain.Internal(err, op, "failed to unlink regulation from violation")
 	}
 
-	s.logger.Info("regulation unlinked from violation",
+	s.logger.InfoContext(ctx, "regulation unlinked from violation",
 		"violation_id", params.ViolationID,
 		"regulation_id", params.RegulationID,
 		"user_id", params.UserID,
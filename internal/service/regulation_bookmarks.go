@@ -0,0 +1,136 @@
+// Package service contains the business logic layer.
+//
+// This file implements regulation bookmarks: standards a user saves so they
+// are offered first when linking regulations to violations, alongside the
+// standards the user links most often.
+package service
+
+import (
+	"context"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// AddBookmark / RemoveBookmark
+// =============================================================================
+
+// AddBookmark bookmarks a regulation for the user.
+func (s *regulationService) AddBookmark(ctx context.Context, userID, regulationID uuid.UUID) error {
+	const op = "regulation.add_bookmark"
+
+	// Verify the regulation exists
+	if _, err := s.GetByID(ctx, regulationID); err != nil {
+		return err
+	}
+
+	err := s.queries.AddRegulationBookmark(ctx, repository.AddRegulationBookmarkParams{
+		UserID:       userID,
+		RegulationID: regulationID,
+	})
+	if err != nil {
+		return domain.Internal(err, op, "failed to add regulation bookmark")
+	}
+
+	s.logger.InfoContext(ctx, "regulation bookmarked",
+		"user_id", userID,
+		"regulation_id", regulationID,
+	)
+
+	return nil
+}
+
+// RemoveBookmark removes a regulation from the user's bookmarks.
+func (s *regulationService) RemoveBookmark(ctx context.Context, userID, regulationID uuid.UUID) error {
+	const op = "regulation.remove_bookmark"
+
+	err := s.queries.RemoveRegulationBookmark(ctx, repository.RemoveRegulationBookmarkParams{
+		UserID:       userID,
+		RegulationID: regulationID,
+	})
+	if err != nil {
+		return domain.Internal(err, op, "failed to remove regulation bookmark")
+	}
+
+	s.logger.InfoContext(ctx, "regulation unbookmarked",
+		"user_id", userID,
+		"regulation_id", regulationID,
+	)
+
+	return nil
+}
+
+// =============================================================================
+// ListBookmarks / ListBookmarkIDs / ListMostUsed
+// =============================================================================
+
+// ListBookmarks returns the user's bookmarked regulations.
+func (s *regulationService) ListBookmarks(ctx context.Context, userID uuid.UUID) ([]domain.RegulationSummary, error) {
+	const op = "regulation.list_bookmarks"
+
+	rows, err := s.queries.ListBookmarkedRegulationsByUserID(ctx, userID)
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to list bookmarked regulations")
+	}
+
+	summaries := make([]domain.RegulationSummary, len(rows))
+	for i, r := range rows {
+		summaries[i] = domain.RegulationSummary{
+			ID:              r.ID,
+			StandardNumber:  r.StandardNumber,
+			Title:           r.Title,
+			Category:        r.Category,
+			Subcategory:     domain.NullStringValue(r.Subcategory),
+			Summary:         domain.NullStringValue(r.Summary),
+			SeverityTypical: domain.NullStringValue(r.SeverityTypical),
+		}
+	}
+	return summaries, nil
+}
+
+// ListBookmarkIDs returns the IDs of the user's bookmarked regulations.
+func (s *regulationService) ListBookmarkIDs(ctx context.Context, userID uuid.UUID) (map[uuid.UUID]bool, error) {
+	const op = "regulation.list_bookmark_ids"
+
+	ids, err := s.queries.ListBookmarkedRegulationIDsByUserID(ctx, userID)
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to list bookmarked regulations")
+	}
+
+	bookmarks := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		bookmarks[id] = true
+	}
+	return bookmarks, nil
+}
+
+// ListMostUsed returns the regulations most often linked to the user's
+// violations, excluding bookmarks.
+func (s *regulationService) ListMostUsed(ctx context.Context, userID uuid.UUID, limit int32) ([]domain.RegulationSummary, error) {
+	const op = "regulation.list_most_used"
+
+	rows, err := s.queries.ListMostUsedRegulationsByUserID(ctx, repository.ListMostUsedRegulationsByUserIDParams{
+		UserID: userID,
+		Limit:  limit,
+	})
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to list most used regulations")
+	}
+
+	summaries := make([]domain.RegulationSummary, len(rows))
+	for i, r := range rows {
+		summaries[i] = domain.RegulationSummary{
+			ID:              r.ID,
+			StandardNumber:  r.StandardNumber,
+			Title:           r.Title,
+			Category:        r.Category,
+			Subcategory:     domain.NullStringValue(r.Subcategory),
+			Summary:         domain.NullStringValue(r.Summary),
+			SeverityTypical: domain.NullStringValue(r.SeverityTypical),
+			UseCount:        r.UseCount,
+		}
+	}
+	return summaries, nil
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/DukeRupert/lukaut/internal/testutil/fakedb"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// In-Memory Regulation Browse Database
+// =============================================================================
+
+// fakeBrowseRegulation is an active regulation with the columns the browse
+// and search filters match on.
+type fakeBrowseRegulation struct {
+	standardNumber string
+	category       string
+	subcategory    string
+	severity       string
+}
+
+// fakeBrowseDB answers the regulation browse and search queries, applying
+// the nullable category, subcategory, and severity arguments the way the SQL
+// does. Every regulation matches the text query.
+type fakeBrowseDB struct {
+	regulations []fakeBrowseRegulation
+}
+
+func (f *fakeBrowseDB) Query(q fakedb.Query) (*fakedb.Rows, error) {
+	args := q.Args
+	switch q.Name {
+	case "ListRegulations":
+		// $1 limit, $2 offset, $3 category, $4 subcategory, $5 severity
+		matched := f.match(args[2].Value, args[3].Value, args[4].Value)
+		return browseRows(browsePage(matched, args[0].Value, args[1].Value), false), nil
+	case "CountRegulations":
+		return countRows(f.match(args[0].Value, args[1].Value, args[2].Value)), nil
+	case "SearchRegulationsWithOffset":
+		// $1 query, $2 limit, $3 offset, $4 category, $5 subcategory, $6 severity
+		matched := f.match(args[3].Value, args[4].Value, args[5].Value)
+		return browseRows(browsePage(matched, args[1].Value, args[2].Value), true), nil
+	case "CountSearchResults":
+		return countRows(f.match(args[1].Value, args[2].Value, args[3].Value)), nil
+	}
+	return nil, fmt.Errorf("fakeBrowseDB: unexpected query %q", q.Name)
+}
+
+// match returns the regulations equal to every non-NULL argument.
+func (f *fakeBrowseDB) match(category, subcategory, severity driver.Value) []fakeBrowseRegulation {
+	var matched []fakeBrowseRegulation
+	for _, r := range f.regulations {
+		if matchesArg(category, r.category) && matchesArg(subcategory, r.subcategory) && matchesArg(severity, r.severity) {
+			matched = append(matched, r)
+		}
+	}
+	return matched
+}
+
+func matchesArg(arg driver.Value, value string) bool {
+	return arg == nil || arg.(string) == value
+}
+
+func browsePage(regs []fakeBrowseRegulation, limit, offset driver.Value) []fakeBrowseRegulation {
+	start := min(int(offset.(int64)), len(regs))
+	end := min(start+int(limit.(int64)), len(regs))
+	return regs[start:end]
+}
+
+// browseRows returns regulations in ListRegulationsRow column order, with a
+// trailing rank for search results.
+func browseRows(regs []fakeBrowseRegulation, ranked bool) *fakedb.Rows {
+	rows := &fakedb.Rows{Columns: 7}
+	if ranked {
+		rows.Columns = 8
+	}
+	for _, r := range regs {
+		row := []driver.Value{uuid.New().String(), r.standardNumber, r.standardNumber, r.category, r.subcategory, nil, r.severity}
+		if ranked {
+			row = append(row, float64(0.5))
+		}
+		rows.Values = append(rows.Values, row)
+	}
+	return rows
+}
+
+func countRows(regs []fakeBrowseRegulation) *fakedb.Rows {
+	return &fakedb.Rows{Columns: 1, Values: [][]driver.Value{{int64(len(regs))}}}
+}
+
+func newBrowseTestService() RegulationService {
+	db := fakedb.Open(&fakeBrowseDB{regulations: []fakeBrowseRegulation{
+		{"1926.501(b)(1)", "Fall Protection", "Duty to Have Fall Protection", "critical"},
+		{"1926.501(b)(2)", "Fall Protection", "Duty to Have Fall Protection", "serious"},
+		{"1926.502(b)", "Fall Protection", "Guardrails & Nets", "serious"},
+		{"1926.502(c)", "Fall Protection", "Guardrails & Nets", "serious"},
+		{"1926.451(b)", "Scaffolding", "Scaffold Platforms", "serious"},
+		{"1926.451(g)", "Scaffolding", "Scaffold Platforms", "critical"},
+	}})
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewRegulationService(repository.New(db), logger)
+}
+
+func standardNumbers(result *domain.RegulationSearchResult) []string {
+	numbers := make([]string, len(result.Regulations))
+	for i, r := range result.Regulations {
+		numbers[i] = r.StandardNumber
+	}
+	return numbers
+}
+
+// =============================================================================
+// Tests
+// =============================================================================
+
+func TestRegulationBrowseAndSearch_CombinedFilters(t *testing.T) {
+	svc := newBrowseTestService()
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		filter    domain.RegulationFilter
+		wantTotal int64
+		wantFirst string
+	}{
+		{
+			name:      "no filter",
+			wantTotal: 6,
+			wantFirst: "1926.501(b)(1)",
+		},
+		{
+			name:      "severity alone",
+			filter:    domain.RegulationFilter{Severity: domain.ViolationSeverityCritical},
+			wantTotal: 2,
+			wantFirst: "1926.501(b)(1)",
+		},
+		{
+			name:      "category and severity",
+			filter:    domain.RegulationFilter{Category: "Scaffolding", Severity: domain.ViolationSeveritySerious},
+			wantTotal: 1,
+			wantFirst: "1926.451(b)",
+		},
+		{
+			name: "category, subcategory, and severity",
+			filter: domain.RegulationFilter{
+				Category:    "Fall Protection",
+				Subcategory: "Guardrails & Nets",
+				Severity:    domain.ViolationSeveritySerious,
+			},
+			wantTotal: 2,
+			wantFirst: "1926.502(b)",
+		},
+		{
+			name:      "subcategory without category is ignored",
+			filter:    domain.RegulationFilter{Subcategory: "Guardrails & Nets", Severity: domain.ViolationSeverityCritical},
+			wantTotal: 2,
+			wantFirst: "1926.501(b)(1)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			browse, err := svc.Browse(ctx, tt.filter, 1, 0)
+			if err != nil {
+				t.Fatalf("Browse failed: %v", err)
+			}
+			search, err := svc.Search(ctx, "protection", tt.filter, 1, 0)
+			if err != nil {
+				t.Fatalf("Search failed: %v", err)
+			}
+
+			for mode, result := range map[string]*domain.RegulationSearchResult{"browse": browse, "search": search} {
+				// The total counts every match, not just the page returned
+				if result.Total != tt.wantTotal {
+					t.Errorf("%s: expected total %d, got %d", mode, tt.wantTotal, result.Total)
+				}
+				if got := standardNumbers(result); len(got) != 1 || got[0] != tt.wantFirst {
+					t.Errorf("%s: expected page [%s], got %v", mode, tt.wantFirst, got)
+				}
+			}
+		})
+	}
+}
+
+func TestRegulationBrowse_SeverityPages(t *testing.T) {
+	svc := newBrowseTestService()
+
+	result, err := svc.Browse(context.Background(), domain.RegulationFilter{Severity: domain.ViolationSeveritySerious}, 2, 2)
+	if err != nil {
+		t.Fatalf("Browse failed: %v", err)
+	}
+	if result.Total != 4 {
+		t.Errorf("expected total 4, got %d", result.Total)
+	}
+	got := standardNumbers(result)
+	if len(got) != 2 || got[0] != "1926.502(c)" || got[1] != "1926.451(b)" {
+		t.Errorf("expected second page [1926.502(c) 1926.451(b)], got %v", got)
+	}
+	for _, r := range result.Regulations {
+		if r.SeverityTypical != "serious" {
+			t.Errorf("expected only serious regulations, got %s with %q", r.StandardNumber, r.SeverityTypical)
+		}
+	}
+}
@@ -0,0 +1,398 @@
+// Package service contains the business logic layer.
+//
+// This file implements the regulation import: loading an OSHA standards
+// export into the regulations table so the reference data can follow
+// upstream changes.
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+)
+
+// =============================================================================
+// Interface Definition
+// =============================================================================
+
+// RegulationImportService loads OSHA standards exports into the regulations table.
+type RegulationImportService interface {
+	// Import upserts the standards in r by standard number and deactivates
+	// active standards that are missing from it. Deactivated standards are
+	// kept so existing violation links stay valid, and are reactivated if a
+	// later import includes them again. Re-running the same import changes
+	// nothing.
+	//
+	// Malformed rows are skipped and reported in the summary's Errors; they
+	// do not abort the import. Standards named by a malformed row are left
+	// as they are rather than deactivated. The summary is recorded in
+	// regulation_imports.
+	//
+	// Returns domain.EINVALID if the file cannot be read as the given format
+	// or contains no valid rows.
+	Import(ctx context.Context, source string, format domain.RegulationImportFormat, r io.Reader) (*domain.RegulationImportSummary, error)
+
+	// Merge upserts the standards in r like Import but leaves standards
+	// missing from it alone, so a file holding a few corrected standards
+	// can be loaded without deactivating the rest.
+	Merge(ctx context.Context, source string, format domain.RegulationImportFormat, r io.Reader) (*domain.RegulationImportSummary, error)
+}
+
+// =============================================================================
+// Implementation
+// =============================================================================
+
+// regulationImportService implements the RegulationImportService interface.
+type regulationImportService struct {
+	db      *sql.DB
+	queries *repository.Queries
+	logger  *slog.Logger
+}
+
+// NewRegulationImportService creates a new RegulationImportService.
+// The db handle is used to apply each import in one transaction.
+func NewRegulationImportService(
+	db *sql.DB,
+	queries *repository.Queries,
+	logger *slog.Logger,
+) RegulationImportService {
+	return &regulationImportService{
+		db:      db,
+		queries: queries,
+		logger:  logger,
+	}
+}
+
+// regulationImportDateLayout is the layout of effective_date and last_updated.
+const regulationImportDateLayout = "2006-01-02"
+
+// =============================================================================
+// Import
+// =============================================================================
+
+// Import applies an OSHA standards export to the regulations table.
+func (s *regulationImportService) Import(ctx context.Context, source string, format domain.RegulationImportFormat, r io.Reader) (*domain.RegulationImportSummary, error) {
+	return s.apply(ctx, source, format, r, true)
+}
+
+// Merge applies a partial standards file to the regulations table.
+func (s *regulationImportService) Merge(ctx context.Context, source string, format domain.RegulationImportFormat, r io.Reader) (*domain.RegulationImportSummary, error) {
+	return s.apply(ctx, source, format, r, false)
+}
+
+// apply upserts the parsed standards in one transaction, deactivating
+// standards missing from the file if deactivateMissing is set.
+func (s *regulationImportService) apply(ctx context.Context, source string, format domain.RegulationImportFormat, r io.Reader, deactivateMissing bool) (*domain.RegulationImportSummary, error) {
+	const op = "regulation.import"
+
+	parsed, err := parseRegulationImport(format, r)
+	if err != nil {
+		return nil, domain.Invalid(op, err.Error())
+	}
+	if len(parsed.records) == 0 {
+		return nil, domain.Invalid(op, fmt.Sprintf("no valid regulations in import (%d malformed rows)", len(parsed.errors)))
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to begin transaction")
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	qtx := s.queries.WithTx(tx)
+
+	summary := &domain.RegulationImportSummary{
+		Source: source,
+		Errors: parsed.errors,
+	}
+
+	for _, rec := range parsed.records {
+		inserted, err := qtx.UpsertRegulation(ctx, repository.UpsertRegulationParams{
+			StandardNumber:  rec.StandardNumber,
+			Title:           rec.Title,
+			Category:        rec.Category,
+			Subcategory:     domain.ToNullString(rec.Subcategory),
+			FullText:        rec.FullText,
+			Summary:         domain.ToNullString(rec.Summary),
+			SeverityTypical: domain.ToNullString(rec.SeverityTypical),
+			ParentStandard:  domain.ToNullString(rec.ParentStandard),
+			EffectiveDate:   nullTimeFromPtr(rec.EffectiveDate),
+			LastUpdated:     nullTimeFromPtr(rec.LastUpdated),
+		})
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			summary.Unchanged++
+		case err != nil:
+			return nil, domain.Internal(err, op, "failed to upsert regulation "+rec.StandardNumber)
+		case inserted:
+			summary.Added++
+		default:
+			summary.Updated++
+		}
+	}
+
+	if deactivateMissing {
+		deactivated, err := qtx.DeactivateRegulationsNotIn(ctx, parsed.standardNumbers)
+		if err != nil {
+			return nil, domain.Internal(err, op, "failed to deactivate removed regulations")
+		}
+		summary.Deactivated = int(deactivated)
+	}
+
+	if err := qtx.RefreshRegulationSearchVectors(ctx); err != nil {
+		return nil, domain.Internal(err, op, "failed to refresh search vectors")
+	}
+
+	row, err := qtx.CreateRegulationImport(ctx, repository.CreateRegulationImportParams{
+		Source:           source,
+		AddedCount:       int32(summary.Added),
+		UpdatedCount:     int32(summary.Updated),
+		UnchangedCount:   int32(summary.Unchanged),
+		DeactivatedCount: int32(summary.Deactivated),
+		Errors:           summary.Errors,
+	})
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to record import summary")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, domain.Internal(err, op, "failed to commit transaction")
+	}
+
+	summary.ID = row.ID
+	summary.CreatedAt = row.CreatedAt
+
+	s.logger.InfoContext(ctx, "regulations imported",
+		"import_id", row.ID,
+		"source", source,
+		"deactivate_missing", deactivateMissing,
+		"added", summary.Added,
+		"updated", summary.Updated,
+		"unchanged", summary.Unchanged,
+		"deactivated", summary.Deactivated,
+		"errors", len(summary.Errors),
+	)
+
+	return summary, nil
+}
+
+// =============================================================================
+// Parsing
+// =============================================================================
+
+// regulationImport is the result of parsing an import file.
+type regulationImport struct {
+	records         []domain.RegulationImportRecord
+	errors          []string // One entry per skipped row
+	standardNumbers []string // Every standard number named in the file, valid or not
+}
+
+// add validates a row and records it, or records why it was skipped.
+// label identifies the row in error messages.
+func (p *regulationImport) add(label string, fields map[string]string, seen map[string]bool) {
+	standardNumber := strings.TrimSpace(fields["standard_number"])
+	if standardNumber != "" {
+		if seen[standardNumber] {
+			p.errors = append(p.errors, fmt.Sprintf("%s (%s): duplicate standard number", label, standardNumber))
+			return
+		}
+		seen[standardNumber] = true
+		p.standardNumbers = append(p.standardNumbers, standardNumber)
+	}
+
+	rec, err := regulationImportRecord(fields)
+	if err != nil {
+		if standardNumber != "" {
+			label = fmt.Sprintf("%s (%s)", label, standardNumber)
+		}
+		p.errors = append(p.errors, fmt.Sprintf("%s: %v", label, err))
+		return
+	}
+	p.records = append(p.records, rec)
+}
+
+// parseRegulationImport reads an import file in the given format.
+// An error means the file as a whole could not be read.
+func parseRegulationImport(format domain.RegulationImportFormat, r io.Reader) (*regulationImport, error) {
+	switch format {
+	case domain.RegulationImportFormatCSV:
+		return parseRegulationCSV(r)
+	case domain.RegulationImportFormatJSON:
+		return parseRegulationJSON(r)
+	default:
+		return nil, fmt.Errorf("unsupported import format: %q", format)
+	}
+}
+
+// parseRegulationCSV reads a CSV file whose header row names the columns.
+// Rows are identified by line number in error messages.
+func parseRegulationCSV(r io.Reader) (*regulationImport, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1 // Row width is checked per row so one bad row doesn't abort the import
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read CSV header: %w", err)
+	}
+	for i := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff")))
+	}
+	if !slices.Contains(header, "standard_number") {
+		return nil, errors.New("CSV header must include standard_number")
+	}
+
+	parsed := &regulationImport{errors: []string{}}
+	seen := make(map[string]bool)
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				parsed.errors = append(parsed.errors, fmt.Sprintf("line %d: %v", parseErr.Line, parseErr.Err))
+				continue
+			}
+			return nil, fmt.Errorf("read CSV: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		label := fmt.Sprintf("line %d", line)
+		if len(row) != len(header) {
+			parsed.errors = append(parsed.errors, fmt.Sprintf("%s: expected %d fields, got %d", label, len(header), len(row)))
+			continue
+		}
+
+		fields := make(map[string]string, len(header))
+		for i, name := range header {
+			fields[name] = row[i]
+		}
+		parsed.add(label, fields, seen)
+	}
+
+	return parsed, nil
+}
+
+// parseRegulationJSON reads a JSON array of objects keyed by column name.
+// Rows are identified by their 1-based position in the array.
+func parseRegulationJSON(r io.Reader) (*regulationImport, error) {
+	var elements []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&elements); err != nil {
+		return nil, fmt.Errorf("decode JSON array: %w", err)
+	}
+
+	parsed := &regulationImport{errors: []string{}}
+	seen := make(map[string]bool)
+	for i, element := range elements {
+		label := fmt.Sprintf("record %d", i+1)
+
+		var values map[string]*string
+		if err := json.Unmarshal(element, &values); err != nil {
+			parsed.errors = append(parsed.errors, fmt.Sprintf("%s: not an object of string values", label))
+			continue
+		}
+
+		fields := make(map[string]string, len(values))
+		for name, value := range values {
+			if value != nil {
+				fields[name] = *value
+			}
+		}
+		parsed.add(label, fields, seen)
+	}
+
+	return parsed, nil
+}
+
+// regulationImportRecord validates a row's fields against the regulations
+// schema so a bad row is reported instead of failing the transaction.
+func regulationImportRecord(fields map[string]string) (domain.RegulationImportRecord, error) {
+	value := func(name string) string { return strings.TrimSpace(fields[name]) }
+
+	rec := domain.RegulationImportRecord{
+		StandardNumber:  value("standard_number"),
+		Title:           value("title"),
+		Category:        value("category"),
+		Subcategory:     value("subcategory"),
+		FullText:        value("full_text"),
+		Summary:         value("summary"),
+		SeverityTypical: strings.ToLower(value("severity_typical")),
+		ParentStandard:  value("parent_standard"),
+	}
+
+	required := []struct {
+		name, value string
+	}{
+		{"standard_number", rec.StandardNumber},
+		{"title", rec.Title},
+		{"category", rec.Category},
+		{"full_text", rec.FullText},
+	}
+	for _, field := range required {
+		if field.value == "" {
+			return rec, fmt.Errorf("%s is required", field.name)
+		}
+	}
+
+	limits := []struct {
+		name, value string
+		max         int
+	}{
+		{"standard_number", rec.StandardNumber, 50},
+		{"title", rec.Title, 255},
+		{"category", rec.Category, 100},
+		{"subcategory", rec.Subcategory, 100},
+		{"severity_typical", rec.SeverityTypical, 20},
+		{"parent_standard", rec.ParentStandard, 50},
+	}
+	for _, field := range limits {
+		if len(field.value) > field.max {
+			return rec, fmt.Errorf("%s must be %d characters or less", field.name, field.max)
+		}
+	}
+
+	if rec.SeverityTypical != "" && !domain.ViolationSeverity(rec.SeverityTypical).IsValid() {
+		return rec, fmt.Errorf("invalid severity_typical: %s", rec.SeverityTypical)
+	}
+
+	var err error
+	if rec.EffectiveDate, err = parseRegulationImportDate(value("effective_date")); err != nil {
+		return rec, fmt.Errorf("invalid effective_date: %w", err)
+	}
+	if rec.LastUpdated, err = parseRegulationImportDate(value("last_updated")); err != nil {
+		return rec, fmt.Errorf("invalid last_updated: %w", err)
+	}
+
+	return rec, nil
+}
+
+// parseRegulationImportDate parses an optional YYYY-MM-DD date.
+func parseRegulationImportDate(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(regulationImportDateLayout, s)
+	if err != nil {
+		return nil, fmt.Errorf("expected YYYY-MM-DD, got %q", s)
+	}
+	return &t, nil
+}
+
+// nullTimeFromPtr converts an optional time to sql.NullTime.
+func nullTimeFromPtr(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *t, Valid: true}
+}
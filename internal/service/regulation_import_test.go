@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/DukeRupert/lukaut/internal/testutil/fakedb"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// =============================================================================
+// In-Memory Regulations Database
+// =============================================================================
+
+// fakeRegulation is a stored regulation: the ten UpsertRegulation arguments
+// and whether it has been deactivated.
+type fakeRegulation struct {
+	values      []driver.Value
+	deactivated bool
+}
+
+// fakeRegulationsDB answers the queries used by the regulation import,
+// dispatching on the "-- name:" header.
+type fakeRegulationsDB struct {
+	regulations map[string]*fakeRegulation
+	refreshes   int
+	imports     []string // Source of each recorded import
+}
+
+func (f *fakeRegulationsDB) Query(q fakedb.Query) (*fakedb.Rows, error) {
+	args := q.Args
+
+	switch q.Name {
+	case "UpsertRegulation":
+		values := make([]driver.Value, len(args))
+		for i, arg := range args {
+			values[i] = arg.Value
+		}
+		standardNumber := values[0].(string)
+		existing, ok := f.regulations[standardNumber]
+		if !ok {
+			f.regulations[standardNumber] = &fakeRegulation{values: values}
+			return &fakedb.Rows{Columns: 1, Values: [][]driver.Value{{true}}}, nil
+		}
+		// Mirrors the ON CONFLICT ... WHERE: identical active rows are left alone
+		if !existing.deactivated && reflect.DeepEqual(existing.values, values) {
+			return &fakedb.Rows{Columns: 1}, nil
+		}
+		existing.values = values
+		existing.deactivated = false
+		return &fakedb.Rows{Columns: 1, Values: [][]driver.Value{{false}}}, nil
+	case "CreateRegulationImport":
+		f.imports = append(f.imports, args[0].Value.(string))
+		row := []driver.Value{uuid.New().String(), args[0].Value, args[1].Value, args[2].Value, args[3].Value, args[4].Value, args[5].Value, time.Now()}
+		return &fakedb.Rows{Columns: 8, Values: [][]driver.Value{row}}, nil
+	}
+	return nil, fmt.Errorf("fakeRegulationsDB: unexpected query %q", q.Name)
+}
+
+func (f *fakeRegulationsDB) Exec(q fakedb.Query) (int64, error) {
+	args := q.Args
+
+	switch q.Name {
+	case "DeactivateRegulationsNotIn":
+		var keep pq.StringArray
+		if err := keep.Scan(args[0].Value); err != nil {
+			return 0, err
+		}
+		var n int64
+		for standardNumber, reg := range f.regulations {
+			if !reg.deactivated && !slices.Contains(keep, standardNumber) {
+				reg.deactivated = true
+				n++
+			}
+		}
+		return n, nil
+	case "RefreshRegulationSearchVectors":
+		f.refreshes++
+		return 0, nil
+	}
+	return 0, fmt.Errorf("fakeRegulationsDB: unexpected exec %q", q.Name)
+}
+
+func newRegulationImportTestService() (RegulationImportService, *fakeRegulationsDB) {
+	f := &fakeRegulationsDB{regulations: map[string]*fakeRegulation{}}
+	db := fakedb.Open(f)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewRegulationImportService(db, repository.New(db), logger), f
+}
+
+func importCSV(t *testing.T, svc RegulationImportService, csv string) *domain.RegulationImportSummary {
+	t.Helper()
+	summary, err := svc.Import(context.Background(), "standards.csv", domain.RegulationImportFormatCSV, strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	return summary
+}
+
+func assertImportCounts(t *testing.T, s *domain.RegulationImportSummary, added, updated, unchanged, deactivated int) {
+	t.Helper()
+	got := []int{s.Added, s.Updated, s.Unchanged, s.Deactivated}
+	want := []int{added, updated, unchanged, deactivated}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected added/updated/unchanged/deactivated %v, got %v", want, got)
+	}
+}
+
+const regulationCSVHeader = "standard_number,title,category,full_text,severity_typical,effective_date\n"
+
+// =============================================================================
+// Regulation Import Tests
+// =============================================================================
+
+func TestRegulationImport_IdempotentOnRerun(t *testing.T) {
+	svc, f := newRegulationImportTestService()
+	csv := regulationCSVHeader +
+		"1926.501(b)(1),Unprotected Sides and Edges,Fall Protection,Each employee...,serious,2024-01-01\n" +
+		"1926.502(d),Personal Fall Arrest Systems,Fall Protection,Personal fall arrest...,,\n"
+
+	first := importCSV(t, svc, csv)
+	assertImportCounts(t, first, 2, 0, 0, 0)
+
+	second := importCSV(t, svc, csv)
+	assertImportCounts(t, second, 0, 0, 2, 0)
+
+	if len(f.imports) != 2 {
+		t.Errorf("expected each run to record an import, got %d", len(f.imports))
+	}
+	if f.refreshes != 2 {
+		t.Errorf("expected search vectors to be refreshed after each run, got %d", f.refreshes)
+	}
+}
+
+func TestRegulationImport_DeactivatesMissingAndReactivates(t *testing.T) {
+	svc, f := newRegulationImportTestService()
+	importCSV(t, svc, regulationCSVHeader+
+		"1926.501(b)(1),Unprotected Sides and Edges,Fall Protection,Each employee...,serious,\n"+
+		"1926.502(d),Personal Fall Arrest Systems,Fall Protection,Personal fall arrest...,,\n")
+
+	// 1926.502(d) is dropped and 1926.501(b)(1) changes
+	summary := importCSV(t, svc, regulationCSVHeader+
+		"1926.501(b)(1),Unprotected Sides and Edges,Fall Protection,Revised text,critical,\n")
+	assertImportCounts(t, summary, 0, 1, 0, 1)
+	if !f.regulations["1926.502(d)"].deactivated {
+		t.Error("expected missing standard to be deactivated, not deleted")
+	}
+
+	// Restoring the standard reactivates it
+	summary = importCSV(t, svc, regulationCSVHeader+
+		"1926.501(b)(1),Unprotected Sides and Edges,Fall Protection,Revised text,critical,\n"+
+		"1926.502(d),Personal Fall Arrest Systems,Fall Protection,Personal fall arrest...,,\n")
+	assertImportCounts(t, summary, 0, 1, 1, 0)
+	if f.regulations["1926.502(d)"].deactivated {
+		t.Error("expected restored standard to be reactivated")
+	}
+}
+
+func TestRegulationMerge_KeepsMissingActive(t *testing.T) {
+	svc, f := newRegulationImportTestService()
+	importCSV(t, svc, regulationCSVHeader+
+		"1926.501(b)(1),Unprotected Sides and Edges,Fall Protection,Each employee...,serious,\n"+
+		"1926.502(d),Personal Fall Arrest Systems,Fall Protection,Personal fall arrest...,,\n")
+
+	summary, err := svc.Merge(context.Background(), "corrections.csv", domain.RegulationImportFormatCSV, strings.NewReader(regulationCSVHeader+
+		"1926.501(b)(1),Unprotected Sides and Edges,Fall Protection,Revised text,critical,\n"+
+		"1926.503(a),Training Requirements,Fall Protection,Training program...,other,\n"))
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+
+	assertImportCounts(t, summary, 1, 1, 0, 0)
+	if f.regulations["1926.502(d)"].deactivated {
+		t.Error("expected a standard missing from a merge to stay active")
+	}
+	if f.refreshes != 2 || len(f.imports) != 2 {
+		t.Errorf("expected the merge to refresh search vectors and record an import, got %d refreshes and %d imports", f.refreshes, len(f.imports))
+	}
+}
+
+func TestRegulationImport_CollectsMalformedRows(t *testing.T) {
+	svc, f := newRegulationImportTestService()
+	importCSV(t, svc, regulationCSVHeader+
+		"1926.451(g)(1),Fall Protection on Scaffolds,Scaffolding,Each employee on a scaffold...,serious,\n")
+
+	summary := importCSV(t, svc, regulationCSVHeader+
+		"1926.501(b)(1),Unprotected Sides and Edges,Fall Protection,Each employee...,serious,\n"+
+		"1926.451(g)(1),,Scaffolding,Each employee on a scaffold...,serious,\n"+ // missing title
+		"1926.502(d),Personal Fall Arrest Systems,Fall Protection,Personal fall arrest...,,01/02/2024\n"+
+		"1926.503(a),Training,Fall Protection,Training program...,urgent,\n"+
+		"1926.1053(b),Ladders\n"+
+		"1926.501(b)(1),Unprotected Sides and Edges,Fall Protection,Each employee...,serious,\n")
+
+	assertImportCounts(t, summary, 1, 0, 0, 0)
+	want := []string{
+		"line 3 (1926.451(g)(1)): title is required",
+		`line 4 (1926.502(d)): invalid effective_date: expected YYYY-MM-DD, got "01/02/2024"`,
+		"line 5 (1926.503(a)): invalid severity_typical: urgent",
+		"line 6: expected 6 fields, got 2",
+		"line 7 (1926.501(b)(1)): duplicate standard number",
+	}
+	if !reflect.DeepEqual(summary.Errors, want) {
+		t.Errorf("expected errors:\n%s\ngot:\n%s", strings.Join(want, "\n"), strings.Join(summary.Errors, "\n"))
+	}
+	if f.regulations["1926.451(g)(1)"].deactivated {
+		t.Error("expected standard named by a malformed row to stay active")
+	}
+}
+
+func TestRegulationImport_JSON(t *testing.T) {
+	svc, f := newRegulationImportTestService()
+	body := `[
+		{"standard_number": "1926.501(b)(1)", "title": "Unprotected Sides and Edges", "category": "Fall Protection", "full_text": "Each employee...", "summary": null},
+		{"standard_number": "1926.502(d)", "title": 42},
+		{"standard_number": "1926.503(a)", "category": "Fall Protection"}
+	]`
+
+	summary, err := svc.Import(context.Background(), "standards.json", domain.RegulationImportFormatJSON, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+
+	assertImportCounts(t, summary, 1, 0, 0, 0)
+	want := []string{
+		"record 2: not an object of string values",
+		"record 3 (1926.503(a)): title is required",
+	}
+	if !reflect.DeepEqual(summary.Errors, want) {
+		t.Errorf("expected errors %q, got %q", want, summary.Errors)
+	}
+	if _, ok := f.regulations["1926.501(b)(1)"]; !ok {
+		t.Error("expected valid record to be imported")
+	}
+}
+
+func TestRegulationImport_NoValidRows(t *testing.T) {
+	svc, f := newRegulationImportTestService()
+	f.regulations["1926.501(b)(1)"] = &fakeRegulation{}
+
+	_, err := svc.Import(context.Background(), "standards.csv", domain.RegulationImportFormatCSV,
+		strings.NewReader(regulationCSVHeader+"1926.502(d),,,,,\n"))
+
+	if domain.ErrorCode(err) != domain.EINVALID {
+		t.Fatalf("expected EINVALID, got %v", err)
+	}
+	if f.regulations["1926.501(b)(1)"].deactivated || len(f.imports) != 0 {
+		t.Error("expected a file with no valid rows to change nothing")
+	}
+}
@@ -0,0 +1,66 @@
+// Package service contains the business logic layer.
+//
+// This file implements recently viewed regulations: the last few standards a
+// user opened, offered on the regulations index so they are easy to return to.
+package service
+
+import (
+	"context"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/google/uuid"
+)
+
+// RecordView records that the user viewed a regulation. Viewing a regulation
+// again moves it to the front, and views beyond
+// domain.RecentRegulationViewsLimit are dropped.
+func (s *regulationService) RecordView(ctx context.Context, userID, regulationID uuid.UUID) error {
+	const op = "regulation.record_view"
+
+	err := s.queries.RecordRegulationView(ctx, repository.RecordRegulationViewParams{
+		UserID:       userID,
+		RegulationID: regulationID,
+	})
+	if err != nil {
+		return domain.Internal(err, op, "failed to record regulation view")
+	}
+
+	err = s.queries.TrimRegulationViews(ctx, repository.TrimRegulationViewsParams{
+		UserID: userID,
+		Limit:  domain.RecentRegulationViewsLimit,
+	})
+	if err != nil {
+		return domain.Internal(err, op, "failed to trim regulation views")
+	}
+
+	return nil
+}
+
+// ListRecentlyViewed returns the user's recently viewed regulations, most
+// recent first.
+func (s *regulationService) ListRecentlyViewed(ctx context.Context, userID uuid.UUID) ([]domain.RegulationSummary, error) {
+	const op = "regulation.list_recently_viewed"
+
+	rows, err := s.queries.ListRecentlyViewedRegulationsByUserID(ctx, repository.ListRecentlyViewedRegulationsByUserIDParams{
+		UserID: userID,
+		Limit:  domain.RecentRegulationViewsLimit,
+	})
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to list recently viewed regulations")
+	}
+
+	summaries := make([]domain.RegulationSummary, len(rows))
+	for i, r := range rows {
+		summaries[i] = domain.RegulationSummary{
+			ID:              r.ID,
+			StandardNumber:  r.StandardNumber,
+			Title:           r.Title,
+			Category:        r.Category,
+			Subcategory:     domain.NullStringValue(r.Subcategory),
+			Summary:         domain.NullStringValue(r.Summary),
+			SeverityTypical: domain.NullStringValue(r.SeverityTypical),
+		}
+	}
+	return summaries, nil
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/DukeRupert/lukaut/internal/testutil/fakedb"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// In-Memory Regulation Views Database
+// =============================================================================
+
+// fakeViewsDB keeps each user's regulation views as a slice, most recent
+// first, standing in for the viewed_at ordering of user_regulation_views.
+type fakeViewsDB struct {
+	regulations map[string]string // ID to standard number
+	views       map[string][]string
+}
+
+func (f *fakeViewsDB) Exec(q fakedb.Query) (int64, error) {
+	args := q.Args
+
+	userID := args[0].Value.(string)
+	switch q.Name {
+	case "RecordRegulationView":
+		regulationID := args[1].Value.(string)
+		views := slices.DeleteFunc(f.views[userID], func(id string) bool { return id == regulationID })
+		f.views[userID] = append([]string{regulationID}, views...)
+		return 1, nil
+	case "TrimRegulationViews":
+		limit := int(args[1].Value.(int64))
+		if views := f.views[userID]; len(views) > limit {
+			f.views[userID] = views[:limit]
+			return int64(len(views) - limit), nil
+		}
+		return 0, nil
+	}
+	return 0, fmt.Errorf("fakeViewsDB: unexpected exec %q", q.Name)
+}
+
+func (f *fakeViewsDB) Query(q fakedb.Query) (*fakedb.Rows, error) {
+	args := q.Args
+
+	if q.Name != "ListRecentlyViewedRegulationsByUserID" {
+		return nil, fmt.Errorf("fakeViewsDB: unexpected query %q", q.Name)
+	}
+	views := f.views[args[0].Value.(string)]
+	views = views[:min(len(views), int(args[1].Value.(int64)))]
+
+	rows := &fakedb.Rows{Columns: 7}
+	for _, id := range views {
+		standard := f.regulations[id]
+		rows.Values = append(rows.Values, []driver.Value{id, standard, standard, "Fall Protection", nil, nil, nil})
+	}
+	return rows, nil
+}
+
+func newViewsTestService(regulationCount int) (RegulationService, []uuid.UUID, *fakeViewsDB) {
+	f := &fakeViewsDB{regulations: map[string]string{}, views: map[string][]string{}}
+	ids := make([]uuid.UUID, regulationCount)
+	for i := range ids {
+		ids[i] = uuid.New()
+		f.regulations[ids[i].String()] = fmt.Sprintf("1926.%d", 500+i)
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewRegulationService(repository.New(fakedb.Open(f)), logger), ids, f
+}
+
+func recentIDs(t *testing.T, svc RegulationService, userID uuid.UUID) []uuid.UUID {
+	t.Helper()
+	recent, err := svc.ListRecentlyViewed(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("ListRecentlyViewed failed: %v", err)
+	}
+	ids := make([]uuid.UUID, len(recent))
+	for i, r := range recent {
+		ids[i] = r.ID
+	}
+	return ids
+}
+
+// =============================================================================
+// Tests
+// =============================================================================
+
+func TestRegulationViews_MostRecentFirst(t *testing.T) {
+	svc, ids, _ := newViewsTestService(3)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	for _, id := range []uuid.UUID{ids[0], ids[1], ids[2], ids[0]} {
+		if err := svc.RecordView(ctx, userID, id); err != nil {
+			t.Fatalf("RecordView failed: %v", err)
+		}
+	}
+
+	// Viewing a regulation again moves it to the front without duplicating it
+	want := []uuid.UUID{ids[0], ids[2], ids[1]}
+	if got := recentIDs(t, svc, userID); !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	if got := recentIDs(t, svc, uuid.New()); len(got) != 0 {
+		t.Errorf("expected another user to have no recent views, got %v", got)
+	}
+}
+
+func TestRegulationViews_CappedAtLimit(t *testing.T) {
+	limit := domain.RecentRegulationViewsLimit
+	svc, ids, f := newViewsTestService(limit + 3)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	for _, id := range ids {
+		if err := svc.RecordView(ctx, userID, id); err != nil {
+			t.Fatalf("RecordView failed: %v", err)
+		}
+	}
+
+	got := recentIDs(t, svc, userID)
+	if len(got) != limit {
+		t.Fatalf("expected %d recent views, got %d", limit, len(got))
+	}
+	if got[0] != ids[len(ids)-1] || got[limit-1] != ids[3] {
+		t.Errorf("expected the newest %d views, newest first, got %v", limit, got)
+	}
+	// Older views are trimmed from storage, not just hidden
+	if stored := len(f.views[userID.String()]); stored != limit {
+		t.Errorf("expected %d stored views after trimming, got %d", limit, stored)
+	}
+}
@@ -6,11 +6,16 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/report"
 	"github.com/DukeRupert/lukaut/internal/repository"
 	"github.com/DukeRupert/lukaut/internal/storage"
 	"github.com/google/uuid"
@@ -25,16 +30,49 @@ type ReportService interface {
 	// PrepareReportData aggregates all data needed for report generation.
 	PrepareReportData(ctx context.Context, inspectionID, userID uuid.UUID) (*domain.ReportData, error)
 
+	// PreviewReportData aggregates report data for an inline preview without
+	// creating a report record or storage object.
+	// Returns domain.ENOTFOUND if the inspection doesn't exist or doesn't belong to user.
+	// Returns domain.EINVALID if the inspection is not in review or completed status.
+	PreviewReportData(ctx context.Context, inspectionID, userID uuid.UUID) (*domain.ReportData, error)
+
+	// Preview returns what the inline report preview shows: a PDF rendered from
+	// the current report data for inspections with up to
+	// domain.MaxInlinePreviewViolations confirmed violations, reusing a recent
+	// render when the data is unchanged. Larger inspections show their latest
+	// generated report, if any. Preview never creates a report or job.
+	// Returns domain.ENOTFOUND if the inspection doesn't exist or doesn't belong to user.
+	// Returns domain.EINVALID if the inspection is not in review or completed status.
+	Preview(ctx context.Context, inspectionID, userID uuid.UUID) (*domain.ReportPreview, error)
+
+	// Render writes the report for data in the given format to w and returns
+	// the number of bytes written. Shared by report generation jobs and the
+	// inline preview.
+	// Returns domain.EINVALID if the format is not pdf or docx.
+	Render(ctx context.Context, data *domain.ReportData, format domain.ReportFormat, w io.Writer) (int64, error)
+
 	// GetByID retrieves a report by ID with user authorization.
-	// Returns domain.ENOTFOUND if report doesn't exist or doesn't belong to user.
+	// Returns domain.ENOTFOUND if report doesn't exist or the user cannot
+	// access its inspection.
 	GetByID(ctx context.Context, id, userID uuid.UUID) (*domain.Report, error)
 
-	// ListByInspection returns all reports for an inspection owned by the user.
+	// ListByInspection returns all reports for an inspection the user can
+	// access, including organization members' shared inspections.
 	ListByInspection(ctx context.Context, inspectionID, userID uuid.UUID) ([]domain.Report, error)
 
-	// TriggerGeneration enqueues a job to generate a report.
-	// Returns domain.EINVALID if generation cannot proceed.
-	TriggerGeneration(ctx context.Context, inspectionID, userID uuid.UUID, format, recipientEmail string) error
+	// TriggerGeneration enqueues a job to generate a report and email it to
+	// the recipients, if any, copying the inspector. Recipients should come
+	// from domain.ParseReportRecipients. The job runs as the inspection's
+	// owner, whose quota it counts against.
+	// Returns domain.EINVALID if generation cannot proceed or there are more
+	// than domain.MaxReportRecipients recipients. Returns the ID of the job,
+	// which the user can follow with JobService.Get.
+	TriggerGeneration(ctx context.Context, inspectionID, userID uuid.UUID, format string, recipientEmails []string) (uuid.UUID, error)
+
+	// Summary counts violations by severity and status across the inspections
+	// of the user and their organization dated between from and to, inclusive.
+	// Returns domain.EINVALID if the range fails domain.ValidateSummaryRange.
+	Summary(ctx context.Context, userID uuid.UUID, from, to time.Time) (*domain.ViolationSummary, error)
 }
 
 // =============================================================================
@@ -46,15 +84,28 @@ type reportService struct {
 	storage      storage.Storage
 	jobEnqueuer  JobEnqueuer
 	quotaService QuotaService
+	audit        AuditService
+	preferences  PreferenceService
+	templates    ReportTemplateService
+	pdfGen       report.Generator
+	docxGen      report.Generator
+	previews     *reportPreviewCache
+	access       orgAccess
 	logger       *slog.Logger
 }
 
-// NewReportService creates a new ReportService.
+// NewReportService creates a new ReportService. Reports carry the
+// inspector's business name, logo and brand color, with branding filling in
+// whatever the inspector has not set, and the inspector's boilerplate
+// sections from the report template settings.
 func NewReportService(
 	queries *repository.Queries,
 	storage storage.Storage,
 	jobEnqueuer JobEnqueuer,
 	quotaService QuotaService,
+	audit AuditService,
+	preferences PreferenceService,
+	branding report.Branding,
 	logger *slog.Logger,
 ) ReportService {
 	return &reportService{
@@ -62,6 +113,13 @@ func NewReportService(
 		storage:      storage,
 		jobEnqueuer:  jobEnqueuer,
 		quotaService: quotaService,
+		audit:        audit,
+		preferences:  preferences,
+		templates:    NewReportTemplateService(queries, logger),
+		pdfGen:       report.NewHTMLPDFGenerator(branding, logger),
+		docxGen:      report.NewHTMLDOCXGenerator(branding, logger),
+		previews:     newReportPreviewCache(),
+		access:       orgAccess{queries: queries},
 		logger:       logger,
 	}
 }
@@ -70,8 +128,15 @@ func NewReportService(
 // PrepareReportData
 // =============================================================================
 
-// PrepareReportData aggregates all data needed for report generation.
+// PrepareReportData aggregates all data needed for report generation. The
+// report is the inspection owner's, with their business profile, even when
+// an organization member prepares it.
 func (s *reportService) PrepareReportData(ctx context.Context, inspectionID, userID uuid.UUID) (*domain.ReportData, error) {
+	userID, err := s.access.inspectionOwner(ctx, inspectionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
 	// Fetch user with business profile
 	user, err := s.queries.GetUserByID(ctx, userID)
 	if err != nil {
@@ -96,7 +161,7 @@ func (s *reportService) PrepareReportData(ctx context.Context, inspectionID, use
 			clientEmail = domain.NullStringValue(client.Email)
 			clientPhone = domain.NullStringValue(client.Phone)
 		} else {
-			s.logger.Warn("Failed to fetch client for inspection",
+			s.logger.WarnContext(ctx, "Failed to fetch client for inspection",
 				"inspection_id", inspectionID,
 				"client_id", inspection.ClientID.UUID,
 				"error", err,
@@ -119,7 +184,7 @@ func (s *reportService) PrepareReportData(ctx context.Context, inspectionID, use
 		// Fetch regulations for this violation
 		regs, err := s.queries.ListRegulationsByViolationID(ctx, v.ID)
 		if err != nil {
-			s.logger.Warn("Failed to fetch regulations for violation",
+			s.logger.WarnContext(ctx, "Failed to fetch regulations for violation",
 				"violation_id", v.ID,
 				"error", err,
 			)
@@ -146,6 +211,8 @@ func (s *reportService) PrepareReportData(ctx context.Context, inspectionID, use
 
 		// Get thumbnail URL if violation has image
 		thumbnailURL := ""
+		originalURL := ""
+		region := boundingBoxFromJSON(v.BoundingBox)
 		if v.ImageID.Valid {
 			img, err := s.queries.GetImageByID(ctx, v.ImageID.UUID)
 			if err == nil && img.ThumbnailKey.Valid {
@@ -153,13 +220,55 @@ func (s *reportService) PrepareReportData(ctx context.Context, inspectionID, use
 				if err == nil {
 					thumbnailURL = url
 				} else {
-					s.logger.Warn("Failed to generate thumbnail URL",
+					s.logger.WarnContext(ctx, "Failed to generate thumbnail URL",
+						"image_id", v.ImageID.UUID,
+						"error", err,
+					)
+				}
+			}
+			// The close-up is cut from the original so a small region stays sharp
+			if err == nil && region != nil && thumbnailURL != "" {
+				url, err := s.storage.URL(ctx, img.StorageKey, time.Hour)
+				if err == nil {
+					originalURL = url
+				} else {
+					s.logger.WarnContext(ctx, "Failed to generate original image URL",
 						"image_id", v.ImageID.UUID,
 						"error", err,
 					)
 				}
 			}
 		}
+		if originalURL == "" {
+			region = nil
+		} else {
+			zoomed := region.Zoom(domain.RegionZoomMargin)
+			region = &zoomed
+		}
+
+		// Get thumbnail URLs of the additional photos, in attached order
+		evidenceURLs := []string{}
+		evidence, err := s.queries.ListViolationImages(ctx, v.ID)
+		if err != nil {
+			s.logger.WarnContext(ctx, "Failed to fetch additional photos for violation",
+				"violation_id", v.ID,
+				"error", err,
+			)
+		}
+		for _, img := range evidence {
+			if !img.ThumbnailKey.Valid {
+				continue
+			}
+			url, err := s.storage.URL(ctx, img.ThumbnailKey.String, time.Hour)
+			if err != nil {
+				s.logger.WarnContext(ctx, "Failed to generate thumbnail URL",
+					"image_id", img.ImageID,
+					"error", err,
+				)
+				continue
+			}
+			evidenceURLs = append(evidenceURLs, url)
+		}
 
 		reportViolations = append(reportViolations, domain.ReportViolation{
 			Number:         i + 1,
@@ -167,6 +276,9 @@ func (s *reportService) PrepareReportData(ctx context.Context, inspectionID, use
 			Severity:       domain.ViolationSeverity(domain.NullStringValue(v.Severity)),
 			InspectorNotes: domain.NullStringValue(v.InspectorNotes),
 			ThumbnailURL:   thumbnailURL,
+			EvidenceURLs:   evidenceURLs,
+			Region:         region,
+			OriginalURL:    originalURL,
 			Regulations:    reportRegs,
 		})
 	}
@@ -191,7 +303,27 @@ func (s *reportService) PrepareReportData(ctx context.Context, inspectionID, use
 		inspectorEmail = user.Email
 	}
 
-	return &domain.ReportData{
+	// An uploaded logo takes precedence over a linked one
+	inspectorLogoURL := domain.NullStringValue(user.BusinessLogoUrl)
+	if user.BusinessLogoKey.Valid {
+		url, err := s.storage.URL(ctx, user.BusinessLogoKey.String, time.Hour)
+		if err == nil {
+			inspectorLogoURL = url
+		} else {
+			s.logger.WarnContext(ctx, "Failed to generate logo URL",
+				"user_id", userID,
+				"error", err,
+			)
+		}
+	}
+
+	// Measurements and dates are written the way the inspector reads them
+	format := domain.DefaultDisplayFormat()
+	if s.preferences != nil {
+		format = s.preferences.DisplayFormat(ctx, userID)
+	}
+
+	data := &domain.ReportData{
 		// Inspector info
 		InspectorName:    inspectorName,
 		InspectorCompany: domain.NullStringValue(user.BusinessName),
@@ -199,14 +331,15 @@ func (s *reportService) PrepareReportData(ctx context.Context, inspectionID, use
 		InspectorEmail:   inspectorEmail,
 		InspectorPhone:   domain.NullStringValue(user.BusinessPhone),
 		InspectorAddress: inspectorAddress,
-		InspectorLogoURL: domain.NullStringValue(user.BusinessLogoUrl),
+		InspectorLogoURL: inspectorLogoURL,
+		BrandColor:       domain.NullStringValue(user.BusinessBrandColor),
 
 		// Inspection details
 		InspectionID:      inspection.ID,
 		InspectionTitle:   inspection.Title,
 		InspectionDate:    inspection.InspectionDate,
 		WeatherConditions: domain.NullStringValue(inspection.WeatherConditions),
-		Temperature:       domain.NullStringValue(inspection.Temperature),
+		Temperature:       domain.FormatTemperature(domain.NullStringValue(inspection.Temperature), format.Units),
 		InspectorNotes:    domain.NullStringValue(inspection.InspectorNotes),
 
 		// Location info
@@ -226,7 +359,11 @@ func (s *reportService) PrepareReportData(ctx context.Context, inspectionID, use
 
 		// Metadata
 		GeneratedAt: time.Now(),
-	}, nil
+		DateLocale:  format.DateLocale,
+	}
+	data.Boilerplate = s.templates.Get(ctx, userID).Render(data.PlaceholderValues())
+
+	return data, nil
 }
 
 // formatAddress combines address components into a formatted string.
@@ -257,21 +394,62 @@ func formatAddress(line1, line2, city, state, postal string) string {
 	return addr
 }
 
+// =============================================================================
+// PreviewReportData
+// =============================================================================
+
+// PreviewReportData aggregates report data for an inline preview.
+// Applies the same status gate as report generation.
+func (s *reportService) PreviewReportData(ctx context.Context, inspectionID, userID uuid.UUID) (*domain.ReportData, error) {
+	const op = "report.preview"
+
+	ownerID, err := s.access.inspectionOwner(ctx, inspectionID, userID)
+	if err != nil {
+		return nil, err
+	}
+	inspection, err := s.queries.GetInspectionByIDAndUserID(ctx, repository.GetInspectionByIDAndUserIDParams{
+		ID:     inspectionID,
+		UserID: ownerID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.NotFound(op, "inspection", inspectionID.String())
+		}
+		return nil, domain.Internal(err, op, "failed to get inspection")
+	}
+
+	if !domain.InspectionStatus(inspection.Status).AllowsReportGeneration() {
+		return nil, domain.Invalid(op, "Inspection must be in 'review' or 'completed' status to preview a report")
+	}
+
+	data, err := s.PrepareReportData(ctx, inspectionID, userID)
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to prepare report data")
+	}
+
+	return data, nil
+}
+
 // =============================================================================
 // GetByID
 // =============================================================================
 
-// GetByID retrieves a report by ID with user authorization.
+// GetByID retrieves a report by ID with user authorization. Organization
+// members may get reports of the inspections shared with them.
 func (s *reportService) GetByID(ctx context.Context, id, userID uuid.UUID) (*domain.Report, error) {
 	const op = "report.get_by_id"
 
-	report, err := s.queries.GetReportByIDAndUserID(ctx, repository.GetReportByIDAndUserIDParams{
-		ID:     id,
-		UserID: userID,
-	})
+	report, err := s.queries.GetReportByID(ctx, id)
 	if err != nil {
 		return nil, domain.NotFound(op, "report", id.String())
 	}
+	ownerID, err := s.access.inspectionOwner(ctx, report.InspectionID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if report.UserID != ownerID {
+		return nil, domain.NotFound(op, "report", id.String())
+	}
 
 	return s.repoReportToDomain(report), nil
 }
@@ -280,19 +458,25 @@ func (s *reportService) GetByID(ctx context.Context, id, userID uuid.UUID) (*dom
 // ListByInspection
 // =============================================================================
 
-// ListByInspection returns all reports for an inspection owned by the user.
+// ListByInspection returns all reports for an inspection the user may work
+// on, which are the inspection owner's.
 func (s *reportService) ListByInspection(ctx context.Context, inspectionID, userID uuid.UUID) ([]domain.Report, error) {
 	const op = "report.list_by_inspection"
 
+	ownerID, err := s.access.inspectionOwner(ctx, inspectionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
 	repoReports, err := s.queries.ListReportsByInspectionID(ctx, inspectionID)
 	if err != nil {
 		return nil, domain.Internal(err, op, "failed to list reports")
 	}
 
-	// Filter to only reports owned by this user
+	// Filter to only reports owned by the inspection's owner
 	var reports []domain.Report
 	for _, r := range repoReports {
-		if r.UserID == userID {
+		if r.UserID == ownerID {
 			reports = append(reports, *s.repoReportToDomain(r))
 		}
 	}
@@ -305,19 +489,30 @@ func (s *reportService) ListByInspection(ctx context.Context, inspectionID, user
 // =============================================================================
 
 // TriggerGeneration enqueues a job to generate a report.
-func (s *reportService) TriggerGeneration(ctx context.Context, inspectionID, userID uuid.UUID, format, recipientEmail string) error {
+func (s *reportService) TriggerGeneration(ctx context.Context, inspectionID, userID uuid.UUID, format string, recipientEmails []string) (uuid.UUID, error) {
 	const op = "report.trigger_generation"
 
 	if s.jobEnqueuer == nil {
-		return domain.Internal(nil, op, "job enqueuer not configured")
+		return uuid.Nil, domain.Internal(nil, op, "job enqueuer not configured")
+	}
+
+	if len(recipientEmails) > domain.MaxReportRecipients {
+		return uuid.Nil, domain.Invalid(op, fmt.Sprintf("a report can be emailed to at most %d recipients", domain.MaxReportRecipients))
+	}
+
+	// The report is generated for, and counts against the quota of, the
+	// inspection's owner, even when an organization member requests it
+	ownerID, err := s.access.inspectionOwner(ctx, inspectionID, userID)
+	if err != nil {
+		return uuid.Nil, err
 	}
 
 	// Check quota if quota service is configured
 	if s.quotaService != nil {
-		// Get user's subscription tier
-		user, err := s.queries.GetUserByID(ctx, userID)
+		// Get owner's subscription tier
+		user, err := s.queries.GetUserByID(ctx, ownerID)
 		if err != nil {
-			return domain.Internal(err, op, "failed to get user")
+			return uuid.Nil, domain.Internal(err, op, "failed to get user")
 		}
 
 		// Determine tier: use subscription tier if active, otherwise free
@@ -330,23 +525,47 @@ func (s *reportService) TriggerGeneration(ctx context.Context, inspectionID, use
 			}
 		}
 
-		if err := s.quotaService.CheckReportQuota(ctx, userID, tier); err != nil {
-			return err
+		if err := s.quotaService.CheckReportQuota(ctx, ownerID, tier); err != nil {
+			return uuid.Nil, err
 		}
 	}
 
-	_, err := s.jobEnqueuer.EnqueueGenerateReport(ctx, inspectionID, userID, format, recipientEmail)
+	job, err := s.jobEnqueuer.EnqueueGenerateReport(ctx, inspectionID, ownerID, format, recipientEmails)
 	if err != nil {
-		return domain.Internal(err, op, "failed to enqueue report generation job")
+		if domain.ErrorCode(err) == domain.ERATELIMIT {
+			return uuid.Nil, err
+		}
+		return uuid.Nil, domain.Internal(err, op, "failed to enqueue report generation job")
+	}
+
+	// The job is already queued, so an audit failure is logged rather than returned
+	newValues := map[string]string{"format": format}
+	if len(recipientEmails) > 0 {
+		newValues["recipient_emails"] = strings.Join(recipientEmails, ", ")
+	}
+	if err := s.audit.Record(ctx, domain.AuditEvent{
+		ActorUserID:  userID,
+		InspectionID: &inspectionID,
+		EntityType:   domain.AuditEntityInspection,
+		EntityID:     inspectionID,
+		Action:       domain.AuditActionReportRequested,
+		NewValues:    newValues,
+	}); err != nil {
+		s.logger.ErrorContext(ctx, "failed to record report request audit event",
+			"error", err,
+			"inspection_id", inspectionID,
+			"user_id", userID,
+		)
 	}
 
-	s.logger.Info("Report generation job enqueued",
+	s.logger.InfoContext(ctx, "Report generation job enqueued",
 		"inspection_id", inspectionID,
 		"user_id", userID,
+		"owner_id", ownerID,
 		"format", format,
 	)
 
-	return nil
+	return job.ID, nil
 }
 
 // =============================================================================
@@ -369,12 +588,15 @@ func (s *reportService) repoReportToDomain(r repository.Report) *domain.Report {
 	}
 
 	return &domain.Report{
-		ID:             r.ID,
-		InspectionID:   r.InspectionID,
-		UserID:         r.UserID,
-		PDFStorageKey:  pdfKey,
-		DOCXStorageKey: docxKey,
-		ViolationCount: int(r.ViolationCount),
-		GeneratedAt:    generatedAt,
+		ID:              r.ID,
+		InspectionID:    r.InspectionID,
+		UserID:          r.UserID,
+		PDFStorageKey:   pdfKey,
+		DOCXStorageKey:  docxKey,
+		ViolationCount:  int(r.ViolationCount),
+		GeneratedAt:     generatedAt,
+		FailedAt:        domain.NullTimeValue(r.FailedAt),
+		FailedFormat:    domain.ReportFormat(r.FailedFormat.String),
+		RecipientEmails: r.RecipientEmails,
 	}
 }
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/report"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/DukeRupert/lukaut/internal/testutil/fakedb"
+	"github.com/google/uuid"
+)
+
+// fakeReportEnqueuer records report jobs instead of queueing them.
+type fakeReportEnqueuer struct {
+	userIDs []uuid.UUID // User named in each report job's payload
+}
+
+func (e *fakeReportEnqueuer) EnqueueAnalyzeInspection(context.Context, uuid.UUID, uuid.UUID) (repository.Job, error) {
+	return repository.Job{ID: uuid.New()}, nil
+}
+
+func (e *fakeReportEnqueuer) EnqueueAnalyzeImages(context.Context, uuid.UUID, uuid.UUID, []uuid.UUID, bool) (repository.Job, error) {
+	return repository.Job{ID: uuid.New()}, nil
+}
+
+func (e *fakeReportEnqueuer) EnqueueGenerateReport(_ context.Context, _, userID uuid.UUID, _ string, _ []string) (repository.Job, error) {
+	e.userIDs = append(e.userIDs, userID)
+	return repository.Job{ID: uuid.New()}, nil
+}
+
+// newTeamReportService returns a report service over an organization's
+// inspections database, with one inspection of the owner's in review that
+// already has a report.
+func newTeamReportService() (svc ReportService, enqueuer *fakeReportEnqueuer, inspectionID, owner, teammate, outsider uuid.UUID) {
+	f, owner, teammate, outsider := newTeamInspectionsDB()
+	inspectionID = uuid.New()
+	f.inspections[inspectionID] = &fakeInspectionRow{
+		id:     inspectionID,
+		userID: owner,
+		title:  "Site walk",
+		date:   time.Now(),
+		status: domain.InspectionStatusReview,
+	}
+	f.reportRows = []fakeReportRow{{id: uuid.New(), inspectionID: inspectionID, userID: owner}}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	queries := repository.New(fakedb.Open(f))
+	enqueuer = &fakeReportEnqueuer{}
+	svc = NewReportService(queries, nil, enqueuer, nil, NewAuditService(queries, repository.NewUnitOfWork(queries), logger), nil, report.DefaultBranding(), logger)
+	return svc, enqueuer, inspectionID, owner, teammate, outsider
+}
+
+// =============================================================================
+// Organization Report Access Tests
+// =============================================================================
+
+func TestReportAccess_TeammateCanGenerateListAndPreview(t *testing.T) {
+	ctx := context.Background()
+	svc, enqueuer, inspectionID, owner, teammate, _ := newTeamReportService()
+
+	if _, err := svc.TriggerGeneration(ctx, inspectionID, teammate, "pdf", nil); err != nil {
+		t.Fatalf("expected teammate to generate a report, got %v", err)
+	}
+	if len(enqueuer.userIDs) != 1 || enqueuer.userIDs[0] != owner {
+		t.Errorf("expected the job to run as the inspection owner %s, got %v", owner, enqueuer.userIDs)
+	}
+
+	reports, err := svc.ListByInspection(ctx, inspectionID, teammate)
+	if err != nil {
+		t.Fatalf("ListByInspection failed: %v", err)
+	}
+	if len(reports) != 1 || reports[0].UserID != owner {
+		t.Fatalf("expected the owner's report in the teammate's list, got %+v", reports)
+	}
+	if _, err := svc.GetByID(ctx, reports[0].ID, teammate); err != nil {
+		t.Errorf("expected teammate to get the report, got %v", err)
+	}
+
+	data, err := svc.PreviewReportData(ctx, inspectionID, teammate)
+	if err != nil {
+		t.Fatalf("expected teammate to preview the report, got %v", err)
+	}
+	if data.InspectorName != "Olive Owner" {
+		t.Errorf("expected the owner's report, got inspector %q", data.InspectorName)
+	}
+}
+
+func TestReportAccess_TeammateAndOwnerRequestTheSameJob(t *testing.T) {
+	ctx := context.Background()
+	svc, enqueuer, inspectionID, owner, teammate, _ := newTeamReportService()
+
+	// Report jobs are keyed by the user they run as, so the teammate's and
+	// the owner's requests must name the same user to share a queued report.
+	for _, userID := range []uuid.UUID{teammate, owner} {
+		if _, err := svc.TriggerGeneration(ctx, inspectionID, userID, "pdf", nil); err != nil {
+			t.Fatalf("TriggerGeneration failed: %v", err)
+		}
+	}
+	if len(enqueuer.userIDs) != 2 || enqueuer.userIDs[0] != enqueuer.userIDs[1] {
+		t.Errorf("expected both requests to run as the owner %s, got %v", owner, enqueuer.userIDs)
+	}
+}
+
+func TestReportAccess_NonMemberDenied(t *testing.T) {
+	ctx := context.Background()
+	svc, enqueuer, inspectionID, _, _, outsider := newTeamReportService()
+
+	reports, err := svc.ListByInspection(ctx, inspectionID, outsider)
+	if err != nil {
+		t.Fatalf("ListByInspection failed: %v", err)
+	}
+	if len(reports) != 0 {
+		t.Errorf("expected no reports for an outsider, got %+v", reports)
+	}
+
+	if _, err := svc.PreviewReportData(ctx, inspectionID, outsider); domain.ErrorCode(err) != domain.ENOTFOUND {
+		t.Errorf("expected ENOTFOUND previewing as an outsider, got %v", err)
+	}
+
+	// The job runs as the outsider and finds nothing, as before
+	if _, err := svc.TriggerGeneration(ctx, inspectionID, outsider, "pdf", nil); err != nil {
+		t.Fatalf("TriggerGeneration failed: %v", err)
+	}
+	if len(enqueuer.userIDs) != 1 || enqueuer.userIDs[0] != outsider {
+		t.Errorf("expected the outsider's job not to run as the owner, got %v", enqueuer.userIDs)
+	}
+}
@@ -0,0 +1,177 @@
+// Package service contains the business logic layer.
+//
+// This file implements report rendering and the inline report preview, which
+// renders small reports while the user waits and offers background
+// generation for large ones.
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/report"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// Render
+// =============================================================================
+
+// Render writes the report for data in the given format to w.
+func (s *reportService) Render(ctx context.Context, data *domain.ReportData, format domain.ReportFormat, w io.Writer) (int64, error) {
+	const op = "report.render"
+
+	var gen report.Generator
+	switch format {
+	case domain.ReportFormatPDF:
+		gen = s.pdfGen
+	case domain.ReportFormatDOCX:
+		gen = s.docxGen
+	default:
+		return 0, domain.Invalid(op, fmt.Sprintf("invalid format: %s (must be 'pdf' or 'docx')", format))
+	}
+
+	n, err := gen.Generate(ctx, data, w)
+	if err != nil {
+		return n, domain.Internal(err, op, fmt.Sprintf("failed to render %s report", format))
+	}
+	return n, nil
+}
+
+// =============================================================================
+// Preview
+// =============================================================================
+
+// Preview returns what the inline report preview shows.
+func (s *reportService) Preview(ctx context.Context, inspectionID, userID uuid.UUID) (*domain.ReportPreview, error) {
+	const op = "report.preview_pdf"
+
+	data, err := s.PreviewReportData(ctx, inspectionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data.Violations) <= domain.MaxInlinePreviewViolations {
+		fingerprint, err := reportPreviewFingerprint(data)
+		if err != nil {
+			return nil, domain.Internal(err, op, "failed to fingerprint report data")
+		}
+		if pdf, ok := s.previews.get(inspectionID, fingerprint); ok {
+			return &domain.ReportPreview{PDF: pdf, ViolationCount: len(data.Violations)}, nil
+		}
+
+		var buf bytes.Buffer
+		if _, err := s.Render(ctx, data, domain.ReportFormatPDF, &buf); err != nil {
+			return nil, err
+		}
+		s.previews.put(inspectionID, fingerprint, buf.Bytes())
+		return &domain.ReportPreview{PDF: buf.Bytes(), ViolationCount: len(data.Violations)}, nil
+	}
+
+	// Too large to render within the request: show the latest report, which
+	// may predate recent edits. Generating one is left to the user, so that
+	// viewing the preview has no side effects.
+	reports, err := s.ListByInspection(ctx, inspectionID, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range reports {
+		if !reports[i].IsFailed() && reports[i].HasPDF() {
+			return &domain.ReportPreview{Report: &reports[i], ViolationCount: len(data.Violations)}, nil
+		}
+	}
+	return &domain.ReportPreview{ViolationCount: len(data.Violations)}, nil
+}
+
+// reportPreviewFingerprint hashes the report data a preview is rendered from.
+// The generation time is left out, as are the query strings of presigned
+// image URLs, which change on every request for the same image.
+func reportPreviewFingerprint(data *domain.ReportData) ([32]byte, error) {
+	stable := *data
+	stable.GeneratedAt = time.Time{}
+	stable.InspectorLogoURL = stripQuery(stable.InspectorLogoURL)
+	stable.Violations = make([]domain.ReportViolation, len(data.Violations))
+	for i, v := range data.Violations {
+		v.ThumbnailURL = stripQuery(v.ThumbnailURL)
+		v.OriginalURL = stripQuery(v.OriginalURL)
+		v.EvidenceURLs = make([]string, len(data.Violations[i].EvidenceURLs))
+		for j, url := range data.Violations[i].EvidenceURLs {
+			v.EvidenceURLs[j] = stripQuery(url)
+		}
+		stable.Violations[i] = v
+	}
+
+	b, err := json.Marshal(stable)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return sha256.Sum256(b), nil
+}
+
+// stripQuery returns a URL without its query string.
+func stripQuery(url string) string {
+	base, _, _ := strings.Cut(url, "?")
+	return base
+}
+
+// =============================================================================
+// Preview Cache
+// =============================================================================
+
+// reportPreviewCache keeps the last rendered preview of each inspection for
+// domain.ReportPreviewCacheTTL, so reopening an unchanged preview does not
+// render it again.
+type reportPreviewCache struct {
+	mu      sync.Mutex
+	entries map[uuid.UUID]reportPreviewEntry
+	now     func() time.Time
+}
+
+type reportPreviewEntry struct {
+	fingerprint [32]byte
+	pdf         []byte
+	renderedAt  time.Time
+}
+
+func newReportPreviewCache() *reportPreviewCache {
+	return &reportPreviewCache{
+		entries: map[uuid.UUID]reportPreviewEntry{},
+		now:     time.Now,
+	}
+}
+
+// get returns the cached preview of the inspection if it was rendered from
+// data with the same fingerprint and has not expired.
+func (c *reportPreviewCache) get(inspectionID uuid.UUID, fingerprint [32]byte) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[inspectionID]
+	if !ok || entry.fingerprint != fingerprint || c.now().Sub(entry.renderedAt) >= domain.ReportPreviewCacheTTL {
+		return nil, false
+	}
+	return entry.pdf, true
+}
+
+// put caches a rendered preview, replacing the inspection's previous one and
+// dropping expired entries.
+func (c *reportPreviewCache) put(inspectionID uuid.UUID, fingerprint [32]byte, pdf []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for id, entry := range c.entries {
+		if now.Sub(entry.renderedAt) >= domain.ReportPreviewCacheTTL {
+			delete(c.entries, id)
+		}
+	}
+	c.entries[inspectionID] = reportPreviewEntry{fingerprint: fingerprint, pdf: pdf, renderedAt: now}
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/google/uuid"
+)
+
+// fakeReportGenerator writes its format name as the report.
+type fakeReportGenerator struct {
+	format domain.ReportFormat
+}
+
+func (g fakeReportGenerator) Generate(ctx context.Context, data *domain.ReportData, w io.Writer) (int64, error) {
+	n, err := io.WriteString(w, string(g.format))
+	return int64(n), err
+}
+
+func (g fakeReportGenerator) Format() domain.ReportFormat { return g.format }
+
+// =============================================================================
+// Render Tests
+// =============================================================================
+
+func TestReportRender_SelectsGeneratorByFormat(t *testing.T) {
+	svc := &reportService{
+		pdfGen:  fakeReportGenerator{domain.ReportFormatPDF},
+		docxGen: fakeReportGenerator{domain.ReportFormatDOCX},
+	}
+
+	for _, format := range []domain.ReportFormat{domain.ReportFormatPDF, domain.ReportFormatDOCX} {
+		var buf bytes.Buffer
+		if _, err := svc.Render(context.Background(), &domain.ReportData{}, format, &buf); err != nil {
+			t.Fatalf("Render %s failed: %v", format, err)
+		}
+		if buf.String() != string(format) {
+			t.Errorf("expected the %s generator, got %q", format, buf.String())
+		}
+	}
+
+	_, err := svc.Render(context.Background(), &domain.ReportData{}, "html", io.Discard)
+	if domain.ErrorCode(err) != domain.EINVALID {
+		t.Errorf("expected EINVALID for an unknown format, got %v", err)
+	}
+}
+
+// =============================================================================
+// Preview Cache Tests
+// =============================================================================
+
+func previewTestData() *domain.ReportData {
+	return &domain.ReportData{
+		InspectionTitle: "Riverside Tower Level 3",
+		Violations: []domain.ReportViolation{
+			{Number: 1, Description: "Missing guardrail", ThumbnailURL: "https://cdn.example.com/a.jpg?X-Amz-Signature=one"},
+		},
+		GeneratedAt: time.Now(),
+	}
+}
+
+func TestReportPreviewFingerprint_IgnoresGenerationTimeAndURLSignatures(t *testing.T) {
+	a := previewTestData()
+	b := previewTestData()
+	b.GeneratedAt = a.GeneratedAt.Add(time.Minute)
+	b.Violations[0].ThumbnailURL = "https://cdn.example.com/a.jpg?X-Amz-Signature=two"
+
+	fa, err := reportPreviewFingerprint(a)
+	if err != nil {
+		t.Fatalf("fingerprint failed: %v", err)
+	}
+	fb, _ := reportPreviewFingerprint(b)
+	if fa != fb {
+		t.Error("expected the same fingerprint for re-signed URLs and a later generation time")
+	}
+	if a.Violations[0].ThumbnailURL != "https://cdn.example.com/a.jpg?X-Amz-Signature=one" {
+		t.Error("expected the report data left unchanged")
+	}
+
+	b.Violations[0].Description = "Missing guardrail on east edge"
+	if fc, _ := reportPreviewFingerprint(b); fc == fa {
+		t.Error("expected an edited violation to change the fingerprint")
+	}
+}
+
+func TestReportPreviewCache_ReusesUnchangedRecentRender(t *testing.T) {
+	now := time.Now()
+	cache := newReportPreviewCache()
+	cache.now = func() time.Time { return now }
+	inspectionID := uuid.New()
+	fingerprint, _ := reportPreviewFingerprint(previewTestData())
+
+	cache.put(inspectionID, fingerprint, []byte("pdf"))
+
+	if pdf, ok := cache.get(inspectionID, fingerprint); !ok || string(pdf) != "pdf" {
+		t.Errorf("expected the cached preview, got %q, %v", pdf, ok)
+	}
+	if _, ok := cache.get(inspectionID, [32]byte{1}); ok {
+		t.Error("expected a miss when the report data changed")
+	}
+	if _, ok := cache.get(uuid.New(), fingerprint); ok {
+		t.Error("expected a miss for another inspection")
+	}
+
+	now = now.Add(domain.ReportPreviewCacheTTL)
+	if _, ok := cache.get(inspectionID, fingerprint); ok {
+		t.Error("expected a miss once the preview expired")
+	}
+
+	cache.put(uuid.New(), fingerprint, []byte("other"))
+	if _, ok := cache.entries[inspectionID]; ok {
+		t.Error("expected expired previews dropped when caching another")
+	}
+}
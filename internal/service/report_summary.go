@@ -0,0 +1,50 @@
+// Package service contains the business logic layer.
+//
+// This file implements the violations summary: counts of violations by
+// severity and status across all of a firm's inspections in a date range.
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/google/uuid"
+)
+
+// Summary counts the violations of the inspections dated between from and
+// to, inclusive, by severity and status. Inspections of the other members of
+// the user's organization are included.
+func (s *reportService) Summary(ctx context.Context, userID uuid.UUID, from, to time.Time) (*domain.ViolationSummary, error) {
+	const op = "report.summary"
+
+	if err := domain.ValidateSummaryRange(from, to); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.queries.SummarizeViolationsByUserID(ctx, repository.SummarizeViolationsByUserIDParams{
+		UserID:   userID,
+		FromDate: from,
+		ToDate:   to,
+	})
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to summarize violations")
+	}
+
+	counts := make([]domain.ViolationSummaryCount, 0, len(rows))
+	for _, row := range rows {
+		counts = append(counts, domain.ViolationSummaryCount{
+			Severity: domain.ViolationSeverity(row.Severity),
+			Status:   domain.ViolationStatus(row.Status),
+			Count:    row.ViolationCount,
+		})
+	}
+
+	return &domain.ViolationSummary{
+		From:        from,
+		To:          to,
+		Counts:      counts,
+		GeneratedAt: time.Now(),
+	}, nil
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/report"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/DukeRupert/lukaut/internal/testutil/fakedb"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// In-Memory Violations Summary Database
+// =============================================================================
+
+// fakeSummaryViolation is a violation with the date of its inspection.
+type fakeSummaryViolation struct {
+	severity       string // Empty for an unrated violation
+	status         string
+	inspectionDate time.Time
+}
+
+// fakeSummaryDB answers SummarizeViolationsByUserID by grouping its
+// violations the way the SQL does. Every violation belongs to the user.
+type fakeSummaryDB struct {
+	violations []fakeSummaryViolation
+	queries    int
+}
+
+func (f *fakeSummaryDB) Query(q fakedb.Query) (*fakedb.Rows, error) {
+	args := q.Args
+	if q.Name != "SummarizeViolationsByUserID" {
+		return nil, fmt.Errorf("fakeSummaryDB: unexpected query %q", q.Name)
+	}
+	f.queries++
+
+	// $1 user ID, $2 from date, $3 to date
+	from, to := args[1].Value.(time.Time), args[2].Value.(time.Time)
+	counts := make(map[[2]string]int64)
+	for _, v := range f.violations {
+		if v.inspectionDate.Before(from) || v.inspectionDate.After(to) {
+			continue
+		}
+		counts[[2]string{v.severity, v.status}]++
+	}
+
+	keys := make([][2]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+
+	rows := &fakedb.Rows{Columns: 3}
+	for _, key := range keys {
+		rows.Values = append(rows.Values, []driver.Value{key[0], key[1], counts[key]})
+	}
+	return rows, nil
+}
+
+func newSummaryTestService(db *fakeSummaryDB) ReportService {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewReportService(repository.New(fakedb.Open(db)), nil, nil, nil, nil, nil, report.DefaultBranding(), logger)
+}
+
+func day(month time.Month, d int) time.Time {
+	return time.Date(2025, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+// =============================================================================
+// Tests
+// =============================================================================
+
+func TestReportSummary_Aggregates(t *testing.T) {
+	db := &fakeSummaryDB{violations: []fakeSummaryViolation{
+		{"critical", "confirmed", day(time.January, 1)},
+		{"critical", "confirmed", day(time.January, 15)},
+		{"critical", "pending", day(time.February, 3)},
+		{"serious", "confirmed", day(time.February, 3)},
+		{"serious", "rejected", day(time.March, 31)},
+		{"", "pending", day(time.March, 2)},
+		// Outside the range on either side
+		{"critical", "confirmed", day(time.April, 1)},
+		{"serious", "confirmed", time.Date(2024, time.December, 31, 0, 0, 0, 0, time.UTC)},
+	}}
+	svc := newSummaryTestService(db)
+
+	summary, err := svc.Summary(context.Background(), uuid.New(), day(time.January, 1), day(time.March, 31))
+	if err != nil {
+		t.Fatalf("Summary failed: %v", err)
+	}
+
+	counts := []struct {
+		severity domain.ViolationSeverity
+		status   domain.ViolationStatus
+		want     int64
+	}{
+		{domain.ViolationSeverityCritical, domain.ViolationStatusConfirmed, 2},
+		{domain.ViolationSeverityCritical, domain.ViolationStatusPending, 1},
+		{domain.ViolationSeveritySerious, domain.ViolationStatusConfirmed, 1},
+		{domain.ViolationSeveritySerious, domain.ViolationStatusRejected, 1},
+		{domain.ViolationSeveritySerious, domain.ViolationStatusPending, 0},
+		{"", domain.ViolationStatusPending, 1},
+	}
+	for _, c := range counts {
+		if got := summary.Count(c.severity, c.status); got != c.want {
+			t.Errorf("Count(%q, %q) = %d, want %d", c.severity, c.status, got, c.want)
+		}
+	}
+
+	if got := summary.SeverityTotal(domain.ViolationSeverityCritical); got != 3 {
+		t.Errorf("critical total = %d, want 3", got)
+	}
+	if got := summary.SeverityTotal(domain.ViolationSeverityOther); got != 0 {
+		t.Errorf("other total = %d, want 0", got)
+	}
+	if got := summary.StatusTotal(domain.ViolationStatusPending); got != 2 {
+		t.Errorf("pending total = %d, want 2", got)
+	}
+	if got := summary.Total(); got != 6 {
+		t.Errorf("total = %d, want 6", got)
+	}
+
+	// Row and column totals both add up to the grand total
+	var bySeverity, byStatus int64
+	for _, severity := range domain.SummarySeverities {
+		bySeverity += summary.SeverityTotal(severity)
+	}
+	for _, status := range domain.SummaryStatuses {
+		byStatus += summary.StatusTotal(status)
+	}
+	if bySeverity != summary.Total() || byStatus != summary.Total() {
+		t.Errorf("severity totals %d and status totals %d should both equal %d", bySeverity, byStatus, summary.Total())
+	}
+}
+
+func TestReportSummary_RejectsInvalidRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to time.Time
+	}{
+		{name: "from after to", from: day(time.March, 2), to: day(time.March, 1)},
+		{name: "span too long", from: day(time.January, 1), to: day(time.January, 1).AddDate(0, 0, domain.MaxSummaryRangeDays)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &fakeSummaryDB{}
+			svc := newSummaryTestService(db)
+
+			_, err := svc.Summary(context.Background(), uuid.New(), tt.from, tt.to)
+			if domain.ErrorCode(err) != domain.EINVALID {
+				t.Errorf("expected EINVALID, got %v", err)
+			}
+			if db.queries != 0 {
+				t.Errorf("expected no query for an invalid range, got %d", db.queries)
+			}
+		})
+	}
+}
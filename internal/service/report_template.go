@@ -0,0 +1,86 @@
+// Package service contains the business logic layer.
+//
+// This file implements the report template service for the customizable
+// boilerplate sections of generated reports.
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// Interface Definition
+// =============================================================================
+
+// ReportTemplateService defines operations for a user's report boilerplate.
+type ReportTemplateService interface {
+	// Get returns the user's report sections, or domain.DefaultReportTemplate
+	// if they have not saved any. Lookup failures are logged and also fall
+	// back to the defaults, since they should never stop a report.
+	Get(ctx context.Context, userID uuid.UUID) domain.ReportTemplate
+
+	// Save stores the user's report sections.
+	// Returns domain.EINVALID if the template fails Validate.
+	Save(ctx context.Context, userID uuid.UUID, tmpl domain.ReportTemplate) error
+}
+
+// =============================================================================
+// Implementation
+// =============================================================================
+
+// reportTemplateService implements the ReportTemplateService interface.
+type reportTemplateService struct {
+	queries *repository.Queries
+	logger  *slog.Logger
+}
+
+// NewReportTemplateService creates a new ReportTemplateService.
+func NewReportTemplateService(queries *repository.Queries, logger *slog.Logger) ReportTemplateService {
+	return &reportTemplateService{
+		queries: queries,
+		logger:  logger,
+	}
+}
+
+// Get returns the user's report sections.
+func (s *reportTemplateService) Get(ctx context.Context, userID uuid.UUID) domain.ReportTemplate {
+	row, err := s.queries.GetReportSettings(ctx, userID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			s.logger.ErrorContext(ctx, "failed to get report settings", "error", err, "user_id", userID)
+		}
+		return domain.DefaultReportTemplate()
+	}
+
+	return domain.ReportTemplate{
+		Intro:      row.Intro,
+		Disclaimer: row.Disclaimer,
+		Signature:  row.Signature,
+	}
+}
+
+// Save stores the user's report sections.
+func (s *reportTemplateService) Save(ctx context.Context, userID uuid.UUID, tmpl domain.ReportTemplate) error {
+	const op = "report_template.save"
+
+	if err := tmpl.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.queries.UpsertReportSettings(ctx, repository.UpsertReportSettingsParams{
+		UserID:     userID,
+		Intro:      tmpl.Intro,
+		Disclaimer: tmpl.Disclaimer,
+		Signature:  tmpl.Signature,
+	}); err != nil {
+		return domain.Internal(err, op, "failed to save report template")
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"net"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/auth"
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// Session Client Details
+// =============================================================================
+
+// maxSessionUserAgentLength truncates oversized User-Agent headers before
+// they are stored.
+const maxSessionUserAgentLength = 512
+
+// sessionClientParams converts client details to nullable columns. Values
+// that are missing or not a valid IP address are stored as NULL.
+func sessionClientParams(client domain.SessionClient) (userAgent, ipAddress sql.NullString) {
+	if ua := client.UserAgent; ua != "" {
+		if len(ua) > maxSessionUserAgentLength {
+			ua = ua[:maxSessionUserAgentLength]
+		}
+		userAgent = sql.NullString{String: ua, Valid: true}
+	}
+	if ip := net.ParseIP(client.IPAddress); ip != nil {
+		ipAddress = sql.NullString{String: ip.String(), Valid: true}
+	}
+	return userAgent, ipAddress
+}
+
+// touchSession updates last_seen_at and the client details of the request's
+// session. Writes are throttled to SessionTouchInterval unless the client
+// changed. Failures are logged; the session is still valid.
+func (s *userService) touchSession(ctx context.Context, session repository.Session) {
+	userAgent, ipAddress := sessionClientParams(auth.GetSessionClient(ctx))
+
+	// Keep the recorded details when the request carries none
+	if !userAgent.Valid && !ipAddress.Valid {
+		userAgent, ipAddress = session.UserAgent, session.IpAddress
+	}
+
+	changed := userAgent != session.UserAgent || ipAddress != session.IpAddress
+	if !changed && time.Since(session.LastSeenAt) <= SessionTouchInterval {
+		return
+	}
+
+	if err := s.queries.TouchSession(ctx, repository.TouchSessionParams{
+		ID:        session.ID,
+		UserAgent: userAgent,
+		IpAddress: ipAddress,
+	}); err != nil {
+		s.logger.WarnContext(ctx, "failed to update session last seen", "error", err)
+	}
+}
+
+// =============================================================================
+// Session Management
+// =============================================================================
+
+// ListSessions returns the user's active sessions, most recently used first.
+func (s *userService) ListSessions(ctx context.Context, userID uuid.UUID) ([]domain.Session, error) {
+	const op = "UserService.ListSessions"
+
+	rows, err := s.queries.ListSessionsByUserID(ctx, userID)
+	if err != nil {
+		return nil, domain.Internal(err, op, "Failed to list sessions")
+	}
+
+	currentHash := ""
+	if token := auth.GetSessionToken(ctx); token != "" {
+		currentHash = hashSessionToken(token)
+	}
+
+	sessions := make([]domain.Session, 0, len(rows))
+	for _, row := range rows {
+		sessions = append(sessions, domain.Session{
+			ID:         row.ID,
+			UserID:     row.UserID,
+			ExpiresAt:  row.ExpiresAt,
+			CreatedAt:  row.CreatedAt.Time,
+			LastSeenAt: row.LastSeenAt,
+			UserAgent:  domain.NullStringValue(row.UserAgent),
+			IPAddress:  domain.NullStringValue(row.IpAddress),
+			Current:    row.TokenHash == currentHash,
+		})
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession deletes one of the user's sessions. GetBySessionToken looks
+// sessions up on every request, so the revoked token stops working at once.
+func (s *userService) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	const op = "UserService.RevokeSession"
+
+	n, err := s.queries.DeleteSessionByIDAndUserID(ctx, repository.DeleteSessionByIDAndUserIDParams{
+		ID:     sessionID,
+		UserID: userID,
+	})
+	if err != nil {
+		return domain.Internal(err, op, "Failed to revoke session")
+	}
+	if n == 0 {
+		return domain.NotFound(op, "session", sessionID.String())
+	}
+
+	s.logger.InfoContext(ctx, "session revoked", "user_id", userID, "session_id", sessionID)
+	return nil
+}
+
+// RevokeOtherSessions deletes every session of the user except the one
+// making the request.
+func (s *userService) RevokeOtherSessions(ctx context.Context, userID uuid.UUID) (int64, error) {
+	const op = "UserService.RevokeOtherSessions"
+
+	// Without the current token every session would match
+	token := auth.GetSessionToken(ctx)
+	if token == "" {
+		return 0, domain.Unauthorized(op, "No current session")
+	}
+
+	n, err := s.queries.DeleteUserSessionsExcept(ctx, repository.DeleteUserSessionsExceptParams{
+		UserID:    userID,
+		TokenHash: hashSessionToken(token),
+	})
+	if err != nil {
+		return 0, domain.Internal(err, op, "Failed to revoke sessions")
+	}
+
+	s.logger.InfoContext(ctx, "other sessions revoked", "user_id", userID, "count", n)
+	return n, nil
+}
+
+// LogoutAll deletes every session of the user, including the one making
+// the request.
+func (s *userService) LogoutAll(ctx context.Context, userID uuid.UUID) error {
+	const op = "UserService.LogoutAll"
+
+	if err := s.queries.DeleteUserSessions(ctx, userID); err != nil {
+		return domain.Internal(err, op, "Failed to sign out of all devices")
+	}
+
+	s.logger.InfoContext(ctx, "all sessions revoked", "user_id", userID)
+	return nil
+}
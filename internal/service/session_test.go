@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/DukeRupert/lukaut/internal/auth"
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/google/uuid"
+)
+
+// loginFrom logs in with the given client details in context.
+func loginFrom(t *testing.T, svc UserService, email, password string, client domain.SessionClient) *domain.LoginResult {
+	t.Helper()
+	login, err := svc.Login(auth.SetSessionClient(context.Background(), client), email, password)
+	if err != nil {
+		t.Fatalf("login failed: %v", err)
+	}
+	return login
+}
+
+// =============================================================================
+// Client Details Tests
+// =============================================================================
+
+func TestLogin_RecordsSessionClient(t *testing.T) {
+	f := newFakeUsersDB()
+	user := f.addUser(t, "inspector@example.com", "correct-horse-1")
+	svc := newFakeDBUserService(f, UserServiceConfig{})
+
+	loginFrom(t, svc, user.email, "correct-horse-1", domain.SessionClient{
+		UserAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 14_5) Chrome/126.0",
+		IPAddress: "203.0.113.7",
+	})
+
+	for _, s := range f.sessions {
+		if s.UserAgent.String != "Mozilla/5.0 (Macintosh; Intel Mac OS X 14_5) Chrome/126.0" || s.IpAddress.String != "203.0.113.7" {
+			t.Errorf("expected client details on session, got %q, %q", s.UserAgent.String, s.IpAddress.String)
+		}
+	}
+}
+
+func TestSessionClientParams(t *testing.T) {
+	ua, ip := sessionClientParams(domain.SessionClient{
+		UserAgent: strings.Repeat("x", 2000),
+		IPAddress: "not-an-ip, 10.0.0.1",
+	})
+	if len(ua.String) != maxSessionUserAgentLength {
+		t.Errorf("expected user agent truncated to %d, got %d", maxSessionUserAgentLength, len(ua.String))
+	}
+	if ip.Valid {
+		t.Errorf("expected invalid IP to be stored as NULL, got %q", ip.String)
+	}
+
+	if _, ip := sessionClientParams(domain.SessionClient{IPAddress: "2001:db8::1"}); ip.String != "2001:db8::1" {
+		t.Errorf("expected IPv6 address to be kept, got %q", ip.String)
+	}
+}
+
+func TestGetBySessionToken_RecordsChangedClient(t *testing.T) {
+	f := newFakeUsersDB()
+	user := f.addUser(t, "inspector@example.com", "correct-horse-1")
+	svc := newFakeDBUserService(f, UserServiceConfig{})
+	login := loginFrom(t, svc, user.email, "correct-horse-1", domain.SessionClient{IPAddress: "203.0.113.7"})
+
+	// Same client within the touch interval: no write
+	ctx := auth.SetSessionClient(context.Background(), domain.SessionClient{IPAddress: "203.0.113.7"})
+	if _, err := svc.GetBySessionToken(ctx, login.Token); err != nil {
+		t.Fatalf("expected active session, got %v", err)
+	}
+	if f.touches != 0 {
+		t.Errorf("expected no update for an unchanged client, got %d", f.touches)
+	}
+
+	// A new IP is recorded immediately
+	ctx = auth.SetSessionClient(context.Background(), domain.SessionClient{IPAddress: "198.51.100.2"})
+	if _, err := svc.GetBySessionToken(ctx, login.Token); err != nil {
+		t.Fatalf("expected active session, got %v", err)
+	}
+	if f.touches != 1 || f.sessions[hashSessionToken(login.Token)].IpAddress.String != "198.51.100.2" {
+		t.Errorf("expected new IP to be recorded, got %d updates", f.touches)
+	}
+}
+
+// =============================================================================
+// Session Management Tests
+// =============================================================================
+
+func TestListSessions_MarksCurrentSession(t *testing.T) {
+	f := newFakeUsersDB()
+	user := f.addUser(t, "inspector@example.com", "correct-horse-1")
+	svc := newFakeDBUserService(f, UserServiceConfig{})
+
+	laptop := loginFrom(t, svc, user.email, "correct-horse-1", domain.SessionClient{UserAgent: "laptop"})
+	loginFrom(t, svc, user.email, "correct-horse-1", domain.SessionClient{UserAgent: "phone"})
+
+	sessions, err := svc.ListSessions(auth.SetSessionToken(context.Background(), laptop.Token), user.id)
+	if err != nil {
+		t.Fatalf("ListSessions failed: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("expected 2 sessions, got %d", len(sessions))
+	}
+	for _, s := range sessions {
+		if s.Current != (s.UserAgent == "laptop") {
+			t.Errorf("session %q: Current = %v", s.UserAgent, s.Current)
+		}
+		if s.TokenHash != "" {
+			t.Error("expected token hash not to be exposed")
+		}
+	}
+}
+
+func TestRevokeSession_TakesEffectImmediately(t *testing.T) {
+	ctx := context.Background()
+	f := newFakeUsersDB()
+	user := f.addUser(t, "inspector@example.com", "correct-horse-1")
+	svc := newFakeDBUserService(f, UserServiceConfig{})
+
+	laptop := loginFrom(t, svc, user.email, "correct-horse-1", domain.SessionClient{UserAgent: "laptop"})
+	stolen := loginFrom(t, svc, user.email, "correct-horse-1", domain.SessionClient{UserAgent: "stolen"})
+	stolenID := f.sessions[hashSessionToken(stolen.Token)].ID
+
+	if err := svc.RevokeSession(ctx, user.id, stolenID); err != nil {
+		t.Fatalf("RevokeSession failed: %v", err)
+	}
+
+	if _, err := svc.GetBySessionToken(ctx, stolen.Token); domain.ErrorCode(err) != domain.EUNAUTHORIZED {
+		t.Errorf("expected revoked token to be rejected, got %v", err)
+	}
+	if _, err := svc.GetBySessionToken(ctx, laptop.Token); err != nil {
+		t.Errorf("expected other session to stay valid, got %v", err)
+	}
+}
+
+func TestRevokeSession_CurrentSession(t *testing.T) {
+	f := newFakeUsersDB()
+	user := f.addUser(t, "inspector@example.com", "correct-horse-1")
+	svc := newFakeDBUserService(f, UserServiceConfig{})
+
+	login := loginFrom(t, svc, user.email, "correct-horse-1", domain.SessionClient{})
+	ctx := auth.SetSessionToken(context.Background(), login.Token)
+	currentID := f.sessions[hashSessionToken(login.Token)].ID
+
+	if err := svc.RevokeSession(ctx, user.id, currentID); err != nil {
+		t.Fatalf("RevokeSession failed: %v", err)
+	}
+
+	if _, err := svc.GetBySessionToken(ctx, login.Token); domain.ErrorCode(err) != domain.EUNAUTHORIZED {
+		t.Errorf("expected own revoked session to be rejected, got %v", err)
+	}
+}
+
+func TestRevokeSession_OtherUsersSession(t *testing.T) {
+	ctx := context.Background()
+	f := newFakeUsersDB()
+	owner := f.addUser(t, "owner@example.com", "correct-horse-1")
+	attacker := f.addUser(t, "attacker@example.com", "correct-horse-2")
+	svc := newFakeDBUserService(f, UserServiceConfig{})
+
+	login := loginFrom(t, svc, owner.email, "correct-horse-1", domain.SessionClient{})
+	sessionID := f.sessions[hashSessionToken(login.Token)].ID
+
+	if err := svc.RevokeSession(ctx, attacker.id, sessionID); domain.ErrorCode(err) != domain.ENOTFOUND {
+		t.Errorf("expected ENOTFOUND, got %v", err)
+	}
+	if err := svc.RevokeSession(ctx, owner.id, uuid.New()); domain.ErrorCode(err) != domain.ENOTFOUND {
+		t.Errorf("expected ENOTFOUND for unknown session, got %v", err)
+	}
+	if _, err := svc.GetBySessionToken(ctx, login.Token); err != nil {
+		t.Errorf("expected session to survive, got %v", err)
+	}
+}
+
+func TestRevokeOtherSessions_KeepsCurrent(t *testing.T) {
+	f := newFakeUsersDB()
+	user := f.addUser(t, "inspector@example.com", "correct-horse-1")
+	other := f.addUser(t, "other@example.com", "correct-horse-2")
+	svc := newFakeDBUserService(f, UserServiceConfig{})
+
+	current := loginFrom(t, svc, user.email, "correct-horse-1", domain.SessionClient{})
+	loginFrom(t, svc, user.email, "correct-horse-1", domain.SessionClient{})
+	loginFrom(t, svc, user.email, "correct-horse-1", domain.SessionClient{})
+	otherLogin := loginFrom(t, svc, other.email, "correct-horse-2", domain.SessionClient{})
+
+	ctx := auth.SetSessionToken(context.Background(), current.Token)
+	n, err := svc.RevokeOtherSessions(ctx, user.id)
+	if err != nil {
+		t.Fatalf("RevokeOtherSessions failed: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("expected 2 sessions revoked, got %d", n)
+	}
+	if _, err := svc.GetBySessionToken(ctx, current.Token); err != nil {
+		t.Errorf("expected current session to stay valid, got %v", err)
+	}
+	if _, err := svc.GetBySessionToken(ctx, otherLogin.Token); err != nil {
+		t.Errorf("expected another user's session to stay valid, got %v", err)
+	}
+}
+
+func TestRevokeOtherSessions_RequiresCurrentSession(t *testing.T) {
+	f := newFakeUsersDB()
+	user := f.addUser(t, "inspector@example.com", "correct-horse-1")
+	svc := newFakeDBUserService(f, UserServiceConfig{})
+	loginFrom(t, svc, user.email, "correct-horse-1", domain.SessionClient{})
+
+	if _, err := svc.RevokeOtherSessions(context.Background(), user.id); domain.ErrorCode(err) != domain.EUNAUTHORIZED {
+		t.Errorf("expected EUNAUTHORIZED without a current session, got %v", err)
+	}
+	if len(f.sessions) != 1 {
+		t.Error("expected no sessions to be revoked")
+	}
+}
+
+func TestLogoutAll_RevokesEverySession(t *testing.T) {
+	f := newFakeUsersDB()
+	user := f.addUser(t, "inspector@example.com", "correct-horse-1")
+	other := f.addUser(t, "other@example.com", "correct-horse-2")
+	svc := newFakeDBUserService(f, UserServiceConfig{})
+
+	current := loginFrom(t, svc, user.email, "correct-horse-1", domain.SessionClient{})
+	second := loginFrom(t, svc, user.email, "correct-horse-1", domain.SessionClient{})
+	otherLogin := loginFrom(t, svc, other.email, "correct-horse-2", domain.SessionClient{})
+
+	ctx := auth.SetSessionToken(context.Background(), current.Token)
+	if err := svc.LogoutAll(ctx, user.id); err != nil {
+		t.Fatalf("LogoutAll failed: %v", err)
+	}
+	for _, token := range []string{current.Token, second.Token} {
+		if _, err := svc.GetBySessionToken(ctx, token); domain.ErrorCode(err) != domain.EUNAUTHORIZED {
+			t.Errorf("expected the session to be revoked, got %v", err)
+		}
+	}
+	if _, err := svc.GetBySessionToken(ctx, otherLogin.Token); err != nil {
+		t.Errorf("expected another user's session to stay valid, got %v", err)
+	}
+}
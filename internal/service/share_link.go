@@ -0,0 +1,229 @@
+// Package service contains the business logic layer.
+//
+// This file implements share links: expiring, read-only public URLs that
+// let a client view a completed inspection's report without an account.
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// CreateShareLink
+// =============================================================================
+
+// CreateShareLink mints a share link for a completed inspection.
+func (s *inspectionService) CreateShareLink(ctx context.Context, params domain.CreateShareLinkParams) (*domain.ShareLinkResult, error) {
+	const op = "inspection.create_share_link"
+
+	inspection, err := s.GetByID(ctx, params.InspectionID, params.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if inspection.Status != domain.InspectionStatusCompleted {
+		return nil, domain.Invalid(op, "only completed inspections can be shared")
+	}
+
+	ttl, err := normalizeShareLinkTTL(params.TTL)
+	if err != nil {
+		return nil, domain.Invalid(op, err.Error())
+	}
+
+	var maxViews sql.NullInt32
+	if params.MaxViews != nil {
+		if *params.MaxViews < 1 {
+			return nil, domain.Invalid(op, "view limit must be at least 1")
+		}
+		maxViews = sql.NullInt32{Int32: int32(*params.MaxViews), Valid: true}
+	}
+
+	token, err := generateShareToken()
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to generate share token")
+	}
+
+	row, err := s.queries.CreateInspectionShareLink(ctx, repository.CreateInspectionShareLinkParams{
+		InspectionID: params.InspectionID,
+		UserID:       params.UserID,
+		TokenHash:    hashSessionToken(token),
+		ExpiresAt:    time.Now().Add(ttl),
+		MaxViews:     maxViews,
+	})
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to create share link")
+	}
+
+	s.logger.InfoContext(ctx, "share link created",
+		"inspection_id", params.InspectionID,
+		"share_link_id", row.ID,
+		"expires_at", row.ExpiresAt,
+	)
+
+	return &domain.ShareLinkResult{
+		Link:  repoShareLinkToDomain(row),
+		Token: token,
+	}, nil
+}
+
+// =============================================================================
+// ResolveShareToken
+// =============================================================================
+
+// ResolveShareToken returns the share link for a raw token. The view is
+// counted separately with RecordShareLinkView, once the report is ready.
+//
+// Unknown, expired, revoked, and exhausted links all return the same
+// not-found error so a visitor cannot tell which applies.
+func (s *inspectionService) ResolveShareToken(ctx context.Context, token string) (*domain.ShareLink, error) {
+	const op = "inspection.resolve_share_token"
+
+	if token == "" {
+		return nil, domain.NotFound(op, "share link", "")
+	}
+
+	row, err := s.queries.GetInspectionShareLinkByTokenHash(ctx, hashSessionToken(token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.NotFound(op, "share link", "")
+		}
+		return nil, domain.Internal(err, op, "failed to get share link")
+	}
+
+	link := repoShareLinkToDomain(row)
+	if !link.IsUsable(time.Now()) {
+		return nil, domain.NotFound(op, "share link", link.ID.String())
+	}
+
+	// The inspection may have been reopened since the link was created;
+	// only finalized reports are shared.
+	inspection, err := s.GetByID(ctx, link.InspectionID, link.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if inspection.Status != domain.InspectionStatusCompleted {
+		return nil, domain.NotFound(op, "share link", link.ID.String())
+	}
+
+	return link, nil
+}
+
+// =============================================================================
+// RecordShareLinkView
+// =============================================================================
+
+// RecordShareLinkView counts a view of a resolved share link.
+func (s *inspectionService) RecordShareLinkView(ctx context.Context, link *domain.ShareLink) error {
+	const op = "inspection.record_share_link_view"
+
+	// The update re-checks expiry, revocation, and the view limit, so
+	// concurrent visitors cannot exceed max_views.
+	affected, err := s.queries.RecordInspectionShareLinkView(ctx, link.ID)
+	if err != nil {
+		return domain.Internal(err, op, "failed to record share link view")
+	}
+	if affected == 0 {
+		return domain.NotFound(op, "share link", link.ID.String())
+	}
+	link.ViewCount++
+
+	return nil
+}
+
+// =============================================================================
+// ListShareLinks / RevokeShareLink
+// =============================================================================
+
+// ListShareLinks returns the inspection's active share links, newest first.
+func (s *inspectionService) ListShareLinks(ctx context.Context, inspectionID, userID uuid.UUID) ([]domain.ShareLink, error) {
+	const op = "inspection.list_share_links"
+
+	rows, err := s.queries.ListActiveInspectionShareLinks(ctx, repository.ListActiveInspectionShareLinksParams{
+		InspectionID: inspectionID,
+		UserID:       userID,
+	})
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to list share links")
+	}
+
+	links := make([]domain.ShareLink, 0, len(rows))
+	for _, row := range rows {
+		links = append(links, *repoShareLinkToDomain(row))
+	}
+	return links, nil
+}
+
+// RevokeShareLink revokes a share link. ResolveShareToken checks revoked_at
+// on every view, so the URL stops working immediately.
+func (s *inspectionService) RevokeShareLink(ctx context.Context, linkID, userID uuid.UUID) (*domain.ShareLink, error) {
+	const op = "inspection.revoke_share_link"
+
+	row, err := s.queries.RevokeInspectionShareLink(ctx, repository.RevokeInspectionShareLinkParams{
+		ID:     linkID,
+		UserID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.NotFound(op, "share link", linkID.String())
+		}
+		return nil, domain.Internal(err, op, "failed to revoke share link")
+	}
+
+	s.logger.InfoContext(ctx, "share link revoked", "share_link_id", linkID, "inspection_id", row.InspectionID)
+	return repoShareLinkToDomain(row), nil
+}
+
+// =============================================================================
+// Helper Functions
+// =============================================================================
+
+// normalizeShareLinkTTL applies the default TTL and rejects TTLs above the maximum.
+func normalizeShareLinkTTL(ttl time.Duration) (time.Duration, error) {
+	switch {
+	case ttl == 0:
+		return domain.DefaultShareLinkTTL, nil
+	case ttl < 0:
+		return 0, errors.New("expiry must be in the future")
+	case ttl > domain.MaxShareLinkTTL:
+		return 0, errors.New("share links can be valid for at most 30 days")
+	}
+	return ttl, nil
+}
+
+// generateShareToken creates a cryptographically secure share token.
+// Same strength as session tokens: 32 random bytes, hex-encoded.
+func generateShareToken() (string, error) {
+	bytes := make([]byte, domain.TokenBytes)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
+
+// repoShareLinkToDomain converts a repository.InspectionShareLink to domain.ShareLink.
+func repoShareLinkToDomain(row repository.InspectionShareLink) *domain.ShareLink {
+	link := &domain.ShareLink{
+		ID:           row.ID,
+		InspectionID: row.InspectionID,
+		UserID:       row.UserID,
+		ExpiresAt:    row.ExpiresAt,
+		ViewCount:    int(row.ViewCount),
+		CreatedAt:    row.CreatedAt,
+	}
+	if row.MaxViews.Valid {
+		maxViews := int(row.MaxViews.Int32)
+		link.MaxViews = &maxViews
+	}
+	if row.RevokedAt.Valid {
+		link.RevokedAt = &row.RevokedAt.Time
+	}
+	return link
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/DukeRupert/lukaut/internal/testutil/fakedb"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// In-Memory Share Links Database
+// =============================================================================
+
+// fakeShareLinkRow holds an inspection share link.
+type fakeShareLinkRow struct {
+	id, inspectionID, userID uuid.UUID
+	expiresAt                time.Time
+	maxViews                 *int32
+	viewCount                int32
+	revokedAt                *time.Time
+}
+
+// fakeShareLinksDB adds share links to the inspections database.
+type fakeShareLinksDB struct {
+	*fakeInspectionsDB
+	links map[string]*fakeShareLinkRow // By token hash
+}
+
+func (f *fakeShareLinksDB) Query(q fakedb.Query) (*fakedb.Rows, error) {
+	if q.Name != "GetInspectionShareLinkByTokenHash" {
+		return f.fakeInspectionsDB.Query(q)
+	}
+	rows := &fakedb.Rows{Columns: 9}
+	if l, ok := f.links[q.Arg(0).(string)]; ok {
+		var maxViews, revokedAt driver.Value
+		if l.maxViews != nil {
+			maxViews = int64(*l.maxViews)
+		}
+		if l.revokedAt != nil {
+			revokedAt = *l.revokedAt
+		}
+		rows.Values = append(rows.Values, []driver.Value{
+			l.id.String(), l.inspectionID.String(), l.userID.String(), q.Arg(0), l.expiresAt, maxViews, int64(l.viewCount), revokedAt, time.Now(),
+		})
+	}
+	return rows, nil
+}
+
+func (f *fakeShareLinksDB) Exec(q fakedb.Query) (int64, error) {
+	if q.Name != "RecordInspectionShareLinkView" {
+		return f.fakeInspectionsDB.Exec(q)
+	}
+	// Mirrors the WHERE clause
+	for _, l := range f.links {
+		if l.id.String() != q.Arg(0).(string) {
+			continue
+		}
+		if l.revokedAt != nil || !l.expiresAt.After(time.Now()) || (l.maxViews != nil && l.viewCount >= *l.maxViews) {
+			return 0, nil
+		}
+		l.viewCount++
+		return 1, nil
+	}
+	return 0, fmt.Errorf("fakeShareLinksDB: no share link %v", q.Arg(0))
+}
+
+// newShareTestService returns a service over one completed inspection with a
+// share link, the link's raw token, and the link.
+func newShareTestService() (InspectionService, string, *fakeShareLinkRow) {
+	owner, inspectionID := uuid.New(), uuid.New()
+	token := "share-token"
+	link := &fakeShareLinkRow{
+		id:           uuid.New(),
+		inspectionID: inspectionID,
+		userID:       owner,
+		expiresAt:    time.Now().Add(time.Hour),
+	}
+	f := &fakeShareLinksDB{
+		fakeInspectionsDB: &fakeInspectionsDB{inspections: map[uuid.UUID]*fakeInspectionRow{
+			inspectionID: {id: inspectionID, userID: owner, status: domain.InspectionStatusCompleted},
+		}},
+		links: map[string]*fakeShareLinkRow{hashSessionToken(token): link},
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewInspectionService(repository.New(fakedb.Open(f)), nil, nil, nil, nil, nil, logger), token, link
+}
+
+// =============================================================================
+// Share Link Tests
+// =============================================================================
+
+func TestNormalizeShareLinkTTL(t *testing.T) {
+	testCases := []struct {
+		name    string
+		input   time.Duration
+		want    time.Duration
+		wantErr bool
+	}{
+		{"zero uses default", 0, domain.DefaultShareLinkTTL, false},
+		{"one day", 24 * time.Hour, 24 * time.Hour, false},
+		{"at maximum", domain.MaxShareLinkTTL, domain.MaxShareLinkTTL, false},
+		{"above maximum", domain.MaxShareLinkTTL + time.Hour, 0, true},
+		{"negative", -time.Hour, 0, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := normalizeShareLinkTTL(tc.input)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected error=%v, got %v", tc.wantErr, err)
+			}
+			if got != tc.want {
+				t.Errorf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestGenerateShareToken(t *testing.T) {
+	a, err := generateShareToken()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, _ := generateShareToken()
+
+	if len(a) != 64 {
+		t.Errorf("expected 64 hex chars, got %d", len(a))
+	}
+	if a == b {
+		t.Error("expected distinct tokens")
+	}
+}
+
+func TestResolveShareToken_CountsViewsOnlyWhenRecorded(t *testing.T) {
+	svc, token, row := newShareTestService()
+	ctx := context.Background()
+
+	link, err := svc.ResolveShareToken(ctx, token)
+	if err != nil {
+		t.Fatalf("ResolveShareToken: %v", err)
+	}
+	if link.ID != row.id || row.viewCount != 0 {
+		t.Fatalf("expected the link resolved without counting a view, got %d views", row.viewCount)
+	}
+
+	if err := svc.RecordShareLinkView(ctx, link); err != nil {
+		t.Fatalf("RecordShareLinkView: %v", err)
+	}
+	if row.viewCount != 1 || link.ViewCount != 1 {
+		t.Errorf("expected one view counted, got %d stored and %d returned", row.viewCount, link.ViewCount)
+	}
+}
+
+func TestResolveShareToken_UnusableLinksNotFound(t *testing.T) {
+	testCases := []struct {
+		name   string
+		expire func(l *fakeShareLinkRow)
+	}{
+		{"expired", func(l *fakeShareLinkRow) { l.expiresAt = time.Now().Add(-time.Minute) }},
+		{"revoked", func(l *fakeShareLinkRow) {
+			now := time.Now()
+			l.revokedAt = &now
+		}},
+		{"out of views", func(l *fakeShareLinkRow) {
+			maxViews := int32(2)
+			l.maxViews, l.viewCount = &maxViews, 2
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc, token, row := newShareTestService()
+			tc.expire(row)
+
+			_, err := svc.ResolveShareToken(context.Background(), token)
+
+			if domain.ErrorCode(err) != domain.ENOTFOUND {
+				t.Errorf("expected ENOTFOUND, got %v", err)
+			}
+		})
+	}
+
+	svc, _, _ := newShareTestService()
+	if _, err := svc.ResolveShareToken(context.Background(), "unknown-token"); domain.ErrorCode(err) != domain.ENOTFOUND {
+		t.Errorf("expected ENOTFOUND for an unknown token, got %v", err)
+	}
+}
+
+func TestRecordShareLinkView_StopsAtViewLimit(t *testing.T) {
+	svc, token, row := newShareTestService()
+	ctx := context.Background()
+	maxViews := int32(1)
+	row.maxViews = &maxViews
+
+	// Two visitors resolve the link before either view is counted
+	first, err := svc.ResolveShareToken(ctx, token)
+	if err != nil {
+		t.Fatalf("ResolveShareToken: %v", err)
+	}
+	second, err := svc.ResolveShareToken(ctx, token)
+	if err != nil {
+		t.Fatalf("ResolveShareToken: %v", err)
+	}
+
+	if err := svc.RecordShareLinkView(ctx, first); err != nil {
+		t.Fatalf("RecordShareLinkView: %v", err)
+	}
+	if err := svc.RecordShareLinkView(ctx, second); domain.ErrorCode(err) != domain.ENOTFOUND {
+		t.Errorf("expected ENOTFOUND past the view limit, got %v", err)
+	}
+	if row.viewCount != 1 {
+		t.Errorf("expected one view counted, got %d", row.viewCount)
+	}
+}
@@ -5,24 +5,106 @@ package service
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"image"
 	"io"
+	"sort"
 
 	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/HugoSmits86/nativewebp"
 	"github.com/disintegration/imaging"
 )
 
+// =============================================================================
+// Configuration
+// =============================================================================
+
+// ThumbnailConfig controls which thumbnail variants are generated.
+type ThumbnailConfig struct {
+	// Sizes are the bounding boxes (in pixels) to generate, e.g. 200 fits the
+	// thumbnail within 200x200. Empty means domain.DefaultThumbnailSizes.
+	Sizes []int
+
+	// WebP additionally generates a WebP variant for every size.
+	// JPEG variants are always generated.
+	WebP bool
+}
+
+// normalize returns the config with defaults applied and sizes sorted
+// ascending with invalid and duplicate entries removed.
+func (c ThumbnailConfig) normalize() ThumbnailConfig {
+	seen := make(map[int]bool, len(c.Sizes))
+	sizes := make([]int, 0, len(c.Sizes))
+	for _, size := range c.Sizes {
+		if size > 0 && !seen[size] {
+			seen[size] = true
+			sizes = append(sizes, size)
+		}
+	}
+	if len(sizes) == 0 {
+		sizes = append(sizes, domain.DefaultThumbnailSizes...)
+	}
+	sort.Ints(sizes)
+
+	return ThumbnailConfig{Sizes: sizes, WebP: c.WebP}
+}
+
+// Formats returns the formats generated for each size, JPEG first.
+func (c ThumbnailConfig) Formats() []domain.ThumbnailFormat {
+	if c.WebP {
+		return []domain.ThumbnailFormat{domain.ThumbnailFormatJPEG, domain.ThumbnailFormatWebP}
+	}
+	return []domain.ThumbnailFormat{domain.ThumbnailFormatJPEG}
+}
+
+// DefaultSize returns the size whose JPEG variant is recorded as the
+// image's thumbnail key: the configured default if present, else the smallest.
+func (c ThumbnailConfig) DefaultSize() int {
+	for _, size := range c.Sizes {
+		if size == domain.DefaultThumbnailSize {
+			return size
+		}
+	}
+	return c.Sizes[0]
+}
+
+// SizeFor returns the smallest configured size that is at least requested,
+// or the largest configured size if none is. A requested size of zero or
+// less returns DefaultSize.
+func (c ThumbnailConfig) SizeFor(requested int) int {
+	if requested <= 0 {
+		return c.DefaultSize()
+	}
+	for _, size := range c.Sizes {
+		if size >= requested {
+			return size
+		}
+	}
+	return c.Sizes[len(c.Sizes)-1]
+}
+
 // =============================================================================
 // Interface Definition
 // =============================================================================
 
+// ThumbnailVariant is one generated thumbnail at a given size and format.
+type ThumbnailVariant struct {
+	Size   int                    // Bounding box the thumbnail fits within
+	Format domain.ThumbnailFormat // Encoding of Data
+	Data   []byte                 // Encoded thumbnail bytes
+}
+
 // ThumbnailProcessor handles thumbnail generation from images.
 type ThumbnailProcessor interface {
-	// GenerateThumbnail creates a thumbnail from the provided image data.
-	// Returns the thumbnail bytes (as JPEG), original width, and original height.
-	// The thumbnail will fit within maxWidth x maxHeight while preserving aspect ratio.
-	GenerateThumbnail(data io.Reader, maxWidth, maxHeight int) ([]byte, int, int, error)
+	// GenerateThumbnails creates every configured thumbnail variant from the
+	// provided image data. Returns the variants, original width, and original
+	// height. Each thumbnail fits within its size while preserving aspect ratio.
+	// Generation stops between variants once ctx is canceled.
+	GenerateThumbnails(ctx context.Context, data io.Reader) ([]ThumbnailVariant, int, int, error)
+
+	// Config returns the normalized configuration the processor generates.
+	Config() ThumbnailConfig
 }
 
 // =============================================================================
@@ -30,26 +112,35 @@ type ThumbnailProcessor interface {
 // =============================================================================
 
 // imagingProcessor implements ThumbnailProcessor using the imaging library.
-type imagingProcessor struct{}
+type imagingProcessor struct {
+	config ThumbnailConfig
+}
 
 // NewImagingProcessor creates a new thumbnail processor using the imaging library.
-func NewImagingProcessor() ThumbnailProcessor {
-	return &imagingProcessor{}
+// Sizes missing from cfg default to domain.DefaultThumbnailSizes.
+func NewImagingProcessor(cfg ThumbnailConfig) ThumbnailProcessor {
+	return &imagingProcessor{config: cfg.normalize()}
+}
+
+// Config returns the normalized configuration.
+func (p *imagingProcessor) Config() ThumbnailConfig {
+	return p.config
 }
 
-// GenerateThumbnail creates a thumbnail from the provided image data.
+// GenerateThumbnails creates every configured thumbnail variant.
 //
-// The thumbnail is resized to fit within maxWidth x maxHeight while preserving
-// the original aspect ratio. The output is always JPEG format with quality 85.
+// Each size is resized once and encoded in every configured format. JPEG
+// output uses domain.ThumbnailJPEGQuality; WebP output is lossless, as the
+// pure-Go encoder does not support lossy compression.
 //
 // Returns:
-//   - thumbnail bytes (JPEG format)
+//   - variants ordered by size ascending, then format (JPEG first)
 //   - original image width
 //   - original image height
-//   - error if generation fails
-func (p *imagingProcessor) GenerateThumbnail(data io.Reader, maxWidth, maxHeight int) ([]byte, int, int, error) {
+//   - error if decoding or encoding fails, or ctx is canceled
+func (p *imagingProcessor) GenerateThumbnails(ctx context.Context, data io.Reader) ([]ThumbnailVariant, int, int, error) {
 	// Decode the image
-	img, format, err := image.Decode(data)
+	img, _, err := image.Decode(data)
 	if err != nil {
 		return nil, 0, 0, fmt.Errorf("failed to decode image: %w", err)
 	}
@@ -59,18 +150,55 @@ func (p *imagingProcessor) GenerateThumbnail(data io.Reader, maxWidth, maxHeight
 	originalWidth := bounds.Dx()
 	originalHeight := bounds.Dy()
 
-	// Resize to fit within maxWidth x maxHeight while preserving aspect ratio
-	// imaging.Fit will resize the image to fit within the specified dimensions
-	// while maintaining aspect ratio
-	thumbnail := imaging.Fit(img, maxWidth, maxHeight, imaging.Lanczos)
+	formats := p.config.Formats()
+	variants := make([]ThumbnailVariant, 0, len(p.config.Sizes)*len(formats))
 
-	// Encode thumbnail as JPEG
-	var buf bytes.Buffer
-	if err := imaging.Encode(&buf, thumbnail, imaging.JPEG, imaging.JPEGQuality(domain.ThumbnailJPEGQuality)); err != nil {
-		return nil, 0, 0, fmt.Errorf("failed to encode thumbnail: %w", err)
+	for _, size := range p.config.Sizes {
+		// Resizing is the slow part, so a canceled upload stops here
+		if err := ctx.Err(); err != nil {
+			return nil, 0, 0, err
+		}
+
+		// imaging.Fit resizes to fit within size x size while maintaining
+		// aspect ratio, and never upscales smaller images
+		thumbnail := imaging.Fit(img, size, size, imaging.Lanczos)
+
+		for _, format := range formats {
+			encoded, err := encodeThumbnail(thumbnail, format)
+			if err != nil {
+				return nil, 0, 0, fmt.Errorf("failed to encode %dpx %s thumbnail: %w", size, format, err)
+			}
+			variants = append(variants, ThumbnailVariant{Size: size, Format: format, Data: encoded})
+		}
 	}
 
-	_ = format // Acknowledge we read the format even though we don't use it
+	return variants, originalWidth, originalHeight, nil
+}
+
+// CropThumbnail decodes an image and returns a JPEG thumbnail of the part
+// inside box, fit within size x size.
+func CropThumbnail(data io.Reader, box domain.AnnotationBox, size int) ([]byte, error) {
+	img, _, err := image.Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
 
-	return buf.Bytes(), originalWidth, originalHeight, nil
+	region := imaging.Crop(img, box.Rect(img.Bounds()))
+	return encodeThumbnail(imaging.Fit(region, size, size, imaging.Lanczos), domain.ThumbnailFormatJPEG)
+}
+
+// encodeThumbnail encodes img in the given format.
+func encodeThumbnail(img image.Image, format domain.ThumbnailFormat) ([]byte, error) {
+	var buf bytes.Buffer
+	switch format {
+	case domain.ThumbnailFormatWebP:
+		if err := nativewebp.Encode(&buf, img, nil); err != nil {
+			return nil, err
+		}
+	default:
+		if err := imaging.Encode(&buf, img, imaging.JPEG, imaging.JPEGQuality(domain.ThumbnailJPEGQuality)); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
 }
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/HugoSmits86/nativewebp"
+)
+
+// =============================================================================
+// Test Helpers
+// =============================================================================
+
+// testPNG encodes a width x height gradient as PNG.
+func testPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x % 256), G: uint8(y % 256), B: 128, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("failed to encode test image: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// =============================================================================
+// GenerateThumbnails Tests
+// =============================================================================
+
+func TestGenerateThumbnails_JPEGVariantPerSize(t *testing.T) {
+	p := NewImagingProcessor(ThumbnailConfig{Sizes: []int{600, 200}})
+
+	variants, width, height, err := p.GenerateThumbnails(context.Background(), bytes.NewReader(testPNG(t, 800, 600)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if width != 800 || height != 600 {
+		t.Errorf("expected original dimensions 800x600, got %dx%d", width, height)
+	}
+	if len(variants) != 2 {
+		t.Fatalf("expected 2 variants, got %d", len(variants))
+	}
+
+	wantSizes := []int{200, 600}
+	for i, v := range variants {
+		if v.Size != wantSizes[i] {
+			t.Errorf("variant %d: expected size %d, got %d", i, wantSizes[i], v.Size)
+		}
+		if v.Format != domain.ThumbnailFormatJPEG {
+			t.Errorf("variant %d: expected JPEG, got %s", i, v.Format)
+		}
+
+		cfg, err := jpeg.DecodeConfig(bytes.NewReader(v.Data))
+		if err != nil {
+			t.Fatalf("variant %d: expected valid JPEG: %v", i, err)
+		}
+		// 800x600 fit within size x size keeps the 4:3 aspect ratio
+		if cfg.Width != v.Size || cfg.Height != v.Size*3/4 {
+			t.Errorf("variant %d: expected %dx%d, got %dx%d", i, v.Size, v.Size*3/4, cfg.Width, cfg.Height)
+		}
+	}
+}
+
+func TestGenerateThumbnails_WebPWhenRequested(t *testing.T) {
+	p := NewImagingProcessor(ThumbnailConfig{Sizes: []int{200, 600}, WebP: true})
+
+	variants, _, _, err := p.GenerateThumbnails(context.Background(), bytes.NewReader(testPNG(t, 800, 600)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(variants) != 4 {
+		t.Fatalf("expected 4 variants (JPEG + WebP per size), got %d", len(variants))
+	}
+
+	webpCount := 0
+	for _, v := range variants {
+		if v.Format != domain.ThumbnailFormatWebP {
+			continue
+		}
+		webpCount++
+
+		if len(v.Data) < 12 || string(v.Data[0:4]) != "RIFF" || string(v.Data[8:12]) != "WEBP" {
+			t.Errorf("%dpx variant: expected WebP RIFF header", v.Size)
+			continue
+		}
+		cfg, err := nativewebp.DecodeConfig(bytes.NewReader(v.Data))
+		if err != nil {
+			t.Fatalf("%dpx variant: expected decodable WebP: %v", v.Size, err)
+		}
+		if cfg.Width != v.Size || cfg.Height != v.Size*3/4 {
+			t.Errorf("%dpx variant: expected %dx%d, got %dx%d", v.Size, v.Size, v.Size*3/4, cfg.Width, cfg.Height)
+		}
+	}
+	if webpCount != 2 {
+		t.Errorf("expected 2 WebP variants, got %d", webpCount)
+	}
+}
+
+func TestGenerateThumbnails_DoesNotUpscale(t *testing.T) {
+	p := NewImagingProcessor(ThumbnailConfig{Sizes: []int{200}})
+
+	variants, _, _, err := p.GenerateThumbnails(context.Background(), bytes.NewReader(testPNG(t, 100, 50)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg, err := jpeg.DecodeConfig(bytes.NewReader(variants[0].Data))
+	if err != nil {
+		t.Fatalf("expected valid JPEG: %v", err)
+	}
+	if cfg.Width != 100 || cfg.Height != 50 {
+		t.Errorf("expected small image to keep 100x50, got %dx%d", cfg.Width, cfg.Height)
+	}
+}
+
+func TestGenerateThumbnails_InvalidImage(t *testing.T) {
+	p := NewImagingProcessor(ThumbnailConfig{})
+
+	if _, _, _, err := p.GenerateThumbnails(context.Background(), bytes.NewReader([]byte("not an image"))); err == nil {
+		t.Error("expected error for undecodable data")
+	}
+}
+
+func TestGenerateThumbnails_StopsWhenCanceled(t *testing.T) {
+	p := NewImagingProcessor(ThumbnailConfig{})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, _, _, err := p.GenerateThumbnails(ctx, bytes.NewReader(testPNG(t, 800, 600)))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+// =============================================================================
+// ThumbnailConfig Tests
+// =============================================================================
+
+func TestThumbnailConfig_Defaults(t *testing.T) {
+	cfg := NewImagingProcessor(ThumbnailConfig{Sizes: []int{0, -5}}).Config()
+
+	if len(cfg.Sizes) != len(domain.DefaultThumbnailSizes) {
+		t.Fatalf("expected default sizes %v, got %v", domain.DefaultThumbnailSizes, cfg.Sizes)
+	}
+	if cfg.DefaultSize() != domain.DefaultThumbnailSize {
+		t.Errorf("expected default size %d, got %d", domain.DefaultThumbnailSize, cfg.DefaultSize())
+	}
+	if formats := cfg.Formats(); len(formats) != 1 || formats[0] != domain.ThumbnailFormatJPEG {
+		t.Errorf("expected JPEG only by default, got %v", formats)
+	}
+}
+
+func TestThumbnailConfig_SizeFor(t *testing.T) {
+	cfg := NewImagingProcessor(ThumbnailConfig{Sizes: []int{600, 200, 200}}).Config()
+
+	testCases := []struct {
+		requested int
+		want      int
+	}{
+		{0, 200},
+		{150, 200},
+		{200, 200},
+		{201, 600},
+		{1200, 600},
+	}
+
+	for _, tc := range testCases {
+		if got := cfg.SizeFor(tc.requested); got != tc.want {
+			t.Errorf("SizeFor(%d): expected %d, got %d", tc.requested, tc.want, got)
+		}
+	}
+}
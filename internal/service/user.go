@@ -15,10 +15,12 @@ import (
 	"database/sql"
 	"encoding/hex"
 	"errors"
+	"fmt"
 	"log/slog"
 	"strings"
 	"time"
 
+	"github.com/DukeRupert/lukaut/internal/auth"
 	"github.com/DukeRupert/lukaut/internal/domain"
 	"github.com/DukeRupert/lukaut/internal/repository"
 	"github.com/google/uuid"
@@ -54,6 +56,16 @@ const (
 	// MaxSessionDuration is the maximum allowed session duration.
 	MaxSessionDuration = 30 * 24 * time.Hour
 
+	// MinSessionIdleTimeout is the minimum idle timeout when one is configured.
+	// It keeps the timeout well above SessionTouchInterval.
+	MinSessionIdleTimeout = 5 * time.Minute
+
+	// SessionTouchInterval throttles last_seen_at updates: a session is
+	// written at most once per interval rather than on every request, unless
+	// its client details change. Idle expiry may therefore be up to this
+	// much late.
+	SessionTouchInterval = time.Minute
+
 	// MinPasswordLength is the minimum password length.
 	// NIST SP 800-63B recommends 8+ characters minimum.
 	MinPasswordLength = 8
@@ -63,6 +75,11 @@ const (
 	MaxPasswordLength = 72
 )
 
+// ErrMsgAccountDisabled is returned by Login for a disabled account. It is
+// only shown after the password has been checked, so it reveals nothing to
+// someone without the user's credentials.
+const ErrMsgAccountDisabled = "Your account has been disabled. Please contact support."
+
 // =============================================================================
 // Interface Definition
 // =============================================================================
@@ -81,7 +98,8 @@ type UserService interface {
 
 	// Login authenticates a user and creates a new session.
 	// Returns the user and raw session token on success.
-	// Returns domain.EUNAUTHORIZED for invalid credentials.
+	// Returns domain.EUNAUTHORIZED for invalid credentials, or with
+	// ErrMsgAccountDisabled if the account is disabled.
 	Login(ctx context.Context, email, password string) (*domain.LoginResult, error)
 
 	// Logout invalidates a session by its raw token.
@@ -94,7 +112,8 @@ type UserService interface {
 
 	// GetBySessionToken retrieves a user by their session token.
 	// This validates the session and returns the associated user.
-	// Returns domain.EUNAUTHORIZED if token is invalid or expired.
+	// Returns domain.EUNAUTHORIZED if token is invalid or expired, or if the
+	// user's account is disabled.
 	GetBySessionToken(ctx context.Context, token string) (*domain.User, error)
 
 	// UpdateProfile updates a user's profile information.
@@ -102,6 +121,8 @@ type UserService interface {
 	UpdateProfile(ctx context.Context, params domain.ProfileUpdateParams) error
 
 	// UpdateBusinessProfile updates a user's business profile information.
+	// Returns domain.EINVALID if the profile is missing required fields (see
+	// domain.BusinessProfileUpdateParams.MissingFields).
 	// Returns domain.ENOTFOUND if user does not exist.
 	UpdateBusinessProfile(ctx context.Context, params domain.BusinessProfileUpdateParams) error
 
@@ -115,6 +136,33 @@ type UserService interface {
 	// This should be called periodically (e.g., daily) to clean up.
 	DeleteExpiredSessions(ctx context.Context) error
 
+	// ListSessions returns the user's active sessions, most recently used first.
+	// The session making the request (see auth.SetSessionToken) is marked Current.
+	ListSessions(ctx context.Context, userID uuid.UUID) ([]domain.Session, error)
+
+	// RevokeSession signs out one of the user's sessions. The revoked token is
+	// rejected on its next request.
+	// Returns domain.ENOTFOUND if the session does not exist or belongs to another user.
+	RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error
+
+	// RevokeOtherSessions signs out every session of the user except the one
+	// making the request, and returns how many were revoked.
+	// Returns domain.EUNAUTHORIZED if the request has no session token.
+	RevokeOtherSessions(ctx context.Context, userID uuid.UUID) (int64, error)
+
+	// LogoutAll signs out every session of the user, including the one
+	// making the request.
+	LogoutAll(ctx context.Context, userID uuid.UUID) error
+
+	// DisableUser locks an account out without deleting its data.
+	// Login is refused and existing sessions stop working immediately.
+	// Returns domain.ENOTFOUND if user does not exist.
+	DisableUser(ctx context.Context, id uuid.UUID) error
+
+	// EnableUser reverses DisableUser. The user must log in again.
+	// Returns domain.ENOTFOUND if user does not exist.
+	EnableUser(ctx context.Context, id uuid.UUID) error
+
 	// =========================================================================
 	// Email Verification Methods
 	// =========================================================================
@@ -141,6 +189,12 @@ type UserService interface {
 	// This should be called periodically (e.g., daily) as a cleanup task.
 	DeleteExpiredEmailVerificationTokens(ctx context.Context) error
 
+	// CheckEmailVerification decides whether the user may use features that
+	// require a verified email (analysis, report generation).
+	// Unverified users are allowed until the grace period after registration ends.
+	// Returns domain.EFORBIDDEN once the grace period has passed.
+	CheckEmailVerification(user *domain.User) error
+
 	// =========================================================================
 	// Password Reset Methods
 	// =========================================================================
@@ -192,35 +246,62 @@ type UserServiceConfig struct {
 	// If zero, DefaultSessionDuration is used.
 	// Values are clamped to MinSessionDuration and MaxSessionDuration.
 	SessionDuration time.Duration
+
+	// SessionIdleTimeout expires a session after this long without an
+	// authenticated request, in addition to SessionDuration.
+	// Zero disables the idle timeout; other values are raised to
+	// MinSessionIdleTimeout.
+	SessionIdleTimeout time.Duration
+
+	// EmailVerificationGracePeriod is how long after registration an
+	// unverified user may still use verification-gated features.
+	// Zero (or negative) requires verification immediately.
+	EmailVerificationGracePeriod time.Duration
+
+	// BreachedPasswordChecker, if set, rejects new passwords that appear in
+	// known data breaches. Nil disables the check.
+	BreachedPasswordChecker BreachedPasswordChecker
 }
 
 // userService is the concrete implementation of UserService.
 type userService struct {
-	queries         *repository.Queries
-	logger          *slog.Logger
-	sessionDuration time.Duration
+	queries                 *repository.Queries
+	uow                     repository.UnitOfWork
+	logger                  *slog.Logger
+	sessionDuration         time.Duration
+	sessionIdleTimeout      time.Duration
+	verificationGracePeriod time.Duration
+	breachChecker           BreachedPasswordChecker
 }
 
 // NewUserService creates a new UserService instance with default configuration.
 //
 // Dependencies:
 // - queries: sqlc-generated database queries
+// - uow: runs multi-step writes in one transaction
 // - logger: structured logger for operation logging
-func NewUserService(queries *repository.Queries, logger *slog.Logger) UserService {
-	return NewUserServiceWithConfig(queries, logger, UserServiceConfig{})
+func NewUserService(queries *repository.Queries, uow repository.UnitOfWork, logger *slog.Logger) UserService {
+	return NewUserServiceWithConfig(queries, uow, logger, UserServiceConfig{})
 }
 
 // NewUserServiceWithConfig creates a new UserService with custom configuration.
-func NewUserServiceWithConfig(queries *repository.Queries, logger *slog.Logger, cfg UserServiceConfig) UserService {
+func NewUserServiceWithConfig(queries *repository.Queries, uow repository.UnitOfWork, logger *slog.Logger, cfg UserServiceConfig) UserService {
 	return &userService{
-		queries:         queries,
-		logger:          logger,
-		sessionDuration: normalizeSessionDuration(cfg.SessionDuration),
+		queries:                 queries,
+		uow:                     uow,
+		logger:                  logger,
+		sessionDuration:         SessionDuration(cfg.SessionDuration),
+		sessionIdleTimeout:      normalizeSessionIdleTimeout(cfg.SessionIdleTimeout),
+		verificationGracePeriod: max(cfg.EmailVerificationGracePeriod, 0),
+		breachChecker:           cfg.BreachedPasswordChecker,
 	}
 }
 
-// normalizeSessionDuration ensures the session duration is within valid bounds.
-func normalizeSessionDuration(d time.Duration) time.Duration {
+// SessionDuration returns the session lifetime the user service uses for a
+// configured duration: zero means DefaultSessionDuration, and other values
+// are clamped to MinSessionDuration and MaxSessionDuration. The session
+// cookie's MaxAge uses it too, so the two can't drift apart.
+func SessionDuration(d time.Duration) time.Duration {
 	if d == 0 {
 		return DefaultSessionDuration
 	}
@@ -233,6 +314,15 @@ func normalizeSessionDuration(d time.Duration) time.Duration {
 	return d
 }
 
+// normalizeSessionIdleTimeout returns zero (disabled) for non-positive
+// timeouts and raises others to MinSessionIdleTimeout.
+func normalizeSessionIdleTimeout(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	return max(d, MinSessionIdleTimeout)
+}
+
 // =============================================================================
 // Register Implementation
 // =============================================================================
@@ -271,8 +361,8 @@ func (s *userService) Register(ctx context.Context, params domain.RegisterParams
 	}
 
 	// Validate password
-	if err := validatePassword(params.Password); err != nil {
-		return nil, domain.Wrap(err, domain.EINVALID, op, "Invalid password")
+	if err := s.checkPassword(ctx, params.Password); err != nil {
+		return nil, domain.Wrap(err, domain.EINVALID, op, domain.ErrorMessage(err))
 	}
 
 	// Check if email already exists
@@ -316,7 +406,7 @@ func (s *userService) Register(ctx context.Context, params domain.RegisterParams
 	user.PasswordHash = ""
 
 	// Log successful registration
-	s.logger.Info("user registered", "user_id", user.ID, "email", user.Email)
+	s.logger.InfoContext(ctx, "user registered", "user_id", user.ID, "email", user.Email)
 
 	return user, nil
 }
@@ -367,6 +457,12 @@ func (s *userService) Login(ctx context.Context, email, password string) (*domai
 		return nil, domain.Unauthorized(op, "Invalid email or password")
 	}
 
+	// Refuse disabled accounts only after the password matched
+	if repoUser.DisabledAt.Valid {
+		s.logger.InfoContext(ctx, "login refused: account disabled", "user_id", repoUser.ID)
+		return nil, domain.Unauthorized(op, ErrMsgAccountDisabled)
+	}
+
 	// Generate session token
 	token, err := generateSessionToken()
 	if err != nil {
@@ -379,11 +475,14 @@ func (s *userService) Login(ctx context.Context, email, password string) (*domai
 	// Calculate session expiration using configured duration
 	expiresAt := time.Now().Add(s.sessionDuration)
 
-	// Create session in database
+	// Create session in database, recording the client that logged in
+	userAgent, ipAddress := sessionClientParams(auth.GetSessionClient(ctx))
 	_, err = s.queries.CreateSession(ctx, repository.CreateSessionParams{
 		UserID:    repoUser.ID,
 		TokenHash: tokenHash,
 		ExpiresAt: expiresAt,
+		UserAgent: userAgent,
+		IpAddress: ipAddress,
 	})
 	if err != nil {
 		return nil, domain.Internal(err, op, "Failed to create session")
@@ -396,7 +495,7 @@ func (s *userService) Login(ctx context.Context, email, password string) (*domai
 	user.PasswordHash = ""
 
 	// Log successful login
-	s.logger.Info("user logged in", "user_id", user.ID, "email", user.Email)
+	s.logger.InfoContext(ctx, "user logged in", "user_id", user.ID, "email", user.Email)
 
 	// Return result with user and RAW token (not hash)
 	return &domain.LoginResult{
@@ -437,12 +536,12 @@ func (s *userService) Logout(ctx context.Context, token string) error {
 		// Ignore not found errors - logout is idempotent
 		// Log other errors but don't fail the operation
 		if !errors.Is(err, sql.ErrNoRows) {
-			s.logger.Warn("failed to delete session", "error", err)
+			s.logger.WarnContext(ctx, "failed to delete session", "error", err)
 		}
 	}
 
 	// Log logout
-	s.logger.Debug("session invalidated")
+	s.logger.DebugContext(ctx, "session invalidated")
 
 	return nil
 }
@@ -483,12 +582,17 @@ func (s *userService) GetByID(ctx context.Context, id uuid.UUID) (*domain.User,
 // 1. Hash the provided raw token
 // 2. Look up session by token hash
 // 3. Verify session is not expired (database query handles this)
-// 4. Look up associated user
-// 5. Return user
+// 4. Verify session is not idle past the idle timeout, if configured
+// 5. Update last_seen_at and the client details (throttled)
+// 6. Look up associated user
+// 7. Reject disabled users
+// 8. Return user
 //
 // Security Considerations:
 // - Token is hashed before database lookup
 // - Expired sessions are rejected at database level
+// - Idle sessions are deleted when rejected
+// - Sessions of disabled users are rejected without being deleted
 func (s *userService) GetBySessionToken(ctx context.Context, token string) (*domain.User, error) {
 	const op = "UserService.GetBySessionToken"
 
@@ -514,6 +618,15 @@ func (s *userService) GetBySessionToken(ctx context.Context, token string) (*dom
 		return nil, domain.Internal(err, op, "Failed to retrieve session")
 	}
 
+	if s.sessionIdleTimeout > 0 && time.Since(session.LastSeenAt) > s.sessionIdleTimeout {
+		if err := s.queries.DeleteSession(ctx, tokenHash); err != nil {
+			s.logger.WarnContext(ctx, "failed to delete idle session", "error", err)
+		}
+		return nil, domain.Unauthorized(op, "Invalid or expired session")
+	}
+
+	s.touchSession(ctx, session)
+
 	// Get user by session's user_id
 	repoUser, err := s.queries.GetUserByID(ctx, session.UserID)
 	if err != nil {
@@ -524,6 +637,12 @@ func (s *userService) GetBySessionToken(ctx context.Context, token string) (*dom
 		return nil, domain.Internal(err, op, "Failed to retrieve user")
 	}
 
+	// Checked on every request so disabling takes effect immediately,
+	// however disabled_at was set
+	if repoUser.DisabledAt.Valid {
+		return nil, domain.Unauthorized(op, "Invalid or expired session")
+	}
+
 	// Convert to domain user
 	user := repoUserToDomain(repoUser)
 
@@ -571,7 +690,7 @@ func (s *userService) UpdateProfile(ctx context.Context, params domain.ProfileUp
 	}
 
 	// Log update
-	s.logger.Info("user profile updated", "user_id", params.UserID)
+	s.logger.InfoContext(ctx, "user profile updated", "user_id", params.UserID)
 
 	return nil
 }
@@ -594,6 +713,18 @@ func (s *userService) UpdateBusinessProfile(ctx context.Context, params domain.B
 	params.State = strings.TrimSpace(params.State)
 	params.PostalCode = strings.TrimSpace(params.PostalCode)
 	params.LicenseNumber = strings.TrimSpace(params.LicenseNumber)
+	params.BrandColor = strings.ToUpper(strings.TrimSpace(params.BrandColor))
+
+	if params.BrandColor != "" && !domain.IsValidBrandColor(params.BrandColor) {
+		return domain.Invalid(op, "Brand color must be a hex color like #1E3A5F.")
+	}
+	if missing := params.MissingFields(); len(missing) > 0 {
+		labels := make([]string, len(missing))
+		for i, f := range missing {
+			labels[i] = f.Label
+		}
+		return domain.Errorf(domain.EINVALID, op, "Please fill in the required business fields: %s.", strings.Join(labels, ", "))
+	}
 
 	// Verify user exists
 	_, err := s.queries.GetUserByID(ctx, params.UserID)
@@ -617,13 +748,14 @@ func (s *userService) UpdateBusinessProfile(ctx context.Context, params domain.B
 		BusinessPostalCode:    domain.ToNullString(params.PostalCode),
 		BusinessLicenseNumber: domain.ToNullString(params.LicenseNumber),
 		BusinessLogoUrl:       domain.ToNullString(params.LogoURL),
+		BusinessBrandColor:    domain.ToNullString(params.BrandColor),
 	})
 	if err != nil {
 		return domain.Internal(err, op, "Failed to update business profile")
 	}
 
 	// Log update
-	s.logger.Info("user business profile updated", "user_id", params.UserID)
+	s.logger.InfoContext(ctx, "user business profile updated", "user_id", params.UserID)
 
 	return nil
 }
@@ -639,8 +771,7 @@ func (s *userService) UpdateBusinessProfile(ctx context.Context, params domain.B
 // 2. Validate current password
 // 3. Validate new password meets requirements
 // 4. Hash new password
-// 5. Update password in database
-// 6. Invalidate all existing sessions for security
+// 5. Update password and invalidate all existing sessions in one transaction
 //
 // Security Considerations:
 // - Current password must be verified to prevent session hijacking attacks
@@ -650,8 +781,8 @@ func (s *userService) ChangePassword(ctx context.Context, params domain.Password
 	const op = "UserService.ChangePassword"
 
 	// Validate new password
-	if err := validatePassword(params.NewPassword); err != nil {
-		return domain.Wrap(err, domain.EINVALID, op, "Invalid new password")
+	if err := s.checkPassword(ctx, params.NewPassword); err != nil {
+		return domain.Wrap(err, domain.EINVALID, op, domain.ErrorMessage(err))
 	}
 
 	// Get user to verify current password
@@ -675,24 +806,27 @@ func (s *userService) ChangePassword(ctx context.Context, params domain.Password
 		return domain.Internal(err, op, "Failed to hash new password")
 	}
 
-	// Update password in database
-	err = s.queries.UpdateUserPassword(ctx, repository.UpdateUserPasswordParams{
-		ID:           params.UserID,
-		PasswordHash: string(newPasswordHash),
+	// Update the password and invalidate all user sessions (force
+	// re-authentication) together, so a failure can't leave old sessions
+	// valid under the new password
+	err = s.uow.Do(ctx, func(q *repository.Queries) error {
+		if err := q.UpdateUserPassword(ctx, repository.UpdateUserPasswordParams{
+			ID:           params.UserID,
+			PasswordHash: string(newPasswordHash),
+		}); err != nil {
+			return fmt.Errorf("update password: %w", err)
+		}
+		if err := q.DeleteUserSessions(ctx, params.UserID); err != nil {
+			return fmt.Errorf("delete sessions: %w", err)
+		}
+		return nil
 	})
 	if err != nil {
 		return domain.Internal(err, op, "Failed to update password")
 	}
 
-	// Invalidate all user sessions (force re-authentication)
-	err = s.queries.DeleteUserSessions(ctx, params.UserID)
-	if err != nil {
-		// Log but don't fail - password was changed successfully
-		s.logger.Warn("failed to delete user sessions after password change", "user_id", params.UserID, "error", err)
-	}
-
 	// Log password change
-	s.logger.Info("user password changed", "user_id", params.UserID)
+	s.logger.InfoContext(ctx, "user password changed", "user_id", params.UserID)
 
 	return nil
 }
@@ -713,11 +847,61 @@ func (s *userService) DeleteExpiredSessions(ctx context.Context) error {
 	}
 
 	// Log cleanup
-	s.logger.Info("expired sessions cleaned up")
+	s.logger.InfoContext(ctx, "expired sessions cleaned up")
 
 	return nil
 }
 
+// =============================================================================
+// DisableUser / EnableUser Implementation
+// =============================================================================
+
+// DisableUser sets disabled_at on the user. GetBySessionToken checks it on
+// every request, so open sessions stop working as soon as it is set. The
+// sessions are then deleted so re-enabling the account does not revive them.
+func (s *userService) DisableUser(ctx context.Context, id uuid.UUID) error {
+	const op = "UserService.DisableUser"
+
+	if err := s.setDisabledAt(ctx, op, id, sql.NullTime{Time: time.Now(), Valid: true}); err != nil {
+		return err
+	}
+
+	if err := s.queries.DeleteUserSessions(ctx, id); err != nil {
+		// Log but don't fail - the sessions are already rejected
+		s.logger.WarnContext(ctx, "failed to delete sessions of disabled user", "user_id", id, "error", err)
+	}
+
+	s.logger.InfoContext(ctx, "user account disabled", "user_id", id)
+	return nil
+}
+
+// EnableUser clears disabled_at on the user.
+func (s *userService) EnableUser(ctx context.Context, id uuid.UUID) error {
+	const op = "UserService.EnableUser"
+
+	if err := s.setDisabledAt(ctx, op, id, sql.NullTime{}); err != nil {
+		return err
+	}
+
+	s.logger.InfoContext(ctx, "user account enabled", "user_id", id)
+	return nil
+}
+
+// setDisabledAt updates disabled_at, returning domain.ENOTFOUND for unknown users.
+func (s *userService) setDisabledAt(ctx context.Context, op string, id uuid.UUID, disabledAt sql.NullTime) error {
+	affected, err := s.queries.AdminUpdateUserDisabled(ctx, repository.AdminUpdateUserDisabledParams{
+		ID:         id,
+		DisabledAt: disabledAt,
+	})
+	if err != nil {
+		return domain.Internal(err, op, "Failed to update user")
+	}
+	if affected == 0 {
+		return domain.NotFound(op, "user", id.String())
+	}
+	return nil
+}
+
 // =============================================================================
 // Helper Functions
 // =============================================================================
@@ -776,6 +960,11 @@ func repoUserToDomain(u repository.User) *domain.User {
 		updatedAt = u.UpdatedAt.Time
 	}
 
+	var disabledAt *time.Time
+	if u.DisabledAt.Valid {
+		disabledAt = &u.DisabledAt.Time
+	}
+
 	return &domain.User{
 		ID:                 u.ID,
 		Email:              u.Email,
@@ -789,6 +978,7 @@ func repoUserToDomain(u repository.User) *domain.User {
 		SubscriptionID:     domain.NullStringValue(u.SubscriptionID),
 		EmailVerified:      domain.NullBoolValue(u.EmailVerified),
 		EmailVerifiedAt:    emailVerifiedAt,
+		DisabledAt:         disabledAt,
 		CreatedAt:          createdAt,
 		UpdatedAt:          updatedAt,
 
@@ -803,6 +993,8 @@ func repoUserToDomain(u repository.User) *domain.User {
 		BusinessPostalCode:    domain.NullStringValue(u.BusinessPostalCode),
 		BusinessLicenseNumber: domain.NullStringValue(u.BusinessLicenseNumber),
 		BusinessLogoURL:       domain.NullStringValue(u.BusinessLogoUrl),
+		BusinessLogoKey:       domain.NullStringValue(u.BusinessLogoKey),
+		BusinessBrandColor:    domain.NullStringValue(u.BusinessBrandColor),
 	}
 }
 
@@ -874,14 +1066,14 @@ func validateEmail(email string) error {
 // - Maximum length: 72 characters (bcrypt limit)
 // - Must contain at least one letter
 // - Must contain at least one number
-// - Must not be a common password
+// - Must not be in the bundled common password list (common_passwords.txt)
 func validatePassword(password string) error {
 	if len(password) < MinPasswordLength {
-		return domain.Invalid("", "Password must be at least 8 characters")
+		return weakPassword("Password must be at least 8 characters")
 	}
 
 	if len(password) > MaxPasswordLength {
-		return domain.Invalid("", "Password must be 72 characters or less")
+		return weakPassword("Password must be 72 characters or less")
 	}
 
 	// Check for at least one letter
@@ -893,7 +1085,7 @@ func validatePassword(password string) error {
 		}
 	}
 	if !hasLetter {
-		return domain.Invalid("", "Password must contain at least one letter")
+		return weakPassword("Password must contain at least one letter")
 	}
 
 	// Check for at least one number
@@ -905,64 +1097,17 @@ func validatePassword(password string) error {
 		}
 	}
 	if !hasNumber {
-		return domain.Invalid("", "Password must contain at least one number")
+		return weakPassword("Password must contain at least one number")
 	}
 
 	// Check against common passwords
 	if isCommonPassword(password) {
-		return domain.Invalid("", "Password is too common. Please choose a more unique password.")
+		return weakPassword(ErrMsgPasswordTooCommon)
 	}
 
 	return nil
 }
 
-// isCommonPassword checks if the password is in our list of common passwords.
-// This list includes the most common passwords that meet our basic requirements.
-func isCommonPassword(password string) bool {
-	// Common passwords that would otherwise pass our validation.
-	// These are lowercase for case-insensitive comparison.
-	commonPasswords := map[string]bool{
-		"password1":   true,
-		"password12":  true,
-		"password123": true,
-		"qwerty123":   true,
-		"qwerty1234":  true,
-		"letmein1":    true,
-		"letmein12":   true,
-		"welcome1":    true,
-		"welcome12":   true,
-		"admin123":    true,
-		"admin1234":   true,
-		"abc12345":    true,
-		"abcd1234":    true,
-		"123456ab":    true,
-		"1234abcd":    true,
-		"iloveyou1":   true,
-		"sunshine1":   true,
-		"princess1":   true,
-		"football1":   true,
-		"baseball1":   true,
-		"dragon123":   true,
-		"master123":   true,
-		"monkey123":   true,
-		"shadow123":   true,
-		"michael1":    true,
-		"jennifer1":   true,
-		"trustno1":    true,
-		"passw0rd":    true,
-		"p@ssword1":   true,
-		"p@ssw0rd":    true,
-		"changeme1":   true,
-		"test1234":    true,
-		"testing1":    true,
-		"testing123":  true,
-	}
-
-	// Check case-insensitively
-	lower := strings.ToLower(password)
-	return commonPasswords[lower]
-}
-
 // contains checks if a string contains a substring
 func contains(s, substr string) bool {
 	for i := 0; i <= len(s)-len(substr); i++ {
@@ -1031,7 +1176,7 @@ func (s *userService) CreateEmailVerificationToken(ctx context.Context, userID u
 	}
 
 	// Log token creation
-	s.logger.Info("email verification token created", "user_id", userID)
+	s.logger.InfoContext(ctx, "email verification token created", "user_id", userID)
 
 	// 7. Return result with raw token
 	return &domain.EmailVerificationResult{
@@ -1091,7 +1236,7 @@ func (s *userService) VerifyEmail(ctx context.Context, token string) error {
 	if user.EmailVerified.Valid && user.EmailVerified.Bool {
 		// Already verified - delete the token and return success
 		_ = s.queries.DeleteEmailVerificationToken(ctx, tokenHash)
-		s.logger.Info("email already verified, silent success", "user_id", user.ID)
+		s.logger.InfoContext(ctx, "email already verified, silent success", "user_id", user.ID)
 		return nil
 	}
 
@@ -1109,11 +1254,11 @@ func (s *userService) VerifyEmail(ctx context.Context, token string) error {
 	err = s.queries.DeleteEmailVerificationToken(ctx, tokenHash)
 	if err != nil {
 		// Log but don't fail - verification already succeeded
-		s.logger.Warn("failed to delete verification token after use", "error", err, "user_id", user.ID)
+		s.logger.WarnContext(ctx, "failed to delete verification token after use", "error", err, "user_id", user.ID)
 	}
 
 	// 8. Log success
-	s.logger.Info("email verified", "user_id", user.ID, "email", user.Email)
+	s.logger.InfoContext(ctx, "email verified", "user_id", user.ID, "email", user.Email)
 
 	return nil
 }
@@ -1161,10 +1306,32 @@ func (s *userService) DeleteExpiredEmailVerificationTokens(ctx context.Context)
 		return domain.Internal(err, op, "Failed to delete expired tokens")
 	}
 
-	s.logger.Info("expired email verification tokens cleaned up")
+	s.logger.InfoContext(ctx, "expired email verification tokens cleaned up")
 	return nil
 }
 
+// =============================================================================
+// CheckEmailVerification Implementation
+// =============================================================================
+
+// CheckEmailVerification enforces the email verification policy.
+//
+// Unverified users can browse and create inspections, but analysis and
+// report generation are blocked once verificationGracePeriod has passed
+// since registration. This keeps sign-up friction low while ensuring the
+// accounts consuming AI quota and emailing reports own their address.
+func (s *userService) CheckEmailVerification(user *domain.User) error {
+	const op = "UserService.CheckEmailVerification"
+
+	if user.EmailVerified {
+		return nil
+	}
+	if time.Now().Before(user.CreatedAt.Add(s.verificationGracePeriod)) {
+		return nil
+	}
+	return domain.Forbidden(op, "Please verify your email address to continue")
+}
+
 // =============================================================================
 // Password Reset Token Implementation
 // =============================================================================
@@ -1227,7 +1394,7 @@ func (s *userService) CreatePasswordResetToken(ctx context.Context, email string
 	}
 
 	// Log token creation
-	s.logger.Info("password reset token created", "user_id", user.ID, "email", user.Email)
+	s.logger.InfoContext(ctx, "password reset token created", "user_id", user.ID, "email", user.Email)
 
 	// 8. Return result
 	return &domain.PasswordResetResult{
@@ -1301,7 +1468,7 @@ func (s *userService) ResetPassword(ctx context.Context, params domain.ResetPass
 	}
 
 	// 2. Validate new password (this error is fine to be specific - user needs feedback)
-	if err := validatePassword(params.NewPassword); err != nil {
+	if err := s.checkPassword(ctx, params.NewPassword); err != nil {
 		return err // Return the specific password validation error
 	}
 
@@ -1337,18 +1504,18 @@ func (s *userService) ResetPassword(ctx context.Context, params domain.ResetPass
 	err = s.queries.MarkPasswordResetTokenUsed(ctx, tokenHash)
 	if err != nil {
 		// Log but don't fail - password was already changed
-		s.logger.Warn("failed to mark reset token as used", "error", err, "user_id", resetToken.UserID)
+		s.logger.WarnContext(ctx, "failed to mark reset token as used", "error", err, "user_id", resetToken.UserID)
 	}
 
 	// 8. Invalidate all sessions
 	err = s.queries.DeleteUserSessions(ctx, resetToken.UserID)
 	if err != nil {
 		// Log but don't fail - password was changed successfully
-		s.logger.Warn("failed to delete user sessions after password reset", "error", err, "user_id", resetToken.UserID)
+		s.logger.WarnContext(ctx, "failed to delete user sessions after password reset", "error", err, "user_id", resetToken.UserID)
 	}
 
 	// 9. Log success
-	s.logger.Info("password reset completed", "user_id", resetToken.UserID)
+	s.logger.InfoContext(ctx, "password reset completed", "user_id", resetToken.UserID)
 
 	return nil
 }
@@ -1362,7 +1529,7 @@ func (s *userService) DeleteExpiredPasswordResetTokens(ctx context.Context) erro
 		return domain.Internal(err, op, "Failed to delete expired tokens")
 	}
 
-	s.logger.Info("expired password reset tokens cleaned up")
+	s.logger.InfoContext(ctx, "expired password reset tokens cleaned up")
 	return nil
 }
 
@@ -1382,7 +1549,7 @@ func (s *userService) UpdateStripeCustomer(ctx context.Context, userID uuid.UUID
 		return domain.Internal(err, op, "Failed to update Stripe customer ID")
 	}
 
-	s.logger.Info("stripe customer ID updated", "user_id", userID, "stripe_customer_id", stripeCustomerID)
+	s.logger.InfoContext(ctx, "stripe customer ID updated", "user_id", userID, "stripe_customer_id", stripeCustomerID)
 	return nil
 }
 
@@ -1400,7 +1567,7 @@ func (s *userService) UpdateSubscription(ctx context.Context, userID uuid.UUID,
 		return domain.Internal(err, op, "Failed to update subscription")
 	}
 
-	s.logger.Info("subscription updated", "user_id", userID, "status", status, "tier", tier)
+	s.logger.InfoContext(ctx, "subscription updated", "user_id", userID, "status", status, "tier", tier)
 	return nil
 }
 
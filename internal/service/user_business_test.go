@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+)
+
+func TestUpdateBusinessProfile_SavesNormalizedProfile(t *testing.T) {
+	f := newFakeUsersDB()
+	u := f.addUser(t, "pat@example.com", "correct horse battery")
+	svc := newFakeDBUserService(f, UserServiceConfig{})
+
+	err := svc.UpdateBusinessProfile(context.Background(), domain.BusinessProfileUpdateParams{
+		UserID:        u.id,
+		BusinessName:  "  Acme Safety  ",
+		AddressLine1:  "100 Main St",
+		City:          "Tampa",
+		State:         "FL",
+		PostalCode:    "33602",
+		LicenseNumber: "CSP-12345",
+		BrandColor:    "#0055aa",
+	})
+	if err != nil {
+		t.Fatalf("UpdateBusinessProfile: %v", err)
+	}
+
+	got := u.business
+	if got == nil {
+		t.Fatal("business profile was not saved")
+	}
+	if got.BusinessName.String != "Acme Safety" || got.BusinessCity.String != "Tampa" || got.BusinessLicenseNumber.String != "CSP-12345" {
+		t.Errorf("saved %+v, want the trimmed profile", got)
+	}
+	if got.BusinessBrandColor.String != "#0055AA" {
+		t.Errorf("saved brand color %q, want #0055AA", got.BusinessBrandColor.String)
+	}
+	if got.BusinessAddressLine2.Valid {
+		t.Error("empty address line 2 saved as a value, want NULL")
+	}
+}
+
+func TestUpdateBusinessProfile_RequiresFieldsWhenEnabled(t *testing.T) {
+	f := newFakeUsersDB()
+	u := f.addUser(t, "pat@example.com", "correct horse battery")
+	svc := newFakeDBUserService(f, UserServiceConfig{})
+
+	err := svc.UpdateBusinessProfile(context.Background(), domain.BusinessProfileUpdateParams{
+		UserID: u.id,
+		City:   "Tampa",
+	})
+	if domain.ErrorCode(err) != domain.EINVALID {
+		t.Fatalf("expected EINVALID, got %v", err)
+	}
+	if want := "Please fill in the required business fields: Business name, Street address, State, ZIP code."; domain.ErrorMessage(err) != want {
+		t.Errorf("message %q, want %q", domain.ErrorMessage(err), want)
+	}
+	if u.business != nil {
+		t.Error("invalid profile was saved")
+	}
+}
+
+func TestUpdateBusinessProfile_ClearingDisablesProfile(t *testing.T) {
+	f := newFakeUsersDB()
+	u := f.addUser(t, "pat@example.com", "correct horse battery")
+	svc := newFakeDBUserService(f, UserServiceConfig{})
+
+	if err := svc.UpdateBusinessProfile(context.Background(), domain.BusinessProfileUpdateParams{UserID: u.id}); err != nil {
+		t.Fatalf("UpdateBusinessProfile: %v", err)
+	}
+	if u.business == nil || u.business.BusinessName.Valid {
+		t.Errorf("saved %+v, want an empty profile", u.business)
+	}
+}
+
+func TestUpdateBusinessProfile_InvalidBrandColor(t *testing.T) {
+	f := newFakeUsersDB()
+	u := f.addUser(t, "pat@example.com", "correct horse battery")
+	svc := newFakeDBUserService(f, UserServiceConfig{})
+
+	err := svc.UpdateBusinessProfile(context.Background(), domain.BusinessProfileUpdateParams{
+		UserID:       u.id,
+		BusinessName: "Acme Safety",
+		BrandColor:   "orange",
+	})
+	if domain.ErrorCode(err) != domain.EINVALID {
+		t.Fatalf("expected EINVALID, got %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// Account Disable Tests
+// =============================================================================
+
+func TestDisableUser_EndsInFlightSession(t *testing.T) {
+	ctx := context.Background()
+	f := newFakeUsersDB()
+	user := f.addUser(t, "inspector@example.com", "correct-horse-1")
+	svc := newFakeDBUserService(f, UserServiceConfig{})
+
+	login, err := svc.Login(ctx, user.email, "correct-horse-1")
+	if err != nil {
+		t.Fatalf("login failed: %v", err)
+	}
+	if _, err := svc.GetBySessionToken(ctx, login.Token); err != nil {
+		t.Fatalf("expected session to be valid before disabling, got %v", err)
+	}
+
+	if err := svc.DisableUser(ctx, user.id); err != nil {
+		t.Fatalf("DisableUser failed: %v", err)
+	}
+
+	_, err = svc.GetBySessionToken(ctx, login.Token)
+	if domain.ErrorCode(err) != domain.EUNAUTHORIZED {
+		t.Errorf("expected EUNAUTHORIZED for session of disabled user, got %v", err)
+	}
+
+	// Re-enabling does not revive the old session
+	if err := svc.EnableUser(ctx, user.id); err != nil {
+		t.Fatalf("EnableUser failed: %v", err)
+	}
+	if _, err := svc.GetBySessionToken(ctx, login.Token); err == nil {
+		t.Error("expected old session to stay invalid after re-enabling")
+	}
+	if _, err := svc.Login(ctx, user.email, "correct-horse-1"); err != nil {
+		t.Errorf("expected login to work after re-enabling, got %v", err)
+	}
+}
+
+func TestGetBySessionToken_RejectsUserDisabledOutsideService(t *testing.T) {
+	ctx := context.Background()
+	f := newFakeUsersDB()
+	user := f.addUser(t, "inspector@example.com", "correct-horse-1")
+	svc := newFakeDBUserService(f, UserServiceConfig{})
+
+	login, err := svc.Login(ctx, user.email, "correct-horse-1")
+	if err != nil {
+		t.Fatalf("login failed: %v", err)
+	}
+
+	// Simulate disabled_at being set by a script: the session row survives
+	now := time.Now()
+	user.disabledAt = &now
+
+	_, err = svc.GetBySessionToken(ctx, login.Token)
+	if domain.ErrorCode(err) != domain.EUNAUTHORIZED {
+		t.Errorf("expected EUNAUTHORIZED, got %v", err)
+	}
+}
+
+func TestLogin_DisabledAccount(t *testing.T) {
+	ctx := context.Background()
+	f := newFakeUsersDB()
+	user := f.addUser(t, "inspector@example.com", "correct-horse-1")
+	now := time.Now()
+	user.disabledAt = &now
+	svc := newFakeDBUserService(f, UserServiceConfig{})
+
+	_, err := svc.Login(ctx, user.email, "wrong-password-1")
+	if msg := domain.ErrorMessage(err); msg != "Invalid email or password" {
+		t.Errorf("expected generic message for wrong password, got %q", msg)
+	}
+
+	_, err = svc.Login(ctx, user.email, "correct-horse-1")
+	if domain.ErrorCode(err) != domain.EUNAUTHORIZED {
+		t.Errorf("expected EUNAUTHORIZED, got %v", err)
+	}
+	if msg := domain.ErrorMessage(err); msg != ErrMsgAccountDisabled {
+		t.Errorf("expected %q, got %q", ErrMsgAccountDisabled, msg)
+	}
+	if len(f.sessions) != 0 {
+		t.Error("expected no session to be created for a disabled account")
+	}
+}
+
+func TestDisableUser_UnknownUser(t *testing.T) {
+	svc := newFakeDBUserService(newFakeUsersDB(), UserServiceConfig{})
+
+	err := svc.DisableUser(context.Background(), uuid.New())
+	if domain.ErrorCode(err) != domain.ENOTFOUND {
+		t.Errorf("expected ENOTFOUND, got %v", err)
+	}
+}
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/DukeRupert/lukaut/internal/testutil/fakedb"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// =============================================================================
+// In-Memory Users Database
+// =============================================================================
+
+// fakeUsersDB answers the handful of sqlc queries used by login, session
+// validation, and account disabling.
+type fakeUsersDB struct {
+	users    map[string]*fakeUserRow       // keyed by email
+	sessions map[string]repository.Session // keyed by token hash
+	touches  int                           // TouchSession calls
+	failExec string                        // Exec that fails, by query name
+	commits  int                           // Committed transactions
+}
+
+type fakeUserRow struct {
+	id           uuid.UUID
+	email        string
+	passwordHash string
+	disabledAt   *time.Time
+	business     *repository.UpdateUserBusinessProfileParams // last UpdateUserBusinessProfile
+}
+
+func newFakeUsersDB() *fakeUsersDB {
+	return &fakeUsersDB{
+		users:    map[string]*fakeUserRow{},
+		sessions: map[string]repository.Session{},
+	}
+}
+
+func (f *fakeUsersDB) addUser(t *testing.T, email, password string) *fakeUserRow {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	row := &fakeUserRow{id: uuid.New(), email: email, passwordHash: string(hash)}
+	f.users[email] = row
+	return row
+}
+
+// setLastSeen backdates last_seen_at on every session.
+func (f *fakeUsersDB) setLastSeen(ago time.Duration) {
+	for hash, s := range f.sessions {
+		s.LastSeenAt = time.Now().Add(-ago)
+		f.sessions[hash] = s
+	}
+}
+
+// Begin snapshots passwords and sessions, which rollback restores.
+func (f *fakeUsersDB) Begin() (commit, rollback func()) {
+	passwords := map[string]string{}
+	for email, u := range f.users {
+		passwords[email] = u.passwordHash
+	}
+	sessions := map[string]repository.Session{}
+	for hash, s := range f.sessions {
+		sessions[hash] = s
+	}
+
+	commit = func() { f.commits++ }
+	rollback = func() {
+		for email, hash := range passwords {
+			f.users[email].passwordHash = hash
+		}
+		f.sessions = sessions
+	}
+	return commit, rollback
+}
+
+func (f *fakeUsersDB) Query(q fakedb.Query) (*fakedb.Rows, error) {
+	args := q.Args
+
+	switch q.Name {
+	case "GetUserByEmail":
+		if u, ok := f.users[args[0].Value.(string)]; ok {
+			return userRows(u), nil
+		}
+	case "GetUserByID":
+		for _, u := range f.users {
+			if u.id.String() == args[0].Value.(string) {
+				return userRows(u), nil
+			}
+		}
+	case "CreateSession":
+		s := repository.Session{
+			ID:         uuid.New(),
+			UserID:     uuid.MustParse(args[0].Value.(string)),
+			TokenHash:  args[1].Value.(string),
+			ExpiresAt:  args[2].Value.(time.Time),
+			LastSeenAt: time.Now(),
+			UserAgent:  nullStringArg(args[3]),
+			IpAddress:  nullStringArg(args[4]),
+		}
+		f.sessions[s.TokenHash] = s
+		return sessionRows(s), nil
+	case "GetSessionByTokenHash":
+		if s, ok := f.sessions[args[0].Value.(string)]; ok && s.ExpiresAt.After(time.Now()) {
+			return sessionRows(s), nil
+		}
+	case "ListSessionsByUserID":
+		rows := &fakedb.Rows{Columns: 8}
+		for _, s := range f.sessions {
+			if s.UserID.String() == args[0].Value.(string) && s.ExpiresAt.After(time.Now()) {
+				rows.Values = append(rows.Values, sessionRows(s).Values[0])
+			}
+		}
+		return rows, nil
+	default:
+		return nil, fmt.Errorf("fakeUsersDB: unexpected query %q", q.Name)
+	}
+	return &fakedb.Rows{}, nil
+}
+
+func (f *fakeUsersDB) Exec(q fakedb.Query) (int64, error) {
+	args := q.Args
+
+	if q.Name == f.failExec {
+		return 0, fmt.Errorf("fakeUsersDB: %s failed", f.failExec)
+	}
+
+	switch q.Name {
+	case "UpdateUserPassword":
+		for _, u := range f.users {
+			if u.id.String() == args[0].Value.(string) {
+				u.passwordHash = args[1].Value.(string)
+			}
+		}
+		return 1, nil
+	case "AdminUpdateUserDisabled":
+		for _, u := range f.users {
+			if u.id.String() == args[0].Value.(string) {
+				u.disabledAt = nil
+				if t, ok := args[1].Value.(time.Time); ok {
+					u.disabledAt = &t
+				}
+				return 1, nil
+			}
+		}
+		return 0, nil
+	case "UpdateUserBusinessProfile":
+		for _, u := range f.users {
+			if u.id.String() == args[0].Value.(string) {
+				u.business = &repository.UpdateUserBusinessProfileParams{
+					ID:                    u.id,
+					BusinessName:          nullStringArg(args[1]),
+					BusinessAddressLine1:  nullStringArg(args[4]),
+					BusinessCity:          nullStringArg(args[6]),
+					BusinessState:         nullStringArg(args[7]),
+					BusinessPostalCode:    nullStringArg(args[8]),
+					BusinessLicenseNumber: nullStringArg(args[9]),
+					BusinessBrandColor:    nullStringArg(args[11]),
+				}
+			}
+		}
+		return 1, nil
+	case "TouchSession":
+		for hash, s := range f.sessions {
+			if s.ID.String() == args[0].Value.(string) {
+				s.LastSeenAt = time.Now()
+				s.UserAgent = nullStringArg(args[1])
+				s.IpAddress = nullStringArg(args[2])
+				f.sessions[hash] = s
+				f.touches++
+			}
+		}
+		return 1, nil
+	case "DeleteSession":
+		delete(f.sessions, args[0].Value.(string))
+		return 1, nil
+	case "DeleteSessionByIDAndUserID":
+		for hash, s := range f.sessions {
+			if s.ID.String() == args[0].Value.(string) && s.UserID.String() == args[1].Value.(string) {
+				delete(f.sessions, hash)
+				return 1, nil
+			}
+		}
+		return 0, nil
+	case "DeleteUserSessionsExcept":
+		var n int64
+		for hash, s := range f.sessions {
+			if s.UserID.String() == args[0].Value.(string) && hash != args[1].Value.(string) {
+				delete(f.sessions, hash)
+				n++
+			}
+		}
+		return n, nil
+	case "DeleteUserSessions":
+		var n int64
+		for hash, s := range f.sessions {
+			if s.UserID.String() == args[0].Value.(string) {
+				delete(f.sessions, hash)
+				n++
+			}
+		}
+		return n, nil
+	}
+	return 0, fmt.Errorf("fakeUsersDB: unexpected exec %q", q.Name)
+}
+
+// userRows returns a users row in repository.User column order.
+func userRows(u *fakeUserRow) *fakedb.Rows {
+	values := make([]driver.Value, 29)
+	values[0] = u.id.String()
+	values[1] = u.email
+	values[2] = u.passwordHash
+	values[3] = "Test User"
+	values[10] = true
+	values[12] = time.Now()
+	values[13] = time.Now()
+	if u.disabledAt != nil {
+		values[24] = *u.disabledAt
+	}
+	values[26] = int64(0)
+	return &fakedb.Rows{Columns: 29, Values: [][]driver.Value{values}}
+}
+
+// sessionRows returns a sessions row in repository.Session column order.
+func sessionRows(s repository.Session) *fakedb.Rows {
+	var userAgent, ipAddress driver.Value
+	if s.UserAgent.Valid {
+		userAgent = s.UserAgent.String
+	}
+	if s.IpAddress.Valid {
+		ipAddress = s.IpAddress.String
+	}
+	return &fakedb.Rows{Columns: 8, Values: [][]driver.Value{{
+		s.ID.String(), s.UserID.String(), s.TokenHash, s.ExpiresAt, time.Now(), s.LastSeenAt, userAgent, ipAddress,
+	}}}
+}
+
+// nullStringArg converts a sql.NullString query argument back to a NullString.
+func nullStringArg(arg driver.NamedValue) sql.NullString {
+	if v, ok := arg.Value.(string); ok {
+		return sql.NullString{String: v, Valid: true}
+	}
+	return sql.NullString{}
+}
+
+// newFakeDBUserService returns a user service backed by f.
+func newFakeDBUserService(f *fakeUsersDB, cfg UserServiceConfig) UserService {
+	queries := repository.New(fakedb.Open(f))
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewUserServiceWithConfig(queries, repository.NewUnitOfWork(queries), logger, cfg)
+}
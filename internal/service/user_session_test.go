@@ -1,8 +1,11 @@
 package service
 
 import (
+	"context"
 	"testing"
 	"time"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
 )
 
 // =============================================================================
@@ -57,7 +60,7 @@ func TestSessionDurationMinimum(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			result := normalizeSessionDuration(tc.input)
+			result := SessionDuration(tc.input)
 			if result != tc.shouldUse {
 				t.Errorf("expected %v, got %v", tc.shouldUse, result)
 			}
@@ -81,7 +84,7 @@ func TestSessionDurationMaximum(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			result := normalizeSessionDuration(tc.input)
+			result := SessionDuration(tc.input)
 			if result != tc.shouldUse {
 				t.Errorf("expected %v, got %v", tc.shouldUse, result)
 			}
@@ -91,8 +94,110 @@ func TestSessionDurationMaximum(t *testing.T) {
 
 func TestSessionDurationZeroUsesDefault(t *testing.T) {
 	// Zero duration should use the default
-	result := normalizeSessionDuration(0)
+	result := SessionDuration(0)
 	if result != DefaultSessionDuration {
 		t.Errorf("expected default %v for zero input, got %v", DefaultSessionDuration, result)
 	}
 }
+
+// =============================================================================
+// Session Idle Timeout Tests
+// =============================================================================
+
+func TestNormalizeSessionIdleTimeout(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    time.Duration
+		expected time.Duration
+	}{
+		{"zero disables", 0, 0},
+		{"negative disables", -time.Minute, 0},
+		{"below minimum uses minimum", time.Minute, MinSessionIdleTimeout},
+		{"above minimum uses input", 30 * time.Minute, 30 * time.Minute},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := normalizeSessionIdleTimeout(tc.input)
+			if result != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestGetBySessionToken_IdleExpiry(t *testing.T) {
+	ctx := context.Background()
+	f := newFakeUsersDB()
+	user := f.addUser(t, "inspector@example.com", "correct-horse-1")
+	svc := newFakeDBUserService(f, UserServiceConfig{SessionIdleTimeout: 30 * time.Minute})
+
+	login, err := svc.Login(ctx, user.email, "correct-horse-1")
+	if err != nil {
+		t.Fatalf("login failed: %v", err)
+	}
+
+	f.setLastSeen(31 * time.Minute)
+
+	_, err = svc.GetBySessionToken(ctx, login.Token)
+	if domain.ErrorCode(err) != domain.EUNAUTHORIZED {
+		t.Errorf("expected EUNAUTHORIZED for idle session, got %v", err)
+	}
+	if len(f.sessions) != 0 {
+		t.Error("expected idle session to be deleted")
+	}
+}
+
+func TestGetBySessionToken_SlidingIdleWindow(t *testing.T) {
+	ctx := context.Background()
+	f := newFakeUsersDB()
+	user := f.addUser(t, "inspector@example.com", "correct-horse-1")
+	svc := newFakeDBUserService(f, UserServiceConfig{SessionIdleTimeout: 30 * time.Minute})
+
+	login, err := svc.Login(ctx, user.email, "correct-horse-1")
+	if err != nil {
+		t.Fatalf("login failed: %v", err)
+	}
+
+	// Two 20-minute gaps add up to more than the timeout, but each request
+	// renews the window.
+	for i := 0; i < 2; i++ {
+		f.setLastSeen(20 * time.Minute)
+		if _, err := svc.GetBySessionToken(ctx, login.Token); err != nil {
+			t.Fatalf("request %d: expected active session, got %v", i+1, err)
+		}
+	}
+	if f.touches != 2 {
+		t.Errorf("expected 2 last_seen_at updates, got %d", f.touches)
+	}
+
+	// Requests within SessionTouchInterval do not write
+	if _, err := svc.GetBySessionToken(ctx, login.Token); err != nil {
+		t.Fatalf("expected active session, got %v", err)
+	}
+	if f.touches != 2 {
+		t.Errorf("expected throttled update, got %d updates", f.touches)
+	}
+}
+
+func TestGetBySessionToken_NoIdleTimeoutByDefault(t *testing.T) {
+	ctx := context.Background()
+	f := newFakeUsersDB()
+	user := f.addUser(t, "inspector@example.com", "correct-horse-1")
+	svc := newFakeDBUserService(f, UserServiceConfig{})
+
+	login, err := svc.Login(ctx, user.email, "correct-horse-1")
+	if err != nil {
+		t.Fatalf("login failed: %v", err)
+	}
+
+	f.setLastSeen(12 * time.Hour)
+
+	if _, err := svc.GetBySessionToken(ctx, login.Token); err != nil {
+		t.Errorf("expected session within absolute lifetime to be valid, got %v", err)
+	}
+	// last_seen_at is still recorded for the sessions page
+	if f.touches != 1 {
+		t.Errorf("expected last_seen_at update without idle timeout, got %d", f.touches)
+	}
+}
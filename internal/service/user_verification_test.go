@@ -0,0 +1,44 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+)
+
+// =============================================================================
+// Email Verification Policy Tests
+// =============================================================================
+
+func TestCheckEmailVerification(t *testing.T) {
+	grace := 72 * time.Hour
+	now := time.Now()
+
+	testCases := []struct {
+		name      string
+		grace     time.Duration
+		user      domain.User
+		wantAllow bool
+	}{
+		{"verified user", grace, domain.User{EmailVerified: true, CreatedAt: now.Add(-30 * 24 * time.Hour)}, true},
+		{"unverified within grace period", grace, domain.User{CreatedAt: now.Add(-24 * time.Hour)}, true},
+		{"unverified after grace period", grace, domain.User{CreatedAt: now.Add(-73 * time.Hour)}, false},
+		{"unverified with no grace period", 0, domain.User{CreatedAt: now}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := NewUserServiceWithConfig(nil, nil, nil, UserServiceConfig{EmailVerificationGracePeriod: tc.grace})
+
+			err := svc.CheckEmailVerification(&tc.user)
+
+			if tc.wantAllow && err != nil {
+				t.Errorf("expected access, got %v", err)
+			}
+			if !tc.wantAllow && domain.ErrorCode(err) != domain.EFORBIDDEN {
+				t.Errorf("expected EFORBIDDEN, got %v", err)
+			}
+		})
+	}
+}
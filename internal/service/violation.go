@@ -7,6 +7,7 @@ package service
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -36,9 +37,17 @@ type ViolationService interface {
 	// Returns domain.ENOTFOUND if violation doesn't exist or user doesn't own the inspection.
 	GetByIDWithRegulations(ctx context.Context, id, userID uuid.UUID) (*domain.Violation, []domain.ViolationRegulation, error)
 
-	// ListByInspection retrieves all violations for an inspection.
+	// ListByInspection retrieves violations for an inspection.
+	// An optional filter narrows and orders the result; nil returns all violations
+	// in stored sort order.
 	// Returns domain.ENOTFOUND if inspection doesn't exist or user doesn't own it.
-	ListByInspection(ctx context.Context, inspectionID, userID uuid.UUID) ([]domain.Violation, error)
+	ListByInspection(ctx context.Context, inspectionID, userID uuid.UUID, filter *domain.ViolationFilter) ([]domain.Violation, error)
+
+	// Search finds violations across the user's inspections a page at a
+	// time, newest inspection first. Other users' inspections, including
+	// those of organization members, are never searched.
+	// Returns domain.EINVALID for invalid filters.
+	Search(ctx context.Context, params domain.SearchViolationsParams) (*domain.SearchViolationsResult, error)
 
 	// Create creates a new manual violation.
 	// Returns domain.EINVALID for validation errors.
@@ -55,10 +64,62 @@ type ViolationService interface {
 	// Returns domain.ENOTFOUND if violation doesn't exist or user doesn't own the inspection.
 	UpdateStatus(ctx context.Context, params domain.UpdateViolationStatusParams) error
 
+	// UndoStatus reverts the most recent status change of a violation of the
+	// inspection, as recorded in its audit history, and returns the restored
+	// status.
+	// Returns domain.ENOTFOUND if violation doesn't exist, belongs to another inspection, or user doesn't own the inspection.
+	// Returns domain.ECONFLICT if there is no status change to undo or the status has changed since.
+	UndoStatus(ctx context.Context, inspectionID, id, userID uuid.UUID) (domain.ViolationStatus, error)
+
+	// UpdateSeverity overrides a violation's severity during review. The
+	// severity originally assigned by the AI is kept for comparison.
+	// Returns domain.EINVALID for invalid severity.
+	// Returns domain.ENOTFOUND if violation doesn't exist or user doesn't own the inspection.
+	UpdateSeverity(ctx context.Context, id, userID uuid.UUID, severity domain.ViolationSeverity) error
+
+	// UpdateNotes replaces a violation's inspector notes without changing its
+	// status or other details. Used by notes autosave.
+	// Returns domain.ENOTFOUND if violation doesn't exist or user doesn't own the inspection.
+	UpdateNotes(ctx context.Context, id, userID uuid.UUID, notes string) error
+
+	// BulkUpdateStatus updates the review status of several violations of one
+	// inspection atomically. Either every violation is updated or none are.
+	// Returns domain.ENOTFOUND if any violation doesn't exist, belongs to another
+	// inspection, or the user doesn't own the inspection.
+	BulkUpdateStatus(ctx context.Context, params domain.BulkUpdateViolationStatusParams) error
+
 	// Delete deletes a violation.
 	// Returns domain.ENOTFOUND if violation doesn't exist or user doesn't own the inspection.
 	Delete(ctx context.Context, id, userID uuid.UUID) error
 
+	// AddAnnotation stores a box the inspector drew on the violation's photo.
+	// Boxes overshooting the image edges are trimmed to fit.
+	// Returns domain.EINVALID if the violation has no photo or the box is invalid.
+	// Returns domain.ENOTFOUND if violation doesn't exist or user doesn't own the inspection.
+	AddAnnotation(ctx context.Context, params domain.CreateImageAnnotationParams) (*domain.ImageAnnotation, error)
+
+	// ListAnnotations returns the AI and inspector boxes on the violation's
+	// photo, oldest first.
+	// Returns domain.ENOTFOUND if violation doesn't exist or user doesn't own the inspection.
+	ListAnnotations(ctx context.Context, violationID, userID uuid.UUID) ([]domain.ImageAnnotation, error)
+
+	// ListImages returns the violation's additional photos in attached order.
+	// The primary photo (ImageID) is not included.
+	// Returns domain.ENOTFOUND if violation doesn't exist or user doesn't own the inspection.
+	ListImages(ctx context.Context, violationID, userID uuid.UUID) ([]domain.ViolationImage, error)
+
+	// AddImage attaches another photo of the same inspection to the violation.
+	// Returns domain.EINVALID if the photo is the primary photo or the
+	// violation already has domain.MaxViolationImages photos.
+	// Returns domain.ENOTFOUND if the violation or image doesn't exist, the
+	// image belongs to another inspection, or user doesn't own the inspection.
+	AddImage(ctx context.Context, violationID, imageID, userID uuid.UUID) error
+
+	// RemoveImage detaches an additional photo from the violation.
+	// Returns domain.ENOTFOUND if the violation doesn't exist, user doesn't own
+	// the inspection, or the photo isn't attached.
+	RemoveImage(ctx context.Context, violationID, imageID, userID uuid.UUID) error
+
 	// LinkRegulations finds and links relevant OSHA regulations to a violation.
 	// Tries AI-suggested standard numbers first, falls back to full-text search.
 	LinkRegulations(ctx context.Context, violationID uuid.UUID, suggestedRegs []string, description, category string) error
@@ -71,16 +132,27 @@ type ViolationService interface {
 // violationService implements the ViolationService interface.
 type violationService struct {
 	queries *repository.Queries
+	uow     repository.UnitOfWork
+	access  orgAccess
+	audit   AuditService
 	logger  *slog.Logger
 }
 
 // NewViolationService creates a new ViolationService.
+// Status changes and deletions are recorded through the audit service, and
+// multi-step writes run in one transaction of the unit of work.
+// Members of an organization can work on each other's violations.
 func NewViolationService(
 	queries *repository.Queries,
+	uow repository.UnitOfWork,
+	audit AuditService,
 	logger *slog.Logger,
 ) ViolationService {
 	return &violationService{
 		queries: queries,
+		uow:     uow,
+		access:  orgAccess{queries: queries},
+		audit:   audit,
 		logger:  logger,
 	}
 }
@@ -93,10 +165,14 @@ func NewViolationService(
 func (s *violationService) GetByID(ctx context.Context, id, userID uuid.UUID) (*domain.Violation, error) {
 	const op = "violation.get"
 
-	// Get violation with authorization check (joins to inspections to verify user ownership)
+	// Get violation with authorization check (joins to inspections to verify ownership)
+	ownerID, err := s.access.violationOwner(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
 	row, err := s.queries.GetViolationByIDAndUserID(ctx, repository.GetViolationByIDAndUserIDParams{
 		ID:     id,
-		UserID: userID,
+		UserID: ownerID,
 	})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -155,14 +231,18 @@ func (s *violationService) GetByIDWithRegulations(ctx context.Context, id, userI
 // ListByInspection
 // =============================================================================
 
-// ListByInspection retrieves all violations for an inspection.
-func (s *violationService) ListByInspection(ctx context.Context, inspectionID, userID uuid.UUID) ([]domain.Violation, error) {
+// ListByInspection retrieves violations for an inspection, optionally filtered.
+func (s *violationService) ListByInspection(ctx context.Context, inspectionID, userID uuid.UUID, filter *domain.ViolationFilter) ([]domain.Violation, error) {
 	const op = "violation.list"
 
-	// Verify user owns the inspection
-	_, err := s.queries.GetInspectionByIDAndUserID(ctx, repository.GetInspectionByIDAndUserIDParams{
+	// Verify user can access the inspection
+	ownerID, err := s.access.inspectionOwner(ctx, inspectionID, userID)
+	if err != nil {
+		return nil, err
+	}
+	_, err = s.queries.GetInspectionByIDAndUserID(ctx, repository.GetInspectionByIDAndUserIDParams{
 		ID:     inspectionID,
-		UserID: userID,
+		UserID: ownerID,
 	})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -182,14 +262,79 @@ func (s *violationService) ListByInspection(ctx context.Context, inspectionID, u
 		violations = append(violations, *s.rowToViolation(row))
 	}
 
+	if filter != nil {
+		violations = filter.Apply(violations)
+	}
+
 	return violations, nil
 }
 
+// =============================================================================
+// Search
+// =============================================================================
+
+// Search finds violations across the user's inspections matching params.
+func (s *violationService) Search(ctx context.Context, params domain.SearchViolationsParams) (*domain.SearchViolationsResult, error) {
+	const op = "violation.search"
+
+	params.Query = strings.TrimSpace(params.Query)
+	params.StandardNumber = strings.TrimSpace(params.StandardNumber)
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	total, err := s.queries.CountSearchViolationsByUserID(ctx, repository.CountSearchViolationsByUserIDParams{
+		UserID:         params.UserID,
+		Search:         params.Query,
+		Severity:       string(params.Severity),
+		Status:         string(params.Status),
+		FromDate:       nullTimeFromPtr(params.From),
+		ToDate:         nullTimeFromPtr(params.To),
+		StandardNumber: params.StandardNumber,
+	})
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to count violations")
+	}
+
+	rows, err := s.queries.SearchViolationsByUserID(ctx, repository.SearchViolationsByUserIDParams{
+		UserID:         params.UserID,
+		Search:         params.Query,
+		Severity:       string(params.Severity),
+		Status:         string(params.Status),
+		FromDate:       nullTimeFromPtr(params.From),
+		ToDate:         nullTimeFromPtr(params.To),
+		StandardNumber: params.StandardNumber,
+		Limit:          params.Limit,
+		Offset:         params.Offset,
+	})
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to search violations")
+	}
+
+	results := make([]domain.ViolationSearchResult, 0, len(rows))
+	for _, row := range rows {
+		results = append(results, domain.ViolationSearchResult{
+			Violation:       *s.rowToViolation(row.Violation),
+			InspectionTitle: row.InspectionTitle,
+			InspectionDate:  row.InspectionDate,
+			StandardNumber:  row.StandardNumber,
+		})
+	}
+
+	return &domain.SearchViolationsResult{
+		Results: results,
+		Total:   total,
+		Limit:   params.Limit,
+		Offset:  params.Offset,
+	}, nil
+}
+
 // =============================================================================
 // Create
 // =============================================================================
 
-// Create creates a new manual violation.
+// Create creates a new manual violation, linked to the given regulations.
+// The violation and its links are stored in one transaction.
 func (s *violationService) Create(ctx context.Context, params domain.CreateViolationParams) (*domain.Violation, error) {
 	const op = "violation.create"
 
@@ -198,10 +343,14 @@ func (s *violationService) Create(ctx context.Context, params domain.CreateViola
 		return nil, err
 	}
 
-	// Verify user owns the inspection
-	_, err := s.queries.GetInspectionByIDAndUserID(ctx, repository.GetInspectionByIDAndUserIDParams{
+	// Verify user can access the inspection
+	ownerID, err := s.access.inspectionOwner(ctx, params.InspectionID, params.UserID)
+	if err != nil {
+		return nil, err
+	}
+	_, err = s.queries.GetInspectionByIDAndUserID(ctx, repository.GetInspectionByIDAndUserIDParams{
 		ID:     params.InspectionID,
-		UserID: params.UserID,
+		UserID: ownerID,
 	})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -236,29 +385,62 @@ func (s *violationService) Create(ctx context.Context, params domain.CreateViola
 		}
 	}
 
-	// Create the violation
-	row, err := s.queries.CreateViolation(ctx, repository.CreateViolationParams{
-		InspectionID:   params.InspectionID,
-		ImageID:        domain.ToNullUUID(params.ImageID),
-		Description:    params.Description,
-		AiDescription:  sql.NullString{Valid: false},        // Manual violations have no AI description
-		Confidence:     sql.NullString{Valid: false},        // Manual violations have no confidence
-		BoundingBox:    pqtype.NullRawMessage{Valid: false}, // No bounding box for manual
-		Status:         string(domain.ViolationStatusPending),
-		Severity:       domain.ToNullString(string(params.Severity)),
-		InspectorNotes: domain.ToNullString(params.InspectorNotes),
-		SortOrder:      sql.NullInt32{Valid: true, Int32: maxSortOrder + 1},
+	// Create the violation and link its regulations
+	var row repository.Violation
+	err = s.uow.Do(ctx, func(q *repository.Queries) error {
+		var err error
+		row, err = q.CreateViolation(ctx, repository.CreateViolationParams{
+			InspectionID:   params.InspectionID,
+			ImageID:        domain.ToNullUUID(params.ImageID),
+			Description:    params.Description,
+			AiDescription:  sql.NullString{Valid: false},        // Manual violations have no AI description
+			Confidence:     sql.NullString{Valid: false},        // Manual violations have no confidence
+			BoundingBox:    pqtype.NullRawMessage{Valid: false}, // No bounding box for manual
+			Status:         string(domain.ViolationStatusPending),
+			Severity:       domain.ToNullString(string(params.Severity)),
+			InspectorNotes: domain.ToNullString(params.InspectorNotes),
+			SortOrder:      sql.NullInt32{Valid: true, Int32: maxSortOrder + 1},
+		})
+		if err != nil {
+			return domain.Internal(err, op, "failed to create violation")
+		}
+
+		linked := make(map[uuid.UUID]bool, len(params.RegulationIDs))
+		for i, regulationID := range params.RegulationIDs {
+			// A regulation listed twice is linked once, where it first appears
+			if linked[regulationID] {
+				continue
+			}
+			linked[regulationID] = true
+			if _, err := q.GetRegulationByID(ctx, regulationID); err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return domain.NotFound(op, "regulation", regulationID.String())
+				}
+				return domain.Internal(err, op, "failed to verify regulation exists")
+			}
+			if _, err := q.CreateViolationRegulation(ctx, repository.CreateViolationRegulationParams{
+				ViolationID:    row.ID,
+				RegulationID:   regulationID,
+				RelevanceScore: sql.NullFloat64{Float64: 1.0, Valid: true},
+				AiExplanation:  sql.NullString{String: "Manually added by inspector", Valid: true},
+				IsPrimary:      sql.NullBool{Bool: i == 0, Valid: true},
+			}); err != nil {
+				return domain.Internal(err, op, "failed to link regulation to violation")
+			}
+		}
+		return nil
 	})
 	if err != nil {
-		return nil, domain.Internal(err, op, "failed to create violation")
+		return nil, txError(err, op)
 	}
 
 	violation := s.rowToViolation(row)
 
-	s.logger.Info("violation created",
+	s.logger.InfoContext(ctx, "violation created",
 		"violation_id", violation.ID,
 		"inspection_id", params.InspectionID,
 		"user_id", params.UserID,
+		"regulations", len(params.RegulationIDs),
 	)
 
 	return violation, nil
@@ -298,10 +480,14 @@ func (s *violationService) Update(ctx context.Context, params domain.UpdateViola
 		return err
 	}
 
-	// Verify violation exists and user owns the inspection
-	_, err := s.queries.GetViolationByIDAndUserID(ctx, repository.GetViolationByIDAndUserIDParams{
+	// Verify violation exists and user can access the inspection
+	ownerID, err := s.access.violationOwner(ctx, params.ID, params.UserID)
+	if err != nil {
+		return err
+	}
+	_, err = s.queries.GetViolationByIDAndUserID(ctx, repository.GetViolationByIDAndUserIDParams{
 		ID:     params.ID,
-		UserID: params.UserID,
+		UserID: ownerID,
 	})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -321,7 +507,7 @@ func (s *violationService) Update(ctx context.Context, params domain.UpdateViola
 		return domain.Internal(err, op, "failed to update violation")
 	}
 
-	s.logger.Info("violation updated",
+	s.logger.InfoContext(ctx, "violation updated",
 		"violation_id", params.ID,
 		"user_id", params.UserID,
 	)
@@ -350,6 +536,46 @@ func (s *violationService) validateUpdateParams(params domain.UpdateViolationPar
 	return nil
 }
 
+// =============================================================================
+// UpdateNotes
+// =============================================================================
+
+// UpdateNotes replaces a violation's inspector notes.
+func (s *violationService) UpdateNotes(ctx context.Context, id, userID uuid.UUID, notes string) error {
+	const op = "violation.update_notes"
+
+	// Verify violation exists and user can access the inspection
+	ownerID, err := s.access.violationOwner(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	_, err = s.queries.GetViolationByIDAndUserID(ctx, repository.GetViolationByIDAndUserIDParams{
+		ID:     id,
+		UserID: ownerID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.NotFound(op, "violation", id.String())
+		}
+		return domain.Internal(err, op, "failed to verify violation ownership")
+	}
+
+	err = s.queries.UpdateViolationNotes(ctx, repository.UpdateViolationNotesParams{
+		ID:             id,
+		InspectorNotes: domain.ToNullString(notes),
+	})
+	if err != nil {
+		return domain.Internal(err, op, "failed to update violation notes")
+	}
+
+	s.logger.DebugContext(ctx, "violation notes saved",
+		"violation_id", id,
+		"user_id", userID,
+	)
+
+	return nil
+}
+
 // =============================================================================
 // UpdateStatus
 // =============================================================================
@@ -363,10 +589,14 @@ func (s *violationService) UpdateStatus(ctx context.Context, params domain.Updat
 		return domain.Invalid(op, fmt.Sprintf("invalid status: %s", params.Status))
 	}
 
-	// Verify violation exists and user owns the inspection
-	_, err := s.queries.GetViolationByIDAndUserID(ctx, repository.GetViolationByIDAndUserIDParams{
+	// Verify violation exists and user can access the inspection
+	ownerID, err := s.access.violationOwner(ctx, params.ID, params.UserID)
+	if err != nil {
+		return err
+	}
+	existing, err := s.queries.GetViolationByIDAndUserID(ctx, repository.GetViolationByIDAndUserIDParams{
 		ID:     params.ID,
-		UserID: params.UserID,
+		UserID: ownerID,
 	})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -375,16 +605,21 @@ func (s *violationService) UpdateStatus(ctx context.Context, params domain.Updat
 		return domain.Internal(err, op, "failed to verify violation ownership")
 	}
 
-	// Update status
-	err = s.queries.UpdateViolationStatus(ctx, repository.UpdateViolationStatusParams{
-		ID:     params.ID,
-		Status: string(params.Status),
-	})
+	// Update status and record the change atomically
+	err = s.audit.RecordChange(ctx, func(q *repository.Queries) error {
+		if err := q.UpdateViolationStatus(ctx, repository.UpdateViolationStatusParams{
+			ID:     params.ID,
+			Status: string(params.Status),
+		}); err != nil {
+			return domain.Internal(err, op, "failed to update violation status")
+		}
+		return nil
+	}, violationStatusAuditEvent(existing, params.UserID, params.Status))
 	if err != nil {
-		return domain.Internal(err, op, "failed to update violation status")
+		return err
 	}
 
-	s.logger.Info("violation status updated",
+	s.logger.InfoContext(ctx, "violation status updated",
 		"violation_id", params.ID,
 		"user_id", params.UserID,
 		"status", params.Status,
@@ -393,6 +628,180 @@ func (s *violationService) UpdateStatus(ctx context.Context, params domain.Updat
 	return nil
 }
 
+// =============================================================================
+// UndoStatus
+// =============================================================================
+
+// UndoStatus restores the status a violation had before its last status change.
+func (s *violationService) UndoStatus(ctx context.Context, inspectionID, id, userID uuid.UUID) (domain.ViolationStatus, error) {
+	const op = "violation.undo_status"
+
+	ownerID, err := s.access.violationOwner(ctx, id, userID)
+	if err != nil {
+		return "", err
+	}
+	existing, err := s.queries.GetViolationByIDAndUserID(ctx, repository.GetViolationByIDAndUserIDParams{
+		ID:     id,
+		UserID: ownerID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", domain.NotFound(op, "violation", id.String())
+		}
+		return "", domain.Internal(err, op, "failed to verify violation ownership")
+	}
+	if existing.InspectionID != inspectionID {
+		return "", domain.NotFound(op, "violation", id.String())
+	}
+
+	// The status to restore comes from the audit history, never the client
+	last, err := s.queries.GetLatestViolationStatusChange(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", domain.Conflict(op, "There is no review decision to undo.")
+		}
+		return "", domain.Internal(err, op, "failed to get last status change")
+	}
+	previous := domain.ViolationStatus(unmarshalAuditValues(last.OldValues)["status"])
+	if unmarshalAuditValues(last.NewValues)["status"] != existing.Status || !previous.IsValid() {
+		return "", domain.Conflict(op, "This violation has changed since the decision was made.")
+	}
+
+	err = s.audit.RecordChange(ctx, func(q *repository.Queries) error {
+		if err := q.UpdateViolationStatus(ctx, repository.UpdateViolationStatusParams{
+			ID:     id,
+			Status: string(previous),
+		}); err != nil {
+			return domain.Internal(err, op, "failed to update violation status")
+		}
+		return nil
+	}, violationStatusAuditEvent(existing, userID, previous))
+	if err != nil {
+		return "", err
+	}
+
+	s.logger.InfoContext(ctx, "violation status undone",
+		"violation_id", id,
+		"user_id", userID,
+		"status", previous,
+	)
+
+	return previous, nil
+}
+
+// =============================================================================
+// UpdateSeverity
+// =============================================================================
+
+// UpdateSeverity overrides a violation's severity. Only the severity column
+// changes; ai_severity keeps the value assigned at analysis time.
+func (s *violationService) UpdateSeverity(ctx context.Context, id, userID uuid.UUID, severity domain.ViolationSeverity) error {
+	const op = "violation.update_severity"
+
+	// Validate severity
+	if !severity.IsValid() {
+		return domain.Invalid(op, fmt.Sprintf("invalid severity: %s", severity))
+	}
+
+	// Verify violation exists and user can access the inspection
+	ownerID, err := s.access.violationOwner(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	existing, err := s.queries.GetViolationByIDAndUserID(ctx, repository.GetViolationByIDAndUserIDParams{
+		ID:     id,
+		UserID: ownerID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.NotFound(op, "violation", id.String())
+		}
+		return domain.Internal(err, op, "failed to verify violation ownership")
+	}
+
+	err = s.queries.UpdateViolationSeverity(ctx, repository.UpdateViolationSeverityParams{
+		ID:       id,
+		Severity: domain.ToNullString(string(severity)),
+	})
+	if err != nil {
+		return domain.Internal(err, op, "failed to update violation severity")
+	}
+
+	s.logger.InfoContext(ctx, "violation severity updated",
+		"violation_id", id,
+		"user_id", userID,
+		"severity", severity,
+		"ai_severity", domain.NullStringValue(existing.AiSeverity),
+	)
+
+	return nil
+}
+
+// =============================================================================
+// BulkUpdateStatus
+// =============================================================================
+
+// BulkUpdateStatus updates the review status of several violations atomically.
+func (s *violationService) BulkUpdateStatus(ctx context.Context, params domain.BulkUpdateViolationStatusParams) error {
+	const op = "violation.bulk_update_status"
+
+	// Validate status
+	if !params.Status.IsValid() {
+		return domain.Invalid(op, fmt.Sprintf("invalid status: %s", params.Status))
+	}
+	if len(params.IDs) == 0 {
+		return nil
+	}
+
+	// Verify every violation exists, belongs to the inspection, and the user can access it
+	ownerID, err := s.access.inspectionOwner(ctx, params.InspectionID, params.UserID)
+	if err != nil {
+		return err
+	}
+	events := make([]domain.AuditEvent, 0, len(params.IDs))
+	for _, id := range params.IDs {
+		existing, err := s.queries.GetViolationByIDAndUserID(ctx, repository.GetViolationByIDAndUserIDParams{
+			ID:     id,
+			UserID: ownerID,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return domain.NotFound(op, "violation", id.String())
+			}
+			return domain.Internal(err, op, "failed to verify violation ownership")
+		}
+		if existing.InspectionID != params.InspectionID {
+			return domain.NotFound(op, "violation", id.String())
+		}
+		events = append(events, violationStatusAuditEvent(existing, params.UserID, params.Status))
+	}
+
+	// Update all statuses and record the changes in one transaction
+	err = s.audit.RecordChange(ctx, func(q *repository.Queries) error {
+		for _, id := range params.IDs {
+			if err := q.UpdateViolationStatus(ctx, repository.UpdateViolationStatusParams{
+				ID:     id,
+				Status: string(params.Status),
+			}); err != nil {
+				return domain.Internal(err, op, "failed to update violation status")
+			}
+		}
+		return nil
+	}, events...)
+	if err != nil {
+		return err
+	}
+
+	s.logger.InfoContext(ctx, "violation statuses updated",
+		"inspection_id", params.InspectionID,
+		"user_id", params.UserID,
+		"count", len(params.IDs),
+		"status", params.Status,
+	)
+
+	return nil
+}
+
 // =============================================================================
 // Delete
 // =============================================================================
@@ -401,10 +810,14 @@ func (s *violationService) UpdateStatus(ctx context.Context, params domain.Updat
 func (s *violationService) Delete(ctx context.Context, id, userID uuid.UUID) error {
 	const op = "violation.delete"
 
-	// Verify violation exists and user owns the inspection
-	_, err := s.queries.GetViolationByIDAndUserID(ctx, repository.GetViolationByIDAndUserIDParams{
+	// Verify violation exists and user can access the inspection
+	ownerID, err := s.access.violationOwner(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	existing, err := s.queries.GetViolationByIDAndUserID(ctx, repository.GetViolationByIDAndUserIDParams{
 		ID:     id,
-		UserID: userID,
+		UserID: ownerID,
 	})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -413,16 +826,32 @@ func (s *violationService) Delete(ctx context.Context, id, userID uuid.UUID) err
 		return domain.Internal(err, op, "failed to verify violation ownership")
 	}
 
-	// Delete the violation (cascades to violation_regulations)
-	err = s.queries.DeleteViolationByIDAndUserID(ctx, repository.DeleteViolationByIDAndUserIDParams{
-		ID:     id,
-		UserID: userID,
+	// Delete the violation (cascades to violation_regulations) and record it atomically
+	inspectionID := existing.InspectionID
+	err = s.audit.RecordChange(ctx, func(q *repository.Queries) error {
+		if err := q.DeleteViolationByIDAndUserID(ctx, repository.DeleteViolationByIDAndUserIDParams{
+			ID:     id,
+			UserID: ownerID,
+		}); err != nil {
+			return domain.Internal(err, op, "failed to delete violation")
+		}
+		return nil
+	}, domain.AuditEvent{
+		ActorUserID:  userID,
+		InspectionID: &inspectionID,
+		EntityType:   domain.AuditEntityViolation,
+		EntityID:     id,
+		Action:       domain.AuditActionDeleted,
+		OldValues: map[string]string{
+			"status":      existing.Status,
+			"description": existing.Description,
+		},
 	})
 	if err != nil {
-		return domain.Internal(err, op, "failed to delete violation")
+		return err
 	}
 
-	s.logger.Info("violation deleted",
+	s.logger.InfoContext(ctx, "violation deleted",
 		"violation_id", id,
 		"user_id", userID,
 	)
@@ -434,6 +863,20 @@ func (s *violationService) Delete(ctx context.Context, id, userID uuid.UUID) err
 // Helper Functions
 // =============================================================================
 
+// violationStatusAuditEvent builds the audit event for a violation status change.
+func violationStatusAuditEvent(existing repository.Violation, userID uuid.UUID, status domain.ViolationStatus) domain.AuditEvent {
+	inspectionID := existing.InspectionID
+	return domain.AuditEvent{
+		ActorUserID:  userID,
+		InspectionID: &inspectionID,
+		EntityType:   domain.AuditEntityViolation,
+		EntityID:     existing.ID,
+		Action:       domain.AuditActionStatusChanged,
+		OldValues:    map[string]string{"status": existing.Status},
+		NewValues:    map[string]string{"status": string(status)},
+	}
+}
+
 // rowToViolation converts a repository violation row to a domain Violation.
 func (s *violationService) rowToViolation(row repository.Violation) *domain.Violation {
 	createdAt := time.Time{}
@@ -457,9 +900,10 @@ func (s *violationService) rowToViolation(row repository.Violation) *domain.Viol
 		Description:    row.Description,
 		AIDescription:  domain.NullStringValue(row.AiDescription),
 		Confidence:     domain.ViolationConfidence(domain.NullStringValue(row.Confidence)),
-		BoundingBox:    "", // Skip bounding box for now (pqtype.NullRawMessage)
+		BoundingBox:    boundingBoxFromJSON(row.BoundingBox),
 		Status:         domain.ViolationStatus(row.Status),
 		Severity:       domain.ViolationSeverity(domain.NullStringValue(row.Severity)),
+		AISeverity:     domain.ViolationSeverity(domain.NullStringValue(row.AiSeverity)),
 		InspectorNotes: domain.NullStringValue(row.InspectorNotes),
 		SortOrder:      sortOrder,
 		CreatedAt:      createdAt,
@@ -467,6 +911,23 @@ func (s *violationService) rowToViolation(row repository.Violation) *domain.Viol
 	}
 }
 
+// boundingBoxFromJSON reads the bounding box stored with a violation.
+// Missing or invalid boxes are nil, so the full photo is shown instead.
+func boundingBoxFromJSON(data pqtype.NullRawMessage) *domain.AnnotationBox {
+	if !data.Valid {
+		return nil
+	}
+	var box domain.AnnotationBox
+	if err := json.Unmarshal(data.RawMessage, &box); err != nil {
+		return nil
+	}
+	box, err := box.Normalize()
+	if err != nil {
+		return nil
+	}
+	return &box
+}
+
 // =============================================================================
 // LinkRegulations
 // =============================================================================
@@ -479,7 +940,7 @@ func (s *violationService) LinkRegulations(ctx context.Context, violationID uuid
 	if len(suggestedRegs) > 0 {
 		aiRegs, err := s.queries.GetRegulationsByStandardNumbers(ctx, suggestedRegs)
 		if err != nil {
-			s.logger.Warn("Failed to lookup AI-suggested regulations, falling back to full-text search",
+			s.logger.WarnContext(ctx, "Failed to lookup AI-suggested regulations, falling back to full-text search",
 				"error", err,
 				"violation_id", violationID,
 			)
@@ -503,7 +964,7 @@ func (s *violationService) LinkRegulations(ctx context.Context, violationID uuid
 					},
 				})
 				if err != nil {
-					s.logger.Error("Failed to link AI-suggested regulation",
+					s.logger.ErrorContext(ctx, "Failed to link AI-suggested regulation",
 						"error", err,
 						"violation_id", violationID,
 						"regulation_id", reg.ID,
@@ -511,7 +972,7 @@ func (s *violationService) LinkRegulations(ctx context.Context, violationID uuid
 					continue
 				}
 
-				s.logger.Info("Linked AI-suggested regulation",
+				s.logger.InfoContext(ctx, "Linked AI-suggested regulation",
 					"violation_id", violationID,
 					"regulation_id", reg.ID,
 					"standard_number", reg.StandardNumber,
@@ -519,7 +980,7 @@ func (s *violationService) LinkRegulations(ctx context.Context, violationID uuid
 				)
 			}
 
-			s.logger.Info("Successfully linked AI-suggested regulations",
+			s.logger.InfoContext(ctx, "Successfully linked AI-suggested regulations",
 				"violation_id", violationID,
 				"matched_count", len(aiRegs),
 				"suggested_count", len(suggestedRegs),
@@ -542,7 +1003,7 @@ func (s *violationService) LinkRegulations(ctx context.Context, violationID uuid
 		return domain.Internal(err, op, "failed to search regulations")
 	}
 
-	s.logger.Info("Found matching regulations via full-text search",
+	s.logger.InfoContext(ctx, "Found matching regulations via full-text search",
 		"violation_id", violationID,
 		"count", len(regulations),
 		"query", searchQuery,
@@ -567,7 +1028,7 @@ func (s *violationService) LinkRegulations(ctx context.Context, violationID uuid
 			},
 		})
 		if err != nil {
-			s.logger.Error("Failed to link regulation",
+			s.logger.ErrorContext(ctx, "Failed to link regulation",
 				"error", err,
 				"violation_id", violationID,
 				"regulation_id", reg.ID,
@@ -575,7 +1036,7 @@ func (s *violationService) LinkRegulations(ctx context.Context, violationID uuid
 			continue
 		}
 
-		s.logger.Info("Linked regulation to violation",
+		s.logger.InfoContext(ctx, "Linked regulation to violation",
 			"violation_id", violationID,
 			"regulation_id", reg.ID,
 			"standard_number", reg.StandardNumber,
@@ -0,0 +1,119 @@
+// Package service contains the business logic layer.
+//
+// This file implements image annotations: boxes drawn over a violation's
+// photo to show where the hazard is. AI analysis stores the regions it
+// reports; inspectors can draw their own.
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// AddAnnotation
+// =============================================================================
+
+// AddAnnotation stores a box the inspector drew on a violation's photo.
+func (s *violationService) AddAnnotation(ctx context.Context, params domain.CreateImageAnnotationParams) (*domain.ImageAnnotation, error) {
+	const op = "violation.add_annotation"
+
+	violation, err := s.GetByID(ctx, params.ViolationID, params.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if violation.ImageID == nil {
+		return nil, domain.Invalid(op, "violation has no photo to annotate")
+	}
+
+	box, err := params.Box.Normalize()
+	if err != nil {
+		return nil, domain.Invalid(op, err.Error())
+	}
+
+	label := strings.TrimSpace(params.Label)
+	if utf8.RuneCountInString(label) > domain.MaxAnnotationLabelLength {
+		return nil, domain.Invalid(op, fmt.Sprintf("label must be %d characters or less", domain.MaxAnnotationLabelLength))
+	}
+
+	row, err := s.queries.CreateImageAnnotation(ctx, repository.CreateImageAnnotationParams{
+		ViolationID: violation.ID,
+		ImageID:     *violation.ImageID,
+		X:           box.X,
+		Y:           box.Y,
+		Width:       box.Width,
+		Height:      box.Height,
+		Label:       label,
+		Source:      string(domain.AnnotationSourceManual),
+	})
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to create annotation")
+	}
+
+	s.logger.InfoContext(ctx, "image annotation added",
+		"annotation_id", row.ID,
+		"violation_id", violation.ID,
+		"image_id", row.ImageID,
+		"user_id", params.UserID,
+	)
+
+	annotation := annotationRowToDomain(row)
+	return &annotation, nil
+}
+
+// =============================================================================
+// ListAnnotations
+// =============================================================================
+
+// ListAnnotations returns the boxes drawn on a violation's photo, oldest first.
+func (s *violationService) ListAnnotations(ctx context.Context, violationID, userID uuid.UUID) ([]domain.ImageAnnotation, error) {
+	const op = "violation.list_annotations"
+
+	if _, err := s.GetByID(ctx, violationID, userID); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.queries.ListImageAnnotationsByViolationID(ctx, violationID)
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to list annotations")
+	}
+
+	return annotationRowsToDomain(rows), nil
+}
+
+// =============================================================================
+// Helper Functions
+// =============================================================================
+
+// annotationRowToDomain converts a repository.ImageAnnotation to a domain.ImageAnnotation.
+func annotationRowToDomain(row repository.ImageAnnotation) domain.ImageAnnotation {
+	return domain.ImageAnnotation{
+		ID:          row.ID,
+		ViolationID: row.ViolationID,
+		ImageID:     row.ImageID,
+		Box: domain.AnnotationBox{
+			X:      row.X,
+			Y:      row.Y,
+			Width:  row.Width,
+			Height: row.Height,
+		},
+		Label:     row.Label,
+		Source:    domain.AnnotationSource(row.Source),
+		CreatedAt: row.CreatedAt,
+	}
+}
+
+// annotationRowsToDomain converts annotation rows, keeping an empty result non-nil.
+func annotationRowsToDomain(rows []repository.ImageAnnotation) []domain.ImageAnnotation {
+	annotations := make([]domain.ImageAnnotation, len(rows))
+	for i, row := range rows {
+		annotations[i] = annotationRowToDomain(row)
+	}
+	return annotations
+}
@@ -0,0 +1,142 @@
+// Package service contains the business logic layer.
+//
+// This file implements a violation's additional evidence photos. The
+// violation's image_id stays its primary photo; inspectors can attach up to
+// domain.MaxViolationImages photos in all from the same inspection, such as
+// a wide shot to go with a close-up.
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// ListImages
+// =============================================================================
+
+// ListImages returns a violation's additional photos in attached order.
+func (s *violationService) ListImages(ctx context.Context, violationID, userID uuid.UUID) ([]domain.ViolationImage, error) {
+	const op = "violation.list_images"
+
+	if _, err := s.GetByID(ctx, violationID, userID); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.queries.ListViolationImages(ctx, violationID)
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to list violation photos")
+	}
+
+	images := make([]domain.ViolationImage, 0, len(rows))
+	for _, row := range rows {
+		images = append(images, domain.ViolationImage{
+			ImageID:          row.ImageID,
+			Position:         int(row.Position),
+			ThumbnailKey:     domain.NullStringValue(row.ThumbnailKey),
+			OriginalFilename: domain.NullStringValue(row.OriginalFilename),
+		})
+	}
+	return images, nil
+}
+
+// =============================================================================
+// AddImage
+// =============================================================================
+
+// AddImage attaches another photo of the violation's inspection to it, after
+// the photos already attached. Attaching a photo twice changes nothing.
+func (s *violationService) AddImage(ctx context.Context, violationID, imageID, userID uuid.UUID) error {
+	const op = "violation.add_image"
+
+	violation, err := s.GetByID(ctx, violationID, userID)
+	if err != nil {
+		return err
+	}
+
+	image, err := s.queries.GetImageByID(ctx, imageID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.NotFound(op, "image", imageID.String())
+		}
+		return domain.Internal(err, op, "failed to verify image")
+	}
+	if image.InspectionID != violation.InspectionID {
+		// Reported as missing so other inspections' image IDs can't be probed
+		return domain.NotFound(op, "image", imageID.String())
+	}
+	if violation.ImageID != nil && *violation.ImageID == imageID {
+		return domain.Invalid(op, "this photo is already the violation's primary photo")
+	}
+
+	attached, err := s.queries.ListViolationImages(ctx, violationID)
+	if err != nil {
+		return domain.Internal(err, op, "failed to list violation photos")
+	}
+	count := len(attached)
+	for _, a := range attached {
+		if a.ImageID == imageID {
+			return nil
+		}
+	}
+	if violation.ImageID != nil {
+		count++
+	}
+	if count >= domain.MaxViolationImages {
+		return domain.Invalid(op, fmt.Sprintf("a violation can have at most %d photos", domain.MaxViolationImages))
+	}
+
+	if _, err := s.queries.AddViolationImage(ctx, repository.AddViolationImageParams{
+		ViolationID: violationID,
+		ImageID:     imageID,
+	}); err != nil {
+		return domain.Internal(err, op, "failed to attach photo")
+	}
+
+	s.logger.InfoContext(ctx, "violation photo attached",
+		"violation_id", violationID,
+		"image_id", imageID,
+		"user_id", userID,
+	)
+
+	return nil
+}
+
+// =============================================================================
+// RemoveImage
+// =============================================================================
+
+// RemoveImage detaches one of a violation's additional photos. The image
+// itself is kept, as is the violation's primary photo.
+func (s *violationService) RemoveImage(ctx context.Context, violationID, imageID, userID uuid.UUID) error {
+	const op = "violation.remove_image"
+
+	if _, err := s.GetByID(ctx, violationID, userID); err != nil {
+		return err
+	}
+
+	removed, err := s.queries.RemoveViolationImage(ctx, repository.RemoveViolationImageParams{
+		ViolationID: violationID,
+		ImageID:     imageID,
+	})
+	if err != nil {
+		return domain.Internal(err, op, "failed to detach photo")
+	}
+	if removed == 0 {
+		return domain.NotFound(op, "violation photo", imageID.String())
+	}
+
+	s.logger.InfoContext(ctx, "violation photo detached",
+		"violation_id", violationID,
+		"image_id", imageID,
+		"user_id", userID,
+	)
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/DukeRupert/lukaut/internal/testutil/fakedb"
+	"github.com/google/uuid"
+)
+
+// newPhotosTestService returns a violation service over one violation whose
+// inspection has n photos, the first of them the violation's primary photo.
+func newPhotosTestService(n int) (ViolationService, *fakeViolationsDB, *fakeViolationRow, []uuid.UUID) {
+	row := &fakeViolationRow{id: uuid.New(), inspectionID: uuid.New(), ownerID: uuid.New(), status: string(domain.ViolationStatusPending)}
+	f := &fakeViolationsDB{
+		violations: map[uuid.UUID]*fakeViolationRow{row.id: row},
+		images:     map[uuid.UUID]uuid.UUID{},
+		photos:     map[uuid.UUID][]uuid.UUID{},
+	}
+	var imageIDs []uuid.UUID
+	for range n {
+		id := uuid.New()
+		f.images[id] = row.inspectionID
+		imageIDs = append(imageIDs, id)
+	}
+	row.imageID = &imageIDs[0]
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	queries := repository.New(fakedb.Open(f))
+	return NewViolationService(queries, repository.NewUnitOfWork(queries), nil, logger), f, row, imageIDs
+}
+
+func TestViolationAddImage_AttachesInOrder(t *testing.T) {
+	svc, _, row, imageIDs := newPhotosTestService(3)
+	ctx := context.Background()
+
+	for _, id := range []uuid.UUID{imageIDs[2], imageIDs[1], imageIDs[2]} {
+		if err := svc.AddImage(ctx, row.id, id, row.ownerID); err != nil {
+			t.Fatalf("AddImage(%s) failed: %v", id, err)
+		}
+	}
+
+	images, err := svc.ListImages(ctx, row.id, row.ownerID)
+	if err != nil {
+		t.Fatalf("ListImages failed: %v", err)
+	}
+	if len(images) != 2 || images[0].ImageID != imageIDs[2] || images[1].ImageID != imageIDs[1] {
+		t.Errorf("expected photos 3 then 2 attached once each, got %+v", images)
+	}
+}
+
+func TestViolationAddImage_Rejected(t *testing.T) {
+	svc, f, row, imageIDs := newPhotosTestService(domain.MaxViolationImages + 1)
+	otherInspection := uuid.New()
+	f.images[otherInspection] = uuid.New()
+
+	tests := []struct {
+		name     string
+		imageID  uuid.UUID
+		userID   uuid.UUID
+		attached int
+		wantCode string
+	}{
+		{name: "primary photo", imageID: imageIDs[0], userID: row.ownerID, wantCode: domain.EINVALID},
+		{name: "another inspection's photo", imageID: otherInspection, userID: row.ownerID, wantCode: domain.ENOTFOUND},
+		{name: "missing photo", imageID: uuid.New(), userID: row.ownerID, wantCode: domain.ENOTFOUND},
+		{name: "another user's violation", imageID: imageIDs[1], userID: uuid.New(), wantCode: domain.ENOTFOUND},
+		{name: "photo limit reached", imageID: imageIDs[domain.MaxViolationImages], userID: row.ownerID, attached: domain.MaxViolationImages - 1, wantCode: domain.EINVALID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f.photos[row.id] = append([]uuid.UUID{}, imageIDs[1:1+tt.attached]...)
+
+			err := svc.AddImage(context.Background(), row.id, tt.imageID, tt.userID)
+
+			if code := domain.ErrorCode(err); code != tt.wantCode {
+				t.Errorf("expected %s, got %v", tt.wantCode, err)
+			}
+			if got := len(f.photos[row.id]); got != tt.attached {
+				t.Errorf("expected %d photos attached, got %d", tt.attached, got)
+			}
+		})
+	}
+}
+
+func TestViolationRemoveImage(t *testing.T) {
+	svc, f, row, imageIDs := newPhotosTestService(3)
+	ctx := context.Background()
+	f.photos[row.id] = []uuid.UUID{imageIDs[1], imageIDs[2]}
+
+	if err := svc.RemoveImage(ctx, row.id, imageIDs[1], uuid.New()); domain.ErrorCode(err) != domain.ENOTFOUND {
+		t.Errorf("expected ENOTFOUND for another user, got %v", err)
+	}
+	if err := svc.RemoveImage(ctx, row.id, imageIDs[1], row.ownerID); err != nil {
+		t.Fatalf("RemoveImage failed: %v", err)
+	}
+	if err := svc.RemoveImage(ctx, row.id, imageIDs[0], row.ownerID); domain.ErrorCode(err) != domain.ENOTFOUND {
+		t.Errorf("expected ENOTFOUND for the primary photo, got %v", err)
+	}
+
+	if photos := f.photos[row.id]; len(photos) != 1 || photos[0] != imageIDs[2] {
+		t.Errorf("expected only photo 3 left attached, got %v", photos)
+	}
+}
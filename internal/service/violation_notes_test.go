@@ -0,0 +1,533 @@
+package service
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/DukeRupert/lukaut/internal/testutil/fakedb"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// In-Memory Violations Database
+// =============================================================================
+
+// fakeViolationRow holds the columns the notes and severity tests care about.
+type fakeViolationRow struct {
+	id, inspectionID, ownerID uuid.UUID
+	imageID                   *uuid.UUID
+	status                    string
+	notes                     string
+	severity, aiSeverity      string
+}
+
+// fakeViolationsDB answers the sqlc queries used to create violations,
+// save their notes, severity, and status, and attach photos, dispatching on
+// the "-- name:" header.
+type fakeViolationsDB struct {
+	violations  map[uuid.UUID]*fakeViolationRow
+	orgs        map[uuid.UUID]uuid.UUID   // user ID -> organization ID
+	inspections map[uuid.UUID]uuid.UUID   // inspection ID -> owner ID, for creating violations
+	regulations map[uuid.UUID]bool        // Regulations that exist
+	links       map[uuid.UUID][]uuid.UUID // violation ID -> linked regulation IDs
+	images      map[uuid.UUID]uuid.UUID   // image ID -> inspection ID
+	photos      map[uuid.UUID][]uuid.UUID // violation ID -> additional image IDs, in order
+	audits      int                       // Audit events recorded
+	events      [][]driver.Value          // Audit event rows recorded, in order
+	failStatus  uuid.UUID                 // Violation whose status update fails
+}
+
+// Begin snapshots violations, their statuses and links, and the audit
+// count, which rollback restores.
+func (f *fakeViolationsDB) Begin() (commit, rollback func()) {
+	statuses := map[uuid.UUID]string{}
+	for id, row := range f.violations {
+		statuses[id] = row.status
+	}
+	links := map[uuid.UUID][]uuid.UUID{}
+	for id, regulationIDs := range f.links {
+		links[id] = slices.Clone(regulationIDs)
+	}
+	audits, events := f.audits, len(f.events)
+
+	return nil, func() {
+		for id, row := range f.violations {
+			status, ok := statuses[id]
+			if !ok {
+				delete(f.violations, id)
+				continue
+			}
+			row.status = status
+		}
+		f.links = links
+		f.audits = audits
+		f.events = f.events[:events]
+	}
+}
+
+func (f *fakeViolationsDB) Query(q fakedb.Query) (*fakedb.Rows, error) {
+	args := q.Args
+
+	if q.Name == "GetViolationOwnerIDForUser" {
+		// Mirrors the organization check: the owner and their organization's
+		// members resolve to the owner
+		row, ok := f.violations[uuid.MustParse(args[0].Value.(string))]
+		userID := uuid.MustParse(args[1].Value.(string))
+		if !ok {
+			return &fakedb.Rows{Columns: 1}, nil
+		}
+		org, inOrg := f.orgs[userID]
+		if row.ownerID != userID && (!inOrg || f.orgs[row.ownerID] != org) {
+			return &fakedb.Rows{Columns: 1}, nil
+		}
+		return &fakedb.Rows{Columns: 1, Values: [][]driver.Value{{row.ownerID.String()}}}, nil
+	}
+	switch q.Name {
+	case "CreateAuditEvent":
+		f.audits++
+		values := []driver.Value{
+			uuid.NewString(), args[0].Value, args[1].Value, args[2].Value, args[3].Value, args[4].Value, args[5].Value, args[6].Value, time.Now(),
+		}
+		f.events = append(f.events, values)
+		return &fakedb.Rows{Columns: 9, Values: [][]driver.Value{values}}, nil
+	case "GetLatestViolationStatusChange":
+		for i := len(f.events) - 1; i >= 0; i-- {
+			e := f.events[i]
+			if e[3] == string(domain.AuditEntityViolation) && e[4] == args[0].Value && e[5] == string(domain.AuditActionStatusChanged) {
+				return &fakedb.Rows{Columns: 9, Values: [][]driver.Value{e}}, nil
+			}
+		}
+		return &fakedb.Rows{Columns: 9}, nil
+	case "GetInspectionOwnerIDForUser":
+		// Creating violations is only tested by inspection owners
+		return &fakedb.Rows{Columns: 1}, nil
+	case "GetInspectionByIDAndUserID":
+		id := uuid.MustParse(args[0].Value.(string))
+		owner, ok := f.inspections[id]
+		if !ok || owner.String() != args[1].Value.(string) {
+			return &fakedb.Rows{Columns: 23}, nil
+		}
+		return inspectionRows(&fakeInspectionRow{id: id, userID: owner}), nil
+	case "ListViolationsByInspectionID":
+		rows := &fakedb.Rows{Columns: 14}
+		for _, row := range f.violations {
+			if row.inspectionID.String() == args[0].Value.(string) {
+				rows.Values = append(rows.Values, violationValues(row))
+			}
+		}
+		return rows, nil
+	case "CreateViolation":
+		row := &fakeViolationRow{
+			id:           uuid.New(),
+			inspectionID: uuid.MustParse(args[0].Value.(string)),
+			ownerID:      f.inspections[uuid.MustParse(args[0].Value.(string))],
+			status:       args[6].Value.(string),
+			severity:     nullableString(args[7].Value),
+			notes:        nullableString(args[8].Value),
+		}
+		f.violations[row.id] = row
+		return &fakedb.Rows{Columns: 14, Values: [][]driver.Value{violationValues(row)}}, nil
+	case "GetRegulationByID":
+		if !f.regulations[uuid.MustParse(args[0].Value.(string))] {
+			return &fakedb.Rows{Columns: 15}, nil
+		}
+		values := make([]driver.Value, 15)
+		values[0] = args[0].Value
+		values[1], values[2], values[3], values[5] = "1926.501(b)(1)", "Unprotected sides and edges", "Fall Protection", "Full text"
+		return &fakedb.Rows{Columns: 15, Values: [][]driver.Value{values}}, nil
+	case "CreateViolationRegulation":
+		violationID := uuid.MustParse(args[0].Value.(string))
+		f.links[violationID] = append(f.links[violationID], uuid.MustParse(args[1].Value.(string)))
+		return &fakedb.Rows{Columns: 7, Values: [][]driver.Value{{
+			uuid.NewString(), args[0].Value, args[1].Value, args[2].Value, args[3].Value, args[4].Value, time.Now(),
+		}}}, nil
+	case "GetImageByID":
+		id := uuid.MustParse(args[0].Value.(string))
+		inspectionID, ok := f.images[id]
+		if !ok {
+			return &fakedb.Rows{Columns: 15}, nil
+		}
+		values := make([]driver.Value, 15)
+		values[0], values[1], values[2], values[5], values[6], values[13] = id.String(), inspectionID.String(), "images/"+id.String(), "image/jpeg", int64(1024), int64(0)
+		return &fakedb.Rows{Columns: 15, Values: [][]driver.Value{values}}, nil
+	case "ListViolationImages":
+		rows := &fakedb.Rows{Columns: 4}
+		for i, imageID := range f.photos[uuid.MustParse(args[0].Value.(string))] {
+			rows.Values = append(rows.Values, []driver.Value{imageID.String(), int64(i + 1), "thumbnails/" + imageID.String(), nil})
+		}
+		return rows, nil
+	case "CountSearchViolationsByUserID":
+		return &fakedb.Rows{Columns: 1, Values: [][]driver.Value{{int64(len(f.search(args)))}}}, nil
+	case "SearchViolationsByUserID":
+		matched := f.search(args)
+		limit, offset := int(args[7].Value.(int64)), int(args[8].Value.(int64))
+		rows := &fakedb.Rows{Columns: 17}
+		for i := offset; i < len(matched) && i < offset+limit; i++ {
+			values := append(violationValues(matched[i]), "Roof inspection", time.Now(), "")
+			rows.Values = append(rows.Values, values)
+		}
+		return rows, nil
+	case "GetViolationByIDAndUserID":
+	default:
+		return nil, fmt.Errorf("fakeViolationsDB: unexpected query %q", q.Name)
+	}
+	// Mirrors the join on inspections: only the inspection owner matches
+	row, ok := f.violations[uuid.MustParse(args[0].Value.(string))]
+	if !ok || row.ownerID.String() != args[1].Value.(string) {
+		return &fakedb.Rows{Columns: 14}, nil
+	}
+	return &fakedb.Rows{Columns: 14, Values: [][]driver.Value{violationValues(row)}}, nil
+}
+
+// violationValues returns a violations row in repository.Violation column order.
+func violationValues(row *fakeViolationRow) []driver.Value {
+	values := make([]driver.Value, 14)
+	values[0] = row.id.String()
+	values[1] = row.inspectionID.String()
+	if row.imageID != nil {
+		values[2] = row.imageID.String()
+	}
+	values[3] = "Missing guardrail"
+	values[7] = row.status
+	if row.severity != "" {
+		values[8] = row.severity
+	}
+	if row.notes != "" {
+		values[9] = row.notes
+	}
+	values[10] = int64(0)
+	values[11] = time.Now()
+	values[12] = time.Now()
+	if row.aiSeverity != "" {
+		values[13] = row.aiSeverity
+	}
+	return values
+}
+
+func (f *fakeViolationsDB) Exec(q fakedb.Query) (int64, error) {
+	args := q.Args
+
+	row, ok := f.violations[uuid.MustParse(args[0].Value.(string))]
+	if !ok {
+		return 0, nil
+	}
+	switch q.Name {
+	case "UpdateViolationNotes":
+		row.notes = nullableString(args[1].Value)
+	case "UpdateViolationSeverity":
+		row.severity = nullableString(args[1].Value)
+	case "UpdateViolationStatus":
+		if row.id == f.failStatus {
+			return 0, fmt.Errorf("fakeViolationsDB: status update of %s failed", row.id)
+		}
+		row.status = args[1].Value.(string)
+	case "AddViolationImage":
+		imageID := uuid.MustParse(args[1].Value.(string))
+		if slices.Contains(f.photos[row.id], imageID) {
+			return 0, nil
+		}
+		f.photos[row.id] = append(f.photos[row.id], imageID)
+	case "RemoveViolationImage":
+		i := slices.Index(f.photos[row.id], uuid.MustParse(args[1].Value.(string)))
+		if i < 0 {
+			return 0, nil
+		}
+		f.photos[row.id] = slices.Delete(f.photos[row.id], i, i+1)
+	default:
+		return 0, fmt.Errorf("fakeViolationsDB: unexpected exec %q", q.Name)
+	}
+	return 1, nil
+}
+
+func newNotesTestService(row *fakeViolationRow) ViolationService {
+	return newOrgNotesTestService(row, nil)
+}
+
+// newOrgNotesTestService is newNotesTestService with users in organizations.
+func newOrgNotesTestService(row *fakeViolationRow, orgs map[uuid.UUID]uuid.UUID) ViolationService {
+	f := &fakeViolationsDB{violations: map[uuid.UUID]*fakeViolationRow{row.id: row}, orgs: orgs}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	queries := repository.New(fakedb.Open(f))
+	return NewViolationService(queries, repository.NewUnitOfWork(queries), nil, logger)
+}
+
+// =============================================================================
+// Violation Notes Tests
+// =============================================================================
+
+func TestViolationUpdateNotes_PersistsWithoutChangingStatus(t *testing.T) {
+	row := &fakeViolationRow{id: uuid.New(), inspectionID: uuid.New(), ownerID: uuid.New(), status: string(domain.ViolationStatusPending)}
+	svc := newNotesTestService(row)
+
+	if err := svc.UpdateNotes(context.Background(), row.id, row.ownerID, "Guardrail missing on east side"); err != nil {
+		t.Fatalf("UpdateNotes failed: %v", err)
+	}
+	if row.notes != "Guardrail missing on east side" {
+		t.Errorf("expected notes to be saved, got %q", row.notes)
+	}
+	if row.status != string(domain.ViolationStatusPending) {
+		t.Errorf("expected status to stay pending, got %q", row.status)
+	}
+
+	// Clearing the notes stores NULL
+	if err := svc.UpdateNotes(context.Background(), row.id, row.ownerID, ""); err != nil {
+		t.Fatalf("UpdateNotes failed: %v", err)
+	}
+	if row.notes != "" {
+		t.Errorf("expected notes to be cleared, got %q", row.notes)
+	}
+}
+
+func TestViolationUpdateNotes_OtherUsersViolation(t *testing.T) {
+	row := &fakeViolationRow{id: uuid.New(), inspectionID: uuid.New(), ownerID: uuid.New(), notes: "Original"}
+	svc := newNotesTestService(row)
+
+	err := svc.UpdateNotes(context.Background(), row.id, uuid.New(), "Overwritten")
+
+	if domain.ErrorCode(err) != domain.ENOTFOUND {
+		t.Errorf("expected ENOTFOUND, got %v", err)
+	}
+	if row.notes != "Original" {
+		t.Errorf("expected notes to be unchanged, got %q", row.notes)
+	}
+}
+
+func TestViolationUpdateNotes_OrganizationMember(t *testing.T) {
+	row := &fakeViolationRow{id: uuid.New(), inspectionID: uuid.New(), ownerID: uuid.New(), status: string(domain.ViolationStatusPending)}
+	member := uuid.New()
+	org := uuid.New()
+	svc := newOrgNotesTestService(row, map[uuid.UUID]uuid.UUID{row.ownerID: org, member: org})
+
+	if err := svc.UpdateNotes(context.Background(), row.id, member, "Checked by a colleague"); err != nil {
+		t.Fatalf("UpdateNotes failed: %v", err)
+	}
+	if row.notes != "Checked by a colleague" {
+		t.Errorf("expected notes to be saved, got %q", row.notes)
+	}
+}
+
+func TestViolationUpdateNotes_OtherOrganization(t *testing.T) {
+	row := &fakeViolationRow{id: uuid.New(), inspectionID: uuid.New(), ownerID: uuid.New(), notes: "Original"}
+	outsider := uuid.New()
+	svc := newOrgNotesTestService(row, map[uuid.UUID]uuid.UUID{row.ownerID: uuid.New(), outsider: uuid.New()})
+
+	err := svc.UpdateNotes(context.Background(), row.id, outsider, "Overwritten")
+
+	if domain.ErrorCode(err) != domain.ENOTFOUND {
+		t.Errorf("expected ENOTFOUND, got %v", err)
+	}
+	if row.notes != "Original" {
+		t.Errorf("expected notes to be unchanged, got %q", row.notes)
+	}
+}
+
+// =============================================================================
+// Bulk Status Tests
+// =============================================================================
+
+// newBulkStatusTestService returns a violation service that audits status
+// changes, with violations in one inspection owned by owner.
+func newBulkStatusTestService(owner uuid.UUID, n int) (ViolationService, *fakeViolationsDB, []uuid.UUID) {
+	f := &fakeViolationsDB{violations: map[uuid.UUID]*fakeViolationRow{}}
+	inspectionID := uuid.New()
+	var ids []uuid.UUID
+	for range n {
+		row := &fakeViolationRow{id: uuid.New(), inspectionID: inspectionID, ownerID: owner, status: string(domain.ViolationStatusPending)}
+		f.violations[row.id] = row
+		ids = append(ids, row.id)
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	queries := repository.New(fakedb.Open(f))
+	uow := repository.NewUnitOfWork(queries)
+	return NewViolationService(queries, uow, NewAuditService(queries, uow, logger), logger), f, ids
+}
+
+func TestViolationBulkUpdateStatus_UpdatesAndAuditsAll(t *testing.T) {
+	owner := uuid.New()
+	svc, f, ids := newBulkStatusTestService(owner, 2)
+
+	err := svc.BulkUpdateStatus(context.Background(), domain.BulkUpdateViolationStatusParams{
+		InspectionID: f.violations[ids[0]].inspectionID,
+		UserID:       owner,
+		IDs:          ids,
+		Status:       domain.ViolationStatusConfirmed,
+	})
+	if err != nil {
+		t.Fatalf("BulkUpdateStatus failed: %v", err)
+	}
+	for _, id := range ids {
+		if f.violations[id].status != string(domain.ViolationStatusConfirmed) {
+			t.Errorf("expected violation %s confirmed, got %q", id, f.violations[id].status)
+		}
+	}
+	if f.audits != 2 {
+		t.Errorf("expected 2 audit events, got %d", f.audits)
+	}
+}
+
+func TestViolationBulkUpdateStatus_RollsBackOnFailure(t *testing.T) {
+	owner := uuid.New()
+	svc, f, ids := newBulkStatusTestService(owner, 2)
+	f.failStatus = ids[1]
+
+	err := svc.BulkUpdateStatus(context.Background(), domain.BulkUpdateViolationStatusParams{
+		InspectionID: f.violations[ids[0]].inspectionID,
+		UserID:       owner,
+		IDs:          ids,
+		Status:       domain.ViolationStatusConfirmed,
+	})
+	if domain.ErrorCode(err) != domain.EINTERNAL {
+		t.Fatalf("expected EINTERNAL, got %v", err)
+	}
+
+	// The first violation's update ran before the failure and was rolled back
+	for _, id := range ids {
+		if f.violations[id].status != string(domain.ViolationStatusPending) {
+			t.Errorf("expected violation %s to stay pending, got %q", id, f.violations[id].status)
+		}
+	}
+	if f.audits != 0 {
+		t.Errorf("expected no audit events, got %d", f.audits)
+	}
+}
+
+func TestViolationUndoStatus_RestoresAuditedStatus(t *testing.T) {
+	ctx := context.Background()
+	owner := uuid.New()
+	svc, f, ids := newBulkStatusTestService(owner, 1)
+	row := f.violations[ids[0]]
+
+	if _, err := svc.UndoStatus(ctx, row.inspectionID, row.id, owner); domain.ErrorCode(err) != domain.ECONFLICT {
+		t.Errorf("expected ECONFLICT with no decision to undo, got %v", err)
+	}
+
+	for _, status := range []domain.ViolationStatus{domain.ViolationStatusRejected, domain.ViolationStatusConfirmed} {
+		if err := svc.UpdateStatus(ctx, domain.UpdateViolationStatusParams{ID: row.id, UserID: owner, Status: status}); err != nil {
+			t.Fatalf("UpdateStatus failed: %v", err)
+		}
+	}
+
+	restored, err := svc.UndoStatus(ctx, row.inspectionID, row.id, owner)
+	if err != nil {
+		t.Fatalf("UndoStatus failed: %v", err)
+	}
+	if restored != domain.ViolationStatusRejected || row.status != string(domain.ViolationStatusRejected) {
+		t.Errorf("expected the status before the last decision restored, got %q stored as %q", restored, row.status)
+	}
+	if f.audits != 3 {
+		t.Errorf("expected the undo to be audited, got %d audit events", f.audits)
+	}
+}
+
+func TestViolationUndoStatus_Rejections(t *testing.T) {
+	ctx := context.Background()
+	owner := uuid.New()
+	svc, f, ids := newBulkStatusTestService(owner, 1)
+	row := f.violations[ids[0]]
+	if err := svc.UpdateStatus(ctx, domain.UpdateViolationStatusParams{ID: row.id, UserID: owner, Status: domain.ViolationStatusConfirmed}); err != nil {
+		t.Fatalf("UpdateStatus failed: %v", err)
+	}
+
+	if _, err := svc.UndoStatus(ctx, uuid.New(), row.id, owner); domain.ErrorCode(err) != domain.ENOTFOUND {
+		t.Errorf("expected ENOTFOUND for a violation of another inspection, got %v", err)
+	}
+	if _, err := svc.UndoStatus(ctx, row.inspectionID, row.id, uuid.New()); domain.ErrorCode(err) != domain.ENOTFOUND {
+		t.Errorf("expected ENOTFOUND for another user's violation, got %v", err)
+	}
+
+	// A change made outside the audit history makes the decision stale
+	row.status = string(domain.ViolationStatusRejected)
+	if _, err := svc.UndoStatus(ctx, row.inspectionID, row.id, owner); domain.ErrorCode(err) != domain.ECONFLICT {
+		t.Errorf("expected ECONFLICT once the status has changed, got %v", err)
+	}
+	if row.status != string(domain.ViolationStatusRejected) {
+		t.Errorf("expected the status to stay rejected, got %q", row.status)
+	}
+}
+
+// =============================================================================
+// Create Tests
+// =============================================================================
+
+// newCreateTestService returns a violation service over an inspection
+// owned by owner, with one regulation that exists.
+func newCreateTestService(owner uuid.UUID) (ViolationService, *fakeViolationsDB, uuid.UUID, uuid.UUID) {
+	inspectionID, regulationID := uuid.New(), uuid.New()
+	f := &fakeViolationsDB{
+		violations:  map[uuid.UUID]*fakeViolationRow{},
+		inspections: map[uuid.UUID]uuid.UUID{inspectionID: owner},
+		regulations: map[uuid.UUID]bool{regulationID: true},
+		links:       map[uuid.UUID][]uuid.UUID{},
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	queries := repository.New(fakedb.Open(f))
+	return NewViolationService(queries, repository.NewUnitOfWork(queries), nil, logger), f, inspectionID, regulationID
+}
+
+func TestViolationCreate_LinksRegulations(t *testing.T) {
+	owner := uuid.New()
+	svc, f, inspectionID, regulationID := newCreateTestService(owner)
+
+	violation, err := svc.Create(context.Background(), domain.CreateViolationParams{
+		InspectionID:  inspectionID,
+		UserID:        owner,
+		Description:   "Open floor hole without cover",
+		Severity:      domain.ViolationSeveritySerious,
+		RegulationIDs: []uuid.UUID{regulationID},
+	})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if links := f.links[violation.ID]; len(links) != 1 || links[0] != regulationID {
+		t.Errorf("expected the regulation linked, got %v", links)
+	}
+}
+
+func TestViolationCreate_LinksARepeatedRegulationOnce(t *testing.T) {
+	owner := uuid.New()
+	svc, f, inspectionID, regulationID := newCreateTestService(owner)
+
+	violation, err := svc.Create(context.Background(), domain.CreateViolationParams{
+		InspectionID:  inspectionID,
+		UserID:        owner,
+		Description:   "Open floor hole without cover",
+		Severity:      domain.ViolationSeveritySerious,
+		RegulationIDs: []uuid.UUID{regulationID, regulationID},
+	})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if links := f.links[violation.ID]; len(links) != 1 || links[0] != regulationID {
+		t.Errorf("expected the regulation linked once, got %v", links)
+	}
+}
+
+func TestViolationCreate_RollsBackWhenALinkFails(t *testing.T) {
+	owner := uuid.New()
+	svc, f, inspectionID, regulationID := newCreateTestService(owner)
+	missing := uuid.New()
+
+	// The violation and first link are written before the missing
+	// regulation is found
+	_, err := svc.Create(context.Background(), domain.CreateViolationParams{
+		InspectionID:  inspectionID,
+		UserID:        owner,
+		Description:   "Open floor hole without cover",
+		Severity:      domain.ViolationSeveritySerious,
+		RegulationIDs: []uuid.UUID{regulationID, missing},
+	})
+	if domain.ErrorCode(err) != domain.ENOTFOUND {
+		t.Fatalf("expected ENOTFOUND for the missing regulation, got %v", err)
+	}
+	if len(f.violations) != 0 || len(f.links) != 0 {
+		t.Errorf("expected nothing stored, got %d violations and %d linked", len(f.violations), len(f.links))
+	}
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/DukeRupert/lukaut/internal/testutil/fakedb"
+	"github.com/google/uuid"
+)
+
+// search mirrors the SearchViolationsByUserID WHERE clause for the owner,
+// text, severity and status filters, ordered by ID so pages are stable.
+func (f *fakeViolationsDB) search(args []driver.NamedValue) []*fakeViolationRow {
+	owner := args[0].Value.(string)
+	text := strings.ToLower(args[1].Value.(string))
+	severity, status := args[2].Value.(string), args[3].Value.(string)
+
+	var matched []*fakeViolationRow
+	for _, row := range f.violations {
+		if row.ownerID.String() != owner {
+			continue
+		}
+		if text != "" && !strings.Contains("missing guardrail", text) && !strings.Contains(strings.ToLower(row.notes), text) {
+			continue
+		}
+		if (severity != "" && row.severity != severity) || (status != "" && row.status != status) {
+			continue
+		}
+		matched = append(matched, row)
+	}
+	slices.SortFunc(matched, func(a, b *fakeViolationRow) int { return strings.Compare(a.id.String(), b.id.String()) })
+	return matched
+}
+
+func newSearchTestService(rows ...*fakeViolationRow) ViolationService {
+	f := &fakeViolationsDB{violations: map[uuid.UUID]*fakeViolationRow{}}
+	for _, row := range rows {
+		f.violations[row.id] = row
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	queries := repository.New(fakedb.Open(f))
+	return NewViolationService(queries, repository.NewUnitOfWork(queries), nil, logger)
+}
+
+// =============================================================================
+// Violation Search Tests
+// =============================================================================
+
+func TestViolationSearch_OnlyOwnViolations(t *testing.T) {
+	owner, other := uuid.New(), uuid.New()
+	mine := &fakeViolationRow{id: uuid.New(), inspectionID: uuid.New(), ownerID: owner, status: string(domain.ViolationStatusPending), notes: "North scaffold"}
+	theirs := &fakeViolationRow{id: uuid.New(), inspectionID: uuid.New(), ownerID: other, status: string(domain.ViolationStatusPending), notes: "North scaffold"}
+	svc := newSearchTestService(mine, theirs)
+
+	result, err := svc.Search(context.Background(), domain.SearchViolationsParams{UserID: owner, Query: "  scaffold ", Limit: 20})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if result.Total != 1 || len(result.Results) != 1 || result.Results[0].Violation.ID != mine.id {
+		t.Fatalf("expected only the user's own violation, got %+v", result)
+	}
+	if got := result.Results[0].InspectionTitle; got != "Roof inspection" {
+		t.Errorf("expected the inspection title, got %q", got)
+	}
+	want := "/inspections/" + mine.inspectionID.String() + "/review/queue?violation=" + mine.id.String()
+	if got := result.Results[0].ReviewURL(); got != want {
+		t.Errorf("expected review URL %q, got %q", want, got)
+	}
+}
+
+func TestViolationSearch_FiltersAndPages(t *testing.T) {
+	owner := uuid.New()
+	var rows []*fakeViolationRow
+	for i := 0; i < 5; i++ {
+		rows = append(rows, &fakeViolationRow{id: uuid.New(), inspectionID: uuid.New(), ownerID: owner, status: string(domain.ViolationStatusConfirmed), severity: string(domain.ViolationSeveritySerious)})
+	}
+	rows = append(rows, &fakeViolationRow{id: uuid.New(), inspectionID: uuid.New(), ownerID: owner, status: string(domain.ViolationStatusRejected), severity: string(domain.ViolationSeveritySerious)})
+	svc := newSearchTestService(rows...)
+
+	result, err := svc.Search(context.Background(), domain.SearchViolationsParams{
+		UserID:   owner,
+		Severity: domain.ViolationSeveritySerious,
+		Status:   domain.ViolationStatusConfirmed,
+		Limit:    2,
+		Offset:   4,
+	})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if result.Total != 5 || len(result.Results) != 1 {
+		t.Fatalf("expected the last of 5 confirmed violations, got %d of %d", len(result.Results), result.Total)
+	}
+	if result.CurrentPage() != 3 || result.TotalPages() != 3 || result.HasMore() || !result.HasPrevious() {
+		t.Errorf("unexpected paging: page %d of %d", result.CurrentPage(), result.TotalPages())
+	}
+}
+
+func TestViolationSearch_InvalidFilters(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, -1)
+	tests := []struct {
+		name   string
+		params domain.SearchViolationsParams
+	}{
+		{name: "unknown severity", params: domain.SearchViolationsParams{Severity: "urgent"}},
+		{name: "unknown status", params: domain.SearchViolationsParams{Status: "done"}},
+		{name: "query too long", params: domain.SearchViolationsParams{Query: strings.Repeat("a", domain.MaxViolationSearchLength+1)}},
+		{name: "dates reversed", params: domain.SearchViolationsParams{From: &from, To: &to}},
+	}
+
+	svc := newSearchTestService()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.params.UserID = uuid.New()
+			tt.params.Limit = 20
+			if _, err := svc.Search(context.Background(), tt.params); domain.ErrorCode(err) != domain.EINVALID {
+				t.Errorf("expected EINVALID, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/google/uuid"
+)
+
+func TestViolationUpdateSeverity_RetainsAISeverity(t *testing.T) {
+	row := &fakeViolationRow{id: uuid.New(), inspectionID: uuid.New(), ownerID: uuid.New(), severity: "serious", aiSeverity: "serious"}
+	svc := newNotesTestService(row)
+
+	if err := svc.UpdateSeverity(context.Background(), row.id, row.ownerID, domain.ViolationSeverityCritical); err != nil {
+		t.Fatalf("UpdateSeverity failed: %v", err)
+	}
+
+	v, err := svc.GetByID(context.Background(), row.id, row.ownerID)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if v.Severity != domain.ViolationSeverityCritical {
+		t.Errorf("expected severity critical, got %q", v.Severity)
+	}
+	if v.AISeverity != domain.ViolationSeveritySerious {
+		t.Errorf("expected AI severity to stay serious, got %q", v.AISeverity)
+	}
+	if !v.SeverityOverridden() {
+		t.Error("expected violation to report an overridden severity")
+	}
+}
+
+func TestViolationUpdateSeverity_InvalidValue(t *testing.T) {
+	row := &fakeViolationRow{id: uuid.New(), inspectionID: uuid.New(), ownerID: uuid.New(), severity: "serious", aiSeverity: "serious"}
+	svc := newNotesTestService(row)
+
+	err := svc.UpdateSeverity(context.Background(), row.id, row.ownerID, "catastrophic")
+
+	if domain.ErrorCode(err) != domain.EINVALID {
+		t.Errorf("expected EINVALID, got %v", err)
+	}
+	if row.severity != "serious" {
+		t.Errorf("expected severity to be unchanged, got %q", row.severity)
+	}
+}
+
+func TestViolationUpdateSeverity_OtherUsersViolation(t *testing.T) {
+	row := &fakeViolationRow{id: uuid.New(), inspectionID: uuid.New(), ownerID: uuid.New(), severity: "serious"}
+	svc := newNotesTestService(row)
+
+	err := svc.UpdateSeverity(context.Background(), row.id, uuid.New(), domain.ViolationSeverityOther)
+
+	if domain.ErrorCode(err) != domain.ENOTFOUND {
+		t.Errorf("expected ENOTFOUND, got %v", err)
+	}
+	if row.severity != "serious" {
+		t.Errorf("expected severity to be unchanged, got %q", row.severity)
+	}
+}
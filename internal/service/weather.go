@@ -0,0 +1,122 @@
+// Package service contains the business logic layer.
+//
+// This file implements weather suggestions: looking up the weather at an
+// inspection's site on its inspection date, to pre-fill the weather fields.
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/DukeRupert/lukaut/internal/weather"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// Interface Definition
+// =============================================================================
+
+// WeatherService suggests weather conditions for inspections.
+type WeatherService interface {
+	// Suggest returns the weather at the inspection's site on its inspection
+	// date. It returns nil without an error when no weather is available.
+	// Returns domain.ENOTFOUND if the inspection doesn't exist or doesn't
+	// belong to the user.
+	Suggest(ctx context.Context, inspectionID, userID uuid.UUID) (*domain.WeatherSuggestion, error)
+
+	// FillEmpty stores the suggested weather in whichever of the inspection's
+	// weather fields are empty. It reports whether a field was filled; values
+	// the inspector entered are never replaced.
+	FillEmpty(ctx context.Context, inspectionID, userID uuid.UUID) (bool, error)
+}
+
+// =============================================================================
+// Implementation
+// =============================================================================
+
+type weatherService struct {
+	queries  *repository.Queries
+	provider weather.Provider
+	logger   *slog.Logger
+}
+
+// NewWeatherService creates a new WeatherService. With weather.Noop every
+// suggestion is empty.
+func NewWeatherService(queries *repository.Queries, provider weather.Provider, logger *slog.Logger) WeatherService {
+	return &weatherService{
+		queries:  queries,
+		provider: provider,
+		logger:   logger,
+	}
+}
+
+// Suggest looks up the weather for an inspection.
+func (s *weatherService) Suggest(ctx context.Context, inspectionID, userID uuid.UUID) (*domain.WeatherSuggestion, error) {
+	const op = "weather.suggest"
+
+	row, err := s.queries.GetInspectionByIDAndUserID(ctx, repository.GetInspectionByIDAndUserIDParams{
+		ID:     inspectionID,
+		UserID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.NotFound(op, "inspection", inspectionID.String())
+		}
+		return nil, domain.Internal(err, op, "failed to get inspection")
+	}
+
+	loc := weather.Location{
+		Latitude:   nullFloat64ToPtr(row.Latitude),
+		Longitude:  nullFloat64ToPtr(row.Longitude),
+		PostalCode: row.PostalCode,
+	}
+	if loc.IsZero() {
+		return nil, nil
+	}
+
+	conditions, err := s.provider.Lookup(ctx, loc, row.InspectionDate)
+	if err != nil {
+		if errors.Is(err, weather.ErrNoData) {
+			return nil, nil
+		}
+		return nil, domain.Internal(err, op, "failed to look up weather")
+	}
+
+	return &domain.WeatherSuggestion{
+		Conditions:  conditions.Summary,
+		Temperature: conditions.Temperature(),
+	}, nil
+}
+
+// FillEmpty fills in the inspection's empty weather fields.
+func (s *weatherService) FillEmpty(ctx context.Context, inspectionID, userID uuid.UUID) (bool, error) {
+	const op = "weather.fill_empty"
+
+	suggestion, err := s.Suggest(ctx, inspectionID, userID)
+	if err != nil || suggestion == nil {
+		return false, err
+	}
+
+	filled, err := s.queries.UpdateInspectionWeatherIfEmptyByIDAndUserID(ctx, repository.UpdateInspectionWeatherIfEmptyByIDAndUserIDParams{
+		ID:                inspectionID,
+		UserID:            userID,
+		WeatherConditions: domain.ToNullString(suggestion.Conditions),
+		Temperature:       domain.ToNullString(suggestion.Temperature),
+	})
+	if err != nil {
+		return false, domain.Internal(err, op, "failed to store weather")
+	}
+
+	if filled > 0 {
+		s.logger.InfoContext(ctx, "inspection weather filled",
+			"inspection_id", inspectionID,
+			"conditions", suggestion.Conditions,
+			"temperature", suggestion.Temperature,
+		)
+	}
+	return filled > 0, nil
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/DukeRupert/lukaut/internal/testutil/fakedb"
+	"github.com/DukeRupert/lukaut/internal/weather"
+	"github.com/google/uuid"
+)
+
+// fakeWeatherProvider returns fixed conditions and records the locations it
+// was asked about.
+type fakeWeatherProvider struct {
+	conditions weather.Conditions
+	err        error
+	lookups    []weather.Location
+}
+
+func (p *fakeWeatherProvider) Lookup(ctx context.Context, loc weather.Location, date time.Time) (weather.Conditions, error) {
+	p.lookups = append(p.lookups, loc)
+	return p.conditions, p.err
+}
+
+// fakeWeatherEnqueuer records weather auto-fill jobs instead of queueing them.
+type fakeWeatherEnqueuer struct {
+	inspections []uuid.UUID
+}
+
+func (e *fakeWeatherEnqueuer) EnqueueFillInspectionWeather(ctx context.Context, inspectionID, userID uuid.UUID) (repository.Job, error) {
+	e.inspections = append(e.inspections, inspectionID)
+	return repository.Job{ID: uuid.New()}, nil
+}
+
+func newWeatherTestDB(row *fakeInspectionRow) *fakeInspectionsDB {
+	return &fakeInspectionsDB{inspections: map[uuid.UUID]*fakeInspectionRow{row.id: row}}
+}
+
+func newWeatherTestService(f *fakeInspectionsDB, provider weather.Provider) WeatherService {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewWeatherService(repository.New(fakedb.Open(f)), provider, logger)
+}
+
+// =============================================================================
+// Weather Service Tests
+// =============================================================================
+
+func TestWeatherSuggest_PrefersCoordinates(t *testing.T) {
+	lat, lng := 43.615, -116.2023
+	row := &fakeInspectionRow{id: uuid.New(), userID: uuid.New(), postal: "83702", latitude: &lat, longitude: &lng}
+	provider := &fakeWeatherProvider{conditions: weather.Conditions{Summary: "Clear", TemperatureF: 54.4}}
+
+	got, err := newWeatherTestService(newWeatherTestDB(row), provider).Suggest(context.Background(), row.id, row.userID)
+	if err != nil {
+		t.Fatalf("Suggest failed: %v", err)
+	}
+
+	if got == nil || got.Conditions != "Clear" || got.Temperature != "54°F" {
+		t.Errorf("unexpected suggestion: %+v", got)
+	}
+	if len(provider.lookups) != 1 || provider.lookups[0].Latitude == nil || *provider.lookups[0].Latitude != lat {
+		t.Errorf("expected a lookup by coordinates, got %+v", provider.lookups)
+	}
+}
+
+func TestWeatherSuggest_NoData(t *testing.T) {
+	row := &fakeInspectionRow{id: uuid.New(), userID: uuid.New(), postal: "83702"}
+
+	got, err := newWeatherTestService(newWeatherTestDB(row), weather.Noop{}).Suggest(context.Background(), row.id, row.userID)
+
+	if err != nil || got != nil {
+		t.Errorf("expected no suggestion and no error, got %+v, %v", got, err)
+	}
+}
+
+func TestWeatherSuggest_UnknownInspection(t *testing.T) {
+	row := &fakeInspectionRow{id: uuid.New(), userID: uuid.New(), postal: "83702"}
+
+	_, err := newWeatherTestService(newWeatherTestDB(row), &fakeWeatherProvider{}).Suggest(context.Background(), uuid.New(), row.userID)
+
+	if domain.ErrorCode(err) != domain.ENOTFOUND {
+		t.Errorf("expected ENOTFOUND, got %v", err)
+	}
+}
+
+func TestWeatherFillEmpty_KeepsEnteredValues(t *testing.T) {
+	row := &fakeInspectionRow{id: uuid.New(), userID: uuid.New(), postal: "83702", weather: "Windy"}
+	provider := &fakeWeatherProvider{conditions: weather.Conditions{Summary: "Clear", TemperatureF: 68}}
+
+	filled, err := newWeatherTestService(newWeatherTestDB(row), provider).FillEmpty(context.Background(), row.id, row.userID)
+	if err != nil {
+		t.Fatalf("FillEmpty failed: %v", err)
+	}
+
+	if !filled || row.weather != "Windy" || row.temperature != "68°F" {
+		t.Errorf("expected only the temperature to be filled, got filled=%v weather=%q temperature=%q", filled, row.weather, row.temperature)
+	}
+}
+
+func TestWeatherFillEmpty_ProviderFailure(t *testing.T) {
+	row := &fakeInspectionRow{id: uuid.New(), userID: uuid.New(), postal: "83702"}
+	provider := &fakeWeatherProvider{err: errors.New("visual crossing: unexpected status 503")}
+
+	filled, err := newWeatherTestService(newWeatherTestDB(row), provider).FillEmpty(context.Background(), row.id, row.userID)
+
+	if err == nil || filled {
+		t.Errorf("expected the provider failure to be returned, got filled=%v err=%v", filled, err)
+	}
+	if row.weather != "" || row.temperature != "" {
+		t.Errorf("expected weather to stay empty, got %q %q", row.weather, row.temperature)
+	}
+}
+
+func TestInspectionCreate_EnqueuesWeatherFillOnlyWhenEmpty(t *testing.T) {
+	f := &fakeInspectionsDB{inspections: map[uuid.UUID]*fakeInspectionRow{}}
+	enqueuer := &fakeWeatherEnqueuer{}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	svc := NewInspectionService(repository.New(fakedb.Open(f)), nil, nil, nil, nil, enqueuer, logger)
+
+	empty, err := svc.Create(context.Background(), geocodeTestCreateParams(uuid.New()))
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	params := geocodeTestCreateParams(uuid.New())
+	params.WeatherConditions = "Sunny"
+	if _, err := svc.Create(context.Background(), params); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	if len(enqueuer.inspections) != 1 || enqueuer.inspections[0] != empty.ID {
+		t.Errorf("expected one weather job for the inspection without weather, got %v", enqueuer.inspections)
+	}
+}
@@ -0,0 +1,231 @@
+// Package service contains the business logic layer.
+//
+// This file implements outbound webhooks: endpoints users register to be
+// notified of events, and the fan-out of each event to those endpoints.
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/DukeRupert/lukaut/internal/webhook"
+	"github.com/DukeRupert/lukaut/internal/worker"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// Interface Definition
+// =============================================================================
+
+// WebhookService manages a user's outbound webhook endpoints and notifies
+// them of events.
+type WebhookService interface {
+	// ListEndpoints returns the user's endpoints, oldest first.
+	ListEndpoints(ctx context.Context, userID uuid.UUID) ([]domain.WebhookEndpoint, error)
+
+	// CreateEndpoint registers a URL with a new signing secret.
+	// Returns EINVALID for URLs that cannot receive webhooks or when the user
+	// already has domain.MaxWebhookEndpoints endpoints.
+	CreateEndpoint(ctx context.Context, userID uuid.UUID, rawURL string) (*domain.WebhookEndpoint, error)
+
+	// DeleteEndpoint removes an endpoint and its delivery history.
+	// Returns ENOTFOUND if the endpoint does not exist or belongs to another user.
+	DeleteEndpoint(ctx context.Context, id, userID uuid.UUID) error
+
+	// ListRecentDeliveries returns the user's latest deliveries, newest first.
+	ListRecentDeliveries(ctx context.Context, userID uuid.UUID, limit int32) ([]domain.WebhookDelivery, error)
+
+	// Notify records a delivery of the event to each of the user's endpoints
+	// and queues it to be sent. Users without endpoints are skipped.
+	Notify(ctx context.Context, userID uuid.UUID, event webhook.Event, data any) error
+}
+
+// =============================================================================
+// Implementation
+// =============================================================================
+
+type webhookService struct {
+	queries  *repository.Queries
+	enqueuer worker.JobEnqueuer
+	logger   *slog.Logger
+}
+
+// NewWebhookService creates a new WebhookService.
+func NewWebhookService(queries *repository.Queries, enqueuer worker.JobEnqueuer, logger *slog.Logger) WebhookService {
+	return &webhookService{
+		queries:  queries,
+		enqueuer: enqueuer,
+		logger:   logger,
+	}
+}
+
+// ListEndpoints returns the user's endpoints.
+func (s *webhookService) ListEndpoints(ctx context.Context, userID uuid.UUID) ([]domain.WebhookEndpoint, error) {
+	const op = "webhook.list_endpoints"
+
+	rows, err := s.queries.ListWebhookEndpointsByUserID(ctx, userID)
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to list webhook endpoints")
+	}
+
+	endpoints := make([]domain.WebhookEndpoint, 0, len(rows))
+	for _, row := range rows {
+		endpoints = append(endpoints, repoWebhookEndpointToDomain(row))
+	}
+	return endpoints, nil
+}
+
+// CreateEndpoint validates the URL and registers it with a new secret.
+func (s *webhookService) CreateEndpoint(ctx context.Context, userID uuid.UUID, rawURL string) (*domain.WebhookEndpoint, error) {
+	const op = "webhook.create_endpoint"
+
+	rawURL = strings.TrimSpace(rawURL)
+	if err := webhook.ValidateURL(rawURL); err != nil {
+		return nil, domain.Invalid(op, "Enter a public https:// URL that can receive webhooks.")
+	}
+
+	count, err := s.queries.CountWebhookEndpointsByUserID(ctx, userID)
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to count webhook endpoints")
+	}
+	if count >= domain.MaxWebhookEndpoints {
+		return nil, domain.Errorf(domain.EINVALID, op, "You can register up to %d webhook URLs. Remove one to add another.", domain.MaxWebhookEndpoints)
+	}
+
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to generate webhook secret")
+	}
+
+	row, err := s.queries.CreateWebhookEndpoint(ctx, repository.CreateWebhookEndpointParams{
+		UserID: userID,
+		Url:    rawURL,
+		Secret: secret,
+	})
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to create webhook endpoint")
+	}
+
+	s.logger.InfoContext(ctx, "webhook endpoint created", "user_id", userID, "endpoint_id", row.ID)
+
+	endpoint := repoWebhookEndpointToDomain(row)
+	return &endpoint, nil
+}
+
+// DeleteEndpoint removes an endpoint owned by the user.
+func (s *webhookService) DeleteEndpoint(ctx context.Context, id, userID uuid.UUID) error {
+	const op = "webhook.delete_endpoint"
+
+	affected, err := s.queries.DeleteWebhookEndpointByIDAndUserID(ctx, repository.DeleteWebhookEndpointByIDAndUserIDParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		return domain.Internal(err, op, "failed to delete webhook endpoint")
+	}
+	if affected == 0 {
+		return domain.NotFound(op, "webhook endpoint", id.String())
+	}
+
+	s.logger.InfoContext(ctx, "webhook endpoint deleted", "user_id", userID, "endpoint_id", id)
+	return nil
+}
+
+// ListRecentDeliveries returns the user's latest deliveries.
+func (s *webhookService) ListRecentDeliveries(ctx context.Context, userID uuid.UUID, limit int32) ([]domain.WebhookDelivery, error) {
+	const op = "webhook.list_recent_deliveries"
+
+	rows, err := s.queries.ListRecentWebhookDeliveriesByUserID(ctx, repository.ListRecentWebhookDeliveriesByUserIDParams{
+		UserID: userID,
+		Limit:  limit,
+	})
+	if err != nil {
+		return nil, domain.Internal(err, op, "failed to list webhook deliveries")
+	}
+
+	deliveries := make([]domain.WebhookDelivery, 0, len(rows))
+	for _, row := range rows {
+		delivery := domain.WebhookDelivery{
+			ID:           row.ID,
+			EndpointURL:  row.Url,
+			Event:        row.Event,
+			Status:       domain.WebhookDeliveryStatus(row.Status),
+			Attempts:     int(row.Attempts),
+			ErrorMessage: domain.NullStringValue(row.ErrorMessage),
+			DeliveredAt:  domain.NullTimeValue(row.DeliveredAt),
+			CreatedAt:    row.CreatedAt,
+		}
+		if row.ResponseStatus.Valid {
+			status := int(row.ResponseStatus.Int32)
+			delivery.ResponseStatus = &status
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, nil
+}
+
+// Notify records and queues one delivery per endpoint. A failure for one
+// endpoint does not stop the others; all failures are returned together.
+func (s *webhookService) Notify(ctx context.Context, userID uuid.UUID, event webhook.Event, data any) error {
+	const op = "webhook.notify"
+
+	endpoints, err := s.queries.ListWebhookEndpointsByUserID(ctx, userID)
+	if err != nil {
+		return domain.Internal(err, op, "failed to list webhook endpoints")
+	}
+
+	var errs []error
+	now := time.Now()
+	for _, endpoint := range endpoints {
+		if err := s.queueDelivery(ctx, endpoint.ID, event, data, now); err != nil {
+			s.logger.ErrorContext(ctx, "failed to queue webhook delivery",
+				"error", err,
+				"endpoint_id", endpoint.ID,
+				"event", event,
+			)
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return domain.Internal(errors.Join(errs...), op, "failed to queue webhook deliveries")
+	}
+	return nil
+}
+
+// queueDelivery stores the event for one endpoint and enqueues the job that
+// sends it. The delivery's ID doubles as the event ID in the payload.
+func (s *webhookService) queueDelivery(ctx context.Context, endpointID uuid.UUID, event webhook.Event, data any, now time.Time) error {
+	deliveryID := uuid.New()
+	body, err := webhook.NewPayload(deliveryID, event, data, now)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.queries.CreateWebhookDelivery(ctx, repository.CreateWebhookDeliveryParams{
+		ID:         deliveryID,
+		EndpointID: endpointID,
+		Event:      string(event),
+		Payload:    body,
+	}); err != nil {
+		return err
+	}
+
+	_, err = s.enqueuer.EnqueueDeliverWebhook(ctx, deliveryID)
+	return err
+}
+
+// repoWebhookEndpointToDomain converts a webhook endpoint row to its domain type.
+func repoWebhookEndpointToDomain(row repository.WebhookEndpoint) domain.WebhookEndpoint {
+	return domain.WebhookEndpoint{
+		ID:        row.ID,
+		UserID:    row.UserID,
+		URL:       row.Url,
+		Secret:    row.Secret,
+		CreatedAt: row.CreatedAt,
+	}
+}
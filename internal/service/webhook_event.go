@@ -0,0 +1,76 @@
+// Package service contains the business logic layer.
+//
+// This file implements the webhook event service that makes Stripe webhook
+// processing idempotent across delivery retries.
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+)
+
+// =============================================================================
+// Interface Definition
+// =============================================================================
+
+// WebhookEventService records which Stripe webhook events have been processed.
+//
+// An event is recorded only after it has been applied, so a failure or crash
+// while processing leaves it to Stripe's retry. Two deliveries of one event
+// arriving together may both be applied; event handlers set state rather
+// than increment it, so applying an event twice is harmless.
+type WebhookEventService interface {
+	// Processed reports whether the event has already been applied, meaning
+	// this delivery is a duplicate and must be skipped.
+	Processed(ctx context.Context, eventID string) (bool, error)
+
+	// MarkProcessed records that the event has been applied. Call it only
+	// after processing succeeds.
+	MarkProcessed(ctx context.Context, eventID, eventType string) error
+}
+
+// =============================================================================
+// Implementation
+// =============================================================================
+
+type webhookEventService struct {
+	queries *repository.Queries
+	logger  *slog.Logger
+}
+
+// NewWebhookEventService creates a new WebhookEventService.
+func NewWebhookEventService(queries *repository.Queries, logger *slog.Logger) WebhookEventService {
+	return &webhookEventService{
+		queries: queries,
+		logger:  logger,
+	}
+}
+
+// Processed reports whether the event has been recorded as applied.
+func (s *webhookEventService) Processed(ctx context.Context, eventID string) (bool, error) {
+	const op = "webhook_event.processed"
+
+	processed, err := s.queries.HasStripeWebhookEvent(ctx, eventID)
+	if err != nil {
+		return false, domain.Internal(err, op, "failed to check webhook event")
+	}
+
+	return processed, nil
+}
+
+// MarkProcessed records the event as applied.
+func (s *webhookEventService) MarkProcessed(ctx context.Context, eventID, eventType string) error {
+	const op = "webhook_event.mark_processed"
+
+	if err := s.queries.CreateStripeWebhookEvent(ctx, repository.CreateStripeWebhookEventParams{
+		EventID:   eventID,
+		EventType: eventType,
+	}); err != nil {
+		return domain.Internal(err, op, "failed to record webhook event")
+	}
+
+	return nil
+}
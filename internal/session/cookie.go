@@ -0,0 +1,74 @@
+// Package session owns the session cookie: its name, attributes, and how it
+// is set, cleared, and read. The handler and middleware packages both use it
+// so the cookie is configured in one place.
+package session
+
+import (
+	"net/http"
+	"time"
+)
+
+const (
+	// CookieName is the name of the cookie that stores the session token.
+	CookieName = "lukaut_session"
+
+	// cookiePath ensures the cookie is sent with all requests.
+	cookiePath = "/"
+)
+
+// Options configures the session cookie.
+type Options struct {
+	// Secure sets the Secure flag (true in production, HTTPS only).
+	Secure bool
+
+	// MaxAge is how long the browser keeps the cookie. Use
+	// service.SessionDuration so the cookie expires with the session in
+	// the database.
+	MaxAge time.Duration
+}
+
+// Set sets the session cookie on the response.
+//
+// Cookie Settings:
+// - HttpOnly: true - Prevents JavaScript access (XSS protection)
+// - Secure: opts.Secure - Set true in production (HTTPS only)
+// - SameSite: Lax - Prevents CSRF while allowing normal navigation
+// - Path: / - Cookie sent with all requests
+// - MaxAge: opts.MaxAge - Matches session duration
+func Set(w http.ResponseWriter, token string, opts Options) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     CookieName,
+		Value:    token,
+		Path:     cookiePath,
+		MaxAge:   int(opts.MaxAge / time.Second),
+		HttpOnly: true,
+		Secure:   opts.Secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// Clear removes the session cookie from the client.
+//
+// This is done by setting MaxAge to -1, which tells the browser to delete
+// the cookie immediately. opts.MaxAge is ignored.
+func Clear(w http.ResponseWriter, opts Options) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     CookieName,
+		Value:    "",
+		Path:     cookiePath,
+		MaxAge:   -1, // Delete immediately
+		HttpOnly: true,
+		Secure:   opts.Secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// Read returns the session token from the request's cookie. It reports
+// false if the cookie is missing or empty.
+func Read(r *http.Request) (string, bool) {
+	cookie, err := r.Cookie(CookieName)
+	if err != nil || cookie.Value == "" {
+		return "", false
+	}
+	return cookie.Value, true
+}
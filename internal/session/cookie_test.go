@@ -0,0 +1,62 @@
+package session
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSet(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Set(rec, "test-token", Options{Secure: true, MaxAge: 24 * time.Hour})
+
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("expected 1 cookie, got %d", len(cookies))
+	}
+	c := cookies[0]
+	if c.Name != CookieName || c.Value != "test-token" || c.Path != "/" {
+		t.Errorf("unexpected cookie %+v", c)
+	}
+	if !c.HttpOnly {
+		t.Error("expected HttpOnly flag to be set")
+	}
+	if !c.Secure {
+		t.Error("expected Secure flag to be set")
+	}
+	if c.SameSite != http.SameSiteLaxMode {
+		t.Errorf("expected SameSite=Lax, got %v", c.SameSite)
+	}
+	if c.MaxAge != 24*60*60 {
+		t.Errorf("expected MaxAge to match the session duration, got %d", c.MaxAge)
+	}
+}
+
+func TestClear(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Clear(rec, Options{MaxAge: time.Hour})
+
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != CookieName || cookies[0].MaxAge >= 0 || cookies[0].Value != "" {
+		t.Errorf("expected an expired session cookie, got %+v", cookies)
+	}
+}
+
+func TestRead(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if _, ok := Read(req); ok {
+		t.Error("expected no token without a cookie")
+	}
+
+	req.AddCookie(&http.Cookie{Name: CookieName, Value: ""})
+	if _, ok := Read(req); ok {
+		t.Error("expected no token for an empty cookie")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: CookieName, Value: "abc"})
+	if token, ok := Read(req); !ok || token != "abc" {
+		t.Errorf("expected token abc, got %q, %t", token, ok)
+	}
+}
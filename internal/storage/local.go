@@ -93,6 +93,9 @@ func (s *LocalStorage) Put(ctx context.Context, key string, data io.Reader, opts
 	}
 	defer func() { _ = file.Close() }()
 
+	// Stop copying once ctx is canceled; the partial file is removed below
+	data = &contextReader{ctx: ctx, r: data}
+
 	// Copy data to file with optional size limit
 	var written int64
 	if opts.MaxSize > 0 {
@@ -272,3 +275,17 @@ func (s *LocalStorage) resolvePath(key string) (string, error) {
 
 	return absPath, nil
 }
+
+// contextReader is an io.Reader that fails with ctx's error once ctx is
+// canceled, so a long copy stops between reads.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (cr *contextReader) Read(p []byte) (int, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return cr.r.Read(p)
+}
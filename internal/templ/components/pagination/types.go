@@ -1,6 +1,13 @@
 // Package pagination provides shared pagination components for list pages.
 package pagination
 
+import (
+	"fmt"
+	"strings"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+)
+
 // Data contains pagination information for display.
 type Data struct {
 	CurrentPage int
@@ -13,12 +20,43 @@ type Data struct {
 	NextPage    int
 }
 
+// PageURL returns the link to a page of the list at baseURL. A page size
+// other than the default is carried in the link so it survives paging.
+// baseURL may already have a query, such as the list's sort order.
+func (d Data) PageURL(baseURL string, page int) string {
+	if d.PerPage > 0 && d.PerPage != domain.DefaultPerPage {
+		return fmt.Sprintf("%s%spage=%d&per_page=%d", baseURL, querySep(baseURL), page, d.PerPage)
+	}
+	return fmt.Sprintf("%s%spage=%d", baseURL, querySep(baseURL), page)
+}
+
+// PerPageURL returns the link to the first page of the list at baseURL
+// showing perPage rows per page.
+func PerPageURL(baseURL string, perPage int) string {
+	return fmt.Sprintf("%s%spage=1&per_page=%d", baseURL, querySep(baseURL), perPage)
+}
+
+// querySep returns the separator for appending parameters to baseURL.
+func querySep(baseURL string) string {
+	if strings.Contains(baseURL, "?") {
+		return "&"
+	}
+	return "?"
+}
+
+// ShowPerPage reports whether the page size choice is worth offering: there
+// are more results than the smallest page size holds.
+func (d Data) ShowPerPage() bool {
+	return d.Total > domain.PerPageOptions[0]
+}
+
 // Config allows customization of pagination behavior.
 type Config struct {
 	BaseURL  string // e.g., "/inspections"
 	TargetID string // htmx target, e.g., "content-area"
 	UseHtmx  bool   // Enable htmx partial loading
 	PushURL  bool   // Update browser URL with hx-push-url
+	PerPage  bool   // Offer the page size choice; the handler must honor per_page
 }
 
 // PageRange returns a slice of page numbers for pagination display.
@@ -43,7 +43,20 @@ func AdminLayout(title string) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, " - Lukaut Admin</title><link rel=\"stylesheet\" href=\"/static/css/output.css\"></head><body class=\"h-full text-foreground\"><div class=\"min-h-full\"><!-- Admin Navigation --><nav class=\"bg-primary\"><div class=\"mx-auto max-w-7xl px-4 sm:px-6 lg:px-8\"><div class=\"flex h-14 items-center justify-between\"><div class=\"flex items-center\"><div class=\"flex-shrink-0\"><span class=\"text-primary-foreground font-semibold text-lg\">Lukaut Admin</span></div><div class=\"ml-10 flex items-baseline gap-1\"><a href=\"/admin\" class=\"text-primary-foreground/70 hover:bg-primary-foreground/10 hover:text-primary-foreground rounded-md px-3 py-2 text-sm font-medium transition-colors\">Dashboard</a> <a href=\"/admin/users\" class=\"text-primary-foreground/70 hover:bg-primary-foreground/10 hover:text-primary-foreground rounded-md px-3 py-2 text-sm font-medium transition-colors\">Users</a></div></div><div><a href=\"/dashboard\" class=\"text-primary-foreground/70 hover:bg-primary-foreground/10 hover:text-primary-foreground rounded-md px-3 py-2 text-sm font-medium transition-colors\">Back to App</a></div></div></div></nav><!-- Main content --><main><div class=\"mx-auto max-w-7xl py-6 px-4 sm:px-6 lg:px-8\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, " - Lukaut Admin</title><link rel=\"stylesheet\" href=\"/static/css/output.css\"><!-- Ask before submitting forms with data-confirm --><script nonce=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var3 string
+		templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs(templ.GetNonce(ctx))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/templ/pages/admin/layout.templ`, Line: 13, Col: 38}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var3))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "\">\n\t\t\t\tdocument.addEventListener('submit', function (e) {\n\t\t\t\t\tconst message = e.target.dataset.confirm;\n\t\t\t\t\tif (message && !confirm(message)) e.preventDefault();\n\t\t\t\t});\n\t\t\t</script></head><body class=\"h-full text-foreground\"><div class=\"min-h-full\"><!-- Admin Navigation --><nav class=\"bg-primary\"><div class=\"mx-auto max-w-7xl px-4 sm:px-6 lg:px-8\"><div class=\"flex h-14 items-center justify-between\"><div class=\"flex items-center\"><div class=\"flex-shrink-0\"><span class=\"text-primary-foreground font-semibold text-lg\">Lukaut Admin</span></div><div class=\"ml-10 flex items-baseline gap-1\"><a href=\"/admin\" class=\"text-primary-foreground/70 hover:bg-primary-foreground/10 hover:text-primary-foreground rounded-md px-3 py-2 text-sm font-medium transition-colors\">Dashboard</a> <a href=\"/admin/users\" class=\"text-primary-foreground/70 hover:bg-primary-foreground/10 hover:text-primary-foreground rounded-md px-3 py-2 text-sm font-medium transition-colors\">Users</a> <a href=\"/admin/jobs\" class=\"text-primary-foreground/70 hover:bg-primary-foreground/10 hover:text-primary-foreground rounded-md px-3 py-2 text-sm font-medium transition-colors\">Jobs</a> <a href=\"/admin/usage\" class=\"text-primary-foreground/70 hover:bg-primary-foreground/10 hover:text-primary-foreground rounded-md px-3 py-2 text-sm font-medium transition-colors\">AI Usage</a> <a href=\"/admin/regulations/import\" class=\"text-primary-foreground/70 hover:bg-primary-foreground/10 hover:text-primary-foreground rounded-md px-3 py-2 text-sm font-medium transition-colors\">Regulations</a></div></div><div><a href=\"/dashboard\" class=\"text-primary-foreground/70 hover:bg-primary-foreground/10 hover:text-primary-foreground rounded-md px-3 py-2 text-sm font-medium transition-colors\">Back to App</a></div></div></div></nav><!-- Main content --><main><div class=\"mx-auto max-w-7xl py-6 px-4 sm:px-6 lg:px-8\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -51,7 +64,7 @@ func AdminLayout(title string) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "</div></main></div></body></html>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, "</div></main></div></body></html>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
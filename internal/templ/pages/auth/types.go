@@ -70,3 +70,8 @@ type FormData struct {
 	Password    string
 	InviteCode  string
 }
+
+// LogoutPageData contains data for the logout confirmation page
+type LogoutPageData struct {
+	CSRFToken string
+}
@@ -10,9 +10,11 @@ import templruntime "github.com/a-h/templ/runtime"
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/DukeRupert/lukaut/internal/templ/layouts"
 	"github.com/DukeRupert/lukaut/internal/templ/partials"
+	"github.com/DukeRupert/lukaut/internal/templ/shared"
 )
 
 // ReviewQueuePage renders the keyboard-driven queue-based review page.
@@ -54,7 +56,7 @@ func ReviewQueuePage(data ReviewQueuePageData) templ.Component {
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = queueKeyboardScript(data.Inspection.ID).Render(ctx, templ_7745c5c3_Buffer)
+			templ_7745c5c3_Err = queueKeyboardScript(data.Inspection.ID, data.Shortcuts).Render(ctx, templ_7745c5c3_Buffer)
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -72,11 +74,12 @@ func ReviewQueuePage(data ReviewQueuePageData) templ.Component {
 					Confirmed: data.ViolationCounts.Confirmed,
 					Rejected:  data.ViolationCounts.Rejected,
 				},
+				FilterLabel: data.FilterLabel,
 			}).Render(ctx, templ_7745c5c3_Buffer)
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "<div id=\"queue-content\">")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "<div id=\"queue-undo\"></div><div id=\"queue-content\">")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -98,6 +101,7 @@ func ReviewQueuePage(data ReviewQueuePageData) templ.Component {
 					InspectionID:   data.Inspection.ID,
 					ConfirmedCount: data.ViolationCounts.Confirmed,
 					RejectedCount:  data.ViolationCounts.Rejected,
+					IsFiltered:     data.FilterLabel != "",
 				}).Render(ctx, templ_7745c5c3_Buffer)
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
@@ -115,17 +119,22 @@ func ReviewQueuePage(data ReviewQueuePageData) templ.Component {
 						AIDescription:  data.Violation.AIDescription,
 						Status:         data.Violation.Status,
 						Severity:       data.Violation.Severity,
+						AISeverity:     data.Violation.AISeverity,
 						Confidence:     data.Violation.Confidence,
 						InspectorNotes: data.Violation.InspectorNotes,
 						ThumbnailURL:   data.Violation.ThumbnailURL,
 						OriginalURL:    data.Violation.OriginalURL,
+						RegionURL:      data.Violation.RegionURL,
 						ImageID:        data.Violation.ImageID,
 						Regulations:    violationRegsToQueueRegs(data.Violation.Regulations),
+						Annotations:    violationAnnotationsToQueue(data.Violation.Annotations),
 					},
-					Position:   data.Position,
-					TotalCount: data.TotalCount,
-					HasPrev:    data.Position > 0,
-					HasNext:    data.Position < data.TotalCount-1,
+					Position:    data.Position,
+					TotalCount:  data.TotalCount,
+					HasPrev:     data.Position > 0,
+					HasNext:     data.Position < data.TotalCount-1,
+					FilterQuery: data.FilterQuery,
+					Keys:        reviewShortcutKeys(data.Shortcuts),
 				}).Render(ctx, templ_7745c5c3_Buffer)
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
@@ -143,6 +152,7 @@ func ReviewQueuePage(data ReviewQueuePageData) templ.Component {
 			User:        userToLayoutUser(data.User),
 			CSRFToken:   data.CSRFToken,
 			Flash:       data.Flash,
+			Shortcuts:   reviewHelpShortcuts(data.Shortcuts),
 		}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var2), templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
@@ -152,8 +162,9 @@ func ReviewQueuePage(data ReviewQueuePageData) templ.Component {
 }
 
 // queueKeyboardScript provides keyboard shortcuts using pure JavaScript.
-// It triggers htmx buttons programmatically.
-func queueKeyboardScript(inspectionID string) templ.Component {
+// It triggers htmx buttons programmatically. The action keys come from the
+// user's shortcut map; the arrow keys and Escape are fixed.
+func queueKeyboardScript(inspectionID string, shortcuts []ReviewShortcutDisplay) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
@@ -174,20 +185,46 @@ func queueKeyboardScript(inspectionID string) templ.Component {
 			templ_7745c5c3_Var3 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 8, "<script data-inspection-id=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 8, "<script nonce=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var4 string
-		templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinStringErrs(inspectionID)
+		templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinStringErrs(templ.GetNonce(ctx))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/templ/pages/inspections/review_queue.templ`, Line: 78, Col: 42}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/templ/pages/inspections/review_queue.templ`, Line: 91, Col: 36}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var4))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 9, "\">\n\t\t(function() {\n\t\t\tconst script = document.currentScript;\n\t\t\tconst inspectionId = script.dataset.inspectionId;\n\n\t\t\tdocument.addEventListener('keydown', function(e) {\n\t\t\t\t// Don't handle if in input field\n\t\t\t\tif (['INPUT', 'TEXTAREA', 'SELECT'].includes(e.target.tagName)) {\n\t\t\t\t\treturn;\n\t\t\t\t}\n\n\t\t\t\t// Don't handle with modifier keys\n\t\t\t\tif (e.metaKey || e.ctrlKey || e.altKey) return;\n\n\t\t\t\tswitch (e.key.toLowerCase()) {\n\t\t\t\t\tcase 'a':\n\t\t\t\t\t\t// Accept - click the accept button\n\t\t\t\t\t\tconst acceptBtn = document.getElementById('btn-accept');\n\t\t\t\t\t\tif (acceptBtn && !acceptBtn.disabled) {\n\t\t\t\t\t\t\tacceptBtn.click();\n\t\t\t\t\t\t\te.preventDefault();\n\t\t\t\t\t\t}\n\t\t\t\t\t\tbreak;\n\t\t\t\t\tcase 'r':\n\t\t\t\t\t\t// Reject - click the reject button\n\t\t\t\t\t\tconst rejectBtn = document.getElementById('btn-reject');\n\t\t\t\t\t\tif (rejectBtn && !rejectBtn.disabled) {\n\t\t\t\t\t\t\trejectBtn.click();\n\t\t\t\t\t\t\te.preventDefault();\n\t\t\t\t\t\t}\n\t\t\t\t\t\tbreak;\n\t\t\t\t\tcase 'e':\n\t\t\t\t\t\t// Edit - click the edit button\n\t\t\t\t\t\tconst editBtn = document.getElementById('btn-edit');\n\t\t\t\t\t\tif (editBtn) {\n\t\t\t\t\t\t\teditBtn.click();\n\t\t\t\t\t\t\te.preventDefault();\n\t\t\t\t\t\t}\n\t\t\t\t\t\tbreak;\n\t\t\t\t\tcase 'j':\n\t\t\t\t\tcase 'arrowright':\n\t\t\t\t\t\t// Next - click the next button\n\t\t\t\t\t\tconst nextBtn = document.getElementById('btn-next');\n\t\t\t\t\t\tif (nextBtn && !nextBtn.disabled) {\n\t\t\t\t\t\t\tnextBtn.click();\n\t\t\t\t\t\t\te.preventDefault();\n\t\t\t\t\t\t}\n\t\t\t\t\t\tbreak;\n\t\t\t\t\tcase 'k':\n\t\t\t\t\tcase 'arrowleft':\n\t\t\t\t\t\t// Previous - click the prev button\n\t\t\t\t\t\tconst prevBtn = document.getElementById('btn-prev');\n\t\t\t\t\t\tif (prevBtn && !prevBtn.disabled) {\n\t\t\t\t\t\t\tprevBtn.click();\n\t\t\t\t\t\t\te.preventDefault();\n\t\t\t\t\t\t}\n\t\t\t\t\t\tbreak;\n\t\t\t\t\tcase 'escape':\n\t\t\t\t\t\t// Exit to inspection page\n\t\t\t\t\t\twindow.location.href = '/inspections/' + inspectionId;\n\t\t\t\t\t\te.preventDefault();\n\t\t\t\t\t\tbreak;\n\t\t\t\t}\n\t\t\t});\n\t\t})();\n\t</script>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 9, "\" data-inspection-id=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var5 string
+		templ_7745c5c3_Var5, templ_7745c5c3_Err = templ.JoinStringErrs(inspectionID)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/templ/pages/inspections/review_queue.templ`, Line: 91, Col: 72}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var5))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 10, "\" data-shortcuts=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var6 string
+		templ_7745c5c3_Var6, templ_7745c5c3_Err = templ.JoinStringErrs(templ.JSONString(reviewKeyMap(shortcuts)))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/templ/pages/inspections/review_queue.templ`, Line: 91, Col: 133}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var6))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 11, "\">\n\t\t(function() {\n\t\t\tconst script = document.currentScript;\n\t\t\tconst inspectionId = script.dataset.inspectionId;\n\n\t\t\t// Map each key to the button of the action bound to it\n\t\t\tconst buttons = {\n\t\t\t\tconfirm: 'btn-accept',\n\t\t\t\treject: 'btn-reject',\n\t\t\t\tedit: 'btn-edit',\n\t\t\t\tundo: 'btn-undo',\n\t\t\t\tnext: 'btn-next',\n\t\t\t\tprev: 'btn-prev'\n\t\t\t};\n\t\t\tconst keyButtons = { arrowright: 'btn-next', arrowleft: 'btn-prev' };\n\t\t\tconst shortcuts = JSON.parse(script.dataset.shortcuts || '{}');\n\t\t\tfor (const action in shortcuts) {\n\t\t\t\tif (buttons[action]) {\n\t\t\t\t\tkeyButtons[shortcuts[action]] = buttons[action];\n\t\t\t\t}\n\t\t\t}\n\n\t\t\tdocument.addEventListener('keydown', function(e) {\n\t\t\t\t// Don't handle if in input field\n\t\t\t\tif (['INPUT', 'TEXTAREA', 'SELECT'].includes(e.target.tagName)) {\n\t\t\t\t\treturn;\n\t\t\t\t}\n\n\t\t\t\t// Don't handle with modifier keys\n\t\t\t\tif (e.metaKey || e.ctrlKey || e.altKey) return;\n\n\t\t\t\tconst key = e.key.toLowerCase();\n\t\t\t\tif (key === 'escape') {\n\t\t\t\t\t// Exit to inspection page\n\t\t\t\t\twindow.location.href = '/inspections/' + inspectionId;\n\t\t\t\t\te.preventDefault();\n\t\t\t\t\treturn;\n\t\t\t\t}\n\n\t\t\t\t// Click the action's button unless it is missing or disabled\n\t\t\t\tconst id = keyButtons[key];\n\t\t\t\tconst btn = id && document.getElementById(id);\n\t\t\t\tif (btn && !btn.disabled) {\n\t\t\t\t\tbtn.click();\n\t\t\t\t\te.preventDefault();\n\t\t\t\t}\n\t\t\t});\n\t\t})();\n\t</script>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -210,4 +247,47 @@ func violationRegsToQueueRegs(regs []ViolationRegulationDisplay) []partials.Queu
 	return result
 }
 
+func violationAnnotationsToQueue(annotations []ViolationAnnotationDisplay) []partials.QueueAnnotationDisplay {
+	result := make([]partials.QueueAnnotationDisplay, len(annotations))
+	for i, a := range annotations {
+		result[i] = partials.QueueAnnotationDisplay(a)
+	}
+	return result
+}
+
+// reviewKeyMap returns the action-to-key map the keyboard script reads.
+func reviewKeyMap(shortcuts []ReviewShortcutDisplay) map[string]string {
+	keys := make(map[string]string, len(shortcuts))
+	for _, s := range shortcuts {
+		keys[s.Action] = s.Key
+	}
+	return keys
+}
+
+// reviewShortcutKeys returns the keys shown as hints on the queue buttons.
+func reviewShortcutKeys(shortcuts []ReviewShortcutDisplay) partials.QueueShortcutKeys {
+	keys := reviewKeyMap(shortcuts)
+	return partials.QueueShortcutKeys{
+		Confirm: keys["confirm"],
+		Reject:  keys["reject"],
+		Edit:    keys["edit"],
+		Undo:    keys["undo"],
+		Next:    keys["next"],
+		Prev:    keys["prev"],
+	}
+}
+
+// reviewHelpShortcuts lists the review keys in the help overlay, followed
+// by the fixed keys.
+func reviewHelpShortcuts(shortcuts []ReviewShortcutDisplay) []shared.Shortcut {
+	help := make([]shared.Shortcut, 0, len(shortcuts)+2)
+	for _, s := range shortcuts {
+		help = append(help, shared.Shortcut{Key: strings.ToUpper(s.Key), Description: s.Label})
+	}
+	return append(help,
+		shared.Shortcut{Key: "← →", Description: "Previous / next violation"},
+		shared.Shortcut{Key: "Esc", Description: "Exit the review queue"},
+	)
+}
+
 var _ = templruntime.GeneratedTemplate
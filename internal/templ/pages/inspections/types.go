@@ -2,7 +2,11 @@
 package inspections
 
 import (
+	"net/url"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
 	"github.com/DukeRupert/lukaut/internal/templ/components/pagination"
+	"github.com/DukeRupert/lukaut/internal/templ/partials"
 	"github.com/DukeRupert/lukaut/internal/templ/shared"
 )
 
@@ -17,7 +21,11 @@ type ListPageData struct {
 	User        *UserDisplay
 	Inspections []InspectionListItem
 	Pagination  pagination.Data
+	Sort        SortState
 	Flash       *shared.Flash
+
+	InOrganization bool // User belongs to an organization, so can list its inspections
+	Organization   bool // Listing the organization's inspections rather than the user's
 }
 
 // FormPageData contains data for the inspection create/edit form.
@@ -31,6 +39,21 @@ type FormPageData struct {
 	Errors      map[string]string
 	Flash       *shared.Flash
 	IsEdit      bool
+
+	// Latest holds the saved values when the edit conflicted with someone
+	// else's save; nil otherwise. Form keeps the user's attempted values.
+	Latest *InspectionFormValues
+}
+
+// clientName returns the name of the client with the given ID, or "" if the
+// ID is empty or unknown.
+func (d FormPageData) clientName(id string) string {
+	for _, c := range d.Clients {
+		if c.ID == id {
+			return c.Name
+		}
+	}
+	return ""
 }
 
 // ShowPageData contains data for the inspection detail page.
@@ -40,6 +63,8 @@ type ShowPageData struct {
 	User              *UserDisplay
 	Inspection        *InspectionDisplay
 	InspectionID      string
+	Assignee          AssigneeFieldData
+	Template          TemplateFieldData
 	CanUpload         bool
 	IsAnalyzing       bool
 	GalleryData       ImageGalleryData
@@ -47,8 +72,13 @@ type ShowPageData struct {
 	Violations        []ViolationDisplay
 	ViolationCounts   ViolationCountsData
 	Reports           []ReportDisplay
-	ClientEmail       string // Pre-populated client email for report delivery
-	CanGenerateReport bool   // True if inspection has confirmed violations
+	ClientEmail       string                     // Pre-populated client email for report delivery
+	CanGenerateReport bool                       // True if inspection has confirmed violations
+	CanShare          bool                       // True if inspection is completed and can be shared
+	CanAddViolation   bool                       // True while the inspection is editable
+	ManualViolation   ManualViolationFormData    // Form adding a violation by hand
+	QuotaWarning      *partials.QuotaWarningData // Non-nil when analysis quota is running low
+	UploadLimits      UploadLimitsData           // Photo sizes and types the upload control accepts
 	Flash             *shared.Flash
 }
 
@@ -74,10 +104,20 @@ type ReviewQueuePageData struct {
 	Position        int               // Current position (0-indexed)
 	TotalCount      int               // Total number of violations
 	ViolationCounts ViolationCountsData
-	IsComplete      bool // True if all violations have been reviewed
+	IsComplete      bool                    // True if all violations have been reviewed
+	FilterQuery     string                  // Encoded filter params appended to queue URLs (e.g. "&severity=critical")
+	FilterLabel     string                  // Human-readable description of the active filter (empty if none)
+	Shortcuts       []ReviewShortcutDisplay // The user's review keys, in help overlay order
 	Flash           *shared.Flash
 }
 
+// ReviewShortcutDisplay is a review queue action and the key bound to it.
+type ReviewShortcutDisplay struct {
+	Action string // Review action, e.g. "confirm"
+	Key    string // Single lowercase key, e.g. "a"
+	Label  string // Description for the help overlay
+}
+
 // =============================================================================
 // Display Types
 // =============================================================================
@@ -99,6 +139,57 @@ type InspectionListItem struct {
 	InspectionDate string
 	Status         string
 	ViolationCount int
+	OwnerName      string // Inspector who owns the inspection, set in organization lists
+	AssigneeName   string // Member the inspection is assigned to, set in organization lists
+}
+
+// SortState is the order of the inspections table. It marks the sorted
+// column and builds sort and page links that keep the order.
+type SortState struct {
+	BaseURL string // List URL without a query, e.g. "/inspections"
+	Column  string // Sorted column, one of the domain.InspectionSort values
+	Desc    bool   // Sorted in descending order
+}
+
+// PageBaseURL returns the list URL carrying the order, for pagination links.
+// The default order is left out to keep URLs short.
+func (s SortState) PageBaseURL() string {
+	column := domain.InspectionSort(s.Column)
+	if s.Column == "" || (column == domain.DefaultInspectionSort && s.Desc == column.DefaultDescending()) {
+		return s.BaseURL
+	}
+	return s.BaseURL + "?" + sortQuery(s.Column, s.Desc)
+}
+
+// SortURL returns the link a column header sorts by: the sorted column flips
+// direction, other columns start in their default direction.
+func (s SortState) SortURL(column string) string {
+	desc := domain.InspectionSort(column).DefaultDescending()
+	if column == s.Column {
+		desc = !s.Desc
+	}
+	return s.BaseURL + "?" + sortQuery(column, desc)
+}
+
+// AriaSort returns the aria-sort value for column's header.
+func (s SortState) AriaSort(column string) string {
+	switch {
+	case column != s.Column:
+		return "none"
+	case s.Desc:
+		return "descending"
+	default:
+		return "ascending"
+	}
+}
+
+// sortQuery encodes the sort and dir query parameters.
+func sortQuery(column string, desc bool) string {
+	dir := "asc"
+	if desc {
+		dir = "desc"
+	}
+	return url.Values{"sort": {column}, "dir": {dir}}.Encode()
 }
 
 // InspectionDisplay represents full inspection details.
@@ -117,10 +208,81 @@ type InspectionDisplay struct {
 	WeatherConditions string
 	Temperature       string
 	InspectorNotes    string
+	Latitude          *float64 // Set once the address has been geocoded
+	Longitude         *float64
 	CreatedAt         string
 	UpdatedAt         string
 }
 
+// AssigneeFieldData contains data for the inspection's "Assigned To" field.
+type AssigneeFieldData struct {
+	InspectionID string
+	AssigneeID   string           // Assigned member's user ID; empty if unassigned
+	AssigneeName string           // Assigned member's name; empty if unassigned
+	Members      []AssigneeOption // Members it can be assigned to; empty outside an organization
+	Error        string           // Why the last assignment failed
+}
+
+// Visible reports whether the field has anything to show: a choice of
+// members, or an assignee to name.
+func (d AssigneeFieldData) Visible() bool {
+	return len(d.Members) > 0 || d.AssigneeName != ""
+}
+
+// AssigneeOption is an organization member an inspection can be assigned to.
+type AssigneeOption struct {
+	ID   string
+	Name string
+}
+
+// TemplateFieldData contains data for the "Save as template" section of
+// an inspection.
+type TemplateFieldData struct {
+	InspectionID string
+	CanEdit      bool   // Only the owner can keep an inspection as a template
+	IsTemplate   bool   // The inspection is kept as a template
+	Name         string // Template name, or the suggested name when not a template
+	Error        string // Why the last change failed
+}
+
+// ManualViolationFormData contains data for the form adding a violation
+// the analysis missed.
+type ManualViolationFormData struct {
+	InspectionID string
+	Regulations  []RegulationOption // The user's bookmarked regulations, offered for linking
+}
+
+// RegulationOption is a regulation that can be linked to a new violation.
+type RegulationOption struct {
+	ID             string
+	StandardNumber string
+	Title          string
+}
+
+// TemplatesPageData contains data for the inspection templates page.
+type TemplatesPageData struct {
+	CurrentPath string
+	CSRFToken   string
+	User        *UserDisplay
+	Templates   []TemplateListItem
+	Flash       *shared.Flash
+}
+
+// TemplateListItem is a template on the templates page.
+type TemplateListItem struct {
+	ID         string
+	Name       string
+	Title      string
+	ClientName string
+	Address    string // Single-line address
+	UpdatedAt  string
+}
+
+// HasLocation reports whether the inspection's address has been geocoded.
+func (i *InspectionDisplay) HasLocation() bool {
+	return i != nil && i.Latitude != nil && i.Longitude != nil
+}
+
 // FullAddress returns the formatted full address for display.
 func (i *InspectionDisplay) FullAddress() string {
 	if i == nil {
@@ -147,6 +309,7 @@ type InspectionFormValues struct {
 	WeatherConditions string
 	Temperature       string
 	InspectorNotes    string
+	Version           string // Edit form only: version the edit is based on
 }
 
 // ClientOption represents a client for dropdown selection.
@@ -168,12 +331,23 @@ type ImageGalleryData struct {
 	IsAnalyzing  bool
 }
 
+// UploadLimitsData describes the photos the upload control accepts, so the
+// browser can refuse files before sending them.
+type UploadLimitsData struct {
+	Accept       string   // File input accept attribute
+	ContentTypes []string // Accepted MIME types
+	MaxSize      int64    // Largest accepted file, in bytes
+	MaxSizeLabel string   // e.g. "20 MB"
+	Description  string   // e.g. "JPEG, PNG, and HEIC up to 20 MB"
+}
+
 // ImageDisplay represents an image for display.
 type ImageDisplay struct {
 	ID               string
 	ThumbnailURL     string
 	OriginalFilename string
 	AnalysisStatus   string
+	AnalysisError    string
 	SizeMB           string
 }
 
@@ -191,6 +365,7 @@ type AnalysisStatusData struct {
 	PendingImages  int64
 	TotalImages    int64
 	AnalyzedImages int64
+	FailedImages   int64
 	ViolationCount int64
 	Message        string
 	PollingEnabled bool
@@ -207,12 +382,26 @@ type ViolationDisplay struct {
 	AIDescription  string
 	Status         string
 	Severity       string
+	AISeverity     string
 	Confidence     string
 	InspectorNotes string
 	ThumbnailURL   string
 	OriginalURL    string
+	RegionURL      string // Close-up of the AI-detected region, empty without one
 	ImageID        string
 	Regulations    []ViolationRegulationDisplay
+	Annotations    []ViolationAnnotationDisplay
+}
+
+// ViolationAnnotationDisplay represents a box drawn over a violation's photo.
+// Positions are percentages of the image size.
+type ViolationAnnotationDisplay struct {
+	Left   float64
+	Top    float64
+	Width  float64
+	Height float64
+	Label  string
+	Source string // "ai" or "manual"
 }
 
 // ViolationRegulationDisplay represents a regulation linked to a violation.
@@ -238,6 +427,76 @@ type ReportDisplay struct {
 	ViolationCount int
 	HasPDF         bool
 	HasDOCX        bool
+	Failed         bool     // Generation failed; the row has no files
+	FailedFormat   string   // Format to request when retrying a failed report
+	Recipients     []string // Addresses the report was emailed to
+}
+
+// =============================================================================
+// Violation Search Types
+// =============================================================================
+
+// ViolationSearchPageData contains data for the violation search page.
+type ViolationSearchPageData struct {
+	CurrentPath string
+	CSRFToken   string
+	User        *UserDisplay
+	Filter      ViolationSearchFilter
+	Results     []ViolationSearchItem
+	Pagination  pagination.Data
+	Error       string // Why the filter was rejected; there are no results
+	Flash       *shared.Flash
+}
+
+// ViolationSearchFilter holds the violation search form values as submitted.
+type ViolationSearchFilter struct {
+	Query    string
+	Severity string
+	Status   string
+	From     string // YYYY-MM-DD
+	To       string // YYYY-MM-DD
+	Standard string
+}
+
+// IsEmpty returns true if no search text or filter is set.
+func (f ViolationSearchFilter) IsEmpty() bool {
+	return f == ViolationSearchFilter{}
+}
+
+// PageBaseURL returns the search URL carrying the filter, for pagination
+// links. Empty values are left out to keep URLs short.
+func (f ViolationSearchFilter) PageBaseURL() string {
+	values := url.Values{}
+	for _, field := range []struct{ name, value string }{
+		{"q", f.Query},
+		{"severity", f.Severity},
+		{"status", f.Status},
+		{"from", f.From},
+		{"to", f.To},
+		{"standard", f.Standard},
+	} {
+		if field.value != "" {
+			values.Set(field.name, field.value)
+		}
+	}
+	if len(values) == 0 {
+		return "/violations"
+	}
+	return "/violations?" + values.Encode()
+}
+
+// ViolationSearchItem is a violation search result formatted for display.
+type ViolationSearchItem struct {
+	ID              string
+	Description     string
+	Severity        string
+	Status          string
+	StandardNumber  string // Primary regulation, empty when none is linked
+	ThumbnailURL    string // Empty for violations without a photo
+	InspectionTitle string
+	InspectionDate  string
+	InspectionURL   string
+	ReviewURL       string // Review queue opened at the violation
 }
 
 // =============================================================================
@@ -311,3 +570,13 @@ func TitleCase(s string) string {
 	}
 	return string(s[0]-32) + s[1:]
 }
+
+// draftStorageKey returns the localStorage key for the user's queued draft
+// changes. The key is per user so a shared device never sends one user's
+// changes to another's draft.
+func draftStorageKey(u *UserDisplay) string {
+	if u == nil {
+		return "lukaut:inspection-draft"
+	}
+	return "lukaut:inspection-draft:" + u.Email
+}
@@ -13,15 +13,32 @@ type ListPageData struct {
 	CSRFToken   string
 	User        *UserDisplay
 	Regulations []RegulationDisplay
-	Categories  []string
+	Recent      []RegulationDisplay // Shown above the list when there is no query or category
+	Bookmarks   []RegulationDisplay // Shown with Recent
+	MostUsed    []RegulationDisplay // Shown with Recent
+	Facets      FacetsData
 	Filter      FilterData
 	Pagination  PaginationData
 	Flash       *shared.Flash
 }
 
+// BookmarksPageData contains all data needed to render the bookmarked
+// regulations page.
+type BookmarksPageData struct {
+	CurrentPath string
+	CSRFToken   string
+	User        *UserDisplay
+	Regulations []RegulationDisplay
+	Flash       *shared.Flash
+}
+
 // SearchResultsData contains data for htmx search results partial.
 type SearchResultsData struct {
 	Regulations  []RegulationDisplay
+	Recent       []RegulationDisplay // Shown above the results when there is no query or category
+	Bookmarks    []RegulationDisplay // Shown with Recent
+	MostUsed     []RegulationDisplay // Shown with Recent
+	Facets       FacetsData          // Swapped out-of-band so counts follow the query
 	Filter       FilterData
 	Pagination   PaginationData
 	ViolationID  string
@@ -56,6 +73,8 @@ type RegulationDisplay struct {
 	Summary         string
 	SeverityTypical string
 	Rank            float32
+	UseCount        int64 // Times the user linked it (most-used list only)
+	IsBookmarked    bool
 }
 
 // RegulationDetailDisplay represents full regulation details for the modal.
@@ -75,8 +94,23 @@ type RegulationDetailDisplay struct {
 
 // FilterData represents the current filter/search criteria.
 type FilterData struct {
-	Query    string
-	Category string
+	Query       string
+	Category    string
+	Subcategory string
+	Severity    string
+}
+
+// FacetsData contains the category, subcategory, and severity filter options.
+type FacetsData struct {
+	Categories    []FacetDisplay
+	Subcategories []FacetDisplay // Only populated when a category is selected
+	Severities    []string
+}
+
+// FacetDisplay represents a filter option with the number of matching regulations.
+type FacetDisplay struct {
+	Name  string
+	Count int64
 }
 
 // PaginationData contains pagination information.
@@ -6,10 +6,16 @@ import "github.com/DukeRupert/lukaut/internal/templ/shared"
 type Tab string
 
 const (
-	TabProfile  Tab = "profile"
-	TabBusiness Tab = "business"
-	TabPassword Tab = "password"
-	TabBilling  Tab = "billing"
+	TabProfile      Tab = "profile"
+	TabBusiness     Tab = "business"
+	TabPassword     Tab = "password"
+	TabBilling      Tab = "billing"
+	TabUsage        Tab = "usage"
+	TabSessions     Tab = "sessions"
+	TabWebhooks     Tab = "webhooks"
+	TabOrganization Tab = "organization"
+	TabPreferences  Tab = "preferences"
+	TabReports      Tab = "reports"
 )
 
 // ProfilePageData contains data for the profile settings page
@@ -69,6 +75,7 @@ type BusinessFormData struct {
 	BusinessPostalCode    string
 	BusinessLicenseNumber string
 	BusinessLogoURL       string
+	BusinessBrandColor    string
 }
 
 // BillingPageData contains data for the billing settings page.
@@ -79,19 +86,10 @@ type BillingPageData struct {
 	CurrentPath string
 	User        *UserDisplay
 	Plan        PlanInfo
-	Prices      PriceConfig
 	Flash       *shared.Flash
 	ActiveTab   Tab
 }
 
-// PriceConfig holds the Stripe price IDs for rendering checkout buttons.
-type PriceConfig struct {
-	StarterMonthlyPriceID      string
-	StarterYearlyPriceID       string
-	ProfessionalMonthlyPriceID string
-	ProfessionalYearlyPriceID  string
-}
-
 // PlanInfo contains the user's current subscription plan details.
 //
 // Fields populated from domain.User and Stripe subscription data:
@@ -105,3 +103,175 @@ type PlanInfo struct {
 	PeriodEnd   string // formatted date string, e.g. "January 15, 2026"
 	CancelAtEnd bool   // true if subscription will cancel at period end
 }
+
+// UsagePageData contains data for the quota usage settings page.
+type UsagePageData struct {
+	CurrentPath string
+	User        *UserDisplay
+	Usage       UsageDisplay
+	Flash       *shared.Flash
+	ActiveTab   Tab
+}
+
+// UsageDisplay contains the user's consumption for the current quota period.
+type UsageDisplay struct {
+	IsUnlimited bool
+	ResetsAt    string // formatted date the quota resets, e.g. "February 1, 2026"
+	Analysis    QuotaMeter
+	Reports     QuotaMeter
+}
+
+// QuotaMeter describes usage of a single quota for a progress bar.
+type QuotaMeter struct {
+	Used        int64
+	Limit       int64
+	Remaining   int64
+	PercentUsed int
+	NearLimit   bool // true when remaining usage is at or below the warning threshold
+}
+
+// SessionsPageData contains data for the session management settings page.
+type SessionsPageData struct {
+	CurrentPath string
+	CSRFToken   string
+	User        *UserDisplay
+	Sessions    []SessionDisplay
+	Flash       *shared.Flash
+	ActiveTab   Tab
+}
+
+// SessionDisplay describes one signed-in session.
+type SessionDisplay struct {
+	ID         string
+	Device     string // e.g. "Chrome on macOS"
+	IPAddress  string
+	LastSeenAt string // formatted, e.g. "Jan 2, 2026 3:04 PM"
+	CreatedAt  string // formatted sign-in date
+	Current    bool   // true for the session viewing the page
+}
+
+// WebhooksPageData contains data for the outbound webhooks settings page.
+type WebhooksPageData struct {
+	CurrentPath  string
+	CSRFToken    string
+	User         *UserDisplay
+	Endpoints    []WebhookEndpointDisplay
+	Deliveries   []WebhookDeliveryDisplay
+	MaxEndpoints int
+	FormURL      string // Submitted URL, kept when the form has an error
+	Errors       map[string]string
+	Flash        *shared.Flash
+	ActiveTab    Tab
+}
+
+// WebhookEndpointDisplay describes one registered webhook URL.
+type WebhookEndpointDisplay struct {
+	ID        string
+	URL       string
+	Secret    string // Signing secret, revealed on request
+	CreatedAt string // formatted date
+}
+
+// WebhookDeliveryDisplay describes one recent webhook delivery.
+type WebhookDeliveryDisplay struct {
+	Event     string // e.g. "analysis.completed"
+	URL       string
+	Status    string // "pending", "delivered", or "failed"
+	Attempts  int
+	Response  string // e.g. "HTTP 500", or "" if no response was received
+	Error     string
+	CreatedAt string // formatted, e.g. "Jan 2, 3:04 PM"
+}
+
+// PreferencesPageData contains data for the units and dates settings page.
+type PreferencesPageData struct {
+	CurrentPath   string
+	CSRFToken     string
+	User          *UserDisplay
+	Units         string // Selected unit system
+	DateLocale    string // Selected date locale
+	UnitOptions   []PreferenceOption
+	LocaleOptions []PreferenceOption
+	Errors        map[string]string
+	Flash         *shared.Flash
+	ActiveTab     Tab
+}
+
+// ReportTemplatePageData contains data for the report template settings page.
+type ReportTemplatePageData struct {
+	CurrentPath  string
+	CSRFToken    string
+	User         *UserDisplay
+	Form         ReportTemplateFormData
+	Placeholders []string // Placeholder names, without braces
+	Preview      ReportTemplatePreviewData
+	Errors       map[string]string
+	Flash        *shared.Flash
+	ActiveTab    Tab
+}
+
+// ReportTemplateFormData holds the submitted or saved report sections.
+type ReportTemplateFormData struct {
+	Intro      string
+	Disclaimer string
+	Signature  string
+}
+
+// ReportTemplatePreviewData holds the report sections rendered with sample
+// placeholder values. A section with an error shows the error instead.
+type ReportTemplatePreviewData struct {
+	Intro      string
+	Disclaimer string
+	Signature  string
+	Errors     map[string]string
+}
+
+// PreferenceOption is one choice of a preference.
+type PreferenceOption struct {
+	Value   string
+	Label   string
+	Example string // e.g. "March 5, 2025", or "" if there is none
+}
+
+// OrganizationPageData contains data for the organization settings page.
+type OrganizationPageData struct {
+	CurrentPath      string
+	CSRFToken        string
+	User             *UserDisplay
+	OrganizationName string
+	IsOwner          bool
+	Members          []OrganizationMemberDisplay
+	Invites          []OrganizationInviteDisplay
+	FormEmail        string // Submitted email, kept when the form has an error
+	Errors           map[string]string
+	Flash            *shared.Flash
+	ActiveTab        Tab
+}
+
+// OrganizationMemberDisplay describes one member of an organization.
+type OrganizationMemberDisplay struct {
+	ID        string
+	Name      string
+	Email     string
+	Role      string // "owner" or "member"
+	JoinedAt  string // formatted date, or "" for a personal organization
+	Removable bool   // true if the viewer may remove this member
+}
+
+// OrganizationInviteDisplay describes one pending invitation.
+type OrganizationInviteDisplay struct {
+	Email     string
+	ExpiresAt string // formatted date
+}
+
+// OrganizationJoinPageData contains data for the page that accepts an invitation.
+type OrganizationJoinPageData struct {
+	CurrentPath      string
+	CSRFToken        string
+	User             *UserDisplay
+	Token            string
+	OrganizationName string
+	InviteEmail      string
+	Error            string // Why the invitation cannot be accepted, if it cannot
+	Flash            *shared.Flash
+}
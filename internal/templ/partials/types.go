@@ -17,9 +17,26 @@ type ImageDisplay struct {
 	ThumbnailURL     string  // URL for thumbnail
 	OriginalFilename string  // Original filename
 	AnalysisStatus   string  // Analysis status (pending, analyzing, completed, failed)
+	AnalysisError    string  // Why analysis failed, shown on the failed badge
 	SizeMB           float64 // File size in megabytes
 }
 
+// ImageStatusData contains data for the per-image analysis status partial.
+type ImageStatusData struct {
+	ImageID string // Image ID (as string for templates)
+	Status  string // Analysis status (pending, analyzing, completed, failed)
+	Error   string // Why analysis failed, shown on hover over the failed badge
+	Poll    bool   // Whether to poll for status changes
+}
+
+// QuotaWarningData contains data for the analysis quota warning banner.
+type QuotaWarningData struct {
+	Remaining int64  // Analyses left this period
+	Limit     int64  // Analyses allowed per period
+	ResetsAt  string // Formatted date the quota resets
+	Exhausted bool   // True if no analyses remain
+}
+
 // ViolationCardData contains data for rendering a single violation card.
 type ViolationCardData struct {
 	Violation    ViolationDisplay    // The violation
@@ -34,11 +51,34 @@ type ViolationDisplay struct {
 	Description    string // Description
 	AIDescription  string // AI-generated description (if any)
 	Severity       string // Severity (critical, serious, other, recommendation)
+	AISeverity     string // Severity originally assigned by AI (empty for manual violations)
 	Status         string // Status (pending, confirmed, rejected)
 	Confidence     string // AI confidence (high, medium, low)
 	InspectorNotes string // Inspector notes
 }
 
+// ViolationPhotosData contains data for a violation's additional photos
+// and the picker of the inspection's other photos.
+type ViolationPhotosData struct {
+	ViolationID string                  // Violation ID
+	Attached    []ViolationPhotoDisplay // Additional photos, in attached order
+	Available   []ViolationPhotoDisplay // The inspection's photos that can be attached
+	PhotoCount  int                     // Photos the violation has, counting the primary photo
+	MaxPhotos   int                     // Most photos a violation can have
+}
+
+// CanAttach returns true if another photo can be attached.
+func (d ViolationPhotosData) CanAttach() bool {
+	return d.PhotoCount < d.MaxPhotos
+}
+
+// ViolationPhotoDisplay represents a photo in the violation photo picker.
+type ViolationPhotoDisplay struct {
+	ImageID      string // Image ID
+	ThumbnailURL string // Image thumbnail URL
+	Filename     string // Original filename, for alt text
+}
+
 // RegulationDisplay represents a linked regulation for display.
 type RegulationDisplay struct {
 	RegulationID   string // Regulation ID (for unlinking)
@@ -57,6 +97,7 @@ type AnalysisStatusData struct {
 	PendingImages  int64  // Number of images pending analysis
 	TotalImages    int64  // Total number of images in inspection
 	AnalyzedImages int64  // Number of images analyzed (completed/failed)
+	FailedImages   int64  // Number of images whose analysis failed
 	ViolationCount int64  // Number of violations found
 	Message        string // Status message to display
 	PollingEnabled bool   // Whether to enable htmx polling
@@ -69,6 +110,59 @@ type ViolationsSummaryData struct {
 	ViolationCounts ViolationCounts // Summary counts
 }
 
+// InspectionStatusActionsData contains data for the inspection status buttons partial.
+type InspectionStatusActionsData struct {
+	InspectionID string   // Inspection ID
+	Status       string   // Current status
+	Statuses     []string // Statuses the inspection can move to, in workflow order
+}
+
+// InspectionCompletionBlockedData contains data for the partial explaining
+// why an inspection can't be completed yet.
+type InspectionCompletionBlockedData struct {
+	InspectionID string
+	Blockers     []CompletionBlockerDisplay
+	Confirmation string // Text typed to force completion
+	Error        string // Why forcing completion was refused
+}
+
+// CompletionBlockerDisplay is one reason an inspection can't be completed,
+// with a link to where it can be resolved.
+type CompletionBlockerDisplay struct {
+	Reason    string
+	LinkURL   string
+	LinkLabel string
+}
+
+// InspectionTimelineData contains data for the inspection activity timeline partial.
+type InspectionTimelineData struct {
+	InspectionID string          // Inspection ID
+	Entries      []TimelineEntry // Timeline entries, oldest first
+}
+
+// TimelineEntry represents a single item in the activity timeline.
+type TimelineEntry struct {
+	Kind      string // domain.TimelineEntryKind value (drives the dot color)
+	Summary   string // Human-readable description of the activity
+	ActorName string // Who did it (may be empty)
+	Timestamp string // Formatted time of the activity
+}
+
+// InspectionEventsData contains data for the live activity feed partial.
+type InspectionEventsData struct {
+	InspectionID string                // Inspection ID
+	Events       []InspectionEventItem // Events newer than the cursor, oldest first
+	Cursor       int64                 // ID of the last event shown, for the next poll
+	Polling      bool                  // Keep polling (the inspection is still being analyzed)
+}
+
+// InspectionEventItem represents a single event in the live activity feed.
+type InspectionEventItem struct {
+	Kind      string // domain.InspectionEventKind value (drives the dot color)
+	Summary   string // Human-readable description of the event
+	Timestamp string // Formatted time of the event
+}
+
 // ViolationCounts contains summary statistics for violations.
 type ViolationCounts struct {
 	Total     int // Total violations
@@ -101,6 +195,19 @@ type QueueViolationViewData struct {
 	HasPrev      bool
 	HasNext      bool
 	CSRFToken    string
+	FilterQuery  string // Encoded filter params appended to queue URLs (e.g. "&severity=critical")
+	Keys         QueueShortcutKeys
+}
+
+// QueueShortcutKeys are the user's keys for the review queue actions, shown
+// as hints on the buttons they trigger. An empty key shows no hint.
+type QueueShortcutKeys struct {
+	Confirm string
+	Reject  string
+	Edit    string
+	Undo    string
+	Next    string
+	Prev    string
 }
 
 // QueueViolationDisplay represents a violation for display in the queue.
@@ -110,12 +217,26 @@ type QueueViolationDisplay struct {
 	AIDescription  string
 	Status         string
 	Severity       string
+	AISeverity     string
 	Confidence     string
 	InspectorNotes string
 	ThumbnailURL   string
 	OriginalURL    string
+	RegionURL      string // Close-up of the AI-detected region, empty without one
 	ImageID        string
 	Regulations    []QueueRegulationDisplay
+	Annotations    []QueueAnnotationDisplay // Boxes drawn over the image
+}
+
+// QueueAnnotationDisplay represents a box drawn over a violation's photo in
+// queue view. Positions are percentages of the image size.
+type QueueAnnotationDisplay struct {
+	Left   float64
+	Top    float64
+	Width  float64
+	Height float64
+	Label  string
+	Source string // "ai" or "manual"
 }
 
 // QueueRegulationDisplay represents a regulation linked to a violation in queue view.
@@ -132,6 +253,18 @@ type QueueHeaderData struct {
 	Position     int
 	TotalCount   int
 	Counts       ViolationCounts
+	FilterLabel  string // Human-readable description of the active filter (empty if none)
+}
+
+// QueueUndoData describes the most recent review decision so it can be undone.
+// An empty ViolationID renders an empty placeholder, clearing any prior undo.
+type QueueUndoData struct {
+	InspectionID string
+	ViolationID  string
+	NewStatus    string // Status applied by the decision being undone
+	Position     int    // Queue position of the violation when the decision was made
+	FilterQuery  string // Encoded filter params appended to the undo URL
+	UndoKey      string // Shortcut hint for the undo button; empty shows none
 }
 
 // QueueCompletionData contains data for the queue completion screen.
@@ -139,4 +272,56 @@ type QueueCompletionData struct {
 	InspectionID   string
 	ConfirmedCount int
 	RejectedCount  int
+	IsFiltered     bool // True if the counts cover a filtered subset of violations
+}
+
+// ShareLinksData contains data for the inspection share links partial.
+type ShareLinksData struct {
+	InspectionID string          // Inspection ID
+	NewURL       string          // Public URL of a just-created link; shown once
+	Error        string          // Error from the create form
+	Links        []ShareLinkItem // Active share links, newest first
+}
+
+// ShareLinkItem represents a single active share link in the list.
+type ShareLinkItem struct {
+	ID        string // Share link ID
+	ExpiresAt string // Formatted expiry time
+	Views     string // e.g. "2 views" or "2 of 5 views"
+	CreatedAt string // Formatted creation time
+}
+
+// InspectionCommentsData contains data for the inspection comments partial.
+type InspectionCommentsData struct {
+	InspectionID string                  // Inspection ID
+	Body         string                  // Comment form text, kept when posting fails
+	Error        string                  // Error from the comment form
+	Comments     []InspectionCommentItem // Comments, oldest first
+}
+
+// InspectionCommentItem represents a single comment in the discussion.
+type InspectionCommentItem struct {
+	ID         string // Comment ID
+	AuthorName string // Name of the user who wrote the comment
+	Body       string // Comment text
+	CreatedAt  string // Formatted posting time
+	CanDelete  bool   // True if the current user wrote the comment
+}
+
+// JobStatusData contains data for the background job status partial.
+type JobStatusData struct {
+	JobID    string // Job ID (as string for templates)
+	Status   string // Job status (pending, running, completed, failed)
+	Message  string // What the job is doing or did
+	Error    string // Why the job failed, if it did
+	LinkURL  string // Where to see the job's output, if anywhere
+	LinkText string // Text of the link to LinkURL
+	Poll     bool   // Whether to poll until the job is done
+}
+
+// ReportQueuedData contains data for the message shown when a report is queued.
+type ReportQueuedData struct {
+	JobID      string   // Report job ID (as string for templates)
+	Format     string   // Report format (pdf or docx)
+	Recipients []string // Addresses the report will be emailed to, if any
 }
@@ -0,0 +1,210 @@
+// Package fakedb is an in-memory database/sql driver for unit tests of code
+// that runs the repository's sqlc queries.
+//
+// A test implements Handler to answer each query by its sqlc name, the
+// "-- name:" header sqlc puts first in every query, from whatever state it
+// keeps, and opens a *sql.DB over it with Open. The fake only stands in for
+// the queries a test exercises; it does not check the SQL itself.
+package fakedb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Handler answers the queries sent to a fake database. Calls are
+// serialized, so a handler needs no locking of its own for state only its
+// queries touch.
+type Handler interface {
+	// Query answers a query that returns rows, such as a SELECT or an
+	// INSERT ... RETURNING. A result without values is an empty result,
+	// which :one queries report as sql.ErrNoRows.
+	Query(q Query) (*Rows, error)
+}
+
+// Execer is implemented by handlers that answer statements that return no
+// rows, such as an UPDATE. A fake database whose handler doesn't implement
+// it fails every statement.
+type Execer interface {
+	// Exec answers a statement and returns the number of rows it affected.
+	Exec(q Query) (int64, error)
+}
+
+// TxHandler is implemented by handlers that take part in transactions,
+// e.g. to snapshot their state so a rollback can restore it. Handlers
+// without it accept transactions whose rollbacks only undo the writes
+// registered with Query.OnRollback.
+type TxHandler interface {
+	// Begin starts a transaction and returns the functions that end it.
+	// Either may be nil.
+	Begin() (commit, rollback func())
+}
+
+// Query is a query or statement sent to a fake database.
+type Query struct {
+	Name string              // sqlc query name
+	SQL  string              // Full query text
+	Args []driver.NamedValue // Bound arguments, in order
+	tx   *tx                 // Open transaction of the connection, or nil
+}
+
+// Arg returns the value bound to the i-th parameter ($i+1).
+func (q Query) Arg(i int) driver.Value {
+	return q.Args[i].Value
+}
+
+// InTx reports whether the query runs in a transaction.
+func (q Query) InTx() bool {
+	return q.tx != nil
+}
+
+// OnRollback registers fn to undo a write the query made, should its
+// transaction roll back. Outside a transaction it does nothing. Undo
+// functions run in reverse order of registration.
+func (q Query) OnRollback(fn func()) {
+	if q.tx != nil {
+		q.tx.undo = append(q.tx.undo, fn)
+	}
+}
+
+// Rows is the result of a query.
+type Rows struct {
+	Columns int              // Number of columns the query selects
+	Values  [][]driver.Value // One slice of Columns values per row
+}
+
+// Row returns a single-row result with the given values.
+func Row(values ...driver.Value) *Rows {
+	return &Rows{Columns: len(values), Values: [][]driver.Value{values}}
+}
+
+// Open returns a database whose queries h answers.
+func Open(h Handler) *sql.DB {
+	return sql.OpenDB(&connector{h: h})
+}
+
+// Name returns the sqlc name of a query.
+func Name(query string) string {
+	fields := strings.Fields(strings.TrimPrefix(query, "-- name:"))
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+// connector opens connections to a handler, serializing their calls.
+type connector struct {
+	mu sync.Mutex
+	h  Handler
+}
+
+func (c *connector) Connect(context.Context) (driver.Conn, error) { return &conn{c: c}, nil }
+func (c *connector) Driver() driver.Driver                        { return nil }
+
+// conn is a connection to a fake database. It tracks its own transaction,
+// since database/sql runs a transaction on one connection.
+type conn struct {
+	c  *connector
+	tx *tx
+}
+
+// tx is an open transaction.
+type tx struct {
+	conn     *conn
+	undo     []func()
+	commit   func()
+	rollback func()
+}
+
+func (c *conn) Prepare(string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (c *conn) Close() error                        { return nil }
+
+func (c *conn) Begin() (driver.Tx, error) {
+	c.c.mu.Lock()
+	defer c.c.mu.Unlock()
+	if c.tx != nil {
+		return nil, fmt.Errorf("fakedb: transaction already open")
+	}
+	c.tx = &tx{conn: c}
+	if h, ok := c.c.h.(TxHandler); ok {
+		c.tx.commit, c.tx.rollback = h.Begin()
+	}
+	return c.tx, nil
+}
+
+func (t *tx) Commit() error {
+	t.conn.c.mu.Lock()
+	defer t.conn.c.mu.Unlock()
+	t.conn.tx = nil
+	if t.commit != nil {
+		t.commit()
+	}
+	return nil
+}
+
+func (t *tx) Rollback() error {
+	t.conn.c.mu.Lock()
+	defer t.conn.c.mu.Unlock()
+	t.conn.tx = nil
+	for i := len(t.undo) - 1; i >= 0; i-- {
+		t.undo[i]()
+	}
+	if t.rollback != nil {
+		t.rollback()
+	}
+	return nil
+}
+
+func (c *conn) query(query string, args []driver.NamedValue) Query {
+	return Query{Name: Name(query), SQL: query, Args: args, tx: c.tx}
+}
+
+func (c *conn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.c.mu.Lock()
+	defer c.c.mu.Unlock()
+	result, err := c.c.h.Query(c.query(query, args))
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, fmt.Errorf("fakedb: %s returned no result", Name(query))
+	}
+	return &rows{columns: result.Columns, values: result.Values}, nil
+}
+
+func (c *conn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.c.mu.Lock()
+	defer c.c.mu.Unlock()
+	h, ok := c.c.h.(Execer)
+	if !ok {
+		return nil, fmt.Errorf("fakedb: unexpected exec %q", Name(query))
+	}
+	n, err := h.Exec(c.query(query, args))
+	if err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(n), nil
+}
+
+// rows iterates over a query result.
+type rows struct {
+	columns int
+	values  [][]driver.Value
+}
+
+func (r *rows) Columns() []string { return make([]string, r.columns) }
+func (r *rows) Close() error      { return nil }
+
+func (r *rows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
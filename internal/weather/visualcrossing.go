@@ -0,0 +1,120 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// Visual Crossing Provider
+// =============================================================================
+
+// DefaultVisualCrossingURL is the Visual Crossing Timeline API endpoint.
+const DefaultVisualCrossingURL = "https://weather.visualcrossing.com/VisualCrossingWebServices/rest/services/timeline"
+
+// DefaultRequestTimeout bounds a single provider request.
+const DefaultRequestTimeout = 5 * time.Second
+
+// VisualCrossingConfig configures the Visual Crossing provider.
+type VisualCrossingConfig struct {
+	APIKey  string        // Required: Visual Crossing API key
+	BaseURL string        // Endpoint (default: DefaultVisualCrossingURL)
+	Timeout time.Duration // Per-request timeout (default: DefaultRequestTimeout)
+}
+
+// VisualCrossing looks up daily weather with the Visual Crossing Timeline
+// API, which serves both historical observations and forecasts, in US units.
+type VisualCrossing struct {
+	config VisualCrossingConfig
+	client *http.Client
+}
+
+// NewVisualCrossing creates a Visual Crossing provider.
+func NewVisualCrossing(config VisualCrossingConfig) *VisualCrossing {
+	if config.BaseURL == "" {
+		config.BaseURL = DefaultVisualCrossingURL
+	}
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultRequestTimeout
+	}
+	return &VisualCrossing{config: config, client: &http.Client{}}
+}
+
+var _ Provider = (*VisualCrossing)(nil)
+
+// visualCrossingResponse is the Timeline API response with include=days.
+type visualCrossingResponse struct {
+	Days []struct {
+		Datetime   string   `json:"datetime"`
+		Temp       *float64 `json:"temp"`
+		Conditions string   `json:"conditions"`
+	} `json:"days"`
+}
+
+// Lookup returns the weather at loc on date.
+func (v *VisualCrossing) Lookup(ctx context.Context, loc Location, date time.Time) (Conditions, error) {
+	if loc.IsZero() {
+		return Conditions{}, ErrNoData
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, v.config.Timeout)
+	defer cancel()
+
+	place := strings.TrimSpace(loc.PostalCode)
+	if loc.Latitude != nil && loc.Longitude != nil {
+		place = strconv.FormatFloat(*loc.Latitude, 'f', 5, 64) + "," + strconv.FormatFloat(*loc.Longitude, 'f', 5, 64)
+	}
+	params := url.Values{
+		"unitGroup":   {"us"},
+		"include":     {"days"},
+		"elements":    {"datetime,temp,conditions"},
+		"contentType": {"json"},
+		"key":         {v.config.APIKey},
+	}
+	endpoint := fmt.Sprintf("%s/%s/%s?%s", v.config.BaseURL, url.PathEscape(place), date.Format("2006-01-02"), params.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return Conditions{}, fmt.Errorf("visual crossing: build request: %w", err)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		// The error includes the URL, which contains the API key
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			err = uerr.Err
+		}
+		return Conditions{}, fmt.Errorf("visual crossing: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusNotFound:
+		// Unknown location or a date outside the available range
+		return Conditions{}, ErrNoData
+	case resp.StatusCode != http.StatusOK:
+		return Conditions{}, fmt.Errorf("visual crossing: unexpected status %d", resp.StatusCode)
+	}
+
+	var body visualCrossingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return Conditions{}, fmt.Errorf("visual crossing: decode response: %w", err)
+	}
+	if len(body.Days) == 0 || body.Days[0].Temp == nil {
+		return Conditions{}, ErrNoData
+	}
+
+	day := body.Days[0]
+	return Conditions{
+		Summary:      day.Conditions,
+		TemperatureF: *day.Temp,
+	}, nil
+}
@@ -0,0 +1,107 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVisualCrossingLookup(t *testing.T) {
+	var gotPath, gotKey string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotKey = r.URL.Query().Get("key")
+		_, _ = w.Write([]byte(`{"days":[{"datetime":"2026-03-14","temp":71.6,"conditions":"Partially cloudy"}]}`))
+	}))
+	defer srv.Close()
+
+	v := NewVisualCrossing(VisualCrossingConfig{APIKey: "test-key", BaseURL: srv.URL + "/"})
+	lat, lng := 43.615, -116.2023
+	date := time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)
+
+	got, err := v.Lookup(context.Background(), Location{Latitude: &lat, Longitude: &lng, PostalCode: "83702"}, date)
+	if err != nil {
+		t.Fatalf("Lookup failed: %v", err)
+	}
+	if got.Summary != "Partially cloudy" || got.Temperature() != "72°F" {
+		t.Errorf("unexpected conditions: %+v (%s)", got, got.Temperature())
+	}
+	if gotPath != "/43.61500,-116.20230/2026-03-14" || gotKey != "test-key" {
+		t.Errorf("unexpected request: path=%q key=%q", gotPath, gotKey)
+	}
+
+	// Without coordinates the postal code is used
+	if _, err := v.Lookup(context.Background(), Location{PostalCode: "83702"}, date); err != nil {
+		t.Fatalf("Lookup failed: %v", err)
+	}
+	if gotPath != "/83702/2026-03-14" {
+		t.Errorf("expected a postal code lookup, got path %q", gotPath)
+	}
+}
+
+func TestVisualCrossingLookup_NoData(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{name: "unknown location", status: http.StatusBadRequest},
+		{name: "no days", body: `{"days":[]}`},
+		{name: "no temperature", body: `{"days":[{"datetime":"2026-03-14","conditions":""}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.status != 0 {
+					w.WriteHeader(tt.status)
+					return
+				}
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			v := NewVisualCrossing(VisualCrossingConfig{APIKey: "test-key", BaseURL: srv.URL})
+			_, err := v.Lookup(context.Background(), Location{PostalCode: "83702"}, time.Now())
+			if !errors.Is(err, ErrNoData) {
+				t.Errorf("expected ErrNoData, got %v", err)
+			}
+		})
+	}
+}
+
+func TestVisualCrossingLookup_Timeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer srv.Close()
+
+	v := NewVisualCrossing(VisualCrossingConfig{APIKey: "secret-key", BaseURL: srv.URL, Timeout: 20 * time.Millisecond})
+	_, err := v.Lookup(context.Background(), Location{PostalCode: "83702"}, time.Now())
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a deadline error, got %v", err)
+	}
+	if strings.Contains(err.Error(), "secret-key") {
+		t.Errorf("expected the API key to be kept out of the error, got %v", err)
+	}
+}
+
+func TestNew(t *testing.T) {
+	if _, ok := New(Config{}).(Noop); !ok {
+		t.Error("expected Noop without an API key")
+	}
+	if _, ok := New(Config{APIKey: "key"}).(*VisualCrossing); !ok {
+		t.Error("expected VisualCrossing with an API key")
+	}
+	if _, err := (Noop{}).Lookup(context.Background(), Location{PostalCode: "83702"}, time.Now()); !errors.Is(err, ErrNoData) {
+		t.Errorf("expected Noop to return ErrNoData, got %v", err)
+	}
+}
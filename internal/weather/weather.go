@@ -0,0 +1,78 @@
+// Package weather looks up the weather at an inspection site on a given day.
+//
+// Provider is the extension point for a weather data source. The default,
+// Noop, has no data, so weather fields are left for the inspector to fill
+// in. New selects a provider from configuration.
+package weather
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// ErrNoData is returned by Lookup when the provider has no weather for the
+// location and date.
+var ErrNoData = errors.New("weather: no data for location and date")
+
+// Location identifies where to look up the weather. Coordinates are used
+// when set; otherwise the postal code.
+type Location struct {
+	Latitude   *float64
+	Longitude  *float64
+	PostalCode string
+}
+
+// IsZero reports whether the location has neither coordinates nor a postal code.
+func (l Location) IsZero() bool {
+	return (l.Latitude == nil || l.Longitude == nil) && strings.TrimSpace(l.PostalCode) == ""
+}
+
+// Conditions is the weather for one day.
+type Conditions struct {
+	Summary      string  // e.g. "Partially cloudy"
+	TemperatureF float64 // Mean temperature in degrees Fahrenheit
+}
+
+// Temperature formats the temperature the way inspectors enter it, e.g. "72°F".
+func (c Conditions) Temperature() string {
+	return fmt.Sprintf("%d°F", int(math.Round(c.TemperatureF)))
+}
+
+// Provider looks up daily weather.
+//
+// Implementations:
+// - Noop: has no data (default)
+// - VisualCrossing: Visual Crossing Timeline API (history and forecast)
+type Provider interface {
+	// Lookup returns the weather at loc on date's calendar day. It returns
+	// ErrNoData if the provider has nothing for that place and day.
+	Lookup(ctx context.Context, loc Location, date time.Time) (Conditions, error)
+}
+
+// Noop is a Provider with no weather data.
+type Noop struct{}
+
+// Lookup always returns ErrNoData.
+func (Noop) Lookup(ctx context.Context, loc Location, date time.Time) (Conditions, error) {
+	return Conditions{}, ErrNoData
+}
+
+var _ Provider = Noop{}
+
+// Config configures the weather provider.
+type Config struct {
+	APIKey string // Visual Crossing API key; empty disables weather lookups
+	URL    string // Optional endpoint override
+}
+
+// New returns the provider for cfg. Without an API key it returns Noop.
+func New(cfg Config) Provider {
+	if cfg.APIKey == "" {
+		return Noop{}
+	}
+	return NewVisualCrossing(VisualCrossingConfig{APIKey: cfg.APIKey, BaseURL: cfg.URL})
+}
@@ -0,0 +1,138 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	// DefaultMaxAttempts is the number of times Client.Send tries a delivery.
+	DefaultMaxAttempts = 3
+
+	// DefaultRetryBaseDelay is the wait before the first retry; it doubles
+	// with each further retry.
+	DefaultRetryBaseDelay = 2 * time.Second
+
+	// DefaultRequestTimeout bounds a single delivery attempt.
+	DefaultRequestTimeout = 10 * time.Second
+)
+
+// ErrDeliveryFailed is returned by Client.Send when no attempt got a 2xx response.
+var ErrDeliveryFailed = errors.New("webhook: delivery failed")
+
+// ClientConfig configures a Client.
+type ClientConfig struct {
+	MaxAttempts    int           // Attempts per Send (default: DefaultMaxAttempts)
+	RetryBaseDelay time.Duration // Wait before the first retry (default: DefaultRetryBaseDelay)
+	Timeout        time.Duration // Per-attempt timeout (default: DefaultRequestTimeout)
+}
+
+// Client delivers signed payloads to endpoints.
+type Client struct {
+	config ClientConfig
+	client *http.Client
+}
+
+// NewClient creates a Client. Redirects are not followed, since an endpoint
+// was validated by its URL and a redirect could point anywhere.
+func NewClient(config ClientConfig) *Client {
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = DefaultMaxAttempts
+	}
+	if config.RetryBaseDelay <= 0 {
+		config.RetryBaseDelay = DefaultRetryBaseDelay
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultRequestTimeout
+	}
+	return &Client{
+		config: config,
+		client: &http.Client{
+			Timeout: config.Timeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Request is a delivery to send.
+type Request struct {
+	URL        string
+	Secret     string
+	Event      Event
+	DeliveryID string
+	Body       []byte // Marshaled Payload
+}
+
+// Result describes a Send.
+type Result struct {
+	Attempts   int // Attempts made, including the successful one
+	StatusCode int // Status of the last response; 0 if none was received
+}
+
+// Send POSTs the request body to its URL, retrying with exponential backoff
+// until a 2xx response or MaxAttempts attempts. Each attempt is signed with a
+// fresh timestamp. Returns an error wrapping ErrDeliveryFailed if every
+// attempt failed, or the context error if ctx ends first.
+func (c *Client) Send(ctx context.Context, req Request) (Result, error) {
+	var result Result
+	var lastErr error
+
+	for attempt := 1; attempt <= c.config.MaxAttempts; attempt++ {
+		result.Attempts = attempt
+
+		status, err := c.sendOnce(ctx, req)
+		result.StatusCode = status
+		if err == nil {
+			return result, nil
+		}
+		lastErr = err
+
+		if attempt == c.config.MaxAttempts {
+			break
+		}
+
+		// Exponential backoff: base * 2^(attempt-1)
+		delay := c.config.RetryBaseDelay * time.Duration(1<<(attempt-1))
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return result, ctx.Err()
+		}
+	}
+
+	return result, fmt.Errorf("%w after %d attempts: %v", ErrDeliveryFailed, result.Attempts, lastErr)
+}
+
+// sendOnce makes a single delivery attempt and returns the response status.
+func (c *Client) sendOnce(ctx context.Context, req Request) (int, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, req.URL, bytes.NewReader(req.Body))
+	if err != nil {
+		return 0, fmt.Errorf("create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", "Lukaut-Webhooks/1.0")
+	httpReq.Header.Set(HeaderEvent, string(req.Event))
+	httpReq.Header.Set(HeaderDelivery, req.DeliveryID)
+	httpReq.Header.Set(HeaderSignature, Sign(req.Secret, req.Body, time.Now()))
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return 0, fmt.Errorf("send request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// Drain a little of the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,138 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingEndpoint answers with the given statuses in turn and records
+// each request it receives.
+type recordingEndpoint struct {
+	mu       sync.Mutex
+	statuses []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (e *recordingEndpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	body, _ := io.ReadAll(r.Body)
+	e.requests = append(e.requests, r)
+	e.bodies = append(e.bodies, body)
+
+	status := http.StatusOK
+	if len(e.statuses) > 0 {
+		status, e.statuses = e.statuses[0], e.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func newTestClient(maxAttempts int) *Client {
+	return NewClient(ClientConfig{MaxAttempts: maxAttempts, RetryBaseDelay: time.Millisecond})
+}
+
+func TestClientSend_SignsRequest(t *testing.T) {
+	endpoint := &recordingEndpoint{}
+	srv := httptest.NewServer(endpoint)
+	defer srv.Close()
+
+	body := []byte(`{"event":"report.ready"}`)
+	result, err := newTestClient(3).Send(context.Background(), Request{
+		URL:        srv.URL,
+		Secret:     "whsec_test",
+		Event:      EventReportReady,
+		DeliveryID: "d-1",
+		Body:       body,
+	})
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if result.Attempts != 1 || result.StatusCode != http.StatusOK {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	req := endpoint.requests[0]
+	if req.Method != http.MethodPost || req.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("unexpected request: %s %s", req.Method, req.Header.Get("Content-Type"))
+	}
+	if req.Header.Get(HeaderEvent) != "report.ready" || req.Header.Get(HeaderDelivery) != "d-1" {
+		t.Errorf("unexpected event headers: %v", req.Header)
+	}
+	if string(endpoint.bodies[0]) != string(body) {
+		t.Errorf("expected body %s, got %s", body, endpoint.bodies[0])
+	}
+	if err := Verify("whsec_test", req.Header.Get(HeaderSignature), endpoint.bodies[0], DefaultTolerance, time.Now()); err != nil {
+		t.Errorf("expected receiver to verify the signature, got %v", err)
+	}
+}
+
+func TestClientSend_RetriesNon2xx(t *testing.T) {
+	endpoint := &recordingEndpoint{statuses: []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusAccepted}}
+	srv := httptest.NewServer(endpoint)
+	defer srv.Close()
+
+	result, err := newTestClient(3).Send(context.Background(), Request{URL: srv.URL, Secret: "s", Body: []byte(`{}`)})
+	if err != nil {
+		t.Fatalf("expected the third attempt to succeed, got %v", err)
+	}
+	if result.Attempts != 3 || result.StatusCode != http.StatusAccepted {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if len(endpoint.requests) != 3 {
+		t.Errorf("expected 3 requests, got %d", len(endpoint.requests))
+	}
+}
+
+func TestClientSend_GivesUpAfterMaxAttempts(t *testing.T) {
+	endpoint := &recordingEndpoint{statuses: []int{http.StatusNotFound, http.StatusNotFound, http.StatusNotFound}}
+	srv := httptest.NewServer(endpoint)
+	defer srv.Close()
+
+	result, err := newTestClient(2).Send(context.Background(), Request{URL: srv.URL, Secret: "s", Body: []byte(`{}`)})
+	if !errors.Is(err, ErrDeliveryFailed) {
+		t.Fatalf("expected ErrDeliveryFailed, got %v", err)
+	}
+	if result.Attempts != 2 || result.StatusCode != http.StatusNotFound {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if len(endpoint.requests) != 2 {
+		t.Errorf("expected 2 requests, got %d", len(endpoint.requests))
+	}
+}
+
+func TestClientSend_RedirectIsAFailure(t *testing.T) {
+	target := &recordingEndpoint{}
+	targetSrv := httptest.NewServer(target)
+	defer targetSrv.Close()
+	srv := httptest.NewServer(http.RedirectHandler(targetSrv.URL, http.StatusFound))
+	defer srv.Close()
+
+	result, err := newTestClient(1).Send(context.Background(), Request{URL: srv.URL, Secret: "s", Body: []byte(`{}`)})
+	if !errors.Is(err, ErrDeliveryFailed) || result.StatusCode != http.StatusFound {
+		t.Fatalf("expected the redirect to fail the delivery, got %+v, %v", result, err)
+	}
+	if len(target.requests) != 0 {
+		t.Error("expected the redirect not to be followed")
+	}
+}
+
+func TestClientSend_NetworkErrorIsRetried(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+
+	result, err := newTestClient(2).Send(context.Background(), Request{URL: url, Secret: "s", Body: []byte(`{}`)})
+	if !errors.Is(err, ErrDeliveryFailed) {
+		t.Fatalf("expected ErrDeliveryFailed, got %v", err)
+	}
+	if result.Attempts != 2 || result.StatusCode != 0 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
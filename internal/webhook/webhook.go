@@ -0,0 +1,109 @@
+// Package webhook sends signed event notifications to URLs registered by
+// users, so integrations can react to finished analyses and reports without
+// polling.
+//
+// Each notification is an HTTP POST of a JSON Payload. The body is signed
+// with the endpoint's secret (see Sign) and the signature is sent in the
+// Lukaut-Signature header; receivers verify it with Verify. Client retries
+// deliveries that fail or get a non-2xx response.
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event identifies what happened.
+type Event string
+
+const (
+	// EventAnalysisCompleted is sent when the AI analysis of an inspection's
+	// images finishes. Data is AnalysisCompletedData.
+	EventAnalysisCompleted Event = "analysis.completed"
+
+	// EventReportReady is sent when a generated report is available for
+	// download. Data is ReportReadyData.
+	EventReportReady Event = "report.ready"
+)
+
+// Headers sent with every delivery.
+const (
+	HeaderSignature = "Lukaut-Signature" // Timestamp and HMAC of the body, see Sign
+	HeaderEvent     = "Lukaut-Event"     // Event name, same as Payload.Event
+	HeaderDelivery  = "Lukaut-Delivery"  // Delivery ID, same as Payload.ID; stable across retries
+)
+
+// ErrInvalidURL is returned by ValidateURL for URLs that cannot receive webhooks.
+var ErrInvalidURL = errors.New("webhook: invalid endpoint URL")
+
+// Payload is the JSON body of a delivery.
+type Payload struct {
+	ID        uuid.UUID       `json:"id"`         // Delivery ID; receivers can use it to drop duplicates
+	Event     Event           `json:"event"`      // What happened
+	CreatedAt time.Time       `json:"created_at"` // When the event happened
+	Data      json.RawMessage `json:"data"`       // Event-specific details
+}
+
+// AnalysisCompletedData describes a finished inspection analysis.
+type AnalysisCompletedData struct {
+	InspectionID   uuid.UUID `json:"inspection_id"`
+	ImagesAnalyzed int       `json:"images_analyzed"`
+	ImagesFailed   int       `json:"images_failed"`
+}
+
+// ReportReadyData describes a generated report.
+type ReportReadyData struct {
+	ReportID     uuid.UUID `json:"report_id"`
+	InspectionID uuid.UUID `json:"inspection_id"`
+	Format       string    `json:"format"`       // "pdf" or "docx"
+	DownloadURL  string    `json:"download_url"` // Requires the user to be signed in
+}
+
+// NewPayload marshals a payload for event with the given data.
+func NewPayload(id uuid.UUID, event Event, data any, createdAt time.Time) ([]byte, error) {
+	dataJSON, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("marshal %s data: %w", event, err)
+	}
+	return json.Marshal(Payload{
+		ID:        id,
+		Event:     event,
+		CreatedAt: createdAt.UTC(),
+		Data:      dataJSON,
+	})
+}
+
+// ValidateURL checks that raw can receive webhooks: an absolute https URL
+// without credentials whose host is not a loopback, private, or link-local
+// address. Hostnames are not resolved.
+func ValidateURL(raw string) error {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+	if u.Scheme != "https" {
+		return fmt.Errorf("%w: must start with https://", ErrInvalidURL)
+	}
+	if u.Hostname() == "" {
+		return fmt.Errorf("%w: missing host", ErrInvalidURL)
+	}
+	if u.User != nil {
+		return fmt.Errorf("%w: must not contain credentials", ErrInvalidURL)
+	}
+	if strings.EqualFold(u.Hostname(), "localhost") {
+		return fmt.Errorf("%w: must be publicly reachable", ErrInvalidURL)
+	}
+	if ip := net.ParseIP(u.Hostname()); ip != nil {
+		if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsUnspecified() {
+			return fmt.Errorf("%w: must be publicly reachable", ErrInvalidURL)
+		}
+	}
+	return nil
+}
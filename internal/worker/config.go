@@ -30,6 +30,13 @@ type Config struct {
 	// Stale jobs are recovered on worker startup (likely from crashed workers).
 	// Default: 10 minutes
 	StaleJobThreshold time.Duration
+
+	// ReservedHighPrioritySlots is how many of the Concurrency workers only
+	// take PriorityHigh jobs (analysis and email), so a backlog of reports
+	// or maintenance jobs can't make interactive requests wait. It must
+	// leave at least one worker for other jobs.
+	// Default: 0
+	ReservedHighPrioritySlots int
 }
 
 // DefaultConfig returns a Config with sensible default values.
@@ -64,5 +71,44 @@ func (c Config) Validate() error {
 	if c.StaleJobThreshold < 1*time.Minute {
 		return fmt.Errorf("stale job threshold must be at least 1 minute, got %v", c.StaleJobThreshold)
 	}
+	if c.ReservedHighPrioritySlots < 0 {
+		return fmt.Errorf("reserved high-priority slots cannot be negative, got %d", c.ReservedHighPrioritySlots)
+	}
+	if c.ReservedHighPrioritySlots >= c.Concurrency {
+		return fmt.Errorf("reserved high-priority slots (%d) must be fewer than concurrency (%d)", c.ReservedHighPrioritySlots, c.Concurrency)
+	}
+	return nil
+}
+
+// EnqueueLimits caps how much work users can put in the queue, so one user
+// cannot monopolize the worker. A zero value disables that limit.
+type EnqueueLimits struct {
+	// MaxInFlightPerUser is how many pending or running jobs of one type
+	// (inspection analysis or report generation) a user may have at once.
+	// Default: 3
+	MaxInFlightPerUser int
+
+	// MaxQueueDepth is how many pending jobs the queue may hold before new
+	// user-initiated jobs are refused.
+	// Default: 500
+	MaxQueueDepth int
+}
+
+// DefaultEnqueueLimits returns EnqueueLimits with sensible default values.
+func DefaultEnqueueLimits() EnqueueLimits {
+	return EnqueueLimits{
+		MaxInFlightPerUser: 3,
+		MaxQueueDepth:      500,
+	}
+}
+
+// Validate checks if the limits are valid.
+func (l EnqueueLimits) Validate() error {
+	if l.MaxInFlightPerUser < 0 {
+		return fmt.Errorf("max in-flight jobs per user cannot be negative, got %d", l.MaxInFlightPerUser)
+	}
+	if l.MaxQueueDepth < 0 {
+		return fmt.Errorf("max queue depth cannot be negative, got %d", l.MaxQueueDepth)
+	}
 	return nil
 }
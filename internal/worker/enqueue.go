@@ -2,11 +2,19 @@ package worker
 
 import (
 	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/DukeRupert/lukaut/internal/domain"
 	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/DukeRupert/lukaut/internal/requestid"
 	"github.com/google/uuid"
 )
 
@@ -20,39 +28,132 @@ type JobEnqueuer interface {
 	// EnqueueAnalyzeInspection enqueues a job to analyze an inspection's images.
 	EnqueueAnalyzeInspection(ctx context.Context, inspectionID, userID uuid.UUID, opts ...EnqueueOption) (repository.Job, error)
 
-	// EnqueueGenerateReport enqueues a job to generate a report for an inspection.
-	EnqueueGenerateReport(ctx context.Context, inspectionID, userID uuid.UUID, format, recipientEmail string, opts ...EnqueueOption) (repository.Job, error)
+	// EnqueueAnalyzeImages enqueues a job to analyze only the given images of an inspection.
+	// With force, images that were already analyzed are analyzed again.
+	EnqueueAnalyzeImages(ctx context.Context, inspectionID, userID uuid.UUID, imageIDs []uuid.UUID, force bool, opts ...EnqueueOption) (repository.Job, error)
+
+	// EnqueueGenerateReport enqueues a job to generate a report for an
+	// inspection, run as and counted against its owner.
+	EnqueueGenerateReport(ctx context.Context, inspectionID, ownerID uuid.UUID, format string, recipientEmails []string, opts ...EnqueueOption) (repository.Job, error)
+
+	// EnqueueSendEmail enqueues a job to render and send a transactional email.
+	EnqueueSendEmail(ctx context.Context, template, to string, data map[string]string, opts ...EnqueueOption) (repository.Job, error)
+
+	// EnqueueGeocodeInspection enqueues a job to geocode an inspection's address.
+	EnqueueGeocodeInspection(ctx context.Context, inspectionID, userID uuid.UUID, address string, opts ...EnqueueOption) (repository.Job, error)
+
+	// EnqueueFillInspectionWeather enqueues a job to fill in an inspection's empty weather fields.
+	EnqueueFillInspectionWeather(ctx context.Context, inspectionID, userID uuid.UUID, opts ...EnqueueOption) (repository.Job, error)
+
+	// EnqueueDeliverWebhook enqueues a job to send a webhook delivery to its endpoint.
+	EnqueueDeliverWebhook(ctx context.Context, deliveryID uuid.UUID, opts ...EnqueueOption) (repository.Job, error)
 }
 
 // jobEnqueuer implements the JobEnqueuer interface.
 type jobEnqueuer struct {
 	queries *repository.Queries
+	limits  EnqueueLimits
 }
 
-// NewJobEnqueuer creates a new JobEnqueuer.
-func NewJobEnqueuer(queries *repository.Queries) JobEnqueuer {
+// NewJobEnqueuer creates a new JobEnqueuer. Analysis and report jobs are
+// refused with an ERATELIMIT error once the user or the queue reaches limits.
+func NewJobEnqueuer(queries *repository.Queries, limits EnqueueLimits) JobEnqueuer {
 	return &jobEnqueuer{
 		queries: queries,
+		limits:  limits,
 	}
 }
 
-// EnqueueAnalyzeInspection enqueues an inspection analysis job.
+// EnqueueAnalyzeInspection enqueues an inspection analysis job. If the
+// inspection's analysis is already queued, that job is returned before the
+// limits are checked, since it doesn't add to the queue.
 func (e *jobEnqueuer) EnqueueAnalyzeInspection(ctx context.Context, inspectionID, userID uuid.UUID, opts ...EnqueueOption) (repository.Job, error) {
+	if job, ok, err := findActiveJob(ctx, e.queries, analyzeInspectionKey(inspectionID)); err != nil || ok {
+		return job, err
+	}
+	if err := CheckEnqueueLimits(ctx, e.queries, e.limits, JobTypeAnalyzeInspection, userID); err != nil {
+		return repository.Job{}, err
+	}
 	return EnqueueAnalyzeInspection(ctx, e.queries, inspectionID, userID, opts...)
 }
 
-// EnqueueGenerateReport enqueues a report generation job.
-func (e *jobEnqueuer) EnqueueGenerateReport(ctx context.Context, inspectionID, userID uuid.UUID, format, recipientEmail string, opts ...EnqueueOption) (repository.Job, error) {
-	return EnqueueGenerateReport(ctx, e.queries, inspectionID, userID, format, recipientEmail, opts...)
+// EnqueueAnalyzeImages enqueues an analysis job limited to specific images.
+func (e *jobEnqueuer) EnqueueAnalyzeImages(ctx context.Context, inspectionID, userID uuid.UUID, imageIDs []uuid.UUID, force bool, opts ...EnqueueOption) (repository.Job, error) {
+	if err := CheckEnqueueLimits(ctx, e.queries, e.limits, JobTypeAnalyzeInspection, userID); err != nil {
+		return repository.Job{}, err
+	}
+	return EnqueueAnalyzeImages(ctx, e.queries, inspectionID, userID, imageIDs, force, opts...)
+}
+
+// EnqueueGenerateReport enqueues a report generation job. Like analysis, a
+// report that is already queued is returned before the limits are checked.
+func (e *jobEnqueuer) EnqueueGenerateReport(ctx context.Context, inspectionID, ownerID uuid.UUID, format string, recipientEmails []string, opts ...EnqueueOption) (repository.Job, error) {
+	if job, ok, err := findActiveJob(ctx, e.queries, generateReportKey(inspectionID, ownerID, format, recipientEmails)); err != nil || ok {
+		return job, err
+	}
+	if err := CheckEnqueueLimits(ctx, e.queries, e.limits, JobTypeGenerateReport, ownerID); err != nil {
+		return repository.Job{}, err
+	}
+	return EnqueueGenerateReport(ctx, e.queries, inspectionID, ownerID, format, recipientEmails, opts...)
+}
+
+// EnqueueSendEmail enqueues an email delivery job.
+func (e *jobEnqueuer) EnqueueSendEmail(ctx context.Context, template, to string, data map[string]string, opts ...EnqueueOption) (repository.Job, error) {
+	return EnqueueSendEmail(ctx, e.queries, template, to, data, opts...)
+}
+
+// EnqueueGeocodeInspection enqueues an address geocoding job.
+func (e *jobEnqueuer) EnqueueGeocodeInspection(ctx context.Context, inspectionID, userID uuid.UUID, address string, opts ...EnqueueOption) (repository.Job, error) {
+	return EnqueueGeocodeInspection(ctx, e.queries, inspectionID, userID, address, opts...)
+}
+
+// EnqueueFillInspectionWeather enqueues a weather auto-fill job.
+func (e *jobEnqueuer) EnqueueFillInspectionWeather(ctx context.Context, inspectionID, userID uuid.UUID, opts ...EnqueueOption) (repository.Job, error) {
+	return EnqueueFillInspectionWeather(ctx, e.queries, inspectionID, userID, opts...)
+}
+
+// EnqueueDeliverWebhook enqueues a webhook delivery job.
+func (e *jobEnqueuer) EnqueueDeliverWebhook(ctx context.Context, deliveryID uuid.UUID, opts ...EnqueueOption) (repository.Job, error) {
+	return EnqueueDeliverWebhook(ctx, e.queries, deliveryID, opts...)
 }
 
 // Job type constants - these must match the JobHandler.Type() values
 const (
-	JobTypeAnalyzeInspection = "analyze_inspection"
-	JobTypeGenerateReport    = "generate_report"
+	JobTypeAnalyzeInspection     = "analyze_inspection"
+	JobTypeGenerateReport        = "generate_report"
+	JobTypeSendEmail             = "send_email"
+	JobTypeGeocodeInspection     = "geocode_inspection"
+	JobTypeVerificationReminders = "verification_reminders"
+	JobTypeFillInspectionWeather = "fill_inspection_weather"
+	JobTypeDeliverWebhook        = "deliver_webhook"
+	JobTypePurgeInspections      = "purge_inspections"
 )
 
-// Priority constants for job scheduling
+// SendEmailMaxAttempts is the default number of delivery attempts for email
+// jobs. With the worker's backoff this rides out roughly seven minutes of SMTP outage.
+const SendEmailMaxAttempts = 5
+
+// GeocodeMaxAttempts is the default number of attempts for geocoding jobs.
+// Rate-limited and failed lookups are retried with the worker's backoff.
+const GeocodeMaxAttempts = 5
+
+// FillWeatherMaxAttempts is the default number of attempts for weather
+// auto-fill jobs.
+const FillWeatherMaxAttempts = 3
+
+// FillWeatherDelay is how long a weather auto-fill job waits after the
+// inspection is created, so the geocoding job usually stores coordinates first.
+const FillWeatherDelay = time.Minute
+
+// DeliverWebhookMaxAttempts is the default number of attempts for webhook
+// delivery jobs. Each job attempt already retries the request a few times, so
+// these cover endpoints that are down for longer.
+const DeliverWebhookMaxAttempts = 4
+
+// Priority constants for job scheduling. Workers claim ready jobs by
+// priority, then oldest first. Interactive jobs (analysis, email) are high,
+// reports and webhooks normal, and maintenance (geocoding, weather,
+// reminders) low.
 const (
 	PriorityLow    = 0
 	PriorityNormal = 10
@@ -60,19 +161,81 @@ const (
 )
 
 // AnalyzeInspectionPayload is the payload for inspection analysis jobs.
+// When ImageIDs is set, only those images are analyzed and the inspection's
+// status is left unchanged (e.g. retrying a single failed image).
+// Only pending images are analyzed unless Force is set, in which case images
+// that were already analyzed are analyzed again and their violations updated.
 type AnalyzeInspectionPayload struct {
-	InspectionID uuid.UUID `json:"inspection_id"`
-	UserID       uuid.UUID `json:"user_id"`
+	InspectionID uuid.UUID   `json:"inspection_id"`
+	UserID       uuid.UUID   `json:"user_id"`
+	ImageIDs     []uuid.UUID `json:"image_ids,omitempty"`
+	Force        bool        `json:"force,omitempty"`
+	RequestID    string      `json:"request_id,omitempty"` // ID of the HTTP request that enqueued the job
 }
 
 // GenerateReportPayload is the payload for report generation jobs.
 type GenerateReportPayload struct {
-	InspectionID   uuid.UUID `json:"inspection_id"`
-	UserID         uuid.UUID `json:"user_id"`
-	Format         string    `json:"format"`          // "pdf" or "docx"
-	RecipientEmail string    `json:"recipient_email"` // Optional: email to send report to (e.g., client)
+	InspectionID    uuid.UUID `json:"inspection_id"`
+	UserID          uuid.UUID `json:"user_id"`                    // Inspection owner, even when a teammate requested the report
+	Format          string    `json:"format"`                     // "pdf" or "docx"
+	RecipientEmails []string  `json:"recipient_emails,omitempty"` // Optional: addresses to send the report to (e.g., client)
+	RequestID       string    `json:"request_id,omitempty"`       // ID of the HTTP request that enqueued the job
+
+	// RecipientEmail is the single recipient of jobs queued before reports
+	// could go to several addresses. Use Recipients.
+	RecipientEmail string `json:"recipient_email,omitempty"`
+}
+
+// Recipients returns the addresses to send the report to, including the
+// single recipient of jobs queued before RecipientEmails existed.
+func (p GenerateReportPayload) Recipients() []string {
+	if p.RecipientEmail != "" && !slices.Contains(p.RecipientEmails, p.RecipientEmail) {
+		return append([]string{p.RecipientEmail}, p.RecipientEmails...)
+	}
+	return p.RecipientEmails
+}
+
+// SendEmailPayload is the payload for email delivery jobs.
+type SendEmailPayload struct {
+	Template  string            `json:"template"` // One of the email.Template* names
+	To        string            `json:"to"`
+	Data      map[string]string `json:"data,omitempty"`
+	RequestID string            `json:"request_id,omitempty"` // ID of the HTTP request that enqueued the job
+}
+
+// GeocodeInspectionPayload is the payload for address geocoding jobs.
+// Address is the address as formatted by geocode.FormatAddress when the job
+// was enqueued; the job is skipped if the inspection's address has changed
+// since, because a newer job covers the new address.
+type GeocodeInspectionPayload struct {
+	InspectionID uuid.UUID `json:"inspection_id"`
+	UserID       uuid.UUID `json:"user_id"`
+	Address      string    `json:"address"`
+	RequestID    string    `json:"request_id,omitempty"` // ID of the HTTP request that enqueued the job
+}
+
+// FillInspectionWeatherPayload is the payload for weather auto-fill jobs.
+type FillInspectionWeatherPayload struct {
+	InspectionID uuid.UUID `json:"inspection_id"`
+	UserID       uuid.UUID `json:"user_id"`
+	RequestID    string    `json:"request_id,omitempty"` // ID of the HTTP request that enqueued the job
 }
 
+// DeliverWebhookPayload is the payload for webhook delivery jobs. The
+// endpoint and event body are read from the webhook_deliveries row.
+type DeliverWebhookPayload struct {
+	DeliveryID uuid.UUID `json:"delivery_id"`
+	RequestID  string    `json:"request_id,omitempty"` // ID of the HTTP request that enqueued the job
+}
+
+// VerificationRemindersPayload is the payload for the recurring verification
+// reminder sweep. The sweep takes no parameters.
+type VerificationRemindersPayload struct{}
+
+// PurgeInspectionsPayload is the payload for the recurring data retention
+// purge. The purge takes no parameters.
+type PurgeInspectionsPayload struct{}
+
 // EnqueueOption is a functional option for customizing job enqueue parameters.
 type EnqueueOption func(*repository.EnqueueJobParams)
 
@@ -90,6 +253,15 @@ func WithMaxAttempts(attempts int32) EnqueueOption {
 	}
 }
 
+// WithIdempotencyKey names what the job does, so that only one pending or
+// running job can do it: enqueueing a job whose key is held returns the
+// queued job instead of adding another.
+func WithIdempotencyKey(key string) EnqueueOption {
+	return func(p *repository.EnqueueJobParams) {
+		p.IdempotencyKey = sql.NullString{String: key, Valid: key != ""}
+	}
+}
+
 // WithDelay schedules the job to run after a delay.
 func WithDelay(delay time.Duration) EnqueueOption {
 	return func(p *repository.EnqueueJobParams) {
@@ -125,8 +297,20 @@ func EnqueueJob(
 		opt(&params)
 	}
 
-	// Enqueue the job
+	// Enqueue the job. No row comes back when a pending or running job holds
+	// the idempotency key; return that job instead, unless it finished in
+	// the meantime, in which case the key is free again.
 	job, err := queries.EnqueueJob(ctx, params)
+	if errors.Is(err, sql.ErrNoRows) && params.IdempotencyKey.Valid {
+		existing, ok, findErr := findActiveJob(ctx, queries, params.IdempotencyKey.String)
+		if findErr != nil {
+			return repository.Job{}, findErr
+		}
+		if ok {
+			return existing, nil
+		}
+		job, err = queries.EnqueueJob(ctx, params)
+	}
 	if err != nil {
 		return repository.Job{}, fmt.Errorf("enqueue job: %w", err)
 	}
@@ -134,8 +318,101 @@ func EnqueueJob(
 	return job, nil
 }
 
+// findActiveJob returns the pending or running job holding key, if any.
+func findActiveJob(ctx context.Context, queries *repository.Queries, key string) (repository.Job, bool, error) {
+	job, err := queries.GetActiveJobByIdempotencyKey(ctx, sql.NullString{String: key, Valid: true})
+	if errors.Is(err, sql.ErrNoRows) {
+		return repository.Job{}, false, nil
+	}
+	if err != nil {
+		return repository.Job{}, false, fmt.Errorf("find queued job: %w", err)
+	}
+	return job, true, nil
+}
+
+// analyzeInspectionKey is the idempotency key of an inspection's analysis.
+func analyzeInspectionKey(inspectionID uuid.UUID) string {
+	return JobTypeAnalyzeInspection + ":" + inspectionID.String()
+}
+
+// generateReportKey is the idempotency key of a report: the inspection's
+// owner, the inspection, and the format, so that the owner and their
+// teammates share one queued report. Reports for different recipients are
+// different jobs; the recipients are hashed, in any order, to keep the key
+// short.
+func generateReportKey(inspectionID, ownerID uuid.UUID, format string, recipientEmails []string) string {
+	key := fmt.Sprintf("%s:%s:%s:%s", JobTypeGenerateReport, ownerID, inspectionID, format)
+	if len(recipientEmails) == 0 {
+		return key
+	}
+	recipients := make([]string, len(recipientEmails))
+	for i, addr := range recipientEmails {
+		recipients[i] = strings.ToLower(addr)
+	}
+	slices.Sort(recipients)
+	sum := sha256.Sum256([]byte(strings.Join(recipients, "\n")))
+	return key + ":" + hex.EncodeToString(sum[:])
+}
+
+// CheckEnqueueLimits returns an ERATELIMIT error if the user already has
+// limits.MaxInFlightPerUser pending or running jobs of jobType, or if the
+// queue already holds limits.MaxQueueDepth pending jobs.
+func CheckEnqueueLimits(
+	ctx context.Context,
+	queries *repository.Queries,
+	limits EnqueueLimits,
+	jobType string,
+	userID uuid.UUID,
+) error {
+	const op = "worker.check_enqueue_limits"
+
+	if limits.MaxQueueDepth > 0 {
+		pending, err := queries.CountPendingJobs(ctx)
+		if err != nil {
+			return fmt.Errorf("count pending jobs: %w", err)
+		}
+		if pending >= int64(limits.MaxQueueDepth) {
+			return domain.Errorf(domain.ERATELIMIT, op, "The job queue is full right now. Please try again in a few minutes.")
+		}
+	}
+
+	if limits.MaxInFlightPerUser > 0 {
+		inFlight, err := queries.CountInFlightJobsByUserAndType(ctx, repository.CountInFlightJobsByUserAndTypeParams{
+			JobType: jobType,
+			UserID:  userID.String(),
+		})
+		if err != nil {
+			return fmt.Errorf("count in-flight jobs: %w", err)
+		}
+		if inFlight >= int64(limits.MaxInFlightPerUser) {
+			return domain.Errorf(domain.ERATELIMIT, op,
+				"You already have %d %s in progress. Wait for one to finish before starting another.",
+				inFlight, jobTypeNoun(jobType, inFlight))
+		}
+	}
+
+	return nil
+}
+
+// jobTypeNoun describes count jobs of the given type in user-facing messages.
+func jobTypeNoun(jobType string, count int64) string {
+	singular, plural := "job", "jobs"
+	switch jobType {
+	case JobTypeAnalyzeInspection:
+		singular, plural = "analysis", "analyses"
+	case JobTypeGenerateReport:
+		singular, plural = "report", "reports"
+	}
+	if count == 1 {
+		return singular
+	}
+	return plural
+}
+
 // EnqueueAnalyzeInspection enqueues a job to analyze an inspection's images.
-// This is typically called after images are uploaded to an inspection.
+// This is typically called after images are uploaded to an inspection. A user
+// is waiting on the result, so it runs at high priority. If the inspection's
+// analysis is already queued, that job is returned instead.
 func EnqueueAnalyzeInspection(
 	ctx context.Context,
 	queries *repository.Queries,
@@ -146,29 +423,215 @@ func EnqueueAnalyzeInspection(
 	payload := AnalyzeInspectionPayload{
 		InspectionID: inspectionID,
 		UserID:       userID,
+		RequestID:    requestid.FromContext(ctx),
 	}
 
+	opts = append([]EnqueueOption{WithPriority(PriorityHigh), WithIdempotencyKey(analyzeInspectionKey(inspectionID))}, opts...)
+	return EnqueueJob(ctx, queries, JobTypeAnalyzeInspection, payload, opts...)
+}
+
+// EnqueueAnalyzeImages enqueues a job to analyze specific images of an inspection.
+// This is used to retry images whose analysis failed, or with force to
+// reanalyze images that were already analyzed, without re-running the whole
+// inspection. Like a full analysis, it runs at high priority.
+func EnqueueAnalyzeImages(
+	ctx context.Context,
+	queries *repository.Queries,
+	inspectionID uuid.UUID,
+	userID uuid.UUID,
+	imageIDs []uuid.UUID,
+	force bool,
+	opts ...EnqueueOption,
+) (repository.Job, error) {
+	payload := AnalyzeInspectionPayload{
+		InspectionID: inspectionID,
+		UserID:       userID,
+		ImageIDs:     imageIDs,
+		Force:        force,
+		RequestID:    requestid.FromContext(ctx),
+	}
+
+	opts = append([]EnqueueOption{WithPriority(PriorityHigh)}, opts...)
 	return EnqueueJob(ctx, queries, JobTypeAnalyzeInspection, payload, opts...)
 }
 
 // EnqueueGenerateReport enqueues a job to generate a report for an inspection.
+// The job runs as ownerID, the inspection's owner, whoever requested it.
 // The format should be "pdf" or "docx".
-// The recipientEmail is optional - if provided, the report will be emailed to this address.
+// The recipientEmails are optional - if provided, the report will be emailed to these addresses.
+// While the same report for the same recipients is pending or running, that
+// job is returned instead.
 func EnqueueGenerateReport(
 	ctx context.Context,
 	queries *repository.Queries,
 	inspectionID uuid.UUID,
-	userID uuid.UUID,
+	ownerID uuid.UUID,
 	format string,
-	recipientEmail string,
+	recipientEmails []string,
 	opts ...EnqueueOption,
 ) (repository.Job, error) {
 	payload := GenerateReportPayload{
-		InspectionID:   inspectionID,
-		UserID:         userID,
-		Format:         format,
-		RecipientEmail: recipientEmail,
+		InspectionID:    inspectionID,
+		UserID:          ownerID,
+		Format:          format,
+		RecipientEmails: recipientEmails,
+		RequestID:       requestid.FromContext(ctx),
 	}
 
+	opts = append([]EnqueueOption{WithIdempotencyKey(generateReportKey(inspectionID, ownerID, format, recipientEmails))}, opts...)
 	return EnqueueJob(ctx, queries, JobTypeGenerateReport, payload, opts...)
 }
+
+// EnqueueSendEmail enqueues a job to render the named email template and send
+// it to the recipient. Failed deliveries are retried with backoff, up to
+// SendEmailMaxAttempts unless overridden with WithMaxAttempts.
+func EnqueueSendEmail(
+	ctx context.Context,
+	queries *repository.Queries,
+	template string,
+	to string,
+	data map[string]string,
+	opts ...EnqueueOption,
+) (repository.Job, error) {
+	payload := SendEmailPayload{
+		Template:  template,
+		To:        to,
+		Data:      data,
+		RequestID: requestid.FromContext(ctx),
+	}
+
+	opts = append([]EnqueueOption{WithPriority(PriorityHigh), WithMaxAttempts(SendEmailMaxAttempts)}, opts...)
+	return EnqueueJob(ctx, queries, JobTypeSendEmail, payload, opts...)
+}
+
+// EnqueueGeocodeInspection enqueues a job to look up the coordinates of an
+// inspection's address. Geocoding is not urgent, so it runs at low priority
+// and is retried up to GeocodeMaxAttempts unless overridden with
+// WithMaxAttempts.
+func EnqueueGeocodeInspection(
+	ctx context.Context,
+	queries *repository.Queries,
+	inspectionID uuid.UUID,
+	userID uuid.UUID,
+	address string,
+	opts ...EnqueueOption,
+) (repository.Job, error) {
+	payload := GeocodeInspectionPayload{
+		InspectionID: inspectionID,
+		UserID:       userID,
+		Address:      address,
+		RequestID:    requestid.FromContext(ctx),
+	}
+
+	opts = append([]EnqueueOption{WithPriority(PriorityLow), WithMaxAttempts(GeocodeMaxAttempts)}, opts...)
+	return EnqueueJob(ctx, queries, JobTypeGeocodeInspection, payload, opts...)
+}
+
+// EnqueueFillInspectionWeather enqueues a job to fill in an inspection's
+// empty weather fields from its location and date. It runs at low priority
+// after FillWeatherDelay, unless overridden with WithDelay, and is retried up
+// to FillWeatherMaxAttempts.
+func EnqueueFillInspectionWeather(
+	ctx context.Context,
+	queries *repository.Queries,
+	inspectionID uuid.UUID,
+	userID uuid.UUID,
+	opts ...EnqueueOption,
+) (repository.Job, error) {
+	payload := FillInspectionWeatherPayload{
+		InspectionID: inspectionID,
+		UserID:       userID,
+		RequestID:    requestid.FromContext(ctx),
+	}
+
+	opts = append([]EnqueueOption{
+		WithPriority(PriorityLow),
+		WithMaxAttempts(FillWeatherMaxAttempts),
+		WithDelay(FillWeatherDelay),
+	}, opts...)
+	return EnqueueJob(ctx, queries, JobTypeFillInspectionWeather, payload, opts...)
+}
+
+// EnqueueVerificationReminders enqueues a verification reminder sweep. The
+// sweep runs once: a failed run is not retried, because the next scheduled
+// run picks up the same users.
+func EnqueueVerificationReminders(
+	ctx context.Context,
+	queries *repository.Queries,
+	opts ...EnqueueOption,
+) (repository.Job, error) {
+	opts = append([]EnqueueOption{WithPriority(PriorityLow), WithMaxAttempts(1)}, opts...)
+	return EnqueueJob(ctx, queries, JobTypeVerificationReminders, VerificationRemindersPayload{}, opts...)
+}
+
+// ScheduleVerificationReminders enqueues a verification reminder sweep unless
+// one is already pending, so restarts do not start a second recurring chain.
+// It reports whether a job was enqueued.
+func ScheduleVerificationReminders(
+	ctx context.Context,
+	queries *repository.Queries,
+	opts ...EnqueueOption,
+) (bool, error) {
+	pending, err := queries.HasPendingJobOfType(ctx, JobTypeVerificationReminders)
+	if err != nil {
+		return false, fmt.Errorf("check pending verification reminders: %w", err)
+	}
+	if pending {
+		return false, nil
+	}
+	if _, err := EnqueueVerificationReminders(ctx, queries, opts...); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// EnqueuePurgeInspections enqueues a data retention purge. The purge runs
+// once: a failed run is not retried, because the next scheduled run picks
+// up the same inspections.
+func EnqueuePurgeInspections(
+	ctx context.Context,
+	queries *repository.Queries,
+	opts ...EnqueueOption,
+) (repository.Job, error) {
+	opts = append([]EnqueueOption{WithPriority(PriorityLow), WithMaxAttempts(1)}, opts...)
+	return EnqueueJob(ctx, queries, JobTypePurgeInspections, PurgeInspectionsPayload{}, opts...)
+}
+
+// SchedulePurgeInspections enqueues a data retention purge unless one is
+// already pending, so restarts do not start a second recurring chain.
+// It reports whether a job was enqueued.
+func SchedulePurgeInspections(
+	ctx context.Context,
+	queries *repository.Queries,
+	opts ...EnqueueOption,
+) (bool, error) {
+	pending, err := queries.HasPendingJobOfType(ctx, JobTypePurgeInspections)
+	if err != nil {
+		return false, fmt.Errorf("check pending inspection purge: %w", err)
+	}
+	if pending {
+		return false, nil
+	}
+	if _, err := EnqueuePurgeInspections(ctx, queries, opts...); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// EnqueueDeliverWebhook enqueues a job to send a webhook delivery. Failed
+// deliveries are retried with the worker's backoff, up to
+// DeliverWebhookMaxAttempts unless overridden with WithMaxAttempts.
+func EnqueueDeliverWebhook(
+	ctx context.Context,
+	queries *repository.Queries,
+	deliveryID uuid.UUID,
+	opts ...EnqueueOption,
+) (repository.Job, error) {
+	payload := DeliverWebhookPayload{
+		DeliveryID: deliveryID,
+		RequestID:  requestid.FromContext(ctx),
+	}
+
+	opts = append([]EnqueueOption{WithMaxAttempts(DeliverWebhookMaxAttempts)}, opts...)
+	return EnqueueJob(ctx, queries, JobTypeDeliverWebhook, payload, opts...)
+}
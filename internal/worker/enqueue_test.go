@@ -0,0 +1,398 @@
+package worker
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DukeRupert/lukaut/internal/domain"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/DukeRupert/lukaut/internal/testutil/fakedb"
+	"github.com/google/uuid"
+)
+
+// =============================================================================
+// In-Memory Job Queue
+// =============================================================================
+
+// fakeQueueJob is a job row held by fakeQueueDB.
+type fakeQueueJob struct {
+	id       string
+	jobType  string
+	userID   string
+	status   string
+	priority int64
+	key      string         // Idempotency key, if any
+	values   []driver.Value // The row as returned by EnqueueJob
+	result   []byte         // Set when the worker finishes the job
+}
+
+// fakeQueueDB is a fake database holding the jobs table, in the order
+// the jobs were enqueued. It answers the sqlc queries used when enqueueing
+// and claiming jobs.
+type fakeQueueDB struct {
+	jobs []fakeQueueJob
+}
+
+// countBy counts the jobs matching keep.
+func (f *fakeQueueDB) countBy(keep func(fakeQueueJob) bool) int64 {
+	var n int64
+	for _, j := range f.jobs {
+		if keep(j) {
+			n++
+		}
+	}
+	return n
+}
+
+// activeJob returns the index of the pending or running job holding key, or
+// -1 if there is none.
+func (f *fakeQueueDB) activeJob(key string) int {
+	for i, j := range f.jobs {
+		if j.key == key && (j.status == "pending" || j.status == "running") {
+			return i
+		}
+	}
+	return -1
+}
+
+func (f *fakeQueueDB) Query(q fakedb.Query) (*fakedb.Rows, error) {
+	args := q.Args
+	name := q.Name
+	switch name {
+	case "CountPendingJobs":
+		n := f.countBy(func(j fakeQueueJob) bool { return j.status == "pending" })
+		return &fakedb.Rows{Columns: 1, Values: [][]driver.Value{{n}}}, nil
+	case "CountInFlightJobsByUserAndType":
+		jobType, userID := args[0].Value.(string), args[1].Value.(string)
+		n := f.countBy(func(j fakeQueueJob) bool {
+			return j.jobType == jobType && j.userID == userID && (j.status == "pending" || j.status == "running")
+		})
+		return &fakedb.Rows{Columns: 1, Values: [][]driver.Value{{n}}}, nil
+	case "EnqueueJob":
+		// The unique index allows one pending or running job per key
+		key, _ := args[5].Value.(string)
+		if key != "" && f.activeJob(key) >= 0 {
+			return &fakedb.Rows{Columns: 15}, nil
+		}
+		payload := args[1].Value.([]byte)
+		var p struct {
+			UserID string `json:"user_id"`
+		}
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return nil, err
+		}
+		job := fakeQueueJob{id: uuid.NewString(), jobType: args[0].Value.(string), userID: p.UserID, status: "pending", priority: args[2].Value.(int64), key: key}
+		job.values = []driver.Value{
+			job.id, job.jobType, payload, job.status,
+			args[2].Value, int64(0), args[3].Value, args[4].Value,
+			nil, nil, nil, time.Now(),
+			nil, nil, args[5].Value,
+		}
+		f.jobs = append(f.jobs, job)
+		return &fakedb.Rows{Columns: len(job.values), Values: [][]driver.Value{job.values}}, nil
+	case "GetActiveJobByIdempotencyKey":
+		if i := f.activeJob(args[0].Value.(string)); i >= 0 {
+			return &fakedb.Rows{Columns: 15, Values: [][]driver.Value{f.jobs[i].values}}, nil
+		}
+		return &fakedb.Rows{Columns: 15}, nil
+	case "DequeueJob":
+		// Highest priority first, then oldest first, as ORDER BY priority DESC, created_at ASC
+		minPriority := args[0].Value.(int64)
+		next := -1
+		for i, j := range f.jobs {
+			if j.status == "pending" && j.priority >= minPriority && (next < 0 || j.priority > f.jobs[next].priority) {
+				next = i
+			}
+		}
+		if next < 0 {
+			return &fakedb.Rows{Columns: 15}, nil
+		}
+		return &fakedb.Rows{Columns: 15, Values: [][]driver.Value{f.jobs[next].values}}, nil
+	}
+	return nil, fmt.Errorf("fakeQueueDB: unexpected query %q", name)
+}
+
+// makes when a job finishes.
+func (f *fakeQueueDB) Exec(q fakedb.Query) (int64, error) {
+	args := q.Args
+	name := q.Name
+	switch name {
+	case "UpdateJobStarted":
+		for i := range f.jobs {
+			if f.jobs[i].id == args[0].Value {
+				f.jobs[i].status = "running"
+			}
+		}
+		return 1, nil
+	case "UpdateJobCompleted", "UpdateJobFailed", "UpdateJobPermanentlyFailed":
+		result := args[len(args)-1].Value
+		for i := range f.jobs {
+			if f.jobs[i].id == args[0].Value && result != nil {
+				f.jobs[i].result = result.([]byte)
+			}
+		}
+		return 1, nil
+	}
+	return 0, fmt.Errorf("fakeQueueDB: unexpected exec %q", name)
+}
+
+func newFakeQueue(t *testing.T, limits EnqueueLimits) (*fakeQueueDB, JobEnqueuer) {
+	t.Helper()
+	f := &fakeQueueDB{}
+	db := fakedb.Open(f)
+	t.Cleanup(func() { _ = db.Close() })
+	return f, NewJobEnqueuer(repository.New(db), limits)
+}
+
+// =============================================================================
+// Enqueue Limit Tests
+// =============================================================================
+
+func TestEnqueueAnalyzeInspection_PerUserCap(t *testing.T) {
+	ctx := context.Background()
+	_, enqueuer := newFakeQueue(t, EnqueueLimits{MaxInFlightPerUser: 2})
+	busyUser, otherUser := uuid.New(), uuid.New()
+
+	for i := 0; i < 2; i++ {
+		if _, err := enqueuer.EnqueueAnalyzeInspection(ctx, uuid.New(), busyUser); err != nil {
+			t.Fatalf("enqueue %d: unexpected error: %v", i+1, err)
+		}
+	}
+
+	_, err := enqueuer.EnqueueAnalyzeInspection(ctx, uuid.New(), busyUser)
+	if domain.ErrorCode(err) != domain.ERATELIMIT {
+		t.Fatalf("expected third enqueue to be rate limited, got %v", err)
+	}
+	if !strings.Contains(domain.ErrorMessage(err), "2 analyses in progress") {
+		t.Errorf("expected message to name the in-flight analyses, got %q", domain.ErrorMessage(err))
+	}
+
+	if _, err := enqueuer.EnqueueAnalyzeInspection(ctx, uuid.New(), otherUser); err != nil {
+		t.Errorf("expected another user to be unaffected, got %v", err)
+	}
+	if _, err := enqueuer.EnqueueGenerateReport(ctx, uuid.New(), busyUser, "pdf", nil); err != nil {
+		t.Errorf("expected report jobs to have their own cap, got %v", err)
+	}
+}
+
+func TestEnqueueAnalyzeInspection_FinishedJobsFreeTheCap(t *testing.T) {
+	ctx := context.Background()
+	f, enqueuer := newFakeQueue(t, EnqueueLimits{MaxInFlightPerUser: 1})
+	userID := uuid.New()
+
+	if _, err := enqueuer.EnqueueAnalyzeInspection(ctx, uuid.New(), userID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := enqueuer.EnqueueAnalyzeImages(ctx, uuid.New(), userID, []uuid.UUID{uuid.New()}, false); domain.ErrorCode(err) != domain.ERATELIMIT {
+		t.Fatalf("expected retry of a single image to count toward the cap, got %v", err)
+	}
+
+	f.jobs[0].status = "completed"
+
+	if _, err := enqueuer.EnqueueAnalyzeInspection(ctx, uuid.New(), userID); err != nil {
+		t.Errorf("expected enqueue to succeed once the first job finished, got %v", err)
+	}
+}
+
+func TestEnqueueGenerateReport_QueueDepthLimit(t *testing.T) {
+	ctx := context.Background()
+	f, enqueuer := newFakeQueue(t, EnqueueLimits{MaxQueueDepth: 2})
+	f.jobs = []fakeQueueJob{
+		{jobType: JobTypeSendEmail, status: "pending"},
+		{jobType: JobTypeGeocodeInspection, status: "pending"},
+	}
+
+	_, err := enqueuer.EnqueueGenerateReport(ctx, uuid.New(), uuid.New(), "pdf", nil)
+	if domain.ErrorCode(err) != domain.ERATELIMIT {
+		t.Fatalf("expected full queue to refuse the report, got %v", err)
+	}
+
+	// System jobs are not user-initiated and are never refused
+	if _, err := enqueuer.EnqueueSendEmail(ctx, "welcome", "pat@example.com", nil); err != nil {
+		t.Errorf("expected email to be enqueued despite the full queue, got %v", err)
+	}
+}
+
+func TestEnqueueAnalyzeInspection_ZeroLimitsAreUnlimited(t *testing.T) {
+	ctx := context.Background()
+	_, enqueuer := newFakeQueue(t, EnqueueLimits{})
+	userID := uuid.New()
+
+	for i := 0; i < 10; i++ {
+		if _, err := enqueuer.EnqueueAnalyzeInspection(ctx, uuid.New(), userID); err != nil {
+			t.Fatalf("enqueue %d: unexpected error: %v", i+1, err)
+		}
+	}
+}
+
+// =============================================================================
+// Idempotency Tests
+// =============================================================================
+
+func TestEnqueueGenerateReport_ReturnsPendingDuplicate(t *testing.T) {
+	ctx := context.Background()
+	f, enqueuer := newFakeQueue(t, EnqueueLimits{})
+	inspectionID, userID := uuid.New(), uuid.New()
+
+	first, err := enqueuer.EnqueueGenerateReport(ctx, inspectionID, userID, "pdf", []string{"a@example.com", "b@example.com"})
+	if err != nil {
+		t.Fatalf("first enqueue: unexpected error: %v", err)
+	}
+	second, err := enqueuer.EnqueueGenerateReport(ctx, inspectionID, userID, "pdf", []string{"b@example.com", "a@example.com"})
+	if err != nil {
+		t.Fatalf("second enqueue: unexpected error: %v", err)
+	}
+
+	if second.ID != first.ID {
+		t.Errorf("expected the rapid second enqueue to return job %s, got %s", first.ID, second.ID)
+	}
+	if len(f.jobs) != 1 {
+		t.Errorf("expected one job in the queue, got %d", len(f.jobs))
+	}
+}
+
+func TestEnqueueGenerateReport_DuplicateSkipsLimits(t *testing.T) {
+	ctx := context.Background()
+	_, enqueuer := newFakeQueue(t, EnqueueLimits{MaxInFlightPerUser: 1})
+	inspectionID, userID := uuid.New(), uuid.New()
+
+	first, err := enqueuer.EnqueueGenerateReport(ctx, inspectionID, userID, "pdf", nil)
+	if err != nil {
+		t.Fatalf("first enqueue: unexpected error: %v", err)
+	}
+	second, err := enqueuer.EnqueueGenerateReport(ctx, inspectionID, userID, "pdf", nil)
+	if err != nil {
+		t.Fatalf("expected the duplicate not to be rate limited, got %v", err)
+	}
+	if second.ID != first.ID {
+		t.Errorf("expected job %s, got %s", first.ID, second.ID)
+	}
+}
+
+func TestEnqueueGenerateReport_DifferentRequestsAreNotDuplicates(t *testing.T) {
+	ctx := context.Background()
+	f, enqueuer := newFakeQueue(t, EnqueueLimits{})
+	inspectionID, ownerID := uuid.New(), uuid.New()
+
+	requests := []struct {
+		name         string
+		inspectionID uuid.UUID
+		ownerID      uuid.UUID
+		format       string
+		recipients   []string
+	}{
+		{"original", inspectionID, ownerID, "pdf", nil},
+		{"other format", inspectionID, ownerID, "docx", nil},
+		{"other recipients", inspectionID, ownerID, "pdf", []string{"client@example.com"}},
+		{"other inspection", uuid.New(), ownerID, "pdf", nil},
+		{"other owner", inspectionID, uuid.New(), "pdf", nil},
+	}
+	for _, req := range requests {
+		if _, err := enqueuer.EnqueueGenerateReport(ctx, req.inspectionID, req.ownerID, req.format, req.recipients); err != nil {
+			t.Fatalf("%s: unexpected error: %v", req.name, err)
+		}
+	}
+
+	if len(f.jobs) != len(requests) {
+		t.Errorf("expected %d jobs, got %d", len(requests), len(f.jobs))
+	}
+}
+
+func TestEnqueueGenerateReport_FinishedJobIsNotReused(t *testing.T) {
+	ctx := context.Background()
+	f, enqueuer := newFakeQueue(t, EnqueueLimits{})
+	inspectionID, userID := uuid.New(), uuid.New()
+
+	first, err := enqueuer.EnqueueGenerateReport(ctx, inspectionID, userID, "pdf", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	f.jobs[0].status = "completed"
+
+	second, err := enqueuer.EnqueueGenerateReport(ctx, inspectionID, userID, "pdf", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if second.ID == first.ID {
+		t.Error("expected a new job once the first one finished")
+	}
+}
+
+func TestEnqueueAnalyzeInspection_ReturnsQueuedJob(t *testing.T) {
+	ctx := context.Background()
+	f, enqueuer := newFakeQueue(t, EnqueueLimits{MaxInFlightPerUser: 1})
+	inspectionID, userID := uuid.New(), uuid.New()
+
+	first, err := enqueuer.EnqueueAnalyzeInspection(ctx, inspectionID, userID)
+	if err != nil {
+		t.Fatalf("first enqueue: unexpected error: %v", err)
+	}
+	f.jobs[0].status = "running"
+
+	second, err := enqueuer.EnqueueAnalyzeInspection(ctx, inspectionID, userID)
+	if err != nil {
+		t.Fatalf("expected the repeated enqueue to succeed, got %v", err)
+	}
+	if second.ID != first.ID {
+		t.Errorf("expected the running job %s, got %s", first.ID, second.ID)
+	}
+
+	// Single-image retries are not deduplicated against the whole analysis
+	if _, err := EnqueueAnalyzeImages(ctx, repository.New(fakedb.Open(f)), inspectionID, userID, []uuid.UUID{uuid.New()}, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(f.jobs) != 2 {
+		t.Errorf("expected the image retry to be queued, got %d jobs", len(f.jobs))
+	}
+}
+
+func TestEnqueue_ParallelEnqueuesCreateOneJob(t *testing.T) {
+	ctx := context.Background()
+	f, enqueuer := newFakeQueue(t, EnqueueLimits{})
+	inspectionID, userID := uuid.New(), uuid.New()
+
+	const requests = 10
+	var wg sync.WaitGroup
+	ids := make(chan uuid.UUID, 2*requests)
+	errs := make(chan error, 2*requests)
+	for i := 0; i < requests; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			job, err := enqueuer.EnqueueAnalyzeInspection(ctx, inspectionID, userID)
+			errs <- err
+			ids <- job.ID
+		}()
+		go func() {
+			defer wg.Done()
+			job, err := enqueuer.EnqueueGenerateReport(ctx, inspectionID, userID, "pdf", nil)
+			errs <- err
+			ids <- job.ID
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	close(ids)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(f.jobs) != 2 {
+		t.Fatalf("expected one analysis and one report job, got %d jobs", len(f.jobs))
+	}
+	seen := map[uuid.UUID]bool{}
+	for id := range ids {
+		seen[id] = true
+	}
+	if len(seen) != 2 {
+		t.Errorf("expected every enqueue to return one of the two jobs, got %d distinct IDs", len(seen))
+	}
+}
@@ -19,6 +19,15 @@ type JobHandler interface {
 	Handle(ctx context.Context, payload []byte) error
 }
 
+// FailureHandler is implemented by job handlers that need to react when a job
+// fails for the last time, for example to tell the user. The worker calls
+// OnPermanentFailure after marking the job failed, either because the handler
+// returned a PermanentError or because the job ran out of attempts. It is not
+// called for failures that will be retried.
+type FailureHandler interface {
+	OnPermanentFailure(ctx context.Context, payload []byte, jobErr error)
+}
+
 // PermanentError wraps an error to indicate it should not be retried.
 // Jobs that fail with a PermanentError are immediately marked as 'failed'
 // instead of being rescheduled for retry.
@@ -0,0 +1,60 @@
+package worker
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// resultKey is the context key of the running job's result.
+type resultKey struct{}
+
+// jobResult holds the result a handler reports for its job.
+type jobResult struct {
+	mu    sync.Mutex
+	value any
+}
+
+// SetResult records what the running job produced, such as the ID of a
+// report it generated. The worker stores the result as JSON on the job row,
+// whether the job then succeeds or fails, and GET /jobs/{id} reports it.
+// Setting a result again replaces it. Outside a job (e.g. when a handler is
+// called directly in a test without ContextWithResult) it does nothing.
+func SetResult(ctx context.Context, result any) {
+	r, ok := ctx.Value(resultKey{}).(*jobResult)
+	if !ok {
+		return
+	}
+	r.mu.Lock()
+	r.value = result
+	r.mu.Unlock()
+}
+
+// ContextWithResult returns a context in which SetResult records a result,
+// and a function that returns the last one recorded, or nil.
+func ContextWithResult(ctx context.Context) (context.Context, func() any) {
+	r := &jobResult{}
+	return context.WithValue(ctx, resultKey{}, r), func() any {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return r.value
+	}
+}
+
+// AnalyzeInspectionResult is the result of analyze_inspection jobs.
+type AnalyzeInspectionResult struct {
+	InspectionID    uuid.UUID `json:"inspection_id"`
+	ImagesAnalyzed  int       `json:"images_analyzed"`
+	ImagesFailed    int       `json:"images_failed"`
+	ViolationsFound int       `json:"violations_found"`
+	Errors          []string  `json:"errors,omitempty"` // Why images failed, one entry per distinct reason
+}
+
+// GenerateReportResult is the result of generate_report jobs.
+type GenerateReportResult struct {
+	ReportID       uuid.UUID `json:"report_id"`
+	InspectionID   uuid.UUID `json:"inspection_id"`
+	Format         string    `json:"format"` // "pdf" or "docx"
+	ViolationCount int       `json:"violation_count"`
+}
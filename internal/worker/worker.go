@@ -3,14 +3,19 @@ package worker
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"log/slog"
+	"math"
 	"sync"
 	"time"
 
+	"github.com/DukeRupert/lukaut/internal/jobevents"
 	"github.com/DukeRupert/lukaut/internal/metrics"
 	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/DukeRupert/lukaut/internal/requestid"
 	"github.com/google/uuid"
+	"github.com/sqlc-dev/pqtype"
 )
 
 // Worker manages background job processing with concurrent workers.
@@ -20,6 +25,7 @@ type Worker struct {
 	handlers map[string]JobHandler
 	config   Config
 	logger   *slog.Logger
+	notifier Notifier // Optional: told about job state changes
 
 	// Synchronization
 	wg     sync.WaitGroup
@@ -43,6 +49,19 @@ func New(db *sql.DB, queries *repository.Queries, config Config, logger *slog.Lo
 	}, nil
 }
 
+// Notifier is told about job state changes, e.g. to push analysis progress
+// to browsers. *jobevents.Broker implements it.
+type Notifier interface {
+	Publish(e jobevents.Event)
+}
+
+// WithNotifier sets the notifier told when jobs about an inspection start,
+// complete, or fail. Call this before Start().
+func (w *Worker) WithNotifier(n Notifier) *Worker {
+	w.notifier = n
+	return w
+}
+
 // Register adds a job handler to the worker.
 // The handler's Type() must be unique. Call this before Start().
 func (w *Worker) Register(handler JobHandler) {
@@ -62,13 +81,17 @@ func (w *Worker) Start(ctx context.Context) {
 		w.logger.Error("Failed to recover stale jobs", "error", err)
 	}
 
-	// Start worker goroutines
+	// Start worker goroutines; the reserved ones only claim high-priority jobs
 	for i := 0; i < w.config.Concurrency; i++ {
+		minPriority := int32(math.MinInt32)
+		if i < w.config.ReservedHighPrioritySlots {
+			minPriority = PriorityHigh
+		}
 		w.wg.Add(1)
-		go w.runWorker(ctx, i+1)
+		go w.runWorker(ctx, i+1, minPriority)
 	}
 
-	w.logger.Info("Worker started", "concurrency", w.config.Concurrency)
+	w.logger.Info("Worker started", "concurrency", w.config.Concurrency, "reserved_high_priority", w.config.ReservedHighPrioritySlots)
 }
 
 // Stop signals all workers to stop and waits for them to finish.
@@ -109,8 +132,8 @@ func (w *Worker) recoverStaleJobs(ctx context.Context) error {
 }
 
 // runWorker is the main loop for a worker goroutine.
-// It continuously polls for jobs until stopCh is closed.
-func (w *Worker) runWorker(ctx context.Context, workerID int) {
+// It continuously polls for jobs of at least minPriority until stopCh is closed.
+func (w *Worker) runWorker(ctx context.Context, workerID int, minPriority int32) {
 	defer w.wg.Done()
 
 	logger := w.logger.With("worker_id", workerID)
@@ -125,7 +148,7 @@ func (w *Worker) runWorker(ctx context.Context, workerID int) {
 			logger.Debug("Worker stopping")
 			return
 		case <-ticker.C:
-			if err := w.processNextJob(ctx, logger); err != nil {
+			if err := w.processNextJob(ctx, logger, minPriority); err != nil {
 				if err == sql.ErrNoRows {
 					// No jobs available, this is normal
 					continue
@@ -136,9 +159,9 @@ func (w *Worker) runWorker(ctx context.Context, workerID int) {
 	}
 }
 
-// processNextJob attempts to dequeue and execute a single job.
-// Returns sql.ErrNoRows if no jobs are available.
-func (w *Worker) processNextJob(ctx context.Context, logger *slog.Logger) error {
+// processNextJob attempts to dequeue and execute a single job of at least
+// minPriority. Returns sql.ErrNoRows if no such jobs are available.
+func (w *Worker) processNextJob(ctx context.Context, logger *slog.Logger, minPriority int32) error {
 	// Start a transaction for dequeuing
 	tx, err := w.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -149,7 +172,7 @@ func (w *Worker) processNextJob(ctx context.Context, logger *slog.Logger) error
 	qtx := w.queries.WithTx(tx)
 
 	// Dequeue the next job
-	job, err := qtx.DequeueJob(ctx)
+	job, err := qtx.DequeueJob(ctx, minPriority)
 	if err != nil {
 		return err // Will be sql.ErrNoRows if no jobs available
 	}
@@ -164,78 +187,179 @@ func (w *Worker) processNextJob(ctx context.Context, logger *slog.Logger) error
 		return fmt.Errorf("commit dequeue: %w", err)
 	}
 
+	w.notify(job, jobevents.StateRunning)
+
 	// Execute the job (outside the transaction)
 	logger = logger.With("job_id", job.ID, "job_type", job.JobType, "attempt", job.Attempts+1)
-	logger.Info("Processing job")
+	if id := payloadRequestID(job.Payload); id != "" {
+		// Correlate the job's log lines with the request that enqueued it
+		ctx = requestid.NewContext(ctx, id)
+	}
+	logger.InfoContext(ctx, "Processing job")
 
 	startTime := time.Now()
-	if err := w.executeJob(ctx, job, logger); err != nil {
-		logger.Error("Job failed", "error", err)
-		w.markJobFailed(ctx, job.ID, job.JobType, err)
+	result, err := w.executeJob(ctx, job, logger)
+	if err != nil {
+		logger.ErrorContext(ctx, "Job failed", "error", err)
+		w.markJobFailed(ctx, job, result, err)
 		return fmt.Errorf("execute job: %w", err)
 	}
 
 	duration := time.Since(startTime)
-	logger.Info("Job completed")
-	if err := w.markJobCompleted(ctx, job.ID, job.JobType, duration); err != nil {
-		logger.Error("Failed to mark job as completed", "error", err)
+	logger.InfoContext(ctx, "Job completed")
+	if err := w.markJobCompleted(ctx, job, result, duration); err != nil {
+		logger.ErrorContext(ctx, "Failed to mark job as completed", "error", err)
 		return err
 	}
 
 	return nil
 }
 
-// executeJob runs the appropriate handler for the job with a timeout context.
-func (w *Worker) executeJob(ctx context.Context, job repository.Job, logger *slog.Logger) error {
+// executeJob runs the appropriate handler for the job with a timeout context
+// and returns the result the handler set with SetResult, encoded for the
+// job row, along with its error.
+func (w *Worker) executeJob(ctx context.Context, job repository.Job, logger *slog.Logger) (pqtype.NullRawMessage, error) {
 	// Find the handler for this job type
 	handler, ok := w.handlers[job.JobType]
 	if !ok {
 		// No handler registered - this is a permanent error
-		return NewPermanentError(fmt.Errorf("no handler registered for job type: %s", job.JobType))
+		return pqtype.NullRawMessage{}, NewPermanentError(fmt.Errorf("no handler registered for job type: %s", job.JobType))
 	}
 
 	// Create a context with timeout
 	jobCtx, cancel := context.WithTimeout(ctx, w.config.JobTimeout)
 	defer cancel()
+	jobCtx, result := ContextWithResult(jobCtx)
 
 	// Execute the handler
-	if err := handler.Handle(jobCtx, job.Payload); err != nil {
-		return err
-	}
+	err := handler.Handle(jobCtx, job.Payload)
+	return encodeResult(ctx, result(), logger), err
+}
 
-	return nil
+// encodeResult encodes a job result for the job row. A missing result, or
+// one that can't be encoded, is stored as NULL; the job's outcome doesn't
+// depend on it.
+func encodeResult(ctx context.Context, result any, logger *slog.Logger) pqtype.NullRawMessage {
+	if result == nil {
+		return pqtype.NullRawMessage{}
+	}
+	raw, err := json.Marshal(result)
+	if err != nil {
+		logger.WarnContext(ctx, "Failed to encode job result", "error", err)
+		return pqtype.NullRawMessage{}
+	}
+	return pqtype.NullRawMessage{RawMessage: raw, Valid: true}
 }
 
-// markJobCompleted marks a job as successfully completed.
-func (w *Worker) markJobCompleted(ctx context.Context, jobID uuid.UUID, jobType string, duration time.Duration) error {
-	if err := w.queries.UpdateJobCompleted(ctx, jobID); err != nil {
+// markJobCompleted marks a job as successfully completed with its result.
+func (w *Worker) markJobCompleted(ctx context.Context, job repository.Job, result pqtype.NullRawMessage, duration time.Duration) error {
+	if err := w.queries.UpdateJobCompleted(ctx, repository.UpdateJobCompletedParams{
+		ID:     job.ID,
+		Result: result,
+	}); err != nil {
 		return fmt.Errorf("update job completed: %w", err)
 	}
-	metrics.JobCompleted(jobType, duration)
+	metrics.JobCompleted(job.JobType, duration)
+	w.notify(job, jobevents.StateCompleted)
 	return nil
 }
 
-// markJobFailed marks a job as failed.
-// If the error is permanent or max attempts reached, the job is marked as 'failed'.
-// Otherwise, it's rescheduled with exponential backoff.
-func (w *Worker) markJobFailed(ctx context.Context, jobID uuid.UUID, jobType string, jobErr error) {
-	errorMessage := jobErr.Error()
+// markJobFailed marks a job as failed, keeping any result it reported.
+// If the error is permanent or max attempts reached, the job is marked as 'failed'
+// and reported as permanently failed, and a handler implementing FailureHandler
+// is notified. Otherwise, it's rescheduled with exponential backoff.
+func (w *Worker) markJobFailed(ctx context.Context, job repository.Job, result pqtype.NullRawMessage, jobErr error) {
+	errorMessage := sql.NullString{String: jobErr.Error(), Valid: true}
+
+	// job.Attempts was read before UpdateJobStarted incremented it
+	attempt := job.Attempts + 1
+	permanent := IsPermanent(jobErr)
+
+	var err error
+	if permanent {
+		err = w.queries.UpdateJobPermanentlyFailed(ctx, repository.UpdateJobPermanentlyFailedParams{
+			ID:           job.ID,
+			ErrorMessage: errorMessage,
+			Result:       result,
+		})
+	} else {
+		err = w.queries.UpdateJobFailed(ctx, repository.UpdateJobFailedParams{
+			ID:           job.ID,
+			ErrorMessage: errorMessage,
+			Result:       result,
+		})
+	}
+	if err != nil {
+		w.logger.ErrorContext(ctx, "Failed to mark job as failed", "job_id", job.ID, "error", err)
+	}
+	metrics.JobFailed(job.JobType)
+
+	if permanent || attempt >= job.MaxAttempts {
+		w.logger.ErrorContext(ctx, "Job permanently failed, will not retry",
+			"job_id", job.ID,
+			"job_type", job.JobType,
+			"attempts", attempt,
+			"permanent_error", permanent,
+			"error", jobErr,
+		)
+		metrics.JobPermanentlyFailed(job.JobType)
+		w.notifyPermanentFailure(ctx, job, jobErr)
+		w.notify(job, jobevents.StateFailed)
+		return
+	}
+
+	metrics.JobRetried(job.JobType)
+	w.notify(job, jobevents.StateRetrying)
+}
+
+// notify tells the notifier, if any, that a job about an inspection changed
+// state. Jobs whose payload names no inspection are not reported.
+func (w *Worker) notify(job repository.Job, state jobevents.State) {
+	if w.notifier == nil {
+		return
+	}
+	inspectionID := payloadInspectionID(job.Payload)
+	if inspectionID == uuid.Nil {
+		return
+	}
+	w.notifier.Publish(jobevents.Event{InspectionID: inspectionID, JobType: job.JobType, State: state})
+}
 
-	// Check if this is a permanent error
-	if IsPermanent(jobErr) {
-		w.logger.Warn("Job failed with permanent error, will not retry", "job_id", jobID, "error", errorMessage)
+// notifyPermanentFailure calls the job's handler if it implements
+// FailureHandler.
+func (w *Worker) notifyPermanentFailure(ctx context.Context, job repository.Job, jobErr error) {
+	handler, ok := w.handlers[job.JobType].(FailureHandler)
+	if !ok {
+		return
 	}
 
-	params := repository.UpdateJobFailedParams{
-		ID: jobID,
-		ErrorMessage: sql.NullString{
-			String: errorMessage,
-			Valid:  true,
-		},
+	// Bound the callback like a job run
+	failCtx, cancel := context.WithTimeout(ctx, w.config.JobTimeout)
+	defer cancel()
+
+	handler.OnPermanentFailure(failCtx, job.Payload, jobErr)
+}
+
+// payloadRequestID returns the request_id recorded in a job payload by the
+// enqueue helpers, or "" for jobs enqueued outside a request.
+func payloadRequestID(payload []byte) string {
+	var meta struct {
+		RequestID string `json:"request_id"`
+	}
+	if err := json.Unmarshal(payload, &meta); err != nil {
+		return ""
 	}
+	return meta.RequestID
+}
 
-	if err := w.queries.UpdateJobFailed(ctx, params); err != nil {
-		w.logger.Error("Failed to mark job as failed", "job_id", jobID, "error", err)
+// payloadInspectionID returns the inspection_id of a job payload, or
+// uuid.Nil for jobs that aren't about an inspection.
+func payloadInspectionID(payload []byte) uuid.UUID {
+	var meta struct {
+		InspectionID uuid.UUID `json:"inspection_id"`
+	}
+	if err := json.Unmarshal(payload, &meta); err != nil {
+		return uuid.Nil
 	}
-	metrics.JobFailed(jobType)
+	return meta.InspectionID
 }
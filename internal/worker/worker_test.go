@@ -2,8 +2,21 @@ package worker
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"math"
+	"slices"
 	"testing"
 	"time"
+
+	"github.com/DukeRupert/lukaut/internal/jobevents"
+	"github.com/DukeRupert/lukaut/internal/repository"
+	"github.com/DukeRupert/lukaut/internal/testutil/fakedb"
+	"github.com/google/uuid"
+	"github.com/sqlc-dev/pqtype"
 )
 
 func TestConfig_Validate(t *testing.T) {
@@ -39,6 +52,42 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "reserved slots leave a general worker",
+			config: Config{
+				Concurrency:               2,
+				PollInterval:              5 * time.Second,
+				JobTimeout:                5 * time.Minute,
+				ShutdownTimeout:           30 * time.Second,
+				StaleJobThreshold:         10 * time.Minute,
+				ReservedHighPrioritySlots: 1,
+			},
+			wantErr: false,
+		},
+		{
+			name: "every slot reserved",
+			config: Config{
+				Concurrency:               2,
+				PollInterval:              5 * time.Second,
+				JobTimeout:                5 * time.Minute,
+				ShutdownTimeout:           30 * time.Second,
+				StaleJobThreshold:         10 * time.Minute,
+				ReservedHighPrioritySlots: 2,
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative reserved slots",
+			config: Config{
+				Concurrency:               2,
+				PollInterval:              5 * time.Second,
+				JobTimeout:                5 * time.Minute,
+				ShutdownTimeout:           30 * time.Second,
+				StaleJobThreshold:         10 * time.Minute,
+				ReservedHighPrioritySlots: -1,
+			},
+			wantErr: true,
+		},
 		{
 			name: "poll interval too short",
 			config: Config{
@@ -93,3 +142,272 @@ func TestIsPermanent(t *testing.T) {
 		})
 	}
 }
+
+// failureRecordingHandler records OnPermanentFailure calls.
+type failureRecordingHandler struct {
+	payloads []string
+}
+
+func (h *failureRecordingHandler) Type() string { return "test_job" }
+
+func (h *failureRecordingHandler) Handle(ctx context.Context, payload []byte) error { return nil }
+
+func (h *failureRecordingHandler) OnPermanentFailure(ctx context.Context, payload []byte, jobErr error) {
+	h.payloads = append(h.payloads, string(payload))
+}
+
+// plainHandler does not implement FailureHandler.
+type plainHandler struct{}
+
+func (plainHandler) Type() string                                     { return "plain_job" }
+func (plainHandler) Handle(ctx context.Context, payload []byte) error { return nil }
+
+func TestNotifyPermanentFailure(t *testing.T) {
+	recorder := &failureRecordingHandler{}
+	w := &Worker{
+		handlers: map[string]JobHandler{
+			recorder.Type():       recorder,
+			plainHandler{}.Type(): plainHandler{},
+		},
+		config: DefaultConfig(),
+	}
+
+	w.notifyPermanentFailure(context.Background(), repository.Job{JobType: "test_job", Payload: []byte(`{"a":1}`)}, context.Canceled)
+	if len(recorder.payloads) != 1 || recorder.payloads[0] != `{"a":1}` {
+		t.Errorf("expected failure handler to receive the payload once, got %v", recorder.payloads)
+	}
+
+	// Handlers without the hook and unknown job types are ignored
+	w.notifyPermanentFailure(context.Background(), repository.Job{JobType: "plain_job"}, context.Canceled)
+	w.notifyPermanentFailure(context.Background(), repository.Job{JobType: "missing_job"}, context.Canceled)
+	if len(recorder.payloads) != 1 {
+		t.Errorf("expected no further calls, got %v", recorder.payloads)
+	}
+}
+
+// recordingNotifier records published job events.
+type recordingNotifier struct {
+	events []jobevents.Event
+}
+
+func (n *recordingNotifier) Publish(e jobevents.Event) { n.events = append(n.events, e) }
+
+func TestMarkJobCompleted_NotifiesInspectionSubscribers(t *testing.T) {
+	f := &fakeQueueDB{}
+	db := fakedb.Open(f)
+	t.Cleanup(func() { _ = db.Close() })
+	notifier := &recordingNotifier{}
+	w := (&Worker{queries: repository.New(db), config: DefaultConfig()}).WithNotifier(notifier)
+
+	inspectionID := uuid.New()
+	payload, _ := json.Marshal(AnalyzeInspectionPayload{InspectionID: inspectionID, UserID: uuid.New()})
+	job := repository.Job{ID: uuid.New(), JobType: JobTypeAnalyzeInspection, Payload: payload}
+	if err := w.markJobCompleted(context.Background(), job, pqtype.NullRawMessage{}, time.Second); err != nil {
+		t.Fatalf("markJobCompleted failed: %v", err)
+	}
+
+	want := jobevents.Event{InspectionID: inspectionID, JobType: JobTypeAnalyzeInspection, State: jobevents.StateCompleted}
+	if len(notifier.events) != 1 || notifier.events[0] != want {
+		t.Errorf("expected %+v, got %+v", want, notifier.events)
+	}
+
+	// Jobs that aren't about an inspection aren't reported
+	email, _ := json.Marshal(SendEmailPayload{Template: "welcome", To: "pat@example.com"})
+	if err := w.markJobCompleted(context.Background(), repository.Job{ID: uuid.New(), JobType: JobTypeSendEmail, Payload: email}, pqtype.NullRawMessage{}, time.Second); err != nil {
+		t.Fatalf("markJobCompleted failed: %v", err)
+	}
+	if len(notifier.events) != 1 {
+		t.Errorf("expected no event for an email job, got %+v", notifier.events[1:])
+	}
+}
+
+func TestMarkJobFailed_NotifiesRetryAndFailure(t *testing.T) {
+	f := &fakeQueueDB{}
+	db := fakedb.Open(f)
+	t.Cleanup(func() { _ = db.Close() })
+	notifier := &recordingNotifier{}
+	w := (&Worker{queries: repository.New(db), config: DefaultConfig(), logger: slog.New(slog.NewTextHandler(io.Discard, nil))}).WithNotifier(notifier)
+
+	payload, _ := json.Marshal(AnalyzeInspectionPayload{InspectionID: uuid.New(), UserID: uuid.New()})
+	job := repository.Job{ID: uuid.New(), JobType: JobTypeAnalyzeInspection, Payload: payload, MaxAttempts: 3}
+	w.markJobFailed(context.Background(), job, pqtype.NullRawMessage{}, errors.New("rate limited"))
+	w.markJobFailed(context.Background(), job, pqtype.NullRawMessage{}, NewPermanentError(errors.New("bad image")))
+
+	if len(notifier.events) != 2 || notifier.events[0].State != jobevents.StateRetrying || notifier.events[1].State != jobevents.StateFailed {
+		t.Errorf("expected retrying then failed, got %+v", notifier.events)
+	}
+}
+
+func TestPayloadRequestID(t *testing.T) {
+	withID, _ := json.Marshal(GenerateReportPayload{Format: "pdf", RequestID: "abc123"})
+	withoutID, _ := json.Marshal(SendEmailPayload{Template: "welcome", To: "pat@example.com"})
+
+	tests := []struct {
+		name    string
+		payload []byte
+		want    string
+	}{
+		{"enqueued during a request", withID, "abc123"},
+		{"enqueued outside a request", withoutID, ""},
+		{"not JSON", []byte("garbage"), ""},
+	}
+	for _, tt := range tests {
+		if got := payloadRequestID(tt.payload); got != tt.want {
+			t.Errorf("%s: payloadRequestID = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEnqueueLimits_Validate(t *testing.T) {
+	if err := DefaultEnqueueLimits().Validate(); err != nil {
+		t.Errorf("expected default limits to be valid, got %v", err)
+	}
+	if err := (EnqueueLimits{}).Validate(); err != nil {
+		t.Errorf("expected zero (unlimited) limits to be valid, got %v", err)
+	}
+	if err := (EnqueueLimits{MaxInFlightPerUser: -1}).Validate(); err == nil {
+		t.Error("expected negative per-user limit to be rejected")
+	}
+	if err := (EnqueueLimits{MaxQueueDepth: -1}).Validate(); err == nil {
+		t.Error("expected negative queue depth to be rejected")
+	}
+}
+
+// =============================================================================
+// Priority Tests
+// =============================================================================
+
+// orderRecordingHandler records the type of each job it runs.
+type orderRecordingHandler struct {
+	jobType string
+	ran     *[]string
+}
+
+func (h orderRecordingHandler) Type() string { return h.jobType }
+
+func (h orderRecordingHandler) Handle(ctx context.Context, payload []byte) error {
+	*h.ran = append(*h.ran, h.jobType)
+	return nil
+}
+
+// newQueueWorker returns a worker over f that records the jobs it runs.
+func newQueueWorker(t *testing.T, f *fakeQueueDB) (*Worker, *[]string) {
+	t.Helper()
+	db := fakedb.Open(f)
+	t.Cleanup(func() { _ = db.Close() })
+	w, err := New(db, repository.New(db), DefaultConfig(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ran := &[]string{}
+	for _, jobType := range []string{JobTypeAnalyzeInspection, JobTypeGenerateReport, JobTypeGeocodeInspection} {
+		w.Register(orderRecordingHandler{jobType: jobType, ran: ran})
+	}
+	return w, ran
+}
+
+func TestProcessNextJob_HighPriorityJumpsQueue(t *testing.T) {
+	ctx := context.Background()
+	f, enqueuer := newFakeQueue(t, EnqueueLimits{})
+	for i := 0; i < 3; i++ {
+		if _, err := enqueuer.EnqueueGeocodeInspection(ctx, uuid.New(), uuid.New(), "100 Main St, Boise, ID"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := enqueuer.EnqueueGenerateReport(ctx, uuid.New(), uuid.New(), "pdf", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := enqueuer.EnqueueAnalyzeInspection(ctx, uuid.New(), uuid.New()); err != nil {
+		t.Fatal(err)
+	}
+
+	w, ran := newQueueWorker(t, f)
+	for {
+		err := w.processNextJob(ctx, w.logger, math.MinInt32)
+		if errors.Is(err, sql.ErrNoRows) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("processNextJob failed: %v", err)
+		}
+	}
+
+	want := []string{JobTypeAnalyzeInspection, JobTypeGenerateReport, JobTypeGeocodeInspection, JobTypeGeocodeInspection, JobTypeGeocodeInspection}
+	if !slices.Equal(*ran, want) {
+		t.Errorf("expected jobs to run in order %v, got %v", want, *ran)
+	}
+}
+
+func TestProcessNextJob_ReservedSlotOnlyTakesHighPriority(t *testing.T) {
+	ctx := context.Background()
+	f, enqueuer := newFakeQueue(t, EnqueueLimits{})
+	if _, err := enqueuer.EnqueueGenerateReport(ctx, uuid.New(), uuid.New(), "pdf", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	w, ran := newQueueWorker(t, f)
+	if err := w.processNextJob(ctx, w.logger, PriorityHigh); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected a reserved slot to leave the report queued, got %v", err)
+	}
+
+	if _, err := enqueuer.EnqueueAnalyzeInspection(ctx, uuid.New(), uuid.New()); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.processNextJob(ctx, w.logger, PriorityHigh); err != nil {
+		t.Fatalf("processNextJob failed: %v", err)
+	}
+	if !slices.Equal(*ran, []string{JobTypeAnalyzeInspection}) {
+		t.Errorf("expected only the analysis to run, got %v", *ran)
+	}
+}
+
+// resultHandler reports a result and then fails with err, if any.
+type resultHandler struct {
+	result any
+	err    error
+}
+
+func (resultHandler) Type() string { return JobTypeGenerateReport }
+
+func (h resultHandler) Handle(ctx context.Context, payload []byte) error {
+	SetResult(ctx, h.result)
+	return h.err
+}
+
+func TestProcessNextJob_StoresResult(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler resultHandler
+		want    string
+	}{
+		{name: "completed", handler: resultHandler{result: map[string]string{"report_id": "r1"}}, want: `{"report_id":"r1"}`},
+		{name: "failed", handler: resultHandler{result: map[string]int{"failed": 2}, err: NewPermanentError(errors.New("boom"))}, want: `{"failed":2}`},
+		{name: "no result", handler: resultHandler{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			f, enqueuer := newFakeQueue(t, EnqueueLimits{})
+			if _, err := enqueuer.EnqueueGenerateReport(ctx, uuid.New(), uuid.New(), "pdf", nil); err != nil {
+				t.Fatal(err)
+			}
+
+			db := fakedb.Open(f)
+			t.Cleanup(func() { _ = db.Close() })
+			w, err := New(db, repository.New(db), DefaultConfig(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			w.Register(tt.handler)
+
+			err = w.processNextJob(ctx, w.logger, math.MinInt32)
+			if (err != nil) != (tt.handler.err != nil) {
+				t.Fatalf("processNextJob error = %v", err)
+			}
+			if got := string(f.jobs[0].result); got != tt.want {
+				t.Errorf("expected result %q stored, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -59,7 +59,7 @@ LIMIT $1;
 
 -- name: AdminGetUserInspections :many
 -- Get inspections for a specific user (admin view)
-SELECT id, title, status, inspection_date, created_at
+SELECT id, title, status, inspection_date, created_at, legal_hold
 FROM inspections
 WHERE user_id = $1
 ORDER BY created_at DESC
@@ -80,8 +80,53 @@ WHERE user_id = $1
 ORDER BY created_at DESC
 LIMIT $2;
 
--- name: AdminUpdateUserDisabled :exec
--- Enable or disable a user account
+-- name: AdminUpdateUserDisabled :execrows
+-- Enable or disable a user account; a NULL disabled_at re-enables it
 UPDATE users
-SET updated_at = NOW()
+SET disabled_at = $2,
+    updated_at = NOW()
 WHERE id = $1;
+
+-- name: AdminSearchUsers :many
+-- Search users by email or name (case-insensitive), a page at a time; an empty search lists all users
+-- The search is a LIKE pattern: escape % and _ to match them literally
+SELECT
+    u.id,
+    u.email,
+    u.name,
+    u.subscription_status,
+    u.subscription_tier,
+    u.email_verified,
+    u.disabled_at,
+    u.created_at,
+    COALESCE(SUM(a.cost_cents), 0)::bigint as total_cost_cents,
+    COUNT(DISTINCT i.id) as inspection_count
+FROM users u
+LEFT JOIN ai_usage a ON u.id = a.user_id
+LEFT JOIN inspections i ON u.id = i.user_id
+WHERE sqlc.arg('search')::text = ''
+   OR u.email ILIKE '%' || sqlc.arg('search')::text || '%'
+   OR u.name ILIKE '%' || sqlc.arg('search')::text || '%'
+GROUP BY u.id
+ORDER BY u.created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: AdminCountUsers :one
+-- Count the users AdminSearchUsers matches across all pages
+SELECT COUNT(*) FROM users u
+WHERE sqlc.arg('search')::text = ''
+   OR u.email ILIKE '%' || sqlc.arg('search')::text || '%'
+   OR u.name ILIKE '%' || sqlc.arg('search')::text || '%';
+
+-- name: AdminUpdateUserSubscriptionTier :execrows
+-- Change a user's subscription tier without touching the Stripe subscription
+UPDATE users
+SET subscription_tier = $2,
+    updated_at = NOW()
+WHERE id = $1;
+
+-- name: AdminListRecentJobs :many
+-- List the most recently created worker jobs for admin monitoring
+SELECT * FROM jobs
+ORDER BY created_at DESC
+LIMIT $1;
@@ -0,0 +1,46 @@
+-- name: CreateAnalysisRun :one
+INSERT INTO analysis_runs (
+    inspection_id,
+    user_id,
+    provider,
+    model,
+    images_analyzed,
+    prompt_tokens,
+    completion_tokens,
+    cost_cents,
+    duration_ms
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+RETURNING *;
+
+-- name: GetInspectionAnalysisCost :one
+-- Total usage and cost of every analysis run of an inspection
+SELECT
+    COUNT(*) as run_count,
+    COALESCE(SUM(images_analyzed), 0)::bigint as images_analyzed,
+    COALESCE(SUM(prompt_tokens), 0)::bigint as prompt_tokens,
+    COALESCE(SUM(completion_tokens), 0)::bigint as completion_tokens,
+    COALESCE(SUM(cost_cents), 0)::bigint as cost_cents
+FROM analysis_runs
+WHERE inspection_id = $1;
+
+-- name: ListAnalysisCostByUserAndMonth :many
+-- Analysis usage and cost per user per calendar month within a date range,
+-- newest month first and most expensive user first (for admin/finance reports)
+SELECT
+    date_trunc('month', r.created_at)::timestamptz as month,
+    r.user_id,
+    u.email,
+    u.name,
+    COUNT(*) as run_count,
+    COALESCE(SUM(r.images_analyzed), 0)::bigint as images_analyzed,
+    COALESCE(SUM(r.prompt_tokens), 0)::bigint as prompt_tokens,
+    COALESCE(SUM(r.completion_tokens), 0)::bigint as completion_tokens,
+    COALESCE(SUM(r.cost_cents), 0)::bigint as cost_cents
+FROM analysis_runs r
+JOIN users u ON u.id = r.user_id
+WHERE r.created_at >= $1
+AND r.created_at < $2
+GROUP BY month, r.user_id, u.email, u.name
+ORDER BY month DESC, cost_cents DESC;
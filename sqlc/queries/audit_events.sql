@@ -0,0 +1,43 @@
+-- name: CreateAuditEvent :one
+INSERT INTO audit_events (
+    actor_user_id,
+    inspection_id,
+    entity_type,
+    entity_id,
+    action,
+    old_values,
+    new_values
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING *;
+
+-- name: ListAuditEventsByInspectionIDAndUserID :many
+-- List audit events for an inspection in chronological order.
+-- The inspection must belong to the user.
+SELECT
+    ae.id,
+    ae.actor_user_id,
+    ae.inspection_id,
+    ae.entity_type,
+    ae.entity_id,
+    ae.action,
+    ae.old_values,
+    ae.new_values,
+    ae.created_at,
+    u.name as actor_name
+FROM audit_events ae
+INNER JOIN inspections i ON i.id = ae.inspection_id
+LEFT JOIN users u ON u.id = ae.actor_user_id
+WHERE ae.inspection_id = $1
+AND i.user_id = $2
+ORDER BY ae.created_at ASC, ae.id ASC;
+
+-- name: GetLatestViolationStatusChange :one
+-- Get the most recent status change recorded for a violation.
+SELECT * FROM audit_events
+WHERE entity_type = 'violation'
+AND entity_id = $1
+AND action = 'status_changed'
+ORDER BY created_at DESC, id DESC
+LIMIT 1;
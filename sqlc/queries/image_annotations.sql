@@ -0,0 +1,32 @@
+-- name: CreateImageAnnotation :one
+INSERT INTO image_annotations (
+    violation_id,
+    image_id,
+    x,
+    y,
+    width,
+    height,
+    label,
+    source
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+)
+RETURNING *;
+
+-- name: ListImageAnnotationsByImageIDAndUserID :many
+-- List annotations on an image with user authorization check
+SELECT a.* FROM image_annotations a
+INNER JOIN images img ON img.id = a.image_id
+INNER JOIN inspections i ON i.id = img.inspection_id
+WHERE a.image_id = $1
+AND i.user_id = $2
+ORDER BY a.created_at, a.id;
+
+-- name: ListImageAnnotationsByViolationID :many
+SELECT * FROM image_annotations
+WHERE violation_id = $1
+ORDER BY created_at, id;
+
+-- name: DeleteImageAnnotationsByViolationIDAndSource :exec
+DELETE FROM image_annotations
+WHERE violation_id = $1 AND source = $2;
@@ -1,4 +1,5 @@
 -- name: CreateImage :one
+-- Create an image at the end of its inspection's gallery order
 INSERT INTO images (
     inspection_id,
     storage_key,
@@ -8,9 +9,12 @@ INSERT INTO images (
     size_bytes,
     width,
     height,
-    analysis_status
+    analysis_status,
+    content_hash,
+    display_order
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10,
+    (SELECT COALESCE(MAX(display_order), 0) + 1 FROM images WHERE inspection_id = $1)
 )
 RETURNING *;
 
@@ -31,7 +35,7 @@ WHERE id = $1 AND inspection_id = $2;
 -- name: ListImagesByInspectionID :many
 SELECT * FROM images
 WHERE inspection_id = $1
-ORDER BY created_at DESC;
+ORDER BY display_order ASC, created_at ASC;
 
 -- name: ListPendingImagesByInspectionID :many
 SELECT * FROM images
@@ -59,6 +63,12 @@ SELECT COUNT(*) FROM images
 WHERE inspection_id = $1
 AND (analysis_status IS NULL OR analysis_status = 'pending');
 
+-- name: CountFailedImagesByInspectionID :one
+-- Count images whose analysis failed and can be retried
+SELECT COUNT(*) FROM images
+WHERE inspection_id = $1
+AND analysis_status = 'failed';
+
 -- name: ListPendingImagesByInspectionIDAndUserID :many
 -- List pending images with user authorization check (defense in depth)
 SELECT img.* FROM images img
@@ -68,6 +78,23 @@ AND ins.user_id = $2
 AND img.analysis_status = 'pending'
 ORDER BY img.created_at ASC;
 
+-- name: ListImagesByInspectionIDAndUserID :many
+-- List all images regardless of analysis status with user authorization check
+SELECT img.* FROM images img
+JOIN inspections ins ON ins.id = img.inspection_id
+WHERE ins.id = $1
+AND ins.user_id = $2
+ORDER BY img.display_order ASC, img.created_at ASC;
+
+-- name: UpdateImageDisplayOrder :execrows
+-- Set the display order of an inspection's images to their position in
+-- image_ids (starting at 1). Images of other inspections are not touched.
+UPDATE images
+SET display_order = ordered.position
+FROM unnest(sqlc.arg(image_ids)::uuid[]) WITH ORDINALITY AS ordered(id, position)
+WHERE images.id = ordered.id
+AND images.inspection_id = sqlc.arg(inspection_id);
+
 -- name: UpdateImageAnalysisStatusWithAuth :exec
 -- Update image analysis status with user authorization check
 UPDATE images
@@ -77,3 +104,26 @@ FROM inspections
 WHERE images.id = $1
 AND images.inspection_id = inspections.id
 AND inspections.user_id = $2;
+
+-- name: MarkImageAnalysisFailedWithAuth :exec
+-- Mark image analysis failed with the reason, with user authorization check
+UPDATE images
+SET analysis_status = 'failed',
+    analysis_completed_at = NULL,
+    analysis_error = $3
+FROM inspections
+WHERE images.id = $1
+AND images.inspection_id = inspections.id
+AND inspections.user_id = $2;
+
+-- name: MarkImageAnalysisCompletedWithAuth :exec
+-- Mark image analysis completed and clear any earlier failure, with user
+-- authorization check
+UPDATE images
+SET analysis_status = 'completed',
+    analysis_completed_at = NOW(),
+    analysis_error = NULL
+FROM inspections
+WHERE images.id = $1
+AND images.inspection_id = inspections.id
+AND inspections.user_id = $2;
@@ -0,0 +1,27 @@
+-- name: CreateInspectionComment :one
+INSERT INTO inspection_comments (
+    inspection_id,
+    user_id,
+    body
+) VALUES (
+    $1, $2, $3
+)
+RETURNING *;
+
+-- name: ListInspectionComments :many
+-- List an inspection's comments with their authors' names, oldest first.
+-- The inspection must belong to the owner.
+SELECT c.*, u.name AS author_name FROM inspection_comments c
+INNER JOIN inspections i ON i.id = c.inspection_id
+INNER JOIN users u ON u.id = c.user_id
+WHERE c.inspection_id = $1
+AND i.user_id = $2
+ORDER BY c.created_at ASC, c.id ASC;
+
+-- name: DeleteInspectionCommentByAuthor :execrows
+-- Delete a comment on an inspection. Returns 0 rows affected if the comment
+-- does not exist, is on another inspection, or was written by someone else.
+DELETE FROM inspection_comments
+WHERE id = $1
+AND inspection_id = $2
+AND user_id = $3;
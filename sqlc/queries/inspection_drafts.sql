@@ -0,0 +1,41 @@
+-- name: GetInspectionDraftByUserID :one
+SELECT * FROM inspection_drafts
+WHERE user_id = $1;
+
+-- name: UpsertInspectionDraft :one
+-- Merges autosaved fields into the user's draft. A NULL argument keeps the
+-- stored value; an empty string clears it.
+INSERT INTO inspection_drafts (
+    user_id,
+    title,
+    client_id,
+    address_line1,
+    address_line2,
+    city,
+    state,
+    postal_code,
+    inspection_date,
+    weather_conditions,
+    temperature,
+    inspector_notes
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+)
+ON CONFLICT (user_id) DO UPDATE
+SET title = COALESCE(EXCLUDED.title, inspection_drafts.title),
+    client_id = COALESCE(EXCLUDED.client_id, inspection_drafts.client_id),
+    address_line1 = COALESCE(EXCLUDED.address_line1, inspection_drafts.address_line1),
+    address_line2 = COALESCE(EXCLUDED.address_line2, inspection_drafts.address_line2),
+    city = COALESCE(EXCLUDED.city, inspection_drafts.city),
+    state = COALESCE(EXCLUDED.state, inspection_drafts.state),
+    postal_code = COALESCE(EXCLUDED.postal_code, inspection_drafts.postal_code),
+    inspection_date = COALESCE(EXCLUDED.inspection_date, inspection_drafts.inspection_date),
+    weather_conditions = COALESCE(EXCLUDED.weather_conditions, inspection_drafts.weather_conditions),
+    temperature = COALESCE(EXCLUDED.temperature, inspection_drafts.temperature),
+    inspector_notes = COALESCE(EXCLUDED.inspector_notes, inspection_drafts.inspector_notes),
+    updated_at = NOW()
+RETURNING *;
+
+-- name: DeleteInspectionDraftByUserID :exec
+DELETE FROM inspection_drafts
+WHERE user_id = $1;
@@ -0,0 +1,29 @@
+-- name: CreateInspectionEvent :exec
+INSERT INTO inspection_events (
+    inspection_id,
+    kind,
+    data
+) VALUES (
+    $1, $2, $3
+);
+
+-- name: ListInspectionEventsAfterID :many
+-- List an inspection's events newer than a cursor, oldest first.
+-- The inspection must belong to the user.
+SELECT e.* FROM inspection_events e
+INNER JOIN inspections i ON i.id = e.inspection_id
+WHERE e.inspection_id = $1
+AND i.user_id = $2
+AND e.id > $3
+ORDER BY e.id ASC
+LIMIT $4;
+
+-- name: ListRecentInspectionEvents :many
+-- List an inspection's latest events, newest first.
+-- The inspection must belong to the user.
+SELECT e.* FROM inspection_events e
+INNER JOIN inspections i ON i.id = e.inspection_id
+WHERE e.inspection_id = $1
+AND i.user_id = $2
+ORDER BY e.id DESC
+LIMIT $3;
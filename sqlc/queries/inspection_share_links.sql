@@ -0,0 +1,44 @@
+-- name: CreateInspectionShareLink :one
+INSERT INTO inspection_share_links (
+    inspection_id,
+    user_id,
+    token_hash,
+    expires_at,
+    max_views
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: GetInspectionShareLinkByTokenHash :one
+SELECT * FROM inspection_share_links
+WHERE token_hash = $1;
+
+-- name: ListActiveInspectionShareLinks :many
+-- List the user's usable share links for an inspection, newest first.
+-- Revoked, expired, and used-up links are omitted.
+SELECT * FROM inspection_share_links
+WHERE inspection_id = $1 AND user_id = $2
+AND revoked_at IS NULL
+AND expires_at > NOW()
+AND (max_views IS NULL OR view_count < max_views)
+ORDER BY created_at DESC;
+
+-- name: RecordInspectionShareLinkView :execrows
+-- Count a view of a share link. Returns 0 rows affected if the link is
+-- revoked, expired, or has used up its views, so concurrent viewers cannot
+-- exceed max_views.
+UPDATE inspection_share_links
+SET view_count = view_count + 1
+WHERE id = $1
+AND revoked_at IS NULL
+AND expires_at > NOW()
+AND (max_views IS NULL OR view_count < max_views);
+
+-- name: RevokeInspectionShareLink :one
+-- Revoke a share link owned by the user. Returns no rows if the link does
+-- not exist, belongs to another user, or was already revoked.
+UPDATE inspection_share_links
+SET revoked_at = NOW()
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+RETURNING *;
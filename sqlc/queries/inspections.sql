@@ -58,6 +58,15 @@ LIMIT $2 OFFSET $3;
 SELECT COUNT(*) FROM inspections
 WHERE user_id = $1;
 
+-- name: CountOrganizationInspectionsByUserID :one
+-- Count the inspections of the user and the members of their organization.
+SELECT COUNT(*) FROM inspections i
+WHERE (i.user_id = $1 OR i.user_id IN (
+    SELECT them.user_id FROM organization_members me
+    JOIN organization_members them ON them.organization_id = me.organization_id
+    WHERE me.user_id = $1
+));
+
 -- name: DeleteInspection :exec
 DELETE FROM inspections
 WHERE id = $1;
@@ -88,7 +97,57 @@ GROUP BY i.id
 ORDER BY i.created_at DESC
 LIMIT $2;
 
+-- name: ListInspectionLocationsByUserID :many
+-- Geocoded inspections for the dashboard map, most recent first.
+SELECT
+    id,
+    title,
+    status,
+    inspection_date,
+    address_line1,
+    city,
+    state,
+    latitude,
+    longitude
+FROM inspections
+WHERE user_id = $1
+  AND latitude IS NOT NULL
+  AND longitude IS NOT NULL
+ORDER BY inspection_date DESC
+LIMIT $2;
+
+-- name: ListInspectionsNear :many
+-- The user's geocoded inspections within radius_meters of a point, nearest
+-- first. Distance is the haversine great-circle distance in meters.
+SELECT * FROM (
+    SELECT
+        id,
+        title,
+        status,
+        inspection_date,
+        address_line1,
+        city,
+        state,
+        latitude,
+        longitude,
+        (6371000 * 2 * ASIN(SQRT(
+            POWER(SIN(RADIANS(latitude - sqlc.arg(latitude)::float8) / 2), 2)
+            + COS(RADIANS(sqlc.arg(latitude)::float8)) * COS(RADIANS(latitude))
+            * POWER(SIN(RADIANS(longitude - sqlc.arg(longitude)::float8) / 2), 2)
+        )))::float8 AS distance_meters
+    FROM inspections
+    WHERE user_id = sqlc.arg(user_id)
+      AND latitude IS NOT NULL
+      AND longitude IS NOT NULL
+) AS nearby
+WHERE distance_meters <= sqlc.arg(radius_meters)::float8
+ORDER BY distance_meters
+LIMIT sqlc.arg(max_results);
+
 -- name: ListInspectionsWithClientByUserID :many
+-- List the user's inspections ordered by sort_by, one of inspection_date,
+-- created_at, title, client_name, violation_count, or status. Ties, and
+-- unknown columns, fall back to newest first.
 SELECT
     i.id,
     i.user_id,
@@ -116,7 +175,75 @@ GROUP BY i.id, i.user_id, i.client_id, i.title, i.status, i.inspection_date,
          i.weather_conditions, i.temperature, i.inspector_notes,
          i.address_line1, i.address_line2, i.city, i.state, i.postal_code,
          i.created_at, i.updated_at, c.name
-ORDER BY i.created_at DESC
+ORDER BY
+    CASE WHEN sqlc.arg(sort_by)::text = 'inspection_date' AND NOT sqlc.arg(sort_desc)::bool THEN i.inspection_date END ASC,
+    CASE WHEN sqlc.arg(sort_by)::text = 'inspection_date' AND sqlc.arg(sort_desc)::bool THEN i.inspection_date END DESC,
+    CASE WHEN sqlc.arg(sort_by)::text = 'title' AND NOT sqlc.arg(sort_desc)::bool THEN LOWER(i.title) END ASC,
+    CASE WHEN sqlc.arg(sort_by)::text = 'title' AND sqlc.arg(sort_desc)::bool THEN LOWER(i.title) END DESC,
+    CASE WHEN sqlc.arg(sort_by)::text = 'client_name' AND NOT sqlc.arg(sort_desc)::bool THEN LOWER(COALESCE(c.name, '')) END ASC,
+    CASE WHEN sqlc.arg(sort_by)::text = 'client_name' AND sqlc.arg(sort_desc)::bool THEN LOWER(COALESCE(c.name, '')) END DESC,
+    CASE WHEN sqlc.arg(sort_by)::text = 'violation_count' AND NOT sqlc.arg(sort_desc)::bool THEN COUNT(v.id) END ASC,
+    CASE WHEN sqlc.arg(sort_by)::text = 'violation_count' AND sqlc.arg(sort_desc)::bool THEN COUNT(v.id) END DESC,
+    CASE WHEN sqlc.arg(sort_by)::text = 'status' AND NOT sqlc.arg(sort_desc)::bool THEN i.status END ASC,
+    CASE WHEN sqlc.arg(sort_by)::text = 'status' AND sqlc.arg(sort_desc)::bool THEN i.status END DESC,
+    CASE WHEN sqlc.arg(sort_by)::text = 'created_at' AND NOT sqlc.arg(sort_desc)::bool THEN i.created_at END ASC,
+    i.created_at DESC,
+    i.id
+LIMIT $2 OFFSET $3;
+
+-- name: ListOrganizationInspectionsWithClientByUserID :many
+-- List the inspections of the user and the members of their organization,
+-- with the names of each inspection's owner and assignee, ordered as in
+-- ListInspectionsWithClientByUserID.
+SELECT
+    i.id,
+    i.user_id,
+    i.client_id,
+    i.title,
+    i.status,
+    i.inspection_date,
+    i.weather_conditions,
+    i.temperature,
+    i.inspector_notes,
+    i.address_line1,
+    i.address_line2,
+    i.city,
+    i.state,
+    i.postal_code,
+    i.created_at,
+    i.updated_at,
+    COALESCE(c.name, '') AS client_name,
+    u.name AS owner_name,
+    COALESCE(a.name, '') AS assignee_name,
+    COALESCE(COUNT(v.id), 0)::int AS violation_count
+FROM inspections i
+JOIN users u ON u.id = i.user_id
+LEFT JOIN users a ON a.id = i.assigned_to
+LEFT JOIN clients c ON c.id = i.client_id
+LEFT JOIN violations v ON v.inspection_id = i.id
+WHERE (i.user_id = $1 OR i.user_id IN (
+    SELECT them.user_id FROM organization_members me
+    JOIN organization_members them ON them.organization_id = me.organization_id
+    WHERE me.user_id = $1
+))
+GROUP BY i.id, i.user_id, i.client_id, i.title, i.status, i.inspection_date,
+         i.weather_conditions, i.temperature, i.inspector_notes,
+         i.address_line1, i.address_line2, i.city, i.state, i.postal_code,
+         i.created_at, i.updated_at, c.name, u.name, a.name
+ORDER BY
+    CASE WHEN sqlc.arg(sort_by)::text = 'inspection_date' AND NOT sqlc.arg(sort_desc)::bool THEN i.inspection_date END ASC,
+    CASE WHEN sqlc.arg(sort_by)::text = 'inspection_date' AND sqlc.arg(sort_desc)::bool THEN i.inspection_date END DESC,
+    CASE WHEN sqlc.arg(sort_by)::text = 'title' AND NOT sqlc.arg(sort_desc)::bool THEN LOWER(i.title) END ASC,
+    CASE WHEN sqlc.arg(sort_by)::text = 'title' AND sqlc.arg(sort_desc)::bool THEN LOWER(i.title) END DESC,
+    CASE WHEN sqlc.arg(sort_by)::text = 'client_name' AND NOT sqlc.arg(sort_desc)::bool THEN LOWER(COALESCE(c.name, '')) END ASC,
+    CASE WHEN sqlc.arg(sort_by)::text = 'client_name' AND sqlc.arg(sort_desc)::bool THEN LOWER(COALESCE(c.name, '')) END DESC,
+    CASE WHEN sqlc.arg(sort_by)::text = 'violation_count' AND NOT sqlc.arg(sort_desc)::bool THEN COUNT(v.id) END ASC,
+    CASE WHEN sqlc.arg(sort_by)::text = 'violation_count' AND sqlc.arg(sort_desc)::bool THEN COUNT(v.id) END DESC,
+    CASE WHEN sqlc.arg(sort_by)::text = 'status' AND NOT sqlc.arg(sort_desc)::bool THEN i.status END ASC,
+    CASE WHEN sqlc.arg(sort_by)::text = 'status' AND sqlc.arg(sort_desc)::bool THEN i.status END DESC,
+    CASE WHEN sqlc.arg(sort_by)::text = 'created_at' AND NOT sqlc.arg(sort_desc)::bool THEN i.created_at END ASC,
+    i.created_at DESC,
+    i.id
 LIMIT $2 OFFSET $3;
 
 -- name: GetInspectionWithClientByIDAndUserID :one
@@ -137,16 +264,24 @@ SELECT
     i.postal_code,
     i.created_at,
     i.updated_at,
-    COALESCE(c.name, '') AS client_name
+    i.version,
+    i.assigned_to,
+    i.is_template,
+    i.template_name,
+    COALESCE(c.name, '') AS client_name,
+    COALESCE(a.name, '') AS assignee_name
 FROM inspections i
 LEFT JOIN clients c ON c.id = i.client_id
+LEFT JOIN users a ON a.id = i.assigned_to
 WHERE i.id = $1 AND i.user_id = $2;
 
 -- name: DeleteInspectionByIDAndUserID :exec
 DELETE FROM inspections
 WHERE id = $1 AND user_id = $2;
 
--- name: UpdateInspectionByIDAndUserID :exec
+-- name: UpdateInspectionByIDAndUserID :execrows
+-- Apply an edit only if the row is still at the version the editor loaded.
+-- Zero rows affected means someone else saved first (or the row is gone).
 UPDATE inspections
 SET title = $3,
     client_id = $4,
@@ -159,7 +294,22 @@ SET title = $3,
     city = $11,
     state = $12,
     postal_code = $13,
+    version = version + 1,
     updated_at = NOW()
+WHERE id = $1 AND user_id = $2 AND version = $14;
+
+-- name: UpdateInspectionAssigneeByIDAndUserID :execrows
+-- Assign the inspection to a user, or unassign it with NULL.
+UPDATE inspections
+SET assigned_to = $3,
+    updated_at = NOW()
+WHERE id = $1 AND user_id = $2;
+
+-- name: UpdateInspectionLocationByIDAndUserID :exec
+-- Store (or clear, with NULLs) the geocoded coordinates of the address.
+UPDATE inspections
+SET latitude = $3,
+    longitude = $4
 WHERE id = $1 AND user_id = $2;
 
 -- name: UpdateInspectionStatusByIDAndUserID :exec
@@ -167,3 +317,65 @@ UPDATE inspections
 SET status = $3,
     updated_at = NOW()
 WHERE id = $1 AND user_id = $2;
+
+-- name: UpdateInspectionWeatherIfEmptyByIDAndUserID :execrows
+-- Fill in weather fields the inspector left empty; entered values are kept.
+-- Matches no row when both fields are already set.
+UPDATE inspections
+SET weather_conditions = CASE WHEN COALESCE(weather_conditions, '') = '' THEN $3 ELSE weather_conditions END,
+    temperature = CASE WHEN COALESCE(temperature, '') = '' THEN $4 ELSE temperature END
+WHERE id = $1 AND user_id = $2
+AND (COALESCE(weather_conditions, '') = '' OR COALESCE(temperature, '') = '');
+
+-- name: UpdateInspectionTemplateByIDAndUserID :execrows
+-- Keep the inspection as a template under a name, or stop with false and NULL.
+UPDATE inspections
+SET is_template = $3,
+    template_name = $4,
+    updated_at = NOW()
+WHERE id = $1 AND user_id = $2;
+
+-- name: ListInspectionTemplatesByUserID :many
+-- The user's templates, by name.
+SELECT
+    i.id,
+    i.template_name,
+    i.title,
+    i.address_line1,
+    i.address_line2,
+    i.city,
+    i.state,
+    i.postal_code,
+    i.updated_at,
+    COALESCE(c.name, '') AS client_name
+FROM inspections i
+LEFT JOIN clients c ON c.id = i.client_id
+WHERE i.user_id = $1 AND i.is_template
+ORDER BY LOWER(i.template_name), i.id;
+
+-- name: ListInspectionsDueForPurge :many
+-- Completed inspections last changed before the retention cutoff and not
+-- under legal hold, oldest first
+SELECT id, user_id, title, updated_at FROM inspections
+WHERE status = 'completed'
+AND NOT legal_hold
+AND NOT is_template
+AND updated_at < sqlc.arg(cutoff)
+ORDER BY updated_at
+LIMIT sqlc.arg(batch_size);
+
+-- name: PurgeInspection :execrows
+-- Deletes an inspection that is still due for purge; matches no row if it
+-- was reopened, edited, or put under legal hold since it was listed
+DELETE FROM inspections
+WHERE id = sqlc.arg(id)
+AND status = 'completed'
+AND NOT legal_hold
+AND NOT is_template
+AND updated_at < sqlc.arg(cutoff);
+
+-- name: SetInspectionLegalHold :execrows
+-- Leaves updated_at alone so the hold does not restart the retention period
+UPDATE inspections
+SET legal_hold = $2
+WHERE id = $1;
@@ -1,20 +1,32 @@
 -- name: EnqueueJob :one
+-- Returns no row if a pending or running job already holds the idempotency key
 INSERT INTO jobs (
     job_type,
     payload,
     priority,
     max_attempts,
-    scheduled_at
+    scheduled_at,
+    idempotency_key
 ) VALUES (
-    $1, $2, $3, $4, $5
+    $1, $2, $3, $4, $5, $6
 )
+ON CONFLICT (idempotency_key) WHERE status IN ('pending', 'running') DO NOTHING
 RETURNING *;
 
+-- name: GetActiveJobByIdempotencyKey :one
+-- Get the pending or running job holding an idempotency key
+SELECT * FROM jobs
+WHERE idempotency_key = $1
+AND status IN ('pending', 'running');
+
 -- name: DequeueJob :one
+-- Claims the next ready job of at least priority $1: highest priority first,
+-- then oldest first
 SELECT * FROM jobs
 WHERE status = 'pending'
 AND scheduled_at <= NOW()
-ORDER BY priority DESC, scheduled_at ASC
+AND priority >= $1
+ORDER BY priority DESC, created_at ASC
 LIMIT 1
 FOR UPDATE SKIP LOCKED;
 
@@ -26,9 +38,12 @@ SET status = 'running',
 WHERE id = $1;
 
 -- name: UpdateJobCompleted :exec
+-- Marks a job completed with what it produced, if anything
 UPDATE jobs
 SET status = 'completed',
-    completed_at = NOW()
+    completed_at = NOW(),
+    finished_at = NOW(),
+    result = $2
 WHERE id = $1;
 
 -- name: UpdateJobFailed :exec
@@ -39,16 +54,42 @@ SET status = CASE
     ELSE 'pending'
 END,
 error_message = $2,
+result = $3,
 scheduled_at = CASE
     WHEN attempts < max_attempts THEN NOW() + (LEAST(POWER(2, attempts - 1) * 30, 3600) * INTERVAL '1 second')
     ELSE scheduled_at
+END,
+finished_at = CASE
+    WHEN attempts >= max_attempts THEN NOW()
+    ELSE NULL
 END
 WHERE id = $1;
 
+-- name: UpdateJobPermanentlyFailed :exec
+-- Marks a job as failed without retrying, regardless of remaining attempts
+UPDATE jobs
+SET status = 'failed',
+    error_message = $2,
+    result = $3,
+    finished_at = NOW()
+WHERE id = $1;
+
 -- name: GetJobByID :one
 SELECT * FROM jobs
 WHERE id = $1;
 
+-- name: GetJobByIDAndUserID :one
+-- Get a job enqueued for a user or a member of their organization (the
+-- payload of user jobs names the user, which is the inspection owner for
+-- inspection jobs)
+SELECT * FROM jobs
+WHERE id = $1
+AND (payload->>'user_id' = $2::text OR payload->>'user_id' IN (
+    SELECT them.user_id::text FROM organization_members me
+    JOIN organization_members them ON them.organization_id = me.organization_id
+    WHERE me.user_id::text = $2::text
+));
+
 -- name: DeleteCompletedJobsOlderThan :exec
 DELETE FROM jobs
 WHERE status = 'completed'
@@ -81,3 +122,46 @@ AND status = 'completed'
 AND payload->>'user_id' = $2::text
 AND completed_at >= $3
 AND completed_at < $4;
+
+-- name: RetryFailedJob :execrows
+-- Re-enqueues a permanently failed job with a fresh set of attempts, unless
+-- a copy of it (same idempotency key) has been queued since
+UPDATE jobs
+SET status = 'pending',
+    attempts = 0,
+    error_message = NULL,
+    started_at = NULL,
+    completed_at = NULL,
+    finished_at = NULL,
+    result = NULL,
+    scheduled_at = NOW()
+WHERE id = $1
+AND status = 'failed'
+AND NOT EXISTS (
+    SELECT 1 FROM jobs active
+    WHERE active.idempotency_key = jobs.idempotency_key
+    AND active.status IN ('pending', 'running')
+);
+
+-- name: HasPendingJobOfType :one
+-- Check if a job of the given type is waiting to run (used to keep a single
+-- recurring job scheduled)
+SELECT EXISTS (
+    SELECT 1 FROM jobs
+    WHERE job_type = $1
+    AND status = 'pending'
+) AS has_pending;
+
+-- name: CountInFlightJobsByUserAndType :one
+-- Count pending or running jobs of a type for a user (for the per-user enqueue cap)
+SELECT COUNT(*) as count
+FROM jobs
+WHERE job_type = $1
+AND status IN ('pending', 'running')
+AND payload->>'user_id' = sqlc.arg(user_id)::text;
+
+-- name: CountPendingJobs :one
+-- Count jobs waiting to run across all users (for the queue-depth safeguard)
+SELECT COUNT(*) as count
+FROM jobs
+WHERE status = 'pending';
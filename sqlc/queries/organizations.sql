@@ -0,0 +1,122 @@
+-- name: CreateOrganization :one
+INSERT INTO organizations (
+    name
+) VALUES (
+    $1
+)
+RETURNING *;
+
+-- name: CreateOrganizationMember :exec
+INSERT INTO organization_members (
+    organization_id,
+    user_id,
+    role
+) VALUES (
+    $1, $2, $3
+);
+
+-- name: GetOrganizationMembershipByUserID :one
+-- Get the organization a user belongs to. Returns no rows for users who
+-- work in their implicit personal organization.
+SELECT
+    m.organization_id,
+    o.name AS organization_name,
+    m.role
+FROM organization_members m
+JOIN organizations o ON o.id = m.organization_id
+WHERE m.user_id = $1;
+
+-- name: ListOrganizationMembers :many
+-- List an organization's members, owners first.
+SELECT
+    u.id AS user_id,
+    u.name,
+    u.email,
+    m.role,
+    m.created_at
+FROM organization_members m
+JOIN users u ON u.id = m.user_id
+WHERE m.organization_id = $1
+ORDER BY CASE m.role WHEN 'owner' THEN 0 ELSE 1 END, u.name;
+
+-- name: DeleteOrganizationMember :execrows
+-- Remove a member from an organization. Owners cannot be removed.
+DELETE FROM organization_members
+WHERE organization_id = $1 AND user_id = $2 AND role <> 'owner';
+
+-- name: CreateOrganizationInvite :one
+INSERT INTO organization_invites (
+    organization_id,
+    email,
+    token_hash,
+    invited_by,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: GetOrganizationInviteByTokenHash :one
+SELECT
+    inv.id,
+    inv.organization_id,
+    inv.email,
+    inv.token_hash,
+    inv.invited_by,
+    inv.expires_at,
+    inv.accepted_at,
+    inv.created_at,
+    o.name AS organization_name
+FROM organization_invites inv
+JOIN organizations o ON o.id = inv.organization_id
+WHERE inv.token_hash = $1;
+
+-- name: ListPendingOrganizationInvites :many
+-- List an organization's invitations that can still be accepted, newest first.
+SELECT * FROM organization_invites
+WHERE organization_id = $1
+AND accepted_at IS NULL
+AND expires_at > NOW()
+ORDER BY created_at DESC;
+
+-- name: AcceptOrganizationInvite :execrows
+-- Mark an invitation accepted. Returns 0 rows affected if it was already
+-- accepted or has expired, so an invitation can only be used once.
+UPDATE organization_invites
+SET accepted_at = NOW()
+WHERE id = $1
+AND accepted_at IS NULL
+AND expires_at > NOW();
+
+-- name: GetInspectionOwnerIDForUser :one
+-- Get the owner of an inspection the user may work on: one of their own, or
+-- one owned by a member of their organization.
+SELECT i.user_id FROM inspections i
+WHERE i.id = $1
+AND (i.user_id = $2 OR i.user_id IN (
+    SELECT them.user_id FROM organization_members me
+    JOIN organization_members them ON them.organization_id = me.organization_id
+    WHERE me.user_id = $2
+));
+
+-- name: GetImageOwnerIDForUser :one
+-- Get the owner of the inspection of an image the user may work on.
+SELECT ins.user_id FROM images img
+JOIN inspections ins ON ins.id = img.inspection_id
+WHERE img.id = $1
+AND (ins.user_id = $2 OR ins.user_id IN (
+    SELECT them.user_id FROM organization_members me
+    JOIN organization_members them ON them.organization_id = me.organization_id
+    WHERE me.user_id = $2
+));
+
+-- name: GetViolationOwnerIDForUser :one
+-- Get the owner of the inspection of a violation the user may work on.
+SELECT ins.user_id FROM violations v
+JOIN inspections ins ON ins.id = v.inspection_id
+WHERE v.id = $1
+AND (ins.user_id = $2 OR ins.user_id IN (
+    SELECT them.user_id FROM organization_members me
+    JOIN organization_members them ON them.organization_id = me.organization_id
+    WHERE me.user_id = $2
+));
@@ -0,0 +1,12 @@
+-- name: CreateRegulationImport :one
+INSERT INTO regulation_imports (
+    source,
+    added_count,
+    updated_count,
+    unchanged_count,
+    deactivated_count,
+    errors
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
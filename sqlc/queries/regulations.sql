@@ -10,6 +10,7 @@ WHERE standard_number = $1;
 SELECT * FROM regulations
 WHERE category = $1
 AND (sqlc.narg('subcategory')::text IS NULL OR subcategory = sqlc.narg('subcategory'))
+AND deactivated_at IS NULL
 ORDER BY standard_number ASC;
 
 -- name: SearchRegulations :many
@@ -17,6 +18,7 @@ SELECT *,
     ts_rank(search_vector, websearch_to_tsquery('english', $1)) as rank
 FROM regulations
 WHERE search_vector @@ websearch_to_tsquery('english', $1)
+AND deactivated_at IS NULL
 ORDER BY rank DESC, standard_number ASC
 LIMIT $2;
 
@@ -40,30 +42,67 @@ RETURNING *;
 -- name: ListAllCategories :many
 SELECT DISTINCT category
 FROM regulations
+WHERE deactivated_at IS NULL
 ORDER BY category ASC;
 
 -- name: ListRegulations :many
 SELECT id, standard_number, title, category, subcategory, summary, severity_typical
 FROM regulations
 WHERE (sqlc.narg('category')::text IS NULL OR category = sqlc.narg('category'))
+AND (sqlc.narg('subcategory')::text IS NULL OR subcategory = sqlc.narg('subcategory'))
+AND (sqlc.narg('severity')::text IS NULL OR severity_typical = sqlc.narg('severity'))
+AND deactivated_at IS NULL
 ORDER BY category ASC, standard_number ASC
 LIMIT $1 OFFSET $2;
 
 -- name: CountRegulations :one
 SELECT COUNT(*) FROM regulations
-WHERE (sqlc.narg('category')::text IS NULL OR category = sqlc.narg('category'));
+WHERE (sqlc.narg('category')::text IS NULL OR category = sqlc.narg('category'))
+AND (sqlc.narg('subcategory')::text IS NULL OR subcategory = sqlc.narg('subcategory'))
+AND (sqlc.narg('severity')::text IS NULL OR severity_typical = sqlc.narg('severity'))
+AND deactivated_at IS NULL;
 
 -- name: SearchRegulationsWithOffset :many
 SELECT id, standard_number, title, category, subcategory, summary, severity_typical,
     ts_rank(search_vector, websearch_to_tsquery('english', $1)) as rank
 FROM regulations
 WHERE search_vector @@ websearch_to_tsquery('english', $1)
+AND (sqlc.narg('category')::text IS NULL OR category = sqlc.narg('category'))
+AND (sqlc.narg('subcategory')::text IS NULL OR subcategory = sqlc.narg('subcategory'))
+AND (sqlc.narg('severity')::text IS NULL OR severity_typical = sqlc.narg('severity'))
+AND deactivated_at IS NULL
 ORDER BY rank DESC, standard_number ASC
 LIMIT $2 OFFSET $3;
 
 -- name: CountSearchResults :one
 SELECT COUNT(*) FROM regulations
-WHERE search_vector @@ websearch_to_tsquery('english', $1);
+WHERE search_vector @@ websearch_to_tsquery('english', $1)
+AND (sqlc.narg('category')::text IS NULL OR category = sqlc.narg('category'))
+AND (sqlc.narg('subcategory')::text IS NULL OR subcategory = sqlc.narg('subcategory'))
+AND (sqlc.narg('severity')::text IS NULL OR severity_typical = sqlc.narg('severity'))
+AND deactivated_at IS NULL;
+
+-- name: ListCategoryFacets :many
+-- Count active regulations per category. With a query, only regulations
+-- matching it are counted, so the counts describe the search results.
+SELECT category, COUNT(*) AS regulation_count
+FROM regulations
+WHERE (sqlc.narg('query')::text IS NULL OR search_vector @@ websearch_to_tsquery('english', sqlc.narg('query')))
+AND deactivated_at IS NULL
+GROUP BY category
+ORDER BY category ASC;
+
+-- name: ListSubcategoryFacets :many
+-- Count active regulations per subcategory of a category, optionally limited
+-- to those matching a search query.
+SELECT subcategory, COUNT(*) AS regulation_count
+FROM regulations
+WHERE category = sqlc.arg('category')
+AND subcategory IS NOT NULL
+AND (sqlc.narg('query')::text IS NULL OR search_vector @@ websearch_to_tsquery('english', sqlc.narg('query')))
+AND deactivated_at IS NULL
+GROUP BY subcategory
+ORDER BY subcategory ASC;
 
 -- name: GetRegulationDetail :one
 SELECT id, standard_number, title, category, subcategory, full_text, summary,
@@ -77,4 +116,60 @@ WHERE id = $1;
 SELECT r.*, array_position($1::text[], r.standard_number) as sort_order
 FROM regulations r
 WHERE r.standard_number = ANY($1::text[])
+AND r.deactivated_at IS NULL
 ORDER BY array_position($1::text[], r.standard_number);
+
+-- name: UpsertRegulation :one
+-- Insert a regulation, or update the one with the same standard number and
+-- reactivate it if it was deactivated. Returns no row when the stored
+-- regulation already matches, so re-running an import changes nothing.
+INSERT INTO regulations (
+    standard_number,
+    title,
+    category,
+    subcategory,
+    full_text,
+    summary,
+    severity_typical,
+    parent_standard,
+    effective_date,
+    last_updated
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+)
+ON CONFLICT (standard_number) DO UPDATE SET
+    title = EXCLUDED.title,
+    category = EXCLUDED.category,
+    subcategory = EXCLUDED.subcategory,
+    full_text = EXCLUDED.full_text,
+    summary = EXCLUDED.summary,
+    severity_typical = EXCLUDED.severity_typical,
+    parent_standard = EXCLUDED.parent_standard,
+    effective_date = EXCLUDED.effective_date,
+    last_updated = EXCLUDED.last_updated,
+    deactivated_at = NULL,
+    updated_at = NOW()
+WHERE regulations.deactivated_at IS NOT NULL
+OR (regulations.title, regulations.category, regulations.subcategory, regulations.full_text,
+    regulations.summary, regulations.severity_typical, regulations.parent_standard,
+    regulations.effective_date, regulations.last_updated)
+IS DISTINCT FROM (EXCLUDED.title, EXCLUDED.category, EXCLUDED.subcategory, EXCLUDED.full_text,
+    EXCLUDED.summary, EXCLUDED.severity_typical, EXCLUDED.parent_standard,
+    EXCLUDED.effective_date, EXCLUDED.last_updated)
+RETURNING (xmax = 0) AS inserted;
+
+-- name: DeactivateRegulationsNotIn :execrows
+-- Deactivate active regulations missing from an import. They are kept so
+-- existing violation links stay valid.
+UPDATE regulations
+SET deactivated_at = NOW(),
+    updated_at = NOW()
+WHERE deactivated_at IS NULL
+AND NOT (standard_number = ANY(sqlc.arg('standard_numbers')::text[]));
+
+-- name: RefreshRegulationSearchVectors :exec
+-- Rebuild search_vector for active regulations. Any write to the row fires
+-- the update_regulation_search_vector trigger, which recomputes the column.
+UPDATE regulations
+SET search_vector = NULL
+WHERE deactivated_at IS NULL;
@@ -0,0 +1,12 @@
+-- name: GetReportSettings :one
+SELECT * FROM report_settings
+WHERE user_id = $1;
+
+-- name: UpsertReportSettings :exec
+INSERT INTO report_settings (user_id, intro, disclaimer, signature)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id) DO UPDATE
+SET intro = EXCLUDED.intro,
+    disclaimer = EXCLUDED.disclaimer,
+    signature = EXCLUDED.signature,
+    updated_at = NOW();
@@ -4,12 +4,30 @@ INSERT INTO reports (
     user_id,
     pdf_storage_key,
     docx_storage_key,
-    violation_count
+    violation_count,
+    recipient_emails
 ) VALUES (
-    $1, $2, $3, $4, $5
+    $1, $2, $3, $4, $5, $6
 )
 RETURNING *;
 
+-- name: CreateFailedReport :one
+-- Record a report generation job that failed permanently.
+INSERT INTO reports (
+    inspection_id,
+    user_id,
+    failed_format,
+    failed_at
+) VALUES (
+    $1, $2, $3, NOW()
+)
+RETURNING *;
+
+-- name: DeleteFailedReportsByInspectionID :exec
+-- Clear failure records once a report for the inspection generates.
+DELETE FROM reports
+WHERE inspection_id = $1 AND user_id = $2 AND failed_at IS NOT NULL;
+
 -- name: GetReportByID :one
 SELECT * FROM reports
 WHERE id = $1;
@@ -25,9 +43,15 @@ ORDER BY generated_at DESC;
 
 -- name: CountReportsByUserID :one
 SELECT COUNT(*) FROM reports
-WHERE user_id = $1;
+WHERE user_id = $1 AND failed_at IS NULL;
 
 -- name: CountReportsThisMonthByUserID :one
 SELECT COUNT(*) FROM reports
 WHERE user_id = $1
+  AND failed_at IS NULL
   AND generated_at >= DATE_TRUNC('month', CURRENT_TIMESTAMP);
+
+-- name: CountReportsByInspectionID :one
+-- Count the reports generated for an inspection, leaving out failures.
+SELECT COUNT(*) FROM reports
+WHERE inspection_id = $1 AND failed_at IS NULL;
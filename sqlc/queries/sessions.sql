@@ -2,9 +2,11 @@
 INSERT INTO sessions (
     user_id,
     token_hash,
-    expires_at
+    expires_at,
+    user_agent,
+    ip_address
 ) VALUES (
-    $1, $2, $3
+    $1, $2, $3, $4, $5
 )
 RETURNING *;
 
@@ -13,14 +15,36 @@ SELECT * FROM sessions
 WHERE token_hash = $1
 AND expires_at > NOW();
 
+-- name: ListSessionsByUserID :many
+SELECT * FROM sessions
+WHERE user_id = $1
+AND expires_at > NOW()
+ORDER BY last_seen_at DESC;
+
 -- name: DeleteSession :exec
 DELETE FROM sessions
 WHERE token_hash = $1;
 
+-- name: DeleteSessionByIDAndUserID :execrows
+DELETE FROM sessions
+WHERE id = $1 AND user_id = $2;
+
 -- name: DeleteUserSessions :exec
 DELETE FROM sessions
 WHERE user_id = $1;
 
+-- name: DeleteUserSessionsExcept :execrows
+-- Sign out everywhere else: delete the user's sessions other than the given token.
+DELETE FROM sessions
+WHERE user_id = $1 AND token_hash <> $2;
+
 -- name: DeleteExpiredSessions :exec
 DELETE FROM sessions
 WHERE expires_at <= NOW();
+
+-- name: TouchSession :exec
+UPDATE sessions
+SET last_seen_at = NOW(),
+    user_agent = $2,
+    ip_address = $3
+WHERE id = $1;
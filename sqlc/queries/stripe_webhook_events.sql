@@ -0,0 +1,13 @@
+-- name: HasStripeWebhookEvent :one
+-- Check whether a webhook event has already been processed.
+SELECT EXISTS (
+    SELECT 1 FROM stripe_webhook_events
+    WHERE event_id = $1
+) AS processed;
+
+-- name: CreateStripeWebhookEvent :exec
+-- Record a webhook event as processed. Recording it again, e.g. after two
+-- deliveries of the event were processed concurrently, does nothing.
+INSERT INTO stripe_webhook_events (event_id, event_type)
+VALUES ($1, $2)
+ON CONFLICT (event_id) DO NOTHING;
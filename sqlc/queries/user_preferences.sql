@@ -0,0 +1,11 @@
+-- name: GetUserPreferences :one
+SELECT preferences FROM user_preferences
+WHERE user_id = $1;
+
+-- name: SetUserPreference :exec
+-- Set one preference, keeping the user's others.
+INSERT INTO user_preferences (user_id, preferences)
+VALUES ($1, jsonb_build_object(sqlc.arg(key)::text, sqlc.arg(value)::jsonb))
+ON CONFLICT (user_id) DO UPDATE
+SET preferences = user_preferences.preferences || EXCLUDED.preferences,
+    updated_at = NOW();
@@ -0,0 +1,21 @@
+-- name: GetUserQuotaOverride :one
+SELECT * FROM user_quota_overrides
+WHERE user_id = $1;
+
+-- name: UpsertUserQuotaOverride :one
+INSERT INTO user_quota_overrides (
+    user_id,
+    analysis_per_month,
+    reports_per_month
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (user_id) DO UPDATE
+SET analysis_per_month = EXCLUDED.analysis_per_month,
+    reports_per_month = EXCLUDED.reports_per_month,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: DeleteUserQuotaOverride :exec
+DELETE FROM user_quota_overrides
+WHERE user_id = $1;
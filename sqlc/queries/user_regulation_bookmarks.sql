@@ -0,0 +1,39 @@
+-- name: AddRegulationBookmark :exec
+INSERT INTO user_regulation_bookmarks (user_id, regulation_id)
+VALUES ($1, $2)
+ON CONFLICT (user_id, regulation_id) DO NOTHING;
+
+-- name: RemoveRegulationBookmark :exec
+DELETE FROM user_regulation_bookmarks
+WHERE user_id = $1 AND regulation_id = $2;
+
+-- name: ListBookmarkedRegulationsByUserID :many
+SELECT r.id, r.standard_number, r.title, r.category, r.subcategory, r.summary, r.severity_typical
+FROM user_regulation_bookmarks b
+JOIN regulations r ON r.id = b.regulation_id
+WHERE b.user_id = $1
+AND r.deactivated_at IS NULL
+ORDER BY r.standard_number ASC;
+
+-- name: ListBookmarkedRegulationIDsByUserID :many
+SELECT regulation_id FROM user_regulation_bookmarks
+WHERE user_id = $1;
+
+-- name: ListMostUsedRegulationsByUserID :many
+-- The regulations most often linked to the user's violations, excluding
+-- bookmarks, which are listed separately.
+SELECT r.id, r.standard_number, r.title, r.category, r.subcategory, r.summary, r.severity_typical,
+    COUNT(*) AS use_count
+FROM violation_regulations vr
+JOIN violations v ON v.id = vr.violation_id
+JOIN inspections i ON i.id = v.inspection_id
+JOIN regulations r ON r.id = vr.regulation_id
+WHERE i.user_id = $1
+AND r.deactivated_at IS NULL
+AND NOT EXISTS (
+    SELECT 1 FROM user_regulation_bookmarks b
+    WHERE b.user_id = i.user_id AND b.regulation_id = r.id
+)
+GROUP BY r.id
+ORDER BY use_count DESC, r.standard_number ASC
+LIMIT $2;
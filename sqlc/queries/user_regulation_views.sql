@@ -0,0 +1,26 @@
+-- name: RecordRegulationView :exec
+-- Record that the user viewed a regulation, moving it to the front if it was
+-- already among their recent views.
+INSERT INTO user_regulation_views (user_id, regulation_id)
+VALUES ($1, $2)
+ON CONFLICT (user_id, regulation_id) DO UPDATE SET viewed_at = NOW();
+
+-- name: TrimRegulationViews :exec
+-- Keep only the user's most recent views.
+DELETE FROM user_regulation_views
+WHERE user_id = $1
+AND regulation_id NOT IN (
+    SELECT v.regulation_id FROM user_regulation_views v
+    WHERE v.user_id = $1
+    ORDER BY v.viewed_at DESC
+    LIMIT $2
+);
+
+-- name: ListRecentlyViewedRegulationsByUserID :many
+SELECT r.id, r.standard_number, r.title, r.category, r.subcategory, r.summary, r.severity_typical
+FROM user_regulation_views v
+JOIN regulations r ON r.id = v.regulation_id
+WHERE v.user_id = $1
+AND r.deactivated_at IS NULL
+ORDER BY v.viewed_at DESC
+LIMIT $2;
@@ -69,5 +69,34 @@ SET business_name = $2,
     business_postal_code = $9,
     business_license_number = $10,
     business_logo_url = $11,
+    business_brand_color = $12,
     updated_at = NOW()
 WHERE id = $1;
+
+-- name: UpdateUserBusinessLogo :exec
+UPDATE users
+SET business_logo_key = $2,
+    updated_at = NOW()
+WHERE id = $1;
+
+-- name: ListUsersDueVerificationReminder :many
+-- Unverified, active users who registered before the cutoff and have not been
+-- reminded since it, oldest first
+SELECT id, email, name, verification_reminder_count FROM users
+WHERE email_verified IS NOT TRUE
+AND disabled_at IS NULL
+AND created_at < sqlc.arg(cutoff)
+AND (verification_reminder_sent_at IS NULL OR verification_reminder_sent_at < sqlc.arg(cutoff))
+AND verification_reminder_count < sqlc.arg(max_reminders)
+ORDER BY created_at
+LIMIT sqlc.arg(batch_size);
+
+-- name: ClaimVerificationReminder :execrows
+-- Records a reminder for the user; matches no row if another run already
+-- recorded this reminder or the user has since verified
+UPDATE users
+SET verification_reminder_sent_at = NOW(),
+    verification_reminder_count = verification_reminder_count + 1
+WHERE id = $1
+AND verification_reminder_count = $2
+AND email_verified IS NOT TRUE;
@@ -0,0 +1,24 @@
+-- name: ListViolationImages :many
+-- Lists a violation's additional photos in the order they were attached.
+SELECT
+    vi.image_id,
+    vi.position,
+    i.thumbnail_key,
+    i.original_filename
+FROM violation_images vi
+JOIN images i ON i.id = vi.image_id
+WHERE vi.violation_id = $1
+ORDER BY vi.position, vi.created_at;
+
+-- name: AddViolationImage :execrows
+-- Attaches a photo after the violation's other photos. Attaching the same
+-- photo twice affects no rows.
+INSERT INTO violation_images (violation_id, image_id, position)
+SELECT $1, $2, COALESCE(MAX(position), 0) + 1
+FROM violation_images
+WHERE violation_id = $1
+ON CONFLICT (violation_id, image_id) DO NOTHING;
+
+-- name: RemoveViolationImage :execrows
+DELETE FROM violation_images
+WHERE violation_id = $1 AND image_id = $2;
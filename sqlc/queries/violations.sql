@@ -9,9 +9,10 @@ INSERT INTO violations (
     status,
     severity,
     inspector_notes,
-    sort_order
+    sort_order,
+    ai_severity
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
 )
 RETURNING *;
 
@@ -105,10 +106,116 @@ AND v.inspection_id = i.id
 AND i.user_id = $2;
 
 -- name: ListConfirmedViolationsByInspectionIDAndUserID :many
--- List confirmed violations with user authorization check (defense in depth)
+-- List confirmed violations with user authorization check (defense in depth),
+-- in the gallery order of their images; violations without an image come last
 SELECT v.* FROM violations v
 JOIN inspections i ON i.id = v.inspection_id
+LEFT JOIN images img ON img.id = v.image_id
 WHERE v.inspection_id = $1
 AND i.user_id = $2
 AND v.status = 'confirmed'
+ORDER BY img.display_order ASC NULLS LAST, v.sort_order ASC, v.created_at ASC;
+
+-- name: ListAIViolationMatchesByImageID :many
+-- List the AI-found violations of an image with the standard number of their
+-- primary regulation, to match them against a new analysis of the image
+SELECT
+    v.id,
+    v.description,
+    r.standard_number
+FROM violations v
+LEFT JOIN violation_regulations vr ON vr.violation_id = v.id AND vr.is_primary = true
+LEFT JOIN regulations r ON r.id = vr.regulation_id
+WHERE v.image_id = $1
+AND v.ai_description IS NOT NULL
 ORDER BY v.sort_order ASC, v.created_at ASC;
+
+-- name: UpdateViolationFromReanalysis :exec
+-- Refresh a violation with a new analysis of its image. The description and
+-- severity are only replaced while the violation is still awaiting review.
+UPDATE violations
+SET ai_description = $2,
+    confidence = $3,
+    bounding_box = $4,
+    ai_severity = $5,
+    description = CASE WHEN status = 'pending' THEN sqlc.arg('description')::text ELSE description END,
+    severity = CASE WHEN status = 'pending' THEN $5 ELSE severity END,
+    updated_at = NOW()
+WHERE id = $1;
+
+-- name: SummarizeViolationsByUserID :many
+-- Count the violations of the inspections dated between from_date and
+-- to_date, inclusive, by severity and status, across the user and the
+-- members of their organization. Unrated violations have an empty severity.
+SELECT
+    COALESCE(v.severity, '')::text AS severity,
+    v.status,
+    COUNT(*) AS violation_count
+FROM violations v
+JOIN inspections i ON i.id = v.inspection_id
+WHERE (i.user_id = $1 OR i.user_id IN (
+    SELECT them.user_id FROM organization_members me
+    JOIN organization_members them ON them.organization_id = me.organization_id
+    WHERE me.user_id = $1
+))
+AND i.inspection_date BETWEEN sqlc.arg('from_date')::date AND sqlc.arg('to_date')::date
+GROUP BY COALESCE(v.severity, ''), v.status
+ORDER BY COALESCE(v.severity, ''), v.status;
+
+-- name: SearchViolationsByUserID :many
+-- Search the violations of the user's inspections, newest inspection first.
+-- The search matches the description, AI description and inspector notes
+-- (case-insensitive), and standard_number matches violations linked to a
+-- regulation whose standard number starts with it. Empty filters match
+-- everything. Each row carries the standard number of the violation's
+-- primary regulation, or its most relevant one.
+SELECT
+    sqlc.embed(v),
+    i.title AS inspection_title,
+    i.inspection_date,
+    COALESCE((
+        SELECT r.standard_number FROM violation_regulations vr
+        JOIN regulations r ON r.id = vr.regulation_id
+        WHERE vr.violation_id = v.id
+        ORDER BY vr.is_primary DESC NULLS LAST, vr.relevance_score DESC NULLS LAST
+        LIMIT 1
+    ), '')::text AS standard_number
+FROM violations v
+JOIN inspections i ON i.id = v.inspection_id
+WHERE i.user_id = sqlc.arg('user_id')
+AND (sqlc.arg('search')::text = ''
+    OR v.description ILIKE '%' || sqlc.arg('search')::text || '%'
+    OR v.ai_description ILIKE '%' || sqlc.arg('search')::text || '%'
+    OR v.inspector_notes ILIKE '%' || sqlc.arg('search')::text || '%')
+AND (sqlc.arg('severity')::text = '' OR v.severity = sqlc.arg('severity')::text)
+AND (sqlc.arg('status')::text = '' OR v.status = sqlc.arg('status')::text)
+AND (sqlc.narg('from_date')::date IS NULL OR i.inspection_date >= sqlc.narg('from_date')::date)
+AND (sqlc.narg('to_date')::date IS NULL OR i.inspection_date <= sqlc.narg('to_date')::date)
+AND (sqlc.arg('standard_number')::text = '' OR EXISTS (
+    SELECT 1 FROM violation_regulations vr
+    JOIN regulations r ON r.id = vr.regulation_id
+    WHERE vr.violation_id = v.id
+    AND r.standard_number LIKE sqlc.arg('standard_number')::text || '%'
+))
+ORDER BY i.inspection_date DESC, v.created_at DESC, v.id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountSearchViolationsByUserID :one
+-- Count the violations SearchViolationsByUserID matches across all pages.
+SELECT COUNT(*) FROM violations v
+JOIN inspections i ON i.id = v.inspection_id
+WHERE i.user_id = sqlc.arg('user_id')
+AND (sqlc.arg('search')::text = ''
+    OR v.description ILIKE '%' || sqlc.arg('search')::text || '%'
+    OR v.ai_description ILIKE '%' || sqlc.arg('search')::text || '%'
+    OR v.inspector_notes ILIKE '%' || sqlc.arg('search')::text || '%')
+AND (sqlc.arg('severity')::text = '' OR v.severity = sqlc.arg('severity')::text)
+AND (sqlc.arg('status')::text = '' OR v.status = sqlc.arg('status')::text)
+AND (sqlc.narg('from_date')::date IS NULL OR i.inspection_date >= sqlc.narg('from_date')::date)
+AND (sqlc.narg('to_date')::date IS NULL OR i.inspection_date <= sqlc.narg('to_date')::date)
+AND (sqlc.arg('standard_number')::text = '' OR EXISTS (
+    SELECT 1 FROM violation_regulations vr
+    JOIN regulations r ON r.id = vr.regulation_id
+    WHERE vr.violation_id = v.id
+    AND r.standard_number LIKE sqlc.arg('standard_number')::text || '%'
+));
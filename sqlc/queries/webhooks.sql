@@ -0,0 +1,83 @@
+-- name: CreateWebhookEndpoint :one
+INSERT INTO webhook_endpoints (
+    user_id,
+    url,
+    secret
+) VALUES (
+    $1, $2, $3
+)
+RETURNING *;
+
+-- name: ListWebhookEndpointsByUserID :many
+SELECT * FROM webhook_endpoints
+WHERE user_id = $1
+ORDER BY created_at;
+
+-- name: CountWebhookEndpointsByUserID :one
+SELECT COUNT(*) FROM webhook_endpoints
+WHERE user_id = $1;
+
+-- name: DeleteWebhookEndpointByIDAndUserID :execrows
+-- Delete an endpoint owned by the user, along with its delivery history.
+DELETE FROM webhook_endpoints
+WHERE id = $1 AND user_id = $2;
+
+-- name: CreateWebhookDelivery :one
+-- The caller picks the ID, since the payload carries it as the event ID.
+INSERT INTO webhook_deliveries (
+    id,
+    endpoint_id,
+    event,
+    payload
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: GetWebhookDeliveryForSend :one
+-- Get a delivery with the URL and secret of its endpoint.
+SELECT
+    d.id,
+    d.event,
+    d.payload,
+    d.status,
+    e.url,
+    e.secret
+FROM webhook_deliveries d
+JOIN webhook_endpoints e ON e.id = d.endpoint_id
+WHERE d.id = $1;
+
+-- name: RecordWebhookDeliveryAttempt :exec
+-- Record the outcome of sending a delivery. attempts is added to the
+-- delivery's attempt count.
+UPDATE webhook_deliveries
+SET attempts = attempts + $2,
+    status = $3,
+    response_status = $4,
+    error_message = $5,
+    delivered_at = $6
+WHERE id = $1;
+
+-- name: MarkWebhookDeliveryFailed :exec
+-- Give up on a delivery that ran out of retries.
+UPDATE webhook_deliveries
+SET status = 'failed'
+WHERE id = $1 AND status = 'pending';
+
+-- name: ListRecentWebhookDeliveriesByUserID :many
+-- List the user's most recent deliveries across all endpoints, newest first.
+SELECT
+    d.id,
+    d.event,
+    d.status,
+    d.attempts,
+    d.response_status,
+    d.error_message,
+    d.delivered_at,
+    d.created_at,
+    e.url
+FROM webhook_deliveries d
+JOIN webhook_endpoints e ON e.id = d.endpoint_id
+WHERE e.user_id = $1
+ORDER BY d.created_at DESC
+LIMIT $2;